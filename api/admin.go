@@ -0,0 +1,265 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/logging"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrIntakePaused rejects transactions while the node operator has paused
+// intake through the admin API
+var ErrIntakePaused = errors.New("transaction intake is paused")
+
+// IntakeResponse reports whether the node accepts transactions
+type IntakeResponse struct {
+	Paused bool `json:"paused"`
+}
+
+// AdminSnapshotResponse describes a state snapshot written to disk
+type AdminSnapshotResponse struct {
+	Path      string `json:"path"`
+	Height    uint32 `json:"height"`
+	StateRoot string `json:"state_root"`
+	Entries   int    `json:"entries"`
+}
+
+// LogLevelResponse is the level the node logs at
+type LogLevelResponse struct {
+	Level string `json:"level"`
+	File  string `json:"file,omitempty"`
+}
+
+// PauseIntake makes the API reject transactions until ResumeIntake
+func (s *Server) PauseIntake() {
+	atomic.StoreInt32(&s.intakePaused, 1)
+}
+
+// ResumeIntake accepts transactions again
+func (s *Server) ResumeIntake() {
+	atomic.StoreInt32(&s.intakePaused, 0)
+}
+
+// IntakePaused reports whether transactions are rejected
+func (s *Server) IntakePaused() bool {
+	return atomic.LoadInt32(&s.intakePaused) == 1
+}
+
+func (s *DAOServer) handleGetIntake(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, IntakeResponse{Paused: s.IntakePaused()})
+}
+
+func (s *DAOServer) handlePauseIntake(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	s.PauseIntake()
+	s.Logger.Log("msg", "transaction intake paused by admin")
+	return c.JSON(http.StatusOK, IntakeResponse{Paused: true})
+}
+
+func (s *DAOServer) handleResumeIntake(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	s.ResumeIntake()
+	s.Logger.Log("msg", "transaction intake resumed by admin")
+	return c.JSON(http.StatusOK, IntakeResponse{Paused: false})
+}
+
+// handleCreateSnapshot writes the current DAO state to the snapshot
+// directory, in the format of `bockchain snapshot export`
+func (s *DAOServer) handleCreateSnapshot(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	snapshot, err := s.bc.GetDAOStateAt(s.bc.Height())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+	response, err := s.snapshotResponse(snapshot)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+	path, err := writeSnapshotFile(s.Config.Server.SnapshotDir, response.Height, data)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	s.Logger.Log("msg", "state snapshot written", "path", path, "height", response.Height)
+	return c.JSON(http.StatusCreated, AdminSnapshotResponse{
+		Path:      path,
+		Height:    response.Height,
+		StateRoot: response.StateRoot,
+		Entries:   len(response.Entries),
+	})
+}
+
+// writeSnapshotFile writes a snapshot into dir without leaving a partial
+// file behind when it fails
+func writeSnapshotFile(dir string, height uint32, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("snapshot-%d.json", height))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+func (s *DAOServer) handleRotateLogs(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+	if s.LogControl == nil {
+		return errorMessage(c, http.StatusConflict, "logging cannot be changed at runtime")
+	}
+
+	rotated, err := s.LogControl.Rotate()
+	if err != nil {
+		return errorResponse(c, http.StatusConflict, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"file":    s.LogControl.Path(),
+		"rotated": rotated,
+	})
+}
+
+func (s *DAOServer) handleGetLogLevel(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+	if s.LogControl == nil {
+		return errorMessage(c, http.StatusConflict, "logging cannot be changed at runtime")
+	}
+
+	return c.JSON(http.StatusOK, LogLevelResponse{
+		Level: s.LogControl.Level().String(),
+		File:  s.LogControl.Path(),
+	})
+}
+
+func (s *DAOServer) handleSetLogLevel(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+	if s.LogControl == nil {
+		return errorMessage(c, http.StatusConflict, "logging cannot be changed at runtime")
+	}
+
+	var req LogLevelResponse
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		return fieldErrorResponse(c, err.Error(), []FieldError{{Field: "level", Message: err.Error()}})
+	}
+
+	previous := s.LogControl.Level()
+	s.LogControl.SetLevel(level)
+	s.Logger.Log("msg", "log level changed by admin", "from", previous, "to", level)
+
+	return c.JSON(http.StatusOK, LogLevelResponse{
+		Level: level.String(),
+		File:  s.LogControl.Path(),
+	})
+}
+
+// SetScheduler sets the scheduler of the maintenance jobs. It must be
+// called before the server is started.
+func (s *DAOServer) SetScheduler(scheduler *dao.Scheduler) {
+	s.scheduler = scheduler
+}
+
+// handleGetJobs reports the schedule and run statistics of every
+// maintenance job
+func (s *DAOServer) handleGetJobs(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+	if s.scheduler == nil {
+		return c.JSON(http.StatusOK, []dao.JobMetrics{})
+	}
+
+	return c.JSON(http.StatusOK, s.scheduler.Metrics())
+}
+
+// handleRunJob runs a maintenance job now and reports how it went
+func (s *DAOServer) handleRunJob(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+	if s.scheduler == nil {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrJobNotFound, "no maintenance jobs are scheduled", nil))
+	}
+
+	name := c.Param("name")
+	err := s.scheduler.RunJob(name)
+	var daoErr *dao.DAOError
+	if errors.As(err, &daoErr) && daoErr.Code == dao.ErrJobNotFound {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	// A failed run shows up as the last error of the job
+	var response dao.JobMetrics
+	for _, metrics := range s.scheduler.Metrics() {
+		if metrics.Name == name {
+			response = metrics
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// registerProfiling serves the pprof profiles of the node under
+// /admin/debug/pprof/, for the admin token only
+func (s *DAOServer) registerProfiling(e *echo.Echo) {
+	e.GET("/admin/debug/pprof/", s.requireAdmin(echo.WrapHandler(http.HandlerFunc(pprof.Index))))
+	e.GET("/admin/debug/pprof/cmdline", s.requireAdmin(echo.WrapHandler(http.HandlerFunc(pprof.Cmdline))))
+	e.GET("/admin/debug/pprof/profile", s.requireAdmin(echo.WrapHandler(http.HandlerFunc(pprof.Profile))))
+	e.GET("/admin/debug/pprof/symbol", s.requireAdmin(echo.WrapHandler(http.HandlerFunc(pprof.Symbol))))
+	e.GET("/admin/debug/pprof/trace", s.requireAdmin(echo.WrapHandler(http.HandlerFunc(pprof.Trace))))
+	// goroutine, heap, allocs, block, mutex and threadcreate
+	e.GET("/admin/debug/pprof/:profile", s.requireAdmin(func(c echo.Context) error {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Response(), c.Request())
+		return nil
+	}))
+}
+
+// requireAdmin wraps handlers that cannot check the admin token themselves
+func (s *DAOServer) requireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if status, err := s.authorizeAdmin(c); err != nil {
+			return errorResponse(c, status, err)
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,432 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/config"
+	"github.com/labstack/echo/v4"
+)
+
+// CacheStore keeps cached values for a time, and the counters that version
+// them
+type CacheStore interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Incr(key string) (int64, error)
+}
+
+// memoryCacheMaxEntries bounds the entries of the in-process store
+const memoryCacheMaxEntries = 4096
+
+// MemoryCacheStore is a CacheStore inside the process
+type MemoryCacheStore struct {
+	entries  map[string]memoryCacheEntry
+	counters map[string]int64
+	mu       sync.Mutex
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCacheStore creates an empty in-process cache store
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{
+		entries:  make(map[string]memoryCacheEntry),
+		counters: make(map[string]int64),
+	}
+}
+
+// Get returns the value of key unless it expired. Counters read as their
+// decimal value, like in Redis.
+func (ms *MemoryCacheStore) Get(key string) ([]byte, bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if counter, exists := ms.counters[key]; exists {
+		return []byte(strconv.FormatInt(counter, 10)), true, nil
+	}
+
+	entry, exists := ms.entries[key]
+	if !exists {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(ms.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set keeps value under key for ttl, evicting expired entries, then
+// arbitrary ones, when the store is full
+func (ms *MemoryCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	if len(ms.entries) >= memoryCacheMaxEntries {
+		for k, entry := range ms.entries {
+			if now.After(entry.expires) {
+				delete(ms.entries, k)
+			}
+		}
+		for k := range ms.entries {
+			if len(ms.entries) < memoryCacheMaxEntries {
+				break
+			}
+			delete(ms.entries, k)
+		}
+	}
+
+	ms.entries[key] = memoryCacheEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+// Incr increments the counter at key and returns its new value
+func (ms *MemoryCacheStore) Incr(key string) (int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.counters[key]++
+	return ms.counters[key], nil
+}
+
+// redisTimeout bounds every round trip to Redis, so a slow Redis degrades to
+// uncached responses
+const redisTimeout = time.Second
+
+// RedisCacheStore is a CacheStore in Redis, spoken to over a single
+// connection that is reopened after errors
+type RedisCacheStore struct {
+	addr     string
+	password string
+	conn     net.Conn
+	reader   *bufio.Reader
+	mu       sync.Mutex
+}
+
+// NewRedisCacheStore creates a store in the Redis at addr. It connects on
+// first use.
+func NewRedisCacheStore(addr, password string) *RedisCacheStore {
+	return &RedisCacheStore{
+		addr:     addr,
+		password: password,
+	}
+}
+
+// Get returns the value of key
+func (rs *RedisCacheStore) Get(key string) ([]byte, bool, error) {
+	reply, err := rs.do("GET", key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected redis reply to GET: %v", reply)
+	}
+	return value, true, nil
+}
+
+// Set keeps value under key for ttl
+func (rs *RedisCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := rs.do("SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Incr increments the counter at key and returns its new value
+func (rs *RedisCacheStore) Incr(key string) (int64, error) {
+	reply, err := rs.do("INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis reply to INCR: %v", reply)
+	}
+	return value, nil
+}
+
+// Close closes the connection to Redis
+func (rs *RedisCacheStore) Close() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.conn == nil {
+		return nil
+	}
+	err := rs.conn.Close()
+	rs.conn = nil
+	return err
+}
+
+// do sends a command and reads its reply, reconnecting when needed
+func (rs *RedisCacheStore) do(args ...string) (interface{}, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.conn == nil {
+		conn, err := net.DialTimeout("tcp", rs.addr, redisTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		rs.conn = conn
+		rs.reader = bufio.NewReader(conn)
+
+		if rs.password != "" {
+			if _, err := rs.roundTrip("AUTH", rs.password); err != nil {
+				rs.conn.Close()
+				rs.conn = nil
+				return nil, err
+			}
+		}
+	}
+
+	reply, err := rs.roundTrip(args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection is in an unknown state
+		rs.conn.Close()
+		rs.conn = nil
+	}
+	return reply, err
+}
+
+// redisError is an error reply of Redis
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// roundTrip writes a command in the Redis protocol and reads the reply;
+// callers must hold the lock
+func (rs *RedisCacheStore) roundTrip(args ...string) (interface{}, error) {
+	if err := rs.conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+
+	var command bytes.Buffer
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := rs.conn.Write(command.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to write to redis: %w", err)
+	}
+
+	return readRedisReply(rs.reader)
+}
+
+// readRedisReply reads a simple string, error, integer or bulk string reply.
+// A missing bulk string reads as nil.
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from redis: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length: %w", err)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, fmt.Errorf("failed to read from redis: %w", err)
+		}
+		return value[:size], nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply %q", line)
+	}
+}
+
+// ResponseCache caches successful GET responses with their ETag. Entries are
+// versioned by a generation counter, so bumping it invalidates all of them.
+type ResponseCache struct {
+	store  CacheStore
+	ttl    time.Duration
+	prefix string
+}
+
+// cachedResponse is a response as it is kept in the store
+type cachedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	TotalCount  string `json:"total_count,omitempty"`
+	ETag        string `json:"etag"`
+	Body        []byte `json:"body"`
+}
+
+// NewResponseCache creates a cache keeping responses in store for ttl, under
+// keys starting with prefix
+func NewResponseCache(store CacheStore, ttl time.Duration, prefix string) *ResponseCache {
+	return &ResponseCache{
+		store:  store,
+		ttl:    ttl,
+		prefix: prefix,
+	}
+}
+
+// newResponseCache creates the response cache configured for the API, or
+// nil when caching is disabled
+func newResponseCache(cfg config.CacheConfig) *ResponseCache {
+	if cfg.TTL <= 0 {
+		return nil
+	}
+
+	var store CacheStore = NewMemoryCacheStore()
+	if cfg.RedisAddr != "" {
+		store = NewRedisCacheStore(cfg.RedisAddr, cfg.RedisPassword)
+	}
+	return NewResponseCache(store, cfg.TTL, cfg.KeyPrefix)
+}
+
+// Invalidate drops every cached response
+func (rc *ResponseCache) Invalidate() error {
+	_, err := rc.store.Incr(rc.prefix + "cache:generation")
+	return err
+}
+
+// key returns the key of the cached response to a request URI
+func (rc *ResponseCache) key(uri string) (string, error) {
+	generation := "0"
+	value, exists, err := rc.store.Get(rc.prefix + "cache:generation")
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		generation = string(value)
+	}
+	return rc.prefix + "cache:" + generation + ":" + uri, nil
+}
+
+// Middleware serves GET responses from the cache and answers If-None-Match
+// with 304 Not Modified when the ETag still matches. The cache is bypassed
+// while the store is unavailable.
+func (rc *ResponseCache) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Method != http.MethodGet {
+			return next(c)
+		}
+
+		key, err := rc.key(c.Request().URL.RequestURI())
+		if err == nil {
+			if data, exists, err := rc.store.Get(key); err == nil && exists {
+				cached := &cachedResponse{}
+				if json.Unmarshal(data, cached) == nil {
+					c.Response().Header().Set("X-Cache", "HIT")
+					return writeCachedResponse(c, cached)
+				}
+			}
+		}
+
+		// Buffer the response so the ETag can be set before it is sent
+		res := c.Response()
+		writer := res.Writer
+		recorder := &responseRecorder{ResponseWriter: writer, status: http.StatusOK}
+		res.Writer = recorder
+		handlerErr := next(c)
+		res.Writer = writer
+
+		if !res.Committed {
+			return handlerErr
+		}
+		res.Committed = false
+		res.Size = 0
+
+		body := recorder.body.Bytes()
+		digest := sha256.Sum256(body)
+		cached := &cachedResponse{
+			Status:      recorder.status,
+			ContentType: res.Header().Get(echo.HeaderContentType),
+			TotalCount:  res.Header().Get("X-Total-Count"),
+			ETag:        `"` + hex.EncodeToString(digest[:16]) + `"`,
+			Body:        body,
+		}
+		if key != "" && cached.Status == http.StatusOK {
+			if data, err := json.Marshal(cached); err == nil {
+				rc.store.Set(key, data, rc.ttl)
+			}
+		}
+
+		res.Header().Set("X-Cache", "MISS")
+		if err := writeCachedResponse(c, cached); err != nil {
+			return err
+		}
+		return handlerErr
+	}
+}
+
+// writeCachedResponse sends a cached response, or 304 Not Modified when the
+// client already has it
+func writeCachedResponse(c echo.Context, cached *cachedResponse) error {
+	header := c.Response().Header()
+	if cached.TotalCount != "" {
+		header.Set("X-Total-Count", cached.TotalCount)
+	}
+	if cached.Status != http.StatusOK {
+		return c.Blob(cached.Status, cached.ContentType, cached.Body)
+	}
+
+	header.Set("ETag", cached.ETag)
+	header.Set("Cache-Control", "no-cache")
+	if etagMatches(c.Request().Header.Get("If-None-Match"), cached.ETag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(cached.Status, cached.ContentType, cached.Body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseRecorder buffers a response instead of sending it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
@@ -1,14 +1,23 @@
 package api
 
 import (
-	"crypto/ecdsa"
-	"crypto/elliptic"
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"math/big"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/BOCK-CHAIN/BockChain/core"
 	"github.com/BOCK-CHAIN/BockChain/crypto"
@@ -25,59 +34,121 @@ type DAOServer struct {
 	eventBus  *EventBus
 	upgrader  websocket.Upgrader
 	wsClients map[*websocket.Conn]bool
+	// cache keeps hot read responses, nil when caching is disabled
+	cache *ResponseCache
+	// idempotency replays submissions retried with an Idempotency-Key, nil
+	// when disabled
+	idempotency *IdempotencyCache
+	// scheduler runs the maintenance jobs, the admin API reports on it
+	scheduler *dao.Scheduler
+	// eventTokens authenticate members on the event WebSocket
+	eventTokens *EventTokenStore
+	// daoID is the hosted DAO the server serves, empty for the default DAO
+	daoID string
+	// registry hosts the DAOs served under /daos/:daoID, nil when the node
+	// serves only the default DAO
+	registry *dao.DAORegistry
+	// hosted are the servers of the DAOs in the registry, by DAO ID
+	hosted   map[string]*hostedServer
+	hostedMu sync.RWMutex
 }
 
 // Helper functions for crypto key conversion
+// privateKeyFromHex parses a hex encoded P-256 private key, or a
+// secp256k1 key prefixed with secp256k1:
 func privateKeyFromHex(hexStr string) (crypto.PrivateKey, error) {
-	b, err := hex.DecodeString(hexStr)
+	privKey, err := crypto.PrivateKeyFromHex(hexStr)
 	if err != nil {
-		return crypto.PrivateKey{}, dao.NewDAOError(dao.ErrInvalidSignature, "invalid private key hex format", nil)
-	}
-
-	if len(b) != 32 {
-		return crypto.PrivateKey{}, dao.NewDAOError(dao.ErrInvalidSignature, "private key must be 32 bytes", nil)
+		return crypto.PrivateKey{}, dao.NewDAOError(dao.ErrInvalidSignature, err.Error(), nil)
 	}
+	return privKey, nil
+}
 
-	// Create ECDSA private key from bytes
-	k := new(big.Int).SetBytes(b)
-	priv := &ecdsa.PrivateKey{
-		PublicKey: ecdsa.PublicKey{
-			Curve: elliptic.P256(),
-		},
-		D: k,
+// publicKeyFromHex parses a hex encoded public key and checks it is a point
+// of its curve
+func publicKeyFromHex(hexStr string) (crypto.PublicKey, error) {
+	pubKey, err := crypto.PublicKeyFromHex(hexStr)
+	if err != nil {
+		return nil, dao.NewDAOError(dao.ErrInvalidSignature, err.Error(), nil)
 	}
-	priv.PublicKey.X, priv.PublicKey.Y = elliptic.P256().ScalarBaseMult(k.Bytes())
-
-	// For now, we'll use a workaround since we can't modify the crypto package
-	// In production, this should be implemented in the crypto package
-	return crypto.GeneratePrivateKey(), nil // Temporary workaround
+	return pubKey, nil
 }
 
-func publicKeyFromHex(hexStr string) (crypto.PublicKey, error) {
+func hashFromHex(hexStr string) (types.Hash, error) {
 	b, err := hex.DecodeString(hexStr)
-	if err != nil {
-		return nil, dao.NewDAOError(dao.ErrInvalidSignature, "invalid public key hex format", nil)
+	if err != nil || len(b) != 32 {
+		return types.Hash{}, dao.NewDAOError(dao.ErrInvalidProposal, "hash must be 32 hex encoded bytes", nil)
 	}
-	return crypto.PublicKey(b), nil
+	return types.HashFromBytes(b), nil
 }
 
 // EventBus handles real-time event broadcasting
 type EventBus struct {
-	clients    map[*websocket.Conn]bool
+	clients    map[*wsClient]bool
 	broadcast  chan []byte
-	register   chan *websocket.Conn
-	unregister chan *websocket.Conn
+	private    chan privateMessage
+	register   chan *wsClient
+	unregister chan *wsClient
+
+	// Subscribers consume events inside the node, they must not block
+	subscribers    map[int]func(Event)
+	nextSubscriber int
+	// history keeps the latest events so streams can resume after
+	// reconnecting
+	history []Event
+	lastID  uint64
+	mu      sync.RWMutex
 }
 
+// eventHistorySize is how many events streams can replay
+const eventHistorySize = 1000
+
+// eventBroadcastBuffer is how many events can wait for the fan-out to
+// WebSocket clients
+const eventBroadcastBuffer = 1024
+
 // NewDAOServer creates a new DAO-enhanced API server
 func NewDAOServer(cfg ServerConfig, bc *core.Blockchain, txChan chan *core.Transaction, daoInstance *dao.DAO) *DAOServer {
 	baseServer := NewServer(cfg, bc, txChan)
+	daoServer := newDAOServer(baseServer, daoInstance, NewEventTokenStore())
+	daoServer.hosted = make(map[string]*hostedServer)
+
+	if ttl := baseServer.Config.Server.IdempotencyTTL; ttl > 0 {
+		daoServer.idempotency = NewIdempotencyCache(ttl, baseServer.Config.Server.IdempotencyMaxBody)
+	}
+
+	// Applied transactions and API events invalidate cached responses
+	daoServer.cache = newResponseCache(baseServer.Config.Server.Cache)
+	if daoServer.cache != nil {
+		daoInstance.OnTransactionApplied(func(interface{}, crypto.PublicKey, types.Hash) {
+			daoServer.cache.Invalidate()
+		})
+		daoServer.eventBus.Subscribe(func(Event) {
+			daoServer.cache.Invalidate()
+		})
+	}
+
+	// Stream transaction lifecycle changes
+	bc.TxTracker().Subscribe(func(status core.TxStatus) {
+		daoServer.broadcastEvent(Event{
+			Type:      EventTxStatus,
+			Data:      daoServer.txStatusResponse(status),
+			Timestamp: status.UpdatedAt,
+		})
+	})
+
+	return daoServer
+}
 
+// newDAOServer creates the API server of one DAO with its own event bus
+func newDAOServer(baseServer *Server, daoInstance *dao.DAO, eventTokens *EventTokenStore) *DAOServer {
 	eventBus := &EventBus{
-		clients:    make(map[*websocket.Conn]bool),
-		broadcast:  make(chan []byte),
-		register:   make(chan *websocket.Conn),
-		unregister: make(chan *websocket.Conn),
+		clients:     make(map[*wsClient]bool),
+		broadcast:   make(chan []byte, eventBroadcastBuffer),
+		private:     make(chan privateMessage, eventBroadcastBuffer),
+		register:    make(chan *wsClient),
+		unregister:  make(chan *wsClient),
+		subscribers: make(map[int]func(Event)),
 	}
 
 	daoServer := &DAOServer{
@@ -85,13 +156,62 @@ func NewDAOServer(cfg ServerConfig, bc *core.Blockchain, txChan chan *core.Trans
 		dao:      daoInstance,
 		eventBus: eventBus,
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true // Allow all origins for development
-			},
+			CheckOrigin: baseServer.checkOrigin,
 		},
-		wsClients: make(map[*websocket.Conn]bool),
+		wsClients:   make(map[*websocket.Conn]bool),
+		eventTokens: eventTokens,
 	}
 
+	// Fan events out to subscribed members and integrators
+	eventBus.Subscribe(func(event Event) {
+		notification := dao.NewNotification(string(event.Type), event.Data, event.Timestamp)
+		notification.Recipients = eventRecipients(event)
+		daoInstance.Notifications.Notify(notification)
+	})
+	eventBus.Subscribe(func(event Event) {
+		daoInstance.Webhooks.Dispatch(string(event.Type), event.Data, event.Timestamp)
+	})
+
+	// Tell members about applied transactions that concern them privately
+	daoInstance.OnTransactionApplied(daoServer.privateEventsFor)
+
+	// Tell both parties when the expiry sweep ends or renews a delegation
+	daoInstance.OnDelegationExpiry(func(expiry dao.DelegationExpiry) {
+		eventType := EventDelegationExpired
+		if expiry.Renewed {
+			eventType = EventDelegationRenewed
+		}
+		daoServer.broadcastEvent(Event{
+			Type: eventType,
+			Data: map[string]interface{}{
+				"delegator":  expiry.Delegation.Delegator.String(),
+				"delegate":   expiry.Delegation.Delegate.String(),
+				"start_time": expiry.Delegation.StartTime,
+				"end_time":   expiry.Delegation.EndTime,
+				"auto_renew": expiry.Delegation.AutoRenew,
+			},
+			Timestamp: time.Now().Unix(),
+		})
+	})
+
+	// Announce optimistic proposals the proposal status job executed or
+	// settled after a challenge vote
+	daoInstance.OnOptimisticResolution(func(optimistic dao.OptimisticProposal) {
+		eventType := EventOptimisticExecuted
+		if optimistic.Status == dao.OptimisticStatusRejected {
+			eventType = EventOptimisticRejected
+		}
+		daoServer.broadcastEvent(Event{
+			Type: eventType,
+			Data: map[string]interface{}{
+				"proposal_id": optimistic.ID.String(),
+				"proposer":    optimistic.Proposer.String(),
+				"challenged":  len(optimistic.Challenger) > 0,
+			},
+			Timestamp: time.Now().Unix(),
+		})
+	})
+
 	// Start event bus
 	go eventBus.run()
 
@@ -100,14 +220,21 @@ func NewDAOServer(cfg ServerConfig, bc *core.Blockchain, txChan chan *core.Trans
 
 // Start starts the enhanced DAO API server
 func (s *DAOServer) Start() error {
-	e := echo.New()
+	e := s.newEcho()
 
-	// Enable CORS for web interface
+	// Enable CORS for the configured web origins
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+			origin := c.Request().Header.Get("Origin")
+			if s.Config.Server.AllowsOrigin("*") {
+				c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+			} else if origin != "" && s.Config.Server.AllowsOrigin(origin) {
+				c.Response().Header().Set("Access-Control-Allow-Origin", origin)
+				c.Response().Header().Add("Vary", "Origin")
+			}
 			c.Response().Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+IdempotencyHeader)
+			c.Response().Header().Set("Access-Control-Expose-Headers", "ETag, X-Total-Count, X-Cache, Idempotent-Replayed, Deprecation, Sunset, Link")
 
 			if c.Request().Method == "OPTIONS" {
 				return c.NoContent(http.StatusOK)
@@ -117,71 +244,421 @@ func (s *DAOServer) Start() error {
 		}
 	})
 
+	// Read replicas serve reads only, writes go to the primary
+	if s.Config.Node.ReplicaOf != "" {
+		e.Use(s.readOnly)
+	}
+
+	// Retried submissions with the same Idempotency-Key are not resubmitted
+	if s.idempotency != nil {
+		e.Use(s.idempotency.Middleware)
+	}
+
 	// Serve static web files
 	e.Static("/", "web")
 	e.File("/", "web/index.html")
 
+	// Public endpoints are served under every API version, and those of the
+	// legacy version without a prefix for apps released before versioning
+	routes := NewRouteRegistry(APIVersions...)
+
 	// Base endpoints
-	e.GET("/block/:hashorid", s.handleGetBlock)
-	e.GET("/tx/:hash", s.handleGetTx)
-	e.POST("/tx", s.handlePostTx)
+	routes.GET("/block/:hashorid", s.handleGetBlock)
+	routes.GET("/tx/:hash", s.handleGetTx)
+	routes.POST("/tx", s.handlePostTx)
+	routes.GET("/network/peers", s.handleGetPeers)
+	routes.GET("/chain/feed", s.handleBlockFeed)
+
+	// Light client proof endpoints
+	routes.GET("/proof/balance/:address", s.handleGetBalanceProof)
+	routes.GET("/proof/proposal/:id", s.handleGetProposalProof)
+	routes.GET("/proof/vote/:proposal/:voter", s.handleGetVoteProof)
+
+	// DAO endpoints of the default DAO, and of every DAO under /daos/:daoID
+	s.registerDAORoutes(routes)
+	routes.GET("/daos", s.handleListDAOs)
+	routes.POST("/daos", s.handleCreateDAO)
+	routes.POST("/daos/validate", s.handleValidateDAO)
+	routes.GET("/daos/:daoID", s.handleGetDAO)
+	routes.Any("/daos/:daoID/*", s.handleHostedDAO)
+
+	routes.Mount(e, LegacyAPIVersion, s.versionMiddleware)
+	e.GET("/versions", s.handleGetAPIVersions)
+
+	// Admin endpoints
+	e.GET("/admin/config", s.handleGetConfig)
+	e.GET("/admin/intake", s.handleGetIntake)
+	e.POST("/admin/intake/pause", s.handlePauseIntake)
+	e.POST("/admin/intake/resume", s.handleResumeIntake)
+	e.POST("/admin/snapshot", s.handleCreateSnapshot)
+	e.POST("/admin/logs/rotate", s.handleRotateLogs)
+	e.GET("/admin/logs/level", s.handleGetLogLevel)
+	e.PUT("/admin/logs/level", s.handleSetLogLevel)
+	e.GET("/admin/jobs", s.handleGetJobs)
+	e.POST("/admin/jobs/:name/run", s.handleRunJob)
+	s.registerProfiling(e)
+
+	return s.listen(e)
+}
 
-	// DAO endpoints
-	e.GET("/dao/proposals", s.handleGetProposals)
-	e.GET("/dao/proposal/:id", s.handleGetProposal)
-	e.POST("/dao/proposal", s.handleCreateProposal)
-	e.POST("/dao/vote", s.handleCastVote)
-	e.GET("/dao/proposal/:id/votes", s.handleGetProposalVotes)
+// registerDAORoutes registers the endpoints of the DAO the server serves
+func (s *DAOServer) registerDAORoutes(r *RouteRegistry) {
+	// Proposal endpoints
+	r.GET("/dao/proposals", s.handleGetProposals, s.cached)
+	r.GET("/dao/proposal/:id", s.handleGetProposal)
+	r.POST("/dao/proposal", s.handleCreateProposal)
+	r.GET("/dao/proposal/templates", s.handleGetProposalTemplates)
+	r.POST("/dao/proposal/validate", s.handleValidateProposal)
+	r.POST("/dao/vote", s.handleCastVote)
+	r.GET("/dao/proposal/:id/votes", s.handleGetProposalVotes)
+	r.GET("/dao/proposal/:id/metadata", s.handleGetProposalMetadata)
+	r.GET("/dao/proposal/:id/result", s.handleGetProposalResult)
+	r.GET("/dao/proposal/:id/impact", s.handleGetProposalImpact)
+	r.GET("/dao/proposal/:id/sponsorship", s.handleGetVoteSponsorship)
+	r.GET("/dao/proposal/:id/conditions", s.handleGetProposalConditions)
+	r.GET("/dao/proposal/:id/dependencies", s.handleGetProposalDependencies)
+	r.GET("/dao/proposal/:id/endorsements", s.handleGetProposalEndorsements)
+	r.POST("/dao/proposal/:id/endorse", s.handleEndorseProposal)
+	r.GET("/dao/proposal/:id/simulate", s.handleSimulateProposal)
+	r.POST("/dao/proposal/kpis", s.handleAttachProposalKPIs)
+	r.POST("/dao/proposal/review", s.handleSubmitImpactReview)
+
+	// Optimistic proposal endpoints
+	r.GET("/dao/optimistic", s.handleGetOptimisticProposals)
+	r.GET("/dao/optimistic/:id", s.handleGetOptimisticProposal)
+	r.POST("/dao/optimistic", s.handleCreateOptimisticProposal)
+	r.POST("/dao/optimistic/:id/challenge", s.handleChallengeOptimisticProposal)
 
 	// Treasury endpoints
-	e.GET("/dao/treasury", s.handleGetTreasury)
-	e.GET("/dao/treasury/transactions", s.handleGetTreasuryTransactions)
-	e.POST("/dao/treasury/transaction", s.handleCreateTreasuryTransaction)
-	e.POST("/dao/treasury/sign", s.handleSignTreasuryTransaction)
+	r.GET("/dao/treasury", s.handleGetTreasury, s.cached)
+	r.GET("/dao/treasury/transactions", s.handleGetTreasuryTransactions, s.cached)
+	r.POST("/dao/treasury/transaction", s.handleCreateTreasuryTransaction)
+	r.POST("/dao/treasury/transaction/preview", s.handlePreviewTreasuryTransaction)
+	r.POST("/dao/treasury/sign", s.handleSignTreasuryTransaction)
+	r.POST("/dao/treasury/aggregate", s.handleAttachAggregateSignature)
+	r.GET("/dao/treasury/tss", s.handleGetThresholdKey)
+	r.POST("/dao/treasury/tss/keygen", s.handleStartKeyGeneration)
+	r.POST("/dao/treasury/tss/keygen/deal", s.handleSubmitKeyDealing)
+	r.POST("/dao/treasury/tss/keygen/confirm", s.handleConfirmKeyGeneration)
+	r.POST("/dao/treasury/tss/commit", s.handleCommitThresholdNonce)
+	r.POST("/dao/treasury/tss/partial", s.handleSubmitThresholdPartial)
+	r.GET("/dao/treasury/yield", s.handleGetTreasuryYield)
+	r.GET("/dao/treasury/reports", s.handleGetTreasuryReport)
+	r.GET("/dao/treasury/ragequit", s.handlePreviewRageQuit)
+	r.POST("/dao/treasury/ragequit", s.handleRageQuit)
 
 	// Token endpoints
-	e.GET("/dao/token/balance/:address", s.handleGetTokenBalance)
-	e.GET("/dao/token/supply", s.handleGetTokenSupply)
-	e.POST("/dao/token/transfer", s.handleTokenTransfer)
-	e.POST("/dao/token/approve", s.handleTokenApprove)
-	e.GET("/dao/token/allowance/:owner/:spender", s.handleGetTokenAllowance)
+	r.GET("/dao/token/balance/:address", s.handleGetTokenBalance)
+	r.POST("/dao/token/balances", s.handleGetTokenBalances)
+	r.GET("/dao/token/supply", s.handleGetTokenSupply)
+	r.POST("/dao/token/transfer", s.handleTokenTransfer)
+	r.POST("/dao/token/approve", s.handleTokenApprove)
+	r.GET("/dao/token/allowance/:owner/:spender", s.handleGetTokenAllowance)
 
 	// Delegation endpoints
-	e.POST("/dao/delegate", s.handleDelegate)
-	e.POST("/dao/revoke-delegation", s.handleRevokeDelegation)
-	e.GET("/dao/delegation/:address", s.handleGetDelegation)
-	e.GET("/dao/delegations", s.handleGetDelegations)
+	r.POST("/dao/delegate", s.handleDelegate)
+	r.POST("/dao/revoke-delegation", s.handleRevokeDelegation)
+	r.GET("/dao/delegation/:address", s.handleGetDelegation)
+	r.GET("/dao/delegations", s.handleGetDelegations)
+	r.GET("/dao/delegates", s.handleSearchDelegates, s.cached)
+	r.GET("/dao/delegates/:address", s.handleGetDelegateListing, s.cached)
+	r.POST("/dao/delegates/statement", s.handlePublishDelegateStatement)
+
+	// Parameter endpoints
+	r.GET("/dao/parameters", s.handleGetParameters)
+	r.GET("/dao/parameters/pending", s.handleGetPendingParameters)
+	r.GET("/dao/parameters/simulate", s.handleSimulateParameters)
+	r.POST("/dao/parameters/schedule", s.handleScheduleParameters)
 
 	// Member endpoints
-	e.GET("/dao/member/:address", s.handleGetMember)
-	e.GET("/dao/members", s.handleGetMembers)
+	r.GET("/dao/member/:address", s.handleGetMember)
+	r.GET("/dao/members", s.handleGetMembers)
+	r.GET("/dao/member/:address/activity", s.handleGetMemberActivity)
+	r.GET("/dao/member/:address/positions", s.handleGetMemberPositions)
+	r.GET("/dao/member/:address/statement", s.handleGetMemberStatement)
+	r.GET("/dao/member/:address/privacy", s.handleGetMemberPrivacy)
+	r.POST("/dao/member/privacy", s.handleSetMemberPrivacy)
+	r.GET("/dao/member/:address/profile", s.handleGetMemberProfile)
+	r.PUT("/dao/member/:address/profile", s.handleSetMemberProfile)
+	r.GET("/dao/member/:address/soulbound", s.handleGetMemberSoulboundTokens)
+	r.GET("/dao/soulbound/:id", s.handleGetSoulboundToken)
+
+	// Name registry endpoints
+	r.GET("/dao/names", s.handleGetNames)
+	r.GET("/dao/names/:name", s.handleResolveName)
+	r.POST("/dao/names", s.handleRegisterName)
+	r.POST("/dao/names/:name/renew", s.handleRenewName)
+	r.POST("/dao/names/:name/transfer", s.handleTransferName)
+
+	// Position endpoints
+	r.GET("/dao/position/:id", s.handleGetPosition)
+	r.POST("/dao/position/transfer", s.handleTransferPosition)
+
+	// Bootstrap endpoints
+	r.GET("/dao/bootstrap", s.handleGetBootstrapStatus)
+	r.POST("/dao/bootstrap/veto", s.handleFounderVeto)
+	r.POST("/dao/bootstrap/fast-track", s.handleFastTrackProposal)
+
+	// Transaction status endpoints
+	r.GET("/dao/tx/:hash/status", s.handleGetTxStatus)
+
+	// Snapshot endpoints
+	r.GET("/dao/snapshot", s.handleGetSnapshot)
+	r.GET("/dao/sync", s.handleSync)
+
+	// Dispute endpoints
+	r.GET("/dao/disputes", s.handleGetDisputes)
+	r.GET("/dao/dispute/:id", s.handleGetDispute)
+	r.POST("/dao/dispute", s.handleOpenDispute)
+	r.POST("/dao/dispute/evidence", s.handleSubmitDisputeEvidence)
+	r.POST("/dao/dispute/commit", s.handleCommitJurorRuling)
+	r.POST("/dao/dispute/reveal", s.handleRevealJurorRuling)
+
+	// Validator endpoints
+	r.GET("/dao/validators", s.handleGetValidators)
+	r.GET("/dao/validator/:address", s.handleGetValidator)
+	r.POST("/dao/validator/config", s.handleConfigureValidator)
+	r.POST("/dao/validator/claim", s.handleClaimCommission)
+	r.POST("/dao/validator/set/propose", s.handleProposeValidatorSetChange)
+	r.POST("/dao/validator/set/execute", s.handleExecuteValidatorSetChange)
+	r.GET("/dao/finality", s.handleGetFinality)
+
+	// Multisig endpoints
+	r.POST("/dao/multisig", s.handleCreateMultisig)
+	r.GET("/dao/multisigs", s.handleGetMultisigs)
+	r.GET("/dao/multisig/:id", s.handleGetMultisig)
+	r.POST("/dao/multisig/:id/submit", s.handleSubmitMultisigTx)
+	r.POST("/dao/multisig/:id/sign", s.handleSignMultisigTx)
+
+	// Sub-DAO endpoints
+	r.POST("/dao/subdao/propose", s.handleProposeSubDAO)
+	r.POST("/dao/subdao/execute", s.handleExecuteSubDAO)
+	r.GET("/dao/subdaos", s.handleGetSubDAOs)
+	r.GET("/dao/subdao/:id", s.handleGetSubDAO)
+	r.POST("/dao/subdao/:id/proposal", s.handleCreateSubDAOProposal)
+	r.POST("/dao/subdao/:id/vote", s.handleVoteSubDAOProposal)
+
+	// Grant endpoints
+	r.GET("/dao/grants", s.handleGetGrants)
+	r.GET("/dao/grants/pipeline", s.handleGetGrantPipeline)
+	r.GET("/dao/grants/:id", s.handleGetGrant)
+	r.POST("/dao/grants", s.handleProposeGrant)
+	r.POST("/dao/grants/:id/execute", s.handleExecuteGrant)
+	r.POST("/dao/grants/:id/milestones/:index/submit", s.handleSubmitGrantMilestone)
+	r.POST("/dao/grants/:id/milestones/:index/review", s.handleReviewGrantMilestone)
+	r.POST("/dao/grants/:id/cancel", s.handleCancelGrant)
+
+	// Bounty endpoints
+	r.GET("/dao/bounties", s.handleGetBounties)
+	r.GET("/dao/bounties/:id", s.handleGetBounty)
+	r.POST("/dao/bounties", s.handleProposeBounty)
+	r.POST("/dao/bounties/:id/post", s.handlePostBounty)
+	r.POST("/dao/bounties/:id/claim", s.handleClaimBounty)
+	r.POST("/dao/bounties/:id/submit", s.handleSubmitBountyWork)
+	r.POST("/dao/bounties/:id/review", s.handleReviewBounty)
+	r.POST("/dao/bounties/:id/cancel", s.handleCancelBounty)
+
+	// Quadratic funding endpoints
+	r.GET("/dao/qf/rounds", s.handleGetQFRounds)
+	r.GET("/dao/qf/rounds/:id", s.handleGetQFRound)
+	r.POST("/dao/qf/rounds", s.handleProposeQFRound)
+	r.POST("/dao/qf/rounds/:id/open", s.handleOpenQFRound)
+	r.POST("/dao/qf/rounds/:id/contribute", s.handleContributeQF)
+
+	// Retroactive public goods funding endpoints
+	r.GET("/dao/rpgf/rounds", s.handleGetRPGFRounds)
+	r.GET("/dao/rpgf/rounds/:id", s.handleGetRPGFRound)
+	r.POST("/dao/rpgf/rounds", s.handleProposeRPGFRound)
+	r.POST("/dao/rpgf/rounds/:id/open", s.handleOpenRPGFRound)
+	r.POST("/dao/rpgf/rounds/:id/nominate", s.handleNominateRPGF)
+	r.POST("/dao/rpgf/rounds/:id/ballot", s.handleCastRPGFBallot)
+	r.POST("/dao/rpgf/rounds/:id/finalize", s.handleFinalizeRPGFRound)
+	r.POST("/dao/rpgf/rounds/:id/claim", s.handleClaimRPGF)
+
+	// Oracle endpoints
+	r.GET("/dao/oracles", s.handleGetOracleFeeds)
+	r.GET("/dao/oracles/:id", s.handleGetOracleFeed)
+	r.POST("/dao/oracles", s.handleProposeOracleFeed)
+	r.POST("/dao/oracles/activate", s.handleActivateOracleFeed)
+	r.POST("/dao/oracles/:id/report", s.handleReportOracle)
+	r.GET("/dao/tracks", s.handleGetProposalTracks)
+	r.GET("/dao/tracks/:id", s.handleGetProposalTrack)
+	r.POST("/dao/tracks", s.handleProposeTrack)
+	r.POST("/dao/tracks/activate", s.handleActivateTrack)
+
+	// Governance document endpoints
+	r.GET("/dao/constitution", s.handleGetGovernanceDocuments)
+	r.GET("/dao/constitution/:id", s.handleGetGovernanceDocument)
+	r.GET("/dao/constitution/:id/versions/:version", s.handleGetGovernanceDocumentVersion)
+	r.POST("/dao/constitution/:id/amend", s.handleProposeAmendment)
+	r.POST("/dao/constitution/enact", s.handleEnactAmendment)
+
+	// Emergency spend endpoints
+	r.GET("/dao/emergency", s.handleGetEmergencySpends)
+	r.GET("/dao/emergency/:id", s.handleGetEmergencySpend)
+	r.POST("/dao/emergency", s.handleProposeEmergencySpend)
+	r.POST("/dao/emergency/execute", s.handleExecuteEmergencySpend)
+
+	// Participation reward endpoints
+	r.GET("/dao/rewards/participation", s.handleGetParticipationRewards)
+	r.GET("/dao/rewards/participation/:address", s.handleGetParticipationRewardStatus)
+	r.POST("/dao/rewards/participation/opt-in", s.handleParticipationOptIn)
+
+	// Metadata schema endpoints
+	r.GET("/dao/metadata/schemas", s.handleGetMetadataSchemas)
+	r.GET("/dao/metadata/schemas/:version", s.handleGetMetadataSchema)
+
+	// Notification endpoints
+	r.GET("/dao/notifications/preferences/:address", s.handleGetNotificationPreferences)
+	r.PUT("/dao/notifications/preferences", s.handleSetNotificationPreferences)
+	r.DELETE("/dao/notifications/preferences/:address", s.handleDeleteNotificationPreferences)
+
+	// Webhook endpoints
+	r.GET("/dao/webhooks", s.handleGetWebhooks)
+	r.POST("/dao/webhooks", s.handleCreateWebhook)
+	r.GET("/dao/webhooks/:id", s.handleGetWebhook)
+	r.DELETE("/dao/webhooks/:id", s.handleDeleteWebhook)
+	r.GET("/dao/webhooks/:id/deliveries", s.handleGetWebhookDeliveries)
+	r.POST("/dao/webhooks/:id/test", s.handleTestWebhook)
+
+	// Comment endpoints
+	r.GET("/dao/proposal/:id/comments", s.handleGetProposalComments)
+	r.POST("/dao/proposal/:id/comments", s.handlePostComment)
+	r.POST("/dao/comments/:id/reactions", s.handleReactToComment)
+	r.POST("/dao/comments/:id/moderation", s.handleModerateComment)
+
+	// Draft endpoints
+	r.GET("/dao/drafts", s.handleGetDrafts)
+	r.POST("/dao/drafts", s.handleCreateDraft)
+	r.GET("/dao/drafts/:id", s.handleGetDraft)
+	r.PUT("/dao/drafts/:id", s.handleUpdateDraft)
+	r.DELETE("/dao/drafts/:id", s.handleDeleteDraft)
+	r.POST("/dao/drafts/:id/coauthors", s.handleManageDraftCoAuthor)
+	r.POST("/dao/drafts/:id/submit", s.handleSubmitDraft)
+
+	// Calendar endpoints
+	r.GET("/dao/calendar", s.handleGetCalendar)
+
+	// Membership endpoints
+	r.POST("/dao/membership/apply", s.handleApplyForMembership)
+	r.POST("/dao/membership/approve", s.handleApproveMembership)
+	r.POST("/dao/membership/status", s.handleChangeMembershipStatus)
+	r.GET("/dao/membership/applications", s.handleGetMembershipApplications)
+	r.GET("/dao/membership/:address", s.handleGetMembership)
 
 	// Analytics endpoints
-	e.GET("/dao/analytics/participation", s.handleGetParticipationMetrics)
-	e.GET("/dao/analytics/treasury", s.handleGetTreasuryMetrics)
-	e.GET("/dao/analytics/proposals", s.handleGetProposalAnalytics)
-	e.GET("/dao/analytics/health", s.handleGetHealthMetrics)
-	e.GET("/dao/analytics/summary", s.handleGetAnalyticsSummary)
+	r.GET("/dao/analytics/participation", s.handleGetParticipationMetrics, s.cached)
+	r.GET("/dao/analytics/treasury", s.handleGetTreasuryMetrics, s.cached)
+	r.GET("/dao/analytics/proposals", s.handleGetProposalAnalytics, s.cached)
+	r.GET("/dao/analytics/proposal/:id", s.handleGetProposalTurnout, s.cached)
+	r.GET("/dao/analytics/health", s.handleGetHealthMetrics, s.cached)
+	r.GET("/dao/analytics/summary", s.handleGetAnalyticsSummary, s.cached)
+	r.GET("/dao/analytics/staking", s.handleGetStakingYieldMetrics, s.cached)
+	r.GET("/dao/analytics/public-goods", s.handleGetPublicGoodsMetrics, s.cached)
+	r.GET("/dao/analytics/subdaos", s.handleGetSubDAOAnalytics, s.cached)
+	r.GET("/dao/analytics/timeseries", s.handleGetMetricTimeSeries, s.cached)
+	r.GET("/dao/analytics/delegates", s.handleGetDelegateScorecards, s.cached)
+	r.GET("/dao/analytics/delegates/:address", s.handleGetDelegateScorecard, s.cached)
+	r.GET("/dao/analytics/quorum", s.handleGetAdaptiveQuorum, s.cached)
+
+	// Data export
+	r.GET("/dao/export", s.handleExport)
 
 	// WebSocket endpoint for real-time events
-	e.GET("/dao/events", s.handleWebSocket)
-
-	return e.Start(s.ListenAddr)
+	r.GET("/dao/events", s.handleWebSocket)
+	r.POST("/dao/events/token", s.handleIssueEventToken)
+	// Server-Sent Events for clients that cannot open WebSockets
+	r.GET("/dao/events/stream", s.handleEventStream)
 }
 
 // Event types for WebSocket broadcasting
 type EventType string
 
 const (
-	EventProposalCreated  EventType = "proposal_created"
-	EventVoteCast         EventType = "vote_cast"
-	EventProposalPassed   EventType = "proposal_passed"
-	EventProposalRejected EventType = "proposal_rejected"
-	EventTreasuryTx       EventType = "treasury_transaction"
-	EventDelegation       EventType = "delegation_updated"
+	EventProposalCreated   EventType = "proposal_created"
+	EventVoteCast          EventType = "vote_cast"
+	EventProposalPassed    EventType = "proposal_passed"
+	EventProposalRejected  EventType = "proposal_rejected"
+	EventTreasuryTx        EventType = "treasury_transaction"
+	EventTreasuryExecuted  EventType = "treasury_executed"
+	EventDelegation        EventType = "delegation_updated"
+	EventDelegationExpired EventType = "delegation_expired"
+	EventDelegationRenewed EventType = "delegation_renewed"
+	EventRageQuit          EventType = "rage_quit"
+
+	EventOptimisticCreated    EventType = "optimistic_proposal_created"
+	EventOptimisticChallenged EventType = "optimistic_proposal_challenged"
+	EventOptimisticExecuted   EventType = "optimistic_proposal_executed"
+	EventOptimisticRejected   EventType = "optimistic_proposal_rejected"
+
+	EventBountyProposed  EventType = "bounty_proposed"
+	EventBountyPosted    EventType = "bounty_posted"
+	EventBountyClaimed   EventType = "bounty_claimed"
+	EventBountySubmitted EventType = "bounty_submitted"
+	EventBountyApproved  EventType = "bounty_approved"
+	EventBountyDisputed  EventType = "bounty_disputed"
+	EventBountyCancelled EventType = "bounty_cancelled"
+
+	EventQFRoundProposed EventType = "qf_round_proposed"
+	EventQFRoundOpened   EventType = "qf_round_opened"
+	EventQFContribution  EventType = "qf_contribution"
+
+	EventRPGFRoundProposed  EventType = "rpgf_round_proposed"
+	EventRPGFRoundOpened    EventType = "rpgf_round_opened"
+	EventRPGFNominated      EventType = "rpgf_nominated"
+	EventRPGFBallotCast     EventType = "rpgf_ballot_cast"
+	EventRPGFRoundFinalized EventType = "rpgf_round_finalized"
+	EventRPGFClaimed        EventType = "rpgf_claimed"
+
+	EventOracleFeedProposed  EventType = "oracle_feed_proposed"
+	EventOracleFeedActivated EventType = "oracle_feed_activated"
+	EventOracleReported      EventType = "oracle_reported"
+
+	EventTrackProposed  EventType = "proposal_track_proposed"
+	EventTrackActivated EventType = "proposal_track_activated"
+
+	EventParametersScheduled EventType = "parameters_scheduled"
+
+	EventAmendmentProposed EventType = "constitution_amendment_proposed"
+	EventAmendmentEnacted  EventType = "constitution_amended"
+
+	EventEmergencySpendProposed EventType = "emergency_spend_proposed"
+	EventEmergencySpendExecuted EventType = "emergency_spend_executed"
+
+	EventParticipationOptIn EventType = "participation_opt_in"
+
+	EventProposalEndorsed EventType = "proposal_endorsed"
+
+	EventMemberPrivacyUpdated EventType = "member_privacy_updated"
+
+	EventNameRegistered  EventType = "name_registered"
+	EventNameRenewed     EventType = "name_renewed"
+	EventNameTransferred EventType = "name_transferred"
+
+	EventMemberProfileUpdated EventType = "member_profile_updated"
+
+	EventDelegateStatementUpdated EventType = "delegate_statement_updated"
+
+	EventCommentPosted    EventType = "comment_posted"
+	EventCommentReacted   EventType = "comment_reacted"
+	EventCommentModerated EventType = "comment_moderated"
+
+	EventMembershipApplied  EventType = "membership_applied"
+	EventMembershipApproved EventType = "membership_approved"
+	EventMembershipStatus   EventType = "membership_status"
+
+	EventTxStatus EventType = "tx_status"
+
+	EventDAOCreated EventType = "dao_created"
+
+	// Private channel events
+	EventTreasurySignatureRequested EventType = "treasury_signature_requested"
+	EventDelegationActivity         EventType = "delegation_activity"
 )
 
 type Event struct {
+	ID        uint64      `json:"id,omitempty"`      // Sequence number on this node
+	Channel   string      `json:"channel,omitempty"` // Private channel, empty for public events
 	Type      EventType   `json:"type"`
 	Data      interface{} `json:"data"`
 	Timestamp int64       `json:"timestamp"`
@@ -189,22 +666,65 @@ type Event struct {
 
 // DAO API Response Types
 type ProposalResponse struct {
-	ID           string             `json:"id"`
-	Creator      string             `json:"creator"`
-	Title        string             `json:"title"`
-	Description  string             `json:"description"`
-	ProposalType dao.ProposalType   `json:"proposal_type"`
-	VotingType   dao.VotingType     `json:"voting_type"`
-	StartTime    int64              `json:"start_time"`
-	EndTime      int64              `json:"end_time"`
-	Status       dao.ProposalStatus `json:"status"`
-	Threshold    uint64             `json:"threshold"`
-	Results      *dao.VoteResults   `json:"results,omitempty"`
-	MetadataHash string             `json:"metadata_hash"`
+	ID             string             `json:"id"`
+	Creator        string             `json:"creator"`
+	CreatorName    string             `json:"creator_name,omitempty"` // Registered name of the creator
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	ProposalType   dao.ProposalType   `json:"proposal_type"`
+	VotingType     dao.VotingType     `json:"voting_type"`
+	StartTime      int64              `json:"start_time"`
+	EndTime        int64              `json:"end_time"`
+	Status         dao.ProposalStatus `json:"status"`
+	Threshold      uint64             `json:"threshold"`
+	Quorum         uint64             `json:"quorum,omitempty"`          // Participation required above the DAO quorum
+	AdaptiveQuorum uint64             `json:"adaptive_quorum,omitempty"` // DAO quorum recent turnout set at creation
+	Results        *dao.VoteResults   `json:"results,omitempty"`
+	MetadataHash   string             `json:"metadata_hash"`
+	ResultHash     string             `json:"result_hash,omitempty"`    // Signed result document on IPFS
+	Track          string             `json:"track,omitempty"`          // Track whose rules the proposal follows
+	Timelock       int64              `json:"timelock,omitempty"`       // Seconds after the vote ends before execution
+	QueuePosition  int                `json:"queue_position,omitempty"` // Place in the track's queue, 1 is next to open
+	Finalized      bool               `json:"finalized"`                // Created in a block finalized by the validator set
+}
+
+// OptimisticProposalResponse is an optimistic proposal. Once challenged its
+// vote is the proposal with the same ID.
+type OptimisticProposalResponse struct {
+	ID              string           `json:"id"`
+	Proposer        string           `json:"proposer"`
+	Title           string           `json:"title"`
+	Description     string           `json:"description"`
+	ProposalType    dao.ProposalType `json:"proposal_type"`
+	VotingType      dao.VotingType   `json:"voting_type"`
+	Threshold       uint64           `json:"threshold"`
+	MetadataHash    string           `json:"metadata_hash,omitempty"`
+	VotingPeriod    int64            `json:"voting_period"`
+	Bond            uint64           `json:"bond"`
+	Status          string           `json:"status"`
+	CreatedAt       int64            `json:"created_at"`
+	ChallengeEnds   int64            `json:"challenge_ends"`
+	Challenger      string           `json:"challenger,omitempty"`
+	ChallengeReason string           `json:"challenge_reason,omitempty"`
+	ChallengedAt    int64            `json:"challenged_at,omitempty"`
+	ResolvedAt      int64            `json:"resolved_at,omitempty"`
+}
+
+type VoteSponsorshipResponse struct {
+	ProposalID      string `json:"proposal_id"`
+	Budget          uint64 `json:"budget"`
+	Spent           uint64 `json:"spent"`
+	Remaining       uint64 `json:"remaining"`
+	SponsoredVoters int    `json:"sponsored_voters"`
+	WindowStart     int64  `json:"window_start"`
+	WindowEnd       int64  `json:"window_end"`
+	Active          bool   `json:"active"`
+	Released        bool   `json:"released"`
 }
 
 type VoteResponse struct {
 	Voter     string         `json:"voter"`
+	VoterName string         `json:"voter_name,omitempty"`
 	Choice    dao.VoteChoice `json:"choice"`
 	Weight    uint64         `json:"weight"`
 	Timestamp int64          `json:"timestamp"`
@@ -218,655 +738,1105 @@ type TreasuryResponse struct {
 }
 
 type TreasuryTransactionResponse struct {
-	ID         string   `json:"id"`
-	Recipient  string   `json:"recipient"`
-	Amount     uint64   `json:"amount"`
-	Purpose    string   `json:"purpose"`
-	Signatures []string `json:"signatures"`
-	CreatedAt  int64    `json:"created_at"`
-	ExpiresAt  int64    `json:"expires_at"`
-	Executed   bool     `json:"executed"`
+	ID            string                      `json:"id"`
+	Recipient     string                      `json:"recipient"`
+	Amount        uint64                      `json:"amount"`
+	Purpose       string                      `json:"purpose"`
+	Signatures    []string                    `json:"signatures"`
+	Aggregate     *AggregateSignatureResponse `json:"aggregate,omitempty"` // Replaces signatures when set
+	Threshold     *ThresholdSigningResponse   `json:"threshold,omitempty"` // Signing with the treasury threshold key
+	CreatedAt     int64                       `json:"created_at"`
+	ExpiresAt     int64                       `json:"expires_at"`
+	Executed      bool                        `json:"executed"`
+	ExecutedAt    int64                       `json:"executed_at,omitempty"`
+	Denomination  string                      `json:"denomination,omitempty"`
+	StableAmount  uint64                      `json:"stable_amount,omitempty"`
+	QuotedAmount  uint64                      `json:"quoted_amount,omitempty"`
+	Requeues      int                         `json:"requeues,omitempty"`
+	RequeueReason string                      `json:"requeue_reason,omitempty"`
 }
 
 type DelegationResponse struct {
-	Delegator string `json:"delegator"`
-	Delegate  string `json:"delegate"`
-	StartTime int64  `json:"start_time"`
-	EndTime   int64  `json:"end_time"`
-	Active    bool   `json:"active"`
+	Delegator     string `json:"delegator"`
+	DelegatorName string `json:"delegator_name,omitempty"`
+	Delegate      string `json:"delegate"`
+	DelegateName  string `json:"delegate_name,omitempty"`
+	StartTime     int64  `json:"start_time"`
+	EndTime       int64  `json:"end_time"`
+	Active        bool   `json:"active"`
+	AutoRenew     bool   `json:"auto_renew"`
 }
 
 type MemberResponse struct {
-	Address    string `json:"address"`
-	Balance    uint64 `json:"balance"`
-	Staked     uint64 `json:"staked"`
-	Reputation uint64 `json:"reputation"`
-	JoinedAt   int64  `json:"joined_at"`
-	LastActive int64  `json:"last_active"`
+	Address    string   `json:"address"`
+	Name       string   `json:"name,omitempty"`         // Registered name
+	Profile    string   `json:"profile_hash,omitempty"` // IPFS hash of the member's profile
+	Balance    uint64   `json:"balance"`
+	Staked     uint64   `json:"staked"`
+	Reputation uint64   `json:"reputation"`
+	JoinedAt   int64    `json:"joined_at"`
+	LastActive int64    `json:"last_active"`
+	Status     string   `json:"status"`
+	Hidden     []string `json:"hidden,omitempty"` // Fields the member's privacy settings withhold from the viewer, reported as 0
 }
 
-// Proposal endpoints
-func (s *DAOServer) handleGetProposals(c echo.Context) error {
-	proposals := s.dao.ListAllProposals()
-	response := make([]ProposalResponse, len(proposals))
+type ActivityResponse struct {
+	TxHash       string `json:"tx_hash"`
+	TxType       string `json:"tx_type"`
+	Role         string `json:"role"`
+	Counterparty string `json:"counterparty,omitempty"`
+	Amount       uint64 `json:"amount"`
+	Fee          uint64 `json:"fee,omitempty"`
+	BlockHeight  uint32 `json:"block_height"`
+	Timestamp    int64  `json:"timestamp"`
+}
 
-	for i, proposal := range proposals {
-		response[i] = ProposalResponse{
-			ID:           proposal.ID.String(),
-			Creator:      proposal.Creator.String(),
-			Title:        proposal.Title,
-			Description:  proposal.Description,
-			ProposalType: proposal.ProposalType,
-			VotingType:   proposal.VotingType,
-			StartTime:    proposal.StartTime,
-			EndTime:      proposal.EndTime,
-			Status:       proposal.Status,
-			Threshold:    proposal.Threshold,
-			Results:      proposal.Results,
-			MetadataHash: proposal.MetadataHash.String(),
-		}
-	}
+type PositionTransferResponse struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Timestamp int64  `json:"timestamp"`
+	TxHash    string `json:"tx_hash"`
+}
 
-	return c.JSON(http.StatusOK, response)
+// SoulboundTokenResponse is a non-transferable membership or role token
+type SoulboundTokenResponse struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"` // "membership" or "role"
+	Holder   string `json:"holder"`
+	Role     string `json:"role,omitempty"` // Role of role tokens
+	Name     string `json:"name"`
+	IssuedAt int64  `json:"issued_at"`
+	BurnedAt int64  `json:"burned_at,omitempty"`
+	Active   bool   `json:"active"`
 }
 
-func (s *DAOServer) handleGetProposal(c echo.Context) error {
-	idStr := c.Param("id")
+type PositionResponse struct {
+	ID           string                     `json:"id"`
+	Type         string                     `json:"type"`
+	Owner        string                     `json:"owner"`
+	SourceID     string                     `json:"source_id"`
+	Amount       uint64                     `json:"amount"`
+	UnlockTime   int64                      `json:"unlock_time"`
+	CreatedAt    int64                      `json:"created_at"`
+	Closed       bool                       `json:"closed"`
+	Transferable bool                       `json:"transferable"`
+	History      []PositionTransferResponse `json:"history"`
+}
 
-	idBytes, err := hex.DecodeString(idStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid proposal ID format"})
-	}
+type DisputeEvidenceResponse struct {
+	Submitter   string `json:"submitter"`
+	Hash        string `json:"hash"`
+	Description string `json:"description"`
+	SubmittedAt int64  `json:"submitted_at"`
+}
 
-	proposalID := types.HashFromBytes(idBytes)
-	proposal, err := s.dao.GetProposal(proposalID)
-	if err != nil {
-		return c.JSON(http.StatusNotFound, APIError{Error: "proposal not found"})
-	}
+type DisputeResponse struct {
+	ID               string                    `json:"id"`
+	Type             string                    `json:"type"`
+	Claimant         string                    `json:"claimant"`
+	Respondent       string                    `json:"respondent,omitempty"`
+	Subject          string                    `json:"subject"`
+	Amount           uint64                    `json:"amount"`
+	Description      string                    `json:"description"`
+	Bond             uint64                    `json:"bond"`
+	Phase            string                    `json:"phase"`
+	Evidence         []DisputeEvidenceResponse `json:"evidence"`
+	Jurors           []string                  `json:"jurors"`
+	Commitments      int                       `json:"commitments"`
+	Reveals          int                       `json:"reveals"`
+	OpenedAt         int64                     `json:"opened_at"`
+	EvidenceDeadline int64                     `json:"evidence_deadline"`
+	CommitDeadline   int64                     `json:"commit_deadline"`
+	RevealDeadline   int64                     `json:"reveal_deadline"`
+	Ruling           string                    `json:"ruling,omitempty"`
+	ResolvedAt       int64                     `json:"resolved_at,omitempty"`
+	Executed         uint64                    `json:"executed"`
+}
 
-	response := ProposalResponse{
-		ID:           proposal.ID.String(),
-		Creator:      proposal.Creator.String(),
-		Title:        proposal.Title,
-		Description:  proposal.Description,
-		ProposalType: proposal.ProposalType,
-		VotingType:   proposal.VotingType,
-		StartTime:    proposal.StartTime,
-		EndTime:      proposal.EndTime,
-		Status:       proposal.Status,
-		Threshold:    proposal.Threshold,
-		Results:      proposal.Results,
-		MetadataHash: proposal.MetadataHash.String(),
-	}
+type ImpactReviewResponse struct {
+	Reviewer   string            `json:"reviewer"`
+	Actuals    map[string]uint64 `json:"actuals"`
+	ReportHash string            `json:"report_hash"`
+	ReviewedAt int64             `json:"reviewed_at"`
+}
 
-	return c.JSON(http.StatusOK, response)
+type FundingImpactResponse struct {
+	ProposalID  string                 `json:"proposal_id"`
+	Category    string                 `json:"category"`
+	Grantee     string                 `json:"grantee"`
+	Amount      uint64                 `json:"amount"`
+	KPIs        []*dao.KPI             `json:"kpis"`
+	Reviews     []ImpactReviewResponse `json:"reviews"`
+	Achievement float64                `json:"achievement"`
+	AttachedAt  int64                  `json:"attached_at"`
 }
 
-func (s *DAOServer) handleCreateProposal(c echo.Context) error {
-	var req struct {
-		Title        string           `json:"title"`
-		Description  string           `json:"description"`
-		ProposalType dao.ProposalType `json:"proposal_type"`
-		VotingType   dao.VotingType   `json:"voting_type"`
-		Duration     int64            `json:"duration"` // Duration in seconds
-		Threshold    uint64           `json:"threshold"`
-		MetadataHash string           `json:"metadata_hash"`
-		PrivateKey   string           `json:"private_key"` // For signing
-	}
+type ValidatorResponse struct {
+	Address              string `json:"address"`
+	PoolID               string `json:"pool_id"`
+	CommissionBps        uint64 `json:"commission_bps"`
+	LastCommissionChange int64  `json:"last_commission_change"`
+	BlocksProduced       uint64 `json:"blocks_produced"`
+	FeesEarned           uint64 `json:"fees_earned"`
+	CommissionEarned     uint64 `json:"commission_earned"`
+	DelegatorRewards     uint64 `json:"delegator_rewards"`
+	ClaimableCommission  uint64 `json:"claimable_commission"`
+	DelegatedStake       uint64 `json:"delegated_stake"`
+	DelegatorCount       int    `json:"delegator_count"`
+	RegisteredAt         int64  `json:"registered_at"`
+	Active               bool   `json:"active"`
+}
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
-	}
+type ValidatorPowerResponse struct {
+	Address string `json:"address"`
+	Power   uint64 `json:"power"`
+}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
-	}
+type FinalityResponse struct {
+	Finalized       bool                     `json:"finalized"`
+	FinalizedHeight uint32                   `json:"finalized_height"`
+	FinalizedHash   string                   `json:"finalized_hash,omitempty"`
+	Validators      []ValidatorPowerResponse `json:"validators"`
+	TotalPower      uint64                   `json:"total_power"`
+}
 
-	// Parse metadata hash
-	var metadataHash types.Hash
-	if req.MetadataHash != "" {
-		metadataBytes, err := hex.DecodeString(req.MetadataHash)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, APIError{Error: "invalid metadata hash format"})
-		}
-		metadataHash = types.HashFromBytes(metadataBytes)
-	}
+type MultisigTransactionResponse struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Submitter   string   `json:"submitter"`
+	Approvals   []string `json:"approvals"`
+	SubmittedAt int64    `json:"submitted_at"`
+	Executed    bool     `json:"executed"`
+	ExecutedAt  int64    `json:"executed_at,omitempty"`
+	ExecutedIn  string   `json:"executed_in,omitempty"`
+}
 
-	// Create proposal transaction
-	proposalTx := &dao.ProposalTx{
-		Fee:          1000, // Fixed fee for now
-		Title:        req.Title,
-		Description:  req.Description,
-		ProposalType: req.ProposalType,
-		VotingType:   req.VotingType,
-		StartTime:    time.Now().Unix(),
-		EndTime:      time.Now().Unix() + req.Duration,
-		Threshold:    req.Threshold,
-		MetadataHash: metadataHash,
-	}
+type MultisigResponse struct {
+	ID           string                        `json:"id"`
+	Name         string                        `json:"name"`
+	Account      string                        `json:"account"` // Member key the multisig acts as
+	Address      string                        `json:"address"`
+	Owners       []string                      `json:"owners"`
+	Threshold    uint8                         `json:"threshold"`
+	Creator      string                        `json:"creator"`
+	CreatedAt    int64                         `json:"created_at"`
+	Balance      uint64                        `json:"balance"`
+	Transactions []MultisigTransactionResponse `json:"transactions,omitempty"`
+}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: proposalTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
-	}
+// MultisigActionRequest describes the DAO transaction a multisig submits.
+// Type selects the transaction and the fields it uses.
+type MultisigActionRequest struct {
+	Type string `json:"type"` // token_transfer, vote, proposal, delegation, grant_milestone_review or qf_payout
 
-	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
-	}
+	Recipient string `json:"recipient"`
+	Amount    uint64 `json:"amount"`
 
-	// Send transaction
-	s.txChan <- tx
+	ProposalID string         `json:"proposal_id"`
+	Choice     dao.VoteChoice `json:"choice"`
+	Weight     uint64         `json:"weight"`
+	Reason     string         `json:"reason"`
 
-	// Broadcast event
-	event := Event{
-		Type: EventProposalCreated,
-		Data: map[string]interface{}{
-			"title":   req.Title,
-			"creator": privKey.PublicKey().String(),
-		},
-		Timestamp: time.Now().Unix(),
-	}
-	s.broadcastEvent(event)
+	Title        string           `json:"title"`
+	Description  string           `json:"description"`
+	ProposalType dao.ProposalType `json:"proposal_type"`
+	VotingType   dao.VotingType   `json:"voting_type"`
+	Duration     int64            `json:"duration"` // Voting or delegation duration in seconds
+	Threshold    uint64           `json:"threshold"`
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "proposal created successfully",
-	})
-}
+	Delegate  string `json:"delegate"`
+	Revoke    bool   `json:"revoke"`
+	AutoRenew bool   `json:"auto_renew"`
 
-func (s *DAOServer) handleCastVote(c echo.Context) error {
-	var req struct {
-		ProposalID string         `json:"proposal_id"`
-		Choice     dao.VoteChoice `json:"choice"`
-		Weight     uint64         `json:"weight"`
-		Reason     string         `json:"reason"`
-		PrivateKey string         `json:"private_key"`
-	}
+	GrantID   string `json:"grant_id"`
+	Milestone uint8  `json:"milestone"`
+	Approve   bool   `json:"approve"`
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
-	}
+	RoundID string `json:"round_id"`
+}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
-	}
+type SubDAOProposalResponse struct {
+	ID          string `json:"id"`
+	Proposer    string `json:"proposer"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Action      string `json:"action"`
+	Target      string `json:"target,omitempty"`
+	Amount      uint64 `json:"amount,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	EndTime     int64  `json:"end_time"`
+	YesVotes    uint64 `json:"yes_votes"`
+	NoVotes     uint64 `json:"no_votes"`
+	Status      string `json:"status"`
+	ResolvedAt  int64  `json:"resolved_at,omitempty"`
+}
 
-	// Parse proposal ID
-	proposalIDBytes, err := hex.DecodeString(req.ProposalID)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid proposal ID format"})
-	}
+type SubDAOResponse struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Members     []string                 `json:"members"`
+	Budget      uint64                   `json:"budget"`
+	StreamStart int64                    `json:"stream_start"`
+	StreamEnd   int64                    `json:"stream_end"`
+	Vested      uint64                   `json:"vested"`
+	Streamed    uint64                   `json:"streamed"`
+	Spent       uint64                   `json:"spent"`
+	Balance     uint64                   `json:"balance"`
+	CreatedAt   int64                    `json:"created_at"`
+	Proposals   []SubDAOProposalResponse `json:"proposals,omitempty"`
+}
 
-	proposalID := types.HashFromBytes(proposalIDBytes)
+type GrantMilestoneResponse struct {
+	Index       int    `json:"index"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Amount      uint64 `json:"amount"`
+	Status      string `json:"status"`
+	Deliverable string `json:"deliverable,omitempty"`
+	SubmittedAt int64  `json:"submitted_at,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	ReviewedAt  int64  `json:"reviewed_at,omitempty"`
+	PaidAt      int64  `json:"paid_at,omitempty"`
+}
 
-	// Create vote transaction
-	voteTx := &dao.VoteTx{
-		Fee:        500, // Fixed fee for now
-		ProposalID: proposalID,
-		Choice:     req.Choice,
-		Weight:     req.Weight,
-		Reason:     req.Reason,
-	}
+type GrantResponse struct {
+	ID          string                   `json:"id"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Applicant   string                   `json:"applicant"`
+	Committee   string                   `json:"committee"`
+	Milestones  []GrantMilestoneResponse `json:"milestones"`
+	Total       uint64                   `json:"total"`
+	Escrowed    uint64                   `json:"escrowed"`
+	Paid        uint64                   `json:"paid"`
+	Status      string                   `json:"status"`
+	CreatedAt   int64                    `json:"created_at"`
+	FundedAt    int64                    `json:"funded_at,omitempty"`
+	ClosedAt    int64                    `json:"closed_at,omitempty"`
+}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: voteTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
-	}
+type BountyResponse struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Reward        uint64 `json:"reward"`
+	Reviewer      string `json:"reviewer"`
+	ClaimPeriod   int64  `json:"claim_period"`
+	Proposer      string `json:"proposer"`
+	Status        string `json:"status"`
+	Claimant      string `json:"claimant,omitempty"`
+	ClaimedAt     int64  `json:"claimed_at,omitempty"`
+	ClaimExpires  int64  `json:"claim_expires,omitempty"`
+	WorkHash      string `json:"work_hash,omitempty"`
+	SubmittedAt   int64  `json:"submitted_at,omitempty"`
+	ReviewComment string `json:"review_comment,omitempty"`
+	ReviewedAt    int64  `json:"reviewed_at,omitempty"`
+	CreatedAt     int64  `json:"created_at"`
+	PostedAt      int64  `json:"posted_at,omitempty"`
+	ClosedAt      int64  `json:"closed_at,omitempty"`
+}
 
-	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
-	}
+// QFProjectResponse is a project of a quadratic funding round. Matching is
+// paid with the payout, until then EstimatedMatching is what the round's
+// contributions would earn if it closed now.
+type QFProjectResponse struct {
+	Index             int    `json:"index"`
+	Name              string `json:"name"`
+	Description       string `json:"description,omitempty"`
+	Recipient         string `json:"recipient"`
+	Raised            uint64 `json:"raised"`
+	Contributors      int    `json:"contributors"`
+	Matching          uint64 `json:"matching"`
+	EstimatedMatching uint64 `json:"estimated_matching,omitempty"`
+}
 
-	// Send transaction
-	s.txChan <- tx
+type QFRoundResponse struct {
+	ID                 string              `json:"id"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description"`
+	MatchingPool       uint64              `json:"matching_pool"`
+	Dampening          uint64              `json:"dampening"`
+	ContributionPeriod int64               `json:"contribution_period"`
+	Multisig           string              `json:"multisig"`
+	Proposer           string              `json:"proposer"`
+	Status             string              `json:"status"`
+	Projects           []QFProjectResponse `json:"projects"`
+	CreatedAt          int64               `json:"created_at"`
+	OpenedAt           int64               `json:"opened_at,omitempty"`
+	ClosesAt           int64               `json:"closes_at,omitempty"`
+	PaidAt             int64               `json:"paid_at,omitempty"`
+	Matched            uint64              `json:"matched"`
+	Returned           uint64              `json:"returned"`
+}
 
-	// Broadcast event
-	event := Event{
-		Type: EventVoteCast,
-		Data: map[string]interface{}{
-			"proposal_id": req.ProposalID,
-			"voter":       privKey.PublicKey().String(),
-			"choice":      req.Choice,
-		},
-		Timestamp: time.Now().Unix(),
-	}
-	s.broadcastEvent(event)
+// RPGFNominationResponse is a nomination of a retroactive funding round.
+// Allocation is set when the round is finalized and Claimable is the part
+// of it streamed but not yet claimed.
+type RPGFNominationResponse struct {
+	Index       int    `json:"index"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Recipient   string `json:"recipient"`
+	Nominator   string `json:"nominator"`
+	NominatedAt int64  `json:"nominated_at"`
+	Allocation  uint64 `json:"allocation"`
+	Claimed     uint64 `json:"claimed"`
+	Claimable   uint64 `json:"claimable"`
+}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "vote cast successfully",
-	})
+// RPGFRoundResponse is a retroactive funding round. Ballots stay private,
+// only which badge holders voted is shown.
+type RPGFRoundResponse struct {
+	ID               string                   `json:"id"`
+	Title            string                   `json:"title"`
+	Description      string                   `json:"description"`
+	Budget           uint64                   `json:"budget"`
+	BadgeHolders     []string                 `json:"badge_holders"`
+	Voted            []string                 `json:"voted"`
+	NominationPeriod int64                    `json:"nomination_period"`
+	VotingPeriod     int64                    `json:"voting_period"`
+	StreamDuration   int64                    `json:"stream_duration"`
+	Proposer         string                   `json:"proposer"`
+	Status           string                   `json:"status"`
+	Nominations      []RPGFNominationResponse `json:"nominations"`
+	CreatedAt        int64                    `json:"created_at"`
+	OpenedAt         int64                    `json:"opened_at,omitempty"`
+	NominationsClose int64                    `json:"nominations_close,omitempty"`
+	VotingCloses     int64                    `json:"voting_closes,omitempty"`
+	StreamStart      int64                    `json:"stream_start,omitempty"`
+	StreamEnd        int64                    `json:"stream_end,omitempty"`
+	Allocated        uint64                   `json:"allocated"`
+	Returned         uint64                   `json:"returned"`
 }
 
-func (s *DAOServer) handleGetProposalVotes(c echo.Context) error {
-	idStr := c.Param("id")
+// OracleReportResponse is the latest report of one of a feed's oracles
+type OracleReportResponse struct {
+	Oracle     string `json:"oracle"`
+	Value      int64  `json:"value"`
+	ObservedAt int64  `json:"observed_at"`
+	ReportedAt int64  `json:"reported_at"`
+	Fresh      bool   `json:"fresh"`
+}
 
-	idBytes, err := hex.DecodeString(idStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid proposal ID format"})
-	}
+// OracleFeedResponse is an oracle feed. Value is omitted while the feed is
+// stale.
+type OracleFeedResponse struct {
+	ID           string                 `json:"id"`
+	Description  string                 `json:"description,omitempty"`
+	Decimals     uint8                  `json:"decimals"`
+	Oracles      []string               `json:"oracles"`
+	MinReports   uint16                 `json:"min_reports"`
+	MaxAge       int64                  `json:"max_age"`
+	ProposalID   string                 `json:"proposal_id"`
+	ActivatedAt  int64                  `json:"activated_at"`
+	Value        *int64                 `json:"value,omitempty"`
+	FreshReports int                    `json:"fresh_reports"`
+	Stale        bool                   `json:"stale"`
+	Reports      []OracleReportResponse `json:"reports"`
+}
 
-	proposalID := types.HashFromBytes(idBytes)
-	votes, err := s.dao.GetVotes(proposalID)
-	if err != nil {
-		return c.JSON(http.StatusNotFound, APIError{Error: "proposal not found"})
-	}
+// ProposalTrackResponse is a proposal track and its voting rules
+type ProposalTrackResponse struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	VotingType  dao.VotingType     `json:"voting_type"`
+	Quorum      uint64             `json:"quorum"`
+	Threshold   uint64             `json:"threshold"`
+	Timelock    int64              `json:"timelock"`
+	Deposit     uint64             `json:"deposit"`
+	Actions     []dao.ProposalType `json:"actions"`
+	MaxActive   uint16             `json:"max_active"` // Open proposals allowed at once, 0 for no limit
+	Queue       []string           `json:"queue"`      // Proposals waiting for a slot, first in line first
+	ProposalID  string             `json:"proposal_id"`
+	ActivatedAt int64              `json:"activated_at"`
+}
 
-	response := make([]VoteResponse, 0, len(votes))
-	for _, vote := range votes {
-		response = append(response, VoteResponse{
-			Voter:     vote.Voter.String(),
-			Choice:    vote.Choice,
-			Weight:    vote.Weight,
-			Timestamp: vote.Timestamp,
-			Reason:    vote.Reason,
-		})
-	}
+// ProposalTrackRequest is the track of a track proposal
+type ProposalTrackRequest struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	VotingType dao.VotingType     `json:"voting_type"`
+	Quorum     uint64             `json:"quorum"`
+	Threshold  uint64             `json:"threshold"`
+	Timelock   int64              `json:"timelock"`
+	Deposit    uint64             `json:"deposit"`
+	Actions    []dao.ProposalType `json:"actions"`
+	MaxActive  uint16             `json:"max_active"`
+}
 
-	return c.JSON(http.StatusOK, response)
+// OracleConditionRequest is an oracle condition of a proposal being created
+type OracleConditionRequest struct {
+	FeedID   string `json:"feed_id"`
+	Operator string `json:"operator"`
+	Value    int64  `json:"value"`
 }
 
-// Treasury endpoints
-func (s *DAOServer) handleGetTreasury(c echo.Context) error {
-	signers := s.dao.GetTreasurySigners()
-	signerStrings := make([]string, len(signers))
-	for i, signer := range signers {
-		signerStrings[i] = signer.String()
-	}
+// OracleConditionResponse is an oracle condition of a proposal with the
+// current value of its feed, or the error envelope of a feed that could not
+// be read
+type OracleConditionResponse struct {
+	FeedID   string    `json:"feed_id"`
+	Operator string    `json:"operator"`
+	Value    int64     `json:"value"`
+	Current  *int64    `json:"current,omitempty"`
+	Holds    bool      `json:"holds"`
+	Error    *APIError `json:"error,omitempty"`
+}
 
-	response := TreasuryResponse{
-		Balance:      s.dao.GetTreasuryBalance(),
-		Signers:      signerStrings,
-		RequiredSigs: s.dao.GetRequiredSignatures(),
-	}
+// ProposalDependencyResponse is a proposal on one side of a dependency
+type ProposalDependencyResponse struct {
+	ID     string             `json:"id"`
+	Title  string             `json:"title"`
+	Status dao.ProposalStatus `json:"status"`
+}
 
-	return c.JSON(http.StatusOK, response)
+// ProposalDependenciesResponse lists the proposals a proposal depends on
+// and the proposals depending on it. Blocking are the dependencies that
+// have not executed yet.
+type ProposalDependenciesResponse struct {
+	DependsOn  []ProposalDependencyResponse `json:"depends_on"`
+	Dependents []ProposalDependencyResponse `json:"dependents"`
+	Blocking   []string                     `json:"blocking"`
 }
 
-func (s *DAOServer) handleGetTreasuryTransactions(c echo.Context) error {
-	transactions := s.dao.GetTreasuryHistory()
-	response := make([]TreasuryTransactionResponse, 0, len(transactions))
+// DocumentVersionResponse is a version of a governance document. Content
+// and Diff are only included for a single version, when IPFS has them.
+type DocumentVersionResponse struct {
+	Version     uint32            `json:"version"`
+	ContentHash string            `json:"content_hash"`
+	DiffHash    string            `json:"diff_hash"`
+	Summary     string            `json:"summary,omitempty"`
+	ProposalID  string            `json:"proposal_id"`
+	Proposer    string            `json:"proposer"`
+	EnactedAt   int64             `json:"enacted_at"`
+	Content     string            `json:"content,omitempty"`
+	Diff        *dao.DocumentDiff `json:"diff,omitempty"`
+}
 
-	for _, tx := range transactions {
-		sigStrings := make([]string, len(tx.Signatures))
-		for i, sig := range tx.Signatures {
-			sigStrings[i] = sig.String()
-		}
-
-		response = append(response, TreasuryTransactionResponse{
-			ID:         tx.ID.String(),
-			Recipient:  tx.Recipient.String(),
-			Amount:     tx.Amount,
-			Purpose:    tx.Purpose,
-			Signatures: sigStrings,
-			CreatedAt:  tx.CreatedAt,
-			ExpiresAt:  tx.ExpiresAt,
-			Executed:   tx.Executed,
-		})
-	}
-
-	return c.JSON(http.StatusOK, response)
+// GovernanceDocumentResponse is a governance document at its current
+// version. Versions are only listed for a single document, oldest first.
+type GovernanceDocumentResponse struct {
+	ID                string                    `json:"id"`
+	Title             string                    `json:"title"`
+	Version           uint32                    `json:"version"`
+	ContentHash       string                    `json:"content_hash"`
+	UpdatedAt         int64                     `json:"updated_at"`
+	PendingAmendments []string                  `json:"pending_amendments"`
+	Versions          []DocumentVersionResponse `json:"versions,omitempty"`
 }
 
-func (s *DAOServer) handleCreateTreasuryTransaction(c echo.Context) error {
-	var req struct {
-		Recipient  string `json:"recipient"`
-		Amount     uint64 `json:"amount"`
-		Purpose    string `json:"purpose"`
-		PrivateKey string `json:"private_key"`
-	}
-
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
-	}
+// EmergencySpendResponse is an emergency spend with the state of its vote
+type EmergencySpendResponse struct {
+	ProposalID string             `json:"proposal_id"`
+	Title      string             `json:"title"`
+	Proposer   string             `json:"proposer"`
+	Guardian   string             `json:"guardian"`
+	Recipient  string             `json:"recipient"`
+	Amount     uint64             `json:"amount"`
+	Incident   string             `json:"incident"`
+	Status     dao.ProposalStatus `json:"status"`
+	EndTime    int64              `json:"end_time"`
+	Quorum     uint64             `json:"quorum"`
+	Threshold  uint64             `json:"threshold"`
+	YesVotes   uint64             `json:"yes_votes"`
+	NoVotes    uint64             `json:"no_votes"`
+	CreatedAt  int64              `json:"created_at"`
+	ExecutedAt int64              `json:"executed_at,omitempty"`
+}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
-	}
+// ProposalEndorsementsResponse is the seconding of a proposal that needed
+// endorsements before its vote opened
+type ProposalEndorsementsResponse struct {
+	ProposalID string             `json:"proposal_id"`
+	Status     string             `json:"status"` // awaiting, endorsed or expired
+	Required   uint64             `json:"required"`
+	Total      uint64             `json:"total"`
+	Deadline   int64              `json:"deadline"`
+	Deposit    uint64             `json:"deposit"`
+	Burned     uint64             `json:"burned,omitempty"`
+	EndorsedAt int64              `json:"endorsed_at,omitempty"`
+	ExpiredAt  int64              `json:"expired_at,omitempty"`
+	Endorsers  []EndorserResponse `json:"endorsers"`
+}
 
-	// Parse recipient
-	recipient, err := publicKeyFromHex(req.Recipient)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid recipient format"})
-	}
+// EndorserResponse is the voting power a member endorsed a proposal with
+type EndorserResponse struct {
+	Address string `json:"address"`
+	Power   uint64 `json:"power"`
+}
 
-	// Create treasury transaction
-	treasuryTx := &dao.TreasuryTx{
-		Fee:          1000,
-		Recipient:    recipient,
-		Amount:       req.Amount,
-		Purpose:      req.Purpose,
-		Signatures:   []crypto.Signature{},
-		RequiredSigs: s.dao.GetRequiredSignatures(),
-	}
+// MemberProfileResponse is a member's signed profile document with where
+// it is published
+type MemberProfileResponse struct {
+	dao.MemberProfile
+	Name        string `json:"name,omitempty"` // Registered name
+	ProfileHash string `json:"profile_hash"`
+	PublishedAt int64  `json:"published_at"`
+}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: treasuryTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
-	}
+// DelegateListingResponse is a delegate's statement with their scorecard
+// and the deep link that opens a delegation to them in the app
+type DelegateListingResponse struct {
+	Address      string                 `json:"address"`
+	Name         string                 `json:"name,omitempty"` // Registered name
+	Platform     string                 `json:"platform"`
+	FocusAreas   []string               `json:"focus_areas"`
+	Fee          uint64                 `json:"fee"` // Basis points of delegators' rewards
+	PublishedAt  int64                  `json:"published_at"`
+	UpdatedAt    int64                  `json:"updated_at"`
+	Scorecard    *dao.DelegateScorecard `json:"scorecard"`
+	DelegateLink string                 `json:"delegate_link"`
+}
 
-	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
+func (s *DAOServer) newDelegateListingResponse(listing *dao.DelegateListing) DelegateListingResponse {
+	address := listing.Statement.Delegate.String()
+	return DelegateListingResponse{
+		Address:      address,
+		Name:         s.dao.Names.NameOfAddress(address),
+		Platform:     listing.Statement.Platform,
+		FocusAreas:   listing.Statement.FocusAreas,
+		Fee:          listing.Statement.Fee,
+		PublishedAt:  listing.Statement.PublishedAt,
+		UpdatedAt:    listing.Statement.UpdatedAt,
+		Scorecard:    listing.Scorecard,
+		DelegateLink: s.Config.Server.AppLinkBase + "delegate?" + url.Values{"delegate": {address}}.Encode(),
 	}
+}
 
-	// Send transaction
-	s.txChan <- tx
+// NameResponse is a registered member handle
+type NameResponse struct {
+	Name         string `json:"name"`
+	Owner        string `json:"owner"`
+	RegisteredAt int64  `json:"registered_at"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
 
-	// Broadcast event
-	event := Event{
-		Type: EventTreasuryTx,
-		Data: map[string]interface{}{
-			"amount":    req.Amount,
-			"recipient": req.Recipient,
-			"purpose":   req.Purpose,
-		},
-		Timestamp: time.Now().Unix(),
+func newNameResponse(registration *dao.NameRegistration) NameResponse {
+	return NameResponse{
+		Name:         registration.Name,
+		Owner:        registration.Owner.String(),
+		RegisteredAt: registration.RegisteredAt,
+		ExpiresAt:    registration.ExpiresAt,
 	}
-	s.broadcastEvent(event)
+}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "treasury transaction created successfully",
-	})
+// CommentResponse is a proposal comment with its replies. Hidden comments
+// keep their place in the thread without their body.
+type CommentResponse struct {
+	ID           string            `json:"id"`
+	ProposalID   string            `json:"proposal_id"`
+	ParentID     string            `json:"parent_id,omitempty"`
+	Author       string            `json:"author"`
+	BodyHash     string            `json:"body_hash"`
+	Body         string            `json:"body,omitempty"`
+	CreatedAt    int64             `json:"created_at"`
+	Hidden       bool              `json:"hidden"`
+	HiddenReason string            `json:"hidden_reason,omitempty"`
+	Reactions    map[string]int    `json:"reactions"`
+	Replies      []CommentResponse `json:"replies,omitempty"`
 }
 
-func (s *DAOServer) handleSignTreasuryTransaction(c echo.Context) error {
-	var req struct {
-		TransactionID string `json:"transaction_id"`
-		PrivateKey    string `json:"private_key"`
-	}
+// ProposalDraftResponse reports whether a draft would be accepted, with the
+// proposal it would create
+type ProposalDraftResponse struct {
+	Valid    bool             `json:"valid"`
+	Issues   []dao.DraftIssue `json:"issues"`
+	Proposal *ProposalPreview `json:"proposal,omitempty"`
+}
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
-	}
+// ProposalPreview is the proposal a draft renders to
+type ProposalPreview struct {
+	Title        string           `json:"title"`
+	Description  string           `json:"description"`
+	ProposalType dao.ProposalType `json:"proposal_type"`
+	VotingType   dao.VotingType   `json:"voting_type"`
+	StartTime    int64            `json:"start_time"`
+	EndTime      int64            `json:"end_time"`
+	Threshold    uint64           `json:"threshold"`
+	Fee          int64            `json:"fee"`
+}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
-	}
+// DraftResponse is a saved proposal draft
+type DraftResponse struct {
+	ID          string            `json:"id"`
+	Owner       string            `json:"owner"`
+	CoAuthors   []string          `json:"co_authors"`
+	Content     dao.ProposalDraft `json:"content"`
+	Version     uint64            `json:"version"`
+	CreatedAt   int64             `json:"created_at"`
+	UpdatedAt   int64             `json:"updated_at"`
+	UpdatedBy   string            `json:"updated_by"`
+	SubmittedAt int64             `json:"submitted_at,omitempty"`
+	SubmittedTx string            `json:"submitted_tx,omitempty"`
+	ContentHash string            `json:"content_hash,omitempty"`
+}
 
-	// Parse transaction ID
-	txIDBytes, err := hex.DecodeString(req.TransactionID)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid transaction ID format"})
-	}
+// MembershipApplicationResponse is a request to join the DAO
+type MembershipApplicationResponse struct {
+	Applicant         string   `json:"applicant"`
+	Statement         string   `json:"statement"`
+	KYCAttestation    string   `json:"kyc_attestation,omitempty"`
+	Status            string   `json:"status"`
+	Approvals         []string `json:"approvals"`
+	ApprovalsRequired uint64   `json:"approvals_required"`
+	AutoApproved      bool     `json:"auto_approved"`
+	CreatedAt         int64    `json:"created_at"`
+	DecidedAt         int64    `json:"decided_at,omitempty"`
+}
 
-	txID := types.HashFromBytes(txIDBytes)
+// StatusChangeResponse is a pending vote to suspend or reinstate a member
+type StatusChangeResponse struct {
+	Status    string   `json:"status"`
+	Reason    string   `json:"reason,omitempty"`
+	Votes     []string `json:"votes"`
+	CreatedAt int64    `json:"created_at"`
+}
 
-	// Sign treasury transaction
-	if err := s.dao.SignTreasuryTransaction(txID, privKey); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
-	}
+// MembershipResponse is the membership of an address. Status is "none" for
+// addresses that never joined.
+type MembershipResponse struct {
+	Address        string                         `json:"address"`
+	Status         string                         `json:"status"`
+	JoinedAt       int64                          `json:"joined_at,omitempty"`
+	KYCAttestation string                         `json:"kyc_attestation,omitempty"`
+	Application    *MembershipApplicationResponse `json:"application,omitempty"`
+	StatusChanges  []StatusChangeResponse         `json:"status_changes"`
+}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"message": "treasury transaction signed successfully",
-	})
+type ProofStepResponse struct {
+	Hash     string `json:"hash"`
+	Position string `json:"position"` // Side of the sibling, "left" or "right"
 }
 
-// Token endpoints
-func (s *DAOServer) handleGetTokenBalance(c echo.Context) error {
-	addressStr := c.Param("address")
+// StateProofResponse proves a DAO state entry against the state root of a
+// block header. Leaves hash as sha256(0x00 || key || 0x00 || value) and inner
+// nodes as sha256(0x01 || left || right).
+type StateProofResponse struct {
+	Key        string              `json:"key"`
+	Value      json.RawMessage     `json:"value"`
+	ValueHex   string              `json:"value_hex"`
+	Height     uint32              `json:"height"`
+	HeaderHash string              `json:"header_hash"`
+	StateRoot  string              `json:"state_root"`
+	Proof      []ProofStepResponse `json:"proof"`
+}
 
-	address, err := publicKeyFromHex(addressStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid address format"})
-	}
-	balance := s.dao.GetTokenBalance(address)
+type SnapshotEntryResponse struct {
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+	ValueHex string          `json:"value_hex"`
+}
 
-	return c.JSON(http.StatusOK, map[string]uint64{
-		"balance": balance,
-	})
+// SnapshotResponse is the full DAO state after the block at Height. The
+// entries other than "parameters" hash to StateRoot.
+type SnapshotResponse struct {
+	Height    uint32                  `json:"height"`
+	StateRoot string                  `json:"state_root"`
+	Entries   []SnapshotEntryResponse `json:"entries"`
 }
 
-func (s *DAOServer) handleGetTokenSupply(c echo.Context) error {
-	supply := s.dao.GetTotalSupply()
+// ProposalTallyResponse is a proposal whose votes changed but nothing else
+type ProposalTallyResponse struct {
+	ProposalID string           `json:"proposal_id"`
+	Results    *dao.VoteResults `json:"results"`
+}
 
-	return c.JSON(http.StatusOK, map[string]uint64{
-		"total_supply": supply,
-	})
+// SyncBalanceResponse is the token balance of the synced address
+type SyncBalanceResponse struct {
+	Address string `json:"address"`
+	Balance uint64 `json:"balance"`
 }
 
-func (s *DAOServer) handleTokenTransfer(c echo.Context) error {
-	var req struct {
-		To         string `json:"to"`
-		Amount     uint64 `json:"amount"`
-		PrivateKey string `json:"private_key"`
-	}
+// SyncResponse is what changed in the DAO after Since up to Height. Clients
+// pass Height as since on their next sync; Full is set when the changeset
+// holds the whole state.
+type SyncResponse struct {
+	Since                uint32                        `json:"since"`
+	Height               uint32                        `json:"height"`
+	Full                 bool                          `json:"full"`
+	Proposals            []ProposalResponse            `json:"proposals"`
+	Tallies              []ProposalTallyResponse       `json:"tallies"`
+	RemovedProposals     []string                      `json:"removed_proposals"`
+	Balance              *SyncBalanceResponse          `json:"balance,omitempty"`
+	Treasury             *TreasuryResponse             `json:"treasury,omitempty"`
+	TreasuryTransactions []TreasuryTransactionResponse `json:"treasury_transactions"`
+}
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
+// stateAtHeight returns the DAO state requested with ?at_height=, or nil
+// for the current state. On failure it returns the HTTP status to reply with.
+func (s *DAOServer) stateAtHeight(c echo.Context) (*core.DAOStateSnapshot, int, error) {
+	heightStr := c.QueryParam("at_height")
+	if heightStr == "" {
+		return nil, 0, nil
 	}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
+	height, err := strconv.ParseUint(heightStr, 10, 32)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid at_height")
 	}
 
-	// Parse recipient
-	to, err := publicKeyFromHex(req.To)
+	snapshot, err := s.bc.GetDAOStateAt(uint32(height))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid recipient format"})
+		return nil, http.StatusNotFound, err
 	}
 
-	// Create token transfer transaction
-	transferTx := &dao.TokenTransferTx{
-		Fee:       100,
-		Recipient: to,
-		Amount:    req.Amount,
-	}
+	return snapshot, 0, nil
+}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: transferTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
+func newProposalResponse(proposal *dao.Proposal, names *dao.NameRegistry) ProposalResponse {
+	response := ProposalResponse{
+		ID:             proposal.ID.String(),
+		Creator:        proposal.Creator.String(),
+		CreatorName:    names.NameOf(proposal.Creator),
+		Title:          proposal.Title,
+		Description:    proposal.Description,
+		ProposalType:   proposal.ProposalType,
+		VotingType:     proposal.VotingType,
+		StartTime:      proposal.StartTime,
+		EndTime:        proposal.EndTime,
+		Status:         proposal.Status,
+		Threshold:      proposal.Threshold,
+		Quorum:         proposal.Quorum,
+		AdaptiveQuorum: proposal.AdaptiveQuorum,
+		Results:        proposal.Results,
+		MetadataHash:   proposal.MetadataHash.String(),
+		Track:          proposal.Track,
+		Timelock:       proposal.Timelock,
 	}
+	if proposal.ResultHash != (types.Hash{}) {
+		response.ResultHash = proposal.ResultHash.String()
+	}
+	return response
+}
 
-	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
+func newDelegationResponse(delegation *dao.Delegation, names *dao.NameRegistry) DelegationResponse {
+	return DelegationResponse{
+		Delegator:     delegation.Delegator.String(),
+		DelegatorName: names.NameOf(delegation.Delegator),
+		Delegate:      delegation.Delegate.String(),
+		DelegateName:  names.NameOf(delegation.Delegate),
+		StartTime:     delegation.StartTime,
+		EndTime:       delegation.EndTime,
+		Active:        delegation.Active,
+		AutoRenew:     delegation.AutoRenew,
 	}
+}
 
-	// Send transaction
-	s.txChan <- tx
+// Proposal endpoints
+func (s *DAOServer) handleGetProposals(c echo.Context) error {
+	fields, err := parseFieldSet(c, ProposalResponse{})
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, err.Error())
+	}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "token transfer successful",
-	})
-}
+	snapshot, status, err := s.stateAtHeight(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
 
-func (s *DAOServer) handleTokenApprove(c echo.Context) error {
-	var req struct {
-		Spender    string `json:"spender"`
-		Amount     uint64 `json:"amount"`
-		PrivateKey string `json:"private_key"`
+	if snapshot != nil {
+		response := []ProposalResponse{}
+		for _, key := range snapshot.Keys("proposal/") {
+			proposal := &dao.Proposal{}
+			if _, err := snapshot.Decode(key, proposal); err != nil {
+				return errorResponse(c, http.StatusInternalServerError, err)
+			}
+			response = append(response, newProposalResponse(proposal, s.dao.Names))
+		}
+		return sparseJSON(c, fields, response)
 	}
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
+	// Optional filters and paging are served from the proposal index
+	var statusFilter *dao.ProposalStatus
+	if value := c.QueryParam("status"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 8)
+		if err != nil || parsed < uint64(dao.ProposalStatusPending) || parsed > uint64(dao.ProposalStatusCancelled) {
+			return errorMessage(c, http.StatusBadRequest, "invalid status")
+		}
+		filter := dao.ProposalStatus(parsed)
+		statusFilter = &filter
 	}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
+	var endAfter, endBefore int64
+	for param, bound := range map[string]*int64{"ending_after": &endAfter, "ending_before": &endBefore} {
+		if value := c.QueryParam(param); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errorMessage(c, http.StatusBadRequest, "invalid "+param)
+			}
+			*bound = parsed
+		}
 	}
 
-	// Parse spender
-	spender, err := publicKeyFromHex(req.Spender)
+	descending, err := parseSortOrder(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid spender format"})
+		return errorResponse(c, http.StatusBadRequest, err)
 	}
 
-	// Create token approve transaction
-	approveTx := &dao.TokenApproveTx{
-		Fee:     100,
-		Spender: spender,
-		Amount:  req.Amount,
+	// Without a limit every matching proposal is returned
+	offset, limit := 0, 0
+	if c.QueryParam("page") != "" || c.QueryParam("limit") != "" {
+		page, _ := strconv.Atoi(c.QueryParam("page"))
+		if page < 1 {
+			page = 1
+		}
+		limit, _ = strconv.Atoi(c.QueryParam("limit"))
+		if limit < 1 || limit > 100 {
+			limit = 50
+		}
+		offset = (page - 1) * limit
 	}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: approveTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
-	}
+	proposals, total := s.dao.ListProposalPage(statusFilter, endAfter, endBefore, descending, offset, limit)
+	response := make([]ProposalResponse, len(proposals))
 
-	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
+	for i, proposal := range proposals {
+		response[i] = newProposalResponse(proposal, s.dao.Names)
+		response[i].Finalized = s.bc.IsDAOTxFinalized(proposal.ID)
+		response[i].QueuePosition, _ = s.dao.GetTrackQueuePosition(proposal.ID)
 	}
 
-	// Send transaction
-	s.txChan <- tx
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(total))
+	return sparseJSON(c, fields, response)
+}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "token approval successful",
-	})
+// parseSortOrder reads the order query parameter, descending by default
+func parseSortOrder(c echo.Context) (bool, error) {
+	switch c.QueryParam("order") {
+	case "", "desc":
+		return true, nil
+	case "asc":
+		return false, nil
+	default:
+		return false, fmt.Errorf("order must be asc or desc")
+	}
 }
 
-func (s *DAOServer) handleGetTokenAllowance(c echo.Context) error {
-	ownerStr := c.Param("owner")
-	spenderStr := c.Param("spender")
+func (s *DAOServer) handleGetProposal(c echo.Context) error {
+	idStr := c.Param("id")
 
-	owner, err := publicKeyFromHex(ownerStr)
+	idBytes, err := hex.DecodeString(idStr)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid owner address format"})
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
 	}
 
-	spender, err := publicKeyFromHex(spenderStr)
+	proposalID := types.HashFromBytes(idBytes)
+
+	snapshot, status, err := s.stateAtHeight(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid spender address format"})
+		return errorResponse(c, status, err)
 	}
 
-	allowance := s.dao.GetTokenAllowance(owner, spender)
-
-	return c.JSON(http.StatusOK, map[string]uint64{
-		"allowance": allowance,
-	})
-}
-
-// Delegation endpoints
-func (s *DAOServer) handleDelegate(c echo.Context) error {
-	var req struct {
-		Delegate   string `json:"delegate"`
-		Duration   int64  `json:"duration"`
-		PrivateKey string `json:"private_key"`
+	var proposal *dao.Proposal
+	if snapshot != nil {
+		proposal = &dao.Proposal{}
+		exists, err := snapshot.Decode(dao.ProposalStateKey(proposalID), proposal)
+		if err != nil {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+		if !exists {
+			return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrProposalNotFound, "proposal not found", nil))
+		}
+	} else {
+		proposal, err = s.dao.GetProposal(proposalID)
+		if err != nil {
+			return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrProposalNotFound, "proposal not found", nil))
+		}
 	}
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
+	response := newProposalResponse(proposal, s.dao.Names)
+	response.Finalized = s.bc.IsDAOTxFinalized(proposal.ID)
+	if snapshot == nil {
+		response.QueuePosition, _ = s.dao.GetTrackQueuePosition(proposal.ID)
 	}
 
-	// Parse private key
-	privKey, err := privateKeyFromHex(req.PrivateKey)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetProposalMetadata(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
 	}
 
-	// Parse delegate
-	delegate, err := publicKeyFromHex(req.Delegate)
+	proposal, err := s.dao.GetProposal(proposalID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid delegate format"})
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrProposalNotFound, "proposal not found", nil))
 	}
-
-	// Create delegation transaction
-	delegationTx := &dao.DelegationTx{
-		Fee:      200,
-		Delegate: delegate,
-		Duration: req.Duration,
-		Revoke:   false,
+	if proposal.MetadataHash == (types.Hash{}) {
+		return errorMessage(c, http.StatusNotFound, "proposal has no metadata")
 	}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: delegationTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
+	metadata, err := s.dao.GetProposalMetadata(proposalID)
+	if err != nil {
+		return errorResponse(c, http.StatusBadGateway, err)
 	}
 
-	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
+	return c.JSON(http.StatusOK, metadata)
+}
+
+func (s *DAOServer) handleGetProposalResult(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
 	}
 
-	// Send transaction
-	s.txChan <- tx
+	proposal, err := s.dao.GetProposal(proposalID)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrProposalNotFound, "proposal not found", nil))
+	}
+	if proposal.ResultHash == (types.Hash{}) {
+		return errorMessage(c, http.StatusNotFound, "proposal result is not anchored")
+	}
 
-	// Broadcast event
-	event := Event{
-		Type: EventDelegation,
-		Data: map[string]interface{}{
-			"delegator": privKey.PublicKey().String(),
-			"delegate":  req.Delegate,
-			"action":    "delegate",
-		},
-		Timestamp: time.Now().Unix(),
+	result, err := s.dao.GetProposalResult(proposalID)
+	if err != nil {
+		return errorResponse(c, http.StatusBadGateway, err)
 	}
-	s.broadcastEvent(event)
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "delegation successful",
-	})
+	return c.JSON(http.StatusOK, result)
 }
 
-func (s *DAOServer) handleRevokeDelegation(c echo.Context) error {
+func (s *DAOServer) handleCreateProposal(c echo.Context) error {
 	var req struct {
-		PrivateKey string `json:"private_key"`
+		Title        string                   `json:"title"`
+		Description  string                   `json:"description"`
+		ProposalType dao.ProposalType         `json:"proposal_type"`
+		VotingType   dao.VotingType           `json:"voting_type"`
+		StartTime    int64                    `json:"start_time"` // Scheduled vote start, 0 opens voting now
+		Duration     int64                    `json:"duration"`   // Duration in seconds
+		Threshold    uint64                   `json:"threshold"`
+		MetadataHash string                   `json:"metadata_hash"`
+		VoteSponsor  uint64                   `json:"vote_sponsor"` // Treasury budget covering voting fees
+		Conditions   []OracleConditionRequest `json:"conditions"`   // Oracle data required to execute
+		DependsOn    []string                 `json:"depends_on"`   // Proposals that must execute first
+		Track        string                   `json:"track"`        // Proposal track setting the voting rules
+		Template     string                   `json:"template"`     // Optional proposal template
+		Fields       map[string]string        `json:"fields"`       // Template field values
+		PrivateKey   string                   `json:"private_key"`  // For signing
 	}
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid request format"})
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
 	}
 
 	// Parse private key
 	privKey, err := privateKeyFromHex(req.PrivateKey)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid private key format"})
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
 	}
 
-	// Create revoke delegation transaction
-	delegationTx := &dao.DelegationTx{
-		Fee:      200,
-		Delegate: crypto.PublicKey{}, // Empty delegate for revocation
-		Duration: 0,
-		Revoke:   true,
+	now := time.Now().Unix()
+	startTime := now
+	if req.StartTime != 0 {
+		if err := dao.ValidateProposalSchedule(req.StartTime, now); err != nil {
+			return errorResponse(c, http.StatusBadRequest, err)
+		}
+		startTime = req.StartTime
 	}
 
-	// Create and sign transaction
-	tx := &core.Transaction{
-		TxInner: delegationTx,
-		To:      crypto.PublicKey{}, // DAO contract address
-		Value:   0,
+	// Render templated proposals, rejecting invalid fields before signing
+	if req.Template != "" {
+		draft := &dao.ProposalDraft{
+			Template:    req.Template,
+			Fields:      req.Fields,
+			Title:       req.Title,
+			Description: req.Description,
+			VotingType:  req.VotingType,
+			StartTime:   req.StartTime,
+			Duration:    req.Duration,
+			Threshold:   req.Threshold,
+			VoteSponsor: req.VoteSponsor,
+		}
+		rendered, issues := s.dao.ValidateProposalDraft(draft, privKey.PublicKey(), s.Config.DAO.Fees.Proposal, now)
+		if rendered == nil {
+			return fieldErrorResponse(c, "invalid template fields: "+draftIssuesString(issues), draftFieldErrors(issues))
+		}
+		req.Title = rendered.Title
+		req.Description = rendered.Description
+		req.ProposalType = rendered.ProposalType
 	}
 
+	// Parse metadata hash
+	var metadataHash types.Hash
+	if req.MetadataHash != "" {
+		metadataBytes, err := hex.DecodeString(req.MetadataHash)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid metadata hash format")
+		}
+		metadataHash = types.HashFromBytes(metadataBytes)
+	}
+
+	// Parse oracle conditions
+	var conditions []dao.OracleCondition
+	for i, condition := range req.Conditions {
+		operator := dao.OracleOperator(condition.Operator)
+		if !operator.Valid() {
+			return fieldErrorResponse(c, "invalid oracle condition", []FieldError{{
+				Field:   fmt.Sprintf("conditions[%d].operator", i),
+				Message: "must be gt, gte, lt or lte",
+			}})
+		}
+		conditions = append(conditions, dao.OracleCondition{FeedID: condition.FeedID, Operator: operator, Value: condition.Value})
+	}
+
+	// Parse dependencies
+	var dependsOn []types.Hash
+	for i, dependency := range req.DependsOn {
+		id, err := hashFromHex(dependency)
+		if err != nil {
+			return fieldErrorResponse(c, "invalid dependency", []FieldError{{
+				Field:   fmt.Sprintf("depends_on[%d]", i),
+				Message: "invalid proposal ID",
+			}})
+		}
+		if _, err := s.dao.GetProposal(id); err != nil {
+			return fieldErrorResponse(c, "invalid dependency", []FieldError{{
+				Field:   fmt.Sprintf("depends_on[%d]", i),
+				Message: "proposal not found",
+			}})
+		}
+		dependsOn = append(dependsOn, id)
+	}
+
+	// Create proposal transaction
+	proposalTx := &dao.ProposalTx{
+		Fee:          s.Config.DAO.Fees.Proposal,
+		Title:        req.Title,
+		Description:  req.Description,
+		ProposalType: req.ProposalType,
+		VotingType:   req.VotingType,
+		StartTime:    startTime,
+		EndTime:      startTime + req.Duration,
+		Threshold:    req.Threshold,
+		MetadataHash: metadataHash,
+		VoteSponsor:  req.VoteSponsor,
+		Conditions:   conditions,
+		DependsOn:    dependsOn,
+		Track:        req.Track,
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(proposalTx)
+
 	if err := tx.Sign(privKey); err != nil {
-		return c.JSON(http.StatusInternalServerError, APIError{Error: "failed to sign transaction"})
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
 	}
 
 	// Send transaction
-	s.txChan <- tx
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
 
 	// Broadcast event
 	event := Event{
-		Type: EventDelegation,
+		Type: EventProposalCreated,
 		Data: map[string]interface{}{
-			"delegator": privKey.PublicKey().String(),
-			"action":    "revoke",
+			"title":      req.Title,
+			"creator":    privKey.PublicKey().String(),
+			"start_time": startTime,
 		},
 		Timestamp: time.Now().Unix(),
 	}
@@ -874,635 +1844,7922 @@ func (s *DAOServer) handleRevokeDelegation(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"tx_hash": tx.Hash(core.TxHasher{}).String(),
-		"message": "delegation revoked successfully",
+		"message": "proposal created successfully",
 	})
 }
 
-func (s *DAOServer) handleGetDelegation(c echo.Context) error {
-	addressStr := c.Param("address")
-
-	address, err := publicKeyFromHex(addressStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid address format"})
+func (s *DAOServer) handleCastVote(c echo.Context) error {
+	var req struct {
+		ProposalID string         `json:"proposal_id"`
+		Choice     dao.VoteChoice `json:"choice"`
+		Weight     uint64         `json:"weight"`
+		Reason     string         `json:"reason"`
+		PrivateKey string         `json:"private_key"`
 	}
-	delegation, exists := s.dao.GetDelegation(address)
-	if !exists {
-		return c.JSON(http.StatusNotFound, APIError{Error: "delegation not found"})
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
 	}
 
-	response := DelegationResponse{
-		Delegator: delegation.Delegator.String(),
-		Delegate:  delegation.Delegate.String(),
-		StartTime: delegation.StartTime,
-		EndTime:   delegation.EndTime,
-		Active:    delegation.Active,
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
 	}
 
-	return c.JSON(http.StatusOK, response)
-}
+	// Parse proposal ID
+	proposalIDBytes, err := hex.DecodeString(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
 
-func (s *DAOServer) handleGetDelegations(c echo.Context) error {
-	delegations := s.dao.ListDelegations()
-	response := make([]DelegationResponse, 0, len(delegations))
+	proposalID := types.HashFromBytes(proposalIDBytes)
 
-	for _, delegation := range delegations {
-		response = append(response, DelegationResponse{
-			Delegator: delegation.Delegator.String(),
-			Delegate:  delegation.Delegate.String(),
-			StartTime: delegation.StartTime,
-			EndTime:   delegation.EndTime,
-			Active:    delegation.Active,
-		})
+	// Create vote transaction
+	voteTx := &dao.VoteTx{
+		Fee:        s.Config.DAO.Fees.Vote,
+		ProposalID: proposalID,
+		Choice:     req.Choice,
+		Weight:     req.Weight,
+		Reason:     req.Reason,
 	}
 
-	return c.JSON(http.StatusOK, response)
-}
-
-// Member endpoints
-func (s *DAOServer) handleGetMember(c echo.Context) error {
-	addressStr := c.Param("address")
+	// Create and sign transaction
+	tx := s.newDAOTransaction(voteTx)
 
-	address, err := publicKeyFromHex(addressStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "invalid address format"})
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
 	}
-	member, exists := s.dao.GetTokenHolder(address)
-	if !exists {
-		return c.JSON(http.StatusNotFound, APIError{Error: "member not found"})
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
 	}
 
-	response := MemberResponse{
-		Address:    member.Address.String(),
-		Balance:    member.Balance,
-		Staked:     member.Staked,
-		Reputation: member.Reputation,
-		JoinedAt:   member.JoinedAt,
-		LastActive: member.LastActive,
+	// Broadcast event
+	event := Event{
+		Type: EventVoteCast,
+		Data: map[string]interface{}{
+			"proposal_id": req.ProposalID,
+			"voter":       privKey.PublicKey().String(),
+			"choice":      req.Choice,
+		},
+		Timestamp: time.Now().Unix(),
 	}
+	s.broadcastEvent(event)
 
-	return c.JSON(http.StatusOK, response)
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "vote cast successfully",
+	})
 }
 
-func (s *DAOServer) handleGetMembers(c echo.Context) error {
-	// Get pagination parameters
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page < 1 {
-		page = 1
+func (s *DAOServer) handleGetProposalVotes(c echo.Context) error {
+	idStr := c.Param("id")
+
+	idBytes, err := hex.DecodeString(idStr)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
 	}
 
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit < 1 || limit > 100 {
-		limit = 50
+	proposalID := types.HashFromBytes(idBytes)
+	votes, err := s.dao.GetVotes(proposalID)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrProposalNotFound, "proposal not found", nil))
 	}
 
-	// This is a simplified implementation - in production you'd want proper pagination
-	allMembers := make([]MemberResponse, 0)
+	viewer, err := viewerFromRequest(c)
+	if err != nil {
+		return errorMessage(c, http.StatusUnauthorized, err.Error())
+	}
 
-	// Get all token holders (this would be optimized in production)
-	for addressStr, holder := range s.dao.GovernanceState.TokenHolders {
-		allMembers = append(allMembers, MemberResponse{
-			Address:    addressStr,
-			Balance:    holder.Balance,
-			Staked:     holder.Staked,
-			Reputation: holder.Reputation,
-			JoinedAt:   holder.JoinedAt,
-			LastActive: holder.LastActive,
+	// Votes of members who hide their vote history from the viewer are left
+	// out; the tally still counts them
+	hidden := 0
+	response := make([]VoteResponse, 0, len(votes))
+	for _, vote := range votes {
+		if !s.dao.Privacy.CanView(vote.Voter.String(), dao.PrivacyFieldVotes, viewer) {
+			hidden++
+			continue
+		}
+		response = append(response, VoteResponse{
+			Voter:     vote.Voter.String(),
+			VoterName: s.dao.Names.NameOf(vote.Voter),
+			Choice:    vote.Choice,
+			Weight:    vote.Weight,
+			Timestamp: vote.Timestamp,
+			Reason:    vote.Reason,
 		})
 	}
 
-	// Simple pagination
-	start := (page - 1) * limit
-	end := start + limit
+	c.Response().Header().Set("X-Hidden-Votes", strconv.Itoa(hidden))
+	return c.JSON(http.StatusOK, response)
+}
 
-	if start >= len(allMembers) {
-		return c.JSON(http.StatusOK, []MemberResponse{})
+// Treasury endpoints
+func (s *DAOServer) handleGetTreasury(c echo.Context) error {
+	signers := s.dao.GetTreasurySigners()
+	signerStrings := make([]string, len(signers))
+	for i, signer := range signers {
+		signerStrings[i] = signer.String()
 	}
 
-	if end > len(allMembers) {
-		end = len(allMembers)
+	response := TreasuryResponse{
+		Balance:      s.dao.GetTreasuryBalance(),
+		Signers:      signerStrings,
+		RequiredSigs: s.dao.GetRequiredSignatures(),
 	}
 
-	response := allMembers[start:end]
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"members": response,
-		"page":    page,
-		"limit":   limit,
-		"total":   len(allMembers),
-	})
+	return c.JSON(http.StatusOK, response)
 }
 
-// WebSocket handling
-func (s *DAOServer) handleWebSocket(c echo.Context) error {
-	conn, err := s.upgrader.Upgrade(c.Response(), c.Request(), nil)
+func (s *DAOServer) handleGetTreasuryTransactions(c echo.Context) error {
+	fields, err := parseFieldSet(c, TreasuryTransactionResponse{})
 	if err != nil {
-		return err
+		return errorMessage(c, http.StatusBadRequest, err.Error())
 	}
 
-	// Register client
-	s.eventBus.register <- conn
-
-	// Handle client disconnection
-	defer func() {
-		s.eventBus.unregister <- conn
-		conn.Close()
-	}()
+	transactions := s.dao.GetTreasuryHistory()
+	response := make([]TreasuryTransactionResponse, 0, len(transactions))
 
-	// Keep connection alive and handle ping/pong
-	for {
-		_, _, err := conn.ReadMessage()
-		if err != nil {
-			break
-		}
+	for _, tx := range transactions {
+		response = append(response, newTreasuryTransactionResponse(tx))
 	}
 
-	return nil
+	return sparseJSON(c, fields, response)
 }
 
-// Event broadcasting
-func (s *DAOServer) broadcastEvent(event Event) {
-	eventData, err := json.Marshal(event)
-	if err != nil {
-		return
+func newTreasuryTransactionResponse(tx *dao.PendingTx) TreasuryTransactionResponse {
+	sigStrings := make([]string, len(tx.Signatures))
+	for i, sig := range tx.Signatures {
+		sigStrings[i] = sig.String()
 	}
 
-	s.eventBus.broadcast <- eventData
+	response := TreasuryTransactionResponse{
+		ID:            tx.ID.String(),
+		Recipient:     tx.Recipient.String(),
+		Amount:        tx.Amount,
+		Purpose:       tx.Purpose,
+		Signatures:    sigStrings,
+		CreatedAt:     tx.CreatedAt,
+		ExpiresAt:     tx.ExpiresAt,
+		Executed:      tx.Executed,
+		ExecutedAt:    tx.ExecutedAt,
+		Denomination:  tx.Denomination,
+		StableAmount:  tx.StableAmount,
+		QuotedAmount:  tx.QuotedAmount,
+		Requeues:      tx.Requeues,
+		RequeueReason: tx.RequeueReason,
+	}
+	if tx.Aggregate != nil {
+		response.Aggregate = newAggregateSignatureResponse(tx.Aggregate)
+	}
+	if tx.Threshold != nil {
+		response.Threshold = newThresholdSigningResponse(tx.Threshold)
+	}
+	return response
 }
 
-// EventBus methods
-func (eb *EventBus) run() {
-	for {
-		select {
-		case client := <-eb.register:
-			eb.clients[client] = true
-
-		case client := <-eb.unregister:
-			if _, ok := eb.clients[client]; ok {
-				delete(eb.clients, client)
-				client.Close()
-			}
+// AggregateSignatureResponse is one signature of several treasury signers
+type AggregateSignatureResponse struct {
+	Scheme    string   `json:"scheme"`
+	Signers   []string `json:"signers"`
+	Signature string   `json:"signature"`
+}
 
-		case message := <-eb.broadcast:
-			for client := range eb.clients {
-				err := client.WriteMessage(websocket.TextMessage, message)
-				if err != nil {
-					delete(eb.clients, client)
-					client.Close()
-				}
-			}
-		}
+func newAggregateSignatureResponse(sig *crypto.AggregateSignature) *AggregateSignatureResponse {
+	signers := make([]string, len(sig.Signers))
+	for i, signer := range sig.Signers {
+		signers[i] = signer.String()
+	}
+	return &AggregateSignatureResponse{
+		Scheme:    string(sig.Scheme),
+		Signers:   signers,
+		Signature: hex.EncodeToString(sig.Data),
 	}
 }
 
-// Wallet integration endpoints
-
-// WalletConnectionRequest represents a wallet connection request
-type WalletConnectionRequest struct {
-	Provider  string `json:"provider"`
-	Address   string `json:"address"`
-	PublicKey string `json:"publicKey"`
-	ChainID   string `json:"chainId,omitempty"`
+// ThresholdSigningResponse is the signing of a treasury transaction with the
+// threshold key, commitments and partial signatures keyed by share index
+type ThresholdSigningResponse struct {
+	Commitments map[string]string `json:"commitments"`
+	Partials    map[string]string `json:"partials"`
+	CompletedAt int64             `json:"completed_at,omitempty"`
+	Signature   string            `json:"signature,omitempty"`
 }
 
-// WalletConnectionResponse represents a wallet connection response
-type WalletConnectionResponse struct {
-	Success    bool                  `json:"success"`
-	Connection *dao.WalletConnection `json:"connection,omitempty"`
-	Error      string                `json:"error,omitempty"`
+func newThresholdSigningResponse(signing *dao.ThresholdSigning) *ThresholdSigningResponse {
+	return &ThresholdSigningResponse{
+		Commitments: hexByIndex(signing.Commitments),
+		Partials:    hexByIndex(signing.Partials),
+		CompletedAt: signing.CompletedAt,
+		Signature:   hex.EncodeToString(signing.Signature),
+	}
 }
 
-// Analytics endpoint handlers
-
-func (s *DAOServer) handleGetParticipationMetrics(c echo.Context) error {
-	metrics := s.dao.GetGovernanceParticipationMetrics()
-	return c.JSON(http.StatusOK, metrics)
+// ThresholdKeyResponse is a treasury threshold key. Participant i holds the
+// share whose public key is shares[i], share index i+1.
+type ThresholdKeyResponse struct {
+	GroupKey     string   `json:"group_key"`
+	Threshold    uint8    `json:"threshold"`
+	Participants []string `json:"participants"`
+	Shares       []string `json:"shares"`
+	CreatedAt    int64    `json:"created_at"`
 }
 
-func (s *DAOServer) handleGetTreasuryMetrics(c echo.Context) error {
-	metrics := s.dao.GetTreasuryPerformanceMetrics()
-	return c.JSON(http.StatusOK, metrics)
+func newThresholdKeyResponse(key *dao.TreasuryThresholdKey) *ThresholdKeyResponse {
+	return &ThresholdKeyResponse{
+		GroupKey:     key.GroupKey.String(),
+		Threshold:    key.Threshold,
+		Participants: publicKeyStrings(key.Participants),
+		Shares:       publicKeyStrings(key.Shares),
+		CreatedAt:    key.CreatedAt,
+	}
 }
 
-func (s *DAOServer) handleGetProposalAnalytics(c echo.Context) error {
-	analytics := s.dao.GetProposalAnalytics()
-	return c.JSON(http.StatusOK, analytics)
+// KeyGenerationResponse is a running treasury key generation. The key is
+// set once every participant dealt and awaits their confirmations.
+type KeyGenerationResponse struct {
+	Threshold     uint8                         `json:"threshold"`
+	Participants  []string                      `json:"participants"`
+	Dealings      map[string]DKGDealingResponse `json:"dealings"`
+	Key           *ThresholdKeyResponse         `json:"key,omitempty"`
+	Confirmations []int                         `json:"confirmations"`
+	StartedAt     int64                         `json:"started_at"`
+	ExpiresAt     int64                         `json:"expires_at"`
 }
 
-func (s *DAOServer) handleGetHealthMetrics(c echo.Context) error {
-	health := s.dao.GetDAOHealthMetrics()
-	return c.JSON(http.StatusOK, health)
+// DKGDealingResponse is the dealing of a key generation participant: its
+// coefficient commitments and the shares sealed to the other participants
+type DKGDealingResponse struct {
+	Commitments []string          `json:"commitments"`
+	Shares      map[string]string `json:"shares"`
+	Proof       string            `json:"proof"`
 }
 
-func (s *DAOServer) handleGetAnalyticsSummary(c echo.Context) error {
-	summary := s.dao.GetAnalyticsSummary()
-	return c.JSON(http.StatusOK, summary)
+func newKeyGenerationResponse(keygen *dao.TreasuryKeyGeneration) *KeyGenerationResponse {
+	response := &KeyGenerationResponse{
+		Threshold:     keygen.Threshold,
+		Participants:  publicKeyStrings(keygen.Participants),
+		Dealings:      make(map[string]DKGDealingResponse, len(keygen.Dealings)),
+		Confirmations: make([]int, 0, len(keygen.Confirmations)),
+		StartedAt:     keygen.StartedAt,
+		ExpiresAt:     keygen.ExpiresAt,
+	}
+	for index, dealing := range keygen.Dealings {
+		commitments := make([]string, len(dealing.Commitments))
+		for i, commitment := range dealing.Commitments {
+			commitments[i] = hex.EncodeToString(commitment)
+		}
+		response.Dealings[strconv.Itoa(index)] = DKGDealingResponse{
+			Commitments: commitments,
+			Shares:      hexByIndex(dealing.Shares),
+			Proof:       hex.EncodeToString(dealing.Proof),
+		}
+	}
+	if keygen.Key != nil {
+		response.Key = newThresholdKeyResponse(keygen.Key)
+	}
+	for index := range keygen.Confirmations {
+		response.Confirmations = append(response.Confirmations, index)
+	}
+	sort.Ints(response.Confirmations)
+	return response
 }
 
-// WalletIntegrationResponse represents a wallet integration response
-type WalletIntegrationResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+func publicKeyStrings(keys []crypto.PublicKey) []string {
+	strs := make([]string, len(keys))
+	for i, key := range keys {
+		strs[i] = key.String()
+	}
+	return strs
 }
 
-// TransactionSigningRequest represents a transaction signing request
-type TransactionSigningRequest struct {
-	Address     string      `json:"address"`
-	Transaction interface{} `json:"transaction"`
-	Signature   string      `json:"signature"`
+func hexByIndex(values map[int][]byte) map[string]string {
+	encoded := make(map[string]string, len(values))
+	for index, value := range values {
+		encoded[strconv.Itoa(index)] = hex.EncodeToString(value)
+	}
+	return encoded
 }
 
-// TransactionSigningResponse represents a transaction signing response
-type TransactionSigningResponse struct {
-	Success           bool                   `json:"success"`
-	SignedTransaction *dao.SignedTransaction `json:"signedTransaction,omitempty"`
-	Error             string                 `json:"error,omitempty"`
-}
+func (s *DAOServer) handleGetTreasuryYield(c echo.Context) error {
+	config := s.dao.ParameterManager.GetParameterConfig()
 
-// BroadcastTransactionRequest represents a transaction broadcast request
-type BroadcastTransactionRequest struct {
-	SignedTransaction *dao.SignedTransaction `json:"signedTransaction"`
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"share_bps":         config.TreasuryYieldShare,
+		"epoch_length":      config.TreasuryYieldEpoch,
+		"yield_distributed": s.dao.GovernanceState.Treasury.YieldDistributed,
+		"current_epoch":     s.dao.GetCurrentYieldEpoch(),
+		"epochs":            s.dao.GetYieldEpochs(),
+	})
 }
 
-// BroadcastTransactionResponse represents a transaction broadcast response
-type BroadcastTransactionResponse struct {
-	Success         bool   `json:"success"`
-	TransactionHash string `json:"transactionHash,omitempty"`
-	BlockHeight     int64  `json:"blockHeight,omitempty"`
-	Error           string `json:"error,omitempty"`
-}
+// handleGetTreasuryReport returns the treasury statement of a month or a
+// year as JSON, or as CSV with format=csv
+func (s *DAOServer) handleGetTreasuryReport(c echo.Context) error {
+	period := c.QueryParam("period")
+	if period == "" {
+		period = time.Now().UTC().Format("2006-01")
+	}
 
-// WalletInfoResponse represents wallet information response
-type WalletInfoResponse struct {
-	Success bool                  `json:"success"`
-	Wallet  *dao.WalletConnection `json:"wallet,omitempty"`
-	Balance int64                 `json:"balance,omitempty"`
-	Error   string                `json:"error,omitempty"`
+	statement, err := s.dao.GetTreasuryStatement(period)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	switch c.QueryParam("format") {
+	case "", "json":
+		return c.JSON(http.StatusOK, statement)
+	case "csv":
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="treasury-%s.csv"`, period))
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		c.Response().WriteHeader(http.StatusOK)
+		return statement.WriteCSV(c.Response())
+	default:
+		return errorMessage(c, http.StatusBadRequest, "format must be json or csv")
+	}
 }
 
-// Add wallet integration routes to the DAO server
-func (s *DAOServer) setupWalletRoutes(e *echo.Echo) {
-	// Wallet connection endpoints
-	e.POST("/dao/wallet/connect", s.handleWalletConnect)
-	e.POST("/dao/wallet/disconnect", s.handleWalletDisconnect)
-	e.GET("/dao/wallet/info/:address", s.handleGetWalletInfo)
-	e.GET("/dao/wallet/connections", s.handleGetActiveConnections)
+// handlePreviewRageQuit returns the treasury share an address would receive
+// for burning amount tokens, and the proposals blocking its exit
+func (s *DAOServer) handlePreviewRageQuit(c echo.Context) error {
+	address, err := publicKeyFromHex(c.QueryParam("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
 
-	// Transaction signing endpoints
-	e.POST("/dao/wallet/sign", s.handleSignTransaction)
-	e.POST("/dao/wallet/broadcast", s.handleBroadcastTransaction)
-	e.POST("/dao/wallet/verify", s.handleVerifyTransaction)
+	amount, err := strconv.ParseUint(c.QueryParam("amount"), 10, 64)
+	if err != nil || amount == 0 {
+		return errorMessage(c, http.StatusBadRequest, "amount must be a positive integer")
+	}
 
-	// Wallet utilities
-	e.POST("/dao/wallet/generate-test", s.handleGenerateTestWallet)
-	e.GET("/dao/wallet/supported", s.handleGetSupportedWallets)
+	balance := s.dao.GetTokenBalance(address)
+	if amount > balance {
+		return errorMessage(c, http.StatusBadRequest, "amount exceeds token balance")
+	}
+
+	return c.JSON(http.StatusOK, s.dao.PreviewRageQuit(address, amount))
 }
 
-// handleWalletConnect handles wallet connection requests
-func (s *DAOServer) handleWalletConnect(c echo.Context) error {
-	var req WalletConnectionRequest
+func (s *DAOServer) handleRageQuit(c echo.Context) error {
+	var req struct {
+		Amount     uint64 `json:"amount"`
+		PrivateKey string `json:"private_key"`
+	}
+
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, WalletConnectionResponse{
-			Success: false,
-			Error:   "Invalid request format",
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
 	}
 
-	// Validate required fields
-	if req.Provider == "" || req.Address == "" || req.PublicKey == "" {
-		return c.JSON(http.StatusBadRequest, WalletConnectionResponse{
-			Success: false,
-			Error:   "Provider, address, and publicKey are required",
-		})
+	if req.Amount == 0 {
+		return errorMessage(c, http.StatusBadRequest, "amount must be greater than zero")
 	}
 
-	// Get wallet connection manager
-	walletManager := dao.NewWalletConnectionManager()
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
 
-	// Handle wallet connection
-	connection, err := walletManager.HandleWalletConnection(
-		dao.WalletProvider(req.Provider),
-		req.Address,
-		req.PublicKey,
-		req.ChainID,
-	)
+	preview := s.dao.PreviewRageQuit(privKey.PublicKey(), req.Amount)
+	if len(preview.OpenVotes) > 0 {
+		return errorMessage(c, http.StatusConflict, "cannot rage quit with votes on pending proposals")
+	}
+
+	rageQuitTx := &dao.RageQuitTx{
+		Fee:    s.Config.DAO.Fees.Default,
+		Amount: req.Amount,
+	}
+
+	data := map[string]interface{}{
+		"amount": req.Amount,
+		"share":  preview.Share,
+	}
+
+	return s.submitDAOTxWithEvent(c, rageQuitTx, privKey, "rage quit submitted", EventRageQuit, data)
+}
+
+func (s *DAOServer) handleCreateTreasuryTransaction(c echo.Context) error {
+	var req struct {
+		Recipient    string `json:"recipient"`
+		Amount       uint64 `json:"amount"`
+		Denomination string `json:"denomination"`
+		Purpose      string `json:"purpose"`
+		PrivateKey   string `json:"private_key"`
+	}
 
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Stable denominated amounts convert through an active oracle feed
+	if req.Denomination != "" {
+		if _, exists := s.dao.GetOracleFeed(req.Denomination); !exists {
+			return fieldErrorResponse(c, "invalid denomination", []FieldError{{
+				Field:   "denomination",
+				Message: "must name an active oracle feed",
+			}})
+		}
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, WalletConnectionResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
 	}
 
-	// Broadcast wallet connection event
+	// Parse recipient
+	recipient, err := publicKeyFromHex(req.Recipient)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid recipient format")
+	}
+
+	// Create treasury transaction
+	treasuryTx := &dao.TreasuryTx{
+		Fee:          s.Config.DAO.Fees.Treasury,
+		Recipient:    recipient,
+		Amount:       req.Amount,
+		Denomination: req.Denomination,
+		Purpose:      req.Purpose,
+		Signatures:   []crypto.Signature{},
+		RequiredSigs: s.dao.GetRequiredSignatures(),
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(treasuryTx)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	// Broadcast event
 	event := Event{
-		Type: EventType("wallet_connected"),
+		Type: EventTreasuryTx,
 		Data: map[string]interface{}{
-			"address":  req.Address,
-			"provider": req.Provider,
-			"chainId":  req.ChainID,
+			"amount":       req.Amount,
+			"denomination": req.Denomination,
+			"recipient":    req.Recipient,
+			"purpose":      req.Purpose,
 		},
 		Timestamp: time.Now().Unix(),
 	}
 	s.broadcastEvent(event)
 
-	return c.JSON(http.StatusOK, WalletConnectionResponse{
-		Success:    true,
-		Connection: connection,
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "treasury transaction created successfully",
+	})
+}
+
+// handlePreviewTreasuryTransaction checks a treasury disbursement, new or
+// pending, without submitting anything, so clients can fix it before
+// signers spend signatures on it
+func (s *DAOServer) handlePreviewTreasuryTransaction(c echo.Context) error {
+	var req struct {
+		TransactionID string `json:"transaction_id"`
+		Recipient     string `json:"recipient"`
+		Amount        uint64 `json:"amount"`
+		Denomination  string `json:"denomination"`
+		Purpose       string `json:"purpose"`
+		Signer        string `json:"signer"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	preview := dao.TreasuryPreviewRequest{
+		Amount:       req.Amount,
+		Denomination: req.Denomination,
+		Purpose:      req.Purpose,
+	}
+
+	var fieldErrors []FieldError
+	if req.TransactionID != "" {
+		id, err := hashFromHex(req.TransactionID)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "transaction_id", Message: "must be 32 hex encoded bytes"})
+		}
+		preview.TransactionID = id
+	} else if req.Recipient != "" {
+		recipient, err := publicKeyFromHex(req.Recipient)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "recipient", Message: "must be a hex encoded public key"})
+		}
+		preview.Recipient = recipient
+	}
+	if req.Signer != "" {
+		signer, err := publicKeyFromHex(req.Signer)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "signer", Message: "must be a hex encoded public key"})
+		}
+		preview.Signer = signer
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrorResponse(c, "invalid treasury preview", fieldErrors)
+	}
+
+	result, err := s.dao.PreviewTreasuryTransaction(preview)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (s *DAOServer) handleSignTreasuryTransaction(c echo.Context) error {
+	var req struct {
+		TransactionID string `json:"transaction_id"`
+		PrivateKey    string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse transaction ID
+	txIDBytes, err := hex.DecodeString(req.TransactionID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid transaction ID format")
+	}
+
+	txID := types.HashFromBytes(txIDBytes)
+
+	// Sign treasury transaction
+	if err := s.dao.SignTreasuryTransaction(txID, privKey); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	// The last required signature executes the transaction
+	if pendingTx, exists := s.dao.GetTreasuryTransaction(txID); exists && pendingTx.Executed {
+		s.broadcastEvent(Event{
+			Type: EventTreasuryExecuted,
+			Data: map[string]interface{}{
+				"transaction_id": txID.String(),
+				"recipient":      pendingTx.Recipient.String(),
+				"amount":         pendingTx.Amount,
+			},
+			Timestamp: time.Now().Unix(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "treasury transaction signed successfully",
 	})
 }
 
-// handleWalletDisconnect handles wallet disconnection requests
-func (s *DAOServer) handleWalletDisconnect(c echo.Context) error {
-	address := c.FormValue("address")
-	if address == "" {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"success": false,
-			"error":   "Address is required",
-		})
+// handleAttachAggregateSignature signs a pending treasury transaction with
+// one signature its signers aggregated, such as a MuSig2 signature of the
+// signing payload digest
+func (s *DAOServer) handleAttachAggregateSignature(c echo.Context) error {
+	var req struct {
+		TransactionID string   `json:"transaction_id"`
+		Scheme        string   `json:"scheme"`
+		Signers       []string `json:"signers"`
+		Signature     string   `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	txID, err := hashFromHex(req.TransactionID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid transaction ID format")
+	}
+
+	var fields []FieldError
+	if _, err := crypto.AggregateSignerFor(crypto.AggregateScheme(req.Scheme)); err != nil {
+		fields = append(fields, FieldError{Field: "scheme", Message: err.Error()})
+	}
+	signers := make([]crypto.PublicKey, len(req.Signers))
+	for i, signerStr := range req.Signers {
+		signer, err := publicKeyFromHex(signerStr)
+		if err != nil {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("signers[%d]", i), Message: "Invalid signer format"})
+			continue
+		}
+		signers[i] = signer
+	}
+	if len(req.Signers) == 0 {
+		fields = append(fields, FieldError{Field: "signers", Message: "At least one signer is required"})
+	}
+	data, err := hex.DecodeString(req.Signature)
+	if err != nil || len(data) == 0 {
+		fields = append(fields, FieldError{Field: "signature", Message: "Signature must be hex encoded"})
+	}
+	if len(fields) > 0 {
+		return fieldErrorResponse(c, "invalid aggregate signature", fields)
+	}
+
+	sig := &crypto.AggregateSignature{
+		Scheme:  crypto.AggregateScheme(req.Scheme),
+		Signers: signers,
+		Data:    data,
+	}
+	if err := s.dao.AttachAggregateSignature(txID, sig); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	pendingTx, _ := s.dao.GetTreasuryTransaction(txID)
+	if pendingTx.Executed {
+		s.broadcastEvent(Event{
+			Type: EventTreasuryExecuted,
+			Data: map[string]interface{}{
+				"transaction_id": txID.String(),
+				"recipient":      pendingTx.Recipient.String(),
+				"amount":         pendingTx.Amount,
+			},
+			Timestamp: time.Now().Unix(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, newTreasuryTransactionResponse(pendingTx))
+}
+
+// handleGetThresholdKey returns the treasury threshold key and the running
+// key generation, each null when there is none
+func (s *DAOServer) handleGetThresholdKey(c echo.Context) error {
+	response := map[string]interface{}{
+		"key":            nil,
+		"key_generation": nil,
+	}
+	if key := s.dao.GetThresholdKey(); key != nil {
+		response["key"] = newThresholdKeyResponse(key)
+	}
+	if keygen := s.dao.GetKeyGeneration(); keygen != nil {
+		response["key_generation"] = newKeyGenerationResponse(keygen)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// handleStartKeyGeneration starts a treasury key generation for a signer,
+// signed over the threshold in decimal
+func (s *DAOServer) handleStartKeyGeneration(c echo.Context) error {
+	var req struct {
+		Address   string `json:"address"`
+		Threshold uint8  `json:"threshold"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	initiator, err := verifyMemberRequest("start_treasury_keygen", req.Address, req.Timestamp, []byte(strconv.Itoa(int(req.Threshold))), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	keygen, err := s.dao.StartKeyGeneration(initiator, req.Threshold)
+	if err != nil {
+		return errorResponse(c, thresholdErrorStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, newKeyGenerationResponse(keygen))
+}
+
+// handleSubmitKeyDealing records the dealing of a key generation
+// participant, signed over the dealing exactly as sent
+func (s *DAOServer) handleSubmitKeyDealing(c echo.Context) error {
+	var req struct {
+		Address   string          `json:"address"`
+		Dealing   json.RawMessage `json:"dealing"`
+		Timestamp int64           `json:"timestamp"`
+		Signature string          `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	signer, err := verifyMemberRequest("deal_treasury_keygen", req.Address, req.Timestamp, req.Dealing, req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	var content struct {
+		From        int               `json:"from"`
+		Commitments []string          `json:"commitments"`
+		Shares      map[string]string `json:"shares"`
+		Proof       string            `json:"proof"`
+	}
+	if err := json.Unmarshal(req.Dealing, &content); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid dealing format")
+	}
+
+	dealing := &crypto.DKGDealing{
+		From:        content.From,
+		Commitments: make([][]byte, len(content.Commitments)),
+		Shares:      make(map[int][]byte, len(content.Shares)),
+	}
+	var fields []FieldError
+	for i, commitment := range content.Commitments {
+		if dealing.Commitments[i], err = hex.DecodeString(commitment); err != nil {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("dealing.commitments[%d]", i), Message: "Commitment must be hex encoded"})
+		}
+	}
+	for indexStr, share := range content.Shares {
+		index, err := strconv.Atoi(indexStr)
+		if err != nil {
+			fields = append(fields, FieldError{Field: "dealing.shares", Message: "Shares must be keyed by participant index"})
+			continue
+		}
+		if dealing.Shares[index], err = hex.DecodeString(share); err != nil {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("dealing.shares.%d", index), Message: "Share must be hex encoded"})
+		}
+	}
+	if dealing.Proof, err = hex.DecodeString(content.Proof); err != nil {
+		fields = append(fields, FieldError{Field: "dealing.proof", Message: "Proof must be hex encoded"})
+	}
+	if len(fields) > 0 {
+		return fieldErrorResponse(c, "invalid dealing", fields)
+	}
+
+	if err := s.dao.SubmitKeyDealing(signer, dealing); err != nil {
+		return errorResponse(c, thresholdErrorStatus(err), err)
+	}
+
+	return s.handleGetThresholdKey(c)
+}
+
+// handleConfirmKeyGeneration records whether a participant accepts the
+// shares dealt to it, signed over "accept" or "reject"
+func (s *DAOServer) handleConfirmKeyGeneration(c echo.Context) error {
+	var req struct {
+		Address   string `json:"address"`
+		Accept    bool   `json:"accept"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	decision := "reject"
+	if req.Accept {
+		decision = "accept"
+	}
+	signer, err := verifyMemberRequest("confirm_treasury_keygen", req.Address, req.Timestamp, []byte(decision), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	if err := s.dao.ConfirmKeyGeneration(signer, req.Accept); err != nil {
+		return errorResponse(c, thresholdErrorStatus(err), err)
+	}
+
+	return s.handleGetThresholdKey(c)
+}
+
+// handleCommitThresholdNonce records the nonce commitment of a share holder
+// to sign a treasury transaction, signed over "<transaction id>:<commitment>"
+func (s *DAOServer) handleCommitThresholdNonce(c echo.Context) error {
+	var req struct {
+		Address       string `json:"address"`
+		TransactionID string `json:"transaction_id"`
+		Commitment    string `json:"commitment"`
+		Timestamp     int64  `json:"timestamp"`
+		Signature     string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	payload := req.TransactionID + ":" + req.Commitment
+	signer, err := verifyMemberRequest("commit_treasury_nonce", req.Address, req.Timestamp, []byte(payload), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	txID, err := hashFromHex(req.TransactionID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid transaction ID format")
+	}
+	commitment, err := hex.DecodeString(req.Commitment)
+	if err != nil {
+		return fieldErrorResponse(c, "invalid commitment", []FieldError{{Field: "commitment", Message: "Commitment must be hex encoded"}})
+	}
+
+	if err := s.dao.CommitThresholdNonce(txID, signer, commitment); err != nil {
+		return errorResponse(c, thresholdErrorStatus(err), err)
+	}
+
+	pendingTx, _ := s.dao.GetTreasuryTransaction(txID)
+	return c.JSON(http.StatusOK, newTreasuryTransactionResponse(pendingTx))
+}
+
+// handleSubmitThresholdPartial records the partial signature of a share
+// holder, signed over "<transaction id>:<partial>". The last partial of the
+// signing set executes the transaction.
+func (s *DAOServer) handleSubmitThresholdPartial(c echo.Context) error {
+	var req struct {
+		Address       string `json:"address"`
+		TransactionID string `json:"transaction_id"`
+		Partial       string `json:"partial"`
+		Timestamp     int64  `json:"timestamp"`
+		Signature     string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	payload := req.TransactionID + ":" + req.Partial
+	signer, err := verifyMemberRequest("sign_treasury_partial", req.Address, req.Timestamp, []byte(payload), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	txID, err := hashFromHex(req.TransactionID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid transaction ID format")
+	}
+	partial, err := hex.DecodeString(req.Partial)
+	if err != nil {
+		return fieldErrorResponse(c, "invalid partial signature", []FieldError{{Field: "partial", Message: "Partial signature must be hex encoded"}})
+	}
+
+	if err := s.dao.SubmitThresholdPartial(txID, signer, partial); err != nil {
+		return errorResponse(c, thresholdErrorStatus(err), err)
+	}
+
+	pendingTx, _ := s.dao.GetTreasuryTransaction(txID)
+	if pendingTx.Executed {
+		s.broadcastEvent(Event{
+			Type: EventTreasuryExecuted,
+			Data: map[string]interface{}{
+				"transaction_id": txID.String(),
+				"recipient":      pendingTx.Recipient.String(),
+				"amount":         pendingTx.Amount,
+			},
+			Timestamp: time.Now().Unix(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, newTreasuryTransactionResponse(pendingTx))
+}
+
+// thresholdErrorStatus maps threshold key errors to HTTP statuses
+func thresholdErrorStatus(err error) int {
+	if daoErr, ok := err.(*dao.DAOError); ok {
+		switch daoErr.Code {
+		case dao.ErrProposalNotFound:
+			return http.StatusNotFound
+		case dao.ErrUnauthorized:
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusBadRequest
+}
+
+// Token endpoints
+func (s *DAOServer) handleGetTokenBalance(c echo.Context) error {
+	addressStr := c.Param("address")
+
+	address, err := publicKeyFromHex(addressStr)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	// Privacy settings apply as on /dao/token/balances
+	viewer, err := viewerFromRequest(c)
+	if err != nil {
+		return errorMessage(c, http.StatusUnauthorized, err.Error())
+	}
+	if !s.dao.Privacy.CanView(address.String(), dao.PrivacyFieldBalance, viewer) {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"balance": 0,
+			"hidden":  []string{"balance"},
+		})
+	}
+
+	snapshot, status, err := s.stateAtHeight(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	var balance uint64
+	if snapshot != nil {
+		if _, err := snapshot.Decode(dao.BalanceStateKey(address.String()), &balance); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+	} else {
+		balance = s.dao.GetTokenBalance(address)
+	}
+
+	return c.JSON(http.StatusOK, map[string]uint64{
+		"balance": balance,
+	})
+}
+
+// MaxBalanceLookups bounds the addresses of one bulk balance lookup
+const MaxBalanceLookups = 500
+
+// TokenBalanceResponse is the token position of an address
+type TokenBalanceResponse struct {
+	Address     string   `json:"address"`
+	Balance     uint64   `json:"balance"`
+	Staked      uint64   `json:"staked"`
+	Reputation  uint64   `json:"reputation"`
+	VotingPower uint64   `json:"voting_power"`     // Effective voting power, with delegations
+	Hidden      []string `json:"hidden,omitempty"` // Fields the member's privacy settings withhold from the viewer, reported as 0
+}
+
+// handleGetTokenBalances looks up the balances, stakes, reputation and
+// voting power of many addresses at once, in the order they are given.
+// Privacy settings apply as on /dao/members.
+func (s *DAOServer) handleGetTokenBalances(c echo.Context) error {
+	var req struct {
+		Addresses []string `json:"addresses"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+	if len(req.Addresses) == 0 || len(req.Addresses) > MaxBalanceLookups {
+		return errorMessage(c, http.StatusBadRequest, fmt.Sprintf("addresses must list 1 to %d addresses", MaxBalanceLookups))
+	}
+
+	addresses := make([]crypto.PublicKey, len(req.Addresses))
+	var fields []FieldError
+	for i, addressStr := range req.Addresses {
+		address, err := publicKeyFromHex(addressStr)
+		if err != nil {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("addresses[%d]", i), Message: "Invalid address format"})
+			continue
+		}
+		addresses[i] = address
+	}
+	if len(fields) > 0 {
+		return fieldErrorResponse(c, "invalid addresses", fields)
+	}
+
+	viewer, err := viewerFromRequest(c)
+	if err != nil {
+		return errorMessage(c, http.StatusUnauthorized, err.Error())
+	}
+
+	powers := s.dao.GetEffectiveVotingPowers(addresses)
+	response := make([]TokenBalanceResponse, len(addresses))
+	for i, address := range addresses {
+		addressStr := address.String()
+		response[i] = TokenBalanceResponse{
+			Address:     addressStr,
+			Balance:     s.dao.GetTokenBalance(address),
+			VotingPower: powers[addressStr],
+		}
+		if holder, exists := s.dao.GovernanceState.TokenHolders[addressStr]; exists {
+			response[i].Staked = holder.Staked
+			response[i].Reputation = holder.Reputation
+		}
+
+		if !s.dao.Privacy.CanView(addressStr, dao.PrivacyFieldBalance, viewer) {
+			response[i].Balance = 0
+			response[i].Staked = 0
+			response[i].VotingPower = 0
+			response[i].Hidden = append(response[i].Hidden, "balance", "staked", "voting_power")
+		}
+		if !s.dao.Privacy.CanView(addressStr, dao.PrivacyFieldReputation, viewer) {
+			response[i].Reputation = 0
+			response[i].Hidden = append(response[i].Hidden, "reputation")
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"balances": response,
+	})
+}
+
+func (s *DAOServer) handleGetTokenSupply(c echo.Context) error {
+	supply := s.dao.GetTotalSupply()
+
+	return c.JSON(http.StatusOK, map[string]uint64{
+		"total_supply": supply,
+	})
+}
+
+func (s *DAOServer) handleTokenTransfer(c echo.Context) error {
+	var req struct {
+		To         string `json:"to"`
+		Amount     uint64 `json:"amount"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse recipient
+	to, err := publicKeyFromHex(req.To)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid recipient format")
+	}
+
+	// Create token transfer transaction
+	transferTx := &dao.TokenTransferTx{
+		Fee:       s.Config.DAO.Fees.Default,
+		Recipient: to,
+		Amount:    req.Amount,
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(transferTx)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "token transfer successful",
+	})
+}
+
+func (s *DAOServer) handleTokenApprove(c echo.Context) error {
+	var req struct {
+		Spender    string `json:"spender"`
+		Amount     uint64 `json:"amount"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse spender
+	spender, err := publicKeyFromHex(req.Spender)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid spender format")
+	}
+
+	// Create token approve transaction
+	approveTx := &dao.TokenApproveTx{
+		Fee:     s.Config.DAO.Fees.Default,
+		Spender: spender,
+		Amount:  req.Amount,
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(approveTx)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "token approval successful",
+	})
+}
+
+func (s *DAOServer) handleGetTokenAllowance(c echo.Context) error {
+	ownerStr := c.Param("owner")
+	spenderStr := c.Param("spender")
+
+	owner, err := publicKeyFromHex(ownerStr)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid owner address format")
+	}
+
+	spender, err := publicKeyFromHex(spenderStr)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid spender address format")
+	}
+
+	allowance := s.dao.GetTokenAllowance(owner, spender)
+
+	return c.JSON(http.StatusOK, map[string]uint64{
+		"allowance": allowance,
+	})
+}
+
+// Delegation endpoints
+func (s *DAOServer) handleDelegate(c echo.Context) error {
+	var req struct {
+		Delegate   string `json:"delegate"`
+		Duration   int64  `json:"duration"`
+		AutoRenew  bool   `json:"auto_renew"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse delegate
+	delegate, err := publicKeyFromHex(req.Delegate)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid delegate format")
+	}
+
+	// Create delegation transaction
+	delegationTx := &dao.DelegationTx{
+		Fee:       s.Config.DAO.Fees.Delegation,
+		Delegate:  delegate,
+		Duration:  req.Duration,
+		Revoke:    false,
+		AutoRenew: req.AutoRenew,
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(delegationTx)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	// Broadcast event
+	event := Event{
+		Type: EventDelegation,
+		Data: map[string]interface{}{
+			"delegator": privKey.PublicKey().String(),
+			"delegate":  req.Delegate,
+			"action":    "delegate",
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	s.broadcastEvent(event)
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "delegation successful",
+	})
+}
+
+func (s *DAOServer) handleRevokeDelegation(c echo.Context) error {
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Create revoke delegation transaction
+	delegationTx := &dao.DelegationTx{
+		Fee:      s.Config.DAO.Fees.Delegation,
+		Delegate: crypto.PublicKey{}, // Empty delegate for revocation
+		Duration: 0,
+		Revoke:   true,
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(delegationTx)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	// Broadcast event
+	event := Event{
+		Type: EventDelegation,
+		Data: map[string]interface{}{
+			"delegator": privKey.PublicKey().String(),
+			"action":    "revoke",
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	s.broadcastEvent(event)
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "delegation revoked successfully",
+	})
+}
+
+func (s *DAOServer) handleGetDelegation(c echo.Context) error {
+	addressStr := c.Param("address")
+
+	address, err := publicKeyFromHex(addressStr)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	snapshot, status, err := s.stateAtHeight(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	var (
+		delegation *dao.Delegation
+		exists     bool
+	)
+	if snapshot != nil {
+		delegation = &dao.Delegation{}
+		exists, err = snapshot.Decode(dao.DelegationStateKey(address.String()), delegation)
+		if err != nil {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+	} else {
+		delegation, exists = s.dao.GetDelegation(address)
+	}
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "delegation not found")
+	}
+
+	return c.JSON(http.StatusOK, newDelegationResponse(delegation, s.dao.Names))
+}
+
+func (s *DAOServer) handleGetDelegations(c echo.Context) error {
+	snapshot, status, err := s.stateAtHeight(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	// Past heights report the delegations that were marked active
+	if snapshot != nil {
+		response := []DelegationResponse{}
+		for _, key := range snapshot.Keys("delegation/") {
+			delegation := &dao.Delegation{}
+			if _, err := snapshot.Decode(key, delegation); err != nil {
+				return errorResponse(c, http.StatusInternalServerError, err)
+			}
+			if delegation.Active {
+				response = append(response, newDelegationResponse(delegation, s.dao.Names))
+			}
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
+	delegations := s.dao.ListDelegations()
+	response := make([]DelegationResponse, 0, len(delegations))
+
+	for _, delegation := range delegations {
+		response = append(response, newDelegationResponse(delegation, s.dao.Names))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Parameter endpoints
+func (s *DAOServer) handleGetParameters(c echo.Context) error {
+	snapshot, status, err := s.stateAtHeight(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	if snapshot == nil {
+		return c.JSON(http.StatusOK, s.dao.GetParameterConfig())
+	}
+
+	config := &dao.ParameterConfig{}
+	exists, err := snapshot.Decode(dao.ParametersStateKey, config)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "parameters not journaled at height")
+	}
+
+	return c.JSON(http.StatusOK, config)
+}
+
+// handleGetPendingParameters previews scheduled parameter changes, those
+// awaiting their vote, waiting for their activation height or ramping
+func (s *DAOServer) handleGetPendingParameters(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.dao.GetPendingParameterChanges())
+}
+
+// handleSimulateParameters replays the latest closed proposals under the
+// quorum and passing thresholds given as query parameters. proposals sets
+// how many are replayed.
+func (s *DAOServer) handleSimulateParameters(c echo.Context) error {
+	changes := make(map[string]string)
+	for _, param := range dao.SimulatedParameters {
+		if value := c.QueryParam(param); value != "" {
+			changes[param] = value
+		}
+	}
+	if len(changes) == 0 {
+		return errorMessage(c, http.StatusBadRequest, "one of "+strings.Join(dao.SimulatedParameters, ", ")+" is required")
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("proposals"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	simulation, err := s.dao.SimulateParameterChanges(changes, limit)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	return c.JSON(http.StatusOK, simulation)
+}
+
+// handleScheduleParameters proposes staged parameter changes
+func (s *DAOServer) handleScheduleParameters(c echo.Context) error {
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Changes     []struct {
+			Parameter        string          `json:"parameter"`
+			Value            json.RawMessage `json:"value"`
+			ActivationHeight uint32          `json:"activation_height"`
+			RampDays         uint16          `json:"ramp_days"`
+		} `json:"changes"`
+		VotingType dao.VotingType `json:"voting_type"`
+		StartTime  int64          `json:"start_time"`
+		EndTime    int64          `json:"end_time"`
+		Threshold  uint64         `json:"threshold"`
+		PrivateKey string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	changes := make([]dao.ScheduledParameterChange, len(req.Changes))
+	var fields []FieldError
+	for i, change := range req.Changes {
+		value, err := dao.ParameterValueString(change.Value)
+		if err != nil {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("changes[%d].value", i), Message: "Value must be a string, number or boolean"})
+		}
+		changes[i] = dao.ScheduledParameterChange{
+			Parameter:        change.Parameter,
+			Value:            value,
+			ActivationHeight: change.ActivationHeight,
+			RampPeriod:       int64(change.RampDays) * 86400,
+		}
+	}
+	if len(fields) > 0 {
+		return fieldErrorResponse(c, "invalid parameter schedule", fields)
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	scheduleTx := &dao.ParameterScheduleTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Title:       req.Title,
+		Description: req.Description,
+		Changes:     changes,
+		VotingType:  req.VotingType,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Threshold:   req.Threshold,
+	}
+
+	parameters := make([]string, len(changes))
+	for i, change := range changes {
+		parameters[i] = change.Parameter
+	}
+
+	return s.submitDAOTxWithEvent(c, scheduleTx, privKey, "parameter schedule proposed", EventParametersScheduled, map[string]interface{}{
+		"title":      req.Title,
+		"parameters": parameters,
+	})
+}
+
+// Member endpoints
+func (s *DAOServer) handleGetMember(c echo.Context) error {
+	addressStr := c.Param("address")
+
+	address, err := publicKeyFromHex(addressStr)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+	member, exists := s.dao.GetTokenHolder(address)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "member not found")
+	}
+
+	viewer, err := viewerFromRequest(c)
+	if err != nil {
+		return errorMessage(c, http.StatusUnauthorized, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, s.memberResponse(address.String(), member, viewer))
+}
+
+// memberResponse builds the response for a member, withholding the fields
+// their privacy settings hide from the viewer
+func (s *DAOServer) memberResponse(address string, holder *dao.TokenHolder, viewer crypto.PublicKey) MemberResponse {
+	response := MemberResponse{
+		Address:    address,
+		Name:       s.dao.Names.NameOfAddress(address),
+		Balance:    holder.Balance,
+		Staked:     holder.Staked,
+		Reputation: holder.Reputation,
+		JoinedAt:   holder.JoinedAt,
+		LastActive: holder.LastActive,
+		Status:     holder.Status.String(),
+	}
+	if record, exists := s.dao.Profiles.Get(address); exists {
+		response.Profile = record.Hash.String()
+	}
+
+	if !s.dao.Privacy.CanView(address, dao.PrivacyFieldBalance, viewer) {
+		response.Balance = 0
+		response.Staked = 0
+		response.Hidden = append(response.Hidden, "balance", "staked")
+	}
+	if !s.dao.Privacy.CanView(address, dao.PrivacyFieldReputation, viewer) {
+		response.Reputation = 0
+		response.Hidden = append(response.Hidden, "reputation")
+	}
+	return response
+}
+
+// viewerFromRequest returns who is viewing a request that reveals member
+// data. Viewers prove who they are with the viewer, timestamp and signature
+// query parameters, signing the "view_members" action. Requests without a
+// viewer are anonymous and a nil key is returned.
+func viewerFromRequest(c echo.Context) (crypto.PublicKey, error) {
+	viewer := c.QueryParam("viewer")
+	if viewer == "" {
+		return nil, nil
+	}
+
+	timestamp, err := strconv.ParseInt(c.QueryParam("timestamp"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp")
+	}
+	return verifyMemberRequest("view_members", viewer, timestamp, nil, c.QueryParam("signature"))
+}
+
+func (s *DAOServer) handleGetMembers(c echo.Context) error {
+	fields, err := parseFieldSet(c, MemberResponse{})
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Get pagination parameters
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	sortBy := c.QueryParam("sort")
+	if sortBy == "" {
+		sortBy = dao.HolderOrderBalance
+	}
+	if !dao.IsHolderOrder(sortBy) {
+		return errorMessage(c, http.StatusBadRequest, "sort must be one of "+strings.Join(dao.HolderOrders, ", "))
+	}
+
+	descending, err := parseSortOrder(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	viewer, err := viewerFromRequest(c)
+	if err != nil {
+		return errorMessage(c, http.StatusUnauthorized, err.Error())
+	}
+
+	addresses, total, err := s.dao.ListTokenHolders(sortBy, descending, (page-1)*limit, limit)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	response := make([]MemberResponse, 0, len(addresses))
+	for _, addressStr := range addresses {
+		holder, exists := s.dao.GovernanceState.TokenHolders[addressStr]
+		if !exists {
+			continue
+		}
+		response = append(response, s.memberResponse(addressStr, holder, viewer))
+	}
+	members, err := fields.Select(response)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"members": members,
+		"page":    page,
+		"limit":   limit,
+		"sort":    sortBy,
+		"total":   total,
+	})
+}
+
+func (s *DAOServer) handleGetMemberActivity(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	// Optional comma separated list of activity types, e.g. ?type=vote,token_transfer
+	var txTypes []string
+	if typeParam := c.QueryParam("type"); typeParam != "" {
+		for _, txType := range strings.Split(typeParam, ",") {
+			if txType = strings.TrimSpace(txType); txType != "" {
+				txTypes = append(txTypes, txType)
+			}
+		}
+	}
+
+	records, total := s.dao.GetMemberActivity(address, txTypes, (page-1)*limit, limit)
+
+	response := make([]ActivityResponse, len(records))
+	for i, record := range records {
+		response[i] = ActivityResponse{
+			TxHash:       record.TxHash.String(),
+			TxType:       record.TxType,
+			Role:         record.Role,
+			Counterparty: record.Counterparty,
+			Amount:       record.Amount,
+			Fee:          record.Fee,
+			BlockHeight:  record.BlockHeight,
+			Timestamp:    record.Timestamp,
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"activity": response,
+		"page":     page,
+		"limit":    limit,
+		"total":    total,
+	})
+}
+
+// handleGetMemberStatement returns the yearly token statement of an address
+// as JSON, or as CSV with format=csv
+func (s *DAOServer) handleGetMemberStatement(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	year := c.QueryParam("year")
+	if year == "" {
+		year = time.Now().UTC().Format("2006")
+	}
+
+	statement, err := s.dao.GetHolderStatement(address, year)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	switch c.QueryParam("format") {
+	case "", "json":
+		return c.JSON(http.StatusOK, statement)
+	case "csv":
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="statement-%s.csv"`, year))
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		c.Response().WriteHeader(http.StatusOK)
+		return statement.WriteCSV(c.Response())
+	default:
+		return errorMessage(c, http.StatusBadRequest, "format must be json or csv")
+	}
+}
+
+func (s *DAOServer) handleGetMemberPositions(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	positions := s.dao.GetPositionsByOwner(address)
+	response := make([]PositionResponse, len(positions))
+	for i, position := range positions {
+		response[i] = s.positionResponse(position)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func soulboundTokenResponse(token *dao.SoulboundToken) SoulboundTokenResponse {
+	response := SoulboundTokenResponse{
+		ID:       token.ID.String(),
+		Kind:     token.Kind,
+		Holder:   token.Holder.String(),
+		Name:     token.Name,
+		IssuedAt: token.IssuedAt,
+		BurnedAt: token.BurnedAt,
+		Active:   token.Active(),
+	}
+	if token.Kind == dao.SoulboundKindRole {
+		response.Role = token.Role.String()
+	}
+	return response
+}
+
+// handleGetMemberSoulboundTokens lists a member's soulbound tokens in mint
+// order, with the burned ones when include_burned is true
+func (s *DAOServer) handleGetMemberSoulboundTokens(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	includeBurned := false
+	if param := c.QueryParam("include_burned"); param != "" {
+		includeBurned, err = strconv.ParseBool(param)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "include_burned must be true or false")
+		}
+	}
+
+	tokens := s.dao.ListSoulboundTokens(address, includeBurned)
+	response := make([]SoulboundTokenResponse, len(tokens))
+	for i, token := range tokens {
+		response[i] = soulboundTokenResponse(token)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetSoulboundToken(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid token ID format")
+	}
+
+	token, exists := s.dao.GetSoulboundToken(id)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "soulbound token not found")
+	}
+
+	return c.JSON(http.StatusOK, soulboundTokenResponse(token))
+}
+
+// Position endpoints
+func (s *DAOServer) handleGetPosition(c echo.Context) error {
+	position, exists := s.dao.GetPosition(c.Param("id"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrPositionNotFound, "position not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, s.positionResponse(position))
+}
+
+func (s *DAOServer) handleTransferPosition(c echo.Context) error {
+	var req struct {
+		PositionID string `json:"position_id"`
+		Recipient  string `json:"recipient"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse recipient
+	recipient, err := publicKeyFromHex(req.Recipient)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid recipient format")
+	}
+
+	position, exists := s.dao.GetPosition(req.PositionID)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrPositionNotFound, "position not found", nil))
+	}
+
+	if !s.dao.PositionManager.IsTransferable(position.Type) {
+		return errorMessage(c, http.StatusBadRequest, "position is not transferable")
+	}
+
+	// Create position transfer transaction
+	transferTx := &dao.PositionTransferTx{
+		Fee:        s.Config.DAO.Fees.Default,
+		PositionID: req.PositionID,
+		Recipient:  recipient,
+	}
+
+	// Create and sign transaction
+	tx := s.newDAOTransaction(transferTx)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": tx.Hash(core.TxHasher{}).String(),
+		"message": "position transfer submitted",
+	})
+}
+
+// Bootstrap endpoints
+func (s *DAOServer) handleGetBootstrapStatus(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.dao.GetBootstrapStatus())
+}
+
+func (s *DAOServer) handleFounderVeto(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		Reason     string `json:"reason"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse proposal ID
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	if err := s.dao.FounderVeto(proposalID, privKey.PublicKey(), req.Reason); err != nil {
+		return errorResponse(c, http.StatusForbidden, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "proposal vetoed",
+	})
+}
+
+func (s *DAOServer) handleFastTrackProposal(c echo.Context) error {
+	var req struct {
+		ProposalID   string `json:"proposal_id"`
+		VotingPeriod int64  `json:"voting_period"`
+		PrivateKey   string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse proposal ID
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	if err := s.dao.FastTrackProposal(proposalID, privKey.PublicKey(), req.VotingPeriod); err != nil {
+		return errorResponse(c, http.StatusForbidden, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "proposal fast-tracked",
+	})
+}
+
+// Light client proof endpoints
+func (s *DAOServer) handleGetBalanceProof(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	return s.stateProof(c, dao.BalanceStateKey(address.String()))
+}
+
+func (s *DAOServer) handleGetProposalProof(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	return s.stateProof(c, dao.ProposalStateKey(proposalID))
+}
+
+func (s *DAOServer) handleGetVoteProof(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("proposal"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	voter, err := publicKeyFromHex(c.Param("voter"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid voter format")
+	}
+
+	return s.stateProof(c, dao.VoteStateKey(proposalID, voter.String()))
+}
+
+// stateProof responds with a Merkle proof of key against the latest header
+func (s *DAOServer) stateProof(c echo.Context, key string) error {
+	proof, height, stateRoot, err := s.bc.GetDAOStateProof(key)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	header, err := s.bc.GetHeader(height)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	steps := make([]ProofStepResponse, len(proof.Steps))
+	for i, step := range proof.Steps {
+		position := "right"
+		if step.Left {
+			position = "left"
+		}
+		steps[i] = ProofStepResponse{Hash: step.Hash.String(), Position: position}
+	}
+
+	return c.JSON(http.StatusOK, StateProofResponse{
+		Key:        proof.Key,
+		Value:      json.RawMessage(proof.Value),
+		ValueHex:   hex.EncodeToString(proof.Value),
+		Height:     height,
+		HeaderHash: core.BlockHasher{}.Hash(header).String(),
+		StateRoot:  stateRoot.String(),
+		Proof:      steps,
+	})
+}
+
+// Snapshot endpoints
+func (s *DAOServer) handleGetSnapshot(c echo.Context) error {
+	snapshot, status, err := s.stateAtHeight(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	if snapshot == nil {
+		if snapshot, err = s.bc.GetDAOStateAt(s.bc.Height()); err != nil {
+			return errorResponse(c, http.StatusNotFound, err)
+		}
+	}
+
+	response, err := s.snapshotResponse(snapshot)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// snapshotResponse lists the entries of a state snapshot with its state root
+func (s *DAOServer) snapshotResponse(snapshot *core.DAOStateSnapshot) (SnapshotResponse, error) {
+	stateRoot, err := s.bc.GetDAOStateRoot(snapshot.Height)
+	if err != nil {
+		return SnapshotResponse{}, err
+	}
+
+	response := SnapshotResponse{
+		Height:    snapshot.Height,
+		StateRoot: stateRoot.String(),
+		Entries:   []SnapshotEntryResponse{},
+	}
+	for _, key := range snapshot.Keys("") {
+		value, _ := snapshot.Get(key)
+		response.Entries = append(response.Entries, SnapshotEntryResponse{
+			Key:      key,
+			Value:    json.RawMessage(value),
+			ValueHex: hex.EncodeToString(value),
+		})
+	}
+	return response, nil
+}
+
+// syncEntries is the part of a state snapshot or changeset a sync reads
+type syncEntries interface {
+	Keys(prefix string) []string
+	Decode(key string, v interface{}) (bool, error)
+}
+
+// handleSync returns the changes since a height so mobile clients can keep
+// a local cache. Without since it returns the whole state.
+func (s *DAOServer) handleSync(c echo.Context) error {
+	var address crypto.PublicKey
+	if addressStr := c.QueryParam("address"); addressStr != "" {
+		parsed, err := publicKeyFromHex(addressStr)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid address format")
+		}
+		address = parsed
+	}
+
+	response := SyncResponse{
+		Proposals:            []ProposalResponse{},
+		Tallies:              []ProposalTallyResponse{},
+		RemovedProposals:     []string{},
+		TreasuryTransactions: []TreasuryTransactionResponse{},
+	}
+
+	var entries syncEntries
+	var removed []string
+	var previous *core.DAOStateSnapshot
+	if sinceStr := c.QueryParam("since"); sinceStr != "" {
+		since, err := strconv.ParseUint(sinceStr, 10, 32)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid since")
+		}
+
+		changes, err := s.bc.GetDAOStateChanges(uint32(since))
+		if err != nil {
+			return errorResponse(c, http.StatusNotFound, err)
+		}
+		entries = changes
+		removed = changes.Removed
+		response.Since = changes.Since
+		response.Height = changes.Height
+
+		for _, key := range removed {
+			if id := strings.TrimPrefix(key, "proposal/"); id != key {
+				response.RemovedProposals = append(response.RemovedProposals, id)
+			}
+		}
+
+		// Without the earlier state every changed proposal is sent whole
+		previous, _ = s.bc.GetDAOStateAt(changes.Since)
+	} else {
+		snapshot, err := s.bc.GetDAOStateAt(s.bc.Height())
+		if err != nil {
+			return errorResponse(c, http.StatusNotFound, err)
+		}
+		entries = snapshot
+		response.Height = snapshot.Height
+		response.Full = true
+	}
+
+	for _, key := range entries.Keys("proposal/") {
+		proposal := &dao.Proposal{}
+		if _, err := entries.Decode(key, proposal); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+
+		if previous != nil && onlyTallyChanged(previous, key, proposal) {
+			response.Tallies = append(response.Tallies, ProposalTallyResponse{
+				ProposalID: proposal.ID.String(),
+				Results:    proposal.Results,
+			})
+			continue
+		}
+		response.Proposals = append(response.Proposals, newProposalResponse(proposal, s.dao.Names))
+	}
+
+	if address != nil {
+		var balance uint64
+		key := dao.BalanceStateKey(address.String())
+		changed, err := entries.Decode(key, &balance)
+		if err != nil {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+		for _, removedKey := range removed {
+			changed = changed || removedKey == key
+		}
+		if changed || response.Full {
+			response.Balance = &SyncBalanceResponse{Address: address.String(), Balance: balance}
+		}
+	}
+
+	var treasury struct {
+		Balance      uint64
+		Signers      []crypto.PublicKey
+		RequiredSigs uint8
+	}
+	changed, err := entries.Decode("treasury", &treasury)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+	if changed {
+		signers := make([]string, len(treasury.Signers))
+		for i, signer := range treasury.Signers {
+			signers[i] = signer.String()
+		}
+		response.Treasury = &TreasuryResponse{
+			Balance:      treasury.Balance,
+			Signers:      signers,
+			RequiredSigs: treasury.RequiredSigs,
+		}
+	}
+
+	for _, key := range entries.Keys("treasury_tx/") {
+		tx := &dao.PendingTx{}
+		if _, err := entries.Decode(key, tx); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+		response.TreasuryTransactions = append(response.TreasuryTransactions, newTreasuryTransactionResponse(tx))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// onlyTallyChanged reports whether proposal differs from its version in
+// previous by its vote results alone
+func onlyTallyChanged(previous *core.DAOStateSnapshot, key string, proposal *dao.Proposal) bool {
+	before := &dao.Proposal{}
+	if exists, err := previous.Decode(key, before); !exists || err != nil {
+		return false
+	}
+
+	after := *proposal
+	before.Results, after.Results = nil, nil
+	beforeJSON, _ := json.Marshal(before)
+	afterJSON, _ := json.Marshal(&after)
+	return bytes.Equal(beforeJSON, afterJSON)
+}
+
+func (s *DAOServer) handleGetVoteSponsorship(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	sponsorship, exists := s.dao.GetVoteSponsorship(proposalID)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "proposal voting is not sponsored")
+	}
+
+	now := time.Now().Unix()
+	return c.JSON(http.StatusOK, VoteSponsorshipResponse{
+		ProposalID:      sponsorship.ProposalID.String(),
+		Budget:          sponsorship.Budget,
+		Spent:           sponsorship.Spent,
+		Remaining:       sponsorship.Remaining(),
+		SponsoredVoters: len(sponsorship.Voters),
+		WindowStart:     sponsorship.StartTime,
+		WindowEnd:       sponsorship.EndTime,
+		Active:          !sponsorship.Released && now >= sponsorship.StartTime && now <= sponsorship.EndTime,
+		Released:        sponsorship.Released,
+	})
+}
+
+func (s *DAOServer) handleGetProposalConditions(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	if _, err := s.dao.GetProposal(proposalID); err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	results := s.dao.EvaluateProposalConditions(proposalID)
+	response := make([]OracleConditionResponse, len(results))
+	for i, result := range results {
+		response[i] = OracleConditionResponse{
+			FeedID:   result.Condition.FeedID,
+			Operator: string(result.Condition.Operator),
+			Value:    result.Condition.Value,
+			Holds:    result.Holds,
+		}
+		if result.Err != nil {
+			apiErr := errorFromErr(http.StatusBadGateway, result.Err)
+			response[i].Error = &apiErr
+		} else {
+			current := result.Value
+			response[i].Current = &current
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetProposalDependencies(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	if _, err := s.dao.GetProposal(proposalID); err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	linked := func(ids []types.Hash) []ProposalDependencyResponse {
+		response := make([]ProposalDependencyResponse, 0, len(ids))
+		for _, id := range ids {
+			item := ProposalDependencyResponse{ID: id.String()}
+			if proposal, err := s.dao.GetProposal(id); err == nil {
+				item.Title = proposal.Title
+				item.Status = proposal.Status
+			}
+			response = append(response, item)
+		}
+		return response
+	}
+
+	response := ProposalDependenciesResponse{
+		DependsOn:  linked(s.dao.Dependencies.DependsOn(proposalID)),
+		Dependents: linked(s.dao.Dependencies.Dependents(proposalID)),
+		Blocking:   make([]string, 0),
+	}
+	for _, id := range s.dao.Dependencies.Blocking(proposalID) {
+		response.Blocking = append(response.Blocking, id.String())
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// handleSimulateProposal projects the outcome of an open proposal, with the
+// weight the optional address would contribute
+func (s *DAOServer) handleSimulateProposal(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	var voter crypto.PublicKey
+	if address := c.QueryParam("address"); address != "" {
+		if voter, err = publicKeyFromHex(address); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid address format")
+		}
+	}
+
+	simulation, err := s.dao.SimulateProposal(proposalID, voter)
+	if err != nil {
+		if err == dao.ErrProposalNotFoundError {
+			return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrProposalNotFound, "proposal not found", nil))
+		}
+		return errorResponse(c, http.StatusConflict, err)
+	}
+
+	return c.JSON(http.StatusOK, simulation)
+}
+
+// Public goods impact endpoints
+func (s *DAOServer) handleGetProposalImpact(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	impact, exists := s.dao.GetFundingImpact(proposalID)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "proposal has no KPIs attached")
+	}
+
+	reviews := make([]ImpactReviewResponse, len(impact.Reviews))
+	for i, review := range impact.Reviews {
+		reviews[i] = ImpactReviewResponse{
+			Reviewer:   review.Reviewer.String(),
+			Actuals:    review.Actuals,
+			ReportHash: review.ReportHash.String(),
+			ReviewedAt: review.ReviewedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, FundingImpactResponse{
+		ProposalID:  impact.ProposalID.String(),
+		Category:    impact.Category,
+		Grantee:     impact.Grantee.String(),
+		Amount:      impact.Amount,
+		KPIs:        impact.KPIs,
+		Reviews:     reviews,
+		Achievement: impact.Achievement(),
+		AttachedAt:  impact.AttachedAt,
+	})
+}
+
+func (s *DAOServer) handleAttachProposalKPIs(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		Category   string `json:"category"`
+		Grantee    string `json:"grantee"`
+		Amount     uint64 `json:"amount"`
+		KPIs       []struct {
+			Name   string `json:"name"`
+			Unit   string `json:"unit"`
+			Target uint64 `json:"target"`
+		} `json:"kpis"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	grantee, err := publicKeyFromHex(req.Grantee)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid grantee format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	kpis := make([]dao.KPITarget, len(req.KPIs))
+	for i, kpi := range req.KPIs {
+		kpis[i] = dao.KPITarget{Name: kpi.Name, Unit: kpi.Unit, Target: kpi.Target}
+	}
+
+	kpiTx := &dao.FundingKPITx{
+		Fee:        s.Config.DAO.Fees.Default,
+		ProposalID: proposalID,
+		Category:   req.Category,
+		Grantee:    grantee,
+		Amount:     req.Amount,
+		KPIs:       kpis,
+	}
+
+	return s.submitDAOTx(c, kpiTx, privKey, "KPIs submitted")
+}
+
+func (s *DAOServer) handleSubmitImpactReview(c echo.Context) error {
+	var req struct {
+		ProposalID string            `json:"proposal_id"`
+		Actuals    map[string]uint64 `json:"actuals"`
+		ReportHash string            `json:"report_hash"`
+		PrivateKey string            `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	reviewTx := &dao.ImpactReviewTx{
+		Fee:        s.Config.DAO.Fees.Default,
+		ProposalID: proposalID,
+		Actuals:    req.Actuals,
+	}
+
+	if req.ReportHash != "" {
+		if reviewTx.ReportHash, err = hashFromHex(req.ReportHash); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid report hash format")
+		}
+	}
+
+	return s.submitDAOTx(c, reviewTx, privKey, "impact review submitted")
+}
+
+// Validator endpoints
+func (s *DAOServer) validatorResponse(validator *dao.ValidatorInfo) ValidatorResponse {
+	response := ValidatorResponse{
+		Address:              validator.Address.String(),
+		PoolID:               validator.PoolID,
+		CommissionBps:        validator.CommissionBps,
+		LastCommissionChange: validator.LastCommissionChange,
+		BlocksProduced:       validator.BlocksProduced,
+		FeesEarned:           validator.FeesEarned,
+		CommissionEarned:     validator.CommissionEarned,
+		DelegatorRewards:     validator.DelegatorRewards,
+		ClaimableCommission:  validator.ClaimableCommission,
+		RegisteredAt:         validator.RegisteredAt,
+		Active:               validator.Active,
+	}
+
+	if pool, exists := s.dao.TokenomicsManager.GetStakingPool(validator.PoolID); exists {
+		response.DelegatedStake = pool.TotalStaked
+		response.DelegatorCount = len(pool.Stakers)
+	}
+
+	return response
+}
+
+func (s *DAOServer) handleGetValidators(c echo.Context) error {
+	validators := s.dao.ListValidators()
+	response := make([]ValidatorResponse, len(validators))
+	for i, validator := range validators {
+		response[i] = s.validatorResponse(validator)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetValidator(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	validator, exists := s.dao.GetValidator(address)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "validator not found")
+	}
+
+	return c.JSON(http.StatusOK, s.validatorResponse(validator))
+}
+
+func (s *DAOServer) handleConfigureValidator(c echo.Context) error {
+	var req struct {
+		PoolID        string `json:"pool_id"`
+		CommissionBps uint64 `json:"commission_bps"`
+		PrivateKey    string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	configTx := &dao.ValidatorConfigTx{
+		Fee:           s.Config.DAO.Fees.Default,
+		PoolID:        req.PoolID,
+		CommissionBps: req.CommissionBps,
+	}
+
+	return s.submitDAOTx(c, configTx, privKey, "validator config submitted")
+}
+
+func (s *DAOServer) handleClaimCommission(c echo.Context) error {
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	return s.submitDAOTx(c, &dao.ClaimCommissionTx{Fee: s.Config.DAO.Fees.Default}, privKey, "commission claim submitted")
+}
+
+func (s *DAOServer) handleProposeValidatorSetChange(c echo.Context) error {
+	var req struct {
+		Validator   string         `json:"validator"`
+		Remove      bool           `json:"remove"`
+		Description string         `json:"description"`
+		VotingType  dao.VotingType `json:"voting_type"`
+		StartTime   int64          `json:"start_time"`
+		EndTime     int64          `json:"end_time"`
+		Threshold   uint64         `json:"threshold"`
+		PrivateKey  string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	validator, err := publicKeyFromHex(req.Validator)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid validator address")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.ValidatorSetProposalTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Validator:   validator,
+		Remove:      req.Remove,
+		Description: req.Description,
+		VotingType:  req.VotingType,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Threshold:   req.Threshold,
+	}
+
+	return s.submitDAOTx(c, proposalTx, privKey, "validator set proposal submitted")
+}
+
+func (s *DAOServer) handleExecuteValidatorSetChange(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	executeTx := &dao.ValidatorSetExecuteTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTx(c, executeTx, privKey, "validator set change submitted")
+}
+
+func (s *DAOServer) handleGetFinality(c echo.Context) error {
+	status := s.bc.Finality()
+	response := FinalityResponse{
+		Finalized:       status.Finalized,
+		FinalizedHeight: status.Height,
+		Validators:      []ValidatorPowerResponse{},
+	}
+	if status.Finalized {
+		response.FinalizedHash = status.BlockHash.String()
+	}
+
+	for _, validator := range s.dao.ValidatorSet() {
+		response.Validators = append(response.Validators, ValidatorPowerResponse{
+			Address: validator.Address.String(),
+			Power:   validator.Power,
+		})
+		response.TotalPower += validator.Power
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Multisig endpoints
+func (s *DAOServer) multisigResponse(account *dao.MultisigAccount, withTransactions bool) MultisigResponse {
+	owners := make([]string, len(account.Owners))
+	for i, owner := range account.Owners {
+		owners[i] = owner.String()
+	}
+
+	response := MultisigResponse{
+		ID:        account.ID.String(),
+		Name:      account.Name,
+		Account:   account.Account().String(),
+		Address:   account.Account().Address().String(),
+		Owners:    owners,
+		Threshold: account.Threshold,
+		Creator:   account.Creator.String(),
+		CreatedAt: account.CreatedAt,
+		Balance:   s.dao.GetTokenBalance(account.Account()),
+	}
+
+	if withTransactions {
+		response.Transactions = make([]MultisigTransactionResponse, 0)
+		for _, pending := range s.dao.GetMultisigTransactions(account.ID) {
+			approvals := make([]string, len(pending.Approvals))
+			for i, approval := range pending.Approvals {
+				approvals[i] = approval.String()
+			}
+
+			item := MultisigTransactionResponse{
+				ID:          pending.ID.String(),
+				Type:        dao.ActivityTypeOf(pending.Tx),
+				Description: pending.Description,
+				Submitter:   pending.Submitter.String(),
+				Approvals:   approvals,
+				SubmittedAt: pending.SubmittedAt,
+				Executed:    pending.Executed,
+				ExecutedAt:  pending.ExecutedAt,
+			}
+			if pending.Executed {
+				item.ExecutedIn = pending.ExecutedIn.String()
+			}
+			response.Transactions = append(response.Transactions, item)
+		}
+	}
+
+	return response
+}
+
+func (s *DAOServer) handleCreateMultisig(c echo.Context) error {
+	var req struct {
+		Name       string   `json:"name"`
+		Owners     []string `json:"owners"`
+		Threshold  uint8    `json:"threshold"`
+		PrivateKey string   `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	owners := make([]crypto.PublicKey, len(req.Owners))
+	for i, owner := range req.Owners {
+		key, err := publicKeyFromHex(owner)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid owner address")
+		}
+		owners[i] = key
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	createTx := &dao.MultisigCreateTx{
+		Fee:       s.Config.DAO.Fees.Default,
+		Name:      req.Name,
+		Owners:    owners,
+		Threshold: req.Threshold,
+	}
+
+	return s.submitDAOTx(c, createTx, privKey, "multisig creation submitted")
+}
+
+func (s *DAOServer) handleGetMultisigs(c echo.Context) error {
+	var owner crypto.PublicKey
+	if ownerHex := c.QueryParam("owner"); ownerHex != "" {
+		key, err := publicKeyFromHex(ownerHex)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid owner address")
+		}
+		owner = key
+	}
+
+	accounts := s.dao.ListMultisigAccounts(owner)
+	response := make([]MultisigResponse, len(accounts))
+	for i, account := range accounts {
+		response[i] = s.multisigResponse(account, false)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetMultisig(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid multisig ID format")
+	}
+
+	account, exists := s.dao.GetMultisigAccount(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrMultisigNotFound, "multisig not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, s.multisigResponse(account, true))
+}
+
+func (s *DAOServer) handleSubmitMultisigTx(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid multisig ID format")
+	}
+
+	var req struct {
+		Description string                `json:"description"`
+		Action      MultisigActionRequest `json:"action"`
+		PrivateKey  string                `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	action, err := s.multisigAction(req.Action)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	submitTx := &dao.MultisigSubmitTx{
+		Fee:         s.Config.DAO.Fees.Default,
+		MultisigID:  id,
+		Description: req.Description,
+		Tx:          action,
+	}
+
+	return s.submitDAOTx(c, submitTx, privKey, "multisig transaction submitted")
+}
+
+func (s *DAOServer) handleSignMultisigTx(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid multisig ID format")
+	}
+
+	var req struct {
+		TxID       string `json:"tx_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	txID, err := hashFromHex(req.TxID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid transaction ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	signTx := &dao.MultisigSignTx{
+		Fee:        s.Config.DAO.Fees.Default,
+		MultisigID: id,
+		TxID:       txID,
+	}
+
+	return s.submitDAOTx(c, signTx, privKey, "multisig signature submitted")
+}
+
+// multisigAction builds the DAO transaction a multisig submits, charging
+// the fees the API charges members for the same transaction
+func (s *DAOServer) multisigAction(req MultisigActionRequest) (interface{}, error) {
+	fees := s.Config.DAO.Fees
+
+	switch req.Type {
+	case dao.ActivityTypeTokenTransfer:
+		recipient, err := publicKeyFromHex(req.Recipient)
+		if err != nil || len(recipient) == 0 {
+			return nil, fmt.Errorf("invalid recipient address")
+		}
+		return &dao.TokenTransferTx{Fee: fees.Default, Recipient: recipient, Amount: req.Amount}, nil
+	case dao.ActivityTypeVote:
+		proposalID, err := hashFromHex(req.ProposalID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proposal ID format")
+		}
+		return &dao.VoteTx{Fee: fees.Vote, ProposalID: proposalID, Choice: req.Choice, Weight: req.Weight, Reason: req.Reason}, nil
+	case dao.ActivityTypeProposal:
+		now := time.Now().Unix()
+		return &dao.ProposalTx{
+			Fee:          fees.Proposal,
+			Title:        req.Title,
+			Description:  req.Description,
+			ProposalType: req.ProposalType,
+			VotingType:   req.VotingType,
+			StartTime:    now,
+			EndTime:      now + req.Duration,
+			Threshold:    req.Threshold,
+		}, nil
+	case dao.ActivityTypeDelegation:
+		var delegate crypto.PublicKey
+		if !req.Revoke {
+			key, err := publicKeyFromHex(req.Delegate)
+			if err != nil || len(key) == 0 {
+				return nil, fmt.Errorf("invalid delegate address")
+			}
+			delegate = key
+		}
+		return &dao.DelegationTx{Fee: fees.Delegation, Delegate: delegate, Duration: req.Duration, Revoke: req.Revoke, AutoRenew: req.AutoRenew}, nil
+	case dao.ActivityTypeGrantReview:
+		grantID, err := hashFromHex(req.GrantID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grant ID format")
+		}
+		return &dao.GrantMilestoneReviewTx{Fee: fees.Default, GrantID: grantID, Milestone: req.Milestone, Approve: req.Approve, Comment: req.Reason}, nil
+	case dao.ActivityTypeQFPayout:
+		roundID, err := hashFromHex(req.RoundID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid round ID format")
+		}
+		return &dao.QFPayoutTx{Fee: fees.Default, RoundID: roundID}, nil
+	default:
+		return nil, fmt.Errorf("unsupported multisig action %q", req.Type)
+	}
+}
+
+// Sub-DAO endpoints
+var subDAOActions = map[string]dao.SubDAOAction{
+	"spend":         dao.SubDAOActionSpend,
+	"add_member":    dao.SubDAOActionAddMember,
+	"remove_member": dao.SubDAOActionRemoveMember,
+	"signal":        dao.SubDAOActionSignal,
+}
+
+func subDAOActionName(action dao.SubDAOAction) string {
+	for name, value := range subDAOActions {
+		if value == action {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func (s *DAOServer) subDAOResponse(subDAO *dao.SubDAO, withProposals bool) SubDAOResponse {
+	now := time.Now().Unix()
+
+	members := make([]string, len(subDAO.Members))
+	for i, member := range subDAO.Members {
+		members[i] = member.String()
+	}
+
+	response := SubDAOResponse{
+		ID:          subDAO.ID.String(),
+		Name:        subDAO.Name,
+		Description: subDAO.Description,
+		Members:     members,
+		Budget:      subDAO.Budget,
+		StreamStart: subDAO.StreamStart,
+		StreamEnd:   subDAO.StreamEnd,
+		Vested:      subDAO.Vested(now),
+		Streamed:    subDAO.Streamed,
+		Spent:       subDAO.Spent,
+		Balance:     subDAO.Balance(),
+		CreatedAt:   subDAO.CreatedAt,
+	}
+
+	if withProposals {
+		response.Proposals = make([]SubDAOProposalResponse, 0)
+		for _, proposal := range s.dao.GetSubDAOProposals(subDAO.ID) {
+			item := SubDAOProposalResponse{
+				ID:          proposal.ID.String(),
+				Proposer:    proposal.Proposer.String(),
+				Title:       proposal.Title,
+				Description: proposal.Description,
+				Action:      subDAOActionName(proposal.Action),
+				Amount:      proposal.Amount,
+				CreatedAt:   proposal.CreatedAt,
+				EndTime:     proposal.EndTime,
+				YesVotes:    proposal.YesVotes,
+				NoVotes:     proposal.NoVotes,
+				Status:      string(proposal.StatusAt(now)),
+				ResolvedAt:  proposal.ResolvedAt,
+			}
+			if len(proposal.Target) > 0 {
+				item.Target = proposal.Target.String()
+			}
+			response.Proposals = append(response.Proposals, item)
+		}
+	}
+
+	return response
+}
+
+func (s *DAOServer) handleProposeSubDAO(c echo.Context) error {
+	var req struct {
+		Name           string         `json:"name"`
+		Description    string         `json:"description"`
+		Members        []string       `json:"members"`
+		Budget         uint64         `json:"budget"`
+		StreamDuration int64          `json:"stream_duration"`
+		VotingType     dao.VotingType `json:"voting_type"`
+		StartTime      int64          `json:"start_time"`
+		EndTime        int64          `json:"end_time"`
+		Threshold      uint64         `json:"threshold"`
+		PrivateKey     string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	members := make([]crypto.PublicKey, len(req.Members))
+	for i, member := range req.Members {
+		key, err := publicKeyFromHex(member)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid member address")
+		}
+		members[i] = key
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.SubDAOCreateProposalTx{
+		Fee:            s.Config.DAO.Fees.Proposal,
+		Name:           req.Name,
+		Description:    req.Description,
+		Members:        members,
+		Budget:         req.Budget,
+		StreamDuration: req.StreamDuration,
+		VotingType:     req.VotingType,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Threshold:      req.Threshold,
+	}
+
+	return s.submitDAOTx(c, proposalTx, privKey, "sub-DAO proposal submitted")
+}
+
+func (s *DAOServer) handleExecuteSubDAO(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	executeTx := &dao.SubDAOCreateExecuteTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTx(c, executeTx, privKey, "sub-DAO creation submitted")
+}
+
+func (s *DAOServer) handleGetSubDAOs(c echo.Context) error {
+	subDAOs := s.dao.ListSubDAOs()
+	response := make([]SubDAOResponse, len(subDAOs))
+	for i, subDAO := range subDAOs {
+		response[i] = s.subDAOResponse(subDAO, false)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetSubDAO(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid sub-DAO ID format")
+	}
+
+	subDAO, exists := s.dao.GetSubDAO(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrSubDAONotFound, "sub-DAO not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, s.subDAOResponse(subDAO, true))
+}
+
+func (s *DAOServer) handleCreateSubDAOProposal(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid sub-DAO ID format")
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Action      string `json:"action"` // spend, add_member, remove_member or signal
+		Target      string `json:"target"`
+		Amount      uint64 `json:"amount"`
+		Duration    int64  `json:"duration"` // Voting window in seconds
+		PrivateKey  string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	action, ok := subDAOActions[req.Action]
+	if !ok {
+		return errorMessage(c, http.StatusBadRequest, "invalid sub-DAO action")
+	}
+
+	var target crypto.PublicKey
+	if req.Target != "" {
+		if target, err = publicKeyFromHex(req.Target); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid target address")
+		}
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.SubDAOProposalTx{
+		Fee:         s.Config.DAO.Fees.Default,
+		SubDAOID:    id,
+		Title:       req.Title,
+		Description: req.Description,
+		Action:      action,
+		Target:      target,
+		Amount:      req.Amount,
+		Duration:    req.Duration,
+	}
+
+	return s.submitDAOTx(c, proposalTx, privKey, "sub-DAO proposal submitted")
+}
+
+func (s *DAOServer) handleVoteSubDAOProposal(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid sub-DAO ID format")
+	}
+
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		Support    bool   `json:"support"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	voteTx := &dao.SubDAOVoteTx{
+		Fee:        s.Config.DAO.Fees.Vote,
+		SubDAOID:   id,
+		ProposalID: proposalID,
+		Support:    req.Support,
+	}
+
+	return s.submitDAOTx(c, voteTx, privKey, "sub-DAO vote submitted")
+}
+
+// Grant endpoints
+func (s *DAOServer) grantResponse(grant *dao.Grant) GrantResponse {
+	milestones := make([]GrantMilestoneResponse, len(grant.Milestones))
+	for i, milestone := range grant.Milestones {
+		milestones[i] = GrantMilestoneResponse{
+			Index:       i,
+			Title:       milestone.Title,
+			Description: milestone.Description,
+			Amount:      milestone.Amount,
+			Status:      string(milestone.Status),
+			Deliverable: milestone.Deliverable,
+			SubmittedAt: milestone.SubmittedAt,
+			Comment:     milestone.Comment,
+			ReviewedAt:  milestone.ReviewedAt,
+			PaidAt:      milestone.PaidAt,
+		}
+	}
+
+	return GrantResponse{
+		ID:          grant.ID.String(),
+		Title:       grant.Title,
+		Description: grant.Description,
+		Applicant:   grant.Applicant.String(),
+		Committee:   grant.Committee.String(),
+		Milestones:  milestones,
+		Total:       grant.Total,
+		Escrowed:    grant.Escrowed,
+		Paid:        grant.Paid,
+		Status:      string(s.dao.GrantManager.StatusOf(grant)),
+		CreatedAt:   grant.CreatedAt,
+		FundedAt:    grant.FundedAt,
+		ClosedAt:    grant.ClosedAt,
+	}
+}
+
+func (s *DAOServer) handleGetGrants(c echo.Context) error {
+	var applicant crypto.PublicKey
+	if applicantHex := c.QueryParam("applicant"); applicantHex != "" {
+		key, err := publicKeyFromHex(applicantHex)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid applicant address")
+		}
+		applicant = key
+	}
+
+	grants := s.dao.ListGrants(dao.GrantStatus(c.QueryParam("status")), applicant)
+	response := make([]GrantResponse, len(grants))
+	for i, grant := range grants {
+		response[i] = s.grantResponse(grant)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetGrantPipeline(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.dao.GetGrantPipeline())
+}
+
+func (s *DAOServer) handleGetGrant(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid grant ID format")
+	}
+
+	grant, exists := s.dao.GetGrant(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrGrantNotFound, "grant not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, s.grantResponse(grant))
+}
+
+func (s *DAOServer) handleProposeGrant(c echo.Context) error {
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Milestones  []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Amount      uint64 `json:"amount"`
+		} `json:"milestones"`
+		Committee  string         `json:"committee"`
+		VotingType dao.VotingType `json:"voting_type"`
+		StartTime  int64          `json:"start_time"`
+		EndTime    int64          `json:"end_time"`
+		Threshold  uint64         `json:"threshold"`
+		PrivateKey string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	committee, err := publicKeyFromHex(req.Committee)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid committee address")
+	}
+
+	milestones := make([]dao.GrantMilestoneSpec, len(req.Milestones))
+	for i, milestone := range req.Milestones {
+		milestones[i] = dao.GrantMilestoneSpec{
+			Title:       milestone.Title,
+			Description: milestone.Description,
+			Amount:      milestone.Amount,
+		}
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.GrantProposalTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Title:       req.Title,
+		Description: req.Description,
+		Milestones:  milestones,
+		Committee:   committee,
+		VotingType:  req.VotingType,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Threshold:   req.Threshold,
+	}
+
+	return s.submitDAOTx(c, proposalTx, privKey, "grant proposal submitted")
+}
+
+func (s *DAOServer) handleExecuteGrant(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid grant ID format")
+	}
+
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	executeTx := &dao.GrantExecuteTx{Fee: s.Config.DAO.Fees.Treasury, ProposalID: id}
+
+	return s.submitDAOTx(c, executeTx, privKey, "grant funding submitted")
+}
+
+// grantMilestoneParams parses the grant ID and milestone index of a
+// milestone route
+func grantMilestoneParams(c echo.Context) (types.Hash, uint8, error) {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return types.Hash{}, 0, fmt.Errorf("invalid grant ID format")
+	}
+
+	index, err := strconv.ParseUint(c.Param("index"), 10, 8)
+	if err != nil {
+		return types.Hash{}, 0, fmt.Errorf("invalid milestone index")
+	}
+
+	return id, uint8(index), nil
+}
+
+func (s *DAOServer) handleSubmitGrantMilestone(c echo.Context) error {
+	id, index, err := grantMilestoneParams(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	var req struct {
+		Deliverable string `json:"deliverable"`
+		PrivateKey  string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	submitTx := &dao.GrantMilestoneSubmitTx{
+		Fee:         s.Config.DAO.Fees.Default,
+		GrantID:     id,
+		Milestone:   index,
+		Deliverable: req.Deliverable,
+	}
+
+	return s.submitDAOTx(c, submitTx, privKey, "milestone submitted")
+}
+
+func (s *DAOServer) handleReviewGrantMilestone(c echo.Context) error {
+	id, index, err := grantMilestoneParams(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	var req struct {
+		Approve    bool   `json:"approve"`
+		Comment    string `json:"comment"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	reviewTx := &dao.GrantMilestoneReviewTx{
+		Fee:       s.Config.DAO.Fees.Default,
+		GrantID:   id,
+		Milestone: index,
+		Approve:   req.Approve,
+		Comment:   req.Comment,
+	}
+
+	return s.submitDAOTx(c, reviewTx, privKey, "milestone review submitted")
+}
+
+func (s *DAOServer) handleCancelGrant(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid grant ID format")
+	}
+
+	var req struct {
+		Reason     string `json:"reason"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	cancelTx := &dao.GrantCancelTx{Fee: s.Config.DAO.Fees.Default, GrantID: id, Reason: req.Reason}
+
+	return s.submitDAOTx(c, cancelTx, privKey, "grant cancellation submitted")
+}
+
+// Bounty endpoints
+func bountyResponse(bounty *dao.Bounty, now int64) BountyResponse {
+	response := BountyResponse{
+		ID:            bounty.ID.String(),
+		Title:         bounty.Title,
+		Description:   bounty.Description,
+		Reward:        bounty.Reward,
+		Reviewer:      bounty.Reviewer.String(),
+		ClaimPeriod:   bounty.ClaimPeriod,
+		Proposer:      bounty.Proposer.String(),
+		Status:        string(bounty.StatusAt(now)),
+		SubmittedAt:   bounty.SubmittedAt,
+		ReviewComment: bounty.ReviewComment,
+		ReviewedAt:    bounty.ReviewedAt,
+		CreatedAt:     bounty.CreatedAt,
+		PostedAt:      bounty.PostedAt,
+		ClosedAt:      bounty.ClosedAt,
+	}
+
+	if len(bounty.Claimant) > 0 {
+		response.Claimant = bounty.Claimant.String()
+		response.ClaimedAt = bounty.ClaimedAt
+		response.ClaimExpires = bounty.ClaimExpires
+	}
+	if !bounty.WorkHash.IsZero() {
+		response.WorkHash = bounty.WorkHash.String()
+	}
+
+	return response
+}
+
+func (s *DAOServer) handleGetBounties(c echo.Context) error {
+	var claimant crypto.PublicKey
+	if claimantHex := c.QueryParam("claimant"); claimantHex != "" {
+		key, err := publicKeyFromHex(claimantHex)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid claimant address")
+		}
+		claimant = key
+	}
+
+	now := time.Now().Unix()
+	bounties := s.dao.ListBounties(dao.BountyStatus(c.QueryParam("status")), claimant)
+	response := make([]BountyResponse, len(bounties))
+	for i, bounty := range bounties {
+		response[i] = bountyResponse(bounty, now)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetBounty(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid bounty ID format")
+	}
+
+	bounty, exists := s.dao.GetBounty(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrBountyNotFound, "bounty not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, bountyResponse(bounty, time.Now().Unix()))
+}
+
+func (s *DAOServer) handleProposeBounty(c echo.Context) error {
+	var req struct {
+		Title       string         `json:"title"`
+		Description string         `json:"description"`
+		Reward      uint64         `json:"reward"`
+		Reviewer    string         `json:"reviewer"`
+		ClaimPeriod int64          `json:"claim_period"`
+		VotingType  dao.VotingType `json:"voting_type"`
+		StartTime   int64          `json:"start_time"`
+		EndTime     int64          `json:"end_time"`
+		Threshold   uint64         `json:"threshold"`
+		PrivateKey  string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	reviewer, err := publicKeyFromHex(req.Reviewer)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid reviewer address")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.BountyProposalTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Title:       req.Title,
+		Description: req.Description,
+		Reward:      req.Reward,
+		Reviewer:    reviewer,
+		ClaimPeriod: req.ClaimPeriod,
+		VotingType:  req.VotingType,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Threshold:   req.Threshold,
+	}
+
+	return s.submitDAOTxWithEvent(c, proposalTx, privKey, "bounty proposal submitted", EventBountyProposed, map[string]interface{}{
+		"title":  req.Title,
+		"reward": req.Reward,
+	})
+}
+
+// bountyAction parses the bounty ID and signing key shared by the bounty
+// state changes
+func bountyAction(c echo.Context, req interface{}, privateKey *string) (types.Hash, crypto.PrivateKey, error) {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return types.Hash{}, crypto.PrivateKey{}, fmt.Errorf("invalid bounty ID format")
+	}
+
+	if err := c.Bind(req); err != nil {
+		return types.Hash{}, crypto.PrivateKey{}, fmt.Errorf("invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(*privateKey)
+	if err != nil {
+		return types.Hash{}, crypto.PrivateKey{}, fmt.Errorf("invalid private key format")
+	}
+
+	return id, privKey, nil
+}
+
+func (s *DAOServer) handlePostBounty(c echo.Context) error {
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	id, privKey, err := bountyAction(c, &req, &req.PrivateKey)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	postTx := &dao.BountyPostTx{Fee: s.Config.DAO.Fees.Treasury, ProposalID: id}
+
+	return s.submitDAOTxWithEvent(c, postTx, privKey, "bounty posting submitted", EventBountyPosted, map[string]interface{}{
+		"bounty_id": id.String(),
+	})
+}
+
+func (s *DAOServer) handleClaimBounty(c echo.Context) error {
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	id, privKey, err := bountyAction(c, &req, &req.PrivateKey)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	claimTx := &dao.BountyClaimTx{Fee: s.Config.DAO.Fees.Default, BountyID: id}
+
+	return s.submitDAOTxWithEvent(c, claimTx, privKey, "bounty claim submitted", EventBountyClaimed, map[string]interface{}{
+		"bounty_id": id.String(),
+	})
+}
+
+func (s *DAOServer) handleSubmitBountyWork(c echo.Context) error {
+	var req struct {
+		WorkHash   string `json:"work_hash"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	id, privKey, err := bountyAction(c, &req, &req.PrivateKey)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	workHash, err := hashFromHex(req.WorkHash)
+	if err != nil || workHash.IsZero() {
+		return errorMessage(c, http.StatusBadRequest, "invalid work hash format")
+	}
+
+	submitTx := &dao.BountySubmitTx{Fee: s.Config.DAO.Fees.Default, BountyID: id, WorkHash: workHash}
+
+	return s.submitDAOTxWithEvent(c, submitTx, privKey, "bounty work submitted", EventBountySubmitted, map[string]interface{}{
+		"bounty_id": id.String(),
+		"work_hash": workHash.String(),
+	})
+}
+
+func (s *DAOServer) handleReviewBounty(c echo.Context) error {
+	var req struct {
+		Approve    bool   `json:"approve"`
+		Comment    string `json:"comment"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	id, privKey, err := bountyAction(c, &req, &req.PrivateKey)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	reviewTx := &dao.BountyReviewTx{
+		Fee:      s.Config.DAO.Fees.Default,
+		BountyID: id,
+		Approve:  req.Approve,
+		Comment:  req.Comment,
+	}
+
+	eventType := EventBountyDisputed
+	if req.Approve {
+		eventType = EventBountyApproved
+	}
+
+	return s.submitDAOTxWithEvent(c, reviewTx, privKey, "bounty review submitted", eventType, map[string]interface{}{
+		"bounty_id": id.String(),
+		"comment":   req.Comment,
+	})
+}
+
+func (s *DAOServer) handleCancelBounty(c echo.Context) error {
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	id, privKey, err := bountyAction(c, &req, &req.PrivateKey)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	cancelTx := &dao.BountyCancelTx{Fee: s.Config.DAO.Fees.Default, BountyID: id}
+
+	return s.submitDAOTxWithEvent(c, cancelTx, privKey, "bounty cancellation submitted", EventBountyCancelled, map[string]interface{}{
+		"bounty_id": id.String(),
+	})
+}
+
+// Quadratic funding endpoints
+func (s *DAOServer) qfRoundResponse(round *dao.QFRound, now int64) QFRoundResponse {
+	status := s.dao.QFManager.StatusOf(round, now)
+
+	var estimated []uint64
+	if status == dao.QFRoundStatusOpen || status == dao.QFRoundStatusTallying {
+		estimated = round.Matching()
+	}
+
+	projects := make([]QFProjectResponse, len(round.Projects))
+	for i, project := range round.Projects {
+		projects[i] = QFProjectResponse{
+			Index:        i,
+			Name:         project.Name,
+			Description:  project.Description,
+			Recipient:    project.Recipient.String(),
+			Raised:       project.Raised,
+			Contributors: len(project.Contributions),
+			Matching:     project.Matching,
+		}
+		if estimated != nil {
+			projects[i].EstimatedMatching = estimated[i]
+		}
+	}
+
+	return QFRoundResponse{
+		ID:                 round.ID.String(),
+		Title:              round.Title,
+		Description:        round.Description,
+		MatchingPool:       round.MatchingPool,
+		Dampening:          round.Dampening,
+		ContributionPeriod: round.ContributionPeriod,
+		Multisig:           round.Multisig.String(),
+		Proposer:           round.Proposer.String(),
+		Status:             string(status),
+		Projects:           projects,
+		CreatedAt:          round.CreatedAt,
+		OpenedAt:           round.OpenedAt,
+		ClosesAt:           round.ClosesAt,
+		PaidAt:             round.PaidAt,
+		Matched:            round.Matched,
+		Returned:           round.Returned,
+	}
+}
+
+func (s *DAOServer) handleGetQFRounds(c echo.Context) error {
+	now := time.Now().Unix()
+	rounds := s.dao.ListQFRounds(dao.QFRoundStatus(c.QueryParam("status")))
+	response := make([]QFRoundResponse, len(rounds))
+	for i, round := range rounds {
+		response[i] = s.qfRoundResponse(round, now)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetQFRound(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	round, exists := s.dao.GetQFRound(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrQFRoundNotFoundError)
+	}
+
+	return c.JSON(http.StatusOK, s.qfRoundResponse(round, time.Now().Unix()))
+}
+
+func (s *DAOServer) handleProposeQFRound(c echo.Context) error {
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Projects    []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Recipient   string `json:"recipient"`
+		} `json:"projects"`
+		MatchingPool       uint64         `json:"matching_pool"`
+		Dampening          uint64         `json:"dampening"`
+		ContributionPeriod int64          `json:"contribution_period"`
+		Multisig           string         `json:"multisig"`
+		VotingType         dao.VotingType `json:"voting_type"`
+		StartTime          int64          `json:"start_time"`
+		EndTime            int64          `json:"end_time"`
+		Threshold          uint64         `json:"threshold"`
+		PrivateKey         string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	multisig, err := hashFromHex(req.Multisig)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid multisig ID format")
+	}
+
+	projects := make([]dao.QFProjectSpec, len(req.Projects))
+	for i, project := range req.Projects {
+		recipient, err := publicKeyFromHex(project.Recipient)
+		if err != nil || len(recipient) == 0 {
+			return fieldErrorResponse(c, "invalid project", []FieldError{{
+				Field:   fmt.Sprintf("projects[%d].recipient", i),
+				Message: "invalid recipient address",
+			}})
+		}
+		projects[i] = dao.QFProjectSpec{
+			Name:        project.Name,
+			Description: project.Description,
+			Recipient:   recipient,
+		}
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.QFRoundProposalTx{
+		Fee:                s.Config.DAO.Fees.Proposal,
+		Title:              req.Title,
+		Description:        req.Description,
+		Projects:           projects,
+		MatchingPool:       req.MatchingPool,
+		Dampening:          req.Dampening,
+		ContributionPeriod: req.ContributionPeriod,
+		Multisig:           multisig,
+		VotingType:         req.VotingType,
+		StartTime:          req.StartTime,
+		EndTime:            req.EndTime,
+		Threshold:          req.Threshold,
+	}
+
+	return s.submitDAOTxWithEvent(c, proposalTx, privKey, "quadratic funding round proposed", EventQFRoundProposed, map[string]interface{}{
+		"title":         req.Title,
+		"matching_pool": req.MatchingPool,
+	})
+}
+
+func (s *DAOServer) handleOpenQFRound(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	openTx := &dao.QFRoundOpenTx{Fee: s.Config.DAO.Fees.Treasury, ProposalID: id}
+
+	return s.submitDAOTxWithEvent(c, openTx, privKey, "quadratic funding round opening submitted", EventQFRoundOpened, map[string]interface{}{
+		"round_id": id.String(),
+	})
+}
+
+func (s *DAOServer) handleContributeQF(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		Project    uint8  `json:"project"`
+		Amount     uint64 `json:"amount"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	contributeTx := &dao.QFContributeTx{
+		Fee:     s.Config.DAO.Fees.Default,
+		RoundID: id,
+		Project: req.Project,
+		Amount:  req.Amount,
+	}
+
+	return s.submitDAOTxWithEvent(c, contributeTx, privKey, "contribution submitted", EventQFContribution, map[string]interface{}{
+		"round_id": id.String(),
+		"project":  req.Project,
+		"amount":   req.Amount,
+	})
+}
+
+// Optimistic proposal endpoints
+func optimisticProposalResponse(optimistic *dao.OptimisticProposal) OptimisticProposalResponse {
+	response := OptimisticProposalResponse{
+		ID:              optimistic.ID.String(),
+		Proposer:        optimistic.Proposer.String(),
+		Title:           optimistic.Title,
+		Description:     optimistic.Description,
+		ProposalType:    optimistic.ProposalType,
+		VotingType:      optimistic.VotingType,
+		Threshold:       optimistic.Threshold,
+		VotingPeriod:    optimistic.VotingPeriod,
+		Bond:            optimistic.Bond,
+		Status:          string(optimistic.Status),
+		CreatedAt:       optimistic.CreatedAt,
+		ChallengeEnds:   optimistic.ChallengeEnds,
+		ChallengeReason: optimistic.ChallengeReason,
+		ChallengedAt:    optimistic.ChallengedAt,
+		ResolvedAt:      optimistic.ResolvedAt,
+	}
+	if !optimistic.MetadataHash.IsZero() {
+		response.MetadataHash = optimistic.MetadataHash.String()
+	}
+	if len(optimistic.Challenger) > 0 {
+		response.Challenger = optimistic.Challenger.String()
+	}
+	return response
+}
+
+func (s *DAOServer) handleGetOptimisticProposals(c echo.Context) error {
+	proposals := s.dao.ListOptimisticProposals(dao.OptimisticStatus(c.QueryParam("status")))
+	response := make([]OptimisticProposalResponse, len(proposals))
+	for i, optimistic := range proposals {
+		response[i] = optimisticProposalResponse(optimistic)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetOptimisticProposal(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	optimistic, exists := s.dao.GetOptimisticProposal(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrOptimisticNotFoundError)
+	}
+
+	return c.JSON(http.StatusOK, optimisticProposalResponse(optimistic))
+}
+
+func (s *DAOServer) handleCreateOptimisticProposal(c echo.Context) error {
+	var req struct {
+		Title        string           `json:"title"`
+		Description  string           `json:"description"`
+		ProposalType dao.ProposalType `json:"proposal_type"`
+		VotingType   dao.VotingType   `json:"voting_type"`
+		Threshold    uint64           `json:"threshold"`
+		MetadataHash string           `json:"metadata_hash"`
+		VotingPeriod int64            `json:"voting_period"` // Length of the vote if challenged
+		PrivateKey   string           `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Parse metadata hash
+	var metadataHash types.Hash
+	if req.MetadataHash != "" {
+		metadataBytes, err := hex.DecodeString(req.MetadataHash)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid metadata hash format")
+		}
+		metadataHash = types.HashFromBytes(metadataBytes)
+	}
+
+	proposalTx := &dao.OptimisticProposalTx{
+		Fee:          s.Config.DAO.Fees.Proposal,
+		Title:        req.Title,
+		Description:  req.Description,
+		ProposalType: req.ProposalType,
+		VotingType:   req.VotingType,
+		Threshold:    req.Threshold,
+		MetadataHash: metadataHash,
+		VotingPeriod: req.VotingPeriod,
+	}
+
+	return s.submitDAOTxWithEvent(c, proposalTx, privKey, "optimistic proposal submitted", EventOptimisticCreated, map[string]interface{}{
+		"title": req.Title,
+		"bond":  s.dao.GetParameterConfig().OptimisticBond,
+	})
+}
+
+func (s *DAOServer) handleChallengeOptimisticProposal(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	var req struct {
+		Reason     string `json:"reason"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	challengeTx := &dao.OptimisticChallengeTx{
+		Fee:        s.Config.DAO.Fees.Vote,
+		ProposalID: id,
+		Reason:     req.Reason,
+	}
+
+	return s.submitDAOTxWithEvent(c, challengeTx, privKey, "challenge submitted", EventOptimisticChallenged, map[string]interface{}{
+		"proposal_id": id.String(),
+		"reason":      req.Reason,
+	})
+}
+
+// Retroactive public goods funding endpoints
+func (s *DAOServer) rpgfRoundResponse(round *dao.RPGFRound, now int64) RPGFRoundResponse {
+	badgeHolders := make([]string, len(round.BadgeHolders))
+	voted := make([]string, 0, len(round.Ballots))
+	for i, holder := range round.BadgeHolders {
+		badgeHolders[i] = holder.String()
+		if _, exists := round.Ballots[badgeHolders[i]]; exists {
+			voted = append(voted, badgeHolders[i])
+		}
+	}
+
+	nominations := make([]RPGFNominationResponse, len(round.Nominations))
+	for i, nomination := range round.Nominations {
+		nominations[i] = RPGFNominationResponse{
+			Index:       i,
+			Name:        nomination.Name,
+			Description: nomination.Description,
+			Recipient:   nomination.Recipient.String(),
+			Nominator:   nomination.Nominator.String(),
+			NominatedAt: nomination.NominatedAt,
+			Allocation:  nomination.Allocation,
+			Claimed:     nomination.Claimed,
+			Claimable:   round.Vested(nomination, now) - nomination.Claimed,
+		}
+	}
+
+	return RPGFRoundResponse{
+		ID:               round.ID.String(),
+		Title:            round.Title,
+		Description:      round.Description,
+		Budget:           round.Budget,
+		BadgeHolders:     badgeHolders,
+		Voted:            voted,
+		NominationPeriod: round.NominationPeriod,
+		VotingPeriod:     round.VotingPeriod,
+		StreamDuration:   round.StreamDuration,
+		Proposer:         round.Proposer.String(),
+		Status:           string(s.dao.RPGFManager.StatusOf(round, now)),
+		Nominations:      nominations,
+		CreatedAt:        round.CreatedAt,
+		OpenedAt:         round.OpenedAt,
+		NominationsClose: round.NominationsClose,
+		VotingCloses:     round.VotingCloses,
+		StreamStart:      round.StreamStart,
+		StreamEnd:        round.StreamEnd,
+		Allocated:        round.Allocated,
+		Returned:         round.Returned,
+	}
+}
+
+func (s *DAOServer) handleGetRPGFRounds(c echo.Context) error {
+	now := time.Now().Unix()
+	rounds := s.dao.ListRPGFRounds(dao.RPGFRoundStatus(c.QueryParam("status")))
+	response := make([]RPGFRoundResponse, len(rounds))
+	for i, round := range rounds {
+		response[i] = s.rpgfRoundResponse(round, now)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetRPGFRound(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	round, exists := s.dao.GetRPGFRound(id)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrRPGFRoundNotFoundError)
+	}
+
+	return c.JSON(http.StatusOK, s.rpgfRoundResponse(round, time.Now().Unix()))
+}
+
+func (s *DAOServer) handleProposeRPGFRound(c echo.Context) error {
+	var req struct {
+		Title            string         `json:"title"`
+		Description      string         `json:"description"`
+		Budget           uint64         `json:"budget"`
+		BadgeHolders     []string       `json:"badge_holders"`
+		NominationPeriod int64          `json:"nomination_period"`
+		VotingPeriod     int64          `json:"voting_period"`
+		StreamDuration   int64          `json:"stream_duration"`
+		VotingType       dao.VotingType `json:"voting_type"`
+		StartTime        int64          `json:"start_time"`
+		EndTime          int64          `json:"end_time"`
+		Threshold        uint64         `json:"threshold"`
+		PrivateKey       string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	badgeHolders := make([]crypto.PublicKey, len(req.BadgeHolders))
+	for i, holder := range req.BadgeHolders {
+		key, err := publicKeyFromHex(holder)
+		if err != nil || len(key) == 0 {
+			return fieldErrorResponse(c, "invalid badge holder", []FieldError{{
+				Field:   fmt.Sprintf("badge_holders[%d]", i),
+				Message: "invalid address",
+			}})
+		}
+		badgeHolders[i] = key
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.RPGFRoundProposalTx{
+		Fee:              s.Config.DAO.Fees.Proposal,
+		Title:            req.Title,
+		Description:      req.Description,
+		Budget:           req.Budget,
+		BadgeHolders:     badgeHolders,
+		NominationPeriod: req.NominationPeriod,
+		VotingPeriod:     req.VotingPeriod,
+		StreamDuration:   req.StreamDuration,
+		VotingType:       req.VotingType,
+		StartTime:        req.StartTime,
+		EndTime:          req.EndTime,
+		Threshold:        req.Threshold,
+	}
+
+	return s.submitDAOTxWithEvent(c, proposalTx, privKey, "retroactive funding round proposed", EventRPGFRoundProposed, map[string]interface{}{
+		"title":  req.Title,
+		"budget": req.Budget,
+	})
+}
+
+func (s *DAOServer) handleOpenRPGFRound(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	openTx := &dao.RPGFRoundOpenTx{Fee: s.Config.DAO.Fees.Treasury, ProposalID: id}
+
+	return s.submitDAOTxWithEvent(c, openTx, privKey, "retroactive funding round opening submitted", EventRPGFRoundOpened, map[string]interface{}{
+		"round_id": id.String(),
+	})
+}
+
+func (s *DAOServer) handleNominateRPGF(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Recipient   string `json:"recipient"`
+		PrivateKey  string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	recipient, err := publicKeyFromHex(req.Recipient)
+	if err != nil || len(recipient) == 0 {
+		return fieldErrorResponse(c, "invalid nomination", []FieldError{{Field: "recipient", Message: "invalid recipient address"}})
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	nominateTx := &dao.RPGFNominateTx{
+		Fee:         s.Config.DAO.Fees.Default,
+		RoundID:     id,
+		Name:        req.Name,
+		Description: req.Description,
+		Recipient:   recipient,
+	}
+
+	return s.submitDAOTxWithEvent(c, nominateTx, privKey, "nomination submitted", EventRPGFNominated, map[string]interface{}{
+		"round_id":  id.String(),
+		"name":      req.Name,
+		"recipient": req.Recipient,
+	})
+}
+
+func (s *DAOServer) handleCastRPGFBallot(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		Allocations []struct {
+			Nomination uint16 `json:"nomination"`
+			Amount     uint64 `json:"amount"`
+		} `json:"allocations"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	allocations := make([]dao.RPGFAllocation, len(req.Allocations))
+	for i, allocation := range req.Allocations {
+		allocations[i] = dao.RPGFAllocation{Nomination: allocation.Nomination, Amount: allocation.Amount}
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	ballotTx := &dao.RPGFBallotTx{
+		Fee:         s.Config.DAO.Fees.Vote,
+		RoundID:     id,
+		Allocations: allocations,
+	}
+
+	// The event names the voter but not the ballot, which stays private
+	return s.submitDAOTxWithEvent(c, ballotTx, privKey, "ballot submitted", EventRPGFBallotCast, map[string]interface{}{
+		"round_id": id.String(),
+		"voter":    privKey.PublicKey().String(),
+	})
+}
+
+func (s *DAOServer) handleFinalizeRPGFRound(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	finalizeTx := &dao.RPGFFinalizeTx{Fee: s.Config.DAO.Fees.Treasury, RoundID: id}
+
+	return s.submitDAOTxWithEvent(c, finalizeTx, privKey, "retroactive funding round finalization submitted", EventRPGFRoundFinalized, map[string]interface{}{
+		"round_id": id.String(),
+	})
+}
+
+func (s *DAOServer) handleClaimRPGF(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid round ID format")
+	}
+
+	var req struct {
+		Nomination uint16 `json:"nomination"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	claimTx := &dao.RPGFClaimTx{Fee: s.Config.DAO.Fees.Default, RoundID: id, Nomination: req.Nomination}
+
+	return s.submitDAOTxWithEvent(c, claimTx, privKey, "claim submitted", EventRPGFClaimed, map[string]interface{}{
+		"round_id":   id.String(),
+		"nomination": req.Nomination,
+	})
+}
+
+// Oracle endpoints
+func oracleFeedResponse(feed *dao.OracleFeed, now int64) OracleFeedResponse {
+	oracles := make([]string, len(feed.Oracles))
+	reports := make([]OracleReportResponse, 0, len(feed.Reports))
+	for i, oracle := range feed.Oracles {
+		oracles[i] = oracle.String()
+		if report, exists := feed.Reports[oracles[i]]; exists {
+			reports = append(reports, OracleReportResponse{
+				Oracle:     oracles[i],
+				Value:      report.Value,
+				ObservedAt: report.ObservedAt,
+				ReportedAt: report.ReportedAt,
+				Fresh:      now-report.ObservedAt <= feed.MaxAge,
+			})
+		}
+	}
+
+	response := OracleFeedResponse{
+		ID:          feed.ID,
+		Description: feed.Description,
+		Decimals:    feed.Decimals,
+		Oracles:     oracles,
+		MinReports:  feed.MinReports,
+		MaxAge:      feed.MaxAge,
+		ProposalID:  feed.ProposalID.String(),
+		ActivatedAt: feed.ActivatedAt,
+		Reports:     reports,
+	}
+	value, fresh, err := feed.Value(now)
+	response.FreshReports = fresh
+	response.Stale = err != nil
+	if err == nil {
+		response.Value = &value
+	}
+	return response
+}
+
+func (s *DAOServer) handleGetOracleFeeds(c echo.Context) error {
+	now := time.Now().Unix()
+	feeds := s.dao.ListOracleFeeds()
+	response := make([]OracleFeedResponse, len(feeds))
+	for i, feed := range feeds {
+		response[i] = oracleFeedResponse(feed, now)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetOracleFeed(c echo.Context) error {
+	feed, exists := s.dao.GetOracleFeed(c.Param("id"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrOracleFeedNotFoundError)
+	}
+
+	return c.JSON(http.StatusOK, oracleFeedResponse(feed, time.Now().Unix()))
+}
+
+func (s *DAOServer) handleProposeOracleFeed(c echo.Context) error {
+	var req struct {
+		Title       string         `json:"title"`
+		Description string         `json:"description"`
+		FeedID      string         `json:"feed_id"`
+		Decimals    uint8          `json:"decimals"`
+		Oracles     []string       `json:"oracles"`
+		MinReports  uint16         `json:"min_reports"`
+		MaxAge      int64          `json:"max_age"`
+		VotingType  dao.VotingType `json:"voting_type"`
+		StartTime   int64          `json:"start_time"`
+		EndTime     int64          `json:"end_time"`
+		Threshold   uint64         `json:"threshold"`
+		PrivateKey  string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	oracles := make([]crypto.PublicKey, len(req.Oracles))
+	for i, oracle := range req.Oracles {
+		key, err := publicKeyFromHex(oracle)
+		if err != nil || len(key) == 0 {
+			return fieldErrorResponse(c, "invalid oracle", []FieldError{{
+				Field:   fmt.Sprintf("oracles[%d]", i),
+				Message: "invalid address",
+			}})
+		}
+		oracles[i] = key
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.OracleFeedProposalTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Title:       req.Title,
+		Description: req.Description,
+		FeedID:      req.FeedID,
+		Decimals:    req.Decimals,
+		Oracles:     oracles,
+		MinReports:  req.MinReports,
+		MaxAge:      req.MaxAge,
+		VotingType:  req.VotingType,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Threshold:   req.Threshold,
+	}
+
+	return s.submitDAOTxWithEvent(c, proposalTx, privKey, "oracle feed proposed", EventOracleFeedProposed, map[string]interface{}{
+		"feed_id": req.FeedID,
+		"oracles": len(oracles),
+	})
+}
+
+func (s *DAOServer) handleActivateOracleFeed(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	activateTx := &dao.OracleFeedActivateTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTxWithEvent(c, activateTx, privKey, "oracle feed activation submitted", EventOracleFeedActivated, map[string]interface{}{
+		"proposal_id": proposalID.String(),
+	})
+}
+
+func proposalTrackResponse(track *dao.ProposalTrack, queue []types.Hash) ProposalTrackResponse {
+	queued := make([]string, len(queue))
+	for i, proposalID := range queue {
+		queued[i] = proposalID.String()
+	}
+
+	return ProposalTrackResponse{
+		ID:          track.ID,
+		Name:        track.Name,
+		VotingType:  track.VotingType,
+		Quorum:      track.Quorum,
+		Threshold:   track.Threshold,
+		Timelock:    track.Timelock,
+		Deposit:     track.Deposit,
+		Actions:     track.Actions,
+		MaxActive:   track.MaxActive,
+		Queue:       queued,
+		ProposalID:  track.ProposalID.String(),
+		ActivatedAt: track.ActivatedAt,
+	}
+}
+
+func (s *DAOServer) handleGetProposalTracks(c echo.Context) error {
+	tracks := s.dao.ListProposalTracks()
+	response := make([]ProposalTrackResponse, len(tracks))
+	for i, track := range tracks {
+		response[i] = proposalTrackResponse(track, s.dao.GetTrackQueue(track.ID))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetProposalTrack(c echo.Context) error {
+	track, exists := s.dao.GetProposalTrack(c.Param("id"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrTrackNotFoundError)
+	}
+
+	return c.JSON(http.StatusOK, proposalTrackResponse(track, s.dao.GetTrackQueue(track.ID)))
+}
+
+// handleProposeTrack proposes a proposal track, or its removal, on a
+// parameter proposal
+func (s *DAOServer) handleProposeTrack(c echo.Context) error {
+	var req struct {
+		Title       string               `json:"title"`
+		Description string               `json:"description"`
+		Track       ProposalTrackRequest `json:"track"`
+		Remove      bool                 `json:"remove"`
+		VotingType  dao.VotingType       `json:"voting_type"`
+		StartTime   int64                `json:"start_time"`
+		EndTime     int64                `json:"end_time"`
+		Threshold   uint64               `json:"threshold"`
+		PrivateKey  string               `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	proposalTx := &dao.TrackProposalTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Title:       req.Title,
+		Description: req.Description,
+		Track: dao.ProposalTrack{
+			ID:         req.Track.ID,
+			Name:       req.Track.Name,
+			VotingType: req.Track.VotingType,
+			Quorum:     req.Track.Quorum,
+			Threshold:  req.Track.Threshold,
+			Timelock:   req.Track.Timelock,
+			Deposit:    req.Track.Deposit,
+			Actions:    req.Track.Actions,
+			MaxActive:  req.Track.MaxActive,
+		},
+		Remove:     req.Remove,
+		VotingType: req.VotingType,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Threshold:  req.Threshold,
+	}
+
+	return s.submitDAOTxWithEvent(c, proposalTx, privKey, "proposal track proposed", EventTrackProposed, map[string]interface{}{
+		"track":  req.Track.ID,
+		"remove": req.Remove,
+	})
+}
+
+func (s *DAOServer) handleActivateTrack(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	activateTx := &dao.TrackActivateTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTxWithEvent(c, activateTx, privKey, "proposal track activation submitted", EventTrackActivated, map[string]interface{}{
+		"proposal_id": proposalID.String(),
+	})
+}
+
+// handleReportOracle submits a data point. The oracle key signs the data
+// point and defaults to the submitting key.
+func (s *DAOServer) handleReportOracle(c echo.Context) error {
+	var req struct {
+		Value            int64  `json:"value"`
+		ObservedAt       int64  `json:"observed_at"` // Defaults to now
+		OraclePrivateKey string `json:"oracle_private_key"`
+		PrivateKey       string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+	oracleKey := privKey
+	if req.OraclePrivateKey != "" {
+		if oracleKey, err = privateKeyFromHex(req.OraclePrivateKey); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid oracle private key format")
+		}
+	}
+
+	observedAt := req.ObservedAt
+	if observedAt == 0 {
+		observedAt = time.Now().Unix()
+	}
+
+	reportTx := &dao.OracleReportTx{
+		Fee:        s.Config.DAO.Fees.Default,
+		FeedID:     c.Param("id"),
+		Value:      req.Value,
+		ObservedAt: observedAt,
+	}
+	if err := reportTx.Sign(oracleKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign report")
+	}
+
+	return s.submitDAOTxWithEvent(c, reportTx, privKey, "oracle report submitted", EventOracleReported, map[string]interface{}{
+		"feed_id":     reportTx.FeedID,
+		"oracle":      reportTx.Oracle.String(),
+		"value":       req.Value,
+		"observed_at": observedAt,
+	})
+}
+
+// Metadata schema endpoints
+func (s *DAOServer) handleGetMetadataSchemas(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"current":  dao.CurrentMetadataSchemaVersion,
+		"versions": dao.MetadataSchemaVersions(),
+	})
+}
+
+func (s *DAOServer) handleGetMetadataSchema(c echo.Context) error {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid schema version")
+	}
+
+	schema, exists := dao.MetadataSchema(version)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "metadata schema not found")
+	}
+
+	return c.JSON(http.StatusOK, schema)
+}
+
+// Notification endpoints
+
+// memberRequestMaxAge bounds the clock skew of signed member requests
+const memberRequestMaxAge = 5 * time.Minute
+
+// memberRequestDigest is what a member signs to authorize action on their
+// own off-chain data: sha256("<action>:<address>:<timestamp>:<payload>")
+func memberRequestDigest(action, address string, timestamp int64, payload []byte) []byte {
+	digest := sha256.Sum256(append([]byte(fmt.Sprintf("%s:%s:%d:", action, address, timestamp)), payload...))
+	return digest[:]
+}
+
+// verifyMemberRequest checks that a request was signed by the key of
+// address within memberRequestMaxAge. Signatures are hex encoded 32 byte r
+// and s values.
+func verifyMemberRequest(action, address string, timestamp int64, payload []byte, signatureHex string) (crypto.PublicKey, error) {
+	member, err := publicKeyFromHex(address)
+	if err != nil {
+		return nil, err
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > memberRequestMaxAge || age < -memberRequestMaxAge {
+		return nil, fmt.Errorf("request timestamp is too far from the current time")
+	}
+
+	sigBytes, err := hex.DecodeString(signatureHex)
+	if err != nil || len(sigBytes) != 64 {
+		return nil, fmt.Errorf("signature must be 64 hex encoded bytes")
+	}
+	signature := crypto.Signature{
+		R: new(big.Int).SetBytes(sigBytes[:32]),
+		S: new(big.Int).SetBytes(sigBytes[32:]),
+	}
+
+	if !signature.Verify(member, memberRequestDigest(action, address, timestamp, payload)) {
+		return nil, fmt.Errorf("invalid request signature")
+	}
+	return member, nil
+}
+
+// memberQueryRequest verifies a signed request carrying its timestamp and
+// signature in the query string. The address comes from the path, or the
+// query string for routes without one.
+func memberQueryRequest(c echo.Context, action string, payload []byte) (crypto.PublicKey, error) {
+	timestamp, err := strconv.ParseInt(c.QueryParam("timestamp"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp")
+	}
+	address := c.Param("address")
+	if address == "" {
+		address = c.QueryParam("address")
+	}
+	return verifyMemberRequest(action, address, timestamp, payload, c.QueryParam("signature"))
+}
+
+func (s *DAOServer) handleGetNotificationPreferences(c echo.Context) error {
+	member, err := memberQueryRequest(c, "get_notification_preferences", nil)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	preferences, exists := s.dao.GetNotificationPreferences(member)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "no notification preferences")
+	}
+
+	return c.JSON(http.StatusOK, preferences)
+}
+
+func (s *DAOServer) handleSetNotificationPreferences(c echo.Context) error {
+	var req struct {
+		Address     string          `json:"address"`
+		Preferences json.RawMessage `json:"preferences"`
+		Timestamp   int64           `json:"timestamp"`
+		Signature   string          `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// The preferences are signed exactly as sent
+	member, err := verifyMemberRequest("set_notification_preferences", req.Address, req.Timestamp, req.Preferences, req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	var preferences dao.NotificationPreferences
+	if err := json.Unmarshal(req.Preferences, &preferences); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid preferences format")
+	}
+
+	if err := s.dao.SetNotificationPreferences(member, &preferences); err != nil {
+		if daoErr, ok := err.(*dao.DAOError); ok && daoErr.Code == dao.ErrUnauthorized {
+			return errorResponse(c, http.StatusForbidden, daoErr)
+		}
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	stored, _ := s.dao.GetNotificationPreferences(member)
+	return c.JSON(http.StatusOK, stored)
+}
+
+func (s *DAOServer) handleDeleteNotificationPreferences(c echo.Context) error {
+	member, err := memberQueryRequest(c, "delete_notification_preferences", nil)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	s.dao.RemoveNotificationPreferences(member)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Webhook endpoints, integrators are registered by the node operator
+func (s *DAOServer) handleGetWebhooks(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, s.dao.Webhooks.ListSubscriptions())
+}
+
+func (s *DAOServer) handleCreateWebhook(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	var req struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	subscription, err := s.dao.Webhooks.Subscribe(req.URL, req.Events)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	return c.JSON(http.StatusCreated, subscription)
+}
+
+func (s *DAOServer) handleGetWebhook(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	subscription, exists := s.dao.Webhooks.GetSubscription(c.Param("id"))
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "webhook subscription not found")
+	}
+
+	return c.JSON(http.StatusOK, subscription)
+}
+
+func (s *DAOServer) handleDeleteWebhook(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	if err := s.dao.Webhooks.Unsubscribe(c.Param("id")); err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *DAOServer) handleGetWebhookDeliveries(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	deliveries, err := s.dao.Webhooks.GetDeliveries(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+func (s *DAOServer) handleTestWebhook(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	delivery, err := s.dao.Webhooks.TestFire(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// Comment endpoints
+
+func (s *DAOServer) newCommentResponse(comment *dao.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:           comment.ID.String(),
+		ProposalID:   comment.ProposalID.String(),
+		Author:       comment.Author.String(),
+		BodyHash:     comment.BodyHash.String(),
+		CreatedAt:    comment.CreatedAt,
+		Hidden:       comment.Hidden,
+		HiddenReason: comment.HiddenReason,
+		Reactions:    comment.Reactions,
+	}
+	if !comment.ParentID.IsZero() {
+		response.ParentID = comment.ParentID.String()
+	}
+
+	// Bodies that cannot be fetched leave the client the hash to retry with
+	if !comment.Hidden {
+		if body, err := s.dao.IPFSClient.RetrieveComment(comment.BodyHash); err == nil {
+			response.Body = body
+		}
+	}
+
+	return response
+}
+
+func (s *DAOServer) newCommentThreadResponse(thread *dao.CommentThread) CommentResponse {
+	response := s.newCommentResponse(thread.Comment)
+	for _, reply := range thread.Replies {
+		response.Replies = append(response.Replies, s.newCommentThreadResponse(reply))
+	}
+	return response
+}
+
+func (s *DAOServer) handleGetProposalComments(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	threads, total := s.dao.GetProposalComments(proposalID, (page-1)*limit, limit)
+
+	response := make([]CommentResponse, len(threads))
+	for i, thread := range threads {
+		response[i] = s.newCommentThreadResponse(thread)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"comments": response,
+		"page":     page,
+		"limit":    limit,
+		"total":    total,
+	})
+}
+
+func (s *DAOServer) handlePostComment(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	var req struct {
+		Body       string `json:"body"`
+		ParentID   string `json:"parent_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	if strings.TrimSpace(req.Body) == "" || len(req.Body) > dao.MaxCommentLength {
+		return errorMessage(c, http.StatusBadRequest, fmt.Sprintf("comment body must be 1 to %d bytes", dao.MaxCommentLength))
+	}
+
+	var parentID types.Hash
+	if req.ParentID != "" {
+		if parentID, err = hashFromHex(req.ParentID); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid parent ID format")
+		}
+	}
+
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	bodyHash, err := s.dao.IPFSClient.UploadComment(req.Body)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	commentTx := &dao.CommentTx{
+		Fee:        s.Config.DAO.Fees.Default,
+		ProposalID: proposalID,
+		ParentID:   parentID,
+		BodyHash:   bodyHash,
+	}
+
+	data := map[string]interface{}{
+		"proposal_id": proposalID.String(),
+		"body_hash":   bodyHash.String(),
+	}
+	if !parentID.IsZero() {
+		data["parent_id"] = parentID.String()
+	}
+
+	return s.submitDAOTxWithEvent(c, commentTx, privKey, "comment submitted", EventCommentPosted, data)
+}
+
+// handleReactToComment adds or removes a member's reaction, signed over
+// "<comment id>:<reaction>:<add|remove>"
+func (s *DAOServer) handleReactToComment(c echo.Context) error {
+	commentID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid comment ID format")
+	}
+
+	var req struct {
+		Address   string `json:"address"`
+		Reaction  string `json:"reaction"`
+		Remove    bool   `json:"remove"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	action := "add"
+	if req.Remove {
+		action = "remove"
+	}
+	payload := fmt.Sprintf("%s:%s:%s", commentID.String(), req.Reaction, action)
+	member, err := verifyMemberRequest("react_comment", req.Address, req.Timestamp, []byte(payload), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	comment, err := s.dao.ReactToComment(commentID, member, req.Reaction, !req.Remove)
+	if err != nil {
+		return errorResponse(c, commentErrorStatus(err), err)
+	}
+
+	s.broadcastEvent(Event{
+		Type: EventCommentReacted,
+		Data: map[string]interface{}{
+			"comment_id":  commentID.String(),
+			"proposal_id": comment.ProposalID.String(),
+			"member":      member.String(),
+			"reaction":    req.Reaction,
+			"removed":     req.Remove,
+			"reactions":   comment.Reactions,
+		},
+		Timestamp: time.Now().Unix(),
+	})
+
+	return c.JSON(http.StatusOK, s.newCommentResponse(comment))
+}
+
+// handleModerateComment hides or shows a comment for a moderator, signed
+// over "<comment id>:<hidden>:<reason>"
+func (s *DAOServer) handleModerateComment(c echo.Context) error {
+	commentID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid comment ID format")
+	}
+
+	var req struct {
+		Address   string `json:"address"`
+		Hidden    bool   `json:"hidden"`
+		Reason    string `json:"reason"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	payload := fmt.Sprintf("%s:%t:%s", commentID.String(), req.Hidden, req.Reason)
+	moderator, err := verifyMemberRequest("moderate_comment", req.Address, req.Timestamp, []byte(payload), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	comment, err := s.dao.ModerateComment(commentID, moderator, req.Hidden, req.Reason)
+	if err != nil {
+		return errorResponse(c, commentErrorStatus(err), err)
+	}
+
+	s.broadcastEvent(Event{
+		Type: EventCommentModerated,
+		Data: map[string]interface{}{
+			"comment_id":  commentID.String(),
+			"proposal_id": comment.ProposalID.String(),
+			"moderator":   moderator.String(),
+			"hidden":      req.Hidden,
+		},
+		Timestamp: time.Now().Unix(),
+	})
+
+	return c.JSON(http.StatusOK, s.newCommentResponse(comment))
+}
+
+// commentErrorStatus maps comment errors to HTTP statuses
+func commentErrorStatus(err error) int {
+	if daoErr, ok := err.(*dao.DAOError); ok {
+		switch daoErr.Code {
+		case dao.ErrCommentNotFound:
+			return http.StatusNotFound
+		case dao.ErrUnauthorized:
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusBadRequest
+}
+
+func (s *DAOServer) handleGetProposalTemplates(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"templates": s.dao.ProposalTemplates.List(),
+	})
+}
+
+// handleValidateProposal pre-checks a proposal draft so clients can fix it
+// before signing
+func (s *DAOServer) handleValidateProposal(c echo.Context) error {
+	var req struct {
+		dao.ProposalDraft
+		Creator string `json:"creator"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	creator, err := publicKeyFromHex(req.Creator)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid creator address")
+	}
+
+	fee := s.Config.DAO.Fees.Proposal
+	tx, issues := s.dao.ValidateProposalDraft(&req.ProposalDraft, creator, fee, time.Now().Unix())
+
+	response := ProposalDraftResponse{
+		Valid:  len(issues) == 0,
+		Issues: issues,
+	}
+	if response.Issues == nil {
+		response.Issues = []dao.DraftIssue{}
+	}
+	if tx != nil {
+		response.Proposal = &ProposalPreview{
+			Title:        tx.Title,
+			Description:  tx.Description,
+			ProposalType: tx.ProposalType,
+			VotingType:   tx.VotingType,
+			StartTime:    tx.StartTime,
+			EndTime:      tx.EndTime,
+			Threshold:    tx.Threshold,
+			Fee:          tx.Fee,
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// draftIssuesString joins draft issues into one error message
+func draftIssuesString(issues []dao.DraftIssue) string {
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		if issue.Field != "" {
+			messages[i] = issue.Field + ": " + issue.Message
+		} else {
+			messages[i] = issue.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// draftFieldErrors returns draft issues as field errors
+func draftFieldErrors(issues []dao.DraftIssue) []FieldError {
+	fields := make([]FieldError, len(issues))
+	for i, issue := range issues {
+		fields[i] = FieldError{Field: issue.Field, Message: issue.Message}
+	}
+	return fields
+}
+
+func newDraftResponse(draft *dao.SavedDraft) DraftResponse {
+	response := DraftResponse{
+		ID:          draft.ID.String(),
+		Owner:       draft.Owner.String(),
+		CoAuthors:   make([]string, len(draft.CoAuthors)),
+		Content:     draft.Content,
+		Version:     draft.Version,
+		CreatedAt:   draft.CreatedAt,
+		UpdatedAt:   draft.UpdatedAt,
+		UpdatedBy:   draft.UpdatedBy.String(),
+		SubmittedAt: draft.SubmittedAt,
+	}
+	for i, coAuthor := range draft.CoAuthors {
+		response.CoAuthors[i] = coAuthor.String()
+	}
+	if draft.SubmittedAt != 0 {
+		response.SubmittedTx = draft.SubmittedTx.String()
+		response.ContentHash = draft.ContentHash.String()
+	}
+	return response
+}
+
+func draftErrorStatus(err error) int {
+	if daoErr, ok := err.(*dao.DAOError); ok {
+		switch daoErr.Code {
+		case dao.ErrDraftNotFound:
+			return http.StatusNotFound
+		case dao.ErrDraftConflict:
+			return http.StatusConflict
+		case dao.ErrUnauthorized:
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusBadRequest
+}
+
+func (s *DAOServer) handleGetDrafts(c echo.Context) error {
+	member, err := memberQueryRequest(c, "list_drafts", nil)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	drafts := s.dao.Drafts.List(member)
+	responses := make([]DraftResponse, len(drafts))
+	for i, draft := range drafts {
+		responses[i] = newDraftResponse(draft)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"drafts": responses,
+	})
+}
+
+// handleGetDraft returns a draft to one of its authors, signed over the
+// draft ID
+func (s *DAOServer) handleGetDraft(c echo.Context) error {
+	draftID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft ID format")
+	}
+
+	member, err := memberQueryRequest(c, "get_draft", []byte(draftID.String()))
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	draft, err := s.dao.Drafts.Get(draftID, member)
+	if err != nil {
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, newDraftResponse(draft))
+}
+
+// handleCreateDraft saves a new draft, signed over the draft exactly as sent
+func (s *DAOServer) handleCreateDraft(c echo.Context) error {
+	var req struct {
+		Address   string          `json:"address"`
+		Draft     json.RawMessage `json:"draft"`
+		Timestamp int64           `json:"timestamp"`
+		Signature string          `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	owner, err := verifyMemberRequest("create_draft", req.Address, req.Timestamp, req.Draft, req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	var content dao.ProposalDraft
+	if err := json.Unmarshal(req.Draft, &content); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft format")
+	}
+
+	draft, err := s.dao.Drafts.Create(owner, content)
+	if err != nil {
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, newDraftResponse(draft))
+}
+
+// handleUpdateDraft replaces the content of a draft, signed over
+// "<draft id>:<version>:<draft>". Edits must be based on the current version.
+func (s *DAOServer) handleUpdateDraft(c echo.Context) error {
+	draftID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft ID format")
+	}
+
+	var req struct {
+		Address   string          `json:"address"`
+		Draft     json.RawMessage `json:"draft"`
+		Version   uint64          `json:"version"`
+		Timestamp int64           `json:"timestamp"`
+		Signature string          `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	payload := append([]byte(fmt.Sprintf("%s:%d:", draftID.String(), req.Version)), req.Draft...)
+	editor, err := verifyMemberRequest("edit_draft", req.Address, req.Timestamp, payload, req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	var content dao.ProposalDraft
+	if err := json.Unmarshal(req.Draft, &content); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft format")
+	}
+
+	draft, err := s.dao.Drafts.Update(draftID, editor, content, req.Version)
+	if err != nil {
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, newDraftResponse(draft))
+}
+
+func (s *DAOServer) handleDeleteDraft(c echo.Context) error {
+	draftID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft ID format")
+	}
+
+	owner, err := memberQueryRequest(c, "delete_draft", []byte(draftID.String()))
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	if err := s.dao.Drafts.Delete(draftID, owner); err != nil {
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// handleManageDraftCoAuthor invites or removes a co-author, signed by the
+// owner over "<draft id>:<co-author>:<add|remove>"
+func (s *DAOServer) handleManageDraftCoAuthor(c echo.Context) error {
+	draftID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft ID format")
+	}
+
+	var req struct {
+		Address   string `json:"address"`
+		CoAuthor  string `json:"co_author"`
+		Remove    bool   `json:"remove"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	coAuthor, err := publicKeyFromHex(req.CoAuthor)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid co-author address")
+	}
+
+	operation := "add"
+	if req.Remove {
+		operation = "remove"
+	}
+	payload := fmt.Sprintf("%s:%s:%s", draftID.String(), req.CoAuthor, operation)
+	owner, err := verifyMemberRequest("manage_draft_coauthors", req.Address, req.Timestamp, []byte(payload), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	var draft *dao.SavedDraft
+	if req.Remove {
+		draft, err = s.dao.Drafts.RemoveCoAuthor(draftID, owner, coAuthor)
+	} else {
+		draft, err = s.dao.Drafts.AddCoAuthor(draftID, owner, coAuthor)
+	}
+	if err != nil {
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+
+	return c.JSON(http.StatusOK, newDraftResponse(draft))
+}
+
+// handleSubmitDraft promotes a draft to a proposal transaction. The owner
+// signs over the draft ID and the transaction is signed with private_key.
+// The hash of the final content is committed as the proposal metadata.
+func (s *DAOServer) handleSubmitDraft(c echo.Context) error {
+	draftID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid draft ID format")
+	}
+
+	var req struct {
+		Address    string `json:"address"`
+		Timestamp  int64  `json:"timestamp"`
+		Signature  string `json:"signature"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	owner, err := verifyMemberRequest("submit_draft", req.Address, req.Timestamp, []byte(draftID.String()), req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Check intake before the draft is marked submitted
+	if s.IntakePaused() {
+		return errorResponse(c, http.StatusServiceUnavailable, ErrIntakePaused)
+	}
+
+	draft, proposalTx, issues, err := s.dao.SubmitDraft(draftID, owner, s.Config.DAO.Fees.Proposal, time.Now().Unix())
+	if err != nil {
+		if _, ok := err.(*dao.DAOError); !ok {
+			return errorResponse(c, http.StatusInternalServerError, err)
+		}
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+	if len(issues) > 0 {
+		return fieldErrorResponse(c, "invalid draft: "+draftIssuesString(issues), draftFieldErrors(issues))
+	}
+
+	tx := s.newDAOTransaction(proposalTx)
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+	txHash := tx.Hash(core.TxHasher{})
+
+	// Record the submission first so a concurrent edit cannot slip in
+	// after the content was committed
+	if _, err := s.dao.Drafts.MarkSubmitted(draftID, owner, draft.Version, txHash, proposalTx.MetadataHash); err != nil {
+		return errorResponse(c, draftErrorStatus(err), err)
+	}
+
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+
+	s.broadcastEvent(Event{
+		Type: EventProposalCreated,
+		Data: map[string]interface{}{
+			"title":    proposalTx.Title,
+			"creator":  owner.String(),
+			"draft_id": draftID.String(),
+			"tx_hash":  txHash.String(),
+		},
+		Timestamp: time.Now().Unix(),
+	})
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash":      txHash.String(),
+		"content_hash": proposalTx.MetadataHash.String(),
+		"message":      "draft submitted",
+	})
+}
+
+// Calendar windows in seconds
+const (
+	calendarDefaultWindow int64 = 30 * 24 * 3600
+	calendarMaxWindow     int64 = 366 * 24 * 3600
+)
+
+// handleGetCalendar returns upcoming governance events. Vesting and staking
+// unlocks are included for the optional address.
+func (s *DAOServer) handleGetCalendar(c echo.Context) error {
+	now := time.Now().Unix()
+	from, to := now, now+calendarDefaultWindow
+
+	if value := c.QueryParam("from"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid from")
+		}
+		from = parsed
+		to = from + calendarDefaultWindow
+	}
+	if value := c.QueryParam("to"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid to")
+		}
+		to = parsed
+	}
+	if to < from || to-from > calendarMaxWindow {
+		return errorMessage(c, http.StatusBadRequest, "calendar window must be between 0 and 366 days")
+	}
+
+	var member crypto.PublicKey
+	if address := c.QueryParam("address"); address != "" {
+		var err error
+		if member, err = publicKeyFromHex(address); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid address")
+		}
+	}
+
+	events := s.dao.GovernanceCalendar(from, to, member)
+	if events == nil {
+		events = []dao.CalendarEvent{}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"from":   from,
+		"to":     to,
+		"events": events,
+	})
+}
+
+// newMembershipApplicationResponse converts a membership application into
+// its API representation
+func (s *DAOServer) newMembershipApplicationResponse(application *dao.MembershipApplication) *MembershipApplicationResponse {
+	response := &MembershipApplicationResponse{
+		Applicant:         application.Applicant.String(),
+		Statement:         application.Statement,
+		Status:            string(application.Status),
+		Approvals:         make([]string, len(application.Approvals)),
+		ApprovalsRequired: s.dao.ParameterManager.GetParameterConfig().MembershipApprovals,
+		AutoApproved:      application.AutoApproved,
+		CreatedAt:         application.CreatedAt,
+		DecidedAt:         application.DecidedAt,
+	}
+	if !application.KYCAttestation.IsZero() {
+		response.KYCAttestation = application.KYCAttestation.String()
+	}
+	for i, approver := range application.Approvals {
+		response.Approvals[i] = approver.String()
+	}
+	return response
+}
+
+func (s *DAOServer) handleApplyForMembership(c echo.Context) error {
+	var req struct {
+		Statement      string `json:"statement"`
+		KYCAttestation string `json:"kyc_attestation"`
+		PrivateKey     string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	var attestation types.Hash
+	if req.KYCAttestation != "" {
+		var err error
+		if attestation, err = hashFromHex(req.KYCAttestation); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid KYC attestation format")
+		}
+	}
+	if attestation.IsZero() && s.dao.ParameterManager.GetParameterConfig().MembershipRequireKYC {
+		return errorMessage(c, http.StatusBadRequest, "membership requires a KYC attestation")
+	}
+
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Applicants may not hold tokens yet
+	joinTx := &dao.JoinRequestTx{
+		Statement:      req.Statement,
+		KYCAttestation: attestation,
+	}
+
+	data := map[string]interface{}{
+		"applicant": privKey.PublicKey().String(),
+	}
+
+	return s.submitDAOTxWithEvent(c, joinTx, privKey, "membership application submitted", EventMembershipApplied, data)
+}
+
+func (s *DAOServer) handleApproveMembership(c echo.Context) error {
+	var req struct {
+		Applicant  string `json:"applicant"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	applicant, err := publicKeyFromHex(req.Applicant)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid applicant address")
+	}
+
+	application, exists := s.dao.GetMembershipApplication(applicant)
+	if !exists || application.Status != dao.ApplicationStatusPending {
+		return errorMessage(c, http.StatusNotFound, "no pending application for applicant")
+	}
+
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	approvalTx := &dao.JoinApprovalTx{
+		Fee:       s.Config.DAO.Fees.Default,
+		Applicant: applicant,
+	}
+
+	data := map[string]interface{}{
+		"applicant": applicant.String(),
+	}
+
+	return s.submitDAOTxWithEvent(c, approvalTx, privKey, "membership approval submitted", EventMembershipApproved, data)
+}
+
+// handleChangeMembershipStatus exits the sender, or votes to suspend or
+// reinstate another member
+func (s *DAOServer) handleChangeMembershipStatus(c echo.Context) error {
+	var req struct {
+		Member     string `json:"member"`
+		Status     string `json:"status"`
+		Reason     string `json:"reason"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	member, err := publicKeyFromHex(req.Member)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid member address")
+	}
+
+	status, ok := dao.ParseMembershipStatus(req.Status)
+	if !ok {
+		return errorMessage(c, http.StatusBadRequest, "status must be active, suspended or exited")
+	}
+
+	if len(req.Reason) > 500 {
+		return errorMessage(c, http.StatusBadRequest, "reason cannot exceed 500 characters")
+	}
+
+	if _, isMember := s.dao.GetMembershipStatus(member); !isMember {
+		return errorMessage(c, http.StatusNotFound, "member not found")
+	}
+
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	statusTx := &dao.MembershipStatusTx{
+		Fee:    s.Config.DAO.Fees.Default,
+		Member: member,
+		Status: status,
+		Reason: req.Reason,
+	}
+
+	data := map[string]interface{}{
+		"member": member.String(),
+		"status": status.String(),
+	}
+	if req.Reason != "" {
+		data["reason"] = req.Reason
+	}
+
+	return s.submitDAOTxWithEvent(c, statusTx, privKey, "membership status change submitted", EventMembershipStatus, data)
+}
+
+func (s *DAOServer) handleGetMembershipApplications(c echo.Context) error {
+	status := dao.ApplicationStatus(c.QueryParam("status"))
+	switch status {
+	case "", dao.ApplicationStatusPending, dao.ApplicationStatusApproved:
+	default:
+		return errorMessage(c, http.StatusBadRequest, "status must be pending or approved")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	applications := s.dao.ListMembershipApplications(status)
+
+	start := (page - 1) * limit
+	if start > len(applications) {
+		start = len(applications)
+	}
+	end := start + limit
+	if end > len(applications) {
+		end = len(applications)
+	}
+
+	response := make([]*MembershipApplicationResponse, 0, end-start)
+	for _, application := range applications[start:end] {
+		response = append(response, s.newMembershipApplicationResponse(application))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"applications": response,
+		"page":         page,
+		"limit":        limit,
+		"total":        len(applications),
+	})
+}
+
+func (s *DAOServer) handleGetMembership(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	response := MembershipResponse{
+		Address:       address.String(),
+		Status:        "none",
+		StatusChanges: []StatusChangeResponse{},
+	}
+
+	if holder, exists := s.dao.GetTokenHolder(address); exists {
+		response.Status = holder.Status.String()
+		response.JoinedAt = holder.JoinedAt
+		if !holder.KYCAttestation.IsZero() {
+			response.KYCAttestation = holder.KYCAttestation.String()
+		}
+	}
+
+	if application, exists := s.dao.GetMembershipApplication(address); exists {
+		response.Application = s.newMembershipApplicationResponse(application)
+	}
+
+	for _, change := range s.dao.Membership.GetStatusChanges(address) {
+		votes := make([]string, len(change.Votes))
+		for i, voter := range change.Votes {
+			votes[i] = voter.String()
+		}
+		response.StatusChanges = append(response.StatusChanges, StatusChangeResponse{
+			Status:    change.Status.String(),
+			Reason:    change.Reason,
+			Votes:     votes,
+			CreatedAt: change.CreatedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// exportFlushRows is how many rows an export writes between flushes
+const exportFlushRows = 500
+
+// handleExport streams entities of the DAO state as JSON, an array per
+// entity, or as CSV for a single entity. Exports need an export or admin token.
+func (s *DAOServer) handleExport(c echo.Context) error {
+	if status, err := s.authorizeExport(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	entities := dao.ExportEntities
+	if value := c.QueryParam("entities"); value != "" {
+		entities = strings.Split(value, ",")
+	}
+	for _, entity := range entities {
+		if _, exists := dao.ExportColumns(entity); !exists {
+			return errorMessage(c, http.StatusBadRequest, "entities must be among "+strings.Join(dao.ExportEntities, ", "))
+		}
+	}
+
+	format := c.QueryParam("format")
+	switch format {
+	case "", "json":
+		format = "json"
+	case "csv":
+		if len(entities) != 1 {
+			return errorMessage(c, http.StatusBadRequest, "csv exports one entity at a time")
+		}
+	default:
+		return errorMessage(c, http.StatusBadRequest, "format must be json or csv")
+	}
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="dao-%s.%s"`, strings.Join(entities, "-"), format))
+	if format == "csv" {
+		response.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		response.WriteHeader(http.StatusOK)
+		return s.exportCSV(response, entities[0])
+	}
+	response.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	response.WriteHeader(http.StatusOK)
+	return s.exportJSON(response, entities)
+}
+
+// exportJSON writes entities as a JSON object with an array of rows per
+// entity, keeping the columns in order
+func (s *DAOServer) exportJSON(response *echo.Response, entities []string) error {
+	rows := 0
+	if _, err := response.Write([]byte("{")); err != nil {
+		return err
+	}
+	for i, entity := range entities {
+		columns, _ := dao.ExportColumns(entity)
+		if i > 0 {
+			response.Write([]byte(","))
+		}
+		fmt.Fprintf(response, "%q:[", entity)
+
+		first := true
+		err := s.dao.Export(entity, func(row []interface{}) error {
+			var record bytes.Buffer
+			if !first {
+				record.WriteByte(',')
+			}
+			first = false
+
+			record.WriteByte('{')
+			for j, column := range columns {
+				value, err := json.Marshal(row[j])
+				if err != nil {
+					return err
+				}
+				if j > 0 {
+					record.WriteByte(',')
+				}
+				fmt.Fprintf(&record, "%q:", column)
+				record.Write(value)
+			}
+			record.WriteByte('}')
+
+			if _, err := response.Write(record.Bytes()); err != nil {
+				return err
+			}
+			if rows++; rows%exportFlushRows == 0 {
+				response.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		response.Write([]byte("]"))
+	}
+	_, err := response.Write([]byte("}"))
+	return err
+}
+
+// exportCSV writes an entity as CSV with a header row
+func (s *DAOServer) exportCSV(response *echo.Response, entity string) error {
+	columns, _ := dao.ExportColumns(entity)
+	writer := csv.NewWriter(response)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := s.dao.Export(entity, func(row []interface{}) error {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = fmt.Sprint(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			writer.Flush()
+			response.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// authorizeExport checks the bearer token of an export request against the
+// export tokens and the admin token
+func (s *DAOServer) authorizeExport(c echo.Context) (int, error) {
+	tokens := s.Config.Server.ExportTokens
+	if s.Config.Server.AdminToken != "" {
+		tokens = append([]string{s.Config.Server.AdminToken}, tokens...)
+	}
+	if len(tokens) == 0 {
+		return http.StatusForbidden, fmt.Errorf("data export is disabled")
+	}
+
+	provided := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	for _, token := range tokens {
+		if token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			return http.StatusOK, nil
+		}
+	}
+	return http.StatusUnauthorized, fmt.Errorf("invalid export token")
+}
+
+// Admin endpoints
+func (s *DAOServer) handleGetConfig(c echo.Context) error {
+	if status, err := s.authorizeAdmin(c); err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, s.Config.Redacted())
+}
+
+// authorizeAdmin checks the bearer token of an admin request, returning the
+// HTTP status to answer with when it is not authorized
+func (s *DAOServer) authorizeAdmin(c echo.Context) (int, error) {
+	token := s.Config.Server.AdminToken
+	if token == "" {
+		return http.StatusForbidden, fmt.Errorf("admin API is disabled")
+	}
+
+	provided := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+		return http.StatusUnauthorized, fmt.Errorf("invalid admin token")
+	}
+
+	return http.StatusOK, nil
+}
+
+// Dispute endpoints
+func (s *DAOServer) handleGetDisputes(c echo.Context) error {
+	status := c.QueryParam("status")
+	now := time.Now().Unix()
+
+	response := make([]DisputeResponse, 0)
+	for _, dispute := range s.dao.ListDisputes() {
+		if status == "open" && dispute.Resolved || status == "resolved" && !dispute.Resolved {
+			continue
+		}
+		response = append(response, disputeResponse(dispute, now))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetDispute(c echo.Context) error {
+	disputeID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid dispute ID format")
+	}
+
+	dispute, exists := s.dao.GetDispute(disputeID)
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.NewDAOError(dao.ErrDisputeNotFound, "dispute not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, disputeResponse(dispute, time.Now().Unix()))
+}
+
+func (s *DAOServer) handleOpenDispute(c echo.Context) error {
+	var req struct {
+		DisputeType  uint8  `json:"dispute_type"`
+		Respondent   string `json:"respondent"`
+		Subject      string `json:"subject"`
+		Amount       uint64 `json:"amount"`
+		Description  string `json:"description"`
+		EvidenceHash string `json:"evidence_hash"`
+		PrivateKey   string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	disputeTx := &dao.DisputeTx{
+		Fee:         s.Config.DAO.Fees.Default,
+		DisputeType: dao.DisputeType(req.DisputeType),
+		Amount:      req.Amount,
+		Description: req.Description,
+	}
+
+	if req.Respondent != "" {
+		if disputeTx.Respondent, err = publicKeyFromHex(req.Respondent); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid respondent format")
+		}
+	}
+
+	if req.Subject != "" {
+		if disputeTx.Subject, err = hashFromHex(req.Subject); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid subject format")
+		}
+	}
+
+	if req.EvidenceHash != "" {
+		if disputeTx.EvidenceHash, err = hashFromHex(req.EvidenceHash); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid evidence hash format")
+		}
+	}
+
+	return s.submitDAOTx(c, disputeTx, privKey, "dispute submitted")
+}
+
+func (s *DAOServer) handleSubmitDisputeEvidence(c echo.Context) error {
+	var req struct {
+		DisputeID    string `json:"dispute_id"`
+		EvidenceHash string `json:"evidence_hash"`
+		Description  string `json:"description"`
+		PrivateKey   string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	disputeID, err := hashFromHex(req.DisputeID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid dispute ID format")
+	}
+
+	evidenceHash, err := hashFromHex(req.EvidenceHash)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid evidence hash format")
+	}
+
+	evidenceTx := &dao.DisputeEvidenceTx{
+		Fee:          s.Config.DAO.Fees.Default,
+		DisputeID:    disputeID,
+		EvidenceHash: evidenceHash,
+		Description:  req.Description,
+	}
+
+	return s.submitDAOTx(c, evidenceTx, privKey, "dispute evidence submitted")
+}
+
+func (s *DAOServer) handleCommitJurorRuling(c echo.Context) error {
+	var req struct {
+		DisputeID  string `json:"dispute_id"`
+		Commitment string `json:"commitment"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	disputeID, err := hashFromHex(req.DisputeID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid dispute ID format")
+	}
+
+	commitment, err := hashFromHex(req.Commitment)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid commitment format")
+	}
+
+	commitTx := &dao.JurorCommitTx{
+		Fee:        s.Config.DAO.Fees.Default,
+		DisputeID:  disputeID,
+		Commitment: commitment,
+	}
+
+	return s.submitDAOTx(c, commitTx, privKey, "juror commitment submitted")
+}
+
+func (s *DAOServer) handleRevealJurorRuling(c echo.Context) error {
+	var req struct {
+		DisputeID  string `json:"dispute_id"`
+		Ruling     uint8  `json:"ruling"`
+		Salt       string `json:"salt"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	disputeID, err := hashFromHex(req.DisputeID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid dispute ID format")
+	}
+
+	salt, err := hashFromHex(req.Salt)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid salt format")
+	}
+
+	revealTx := &dao.JurorRevealTx{
+		Fee:       s.Config.DAO.Fees.Default,
+		DisputeID: disputeID,
+		Ruling:    dao.DisputeRuling(req.Ruling),
+		Salt:      salt,
+	}
+
+	return s.submitDAOTx(c, revealTx, privKey, "juror reveal submitted")
+}
+
+// newDAOTransaction wraps a DAO transaction for submission. The random nonce
+// keeps the hashes of otherwise identical transactions from one sender distinct.
+func newDAOTransaction(txInner interface{}) *core.Transaction {
+	return &core.Transaction{
+		TxInner: txInner,
+		To:      crypto.PublicKey{}, // DAO contract address
+		Value:   0,
+		Nonce:   rand.Int63(),
+	}
+}
+
+// TxStatusResponse is where a transaction is in its lifecycle
+type TxStatusResponse struct {
+	Hash          string       `json:"hash"`
+	State         core.TxState `json:"state"`
+	Height        uint32       `json:"height,omitempty"`        // Block that included the transaction
+	Confirmations uint32       `json:"confirmations,omitempty"` // Blocks since, including its own
+	Reason        string       `json:"reason,omitempty"`        // Why the transaction was rejected
+	UpdatedAt     int64        `json:"updated_at"`
+}
+
+func (s *DAOServer) txStatusResponse(status core.TxStatus) TxStatusResponse {
+	response := TxStatusResponse{
+		Hash:      status.Hash.String(),
+		State:     status.State,
+		Reason:    status.Reason,
+		UpdatedAt: status.UpdatedAt,
+	}
+	if status.State == core.TxIncluded || status.State == core.TxFinalized {
+		response.Height = status.Height
+		if height := s.bc.Height(); height >= status.Height {
+			response.Confirmations = height - status.Height + 1
+		}
+	}
+	return response
+}
+
+// handleGetTxStatus returns whether a transaction is pending, included,
+// finalized or rejected
+func (s *DAOServer) handleGetTxStatus(c echo.Context) error {
+	hash, err := hashFromHex(c.Param("hash"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid transaction hash")
+	}
+
+	status, exists := s.bc.TxTracker().Status(hash)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "transaction not known to this node")
+	}
+
+	return c.JSON(http.StatusOK, s.txStatusResponse(status))
+}
+
+// submitDAOTx signs a DAO transaction and sends it to the chain
+func (s *DAOServer) submitDAOTx(c echo.Context, txInner interface{}, privKey crypto.PrivateKey, message string) error {
+	return s.submitDAOTxWithEvent(c, txInner, privKey, message, "", nil)
+}
+
+// submitDAOTxWithEvent submits a DAO transaction and broadcasts an event
+// carrying data and the transaction hash to WebSocket clients
+func (s *DAOServer) submitDAOTxWithEvent(c echo.Context, txInner interface{}, privKey crypto.PrivateKey, message string, eventType EventType, data map[string]interface{}) error {
+	tx := s.newDAOTransaction(txInner)
+
+	if err := tx.Sign(privKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to sign transaction")
+	}
+
+	// Send transaction
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
+	}
+	txHash := tx.Hash(core.TxHasher{}).String()
+
+	if eventType != "" {
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		data["tx_hash"] = txHash
+		data["sender"] = privKey.PublicKey().String()
+
+		s.broadcastEvent(Event{
+			Type:      eventType,
+			Data:      data,
+			Timestamp: time.Now().Unix(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tx_hash": txHash,
+		"message": message,
+	})
+}
+
+// disputeResponse converts a dispute into its API representation
+func disputeResponse(dispute *dao.Dispute, now int64) DisputeResponse {
+	disputeTypes := map[dao.DisputeType]string{
+		dao.DisputeTypeGrant:            "grant",
+		dao.DisputeTypeClawback:         "clawback",
+		dao.DisputeTypeModerationAppeal: "moderation_appeal",
+	}
+
+	evidence := make([]DisputeEvidenceResponse, len(dispute.Evidence))
+	for i, item := range dispute.Evidence {
+		evidence[i] = DisputeEvidenceResponse{
+			Submitter:   item.Submitter.String(),
+			Hash:        item.Hash.String(),
+			Description: item.Description,
+			SubmittedAt: item.SubmittedAt,
+		}
+	}
+
+	jurors := make([]string, len(dispute.Jurors))
+	for i, juror := range dispute.Jurors {
+		jurors[i] = juror.String()
+	}
+
+	response := DisputeResponse{
+		ID:               dispute.ID.String(),
+		Type:             disputeTypes[dispute.Type],
+		Claimant:         dispute.Claimant.String(),
+		Subject:          dispute.Subject.String(),
+		Amount:           dispute.Amount,
+		Description:      dispute.Description,
+		Bond:             dispute.Bond,
+		Phase:            string(dispute.Phase(now)),
+		Evidence:         evidence,
+		Jurors:           jurors,
+		Commitments:      len(dispute.Commitments),
+		Reveals:          len(dispute.Reveals),
+		OpenedAt:         dispute.OpenedAt,
+		EvidenceDeadline: dispute.EvidenceDeadline,
+		CommitDeadline:   dispute.CommitDeadline,
+		RevealDeadline:   dispute.RevealDeadline,
+		ResolvedAt:       dispute.ResolvedAt,
+		Executed:         dispute.Executed,
+	}
+
+	if len(dispute.Respondent) > 0 {
+		response.Respondent = dispute.Respondent.String()
+	}
+
+	switch dispute.Ruling {
+	case dao.DisputeRulingClaimant:
+		response.Ruling = "claimant"
+	case dao.DisputeRulingRespondent:
+		response.Ruling = "respondent"
+	}
+
+	return response
+}
+
+// positionResponse converts a position into its API representation
+func (s *DAOServer) positionResponse(position *dao.Position) PositionResponse {
+	positionType := "vesting"
+	if position.Type == dao.PositionTypeStake {
+		positionType = "stake"
+	}
+
+	history := make([]PositionTransferResponse, len(position.History))
+	for i, transfer := range position.History {
+		history[i] = PositionTransferResponse{
+			From:      transfer.From.String(),
+			To:        transfer.To.String(),
+			Timestamp: transfer.Timestamp,
+			TxHash:    transfer.TxHash.String(),
+		}
+	}
+
+	return PositionResponse{
+		ID:           position.ID,
+		Type:         positionType,
+		Owner:        position.Owner.String(),
+		SourceID:     position.SourceID,
+		Amount:       position.Amount,
+		UnlockTime:   position.UnlockTime,
+		CreatedAt:    position.CreatedAt,
+		Closed:       position.Closed,
+		Transferable: s.dao.PositionManager.IsTransferable(position.Type),
+		History:      history,
+	}
+}
+
+// WebSocket connection settings
+var (
+	// wsWriteWait is how long a write to a client may take
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is how long a client may stay silent before it is dropped
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod is how often clients are pinged, shorter than wsPongWait
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+const (
+	// wsSendBuffer is how many messages can queue for a client, clients
+	// that fall further behind are evicted
+	wsSendBuffer = 256
+	// wsMaxMessageSize is the largest message read from a client
+	wsMaxMessageSize = 4096
+)
+
+// wsClient is a WebSocket connection with its own send queue, written by
+// its write pump so slow clients never hold up the others
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte
+	// member is the authenticated member, empty for anonymous clients
+	member string
+	// channels are the private channels the member takes, nil for all
+	channels map[string]bool
+	// evicted is set before send is closed when the client fell behind
+	evicted bool
+}
+
+func newWSClient(conn *websocket.Conn) *wsClient {
+	return &wsClient{conn: conn, send: make(chan []byte, wsSendBuffer)}
+}
+
+// writePump writes queued messages and keepalive pings to the client until
+// its queue is closed or a write fails
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				if c.evicted {
+					c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"))
+				}
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readPump reads from the client until it disconnects or stops answering
+// pings
+func (c *wsClient) readPump() {
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// handleWebSocket streams events over a WebSocket. Connections with an
+// event token also receive the member's private channels.
+func (s *DAOServer) handleWebSocket(c echo.Context) error {
+	member, channels, status, err := s.eventSubscription(c)
+	if err != nil {
+		return errorResponse(c, status, err)
+	}
+
+	conn, err := s.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return err
+	}
+
+	client := newWSClient(conn)
+	client.member = member
+	client.channels = channels
+	s.eventBus.register <- client
+	go client.writePump()
+
+	client.readPump()
+	s.eventBus.unregister <- client
+	conn.Close()
+
+	return nil
+}
+
+// eventStreamKeepAlive is how often idle event streams send a comment so
+// proxies keep them open
+var eventStreamKeepAlive = 15 * time.Second
+
+// handleEventStream streams events as Server-Sent Events, replaying the
+// kept events after Last-Event-ID first
+func (s *DAOServer) handleEventStream(c echo.Context) error {
+	var lastID uint64
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+	if lastEventID != "" {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "Invalid Last-Event-ID")
+		}
+		lastID = parsed
+	}
+
+	var types map[EventType]bool
+	if param := c.QueryParam("types"); param != "" {
+		types = make(map[EventType]bool)
+		for _, eventType := range strings.Split(param, ",") {
+			types[EventType(strings.TrimSpace(eventType))] = true
+		}
+	}
+
+	// Subscribe before replaying so no event falls between the two, slow
+	// clients are dropped and resume with Last-Event-ID
+	events := make(chan Event, 256)
+	dropped := make(chan struct{})
+	var dropOnce sync.Once
+	unsubscribe := s.eventBus.Subscribe(func(event Event) {
+		select {
+		case events <- event:
+		default:
+			dropOnce.Do(func() { close(dropped) })
+		}
+	})
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	send := func(event Event) error {
+		if event.ID <= lastID {
+			return nil
+		}
+		lastID = event.ID
+		if types != nil && !types[event.Type] {
+			return nil
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+
+	for _, event := range s.eventBus.History(lastID) {
+		if err := send(event); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if err := send(event); err != nil {
+				return nil
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keepalive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-dropped:
+			return nil
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// cached serves a read endpoint through the response cache when caching is
+// enabled
+func (s *DAOServer) cached(next echo.HandlerFunc) echo.HandlerFunc {
+	if s.cache == nil {
+		return next
+	}
+	return s.cache.Middleware(next)
+}
+
+// readOnly rejects requests that would change state, read replicas have no
+// path to consensus to submit them through
+func (s *DAOServer) readOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		switch c.Request().Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return next(c)
+		}
+		// The admin API controls the replica itself
+		if strings.HasPrefix(c.Request().URL.Path, "/admin/") {
+			return next(c)
+		}
+		c.Response().Header().Set(echo.HeaderAllow, "GET, HEAD, OPTIONS")
+		return errorMessage(c, http.StatusMethodNotAllowed, fmt.Sprintf("this node is a read-only replica, send writes to %s", s.Config.Node.ReplicaOf))
+	}
+}
+
+// Event broadcasting
+// eventRecipients returns the members an event is addressed to, nil for
+// events every subscriber is notified of
+func eventRecipients(event Event) []string {
+	switch event.Type {
+	case EventDelegationExpired, EventDelegationRenewed:
+		data, ok := event.Data.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		delegator, _ := data["delegator"].(string)
+		delegate, _ := data["delegate"].(string)
+		return []string{delegator, delegate}
+	}
+	return nil
+}
+
+func (s *DAOServer) broadcastEvent(event Event) {
+	event = s.eventBus.publish(event)
+
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	s.eventBus.broadcast <- eventData
+}
+
+// EventBus methods
+
+// Subscribe calls fn with every event broadcast on the bus until the
+// returned function is called
+func (eb *EventBus) Subscribe(fn func(Event)) (unsubscribe func()) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	id := eb.nextSubscriber
+	eb.nextSubscriber++
+	eb.subscribers[id] = fn
+
+	return func() {
+		eb.mu.Lock()
+		defer eb.mu.Unlock()
+		delete(eb.subscribers, id)
+	}
+}
+
+// History returns the kept events after afterID, oldest first
+func (eb *EventBus) History(afterID uint64) []Event {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	start := sort.Search(len(eb.history), func(i int) bool { return eb.history[i].ID > afterID })
+	return append([]Event(nil), eb.history[start:]...)
+}
+
+// publish numbers an event, keeps it for replay and passes it to the
+// subscribers
+func (eb *EventBus) publish(event Event) Event {
+	eb.mu.Lock()
+	eb.lastID++
+	event.ID = eb.lastID
+	eb.history = append(eb.history, event)
+	if len(eb.history) > eventHistorySize {
+		eb.history = append([]Event(nil), eb.history[len(eb.history)-eventHistorySize:]...)
+	}
+	eb.mu.Unlock()
+
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	for _, fn := range eb.subscribers {
+		fn(event)
+	}
+	return event
+}
+
+// run registers clients and fans broadcasts out to their send queues. It
+// never waits on a client: one whose queue is full is evicted and its write
+// pump closes the connection.
+func (eb *EventBus) run() {
+	for {
+		select {
+		case client := <-eb.register:
+			eb.clients[client] = true
+
+		case client := <-eb.unregister:
+			if _, ok := eb.clients[client]; ok {
+				delete(eb.clients, client)
+				close(client.send)
+			}
+
+		case message := <-eb.broadcast:
+			for client := range eb.clients {
+				eb.deliver(client, message)
+			}
+
+		case private := <-eb.private:
+			for client := range eb.clients {
+				if private.recipients[client.member] && client.subscribes(private.channel) {
+					eb.deliver(client, private.message)
+				}
+			}
+		}
+	}
+}
+
+// deliver queues a message for a client, evicting it when its queue is full
+func (eb *EventBus) deliver(client *wsClient, message []byte) {
+	select {
+	case client.send <- message:
+	default:
+		delete(eb.clients, client)
+		client.evicted = true
+		close(client.send)
+	}
+}
+
+// Wallet integration endpoints
+
+// WalletConnectionRequest represents a wallet connection request
+type WalletConnectionRequest struct {
+	Provider  string `json:"provider"`
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey"`
+	ChainID   string `json:"chainId,omitempty"`
+}
+
+// WalletConnectionResponse represents a wallet connection response
+type WalletConnectionResponse struct {
+	Success    bool                  `json:"success"`
+	Connection *dao.WalletConnection `json:"connection,omitempty"`
+}
+
+// Analytics endpoint handlers
+
+func (s *DAOServer) handleGetParticipationMetrics(c echo.Context) error {
+	metrics := s.dao.GetGovernanceParticipationMetrics()
+	return c.JSON(http.StatusOK, metrics)
+}
+
+func (s *DAOServer) handleGetTreasuryMetrics(c echo.Context) error {
+	metrics := s.dao.GetTreasuryPerformanceMetrics()
+	return c.JSON(http.StatusOK, metrics)
+}
+
+func (s *DAOServer) handleGetProposalAnalytics(c echo.Context) error {
+	analytics := s.dao.GetProposalAnalytics()
+	return c.JSON(http.StatusOK, analytics)
+}
+
+func (s *DAOServer) handleGetStakingYieldMetrics(c echo.Context) error {
+	metrics := s.dao.GetStakingYieldMetrics()
+	return c.JSON(http.StatusOK, metrics)
+}
+
+func (s *DAOServer) handleGetPublicGoodsMetrics(c echo.Context) error {
+	analytics := s.dao.GetPublicGoodsAnalytics()
+	return c.JSON(http.StatusOK, analytics)
+}
+
+func (s *DAOServer) handleGetSubDAOAnalytics(c echo.Context) error {
+	analytics := s.dao.GetSubDAOAnalytics()
+	return c.JSON(http.StatusOK, analytics)
+}
+
+// timeSeriesDefaultWindow is the span of a time series query without from
+const timeSeriesDefaultWindow int64 = 7 * 24 * 3600
+
+// handleGetMetricTimeSeries returns the sampled history of an analytics
+// metric. The resolution is in seconds or a duration such as "1h".
+func (s *DAOServer) handleGetMetricTimeSeries(c echo.Context) error {
+	metric := c.QueryParam("metric")
+	if !dao.IsTimeSeriesMetric(metric) {
+		return errorMessage(c, http.StatusBadRequest, "metric must be one of "+strings.Join(dao.TimeSeriesMetrics, ", "))
+	}
+
+	to := time.Now().Unix()
+	if value := c.QueryParam("to"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid to")
+		}
+		to = parsed
+	}
+	from := to - timeSeriesDefaultWindow
+	if value := c.QueryParam("from"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "invalid from")
+		}
+		from = parsed
+	}
+	if from > to {
+		return errorMessage(c, http.StatusBadRequest, "from must not be after to")
+	}
+
+	var resolution int64
+	if value := c.QueryParam("resolution"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			duration, durationErr := time.ParseDuration(value)
+			if durationErr != nil {
+				return errorMessage(c, http.StatusBadRequest, "invalid resolution")
+			}
+			parsed = int64(duration / time.Second)
+		}
+		if parsed < 0 {
+			return errorMessage(c, http.StatusBadRequest, "invalid resolution")
+		}
+		resolution = parsed
+	}
+
+	points, err := s.dao.GetMetricTimeSeries(metric, from, to, resolution)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"metric":     metric,
+		"from":       from,
+		"to":         to,
+		"resolution": resolution,
+		"points":     points,
+	})
+}
+
+// handleGetDelegateScorecards lists the scorecards of current delegates, most
+// delegated power first
+func (s *DAOServer) handleGetDelegateScorecards(c echo.Context) error {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	scorecards := s.dao.GetDelegateScorecards()
+	total := len(scorecards)
+
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"delegates": scorecards[start:end],
+		"page":      page,
+		"limit":     limit,
+		"total":     total,
+	})
+}
+
+// handleGetDelegateScorecard returns the scorecard of one delegate
+func (s *DAOServer) handleGetDelegateScorecard(c echo.Context) error {
+	delegate, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address")
+	}
+
+	return c.JSON(http.StatusOK, s.dao.GetDelegateScorecard(delegate))
+}
+
+func (s *DAOServer) handleGetHealthMetrics(c echo.Context) error {
+	health := s.dao.GetDAOHealthMetrics()
+	return c.JSON(http.StatusOK, health)
+}
+
+// handleGetAdaptiveQuorum returns the quorum recent turnout sets for new
+// proposals
+func (s *DAOServer) handleGetAdaptiveQuorum(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.dao.GetAdaptiveQuorum())
+}
+
+// handleGetProposalTurnout returns who voted on a proposal, by segment and
+// over time
+func (s *DAOServer) handleGetProposalTurnout(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	buckets := dao.DefaultTurnoutBuckets
+	if raw := c.QueryParam("buckets"); raw != "" {
+		buckets, err = strconv.Atoi(raw)
+		if err != nil || buckets < 1 || buckets > dao.MaxTurnoutBuckets {
+			return errorMessage(c, http.StatusBadRequest, fmt.Sprintf("buckets must be 1 to %d", dao.MaxTurnoutBuckets))
+		}
+	}
+
+	turnout, err := s.dao.GetProposalTurnout(proposalID, buckets)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, turnout)
+}
+
+func (s *DAOServer) handleGetAnalyticsSummary(c echo.Context) error {
+	summary := s.dao.GetAnalyticsSummary()
+	return c.JSON(http.StatusOK, summary)
+}
+
+// WalletIntegrationResponse represents a wallet integration response
+type WalletIntegrationResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// TransactionSigningRequest represents a transaction signing request
+type TransactionSigningRequest struct {
+	Address     string      `json:"address"`
+	Transaction interface{} `json:"transaction"`
+	Signature   string      `json:"signature"`
+}
+
+// TransactionSigningResponse represents a transaction signing response
+type TransactionSigningResponse struct {
+	Success           bool                   `json:"success"`
+	SignedTransaction *dao.SignedTransaction `json:"signedTransaction,omitempty"`
+}
+
+// BroadcastTransactionRequest represents a transaction broadcast request
+type BroadcastTransactionRequest struct {
+	SignedTransaction *dao.SignedTransaction `json:"signedTransaction"`
+}
+
+// BroadcastTransactionResponse represents a transaction broadcast response
+type BroadcastTransactionResponse struct {
+	Success         bool   `json:"success"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+	BlockHeight     int64  `json:"blockHeight,omitempty"`
+}
+
+// WalletInfoResponse represents wallet information response
+type WalletInfoResponse struct {
+	Success bool                  `json:"success"`
+	Wallet  *dao.WalletConnection `json:"wallet,omitempty"`
+	Balance int64                 `json:"balance,omitempty"`
+}
+
+// Add wallet integration routes to the DAO server
+func (s *DAOServer) setupWalletRoutes(e *echo.Echo) {
+	// Wallet connection endpoints
+	e.POST("/dao/wallet/connect", s.handleWalletConnect)
+	e.POST("/dao/wallet/disconnect", s.handleWalletDisconnect)
+	e.GET("/dao/wallet/info/:address", s.handleGetWalletInfo)
+	e.GET("/dao/wallet/connections", s.handleGetActiveConnections)
+
+	// Transaction signing endpoints
+	e.POST("/dao/wallet/sign", s.handleSignTransaction)
+	e.POST("/dao/wallet/broadcast", s.handleBroadcastTransaction)
+	e.POST("/dao/wallet/verify", s.handleVerifyTransaction)
+
+	// Wallet utilities
+	e.POST("/dao/wallet/generate-test", s.handleGenerateTestWallet)
+	e.POST("/dao/wallet/mnemonic", s.handleGenerateMnemonic)
+	e.POST("/dao/wallet/derive", s.handleDeriveAccounts)
+	e.GET("/dao/wallet/supported", s.handleGetSupportedWallets)
+}
+
+// handleWalletConnect handles wallet connection requests
+func (s *DAOServer) handleWalletConnect(c echo.Context) error {
+	var req WalletConnectionRequest
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid request format")
+	}
+
+	// Validate required fields
+	if req.Provider == "" || req.Address == "" || req.PublicKey == "" {
+		return errorMessage(c, http.StatusBadRequest, "Provider, address, and publicKey are required")
+	}
+
+	// Get wallet connection manager
+	walletManager := dao.NewWalletConnectionManager()
+
+	// Handle wallet connection
+	connection, err := walletManager.HandleWalletConnection(
+		dao.WalletProvider(req.Provider),
+		req.Address,
+		req.PublicKey,
+		req.ChainID,
+	)
+
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	// Broadcast wallet connection event
+	event := Event{
+		Type: EventType("wallet_connected"),
+		Data: map[string]interface{}{
+			"address":  req.Address,
+			"provider": req.Provider,
+			"chainId":  req.ChainID,
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	s.broadcastEvent(event)
+
+	return c.JSON(http.StatusOK, WalletConnectionResponse{
+		Success:    true,
+		Connection: connection,
+	})
+}
+
+// handleWalletDisconnect handles wallet disconnection requests
+func (s *DAOServer) handleWalletDisconnect(c echo.Context) error {
+	address := c.FormValue("address")
+	if address == "" {
+		return errorMessage(c, http.StatusBadRequest, "Address is required")
+	}
+
+	walletManager := dao.NewWalletConnectionManager()
+	err := walletManager.DisconnectWallet(address)
+
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	// Broadcast wallet disconnection event
+	event := Event{
+		Type: EventType("wallet_disconnected"),
+		Data: map[string]interface{}{
+			"address": address,
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	s.broadcastEvent(event)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// handleGetWalletInfo handles wallet information requests
+func (s *DAOServer) handleGetWalletInfo(c echo.Context) error {
+	address := c.Param("address")
+	if address == "" {
+		return errorMessage(c, http.StatusBadRequest, "Address is required")
+	}
+
+	walletManager := dao.NewWalletConnectionManager()
+	wallet, err := walletManager.GetWalletInfo(address)
+
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, err)
+	}
+
+	// Get token balance - convert address string to PublicKey
+	// For now, we'll set balance to 0 since we need proper address conversion
+	balance := uint64(0)
+
+	return c.JSON(http.StatusOK, WalletInfoResponse{
+		Success: true,
+		Wallet:  wallet,
+		Balance: int64(balance),
+	})
+}
+
+// handleGetActiveConnections handles requests for active wallet connections
+func (s *DAOServer) handleGetActiveConnections(c echo.Context) error {
+	// Simplified implementation for testing
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"connections": []interface{}{},
+		"count":       0,
+	})
+}
+
+// handleSignTransaction handles transaction signing requests
+func (s *DAOServer) handleSignTransaction(c echo.Context) error {
+	var req TransactionSigningRequest
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid request format")
+	}
+
+	// Validate required fields
+	if req.Address == "" || req.Transaction == nil || req.Signature == "" {
+		return errorMessage(c, http.StatusBadRequest, "Address, transaction, and signature are required")
+	}
+
+	walletManager := dao.NewWalletConnectionManager()
+	signedTx, err := walletManager.HandleTransactionSigning(
+		req.Address,
+		req.Transaction,
+		req.Signature,
+	)
+
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	// Broadcast transaction signed event
+	event := Event{
+		Type: EventType("transaction_signed"),
+		Data: map[string]interface{}{
+			"address":         req.Address,
+			"transactionHash": signedTx.TransactionHash.String(),
+			"signingMethod":   signedTx.SigningMethod,
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	s.broadcastEvent(event)
+
+	return c.JSON(http.StatusOK, TransactionSigningResponse{
+		Success:           true,
+		SignedTransaction: signedTx,
+	})
+}
+
+// handleBroadcastTransaction handles transaction broadcasting requests
+func (s *DAOServer) handleBroadcastTransaction(c echo.Context) error {
+	var req BroadcastTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid request format")
+	}
+
+	if req.SignedTransaction == nil {
+		return errorMessage(c, http.StatusBadRequest, "Signed transaction is required")
+	}
+
+	// Verify the signed transaction
+	walletService := dao.NewWalletIntegrationService()
+	err := walletService.VerifySignedTransaction(req.SignedTransaction)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, fmt.Errorf("transaction verification failed: %w", err))
+	}
+
+	// Create core transaction from signed DAO transaction
+	coreTx := &core.Transaction{
+		TxInner:   req.SignedTransaction.Transaction,
+		From:      crypto.PublicKey(req.SignedTransaction.Signer),
+		Signature: &req.SignedTransaction.Signature,
+		Nonce:     time.Now().Unix(),
+	}
+
+	// Add transaction to channel (simulating mempool)
+	if err := s.submitTx(coreTx); err != nil {
+		return errorResponse(c, submitStatus(err), fmt.Errorf("failed to add transaction to mempool: %w", err))
+	}
+
+	// Get current block height
+	blockHeight := s.bc.Height()
+
+	// Broadcast transaction event
+	event := Event{
+		Type: EventType("transaction_broadcast"),
+		Data: map[string]interface{}{
+			"transactionHash": req.SignedTransaction.TransactionHash.String(),
+			"signer":          req.SignedTransaction.Signer.String(),
+			"blockHeight":     blockHeight,
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	s.broadcastEvent(event)
+
+	return c.JSON(http.StatusOK, BroadcastTransactionResponse{
+		Success:         true,
+		TransactionHash: req.SignedTransaction.TransactionHash.String(),
+		BlockHeight:     int64(blockHeight),
+	})
+}
+
+// handleVerifyTransaction handles transaction verification requests
+func (s *DAOServer) handleVerifyTransaction(c echo.Context) error {
+	var signedTx dao.SignedTransaction
+	if err := c.Bind(&signedTx); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid request format")
+	}
+
+	walletService := dao.NewWalletIntegrationService()
+	err := walletService.VerifySignedTransaction(&signedTx)
+
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, fmt.Errorf("transaction verification failed: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"valid":   true,
+	})
+}
+
+// handleGenerateTestWallet handles test wallet generation requests
+func (s *DAOServer) handleGenerateTestWallet(c echo.Context) error {
+	_, publicKey, address, err := dao.GenerateTestWallet()
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	// Convert to hex strings for JSON response
+	privateKeyHex := "generated_private_key" // Simplified for testing
+	publicKeyHex := hex.EncodeToString(publicKey)
+	addressHex := address.String()
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"privateKey": privateKeyHex,
+		"publicKey":  publicKeyHex,
+		"address":    addressHex,
+		"warning":    "This is for development only. Never use in production.",
+	})
+}
+
+// MnemonicRequest selects the strength of a generated mnemonic
+type MnemonicRequest struct {
+	Words int `json:"words"` // 12, 15, 18, 21 or 24, defaults to 12
+}
+
+// MnemonicResponse is a generated BIP-39 mnemonic
+type MnemonicResponse struct {
+	Mnemonic string `json:"mnemonic"`
+	Words    int    `json:"words"`
+}
+
+// DeriveAccountsRequest selects the accounts derived from a mnemonic
+type DeriveAccountsRequest struct {
+	Mnemonic   string `json:"mnemonic"`
+	Passphrase string `json:"passphrase"`
+	Start      uint32 `json:"start"`
+	Count      uint32 `json:"count"` // Defaults to 1, at most 100
+}
+
+// DeriveAccountsResponse lists the accounts derived from a mnemonic
+type DeriveAccountsResponse struct {
+	CoinType uint32               `json:"coin_type"`
+	Accounts []dao.DerivedAccount `json:"accounts"`
+}
+
+// handleGenerateMnemonic generates a BIP-39 mnemonic for a new HD wallet
+func (s *DAOServer) handleGenerateMnemonic(c echo.Context) error {
+	var req MnemonicRequest
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid request format")
+	}
+	if req.Words == 0 {
+		req.Words = 12
+	}
+	if req.Words%3 != 0 || req.Words < 12 || req.Words > 24 {
+		return errorMessage(c, http.StatusBadRequest, "words must be 12, 15, 18, 21 or 24")
+	}
+
+	mnemonic, err := crypto.NewMnemonic(req.Words * 32 / 3)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, MnemonicResponse{Mnemonic: mnemonic, Words: req.Words})
+}
+
+// handleDeriveAccounts derives the BIP-44 account addresses of a mnemonic
+func (s *DAOServer) handleDeriveAccounts(c echo.Context) error {
+	var req DeriveAccountsRequest
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "Invalid request format")
+	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	if req.Count > 100 {
+		return errorMessage(c, http.StatusBadRequest, "count must be at most 100")
+	}
+	if req.Start >= crypto.HardenedOffset-req.Count {
+		return errorMessage(c, http.StatusBadRequest, "start is out of range")
+	}
+
+	accounts, err := dao.DeriveAccounts(req.Mnemonic, req.Passphrase, req.Start, req.Count)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+
+	return c.JSON(http.StatusOK, DeriveAccountsResponse{CoinType: crypto.BockCoinType, Accounts: accounts})
+}
+
+// handleGetSupportedWallets handles requests for supported wallet providers
+func (s *DAOServer) handleGetSupportedWallets(c echo.Context) error {
+	supportedWallets := map[string]interface{}{
+		"metamask": map[string]interface{}{
+			"name":        "MetaMask",
+			"description": "Browser extension wallet",
+			"supported":   true,
+			"features":    []string{"signing", "account_management", "chain_switching"},
+		},
+		"walletconnect": map[string]interface{}{
+			"name":        "WalletConnect",
+			"description": "Connect to mobile wallets",
+			"supported":   true,
+			"features":    []string{"signing", "qr_code", "deep_linking"},
+		},
+		"manual": map[string]interface{}{
+			"name":        "Manual Key Input",
+			"description": "Direct private key input (development only)",
+			"supported":   true,
+			"features":    []string{"signing", "key_generation"},
+			"warning":     "For development use only",
+		},
+		"ledger": map[string]interface{}{
+			"name":        "Ledger Hardware Wallet",
+			"description": "Hardware wallet integration",
+			"supported":   false,
+			"features":    []string{"signing", "hardware_security"},
+			"note":        "Coming soon",
+		},
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"wallets": supportedWallets,
+	})
+}
+
+// Initialize wallet integration in the DAO server
+func (s *DAOServer) initWalletIntegration() {
+	// Initialize WebSocket clients map if not already done
+	if s.wsClients == nil {
+		s.wsClients = make(map[*websocket.Conn]bool)
+	}
+
+	// Set up WebSocket upgrader
+	s.upgrader = websocket.Upgrader{
+		CheckOrigin: s.checkOrigin,
+	}
+}
+
+// Governance document endpoints
+func documentVersionResponse(version *dao.DocumentVersion) DocumentVersionResponse {
+	return DocumentVersionResponse{
+		Version:     version.Version,
+		ContentHash: version.ContentHash.String(),
+		DiffHash:    version.DiffHash.String(),
+		Summary:     version.Summary,
+		ProposalID:  version.ProposalID.String(),
+		Proposer:    version.Proposer.String(),
+		EnactedAt:   version.EnactedAt,
+	}
+}
+
+func (s *DAOServer) governanceDocumentResponse(doc *dao.GovernanceDocument) GovernanceDocumentResponse {
+	response := GovernanceDocumentResponse{
+		ID:                doc.ID,
+		Title:             doc.Title,
+		PendingAmendments: make([]string, 0),
+	}
+	if current := doc.Current(); current != nil {
+		response.Version = current.Version
+		response.ContentHash = current.ContentHash.String()
+		response.UpdatedAt = current.EnactedAt
+	}
+	for _, id := range s.dao.Constitution.PendingAmendments(doc.ID) {
+		response.PendingAmendments = append(response.PendingAmendments, id.String())
+	}
+	return response
+}
+
+func (s *DAOServer) handleGetGovernanceDocuments(c echo.Context) error {
+	docs := s.dao.ListGovernanceDocuments()
+	response := make([]GovernanceDocumentResponse, len(docs))
+	for i, doc := range docs {
+		response[i] = s.governanceDocumentResponse(doc)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetGovernanceDocument(c echo.Context) error {
+	doc, exists := s.dao.GetGovernanceDocument(c.Param("id"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrDocumentNotFoundError)
+	}
+
+	response := s.governanceDocumentResponse(doc)
+	response.Versions = make([]DocumentVersionResponse, len(doc.Versions))
+	for i, version := range doc.Versions {
+		response.Versions[i] = documentVersionResponse(version)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// handleGetGovernanceDocumentVersion returns a version with its text and
+// diff metadata. Content that cannot be fetched from IPFS is left out and
+// the client keeps the hashes to retry with.
+func (s *DAOServer) handleGetGovernanceDocumentVersion(c echo.Context) error {
+	doc, exists := s.dao.GetGovernanceDocument(c.Param("id"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrDocumentNotFoundError)
+	}
+
+	number, err := strconv.ParseUint(c.Param("version"), 10, 32)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid version")
+	}
+	version, exists := doc.Version(uint32(number))
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "document version not found")
+	}
+
+	response := documentVersionResponse(version)
+	if content, err := s.dao.IPFSClient.RetrieveGovernanceDocument(version.ContentHash); err == nil {
+		response.Content = content
+	}
+	if diff, err := s.dao.IPFSClient.RetrieveDocumentDiff(version.DiffHash); err == nil {
+		response.Diff = diff
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// handleProposeAmendment stores the amended text and its diff from the
+// current version on IPFS and proposes the amendment. A document without
+// versions is adopted by its first amendment.
+func (s *DAOServer) handleProposeAmendment(c echo.Context) error {
+	var req struct {
+		Title         string         `json:"title"`
+		Description   string         `json:"description"`
+		DocumentTitle string         `json:"document_title"` // Required to adopt a document
+		Content       string         `json:"content"`
+		Summary       string         `json:"summary"`
+		Sections      []string       `json:"sections"`
+		VotingType    dao.VotingType `json:"voting_type"`
+		StartTime     int64          `json:"start_time"`
+		EndTime       int64          `json:"end_time"`
+		Threshold     uint64         `json:"threshold"` // Defaults to the amendment threshold
+		PrivateKey    string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	if strings.TrimSpace(req.Content) == "" || len(req.Content) > dao.MaxDocumentLength {
+		return fieldErrorResponse(c, "invalid document content", []FieldError{{
+			Field:   "content",
+			Message: fmt.Sprintf("must be 1 to %d bytes", dao.MaxDocumentLength),
+		}})
+	}
+
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	// Amend the current version, diffing against its text
+	documentID := c.Param("id")
+	var base uint32
+	var previousHash types.Hash
+	var previous string
+	if doc, exists := s.dao.GetGovernanceDocument(documentID); exists && doc.Current() != nil {
+		base = doc.Current().Version
+		previousHash = doc.Current().ContentHash
+		if previous, err = s.dao.IPFSClient.RetrieveGovernanceDocument(previousHash); err != nil {
+			return errorResponse(c, http.StatusBadGateway, err)
+		}
+	}
+
+	contentHash, err := s.dao.IPFSClient.UploadGovernanceDocument(req.Content)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+	diff := dao.NewDocumentDiff(documentID, base, previousHash, contentHash, previous, req.Content, req.Summary, req.Sections)
+	diffHash, err := s.dao.IPFSClient.UploadDocumentDiff(diff)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	threshold := req.Threshold
+	if threshold == 0 {
+		threshold = s.dao.ParameterManager.GetParameterConfig().ConstitutionAmendmentThreshold
+	}
+
+	amendmentTx := &dao.ConstitutionAmendmentTx{
+		Fee:           s.Config.DAO.Fees.Proposal,
+		Title:         req.Title,
+		Description:   req.Description,
+		DocumentID:    documentID,
+		DocumentTitle: req.DocumentTitle,
+		BaseVersion:   base,
+		ContentHash:   contentHash,
+		DiffHash:      diffHash,
+		Summary:       req.Summary,
+		VotingType:    req.VotingType,
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+		Threshold:     threshold,
+	}
+
+	return s.submitDAOTxWithEvent(c, amendmentTx, privKey, "amendment proposed", EventAmendmentProposed, map[string]interface{}{
+		"document_id":  documentID,
+		"base_version": base,
+		"content_hash": contentHash.String(),
+		"additions":    diff.Additions,
+		"deletions":    diff.Deletions,
+	})
+}
+
+func (s *DAOServer) handleEnactAmendment(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	enactTx := &dao.ConstitutionEnactTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTxWithEvent(c, enactTx, privKey, "amendment enactment submitted", EventAmendmentEnacted, map[string]interface{}{
+		"proposal_id": proposalID.String(),
+	})
+}
+
+// Emergency spend endpoints
+func (s *DAOServer) emergencySpendResponse(spend *dao.EmergencySpend) EmergencySpendResponse {
+	response := EmergencySpendResponse{
+		ProposalID: spend.ID.String(),
+		Proposer:   spend.Proposer.String(),
+		Guardian:   spend.Guardian.String(),
+		Recipient:  spend.Recipient.String(),
+		Amount:     spend.Amount,
+		Incident:   spend.Incident,
+		CreatedAt:  spend.CreatedAt,
+		ExecutedAt: spend.ExecutedAt,
+	}
+	if proposal, err := s.dao.GetProposal(spend.ID); err == nil {
+		config := s.dao.GovernanceState.Config
+		response.Title = proposal.Title
+		response.Status = proposal.Status
+		response.EndTime = proposal.EndTime
+		response.Quorum = proposal.RequiredQuorum(config)
+		response.Threshold = proposal.RequiredThreshold(config)
+		if proposal.Results != nil {
+			response.YesVotes = proposal.Results.YesVotes
+			response.NoVotes = proposal.Results.NoVotes
+		}
+	}
+	return response
+}
+
+func (s *DAOServer) handleGetEmergencySpends(c echo.Context) error {
+	spends := s.dao.ListEmergencySpends()
+	response := make([]EmergencySpendResponse, len(spends))
+	for i, spend := range spends {
+		response[i] = s.emergencySpendResponse(spend)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetEmergencySpend(c echo.Context) error {
+	id, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	spend, exists := s.dao.GetEmergencySpend(id)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "emergency spend not found")
+	}
+
+	return c.JSON(http.StatusOK, s.emergencySpendResponse(spend))
+}
+
+// handleProposeEmergencySpend proposes an emergency spend co-signed with the
+// guardian's key. The vote opens at once and runs for the emergency voting
+// period.
+func (s *DAOServer) handleProposeEmergencySpend(c echo.Context) error {
+	var req struct {
+		Title              string         `json:"title"`
+		Description        string         `json:"description"`
+		Recipient          string         `json:"recipient"`
+		Amount             uint64         `json:"amount"`
+		Incident           string         `json:"incident"`
+		VotingType         dao.VotingType `json:"voting_type"`
+		GuardianPrivateKey string         `json:"guardian_private_key"`
+		PrivateKey         string         `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	recipient, err := publicKeyFromHex(req.Recipient)
+	if err != nil {
+		return fieldErrorResponse(c, "invalid recipient", []FieldError{{Field: "recipient", Message: "must be a hex encoded public key"}})
+	}
+	if req.GuardianPrivateKey == "" {
+		return fieldErrorResponse(c, "guardian co-signature required", []FieldError{{Field: "guardian_private_key", Message: "is required"}})
+	}
+
+	// Parse private keys
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+	guardianKey, err := privateKeyFromHex(req.GuardianPrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid guardian private key format")
+	}
+
+	votingType := req.VotingType
+	if votingType == 0 {
+		votingType = dao.VotingTypeSimple
+	}
+
+	spendTx := &dao.EmergencySpendTx{
+		Fee:         s.Config.DAO.Fees.Proposal,
+		Title:       req.Title,
+		Description: req.Description,
+		Recipient:   recipient,
+		Amount:      req.Amount,
+		Incident:    req.Incident,
+		VotingType:  votingType,
+	}
+	if err := spendTx.Sign(guardianKey); err != nil {
+		return errorMessage(c, http.StatusInternalServerError, "failed to co-sign spend")
+	}
+
+	return s.submitDAOTxWithEvent(c, spendTx, privKey, "emergency spend proposed", EventEmergencySpendProposed, map[string]interface{}{
+		"guardian":  spendTx.Guardian.String(),
+		"recipient": recipient.String(),
+		"amount":    req.Amount,
+		"incident":  req.Incident,
+	})
+}
+
+func (s *DAOServer) handleExecuteEmergencySpend(c echo.Context) error {
+	var req struct {
+		ProposalID string `json:"proposal_id"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	proposalID, err := hashFromHex(req.ProposalID)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	executeTx := &dao.EmergencyExecuteTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTxWithEvent(c, executeTx, privKey, "emergency spend execution submitted", EventEmergencySpendExecuted, map[string]interface{}{
+		"proposal_id": proposalID.String(),
+	})
+}
+
+// Participation reward endpoints
+func (s *DAOServer) handleGetParticipationRewards(c echo.Context) error {
+	config := s.dao.ParameterManager.GetParameterConfig()
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"budget":        config.ParticipationRewardBudget,
+		"voter_cap":     config.ParticipationRewardCap,
+		"epoch_length":  config.ParticipationRewardEpoch,
+		"distributed":   s.dao.GovernanceState.Treasury.ParticipationRewards,
+		"current_epoch": s.dao.TokenomicsManager.GetCurrentParticipationEpoch(),
+		"epochs":        s.dao.TokenomicsManager.GetParticipationEpochs(),
+	})
+}
+
+func (s *DAOServer) handleGetParticipationRewardStatus(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	optedInAt, optedIn := s.dao.TokenomicsManager.ParticipationOptIn(address)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"address":     address.String(),
+		"opted_in":    optedIn,
+		"opted_in_at": optedInAt,
+		"paid":        s.dao.TokenomicsManager.ParticipationRewardsPaid(address),
+	})
+}
+
+func (s *DAOServer) handleParticipationOptIn(c echo.Context) error {
+	var req struct {
+		OptIn      bool   `json:"opt_in"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	optInTx := &dao.ParticipationOptInTx{Fee: s.Config.DAO.Fees.Default, OptIn: req.OptIn}
+
+	return s.submitDAOTxWithEvent(c, optInTx, privKey, "participation reward opt-in submitted", EventParticipationOptIn, map[string]interface{}{
+		"opt_in": req.OptIn,
+	})
+}
+
+// Endorsement endpoints
+func (s *DAOServer) handleGetProposalEndorsements(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	record, exists := s.dao.GetProposalEndorsements(proposalID)
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "proposal did not need endorsements")
+	}
+
+	response := ProposalEndorsementsResponse{
+		ProposalID: proposalID.String(),
+		Status:     "awaiting",
+		Required:   record.Required,
+		Total:      record.Total,
+		Deadline:   record.Deadline,
+		Deposit:    record.Deposit,
+		Burned:     record.Burned,
+		EndorsedAt: record.EndorsedAt,
+		ExpiredAt:  record.ExpiredAt,
+		Endorsers:  make([]EndorserResponse, 0, len(record.Endorsers)),
+	}
+	if record.EndorsedAt > 0 {
+		response.Status = "endorsed"
+	} else if record.ExpiredAt > 0 {
+		response.Status = "expired"
+	}
+	for address, power := range record.Endorsers {
+		response.Endorsers = append(response.Endorsers, EndorserResponse{Address: address, Power: power})
+	}
+	sort.Slice(response.Endorsers, func(i, j int) bool {
+		if response.Endorsers[i].Power != response.Endorsers[j].Power {
+			return response.Endorsers[i].Power > response.Endorsers[j].Power
+		}
+		return response.Endorsers[i].Address < response.Endorsers[j].Address
+	})
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleEndorseProposal(c echo.Context) error {
+	proposalID, err := hashFromHex(c.Param("id"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid proposal ID format")
+	}
+
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	endorseTx := &dao.EndorseProposalTx{Fee: s.Config.DAO.Fees.Default, ProposalID: proposalID}
+
+	return s.submitDAOTxWithEvent(c, endorseTx, privKey, "proposal endorsement submitted", EventProposalEndorsed, map[string]interface{}{
+		"proposal_id": proposalID.String(),
+	})
+}
+
+// Member privacy endpoints
+func (s *DAOServer) handleGetMemberPrivacy(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
+	}
+
+	addressStr := address.String()
+	settings := s.dao.Privacy.Settings(addressStr)
+	field := func(chosen dao.Visibility, field dao.PrivacyField) map[string]string {
+		return map[string]string{
+			"setting":   chosen.String(),
+			"effective": s.dao.Privacy.Visibility(addressStr, field).String(),
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"address":    addressStr,
+		"balance":    field(settings.Balance, dao.PrivacyFieldBalance),
+		"reputation": field(settings.Reputation, dao.PrivacyFieldReputation),
+		"votes":      field(settings.Votes, dao.PrivacyFieldVotes),
+		"updated_at": settings.UpdatedAt,
+	})
+}
+
+func (s *DAOServer) handleSetMemberPrivacy(c echo.Context) error {
+	var req struct {
+		Balance    string `json:"balance"`
+		Reputation string `json:"reputation"`
+		Votes      string `json:"votes"`
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	privacyTx := &dao.PrivacySettingsTx{Fee: s.Config.DAO.Fees.Default}
+	var fieldErrors []FieldError
+	for _, field := range []struct {
+		name  string
+		value string
+		into  *dao.Visibility
+	}{
+		{"balance", req.Balance, &privacyTx.Balance},
+		{"reputation", req.Reputation, &privacyTx.Reputation},
+		{"votes", req.Votes, &privacyTx.Votes},
+	} {
+		if field.value == "" {
+			continue
+		}
+		visibility, ok := dao.ParseVisibility(field.value)
+		if !ok {
+			fieldErrors = append(fieldErrors, FieldError{Field: field.name, Message: "must be default, public, members or private"})
+			continue
+		}
+		*field.into = visibility
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrorResponse(c, "invalid privacy settings", fieldErrors)
+	}
+
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	return s.submitDAOTxWithEvent(c, privacyTx, privKey, "privacy settings submitted", EventMemberPrivacyUpdated, map[string]interface{}{
+		"balance":    privacyTx.Balance.String(),
+		"reputation": privacyTx.Reputation.String(),
+		"votes":      privacyTx.Votes.String(),
+	})
+}
+
+// Name registry endpoints
+func (s *DAOServer) handleGetNames(c echo.Context) error {
+	registrations := s.dao.Names.List()
+	response := make([]NameResponse, 0, len(registrations))
+	for _, registration := range registrations {
+		response = append(response, newNameResponse(registration))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleResolveName(c echo.Context) error {
+	registration, exists := s.dao.Names.Resolve(c.Param("name"))
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "name not registered")
+	}
+
+	return c.JSON(http.StatusOK, newNameResponse(registration))
+}
+
+func (s *DAOServer) handleRegisterName(c echo.Context) error {
+	var req struct {
+		Name       string `json:"name"`
+		PrivateKey string `json:"private_key"`
 	}
 
-	walletManager := dao.NewWalletConnectionManager()
-	err := walletManager.DisconnectWallet(address)
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
 
+	name, err := dao.NormalizeName(req.Name)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-		})
+		return fieldErrorResponse(c, "invalid name", []FieldError{{Field: "name", Message: err.(*dao.DAOError).Message}})
 	}
 
-	// Broadcast wallet disconnection event
-	event := Event{
-		Type: EventType("wallet_disconnected"),
-		Data: map[string]interface{}{
-			"address": address,
-		},
-		Timestamp: time.Now().Unix(),
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
 	}
-	s.broadcastEvent(event)
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success": true,
+	registerTx := &dao.NameRegisterTx{Fee: s.Config.DAO.Fees.Default, Name: name}
+
+	return s.submitDAOTxWithEvent(c, registerTx, privKey, "name registration submitted", EventNameRegistered, map[string]interface{}{
+		"name": name,
 	})
 }
 
-// handleGetWalletInfo handles wallet information requests
-func (s *DAOServer) handleGetWalletInfo(c echo.Context) error {
-	address := c.Param("address")
-	if address == "" {
-		return c.JSON(http.StatusBadRequest, WalletInfoResponse{
-			Success: false,
-			Error:   "Address is required",
-		})
+func (s *DAOServer) handleRenewName(c echo.Context) error {
+	name, err := dao.NormalizeName(c.Param("name"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid name")
 	}
 
-	walletManager := dao.NewWalletConnectionManager()
-	wallet, err := walletManager.GetWalletInfo(address)
+	var req struct {
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
 
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, WalletInfoResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
 	}
 
-	// Get token balance - convert address string to PublicKey
-	// For now, we'll set balance to 0 since we need proper address conversion
-	balance := uint64(0)
+	renewTx := &dao.NameRenewTx{Fee: s.Config.DAO.Fees.Default, Name: name}
 
-	return c.JSON(http.StatusOK, WalletInfoResponse{
-		Success: true,
-		Wallet:  wallet,
-		Balance: int64(balance),
+	return s.submitDAOTxWithEvent(c, renewTx, privKey, "name renewal submitted", EventNameRenewed, map[string]interface{}{
+		"name": name,
 	})
 }
 
-// handleGetActiveConnections handles requests for active wallet connections
-func (s *DAOServer) handleGetActiveConnections(c echo.Context) error {
-	// Simplified implementation for testing
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success":     true,
-		"connections": []interface{}{},
-		"count":       0,
-	})
-}
+func (s *DAOServer) handleTransferName(c echo.Context) error {
+	name, err := dao.NormalizeName(c.Param("name"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid name")
+	}
+
+	var req struct {
+		Recipient  string `json:"recipient"`
+		PrivateKey string `json:"private_key"`
+	}
 
-// handleSignTransaction handles transaction signing requests
-func (s *DAOServer) handleSignTransaction(c echo.Context) error {
-	var req TransactionSigningRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, TransactionSigningResponse{
-			Success: false,
-			Error:   "Invalid request format",
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
 	}
 
-	// Validate required fields
-	if req.Address == "" || req.Transaction == nil || req.Signature == "" {
-		return c.JSON(http.StatusBadRequest, TransactionSigningResponse{
-			Success: false,
-			Error:   "Address, transaction, and signature are required",
-		})
+	recipient, err := publicKeyFromHex(req.Recipient)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid recipient format")
 	}
 
-	walletManager := dao.NewWalletConnectionManager()
-	signedTx, err := walletManager.HandleTransactionSigning(
-		req.Address,
-		req.Transaction,
-		req.Signature,
-	)
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	transferTx := &dao.NameTransferTx{Fee: s.Config.DAO.Fees.Default, Name: name, Recipient: recipient}
+
+	return s.submitDAOTxWithEvent(c, transferTx, privKey, "name transfer submitted", EventNameTransferred, map[string]interface{}{
+		"name":      name,
+		"recipient": recipient.String(),
+	})
+}
 
+// Member profile endpoints
+func (s *DAOServer) handleGetMemberProfile(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, TransactionSigningResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
 	}
 
-	// Broadcast transaction signed event
-	event := Event{
-		Type: EventType("transaction_signed"),
-		Data: map[string]interface{}{
-			"address":         req.Address,
-			"transactionHash": signedTx.TransactionHash.String(),
-			"signingMethod":   signedTx.SigningMethod,
-		},
-		Timestamp: time.Now().Unix(),
+	addressStr := address.String()
+	if _, exists := s.dao.Profiles.Get(addressStr); !exists {
+		return errorMessage(c, http.StatusNotFound, "member has no profile")
+	}
+	profile, record, err := s.dao.GetMemberProfile(addressStr)
+	if err != nil {
+		return errorResponse(c, http.StatusBadGateway, err)
 	}
-	s.broadcastEvent(event)
 
-	return c.JSON(http.StatusOK, TransactionSigningResponse{
-		Success:           true,
-		SignedTransaction: signedTx,
+	return c.JSON(http.StatusOK, MemberProfileResponse{
+		MemberProfile: *profile,
+		Name:          s.dao.Names.NameOfAddress(addressStr),
+		ProfileHash:   record.Hash.String(),
+		PublishedAt:   record.UpdatedAt,
 	})
 }
 
-// handleBroadcastTransaction handles transaction broadcasting requests
-func (s *DAOServer) handleBroadcastTransaction(c echo.Context) error {
-	var req BroadcastTransactionRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, BroadcastTransactionResponse{
-			Success: false,
-			Error:   "Invalid request format",
-		})
+// handleSetMemberProfile signs the profile with the member's key, stores it
+// on IPFS and publishes its hash. An empty profile removes it.
+func (s *DAOServer) handleSetMemberProfile(c echo.Context) error {
+	address, err := publicKeyFromHex(c.Param("address"))
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid address format")
 	}
 
-	if req.SignedTransaction == nil {
-		return c.JSON(http.StatusBadRequest, BroadcastTransactionResponse{
-			Success: false,
-			Error:   "Signed transaction is required",
-		})
+	var req struct {
+		DisplayName string            `json:"display_name"`
+		AvatarCID   string            `json:"avatar_cid"`
+		Bio         string            `json:"bio"`
+		Links       []dao.ProfileLink `json:"links"`
+		PrivateKey  string            `json:"private_key"`
 	}
 
-	// Verify the signed transaction
-	walletService := dao.NewWalletIntegrationService()
-	err := walletService.VerifySignedTransaction(req.SignedTransaction)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, BroadcastTransactionResponse{
-			Success: false,
-			Error:   "Transaction verification failed: " + err.Error(),
-		})
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
 	}
 
-	// Create core transaction from signed DAO transaction
-	coreTx := &core.Transaction{
-		TxInner:   req.SignedTransaction.Transaction,
-		From:      crypto.PublicKey(req.SignedTransaction.Signer),
-		Signature: &req.SignedTransaction.Signature,
-		Nonce:     time.Now().Unix(),
+	profile := &dao.MemberProfile{
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		AvatarCID:   req.AvatarCID,
+		Bio:         req.Bio,
+		Links:       req.Links,
+		UpdatedAt:   time.Now().Unix(),
+	}
+	if err := profile.Validate(); err != nil {
+		daoErr := err.(*dao.DAOError)
+		return fieldErrorResponse(c, "invalid profile", []FieldError{{Field: fmt.Sprint(daoErr.Details["field"]), Message: daoErr.Message}})
 	}
 
-	// Add transaction to channel (simulating mempool)
-	s.txChan <- coreTx
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, BroadcastTransactionResponse{
-			Success: false,
-			Error:   "Failed to add transaction to mempool: " + err.Error(),
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+	if privKey.PublicKey().String() != address.String() {
+		return errorMessage(c, http.StatusForbidden, "private key does not match the member address")
 	}
 
-	// Get current block height
-	blockHeight := s.bc.Height()
-
-	// Broadcast transaction event
-	event := Event{
-		Type: EventType("transaction_broadcast"),
-		Data: map[string]interface{}{
-			"transactionHash": req.SignedTransaction.TransactionHash.String(),
-			"signer":          req.SignedTransaction.Signer.String(),
-			"blockHeight":     blockHeight,
-		},
-		Timestamp: time.Now().Unix(),
+	var profileHash types.Hash
+	if profile.DisplayName != "" || profile.AvatarCID != "" || profile.Bio != "" || len(profile.Links) > 0 {
+		if profileHash, err = s.dao.PublishMemberProfile(profile, privKey); err != nil {
+			return errorResponse(c, http.StatusBadGateway, err)
+		}
 	}
-	s.broadcastEvent(event)
 
-	return c.JSON(http.StatusOK, BroadcastTransactionResponse{
-		Success:         true,
-		TransactionHash: req.SignedTransaction.TransactionHash.String(),
-		BlockHeight:     int64(blockHeight),
+	profileTx := &dao.ProfileUpdateTx{Fee: s.Config.DAO.Fees.Default, ProfileHash: profileHash}
+
+	return s.submitDAOTxWithEvent(c, profileTx, privKey, "profile update submitted", EventMemberProfileUpdated, map[string]interface{}{
+		"profile_hash": profileHash.String(),
 	})
 }
 
-// handleVerifyTransaction handles transaction verification requests
-func (s *DAOServer) handleVerifyTransaction(c echo.Context) error {
-	var signedTx dao.SignedTransaction
-	if err := c.Bind(&signedTx); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"success": false,
-			"error":   "Invalid request format",
-		})
+// Delegate marketplace endpoints
+
+// handleSearchDelegates lists the delegates with a statement, filtered by
+// focus area and text and sorted by a scorecard metric or their fee
+func (s *DAOServer) handleSearchDelegates(c echo.Context) error {
+	query := dao.DelegateQuery{
+		Focus:     c.QueryParam("focus"),
+		Text:      c.QueryParam("q"),
+		SortBy:    c.QueryParam("sort"),
+		Ascending: c.QueryParam("order") == "asc",
+	}
+	if query.SortBy != "" {
+		known := false
+		for _, key := range dao.DelegateSortKeys {
+			known = known || key == query.SortBy
+		}
+		if !known {
+			return errorMessage(c, http.StatusBadRequest, "sort must be one of "+strings.Join(dao.DelegateSortKeys, ", "))
+		}
+	}
+	if order := c.QueryParam("order"); order != "" && order != "asc" && order != "desc" {
+		return errorMessage(c, http.StatusBadRequest, "order must be asc or desc")
 	}
 
-	walletService := dao.NewWalletIntegrationService()
-	err := walletService.VerifySignedTransaction(&signedTx)
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
 
-	if err != nil {
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"success": false,
-			"valid":   false,
-			"error":   err.Error(),
-		})
+	listings := s.dao.SearchDelegates(query)
+	total := len(listings)
+
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	delegates := make([]DelegateListingResponse, 0, end-start)
+	for _, listing := range listings[start:end] {
+		delegates = append(delegates, s.newDelegateListingResponse(listing))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success": true,
-		"valid":   true,
+		"delegates": delegates,
+		"page":      page,
+		"limit":     limit,
+		"total":     total,
 	})
 }
 
-// handleGenerateTestWallet handles test wallet generation requests
-func (s *DAOServer) handleGenerateTestWallet(c echo.Context) error {
-	_, publicKey, address, err := dao.GenerateTestWallet()
+func (s *DAOServer) handleGetDelegateListing(c echo.Context) error {
+	delegate, err := publicKeyFromHex(c.Param("address"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-		})
+		return errorMessage(c, http.StatusBadRequest, "invalid address")
 	}
 
-	// Convert to hex strings for JSON response
-	privateKeyHex := "generated_private_key" // Simplified for testing
-	publicKeyHex := hex.EncodeToString(publicKey)
-	addressHex := address.String()
+	listing, exists := s.dao.GetDelegateListing(delegate.String())
+	if !exists {
+		return errorMessage(c, http.StatusNotFound, "delegate has no statement")
+	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success":    true,
-		"privateKey": privateKeyHex,
-		"publicKey":  publicKeyHex,
-		"address":    addressHex,
-		"warning":    "This is for development only. Never use in production.",
-	})
+	return c.JSON(http.StatusOK, s.newDelegateListingResponse(listing))
 }
 
-// handleGetSupportedWallets handles requests for supported wallet providers
-func (s *DAOServer) handleGetSupportedWallets(c echo.Context) error {
-	supportedWallets := map[string]interface{}{
-		"metamask": map[string]interface{}{
-			"name":        "MetaMask",
-			"description": "Browser extension wallet",
-			"supported":   true,
-			"features":    []string{"signing", "account_management", "chain_switching"},
-		},
-		"walletconnect": map[string]interface{}{
-			"name":        "WalletConnect",
-			"description": "Connect to mobile wallets",
-			"supported":   true,
-			"features":    []string{"signing", "qr_code", "deep_linking"},
-		},
-		"manual": map[string]interface{}{
-			"name":        "Manual Key Input",
-			"description": "Direct private key input (development only)",
-			"supported":   true,
-			"features":    []string{"signing", "key_generation"},
-			"warning":     "For development use only",
-		},
-		"ledger": map[string]interface{}{
-			"name":        "Ledger Hardware Wallet",
-			"description": "Hardware wallet integration",
-			"supported":   false,
-			"features":    []string{"signing", "hardware_security"},
-			"note":        "Coming soon",
-		},
+func (s *DAOServer) handlePublishDelegateStatement(c echo.Context) error {
+	var req struct {
+		Platform   string   `json:"platform"`
+		FocusAreas []string `json:"focus_areas"`
+		Fee        uint64   `json:"fee"`
+		Withdraw   bool     `json:"withdraw"`
+		PrivateKey string   `json:"private_key"`
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success": true,
-		"wallets": supportedWallets,
-	})
-}
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
 
-// Initialize wallet integration in the DAO server
-func (s *DAOServer) initWalletIntegration() {
-	// Initialize WebSocket clients map if not already done
-	if s.wsClients == nil {
-		s.wsClients = make(map[*websocket.Conn]bool)
+	statementTx := &dao.DelegateStatementTx{Fee: s.Config.DAO.Fees.Default, Withdraw: req.Withdraw}
+	if !req.Withdraw {
+		var fieldErrors []FieldError
+		if platform := strings.TrimSpace(req.Platform); platform == "" || utf8.RuneCountInString(platform) > dao.MaxDelegatePlatformLength {
+			fieldErrors = append(fieldErrors, FieldError{Field: "platform", Message: fmt.Sprintf("must be 1 to %d characters", dao.MaxDelegatePlatformLength)})
+		}
+		focusAreas, err := dao.NormalizeFocusAreas(req.FocusAreas)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "focus_areas", Message: err.(*dao.DAOError).Message})
+		}
+		if req.Fee > 10000 {
+			fieldErrors = append(fieldErrors, FieldError{Field: "fee", Message: "must be at most 10000 basis points"})
+		}
+		if len(fieldErrors) > 0 {
+			return fieldErrorResponse(c, "invalid delegate statement", fieldErrors)
+		}
+
+		statementTx.Platform = strings.TrimSpace(req.Platform)
+		statementTx.FocusAreas = focusAreas
+		statementTx.DelegateFee = req.Fee
 	}
 
-	// Set up WebSocket upgrader
-	s.upgrader = websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
-			return true // Allow all origins for development
-		},
+	// Parse private key
+	privKey, err := privateKeyFromHex(req.PrivateKey)
+	if err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
 	}
+
+	return s.submitDAOTxWithEvent(c, statementTx, privKey, "delegate statement submitted", EventDelegateStatementUpdated, map[string]interface{}{
+		"focus_areas": statementTx.FocusAreas,
+		"fee":         statementTx.DelegateFee,
+		"withdraw":    statementTx.Withdraw,
+	})
 }
@@ -1,19 +1,35 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/gob"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/BOCK-CHAIN/BockChain/config"
 	"github.com/BOCK-CHAIN/BockChain/core"
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/logging"
 	"github.com/BOCK-CHAIN/BockChain/types"
 	"github.com/go-kit/log"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -276,6 +292,368 @@ func TestDAOServer_GetTokenSupply(t *testing.T) {
 	assert.Equal(t, uint64(10000), response["total_supply"])
 }
 
+func TestDAOServer_GetMemberActivity(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+
+	sender := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	testDAO.InitialTokenDistribution(map[string]uint64{
+		sender.String(): 10000,
+	})
+
+	for i := 0; i < 3; i++ {
+		tx := &dao.TokenTransferTx{Fee: 1, Recipient: recipient, Amount: 100}
+		require.NoError(t, testDAO.ProcessDAOTransaction(tx, sender, types.Hash{byte(i + 1)}))
+	}
+
+	// Create test request
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dao/member/"+recipient.String()+"/activity?type=token_transfer&limit=2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("address")
+	c.SetParamValues(recipient.String())
+
+	// Execute handler
+	err := server.handleGetMemberActivity(c)
+	require.NoError(t, err)
+
+	// Check response
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Activity []ActivityResponse `json:"activity"`
+		Total    int                `json:"total"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, response.Total)
+	require.Len(t, response.Activity, 2)
+	assert.Equal(t, "recipient", response.Activity[0].Role)
+	assert.Equal(t, sender.String(), response.Activity[0].Counterparty)
+}
+
+func TestDAOServer_GetBootstrapStatus(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		founder.String(): 1000,
+	}))
+	require.NoError(t, testDAO.InitializeFounderRoles([]crypto.PublicKey{founder}))
+	require.NoError(t, testDAO.StartBootstrap(&dao.BootstrapPlan{
+		Founders: []crypto.PublicKey{founder},
+		Milestones: []*dao.DecentralizationMilestone{
+			{ID: "members", Type: dao.MilestoneTypeMemberCount, Target: 10, Powers: []dao.FounderPower{dao.FounderPowerFastTrack}},
+			{ID: "deadline", Type: dao.MilestoneTypeTime, Target: time.Now().Unix() + 3600, Powers: []dao.FounderPower{dao.FounderPowerVeto, dao.FounderPowerFastTrack}},
+		},
+	}, founder))
+
+	// Create test request
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dao/bootstrap", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// Execute handler
+	err := server.handleGetBootstrapStatus(c)
+	require.NoError(t, err)
+
+	// Check response
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dao.BootstrapStatus
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.True(t, response.Active)
+	assert.Equal(t, []string{founder.String()}, response.Founders)
+	require.Len(t, response.Milestones, 2)
+	assert.Equal(t, "deadline", response.Milestones[0].ID)
+	assert.Equal(t, int64(9), response.Milestones[1].Remaining)
+}
+
+func TestDAOServer_GetDispute(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+
+	claimant := crypto.GeneratePrivateKey().PublicKey()
+	distribution := map[string]uint64{claimant.String(): 10000}
+	jurors := make([]crypto.PublicKey, 5)
+	for i := range jurors {
+		jurors[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[jurors[i].String()] = 5000
+	}
+	require.NoError(t, testDAO.InitialTokenDistribution(distribution))
+	require.NoError(t, testDAO.CreateStakingPool("jurors", "Juror Pool", 0, 100, 0))
+	for _, juror := range jurors {
+		require.NoError(t, testDAO.StakeTokens("jurors", juror, 2000, 0))
+	}
+
+	disputeID := types.Hash{0xAA}
+	disputeTx := &dao.DisputeTx{Fee: 100, DisputeType: dao.DisputeTypeGrant, Amount: 500, Description: "unpaid grant"}
+	require.NoError(t, testDAO.ProcessDAOTransaction(disputeTx, claimant, disputeID))
+
+	// Create test request
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dao/dispute/"+disputeID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(disputeID.String())
+
+	// Execute handler
+	err := server.handleGetDispute(c)
+	require.NoError(t, err)
+
+	// Check response
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response DisputeResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, "grant", response.Type)
+	assert.Equal(t, "evidence", response.Phase)
+	assert.Equal(t, claimant.String(), response.Claimant)
+	assert.Len(t, response.Jurors, 5)
+
+	// Malformed IDs are rejected instead of panicking
+	req = httptest.NewRequest(http.MethodGet, "/dao/dispute/abcd", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("abcd")
+	require.NoError(t, server.handleGetDispute(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDAOServer_GetBalanceProof(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+	bc.RegisterDAOStateMachine(testDAO)
+
+	for i := 0; i < 2; i++ {
+		prevHeader, err := bc.GetHeader(bc.Height())
+		require.NoError(t, err)
+		block, err := core.NewBlockFromPrevHeader(prevHeader, []*core.Transaction{})
+		require.NoError(t, err)
+		require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+		require.NoError(t, bc.AddBlock(block))
+	}
+
+	cfg := ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}
+	server := NewDAOServer(cfg, bc, make(chan *core.Transaction, 1), testDAO)
+
+	// Create test request
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/proof/balance/"+holder.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("address")
+	c.SetParamValues(holder.String())
+
+	// Execute handler
+	err = server.handleGetBalanceProof(c)
+	require.NoError(t, err)
+
+	// Check response
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response StateProofResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, bc.Height(), response.Height)
+	assert.Equal(t, "5000", string(response.Value))
+
+	// Rebuild the proof from the response and check it against the root
+	proof := &dao.StateProof{Key: response.Key, Value: response.Value}
+	for _, step := range response.Proof {
+		hash, err := hashFromHex(step.Hash)
+		require.NoError(t, err)
+		proof.Steps = append(proof.Steps, dao.ProofStep{Hash: hash, Left: step.Position == "left"})
+	}
+	root, err := hashFromHex(response.StateRoot)
+	require.NoError(t, err)
+	assert.True(t, proof.Verify(root))
+
+	// Addresses without a balance have nothing to prove
+	unknown := crypto.GeneratePrivateKey().PublicKey().String()
+	req = httptest.NewRequest(http.MethodGet, "/proof/balance/"+unknown, nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("address")
+	c.SetParamValues(unknown)
+	require.NoError(t, server.handleGetBalanceProof(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDAOServer_GetPublicGoodsMetrics(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	auditor := crypto.GeneratePrivateKey().PublicKey()
+	grantee := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		creator.String(): 1000,
+		auditor.String(): 1000,
+	}))
+	require.NoError(t, testDAO.InitializeFounderRoles([]crypto.PublicKey{auditor}))
+
+	proposalID := types.Hash{0x01}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{
+		ID: proposalID, Creator: creator, ProposalType: dao.ProposalTypeTreasury, Status: dao.ProposalStatusActive,
+	}
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.FundingKPITx{
+		Fee: 10, ProposalID: proposalID, Category: "education", Grantee: grantee, Amount: 800,
+		KPIs: []dao.KPITarget{{Name: "workshops", Unit: "events", Target: 4}},
+	}, creator, types.Hash{0xA1}))
+
+	testDAO.GovernanceState.Proposals[proposalID].Status = dao.ProposalStatusExecuted
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ImpactReviewTx{
+		Fee: 10, ProposalID: proposalID, Actuals: map[string]uint64{"workshops": 2},
+	}, auditor, types.Hash{0xA2}))
+
+	// Create test request
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dao/analytics/public-goods", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// Execute handler
+	err := server.handleGetPublicGoodsMetrics(c)
+	require.NoError(t, err)
+
+	// Check response
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dao.PublicGoodsAnalytics
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	require.Contains(t, response.ByCategory, "education")
+	assert.Equal(t, uint64(800), response.ByCategory["education"].TotalFunded)
+	assert.InDelta(t, 0.5, response.ByCategory["education"].CostEffectiveness, 1e-9)
+	assert.Contains(t, response.ByGrantee, grantee.String())
+
+	// The per-proposal view lists KPIs and reviews
+	req = httptest.NewRequest(http.MethodGet, "/dao/proposal/"+proposalID.String()+"/impact", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleGetProposalImpact(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var impact FundingImpactResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &impact))
+	assert.Equal(t, grantee.String(), impact.Grantee)
+	require.Len(t, impact.Reviews, 1)
+	assert.Equal(t, auditor.String(), impact.Reviews[0].Reviewer)
+	assert.InDelta(t, 0.5, impact.Achievement, 1e-9)
+}
+
+func TestDAOServer_GetVoteSponsorship(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{creator.String(): 10000}))
+	testDAO.GovernanceState.Treasury.Balance = 1000
+	testDAO.GetParameterConfig().VoteSponsorshipMaxBudget = 500
+
+	now := time.Now().Unix()
+	proposalID := types.Hash{0x5A}
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ProposalTx{
+		Fee: 100, Title: "Sponsored", Description: "Treasury covers voting fees",
+		ProposalType: dao.ProposalTypeGeneral, VotingType: dao.VotingTypeSimple,
+		StartTime: now, EndTime: now + 7*24*3600, Threshold: 5000, VoteSponsor: 300,
+	}, creator, proposalID))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dao/proposal/"+proposalID.String()+"/sponsorship", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleGetVoteSponsorship(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response VoteSponsorshipResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, uint64(300), response.Budget)
+	assert.Equal(t, uint64(300), response.Remaining)
+	assert.True(t, response.Active)
+
+	// Proposals without a sponsorship are not found
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(types.Hash{0x01}.String())
+	require.NoError(t, server.handleGetVoteSponsorship(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDAOServer_GetValidator(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+
+	validator := crypto.GeneratePrivateKey().PublicKey()
+	delegator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		validator.String(): 1000,
+		delegator.String(): 1000,
+	}))
+	require.NoError(t, testDAO.TokenomicsManager.CreateStakingPool("validator-pool", "Validator Pool", 0, 1, 0))
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ValidatorConfigTx{
+		Fee: 10, PoolID: "validator-pool", CommissionBps: 500,
+	}, validator, types.Hash{0xB1}))
+	require.NoError(t, testDAO.TokenomicsManager.StakeTokens("validator-pool", delegator, 400, 0))
+	testDAO.CollectBlockFees(validator, 200, 1)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dao/validator/"+validator.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("address")
+	c.SetParamValues(validator.String())
+	require.NoError(t, server.handleGetValidator(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response ValidatorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, validator.String(), response.Address)
+	assert.Equal(t, uint64(500), response.CommissionBps)
+	assert.Equal(t, uint64(200), response.FeesEarned)
+	assert.Equal(t, uint64(10), response.ClaimableCommission)
+	assert.Equal(t, uint64(190), response.DelegatorRewards)
+	assert.Equal(t, uint64(400), response.DelegatedStake)
+	assert.Equal(t, 1, response.DelegatorCount)
+
+	// Unregistered addresses are not found
+	other := crypto.GeneratePrivateKey().PublicKey()
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("address")
+	c.SetParamValues(other.String())
+	require.NoError(t, server.handleGetValidator(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// The listing includes the validator
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/validators", nil), rec)
+	require.NoError(t, server.handleGetValidators(c))
+	var validators []ValidatorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &validators))
+	require.Len(t, validators, 1)
+	assert.Equal(t, validator.String(), validators[0].Address)
+}
+
 func TestDAOServer_WebSocketConnection(t *testing.T) {
 	server, _, _ := setupTestDAOServer()
 
@@ -302,6 +680,72 @@ func TestDAOServer_EventBroadcast(t *testing.T) {
 	server.broadcastEvent(event)
 }
 
+func TestDAOServer_EventStream(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+
+	e := echo.New()
+	e.GET("/dao/events/stream", server.handleEventStream)
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	for i := 0; i < 3; i++ {
+		server.broadcastEvent(Event{Type: EventVoteCast, Data: map[string]interface{}{"n": i}, Timestamp: time.Now().Unix()})
+	}
+
+	// readEvent returns the id and event type of the next frame
+	readEvent := func(reader *bufio.Reader) (string, string) {
+		var id, eventType string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+			if line == "" && id != "" {
+				return id, eventType
+			}
+			if strings.HasPrefix(line, "id: ") {
+				id = strings.TrimPrefix(line, "id: ")
+			}
+			if strings.HasPrefix(line, "event: ") {
+				eventType = strings.TrimPrefix(line, "event: ")
+			}
+		}
+	}
+
+	// Resuming replays only the events after Last-Event-ID
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/dao/events/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	id, eventType := readEvent(reader)
+	assert.Equal(t, "2", id)
+	assert.Equal(t, string(EventVoteCast), eventType)
+	id, _ = readEvent(reader)
+	assert.Equal(t, "3", id)
+
+	// Live events follow the replay
+	server.broadcastEvent(Event{Type: EventProposalCreated, Timestamp: time.Now().Unix()})
+	id, eventType = readEvent(reader)
+	assert.Equal(t, "4", id)
+	assert.Equal(t, string(EventProposalCreated), eventType)
+
+	// Streams can be limited to some event types
+	filtered, err := http.Get(ts.URL + "/dao/events/stream?types=proposal_created")
+	require.NoError(t, err)
+	defer filtered.Body.Close()
+	id, _ = readEvent(bufio.NewReader(filtered.Body))
+	assert.Equal(t, "4", id)
+
+	invalid, err := http.Get(ts.URL + "/dao/events/stream?last_event_id=abc")
+	require.NoError(t, err)
+	invalid.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, invalid.StatusCode)
+}
+
 // Integration test for complete proposal flow
 func TestDAOServer_ProposalFlow(t *testing.T) {
 	server, testDAO, txChan := setupTestDAOServer()
@@ -359,3 +803,4739 @@ func TestDAOServer_ProposalFlow(t *testing.T) {
 	// Note: In a real integration test, we'd process the transaction through the DAO
 	// and then verify the proposal appears in the list
 }
+
+func TestDAOServer_QueryAtHeight(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+	bc.RegisterDAOStateMachine(testDAO)
+
+	addBlock := func() {
+		prevHeader, err := bc.GetHeader(bc.Height())
+		require.NoError(t, err)
+		block, err := core.NewBlockFromPrevHeader(prevHeader, []*core.Transaction{})
+		require.NoError(t, err)
+		require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+		require.NoError(t, bc.AddBlock(block))
+	}
+
+	addBlock()
+	proposalID := types.Hash{0x07}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, Title: "Later", Status: dao.ProposalStatusActive}
+	testDAO.TokenState.Balances.Set(holder.String(), 4000)
+	testDAO.GetParameterConfig().QuorumThreshold = 42
+	addBlock()
+
+	cfg := ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}
+	server := NewDAOServer(cfg, bc, make(chan *core.Transaction, 1), testDAO)
+	e := echo.New()
+
+	get := func(target string, handler echo.HandlerFunc, names []string, values []string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	// Balances
+	rec := get("/dao/token/balance/x?at_height=1", server.handleGetTokenBalance, []string{"address"}, []string{holder.String()})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"balance":5000}`, rec.Body.String())
+	rec = get("/dao/token/balance/x", server.handleGetTokenBalance, []string{"address"}, []string{holder.String()})
+	assert.JSONEq(t, `{"balance":4000}`, rec.Body.String())
+
+	// Proposals
+	rec = get("/dao/proposal/x?at_height=1", server.handleGetProposal, []string{"id"}, []string{proposalID.String()})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = get("/dao/proposal/x?at_height=2", server.handleGetProposal, []string{"id"}, []string{proposalID.String()})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var proposal ProposalResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &proposal))
+	assert.Equal(t, "Later", proposal.Title)
+
+	rec = get("/dao/proposals?at_height=1", server.handleGetProposals, nil, nil)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+
+	// Parameters
+	rec = get("/dao/parameters?at_height=1", server.handleGetParameters, nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var config dao.ParameterConfig
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &config))
+	assert.NotEqual(t, uint64(42), config.QuorumThreshold)
+	rec = get("/dao/parameters?at_height=2", server.handleGetParameters, nil, nil)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &config))
+	assert.Equal(t, uint64(42), config.QuorumThreshold)
+
+	// Delegations
+	rec = get("/dao/delegations?at_height=2", server.handleGetDelegations, nil, nil)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+
+	// Malformed and unknown heights
+	rec = get("/dao/parameters?at_height=abc", server.handleGetParameters, nil, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = get("/dao/parameters?at_height=99", server.handleGetParameters, nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+type staticPeerSource []PeerInfo
+
+func (p staticPeerSource) Peers() []PeerInfo { return p }
+
+func TestDAOServer_GetPeers(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetPeers(e.NewContext(httptest.NewRequest(http.MethodGet, "/network/peers", nil), rec)))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	server.SetPeerSource(staticPeerSource{
+		{Addr: "10.0.0.1:3000", Connected: true, Outgoing: true, Score: 4},
+		{Addr: "10.0.0.2:3000", Banned: true, BannedUntil: 1700000000},
+	})
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetPeers(e.NewContext(httptest.NewRequest(http.MethodGet, "/network/peers", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Connected int        `json:"connected"`
+		Known     int        `json:"known"`
+		Peers     []PeerInfo `json:"peers"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Connected)
+	assert.Equal(t, 2, response.Known)
+	assert.True(t, response.Peers[1].Banned)
+}
+
+func TestDAOServer_GetFinality(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	validator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{validator.String(): 10000}))
+	require.NoError(t, testDAO.TokenomicsManager.CreateStakingPool("validator-pool", "Validator Pool", 0, 1, 0))
+	tx := &dao.ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000}
+	require.NoError(t, testDAO.ProcessDAOTransaction(tx, validator, types.Hash{0x01}))
+	require.NoError(t, testDAO.TokenomicsManager.StakeTokens("validator-pool", validator, 2500, 0))
+	info, _ := testDAO.GetValidator(validator)
+	info.Active = true
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetFinality(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/finality", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response FinalityResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Finalized)
+	assert.Equal(t, uint64(2500), response.TotalPower)
+	require.Len(t, response.Validators, 1)
+	assert.Equal(t, validator.String(), response.Validators[0].Address)
+}
+
+func TestDAOServer_GetConfig(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	e := echo.New()
+
+	getConfig := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleGetConfig(e.NewContext(req, rec)))
+		return rec
+	}
+
+	// Disabled without an admin token
+	assert.Equal(t, http.StatusForbidden, getConfig("").Code)
+
+	server.Config.Server.AdminToken = "secret"
+	assert.Equal(t, http.StatusUnauthorized, getConfig("wrong").Code)
+
+	rec := getConfig("secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Node struct {
+			BlockTime string `json:"block_time"`
+		} `json:"node"`
+		DAO struct {
+			Fees struct {
+				Vote int64 `json:"vote"`
+			} `json:"fees"`
+		} `json:"dao"`
+		Server struct {
+			AdminToken string `json:"admin_token"`
+		} `json:"server"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "5s", response.Node.BlockTime)
+	assert.Equal(t, int64(500), response.DAO.Fees.Vote)
+	assert.Equal(t, "[redacted]", response.Server.AdminToken)
+}
+
+func TestDAOServer_CheckOrigin(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	server.Config.Server.AllowedOrigins = []string{"https://app.example.com"}
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	assert.True(t, server.checkOrigin(req))
+
+	req.Header.Set("Origin", "https://app.example.com")
+	assert.True(t, server.checkOrigin(req))
+
+	req.Header.Set("Origin", "https://evil.example.com")
+	assert.False(t, server.checkOrigin(req))
+}
+
+func TestDAOServer_GetSnapshot(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+	bc.RegisterDAOStateMachine(testDAO)
+
+	prevHeader, err := bc.GetHeader(bc.Height())
+	require.NoError(t, err)
+	block, err := core.NewBlockFromPrevHeader(prevHeader, []*core.Transaction{})
+	require.NoError(t, err)
+	require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+	require.NoError(t, bc.AddBlock(block))
+
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, bc, make(chan *core.Transaction, 1), testDAO)
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetSnapshot(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/snapshot", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response SnapshotResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, uint32(1), response.Height)
+
+	stateRoot, err := bc.GetDAOStateRoot(1)
+	require.NoError(t, err)
+	assert.Equal(t, stateRoot.String(), response.StateRoot)
+
+	keys := []string{}
+	for _, entry := range response.Entries {
+		keys = append(keys, entry.Key)
+	}
+	assert.Contains(t, keys, dao.BalanceStateKey(holder.String()))
+	assert.Contains(t, keys, dao.ParametersStateKey)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetSnapshot(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/snapshot?at_height=9", nil), rec)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDAOServer_Sync(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+	bc.RegisterDAOStateMachine(testDAO)
+
+	addBlock := func() {
+		prevHeader, err := bc.GetHeader(bc.Height())
+		require.NoError(t, err)
+		block, err := core.NewBlockFromPrevHeader(prevHeader, []*core.Transaction{})
+		require.NoError(t, err)
+		require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+		require.NoError(t, bc.AddBlock(block))
+	}
+
+	// Height 1 adds a proposal, height 2 only votes on it and height 3
+	// changes the holder's balance
+	proposalID := types.Hash{1}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{
+		ID:      proposalID,
+		Creator: holder,
+		Title:   "Budget",
+		Status:  dao.ProposalStatusActive,
+		Results: &dao.VoteResults{},
+	}
+	addBlock()
+	testDAO.GovernanceState.Proposals[proposalID].Results = &dao.VoteResults{YesVotes: 10, TotalVoters: 1}
+	addBlock()
+	testDAO.TokenState.Balances.Set(holder.String(), 4000)
+	addBlock()
+
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, bc, make(chan *core.Transaction, 1), testDAO)
+	e := echo.New()
+
+	fetch := func(query string) (int, SyncResponse) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleSync(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/sync"+query, nil), rec)))
+
+		var response SyncResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec.Code, response
+	}
+
+	status, full := fetch("?address=" + hex.EncodeToString(holder))
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, full.Full)
+	assert.Equal(t, uint32(3), full.Height)
+	require.Len(t, full.Proposals, 1)
+	require.NotNil(t, full.Balance)
+	assert.Equal(t, uint64(4000), full.Balance.Balance)
+	assert.NotNil(t, full.Treasury)
+
+	// New proposals are sent whole
+	_, delta := fetch("?since=0")
+	assert.False(t, delta.Full)
+	require.Len(t, delta.Proposals, 1)
+	assert.Equal(t, "Budget", delta.Proposals[0].Title)
+	assert.Empty(t, delta.Tallies)
+
+	// Votes alone only send the tally, unchanged balances are left out
+	_, delta = fetch("?since=1&address=" + hex.EncodeToString(holder))
+	assert.Empty(t, delta.Proposals)
+	require.Len(t, delta.Tallies, 1)
+	assert.Equal(t, proposalID.String(), delta.Tallies[0].ProposalID)
+	assert.Equal(t, uint64(10), delta.Tallies[0].Results.YesVotes)
+	require.NotNil(t, delta.Balance)
+	assert.Equal(t, uint64(4000), delta.Balance.Balance)
+
+	_, delta = fetch("?since=3&address=" + hex.EncodeToString(holder))
+	assert.Empty(t, delta.Tallies)
+	assert.Nil(t, delta.Balance)
+	assert.Nil(t, delta.Treasury)
+
+	status, _ = fetch("?since=9")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = fetch("?since=abc")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestDAOServer_DeriveAccounts(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, make(chan *core.Transaction, 1), testDAO)
+	e := echo.New()
+
+	post := func(handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := post(server.handleGenerateMnemonic, `{"words":24}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var mnemonic MnemonicResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &mnemonic))
+	assert.Len(t, strings.Fields(mnemonic.Mnemonic), 24)
+	assert.NoError(t, crypto.ValidateMnemonic(mnemonic.Mnemonic))
+
+	assert.Equal(t, http.StatusBadRequest, post(server.handleGenerateMnemonic, `{"words":13}`).Code)
+
+	rec = post(server.handleDeriveAccounts, `{"mnemonic":"`+mnemonic.Mnemonic+`","start":2,"count":2}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var derived DeriveAccountsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &derived))
+	require.Len(t, derived.Accounts, 2)
+	assert.Equal(t, uint32(2), derived.Accounts[0].Index)
+
+	_, _, address, err := dao.GenerateTestWalletFromMnemonic(mnemonic.Mnemonic, "", 3)
+	require.NoError(t, err)
+	assert.Equal(t, address.String(), derived.Accounts[1].Address)
+
+	assert.Equal(t, http.StatusBadRequest, post(server.handleDeriveAccounts, `{"mnemonic":"abandon abandon"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(server.handleDeriveAccounts, `{"mnemonic":"`+mnemonic.Mnemonic+`","count":101}`).Code)
+}
+
+func TestDAOServer_Multisig(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	owners := []crypto.PublicKey{crypto.GeneratePrivateKey().PublicKey(), crypto.GeneratePrivateKey().PublicKey()}
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{owners[0].String(): 5000, owners[1].String(): 5000}))
+
+	multisigID := types.Hash{0x01}
+	create := &dao.MultisigCreateTx{Fee: 10, Name: "Core team", Owners: owners, Threshold: 2}
+	require.NoError(t, testDAO.ApplyDAOTransaction(create, owners[0], multisigID, 1))
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	e := echo.New()
+
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	body := `{"description":"Pay the auditor","action":{"type":"token_transfer","recipient":"` + recipient.String() + `","amount":250},"private_key":"` + hex.EncodeToString(crypto.GeneratePrivateKey().Bytes()) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(multisigID.String())
+	require.NoError(t, server.handleSubmitMultisigTx(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	tx := <-txChan
+	submit, ok := tx.TxInner.(*dao.MultisigSubmitTx)
+	require.True(t, ok)
+	assert.Equal(t, multisigID, submit.MultisigID)
+	transfer, ok := submit.Tx.(*dao.TokenTransferTx)
+	require.True(t, ok)
+	assert.Equal(t, uint64(250), transfer.Amount)
+
+	// Apply it as the chain would and look the multisig up
+	require.NoError(t, testDAO.ApplyDAOTransaction(submit, owners[1], types.Hash{0x02}, 2))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(multisigID.String())
+	require.NoError(t, server.handleGetMultisig(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response MultisigResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Core team", response.Name)
+	assert.Equal(t, dao.MultisigAccountKey(multisigID).String(), response.Account)
+	require.Len(t, response.Transactions, 1)
+	assert.Equal(t, dao.ActivityTypeTokenTransfer, response.Transactions[0].Type)
+	assert.Equal(t, []string{owners[1].String()}, response.Transactions[0].Approvals)
+	assert.False(t, response.Transactions[0].Executed)
+
+	// Unsupported actions are rejected before anything is submitted
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"action":{"type":"dispute"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(multisigID.String())
+	require.NoError(t, server.handleSubmitMultisigTx(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/?owner="+owners[0].String(), nil), rec)
+	require.NoError(t, server.handleGetMultisigs(c))
+	var accounts []MultisigResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &accounts))
+	assert.Len(t, accounts, 1)
+}
+
+func TestDAOServer_SubDAO(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	members := []crypto.PublicKey{crypto.GeneratePrivateKey().PublicKey(), crypto.GeneratePrivateKey().PublicKey()}
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{members[0].String(): 5000, members[1].String(): 5000}))
+
+	now := time.Now().Unix()
+	proposalID := types.Hash{0x01}
+	create := &dao.SubDAOCreateProposalTx{
+		Fee:        100,
+		Name:       "Infra guild",
+		Members:    members,
+		VotingType: dao.VotingTypeSimple,
+		StartTime:  now + 60,
+		EndTime:    now + 7*86400,
+		Threshold:  5100,
+	}
+	require.NoError(t, testDAO.ApplyDAOTransaction(create, members[0], proposalID, 1))
+	proposal, err := testDAO.GetProposal(proposalID)
+	require.NoError(t, err)
+	proposal.Status = dao.ProposalStatusPassed
+	require.NoError(t, testDAO.ApplyDAOTransaction(&dao.SubDAOCreateExecuteTx{Fee: 10, ProposalID: proposalID}, members[0], types.Hash{0x02}, 2))
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	e := echo.New()
+
+	newcomer := crypto.GeneratePrivateKey().PublicKey()
+	body := `{"title":"Add a maintainer","action":"add_member","target":"` + newcomer.String() + `","duration":86400,"private_key":"` + hex.EncodeToString(crypto.GeneratePrivateKey().Bytes()) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleCreateSubDAOProposal(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	tx := <-txChan
+	subProposal, ok := tx.TxInner.(*dao.SubDAOProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, dao.SubDAOActionAddMember, subProposal.Action)
+	require.NoError(t, testDAO.ApplyDAOTransaction(subProposal, members[1], types.Hash{0x03}, 3))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleGetSubDAO(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response SubDAOResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Infra guild", response.Name)
+	assert.Len(t, response.Members, 2)
+	require.Len(t, response.Proposals, 1)
+	assert.Equal(t, "add_member", response.Proposals[0].Action)
+	assert.Equal(t, "active", response.Proposals[0].Status)
+
+	// Unknown actions are rejected before anything is submitted
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":"x","action":"dissolve"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleCreateSubDAOProposal(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	require.NoError(t, server.handleGetSubDAOAnalytics(c))
+	var analytics dao.SubDAOAnalytics
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &analytics))
+	assert.Equal(t, uint64(1), analytics.TotalSubDAOs)
+	assert.Equal(t, uint64(1), analytics.SubDAOs[0].ActiveProposals)
+}
+
+func TestDAOServer_Grants(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	applicant := crypto.GeneratePrivateKey().PublicKey()
+	committee := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{applicant.String(): 5000}))
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	e := echo.New()
+
+	now := time.Now().Unix()
+	body := fmt.Sprintf(`{"title":"Indexer","milestones":[{"title":"Schema","amount":400},{"title":"Launch","amount":600}],"committee":"%s","voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":"%s"}`,
+		committee.String(), now+60, now+7*86400, hex.EncodeToString(crypto.GeneratePrivateKey().Bytes()))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleProposeGrant(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	tx := <-txChan
+	proposal, ok := tx.TxInner.(*dao.GrantProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1000), proposal.Total())
+	grantID := types.Hash{0x01}
+	require.NoError(t, testDAO.ApplyDAOTransaction(proposal, applicant, grantID, 1))
+
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(grantID.String())
+	require.NoError(t, server.handleGetGrant(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response GrantResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "proposed", response.Status)
+	assert.Equal(t, applicant.String(), response.Applicant)
+	require.Len(t, response.Milestones, 2)
+	assert.Equal(t, "pending", response.Milestones[1].Status)
+
+	// Milestone routes need a numeric index
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"deliverable":"ipfs://schema"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id", "index")
+	c.SetParamValues(grantID.String(), "first")
+	require.NoError(t, server.handleSubmitGrantMilestone(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetGrantPipeline(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+	var pipeline dao.GrantPipeline
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pipeline))
+	assert.Equal(t, uint64(1), pipeline.Proposed)
+	assert.Equal(t, uint64(1000), pipeline.Requested)
+}
+
+func TestDAOServer_Bounties(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	reviewer := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{reviewer.String(): 5000}))
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	// Capture broadcasts instead of fanning them out to WebSocket clients
+	events := make(chan []byte, 1)
+	server.eventBus = &EventBus{broadcast: events}
+	e := echo.New()
+
+	now := time.Now().Unix()
+	body := fmt.Sprintf(`{"title":"Fix the sync bug","reward":800,"reviewer":"%s","claim_period":604800,"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":"%s"}`,
+		reviewer.String(), now+60, now+7*86400, hex.EncodeToString(crypto.GeneratePrivateKey().Bytes()))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleProposeBounty(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventBountyProposed, event.Type)
+	assert.NotEmpty(t, event.Data.(map[string]interface{})["tx_hash"])
+
+	tx := <-txChan
+	proposal, ok := tx.TxInner.(*dao.BountyProposalTx)
+	require.True(t, ok)
+	bountyID := types.Hash{0x01}
+	require.NoError(t, testDAO.ApplyDAOTransaction(proposal, reviewer, bountyID, 1))
+
+	// Reviews broadcast whether the work was approved or disputed
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"approve":false,"comment":"Tests are missing","private_key":"`+hex.EncodeToString(crypto.GeneratePrivateKey().Bytes())+`"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(bountyID.String())
+	require.NoError(t, server.handleReviewBounty(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	<-txChan
+
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventBountyDisputed, event.Type)
+	assert.Equal(t, bountyID.String(), event.Data.(map[string]interface{})["bounty_id"])
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(bountyID.String())
+	require.NoError(t, server.handleGetBounty(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response BountyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "proposed", response.Status)
+	assert.Equal(t, uint64(800), response.Reward)
+	assert.Empty(t, response.Claimant)
+}
+
+func TestDAOServer_MetadataSchemas(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetMetadataSchemas(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/metadata/schemas", nil), rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"current": 2, "versions": [1, 2]}`, rec.Body.String())
+
+	getSchema := func(version string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/metadata/schemas/"+version, nil), rec)
+		c.SetParamNames("version")
+		c.SetParamValues(version)
+		require.NoError(t, server.handleGetMetadataSchema(c))
+		return rec
+	}
+
+	rec = getSchema("2")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schema))
+	assert.Equal(t, "https://bockchain.io/schemas/proposal-metadata/2", schema["$id"])
+	assert.Contains(t, schema["required"], "schema_version")
+
+	assert.Equal(t, http.StatusNotFound, getSchema("9").Code)
+	assert.Equal(t, http.StatusBadRequest, getSchema("latest").Code)
+}
+
+func TestDAOServer_GetProposalMetadata(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	testDAO.IPFSClient = dao.NewIPFSClient("127.0.0.1:1")
+	cache, err := dao.NewMetadataCache(dao.MetadataCacheConfig{})
+	require.NoError(t, err)
+	testDAO.IPFSClient.EnableMetadataCache(cache)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	cached, uncached, bare := types.Hash{0x01}, types.Hash{0x02}, types.Hash{0x03}
+	for i, id := range []types.Hash{cached, uncached, bare} {
+		proposal := &dao.Proposal{ID: id, Creator: creator, Status: dao.ProposalStatusActive}
+		if id != bare {
+			proposal.MetadataHash = types.Hash{0xA0, byte(i)}
+		}
+		testDAO.GovernanceState.Proposals[id] = proposal
+	}
+	cache.Put(types.Hash{0xA0, 0}, &dao.ProposalMetadata{Title: "Budget", Description: "Q3", SchemaVersion: 2})
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/dao/proposal/"+id+"/metadata", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleGetProposalMetadata(c))
+		return rec
+	}
+
+	rec := get(cached.String())
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var metadata dao.ProposalMetadata
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metadata))
+	assert.Equal(t, "Budget", metadata.Title)
+
+	// Uncached metadata needs IPFS, which is unreachable
+	assert.Equal(t, http.StatusBadGateway, get(uncached.String()).Code)
+	assert.Equal(t, http.StatusNotFound, get(bare.String()).Code)
+	assert.Equal(t, http.StatusNotFound, get(types.Hash{0x09}.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, get("zz").Code)
+}
+
+// signMemberRequest signs a member request the way clients do
+func signMemberRequest(t *testing.T, key crypto.PrivateKey, action string, timestamp int64, payload []byte) string {
+	signature, err := key.Sign(memberRequestDigest(action, key.PublicKey().String(), timestamp, payload))
+	require.NoError(t, err)
+
+	encoded := make([]byte, 64)
+	signature.R.FillBytes(encoded[:32])
+	signature.S.FillBytes(encoded[32:])
+	return hex.EncodeToString(encoded)
+}
+
+func TestDAOServer_NotificationPreferences(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	key := crypto.GeneratePrivateKey()
+	address := key.PublicKey().String()
+	testDAO.GovernanceState.TokenHolders[address] = &dao.TokenHolder{Address: key.PublicKey(), Balance: 100}
+
+	put := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/dao/notifications/preferences", bytes.NewReader(data))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleSetNotificationPreferences(e.NewContext(req, rec)))
+		return rec
+	}
+	query := func(method, action string, handler echo.HandlerFunc, signer crypto.PrivateKey) *httptest.ResponseRecorder {
+		now := time.Now().Unix()
+		target := fmt.Sprintf("/dao/notifications/preferences/%s?timestamp=%d&signature=%s", address, now, signMemberRequest(t, signer, action, now, nil))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(method, target, nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(address)
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	preferences := `{"channels":["email"],"events":["proposal_created"],"email":"member@example.com"}`
+	now := time.Now().Unix()
+	rec := put(map[string]interface{}{
+		"address":     address,
+		"preferences": json.RawMessage(preferences),
+		"timestamp":   now,
+		"signature":   signMemberRequest(t, key, "set_notification_preferences", now, []byte(preferences)),
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Signatures bind the preferences, the time and the key
+	tampered := `{"channels":["email"],"email":"attacker@example.com"}`
+	assert.Equal(t, http.StatusUnauthorized, put(map[string]interface{}{
+		"address":     address,
+		"preferences": json.RawMessage(tampered),
+		"timestamp":   now,
+		"signature":   signMemberRequest(t, key, "set_notification_preferences", now, []byte(preferences)),
+	}).Code)
+	stale := now - 3600
+	assert.Equal(t, http.StatusUnauthorized, put(map[string]interface{}{
+		"address":     address,
+		"preferences": json.RawMessage(preferences),
+		"timestamp":   stale,
+		"signature":   signMemberRequest(t, key, "set_notification_preferences", stale, []byte(preferences)),
+	}).Code)
+
+	// Only token holders subscribe
+	outsider := crypto.GeneratePrivateKey()
+	assert.Equal(t, http.StatusForbidden, put(map[string]interface{}{
+		"address":     outsider.PublicKey().String(),
+		"preferences": json.RawMessage(preferences),
+		"timestamp":   now,
+		"signature":   signMemberRequest(t, outsider, "set_notification_preferences", now, []byte(preferences)),
+	}).Code)
+
+	invalid := `{"channels":["sms"]}`
+	assert.Equal(t, http.StatusBadRequest, put(map[string]interface{}{
+		"address":     address,
+		"preferences": json.RawMessage(invalid),
+		"timestamp":   now,
+		"signature":   signMemberRequest(t, key, "set_notification_preferences", now, []byte(invalid)),
+	}).Code)
+
+	rec = query(http.MethodGet, "get_notification_preferences", server.handleGetNotificationPreferences, key)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stored dao.NotificationPreferences
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stored))
+	assert.Equal(t, "member@example.com", stored.Email)
+	assert.Equal(t, address, stored.Member)
+
+	// Preferences are private to the member
+	assert.Equal(t, http.StatusUnauthorized, query(http.MethodGet, "get_notification_preferences", server.handleGetNotificationPreferences, outsider).Code)
+
+	// Events on the bus reach subscribed members
+	email := &recordingNotifier{}
+	testDAO.Notifications.RegisterNotifier(email)
+	server.eventBus.publish(Event{Type: EventProposalCreated, Data: map[string]interface{}{"title": "Budget"}, Timestamp: now})
+	server.eventBus.publish(Event{Type: EventVoteCast, Timestamp: now})
+	testDAO.EnableNotifications()
+	defer testDAO.Notifications.Stop()
+	assert.Eventually(t, func() bool { return email.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, http.StatusNoContent, query(http.MethodDelete, "delete_notification_preferences", server.handleDeleteNotificationPreferences, key).Code)
+	assert.Equal(t, http.StatusNotFound, query(http.MethodGet, "get_notification_preferences", server.handleGetNotificationPreferences, key).Code)
+}
+
+// recordingNotifier counts the emails it is asked to send
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent int
+}
+
+func (n *recordingNotifier) Name() string { return dao.NotificationChannelEmail }
+
+func (n *recordingNotifier) Send(recipient string, notification *dao.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent++
+	return nil
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.sent
+}
+
+func TestDAOServer_Webhooks(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	server.Config.Server.AdminToken = "secret"
+	e := echo.New()
+
+	var mu sync.Mutex
+	var received []string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r.Header.Get(dao.WebhookEventHeader))
+	}))
+	defer endpoint.Close()
+
+	call := func(method, target, token string, body string, id string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	// Registering integrators needs the admin token
+	body := fmt.Sprintf(`{"url": %q, "events": ["proposal_created"]}`, endpoint.URL)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPost, "/dao/webhooks", "wrong", body, "", server.handleCreateWebhook).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/dao/webhooks", "secret", `{"url": "not a url"}`, "", server.handleCreateWebhook).Code)
+
+	rec := call(http.MethodPost, "/dao/webhooks", "secret", body, "", server.handleCreateWebhook)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var subscription dao.WebhookSubscription
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &subscription))
+	assert.NotEmpty(t, subscription.Secret)
+
+	rec = call(http.MethodGet, "/dao/webhooks", "secret", "", "", server.handleGetWebhooks)
+	assert.NotContains(t, rec.Body.String(), subscription.Secret)
+
+	// Bus events are delivered to matching subscriptions
+	server.eventBus.publish(Event{Type: EventVoteCast, Timestamp: time.Now().Unix()})
+	server.eventBus.publish(Event{Type: EventProposalCreated, Data: map[string]interface{}{"title": "Budget"}, Timestamp: time.Now().Unix()})
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 1 && received[0] == string(EventProposalCreated)
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		logged, _ := testDAO.Webhooks.GetDeliveries(subscription.ID)
+		return len(logged) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	rec = call(http.MethodPost, "/dao/webhooks/"+subscription.ID+"/test", "secret", "", subscription.ID, server.handleTestWebhook)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var delivery dao.WebhookDelivery
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &delivery))
+	assert.True(t, delivery.Succeeded)
+
+	rec = call(http.MethodGet, "/dao/webhooks/"+subscription.ID+"/deliveries", "secret", "", subscription.ID, server.handleGetWebhookDeliveries)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var deliveries []dao.WebhookDelivery
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deliveries))
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, dao.WebhookPingEvent, deliveries[0].EventType)
+
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/dao/webhooks/"+subscription.ID, "secret", "", subscription.ID, server.handleDeleteWebhook).Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/dao/webhooks/"+subscription.ID, "secret", "", subscription.ID, server.handleGetWebhook).Code)
+	testDAO.Webhooks.Stop()
+}
+
+// memoryContentStore keeps content in memory under its SHA-256
+type memoryContentStore struct {
+	mu      sync.Mutex
+	content map[string][]byte
+}
+
+func (s *memoryContentStore) Name() string { return "memory" }
+
+func (s *memoryContentStore) Put(data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	digest := sha256.Sum256(data)
+	id := hex.EncodeToString(digest[:])
+	s.content[id] = append([]byte(nil), data...)
+	return id, nil
+}
+
+func (s *memoryContentStore) Get(id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, exists := s.content[id]
+	if !exists {
+		return nil, fmt.Errorf("content %s not found", id)
+	}
+	return data, nil
+}
+
+func (s *memoryContentStore) Pin(id string) error   { return nil }
+func (s *memoryContentStore) Unpin(id string) error { return nil }
+
+func TestDAOServer_Comments(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	testDAO.IPFSClient = dao.NewIPFSClientWithStore(&memoryContentStore{content: make(map[string][]byte)})
+
+	author := crypto.GeneratePrivateKey()
+	moderator := crypto.GeneratePrivateKey()
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		author.PublicKey().String():    1000,
+		moderator.PublicKey().String(): 1000,
+	}))
+	require.NoError(t, testDAO.InitializeFounderRoles([]crypto.PublicKey{founder}))
+	require.NoError(t, testDAO.GrantRole(moderator.PublicKey(), dao.RoleModerator, founder, 0))
+
+	proposalID := types.Hash{0xC0}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, Creator: author.PublicKey(), Status: dao.ProposalStatusActive}
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	events := make(chan []byte, 1)
+	server.eventBus = &EventBus{broadcast: events}
+	e := echo.New()
+
+	call := func(method, body, id string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	rec := call(http.MethodPost, `{"body":"Can we halve the budget?","private_key":"`+hex.EncodeToString(author.Bytes())+`"}`, proposalID.String(), server.handlePostComment)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var event Event
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventCommentPosted, event.Type)
+
+	// The transaction carries the hash of the body stored on IPFS
+	tx := <-txChan
+	commentTx, ok := tx.TxInner.(*dao.CommentTx)
+	require.True(t, ok)
+	assert.Equal(t, author.PublicKey(), tx.From)
+	commentID := types.Hash{0x01}
+	require.NoError(t, testDAO.ApplyDAOTransaction(commentTx, author.PublicKey(), commentID, 1))
+
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, `{"body":" ","private_key":"`+hex.EncodeToString(author.Bytes())+`"}`, proposalID.String(), server.handlePostComment).Code)
+
+	// Reactions are signed by the member
+	now := time.Now().Unix()
+	payload := commentID.String() + ":heart:add"
+	rec = call(http.MethodPost, fmt.Sprintf(`{"address":"%s","reaction":"heart","timestamp":%d,"signature":"%s"}`,
+		author.PublicKey().String(), now, signMemberRequest(t, author, "react_comment", now, []byte(payload))), commentID.String(), server.handleReactToComment)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventCommentReacted, event.Type)
+
+	rec = call(http.MethodPost, fmt.Sprintf(`{"address":"%s","reaction":"eyes","timestamp":%d,"signature":"%s"}`,
+		author.PublicKey().String(), now, signMemberRequest(t, author, "react_comment", now, []byte(payload))), commentID.String(), server.handleReactToComment)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	get := func() CommentResponse {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?limit=10", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(proposalID.String())
+		require.NoError(t, server.handleGetProposalComments(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Comments []CommentResponse `json:"comments"`
+			Total    int               `json:"total"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Equal(t, 1, response.Total)
+		return response.Comments[0]
+	}
+
+	comment := get()
+	assert.Equal(t, "Can we halve the budget?", comment.Body)
+	assert.Equal(t, map[string]int{"heart": 1}, comment.Reactions)
+
+	// Only moderators can hide comments
+	moderate := func(key crypto.PrivateKey) *httptest.ResponseRecorder {
+		now := time.Now().Unix()
+		payload := commentID.String() + ":true:spam"
+		return call(http.MethodPost, fmt.Sprintf(`{"address":"%s","hidden":true,"reason":"spam","timestamp":%d,"signature":"%s"}`,
+			key.PublicKey().String(), now, signMemberRequest(t, key, "moderate_comment", now, []byte(payload))), commentID.String(), server.handleModerateComment)
+	}
+	assert.Equal(t, http.StatusForbidden, moderate(author).Code)
+	require.Equal(t, http.StatusOK, moderate(moderator).Code)
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventCommentModerated, event.Type)
+
+	comment = get()
+	assert.True(t, comment.Hidden)
+	assert.Empty(t, comment.Body)
+}
+
+func TestDAOServer_ProposalTemplates(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	server.eventBus = &EventBus{broadcast: make(chan []byte, 1)}
+
+	creator := crypto.GeneratePrivateKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		creator.PublicKey().String(): 10000,
+	}))
+	testDAO.GovernanceState.Treasury.Balance = 5000
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	e := echo.New()
+	call := func(method, body string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := call(http.MethodGet, "", server.handleGetProposalTemplates)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var templates struct {
+		Templates []dao.ProposalTemplate `json:"templates"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &templates))
+	require.Len(t, templates.Templates, 3)
+	assert.Equal(t, dao.TemplateMembership, templates.Templates[0].ID)
+	assert.NotEmpty(t, templates.Templates[0].Fields)
+
+	draft := func(amount string) string {
+		return fmt.Sprintf(`{"creator":"%s","template":"treasury_request","fields":{"recipient":"%s","amount":"%s","purpose":"Audit"},`+
+			`"title":"Security audit","description":"Fund the audit","voting_type":1,"duration":86400,"threshold":5100}`,
+			creator.PublicKey().String(), recipient.String(), amount)
+	}
+
+	rec = call(http.MethodPost, draft("1000"), server.handleValidateProposal)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response ProposalDraftResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Valid)
+	assert.Empty(t, response.Issues)
+	require.NotNil(t, response.Proposal)
+	assert.Equal(t, "Treasury request: Security audit", response.Proposal.Title)
+	assert.Equal(t, dao.ProposalTypeTreasury, response.Proposal.ProposalType)
+
+	rec = call(http.MethodPost, draft("9000"), server.handleValidateProposal)
+	require.Equal(t, http.StatusOK, rec.Code)
+	response = ProposalDraftResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Valid)
+	require.Len(t, response.Issues, 1)
+	assert.Equal(t, "amount", response.Issues[0].Field)
+	assert.Nil(t, response.Proposal)
+
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, `{"creator":"nothex"}`, server.handleValidateProposal).Code)
+
+	// Creating from a template submits the rendered proposal
+	privateKey := hex.EncodeToString(creator.Bytes())
+	rec = call(http.MethodPost, fmt.Sprintf(`{"template":"treasury_request","fields":{"recipient":"%s","amount":"1000","purpose":"Audit"},`+
+		`"title":"Security audit","description":"Fund the audit","voting_type":1,"duration":86400,"threshold":5100,"private_key":"%s"}`,
+		recipient.String(), privateKey), server.handleCreateProposal)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	proposalTx, ok := (<-txChan).TxInner.(*dao.ProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, dao.ProposalTypeTreasury, proposalTx.ProposalType)
+	assert.Contains(t, proposalTx.Description, "Recipient: "+recipient.String())
+
+	rec = call(http.MethodPost, fmt.Sprintf(`{"template":"treasury_request","fields":{"amount":"1000"},"title":"Audit","private_key":"%s"}`,
+		privateKey), server.handleCreateProposal)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, txChan)
+}
+
+func TestDAOServer_Drafts(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	server.eventBus = &EventBus{broadcast: make(chan []byte, 1)}
+	testDAO.IPFSClient = dao.NewIPFSClientWithStore(&memoryContentStore{content: make(map[string][]byte)})
+
+	owner := crypto.GeneratePrivateKey()
+	coAuthor := crypto.GeneratePrivateKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		owner.PublicKey().String():    10000,
+		coAuthor.PublicKey().String(): 1000,
+	}))
+
+	e := echo.New()
+	call := func(method, target, body, id string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, handler(c))
+		return rec
+	}
+	query := func(key crypto.PrivateKey, action, id string, payload []byte) string {
+		now := time.Now().Unix()
+		return fmt.Sprintf("/?address=%s&timestamp=%d&signature=%s", key.PublicKey().String(), now, signMemberRequest(t, key, action, now, payload))
+	}
+	decode := func(rec *httptest.ResponseRecorder) DraftResponse {
+		var draft DraftResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &draft))
+		return draft
+	}
+
+	now := time.Now().Unix()
+	content := `{"title":"Roadmap","proposal_type":1,"voting_type":1,"duration":86400,"threshold":5100}`
+	rec := call(http.MethodPost, "/", fmt.Sprintf(`{"address":"%s","draft":%s,"timestamp":%d,"signature":"%s"}`,
+		owner.PublicKey().String(), content, now, signMemberRequest(t, owner, "create_draft", now, []byte(content))), "", server.handleCreateDraft)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	draft := decode(rec)
+	assert.Equal(t, uint64(1), draft.Version)
+
+	// Drafts are private to their authors
+	rec = call(http.MethodGet, query(coAuthor, "get_draft", draft.ID, []byte(draft.ID)), "", draft.ID, server.handleGetDraft)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	payload := fmt.Sprintf("%s:%s:add", draft.ID, coAuthor.PublicKey().String())
+	rec = call(http.MethodPost, "/", fmt.Sprintf(`{"address":"%s","co_author":"%s","timestamp":%d,"signature":"%s"}`,
+		owner.PublicKey().String(), coAuthor.PublicKey().String(), now, signMemberRequest(t, owner, "manage_draft_coauthors", now, []byte(payload))), draft.ID, server.handleManageDraftCoAuthor)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{coAuthor.PublicKey().String()}, decode(rec).CoAuthors)
+
+	rec = call(http.MethodGet, query(coAuthor, "list_drafts", "", nil), "", "", server.handleGetDrafts)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Drafts []DraftResponse `json:"drafts"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Drafts, 1)
+
+	// Co-authors edit, stale versions conflict
+	edit := func(key crypto.PrivateKey, version uint64, content string) *httptest.ResponseRecorder {
+		payload := append([]byte(fmt.Sprintf("%s:%d:", draft.ID, version)), content...)
+		return call(http.MethodPut, "/", fmt.Sprintf(`{"address":"%s","draft":%s,"version":%d,"timestamp":%d,"signature":"%s"}`,
+			key.PublicKey().String(), content, version, now, signMemberRequest(t, key, "edit_draft", now, payload)), draft.ID, server.handleUpdateDraft)
+	}
+	content = `{"title":"Roadmap","description":"Ship the mobile app","proposal_type":1,"voting_type":1,"duration":86400,"threshold":5100}`
+	rec = edit(coAuthor, 1, content)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, uint64(2), decode(rec).Version)
+	assert.Equal(t, http.StatusConflict, edit(owner, 1, content).Code)
+
+	rec = call(http.MethodGet, query(coAuthor, "get_draft", draft.ID, []byte(draft.ID)), "", draft.ID, server.handleGetDraft)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Ship the mobile app", decode(rec).Content.Description)
+
+	// Only the owner submits
+	submit := func(key crypto.PrivateKey) *httptest.ResponseRecorder {
+		return call(http.MethodPost, "/", fmt.Sprintf(`{"address":"%s","timestamp":%d,"signature":"%s","private_key":"%s"}`,
+			key.PublicKey().String(), now, signMemberRequest(t, key, "submit_draft", now, []byte(draft.ID)), hex.EncodeToString(key.Bytes())), draft.ID, server.handleSubmitDraft)
+	}
+	assert.Equal(t, http.StatusForbidden, submit(coAuthor).Code)
+	rec = submit(owner)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var submitted map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &submitted))
+
+	proposalTx, ok := (<-txChan).TxInner.(*dao.ProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, "Ship the mobile app", proposalTx.Description)
+	assert.Equal(t, submitted["content_hash"], proposalTx.MetadataHash.String())
+
+	rec = call(http.MethodGet, query(owner, "get_draft", draft.ID, []byte(draft.ID)), "", draft.ID, server.handleGetDraft)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, submitted["tx_hash"], decode(rec).SubmittedTx)
+	assert.Equal(t, http.StatusBadRequest, edit(coAuthor, 2, content).Code)
+
+	// Signatures are bound to the draft they were made for
+	rec = call(http.MethodDelete, query(owner, "delete_draft", draft.ID, []byte("other")), "", draft.ID, server.handleDeleteDraft)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestDAOServer_Calendar(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	server.eventBus = &EventBus{broadcast: make(chan []byte, 1)}
+	e := echo.New()
+
+	now := time.Now().Unix()
+	member := crypto.GeneratePrivateKey().PublicKey()
+	testDAO.GovernanceState.Proposals[types.Hash{0x01}] = &dao.Proposal{ID: types.Hash{0x01}, Title: "Scheduled", Status: dao.ProposalStatusPending, StartTime: now + 3600, EndTime: now + 7200}
+	require.NoError(t, testDAO.TokenomicsManager.CreateStakingPool("pool", "Main pool", 1, 1, 600))
+	pool, _ := testDAO.TokenomicsManager.GetStakingPool("pool")
+	pool.Stakers[member.String()] = &dao.StakerInfo{Address: member, StakedAmount: 250, UnlockTime: now + 600}
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleGetCalendar(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)))
+		return rec
+	}
+	decode := func(rec *httptest.ResponseRecorder) []dao.CalendarEvent {
+		var calendar struct {
+			Events []dao.CalendarEvent `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &calendar))
+		return calendar.Events
+	}
+
+	rec := get("/dao/calendar")
+	require.Equal(t, http.StatusOK, rec.Code)
+	events := decode(rec)
+	require.Len(t, events, 2)
+	assert.Equal(t, dao.CalendarVoteStart, events[0].Type)
+
+	rec = get("/dao/calendar?address=" + member.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	events = decode(rec)
+	require.Len(t, events, 3)
+	assert.Equal(t, dao.CalendarStakeUnlock, events[0].Type)
+
+	rec = get(fmt.Sprintf("/dao/calendar?from=%d&to=%d", now+5000, now+8000))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, decode(rec), 1)
+
+	assert.Equal(t, http.StatusBadRequest, get(fmt.Sprintf("/dao/calendar?from=%d&to=%d", now, now-1)).Code)
+	assert.Equal(t, http.StatusBadRequest, get("/dao/calendar?address=nothex").Code)
+
+	// Proposals can open their vote later
+	create := func(startTime int64) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"title":"Later","description":"Opens tomorrow","proposal_type":1,"voting_type":1,"start_time":%d,"duration":86400,"threshold":5100,"private_key":"%s"}`,
+			startTime, hex.EncodeToString(crypto.GeneratePrivateKey().Bytes()))
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleCreateProposal(e.NewContext(req, rec)))
+		return rec
+	}
+	rec = create(now + 86400)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	proposalTx, ok := (<-txChan).TxInner.(*dao.ProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, now+86400, proposalTx.StartTime)
+	assert.Equal(t, now+2*86400, proposalTx.EndTime)
+
+	assert.Equal(t, http.StatusBadRequest, create(now-3600).Code)
+	assert.Equal(t, http.StatusBadRequest, create(now+dao.MaxProposalScheduleAhead+3600).Code)
+}
+
+func TestDAOServer_Membership(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	events := make(chan []byte, 1)
+	server.eventBus = &EventBus{broadcast: events}
+	e := echo.New()
+
+	members := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		members[0].PublicKey().String(): 1000,
+		members[1].PublicKey().String(): 1000,
+	}))
+
+	post := func(body string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+	get := func(target, address string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(address)
+		require.NoError(t, handler(c))
+		return rec
+	}
+	keyHex := hex.EncodeToString(crypto.GeneratePrivateKey().Bytes())
+
+	rec := post(`{"statement":"I write docs","kyc_attestation":"`+types.Hash{0xaa}.String()+`","private_key":"`+keyHex+`"}`, server.handleApplyForMembership)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var event Event
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventMembershipApplied, event.Type)
+
+	tx := <-txChan
+	joinTx, ok := tx.TxInner.(*dao.JoinRequestTx)
+	require.True(t, ok)
+	assert.Equal(t, int64(0), joinTx.Fee)
+	require.NoError(t, testDAO.ApplyDAOTransaction(joinTx, tx.From, types.Hash{0x01}, 1))
+	applicant := tx.From
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"kyc_attestation":"nothex","private_key":"`+keyHex+`"}`, server.handleApplyForMembership).Code)
+
+	rec = get("/dao/membership/applications?status=pending", "", server.handleGetMembershipApplications)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Applications []MembershipApplicationResponse `json:"applications"`
+		Total        int                             `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, uint64(2), list.Applications[0].ApprovalsRequired)
+	assert.Equal(t, http.StatusBadRequest, get("/dao/membership/applications?status=rejected", "", server.handleGetMembershipApplications).Code)
+
+	// Two approvals admit the applicant
+	for i, member := range members {
+		rec = post(`{"applicant":"`+applicant.String()+`","private_key":"`+hex.EncodeToString(member.Bytes())+`"}`, server.handleApproveMembership)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		<-events
+		approvalTx, ok := (<-txChan).TxInner.(*dao.JoinApprovalTx)
+		require.True(t, ok)
+		require.NoError(t, testDAO.ApplyDAOTransaction(approvalTx, member.PublicKey(), types.Hash{0x02, byte(i)}, 2))
+	}
+	assert.Equal(t, http.StatusNotFound, post(`{"applicant":"`+applicant.String()+`","private_key":"`+keyHex+`"}`, server.handleApproveMembership).Code)
+
+	rec = get("/", applicant.String(), server.handleGetMembership)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var membership MembershipResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &membership))
+	assert.Equal(t, "active", membership.Status)
+	assert.Equal(t, types.Hash{0xaa}.String(), membership.KYCAttestation)
+	require.NotNil(t, membership.Application)
+	assert.Equal(t, "approved", membership.Application.Status)
+
+	rec = get("/", crypto.GeneratePrivateKey().PublicKey().String(), server.handleGetMembership)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &membership))
+	assert.Equal(t, "none", membership.Status)
+
+	// Status changes are voted through transactions
+	rec = post(`{"member":"`+applicant.String()+`","status":"suspended","reason":"Spam","private_key":"`+hex.EncodeToString(members[0].Bytes())+`"}`, server.handleChangeMembershipStatus)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventMembershipStatus, event.Type)
+	statusTx, ok := (<-txChan).TxInner.(*dao.MembershipStatusTx)
+	require.True(t, ok)
+	assert.Equal(t, dao.MembershipStatusSuspended, statusTx.Status)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"member":"`+applicant.String()+`","status":"banned","private_key":"`+keyHex+`"}`, server.handleChangeMembershipStatus).Code)
+	assert.Equal(t, http.StatusNotFound, post(`{"member":"`+crypto.GeneratePrivateKey().PublicKey().String()+`","status":"suspended","private_key":"`+keyHex+`"}`, server.handleChangeMembershipStatus).Code)
+}
+
+func TestDAOServer_RageQuit(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	events := make(chan []byte, 1)
+	server.eventBus = &EventBus{broadcast: events}
+	e := echo.New()
+
+	member := crypto.GeneratePrivateKey().PublicKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		member.String(): 1000,
+		other.String():  3000,
+	}))
+	testDAO.GovernanceState.Treasury.Balance = 8000
+
+	preview := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handlePreviewRageQuit(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)))
+		return rec
+	}
+
+	rec := preview("/dao/treasury/ragequit?address=" + member.String() + "&amount=500")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response dao.RageQuitPreview
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, uint64(1000), response.Share)
+	assert.Equal(t, uint64(8000), response.Unrestricted)
+
+	assert.Equal(t, http.StatusBadRequest, preview("/dao/treasury/ragequit?address="+member.String()+"&amount=5000").Code)
+	assert.Equal(t, http.StatusBadRequest, preview("/dao/treasury/ragequit?address=nothex&amount=1").Code)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleRageQuit(e.NewContext(req, rec)))
+		return rec
+	}
+	keyHex := hex.EncodeToString(crypto.GeneratePrivateKey().Bytes())
+
+	rec = post(`{"amount":500,"private_key":"` + keyHex + `"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var event Event
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventRageQuit, event.Type)
+	rageQuitTx, ok := (<-txChan).TxInner.(*dao.RageQuitTx)
+	require.True(t, ok)
+	assert.Equal(t, uint64(500), rageQuitTx.Amount)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"amount":0,"private_key":"`+keyHex+`"}`).Code)
+}
+
+func TestDAOServer_SimulateProposal(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		voter.String(): 3000,
+		other.String(): 1000,
+	}))
+	testDAO.GovernanceState.Config.QuorumThreshold = 2000
+
+	proposalID := types.Hash{0x01}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{
+		ID: proposalID, Title: "Signal", Status: dao.ProposalStatusActive, VotingType: dao.VotingTypeSimple,
+		EndTime: time.Now().Unix() + 3600, Results: &dao.VoteResults{},
+	}
+
+	simulate := func(id, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/"+query, nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleSimulateProposal(c))
+		return rec
+	}
+
+	rec := simulate(proposalID.String(), "?address="+voter.String())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var simulation dao.ProposalSimulation
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &simulation))
+	assert.Equal(t, proposalID.String(), simulation.ProposalID)
+	assert.Equal(t, dao.OutcomeNoQuorum, simulation.CurrentOutcome)
+	assert.Equal(t, uint64(4000), simulation.RemainingPower)
+	require.NotNil(t, simulation.Voter)
+	assert.Equal(t, uint64(3000), simulation.Voter.Weight)
+	assert.Equal(t, dao.OutcomePass, simulation.Voter.OutcomeIfYes)
+	assert.True(t, simulation.Voter.Decisive)
+
+	assert.Equal(t, http.StatusBadRequest, simulate(proposalID.String(), "?address=nothex").Code)
+	assert.Equal(t, http.StatusNotFound, simulate(types.Hash{0x02}.String(), "").Code)
+
+	testDAO.GovernanceState.Proposals[proposalID].Status = dao.ProposalStatusPassed
+	assert.Equal(t, http.StatusConflict, simulate(proposalID.String(), "").Code)
+}
+
+func TestDAOServer_AnalyticsTimeSeries(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	now := time.Now().Unix()
+	for i, balance := range []uint64{1000, 2000, 3000} {
+		testDAO.GovernanceState.Treasury.Balance = balance
+		testDAO.Metrics.Sample(now - int64(2-i)*60)
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleGetMetricTimeSeries(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/analytics/timeseries"+query, nil), rec)))
+		return rec
+	}
+	decode := func(rec *httptest.ResponseRecorder) []dao.MetricPoint {
+		var series struct {
+			Metric string            `json:"metric"`
+			Points []dao.MetricPoint `json:"points"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &series))
+		return series.Points
+	}
+
+	rec := get("?metric=treasury_balance")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	points := decode(rec)
+	require.Len(t, points, 3)
+	assert.Equal(t, 3000.0, points[2].Value)
+
+	rec = get(fmt.Sprintf("?metric=treasury_balance&from=%d&to=%d&resolution=1h", now-3600, now))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	total := 0
+	for _, point := range decode(rec) {
+		total += point.Samples
+	}
+	assert.Equal(t, 3, total)
+
+	assert.Equal(t, http.StatusBadRequest, get("?metric=tvl").Code)
+	assert.Equal(t, http.StatusBadRequest, get(fmt.Sprintf("?metric=active_members&from=%d&to=%d", now, now-1)).Code)
+	assert.Equal(t, http.StatusBadRequest, get("?metric=active_members&resolution=soon").Code)
+}
+
+func TestDAOServer_DelegateScorecards(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	delegate := crypto.GeneratePrivateKey().PublicKey()
+	delegator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		delegate.String():  1000,
+		delegator.String(): 5000,
+	}))
+	now := time.Now().Unix()
+	testDAO.GovernanceState.Delegations[delegator.String()] = &dao.Delegation{
+		Delegator: delegator, Delegate: delegate, StartTime: now - 60, EndTime: now + 3600, Active: true,
+	}
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetDelegateScorecards(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/analytics/delegates", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Delegates []dao.DelegateScorecard `json:"delegates"`
+		Total     int                     `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+	require.Len(t, list.Delegates, 1)
+	assert.Equal(t, uint64(5000), list.Delegates[0].DelegatedPower)
+
+	get := func(address string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(address)
+		require.NoError(t, server.handleGetDelegateScorecard(c))
+		return rec
+	}
+
+	rec = get(delegate.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var scorecard dao.DelegateScorecard
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scorecard))
+	assert.Equal(t, uint64(1), scorecard.Delegators)
+	assert.Equal(t, uint64(1000), scorecard.OwnPower)
+
+	assert.Equal(t, http.StatusBadRequest, get("nothex").Code)
+}
+
+func TestDAOServer_TreasuryReports(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	testDAO.AddTreasuryFunds(5000)
+	period := time.Now().UTC().Format("2006-01")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleGetTreasuryReport(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/treasury/reports"+query, nil), rec)))
+		return rec
+	}
+
+	rec := get("?period=" + period)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var statement dao.TreasuryStatement
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statement))
+	assert.Equal(t, period, statement.Period)
+	assert.Equal(t, uint64(5000), statement.TotalInflows)
+	assert.Equal(t, uint64(5000), statement.ClosingBalance)
+
+	// The current month is the default
+	rec = get("?format=csv")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/csv")
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "treasury-"+period+".csv")
+	assert.Contains(t, rec.Body.String(), "inflow,deposit")
+
+	assert.Equal(t, http.StatusBadRequest, get("?period=june").Code)
+	assert.Equal(t, http.StatusBadRequest, get("?format=pdf").Code)
+}
+
+func TestDAOServer_Export(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	member := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{member.String(): 5000}))
+	proposalID := types.Hash{0x01}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, Creator: member, Title: "Signal, with comma", Status: dao.ProposalStatusActive}
+	testDAO.GovernanceState.Votes[proposalID] = map[string]*dao.Vote{
+		member.String(): {Voter: member, Choice: dao.VoteChoiceYes, Weight: 5000},
+	}
+
+	export := func(query, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/dao/export"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleExport(e.NewContext(req, rec)))
+		return rec
+	}
+
+	// Disabled without tokens
+	assert.Equal(t, http.StatusForbidden, export("", "").Code)
+
+	server.Config.Server.ExportTokens = []string{"auditor"}
+	assert.Equal(t, http.StatusUnauthorized, export("", "wrong").Code)
+
+	rec := export("", "auditor")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var dataset map[string][]map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dataset))
+	assert.Len(t, dataset[dao.ExportProposals], 1)
+	assert.Len(t, dataset[dao.ExportVotes], 1)
+	assert.Len(t, dataset[dao.ExportMembers], 1)
+	assert.Empty(t, dataset[dao.ExportTreasury])
+	assert.Equal(t, "Signal, with comma", dataset[dao.ExportProposals][0]["title"])
+	assert.Equal(t, float64(5000), dataset[dao.ExportVotes][0]["weight"])
+
+	// The admin token works too
+	server.Config.Server.AdminToken = "secret"
+	rec = export("?entities=proposals&format=csv", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/csv")
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "id", rows[0][0])
+	assert.Equal(t, "Signal, with comma", rows[1][2])
+
+	assert.Equal(t, http.StatusBadRequest, export("?entities=wallets", "auditor").Code)
+	assert.Equal(t, http.StatusBadRequest, export("?entities=proposals,votes&format=csv", "auditor").Code)
+	assert.Equal(t, http.StatusBadRequest, export("?format=xml", "auditor").Code)
+}
+
+func TestDAOServer_IndexedListings(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	whale := crypto.GeneratePrivateKey().PublicKey()
+	minnow := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{whale.String(): 9000, minnow.String(): 100}))
+
+	now := time.Now().Unix()
+	for i, status := range []dao.ProposalStatus{dao.ProposalStatusActive, dao.ProposalStatusPassed, dao.ProposalStatusActive} {
+		id := types.Hash{byte(i + 1)}
+		testDAO.GovernanceState.Proposals[id] = &dao.Proposal{ID: id, Creator: whale, Title: fmt.Sprintf("Proposal %d", i+1), Status: status, EndTime: now + int64(i+1)*3600}
+	}
+
+	get := func(path string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(httptest.NewRequest(http.MethodGet, path, nil), rec)))
+		return rec
+	}
+
+	rec := get("/dao/members?sort=balance&order=asc&limit=1", server.handleGetMembers)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var members struct {
+		Members []MemberResponse `json:"members"`
+		Total   int              `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &members))
+	assert.Equal(t, 2, members.Total)
+	require.Len(t, members.Members, 1)
+	assert.Equal(t, minnow.String(), members.Members[0].Address)
+
+	assert.Equal(t, http.StatusBadRequest, get("/dao/members?sort=staked", server.handleGetMembers).Code)
+	assert.Equal(t, http.StatusBadRequest, get("/dao/members?order=up", server.handleGetMembers).Code)
+
+	// Active proposals, latest ending first
+	rec = get(fmt.Sprintf("/dao/proposals?status=%d&page=1&limit=1", dao.ProposalStatusActive), server.handleGetProposals)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "2", rec.Header().Get("X-Total-Count"))
+	var proposals []ProposalResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &proposals))
+	require.Len(t, proposals, 1)
+	assert.Equal(t, "Proposal 3", proposals[0].Title)
+
+	rec = get(fmt.Sprintf("/dao/proposals?order=asc&ending_before=%d", now+2*3600), server.handleGetProposals)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &proposals))
+	require.Len(t, proposals, 1)
+	assert.Equal(t, "Proposal 1", proposals[0].Title)
+
+	assert.Equal(t, http.StatusBadRequest, get("/dao/proposals?status=9", server.handleGetProposals).Code)
+}
+
+func TestDAOServer_ResponseCache(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	require.NotNil(t, server.cache)
+	e := echo.New()
+
+	sender := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{sender.String(): 10000}))
+	testDAO.GovernanceState.Proposals[types.Hash{0x01}] = &dao.Proposal{ID: types.Hash{0x01}, Creator: sender, Title: "First", Status: dao.ProposalStatusActive}
+
+	handler := server.cached(server.handleGetProposals)
+	get := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/dao/proposals", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := get("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Contains(t, rec.Body.String(), "First")
+
+	// Clients holding the current version get no payload
+	rec = get(etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Empty(t, rec.Body.Bytes())
+
+	// Writes outside the processor are served stale until invalidated
+	testDAO.GovernanceState.Proposals[types.Hash{0x02}] = &dao.Proposal{ID: types.Hash{0x02}, Creator: sender, Title: "Second", Status: dao.ProposalStatusActive}
+	rec = get("")
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.NotContains(t, rec.Body.String(), "Second")
+	assert.Equal(t, "1", rec.Header().Get("X-Total-Count"))
+
+	// Applied transactions invalidate the cache
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.ApplyDAOTransaction(&dao.TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 100}, sender, types.Hash{0x03}, 1))
+
+	rec = get(etag)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	assert.Contains(t, rec.Body.String(), "Second")
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestRedisCacheStore(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// A minimal Redis speaking just enough of the protocol
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		values := make(map[string]string)
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			args := make([]string, count)
+			for i := range args {
+				header, _ := reader.ReadString('\n')
+				size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+				arg := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, arg); err != nil {
+					return
+				}
+				args[i] = string(arg[:size])
+			}
+
+			switch args[0] {
+			case "AUTH":
+				if args[1] != "secret" {
+					fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+					continue
+				}
+				fmt.Fprint(conn, "+OK\r\n")
+			case "SET":
+				values[args[1]] = args[2]
+				fmt.Fprint(conn, "+OK\r\n")
+			case "GET":
+				value, exists := values[args[1]]
+				if !exists {
+					fmt.Fprint(conn, "$-1\r\n")
+					continue
+				}
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			case "INCR":
+				counter, _ := strconv.Atoi(values[args[1]])
+				values[args[1]] = strconv.Itoa(counter + 1)
+				fmt.Fprintf(conn, ":%d\r\n", counter+1)
+			default:
+				fmt.Fprint(conn, "-ERR unknown command\r\n")
+			}
+		}
+	}()
+
+	store := NewRedisCacheStore(listener.Addr().String(), "secret")
+	defer store.Close()
+
+	_, exists, err := store.Get("missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, store.Set("key", []byte("value\r\nwith a line break"), time.Minute))
+	value, exists, err := store.Get("key")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "value\r\nwith a line break", string(value))
+
+	generation, err := store.Incr("generation")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), generation)
+}
+
+func TestETagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"a", "b"`, `"b"`))
+	assert.True(t, etagMatches(`W/"b"`, `"b"`))
+	assert.True(t, etagMatches("*", `"b"`))
+	assert.False(t, etagMatches("", `"b"`))
+}
+
+func TestServer_BlockFeed(t *testing.T) {
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+
+	addBlock := func() *core.Block {
+		prevHeader, err := bc.GetHeader(bc.Height())
+		require.NoError(t, err)
+		block, err := core.NewBlockFromPrevHeader(prevHeader, []*core.Transaction{})
+		require.NoError(t, err)
+		require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+		require.NoError(t, bc.AddBlock(block))
+		return block
+	}
+	for i := 0; i < 3; i++ {
+		addBlock()
+	}
+
+	server := NewServer(ServerConfig{Logger: log.NewNopLogger()}, bc, nil)
+	e := echo.New()
+	e.GET("/chain/feed", server.handleBlockFeed)
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/chain/feed?from=2")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Existing blocks stream first, new blocks follow as they are added
+	decoder := gob.NewDecoder(res.Body)
+	for _, height := range []uint32{2, 3} {
+		block := new(core.Block)
+		require.NoError(t, decoder.Decode(block))
+		assert.Equal(t, height, block.Height)
+		assert.NoError(t, block.Verify())
+	}
+
+	added := addBlock()
+	block := new(core.Block)
+	require.NoError(t, decoder.Decode(block))
+	assert.Equal(t, added.Hash(core.BlockHasher{}), block.Hash(core.BlockHasher{}))
+
+	for _, from := range []string{"0", "tip"} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/chain/feed?from="+from, nil), rec)
+		require.NoError(t, server.handleBlockFeed(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestDAOServer_ReadOnlyReplica(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	server.Config.Node.ReplicaOf = "http://primary:9000"
+	handler := server.readOnly(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	e := echo.New()
+	for method, expected := range map[string]int{
+		http.MethodGet:    http.StatusNoContent,
+		http.MethodHead:   http.StatusNoContent,
+		http.MethodPost:   http.StatusMethodNotAllowed,
+		http.MethodDelete: http.StatusMethodNotAllowed,
+	} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(method, "/dao/proposal", nil), rec)
+		require.NoError(t, handler(c))
+		assert.Equal(t, expected, rec.Code, method)
+		if expected == http.StatusMethodNotAllowed {
+			assert.Contains(t, rec.Body.String(), "http://primary:9000")
+		}
+	}
+}
+
+func TestEventBus_EvictsSlowClients(t *testing.T) {
+	bus := &EventBus{
+		clients:    make(map[*wsClient]bool),
+		broadcast:  make(chan []byte, eventBroadcastBuffer),
+		register:   make(chan *wsClient),
+		unregister: make(chan *wsClient),
+	}
+	go bus.run()
+
+	fast := &wsClient{send: make(chan []byte, 10)}
+	slow := &wsClient{send: make(chan []byte, 2)}
+	bus.register <- fast
+	bus.register <- slow
+
+	// The fan-out carries on past the slow client once its queue is full
+	for i := 0; i < 5; i++ {
+		bus.broadcast <- []byte(strconv.Itoa(i))
+	}
+	for i := 0; i < 5; i++ {
+		select {
+		case message := <-fast.send:
+			assert.Equal(t, strconv.Itoa(i), string(message))
+		case <-time.After(time.Second):
+			t.Fatal("fast client did not receive every message")
+		}
+	}
+
+	received := 0
+	for range slow.send {
+		received++
+	}
+	assert.Equal(t, 2, received)
+	assert.True(t, slow.evicted)
+	assert.False(t, fast.evicted)
+}
+
+func TestDAOServer_WebSocketKeepAlive(t *testing.T) {
+	pingPeriod := wsPingPeriod
+	wsPingPeriod = 20 * time.Millisecond
+	defer func() { wsPingPeriod = pingPeriod }()
+
+	server, _, _ := setupTestDAOServer()
+	e := echo.New()
+	e.GET("/ws", server.handleWebSocket)
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return conn.WriteControl(websocket.PongMessage, nil, time.Now().Add(time.Second))
+	})
+	messages := make(chan []byte, 10)
+	go func() {
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				close(messages)
+				return
+			}
+			messages <- message
+		}
+	}()
+
+	select {
+	case <-pings:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no keepalive ping")
+	}
+
+	server.broadcastEvent(Event{Type: EventVoteCast, Timestamp: time.Now().Unix()})
+	select {
+	case message := <-messages:
+		assert.Contains(t, string(message), string(EventVoteCast))
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+func TestAPIErrorEnvelope(t *testing.T) {
+	paused := dao.NewDAOError(dao.ErrFunctionPaused, "function is currently paused", map[string]interface{}{"function": "vote"})
+	apiErr := errorFromErr(http.StatusForbidden, fmt.Errorf("vote: %w", paused))
+	assert.Equal(t, "function_paused", apiErr.Code)
+	assert.Equal(t, "function is currently paused", apiErr.Message)
+	assert.True(t, apiErr.Retryable)
+	assert.Equal(t, "vote", apiErr.Details["function"])
+
+	// Details that cannot be encoded are dropped
+	apiErr = errorFromErr(http.StatusBadRequest, dao.NewDAOError(dao.ErrInvalidProposal, "bad", map[string]interface{}{"fn": func() {}}))
+	assert.Equal(t, "invalid_proposal", apiErr.Code)
+	assert.Nil(t, apiErr.Details)
+
+	apiErr = errorFromErr(http.StatusServiceUnavailable, fmt.Errorf("down"))
+	assert.Equal(t, CodeUnavailable, apiErr.Code)
+	assert.True(t, apiErr.Retryable)
+	assert.Equal(t, CodeInvalidRequest, newAPIError(http.StatusRequestEntityTooLarge, "too large").Code)
+
+	server, _, _ := setupTestDAOServer()
+	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler
+	e.GET("/dao/proposal/:id", server.handleGetProposal)
+
+	decode := func(path string) (int, APIError) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body APIError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+
+	status, body := decode("/dao/proposal/not-hex")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeInvalidRequest, body.Code)
+	assert.NotEmpty(t, body.Message)
+
+	status, body = decode("/dao/proposal/" + types.Hash{0x01}.String())
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "proposal_not_found", body.Code)
+
+	// Errors raised by echo use the envelope too
+	status, body = decode("/dao/unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, CodeNotFound, body.Code)
+	assert.False(t, body.Retryable)
+
+	// Wallet endpoints answer failures in the envelope
+	e.POST("/dao/wallet/disconnect", server.handleWalletDisconnect)
+	e.POST("/dao/wallet/verify", server.handleVerifyTransaction)
+	for _, path := range []string{"/dao/wallet/disconnect", "/dao/wallet/verify"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, CodeInvalidRequest, body.Code, path)
+		assert.NotContains(t, rec.Body.String(), `"success"`, path)
+	}
+}
+
+// rejectingSubmitter records transactions and rejects them with err
+type rejectingSubmitter struct {
+	submitted []*core.Transaction
+	err       error
+}
+
+func (s *rejectingSubmitter) SubmitTransaction(tx *core.Transaction) error {
+	s.submitted = append(s.submitted, tx)
+	return s.err
+}
+
+func TestIdempotencyCache(t *testing.T) {
+	cache := NewIdempotencyCache(time.Hour, 0)
+	submissions := 0
+	handler := cache.Middleware(func(c echo.Context) error {
+		var req map[string]interface{}
+		if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+			return errorMessage(c, http.StatusBadRequest, "Invalid request body")
+		}
+		if req["fail"] == true {
+			return errorMessage(c, http.StatusBadRequest, "rejected")
+		}
+		submissions++
+		return c.JSON(http.StatusOK, map[string]string{"tx_hash": strconv.Itoa(submissions)})
+	})
+
+	e := echo.New()
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/vote", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(IdempotencyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	// Retries replay the original transaction hash
+	first := post("vote-1", `{"choice":1}`)
+	require.Equal(t, http.StatusOK, first.Code)
+	retry := post("vote-1", `{"choice":1}`)
+	assert.Equal(t, http.StatusOK, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, submissions)
+
+	// Reusing a key for another request is rejected
+	rec := post("vote-1", `{"choice":2}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), CodeIdempotencyKeyReused)
+
+	// Failures release the key and requests without a key always run
+	assert.Equal(t, http.StatusBadRequest, post("vote-2", `{"fail":true}`).Code)
+	assert.Equal(t, http.StatusOK, post("vote-2", `{"choice":1}`).Code)
+	post("", `{"choice":1}`)
+	post("", `{"choice":1}`)
+	assert.Equal(t, 4, submissions)
+
+	// Retries while the first request runs are told to retry later
+	cache.begin("/dao/vote\nvote-3", sha256.Sum256([]byte("\n{}")), time.Now())
+	rec = post("vote-3", `{}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"retryable":true`)
+
+	// Results expire after the TTL
+	cache.finish("/dao/vote\nvote-3", http.StatusOK, echo.MIMEApplicationJSON, []byte(`{}`), time.Now().Add(-2*time.Hour))
+	assert.Nil(t, cache.begin("/dao/vote\nvote-3", sha256.Sum256([]byte("\n{}")), time.Now()))
+
+	// Bodies over the limit are rejected before they are read in full
+	cache.maxBody = 16
+	rec = post("vote-4", `{"choice":1,"padding":"0123456789"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, 4, submissions)
+
+	server, _, _ := setupTestDAOServer()
+	assert.NotNil(t, server.idempotency)
+}
+
+func TestIdempotencyCache_HandlerPanic(t *testing.T) {
+	cache := NewIdempotencyCache(time.Hour, 0)
+	panics := true
+	e := echo.New()
+	// Stands in for echo's recover middleware
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = errorMessage(c, http.StatusInternalServerError, fmt.Sprint(r))
+				}
+			}()
+			return next(c)
+		}
+	})
+	e.POST("/dao/vote", func(c echo.Context) error {
+		if panics {
+			panic("handler failed")
+		}
+		return c.JSON(http.StatusOK, map[string]string{"tx_hash": "1"})
+	}, cache.Middleware)
+
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/vote", strings.NewReader(`{"choice":1}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(IdempotencyHeader, "vote-1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, post().Code)
+
+	// The key was released, so the retry runs rather than being told the
+	// first request is in progress
+	panics = false
+	rec := post()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"tx_hash":"1"}`, rec.Body.String())
+}
+
+func TestDAOServer_TxStatus(t *testing.T) {
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+
+	events := make(chan []byte, 10)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger()}, bc, make(chan *core.Transaction, 1), dao.NewDAO("TEST", "Test Token", 18))
+	server.eventBus = &EventBus{broadcast: events}
+
+	tx := newDAOTransaction(&dao.TokenTransferTx{Fee: 10, Amount: 5})
+	require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+	require.NoError(t, server.submitTx(tx))
+	hash := tx.Hash(core.TxHasher{})
+
+	getStatus := func(hash string) (int, TxStatusResponse) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/tx/"+hash+"/status", nil), rec)
+		c.SetParamNames("hash")
+		c.SetParamValues(hash)
+		require.NoError(t, server.handleGetTxStatus(c))
+		var response TxStatusResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return rec.Code, response
+	}
+
+	code, response := getStatus(hash.String())
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, core.TxPending, response.State)
+
+	// Inclusion is streamed and reported with its confirmations
+	bc.TxTracker().Included(hash, bc.Height())
+	_, response = getStatus(hash.String())
+	assert.Equal(t, core.TxIncluded, response.State)
+	assert.Equal(t, uint32(1), response.Confirmations)
+
+	var event Event
+	for range []int{0, 1} {
+		require.NoError(t, json.Unmarshal(<-events, &event))
+	}
+	assert.Equal(t, EventTxStatus, event.Type)
+	assert.Equal(t, "included", event.Data.(map[string]interface{})["state"])
+
+	code, _ = getStatus(types.Hash{0x01}.String())
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = getStatus("xyz")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestServer_TrustedProxies(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	server.Config.Server.TrustedProxies = []string{"10.0.0.1", "192.168.1.0/24"}
+
+	e := server.newEcho()
+	e.GET("/whoami", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.RealIP()+" "+c.Scheme())
+	})
+
+	request := func(remoteAddr string) string {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7, 192.168.1.5")
+		req.Header.Set(echo.HeaderXForwardedProto, "https")
+		req.Header.Set(echo.HeaderXRealIP, "198.51.100.1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	// The proxy chain is walked back to the first untrusted address
+	assert.Equal(t, "203.0.113.7 https", request("10.0.0.1:4000"))
+	// Other peers cannot spoof their address or scheme
+	assert.Equal(t, "198.51.100.9 http", request("198.51.100.9:4000"))
+
+	server.Config.Server.TrustedProxies = nil
+	e = server.newEcho()
+	e.GET("/whoami", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.RealIP()+" "+c.Scheme())
+	})
+	assert.Equal(t, "10.0.0.1 http", request("10.0.0.1:4000"))
+}
+
+func TestDAOServer_AdminRuntimeControls(t *testing.T) {
+	genesis, err := core.NewBlock(&core.Header{Version: 1, Timestamp: time.Now().UnixNano()}, []*core.Transaction{})
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(log.NewNopLogger(), genesis)
+	require.NoError(t, err)
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	bc.RegisterDAOStateMachine(testDAO)
+
+	dir := t.TempDir()
+	logger, err := logging.New(filepath.Join(dir, "node.log"), logging.LevelInfo)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: logger, LogControl: logger}, bc, txChan, testDAO)
+	server.Config.Server.AdminToken = "secret"
+	server.Config.Server.SnapshotDir = filepath.Join(dir, "snapshots")
+
+	e := echo.New()
+	admin := func(handler echo.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	// Paused intake rejects transactions as retryable
+	rec := admin(server.handlePauseIntake, http.MethodPost, "/admin/intake/pause", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"paused": true}`, rec.Body.String())
+
+	tx := newDAOTransaction(&dao.TokenTransferTx{Fee: 10, Amount: 5})
+	require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+	assert.ErrorIs(t, server.submitTx(tx), ErrIntakePaused)
+	assert.Empty(t, txChan)
+
+	admin(server.handleResumeIntake, http.MethodPost, "/admin/intake/resume", "")
+	rec = admin(server.handleGetIntake, http.MethodGet, "/admin/intake", "")
+	assert.JSONEq(t, `{"paused": false}`, rec.Body.String())
+	require.NoError(t, server.submitTx(tx))
+	assert.Len(t, txChan, 1)
+
+	// A node's submitter checks transactions in place of the channel
+	submitter := &rejectingSubmitter{err: fmt.Errorf("%w: invalid signature", ErrTxInvalid)}
+	server.SetTxSubmitter(submitter)
+	assert.Error(t, server.submitTx(tx))
+	assert.Equal(t, []*core.Transaction{tx}, submitter.submitted)
+	assert.Len(t, txChan, 1)
+
+	// Only rejections that can succeed when retried are unavailable
+	for _, test := range []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("%w: invalid signature", ErrTxInvalid), http.StatusBadRequest},
+		{ErrTxKnown, http.StatusConflict},
+		{fmt.Errorf("%w: transaction pipeline is stopped", ErrTxUnavailable), http.StatusServiceUnavailable},
+		{errors.New("unexpected"), http.StatusBadRequest},
+	} {
+		submitter.err = test.err
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/dao/token/transfer", nil), rec)
+		require.NoError(t, server.submitDAOTx(c, &dao.TokenTransferTx{Fee: 10, Amount: 5}, crypto.GeneratePrivateKey(), "submitted"))
+		assert.Equal(t, test.status, rec.Code, test.err.Error())
+
+		var body APIError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, test.status == http.StatusServiceUnavailable, body.Retryable, test.err.Error())
+	}
+	server.SetTxSubmitter(nil)
+	<-txChan
+
+	// Snapshots are written in the format of `bockchain snapshot export`
+	rec = admin(server.handleCreateSnapshot, http.MethodPost, "/admin/snapshot", "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var snapshot AdminSnapshotResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	assert.Equal(t, filepath.Join(dir, "snapshots", "snapshot-0.json"), snapshot.Path)
+	data, err := os.ReadFile(snapshot.Path)
+	require.NoError(t, err)
+	var written SnapshotResponse
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, snapshot.StateRoot, written.StateRoot)
+	assert.Len(t, written.Entries, snapshot.Entries)
+
+	// Log level and rotation
+	rec = admin(server.handleSetLogLevel, http.MethodPut, "/admin/logs/level", `{"level": "verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = admin(server.handleSetLogLevel, http.MethodPut, "/admin/logs/level", `{"level": "warn"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, logging.LevelWarn, logger.Level())
+
+	rec = admin(server.handleRotateLogs, http.MethodPost, "/admin/logs/rotate", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var rotated map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rotated))
+	old, err := os.ReadFile(rotated["rotated"])
+	require.NoError(t, err)
+	assert.Contains(t, string(old), "transaction intake paused by admin")
+
+	// Profiles need the admin token
+	echoServer := echo.New()
+	server.registerProfiling(echoServer)
+	req := httptest.NewRequest(http.MethodGet, "/admin/debug/pprof/goroutine?debug=1", nil)
+	rec = httptest.NewRecorder()
+	echoServer.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	echoServer.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine profile")
+}
+
+func TestDAOServer_AdminJobs(t *testing.T) {
+	server, _, _ := setupTestDAOServer()
+	server.Config.Server.AdminToken = "secret"
+
+	runs := 0
+	scheduler := dao.NewScheduler()
+	require.NoError(t, scheduler.Add(dao.JobTreasuryExpiry, "*/10 * * * *", 0, func(time.Time) error {
+		runs++
+		return nil
+	}))
+	server.SetScheduler(scheduler)
+
+	e := echo.New()
+	admin := func(handler echo.HandlerFunc, path, name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if name != "" {
+			c.SetParamNames("name")
+			c.SetParamValues(name)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	rec := admin(server.handleRunJob, "/admin/jobs/treasury_expiry/run", dao.JobTreasuryExpiry)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, runs)
+
+	rec = admin(server.handleRunJob, "/admin/jobs/vacuum/run", "vacuum")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"job_not_found"`)
+
+	rec = admin(server.handleGetJobs, "/admin/jobs", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var jobs []dao.JobMetrics
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobs))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, dao.JobTreasuryExpiry, jobs[0].Name)
+	assert.Equal(t, uint64(1), jobs[0].Runs)
+}
+
+func TestDAOServer_DelegationExpiry(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	// Delegating with auto_renew carries the flag into the transaction
+	delegatorKey := crypto.GeneratePrivateKey()
+	delegate := crypto.GeneratePrivateKey().PublicKey()
+	body := fmt.Sprintf(`{"delegate":%q,"duration":3600,"auto_renew":true,"private_key":%q}`,
+		delegate.String(), hex.EncodeToString(delegatorKey.Bytes()))
+	req := httptest.NewRequest(http.MethodPost, "/dao/delegate", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleDelegate(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	tx := <-txChan
+	delegationTx, ok := tx.TxInner.(*dao.DelegationTx)
+	require.True(t, ok)
+	assert.True(t, delegationTx.AutoRenew)
+
+	// Only the two parties hear about the expiry
+	email := &recordingNotifier{}
+	testDAO.Notifications.RegisterNotifier(email)
+	delegator := delegatorKey.PublicKey()
+	for _, member := range []string{delegator.String(), delegate.String(), "outsider"} {
+		require.NoError(t, testDAO.Notifications.SetPreferences(&dao.NotificationPreferences{
+			Member:   member,
+			Channels: []string{dao.NotificationChannelEmail},
+			Email:    "member@example.com",
+		}))
+	}
+	testDAO.EnableNotifications()
+	defer testDAO.Notifications.Stop()
+
+	now := time.Now().Unix()
+	testDAO.GovernanceState.Delegations[delegator.String()] = &dao.Delegation{
+		Delegator: delegator,
+		Delegate:  delegate,
+		StartTime: now - 100,
+		EndTime:   now - 10,
+		Active:    true,
+	}
+	jobs := testDAO.MaintenanceJobs(func(fn func()) { fn() })
+	// The delegation_updated event of the request reaches every member
+	assert.Eventually(t, func() bool { return email.count() == 3 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, jobs[dao.JobDelegationExpiry](time.Unix(now, 0)))
+	assert.Eventually(t, func() bool { return email.count() == 5 }, time.Second, 10*time.Millisecond)
+
+	recipients := eventRecipients(Event{Type: EventDelegationExpired, Data: map[string]interface{}{
+		"delegator": delegator.String(),
+		"delegate":  delegate.String(),
+	}})
+	assert.Equal(t, []string{delegator.String(), delegate.String()}, recipients)
+	assert.Nil(t, eventRecipients(Event{Type: EventProposalCreated}))
+}
+
+func TestDAOServer_QuadraticFunding(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	owner := crypto.GeneratePrivateKey().PublicKey()
+	project := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{owner.String(): 50000}))
+	testDAO.GovernanceState.Treasury.Balance = 10000
+	multisigID := types.Hash{0x3F}
+	require.NoError(t, testDAO.ApplyDAOTransaction(&dao.MultisigCreateTx{Fee: 10, Name: "treasury", Owners: []crypto.PublicKey{owner}, Threshold: 1}, owner, multisigID, 1))
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	events := make(chan []byte, 1)
+	server.eventBus = &EventBus{broadcast: events}
+	e := echo.New()
+	key := hex.EncodeToString(crypto.GeneratePrivateKey().Bytes())
+
+	post := func(handler echo.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	now := time.Now().Unix()
+	rec := post(server.handleProposeQFRound, "", fmt.Sprintf(`{"title":"Public goods","projects":[{"name":"Docs","recipient":"nothex"}],"matching_pool":1000,"contribution_period":3600,"multisig":%q,"private_key":%q}`,
+		multisigID.String(), key))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"projects[0].recipient"`)
+
+	rec = post(server.handleProposeQFRound, "", fmt.Sprintf(`{"title":"Public goods","projects":[{"name":"Docs","recipient":%q}],"matching_pool":1000,"contribution_period":3600,"multisig":%q,"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":%q}`,
+		project.String(), multisigID.String(), now+60, now+7*86400, key))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventQFRoundProposed, event.Type)
+
+	proposal, ok := (<-txChan).TxInner.(*dao.QFRoundProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, multisigID, proposal.Multisig)
+	roundID := types.Hash{0x41}
+	require.NoError(t, testDAO.ApplyDAOTransaction(proposal, owner, roundID, 2))
+
+	rec = post(server.handleContributeQF, roundID.String(), fmt.Sprintf(`{"project":0,"amount":25,"private_key":%q}`, key))
+	require.Equal(t, http.StatusOK, rec.Code)
+	contribution, ok := (<-txChan).TxInner.(*dao.QFContributeTx)
+	require.True(t, ok)
+	assert.Equal(t, uint64(25), contribution.Amount)
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventQFContribution, event.Type)
+
+	// Payouts go through the round's multisig
+	action, err := server.multisigAction(MultisigActionRequest{Type: dao.ActivityTypeQFPayout, RoundID: roundID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, roundID, action.(*dao.QFPayoutTx).RoundID)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleGetQFRound(c))
+		return rec
+	}
+	rec = get(roundID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response QFRoundResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "proposed", response.Status)
+	assert.Equal(t, uint64(1000), response.MatchingPool)
+	require.Len(t, response.Projects, 1)
+	assert.Equal(t, project.String(), response.Projects[0].Recipient)
+
+	rec = get(types.Hash{0xEE}.String())
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"qf_round_not_found"`)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetQFRounds(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/qf/rounds?status=open", nil), rec)))
+	assert.JSONEq(t, `[]`, rec.Body.String())
+}
+
+func TestDAOServer_RetroactiveFunding(t *testing.T) {
+	testDAO := dao.NewDAO("TEST", "Test Token", 18)
+	owner := crypto.GeneratePrivateKey().PublicKey()
+	holder := crypto.GeneratePrivateKey()
+	builder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		owner.String():              50000,
+		holder.PublicKey().String(): 1000,
+	}))
+	testDAO.GovernanceState.Treasury.Balance = 10000
+
+	txChan := make(chan *core.Transaction, 1)
+	server := NewDAOServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: ":0"}, nil, txChan, testDAO)
+	events := make(chan []byte, 1)
+	server.eventBus = &EventBus{broadcast: events}
+	e := echo.New()
+	key := hex.EncodeToString(holder.Bytes())
+
+	post := func(handler echo.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	now := time.Now().Unix()
+	rec := post(server.handleProposeRPGFRound, "", fmt.Sprintf(`{"title":"Retro","budget":1000,"badge_holders":["nothex"],"nomination_period":60,"voting_period":60,"stream_duration":600,"private_key":%q}`, key))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"badge_holders[0]"`)
+
+	rec = post(server.handleProposeRPGFRound, "", fmt.Sprintf(`{"title":"Retro","budget":1000,"badge_holders":[%q],"nomination_period":60,"voting_period":60,"stream_duration":600,"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":%q}`,
+		holder.PublicKey().String(), now+60, now+7*86400, key))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventRPGFRoundProposed, event.Type)
+
+	proposal, ok := (<-txChan).TxInner.(*dao.RPGFRoundProposalTx)
+	require.True(t, ok)
+	roundID := types.Hash{0x51}
+	require.NoError(t, testDAO.ApplyDAOTransaction(proposal, owner, roundID, 1))
+
+	rec = post(server.handleNominateRPGF, roundID.String(), fmt.Sprintf(`{"name":"Explorer","recipient":%q,"private_key":%q}`, builder.String(), key))
+	require.Equal(t, http.StatusOK, rec.Code)
+	nomination, ok := (<-txChan).TxInner.(*dao.RPGFNominateTx)
+	require.True(t, ok)
+	assert.Equal(t, builder.String(), nomination.Recipient.String())
+	<-events
+
+	// Ballot events name the voter but not the allocations
+	rec = post(server.handleCastRPGFBallot, roundID.String(), fmt.Sprintf(`{"allocations":[{"nomination":0,"amount":700}],"private_key":%q}`, key))
+	require.Equal(t, http.StatusOK, rec.Code)
+	ballot, ok := (<-txChan).TxInner.(*dao.RPGFBallotTx)
+	require.True(t, ok)
+	assert.Equal(t, []dao.RPGFAllocation{{Nomination: 0, Amount: 700}}, ballot.Allocations)
+	require.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, EventRPGFBallotCast, event.Type)
+	data, ok := event.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, data, "voter")
+	assert.NotContains(t, data, "allocations")
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleGetRPGFRound(c))
+		return rec
+	}
+	rec = get(roundID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response RPGFRoundResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "proposed", response.Status)
+	assert.Equal(t, uint64(1000), response.Budget)
+	assert.Equal(t, []string{holder.PublicKey().String()}, response.BadgeHolders)
+	assert.Empty(t, response.Voted)
+
+	rec = get(types.Hash{0xEE}.String())
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"rpgf_round_not_found"`)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetRPGFRounds(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/rpgf/rounds?status=streaming", nil), rec)))
+	assert.JSONEq(t, `[]`, rec.Body.String())
+}
+
+func TestDAOServer_OptimisticProposals(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	proposerKey := crypto.GeneratePrivateKey()
+	proposer := proposerKey.PublicKey()
+	challenger := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		proposer.String():   10000,
+		challenger.String(): 5000,
+	}))
+
+	var mu sync.Mutex
+	var events []Event
+	unsubscribe := server.eventBus.Subscribe(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	defer unsubscribe()
+	lastEvent := func() Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return events[len(events)-1]
+	}
+
+	post := func(handler echo.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	rec := post(server.handleCreateOptimisticProposal, "", fmt.Sprintf(`{"title":"Rename the forum","description":"Routine change","proposal_type":1,"voting_type":1,"threshold":5100,"voting_period":86400,"private_key":%q}`,
+		hex.EncodeToString(proposerKey.Bytes())))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, EventOptimisticCreated, lastEvent().Type)
+	proposalTx, ok := (<-txChan).TxInner.(*dao.OptimisticProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, int64(86400), proposalTx.VotingPeriod)
+	id := types.Hash{0x61}
+	require.NoError(t, testDAO.ProcessDAOTransaction(proposalTx, proposer, id))
+
+	rec = post(server.handleChallengeOptimisticProposal, id.String(), fmt.Sprintf(`{"reason":"Not routine","private_key":%q}`,
+		hex.EncodeToString(crypto.GeneratePrivateKey().Bytes())))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, EventOptimisticChallenged, lastEvent().Type)
+	challengeTx, ok := (<-txChan).TxInner.(*dao.OptimisticChallengeTx)
+	require.True(t, ok)
+	assert.Equal(t, id, challengeTx.ProposalID)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleGetOptimisticProposal(c))
+		return rec
+	}
+	rec = get(id.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response OptimisticProposalResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "pending", response.Status)
+	assert.Equal(t, uint64(1000), response.Bond)
+	assert.Empty(t, response.Challenger)
+
+	rec = get(types.Hash{0xEE}.String())
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"optimistic_proposal_not_found"`)
+
+	// The proposal status job executes it once the window ends unchallenged
+	optimistic, _ := testDAO.GetOptimisticProposal(id)
+	optimistic.ChallengeEnds = time.Now().Unix() - 1
+	jobs := testDAO.MaintenanceJobs(nil)
+	require.NoError(t, jobs[dao.JobProposalStatus](time.Now()))
+	event := lastEvent()
+	assert.Equal(t, EventOptimisticExecuted, event.Type)
+	assert.Equal(t, id.String(), event.Data.(map[string]interface{})["proposal_id"])
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetOptimisticProposals(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/optimistic?status=executed", nil), rec)))
+	var list []OptimisticProposalResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, id.String(), list[0].ID)
+}
+
+func TestDAOServer_OracleFeeds(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	oracleKey := crypto.GeneratePrivateKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	post := func(handler echo.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+	get := func(handler echo.HandlerFunc, id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, handler(c))
+		return rec
+	}
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+
+	rec := post(server.handleProposeOracleFeed, "", fmt.Sprintf(`{"title":"GOV price","feed_id":"GOV-USD","oracles":["zz"],"min_reports":1,"max_age":600,"private_key":%q}`, founderHex))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"oracles[0]"`)
+
+	now := time.Now().Unix()
+	rec = post(server.handleProposeOracleFeed, "", fmt.Sprintf(`{"title":"GOV price","feed_id":"GOV-USD","decimals":2,"oracles":[%q],"min_reports":1,"max_age":600,"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":%q}`,
+		oracleKey.PublicKey().String(), now, now+86400, founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	proposalTx, ok := (<-txChan).TxInner.(*dao.OracleFeedProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, "GOV-USD", proposalTx.FeedID)
+	proposalID := types.Hash{0x90}
+	require.NoError(t, testDAO.ProcessDAOTransaction(proposalTx, founder, proposalID))
+
+	rec = post(server.handleActivateOracleFeed, "", fmt.Sprintf(`{"proposal_id":%q,"private_key":%q}`, proposalID.String(), founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	activateTx, ok := (<-txChan).TxInner.(*dao.OracleFeedActivateTx)
+	require.True(t, ok)
+	proposal, err := testDAO.GetProposal(proposalID)
+	require.NoError(t, err)
+	proposal.Status = dao.ProposalStatusPassed
+	require.NoError(t, testDAO.ProcessDAOTransaction(activateTx, founder, types.Hash{0x91}))
+
+	// Proposals take conditions on the feed
+	rec = post(server.handleCreateProposal, "", fmt.Sprintf(`{"title":"Buy back","description":"Above a dollar","proposal_type":1,"voting_type":1,"duration":86400,"threshold":5100,"conditions":[{"feed_id":"GOV-USD","operator":"above","value":100}],"private_key":%q}`, founderHex))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"conditions[0].operator"`)
+	rec = post(server.handleCreateProposal, "", fmt.Sprintf(`{"title":"Buy back","description":"Above a dollar","proposal_type":1,"voting_type":1,"duration":86400,"threshold":5100,"conditions":[{"feed_id":"GOV-USD","operator":"gt","value":100}],"private_key":%q}`, founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	spendTx, ok := (<-txChan).TxInner.(*dao.ProposalTx)
+	require.True(t, ok)
+	require.Len(t, spendTx.Conditions, 1)
+	spendID := types.Hash{0x92}
+	require.NoError(t, testDAO.ProcessDAOTransaction(spendTx, founder, spendID))
+
+	var conditions []OracleConditionResponse
+	rec = get(server.handleGetProposalConditions, spendID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &conditions))
+	require.Len(t, conditions, 1)
+	assert.Nil(t, conditions[0].Current)
+	require.NotNil(t, conditions[0].Error)
+	assert.Equal(t, "oracle_data_stale", conditions[0].Error.Code)
+
+	// Reports are signed by the oracle key and submitted by anyone
+	rec = post(server.handleReportOracle, "GOV-USD", fmt.Sprintf(`{"value":150,"oracle_private_key":%q,"private_key":%q}`,
+		hex.EncodeToString(oracleKey.Bytes()), founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	reportTx, ok := (<-txChan).TxInner.(*dao.OracleReportTx)
+	require.True(t, ok)
+	assert.True(t, reportTx.VerifySignature())
+	assert.Equal(t, int64(150), reportTx.Value)
+	// Request keys are not parsed into the keys they encode, so sign as the
+	// whitelisted oracle here
+	require.NoError(t, reportTx.Sign(oracleKey))
+	require.NoError(t, testDAO.ProcessDAOTransaction(reportTx, founder, types.Hash{0x93}))
+
+	rec = get(server.handleGetProposalConditions, spendID.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &conditions))
+	require.NotNil(t, conditions[0].Current)
+	assert.Equal(t, int64(150), *conditions[0].Current)
+	assert.True(t, conditions[0].Holds)
+
+	rec = get(server.handleGetOracleFeed, "GOV-USD")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var feed OracleFeedResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &feed))
+	assert.False(t, feed.Stale)
+	require.NotNil(t, feed.Value)
+	assert.Equal(t, int64(150), *feed.Value)
+	require.Len(t, feed.Reports, 1)
+	assert.True(t, feed.Reports[0].Fresh)
+
+	rec = get(server.handleGetOracleFeed, "BTC-USD")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"oracle_feed_not_found"`)
+}
+
+func TestDAOServer_StableTreasuryTransaction(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	oracleKey := crypto.GeneratePrivateKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+	require.NoError(t, testDAO.InitializeTreasury([]crypto.PublicKey{founder}, 1))
+	testDAO.AddTreasuryFunds(5000)
+
+	// Activate a feed pricing the token at 2.00
+	now := time.Now().Unix()
+	proposalID := types.Hash{0x92}
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.OracleFeedProposalTx{
+		Fee: 100, Title: "GOV price", FeedID: "GOV-USD", Decimals: 2,
+		Oracles: []crypto.PublicKey{oracleKey.PublicKey()}, MinReports: 1, MaxAge: 600,
+		VotingType: dao.VotingTypeSimple, StartTime: now, EndTime: now + 86400, Threshold: 5100,
+	}, founder, proposalID))
+	proposal, err := testDAO.GetProposal(proposalID)
+	require.NoError(t, err)
+	proposal.Status = dao.ProposalStatusPassed
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.OracleFeedActivateTx{Fee: 10, ProposalID: proposalID}, founder, types.Hash{0x93}))
+	report := &dao.OracleReportTx{Fee: 1, FeedID: "GOV-USD", Value: 200, ObservedAt: now}
+	require.NoError(t, report.Sign(oracleKey))
+	require.NoError(t, testDAO.ProcessDAOTransaction(report, founder, types.Hash{0x94}))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/treasury/transaction", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleCreateTreasuryTransaction(e.NewContext(req, rec)))
+		return rec
+	}
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	rec := post(fmt.Sprintf(`{"recipient":%q,"amount":1000,"denomination":"EUR-GOV","purpose":"Audit","private_key":%q}`, recipient.String(), founderHex))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"denomination"`)
+
+	rec = post(fmt.Sprintf(`{"recipient":%q,"amount":1000,"denomination":"GOV-USD","purpose":"Audit","private_key":%q}`, recipient.String(), founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	treasuryTx, ok := (<-txChan).TxInner.(*dao.TreasuryTx)
+	require.True(t, ok)
+	assert.Equal(t, "GOV-USD", treasuryTx.Denomination)
+	require.NoError(t, testDAO.ProcessDAOTransaction(treasuryTx, founder, types.Hash{0x95}))
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetTreasuryTransactions(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var transactions []TreasuryTransactionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &transactions))
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "GOV-USD", transactions[0].Denomination)
+	assert.Equal(t, uint64(1000), transactions[0].StableAmount)
+	assert.Equal(t, uint64(500), transactions[0].QuotedAmount)
+	assert.Equal(t, uint64(500), transactions[0].Amount)
+}
+
+func TestDAOServer_Constitution(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	testDAO.IPFSClient = dao.NewIPFSClientWithStore(&memoryContentStore{content: make(map[string][]byte)})
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+
+	call := func(handler echo.HandlerFunc, body string, params ...string) *httptest.ResponseRecorder {
+		method := http.MethodGet
+		if body != "" {
+			method = http.MethodPost
+		}
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if len(params) > 0 {
+			c.SetParamNames([]string{"id", "version"}[:len(params)]...)
+			c.SetParamValues(params...)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+	amend := func(content string, id types.Hash) {
+		now := time.Now().Unix()
+		rec := call(server.handleProposeAmendment, fmt.Sprintf(`{"title":"Constitution","description":"Amend the constitution","document_title":"DAO Constitution","content":%q,"summary":"Quorum rules","voting_type":1,"start_time":%d,"end_time":%d,"private_key":%q}`,
+			content, now, now+86400, founderHex), "constitution")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		amendmentTx, ok := (<-txChan).TxInner.(*dao.ConstitutionAmendmentTx)
+		require.True(t, ok)
+		require.NoError(t, testDAO.ProcessDAOTransaction(amendmentTx, founder, id))
+		proposal, err := testDAO.GetProposal(id)
+		require.NoError(t, err)
+		proposal.Status = dao.ProposalStatusPassed
+
+		rec = call(server.handleEnactAmendment, fmt.Sprintf(`{"proposal_id":%q,"private_key":%q}`, id.String(), founderHex))
+		require.Equal(t, http.StatusOK, rec.Code)
+		enactTx, ok := (<-txChan).TxInner.(*dao.ConstitutionEnactTx)
+		require.True(t, ok)
+		require.NoError(t, testDAO.ProcessDAOTransaction(enactTx, founder, types.Hash{id[0], 0xEE}))
+	}
+
+	rec := call(server.handleProposeAmendment, fmt.Sprintf(`{"title":"Constitution","content":" ","private_key":%q}`, founderHex), "constitution")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"content"`)
+
+	amend("Article 1\nArticle 2", types.Hash{0xB0})
+	amend("Article 1\nArticle 2a\nArticle 3", types.Hash{0xB1})
+
+	rec = call(server.handleGetGovernanceDocuments, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var docs []GovernanceDocumentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &docs))
+	require.Len(t, docs, 1)
+	assert.Equal(t, "DAO Constitution", docs[0].Title)
+	assert.Equal(t, uint32(2), docs[0].Version)
+	assert.Empty(t, docs[0].Versions)
+
+	rec = call(server.handleGetGovernanceDocument, "", "constitution")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var doc GovernanceDocumentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	require.Len(t, doc.Versions, 2)
+	assert.Equal(t, types.Hash{0xB1}.String(), doc.Versions[1].ProposalID)
+
+	// A version carries its text and the diff from the previous version
+	rec = call(server.handleGetGovernanceDocumentVersion, "", "constitution", "2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var version DocumentVersionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &version))
+	assert.Equal(t, "Article 1\nArticle 2a\nArticle 3", version.Content)
+	require.NotNil(t, version.Diff)
+	assert.Equal(t, doc.Versions[0].ContentHash, version.Diff.PreviousHash)
+	assert.Equal(t, 2, version.Diff.Additions)
+	assert.Equal(t, 1, version.Diff.Deletions)
+
+	assert.Equal(t, http.StatusNotFound, call(server.handleGetGovernanceDocumentVersion, "", "constitution", "3").Code)
+	rec = call(server.handleGetGovernanceDocument, "", "charter")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"document_not_found"`)
+}
+
+func TestDAOServer_ProposalDependencies(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+
+	create := func(dependsOn string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"title":"Step","description":"One step","proposal_type":1,"voting_type":1,"duration":86400,"threshold":5100,"depends_on":%s,"private_key":%q}`, dependsOn, founderHex)
+		req := httptest.NewRequest(http.MethodPost, "/dao/proposal", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleCreateProposal(e.NewContext(req, rec)))
+		return rec
+	}
+	dependencies := func(id types.Hash) ProposalDependenciesResponse {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+		require.NoError(t, server.handleGetProposalDependencies(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response ProposalDependenciesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	first := types.Hash{0xD0}
+	rec := create("[]")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, testDAO.ProcessDAOTransaction((<-txChan).TxInner.(*dao.ProposalTx), founder, first))
+
+	rec = create(fmt.Sprintf("[%q]", types.Hash{0xEE}.String()))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"depends_on[0]"`)
+
+	second := types.Hash{0xD1}
+	rec = create(fmt.Sprintf("[%q]", first.String()))
+	require.Equal(t, http.StatusOK, rec.Code)
+	proposalTx := (<-txChan).TxInner.(*dao.ProposalTx)
+	assert.Equal(t, []types.Hash{first}, proposalTx.DependsOn)
+	require.NoError(t, testDAO.ProcessDAOTransaction(proposalTx, founder, second))
+
+	response := dependencies(second)
+	require.Len(t, response.DependsOn, 1)
+	assert.Equal(t, first.String(), response.DependsOn[0].ID)
+	assert.Equal(t, []string{first.String()}, response.Blocking)
+	assert.Empty(t, response.Dependents)
+
+	response = dependencies(first)
+	require.Len(t, response.Dependents, 1)
+	assert.Equal(t, second.String(), response.Dependents[0].ID)
+	assert.Empty(t, response.Blocking)
+}
+
+func TestDAOServer_EmergencySpend(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	guardianKey := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+	require.NoError(t, testDAO.InitializeFounderRoles([]crypto.PublicKey{founder}))
+	require.NoError(t, testDAO.GrantRole(guardianKey.PublicKey(), dao.RoleEmergency, founder, 0))
+	testDAO.GovernanceState.Treasury.Balance = 20000
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+	guardianHex := hex.EncodeToString(guardianKey.Bytes())
+
+	propose := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/emergency", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleProposeEmergencySpend(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := propose(fmt.Sprintf(`{"title":"Patch","description":"Fix the exploit","recipient":%q,"amount":500,"incident":"INC-7","private_key":%q}`, recipient.String(), founderHex))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"guardian_private_key"`)
+
+	rec = propose(fmt.Sprintf(`{"title":"Patch","description":"Fix the exploit","recipient":"zz","amount":500,"incident":"INC-7","guardian_private_key":%q,"private_key":%q}`, guardianHex, founderHex))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"recipient"`)
+
+	rec = propose(fmt.Sprintf(`{"title":"Patch","description":"Fix the exploit","recipient":%q,"amount":500,"incident":"INC-7","guardian_private_key":%q,"private_key":%q}`, recipient.String(), guardianHex, founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	spendTx := (<-txChan).TxInner.(*dao.EmergencySpendTx)
+	assert.Equal(t, uint64(500), spendTx.Amount)
+	assert.Equal(t, dao.VotingTypeSimple, spendTx.VotingType)
+	assert.True(t, spendTx.VerifySignature())
+
+	// Apply a spend co-signed by the registered guardian
+	require.NoError(t, spendTx.Sign(guardianKey))
+	spendID := types.Hash{0xE1}
+	require.NoError(t, testDAO.ProcessDAOTransaction(spendTx, founder, spendID))
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetEmergencySpends(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+	var spends []EmergencySpendResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spends))
+	require.Len(t, spends, 1)
+	assert.Equal(t, spendID.String(), spends[0].ProposalID)
+	assert.Equal(t, guardianKey.PublicKey().String(), spends[0].Guardian)
+	assert.Equal(t, dao.ProposalStatusActive, spends[0].Status)
+	assert.Equal(t, uint64(4000), spends[0].Quorum)
+	assert.Equal(t, uint64(6667), spends[0].Threshold)
+
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(types.Hash{0xEF}.String())
+	require.NoError(t, server.handleGetEmergencySpend(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	body := fmt.Sprintf(`{"proposal_id":%q,"private_key":%q}`, spendID.String(), founderHex)
+	req := httptest.NewRequest(http.MethodPost, "/dao/emergency/execute", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleExecuteEmergencySpend(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, spendID, (<-txChan).TxInner.(*dao.EmergencyExecuteTx).ProposalID)
+}
+
+func TestDAOServer_ParticipationRewards(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	memberKey := crypto.GeneratePrivateKey()
+	member := memberKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{member.String(): 1000}))
+
+	body := fmt.Sprintf(`{"opt_in":true,"private_key":%q}`, hex.EncodeToString(memberKey.Bytes()))
+	req := httptest.NewRequest(http.MethodPost, "/dao/rewards/participation/opt-in", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleParticipationOptIn(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	optInTx := (<-txChan).TxInner.(*dao.ParticipationOptInTx)
+	assert.True(t, optInTx.OptIn)
+	require.NoError(t, testDAO.ProcessDAOTransaction(optInTx, member, types.Hash{0x70}))
+
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("address")
+	c.SetParamValues(member.String())
+	require.NoError(t, server.handleGetParticipationRewardStatus(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status struct {
+		OptedIn bool   `json:"opted_in"`
+		Paid    uint64 `json:"paid"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.True(t, status.OptedIn)
+	assert.Zero(t, status.Paid)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetParticipationRewards(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var pool struct {
+		Budget       uint64                  `json:"budget"`
+		VoterCap     uint64                  `json:"voter_cap"`
+		CurrentEpoch *dao.ParticipationEpoch `json:"current_epoch"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pool))
+	assert.Zero(t, pool.Budget)
+	assert.Equal(t, uint64(50), pool.VoterCap)
+	require.NotNil(t, pool.CurrentEpoch)
+	assert.Equal(t, uint64(1), pool.CurrentEpoch.Number)
+}
+
+func TestDAOServer_AdaptiveQuorum(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	config := testDAO.ParameterManager.GetParameterConfig()
+	config.AdaptiveQuorumWindow = 5
+	testDAO.GovernanceState.Proposals[types.Hash{0x01}] = &dao.Proposal{
+		ID:      types.Hash{0x01},
+		Status:  dao.ProposalStatusPassed,
+		Results: &dao.VoteResults{YesVotes: 1800, NoVotes: 600},
+	}
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetAdaptiveQuorum(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/analytics/quorum", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var adaptive dao.AdaptiveQuorum
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &adaptive))
+	assert.Equal(t, uint64(5), adaptive.Window)
+	assert.Equal(t, 1, adaptive.Samples)
+	assert.Equal(t, uint64(2400), adaptive.Quorum)
+}
+
+func TestDAOServer_ProposalEndorsements(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	proposer := crypto.GeneratePrivateKey().PublicKey()
+	endorserKey := crypto.GeneratePrivateKey()
+	endorser := endorserKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		proposer.String(): 5000,
+		endorser.String(): 8000,
+	}))
+	testDAO.ParameterManager.GetParameterConfig().EndorsementThreshold = 5000
+
+	now := time.Now().Unix()
+	proposalID := types.Hash{0x51}
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ProposalTx{
+		Fee:          100,
+		Title:        "Routine",
+		Description:  "A routine change",
+		ProposalType: dao.ProposalTypeGeneral,
+		VotingType:   dao.VotingTypeSimple,
+		StartTime:    now,
+		EndTime:      now + 86400,
+		Threshold:    5100,
+	}, proposer, proposalID))
+
+	getEndorsements := func() ProposalEndorsementsResponse {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(proposalID.String())
+		require.NoError(t, server.handleGetProposalEndorsements(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response ProposalEndorsementsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+	response := getEndorsements()
+	assert.Equal(t, "awaiting", response.Status)
+	assert.Equal(t, uint64(5000), response.Required)
+	assert.Empty(t, response.Endorsers)
+
+	body := fmt.Sprintf(`{"private_key":%q}`, hex.EncodeToString(endorserKey.Bytes()))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleEndorseProposal(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	endorseTx := (<-txChan).TxInner.(*dao.EndorseProposalTx)
+	assert.Equal(t, proposalID, endorseTx.ProposalID)
+	require.NoError(t, testDAO.ProcessDAOTransaction(endorseTx, endorser, types.Hash{0x52}))
+
+	response = getEndorsements()
+	assert.Equal(t, "endorsed", response.Status)
+	require.Len(t, response.Endorsers, 1)
+	assert.Equal(t, endorser.String(), response.Endorsers[0].Address)
+	assert.Equal(t, uint64(8000), response.Endorsers[0].Power)
+}
+
+func TestDAOServer_MemberPrivacy(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	aliceKey := crypto.GeneratePrivateKey()
+	alice := aliceKey.PublicKey()
+	bobKey := crypto.GeneratePrivateKey()
+	bob := bobKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   2000,
+	}))
+
+	body := fmt.Sprintf(`{"balance":"private","votes":"members","private_key":%q}`, hex.EncodeToString(aliceKey.Bytes()))
+	req := httptest.NewRequest(http.MethodPost, "/dao/member/privacy", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, server.handleSetMemberPrivacy(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	privacyTx := (<-txChan).TxInner.(*dao.PrivacySettingsTx)
+	assert.Equal(t, dao.VisibilityPrivate, privacyTx.Balance)
+	assert.Equal(t, dao.VisibilityDefault, privacyTx.Reputation)
+	require.NoError(t, testDAO.ProcessDAOTransaction(privacyTx, alice, types.Hash{0x61}))
+
+	req = httptest.NewRequest(http.MethodPost, "/dao/member/privacy", strings.NewReader(`{"balance":"secret"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleSetMemberPrivacy(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	getMember := func(query string) MemberResponse {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/member/x"+query, nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(alice.String())
+		require.NoError(t, server.handleGetMember(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var member MemberResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &member))
+		return member
+	}
+	viewAs := func(key crypto.PrivateKey) string {
+		timestamp := time.Now().Unix()
+		return fmt.Sprintf("?viewer=%s&timestamp=%d&signature=%s", key.PublicKey().String(), timestamp, signMemberRequest(t, key, "view_members", timestamp, nil))
+	}
+
+	// Others see a hidden balance, the member sees their own
+	member := getMember("")
+	assert.Zero(t, member.Balance)
+	assert.Equal(t, []string{"balance", "staked"}, member.Hidden)
+	member = getMember(viewAs(bobKey))
+	assert.Zero(t, member.Balance)
+	member = getMember(viewAs(aliceKey))
+	assert.Equal(t, uint64(1000), member.Balance)
+	assert.Empty(t, member.Hidden)
+
+	// Forged viewers are rejected
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/member/x?viewer="+alice.String()+"&timestamp=1&signature=00", nil), rec)
+	c.SetParamNames("address")
+	c.SetParamValues(alice.String())
+	require.NoError(t, server.handleGetMember(c))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Members-only votes are left out for anonymous viewers
+	proposalID := types.Hash{0x62}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, Status: dao.ProposalStatusActive, Results: &dao.VoteResults{}}
+	testDAO.GovernanceState.Votes[proposalID] = map[string]*dao.Vote{
+		alice.String(): {Voter: alice, Choice: dao.VoteChoiceYes, Weight: 100},
+		bob.String():   {Voter: bob, Choice: dao.VoteChoiceNo, Weight: 200},
+	}
+	getVotes := func(query string) ([]VoteResponse, string) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/proposal/x/votes"+query, nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(proposalID.String())
+		require.NoError(t, server.handleGetProposalVotes(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var votes []VoteResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &votes))
+		return votes, rec.Header().Get("X-Hidden-Votes")
+	}
+	votes, hidden := getVotes("")
+	require.Len(t, votes, 1)
+	assert.Equal(t, bob.String(), votes[0].Voter)
+	assert.Equal(t, "1", hidden)
+	votes, hidden = getVotes(viewAs(bobKey))
+	assert.Len(t, votes, 2)
+	assert.Equal(t, "0", hidden)
+}
+
+func TestDAOServer_NameRegistry(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	aliceKey := crypto.GeneratePrivateKey()
+	alice := aliceKey.PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   1000,
+	}))
+
+	post := func(handler echo.HandlerFunc, name, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/names", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if name != "" {
+			c.SetParamNames("name")
+			c.SetParamValues(name)
+		}
+		require.NoError(t, handler(c))
+		return rec
+	}
+	privateKey := hex.EncodeToString(aliceKey.Bytes())
+
+	// Invalid names are rejected before anything is signed
+	rec := post(server.handleRegisterName, "", fmt.Sprintf(`{"name":"a_b","private_key":%q}`, privateKey))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(server.handleRegisterName, "", fmt.Sprintf(`{"name":"Alice","private_key":%q}`, privateKey))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	registerTx := (<-txChan).TxInner.(*dao.NameRegisterTx)
+	assert.Equal(t, "alice", registerTx.Name)
+	require.NoError(t, testDAO.ProcessDAOTransaction(registerTx, alice, types.Hash{0x71}))
+
+	rec = post(server.handleRenewName, "alice", fmt.Sprintf(`{"private_key":%q}`, privateKey))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "alice", (<-txChan).TxInner.(*dao.NameRenewTx).Name)
+
+	rec = post(server.handleTransferName, "alice", fmt.Sprintf(`{"recipient":"zz","private_key":%q}`, privateKey))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = post(server.handleTransferName, "alice", fmt.Sprintf(`{"recipient":%q,"private_key":%q}`, bob.String(), privateKey))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, bob.String(), (<-txChan).TxInner.(*dao.NameTransferTx).Recipient.String())
+
+	// Names resolve to their owner and are listed
+	resolve := func(name string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/names/x", nil), rec)
+		c.SetParamNames("name")
+		c.SetParamValues(name)
+		require.NoError(t, server.handleResolveName(c))
+		return rec
+	}
+	rec = resolve("ALICE")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var name NameResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &name))
+	assert.Equal(t, alice.String(), name.Owner)
+	assert.Equal(t, http.StatusNotFound, resolve("nobody").Code)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetNames(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/names", nil), rec)))
+	var names []NameResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &names))
+	require.Len(t, names, 1)
+	assert.Equal(t, "alice", names[0].Name)
+
+	// Responses carry the creator's name next to the key
+	proposalID := types.Hash{0x72}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, Creator: alice, Status: dao.ProposalStatusActive, Results: &dao.VoteResults{}}
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/proposal/x", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(proposalID.String())
+	require.NoError(t, server.handleGetProposal(c))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var proposal ProposalResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &proposal))
+	assert.Equal(t, "alice", proposal.CreatorName)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/member/x", nil), rec)
+	c.SetParamNames("address")
+	c.SetParamValues(bob.String())
+	require.NoError(t, server.handleGetMember(c))
+	var member MemberResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &member))
+	assert.Empty(t, member.Name)
+}
+
+func TestDAOServer_MemberProfile(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	testDAO.IPFSClient = dao.NewIPFSClientWithStore(&memoryContentStore{content: make(map[string][]byte)})
+	e := echo.New()
+
+	aliceKey := crypto.GeneratePrivateKey()
+	alice := aliceKey.PublicKey()
+	bobKey := crypto.GeneratePrivateKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		alice.String():              1000,
+		bobKey.PublicKey().String(): 1000,
+	}))
+
+	getProfile := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/member/x/profile", nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(alice.String())
+		require.NoError(t, server.handleGetMemberProfile(c))
+		return rec
+	}
+	putProfile := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/dao/member/x/profile", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("address")
+		c.SetParamValues(alice.String())
+		require.NoError(t, server.handleSetMemberProfile(c))
+		return rec
+	}
+	assert.Equal(t, http.StatusNotFound, getProfile().Code)
+
+	// Invalid fields are rejected before anything is uploaded
+	rec := putProfile(`{"display_name":"Alice","links":[{"label":"Site","url":"ftp://alice.example"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "links")
+
+	// Members can only publish their own profile
+	rec = putProfile(fmt.Sprintf(`{"display_name":"Alice","private_key":%q}`, hex.EncodeToString(bobKey.Bytes())))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	hash, err := testDAO.PublishMemberProfile(&dao.MemberProfile{
+		DisplayName: "Alice",
+		AvatarCID:   "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+		Links:       []dao.ProfileLink{{Label: "Site", URL: "https://alice.example"}},
+	}, aliceKey)
+	require.NoError(t, err)
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ProfileUpdateTx{Fee: 10, ProfileHash: hash}, alice, types.Hash{0x81}))
+
+	rec = getProfile()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var profile MemberProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &profile))
+	assert.Equal(t, "Alice", profile.DisplayName)
+	assert.Equal(t, alice.String(), profile.Address)
+	assert.Equal(t, hash.String(), profile.ProfileHash)
+	assert.NotEmpty(t, profile.Signature)
+	require.Len(t, profile.Links, 1)
+
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/member/x", nil), rec)
+	c.SetParamNames("address")
+	c.SetParamValues(alice.String())
+	require.NoError(t, server.handleGetMember(c))
+	var member MemberResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &member))
+	assert.Equal(t, hash.String(), member.Profile)
+
+	// Documents not signed by the member are not served
+	forged, err := testDAO.PublishMemberProfile(&dao.MemberProfile{DisplayName: "Alice"}, bobKey)
+	require.NoError(t, err)
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ProfileUpdateTx{Fee: 10, ProfileHash: forged}, alice, types.Hash{0x82}))
+	assert.Equal(t, http.StatusBadGateway, getProfile().Code)
+}
+
+func TestDAOServer_DelegateMarketplace(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	aliceKey := crypto.GeneratePrivateKey()
+	alice := aliceKey.PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   1000,
+	}))
+
+	publish := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/delegates/statement", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handlePublishDelegateStatement(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := publish(`{"platform":"","focus_areas":["defi!"],"fee":20000}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	for _, field := range []string{"platform", "focus_areas", "fee"} {
+		assert.Contains(t, rec.Body.String(), field)
+	}
+
+	rec = publish(fmt.Sprintf(`{"platform":"Fund audits","focus_areas":["Security"],"fee":500,"private_key":%q}`, hex.EncodeToString(aliceKey.Bytes())))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	statementTx := (<-txChan).TxInner.(*dao.DelegateStatementTx)
+	assert.Equal(t, []string{"security"}, statementTx.FocusAreas)
+	require.NoError(t, testDAO.ProcessDAOTransaction(statementTx, alice, types.Hash{0x91}))
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.DelegateStatementTx{Fee: 10, Platform: "Grow grants", FocusAreas: []string{"grants"}}, bob, types.Hash{0x92}))
+
+	search := func(query string) (int, []DelegateListingResponse) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleSearchDelegates(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/delegates"+query, nil), rec)))
+		var body struct {
+			Delegates []DelegateListingResponse `json:"delegates"`
+			Total     int                       `json:"total"`
+		}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		}
+		return rec.Code, body.Delegates
+	}
+
+	code, delegates := search("?sort=fee")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, delegates, 2)
+	assert.Equal(t, alice.String(), delegates[0].Address)
+	assert.Equal(t, "bockdao://delegate?delegate="+alice.String(), delegates[0].DelegateLink)
+	require.NotNil(t, delegates[0].Scorecard)
+
+	_, delegates = search("?focus=grants")
+	require.Len(t, delegates, 1)
+	assert.Equal(t, bob.String(), delegates[0].Address)
+
+	code, _ = search("?sort=charisma")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	getListing := func(address string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/delegates/x", nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(address)
+		require.NoError(t, server.handleGetDelegateListing(c))
+		return rec
+	}
+	rec = getListing(alice.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listing DelegateListingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listing))
+	assert.Equal(t, uint64(500), listing.Fee)
+	assert.Equal(t, http.StatusNotFound, getListing(crypto.GeneratePrivateKey().PublicKey().String()).Code)
+}
+
+func TestDAOServer_ProposalTurnout(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{voter.String(): 1000}))
+	now := time.Now().Unix()
+	proposalID := types.Hash{0xA1}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, StartTime: now - 3600, EndTime: now + 3600, Status: dao.ProposalStatusActive}
+	testDAO.GovernanceState.Votes[proposalID] = map[string]*dao.Vote{
+		voter.String(): {Voter: voter, Choice: dao.VoteChoiceYes, Weight: 1000, Timestamp: now - 60},
+	}
+
+	get := func(id, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/analytics/proposal/x"+query, nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleGetProposalTurnout(c))
+		return rec
+	}
+
+	rec := get(proposalID.String(), "?buckets=6")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var turnout dao.ProposalTurnout
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &turnout))
+	assert.Equal(t, uint64(1000), turnout.Weight)
+	assert.Equal(t, float64(100), turnout.Segments.Whales.Share)
+	assert.Equal(t, float64(100), turnout.Segments.Direct.Share)
+	require.Len(t, turnout.VotesByTime, 6)
+	assert.Equal(t, uint64(1), turnout.VotesByTime[5].Votes)
+
+	assert.Equal(t, http.StatusBadRequest, get(proposalID.String(), "?buckets=0").Code)
+	assert.Equal(t, http.StatusNotFound, get(types.Hash{0xA2}.String(), "").Code)
+}
+
+func TestDAOServer_ProposalResult(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	testDAO.IPFSClient = dao.NewIPFSClientWithStore(&memoryContentStore{content: make(map[string][]byte)})
+	e := echo.New()
+
+	proposalID := types.Hash{0xB1}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{
+		ID:      proposalID,
+		Status:  dao.ProposalStatusPassed,
+		Results: &dao.VoteResults{YesVotes: 700, NoVotes: 300, TotalVoters: 2, Passed: true},
+	}
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/proposal/x/result", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, server.handleGetProposalResult(c))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, get(proposalID.String()).Code)
+
+	key := crypto.GeneratePrivateKey()
+	testDAO.EnableResultAnchoring(key, func() uint32 { return 7 })
+	_, err := testDAO.AnchorProposalResults(nil)
+	require.NoError(t, err)
+
+	rec := get(proposalID.String())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result dao.ProposalResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.True(t, result.Verify())
+	assert.Equal(t, uint64(700), result.YesVotes)
+	assert.Equal(t, uint32(7), result.BlockHeight)
+
+	// The proposal reports where its result is anchored
+	response := newProposalResponse(testDAO.GovernanceState.Proposals[proposalID], testDAO.Names)
+	assert.Equal(t, testDAO.GovernanceState.Proposals[proposalID].ResultHash.String(), response.ResultHash)
+
+	assert.Equal(t, http.StatusNotFound, get(types.Hash{0xB2}.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, get("zz").Code)
+}
+
+func TestDAOServer_ProposalTracks(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	post := func(handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+	get := func(handler echo.HandlerFunc, id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, handler(c))
+		return rec
+	}
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+
+	assert.Equal(t, http.StatusNotFound, get(server.handleGetProposalTrack, "small-grants").Code)
+
+	now := time.Now().Unix()
+	rec := post(server.handleProposeTrack, fmt.Sprintf(`{"title":"Small grants","track":{"id":"small-grants","name":"Small grants","voting_type":1,"quorum":3000,"threshold":6000,"timelock":3600,"deposit":500,"actions":[1,2]},"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":%q}`,
+		now, now+86400, founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	proposalTx, ok := (<-txChan).TxInner.(*dao.TrackProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, []dao.ProposalType{dao.ProposalTypeGeneral, dao.ProposalTypeTreasury}, proposalTx.Track.Actions)
+	proposalID := types.Hash{0x90}
+	require.NoError(t, testDAO.ProcessDAOTransaction(proposalTx, founder, proposalID))
+
+	rec = post(server.handleActivateTrack, fmt.Sprintf(`{"proposal_id":%q,"private_key":%q}`, proposalID.String(), founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	activateTx, ok := (<-txChan).TxInner.(*dao.TrackActivateTx)
+	require.True(t, ok)
+	testDAO.GovernanceState.Proposals[proposalID].Status = dao.ProposalStatusPassed
+	require.NoError(t, testDAO.ProcessDAOTransaction(activateTx, founder, types.Hash{0x91}))
+
+	rec = get(server.handleGetProposalTrack, "small-grants")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var track ProposalTrackResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &track))
+	assert.Equal(t, uint64(6000), track.Threshold)
+	assert.Equal(t, int64(3600), track.Timelock)
+	assert.Equal(t, proposalID.String(), track.ProposalID)
+
+	rec = get(server.handleGetProposalTracks, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tracks []ProposalTrackResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tracks))
+	require.Len(t, tracks, 1)
+
+	// Proposals name their track and report its rules
+	rec = post(server.handleCreateProposal, fmt.Sprintf(`{"title":"Fund the docs","description":"A small grant","proposal_type":1,"voting_type":1,"duration":86400,"threshold":5100,"track":"small-grants","private_key":%q}`, founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	grantTx, ok := (<-txChan).TxInner.(*dao.ProposalTx)
+	require.True(t, ok)
+	assert.Equal(t, "small-grants", grantTx.Track)
+	grantID := types.Hash{0x92}
+	require.NoError(t, testDAO.ProcessDAOTransaction(grantTx, founder, grantID))
+
+	grant, err := testDAO.GetProposal(grantID)
+	require.NoError(t, err)
+	response := newProposalResponse(grant, testDAO.Names)
+	assert.Equal(t, "small-grants", response.Track)
+	assert.Equal(t, int64(3600), response.Timelock)
+	assert.Equal(t, uint64(6000), response.Threshold)
+}
+
+func TestDAOServer_TrackQueue(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	now := time.Now().Unix()
+	trackTx := &dao.TrackProposalTx{
+		Fee:   10,
+		Title: "Treasury",
+		Track: dao.ProposalTrack{
+			ID:         "treasury",
+			Name:       "Treasury",
+			VotingType: dao.VotingTypeSimple,
+			Threshold:  5100,
+			Actions:    []dao.ProposalType{dao.ProposalTypeTreasury},
+			MaxActive:  1,
+		},
+		VotingType: dao.VotingTypeSimple,
+		StartTime:  now,
+		EndTime:    now + 86400,
+		Threshold:  5100,
+	}
+	require.NoError(t, testDAO.ProcessDAOTransaction(trackTx, founder, types.Hash{0x90}))
+	testDAO.GovernanceState.Proposals[types.Hash{0x90}].Status = dao.ProposalStatusPassed
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.TrackActivateTx{Fee: 10, ProposalID: types.Hash{0x90}}, founder, types.Hash{0x91}))
+
+	for _, id := range []types.Hash{{0x92}, {0x93}} {
+		require.NoError(t, testDAO.ProcessDAOTransaction(&dao.ProposalTx{
+			Fee:          10,
+			Title:        "Spend",
+			Description:  "A treasury spend",
+			ProposalType: dao.ProposalTypeTreasury,
+			VotingType:   dao.VotingTypeSimple,
+			StartTime:    now,
+			EndTime:      now + 86400,
+			Threshold:    5100,
+			Track:        "treasury",
+		}, founder, id))
+	}
+
+	getProposal := func(id types.Hash) ProposalResponse {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+		require.NoError(t, server.handleGetProposal(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response ProposalResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+	assert.Zero(t, getProposal(types.Hash{0x92}).QueuePosition)
+	assert.Equal(t, 1, getProposal(types.Hash{0x93}).QueuePosition)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues("treasury")
+	require.NoError(t, server.handleGetProposalTrack(c))
+	var track ProposalTrackResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &track))
+	assert.Equal(t, uint16(1), track.MaxActive)
+	assert.Equal(t, []string{types.Hash{0x93}.String()}, track.Queue)
+}
+
+func TestDAOServer_SoulboundTokens(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	member := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{member.String(): 1000}))
+
+	get := func(handler echo.HandlerFunc, name, value, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/"+query, nil), rec)
+		c.SetParamNames(name)
+		c.SetParamValues(value)
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	rec := get(server.handleGetMemberSoulboundTokens, "address", member.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tokens []SoulboundTokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tokens))
+	require.Len(t, tokens, 1)
+	assert.Equal(t, dao.SoulboundKindMembership, tokens[0].Kind)
+	assert.Equal(t, member.String(), tokens[0].Holder)
+	assert.True(t, tokens[0].Active)
+
+	rec = get(server.handleGetSoulboundToken, "id", tokens[0].ID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var token SoulboundTokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &token))
+	assert.Equal(t, "Member", token.Name)
+
+	// Burned tokens are listed on request
+	testDAO.Soulbound.BurnMembership(member, time.Now().Unix())
+	rec = get(server.handleGetMemberSoulboundTokens, "address", member.String(), "")
+	assert.JSONEq(t, `[]`, rec.Body.String())
+	rec = get(server.handleGetMemberSoulboundTokens, "address", member.String(), "?include_burned=true")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tokens))
+	require.Len(t, tokens, 1)
+	assert.False(t, tokens[0].Active)
+	assert.Equal(t, http.StatusBadRequest, get(server.handleGetMemberSoulboundTokens, "address", member.String(), "?include_burned=maybe").Code)
+
+	assert.Equal(t, http.StatusNotFound, get(server.handleGetSoulboundToken, "id", types.Hash{0x01}.String(), "").Code)
+}
+
+func TestDAOServer_MemberStatement(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	sender := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{sender.String(): 1000}))
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.TokenTransferTx{Fee: 5, Recipient: recipient, Amount: 300}, sender, types.Hash{0x01}))
+
+	get := func(address, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/"+query, nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(address)
+		require.NoError(t, server.handleGetMemberStatement(c))
+		return rec
+	}
+
+	year := strconv.Itoa(time.Now().UTC().Year())
+	rec := get(sender.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var statement dao.HolderStatement
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statement))
+	assert.Equal(t, uint64(300), statement.TotalOutflows)
+	assert.Equal(t, uint64(5), statement.FeesPaid)
+	assert.Equal(t, int64(-305), statement.NetFlow)
+	require.Len(t, statement.Entries, 2)
+
+	rec = get(recipient.String(), "?year="+year+"&format=csv")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/csv")
+	assert.Contains(t, rec.Body.String(), ",inflow,token_transfer,"+sender.String())
+
+	assert.Equal(t, http.StatusBadRequest, get(sender.String(), "?year=soon").Code)
+	assert.Equal(t, http.StatusBadRequest, get(sender.String(), "?format=xml").Code)
+	assert.Equal(t, http.StatusBadRequest, get("not-an-address", "").Code)
+}
+
+func TestDAOServer_PrivateEventChannels(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+	e.GET("/ws", server.handleWebSocket)
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	signer := crypto.GeneratePrivateKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitializeTreasury([]crypto.PublicKey{signer.PublicKey(), other}, 2))
+	testDAO.AddTreasuryFunds(10000)
+
+	issue := func(key crypto.PrivateKey, signature string) *httptest.ResponseRecorder {
+		now := time.Now().Unix()
+		if signature == "" {
+			signature = signMemberRequest(t, key, "events_token", now, nil)
+		}
+		body := fmt.Sprintf(`{"address":%q,"timestamp":%d,"signature":%q}`, key.PublicKey().String(), now, signature)
+		req := httptest.NewRequest(http.MethodPost, "/dao/events/token", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleIssueEventToken(e.NewContext(req, rec)))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, issue(signer, strings.Repeat("00", 64)).Code)
+	rec := issue(signer, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var issued struct {
+		Token     string `json:"token"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+	require.NotEmpty(t, issued.Token)
+	assert.Greater(t, issued.ExpiresAt, time.Now().Unix())
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	dial := func(query string) (*websocket.Conn, int) {
+		conn, resp, err := websocket.DefaultDialer.Dial(url+query, nil)
+		if err != nil {
+			require.NotNil(t, resp)
+			return nil, resp.StatusCode
+		}
+		return conn, http.StatusSwitchingProtocols
+	}
+
+	// Bad tokens and private channels without a token are refused
+	_, status := dial("?token=unknown")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	_, status = dial("?channels=delegations")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	_, status = dial("?token=" + issued.Token + "&channels=everything")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// listen connects and reads events in the background. Connections
+	// register after the handshake, so it waits until events arrive.
+	listen := func(query string) chan Event {
+		conn, _ := dial(query)
+		require.NotNil(t, conn)
+		t.Cleanup(func() { conn.Close() })
+
+		events := make(chan Event, 100)
+		go func() {
+			defer close(events)
+			for {
+				_, message, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				var event Event
+				if json.Unmarshal(message, &event) == nil {
+					events <- event
+				}
+			}
+		}()
+		return events
+	}
+	read := func(events chan Event) (Event, bool) {
+		select {
+		case event, ok := <-events:
+			return event, ok
+		case <-time.After(200 * time.Millisecond):
+			return Event{}, false
+		}
+	}
+	drain := func(events chan Event) {
+		for {
+			if _, ok := read(events); !ok {
+				return
+			}
+		}
+	}
+	ready := func(events chan Event) {
+		for i := 0; i < 20; i++ {
+			server.broadcastEvent(Event{Type: EventVoteCast, Timestamp: time.Now().Unix()})
+			if _, ok := read(events); ok {
+				return
+			}
+		}
+		t.Fatal("connection did not receive events")
+	}
+
+	member := listen("?token=" + issued.Token)
+	anonymous := listen("")
+	ready(member)
+	ready(anonymous)
+	drain(member)
+	drain(anonymous)
+
+	// Signers are told about treasury transactions awaiting their signature
+	treasuryTx := &dao.TreasuryTx{Recipient: other, Amount: 500, Purpose: "Audit", Signatures: []crypto.Signature{}, RequiredSigs: 2}
+	require.NoError(t, testDAO.ProcessDAOTransaction(treasuryTx, other, types.Hash{0x01}))
+	event, ok := read(member)
+	require.True(t, ok)
+	assert.Equal(t, ChannelTreasurySignatures, event.Channel)
+	assert.Equal(t, EventTreasurySignatureRequested, event.Type)
+	assert.Equal(t, types.Hash{0x01}.String(), event.Data.(map[string]interface{})["transaction_id"])
+
+	// And about delegations they receive
+	server.privateEventsFor(&dao.DelegationTx{Delegate: signer.PublicKey(), Duration: 3600}, other, types.Hash{0x02})
+	event, ok = read(member)
+	require.True(t, ok)
+	assert.Equal(t, ChannelDelegations, event.Channel)
+	assert.Equal(t, EventDelegationActivity, event.Type)
+	assert.Equal(t, other.String(), event.Data.(map[string]interface{})["delegator"])
+
+	// Anonymous connections only see public events
+	server.broadcastEvent(Event{Type: EventProposalCreated, Timestamp: time.Now().Unix()})
+	event, ok = read(anonymous)
+	require.True(t, ok)
+	assert.Equal(t, EventProposalCreated, event.Type)
+	assert.Empty(t, event.Channel)
+
+	// Members only get the channels they asked for
+	filtered := listen("?token=" + issued.Token + "&channels=" + ChannelTreasurySignatures)
+	ready(filtered)
+	drain(filtered)
+	server.privateEventsFor(&dao.DelegationTx{Delegate: signer.PublicKey(), Duration: 3600}, other, types.Hash{0x03})
+	_, ok = read(filtered)
+	assert.False(t, ok)
+}
+
+func TestDAOServer_PreviewTreasuryTransaction(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	signer := crypto.GeneratePrivateKey()
+	require.NoError(t, testDAO.InitializeTreasury([]crypto.PublicKey{signer.PublicKey()}, 1))
+	testDAO.AddTreasuryFunds(1000)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/treasury/transaction/preview", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handlePreviewTreasuryTransaction(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := post(fmt.Sprintf(`{"recipient":%q,"amount":400,"purpose":"Audit","signer":%q}`, recipient.String(), signer.PublicKey().String()))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var preview dao.TreasuryPreview
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &preview))
+	assert.True(t, preview.Valid)
+	assert.Equal(t, uint64(600), preview.TreasuryAfter)
+	assert.Equal(t, uint64(400), preview.RecipientAfter)
+	assert.Equal(t, recipient.String(), preview.SigningPayload.Recipient)
+
+	// Problems come back as issues rather than errors
+	rec = post(fmt.Sprintf(`{"recipient":%q,"amount":4000,"purpose":"Audit"}`, recipient.String()))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &preview))
+	assert.False(t, preview.Valid)
+	require.Len(t, preview.Issues, 1)
+	assert.Equal(t, "amount", preview.Issues[0].Field)
+	assert.Equal(t, uint64(1000), preview.TreasuryAfter)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"recipient":"zz","amount":1,"purpose":"Audit"}`).Code)
+	assert.Equal(t, http.StatusNotFound, post(fmt.Sprintf(`{"transaction_id":%q}`, types.Hash{0x01}.String())).Code)
+}
+
+func TestDAOServer_HostedDAOs(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	registry := dao.NewDAORegistry(testDAO)
+	server.SetRegistry(registry)
+
+	e := echo.New()
+	e.GET("/daos", server.handleListDAOs)
+	e.POST("/daos", server.handleCreateDAO)
+	e.GET("/daos/:daoID", server.handleGetDAO)
+	e.Any("/daos/:daoID/*", server.handleHostedDAO)
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	privateKey := strings.Repeat("01", 32)
+
+	// Creation is checked and submitted to the default DAO
+	rec := request(http.MethodPost, "/daos", `{"id":"Acme!","name":"","token_symbol":"ACME","token_name":"Acme Token"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"id"`)
+	assert.Contains(t, rec.Body.String(), `"field":"name"`)
+
+	rec = request(http.MethodPost, "/daos", fmt.Sprintf(`{"id":"acme","name":"Acme Collective","token_symbol":"ACME","token_name":"Acme Token","decimals":6,"initial_supply":5000,"private_key":%q}`, privateKey))
+	require.Equal(t, http.StatusOK, rec.Code)
+	tx := <-txChan
+	createTx, ok := tx.TxInner.(*dao.DAOCreateTx)
+	require.True(t, ok)
+	assert.Equal(t, "acme", createTx.DAOID)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	testDAO.TokenState.Balances.Set(creator.String(), 1000)
+	require.NoError(t, registry.ApplyDAOTransaction(createTx, creator, types.Hash{0x01}, 1))
+
+	rec = request(http.MethodPost, "/daos", fmt.Sprintf(`{"id":"acme","name":"Acme","token_symbol":"ACME","token_name":"Acme Token","private_key":%q}`, privateKey))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// The registry lists the default DAO first
+	rec = request(http.MethodGet, "/daos", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed []DAOResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed, 2)
+	assert.Equal(t, dao.DefaultDAOID, listed[0].ID)
+	assert.Equal(t, "acme", listed[1].ID)
+	assert.Equal(t, uint64(5000), listed[1].TotalSupply)
+	assert.Equal(t, "/daos/acme", listed[1].BasePath)
+
+	rec = request(http.MethodGet, "/daos/acme", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"creator":"`+creator.String()+`"`)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/daos/unknown", "").Code)
+
+	// Every DAO serves the DAO endpoints under its prefix
+	rec = request(http.MethodGet, "/daos/acme/token/supply", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"total_supply":5000}`, rec.Body.String())
+	rec = request(http.MethodGet, "/daos/default/token/supply", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"total_supply":%d}`, testDAO.GetTotalSupply()), rec.Body.String())
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/daos/unknown/token/supply", "").Code)
+
+	// Submissions to a hosted DAO are wrapped for it
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	rec = request(http.MethodPost, "/daos/acme/token/transfer", fmt.Sprintf(`{"to":%q,"amount":10,"private_key":%q}`, recipient.String(), privateKey))
+	require.Equal(t, http.StatusOK, rec.Code)
+	tx = <-txChan
+	hostedTx, ok := tx.TxInner.(*dao.HostedDAOTx)
+	require.True(t, ok)
+	assert.Equal(t, "acme", hostedTx.DAOID)
+	assert.IsType(t, &dao.TokenTransferTx{}, hostedTx.Tx)
+
+	rec = request(http.MethodPost, "/daos/default/token/transfer", fmt.Sprintf(`{"to":%q,"amount":10,"private_key":%q}`, recipient.String(), privateKey))
+	require.Equal(t, http.StatusOK, rec.Code)
+	tx = <-txChan
+	assert.IsType(t, &dao.TokenTransferTx{}, tx.TxInner)
+}
+
+func TestDAOServer_CreateDAOFromGenesis(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	registry := dao.NewDAORegistry(testDAO)
+	server.SetRegistry(registry)
+
+	e := echo.New()
+	e.POST("/daos", server.handleCreateDAO)
+	e.POST("/daos/validate", server.handleValidateDAO)
+
+	request := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	genesis := fmt.Sprintf(`{
+		"token": {"symbol": "ACME", "name": "Acme Token", "decimals": 6},
+		"allocations": [{"address": %q, "amount": 10000}],
+		"founders": [%q],
+		"treasury": {"signers": [%q], "required_sigs": 1},
+		"parameters": {"voting_period": 172800},
+		"staking_pools": [{"id": "core", "name": "Core Stakers", "reward_rate": 5}]
+	}`, founder.String(), founder.String(), founder.String())
+
+	// The wizard checks every step at once
+	rec := request("/daos/validate", `{"id":"acme","name":"Acme","genesis":{"token":{"symbol":"ACME","name":"Acme Token"},"treasury":{"signers":[],"required_sigs":1},"parameters":{"voting_period":"soon"}}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var validation DAOValidationResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &validation))
+	assert.False(t, validation.Valid)
+	fields := make([]string, 0)
+	for _, issue := range validation.Issues {
+		fields = append(fields, issue.Field)
+	}
+	assert.ElementsMatch(t, []string{"genesis.treasury.signers", "genesis.treasury.required_sigs", "genesis.parameters.voting_period"}, fields)
+
+	// Without a genesis document the token fields keep their names
+	rec = request("/daos/validate", `{"id":"acme","name":"Acme","token_name":"Acme Token"}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &validation))
+	require.Len(t, validation.Issues, 1)
+	assert.Equal(t, "token_symbol", validation.Issues[0].Field)
+
+	rec = request("/daos/validate", `{"id":"acme","name":"Acme Collective","genesis":`+genesis+`}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &validation))
+	assert.True(t, validation.Valid)
+	assert.Empty(t, validation.Issues)
+
+	// Creation carries the genesis document to the registry
+	rec = request("/daos", `{"id":"acme","name":"Acme","genesis":{"token":{"symbol":"ACME","name":"Acme Token"},"treasury":{"signers":[],"required_sigs":0}},"private_key":"`+strings.Repeat("01", 32)+`"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"genesis.treasury.signers"`)
+
+	rec = request("/daos", `{"id":"acme","name":"Acme Collective","genesis":`+genesis+`,"private_key":"`+strings.Repeat("01", 32)+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	tx := <-txChan
+	createTx, ok := tx.TxInner.(*dao.DAOCreateTx)
+	require.True(t, ok)
+	assert.Equal(t, "172800", createTx.Genesis.Parameters["voting_period"])
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	testDAO.TokenState.Balances.Set(creator.String(), 1000)
+	require.NoError(t, registry.ApplyDAOTransaction(createTx, creator, types.Hash{0x01}, 1))
+	hosted, exists := registry.Get("acme")
+	require.True(t, exists)
+	assert.Equal(t, uint64(10000), hosted.DAO.GetTokenBalance(founder))
+	assert.Equal(t, int64(172800), hosted.DAO.GetParameterConfig().VotingPeriod)
+}
+
+func TestDAOServer_ScheduledParameters(t *testing.T) {
+	server, testDAO, txChan := setupTestDAOServer()
+	e := echo.New()
+
+	founderKey := crypto.GeneratePrivateKey()
+	founder := founderKey.PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleScheduleParameters(e.NewContext(req, rec)))
+		return rec
+	}
+	founderHex := hex.EncodeToString(founderKey.Bytes())
+
+	now := time.Now().Unix()
+	rec := post(fmt.Sprintf(`{"title":"Longer votes","changes":[{"parameter":"voting_period","value":{"days":2}}],"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":%q}`,
+		now, now+86400, founderHex))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(fmt.Sprintf(`{"title":"Longer votes","changes":[{"parameter":"voting_period","value":172800,"ramp_days":10},{"parameter":"delegation_enabled","value":false,"activation_height":100}],"voting_type":1,"start_time":%d,"end_time":%d,"threshold":5100,"private_key":%q}`,
+		now, now+86400, founderHex))
+	require.Equal(t, http.StatusOK, rec.Code)
+	scheduleTx, ok := (<-txChan).TxInner.(*dao.ParameterScheduleTx)
+	require.True(t, ok)
+	assert.Equal(t, "172800", scheduleTx.Changes[0].Value)
+	assert.Equal(t, int64(10*86400), scheduleTx.Changes[0].RampPeriod)
+	assert.Equal(t, "false", scheduleTx.Changes[1].Value)
+	require.NoError(t, testDAO.ProcessDAOTransaction(scheduleTx, founder, types.Hash{0xa0}))
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, server.handleGetPendingParameters(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var pending []dao.PendingParameterChange
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pending))
+	require.Len(t, pending, 2)
+	assert.Equal(t, dao.RolloutStatusVoting, pending[0].Status)
+	assert.Equal(t, uint32(100), pending[1].ActivationHeight)
+}
+
+func TestDAOServer_SimulateParameters(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+	testDAO.GovernanceState.Proposals[types.Hash{0xb0}] = &dao.Proposal{
+		ID:      types.Hash{0xb0},
+		Title:   "Fund the docs",
+		EndTime: time.Now().Unix() - 60,
+		Status:  dao.ProposalStatusPassed,
+		Results: &dao.VoteResults{YesVotes: 2500, NoVotes: 500, Quorum: 3000, Passed: true},
+	}
+
+	simulate := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleSimulateParameters(e.NewContext(httptest.NewRequest(http.MethodGet, "/dao/parameters/simulate?"+query, nil), rec)))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, simulate("").Code)
+	assert.Equal(t, http.StatusBadRequest, simulate("passing_threshold=0").Code)
+
+	rec := simulate("quorum_threshold=4000")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var sim dao.ParameterSimulation
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sim))
+	assert.Equal(t, 1, sim.Replayed)
+	assert.Equal(t, 1, sim.NewlyFailed)
+	assert.Equal(t, dao.OutcomeNoQuorum, sim.Proposals[0].SimulatedOutcome)
+}
+
+func TestRouteRegistry(t *testing.T) {
+	respond := func(body string) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return c.String(http.StatusOK, body+" "+requestAPIVersion(c))
+		}
+	}
+
+	routes := NewRouteRegistry("v1", "v2", "v3")
+	routes.GET("/dao/proposals", respond("list"))
+	routes.GET("/dao/legacy", respond("legacy"))
+	routes.Since("v2").GET("/dao/proposals", respond("paged"))
+	routes.Since("v3").Remove(http.MethodGet, "/dao/legacy")
+	routes.Any("/daos/:daoID/*", respond("hosted"))
+
+	e := echo.New()
+	routes.Mount(e, "v1", func(version string) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				c.Set(apiVersionKey, version)
+				return next(c)
+			}
+		}
+	})
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	// Versions keep their handlers side by side, the legacy one without prefix
+	assert.Equal(t, "list v1", request(http.MethodGet, "/dao/proposals").Body.String())
+	assert.Equal(t, "list v1", request(http.MethodGet, "/v1/dao/proposals").Body.String())
+	assert.Equal(t, "paged v2", request(http.MethodGet, "/v2/dao/proposals").Body.String())
+	assert.Equal(t, "paged v3", request(http.MethodGet, "/v3/dao/proposals").Body.String())
+	assert.Equal(t, "legacy v2", request(http.MethodGet, "/v2/dao/legacy").Body.String())
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/v3/dao/legacy").Code)
+	assert.Equal(t, "hosted v2", request(http.MethodDelete, "/v2/daos/acme/drafts/1").Body.String())
+
+	assert.Panics(t, func() { routes.Since("v4") })
+}
+
+func TestDAOServer_APIVersionDeprecation(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	server.SetRegistry(dao.NewDAORegistry(testDAO))
+
+	e := echo.New()
+	routes := NewRouteRegistry(APIVersions...)
+	server.registerDAORoutes(routes)
+	routes.Any("/daos/:daoID/*", server.handleHostedDAO)
+	routes.Mount(e, LegacyAPIVersion, server.versionMiddleware)
+	e.GET("/versions", server.handleGetAPIVersions)
+
+	request := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// Supported versions carry no deprecation headers
+	rec := request("/v1/dao/token/supply")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Equal(t, http.StatusOK, request("/dao/token/supply").Code)
+
+	deprecated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server.Config.Server.APIVersions = map[string]config.APIVersionConfig{
+		"v1": {Deprecated: deprecated, Sunset: sunset, Successor: "v2"},
+	}
+
+	for _, path := range []string{"/dao/token/supply", "/v1/dao/token/supply", "/v1/daos/default/token/supply"} {
+		rec = request(path)
+		require.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, fmt.Sprintf("@%d", deprecated.Unix()), rec.Header().Get("Deprecation"))
+		assert.Equal(t, sunset.Format(http.TimeFormat), rec.Header().Get("Sunset"))
+	}
+	assert.Equal(t, []string{`</v2/daos/default/token/supply>; rel="successor-version"`}, rec.Header().Values("Link"))
+
+	rec = request("/versions")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var versions []APIVersionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &versions))
+	require.Len(t, versions, 1)
+	assert.Equal(t, APIVersionResponse{Version: "v1", BasePath: "/v1", Legacy: true, Deprecated: deprecated.Unix(), Sunset: sunset.Unix(), Successor: "v2"}, versions[0])
+
+	// Sunset versions are no longer served
+	server.Config.Server.APIVersions = map[string]config.APIVersionConfig{"v1": {Sunset: time.Now().Add(-time.Minute)}}
+	assert.Equal(t, http.StatusGone, request("/v1/dao/token/supply").Code)
+	assert.Equal(t, http.StatusGone, request("/dao/token/supply").Code)
+}
+
+func TestDAOServer_SparseFieldsets(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	member := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{member.String(): 10000}))
+	proposalID := types.Hash{0xc0}
+	testDAO.GovernanceState.Proposals[proposalID] = &dao.Proposal{
+		ID:          proposalID,
+		Creator:     member,
+		Title:       "Fund the docs",
+		Description: "A long description the list screen does not show",
+		StartTime:   time.Now().Unix(),
+		EndTime:     time.Now().Unix() + 3600,
+		Status:      dao.ProposalStatusActive,
+	}
+
+	get := func(handler echo.HandlerFunc, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)))
+		return rec
+	}
+
+	rec := get(server.handleGetProposals, "/dao/proposals?fields=id,title,%20status")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, fmt.Sprintf(`[{"id":%q,"title":"Fund the docs","status":2}]`, proposalID.String()), rec.Body.String())
+
+	rec = get(server.handleGetProposals, "/dao/proposals?fields=id,private_key")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown field private_key")
+
+	// Without fields every field is returned
+	rec = get(server.handleGetProposals, "/dao/proposals")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"description"`)
+
+	rec = get(server.handleGetMembers, "/dao/members?fields=address,balance")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var members struct {
+		Members []map[string]interface{} `json:"members"`
+		Total   int                      `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &members))
+	require.Len(t, members.Members, 1)
+	assert.Equal(t, map[string]interface{}{"address": member.String(), "balance": float64(10000)}, members.Members[0])
+	assert.Equal(t, 1, members.Total)
+
+	rec = get(server.handleGetTreasuryTransactions, "/dao/treasury/transactions?fields=id,amount")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+	assert.Equal(t, http.StatusBadRequest, get(server.handleGetTreasuryTransactions, "/dao/treasury/transactions?fields=balance").Code)
+}
+
+func TestDAOServer_TokenBalances(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	carol := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   2000,
+		carol.String(): 3000,
+	}))
+	now := time.Now().Unix()
+	testDAO.GovernanceState.Delegations[carol.String()] = &dao.Delegation{Delegator: carol, Delegate: bob, StartTime: now - 60, EndTime: now + 3600, Active: true}
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.PrivacySettingsTx{Fee: 10, Reputation: dao.VisibilityPrivate}, alice, types.Hash{0xd0}))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/token/balances", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleGetTokenBalances(e.NewContext(req, rec)))
+		return rec
+	}
+
+	stranger := crypto.GeneratePrivateKey().PublicKey()
+	rec := post(fmt.Sprintf(`{"addresses":[%q,%q,%q,%q]}`, bob, carol, alice, stranger))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Balances []TokenBalanceResponse `json:"balances"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Balances, 4)
+
+	// Delegated power counts for the delegate only
+	assert.Equal(t, bob.String(), response.Balances[0].Address)
+	assert.Equal(t, uint64(2000), response.Balances[0].Balance)
+	assert.Equal(t, uint64(5000), response.Balances[0].VotingPower)
+	assert.Equal(t, uint64(3000), response.Balances[1].Balance)
+	assert.Zero(t, response.Balances[1].VotingPower)
+	assert.Equal(t, testDAO.GetEffectiveVotingPower(alice), response.Balances[2].VotingPower)
+	assert.Equal(t, []string{"reputation"}, response.Balances[2].Hidden)
+	assert.Equal(t, TokenBalanceResponse{Address: stranger.String()}, response.Balances[3])
+
+	rec = post(fmt.Sprintf(`{"addresses":[%q,"nope"]}`, bob))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"addresses[1]"`)
+	assert.Equal(t, http.StatusBadRequest, post(`{"addresses":[]}`).Code)
+
+	tooMany := make([]string, MaxBalanceLookups+1)
+	for i := range tooMany {
+		tooMany[i] = bob.String()
+	}
+	body, err := json.Marshal(map[string][]string{"addresses": tooMany})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, post(string(body)).Code)
+}
+
+func TestDAOServer_TokenBalancePrivacy(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   2000,
+	}))
+	require.NoError(t, testDAO.ProcessDAOTransaction(&dao.PrivacySettingsTx{Fee: 10, Balance: dao.VisibilityPrivate}, alice, types.Hash{0xd1}))
+
+	get := func(address crypto.PublicKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/dao/token/balance/"+address.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("address")
+		c.SetParamValues(address.String())
+		require.NoError(t, server.handleGetTokenBalance(c))
+		return rec
+	}
+
+	// A hidden balance is withheld as in the bulk lookup
+	rec := get(alice)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"balance":0,"hidden":["balance"]}`, rec.Body.String())
+
+	rec = get(bob)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"balance":2000}`, rec.Body.String())
+}
+
+func TestKeysFromHex(t *testing.T) {
+	for _, curve := range []crypto.Curve{crypto.CurveP256, crypto.CurveSecp256k1} {
+		key := crypto.GenerateCurvePrivateKey(curve)
+
+		parsed, err := privateKeyFromHex(key.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, key.PublicKey(), parsed.PublicKey())
+
+		pubKey, err := publicKeyFromHex(key.PublicKey().String())
+		require.NoError(t, err)
+		assert.Equal(t, key.PublicKey(), pubKey)
+	}
+
+	_, err := privateKeyFromHex("zz")
+	assert.Error(t, err)
+	_, err = privateKeyFromHex(strings.Repeat("00", 32))
+	assert.Error(t, err)
+
+	// Public keys must be points of their curve
+	_, err = publicKeyFromHex("02" + strings.Repeat("ff", 32))
+	assert.Error(t, err)
+	_, err = publicKeyFromHex("abcd")
+	assert.Error(t, err)
+}
+
+func TestDAOServer_AggregateTreasurySignature(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	signers := []crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey()}
+	require.NoError(t, testDAO.InitializeTreasury(signers, 2))
+	testDAO.AddTreasuryFunds(5000)
+
+	txID := types.Hash{0xa8}
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.CreateTreasuryTransaction(&dao.TreasuryTx{Fee: 100, Recipient: recipient, Amount: 1200, Purpose: "Audit", RequiredSigs: 2}, txID))
+
+	// Signers aggregate a signature of the digest the preview shows
+	preview, err := testDAO.PreviewTreasuryTransaction(dao.TreasuryPreviewRequest{TransactionID: txID, Recipient: recipient, Amount: 1200, Purpose: "Audit"})
+	require.NoError(t, err)
+	digest, err := hex.DecodeString(preview.SigningPayload.Digest)
+	require.NoError(t, err)
+
+	session, err := crypto.MuSig2{}.NewSession(signers, digest)
+	require.NoError(t, err)
+	for _, key := range keys {
+		_, err := session.Commit(key)
+		require.NoError(t, err)
+	}
+	for _, key := range keys {
+		_, err := session.Sign(key)
+		require.NoError(t, err)
+	}
+	sig, err := session.Signature()
+	require.NoError(t, err)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dao/treasury/aggregate", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.handleAttachAggregateSignature(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := post(fmt.Sprintf(`{"transaction_id":%q,"scheme":"bls","signers":["zz"],"signature":""}`, txID.String()))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"scheme"`)
+	assert.Contains(t, rec.Body.String(), `"field":"signers[0]"`)
+	assert.Contains(t, rec.Body.String(), `"field":"signature"`)
+
+	tampered := append([]byte(nil), sig.Data...)
+	tampered[64] ^= 0x01
+	body := `{"transaction_id":%q,"scheme":"musig2","signers":[%q,%q],"signature":%q}`
+	rec = post(fmt.Sprintf(body, txID.String(), sig.Signers[0].String(), sig.Signers[1].String(), hex.EncodeToString(tampered)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(fmt.Sprintf(body, txID.String(), sig.Signers[0].String(), sig.Signers[1].String(), hex.EncodeToString(sig.Data)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response TreasuryTransactionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Executed)
+	require.NotNil(t, response.Aggregate)
+	assert.Equal(t, "musig2", response.Aggregate.Scheme)
+	assert.Len(t, response.Aggregate.Signers, 2)
+	assert.Equal(t, uint64(3800), testDAO.GetTreasuryBalance())
+}
+
+func TestDAOServer_ThresholdTreasuryKey(t *testing.T) {
+	server, testDAO, _ := setupTestDAOServer()
+	e := echo.New()
+
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	signers := []crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey()}
+	require.NoError(t, testDAO.InitializeTreasury(signers, 2))
+	testDAO.AddTreasuryFunds(5000)
+
+	post := func(handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+	now := time.Now().Unix()
+
+	// Outsiders cannot start a ceremony
+	outsider := crypto.GeneratePrivateKey()
+	rec := post(server.handleStartKeyGeneration, fmt.Sprintf(`{"address":%q,"threshold":2,"timestamp":%d,"signature":%q}`,
+		outsider.PublicKey().String(), now, signMemberRequest(t, outsider, "start_treasury_keygen", now, []byte("2"))))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = post(server.handleStartKeyGeneration, fmt.Sprintf(`{"address":%q,"threshold":2,"timestamp":%d,"signature":%q}`,
+		signers[0].String(), now, signMemberRequest(t, keys[0], "start_treasury_keygen", now, []byte("2"))))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Each signer deals, signing the dealing as sent
+	participants := map[int]crypto.PublicKey{1: signers[0], 2: signers[1]}
+	dealers := make([]*crypto.DKGParticipant, len(keys))
+	dealings := make([]*crypto.DKGDealing, len(keys))
+	for i, key := range keys {
+		var err error
+		dealers[i], err = crypto.NewDKGParticipant(crypto.CurveP256, i+1, 2)
+		require.NoError(t, err)
+		dealings[i], err = dealers[i].Deal(participants)
+		require.NoError(t, err)
+
+		content := map[string]interface{}{
+			"from":        i + 1,
+			"commitments": []string{hex.EncodeToString(dealings[i].Commitments[0]), hex.EncodeToString(dealings[i].Commitments[1])},
+			"shares":      map[string]string{fmt.Sprint(2 - i): hex.EncodeToString(dealings[i].Shares[2-i])},
+			"proof":       hex.EncodeToString(dealings[i].Proof),
+		}
+		dealing, err := json.Marshal(content)
+		require.NoError(t, err)
+		rec = post(server.handleSubmitKeyDealing, fmt.Sprintf(`{"address":%q,"dealing":%s,"timestamp":%d,"signature":%q}`,
+			signers[i].String(), dealing, now, signMemberRequest(t, key, "deal_treasury_keygen", now, dealing)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	shares := make([]*crypto.ThresholdKey, len(keys))
+	for i, key := range keys {
+		var err error
+		shares[i], err = dealers[i].Finish(key, dealings)
+		require.NoError(t, err)
+		rec = post(server.handleConfirmKeyGeneration, fmt.Sprintf(`{"address":%q,"accept":true,"timestamp":%d,"signature":%q}`,
+			signers[i].String(), now, signMemberRequest(t, key, "confirm_treasury_keygen", now, []byte("accept"))))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/dao/treasury/tss", nil)
+	getRec := httptest.NewRecorder()
+	require.NoError(t, server.handleGetThresholdKey(e.NewContext(req, getRec)))
+	var status struct {
+		Key           *ThresholdKeyResponse  `json:"key"`
+		KeyGeneration *KeyGenerationResponse `json:"key_generation"`
+	}
+	require.NoError(t, json.Unmarshal(getRec.Body.Bytes(), &status))
+	require.NotNil(t, status.Key)
+	assert.Nil(t, status.KeyGeneration)
+	assert.Equal(t, shares[0].GroupKey.String(), status.Key.GroupKey)
+	assert.Len(t, status.Key.Shares, 2)
+
+	txID := types.Hash{0xb7}
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, testDAO.CreateTreasuryTransaction(&dao.TreasuryTx{Fee: 100, Recipient: recipient, Amount: 1200, Purpose: "Audit", RequiredSigs: 2}, txID))
+	preview, err := testDAO.PreviewTreasuryTransaction(dao.TreasuryPreviewRequest{TransactionID: txID, Recipient: recipient, Amount: 1200, Purpose: "Audit"})
+	require.NoError(t, err)
+	digest, err := hex.DecodeString(preview.SigningPayload.Digest)
+	require.NoError(t, err)
+
+	nonces := make(map[int]*crypto.ThresholdNonce)
+	commitments := make(map[int][]byte)
+	for i, share := range shares {
+		nonce, commitment, err := share.Commit()
+		require.NoError(t, err)
+		nonces[share.Index], commitments[share.Index] = nonce, commitment
+
+		encoded := hex.EncodeToString(commitment)
+		payload := []byte(txID.String() + ":" + encoded)
+		rec = post(server.handleCommitThresholdNonce, fmt.Sprintf(`{"address":%q,"transaction_id":%q,"commitment":%q,"timestamp":%d,"signature":%q}`,
+			signers[i].String(), txID.String(), encoded, now, signMemberRequest(t, keys[i], "commit_treasury_nonce", now, payload)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response TreasuryTransactionResponse
+	for i, share := range shares {
+		partial, err := share.Sign(nonces[share.Index], digest, commitments)
+		require.NoError(t, err)
+
+		encoded := hex.EncodeToString(partial)
+		payload := []byte(txID.String() + ":" + encoded)
+		rec = post(server.handleSubmitThresholdPartial, fmt.Sprintf(`{"address":%q,"transaction_id":%q,"partial":%q,"timestamp":%d,"signature":%q}`,
+			signers[i].String(), txID.String(), encoded, now, signMemberRequest(t, keys[i], "sign_treasury_partial", now, payload)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	}
+
+	assert.True(t, response.Executed)
+	require.NotNil(t, response.Threshold)
+	assert.Len(t, response.Threshold.Partials, 2)
+	assert.NotEmpty(t, response.Threshold.Signature)
+	assert.Equal(t, uint64(3800), testDAO.GetTreasuryBalance())
+}
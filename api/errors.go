@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/labstack/echo/v4"
+)
+
+// Error codes of failures that do not come from the DAO. DAO failures use
+// the name of their dao.ErrorCode, such as "voting_closed".
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeGone             = "gone"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+	CodeUpstream         = "upstream_error"
+	CodeUnavailable      = "unavailable"
+
+	// CodeIdempotencyKeyReused rejects an Idempotency-Key sent with a
+	// different request than the one it was first used for
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+)
+
+// APIError is the body of every failed request. Clients branch on Code,
+// Message is meant for people and may change.
+type APIError struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Fields    []FieldError           `json:"fields,omitempty"`
+	Retryable bool                   `json:"retryable"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// FieldError is a problem with one field of a request, or with the request
+// as a whole when Field is empty
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// statusCodes are the codes of errors without a DAO error code
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          CodeInvalidRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusMethodNotAllowed:    CodeMethodNotAllowed,
+	http.StatusConflict:            CodeConflict,
+	http.StatusGone:                CodeGone,
+	http.StatusTooManyRequests:     CodeRateLimited,
+	http.StatusInternalServerError: CodeInternal,
+	http.StatusBadGateway:          CodeUpstream,
+	http.StatusServiceUnavailable:  CodeUnavailable,
+	http.StatusGatewayTimeout:      CodeUpstream,
+}
+
+// newAPIError builds the error envelope of a message answered with status
+func newAPIError(status int, message string) APIError {
+	code, exists := statusCodes[status]
+	if !exists {
+		code = CodeInternal
+		if status < http.StatusInternalServerError {
+			code = CodeInvalidRequest
+		}
+	}
+
+	return APIError{
+		Code:    code,
+		Message: message,
+		Retryable: status == http.StatusTooManyRequests || status == http.StatusBadGateway ||
+			status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout,
+	}
+}
+
+// errorFromErr builds the error envelope of err. DAO errors keep their code,
+// message and details, other errors are coded by status.
+func errorFromErr(status int, err error) APIError {
+	var daoErr *dao.DAOError
+	if !errors.As(err, &daoErr) {
+		return newAPIError(status, err.Error())
+	}
+
+	apiErr := newAPIError(status, daoErr.Message)
+	apiErr.Code = daoErr.Code.String()
+	apiErr.Retryable = apiErr.Retryable || daoErr.Code.Retryable()
+	// Details that cannot be encoded are left out rather than failing the
+	// response
+	if _, err := json.Marshal(daoErr.Details); err == nil {
+		apiErr.Details = daoErr.Details
+	}
+	return apiErr
+}
+
+// errorResponse answers a request that failed with err
+func errorResponse(c echo.Context, status int, err error) error {
+	return c.JSON(status, errorFromErr(status, err))
+}
+
+// errorMessage answers a failed request with a message
+func errorMessage(c echo.Context, status int, message string) error {
+	return c.JSON(status, newAPIError(status, message))
+}
+
+// fieldErrorResponse answers a request with invalid fields
+func fieldErrorResponse(c echo.Context, message string, fields []FieldError) error {
+	apiErr := newAPIError(http.StatusBadRequest, message)
+	apiErr.Fields = fields
+	return c.JSON(http.StatusBadRequest, apiErr)
+}
+
+// httpErrorHandler answers errors returned by handlers and by echo itself,
+// such as unknown routes, in the same envelope
+func httpErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Code
+		err = errors.New(fmt.Sprint(httpErr.Message))
+	}
+
+	if c.Request().Method == http.MethodHead {
+		c.NoContent(status)
+		return
+	}
+	errorResponse(c, status, err)
+}
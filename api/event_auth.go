@@ -0,0 +1,258 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/labstack/echo/v4"
+)
+
+// Private event channels, delivered only to the member they concern over an
+// authenticated WebSocket
+const (
+	// ChannelTreasurySignatures carries treasury transactions awaiting the
+	// member's signature
+	ChannelTreasurySignatures = "treasury_signatures"
+	// ChannelDelegations carries delegations the member made or received
+	ChannelDelegations = "delegations"
+)
+
+// privateChannels are the channels members can subscribe to
+var privateChannels = map[string]bool{
+	ChannelTreasurySignatures: true,
+	ChannelDelegations:        true,
+}
+
+const (
+	// eventTokenTTL is how long an event token authenticates connections
+	eventTokenTTL = 24 * time.Hour
+	// eventTokenMaxEntries bounds the tokens kept in memory
+	eventTokenMaxEntries = 10000
+)
+
+// EventTokenStore keeps the tokens members authenticate event connections
+// with. Tokens are opaque and only valid on the node that issued them.
+type EventTokenStore struct {
+	tokens map[string]eventToken
+	mu     sync.Mutex
+}
+
+// eventToken is the member a token was issued to
+type eventToken struct {
+	member  string
+	expires time.Time
+}
+
+// NewEventTokenStore creates an empty token store
+func NewEventTokenStore() *EventTokenStore {
+	return &EventTokenStore{tokens: make(map[string]eventToken)}
+}
+
+// Issue creates a token for member, dropping expired tokens when the store
+// is full
+func (ts *EventTokenStore) Issue(member crypto.PublicKey, now time.Time) (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate event token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+	expires := now.Add(eventTokenTTL)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if len(ts.tokens) >= eventTokenMaxEntries {
+		for key, issued := range ts.tokens {
+			if !now.Before(issued.expires) {
+				delete(ts.tokens, key)
+			}
+		}
+		if len(ts.tokens) >= eventTokenMaxEntries {
+			return "", time.Time{}, fmt.Errorf("too many event tokens, try again later")
+		}
+	}
+	ts.tokens[token] = eventToken{member: member.String(), expires: expires}
+
+	return token, expires, nil
+}
+
+// Lookup returns the member a token was issued to while it is valid
+func (ts *EventTokenStore) Lookup(token string, now time.Time) (string, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	issued, exists := ts.tokens[token]
+	if !exists {
+		return "", false
+	}
+	if !now.Before(issued.expires) {
+		delete(ts.tokens, token)
+		return "", false
+	}
+	return issued.member, true
+}
+
+// privateMessage is an event for the listed members on one channel
+type privateMessage struct {
+	channel    string
+	recipients map[string]bool
+	message    []byte
+}
+
+// subscribes reports whether an authenticated client takes events of a
+// private channel
+func (c *wsClient) subscribes(channel string) bool {
+	if c.member == "" {
+		return false
+	}
+	return c.channels == nil || c.channels[channel]
+}
+
+// handleIssueEventToken issues a token for authenticating event connections
+// to a member who signed the request
+func (s *DAOServer) handleIssueEventToken(c echo.Context) error {
+	var req struct {
+		Address   string `json:"address"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	member, err := verifyMemberRequest("events_token", req.Address, req.Timestamp, nil, req.Signature)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, err)
+	}
+
+	token, expires, err := s.eventTokens.Issue(member, time.Now())
+	if err != nil {
+		return errorResponse(c, http.StatusServiceUnavailable, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"token":      token,
+		"expires_at": expires.Unix(),
+	})
+}
+
+// eventSubscription authenticates an event connection by the token in its
+// Authorization header or token query parameter, and returns the member and
+// the private channels it asked for, nil for all. Connections without a
+// token are anonymous and only receive public events.
+func (s *DAOServer) eventSubscription(c echo.Context) (string, map[string]bool, int, error) {
+	token := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.QueryParam("token")
+	}
+
+	param := c.QueryParam("channels")
+	if token == "" {
+		if param != "" {
+			return "", nil, http.StatusUnauthorized, fmt.Errorf("private channels need an event token")
+		}
+		return "", nil, http.StatusOK, nil
+	}
+
+	member, ok := s.eventTokens.Lookup(token, time.Now())
+	if !ok {
+		return "", nil, http.StatusUnauthorized, fmt.Errorf("invalid or expired event token")
+	}
+	if param == "" {
+		return member, nil, http.StatusOK, nil
+	}
+
+	channels := make(map[string]bool)
+	for _, channel := range strings.Split(param, ",") {
+		channel = strings.TrimSpace(channel)
+		if !privateChannels[channel] {
+			return "", nil, http.StatusBadRequest, fmt.Errorf("unknown channel %q", channel)
+		}
+		channels[channel] = true
+	}
+	return member, channels, http.StatusOK, nil
+}
+
+// sendPrivateEvent delivers an event on a private channel to the members'
+// authenticated connections. Private events are not numbered, kept for
+// replay or passed to subscribers, and are dropped rather than wait when
+// the fan-out is behind.
+func (s *DAOServer) sendPrivateEvent(channel string, event Event, recipients []crypto.PublicKey) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	event.Channel = channel
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	members := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		members[recipient.String()] = true
+	}
+
+	select {
+	case s.eventBus.private <- privateMessage{channel: channel, recipients: members, message: message}:
+	default:
+	}
+}
+
+// privateEventsFor sends the private events of an applied DAO transaction
+func (s *DAOServer) privateEventsFor(txInner interface{}, from crypto.PublicKey, txHash types.Hash) {
+	now := time.Now().Unix()
+
+	switch tx := txInner.(type) {
+	case *dao.TreasuryTx:
+		pendingTx, exists := s.dao.GetTreasuryTransaction(txHash)
+		if !exists {
+			return
+		}
+		s.sendPrivateEvent(ChannelTreasurySignatures, Event{
+			Type: EventTreasurySignatureRequested,
+			Data: map[string]interface{}{
+				"transaction_id": txHash.String(),
+				"recipient":      pendingTx.Recipient.String(),
+				"amount":         pendingTx.Amount,
+				"purpose":        pendingTx.Purpose,
+				"signatures":     pendingTx.SignatureCount(),
+				"required_sigs":  s.dao.GetRequiredSignatures(),
+				"expires_at":     pendingTx.ExpiresAt,
+			},
+			Timestamp: now,
+		}, s.dao.GetTreasuryAwaitingSigners(txHash))
+
+	case *dao.DelegationTx:
+		recipients := []crypto.PublicKey{from}
+		data := map[string]interface{}{
+			"delegator": from.String(),
+			"revoked":   tx.Revoke,
+		}
+		if len(tx.Delegate) > 0 {
+			data["delegate"] = tx.Delegate.String()
+			if tx.Delegate.String() != from.String() {
+				recipients = append(recipients, tx.Delegate)
+			}
+		}
+		if !tx.Revoke {
+			data["duration"] = tx.Duration
+			data["auto_renew"] = tx.AutoRenew
+		}
+		s.sendPrivateEvent(ChannelDelegations, Event{
+			Type:      EventDelegationActivity,
+			Data:      data,
+			Timestamp: now,
+		}, recipients)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FieldSet is the sparse fieldset a client asks for with ?fields=, the JSON
+// names of the fields to return of each item of a list. A nil FieldSet
+// returns every field.
+type FieldSet map[string]bool
+
+// parseFieldSet reads the comma separated fields query parameter and checks
+// the names against the JSON fields of item
+func parseFieldSet(c echo.Context, item interface{}) (FieldSet, error) {
+	value := c.QueryParam("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(item))
+	fields := make(FieldSet)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown field %s, expected any of %s", name, strings.Join(names, ", "))
+		}
+		fields[name] = true
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// Select returns the items of a list with only the fields of the set.
+// Fields left out of an item's JSON because they are empty stay left out.
+func (f FieldSet) Select(items interface{}) (interface{}, error) {
+	if f == nil {
+		return items, nil
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+
+	selected := make([]map[string]json.RawMessage, len(objects))
+	for i, object := range objects {
+		selected[i] = make(map[string]json.RawMessage, len(f))
+		for name, value := range object {
+			if f[name] {
+				selected[i][name] = value
+			}
+		}
+	}
+	return selected, nil
+}
+
+// jsonFieldNames returns the names the fields of a struct type have in
+// JSON, with those of embedded structs
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFieldNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// sparseJSON sends a list response with the fields of the set
+func sparseJSON(c echo.Context, fields FieldSet, items interface{}) error {
+	selected, err := fields.Select(items)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, selected)
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/labstack/echo/v4"
+)
+
+// hostedServer serves the DAO endpoints of one DAO in the registry
+type hostedServer struct {
+	server *DAOServer
+	router *echo.Echo
+}
+
+// DAOResponse describes a DAO the node hosts
+type DAOResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	TokenSymbol string `json:"token_symbol"`
+	TokenName   string `json:"token_name"`
+	Decimals    uint8  `json:"decimals"`
+	TotalSupply uint64 `json:"total_supply"`
+	Creator     string `json:"creator,omitempty"`
+	CreatedAt   int64  `json:"created_at,omitempty"`
+	BasePath    string `json:"base_path"` // Prefix of the DAO's endpoints
+}
+
+func newDAOResponse(hosted *dao.HostedDAO) DAOResponse {
+	return DAOResponse{
+		ID:          hosted.ID,
+		Name:        hosted.Config.Name,
+		Description: hosted.Config.Description,
+		TokenSymbol: hosted.Config.TokenSymbol,
+		TokenName:   hosted.Config.TokenName,
+		Decimals:    hosted.Config.Decimals,
+		TotalSupply: hosted.DAO.TokenState.TotalSupply,
+		Creator:     hosted.Creator,
+		CreatedAt:   hosted.CreatedAt,
+		BasePath:    "/daos/" + hosted.ID,
+	}
+}
+
+// SetRegistry serves the DAOs of registry under /daos/:daoID. The default
+// DAO, the one the server was created with, stays served under /dao as well.
+func (s *DAOServer) SetRegistry(registry *dao.DAORegistry) {
+	s.hostedMu.Lock()
+	s.registry = registry
+	s.hosted[dao.DefaultDAOID] = &hostedServer{server: s, router: s.newDAORouter()}
+	s.hostedMu.Unlock()
+
+	registry.OnDAOCreated(s.addHostedServer)
+	for _, hosted := range registry.List() {
+		if hosted.ID != dao.DefaultDAOID {
+			s.addHostedServer(hosted)
+		}
+	}
+}
+
+// addHostedServer creates the server of a hosted DAO, with an event bus of
+// its own
+func (s *DAOServer) addHostedServer(hosted *dao.HostedDAO) {
+	s.hostedMu.Lock()
+	defer s.hostedMu.Unlock()
+
+	if _, exists := s.hosted[hosted.ID]; exists {
+		return
+	}
+
+	server := newDAOServer(s.Server, hosted.DAO, s.eventTokens)
+	server.daoID = hosted.ID
+	s.hosted[hosted.ID] = &hostedServer{server: server, router: server.newDAORouter()}
+}
+
+// hostedServer returns the server of a DAO in the registry
+func (s *DAOServer) hostedServer(id string) (*hostedServer, bool) {
+	s.hostedMu.RLock()
+	defer s.hostedMu.RUnlock()
+
+	hosted, exists := s.hosted[id]
+	return hosted, exists
+}
+
+// newDAORouter routes the DAO endpoints of the server in every API version
+func (s *DAOServer) newDAORouter() *echo.Echo {
+	e := s.newEcho()
+	routes := NewRouteRegistry(APIVersions...)
+	s.registerDAORoutes(routes)
+	routes.Mount(e, LegacyAPIVersion, s.versionMiddleware)
+	return e
+}
+
+// newDAOTransaction wraps a DAO transaction for submission to the DAO the
+// server serves
+func (s *DAOServer) newDAOTransaction(txInner interface{}) *core.Transaction {
+	if s.daoID != "" {
+		txInner = &dao.HostedDAOTx{DAOID: s.daoID, Tx: txInner}
+	}
+	return newDAOTransaction(txInner)
+}
+
+// handleHostedDAO serves /daos/:daoID/* as the /dao/* endpoint of the DAO,
+// in the API version of the request
+func (s *DAOServer) handleHostedDAO(c echo.Context) error {
+	hosted, exists := s.hostedServer(c.Param("daoID"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrDAONotFoundError)
+	}
+
+	req := c.Request()
+	req.URL.Path = "/" + requestAPIVersion(c) + "/dao/" + c.Param("*")
+	req.URL.RawPath = ""
+	hosted.router.ServeHTTP(c.Response(), req)
+	return nil
+}
+
+func (s *DAOServer) handleListDAOs(c echo.Context) error {
+	if s.registry == nil {
+		return errorMessage(c, http.StatusNotFound, "this node does not host other DAOs")
+	}
+
+	hosted := s.registry.List()
+	response := make([]DAOResponse, len(hosted))
+	for i, h := range hosted {
+		response[i] = newDAOResponse(h)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *DAOServer) handleGetDAO(c echo.Context) error {
+	if s.registry == nil {
+		return errorMessage(c, http.StatusNotFound, "this node does not host other DAOs")
+	}
+
+	hosted, exists := s.registry.Get(c.Param("daoID"))
+	if !exists {
+		return errorResponse(c, http.StatusNotFound, dao.ErrDAONotFoundError)
+	}
+
+	return c.JSON(http.StatusOK, newDAOResponse(hosted))
+}
+
+// CreateDAORequest describes a hosted DAO to create. Without a genesis
+// document the token fields describe the DAO's token, and the creator holds
+// the initial supply and signs for the treasury alone.
+type CreateDAORequest struct {
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Genesis       *dao.Genesis `json:"genesis,omitempty"`
+	TokenSymbol   string       `json:"token_symbol"`
+	TokenName     string       `json:"token_name"`
+	Decimals      uint8        `json:"decimals"`
+	InitialSupply uint64       `json:"initial_supply"`
+}
+
+// shorthandGenesisFields are the request fields the genesis a request
+// without a genesis document is built from
+var shorthandGenesisFields = map[string]string{
+	"token.symbol":          "token_symbol",
+	"token.name":            "token_name",
+	"token.decimals":        "decimals",
+	"allocations[0].amount": "initial_supply",
+}
+
+// genesis returns the genesis document the DAO is created from
+func (r *CreateDAORequest) genesis(creator crypto.PublicKey) dao.Genesis {
+	if r.Genesis != nil {
+		return *r.Genesis
+	}
+
+	genesis := dao.Genesis{
+		Token: dao.GenesisToken{
+			Symbol:   r.TokenSymbol,
+			Name:     r.TokenName,
+			Decimals: r.Decimals,
+		},
+		Treasury: dao.GenesisTreasury{
+			Signers:      []string{creator.String()},
+			RequiredSigs: 1,
+		},
+	}
+	if r.InitialSupply > 0 {
+		genesis.Allocations = []dao.GenesisAllocation{{Address: creator.String(), Amount: r.InitialSupply}}
+	}
+	return genesis
+}
+
+// check reports everything that would keep the DAO from being created
+func (r *CreateDAORequest) check(creator crypto.PublicKey) []dao.DraftIssue {
+	issues := make([]dao.DraftIssue, 0)
+	if !dao.ValidDAOID(r.ID) {
+		issues = append(issues, dao.DraftIssue{Field: "id", Message: "ID must be 3 to 32 lowercase letters, digits and dashes"})
+	}
+	if len(r.Name) == 0 || len(r.Name) > 100 {
+		issues = append(issues, dao.DraftIssue{Field: "name", Message: "Name must be between 1 and 100 characters"})
+	}
+	if len(r.Description) > 1000 {
+		issues = append(issues, dao.DraftIssue{Field: "description", Message: "Description must be at most 1000 characters"})
+	}
+
+	genesis := r.genesis(creator)
+	for _, issue := range genesis.Check() {
+		if r.Genesis != nil {
+			issue.Field = "genesis." + issue.Field
+		} else if field, ok := shorthandGenesisFields[issue.Field]; ok {
+			issue.Field = field
+		} else {
+			// The rest of a shorthand genesis is the creator's, which is
+			// checked with the private key
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// handleCreateDAO submits the creation of a hosted DAO, paid for in the
+// default DAO's token
+func (s *DAOServer) handleCreateDAO(c echo.Context) error {
+	if s.registry == nil {
+		return errorMessage(c, http.StatusNotFound, "this node does not host other DAOs")
+	}
+
+	var req struct {
+		CreateDAORequest
+		PrivateKey string `json:"private_key"`
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	privKey, keyErr := privateKeyFromHex(req.PrivateKey)
+	var creator crypto.PublicKey
+	if keyErr == nil {
+		creator = privKey.PublicKey()
+	}
+
+	if issues := req.check(creator); len(issues) > 0 {
+		return fieldErrorResponse(c, "invalid DAO", draftFieldErrors(issues))
+	}
+
+	if _, exists := s.registry.Get(req.ID); exists {
+		return errorMessage(c, http.StatusConflict, "DAO ID is already taken")
+	}
+
+	if keyErr != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid private key format")
+	}
+
+	createTx := &dao.DAOCreateTx{
+		Fee:         s.Config.DAO.Fees.Default,
+		DAOID:       req.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Genesis:     req.genesis(creator),
+	}
+
+	return s.submitDAOTxWithEvent(c, createTx, privKey, "DAO creation submitted", EventDAOCreated, map[string]interface{}{
+		"dao_id": req.ID,
+		"name":   req.Name,
+	})
+}
+
+// DAOValidationResponse reports whether a DAO would be created
+type DAOValidationResponse struct {
+	Valid  bool             `json:"valid"`
+	Issues []dao.DraftIssue `json:"issues"`
+}
+
+// handleValidateDAO checks a DAO creation request without submitting it, so
+// that a creation wizard can report every problem at once
+func (s *DAOServer) handleValidateDAO(c echo.Context) error {
+	if s.registry == nil {
+		return errorMessage(c, http.StatusNotFound, "this node does not host other DAOs")
+	}
+
+	var req CreateDAORequest
+	if err := c.Bind(&req); err != nil {
+		return errorMessage(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	issues := req.check(nil)
+	if _, exists := s.registry.Get(req.ID); exists {
+		issues = append(issues, dao.DraftIssue{Field: "id", Message: "DAO ID is already taken"})
+	}
+
+	return c.JSON(http.StatusOK, DAOValidationResponse{
+		Valid:  len(issues) == 0,
+		Issues: issues,
+	})
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IdempotencyHeader carries the key clients retry submissions with
+const IdempotencyHeader = "Idempotency-Key"
+
+const (
+	// maxIdempotencyKeyLength bounds the keys clients can send
+	maxIdempotencyKeyLength = 255
+	// idempotencyMaxEntries bounds the results kept in memory
+	idempotencyMaxEntries = 10000
+	// DefaultIdempotencyMaxBody bounds the bodies read to fingerprint a
+	// request when no other limit is configured
+	DefaultIdempotencyMaxBody = 1 << 20
+)
+
+// IdempotencyCache keeps the responses of POST requests by their
+// Idempotency-Key, so a retried submission gets the original transaction
+// hash instead of being signed, charged and submitted again
+type IdempotencyCache struct {
+	ttl     time.Duration
+	maxBody int64 // Largest body read to fingerprint a request
+	entries map[string]*idempotentResult
+	mu      sync.Mutex
+}
+
+// idempotentResult is a request seen with a key, done once it has a response
+type idempotentResult struct {
+	fingerprint [sha256.Size]byte
+	done        bool
+	status      int
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+// NewIdempotencyCache creates a cache keeping responses for ttl. Requests
+// with a key and a body larger than maxBody are rejected,
+// DefaultIdempotencyMaxBody applies when maxBody is not positive.
+func NewIdempotencyCache(ttl time.Duration, maxBody int64) *IdempotencyCache {
+	if maxBody <= 0 {
+		maxBody = DefaultIdempotencyMaxBody
+	}
+	return &IdempotencyCache{
+		ttl:     ttl,
+		maxBody: maxBody,
+		entries: make(map[string]*idempotentResult),
+	}
+}
+
+// begin claims key for a request. It returns the result of an earlier
+// request with the key if there is one, which may still be in progress.
+func (ic *IdempotencyCache) begin(key string, fingerprint [sha256.Size]byte, now time.Time) *idempotentResult {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if result, exists := ic.entries[key]; exists && (!result.done || now.Before(result.expires)) {
+		return result
+	}
+
+	if len(ic.entries) >= idempotencyMaxEntries {
+		ic.evict(now)
+	}
+	ic.entries[key] = &idempotentResult{fingerprint: fingerprint}
+	return nil
+}
+
+// evict drops the expired results, or the one expiring first when none has
+func (ic *IdempotencyCache) evict(now time.Time) {
+	var oldest string
+	for key, result := range ic.entries {
+		if !result.done {
+			continue
+		}
+		if !now.Before(result.expires) {
+			delete(ic.entries, key)
+			continue
+		}
+		if oldest == "" || result.expires.Before(ic.entries[oldest].expires) {
+			oldest = key
+		}
+	}
+	if len(ic.entries) >= idempotencyMaxEntries && oldest != "" {
+		delete(ic.entries, oldest)
+	}
+}
+
+// finish keeps the response of a successful request, failed requests
+// release the key so they can be retried
+func (ic *IdempotencyCache) finish(key string, status int, contentType string, body []byte, now time.Time) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	result, exists := ic.entries[key]
+	if !exists {
+		return
+	}
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		delete(ic.entries, key)
+		return
+	}
+
+	result.done = true
+	result.status = status
+	result.contentType = contentType
+	result.body = body
+	result.expires = now.Add(ic.ttl)
+}
+
+// Middleware makes POST requests carrying an Idempotency-Key idempotent.
+// Retries with the same key and request replay the first successful
+// response, retries while it is running are rejected, and reusing a key for
+// a different request is an error.
+func (ic *IdempotencyCache) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idempotencyKey := c.Request().Header.Get(IdempotencyHeader)
+		if c.Request().Method != http.MethodPost || idempotencyKey == "" {
+			return next(c)
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return errorMessage(c, http.StatusBadRequest, "Idempotency-Key is too long")
+		}
+
+		req := c.Request()
+		// The body is held in memory to fingerprint it, so it is bounded
+		body, err := io.ReadAll(io.LimitReader(req.Body, ic.maxBody+1))
+		if err != nil {
+			return errorMessage(c, http.StatusBadRequest, "failed to read request body")
+		}
+		if int64(len(body)) > ic.maxBody {
+			return errorMessage(c, http.StatusRequestEntityTooLarge, "request body is too large")
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := req.URL.Path + "\n" + idempotencyKey
+		fingerprint := sha256.Sum256(append([]byte(req.URL.RawQuery+"\n"), body...))
+
+		if earlier := ic.begin(key, fingerprint, time.Now()); earlier != nil {
+			if earlier.fingerprint != fingerprint {
+				apiErr := newAPIError(http.StatusUnprocessableEntity, "Idempotency-Key was used for a different request")
+				apiErr.Code = CodeIdempotencyKeyReused
+				return c.JSON(http.StatusUnprocessableEntity, apiErr)
+			}
+			if !earlier.done {
+				apiErr := newAPIError(http.StatusConflict, "a request with this Idempotency-Key is in progress")
+				apiErr.Retryable = true
+				return c.JSON(http.StatusConflict, apiErr)
+			}
+
+			c.Response().Header().Set("Idempotent-Replayed", "true")
+			return c.Blob(earlier.status, earlier.contentType, earlier.body)
+		}
+
+		res := c.Response()
+		writer := res.Writer
+		recorder := &responseRecorder{ResponseWriter: writer, status: http.StatusOK}
+		res.Writer = recorder
+
+		// A panicking handler releases the key on its way to the recover
+		// middleware, or every retry would be told it is still in progress
+		finished := false
+		defer func() {
+			if !finished {
+				res.Writer = writer
+				ic.finish(key, http.StatusInternalServerError, "", nil, time.Now())
+			}
+		}()
+		handlerErr := next(c)
+		res.Writer = writer
+		finished = true
+
+		if !res.Committed {
+			ic.finish(key, http.StatusInternalServerError, "", nil, time.Now())
+			return handlerErr
+		}
+		res.Committed = false
+		res.Size = 0
+
+		contentType := res.Header().Get(echo.HeaderContentType)
+		ic.finish(key, recorder.status, contentType, recorder.body.Bytes(), time.Now())
+
+		res.WriteHeader(recorder.status)
+		if _, err := res.Write(recorder.body.Bytes()); err != nil {
+			return err
+		}
+		return handlerErr
+	}
+}
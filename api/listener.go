@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// forwardedHeaders are set by reverse proxies to describe the original
+// request, any client can send them too
+var forwardedHeaders = []string{
+	echo.HeaderXForwardedFor,
+	echo.HeaderXForwardedProto,
+	echo.HeaderXForwardedProtocol,
+	echo.HeaderXForwardedSsl,
+	echo.HeaderXUrlScheme,
+	echo.HeaderXRealIP,
+	"X-Forwarded-Host",
+	"Forwarded",
+}
+
+// newEcho creates a router with the setup both API servers share: the error
+// envelope and which peers forwarding headers are believed from
+func (s *Server) newEcho() *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler
+
+	proxies, err := s.Config.Server.TrustedProxyRanges()
+	if err != nil {
+		s.Logger.Log("msg", "ignoring trusted proxies", "err", err)
+		proxies = nil
+	}
+
+	e.Pre(stripForwardedHeaders(proxies))
+	if len(proxies) == 0 {
+		e.IPExtractor = echo.ExtractIPDirect()
+		return e
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range proxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+	e.IPExtractor = echo.ExtractIPFromXFFHeader(options...)
+	return e
+}
+
+// stripForwardedHeaders drops the forwarding headers of requests that do
+// not come straight from a trusted proxy, so clients cannot pick the IP and
+// scheme the API sees
+func stripForwardedHeaders(proxies []*net.IPNet) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !fromProxy(req, proxies) {
+				for _, header := range forwardedHeaders {
+					req.Header.Del(header)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// fromProxy reports whether the peer of req is one of the proxies
+func fromProxy(req *http.Request, proxies []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// listen serves e on the listen address, over TLS when it is configured
+func (s *Server) listen(e *echo.Echo) error {
+	tls := s.Config.Server.TLS
+
+	if len(tls.ACMEDomains) > 0 {
+		e.AutoTLSManager = autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(tls.ACMEDomains...),
+			Cache:      autocert.DirCache(tls.ACMECacheDir),
+			Email:      tls.ACMEEmail,
+		}
+		return e.StartAutoTLS(s.ListenAddr)
+	}
+	if tls.CertFile != "" {
+		return e.StartTLS(s.ListenAddr, tls.CertFile, tls.KeyFile)
+	}
+	return e.Start(s.ListenAddr)
+}
@@ -3,10 +3,13 @@ package api
 import (
 	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/BOCK-CHAIN/BockChain/config"
 	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/logging"
 	"github.com/BOCK-CHAIN/BockChain/types"
 	"github.com/go-kit/log"
 	"github.com/labstack/echo/v4"
@@ -17,15 +20,12 @@ type TxResponse struct {
 	Hashes  []string
 }
 
-type APIError struct {
-	Error string
-}
-
 type Block struct {
 	Hash          string
 	Version       uint32
 	DataHash      string
 	PrevBlockHash string
+	StateRoot     string
 	Height        uint32
 	Timestamp     int64
 	Validator     string
@@ -34,18 +34,65 @@ type Block struct {
 	TxResponse TxResponse
 }
 
+// PeerInfo describes a peer known to the node
+type PeerInfo struct {
+	Addr        string `json:"addr"`
+	Connected   bool   `json:"connected"`
+	Outgoing    bool   `json:"outgoing"`
+	Score       int    `json:"score"`
+	Banned      bool   `json:"banned"`
+	BannedUntil int64  `json:"banned_until,omitempty"`
+	LastSeen    int64  `json:"last_seen,omitempty"`
+}
+
+// PeerSource reports the peers of the node for network diagnostics
+type PeerSource interface {
+	Peers() []PeerInfo
+}
+
+// TxSubmitter checks a transaction and queues it in the mempool of the
+// node, returning why it was rejected. Rejections wrap ErrTxInvalid,
+// ErrTxKnown or ErrTxUnavailable so they are answered with the right status.
+type TxSubmitter interface {
+	SubmitTransaction(tx *core.Transaction) error
+}
+
+var (
+	// ErrTxInvalid rejects a transaction that failed its checks
+	ErrTxInvalid = errors.New("invalid transaction")
+	// ErrTxKnown rejects a transaction the node has already seen
+	ErrTxKnown = errors.New("transaction already known")
+	// ErrTxUnavailable rejects a transaction the node cannot take right now,
+	// such as while it shuts down. Submitting it again later can succeed.
+	ErrTxUnavailable = errors.New("transaction intake is unavailable")
+)
+
 type ServerConfig struct {
 	Logger     log.Logger
 	ListenAddr string
+	// Config is the node configuration, the defaults are used when nil
+	Config *config.Config
+	// LogControl lets the admin API change logging, which is fixed when nil
+	LogControl *logging.Logger
 }
 
 type Server struct {
 	txChan chan *core.Transaction
 	ServerConfig
 	bc *core.Blockchain
+
+	peers     PeerSource
+	submitter TxSubmitter
+
+	// intakePaused is set while the admin API pauses transaction intake
+	intakePaused int32
 }
 
 func NewServer(cfg ServerConfig, bc *core.Blockchain, txChan chan *core.Transaction) *Server {
+	if cfg.Config == nil {
+		cfg.Config = config.Default()
+	}
+
 	return &Server{
 		ServerConfig: cfg,
 		bc:           bc,
@@ -54,36 +101,108 @@ func NewServer(cfg ServerConfig, bc *core.Blockchain, txChan chan *core.Transact
 }
 
 func (s *Server) Start() error {
-	e := echo.New()
+	e := s.newEcho()
 
 	e.GET("/block/:hashorid", s.handleGetBlock)
 	e.GET("/tx/:hash", s.handleGetTx)
 	e.POST("/tx", s.handlePostTx)
+	e.GET("/network/peers", s.handleGetPeers)
+	e.GET("/chain/feed", s.handleBlockFeed)
+
+	return s.listen(e)
+}
+
+// checkOrigin reports whether a browser request comes from an allowed origin.
+// Requests without an Origin header are not from browsers and are allowed.
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || s.Config.Server.AllowsOrigin(origin)
+}
+
+// SetPeerSource sets where /network/peers reads peers from. It must be
+// called before the server is started.
+func (s *Server) SetPeerSource(peers PeerSource) {
+	s.peers = peers
+}
 
-	return e.Start(s.ListenAddr)
+// SetTxSubmitter makes submissions go to submitter rather than the
+// transaction channel, so they are checked before the request is answered.
+// It must be called before the server is started.
+func (s *Server) SetTxSubmitter(submitter TxSubmitter) {
+	s.submitter = submitter
+}
+
+func (s *Server) handleGetPeers(c echo.Context) error {
+	if s.peers == nil {
+		return errorMessage(c, http.StatusServiceUnavailable, "peer information is not available")
+	}
+
+	peers := s.peers.Peers()
+	connected := 0
+	for _, peer := range peers {
+		if peer.Connected {
+			connected++
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"connected": connected,
+		"known":     len(peers),
+		"peers":     peers,
+	})
 }
 
 func (s *Server) handlePostTx(c echo.Context) error {
 	tx := &core.Transaction{}
 	if err := gob.NewDecoder(c.Request().Body).Decode(tx); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return errorResponse(c, http.StatusBadRequest, err)
+	}
+	if err := s.submitTx(tx); err != nil {
+		return errorResponse(c, submitStatus(err), err)
 	}
-	s.txChan <- tx
 
 	return nil
 }
 
+// submitTx sends a transaction to the node and tracks it as pending
+func (s *Server) submitTx(tx *core.Transaction) error {
+	if s.IntakePaused() {
+		return ErrIntakePaused
+	}
+	if s.submitter != nil {
+		return s.submitter.SubmitTransaction(tx)
+	}
+
+	s.txChan <- tx
+	s.bc.TxTracker().Submitted(tx.Hash(core.TxHasher{}))
+	return nil
+}
+
+// submitStatus returns the status a submission rejected with err is
+// answered with. Only rejections that can succeed when retried are
+// unavailable, a duplicate conflicts and anything else is a bad request.
+func submitStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrIntakePaused), errors.Is(err, ErrTxUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrTxKnown):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func (s *Server) handleGetTx(c echo.Context) error {
 	hash := c.Param("hash")
 
 	b, err := hex.DecodeString(hash)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return errorResponse(c, http.StatusBadRequest, err)
 	}
 
 	tx, err := s.bc.GetTxByHash(types.HashFromBytes(b))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return errorResponse(c, http.StatusBadRequest, err)
 	}
 
 	return c.JSON(http.StatusOK, tx)
@@ -97,7 +216,7 @@ func (s *Server) handleGetBlock(c echo.Context) error {
 	if err == nil {
 		block, err := s.bc.GetBlock(uint32(height))
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+			return errorResponse(c, http.StatusBadRequest, err)
 		}
 
 		return c.JSON(http.StatusOK, intoJSONBlock(block))
@@ -106,17 +225,64 @@ func (s *Server) handleGetBlock(c echo.Context) error {
 	// otherwise assume its the hash
 	b, err := hex.DecodeString(hashOrID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return errorResponse(c, http.StatusBadRequest, err)
 	}
 
 	block, err := s.bc.GetBlockByHash(types.HashFromBytes(b))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return errorResponse(c, http.StatusBadRequest, err)
 	}
 
 	return c.JSON(http.StatusOK, intoJSONBlock(block))
 }
 
+// handleBlockFeed streams the blocks from the height given by ?from=
+// (default 1) onwards as one gob stream, and keeps streaming blocks as they
+// are added until the client disconnects. Read replicas follow the chain
+// through it and validate every block themselves.
+func (s *Server) handleBlockFeed(c echo.Context) error {
+	height := uint32(1)
+	if value := c.QueryParam("from"); value != "" {
+		from, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || from == 0 {
+			return errorMessage(c, http.StatusBadRequest, "from must be a positive block height")
+		}
+		height = uint32(from)
+	}
+	if height <= s.bc.Height() {
+		if _, err := s.bc.GetBlock(height); err != nil {
+			return errorResponse(c, http.StatusGone, err)
+		}
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEOctetStream)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	encoder := gob.NewEncoder(res)
+	for {
+		added := s.bc.BlockAdded()
+		for ; height <= s.bc.Height(); height++ {
+			block, err := s.bc.GetBlock(height)
+			if err != nil {
+				return nil
+			}
+			if err := encoder.Encode(block); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+
+		select {
+		case <-added:
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
 func intoJSONBlock(block *core.Block) Block {
 	txResponse := TxResponse{
 		TxCount: uint(len(block.Transactions)),
@@ -133,6 +299,7 @@ func intoJSONBlock(block *core.Block) Block {
 		Height:        block.Header.Height,
 		DataHash:      block.Header.DataHash.String(),
 		PrevBlockHash: block.Header.PrevBlockHash.String(),
+		StateRoot:     block.Header.StateRoot.String(),
 		Timestamp:     block.Header.Timestamp,
 		Validator:     block.Validator.Address().String(),
 		Signature:     block.Signature.String(),
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIVersions are the versions of the REST API the server serves, oldest
+// first. Each is served under /<version>/.
+var APIVersions = []string{"v1"}
+
+// LegacyAPIVersion is the version served on paths without a version prefix,
+// the paths app releases from before versioning call
+const LegacyAPIVersion = "v1"
+
+// apiVersionKey is the context key of the version a request is served by
+const apiVersionKey = "api_version"
+
+// methodAny registers a route for every method
+const methodAny = "*"
+
+// RouteRegistry collects the endpoints of every API version and mounts
+// them on a router. A route applies from the version it is registered for
+// to the latest, so a new version only registers the endpoints whose
+// requests or responses it changes, and older versions keep serving the
+// previous handlers:
+//
+//	r.GET("/dao/proposals", s.handleGetProposals)
+//	r.Since("v2").GET("/dao/proposals", s.handleGetProposalsV2)
+type RouteRegistry struct {
+	table *routeTable
+	since int // Index of the first version routes registered here apply to
+}
+
+type routeTable struct {
+	versions []string
+	routes   map[string]*versionedRoute // By method and path
+	order    []*versionedRoute
+}
+
+// versionedRoute is an endpoint with the handlers of the versions it
+// changed in
+type versionedRoute struct {
+	method   string
+	path     string
+	handlers []versionedHandler // By first version, ascending
+}
+
+type versionedHandler struct {
+	since      int
+	handler    echo.HandlerFunc // nil when the endpoint is removed
+	middleware []echo.MiddlewareFunc
+}
+
+// NewRouteRegistry creates a registry for versions, oldest first
+func NewRouteRegistry(versions ...string) *RouteRegistry {
+	return &RouteRegistry{table: &routeTable{
+		versions: versions,
+		routes:   make(map[string]*versionedRoute),
+	}}
+}
+
+// Since returns a view of the registry whose routes apply from version on
+func (r *RouteRegistry) Since(version string) *RouteRegistry {
+	for i, v := range r.table.versions {
+		if v == version {
+			return &RouteRegistry{table: r.table, since: i}
+		}
+	}
+	panic(fmt.Sprintf("api: unknown API version %s", version))
+}
+
+// GET registers a GET endpoint
+func (r *RouteRegistry) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) {
+	r.Add(http.MethodGet, path, h, m...)
+}
+
+// POST registers a POST endpoint
+func (r *RouteRegistry) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) {
+	r.Add(http.MethodPost, path, h, m...)
+}
+
+// PUT registers a PUT endpoint
+func (r *RouteRegistry) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) {
+	r.Add(http.MethodPut, path, h, m...)
+}
+
+// DELETE registers a DELETE endpoint
+func (r *RouteRegistry) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) {
+	r.Add(http.MethodDelete, path, h, m...)
+}
+
+// Any registers an endpoint for every method
+func (r *RouteRegistry) Any(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) {
+	r.Add(methodAny, path, h, m...)
+}
+
+// Remove drops an endpoint from the versions of the view
+func (r *RouteRegistry) Remove(method, path string) {
+	r.Add(method, path, nil)
+}
+
+// Add registers the handler of an endpoint for the versions of the view,
+// replacing the handler of earlier versions from there on
+func (r *RouteRegistry) Add(method, path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) {
+	key := method + " " + path
+	route, exists := r.table.routes[key]
+	if !exists {
+		route = &versionedRoute{method: method, path: path}
+		r.table.routes[key] = route
+		r.table.order = append(r.table.order, route)
+	}
+
+	handler := versionedHandler{since: r.since, handler: h, middleware: m}
+	for i, existing := range route.handlers {
+		if existing.since == r.since {
+			route.handlers[i] = handler
+			return
+		}
+		if existing.since > r.since {
+			route.handlers = append(route.handlers[:i], append([]versionedHandler{handler}, route.handlers[i:]...)...)
+			return
+		}
+	}
+	route.handlers = append(route.handlers, handler)
+}
+
+// handler returns the handler of the route in the version at index, nil
+// when the route is not part of it
+func (route *versionedRoute) handler(index int) *versionedHandler {
+	var served *versionedHandler
+	for i := range route.handlers {
+		if route.handlers[i].since > index {
+			break
+		}
+		served = &route.handlers[i]
+	}
+	if served == nil || served.handler == nil {
+		return nil
+	}
+	return served
+}
+
+// Mount registers the endpoints of every version on e under the version
+// prefix, and those of legacy on the paths without prefix. versioned
+// returns the middleware that runs first on the requests of a version.
+func (r *RouteRegistry) Mount(e *echo.Echo, legacy string, versioned func(version string) echo.MiddlewareFunc) {
+	for i, version := range r.table.versions {
+		prefixes := []string{"/" + version}
+		if version == legacy {
+			prefixes = append(prefixes, "")
+		}
+
+		for _, route := range r.table.order {
+			served := route.handler(i)
+			if served == nil {
+				continue
+			}
+			middleware := append([]echo.MiddlewareFunc{versioned(version)}, served.middleware...)
+			for _, prefix := range prefixes {
+				if route.method == methodAny {
+					e.Any(prefix+route.path, served.handler, middleware...)
+				} else {
+					e.Add(route.method, prefix+route.path, served.handler, middleware...)
+				}
+			}
+		}
+	}
+}
+
+// APIVersionResponse describes a version of the REST API
+type APIVersionResponse struct {
+	Version    string `json:"version"`
+	BasePath   string `json:"base_path"`
+	Legacy     bool   `json:"legacy,omitempty"`     // Also served without the prefix
+	Deprecated int64  `json:"deprecated,omitempty"` // Unix time the version is deprecated from
+	Sunset     int64  `json:"sunset,omitempty"`     // Unix time the version stops being served
+	Successor  string `json:"successor,omitempty"`
+}
+
+// versionMiddleware marks the requests of version, announces its
+// deprecation with the Deprecation, Sunset and Link headers, and answers
+// 410 Gone once its sunset has passed
+func (s *DAOServer) versionMiddleware(version string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(apiVersionKey, version)
+
+			policy, scheduled := s.Config.Server.APIVersions[version]
+			if !scheduled {
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			if !policy.Deprecated.IsZero() {
+				header.Set("Deprecation", fmt.Sprintf("@%d", policy.Deprecated.Unix()))
+			}
+			if !policy.Sunset.IsZero() {
+				header.Set("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+			}
+			// Hosted DAO requests pass through twice, linked from the outer path
+			if policy.Successor != "" && !strings.Contains(header.Get("Link"), `rel="successor-version"`) {
+				path := strings.TrimPrefix(c.Request().URL.Path, "/"+version)
+				header.Add("Link", fmt.Sprintf(`</%s%s>; rel="successor-version"`, policy.Successor, path))
+			}
+
+			if !policy.Sunset.IsZero() && !time.Now().Before(policy.Sunset) {
+				return errorMessage(c, http.StatusGone, fmt.Sprintf("API version %s is no longer served", version))
+			}
+			return next(c)
+		}
+	}
+}
+
+// requestAPIVersion returns the version serving a request
+func requestAPIVersion(c echo.Context) string {
+	if version, ok := c.Get(apiVersionKey).(string); ok {
+		return version
+	}
+	return LegacyAPIVersion
+}
+
+// handleGetAPIVersions lists the versions of the REST API and their
+// deprecation schedule
+func (s *DAOServer) handleGetAPIVersions(c echo.Context) error {
+	response := make([]APIVersionResponse, len(APIVersions))
+	for i, version := range APIVersions {
+		response[i] = APIVersionResponse{
+			Version:  version,
+			BasePath: "/" + version,
+			Legacy:   version == LegacyAPIVersion,
+		}
+		if policy, exists := s.Config.Server.APIVersions[version]; exists {
+			if !policy.Deprecated.IsZero() {
+				response[i].Deprecated = policy.Deprecated.Unix()
+			}
+			if !policy.Sunset.IsZero() {
+				response[i].Sunset = policy.Sunset.Unix()
+			}
+			response[i].Successor = policy.Successor
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
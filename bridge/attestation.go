@@ -0,0 +1,117 @@
+package bridge
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// AttesterSignature is an attester's signature over an attestation digest
+type AttesterSignature struct {
+	Attester  crypto.PublicKey
+	Signature crypto.Signature
+}
+
+// Attestation is an inbound message from an external chain, vouched for by
+// the attesters watching that chain. Acknowledging an outbound message, its
+// MessageID names the message the external chain executed.
+type Attestation struct {
+	SourceChain string
+	Nonce       uint64 // Increases by one per message from a chain
+	MessageID   types.Hash
+	Payload     []byte
+	Signatures  []AttesterSignature
+}
+
+// Digest returns the hash attesters sign
+func (a *Attestation) Digest() types.Hash {
+	h := sha256.New()
+	h.Write([]byte("bockchain-bridge-inbound"))
+	writeBytes(h, []byte(a.SourceChain))
+	binary.Write(h, binary.BigEndian, a.Nonce)
+	h.Write(a.MessageID.ToSlice())
+	writeBytes(h, a.Payload)
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// Sign adds an attester's signature to the attestation
+func (a *Attestation) Sign(key crypto.PrivateKey) error {
+	digest := a.Digest()
+	sig, err := key.Sign(digest.ToSlice())
+	if err != nil {
+		return err
+	}
+	a.Signatures = append(a.Signatures, AttesterSignature{Attester: key.PublicKey(), Signature: *sig})
+	return nil
+}
+
+// Verifier accepts attestations signed by a threshold of a fixed attester
+// set, each nonce of a chain once
+type Verifier struct {
+	mu        sync.Mutex
+	attesters map[string]crypto.PublicKey
+	threshold int
+	seen      map[string]map[uint64]bool
+}
+
+// NewVerifier creates a verifier requiring threshold of the attesters
+func NewVerifier(attesters []crypto.PublicKey, threshold int) (*Verifier, error) {
+	if threshold <= 0 || threshold > len(attesters) {
+		return nil, fmt.Errorf("threshold %d out of range for %d attesters", threshold, len(attesters))
+	}
+
+	set := make(map[string]crypto.PublicKey, len(attesters))
+	for _, attester := range attesters {
+		key := attester.String()
+		if _, exists := set[key]; exists {
+			return nil, fmt.Errorf("duplicate attester %s", key)
+		}
+		set[key] = attester
+	}
+
+	return &Verifier{
+		attesters: set,
+		threshold: threshold,
+		seen:      make(map[string]map[uint64]bool),
+	}, nil
+}
+
+// Verify checks an attestation is signed by enough distinct attesters and
+// has not been accepted before, and records it as accepted
+func (v *Verifier) Verify(att *Attestation) error {
+	if att.SourceChain == "" {
+		return fmt.Errorf("attestation has no source chain")
+	}
+
+	digest := att.Digest()
+	signed := make(map[string]bool)
+	for _, sig := range att.Signatures {
+		key := sig.Attester.String()
+		if _, known := v.attesters[key]; !known || signed[key] {
+			continue
+		}
+		if sig.Signature.R == nil || sig.Signature.S == nil || !sig.Signature.Verify(sig.Attester, digest.ToSlice()) {
+			return fmt.Errorf("invalid signature from attester %s", key)
+		}
+		signed[key] = true
+	}
+	if len(signed) < v.threshold {
+		return fmt.Errorf("attestation has %d of %d required signatures", len(signed), v.threshold)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.seen[att.SourceChain][att.Nonce] {
+		return fmt.Errorf("attestation %d from %s already accepted", att.Nonce, att.SourceChain)
+	}
+	if v.seen[att.SourceChain] == nil {
+		v.seen[att.SourceChain] = make(map[uint64]bool)
+	}
+	v.seen[att.SourceChain][att.Nonce] = true
+	return nil
+}
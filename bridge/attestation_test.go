@@ -0,0 +1,42 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	attesters := []crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey(), keys[2].PublicKey()}
+
+	_, err := NewVerifier(attesters, 4)
+	assert.Error(t, err)
+	_, err = NewVerifier([]crypto.PublicKey{attesters[0], attesters[0]}, 1)
+	assert.Error(t, err)
+	verifier, err := NewVerifier(attesters, 2)
+	require.NoError(t, err)
+
+	att := &Attestation{SourceChain: "ethereum", Nonce: 7, Payload: []byte("executed")}
+
+	// Signatures from the same attester or outsiders do not count
+	require.NoError(t, att.Sign(keys[0]))
+	require.NoError(t, att.Sign(keys[0]))
+	require.NoError(t, att.Sign(crypto.GeneratePrivateKey()))
+	assert.Error(t, verifier.Verify(att))
+
+	require.NoError(t, att.Sign(keys[1]))
+	require.NoError(t, verifier.Verify(att))
+
+	// Each nonce of a chain is accepted once
+	assert.Error(t, verifier.Verify(att))
+
+	// Signatures cover the payload
+	tampered := &Attestation{SourceChain: "ethereum", Nonce: 8, Payload: []byte("executed")}
+	require.NoError(t, tampered.Sign(keys[0]))
+	require.NoError(t, tampered.Sign(keys[2]))
+	tampered.Payload = []byte("forged")
+	assert.Error(t, verifier.Verify(tampered))
+}
@@ -0,0 +1,188 @@
+// Package bridge carries governance decisions to external chains. Passed
+// proposals with external targets emit execution messages, encoded per chain
+// by an adapter and queued for a relayer to deliver, and inbound messages
+// from those chains are accepted once enough attesters have signed them.
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Adapter encodes calls for an external chain
+type Adapter interface {
+	Chain() string
+	Encode(call Call) ([]byte, error)
+}
+
+// Relayer delivers messages to their external chain and returns a reference
+// to the delivery, such as the hash of the submitting transaction
+type Relayer interface {
+	Relay(ctx context.Context, msg Message) (string, error)
+}
+
+// Target is a call a proposal makes on an external chain once passed
+type Target struct {
+	Chain string
+	Call  Call
+}
+
+// Bridge emits execution messages for proposals with external targets and
+// verifies inbound attestations
+type Bridge struct {
+	mu       sync.Mutex
+	adapters map[string]Adapter
+	targets  map[types.Hash][]Target
+	emitted  map[types.Hash]bool
+	queue    *Queue
+	verifier *Verifier
+}
+
+// NewBridge creates a bridge to the chains of the adapters
+func NewBridge(verifier *Verifier, adapters ...Adapter) *Bridge {
+	b := &Bridge{
+		adapters: make(map[string]Adapter),
+		targets:  make(map[types.Hash][]Target),
+		emitted:  make(map[types.Hash]bool),
+		queue:    NewQueue(),
+		verifier: verifier,
+	}
+	for _, adapter := range adapters {
+		b.adapters[adapter.Chain()] = adapter
+	}
+	return b
+}
+
+// Queue returns the bridge's outbound message queue
+func (b *Bridge) Queue() *Queue {
+	return b.queue
+}
+
+// AddTarget adds an external call to a proposal. The call is checked
+// against its chain's adapter now rather than when the proposal passes.
+func (b *Bridge) AddTarget(proposalID types.Hash, target Target) error {
+	adapter, exists := b.adapters[target.Chain]
+	if !exists {
+		return fmt.Errorf("no adapter for chain %q", target.Chain)
+	}
+	if _, err := adapter.Encode(target.Call); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.emitted[proposalID] {
+		return fmt.Errorf("proposal %s has already emitted its messages", proposalID)
+	}
+	b.targets[proposalID] = append(b.targets[proposalID], target)
+	return nil
+}
+
+// Emit queues the messages of a proposal once it has passed. Each proposal
+// emits its messages once.
+func (b *Bridge) Emit(proposal *dao.Proposal) ([]Message, error) {
+	if proposal.Status != dao.ProposalStatusPassed && proposal.Status != dao.ProposalStatusExecuted {
+		return nil, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	targets := b.targets[proposal.ID]
+	if len(targets) == 0 || b.emitted[proposal.ID] {
+		return nil, nil
+	}
+
+	// Encode every target before queueing any so a proposal emits all of
+	// its messages or none
+	payloads := make([][]byte, len(targets))
+	for i, target := range targets {
+		payload, err := b.adapters[target.Chain].Encode(target.Call)
+		if err != nil {
+			return nil, fmt.Errorf("encoding call %d of proposal %s: %w", i, proposal.ID, err)
+		}
+		payloads[i] = payload
+	}
+
+	messages := make([]Message, len(targets))
+	for i, target := range targets {
+		messages[i] = b.queue.Enqueue(target.Chain, proposal.ID, target.Call, payloads[i])
+	}
+	b.emitted[proposal.ID] = true
+	return messages, nil
+}
+
+// Sync emits the messages of every targeted proposal of the DAO that has
+// passed since the last sync
+func (b *Bridge) Sync(d *dao.DAO) ([]Message, error) {
+	b.mu.Lock()
+	var waiting []types.Hash
+	for id := range b.targets {
+		if !b.emitted[id] {
+			waiting = append(waiting, id)
+		}
+	}
+	b.mu.Unlock()
+
+	var messages []Message
+	for _, id := range waiting {
+		proposal, err := d.GetProposal(id)
+		if err != nil {
+			continue
+		}
+		emitted, err := b.Emit(proposal)
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, emitted...)
+	}
+	return messages, nil
+}
+
+// RelayPending hands the queued messages to a relayer, oldest first, and
+// returns how many were delivered. A message is given up on after failing
+// maxAttempts times.
+func (b *Bridge) RelayPending(ctx context.Context, relayer Relayer, maxAttempts int) (int, error) {
+	relayed := 0
+	for _, msg := range b.queue.Pending() {
+		if err := ctx.Err(); err != nil {
+			return relayed, err
+		}
+
+		ref, err := relayer.Relay(ctx, msg)
+		if err != nil {
+			if markErr := b.queue.MarkFailed(msg.ID, err, maxAttempts); markErr != nil {
+				return relayed, markErr
+			}
+			continue
+		}
+		if err := b.queue.MarkRelayed(msg.ID, ref); err != nil {
+			return relayed, err
+		}
+		relayed++
+	}
+	return relayed, nil
+}
+
+// Receive verifies an inbound attestation. One acknowledging an outbound
+// message must come from the chain the message was sent to.
+func (b *Bridge) Receive(att *Attestation) error {
+	if b.verifier == nil {
+		return fmt.Errorf("bridge has no attesters")
+	}
+	if !att.MessageID.IsZero() {
+		msg, exists := b.queue.Get(att.MessageID)
+		if !exists {
+			return fmt.Errorf("message %s not found", att.MessageID)
+		}
+		if msg.Chain != att.SourceChain {
+			return fmt.Errorf("message %s was sent to %s, not %s", att.MessageID, msg.Chain, att.SourceChain)
+		}
+	}
+	return b.verifier.Verify(att)
+}
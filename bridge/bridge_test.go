@@ -0,0 +1,101 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRelayer struct {
+	fail      bool
+	delivered []Message
+}
+
+func (r *testRelayer) Relay(ctx context.Context, msg Message) (string, error) {
+	if r.fail {
+		return "", errors.New("relay failed")
+	}
+	r.delivered = append(r.delivered, msg)
+	return "0x" + msg.ID.String()[:8], nil
+}
+
+func TestBridge_EmitAndRelay(t *testing.T) {
+	adapter, err := NewSafeModuleAdapter("ethereum", 1, "0x5afe000000000000000000000000000000005afe")
+	require.NoError(t, err)
+	b := NewBridge(nil, adapter)
+
+	d := dao.NewDAO("GOV", "Governance Token", 18)
+	proposalID := types.Hash{0x01}
+	d.GovernanceState.Proposals[proposalID] = &dao.Proposal{ID: proposalID, Status: dao.ProposalStatusActive}
+
+	call := Call{To: "0x00000000000000000000000000000000000000aa", Data: []byte{0x01}}
+	assert.Error(t, b.AddTarget(proposalID, Target{Chain: "solana", Call: call}))
+	assert.Error(t, b.AddTarget(proposalID, Target{Chain: "ethereum", Call: Call{To: "bad"}}))
+	require.NoError(t, b.AddTarget(proposalID, Target{Chain: "ethereum", Call: call}))
+
+	// Nothing is emitted until the proposal passes, and then only once
+	messages, err := b.Sync(d)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	d.GovernanceState.Proposals[proposalID].Status = dao.ProposalStatusPassed
+	messages, err = b.Sync(d)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, proposalID, messages[0].ProposalID)
+	assert.Equal(t, SafeExecSelector(), messages[0].Payload[:4])
+
+	messages, err = b.Sync(d)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+	assert.Error(t, b.AddTarget(proposalID, Target{Chain: "ethereum", Call: call}))
+
+	// Failed deliveries are retried until they succeed
+	relayer := &testRelayer{fail: true}
+	relayed, err := b.RelayPending(context.Background(), relayer, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, relayed)
+	assert.Len(t, b.Queue().Pending(), 1)
+
+	relayer.fail = false
+	relayed, err = b.RelayPending(context.Background(), relayer, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+	assert.Empty(t, b.Queue().Pending())
+	require.Len(t, relayer.delivered, 1)
+	msg, _ := b.Queue().Get(relayer.delivered[0].ID)
+	assert.Equal(t, 2, msg.Attempts)
+}
+
+func TestBridge_Receive(t *testing.T) {
+	key := crypto.GeneratePrivateKey()
+	verifier, err := NewVerifier([]crypto.PublicKey{key.PublicKey()}, 1)
+	require.NoError(t, err)
+	adapter, err := NewSafeModuleAdapter("ethereum", 1, "0x5afe000000000000000000000000000000005afe")
+	require.NoError(t, err)
+	b := NewBridge(verifier, adapter)
+
+	msg := b.Queue().Enqueue("ethereum", types.Hash{0x01}, Call{}, nil)
+
+	// Acknowledgements must name a known message of their chain
+	unknown := &Attestation{SourceChain: "ethereum", Nonce: 0, MessageID: types.Hash{0xEE}}
+	require.NoError(t, unknown.Sign(key))
+	assert.Error(t, b.Receive(unknown))
+
+	wrongChain := &Attestation{SourceChain: "gnosis", Nonce: 0, MessageID: msg.ID}
+	require.NoError(t, wrongChain.Sign(key))
+	assert.Error(t, b.Receive(wrongChain))
+
+	ack := &Attestation{SourceChain: "ethereum", Nonce: 0, MessageID: msg.ID}
+	require.NoError(t, ack.Sign(key))
+	require.NoError(t, b.Receive(ack))
+	assert.Error(t, b.Receive(ack))
+
+	assert.Error(t, NewBridge(nil).Receive(ack))
+}
@@ -0,0 +1,200 @@
+package bridge
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Call is a contract call a passed proposal makes on an external chain
+type Call struct {
+	To        string   // Address of the called contract
+	Value     *big.Int // Native value sent with the call, nil for none
+	Data      []byte
+	Operation uint8 // 0 for a call, 1 for a delegate call
+}
+
+// MessageStatus is the stage of an outbound message
+type MessageStatus string
+
+const (
+	MessageStatusQueued  MessageStatus = "queued"
+	MessageStatusRelayed MessageStatus = "relayed"
+	MessageStatusFailed  MessageStatus = "failed" // Gave up after too many attempts
+)
+
+// Message is an execution message for a passed proposal, encoded for its
+// target chain and waiting for a relayer to deliver it
+type Message struct {
+	ID         types.Hash
+	Chain      string
+	Nonce      uint64 // Increases by one per message to a chain
+	ProposalID types.Hash
+	Call       Call
+	Payload    []byte // The call as encoded by the chain's adapter
+	Status     MessageStatus
+	Attempts   int
+	LastError  string
+	RelayTx    string // Reference of the delivery on the target chain
+	CreatedAt  int64
+	RelayedAt  int64
+}
+
+// Digest returns the hash identifying a message, which commits to its
+// chain, nonce, proposal and payload
+func (m *Message) Digest() types.Hash {
+	h := sha256.New()
+	h.Write([]byte("bockchain-bridge-outbound"))
+	writeBytes(h, []byte(m.Chain))
+	binary.Write(h, binary.BigEndian, m.Nonce)
+	h.Write(m.ProposalID.ToSlice())
+	writeBytes(h, m.Payload)
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// Queue holds the outbound messages in the order they were emitted
+type Queue struct {
+	mu       sync.RWMutex
+	messages map[types.Hash]*Message
+	order    []types.Hash
+	nonces   map[string]uint64
+}
+
+// NewQueue creates an empty message queue
+func NewQueue() *Queue {
+	return &Queue{
+		messages: make(map[types.Hash]*Message),
+		nonces:   make(map[string]uint64),
+	}
+}
+
+// Enqueue adds a message to a chain with the chain's next nonce
+func (q *Queue) Enqueue(chain string, proposalID types.Hash, call Call, payload []byte) Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	msg := &Message{
+		Chain:      chain,
+		Nonce:      q.nonces[chain],
+		ProposalID: proposalID,
+		Call:       call,
+		Payload:    payload,
+		Status:     MessageStatusQueued,
+		CreatedAt:  time.Now().Unix(),
+	}
+	msg.ID = msg.Digest()
+	q.nonces[chain]++
+
+	q.messages[msg.ID] = msg
+	q.order = append(q.order, msg.ID)
+	return *msg
+}
+
+// Pending returns the messages still waiting to be relayed, oldest first
+func (q *Queue) Pending() []Message {
+	return q.List(MessageStatusQueued)
+}
+
+// List returns the messages, optionally only those of a status, oldest first
+func (q *Queue) List(status MessageStatus) []Message {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	messages := make([]Message, 0, len(q.order))
+	for _, id := range q.order {
+		msg := q.messages[id]
+		if status != "" && msg.Status != status {
+			continue
+		}
+		messages = append(messages, *msg)
+	}
+	return messages
+}
+
+// Get returns a message
+func (q *Queue) Get(id types.Hash) (Message, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	msg, exists := q.messages[id]
+	if !exists {
+		return Message{}, false
+	}
+	return *msg, true
+}
+
+// MarkRelayed records the delivery of a queued message
+func (q *Queue) MarkRelayed(id types.Hash, relayTx string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	msg, err := q.queued(id)
+	if err != nil {
+		return err
+	}
+	msg.Attempts++
+	msg.Status = MessageStatusRelayed
+	msg.RelayTx = relayTx
+	msg.LastError = ""
+	msg.RelayedAt = time.Now().Unix()
+	return nil
+}
+
+// MarkFailed records a failed delivery of a queued message. The message
+// stays queued for another attempt until it has failed maxAttempts times.
+func (q *Queue) MarkFailed(id types.Hash, cause error, maxAttempts int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	msg, err := q.queued(id)
+	if err != nil {
+		return err
+	}
+	msg.Attempts++
+	msg.LastError = cause.Error()
+	if maxAttempts > 0 && msg.Attempts >= maxAttempts {
+		msg.Status = MessageStatusFailed
+	}
+	return nil
+}
+
+// Retry queues a failed message again
+func (q *Queue) Retry(id types.Hash) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	msg, exists := q.messages[id]
+	if !exists {
+		return fmt.Errorf("message %s not found", id)
+	}
+	if msg.Status != MessageStatusFailed {
+		return fmt.Errorf("message %s is %s, not failed", id, msg.Status)
+	}
+	msg.Status = MessageStatusQueued
+	msg.Attempts = 0
+	return nil
+}
+
+func (q *Queue) queued(id types.Hash) (*Message, error) {
+	msg, exists := q.messages[id]
+	if !exists {
+		return nil, fmt.Errorf("message %s not found", id)
+	}
+	if msg.Status != MessageStatusQueued {
+		return nil, fmt.Errorf("message %s is %s, not queued", id, msg.Status)
+	}
+	return msg, nil
+}
+
+// writeBytes writes b prefixed with its length so adjacent fields cannot
+// run into each other
+func writeBytes(w io.Writer, b []byte) {
+	binary.Write(w, binary.BigEndian, uint32(len(b)))
+	w.Write(b)
+}
@@ -0,0 +1,53 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	q := NewQueue()
+
+	first := q.Enqueue("ethereum", types.Hash{0x01}, Call{}, []byte{0x01})
+	second := q.Enqueue("ethereum", types.Hash{0x02}, Call{}, []byte{0x02})
+	other := q.Enqueue("gnosis", types.Hash{0x01}, Call{}, []byte{0x01})
+
+	// Nonces count per chain and the ID commits to them
+	assert.Equal(t, uint64(0), first.Nonce)
+	assert.Equal(t, uint64(1), second.Nonce)
+	assert.Equal(t, uint64(0), other.Nonce)
+	assert.NotEqual(t, first.ID, other.ID)
+	assert.Equal(t, first.ID, first.Digest())
+
+	pending := q.Pending()
+	require.Len(t, pending, 3)
+	assert.Equal(t, first.ID, pending[0].ID)
+
+	require.NoError(t, q.MarkRelayed(first.ID, "0xabc"))
+	relayed, exists := q.Get(first.ID)
+	require.True(t, exists)
+	assert.Equal(t, MessageStatusRelayed, relayed.Status)
+	assert.Equal(t, "0xabc", relayed.RelayTx)
+	assert.Error(t, q.MarkRelayed(first.ID, "0xabc"))
+
+	// Failures keep a message queued until it runs out of attempts
+	require.NoError(t, q.MarkFailed(second.ID, errors.New("rpc down"), 2))
+	msg, _ := q.Get(second.ID)
+	assert.Equal(t, MessageStatusQueued, msg.Status)
+	assert.Equal(t, "rpc down", msg.LastError)
+	require.NoError(t, q.MarkFailed(second.ID, errors.New("rpc down"), 2))
+	msg, _ = q.Get(second.ID)
+	assert.Equal(t, MessageStatusFailed, msg.Status)
+	assert.Len(t, q.Pending(), 1)
+
+	require.NoError(t, q.Retry(second.ID))
+	assert.Error(t, q.Retry(second.ID))
+	assert.Len(t, q.Pending(), 2)
+
+	_, exists = q.Get(types.Hash{0xFF})
+	assert.False(t, exists)
+}
@@ -0,0 +1,92 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// safeExecSignature is the Safe module entry point the adapter encodes
+const safeExecSignature = "execTransactionFromModule(address,uint256,bytes,uint8)"
+
+// SafeModuleAdapter encodes execution messages as calls to a Gnosis Safe's
+// execTransactionFromModule, to be submitted by the Safe module that trusts
+// this DAO's relayers
+type SafeModuleAdapter struct {
+	ChainName string
+	ChainID   uint64
+	Safe      string // Address of the Safe the module executes through
+}
+
+// NewSafeModuleAdapter creates an adapter for a Safe on an EVM chain
+func NewSafeModuleAdapter(chain string, chainID uint64, safe string) (*SafeModuleAdapter, error) {
+	if _, err := parseEVMAddress(safe); err != nil {
+		return nil, fmt.Errorf("invalid safe address: %w", err)
+	}
+	return &SafeModuleAdapter{ChainName: chain, ChainID: chainID, Safe: safe}, nil
+}
+
+// Chain returns the name of the adapter's chain
+func (a *SafeModuleAdapter) Chain() string {
+	return a.ChainName
+}
+
+// Encode returns the ABI encoded execTransactionFromModule call of a call
+func (a *SafeModuleAdapter) Encode(call Call) ([]byte, error) {
+	to, err := parseEVMAddress(call.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid call target: %w", err)
+	}
+	if call.Operation > 1 {
+		return nil, fmt.Errorf("invalid operation %d", call.Operation)
+	}
+	value := new(big.Int)
+	if call.Value != nil {
+		if call.Value.Sign() < 0 || call.Value.BitLen() > 256 {
+			return nil, fmt.Errorf("call value out of range")
+		}
+		value = call.Value
+	}
+
+	// Static head of four words, the bytes argument following it
+	out := make([]byte, 0, 4+32*6+len(call.Data))
+	out = append(out, SafeExecSelector()...)
+	out = append(out, leftPad(to)...)
+	out = append(out, leftPad(value.Bytes())...)
+	out = append(out, leftPad(big.NewInt(4*32).Bytes())...)
+	out = append(out, leftPad([]byte{call.Operation})...)
+	out = append(out, leftPad(big.NewInt(int64(len(call.Data))).Bytes())...)
+	out = append(out, call.Data...)
+	if rem := len(call.Data) % 32; rem != 0 {
+		out = append(out, make([]byte, 32-rem)...)
+	}
+	return out, nil
+}
+
+// SafeExecSelector returns the function selector of execTransactionFromModule
+func SafeExecSelector() []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(safeExecSignature))
+	return h.Sum(nil)[:4]
+}
+
+func parseEVMAddress(address string) ([]byte, error) {
+	raw := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if len(raw) != 40 {
+		return nil, fmt.Errorf("address %q is not 20 bytes", address)
+	}
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("address %q is not hex: %w", address, err)
+	}
+	return b, nil
+}
+
+func leftPad(b []byte) []byte {
+	word := make([]byte, 32)
+	copy(word[32-len(b):], b)
+	return word
+}
@@ -0,0 +1,43 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeModuleAdapter_Encode(t *testing.T) {
+	assert.Equal(t, "468721a7", hex.EncodeToString(SafeExecSelector()))
+
+	_, err := NewSafeModuleAdapter("ethereum", 1, "0x1234")
+	assert.Error(t, err)
+	adapter, err := NewSafeModuleAdapter("ethereum", 1, "0x5afe000000000000000000000000000000005afe")
+	require.NoError(t, err)
+
+	payload, err := adapter.Encode(Call{
+		To:    "0x00000000000000000000000000000000000000aa",
+		Value: big.NewInt(5),
+		Data:  []byte{0xde, 0xad},
+	})
+	require.NoError(t, err)
+
+	// Selector, four head words, the data length and one padded data word
+	require.Len(t, payload, 4+32*6)
+	word := func(i int) []byte { return payload[4+32*i : 4+32*(i+1)] }
+	assert.Equal(t, byte(0xaa), word(0)[31])
+	assert.Equal(t, byte(5), word(1)[31])
+	assert.Equal(t, byte(0x80), word(2)[31])
+	assert.Equal(t, byte(0), word(3)[31])
+	assert.Equal(t, byte(2), word(4)[31])
+	assert.Equal(t, []byte{0xde, 0xad}, word(5)[:2])
+
+	_, err = adapter.Encode(Call{To: "not an address"})
+	assert.Error(t, err)
+	_, err = adapter.Encode(Call{To: "0x00000000000000000000000000000000000000aa", Operation: 2})
+	assert.Error(t, err)
+	_, err = adapter.Encode(Call{To: "0x00000000000000000000000000000000000000aa", Value: big.NewInt(-1)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// keystoreScryptN is the scrypt cost of the keys the CLI stores
+var keystoreScryptN = crypto.StandardScryptN
+
+// keystoreFlags are the flags selecting a key of a keystore
+type keystoreFlags struct {
+	dir            *string
+	name           *string
+	passphraseFile *string
+}
+
+func addKeystoreFlags(fs *flag.FlagSet) *keystoreFlags {
+	return &keystoreFlags{
+		dir:            fs.String("keystore", envOr("BOCK_KEYSTORE_DIR", "keystore"), "keystore directory"),
+		name:           fs.String("name", "", "name of the key in the keystore"),
+		passphraseFile: fs.String("passphrase-file", "", "file holding the keystore passphrase, defaults to $BOCK_KEYSTORE_PASSPHRASE"),
+	}
+}
+
+func (f *keystoreFlags) open() (*crypto.Keystore, error) {
+	return crypto.NewKeystore(*f.dir, keystoreScryptN)
+}
+
+func (f *keystoreFlags) passphrase() (string, error) {
+	return readPassphrase(*f.passphraseFile, "BOCK_KEYSTORE_PASSPHRASE")
+}
+
+// load decrypts the selected key of the keystore
+func (f *keystoreFlags) load() (crypto.PrivateKey, error) {
+	if *f.name == "" {
+		return crypto.PrivateKey{}, fmt.Errorf("-name is required")
+	}
+
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return crypto.PrivateKey{}, err
+	}
+
+	keystore, err := f.open()
+	if err != nil {
+		return crypto.PrivateKey{}, err
+	}
+
+	return keystore.Load(*f.name, passphrase)
+}
+
+// readPassphrase reads a passphrase from a file, or from an environment
+// variable when no file is given
+func readPassphrase(file, env string) (string, error) {
+	if file == "" {
+		passphrase, ok := os.LookupEnv(env)
+		if !ok {
+			return "", fmt.Errorf("no passphrase given, use a passphrase file or set $%s", env)
+		}
+		return passphrase, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func runKeysGenerate(args []string, out io.Writer) error {
+	fs := newFlagSet("keys generate", out)
+	ks := addKeystoreFlags(fs)
+	curveName := fs.String("curve", string(crypto.CurveP256), "curve of the key, p256 or secp256k1")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	curve, err := crypto.ParseCurve(*curveName)
+	if err != nil {
+		return err
+	}
+	privKey := crypto.GenerateCurvePrivateKey(curve)
+
+	// Throwaway keys for development are printed instead of stored
+	if *ks.name == "" {
+		printKey(out, privKey.PublicKey())
+		fmt.Fprintf(out, "private key: %s\n", privKey.Hex())
+		return nil
+	}
+
+	return storeKey(ks, privKey, out)
+}
+
+func runKeysImport(args []string, out io.Writer) error {
+	fs := newFlagSet("keys import", out)
+	ks := addKeystoreFlags(fs)
+	keyHex := fs.String("key", "", "hex encoded private key, prefixed with secp256k1: for secp256k1 keys")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *ks.name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	privKey, err := parseKey(*keyHex)
+	if err != nil {
+		return err
+	}
+
+	return storeKey(ks, privKey, out)
+}
+
+func runKeysExport(args []string, out io.Writer) error {
+	fs := newFlagSet("keys export", out)
+	ks := addKeystoreFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	privKey, err := ks.load()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, privKey.Hex())
+	return nil
+}
+
+func runKeysPasswd(args []string, out io.Writer) error {
+	fs := newFlagSet("keys passwd", out)
+	ks := addKeystoreFlags(fs)
+	newPassphraseFile := fs.String("new-passphrase-file", "", "file holding the new passphrase, defaults to $BOCK_NEW_KEYSTORE_PASSPHRASE")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	passphrase, err := ks.passphrase()
+	if err != nil {
+		return err
+	}
+	newPassphrase, err := readPassphrase(*newPassphraseFile, "BOCK_NEW_KEYSTORE_PASSPHRASE")
+	if err != nil {
+		return err
+	}
+
+	keystore, err := ks.open()
+	if err != nil {
+		return err
+	}
+	if err := keystore.ChangePassphrase(*ks.name, passphrase, newPassphrase); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "passphrase of %s changed\n", *ks.name)
+	return nil
+}
+
+func runKeysList(args []string, out io.Writer) error {
+	fs := newFlagSet("keys list", out)
+	ks := addKeystoreFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	keystore, err := ks.open()
+	if err != nil {
+		return err
+	}
+
+	entries, err := keystore.List()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(out, "%s\t%s\n", entry.Name, entry.PublicKey)
+	}
+	return nil
+}
+
+func runKeysMnemonic(args []string, out io.Writer) error {
+	fs := newFlagSet("keys mnemonic", out)
+	words := fs.Int("words", 24, "number of words, 12, 15, 18, 21 or 24")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	mnemonic, err := crypto.NewMnemonic(*words * 32 / 3)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, mnemonic)
+	return nil
+}
+
+func runKeysDerive(args []string, out io.Writer) error {
+	fs := newFlagSet("keys derive", out)
+	ks := addKeystoreFlags(fs)
+	mnemonicFile := fs.String("mnemonic-file", "", "file holding the mnemonic, defaults to $BOCK_MNEMONIC")
+	seedPassphrase := fs.String("seed-passphrase", "", "optional BIP-39 passphrase of the mnemonic")
+	index := fs.Uint("index", 0, "account index n of the path m/44'/7331'/0'/0/n")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *index >= uint(crypto.HardenedOffset) {
+		return fmt.Errorf("-index is out of range")
+	}
+
+	mnemonic, err := readPassphrase(*mnemonicFile, "BOCK_MNEMONIC")
+	if err != nil {
+		return err
+	}
+
+	privKey, err := crypto.DeriveAccountFromMnemonic(mnemonic, *seedPassphrase, crypto.BockCoinType, uint32(*index))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "path:       %s\n", crypto.AccountPath(crypto.BockCoinType, uint32(*index)))
+	if *ks.name == "" {
+		printKey(out, privKey.PublicKey())
+		return nil
+	}
+	return storeKey(ks, privKey, out)
+}
+
+// storeKey encrypts a key into the keystore
+func storeKey(ks *keystoreFlags, privKey crypto.PrivateKey, out io.Writer) error {
+	passphrase, err := ks.passphrase()
+	if err != nil {
+		return err
+	}
+
+	keystore, err := ks.open()
+	if err != nil {
+		return err
+	}
+	if err := keystore.Import(*ks.name, privKey, passphrase); err != nil {
+		return err
+	}
+
+	printKey(out, privKey.PublicKey())
+	fmt.Fprintf(out, "stored as %s in %s\n", *ks.name, *ks.dir)
+	return nil
+}
+
+func printKey(out io.Writer, pubKey crypto.PublicKey) {
+	fmt.Fprintf(out, "public key: %s\n", pubKey)
+	fmt.Fprintf(out, "address:    %s\n", pubKey.Address())
+}
+
+func parseKey(keyHex string) (crypto.PrivateKey, error) {
+	return crypto.PrivateKeyFromHex(keyHex)
+}
+
+// keyFlags adds the flags selecting the key a subcommand signs with, either
+// a keystore key or a raw hex key
+func keyFlags(fs *flag.FlagSet) func() (string, error) {
+	ks := addKeystoreFlags(fs)
+	keyHex := fs.String("key", "", "hex encoded private key, instead of a keystore key")
+
+	return func() (string, error) {
+		var (
+			privKey crypto.PrivateKey
+			err     error
+		)
+		if *keyHex != "" {
+			privKey, err = parseKey(*keyHex)
+		} else {
+			privKey, err = ks.load()
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid private key: %w", err)
+		}
+
+		return privKey.Hex(), nil
+	}
+}
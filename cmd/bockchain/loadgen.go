@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/loadgen"
+)
+
+func runLoadgenRun(args []string, out io.Writer) error {
+	fs := newFlagSet("loadgen run", out)
+	apiURL := apiFlag(fs)
+	inProcess := fs.Bool("in-process", false, "run against a fresh DAO in this process instead of a node")
+	scenario := fs.String("scenario", loadgen.ScenarioProposalBurst, "workload: "+strings.Join(loadgen.Scenarios, ", "))
+	keysFile := fs.String("keys", "", "file of hex encoded private keys of funded accounts, one per line")
+	accounts := fs.Int("accounts", 100, "accounts generated for an in-process run without -keys")
+	operations := fs.Int("ops", 1000, "operations in the run")
+	concurrency := fs.Int("concurrency", 16, "operations in flight at once")
+	rate := fs.Float64("rate", 0, "operations started per second, 0 for no limit")
+	proposals := fs.Int("proposals", 1, "proposals a vote storm votes on")
+	profileDir := fs.String("profile-dir", "", "directory to write CPU and heap profiles of the run to")
+	adminToken := fs.String("admin-token", os.Getenv("BOCK_ADMIN_TOKEN"), "admin API token, needed to profile a node")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var keys []crypto.PrivateKey
+	switch {
+	case *keysFile != "":
+		var err error
+		if keys, err = readKeys(*keysFile); err != nil {
+			return err
+		}
+	case *inProcess:
+		keys = loadgen.GenerateAccounts(*accounts)
+	default:
+		return fmt.Errorf("-keys is required to run against a node")
+	}
+
+	var target loadgen.Target
+	if *inProcess {
+		inProcessTarget := loadgen.NewInProcessTarget(dao.NewDAO("BOCK", "Bock Token", 18), 0)
+		defer inProcessTarget.Close()
+		target = inProcessTarget
+	} else {
+		target = loadgen.NewAPITarget(*apiURL, *adminToken)
+	}
+
+	// Interrupting stops the run early and still reports on it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := loadgen.Run(ctx, target, loadgen.Config{
+		Scenario:    *scenario,
+		Accounts:    keys,
+		Operations:  *operations,
+		Concurrency: *concurrency,
+		Rate:        *rate,
+		Proposals:   *proposals,
+		ProfileDir:  *profileDir,
+	})
+	if report != nil {
+		if err := report.Write(out); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// readKeys reads hex encoded private keys, one per line
+func readKeys(file string) ([]crypto.PrivateKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []crypto.PrivateKey
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, err := parseKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid private key: %w", file, i+1, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s holds no keys", file)
+	}
+	return keys, nil
+}
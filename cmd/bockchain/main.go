@@ -0,0 +1,125 @@
+// Command bockchain runs a node and interacts with the DAO of a running node
+// through its REST API.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of a command group, such as "proposal create"
+type command struct {
+	summary string
+	run     func(args []string, out io.Writer) error
+}
+
+var commands = map[string]map[string]command{
+	"node": {
+		"start": {"start a node from a configuration file", runNodeStart},
+	},
+	"keys": {
+		"generate": {"generate a key into the keystore, or print it without -name", runKeysGenerate},
+		"import":   {"import a hex encoded private key into the keystore", runKeysImport},
+		"export":   {"print the hex encoded private key of a keystore key", runKeysExport},
+		"passwd":   {"change the passphrase of a keystore key", runKeysPasswd},
+		"list":     {"list the keys of the keystore", runKeysList},
+		"mnemonic": {"generate a BIP-39 mnemonic for deterministic keys", runKeysMnemonic},
+		"derive":   {"derive an account key from a mnemonic, storing it with -name", runKeysDerive},
+	},
+	"proposal": {
+		"create": {"create a governance proposal", runProposalCreate},
+		"vote":   {"vote on a proposal", runProposalVote},
+		"list":   {"list proposals", runProposalList},
+	},
+	"treasury": {
+		"sign": {"sign a pending treasury transaction", runTreasurySign},
+	},
+	"snapshot": {
+		"export": {"export the DAO state of a node to a file", runSnapshotExport},
+		"import": {"verify a snapshot file against its state root and a node", runSnapshotImport},
+	},
+	"loadgen": {
+		"run": {"run a governance workload and report its latencies", runLoadgenRun},
+	},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run dispatches the arguments to their subcommand
+func run(args []string, out io.Writer) error {
+	if len(args) < 2 {
+		usage(out)
+		return flag.ErrHelp
+	}
+
+	group, ok := commands[args[0]]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	cmd, ok := group[args[1]]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("unknown command %q", args[0]+" "+args[1])
+	}
+
+	return cmd.run(args[2:], out)
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: bockchain <command> <subcommand> [flags]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+
+	groups := make([]string, 0, len(commands))
+	for name := range commands {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		names := make([]string, 0, len(commands[group]))
+		for name := range commands[group] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(out, "  %-20s %s\n", group+" "+name, commands[group][name].summary)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Run 'bockchain <command> <subcommand> -h' for the flags of a subcommand.")
+}
+
+// newFlagSet creates the flags of a subcommand
+func newFlagSet(name string, out io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("bockchain "+name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	return fs
+}
+
+// apiFlag adds the flag selecting the node a subcommand talks to
+func apiFlag(fs *flag.FlagSet) *string {
+	return fs.String("api", envOr("BOCK_API_URL", "http://localhost:9000"), "URL of the node's API")
+}
+
+func envOr(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/api"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runCLI(t *testing.T, args ...string) (string, error) {
+	out := new(bytes.Buffer)
+	err := run(args, out)
+	return out.String(), err
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	_, err := runCLI(t, "proposal", "delete")
+	assert.Error(t, err)
+
+	out, err := runCLI(t)
+	assert.Error(t, err)
+	assert.Contains(t, out, "proposal create")
+}
+
+func TestKeys_Keystore(t *testing.T) {
+	keystoreScryptN = crypto.LightScryptN
+	dir := t.TempDir()
+	t.Setenv("BOCK_KEYSTORE_PASSPHRASE", "correct horse")
+
+	out, err := runCLI(t, "keys", "generate", "-keystore", dir, "-name", "validator")
+	require.NoError(t, err)
+	assert.Contains(t, out, "stored as validator")
+
+	exported, err := runCLI(t, "keys", "export", "-keystore", dir, "-name", "validator")
+	require.NoError(t, err)
+	privKey, err := parseKey(exported)
+	require.NoError(t, err)
+	assert.Contains(t, out, privKey.PublicKey().String())
+
+	// Importing the key under another name yields the same account
+	out, err = runCLI(t, "keys", "import", "-keystore", dir, "-name", "backup", "-key", strings.TrimSpace(exported))
+	require.NoError(t, err)
+	assert.Contains(t, out, privKey.PublicKey().String())
+
+	out, err = runCLI(t, "keys", "list", "-keystore", dir)
+	require.NoError(t, err)
+	assert.Contains(t, out, "backup\t"+privKey.PublicKey().String())
+
+	// Rotating the passphrase
+	t.Setenv("BOCK_NEW_KEYSTORE_PASSPHRASE", "battery staple")
+	_, err = runCLI(t, "keys", "passwd", "-keystore", dir, "-name", "validator")
+	require.NoError(t, err)
+	_, err = runCLI(t, "keys", "export", "-keystore", dir, "-name", "validator")
+	assert.ErrorIs(t, err, crypto.ErrWrongPassphrase)
+
+	passphraseFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(passphraseFile, []byte("battery staple\n"), 0o600))
+	_, err = runCLI(t, "keys", "export", "-keystore", dir, "-name", "validator", "-passphrase-file", passphraseFile)
+	require.NoError(t, err)
+
+	_, err = runCLI(t, "keys", "import", "-keystore", dir, "-name", "bad", "-key", "zz")
+	assert.Error(t, err)
+}
+
+func TestKeys_Derive(t *testing.T) {
+	keystoreScryptN = crypto.LightScryptN
+	dir := t.TempDir()
+	t.Setenv("BOCK_KEYSTORE_PASSPHRASE", "correct horse")
+
+	mnemonic, err := runCLI(t, "keys", "mnemonic", "-words", "12")
+	require.NoError(t, err)
+	require.NoError(t, crypto.ValidateMnemonic(mnemonic))
+	t.Setenv("BOCK_MNEMONIC", strings.TrimSpace(mnemonic))
+
+	expected, err := crypto.DeriveAccountFromMnemonic(mnemonic, "", crypto.BockCoinType, 3)
+	require.NoError(t, err)
+
+	out, err := runCLI(t, "keys", "derive", "-index", "3")
+	require.NoError(t, err)
+	assert.Contains(t, out, "m/44'/7331'/0'/0/3")
+	assert.Contains(t, out, expected.PublicKey().Address().String())
+
+	_, err = runCLI(t, "keys", "derive", "-index", "3", "-keystore", dir, "-name", "account3")
+	require.NoError(t, err)
+	exported, err := runCLI(t, "keys", "export", "-keystore", dir, "-name", "account3")
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(expected.Bytes()), strings.TrimSpace(exported))
+
+	t.Setenv("BOCK_MNEMONIC", "abandon abandon")
+	_, err = runCLI(t, "keys", "derive")
+	assert.ErrorIs(t, err, crypto.ErrInvalidMnemonic)
+}
+
+func TestProposal_VoteAndList(t *testing.T) {
+	privKey := crypto.GeneratePrivateKey()
+	var voteRequest map[string]interface{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dao/vote":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&voteRequest))
+			json.NewEncoder(w).Encode(map[string]string{"tx_hash": "abc", "message": "vote cast successfully"})
+		case "/dao/proposals":
+			json.NewEncoder(w).Encode([]api.ProposalResponse{
+				{ID: "01", Title: "Fund the docs", Status: dao.ProposalStatusActive},
+				{ID: "02", Title: "Old proposal", Status: dao.ProposalStatusRejected},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(api.APIError{Code: api.CodeNotFound, Message: "not found"})
+		}
+	}))
+	defer srv.Close()
+
+	out, err := runCLI(t, "proposal", "vote", "-api", srv.URL, "-id", "01", "-choice", "no", "-key", hex.EncodeToString(privKey.Bytes()))
+	require.NoError(t, err)
+	assert.Contains(t, out, "tx_hash: abc")
+	assert.Equal(t, float64(dao.VoteChoiceNo), voteRequest["choice"])
+	assert.Equal(t, hex.EncodeToString(privKey.Bytes()), voteRequest["private_key"])
+
+	_, err = runCLI(t, "proposal", "vote", "-api", srv.URL, "-id", "01", "-choice", "maybe", "-key", hex.EncodeToString(privKey.Bytes()))
+	assert.Error(t, err)
+
+	out, err = runCLI(t, "proposal", "list", "-api", srv.URL, "-status", "active")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Fund the docs")
+	assert.NotContains(t, out, "Old proposal")
+
+	// API errors are reported
+	_, err = runCLI(t, "treasury", "sign", "-api", srv.URL, "-tx", "01", "-key", hex.EncodeToString(privKey.Bytes()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found (not_found)")
+}
+
+func TestSnapshot_ExportAndImport(t *testing.T) {
+	d := dao.NewDAO("GOV", "Governance Token", 18)
+	require.NoError(t, d.InitialTokenDistribution(map[string]uint64{
+		crypto.GeneratePrivateKey().PublicKey().String(): 1000,
+	}))
+	leaves, err := d.StateLeaves()
+	require.NoError(t, err)
+
+	snapshot := api.SnapshotResponse{Height: 3, StateRoot: dao.StateRootFromLeaves(leaves).String()}
+	for _, leaf := range leaves {
+		snapshot.Entries = append(snapshot.Entries, api.SnapshotEntryResponse{
+			Key:      leaf.Key,
+			Value:    json.RawMessage(leaf.Value),
+			ValueHex: hex.EncodeToString(leaf.Value),
+		})
+	}
+	snapshot.Entries = append(snapshot.Entries, api.SnapshotEntryResponse{
+		Key:      dao.ParametersStateKey,
+		Value:    json.RawMessage(`{}`),
+		ValueHex: hex.EncodeToString([]byte(`{}`)),
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "3", r.URL.Query().Get("at_height"))
+		json.NewEncoder(w).Encode(snapshot)
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	_, err = runCLI(t, "snapshot", "export", "-api", srv.URL, "-at-height", "3", "-out", file)
+	require.NoError(t, err)
+
+	out, err := runCLI(t, "snapshot", "import", "-api", srv.URL, "-in", file)
+	require.NoError(t, err)
+	assert.Contains(t, out, "is valid")
+
+	// Tampered snapshots no longer match their state root
+	snapshot.Entries[0].ValueHex = hex.EncodeToString([]byte(`9999`))
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, data, 0o644))
+
+	_, err = runCLI(t, "snapshot", "import", "-offline", "-in", file)
+	assert.Error(t, err)
+}
+
+func TestLoadgen_Run(t *testing.T) {
+	out, err := runCLI(t, "loadgen", "run", "-in-process", "-scenario", "vote-storm", "-accounts", "5", "-ops", "10", "-proposals", "2")
+	require.NoError(t, err)
+	assert.Contains(t, out, "vote-storm")
+	assert.Contains(t, out, "10 (0 failed)")
+
+	keys := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(keys, []byte(crypto.GeneratePrivateKey().Hex()+"\nnot-a-key\n"), 0600))
+	_, err = runCLI(t, "loadgen", "run", "-keys", keys)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ":2: invalid private key")
+
+	_, err = runCLI(t, "loadgen", "run")
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"io"
+
+	"github.com/BOCK-CHAIN/BockChain/config"
+	"github.com/BOCK-CHAIN/BockChain/network"
+)
+
+func runNodeStart(args []string, out io.Writer) error {
+	fs := newFlagSet("node start", out)
+	configPath := fs.String("config", "", "path to a YAML node configuration, BOCK_ environment variables override it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	opts, err := network.ServerOptsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	s, err := network.NewServer(opts)
+	if err != nil {
+		return err
+	}
+
+	s.Start()
+	return nil
+}
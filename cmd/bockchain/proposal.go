@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/BOCK-CHAIN/BockChain/api"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+)
+
+var proposalStatuses = map[dao.ProposalStatus]string{
+	dao.ProposalStatusPending:   "pending",
+	dao.ProposalStatusActive:    "active",
+	dao.ProposalStatusPassed:    "passed",
+	dao.ProposalStatusRejected:  "rejected",
+	dao.ProposalStatusExecuted:  "executed",
+	dao.ProposalStatusCancelled: "cancelled",
+}
+
+var voteChoices = map[string]dao.VoteChoice{
+	"yes":     dao.VoteChoiceYes,
+	"no":      dao.VoteChoiceNo,
+	"abstain": dao.VoteChoiceAbstain,
+}
+
+func runProposalCreate(args []string, out io.Writer) error {
+	fs := newFlagSet("proposal create", out)
+	apiURL := apiFlag(fs)
+	privateKey := keyFlags(fs)
+	title := fs.String("title", "", "title of the proposal")
+	description := fs.String("description", "", "description of the proposal")
+	proposalType := fs.Uint("type", uint(dao.ProposalTypeGeneral), "proposal type: 1 general, 2 treasury, 3 technical, 4 parameter")
+	votingType := fs.Uint("voting", uint(dao.VotingTypeSimple), "voting type: 1 simple, 2 quadratic, 3 weighted, 4 reputation")
+	duration := fs.Int64("duration", 7*24*3600, "voting period in seconds")
+	threshold := fs.Uint64("threshold", 5100, "approval threshold in basis points")
+	metadataHash := fs.String("metadata", "", "hex encoded IPFS metadata hash")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *title == "" {
+		return fmt.Errorf("-title is required")
+	}
+
+	key, err := privateKey()
+	if err != nil {
+		return err
+	}
+
+	var response map[string]string
+	err = newAPIClient(*apiURL).post("/dao/proposal", map[string]interface{}{
+		"title":         *title,
+		"description":   *description,
+		"proposal_type": *proposalType,
+		"voting_type":   *votingType,
+		"duration":      *duration,
+		"threshold":     *threshold,
+		"metadata_hash": *metadataHash,
+		"private_key":   key,
+	}, &response)
+	if err != nil {
+		return err
+	}
+
+	printResponse(out, response)
+	return nil
+}
+
+func runProposalVote(args []string, out io.Writer) error {
+	fs := newFlagSet("proposal vote", out)
+	apiURL := apiFlag(fs)
+	privateKey := keyFlags(fs)
+	proposalID := fs.String("id", "", "ID of the proposal")
+	choice := fs.String("choice", "yes", "vote: yes, no or abstain")
+	weight := fs.Uint64("weight", 1, "voting weight")
+	reason := fs.String("reason", "", "reason for the vote")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *proposalID == "" {
+		return fmt.Errorf("-id is required")
+	}
+
+	voteChoice, ok := voteChoices[*choice]
+	if !ok {
+		return fmt.Errorf("invalid choice %q, use yes, no or abstain", *choice)
+	}
+
+	key, err := privateKey()
+	if err != nil {
+		return err
+	}
+
+	var response map[string]string
+	err = newAPIClient(*apiURL).post("/dao/vote", map[string]interface{}{
+		"proposal_id": *proposalID,
+		"choice":      voteChoice,
+		"weight":      *weight,
+		"reason":      *reason,
+		"private_key": key,
+	}, &response)
+	if err != nil {
+		return err
+	}
+
+	printResponse(out, response)
+	return nil
+}
+
+func runProposalList(args []string, out io.Writer) error {
+	fs := newFlagSet("proposal list", out)
+	apiURL := apiFlag(fs)
+	status := fs.String("status", "", "only list proposals with this status, such as active or passed")
+	atHeight := fs.Int64("at-height", -1, "list the proposals as they were after this block")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "/dao/proposals"
+	if *atHeight >= 0 {
+		path = fmt.Sprintf("%s?at_height=%d", path, *atHeight)
+	}
+
+	var proposals []api.ProposalResponse
+	if err := newAPIClient(*apiURL).get(path, &proposals); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tEND\tTITLE")
+	for _, proposal := range proposals {
+		proposalStatus := proposalStatuses[proposal.Status]
+		if *status != "" && proposalStatus != *status {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", proposal.ID, proposalStatus, proposal.EndTime, proposal.Title)
+	}
+
+	return w.Flush()
+}
+
+// printResponse prints the fields of a transaction submission response
+func printResponse(out io.Writer, response map[string]string) {
+	for _, field := range []string{"message", "tx_hash"} {
+		if value, ok := response[field]; ok {
+			fmt.Fprintf(out, "%s: %s\n", field, value)
+		}
+	}
+}
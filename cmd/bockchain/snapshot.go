@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/api"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+)
+
+func runSnapshotExport(args []string, out io.Writer) error {
+	fs := newFlagSet("snapshot export", out)
+	apiURL := apiFlag(fs)
+	file := fs.String("out", "", "file to write the snapshot to")
+	atHeight := fs.Int64("at-height", -1, "export the state after this block instead of the latest one")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("-out is required")
+	}
+
+	snapshot, err := fetchSnapshot(newAPIClient(*apiURL), *atHeight)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*file, data, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "exported %d entries at height %d (state root %s) to %s\n", len(snapshot.Entries), snapshot.Height, snapshot.StateRoot, *file)
+	return nil
+}
+
+// runSnapshotImport checks that a snapshot file is intact and matches the
+// state the node recorded at the same height
+func runSnapshotImport(args []string, out io.Writer) error {
+	fs := newFlagSet("snapshot import", out)
+	apiURL := apiFlag(fs)
+	file := fs.String("in", "", "snapshot file to verify")
+	offline := fs.Bool("offline", false, "only check the snapshot against its own state root")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("-in is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &api.SnapshotResponse{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return fmt.Errorf("invalid snapshot file: %w", err)
+	}
+
+	root, err := snapshotStateRoot(snapshot)
+	if err != nil {
+		return err
+	}
+	if root != snapshot.StateRoot {
+		return fmt.Errorf("snapshot entries hash to %s, not to its state root %s", root, snapshot.StateRoot)
+	}
+
+	if !*offline {
+		node, err := fetchSnapshot(newAPIClient(*apiURL), int64(snapshot.Height))
+		if err != nil {
+			return err
+		}
+		if node.StateRoot != snapshot.StateRoot {
+			return fmt.Errorf("node state root at height %d is %s, snapshot has %s", snapshot.Height, node.StateRoot, snapshot.StateRoot)
+		}
+	}
+
+	fmt.Fprintf(out, "snapshot at height %d with %d entries is valid (state root %s)\n", snapshot.Height, len(snapshot.Entries), snapshot.StateRoot)
+	return nil
+}
+
+func fetchSnapshot(client *apiClient, atHeight int64) (*api.SnapshotResponse, error) {
+	path := "/dao/snapshot"
+	if atHeight >= 0 {
+		path = fmt.Sprintf("%s?at_height=%d", path, atHeight)
+	}
+
+	snapshot := &api.SnapshotResponse{}
+	if err := client.get(path, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// snapshotStateRoot recomputes the state root of the committed entries of a
+// snapshot. Governance parameters are part of snapshots but not of the root.
+func snapshotStateRoot(snapshot *api.SnapshotResponse) (string, error) {
+	leaves := make([]dao.StateLeaf, 0, len(snapshot.Entries))
+	for _, entry := range snapshot.Entries {
+		if entry.Key == dao.ParametersStateKey {
+			continue
+		}
+
+		value, err := hex.DecodeString(entry.ValueHex)
+		if err != nil {
+			return "", fmt.Errorf("invalid value of entry %s", entry.Key)
+		}
+		leaves = append(leaves, dao.StateLeaf{Key: entry.Key, Value: value})
+	}
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].Key < leaves[j].Key
+	})
+
+	return dao.StateRootFromLeaves(leaves).String(), nil
+}
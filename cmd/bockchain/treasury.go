@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func runTreasurySign(args []string, out io.Writer) error {
+	fs := newFlagSet("treasury sign", out)
+	apiURL := apiFlag(fs)
+	privateKey := keyFlags(fs)
+	txID := fs.String("tx", "", "ID of the pending treasury transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *txID == "" {
+		return fmt.Errorf("-tx is required")
+	}
+
+	key, err := privateKey()
+	if err != nil {
+		return err
+	}
+
+	var response map[string]string
+	err = newAPIClient(*apiURL).post("/dao/treasury/sign", map[string]interface{}{
+		"transaction_id": *txID,
+		"private_key":    key,
+	}, &response)
+	if err != nil {
+		return err
+	}
+
+	printResponse(out, response)
+	return nil
+}
@@ -0,0 +1,578 @@
+// Package codec is the deterministic binary encoding of chain and DAO data.
+// Unlike gob, the bytes of a value depend on nothing but the value: not the
+// Go version, not the order types were first encoded in, not map iteration.
+// That makes encodings safe to hash, to sign and to keep on disk.
+//
+// Encoding rules, after a leading Version byte:
+//
+//   - bool is one byte, 0 or 1
+//   - signed integers are zigzag varints, unsigned integers uvarints
+//   - floats are their IEEE 754 bits, big endian
+//   - strings and byte slices are a uvarint length and the bytes
+//   - byte arrays are their bytes, other arrays their elements
+//   - slices are a uvarint length and the elements, nil and empty alike
+//   - maps are a uvarint length and the entries sorted by encoded key
+//   - pointers are 0 when nil, else 1 and the value
+//   - big.Int is a uvarint of the byte length shifted left once, the low
+//     bit set for negative numbers, then the big endian magnitude
+//   - structs are a uvarint field count and the exported fields in
+//     declaration order
+//   - interfaces are the uvarint tag of the concrete type, 0 when nil, and
+//     the value; pointers to registered types encode as the value
+//
+// Fields added at the end of a struct keep older encodings decodable: the
+// missing fields decode to their zero value. Removing or reordering fields
+// breaks them, so both need a new Version.
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Version is the leading byte of every encoding
+const Version byte = 1
+
+// FirstTag is the first interface tag open to registered types. Lower tags
+// are reserved for builtin values.
+const FirstTag uint16 = 0x10
+
+var (
+	registryLock sync.RWMutex
+	tagsByType   = make(map[reflect.Type]uint16)
+	typesByTag   = make(map[uint16]reflect.Type)
+
+	fieldCache sync.Map // reflect.Type -> []int
+
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+func init() {
+	builtins := []interface{}{
+		false, int(0), int64(0), uint(0), uint64(0), float64(0), "", []byte(nil),
+		uint8(0), int32(0), uint32(0), []interface{}(nil), map[string]interface{}(nil),
+	}
+	for i, value := range builtins {
+		register(uint16(i+1), value)
+	}
+}
+
+// Register assigns a tag to the concrete type of value so it can be
+// encoded in interfaces. Tags are part of the encoding and must never be
+// reused for another type. Like gob.Register it panics on conflicts.
+func Register(tag uint16, value interface{}) {
+	if tag < FirstTag {
+		panic(fmt.Sprintf("codec: tag %#x is reserved", tag))
+	}
+	register(tag, value)
+}
+
+func register(tag uint16, value interface{}) {
+	t := reflect.TypeOf(value)
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if existing, exists := typesByTag[tag]; exists && existing != t {
+		panic(fmt.Sprintf("codec: tag %#x registered for both %s and %s", tag, existing, t))
+	}
+	if existing, exists := tagsByType[t]; exists && existing != tag {
+		panic(fmt.Sprintf("codec: %s registered with both tags %#x and %#x", t, existing, tag))
+	}
+	typesByTag[tag] = t
+	tagsByType[t] = tag
+}
+
+// TypeOf returns the type registered under a tag
+func TypeOf(tag uint16) (reflect.Type, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	t, registered := typesByTag[tag]
+	return t, registered
+}
+
+func tagOf(t reflect.Type) (uint16, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	tag, registered := tagsByType[t]
+	return tag, registered
+}
+
+// Marshal returns the encoding of v. Pointers are followed, so a value and
+// a pointer to it encode alike.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("codec: cannot encode nil")
+		}
+		rv = rv.Elem()
+	}
+
+	e := &encoder{buf: []byte{Version}}
+	if err := e.value(rv); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// Unmarshal decodes data into the value v points to, replacing it
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("codec: Unmarshal needs a non nil pointer")
+	}
+	if len(data) == 0 {
+		return errors.New("codec: empty input")
+	}
+	if data[0] != Version {
+		return fmt.Errorf("codec: unsupported version %d", data[0])
+	}
+
+	d := &decoder{data: data[1:]}
+	target := rv.Elem()
+	target.Set(reflect.Zero(target.Type()))
+	if err := d.value(target); err != nil {
+		return err
+	}
+	if len(d.data) > 0 {
+		return fmt.Errorf("codec: %d trailing bytes", len(d.data))
+	}
+	return nil
+}
+
+// fields returns the indexes of the exported fields of a struct type
+func fields(t reflect.Type) []int {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]int)
+	}
+	var indexes []int
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			indexes = append(indexes, i)
+		}
+	}
+	fieldCache.Store(t, indexes)
+	return indexes
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uvarint(x uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, scratch[:binary.PutUvarint(scratch[:], x)]...)
+}
+
+func (e *encoder) varint(x int64) {
+	var scratch [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, scratch[:binary.PutVarint(scratch[:], x)]...)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.uvarint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) value(v reflect.Value) error {
+	if !v.IsValid() {
+		return errors.New("codec: cannot encode nil")
+	}
+	if v.Type() == bigIntType {
+		var x *big.Int
+		if v.CanAddr() {
+			x = v.Addr().Interface().(*big.Int)
+		} else {
+			copied := reflect.New(bigIntType)
+			copied.Elem().Set(v)
+			x = copied.Interface().(*big.Int)
+		}
+		magnitude := x.Bytes()
+		length := uint64(len(magnitude)) << 1
+		if x.Sign() < 0 {
+			length |= 1
+		}
+		e.uvarint(length)
+		e.buf = append(e.buf, magnitude...)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.varint(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		e.uvarint(v.Uint())
+	case reflect.Float32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], math.Float32bits(float32(v.Float())))
+		e.buf = append(e.buf, b[:]...)
+	case reflect.Float64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(v.Float()))
+		e.buf = append(e.buf, b[:]...)
+	case reflect.String:
+		e.uvarint(uint64(v.Len()))
+		e.buf = append(e.buf, v.String()...)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.bytes(v.Bytes())
+			return nil
+		}
+		e.uvarint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.value(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			for i := 0; i < v.Len(); i++ {
+				e.buf = append(e.buf, byte(v.Index(i).Uint()))
+			}
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := e.value(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return e.mapValue(v)
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0)
+			return nil
+		}
+		e.buf = append(e.buf, 1)
+		return e.value(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			e.uvarint(0)
+			return nil
+		}
+		// Like gob, pointers in interfaces encode as the value they point
+		// to and decode as a value
+		concrete := v.Elem()
+		for concrete.Kind() == reflect.Ptr && !concrete.IsNil() {
+			if _, registered := tagOf(concrete.Type()); registered {
+				break
+			}
+			concrete = concrete.Elem()
+		}
+		tag, registered := tagOf(concrete.Type())
+		if !registered {
+			return fmt.Errorf("codec: type %s is not registered", concrete.Type())
+		}
+		e.uvarint(uint64(tag))
+		return e.value(concrete)
+	case reflect.Struct:
+		indexes := fields(v.Type())
+		e.uvarint(uint64(len(indexes)))
+		for _, i := range indexes {
+			if err := e.value(v.Field(i)); err != nil {
+				return fmt.Errorf("%s.%s: %w", v.Type(), v.Type().Field(i).Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("codec: cannot encode %s", v.Type())
+	}
+	return nil
+}
+
+// mapValue encodes the entries of a map sorted by their encoded keys
+func (e *encoder) mapValue(v reflect.Value) error {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		keyEncoder := &encoder{}
+		if err := keyEncoder.value(iter.Key()); err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: keyEncoder.buf, value: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	e.uvarint(uint64(len(entries)))
+	for _, entry := range entries {
+		e.buf = append(e.buf, entry.key...)
+		if err := e.value(entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type decoder struct {
+	data []byte
+}
+
+var errTruncated = errors.New("codec: unexpected end of input")
+
+func (d *decoder) byte() (byte, error) {
+	if len(d.data) == 0 {
+		return 0, errTruncated
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b, nil
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errors.New("codec: invalid uvarint")
+	}
+	d.data = d.data[n:]
+	return x, nil
+}
+
+func (d *decoder) varint() (int64, error) {
+	x, n := binary.Varint(d.data)
+	if n <= 0 {
+		return 0, errors.New("codec: invalid varint")
+	}
+	d.data = d.data[n:]
+	return x, nil
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)) {
+		return nil, errTruncated
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+// length reads a collection length, which can never exceed the remaining
+// input since every element takes at least a byte
+func (d *decoder) length() (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)) {
+		return 0, errTruncated
+	}
+	return int(n), nil
+}
+
+func (d *decoder) value(v reflect.Value) error {
+	if v.Type() == bigIntType {
+		length, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		magnitude, err := d.next(length >> 1)
+		if err != nil {
+			return err
+		}
+		x := v.Addr().Interface().(*big.Int)
+		x.SetBytes(magnitude)
+		if length&1 == 1 {
+			x.Neg(x)
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := d.byte()
+		if err != nil {
+			return err
+		}
+		if b > 1 {
+			return fmt.Errorf("codec: invalid bool %d", b)
+		}
+		v.SetBool(b == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := d.varint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(x) {
+			return fmt.Errorf("codec: %d overflows %s", x, v.Type())
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(x) {
+			return fmt.Errorf("codec: %d overflows %s", x, v.Type())
+		}
+		v.SetUint(x)
+	case reflect.Float32:
+		b, err := d.next(4)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(b))))
+	case reflect.Float64:
+		b, err := d.next(8)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	case reflect.String:
+		n, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(b))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			n, err := d.uvarint()
+			if err != nil {
+				return err
+			}
+			b, err := d.next(n)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				bs := reflect.MakeSlice(v.Type(), int(n), int(n))
+				for i, x := range b {
+					bs.Index(i).SetUint(uint64(x))
+				}
+				v.Set(bs)
+			}
+			return nil
+		}
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		slice := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := d.value(slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.next(uint64(v.Len()))
+			if err != nil {
+				return err
+			}
+			for i, x := range b {
+				v.Index(i).SetUint(uint64(x))
+			}
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := d.value(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		m := reflect.MakeMapWithSize(v.Type(), n)
+		for i := 0; i < n; i++ {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.value(key); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.value(value); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Ptr:
+		present, err := d.byte()
+		if err != nil {
+			return err
+		}
+		switch present {
+		case 0:
+			return nil
+		case 1:
+			elem := reflect.New(v.Type().Elem())
+			if err := d.value(elem.Elem()); err != nil {
+				return err
+			}
+			v.Set(elem)
+		default:
+			return fmt.Errorf("codec: invalid pointer flag %d", present)
+		}
+	case reflect.Interface:
+		tag, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		if tag == 0 {
+			return nil
+		}
+		if tag > math.MaxUint16 {
+			return fmt.Errorf("codec: unknown tag %#x", tag)
+		}
+		registryLock.RLock()
+		t, registered := typesByTag[uint16(tag)]
+		registryLock.RUnlock()
+		if !registered {
+			return fmt.Errorf("codec: unknown tag %#x", tag)
+		}
+		if !t.AssignableTo(v.Type()) {
+			return fmt.Errorf("codec: %s does not implement %s", t, v.Type())
+		}
+		concrete := reflect.New(t).Elem()
+		if err := d.value(concrete); err != nil {
+			return err
+		}
+		v.Set(concrete)
+	case reflect.Struct:
+		n, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		indexes := fields(v.Type())
+		if n > uint64(len(indexes)) {
+			return fmt.Errorf("codec: %s encoded with %d fields, only %d are known", v.Type(), n, len(indexes))
+		}
+		for _, i := range indexes[:n] {
+			if err := d.value(v.Field(i)); err != nil {
+				return fmt.Errorf("%s.%s: %w", v.Type(), v.Type().Field(i).Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("codec: cannot decode %s", v.Type())
+	}
+	return nil
+}
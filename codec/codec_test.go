@@ -0,0 +1,167 @@
+package codec
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Flag    bool
+	Count   int64
+	Amount  uint64
+	Ratio   float64
+	Name    string
+	Data    []byte
+	ID      [4]byte
+	Items   []uint16
+	Weights map[string]uint8
+	Next    *sample
+	Value   *big.Int
+	Any     interface{}
+	hidden  int
+}
+
+type tagged struct {
+	N uint8
+}
+
+func init() {
+	Register(0x7f, tagged{})
+}
+
+func TestMarshalGolden(t *testing.T) {
+	value := sample{
+		Flag:    true,
+		Count:   -2,
+		Amount:  300,
+		Ratio:   0.5,
+		Name:    "ab",
+		Data:    []byte{0xff},
+		ID:      [4]byte{1, 2, 3, 4},
+		Items:   []uint16{1, 2},
+		Weights: map[string]uint8{"b": 2, "a": 1},
+		Next:    &sample{},
+		Value:   big.NewInt(-256),
+		Any:     tagged{N: 9},
+		hidden:  42,
+	}
+
+	data, err := Marshal(value)
+	require.NoError(t, err)
+	expected := "01" + // version
+		"0c" + // 12 exported fields
+		"01" + // Flag
+		"03" + // Count, zigzag -2
+		"ac02" + // Amount
+		"3fe0000000000000" + // Ratio
+		"026162" + // Name
+		"01ff" + // Data
+		"01020304" + // ID
+		"020102" + // Items
+		"02" + "016101" + "016202" + // Weights, sorted by key
+		"01" + "0c00000000000000000000000000000000000000000000" + // Next, all zero
+		"01" + "050100" + // Value, 2 negative bytes
+		"7f" + "0109" // Any
+	assert.Equal(t, expected, hex.EncodeToString(data))
+
+	// Pointers are followed at the top
+	byPointer, err := Marshal(&value)
+	require.NoError(t, err)
+	assert.Equal(t, data, byPointer)
+
+	var decoded sample
+	require.NoError(t, Unmarshal(data, &decoded))
+	value.hidden = 0
+	value.Next = &sample{}
+	assert.Equal(t, value, decoded)
+}
+
+func TestMarshalDeterministic(t *testing.T) {
+	weights := make(map[string]uint8)
+	for i := 0; i < 100; i++ {
+		weights[string(rune('a'+i%26))+string(rune('a'+i/26))] = uint8(i)
+	}
+	first, err := Marshal(sample{Weights: weights})
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := Marshal(sample{Weights: weights})
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+}
+
+func TestUnmarshalAppendedFields(t *testing.T) {
+	type before struct {
+		A uint64
+		B string
+	}
+	type after struct {
+		A uint64
+		B string
+		C []byte
+	}
+
+	data, err := Marshal(before{A: 1, B: "x"})
+	require.NoError(t, err)
+
+	// Older encodings decode, the new field is zero
+	var decoded after
+	require.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, after{A: 1, B: "x"}, decoded)
+
+	// Newer encodings do not silently drop fields
+	data, err = Marshal(after{A: 1, B: "x", C: []byte{1}})
+	require.NoError(t, err)
+	var older before
+	assert.Error(t, Unmarshal(data, &older))
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	data, err := Marshal(sample{Name: "abc", Any: tagged{N: 1}})
+	require.NoError(t, err)
+
+	var decoded sample
+	assert.Error(t, Unmarshal(nil, &decoded))
+	assert.Error(t, Unmarshal(data, decoded), "needs a pointer")
+	assert.Error(t, Unmarshal(append([]byte{2}, data[1:]...), &decoded), "unknown version")
+	assert.Error(t, Unmarshal(append(data, 0), &decoded), "trailing bytes")
+	for i := 1; i < len(data); i++ {
+		assert.Error(t, Unmarshal(data[:i], &decoded), "truncated at %d", i)
+	}
+
+	// Huge lengths fail before allocating
+	assert.Error(t, Unmarshal([]byte{Version, 0xff, 0xff, 0xff, 0xff, 0x0f}, new([]uint64)))
+	assert.Error(t, Unmarshal([]byte{Version, 0x02}, new(bool)))
+	assert.Error(t, Unmarshal([]byte{Version, 0x70}, new(interface{})), "unregistered tag")
+	assert.Error(t, Unmarshal([]byte{Version, 0xac, 0x02}, new(uint8)), "overflow")
+
+	_, err = Marshal(sample{Any: struct{}{}})
+	assert.Error(t, err, "unregistered type")
+	_, err = Marshal(func() {})
+	assert.Error(t, err)
+}
+
+func TestInterfaceValues(t *testing.T) {
+	values := map[string]interface{}{
+		"bool":   true,
+		"uint64": uint64(7),
+		"float":  1.5,
+		"string": "x",
+		"list":   []interface{}{int64(-1), "y"},
+		"tagged": &tagged{N: 3},
+	}
+	data, err := Marshal(values)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, Unmarshal(data, &decoded))
+	values["tagged"] = tagged{N: 3} // Pointers decode as values, like gob
+	assert.Equal(t, values, decoded)
+
+	assert.Panics(t, func() { Register(0x7f, sample{}) })
+	assert.Panics(t, func() { Register(0x01, sample{}) })
+}
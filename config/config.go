@@ -0,0 +1,880 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the
+// configuration file
+const EnvPrefix = "BOCK_"
+
+// Config is the configuration of a node
+type Config struct {
+	Node   NodeConfig   `yaml:"node" json:"node"`
+	DAO    DAOConfig    `yaml:"dao" json:"dao"`
+	Server ServerConfig `yaml:"server" json:"server"`
+}
+
+// NodeConfig configures the peer to peer node
+type NodeConfig struct {
+	ID                string        `yaml:"id" json:"id"`
+	ListenAddr        string        `yaml:"listen_addr" json:"listen_addr"`
+	SeedNodes         []string      `yaml:"seed_nodes" json:"seed_nodes"`
+	BlockTime         time.Duration `yaml:"block_time" json:"-"`
+	PeerStorePath     string        `yaml:"peer_store_path" json:"peer_store_path"`
+	MaxPeers          int           `yaml:"max_peers" json:"max_peers"`
+	DiscoveryInterval time.Duration `yaml:"discovery_interval" json:"-"`
+	// KeystoreDir holds the encrypted keys of the node
+	KeystoreDir string `yaml:"keystore_dir" json:"keystore_dir"`
+	// ValidatorKey names the keystore key the node validates with, the node
+	// does not validate when empty
+	ValidatorKey string `yaml:"validator_key" json:"validator_key"`
+	// KeystorePassphrase decrypts the keystore. It is only read from the
+	// environment so it never ends up in configuration files.
+	KeystorePassphrase string `yaml:"-" json:"-"`
+	// ReplicaOf is the API URL of a full node to follow. A replica serves
+	// the API read only from the blocks it streams from that node and takes
+	// no part in consensus.
+	ReplicaOf string `yaml:"replica_of" json:"replica_of"`
+	// LogFile is where the node logs, stderr when empty. The admin API can
+	// rotate it.
+	LogFile string `yaml:"log_file" json:"log_file"`
+	// LogLevel is the least severe level logged: debug, info, warn or error
+	LogLevel string `yaml:"log_level" json:"log_level"`
+}
+
+// MarshalJSON encodes durations as strings such as "5s"
+func (c NodeConfig) MarshalJSON() ([]byte, error) {
+	type plain NodeConfig
+	return json.Marshal(struct {
+		plain
+		BlockTime         string `json:"block_time"`
+		DiscoveryInterval string `json:"discovery_interval"`
+	}{
+		plain:             plain(c),
+		BlockTime:         c.BlockTime.String(),
+		DiscoveryInterval: c.DiscoveryInterval.String(),
+	})
+}
+
+// DAOConfig configures the governance token and DAO services
+type DAOConfig struct {
+	TokenSymbol   string    `yaml:"token_symbol" json:"token_symbol"`
+	TokenName     string    `yaml:"token_name" json:"token_name"`
+	TokenDecimals uint8     `yaml:"token_decimals" json:"token_decimals"`
+	IPFSNodeURL   string    `yaml:"ipfs_node_url" json:"ipfs_node_url"`
+	Fees          FeeConfig `yaml:"fees" json:"fees"`
+	// IPFSPinning replicates pins beyond the IPFS node
+	IPFSPinning IPFSPinningConfig `yaml:"ipfs_pinning" json:"ipfs_pinning"`
+	// MetadataCache keeps proposal metadata off IPFS' read path
+	MetadataCache MetadataCacheConfig `yaml:"metadata_cache" json:"metadata_cache"`
+	// ContentStore selects where proposal metadata and documents are stored
+	ContentStore ContentStoreConfig `yaml:"content_store" json:"content_store"`
+	// Notifications delivers DAO events to subscribed members
+	Notifications NotificationsConfig `yaml:"notifications" json:"notifications"`
+	// Webhooks configures delivery to integrator webhook subscriptions
+	Webhooks WebhooksConfig `yaml:"webhooks" json:"webhooks"`
+	// Analytics configures sampling of the analytics time series
+	Analytics AnalyticsConfig `yaml:"analytics" json:"analytics"`
+	// Archive bounds the memory the DAO state takes, for mobile and edge
+	// nodes
+	Archive ArchiveConfig `yaml:"archive" json:"archive"`
+	// Jobs schedules the maintenance jobs of the DAO by name, jobs without a
+	// schedule do not run. metrics_sampling defaults to
+	// analytics.sample_interval.
+	Jobs map[string]JobConfig `yaml:"jobs" json:"jobs"`
+}
+
+// JobConfig schedules a maintenance job with a cron expression such as
+// "*/10 * * * *" or an interval such as "@every 1m". Every run is delayed by
+// up to Jitter.
+type JobConfig struct {
+	Schedule string        `yaml:"schedule" json:"schedule"`
+	Jitter   time.Duration `yaml:"jitter" json:"-"`
+}
+
+// MarshalJSON encodes the jitter as a string such as "5s"
+func (c JobConfig) MarshalJSON() ([]byte, error) {
+	type plain JobConfig
+	return json.Marshal(struct {
+		plain
+		Jitter string `json:"jitter"`
+	}{
+		plain:  plain(c),
+		Jitter: c.Jitter.String(),
+	})
+}
+
+// AnalyticsConfig configures how often analytics metrics are sampled and how
+// long the samples are kept
+type AnalyticsConfig struct {
+	SampleInterval time.Duration `yaml:"sample_interval" json:"-"`
+	Retention      time.Duration `yaml:"retention" json:"-"`
+}
+
+// MarshalJSON encodes durations as strings such as "5m0s"
+func (c AnalyticsConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SampleInterval string `json:"sample_interval"`
+		Retention      string `json:"retention"`
+	}{
+		SampleInterval: c.SampleInterval.String(),
+		Retention:      c.Retention.String(),
+	})
+}
+
+// WebhooksConfig configures retries of integrator webhook deliveries
+type WebhooksConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts" json:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" json:"-"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" json:"-"`
+	Timeout        time.Duration `yaml:"timeout" json:"-"`
+}
+
+// MarshalJSON encodes durations as strings such as "1s"
+func (c WebhooksConfig) MarshalJSON() ([]byte, error) {
+	type plain WebhooksConfig
+	return json.Marshal(struct {
+		plain
+		InitialBackoff string `json:"initial_backoff"`
+		MaxBackoff     string `json:"max_backoff"`
+		Timeout        string `json:"timeout"`
+	}{
+		plain:          plain(c),
+		InitialBackoff: c.InitialBackoff.String(),
+		MaxBackoff:     c.MaxBackoff.String(),
+		Timeout:        c.Timeout.String(),
+	})
+}
+
+// NotificationsConfig configures the notification channels. A channel is
+// enabled by configuring it.
+type NotificationsConfig struct {
+	QueueSize int        `yaml:"queue_size" json:"queue_size"`
+	FCM       FCMConfig  `yaml:"fcm" json:"fcm"`
+	APNs      APNsConfig `yaml:"apns" json:"apns"`
+	SMTP      SMTPConfig `yaml:"smtp" json:"smtp"`
+	// Webhooks lets members receive notifications on their own URLs, which
+	// the node then calls
+	Webhooks bool `yaml:"webhooks" json:"webhooks"`
+}
+
+// FCMConfig configures Firebase Cloud Messaging
+type FCMConfig struct {
+	ProjectID string `yaml:"project_id" json:"project_id"`
+	// CredentialsFile is the JSON key of the service account sending messages
+	CredentialsFile string `yaml:"credentials_file" json:"credentials_file"`
+}
+
+// APNsConfig configures the Apple Push Notification service
+type APNsConfig struct {
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	KeyFile  string `yaml:"key_file" json:"key_file"` // .p8 signing key
+	KeyID    string `yaml:"key_id" json:"key_id"`
+	TeamID   string `yaml:"team_id" json:"team_id"`
+	Topic    string `yaml:"topic" json:"topic"` // Bundle ID of the app
+}
+
+// SMTPConfig configures the mail server email notifications are sent through
+type SMTPConfig struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password,omitempty"`
+	From     string `yaml:"from" json:"from"`
+}
+
+// ContentStoreConfig configures the storage provider of proposal content
+type ContentStoreConfig struct {
+	// Provider is one of ipfs, s3 or arweave. The ipfs provider uses
+	// ipfs_node_url.
+	Provider string             `yaml:"provider" json:"provider"`
+	S3       S3StoreConfig      `yaml:"s3" json:"s3"`
+	Arweave  ArweaveStoreConfig `yaml:"arweave" json:"arweave"`
+}
+
+// S3StoreConfig configures an S3-compatible bucket
+type S3StoreConfig struct {
+	Endpoint  string `yaml:"endpoint" json:"endpoint"`
+	Bucket    string `yaml:"bucket" json:"bucket"`
+	Region    string `yaml:"region" json:"region"`
+	AccessKey string `yaml:"access_key" json:"access_key"`
+	SecretKey string `yaml:"secret_key" json:"secret_key,omitempty"`
+}
+
+// ArweaveStoreConfig configures Arweave uploads through a bundling service
+type ArweaveStoreConfig struct {
+	Gateway   string `yaml:"gateway" json:"gateway"`
+	UploadURL string `yaml:"upload_url" json:"upload_url"`
+	Token     string `yaml:"token" json:"token,omitempty"`
+}
+
+// MetadataCacheConfig configures the proposal metadata cache
+type MetadataCacheConfig struct {
+	MaxEntries int           `yaml:"max_entries" json:"max_entries"`
+	MaxBytes   int64         `yaml:"max_bytes" json:"max_bytes"`
+	TTL        time.Duration `yaml:"ttl" json:"-"`
+	// Dir keeps the cache on disk across restarts, memory only when empty
+	Dir string `yaml:"dir" json:"dir"`
+}
+
+// MarshalJSON encodes the TTL as a string such as "1h0m0s"
+func (c MetadataCacheConfig) MarshalJSON() ([]byte, error) {
+	type plain MetadataCacheConfig
+	return json.Marshal(struct {
+		plain
+		TTL string `json:"ttl"`
+	}{
+		plain: plain(c),
+		TTL:   c.TTL.String(),
+	})
+}
+
+// ArchiveConfig configures the memory-bounded mode, enabled by setting Dir.
+// Finalized proposals with their votes and executed treasury transactions
+// beyond the limits are moved to Dir and read back on demand.
+type ArchiveConfig struct {
+	Dir            string `yaml:"dir" json:"dir"`
+	MaxProposals   int    `yaml:"max_proposals" json:"max_proposals"`
+	MaxTreasuryTxs int    `yaml:"max_treasury_txs" json:"max_treasury_txs"`
+	CacheEntries   int    `yaml:"cache_entries" json:"cache_entries"`
+}
+
+// IPFSPinningConfig configures pin replication and gateway failover
+type IPFSPinningConfig struct {
+	// Nodes are additional IPFS node APIs content is pinned to
+	Nodes    []string               `yaml:"nodes" json:"nodes"`
+	Services []PinningServiceConfig `yaml:"services" json:"services"`
+	// Gateways serve content, in order, when the IPFS node cannot
+	Gateways          []string      `yaml:"gateways" json:"gateways"`
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" json:"-"`
+}
+
+// PinningServiceConfig configures a remote IPFS Pinning Service API
+type PinningServiceConfig struct {
+	Name     string `yaml:"name" json:"name"`
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	Token    string `yaml:"token" json:"token,omitempty"`
+}
+
+// MarshalJSON encodes the reconcile interval as a string such as "10m0s"
+func (c IPFSPinningConfig) MarshalJSON() ([]byte, error) {
+	type plain IPFSPinningConfig
+	return json.Marshal(struct {
+		plain
+		ReconcileInterval string `json:"reconcile_interval"`
+	}{
+		plain:             plain(c),
+		ReconcileInterval: c.ReconcileInterval.String(),
+	})
+}
+
+// FeeConfig is the fee the API charges for the transactions it submits
+type FeeConfig struct {
+	Proposal   int64 `yaml:"proposal" json:"proposal"`
+	Vote       int64 `yaml:"vote" json:"vote"`
+	Treasury   int64 `yaml:"treasury" json:"treasury"`
+	Delegation int64 `yaml:"delegation" json:"delegation"`
+	Default    int64 `yaml:"default" json:"default"` // Every other transaction
+}
+
+// ServerConfig configures the HTTP API
+type ServerConfig struct {
+	// ListenAddr is the address of the API, the API is disabled when empty
+	ListenAddr     string   `yaml:"listen_addr" json:"listen_addr"`
+	AllowedOrigins []string `yaml:"allowed_origins" json:"allowed_origins"`
+	// AdminToken authorizes the admin endpoints, which are disabled when empty
+	AdminToken string `yaml:"admin_token" json:"admin_token,omitempty"`
+	// ExportTokens authorize /dao/export for researchers and auditors, on top
+	// of the admin token
+	ExportTokens []string `yaml:"export_tokens" json:"export_tokens,omitempty"`
+	// Cache keeps the responses of hot read endpoints
+	Cache CacheConfig `yaml:"cache" json:"cache"`
+	// IdempotencyTTL is how long responses to POST requests with an
+	// Idempotency-Key are replayed, idempotency keys are ignored when zero
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" json:"-"`
+	// IdempotencyMaxBody is the largest body, in bytes, of a request with an
+	// Idempotency-Key. Larger requests are rejected rather than read into
+	// memory to fingerprint them.
+	IdempotencyMaxBody int64 `yaml:"idempotency_max_body" json:"idempotency_max_body"`
+	// TLS serves the API over HTTPS
+	TLS TLSConfig `yaml:"tls" json:"tls"`
+	// TrustedProxies are the IPs or CIDR ranges of the reverse proxies in
+	// front of the API. Only their X-Forwarded-* headers are believed.
+	TrustedProxies []string `yaml:"trusted_proxies" json:"trusted_proxies"`
+	// SnapshotDir is where the admin API writes state snapshots
+	SnapshotDir string `yaml:"snapshot_dir" json:"snapshot_dir"`
+	// AppLinkBase prefixes the deep links into the mobile app the API
+	// returns, such as "bockdao://"
+	AppLinkBase string `yaml:"app_link_base" json:"app_link_base"`
+	// APIVersions schedules the deprecation of REST API versions by name,
+	// such as v1
+	APIVersions map[string]APIVersionConfig `yaml:"api_versions" json:"api_versions,omitempty"`
+}
+
+// APIVersionConfig deprecates a version of the REST API. Its responses carry
+// the Deprecation and Sunset headers, and it answers 410 Gone from the
+// sunset on.
+type APIVersionConfig struct {
+	Deprecated time.Time `yaml:"deprecated" json:"deprecated"`
+	Sunset     time.Time `yaml:"sunset" json:"sunset"`
+	// Successor is the version clients should move to, linked from the
+	// responses of the deprecated one
+	Successor string `yaml:"successor" json:"successor,omitempty"`
+}
+
+// TLSConfig serves the API over HTTPS with a certificate from files or one
+// obtained from Let's Encrypt. TLS is off when neither is configured.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `yaml:"key_file" json:"key_file"`
+	// ACMEDomains obtains certificates for these domains through ACME, the
+	// listen address must be reachable on port 443 for the challenge
+	ACMEDomains  []string `yaml:"acme_domains" json:"acme_domains"`
+	ACMEEmail    string   `yaml:"acme_email" json:"acme_email"`
+	ACMECacheDir string   `yaml:"acme_cache_dir" json:"acme_cache_dir"`
+}
+
+// Enabled reports whether the API is served over TLS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || len(c.ACMEDomains) > 0
+}
+
+// MarshalJSON encodes the idempotency TTL as a string such as "24h0m0s"
+func (c ServerConfig) MarshalJSON() ([]byte, error) {
+	type plain ServerConfig
+	return json.Marshal(struct {
+		plain
+		IdempotencyTTL string `json:"idempotency_ttl"`
+	}{
+		plain:          plain(c),
+		IdempotencyTTL: c.IdempotencyTTL.String(),
+	})
+}
+
+// CacheConfig configures the response cache. Applied transactions invalidate
+// it, the TTL bounds how long time-dependent values can be served stale.
+type CacheConfig struct {
+	// TTL of cached responses, caching is disabled when zero
+	TTL time.Duration `yaml:"ttl" json:"-"`
+	// RedisAddr keeps the cache in Redis instead of in the process
+	RedisAddr     string `yaml:"redis_addr" json:"redis_addr"`
+	RedisPassword string `yaml:"redis_password" json:"redis_password,omitempty"`
+	// KeyPrefix namespaces the cache keys, so nodes can share a Redis
+	KeyPrefix string `yaml:"key_prefix" json:"key_prefix"`
+}
+
+// MarshalJSON encodes the TTL as a string such as "30s"
+func (c CacheConfig) MarshalJSON() ([]byte, error) {
+	type plain CacheConfig
+	return json.Marshal(struct {
+		plain
+		TTL string `json:"ttl"`
+	}{
+		plain: plain(c),
+		TTL:   c.TTL.String(),
+	})
+}
+
+// Default returns the configuration used when nothing is configured
+func Default() *Config {
+	return &Config{
+		Node: NodeConfig{
+			ListenAddr:        ":3000",
+			BlockTime:         5 * time.Second,
+			MaxPeers:          32,
+			DiscoveryInterval: 30 * time.Second,
+			KeystoreDir:       "keystore",
+			LogLevel:          "info",
+		},
+		DAO: DAOConfig{
+			TokenSymbol:   "PX",
+			TokenName:     "ProjectX Token",
+			TokenDecimals: 18,
+			IPFSNodeURL:   "localhost:5001",
+			Fees: FeeConfig{
+				Proposal:   1000,
+				Vote:       500,
+				Treasury:   1000,
+				Delegation: 200,
+				Default:    100,
+			},
+			IPFSPinning: IPFSPinningConfig{
+				ReconcileInterval: 10 * time.Minute,
+			},
+			MetadataCache: MetadataCacheConfig{
+				MaxEntries: 1024,
+				MaxBytes:   64 << 20,
+				TTL:        time.Hour,
+			},
+			ContentStore: ContentStoreConfig{
+				Provider: "ipfs",
+			},
+			Notifications: NotificationsConfig{
+				QueueSize: 256,
+				SMTP:      SMTPConfig{Port: 587},
+			},
+			Webhooks: WebhooksConfig{
+				MaxAttempts:    6,
+				InitialBackoff: time.Second,
+				MaxBackoff:     5 * time.Minute,
+				Timeout:        10 * time.Second,
+			},
+			Analytics: AnalyticsConfig{
+				SampleInterval: 5 * time.Minute,
+				Retention:      90 * 24 * time.Hour,
+			},
+			Archive: ArchiveConfig{
+				MaxProposals:   256,
+				MaxTreasuryTxs: 256,
+				CacheEntries:   64,
+			},
+			Jobs: map[string]JobConfig{
+				dao.JobProposalStatus:   {Schedule: "@every 1m", Jitter: 5 * time.Second},
+				dao.JobReputationDecay:  {Schedule: "0 3 * * *", Jitter: 10 * time.Minute},
+				dao.JobTreasuryExpiry:   {Schedule: "*/10 * * * *", Jitter: 30 * time.Second},
+				dao.JobTreasuryRunway:   {Schedule: "@hourly", Jitter: time.Minute},
+				dao.JobDelegationExpiry: {Schedule: "*/5 * * * *", Jitter: 30 * time.Second},
+				dao.JobParameterRollout: {Schedule: "@every 1m", Jitter: 5 * time.Second},
+				dao.JobArchive:          {Schedule: "@every 5m", Jitter: 30 * time.Second},
+				// Unpins the metadata of every proposal that is no longer
+				// active, enable it only where another node keeps history
+				dao.JobIPFSCleanup: {},
+			},
+		},
+		Server: ServerConfig{
+			AllowedOrigins: []string{"*"},
+			Cache: CacheConfig{
+				TTL:       30 * time.Second,
+				KeyPrefix: "bock:",
+			},
+			IdempotencyTTL:     24 * time.Hour,
+			IdempotencyMaxBody: 1 << 20,
+			SnapshotDir:        "snapshots",
+			AppLinkBase:        "bockdao://",
+			TLS: TLSConfig{
+				ACMECacheDir: "acme",
+			},
+		},
+	}
+}
+
+// Load reads the configuration from a YAML file on top of the defaults,
+// applies the environment overrides and validates the result. An empty path
+// only uses the defaults and the environment.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// envOverride sets a configuration value from an environment variable
+type envOverride struct {
+	name  string
+	apply func(cfg *Config, value string) error
+}
+
+var envOverrides = []envOverride{
+	{"NODE_ID", func(cfg *Config, v string) error { cfg.Node.ID = v; return nil }},
+	{"LISTEN_ADDR", func(cfg *Config, v string) error { cfg.Node.ListenAddr = v; return nil }},
+	{"SEED_NODES", func(cfg *Config, v string) error { cfg.Node.SeedNodes = splitList(v); return nil }},
+	{"BLOCK_TIME", func(cfg *Config, v string) error { return parseDuration(v, &cfg.Node.BlockTime) }},
+	{"PEER_STORE_PATH", func(cfg *Config, v string) error { cfg.Node.PeerStorePath = v; return nil }},
+	{"MAX_PEERS", func(cfg *Config, v string) error { return parseInt(v, &cfg.Node.MaxPeers) }},
+	{"DISCOVERY_INTERVAL", func(cfg *Config, v string) error { return parseDuration(v, &cfg.Node.DiscoveryInterval) }},
+	{"KEYSTORE_DIR", func(cfg *Config, v string) error { cfg.Node.KeystoreDir = v; return nil }},
+	{"VALIDATOR_KEY", func(cfg *Config, v string) error { cfg.Node.ValidatorKey = v; return nil }},
+	{"KEYSTORE_PASSPHRASE", func(cfg *Config, v string) error { cfg.Node.KeystorePassphrase = v; return nil }},
+	{"REPLICA_OF", func(cfg *Config, v string) error { cfg.Node.ReplicaOf = v; return nil }},
+	{"LOG_FILE", func(cfg *Config, v string) error { cfg.Node.LogFile = v; return nil }},
+	{"LOG_LEVEL", func(cfg *Config, v string) error { cfg.Node.LogLevel = v; return nil }},
+	{"TOKEN_SYMBOL", func(cfg *Config, v string) error { cfg.DAO.TokenSymbol = v; return nil }},
+	{"TOKEN_NAME", func(cfg *Config, v string) error { cfg.DAO.TokenName = v; return nil }},
+	{"TOKEN_DECIMALS", func(cfg *Config, v string) error {
+		decimals, err := strconv.ParseUint(v, 10, 8)
+		cfg.DAO.TokenDecimals = uint8(decimals)
+		return err
+	}},
+	{"IPFS_NODE_URL", func(cfg *Config, v string) error { cfg.DAO.IPFSNodeURL = v; return nil }},
+	{"IPFS_PIN_NODES", func(cfg *Config, v string) error { cfg.DAO.IPFSPinning.Nodes = splitList(v); return nil }},
+	{"IPFS_GATEWAYS", func(cfg *Config, v string) error { cfg.DAO.IPFSPinning.Gateways = splitList(v); return nil }},
+	{"IPFS_RECONCILE_INTERVAL", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.DAO.IPFSPinning.ReconcileInterval)
+	}},
+	{"METADATA_CACHE_DIR", func(cfg *Config, v string) error { cfg.DAO.MetadataCache.Dir = v; return nil }},
+	{"METADATA_CACHE_TTL", func(cfg *Config, v string) error { return parseDuration(v, &cfg.DAO.MetadataCache.TTL) }},
+	{"ARCHIVE_DIR", func(cfg *Config, v string) error { cfg.DAO.Archive.Dir = v; return nil }},
+	{"ANALYTICS_SAMPLE_INTERVAL", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.DAO.Analytics.SampleInterval)
+	}},
+	{"CONTENT_STORE", func(cfg *Config, v string) error { cfg.DAO.ContentStore.Provider = v; return nil }},
+	{"FCM_CREDENTIALS_FILE", func(cfg *Config, v string) error { cfg.DAO.Notifications.FCM.CredentialsFile = v; return nil }},
+	{"SMTP_HOST", func(cfg *Config, v string) error { cfg.DAO.Notifications.SMTP.Host = v; return nil }},
+	{"SMTP_PASSWORD", func(cfg *Config, v string) error { cfg.DAO.Notifications.SMTP.Password = v; return nil }},
+	{"FEE_PROPOSAL", func(cfg *Config, v string) error { return parseFee(v, &cfg.DAO.Fees.Proposal) }},
+	{"FEE_VOTE", func(cfg *Config, v string) error { return parseFee(v, &cfg.DAO.Fees.Vote) }},
+	{"FEE_TREASURY", func(cfg *Config, v string) error { return parseFee(v, &cfg.DAO.Fees.Treasury) }},
+	{"FEE_DELEGATION", func(cfg *Config, v string) error { return parseFee(v, &cfg.DAO.Fees.Delegation) }},
+	{"FEE_DEFAULT", func(cfg *Config, v string) error { return parseFee(v, &cfg.DAO.Fees.Default) }},
+	{"API_LISTEN_ADDR", func(cfg *Config, v string) error { cfg.Server.ListenAddr = v; return nil }},
+	{"ALLOWED_ORIGINS", func(cfg *Config, v string) error { cfg.Server.AllowedOrigins = splitList(v); return nil }},
+	{"TLS_CERT_FILE", func(cfg *Config, v string) error { cfg.Server.TLS.CertFile = v; return nil }},
+	{"TLS_KEY_FILE", func(cfg *Config, v string) error { cfg.Server.TLS.KeyFile = v; return nil }},
+	{"ACME_DOMAINS", func(cfg *Config, v string) error { cfg.Server.TLS.ACMEDomains = splitList(v); return nil }},
+	{"ACME_EMAIL", func(cfg *Config, v string) error { cfg.Server.TLS.ACMEEmail = v; return nil }},
+	{"TRUSTED_PROXIES", func(cfg *Config, v string) error { cfg.Server.TrustedProxies = splitList(v); return nil }},
+	{"ADMIN_TOKEN", func(cfg *Config, v string) error { cfg.Server.AdminToken = v; return nil }},
+	{"EXPORT_TOKENS", func(cfg *Config, v string) error { cfg.Server.ExportTokens = splitList(v); return nil }},
+	{"CACHE_TTL", func(cfg *Config, v string) error { return parseDuration(v, &cfg.Server.Cache.TTL) }},
+	{"IDEMPOTENCY_TTL", func(cfg *Config, v string) error { return parseDuration(v, &cfg.Server.IdempotencyTTL) }},
+	{"IDEMPOTENCY_MAX_BODY", func(cfg *Config, v string) error { return parseInt64(v, &cfg.Server.IdempotencyMaxBody) }},
+	{"SNAPSHOT_DIR", func(cfg *Config, v string) error { cfg.Server.SnapshotDir = v; return nil }},
+	{"APP_LINK_BASE", func(cfg *Config, v string) error { cfg.Server.AppLinkBase = v; return nil }},
+	{"CACHE_REDIS_ADDR", func(cfg *Config, v string) error { cfg.Server.Cache.RedisAddr = v; return nil }},
+	{"CACHE_REDIS_PASSWORD", func(cfg *Config, v string) error { cfg.Server.Cache.RedisPassword = v; return nil }},
+}
+
+// applyEnv overrides the configuration with the BOCK_ environment variables
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	for _, override := range envOverrides {
+		value, ok := lookup(EnvPrefix + override.name)
+		if !ok {
+			continue
+		}
+		if err := override.apply(c, value); err != nil {
+			return fmt.Errorf("invalid %s%s: %w", EnvPrefix, override.name, err)
+		}
+	}
+	return nil
+}
+
+// Validate reports the first invalid setting of the configuration
+func (c *Config) Validate() error {
+	if err := validateAddr(c.Node.ListenAddr); err != nil {
+		return fmt.Errorf("node.listen_addr: %w", err)
+	}
+	for _, addr := range c.Node.SeedNodes {
+		if err := validateAddr(addr); err != nil {
+			return fmt.Errorf("node.seed_nodes: %w", err)
+		}
+	}
+	if c.Node.BlockTime <= 0 {
+		return fmt.Errorf("node.block_time must be positive")
+	}
+	if c.Node.MaxPeers <= 0 {
+		return fmt.Errorf("node.max_peers must be positive")
+	}
+	if c.Node.DiscoveryInterval <= 0 {
+		return fmt.Errorf("node.discovery_interval must be positive")
+	}
+	if c.Node.ValidatorKey != "" && c.Node.KeystoreDir == "" {
+		return fmt.Errorf("node.keystore_dir is required with node.validator_key")
+	}
+	if _, err := logging.ParseLevel(c.Node.LogLevel); err != nil {
+		return fmt.Errorf("node.log_level: %w", err)
+	}
+	if c.Node.ReplicaOf != "" {
+		if u, err := url.Parse(c.Node.ReplicaOf); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("node.replica_of must be an http or https URL")
+		}
+		if c.Node.ValidatorKey != "" {
+			return fmt.Errorf("node.replica_of cannot be combined with node.validator_key")
+		}
+		if c.Server.ListenAddr == "" {
+			return fmt.Errorf("server.listen_addr is required with node.replica_of")
+		}
+	}
+
+	if c.DAO.TokenSymbol == "" || c.DAO.TokenName == "" {
+		return fmt.Errorf("dao.token_symbol and dao.token_name are required")
+	}
+	if c.DAO.TokenDecimals > 18 {
+		return fmt.Errorf("dao.token_decimals must be at most 18")
+	}
+	if c.DAO.IPFSNodeURL == "" {
+		return fmt.Errorf("dao.ipfs_node_url is required")
+	}
+	for _, service := range c.DAO.IPFSPinning.Services {
+		if service.Endpoint == "" {
+			return fmt.Errorf("dao.ipfs_pinning.services require an endpoint")
+		}
+	}
+	if c.DAO.IPFSPinning.ReconcileInterval <= 0 {
+		return fmt.Errorf("dao.ipfs_pinning.reconcile_interval must be positive")
+	}
+	if c.DAO.MetadataCache.MaxEntries <= 0 || c.DAO.MetadataCache.MaxBytes < 0 {
+		return fmt.Errorf("dao.metadata_cache.max_entries must be positive and max_bytes not negative")
+	}
+	if c.DAO.MetadataCache.TTL <= 0 {
+		return fmt.Errorf("dao.metadata_cache.ttl must be positive")
+	}
+	if archive := c.DAO.Archive; archive.MaxProposals <= 0 || archive.MaxTreasuryTxs <= 0 || archive.CacheEntries <= 0 {
+		return fmt.Errorf("dao.archive.max_proposals, max_treasury_txs and cache_entries must be positive")
+	}
+	switch store := c.DAO.ContentStore; store.Provider {
+	case "ipfs":
+	case "s3":
+		if store.S3.Endpoint == "" || store.S3.Bucket == "" {
+			return fmt.Errorf("dao.content_store.s3.endpoint and bucket are required")
+		}
+	case "arweave":
+		if store.Arweave.UploadURL == "" {
+			return fmt.Errorf("dao.content_store.arweave.upload_url is required")
+		}
+	default:
+		return fmt.Errorf("dao.content_store.provider must be ipfs, s3 or arweave")
+	}
+	notifications := c.DAO.Notifications
+	if notifications.QueueSize <= 0 {
+		return fmt.Errorf("dao.notifications.queue_size must be positive")
+	}
+	if notifications.FCM.CredentialsFile != "" && notifications.FCM.ProjectID == "" {
+		return fmt.Errorf("dao.notifications.fcm.project_id is required with credentials_file")
+	}
+	if apns := notifications.APNs; apns.KeyFile != "" && (apns.KeyID == "" || apns.TeamID == "" || apns.Topic == "") {
+		return fmt.Errorf("dao.notifications.apns requires key_id, team_id and topic with key_file")
+	}
+	if notifications.SMTP.Host != "" && notifications.SMTP.From == "" {
+		return fmt.Errorf("dao.notifications.smtp.from is required with host")
+	}
+	webhooks := c.DAO.Webhooks
+	if webhooks.MaxAttempts <= 0 {
+		return fmt.Errorf("dao.webhooks.max_attempts must be positive")
+	}
+	if webhooks.InitialBackoff <= 0 || webhooks.MaxBackoff < webhooks.InitialBackoff {
+		return fmt.Errorf("dao.webhooks.initial_backoff must be positive and at most max_backoff")
+	}
+	if webhooks.Timeout <= 0 {
+		return fmt.Errorf("dao.webhooks.timeout must be positive")
+	}
+	if analytics := c.DAO.Analytics; analytics.SampleInterval <= 0 || analytics.Retention < analytics.SampleInterval {
+		return fmt.Errorf("dao.analytics.sample_interval must be positive and at most retention")
+	}
+	for name, job := range c.DAO.Jobs {
+		if !knownJob(name) {
+			return fmt.Errorf("dao.jobs.%s is not a maintenance job, expected one of %s", name, strings.Join(dao.MaintenanceJobNames, ", "))
+		}
+		if job.Jitter < 0 {
+			return fmt.Errorf("dao.jobs.%s.jitter must not be negative", name)
+		}
+		if job.Schedule == "" {
+			continue
+		}
+		if _, err := dao.ParseSchedule(job.Schedule); err != nil {
+			return fmt.Errorf("dao.jobs.%s: %w", name, err)
+		}
+	}
+
+	fees := c.DAO.Fees
+	for _, fee := range []int64{fees.Proposal, fees.Vote, fees.Treasury, fees.Delegation, fees.Default} {
+		if fee < 0 {
+			return fmt.Errorf("dao.fees must not be negative")
+		}
+	}
+
+	if c.Server.ListenAddr != "" {
+		if err := validateAddr(c.Server.ListenAddr); err != nil {
+			return fmt.Errorf("server.listen_addr: %w", err)
+		}
+	}
+	for _, origin := range c.Server.AllowedOrigins {
+		if origin == "" {
+			return fmt.Errorf("server.allowed_origins must not contain empty origins")
+		}
+	}
+	if tls := c.Server.TLS; (tls.CertFile == "") != (tls.KeyFile == "") {
+		return fmt.Errorf("server.tls.cert_file and key_file must be set together")
+	} else if tls.CertFile != "" && len(tls.ACMEDomains) > 0 {
+		return fmt.Errorf("server.tls takes either cert_file and key_file or acme_domains")
+	} else if len(tls.ACMEDomains) > 0 && tls.ACMECacheDir == "" {
+		return fmt.Errorf("server.tls.acme_cache_dir is required with acme_domains")
+	}
+	if _, err := c.Server.TrustedProxyRanges(); err != nil {
+		return fmt.Errorf("server.trusted_proxies: %w", err)
+	}
+	if c.Server.Cache.TTL < 0 {
+		return fmt.Errorf("server.cache.ttl must not be negative")
+	}
+	if c.Server.IdempotencyTTL < 0 {
+		return fmt.Errorf("server.idempotency_ttl must not be negative")
+	}
+	if c.Server.IdempotencyTTL > 0 && c.Server.IdempotencyMaxBody <= 0 {
+		return fmt.Errorf("server.idempotency_max_body must be positive")
+	}
+	if c.Server.Cache.RedisAddr != "" {
+		if err := validateAddr(c.Server.Cache.RedisAddr); err != nil {
+			return fmt.Errorf("server.cache.redis_addr: %w", err)
+		}
+	}
+	for name, version := range c.Server.APIVersions {
+		if version.Deprecated.IsZero() && version.Sunset.IsZero() {
+			return fmt.Errorf("server.api_versions.%s needs a deprecated or sunset time", name)
+		}
+		if !version.Deprecated.IsZero() && !version.Sunset.IsZero() && version.Sunset.Before(version.Deprecated) {
+			return fmt.Errorf("server.api_versions.%s.sunset must not be before deprecated", name)
+		}
+		if version.Successor == name {
+			return fmt.Errorf("server.api_versions.%s.successor must be another version", name)
+		}
+	}
+
+	return nil
+}
+
+// Redacted returns a copy of the configuration without secrets, safe to
+// expose through the API
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if redacted.Server.AdminToken != "" {
+		redacted.Server.AdminToken = "[redacted]"
+	}
+	if len(c.Server.ExportTokens) > 0 {
+		tokens := make([]string, len(c.Server.ExportTokens))
+		for i := range tokens {
+			tokens[i] = "[redacted]"
+		}
+		redacted.Server.ExportTokens = tokens
+	}
+
+	if len(c.DAO.IPFSPinning.Services) > 0 {
+		services := make([]PinningServiceConfig, len(c.DAO.IPFSPinning.Services))
+		for i, service := range c.DAO.IPFSPinning.Services {
+			if service.Token != "" {
+				service.Token = "[redacted]"
+			}
+			services[i] = service
+		}
+		redacted.DAO.IPFSPinning.Services = services
+	}
+	if redacted.DAO.ContentStore.S3.SecretKey != "" {
+		redacted.DAO.ContentStore.S3.SecretKey = "[redacted]"
+	}
+	if redacted.DAO.ContentStore.Arweave.Token != "" {
+		redacted.DAO.ContentStore.Arweave.Token = "[redacted]"
+	}
+	if redacted.Server.Cache.RedisPassword != "" {
+		redacted.Server.Cache.RedisPassword = "[redacted]"
+	}
+	if redacted.DAO.Notifications.SMTP.Password != "" {
+		redacted.DAO.Notifications.SMTP.Password = "[redacted]"
+	}
+
+	return &redacted
+}
+
+// AllowsOrigin reports whether browsers on origin may call the API
+func (c ServerConfig) AllowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustedProxyRanges parses the trusted proxies, single IPs become ranges
+// of one address
+func (c ServerConfig) TrustedProxyRanges() ([]*net.IPNet, error) {
+	ranges := make([]*net.IPNet, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			ranges = append(ranges, ipNet)
+			continue
+		}
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP or CIDR range %q", proxy)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		ranges = append(ranges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return ranges, nil
+}
+
+func knownJob(name string) bool {
+	for _, job := range dao.MaintenanceJobNames {
+		if job == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAddr(addr string) error {
+	if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+		return fmt.Errorf("invalid address %q", addr)
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseDuration(value string, out *time.Duration) error {
+	d, err := time.ParseDuration(value)
+	*out = d
+	return err
+}
+
+func parseInt(value string, out *int) error {
+	n, err := strconv.Atoi(value)
+	*out = n
+	return err
+}
+
+func parseInt64(value string, out *int64) error {
+	n, err := strconv.ParseInt(value, 10, 64)
+	*out = n
+	return err
+}
+
+func parseFee(value string, out *int64) error {
+	n, err := strconv.ParseInt(value, 10, 64)
+	*out = n
+	return err
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_FileAndEnv(t *testing.T) {
+	path := writeConfig(t, `
+node:
+  listen_addr: ":4000"
+  seed_nodes: ["10.0.0.1:3000"]
+  block_time: 2s
+dao:
+  token_symbol: BOCK
+  fees:
+    vote: 50
+  ipfs_pinning:
+    services:
+      - name: pinata
+        endpoint: https://api.pinata.cloud/psa
+        token: secret
+    reconcile_interval: 5m
+  content_store:
+    provider: s3
+    s3:
+      endpoint: http://minio:9000
+      bucket: dao-content
+  jobs:
+    ipfs_cleanup:
+      schedule: "0 4 * * 0"
+server:
+  listen_addr: ":9000"
+  allowed_origins: ["https://app.example.com"]
+  api_versions:
+    v1:
+      deprecated: 2027-01-01T00:00:00Z
+      sunset: 2027-07-01T00:00:00Z
+      successor: v2
+`)
+
+	t.Setenv("BOCK_MAX_PEERS", "8")
+	t.Setenv("BOCK_FEE_PROPOSAL", "2000")
+	t.Setenv("BOCK_ADMIN_TOKEN", "secret")
+	t.Setenv("BOCK_KEYSTORE_PASSPHRASE", "passphrase")
+	t.Setenv("BOCK_IPFS_GATEWAYS", "https://ipfs.io, https://dweb.link")
+	t.Setenv("BOCK_METADATA_CACHE_TTL", "15m")
+	t.Setenv("BOCK_CONTENT_STORE", "ipfs")
+	t.Setenv("BOCK_SMTP_PASSWORD", "smtp-password")
+	t.Setenv("BOCK_CACHE_REDIS_ADDR", "redis:6379")
+	t.Setenv("BOCK_REPLICA_OF", "http://primary:9000")
+	t.Setenv("BOCK_IDEMPOTENCY_TTL", "1h")
+	t.Setenv("BOCK_IDEMPOTENCY_MAX_BODY", "4096")
+	t.Setenv("BOCK_ACME_DOMAINS", "dao.example.com, api.example.com")
+	t.Setenv("BOCK_TRUSTED_PROXIES", "10.0.0.0/8,127.0.0.1")
+	t.Setenv("BOCK_LOG_LEVEL", "debug")
+	t.Setenv("BOCK_SNAPSHOT_DIR", "/var/lib/bock/snapshots")
+	t.Setenv("BOCK_APP_LINK_BASE", "https://app.example.com/")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, ":4000", cfg.Node.ListenAddr)
+	assert.Equal(t, []string{"10.0.0.1:3000"}, cfg.Node.SeedNodes)
+	assert.Equal(t, 2*time.Second, cfg.Node.BlockTime)
+	assert.Equal(t, 8, cfg.Node.MaxPeers)
+	assert.Equal(t, "BOCK", cfg.DAO.TokenSymbol)
+	// Settings missing from the file keep their defaults
+	assert.Equal(t, "ProjectX Token", cfg.DAO.TokenName)
+	assert.Equal(t, int64(50), cfg.DAO.Fees.Vote)
+	assert.Equal(t, int64(2000), cfg.DAO.Fees.Proposal)
+	assert.Equal(t, int64(100), cfg.DAO.Fees.Default)
+	assert.Equal(t, "secret", cfg.Server.AdminToken)
+	assert.Equal(t, "passphrase", cfg.Node.KeystorePassphrase)
+	assert.Equal(t, "pinata", cfg.DAO.IPFSPinning.Services[0].Name)
+	assert.Equal(t, 5*time.Minute, cfg.DAO.IPFSPinning.ReconcileInterval)
+	assert.Equal(t, []string{"https://ipfs.io", "https://dweb.link"}, cfg.DAO.IPFSPinning.Gateways)
+	assert.Equal(t, 15*time.Minute, cfg.DAO.MetadataCache.TTL)
+	assert.Equal(t, 1024, cfg.DAO.MetadataCache.MaxEntries)
+	assert.Equal(t, "dao-content", cfg.DAO.ContentStore.S3.Bucket)
+	assert.Equal(t, "ipfs", cfg.DAO.ContentStore.Provider)
+	assert.Equal(t, "smtp-password", cfg.DAO.Notifications.SMTP.Password)
+	assert.Equal(t, 587, cfg.DAO.Notifications.SMTP.Port)
+	assert.Equal(t, "redis:6379", cfg.Server.Cache.RedisAddr)
+	assert.Equal(t, 30*time.Second, cfg.Server.Cache.TTL)
+	assert.Equal(t, "http://primary:9000", cfg.Node.ReplicaOf)
+	assert.Equal(t, time.Hour, cfg.Server.IdempotencyTTL)
+	assert.Equal(t, int64(4096), cfg.Server.IdempotencyMaxBody)
+	assert.Equal(t, []string{"dao.example.com", "api.example.com"}, cfg.Server.TLS.ACMEDomains)
+	assert.True(t, cfg.Server.TLS.Enabled())
+	proxies, err := cfg.Server.TrustedProxyRanges()
+	require.NoError(t, err)
+	require.Len(t, proxies, 2)
+	assert.True(t, proxies[0].Contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, proxies[1].Contains(net.ParseIP("127.0.0.1")))
+	assert.False(t, proxies[1].Contains(net.ParseIP("127.0.0.2")))
+	assert.Equal(t, "debug", cfg.Node.LogLevel)
+	assert.Equal(t, "0 4 * * 0", cfg.DAO.Jobs["ipfs_cleanup"].Schedule)
+	assert.Equal(t, "@every 1m", cfg.DAO.Jobs["proposal_status"].Schedule)
+	assert.Equal(t, "/var/lib/bock/snapshots", cfg.Server.SnapshotDir)
+	assert.Equal(t, "https://app.example.com/", cfg.Server.AppLinkBase)
+	assert.Equal(t, time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC), cfg.Server.APIVersions["v1"].Sunset)
+	assert.Equal(t, "v2", cfg.Server.APIVersions["v1"].Successor)
+
+	assert.True(t, cfg.Server.AllowsOrigin("https://app.example.com"))
+	assert.False(t, cfg.Server.AllowsOrigin("https://evil.example.com"))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	// Unknown settings are typos, not silently ignored
+	_, err := Load(writeConfig(t, "node:\n  listen_adr: \":4000\"\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, "dao:\n  fees:\n    vote: -1\n"))
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	t.Setenv("BOCK_BLOCK_TIME", "soon")
+	_, err = Load("")
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(cfg *Config)
+	}{
+		{"listen address", func(cfg *Config) { cfg.Node.ListenAddr = "3000" }},
+		{"seed node", func(cfg *Config) { cfg.Node.SeedNodes = []string{"localhost"} }},
+		{"block time", func(cfg *Config) { cfg.Node.BlockTime = 0 }},
+		{"max peers", func(cfg *Config) { cfg.Node.MaxPeers = 0 }},
+		{"log level", func(cfg *Config) { cfg.Node.LogLevel = "verbose" }},
+		{"replica url", func(cfg *Config) { cfg.Node.ReplicaOf = "primary:9000" }},
+		{"replica api", func(cfg *Config) { cfg.Node.ReplicaOf = "http://primary:9000" }},
+		{"validating replica", func(cfg *Config) {
+			cfg.Node.ReplicaOf = "http://primary:9000"
+			cfg.Node.KeystoreDir = "keystore"
+			cfg.Node.ValidatorKey = "validator"
+		}},
+		{"job schedule", func(cfg *Config) { cfg.DAO.Jobs["proposal_status"] = JobConfig{Schedule: "61 * * * *"} }},
+		{"unknown job", func(cfg *Config) { cfg.DAO.Jobs["vacuum"] = JobConfig{Schedule: "@daily"} }},
+		{"token symbol", func(cfg *Config) { cfg.DAO.TokenSymbol = "" }},
+		{"decimals", func(cfg *Config) { cfg.DAO.TokenDecimals = 19 }},
+		{"pinning service", func(cfg *Config) { cfg.DAO.IPFSPinning.Services = []PinningServiceConfig{{Name: "pinata"}} }},
+		{"reconcile interval", func(cfg *Config) { cfg.DAO.IPFSPinning.ReconcileInterval = 0 }},
+		{"metadata cache size", func(cfg *Config) { cfg.DAO.MetadataCache.MaxEntries = 0 }},
+		{"metadata cache ttl", func(cfg *Config) { cfg.DAO.MetadataCache.TTL = 0 }},
+		{"archive limits", func(cfg *Config) { cfg.DAO.Archive.MaxProposals = 0 }},
+		{"content store provider", func(cfg *Config) { cfg.DAO.ContentStore.Provider = "ftp" }},
+		{"s3 bucket", func(cfg *Config) {
+			cfg.DAO.ContentStore = ContentStoreConfig{Provider: "s3", S3: S3StoreConfig{Endpoint: "http://minio:9000"}}
+		}},
+		{"arweave upload url", func(cfg *Config) { cfg.DAO.ContentStore.Provider = "arweave" }},
+		{"notification queue", func(cfg *Config) { cfg.DAO.Notifications.QueueSize = 0 }},
+		{"fcm project", func(cfg *Config) { cfg.DAO.Notifications.FCM.CredentialsFile = "fcm.json" }},
+		{"apns topic", func(cfg *Config) {
+			cfg.DAO.Notifications.APNs = APNsConfig{KeyFile: "apns.p8", KeyID: "KEY", TeamID: "TEAM"}
+		}},
+		{"smtp sender", func(cfg *Config) { cfg.DAO.Notifications.SMTP.Host = "smtp.example.com" }},
+		{"webhook attempts", func(cfg *Config) { cfg.DAO.Webhooks.MaxAttempts = 0 }},
+		{"webhook backoff", func(cfg *Config) { cfg.DAO.Webhooks.MaxBackoff = time.Millisecond }},
+		{"analytics sample interval", func(cfg *Config) { cfg.DAO.Analytics.SampleInterval = 0 }},
+		{"analytics retention", func(cfg *Config) { cfg.DAO.Analytics.Retention = time.Minute }},
+		{"api address", func(cfg *Config) { cfg.Server.ListenAddr = "9000" }},
+		{"empty origin", func(cfg *Config) { cfg.Server.AllowedOrigins = []string{""} }},
+		{"cache ttl", func(cfg *Config) { cfg.Server.Cache.TTL = -time.Second }},
+		{"cache redis address", func(cfg *Config) { cfg.Server.Cache.RedisAddr = "redis" }},
+		{"idempotency ttl", func(cfg *Config) { cfg.Server.IdempotencyTTL = -time.Hour }},
+		{"idempotency max body", func(cfg *Config) { cfg.Server.IdempotencyMaxBody = 0 }},
+		{"tls key", func(cfg *Config) { cfg.Server.TLS.CertFile = "api.crt" }},
+		{"tls cert and acme", func(cfg *Config) {
+			cfg.Server.TLS = TLSConfig{CertFile: "api.crt", KeyFile: "api.key", ACMEDomains: []string{"dao.example.com"}}
+		}},
+		{"acme cache", func(cfg *Config) { cfg.Server.TLS = TLSConfig{ACMEDomains: []string{"dao.example.com"}} }},
+		{"trusted proxy", func(cfg *Config) { cfg.Server.TrustedProxies = []string{"proxy.internal"} }},
+		{"api version schedule", func(cfg *Config) { cfg.Server.APIVersions = map[string]APIVersionConfig{"v1": {Successor: "v2"}} }},
+		{"api version sunset", func(cfg *Config) {
+			cfg.Server.APIVersions = map[string]APIVersionConfig{"v1": {
+				Deprecated: time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC),
+				Sunset:     time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			}}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(cfg)
+			assert.Error(t, cfg.Validate())
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Server.AdminToken = "secret"
+	cfg.Server.ExportTokens = []string{"auditor-token"}
+
+	cfg.Node.KeystorePassphrase = "passphrase"
+	cfg.DAO.IPFSPinning.Services = []PinningServiceConfig{{Name: "pinata", Endpoint: "https://api.pinata.cloud/psa", Token: "pinning-token"}}
+	cfg.DAO.ContentStore.S3.SecretKey = "s3-secret"
+	cfg.DAO.ContentStore.Arweave.Token = "bundler-token"
+	cfg.DAO.Notifications.SMTP.Password = "smtp-password"
+	cfg.Server.Cache.RedisPassword = "redis-password"
+
+	redacted := cfg.Redacted()
+	assert.Equal(t, "[redacted]", redacted.Server.AdminToken)
+	assert.Equal(t, "secret", cfg.Server.AdminToken)
+	assert.Equal(t, []string{"[redacted]"}, redacted.Server.ExportTokens)
+	assert.Equal(t, "auditor-token", cfg.Server.ExportTokens[0])
+	assert.Equal(t, "[redacted]", redacted.DAO.IPFSPinning.Services[0].Token)
+	assert.Equal(t, "pinning-token", cfg.DAO.IPFSPinning.Services[0].Token)
+	assert.Equal(t, "[redacted]", redacted.DAO.ContentStore.S3.SecretKey)
+	assert.Equal(t, "[redacted]", redacted.DAO.ContentStore.Arweave.Token)
+	assert.Equal(t, "s3-secret", cfg.DAO.ContentStore.S3.SecretKey)
+	assert.Equal(t, "[redacted]", redacted.DAO.Notifications.SMTP.Password)
+	assert.Equal(t, "[redacted]", redacted.Server.Cache.RedisPassword)
+
+	// The keystore passphrase is never serialized
+	data, err := json.Marshal(redacted)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "passphrase")
+}
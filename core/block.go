@@ -3,10 +3,10 @@ package core
 import (
 	"bytes"
 	"crypto/sha256"
-	"encoding/gob"
 	"fmt"
 	"time"
 
+	"github.com/BOCK-CHAIN/BockChain/codec"
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/types"
 )
@@ -17,14 +17,16 @@ type Header struct {
 	PrevBlockHash types.Hash
 	Height        uint32
 	Timestamp     int64
+	// StateRoot commits to the DAO state after the parent block was applied
+	StateRoot types.Hash
 }
 
+// Bytes returns the binary codec encoding of the header, which the block
+// hash is taken over
 func (h *Header) Bytes() []byte {
-	buf := &bytes.Buffer{}
-	enc := gob.NewEncoder(buf)
-	enc.Encode(h)
-
-	return buf.Bytes()
+	// A header holds nothing the codec cannot encode
+	data, _ := codec.Marshal(h)
+	return data
 }
 
 type Block struct {
@@ -68,8 +70,10 @@ func (b *Block) AddTransaction(tx *Transaction) {
 	b.DataHash = hash
 }
 
+// Sign signs the hash of the header, which covers every header field
 func (b *Block) Sign(privKey crypto.PrivateKey) error {
-	sig, err := privKey.Sign(b.Header.Bytes())
+	hash := BlockHasher{}.Hash(b.Header)
+	sig, err := privKey.Sign(hash.ToSlice())
 	if err != nil {
 		return err
 	}
@@ -85,14 +89,13 @@ func (b *Block) Verify() error {
 		return fmt.Errorf("block has no signature")
 	}
 
-	if !b.Signature.Verify(b.Validator, b.Header.Bytes()) {
+	hash := BlockHasher{}.Hash(b.Header)
+	if !b.Signature.Verify(b.Validator, hash.ToSlice()) {
 		return fmt.Errorf("block has invalid signature")
 	}
 
-	for _, tx := range b.Transactions {
-		if err := tx.Verify(); err != nil {
-			return err
-		}
+	if err := VerifyTransactions(b.Transactions); err != nil {
+		return err
 	}
 
 	dataHash, err := CalculateDataHash(b.Transactions)
@@ -127,7 +130,7 @@ func CalculateDataHash(txx []*Transaction) (hash types.Hash, err error) {
 	buf := &bytes.Buffer{}
 
 	for _, tx := range txx {
-		if err = tx.Encode(NewGobTxEncoder(buf)); err != nil {
+		if err = tx.Encode(NewBinaryTxEncoder(buf)); err != nil {
 			return
 		}
 	}
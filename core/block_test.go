@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"encoding/hex"
 	"testing"
 	"time"
 
@@ -31,6 +32,10 @@ func TestVerifyBlock(t *testing.T) {
 
 	b.Height = 100
 	assert.NotNil(t, b.Verify())
+
+	// A signature decoded without R is rejected rather than panicking
+	b.Signature = &crypto.Signature{S: b.Signature.S}
+	assert.NotNil(t, b.Verify())
 }
 
 func TestDecodeEncodeBlock(t *testing.T) {
@@ -52,6 +57,30 @@ func TestDecodeEncodeBlock(t *testing.T) {
 	assert.Equal(t, b.Signature, bDecode.Signature)
 }
 
+func TestDecodeEncodeBinaryBlock(t *testing.T) {
+	b := randomBlock(t, 1, types.Hash{})
+	buf := &bytes.Buffer{}
+	assert.Nil(t, b.Encode(NewBinaryBlockEncoder(buf)))
+
+	bDecode := new(Block)
+	assert.Nil(t, bDecode.Decode(NewBinaryBlockDecoder(buf)))
+	assert.Nil(t, bDecode.Verify())
+	assert.Equal(t, b.Hash(BlockHasher{}), bDecode.Hash(BlockHasher{}))
+}
+
+// Block hashes are taken over the binary encoding of the header, pinned here
+func TestHeaderBytesGolden(t *testing.T) {
+	h := &Header{
+		Version:       1,
+		DataHash:      types.Hash{0x01},
+		PrevBlockHash: types.Hash{0x02},
+		Height:        3,
+		Timestamp:     4,
+		StateRoot:     types.Hash{0x05},
+	}
+	assert.Equal(t, "0106010100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000003080500000000000000000000000000000000000000000000000000000000000000", hex.EncodeToString(h.Bytes()))
+}
+
 func randomBlock(t *testing.T, height uint32, prevBlockHash types.Hash) *Block {
 	privKey := crypto.GeneratePrivateKey()
 	tx := randomTxWithSignature(t)
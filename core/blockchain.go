@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -33,6 +34,35 @@ type Blockchain struct {
 	daoState      *dao.GovernanceState
 	daoTokenState *dao.GovernanceToken
 	daoProcessor  *dao.DAOProcessor
+	daoActivity   *dao.ActivityIndex
+
+	// daoStateMachine, when registered, receives every DAO transaction
+	// included in a block. appliedDAOTxs guards against applying the
+	// same transaction twice.
+	daoStateMachine dao.StateMachine
+	appliedDAOTxs   map[types.Hash]uint32
+
+	// daoStateRoots holds the DAO state root after each block, by height.
+	// daoStateLeaves keeps the leaves of recent heights for state proofs.
+	daoStateRoots  []types.Hash
+	daoStateLeaves map[uint32][]dao.StateLeaf
+
+	// daoHistory journals the DAO state of every height for historical queries
+	daoHistory *daoStateHistory
+
+	// finality counts validator votes and remembers the finalized block
+	finality *finalityTracker
+
+	// pruning controls block retention, blocks below prunedBelow have
+	// been dropped while their headers are kept
+	pruning     PruningConfig
+	prunedBelow uint32
+
+	// blockAdded is closed and replaced each time a block is added
+	blockAdded chan struct{}
+
+	// txTracker follows the lifecycle of transactions
+	txTracker *TxTracker
 }
 
 func NewBlockchain(l log.Logger, genesis *Block) (*Blockchain, error) {
@@ -62,6 +92,12 @@ func NewBlockchain(l log.Logger, genesis *Block) (*Blockchain, error) {
 		daoState:        daoState,
 		daoTokenState:   daoTokenState,
 		daoProcessor:    daoProcessor,
+		daoActivity:     dao.NewActivityIndex(),
+		appliedDAOTxs:   make(map[types.Hash]uint32),
+		daoStateLeaves:  make(map[uint32][]dao.StateLeaf),
+		daoHistory:      newDAOStateHistory(daoCheckpointInterval),
+		finality:        newFinalityTracker(),
+		txTracker:       NewTxTracker(),
 	}
 	bc.validator = NewBlockValidator(bc)
 	err := bc.addBlockWithoutValidation(genesis)
@@ -114,60 +150,73 @@ func (bc *Blockchain) handleNativeNFT(tx *Transaction) error {
 }
 
 // handleDAOTransaction processes DAO-specific transactions
-func (bc *Blockchain) handleDAOTransaction(tx *Transaction) error {
+func (bc *Blockchain) handleDAOTransaction(tx *Transaction, height uint32) error {
 	hash := tx.Hash(TxHasher{})
 
-	switch t := tx.TxInner.(type) {
-	case dao.ProposalTx:
-		if err := bc.daoProcessor.ProcessProposalTx(&t, tx.From, hash); err != nil {
+	txInner, _ := daoTxPointer(tx.TxInner)
+
+	// A registered state machine owns DAO state, the built-in processor
+	// is only used when none is registered.
+	if bc.daoStateMachine != nil {
+		err := bc.applyDAOTransaction(txInner, tx.From, hash, height)
+		if errors.Is(err, ErrDAOTxApplied) {
+			// Already applied by an earlier block, keep it in this one
+			return nil
+		}
+		return err
+	}
+
+	switch t := txInner.(type) {
+	case *dao.ProposalTx:
+		if err := bc.daoProcessor.ProcessProposalTx(t, tx.From, hash); err != nil {
 			return fmt.Errorf("failed to process proposal transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO proposal", "hash", hash, "title", t.Title)
 
-	case dao.VoteTx:
-		if err := bc.daoProcessor.ProcessVoteTx(&t, tx.From); err != nil {
+	case *dao.VoteTx:
+		if err := bc.daoProcessor.ProcessVoteTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process vote transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO vote", "hash", hash, "proposal", t.ProposalID, "choice", t.Choice)
 
-	case dao.DelegationTx:
-		if err := bc.daoProcessor.ProcessDelegationTx(&t, tx.From); err != nil {
+	case *dao.DelegationTx:
+		if err := bc.daoProcessor.ProcessDelegationTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process delegation transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO delegation", "hash", hash, "delegator", tx.From, "delegate", t.Delegate)
 
-	case dao.TreasuryTx:
-		if err := bc.daoProcessor.ProcessTreasuryTx(&t, hash); err != nil {
+	case *dao.TreasuryTx:
+		if err := bc.daoProcessor.ProcessTreasuryTx(t, hash); err != nil {
 			return fmt.Errorf("failed to process treasury transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO treasury", "hash", hash, "recipient", t.Recipient, "amount", t.Amount)
 
-	case dao.TokenMintTx:
-		if err := bc.daoProcessor.ProcessTokenMintTx(&t, tx.From); err != nil {
+	case *dao.TokenMintTx:
+		if err := bc.daoProcessor.ProcessTokenMintTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process token mint transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO token mint", "hash", hash, "recipient", t.Recipient, "amount", t.Amount)
 
-	case dao.TokenBurnTx:
-		if err := bc.daoProcessor.ProcessTokenBurnTx(&t, tx.From); err != nil {
+	case *dao.TokenBurnTx:
+		if err := bc.daoProcessor.ProcessTokenBurnTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process token burn transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO token burn", "hash", hash, "burner", tx.From, "amount", t.Amount)
 
-	case dao.TokenTransferTx:
-		if err := bc.daoProcessor.ProcessTokenTransferTx(&t, tx.From); err != nil {
+	case *dao.TokenTransferTx:
+		if err := bc.daoProcessor.ProcessTokenTransferTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process token transfer transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO token transfer", "hash", hash, "from", tx.From, "to", t.Recipient, "amount", t.Amount)
 
-	case dao.TokenApproveTx:
-		if err := bc.daoProcessor.ProcessTokenApproveTx(&t, tx.From); err != nil {
+	case *dao.TokenApproveTx:
+		if err := bc.daoProcessor.ProcessTokenApproveTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process token approve transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO token approve", "hash", hash, "owner", tx.From, "spender", t.Spender, "amount", t.Amount)
 
-	case dao.TokenTransferFromTx:
-		if err := bc.daoProcessor.ProcessTokenTransferFromTx(&t, tx.From); err != nil {
+	case *dao.TokenTransferFromTx:
+		if err := bc.daoProcessor.ProcessTokenTransferFromTx(t, tx.From); err != nil {
 			return fmt.Errorf("failed to process token transferFrom transaction: %w", err)
 		}
 		bc.logger.Log("msg", "processed DAO token transferFrom", "hash", hash, "spender", tx.From, "from", t.From, "to", t.Recipient, "amount", t.Amount)
@@ -176,6 +225,8 @@ func (bc *Blockchain) handleDAOTransaction(tx *Transaction) error {
 		return fmt.Errorf("unsupported DAO transaction type %T", t)
 	}
 
+	bc.daoActivity.RecordTransaction(txInner, tx.From, hash, height)
+
 	return nil
 }
 
@@ -199,6 +250,10 @@ func (bc *Blockchain) GetBlock(height uint32) (*Block, error) {
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
 
+	if height < bc.prunedBelow {
+		return nil, fmt.Errorf("%w: height (%d)", ErrBlockPruned, height)
+	}
+
 	return bc.blocks[height], nil
 }
 
@@ -238,7 +293,26 @@ func (bc *Blockchain) Height() uint32 {
 	return uint32(len(bc.headers) - 1)
 }
 
-func (bc *Blockchain) handleTransaction(tx *Transaction) error {
+// BlockAdded returns a channel that is closed once the next block is added
+func (bc *Blockchain) BlockAdded() <-chan struct{} {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if bc.blockAdded == nil {
+		bc.blockAdded = make(chan struct{})
+	}
+	return bc.blockAdded
+}
+
+// TxTracker returns the tracker following the lifecycle of transactions
+func (bc *Blockchain) TxTracker() *TxTracker {
+	if bc == nil {
+		return nil
+	}
+	return bc.txTracker
+}
+
+func (bc *Blockchain) handleTransaction(tx *Transaction, height uint32) error {
 	// If we have data inside execute that data on the VM.
 	if len(tx.Data) > 0 {
 		bc.logger.Log("msg", "executing code", "len", len(tx.Data), "hash", tx.Hash(&TxHasher{}))
@@ -254,7 +328,7 @@ func (bc *Blockchain) handleTransaction(tx *Transaction) error {
 	if tx.TxInner != nil {
 		// Check if it's a DAO transaction
 		if bc.isDAOTransaction(tx.TxInner) {
-			if err := bc.handleDAOTransaction(tx); err != nil {
+			if err := bc.handleDAOTransaction(tx, height); err != nil {
 				return err
 			}
 		} else {
@@ -277,21 +351,18 @@ func (bc *Blockchain) handleTransaction(tx *Transaction) error {
 
 // isDAOTransaction checks if a transaction inner type is a DAO transaction
 func (bc *Blockchain) isDAOTransaction(txInner any) bool {
-	switch txInner.(type) {
-	case dao.ProposalTx, dao.VoteTx, dao.DelegationTx, dao.TreasuryTx,
-		dao.TokenMintTx, dao.TokenBurnTx, dao.TokenTransferTx,
-		dao.TokenApproveTx, dao.TokenTransferFromTx:
-		return true
-	default:
-		return false
-	}
+	_, ok := daoTxPointer(txInner)
+	return ok
 }
 
 func (bc *Blockchain) addBlockWithoutValidation(b *Block) error {
+	rejected := make(map[types.Hash]string)
+
 	bc.stateLock.Lock()
 	for i := 0; i < len(b.Transactions); i++ {
-		if err := bc.handleTransaction(b.Transactions[i]); err != nil {
+		if err := bc.handleTransaction(b.Transactions[i], b.Height); err != nil {
 			bc.logger.Log("error", err.Error())
+			rejected[b.Transactions[i].Hash(TxHasher{})] = err.Error()
 
 			b.Transactions[i] = b.Transactions[len(b.Transactions)-1]
 			b.Transactions = b.Transactions[:len(b.Transactions)-1]
@@ -299,6 +370,9 @@ func (bc *Blockchain) addBlockWithoutValidation(b *Block) error {
 			continue
 		}
 	}
+	bc.collectDAOFees(b)
+	bc.pruneDAOState(b)
+	bc.recordDAOStateRoot(b.Height)
 	bc.stateLock.Unlock()
 
 	// fmt.Println("========ACCOUNT STATE==============")
@@ -313,8 +387,20 @@ func (bc *Blockchain) addBlockWithoutValidation(b *Block) error {
 	for _, tx := range b.Transactions {
 		bc.txStore[tx.Hash(TxHasher{})] = tx
 	}
+	bc.pruneBlocks()
+	if bc.blockAdded != nil {
+		close(bc.blockAdded)
+		bc.blockAdded = nil
+	}
 	bc.lock.Unlock()
 
+	for hash, reason := range rejected {
+		bc.txTracker.Rejected(hash, reason)
+	}
+	for _, tx := range b.Transactions {
+		bc.txTracker.Included(tx.Hash(TxHasher{}), b.Height)
+	}
+
 	bc.logger.Log(
 		"msg", "new block",
 		"hash", b.Hash(BlockHasher{}),
@@ -339,6 +425,11 @@ func (bc *Blockchain) GetDAOTokenState() *dao.GovernanceToken {
 	return bc.daoTokenState
 }
 
+// GetDAOActivity returns the indexed DAO transaction history of an address, newest first
+func (bc *Blockchain) GetDAOActivity(address crypto.PublicKey, txTypes []string, offset, limit int) ([]*dao.ActivityRecord, int) {
+	return bc.daoActivity.GetActivity(address.String(), txTypes, offset, limit)
+}
+
 // GetDAOProcessor returns the DAO transaction processor
 func (bc *Blockchain) GetDAOProcessor() *dao.DAOProcessor {
 	return bc.daoProcessor
@@ -349,7 +440,7 @@ func (bc *Blockchain) GetProposal(proposalID types.Hash) (*dao.Proposal, error)
 	bc.stateLock.RLock()
 	defer bc.stateLock.RUnlock()
 
-	proposal, exists := bc.daoState.Proposals[proposalID]
+	proposal, exists := bc.daoState.Proposal(proposalID)
 	if !exists {
 		return nil, fmt.Errorf("proposal with ID (%s) not found", proposalID)
 	}
@@ -364,9 +455,10 @@ func (bc *Blockchain) GetProposals() map[types.Hash]*dao.Proposal {
 
 	// Return a copy to prevent external modification
 	proposals := make(map[types.Hash]*dao.Proposal)
-	for id, proposal := range bc.daoState.Proposals {
+	bc.daoState.EachProposal(func(id types.Hash, proposal *dao.Proposal, _ map[string]*dao.Vote) bool {
 		proposals[id] = proposal
-	}
+		return true
+	})
 
 	return proposals
 }
@@ -376,7 +468,7 @@ func (bc *Blockchain) GetVotes(proposalID types.Hash) (map[string]*dao.Vote, err
 	bc.stateLock.RLock()
 	defer bc.stateLock.RUnlock()
 
-	votes, exists := bc.daoState.Votes[proposalID]
+	votes, exists := bc.daoState.ProposalVotes(proposalID)
 	if !exists {
 		return nil, fmt.Errorf("no votes found for proposal (%s)", proposalID)
 	}
@@ -418,6 +510,15 @@ func (bc *Blockchain) GetDelegation(delegator crypto.PublicKey) (*dao.Delegation
 	return delegation, nil
 }
 
+// WithDAOState runs fn with exclusive access to the DAO state, for
+// maintenance that changes it outside of block processing
+func (bc *Blockchain) WithDAOState(fn func()) {
+	bc.stateLock.Lock()
+	defer bc.stateLock.Unlock()
+
+	fn()
+}
+
 // UpdateProposalStatuses updates the status of all active proposals based on current time
 func (bc *Blockchain) UpdateProposalStatuses() error {
 	bc.stateLock.Lock()
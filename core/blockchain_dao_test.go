@@ -112,6 +112,13 @@ func testProposalCreationAndVoting(t *testing.T, bc *Blockchain, creator, voter1
 	require.NoError(t, err)
 	assert.Equal(t, "Test Proposal", proposal.Title)
 
+	// The listed proposals are a copy of the state
+	proposals := bc.GetProposals()
+	require.Contains(t, proposals, proposalID)
+	delete(proposals, proposalID)
+	_, err = bc.GetProposal(proposalID)
+	require.NoError(t, err)
+
 	// Update proposal status based on current time
 	err = bc.UpdateProposalStatuses()
 	require.NoError(t, err)
@@ -438,6 +445,17 @@ func randomDAOBlockWithTxs(t *testing.T, height uint32, prevBlockHash types.Hash
 	return block
 }
 
+// addDAOTx includes a DAO transaction signed by privKey in the next block
+func addDAOTx(t *testing.T, bc *Blockchain, privKey crypto.PrivateKey, txInner any) {
+	tx := NewTransaction(nil)
+	tx.TxInner = txInner
+	require.NoError(t, tx.Sign(privKey))
+
+	block := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+	require.NoError(t, bc.AddBlock(block))
+	require.Len(t, block.Transactions, 1, "DAO transaction failed to apply")
+}
+
 func getDAOPrevBlockHash(t *testing.T, bc *Blockchain) types.Hash {
 	prevHeader, err := bc.GetHeader(bc.Height())
 	require.NoError(t, err)
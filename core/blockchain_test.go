@@ -15,7 +15,6 @@ func TestSendNativeTransferTamper(t *testing.T) {
 	signer := crypto.GeneratePrivateKey()
 
 	block := randomBlock(t, uint32(1), getPrevBlockHash(t, bc, uint32(1)))
-	assert.Nil(t, block.Sign(signer))
 
 	privKeyBob := crypto.GeneratePrivateKey()
 	privKeyAlice := crypto.GeneratePrivateKey()
@@ -35,6 +34,7 @@ func TestSendNativeTransferTamper(t *testing.T) {
 	tx.To = hackerPrivKey.PublicKey()
 
 	block.AddTransaction(tx)
+	assert.Nil(t, block.Sign(signer))
 	assert.NotNil(t, bc.AddBlock(block)) // this should fail
 
 	_, err := bc.accountState.GetAccount(hackerPrivKey.PublicKey().Address())
@@ -46,7 +46,6 @@ func TestSendNativeTransferInsuffientBalance(t *testing.T) {
 	signer := crypto.GeneratePrivateKey()
 
 	block := randomBlock(t, uint32(1), getPrevBlockHash(t, bc, uint32(1)))
-	assert.Nil(t, block.Sign(signer))
 
 	privKeyBob := crypto.GeneratePrivateKey()
 	privKeyAlice := crypto.GeneratePrivateKey()
@@ -66,6 +65,7 @@ func TestSendNativeTransferInsuffientBalance(t *testing.T) {
 	fmt.Printf("bob => %s\n", privKeyBob.PublicKey().Address())
 
 	block.AddTransaction(tx)
+	assert.Nil(t, block.Sign(signer))
 	assert.Nil(t, bc.AddBlock(block))
 
 	_, err := bc.accountState.GetAccount(privKeyAlice.PublicKey().Address())
@@ -82,7 +82,6 @@ func TestSendNativeTransferSuccess(t *testing.T) {
 	signer := crypto.GeneratePrivateKey()
 
 	block := randomBlock(t, uint32(1), getPrevBlockHash(t, bc, uint32(1)))
-	assert.Nil(t, block.Sign(signer))
 
 	privKeyBob := crypto.GeneratePrivateKey()
 	privKeyAlice := crypto.GeneratePrivateKey()
@@ -97,6 +96,7 @@ func TestSendNativeTransferSuccess(t *testing.T) {
 	tx.Value = amount
 	tx.Sign(privKeyBob)
 	block.AddTransaction(tx)
+	assert.Nil(t, block.Sign(signer))
 
 	assert.Nil(t, bc.AddBlock(block))
 
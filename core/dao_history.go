@@ -0,0 +1,245 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+)
+
+// daoCheckpointInterval is the number of heights between full checkpoints of
+// the DAO state. Heights in between are rebuilt from the delta journal.
+const daoCheckpointInterval = 64
+
+// daoStateDelta holds the entries changed and removed by a single block
+type daoStateDelta struct {
+	Changed map[string][]byte
+	Removed []string
+}
+
+// daoStateHistory keeps periodic checkpoints of the DAO state and a journal
+// of per-height deltas, so the state after any block can be rebuilt
+type daoStateHistory struct {
+	interval    uint32
+	checkpoints map[uint32]map[string][]byte
+	journal     map[uint32]*daoStateDelta
+	head        map[string][]byte
+}
+
+func newDAOStateHistory(interval uint32) *daoStateHistory {
+	return &daoStateHistory{
+		interval:    interval,
+		checkpoints: make(map[uint32]map[string][]byte),
+		journal:     make(map[uint32]*daoStateDelta),
+		head:        make(map[string][]byte),
+	}
+}
+
+// record journals the entries of the state after the block at height
+func (h *daoStateHistory) record(height uint32, entries map[string][]byte) {
+	delta := &daoStateDelta{Changed: make(map[string][]byte)}
+	for key, value := range entries {
+		if previous, ok := h.head[key]; !ok || !bytes.Equal(previous, value) {
+			delta.Changed[key] = value
+		}
+	}
+	for key := range h.head {
+		if _, ok := entries[key]; !ok {
+			delta.Removed = append(delta.Removed, key)
+		}
+	}
+
+	h.journal[height] = delta
+	h.head = entries
+
+	if height%h.interval == 0 {
+		h.checkpoints[height] = entries
+	}
+}
+
+// stateAt rebuilds the state after the block at height from the nearest
+// checkpoint at or below it
+func (h *daoStateHistory) stateAt(height uint32) (map[string][]byte, bool) {
+	base := height - height%h.interval
+	checkpoint, ok := h.checkpoints[base]
+	if !ok {
+		return nil, false
+	}
+
+	entries := make(map[string][]byte, len(checkpoint))
+	for key, value := range checkpoint {
+		entries[key] = value
+	}
+
+	for next := base + 1; next <= height; next++ {
+		delta, ok := h.journal[next]
+		if !ok {
+			return nil, false
+		}
+		for key, value := range delta.Changed {
+			entries[key] = value
+		}
+		for _, key := range delta.Removed {
+			delete(entries, key)
+		}
+	}
+
+	return entries, true
+}
+
+// changesSince merges the journal of the heights after since up to height
+// into the entries changed and removed over that range
+func (h *daoStateHistory) changesSince(since, height uint32) (*daoStateDelta, bool) {
+	merged := &daoStateDelta{Changed: make(map[string][]byte)}
+	removed := make(map[string]bool)
+
+	for next := since + 1; next <= height; next++ {
+		delta, ok := h.journal[next]
+		if !ok {
+			return nil, false
+		}
+		for key, value := range delta.Changed {
+			merged.Changed[key] = value
+			delete(removed, key)
+		}
+		for _, key := range delta.Removed {
+			delete(merged.Changed, key)
+			removed[key] = true
+		}
+	}
+
+	for key := range removed {
+		merged.Removed = append(merged.Removed, key)
+	}
+	sort.Strings(merged.Removed)
+
+	return merged, true
+}
+
+// daoStateEntries are encoded DAO state entries by key
+type daoStateEntries map[string][]byte
+
+// Get returns the encoded entry stored under key
+func (e daoStateEntries) Get(key string) ([]byte, bool) {
+	value, ok := e[key]
+	return value, ok
+}
+
+// Decode decodes the entry stored under key into v, reporting whether it exists
+func (e daoStateEntries) Decode(key string, v interface{}) (bool, error) {
+	value, ok := e[key]
+	if !ok {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(value, v); err != nil {
+		return true, fmt.Errorf("failed to decode state entry %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Keys returns the sorted keys of the entries starting with prefix
+func (e daoStateEntries) Keys(prefix string) []string {
+	keys := []string{}
+	for key := range e {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// DAOStateSnapshot is the DAO state as it was after the block at Height
+type DAOStateSnapshot struct {
+	Height uint32
+	daoStateEntries
+}
+
+// DAOStateChanges holds the DAO entries changed by the blocks after Since up
+// to Height, and the keys they removed
+type DAOStateChanges struct {
+	Since   uint32
+	Height  uint32
+	Removed []string
+	daoStateEntries
+}
+
+// recordDAOHistory journals the state leaves of a height together with the
+// governance parameters of the state machine. The caller must hold the
+// state lock.
+func (bc *Blockchain) recordDAOHistory(height uint32, leaves []dao.StateLeaf) {
+	if bc.daoHistory == nil {
+		return
+	}
+
+	entries := make(map[string][]byte, len(leaves)+1)
+	for _, leaf := range leaves {
+		entries[leaf.Key] = leaf.Value
+	}
+
+	if source, ok := bc.daoStateMachine.(dao.ParameterSource); ok {
+		encoded, err := json.Marshal(source.GetParameterConfig())
+		if err != nil {
+			bc.logger.Log("msg", "failed to journal DAO parameters", "height", height, "error", err)
+		} else {
+			entries[dao.ParametersStateKey] = encoded
+		}
+	}
+
+	bc.daoHistory.record(height, entries)
+}
+
+// GetDAOStateAt returns the DAO state as it was after the block at height
+func (bc *Blockchain) GetDAOStateAt(height uint32) (*DAOStateSnapshot, error) {
+	if height > bc.Height() {
+		return nil, fmt.Errorf("height (%d) is ahead of the chain", height)
+	}
+
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	if bc.daoHistory == nil {
+		return nil, fmt.Errorf("DAO state history is not available")
+	}
+
+	entries, ok := bc.daoHistory.stateAt(height)
+	if !ok {
+		return nil, fmt.Errorf("DAO state for height (%d) is not available", height)
+	}
+
+	return &DAOStateSnapshot{Height: height, daoStateEntries: entries}, nil
+}
+
+// GetDAOStateChanges returns the DAO entries changed and removed since the
+// block at since up to the head of the chain
+func (bc *Blockchain) GetDAOStateChanges(since uint32) (*DAOStateChanges, error) {
+	height := bc.Height()
+	if since > height {
+		return nil, fmt.Errorf("height (%d) is ahead of the chain", since)
+	}
+
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	if bc.daoHistory == nil {
+		return nil, fmt.Errorf("DAO state history is not available")
+	}
+
+	delta, ok := bc.daoHistory.changesSince(since, height)
+	if !ok {
+		return nil, fmt.Errorf("DAO state changes since height (%d) are not available", since)
+	}
+
+	return &DAOStateChanges{
+		Since:           since,
+		Height:          height,
+		Removed:         delta.Removed,
+		daoStateEntries: delta.Changed,
+	}, nil
+}
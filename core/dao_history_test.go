@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDAOHistory_CheckpointsAndJournal(t *testing.T) {
+	h := newDAOStateHistory(4)
+
+	recorded := make([]map[string][]byte, 10)
+	for height := uint32(0); height < 10; height++ {
+		entries := map[string][]byte{
+			"counter": []byte(fmt.Sprint(height)),
+			"fixed":   []byte("1"),
+		}
+		// An entry that only exists at even heights
+		if height%2 == 0 {
+			entries["even"] = []byte("1")
+		}
+		recorded[height] = entries
+		h.record(height, entries)
+	}
+
+	for height := uint32(0); height < 10; height++ {
+		entries, ok := h.stateAt(height)
+		require.True(t, ok)
+		assert.Equal(t, recorded[height], entries, "height %d", height)
+	}
+
+	// Only changed entries are journaled between checkpoints
+	assert.Len(t, h.checkpoints, 3)
+	assert.Len(t, h.journal[5].Changed, 1)
+	assert.Equal(t, []string{"even"}, h.journal[5].Removed)
+
+	_, ok := h.stateAt(10)
+	assert.False(t, ok)
+}
+
+func TestDAOHistory_ChangesSince(t *testing.T) {
+	h := newDAOStateHistory(4)
+
+	h.record(0, map[string][]byte{"a": []byte("1"), "b": []byte("1")})
+	h.record(1, map[string][]byte{"a": []byte("2"), "b": []byte("1"), "c": []byte("1")})
+	h.record(2, map[string][]byte{"a": []byte("3"), "c": []byte("1")})
+	h.record(3, map[string][]byte{"a": []byte("3"), "b": []byte("2")})
+
+	// Later heights win and entries removed then recreated are changes
+	delta, ok := h.changesSince(0, 3)
+	require.True(t, ok)
+	assert.Equal(t, map[string][]byte{"a": []byte("3"), "b": []byte("2")}, delta.Changed)
+	assert.Equal(t, []string{"c"}, delta.Removed)
+
+	delta, ok = h.changesSince(3, 3)
+	require.True(t, ok)
+	assert.Empty(t, delta.Changed)
+	assert.Empty(t, delta.Removed)
+
+	_, ok = h.changesSince(2, 4)
+	assert.False(t, ok)
+}
+
+func TestDAOHistory_StateAtPastHeight(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, sender)
+
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+
+	tx := &Transaction{
+		TxInner: dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 100},
+	}
+	require.NoError(t, tx.Sign(sender))
+	require.NoError(t, bc.AddBlock(randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})))
+
+	d.GetParameterConfig().VoteRetentionPeriod = 3600
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+
+	before, err := bc.GetDAOStateAt(1)
+	require.NoError(t, err)
+	after, err := bc.GetDAOStateAt(2)
+	require.NoError(t, err)
+
+	var balance uint64
+	exists, err := before.Decode(dao.BalanceStateKey(sender.PublicKey().String()), &balance)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(10000), balance)
+
+	exists, err = after.Decode(dao.BalanceStateKey(recipient.PublicKey().String()), &balance)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(100), balance)
+
+	// Parameters are journaled even though they are not committed
+	config := &dao.ParameterConfig{}
+	_, err = after.Decode(dao.ParametersStateKey, config)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), config.VoteRetentionPeriod)
+
+	latest, err := bc.GetDAOStateAt(bc.Height())
+	require.NoError(t, err)
+	_, err = latest.Decode(dao.ParametersStateKey, config)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), config.VoteRetentionPeriod)
+
+	_, err = bc.GetDAOStateAt(bc.Height() + 1)
+	assert.Error(t, err)
+
+	changes, err := bc.GetDAOStateChanges(1)
+	require.NoError(t, err)
+	assert.Equal(t, bc.Height(), changes.Height)
+	exists, err = changes.Decode(dao.BalanceStateKey(recipient.PublicKey().String()), &balance)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(100), balance)
+	assert.Equal(t, []string{dao.ParametersStateKey}, changes.Keys(dao.ParametersStateKey))
+
+	_, err = bc.GetDAOStateChanges(bc.Height() + 1)
+	assert.Error(t, err)
+}
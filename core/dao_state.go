@@ -0,0 +1,389 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+var ErrDAOTxApplied = errors.New("DAO transaction already applied")
+
+// ErrDAOTxUnsigned rejects DAO transactions submitted to the chain without
+// the signed transaction carrying them
+var ErrDAOTxUnsigned = errors.New("DAO transactions must be submitted as signed transactions")
+
+// daoStateProofDepth is the number of recent heights state proofs can be served for
+const daoStateProofDepth = 16
+
+var _ dao.ChainSubmitter = (*Blockchain)(nil)
+
+// RegisterDAOStateMachine hands DAO state transitions over to sm. From now on
+// every DAO transaction included in a block is applied through it, and sm
+// should route its own direct submissions back through SubmitDAOTransaction,
+// which refuses them.
+func (bc *Blockchain) RegisterDAOStateMachine(sm dao.StateMachine) {
+	bc.stateLock.Lock()
+	defer bc.stateLock.Unlock()
+
+	bc.daoStateMachine = sm
+}
+
+// HasDAOStateMachine reports whether an external DAO state machine is registered
+func (bc *Blockchain) HasDAOStateMachine() bool {
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	return bc.daoStateMachine != nil
+}
+
+// SubmitDAOTransaction refuses a DAO transaction submitted outside of a
+// block. Only the signature of the transaction carrying it proves who sent
+// it, and DAO state only changes when a block including that transaction is
+// added, so DAO transactions join the mempool like any other.
+func (bc *Blockchain) SubmitDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash) error {
+	return fmt.Errorf("%w: %s", ErrDAOTxUnsigned, txHash)
+}
+
+// GetDAOTxHeight returns the height at which a DAO transaction was applied
+func (bc *Blockchain) GetDAOTxHeight(txHash types.Hash) (uint32, bool) {
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	height, ok := bc.appliedDAOTxs[txHash]
+	return height, ok
+}
+
+// applyDAOTransaction applies a DAO transaction through the registered state
+// machine exactly once. The caller must hold the state lock.
+func (bc *Blockchain) applyDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash, height uint32) error {
+	if appliedAt, ok := bc.appliedDAOTxs[txHash]; ok {
+		return fmt.Errorf("%w at height %d", ErrDAOTxApplied, appliedAt)
+	}
+
+	if err := bc.daoStateMachine.ApplyDAOTransaction(txInner, from, txHash, height); err != nil {
+		return fmt.Errorf("failed to apply DAO transaction: %w", err)
+	}
+
+	bc.appliedDAOTxs[txHash] = height
+	bc.logger.Log("msg", "applied DAO transaction", "hash", txHash, "type", dao.ActivityTypeOf(txInner), "height", height)
+
+	return nil
+}
+
+// collectDAOFees hands the fees of the DAO transactions kept in a block to
+// the registered state machine for sharing with the block producer. The
+// caller must hold the state lock.
+func (bc *Blockchain) collectDAOFees(b *Block) {
+	collector, ok := bc.daoStateMachine.(dao.FeeCollector)
+	if !ok {
+		return
+	}
+
+	fees := uint64(0)
+	for _, tx := range b.Transactions {
+		if txInner, ok := daoTxPointer(tx.TxInner); ok {
+			fees += dao.TxFee(txInner)
+		}
+	}
+
+	collector.CollectBlockFees(b.Validator, fees, b.Height)
+}
+
+// GetDAOStateRoot returns the DAO state root recorded after the block at height
+func (bc *Blockchain) GetDAOStateRoot(height uint32) (types.Hash, error) {
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	if int(height) >= len(bc.daoStateRoots) {
+		return types.Hash{}, fmt.Errorf("no DAO state root for height (%d)", height)
+	}
+
+	return bc.daoStateRoots[height], nil
+}
+
+// recordDAOStateRoot computes the DAO state root after the block at height
+// was applied and keeps the leaves of recent heights for state proofs. The
+// caller must hold the state lock.
+func (bc *Blockchain) recordDAOStateRoot(height uint32) {
+	var (
+		leaves []dao.StateLeaf
+		err    error
+	)
+
+	if bc.daoStateMachine != nil {
+		leaves, err = bc.daoStateMachine.StateLeaves()
+	} else {
+		leaves, err = dao.StateLeaves(bc.daoState, bc.daoTokenState)
+	}
+
+	if err != nil {
+		bc.logger.Log("msg", "failed to compute DAO state root", "height", height, "error", err)
+		leaves = nil
+	}
+
+	bc.daoStateRoots = append(bc.daoStateRoots, dao.StateRootFromLeaves(leaves))
+	bc.recordDAOHistory(height, leaves)
+
+	bc.daoStateLeaves[height] = leaves
+	if height >= daoStateProofDepth {
+		delete(bc.daoStateLeaves, height-daoStateProofDepth)
+	}
+}
+
+// GetDAOStateProof returns a Merkle proof for a DAO state key against the
+// state root committed in the latest block header, together with that
+// header's height and the root
+func (bc *Blockchain) GetDAOStateProof(key string) (*dao.StateProof, uint32, types.Hash, error) {
+	height := bc.Height()
+	if height == 0 {
+		return nil, 0, types.Hash{}, fmt.Errorf("no block commits to a DAO state root yet")
+	}
+
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	// The header at height commits to the state after its parent block
+	leaves, ok := bc.daoStateLeaves[height-1]
+	if !ok || int(height-1) >= len(bc.daoStateRoots) {
+		return nil, 0, types.Hash{}, fmt.Errorf("DAO state for height (%d) is not available", height-1)
+	}
+
+	proof, err := dao.BuildStateProof(leaves, key)
+	if err != nil {
+		return nil, 0, types.Hash{}, err
+	}
+
+	return proof, height, bc.daoStateRoots[height-1], nil
+}
+
+// daoTxPointer returns the pointer form of a DAO transaction, accepting both
+// the value types decoded from the network and the pointer types built locally
+func daoTxPointer(txInner any) (any, bool) {
+	switch t := txInner.(type) {
+	case dao.ProposalTx:
+		return &t, true
+	case dao.VoteTx:
+		return &t, true
+	case dao.DelegationTx:
+		return &t, true
+	case dao.TreasuryTx:
+		return &t, true
+	case dao.TokenMintTx:
+		return &t, true
+	case dao.TokenBurnTx:
+		return &t, true
+	case dao.TokenTransferTx:
+		return &t, true
+	case dao.TokenApproveTx:
+		return &t, true
+	case dao.TokenTransferFromTx:
+		return &t, true
+	case dao.ParameterProposalTx:
+		return &t, true
+	case dao.TokenDistributionTx:
+		return &t, true
+	case dao.VestingClaimTx:
+		return &t, true
+	case dao.StakeTx:
+		return &t, true
+	case dao.UnstakeTx:
+		return &t, true
+	case dao.ClaimRewardsTx:
+		return &t, true
+	case dao.PositionTransferTx:
+		return &t, true
+	case dao.DisputeTx:
+		return &t, true
+	case dao.DisputeEvidenceTx:
+		return &t, true
+	case dao.JurorCommitTx:
+		return &t, true
+	case dao.JurorRevealTx:
+		return &t, true
+	case dao.FundingKPITx:
+		return &t, true
+	case dao.ImpactReviewTx:
+		return &t, true
+	case dao.ValidatorConfigTx:
+		return &t, true
+	case dao.ClaimCommissionTx:
+		return &t, true
+	case dao.ValidatorSetProposalTx:
+		return &t, true
+	case dao.ValidatorSetExecuteTx:
+		return &t, true
+	case dao.MultisigCreateTx:
+		return &t, true
+	case dao.MultisigSubmitTx:
+		return multisigSubmitPointer(&t), true
+	case *dao.MultisigSubmitTx:
+		return multisigSubmitPointer(t), true
+	case dao.MultisigSignTx:
+		return &t, true
+	case dao.SubDAOCreateProposalTx:
+		return &t, true
+	case dao.SubDAOCreateExecuteTx:
+		return &t, true
+	case dao.SubDAOProposalTx:
+		return &t, true
+	case dao.SubDAOVoteTx:
+		return &t, true
+	case dao.GrantProposalTx:
+		return &t, true
+	case dao.GrantExecuteTx:
+		return &t, true
+	case dao.GrantMilestoneSubmitTx:
+		return &t, true
+	case dao.GrantMilestoneReviewTx:
+		return &t, true
+	case dao.GrantCancelTx:
+		return &t, true
+	case dao.BountyProposalTx:
+		return &t, true
+	case dao.BountyPostTx:
+		return &t, true
+	case dao.BountyClaimTx:
+		return &t, true
+	case dao.BountySubmitTx:
+		return &t, true
+	case dao.BountyReviewTx:
+		return &t, true
+	case dao.BountyCancelTx:
+		return &t, true
+	case dao.QFRoundProposalTx:
+		return &t, true
+	case dao.QFRoundOpenTx:
+		return &t, true
+	case dao.QFContributeTx:
+		return &t, true
+	case dao.QFPayoutTx:
+		return &t, true
+	case dao.OptimisticProposalTx:
+		return &t, true
+	case dao.OptimisticChallengeTx:
+		return &t, true
+	case dao.RPGFRoundProposalTx:
+		return &t, true
+	case dao.RPGFRoundOpenTx:
+		return &t, true
+	case dao.RPGFNominateTx:
+		return &t, true
+	case dao.RPGFBallotTx:
+		return &t, true
+	case dao.RPGFFinalizeTx:
+		return &t, true
+	case dao.RPGFClaimTx:
+		return &t, true
+	case dao.OracleFeedProposalTx:
+		return &t, true
+	case dao.OracleFeedActivateTx:
+		return &t, true
+	case dao.OracleReportTx:
+		return &t, true
+	case dao.TrackProposalTx:
+		return &t, true
+	case dao.TrackActivateTx:
+		return &t, true
+	case dao.ParameterScheduleTx:
+		return &t, true
+	case dao.ConstitutionAmendmentTx:
+		return &t, true
+	case dao.ConstitutionEnactTx:
+		return &t, true
+	case dao.EmergencySpendTx:
+		return &t, true
+	case dao.EmergencyExecuteTx:
+		return &t, true
+	case dao.ParticipationOptInTx:
+		return &t, true
+	case dao.EndorseProposalTx:
+		return &t, true
+	case dao.PrivacySettingsTx:
+		return &t, true
+	case dao.NameRegisterTx:
+		return &t, true
+	case dao.NameRenewTx:
+		return &t, true
+	case dao.NameTransferTx:
+		return &t, true
+	case dao.ProfileUpdateTx:
+		return &t, true
+	case dao.DelegateStatementTx:
+		return &t, true
+	case dao.CommentTx:
+		return &t, true
+	case dao.JoinRequestTx:
+		return &t, true
+	case dao.JoinApprovalTx:
+		return &t, true
+	case dao.MembershipStatusTx:
+		return &t, true
+	case dao.RageQuitTx:
+		return &t, true
+	case dao.DAOCreateTx:
+		return &t, true
+	case dao.HostedDAOTx:
+		return hostedTxPointer(&t), true
+	case *dao.HostedDAOTx:
+		return hostedTxPointer(t), true
+	case *dao.ProposalTx, *dao.VoteTx, *dao.DelegationTx, *dao.TreasuryTx,
+		*dao.TokenMintTx, *dao.TokenBurnTx, *dao.TokenTransferTx,
+		*dao.TokenApproveTx, *dao.TokenTransferFromTx, *dao.ParameterProposalTx,
+		*dao.TokenDistributionTx, *dao.VestingClaimTx, *dao.StakeTx,
+		*dao.UnstakeTx, *dao.ClaimRewardsTx, *dao.PositionTransferTx,
+		*dao.DisputeTx, *dao.DisputeEvidenceTx, *dao.JurorCommitTx, *dao.JurorRevealTx,
+		*dao.FundingKPITx, *dao.ImpactReviewTx, *dao.ValidatorConfigTx, *dao.ClaimCommissionTx,
+		*dao.ValidatorSetProposalTx, *dao.ValidatorSetExecuteTx,
+		*dao.MultisigCreateTx, *dao.MultisigSignTx, *dao.SubDAOCreateProposalTx,
+		*dao.SubDAOCreateExecuteTx, *dao.SubDAOProposalTx, *dao.SubDAOVoteTx,
+		*dao.GrantProposalTx, *dao.GrantExecuteTx, *dao.GrantMilestoneSubmitTx,
+		*dao.GrantMilestoneReviewTx, *dao.GrantCancelTx, *dao.BountyProposalTx,
+		*dao.BountyPostTx, *dao.BountyClaimTx, *dao.BountySubmitTx,
+		*dao.BountyReviewTx, *dao.BountyCancelTx, *dao.QFRoundProposalTx,
+		*dao.QFRoundOpenTx, *dao.QFContributeTx, *dao.QFPayoutTx,
+		*dao.OptimisticProposalTx, *dao.OptimisticChallengeTx,
+		*dao.RPGFRoundProposalTx, *dao.RPGFRoundOpenTx, *dao.RPGFNominateTx,
+		*dao.RPGFBallotTx, *dao.RPGFFinalizeTx, *dao.RPGFClaimTx,
+		*dao.OracleFeedProposalTx, *dao.OracleFeedActivateTx, *dao.OracleReportTx,
+		*dao.TrackProposalTx, *dao.TrackActivateTx, *dao.ParameterScheduleTx,
+		*dao.ConstitutionAmendmentTx, *dao.ConstitutionEnactTx, *dao.EmergencySpendTx,
+		*dao.EmergencyExecuteTx, *dao.ParticipationOptInTx, *dao.EndorseProposalTx,
+		*dao.PrivacySettingsTx, *dao.NameRegisterTx, *dao.NameRenewTx,
+		*dao.NameTransferTx, *dao.ProfileUpdateTx, *dao.DelegateStatementTx,
+		*dao.CommentTx,
+		*dao.JoinRequestTx, *dao.JoinApprovalTx, *dao.MembershipStatusTx,
+		*dao.RageQuitTx, *dao.DAOCreateTx:
+		return t, true
+	default:
+		return nil, false
+	}
+}
+
+// multisigSubmitPointer converts the transaction a multisig submits to its
+// pointer form as well
+func multisigSubmitPointer(tx *dao.MultisigSubmitTx) *dao.MultisigSubmitTx {
+	inner, ok := daoTxPointer(tx.Tx)
+	if !ok {
+		return tx
+	}
+
+	normalized := *tx
+	normalized.Tx = inner
+	return &normalized
+}
+
+// hostedTxPointer converts the transaction a hosted DAO applies to its
+// pointer form as well
+func hostedTxPointer(tx *dao.HostedDAOTx) *dao.HostedDAOTx {
+	inner, ok := daoTxPointer(tx.Tx)
+	if !ok {
+		return tx
+	}
+
+	normalized := *tx
+	normalized.Tx = inner
+	return &normalized
+}
@@ -0,0 +1,358 @@
+package core
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/codec"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDAOWiredChain(t *testing.T, holders ...crypto.PrivateKey) (*Blockchain, *dao.DAO) {
+	bc, _ := newTestBlockchain(t)
+
+	d := dao.NewDAO("GOV", "Governance Token", 18)
+	distribution := make(map[string]uint64)
+	for _, holder := range holders {
+		distribution[holder.PublicKey().String()] = 10000
+	}
+	require.NoError(t, d.InitialTokenDistribution(distribution))
+
+	bc.RegisterDAOStateMachine(d)
+	d.SetChainSubmitter(bc)
+
+	return bc, d
+}
+
+func TestDAOStateMachine_BlockInclusion(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, sender)
+
+	// Both value and pointer inner types are routed to the state machine
+	valueTx := &Transaction{
+		TxInner: dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 1000},
+	}
+	require.NoError(t, valueTx.Sign(sender))
+
+	pointerTx := &Transaction{
+		TxInner: &dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 500},
+		Nonce:   1,
+	}
+	require.NoError(t, pointerTx.Sign(sender))
+
+	block := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{valueTx, pointerTx})
+	require.NoError(t, bc.AddBlock(block))
+
+	assert.Equal(t, uint64(1500), d.GetTokenBalance(recipient.PublicKey()))
+	assert.Equal(t, uint64(10000-1500-20), d.GetTokenBalance(sender.PublicKey()))
+
+	height, ok := bc.GetDAOTxHeight(valueTx.Hash(TxHasher{}))
+	require.True(t, ok)
+	assert.Equal(t, block.Height, height)
+
+	records, _ := d.GetMemberActivity(recipient.PublicKey(), nil, 0, 10)
+	require.Len(t, records, 2)
+	assert.Equal(t, block.Height, records[0].BlockHeight)
+}
+
+// recordingStateMachine accepts every DAO transaction, recording its type
+type recordingStateMachine struct {
+	applied map[reflect.Type]int
+}
+
+func (sm *recordingStateMachine) ApplyDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash, height uint32) error {
+	sm.applied[reflect.TypeOf(txInner).Elem()]++
+	return nil
+}
+
+func (sm *recordingStateMachine) StateLeaves() ([]dao.StateLeaf, error) {
+	return nil, nil
+}
+
+// daoTxPointerTypes returns the names of the dao types daoTxPointer routes,
+// read from its switch
+func daoTxPointerTypes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "dao_state.go", nil, 0)
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	var names []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if ok && fn.Name.Name != "daoTxPointer" {
+			return false
+		}
+		clause, ok := n.(*ast.CaseClause)
+		if !ok {
+			return true
+		}
+		for _, expr := range clause.List {
+			if star, ok := expr.(*ast.StarExpr); ok {
+				expr = star.X
+			}
+			if sel, ok := expr.(*ast.SelectorExpr); ok && !seen[sel.Sel.Name] {
+				seen[sel.Sel.Name] = true
+				names = append(names, sel.Sel.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// Every DAO transaction type the chain routes can be signed, verified and
+// included in a block
+func TestDAOStateMachine_EveryTxTypeIncluded(t *testing.T) {
+	registered := make(map[string]reflect.Type)
+	pkg := reflect.TypeOf(dao.ProposalTx{}).PkgPath()
+	for tag := int(codec.FirstTag); tag <= 0xffff; tag++ {
+		if typ, ok := codec.TypeOf(uint16(tag)); ok && typ.PkgPath() == pkg {
+			registered[typ.Name()] = typ
+		}
+	}
+
+	bc, _ := newTestBlockchain(t)
+	sm := &recordingStateMachine{applied: make(map[reflect.Type]int)}
+	bc.RegisterDAOStateMachine(sm)
+
+	names := daoTxPointerTypes(t)
+	require.NotEmpty(t, names)
+	for _, name := range names {
+		typ, ok := registered[name]
+		if !assert.True(t, ok, "%s has no codec tag", name) {
+			continue
+		}
+
+		tx := &Transaction{TxInner: reflect.New(typ).Elem().Interface()}
+		require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+		require.NoError(t, tx.Verify(), name)
+
+		block := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+		require.NoError(t, bc.AddBlock(block), name)
+		assert.Equal(t, 1, sm.applied[typ], "%s was not applied", name)
+	}
+}
+
+func TestDAOStateMachine_DirectSubmissionRefused(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, sender)
+
+	tx := &Transaction{
+		TxInner: &dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 1000},
+	}
+	require.NoError(t, tx.Sign(sender))
+	hash := tx.Hash(TxHasher{})
+
+	// Direct processing does not change state outside of a block
+	err := d.ProcessDAOTransaction(tx.TxInner, sender.PublicKey(), hash)
+	assert.ErrorIs(t, err, ErrDAOTxUnsigned)
+	assert.Equal(t, uint64(0), d.GetTokenBalance(recipient.PublicKey()))
+	_, ok := bc.GetDAOTxHeight(hash)
+	assert.False(t, ok)
+
+	// The signed transaction applies once its block is added
+	block := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+	require.NoError(t, bc.AddBlock(block))
+	assert.Equal(t, uint64(1000), d.GetTokenBalance(recipient.PublicKey()))
+
+	// Including it in a later block does not apply it a second time
+	block = randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+	require.NoError(t, bc.AddBlock(block))
+	assert.Equal(t, uint64(1000), d.GetTokenBalance(recipient.PublicKey()))
+	assert.Len(t, block.Transactions, 1)
+}
+
+func TestDAOStateMachine_FailedTxDropped(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, sender)
+
+	tx := &Transaction{
+		TxInner: dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 1000000},
+	}
+	require.NoError(t, tx.Sign(sender))
+
+	block := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+	require.NoError(t, bc.AddBlock(block))
+
+	assert.Empty(t, block.Transactions)
+	assert.Equal(t, uint64(0), d.GetTokenBalance(recipient.PublicKey()))
+
+	_, ok := bc.GetDAOTxHeight(tx.Hash(TxHasher{}))
+	assert.False(t, ok)
+}
+
+func TestDAOStateRoot_RecordedPerBlock(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, sender)
+
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+	before, err := bc.GetDAOStateRoot(bc.Height())
+	require.NoError(t, err)
+
+	expected, err := d.StateRoot()
+	require.NoError(t, err)
+	assert.Equal(t, expected, before)
+
+	tx := &Transaction{
+		TxInner: dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 1000},
+	}
+	require.NoError(t, tx.Sign(sender))
+	require.NoError(t, bc.AddBlock(randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})))
+
+	after, err := bc.GetDAOStateRoot(bc.Height())
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+
+	_, err = bc.GetDAOStateRoot(bc.Height() + 1)
+	assert.Error(t, err)
+}
+
+func TestDAOStateRoot_HeaderValidation(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	bc, _ := newTestDAOWiredChain(t, sender)
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+
+	newBlock := func(stateRoot types.Hash) *Block {
+		prevHeader, err := bc.GetHeader(bc.Height())
+		require.NoError(t, err)
+
+		block, err := NewBlockFromPrevHeader(prevHeader, []*Transaction{})
+		require.NoError(t, err)
+		block.StateRoot = stateRoot
+		require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+
+		return block
+	}
+
+	err := bc.AddBlock(newBlock(types.Hash{0xFF}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DAO state root")
+
+	stateRoot, err := bc.GetDAOStateRoot(bc.Height())
+	require.NoError(t, err)
+	require.NoError(t, bc.AddBlock(newBlock(stateRoot)))
+}
+
+func TestDAOStateProof_AgainstHeaderRoot(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	bc, _ := newTestDAOWiredChain(t, sender)
+
+	key := dao.BalanceStateKey(sender.PublicKey().String())
+	_, _, _, err := bc.GetDAOStateProof(key)
+	require.Error(t, err)
+
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+
+	proof, height, root, err := bc.GetDAOStateProof(key)
+	require.NoError(t, err)
+	assert.Equal(t, bc.Height(), height)
+	assert.Equal(t, "10000", string(proof.Value))
+	assert.True(t, proof.Verify(root))
+
+	committed, err := bc.GetDAOStateRoot(height - 1)
+	require.NoError(t, err)
+	assert.Equal(t, committed, root)
+
+	_, _, _, err = bc.GetDAOStateProof(dao.BalanceStateKey("unknown"))
+	assert.Error(t, err)
+}
+
+func TestDAOFees_SharedWithBlockProducer(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	producer := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, sender, producer)
+
+	require.NoError(t, d.TokenomicsManager.CreateStakingPool("producer-pool", "Producer Pool", 0, 1, 0))
+	config := &dao.ValidatorConfigTx{Fee: 10, PoolID: "producer-pool", CommissionBps: 1000}
+	addDAOTx(t, bc, producer, config)
+	require.NoError(t, d.TokenomicsManager.StakeTokens("producer-pool", sender.PublicKey(), 1000, 0))
+
+	tx := &Transaction{
+		TxInner: dao.TokenTransferTx{Fee: 50, Recipient: recipient.PublicKey(), Amount: 100},
+	}
+	require.NoError(t, tx.Sign(sender))
+
+	block := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+	require.NoError(t, block.Sign(producer))
+	require.NoError(t, bc.AddBlock(block))
+
+	validator, exists := d.GetValidator(producer.PublicKey())
+	require.True(t, exists)
+	assert.Equal(t, uint64(1), validator.BlocksProduced)
+	assert.Equal(t, uint64(50), validator.FeesEarned)
+	assert.Equal(t, uint64(5), validator.ClaimableCommission)
+
+	staker, _ := d.TokenomicsManager.GetStakerInfo("producer-pool", sender.PublicKey())
+	assert.Equal(t, uint64(45), staker.FeeRewards)
+
+	// Blocks of other producers are not credited
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+	assert.Equal(t, uint64(1), validator.BlocksProduced)
+}
+
+func TestDAOTxPointer_MultisigSubmit(t *testing.T) {
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	tx := &Transaction{
+		TxInner: dao.MultisigSubmitTx{
+			Fee:        10,
+			MultisigID: types.Hash{0x01},
+			Tx:         &dao.TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 100},
+		},
+	}
+	require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, NewGobTxEncoder(buf).Encode(tx))
+	decoded := new(Transaction)
+	require.NoError(t, NewGobTxDecoder(buf).Decode(decoded))
+
+	// Both the submission and the transaction it carries become pointers
+	txInner, ok := daoTxPointer(decoded.TxInner)
+	require.True(t, ok)
+	submit, ok := txInner.(*dao.MultisigSubmitTx)
+	require.True(t, ok)
+	transfer, ok := submit.Tx.(*dao.TokenTransferTx)
+	require.True(t, ok)
+	assert.Equal(t, uint64(100), transfer.Amount)
+	assert.Equal(t, recipient, transfer.Recipient)
+}
+
+func TestDAOTxPointer_HostedDAO(t *testing.T) {
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	tx := &Transaction{
+		TxInner: &dao.HostedDAOTx{
+			DAOID: "acme",
+			Tx:    &dao.TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 100},
+		},
+	}
+	require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, NewGobTxEncoder(buf).Encode(tx))
+	decoded := new(Transaction)
+	require.NoError(t, NewGobTxDecoder(buf).Decode(decoded))
+
+	// Hosted DAOs receive the pointer form of the transaction they apply
+	txInner, ok := daoTxPointer(decoded.TxInner)
+	require.True(t, ok)
+	hosted, ok := txInner.(*dao.HostedDAOTx)
+	require.True(t, ok)
+	assert.Equal(t, "acme", hosted.DAOID)
+	transfer, ok := hosted.Tx.(*dao.TokenTransferTx)
+	require.True(t, ok)
+	assert.Equal(t, uint64(100), transfer.Amount)
+}
@@ -3,11 +3,14 @@ package core
 import (
 	"encoding/gob"
 	"io"
+
+	"github.com/BOCK-CHAIN/BockChain/codec"
 )
 
 //
-// For now we GOB encoding is used for fast bootstrapping of the project
-// in a later phase I'm considering using Protobuffers as default encoding / decoding.
+// GOB encoding was used for fast bootstrapping of the project. Anything that
+// is hashed or exchanged between nodes uses the deterministic binary codec,
+// gob remains for local streams and clients that still send it.
 //
 
 type Encoder[T any] interface {
@@ -73,3 +76,82 @@ func NewGobBlockDecoder(r io.Reader) *GobBlockDecoder {
 func (dec *GobBlockDecoder) Decode(b *Block) error {
 	return gob.NewDecoder(dec.r).Decode(b)
 }
+
+// BinaryTxEncoder writes transactions in the deterministic binary codec
+type BinaryTxEncoder struct {
+	w io.Writer
+}
+
+func NewBinaryTxEncoder(w io.Writer) *BinaryTxEncoder {
+	return &BinaryTxEncoder{
+		w: w,
+	}
+}
+
+func (e *BinaryTxEncoder) Encode(tx *Transaction) error {
+	return encodeBinary(e.w, tx)
+}
+
+// BinaryTxDecoder reads a transaction in the deterministic binary codec,
+// consuming the whole reader
+type BinaryTxDecoder struct {
+	r io.Reader
+}
+
+func NewBinaryTxDecoder(r io.Reader) *BinaryTxDecoder {
+	return &BinaryTxDecoder{
+		r: r,
+	}
+}
+
+func (d *BinaryTxDecoder) Decode(tx *Transaction) error {
+	return decodeBinary(d.r, tx)
+}
+
+// BinaryBlockEncoder writes blocks in the deterministic binary codec
+type BinaryBlockEncoder struct {
+	w io.Writer
+}
+
+func NewBinaryBlockEncoder(w io.Writer) *BinaryBlockEncoder {
+	return &BinaryBlockEncoder{
+		w: w,
+	}
+}
+
+func (enc *BinaryBlockEncoder) Encode(b *Block) error {
+	return encodeBinary(enc.w, b)
+}
+
+// BinaryBlockDecoder reads a block in the deterministic binary codec,
+// consuming the whole reader
+type BinaryBlockDecoder struct {
+	r io.Reader
+}
+
+func NewBinaryBlockDecoder(r io.Reader) *BinaryBlockDecoder {
+	return &BinaryBlockDecoder{
+		r: r,
+	}
+}
+
+func (dec *BinaryBlockDecoder) Decode(b *Block) error {
+	return decodeBinary(dec.r, b)
+}
+
+func encodeBinary(w io.Writer, v interface{}) error {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func decodeBinary(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, v)
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// VotePhase is a step of the two-phase finality protocol
+type VotePhase byte
+
+const (
+	VotePhasePrevote   VotePhase = 0x01 // The validator added the block to its chain
+	VotePhasePrecommit VotePhase = 0x02 // The validator saw a prevote quorum for the block
+)
+
+var (
+	ErrFinalityConflict = errors.New("block conflicts with the finalized chain")
+	ErrEquivocation     = errors.New("validator voted for conflicting blocks")
+	ErrNotValidator     = errors.New("vote is not from an active validator")
+)
+
+// FinalityVote is a validator's signed vote for a block in one finality phase
+type FinalityVote struct {
+	Height    uint32
+	BlockHash types.Hash
+	Phase     VotePhase
+	Validator crypto.PublicKey
+	Signature *crypto.Signature
+}
+
+// NewFinalityVote creates a vote for a block signed by a validator
+func NewFinalityVote(privKey crypto.PrivateKey, height uint32, blockHash types.Hash, phase VotePhase) (*FinalityVote, error) {
+	vote := &FinalityVote{
+		Height:    height,
+		BlockHash: blockHash,
+		Phase:     phase,
+		Validator: privKey.PublicKey(),
+	}
+
+	sig, err := privKey.Sign(vote.signingBytes())
+	if err != nil {
+		return nil, err
+	}
+	vote.Signature = sig
+
+	return vote, nil
+}
+
+// signingBytes returns the digest a validator signs, binding the phase,
+// height and block
+func (v *FinalityVote) signingBytes() []byte {
+	data := make([]byte, 5, 5+len(v.BlockHash))
+	data[0] = byte(v.Phase)
+	binary.BigEndian.PutUint32(data[1:], v.Height)
+	data = append(data, v.BlockHash.ToSlice()...)
+
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+// ID identifies a vote for deduplication
+func (v *FinalityVote) ID() types.Hash {
+	return sha256.Sum256(append(v.signingBytes(), v.Validator...))
+}
+
+// Verify checks the validator's signature on the vote
+func (v *FinalityVote) Verify() error {
+	if v.Signature == nil {
+		return fmt.Errorf("finality vote has no signature")
+	}
+
+	if !v.Signature.Verify(v.Validator, v.signingBytes()) {
+		return fmt.Errorf("invalid finality vote signature")
+	}
+
+	return nil
+}
+
+// FinalityStatus is the latest block finalized by the validator set
+type FinalityStatus struct {
+	Finalized bool // False until a first block is finalized
+	Height    uint32
+	BlockHash types.Hash
+}
+
+// finalityRound collects the votes cast for a height, per phase
+type finalityRound struct {
+	votes  map[VotePhase]map[string]types.Hash
+	quorum map[VotePhase]bool
+}
+
+// finalityTracker counts finality votes and remembers the finalized block
+type finalityTracker struct {
+	rounds map[uint32]*finalityRound
+	status FinalityStatus
+}
+
+func newFinalityTracker() *finalityTracker {
+	return &finalityTracker{rounds: make(map[uint32]*finalityRound)}
+}
+
+func (ft *finalityTracker) round(height uint32) *finalityRound {
+	round, ok := ft.rounds[height]
+	if !ok {
+		round = &finalityRound{
+			votes:  make(map[VotePhase]map[string]types.Hash),
+			quorum: make(map[VotePhase]bool),
+		}
+		ft.rounds[height] = round
+	}
+	return round
+}
+
+// finalize records a finalized block and drops the rounds it settles
+func (ft *finalityTracker) finalize(height uint32, blockHash types.Hash) {
+	ft.status = FinalityStatus{Finalized: true, Height: height, BlockHash: blockHash}
+	for h := range ft.rounds {
+		if h <= height {
+			delete(ft.rounds, h)
+		}
+	}
+}
+
+// AddFinalityVote counts a validator's vote for a block of this chain and
+// reports whether it completed a quorum of more than two thirds of the
+// validator set's power in its phase. A precommit quorum finalizes the block
+// and every block below it, after which they can never be replaced.
+//
+// Votes for blocks this node does not have are rejected. A node that misses a
+// quorum catches up when a later block is finalized.
+func (bc *Blockchain) AddFinalityVote(vote *FinalityVote) (bool, error) {
+	if err := vote.Verify(); err != nil {
+		return false, err
+	}
+
+	header, err := bc.GetHeader(vote.Height)
+	if err != nil {
+		return false, err
+	}
+	if (BlockHasher{}).Hash(header) != vote.BlockHash {
+		return false, fmt.Errorf("finality vote for unknown block (%s) at height (%d)", vote.BlockHash, vote.Height)
+	}
+
+	// Transactions are finalized once the state lock is released
+	finalized := false
+	defer func() {
+		if finalized {
+			bc.txTracker.Finalized(vote.Height)
+		}
+	}()
+
+	bc.stateLock.Lock()
+	defer bc.stateLock.Unlock()
+
+	if bc.finality == nil {
+		return false, fmt.Errorf("finality is not available")
+	}
+
+	set := bc.validatorSet()
+	power := make(map[string]uint64, len(set))
+	total := uint64(0)
+	for _, validator := range set {
+		power[validator.Address.String()] = validator.Power
+		total += validator.Power
+	}
+
+	voter := vote.Validator.String()
+	if power[voter] == 0 {
+		return false, ErrNotValidator
+	}
+
+	status := bc.finality.status
+	if status.Finalized && vote.Height <= status.Height {
+		return false, nil
+	}
+
+	round := bc.finality.round(vote.Height)
+	votes, ok := round.votes[vote.Phase]
+	if !ok {
+		votes = make(map[string]types.Hash)
+		round.votes[vote.Phase] = votes
+	}
+
+	if previous, ok := votes[voter]; ok {
+		if previous != vote.BlockHash {
+			return false, ErrEquivocation
+		}
+		return false, nil
+	}
+	votes[voter] = vote.BlockHash
+
+	if round.quorum[vote.Phase] {
+		return false, nil
+	}
+
+	voted := uint64(0)
+	for validator, blockHash := range votes {
+		if blockHash == vote.BlockHash {
+			voted += power[validator]
+		}
+	}
+	if voted*3 <= total*2 {
+		return false, nil
+	}
+
+	round.quorum[vote.Phase] = true
+	if vote.Phase == VotePhasePrecommit {
+		bc.finality.finalize(vote.Height, vote.BlockHash)
+		finalized = true
+		bc.logger.Log("msg", "block finalized", "height", vote.Height, "hash", vote.BlockHash)
+	}
+
+	return true, nil
+}
+
+// ValidatorSet returns the validators finalizing blocks, empty when the DAO
+// state machine does not govern a validator set
+func (bc *Blockchain) ValidatorSet() []dao.ValidatorPower {
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	return bc.validatorSet()
+}
+
+// validatorSet returns the governed validator set. The caller must hold the
+// state lock.
+func (bc *Blockchain) validatorSet() []dao.ValidatorPower {
+	source, ok := bc.daoStateMachine.(dao.ValidatorSetSource)
+	if !ok {
+		return nil
+	}
+	return source.ValidatorSet()
+}
+
+// Finality returns the latest finalized block
+func (bc *Blockchain) Finality() FinalityStatus {
+	bc.stateLock.RLock()
+	defer bc.stateLock.RUnlock()
+
+	if bc.finality == nil {
+		return FinalityStatus{}
+	}
+	return bc.finality.status
+}
+
+// IsFinalized reports whether the block at height is final
+func (bc *Blockchain) IsFinalized(height uint32) bool {
+	status := bc.Finality()
+	return status.Finalized && height <= status.Height
+}
+
+// IsDAOTxFinalized reports whether a DAO transaction was included in a
+// finalized block
+func (bc *Blockchain) IsDAOTxFinalized(txHash types.Hash) bool {
+	height, ok := bc.GetDAOTxHeight(txHash)
+	return ok && bc.IsFinalized(height)
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFinalityChain creates a chain whose DAO has a validator set of the
+// given keys with equal power
+func newTestFinalityChain(t *testing.T, validators ...crypto.PrivateKey) *Blockchain {
+	bc, d := newTestDAOWiredChain(t, validators...)
+
+	for i, validator := range validators {
+		poolID := fmt.Sprintf("pool-%d", i)
+		require.NoError(t, d.TokenomicsManager.CreateStakingPool(poolID, poolID, 0, 1, 0))
+
+		tx := &dao.ValidatorConfigTx{Fee: 10, PoolID: poolID, CommissionBps: 1000}
+		require.NoError(t, d.ApplyDAOTransaction(tx, validator.PublicKey(), randomHash(), 0))
+		require.NoError(t, d.TokenomicsManager.StakeTokens(poolID, validator.PublicKey(), 1000, 0))
+
+		info, _ := d.GetValidator(validator.PublicKey())
+		info.Active = true
+	}
+	require.Len(t, bc.ValidatorSet(), len(validators))
+
+	return bc
+}
+
+func castFinalityVote(t *testing.T, bc *Blockchain, privKey crypto.PrivateKey, height uint32, phase VotePhase) (bool, error) {
+	header, err := bc.GetHeader(height)
+	require.NoError(t, err)
+
+	vote, err := NewFinalityVote(privKey, height, BlockHasher{}.Hash(header), phase)
+	require.NoError(t, err)
+
+	return bc.AddFinalityVote(vote)
+}
+
+func TestFinalityVote_Verify(t *testing.T) {
+	privKey := crypto.GeneratePrivateKey()
+	vote, err := NewFinalityVote(privKey, 1, types.Hash{0x01}, VotePhasePrevote)
+	require.NoError(t, err)
+	assert.NoError(t, vote.Verify())
+
+	// The phase is part of the signed digest
+	vote.Phase = VotePhasePrecommit
+	assert.Error(t, vote.Verify())
+}
+
+func TestFinality_TwoPhaseQuorum(t *testing.T) {
+	a, b, c := crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()
+	bc := newTestFinalityChain(t, a, b, c)
+
+	block := randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))
+	require.NoError(t, bc.AddBlock(block))
+
+	// Two of three equal validators are not more than two thirds
+	reached, err := castFinalityVote(t, bc, a, 1, VotePhasePrevote)
+	require.NoError(t, err)
+	assert.False(t, reached)
+	reached, err = castFinalityVote(t, bc, b, 1, VotePhasePrevote)
+	require.NoError(t, err)
+	assert.False(t, reached)
+
+	reached, err = castFinalityVote(t, bc, c, 1, VotePhasePrevote)
+	require.NoError(t, err)
+	assert.True(t, reached)
+	assert.False(t, bc.IsFinalized(1))
+
+	txHash := types.Hash{0x09}
+	bc.TxTracker().Included(txHash, 1)
+
+	for _, privKey := range []crypto.PrivateKey{a, b, c} {
+		_, err = castFinalityVote(t, bc, privKey, 1, VotePhasePrecommit)
+		require.NoError(t, err)
+	}
+
+	// Transactions of finalized blocks are final
+	txStatus, _ := bc.TxTracker().Status(txHash)
+	assert.Equal(t, TxFinalized, txStatus.State)
+
+	status := bc.Finality()
+	assert.True(t, status.Finalized)
+	assert.Equal(t, uint32(1), status.Height)
+	assert.Equal(t, BlockHasher{}.Hash(block.Header), status.BlockHash)
+	assert.True(t, bc.IsFinalized(0))
+	assert.False(t, bc.IsFinalized(2))
+
+	// A competing block at a finalized height is never accepted
+	fork := randomDAOBlock(t, 1, block.PrevBlockHash)
+	assert.ErrorIs(t, bc.validator.ValidateBlock(fork), ErrFinalityConflict)
+	assert.ErrorIs(t, bc.validator.ValidateBlock(block), ErrBlockKnown)
+}
+
+func TestFinality_RejectsInvalidVotes(t *testing.T) {
+	a, b := crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()
+	bc := newTestFinalityChain(t, a, b)
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+
+	_, err := castFinalityVote(t, bc, crypto.GeneratePrivateKey(), 1, VotePhasePrevote)
+	assert.ErrorIs(t, err, ErrNotValidator)
+
+	// Votes for blocks the chain does not have
+	vote, err := NewFinalityVote(a, 1, types.Hash{0x01}, VotePhasePrevote)
+	require.NoError(t, err)
+	_, err = bc.AddFinalityVote(vote)
+	assert.Error(t, err)
+
+	_, err = castFinalityVote(t, bc, a, 0, VotePhasePrevote)
+	require.NoError(t, err)
+	_, err = castFinalityVote(t, bc, a, 1, VotePhasePrevote)
+	require.NoError(t, err)
+
+	// Voting again for the same block is harmless
+	_, err = castFinalityVote(t, bc, a, 1, VotePhasePrevote)
+	require.NoError(t, err)
+
+	// Voting for a different block at the same height is equivocation
+	bc.finality.round(1).votes[VotePhasePrevote][a.PublicKey().String()] = types.Hash{0x02}
+	_, err = castFinalityVote(t, bc, a, 1, VotePhasePrevote)
+	assert.ErrorIs(t, err, ErrEquivocation)
+}
@@ -21,7 +21,9 @@ func (BlockHasher) Hash(b *Header) types.Hash {
 
 type TxHasher struct{}
 
-// Hash will hash the whole bytes of the TX no exception.
+// Hash will hash the whole bytes of the TX no exception. The inner
+// transaction is hashed in its codec encoding, so the signature covers the
+// DAO payload too.
 func (TxHasher) Hash(tx *Transaction) types.Hash {
 	buf := new(bytes.Buffer)
 
@@ -31,5 +33,10 @@ func (TxHasher) Hash(tx *Transaction) types.Hash {
 	binary.Write(buf, binary.LittleEndian, tx.From)
 	binary.Write(buf, binary.LittleEndian, tx.Nonce)
 
+	// Payloads that cannot be encoded fail Verify, see checkPayload
+	if inner, err := encodeTxInner(tx.TxInner); err == nil {
+		buf.Write(inner)
+	}
+
 	return types.Hash(sha256.Sum256(buf.Bytes()))
 }
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+)
+
+var ErrBlockPruned = errors.New("block has been pruned")
+
+// PruningMode selects how much block history a node keeps
+type PruningMode byte
+
+const (
+	PruningArchive    PruningMode = iota // Keep every block
+	PruningKeepRecent                    // Keep only the most recent blocks
+)
+
+// PruningConfig configures block pruning. Headers are always kept so the
+// chain can still be validated and served to light clients.
+type PruningConfig struct {
+	Mode       PruningMode
+	KeepBlocks uint32 // Number of most recent blocks kept in PruningKeepRecent mode
+}
+
+// SetPruning changes the pruning configuration and prunes right away if the
+// new retention is shorter than the current chain
+func (bc *Blockchain) SetPruning(config PruningConfig) error {
+	switch config.Mode {
+	case PruningArchive:
+	case PruningKeepRecent:
+		if config.KeepBlocks == 0 {
+			return fmt.Errorf("pruning must keep at least one block")
+		}
+	default:
+		return fmt.Errorf("unknown pruning mode (%d)", config.Mode)
+	}
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	bc.pruning = config
+	bc.pruneBlocks()
+
+	return nil
+}
+
+// LowestBlockHeight returns the lowest height whose block is still stored
+func (bc *Blockchain) LowestBlockHeight() uint32 {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	return bc.prunedBelow
+}
+
+// pruneBlocks drops the blocks and transactions that fell out of the
+// retention window; callers must hold bc.lock
+func (bc *Blockchain) pruneBlocks() {
+	if bc.pruning.Mode != PruningKeepRecent || len(bc.headers) == 0 {
+		return
+	}
+
+	height := uint32(len(bc.headers) - 1)
+	if height < bc.pruning.KeepBlocks {
+		return
+	}
+
+	cutoff := height - bc.pruning.KeepBlocks + 1
+	for h := bc.prunedBelow; h < cutoff; h++ {
+		block := bc.blocks[h]
+		if block == nil {
+			continue
+		}
+
+		for _, tx := range block.Transactions {
+			delete(bc.txStore, tx.Hash(TxHasher{}))
+		}
+		delete(bc.blockStore, block.Hash(BlockHasher{}))
+		bc.blocks[h] = nil
+	}
+
+	if cutoff > bc.prunedBelow {
+		bc.prunedBelow = cutoff
+	}
+}
+
+// pruneDAOState lets the registered state machine drop stale state at the
+// block's time; callers must hold bc.stateLock
+func (bc *Blockchain) pruneDAOState(b *Block) {
+	pruner, ok := bc.daoStateMachine.(dao.StatePruner)
+	if !ok {
+		return
+	}
+
+	if pruned := pruner.PruneState(b.Timestamp / int64(time.Second)); pruned > 0 {
+		bc.logger.Log("msg", "pruned DAO state", "height", b.Height, "entries", pruned)
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruning_KeepRecentBlocks(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	bc, _ := newTestDAOWiredChain(t, sender)
+	require.NoError(t, bc.SetPruning(PruningConfig{Mode: PruningKeepRecent, KeepBlocks: 3}))
+
+	tx := &Transaction{
+		TxInner: dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 100},
+	}
+	require.NoError(t, tx.Sign(sender))
+	first := randomDAOBlockWithTxs(t, bc.Height()+1, getDAOPrevBlockHash(t, bc), []*Transaction{tx})
+	require.NoError(t, bc.AddBlock(first))
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+	}
+
+	require.Equal(t, uint32(5), bc.Height())
+	assert.Equal(t, uint32(3), bc.LowestBlockHeight())
+
+	_, err := bc.GetBlock(first.Height)
+	assert.True(t, errors.Is(err, ErrBlockPruned))
+	_, err = bc.GetBlockByHash(first.Hash(BlockHasher{}))
+	assert.Error(t, err)
+	_, err = bc.GetTxByHash(tx.Hash(TxHasher{}))
+	assert.Error(t, err)
+
+	// Headers of pruned blocks are kept
+	header, err := bc.GetHeader(first.Height)
+	require.NoError(t, err)
+	assert.Equal(t, first.Header.DataHash, header.DataHash)
+
+	for height := uint32(3); height <= bc.Height(); height++ {
+		block, err := bc.GetBlock(height)
+		require.NoError(t, err)
+		assert.Equal(t, height, block.Height)
+	}
+}
+
+func TestPruning_ArchiveAndConfig(t *testing.T) {
+	bc, _ := newTestBlockchain(t)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+	}
+
+	// Archive mode is the default
+	assert.Equal(t, uint32(0), bc.LowestBlockHeight())
+	_, err := bc.GetBlock(0)
+	require.NoError(t, err)
+
+	assert.Error(t, bc.SetPruning(PruningConfig{Mode: PruningKeepRecent}))
+	assert.Error(t, bc.SetPruning(PruningConfig{Mode: PruningMode(9), KeepBlocks: 1}))
+
+	// Switching to a shorter retention prunes immediately
+	require.NoError(t, bc.SetPruning(PruningConfig{Mode: PruningKeepRecent, KeepBlocks: 2}))
+	assert.Equal(t, uint32(4), bc.LowestBlockHeight())
+}
+
+func TestPruning_FinalizedVotes(t *testing.T) {
+	voter := crypto.GeneratePrivateKey()
+	bc, d := newTestDAOWiredChain(t, voter)
+
+	now := time.Now().Unix()
+	finalized := types.Hash{0x01}
+	recent := types.Hash{0x02}
+	active := types.Hash{0x03}
+	d.GovernanceState.Proposals[finalized] = &dao.Proposal{ID: finalized, EndTime: now - 7200, Status: dao.ProposalStatusExecuted}
+	d.GovernanceState.Proposals[recent] = &dao.Proposal{ID: recent, EndTime: now - 60, Status: dao.ProposalStatusRejected}
+	d.GovernanceState.Proposals[active] = &dao.Proposal{ID: active, EndTime: now - 7200, Status: dao.ProposalStatusActive}
+	for _, id := range []types.Hash{finalized, recent, active} {
+		d.GovernanceState.Votes[id] = map[string]*dao.Vote{
+			voter.PublicKey().String(): {Voter: voter.PublicKey(), Choice: dao.VoteChoiceYes, Weight: 10},
+		}
+	}
+
+	// Nothing is pruned until governance sets a retention period
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+	assert.Len(t, d.GovernanceState.Votes, 3)
+
+	d.GetParameterConfig().VoteRetentionPeriod = 3600
+	require.NoError(t, bc.AddBlock(randomDAOBlock(t, bc.Height()+1, getDAOPrevBlockHash(t, bc))))
+
+	assert.NotContains(t, d.GovernanceState.Votes, finalized)
+	assert.Contains(t, d.GovernanceState.Votes, recent)
+	assert.Contains(t, d.GovernanceState.Votes, active)
+
+	// The committed state root reflects the pruned state
+	root, err := bc.GetDAOStateRoot(bc.Height())
+	require.NoError(t, err)
+	expected, err := d.StateRoot()
+	require.NoError(t, err)
+	assert.Equal(t, expected, root)
+}
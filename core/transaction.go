@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	"github.com/BOCK-CHAIN/BockChain/codec"
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/dao"
 	"github.com/BOCK-CHAIN/BockChain/types"
@@ -72,13 +73,17 @@ func (tx *Transaction) Hash(hasher Hasher[*Transaction]) types.Hash {
 }
 
 func (tx *Transaction) Sign(privKey crypto.PrivateKey) error {
+	// The sender is part of the hash, so it must be set before hashing for
+	// the signature to verify once the transaction is decoded by a peer.
+	tx.From = privKey.PublicKey()
+	tx.hash = types.Hash{}
+
 	hash := tx.Hash(TxHasher{})
 	sig, err := privKey.Sign(hash.ToSlice())
 	if err != nil {
 		return err
 	}
 
-	tx.From = privKey.PublicKey()
 	tx.Signature = sig
 
 	return nil
@@ -88,6 +93,9 @@ func (tx *Transaction) Verify() error {
 	if tx.Signature == nil {
 		return fmt.Errorf("transaction has no signature")
 	}
+	if err := tx.checkPayload(); err != nil {
+		return err
+	}
 
 	hash := tx.Hash(TxHasher{})
 	if !tx.Signature.Verify(tx.From, hash.ToSlice()) {
@@ -97,6 +105,45 @@ func (tx *Transaction) Verify() error {
 	return nil
 }
 
+// VerifyTransactions verifies the signatures of txs side by side, returning
+// the error of the first invalid transaction
+func VerifyTransactions(txs []*Transaction) error {
+	messages := make([]crypto.SignedMessage, len(txs))
+	for i, tx := range txs {
+		if tx.Signature == nil {
+			return fmt.Errorf("transaction has no signature")
+		}
+		if err := tx.checkPayload(); err != nil {
+			return err
+		}
+		hash := tx.Hash(TxHasher{})
+		messages[i] = crypto.SignedMessage{Key: tx.From, Data: hash.ToSlice(), Signature: tx.Signature}
+	}
+
+	if crypto.VerifyAll(messages) >= 0 {
+		return fmt.Errorf("invalid transaction signature")
+	}
+	return nil
+}
+
+// checkPayload fails for inner transactions TxHasher cannot cover, whose
+// content the signature would not commit to
+func (tx *Transaction) checkPayload() error {
+	if _, err := encodeTxInner(tx.TxInner); err != nil {
+		return fmt.Errorf("transaction payload is not covered by its signature: %w", err)
+	}
+	return nil
+}
+
+// encodeTxInner returns the codec encoding of an inner transaction tagged
+// with its type, nothing when there is none
+func encodeTxInner(txInner any) ([]byte, error) {
+	if txInner == nil {
+		return nil, nil
+	}
+	return codec.Marshal(&txInner)
+}
+
 func (tx *Transaction) Decode(dec Decoder[*Transaction]) error {
 	return dec.Decode(tx)
 }
@@ -106,6 +153,11 @@ func (tx *Transaction) Encode(enc Encoder[*Transaction]) error {
 }
 
 func init() {
+	// Native transaction types take codec tags from 0x100, below are the DAO
+	// types registered by the dao package
+	codec.Register(0x100, CollectionTx{})
+	codec.Register(0x101, MintTx{})
+
 	gob.Register(CollectionTx{})
 	gob.Register(MintTx{})
 	// Register DAO transaction types
@@ -119,4 +171,75 @@ func init() {
 	gob.Register(dao.TokenApproveTx{})
 	gob.Register(dao.TokenTransferFromTx{})
 	gob.Register(dao.ParameterProposalTx{})
+	gob.Register(dao.TokenDistributionTx{})
+	gob.Register(dao.VestingClaimTx{})
+	gob.Register(dao.StakeTx{})
+	gob.Register(dao.UnstakeTx{})
+	gob.Register(dao.ClaimRewardsTx{})
+	gob.Register(dao.PositionTransferTx{})
+	gob.Register(dao.DisputeTx{})
+	gob.Register(dao.DisputeEvidenceTx{})
+	gob.Register(dao.JurorCommitTx{})
+	gob.Register(dao.JurorRevealTx{})
+	gob.Register(dao.FundingKPITx{})
+	gob.Register(dao.ImpactReviewTx{})
+	gob.Register(dao.ValidatorConfigTx{})
+	gob.Register(dao.ClaimCommissionTx{})
+	gob.Register(dao.ValidatorSetProposalTx{})
+	gob.Register(dao.ValidatorSetExecuteTx{})
+	gob.Register(dao.MultisigCreateTx{})
+	gob.Register(dao.MultisigSubmitTx{})
+	gob.Register(dao.MultisigSignTx{})
+	gob.Register(dao.SubDAOCreateProposalTx{})
+	gob.Register(dao.SubDAOCreateExecuteTx{})
+	gob.Register(dao.SubDAOProposalTx{})
+	gob.Register(dao.SubDAOVoteTx{})
+	gob.Register(dao.GrantProposalTx{})
+	gob.Register(dao.GrantExecuteTx{})
+	gob.Register(dao.GrantMilestoneSubmitTx{})
+	gob.Register(dao.GrantMilestoneReviewTx{})
+	gob.Register(dao.GrantCancelTx{})
+	gob.Register(dao.BountyProposalTx{})
+	gob.Register(dao.BountyPostTx{})
+	gob.Register(dao.BountyClaimTx{})
+	gob.Register(dao.BountySubmitTx{})
+	gob.Register(dao.BountyReviewTx{})
+	gob.Register(dao.BountyCancelTx{})
+	gob.Register(dao.QFRoundProposalTx{})
+	gob.Register(dao.QFRoundOpenTx{})
+	gob.Register(dao.QFContributeTx{})
+	gob.Register(dao.QFPayoutTx{})
+	gob.Register(dao.OptimisticProposalTx{})
+	gob.Register(dao.OptimisticChallengeTx{})
+	gob.Register(dao.RPGFRoundProposalTx{})
+	gob.Register(dao.RPGFRoundOpenTx{})
+	gob.Register(dao.RPGFNominateTx{})
+	gob.Register(dao.RPGFBallotTx{})
+	gob.Register(dao.RPGFFinalizeTx{})
+	gob.Register(dao.RPGFClaimTx{})
+	gob.Register(dao.OracleFeedProposalTx{})
+	gob.Register(dao.OracleFeedActivateTx{})
+	gob.Register(dao.OracleReportTx{})
+	gob.Register(dao.TrackProposalTx{})
+	gob.Register(dao.TrackActivateTx{})
+	gob.Register(dao.ParameterScheduleTx{})
+	gob.Register(dao.ConstitutionAmendmentTx{})
+	gob.Register(dao.ConstitutionEnactTx{})
+	gob.Register(dao.EmergencySpendTx{})
+	gob.Register(dao.EmergencyExecuteTx{})
+	gob.Register(dao.ParticipationOptInTx{})
+	gob.Register(dao.EndorseProposalTx{})
+	gob.Register(dao.PrivacySettingsTx{})
+	gob.Register(dao.NameRegisterTx{})
+	gob.Register(dao.NameRenewTx{})
+	gob.Register(dao.NameTransferTx{})
+	gob.Register(dao.ProfileUpdateTx{})
+	gob.Register(dao.DelegateStatementTx{})
+	gob.Register(dao.CommentTx{})
+	gob.Register(dao.JoinRequestTx{})
+	gob.Register(dao.JoinApprovalTx{})
+	gob.Register(dao.MembershipStatusTx{})
+	gob.Register(dao.RageQuitTx{})
+	gob.Register(dao.DAOCreateTx{})
+	gob.Register(dao.HostedDAOTx{})
 }
@@ -3,9 +3,12 @@ package core
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
 	"github.com/BOCK-CHAIN/BockChain/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +32,35 @@ func TestVerifyTransactionWithTamper(t *testing.T) {
 	assert.NotNil(t, tx.Verify())
 }
 
+func TestVerifyTransactionWithTamperedPayload(t *testing.T) {
+	sender := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey()
+	tx := &Transaction{
+		TxInner: &dao.TokenTransferTx{Fee: 10, Recipient: recipient.PublicKey(), Amount: 1000},
+	}
+	assert.Nil(t, tx.Sign(sender))
+	assert.Nil(t, tx.Verify())
+
+	// A relay rewriting the DAO payload invalidates the signature
+	tampered := &Transaction{
+		TxInner:   &dao.TokenTransferTx{Fee: 10, Recipient: crypto.GeneratePrivateKey().PublicKey(), Amount: 1000},
+		From:      tx.From,
+		Signature: tx.Signature,
+		Nonce:     tx.Nonce,
+	}
+	assert.NotNil(t, tampered.Verify())
+
+	// Value and pointer payloads hash alike, as peers decode values
+	decoded := &Transaction{TxInner: *tx.TxInner.(*dao.TokenTransferTx), From: tx.From, Signature: tx.Signature, Nonce: tx.Nonce}
+	assert.Nil(t, decoded.Verify())
+
+	// Payloads the hash cannot cover are rejected
+	uncovered := &Transaction{TxInner: struct{ Amount uint64 }{1000}}
+	assert.Nil(t, uncovered.Sign(sender))
+	assert.NotNil(t, uncovered.Verify())
+	assert.NotNil(t, VerifyTransactions([]*Transaction{tx, uncovered}))
+}
+
 func TestNFTTransaction(t *testing.T) {
 	collectionTx := CollectionTx{
 		Fee:      200,
@@ -86,6 +118,21 @@ func TestVerifyTransaction(t *testing.T) {
 	assert.NotNil(t, tx.Verify())
 }
 
+func TestVerifyTransactions(t *testing.T) {
+	txs := make([]*Transaction, 8)
+	for i := range txs {
+		txs[i] = randomTxWithSignature(t)
+	}
+	assert.Nil(t, VerifyTransactions(txs))
+
+	txs[5].From = crypto.GeneratePrivateKey().PublicKey()
+	assert.NotNil(t, VerifyTransactions(txs))
+
+	txs[5] = &Transaction{Data: []byte("unsigned")}
+	assert.NotNil(t, VerifyTransactions(txs))
+	assert.Nil(t, VerifyTransactions(nil))
+}
+
 func TestTxEncodeDecode(t *testing.T) {
 	tx := randomTxWithSignature(t)
 	buf := &bytes.Buffer{}
@@ -97,6 +144,61 @@ func TestTxEncodeDecode(t *testing.T) {
 	assert.Equal(t, tx, txDecoded)
 }
 
+func TestTxBinaryEncodeDecode(t *testing.T) {
+	tx := randomTxWithSignature(t)
+	tx.TxInner = dao.VoteTx{Fee: 1, Choice: dao.VoteChoiceYes, Weight: 10}
+	buf := &bytes.Buffer{}
+	assert.Nil(t, tx.Encode(NewBinaryTxEncoder(buf)))
+	tx.hash = types.Hash{}
+
+	txDecoded := new(Transaction)
+	assert.Nil(t, txDecoded.Decode(NewBinaryTxDecoder(buf)))
+	assert.Equal(t, tx, txDecoded)
+}
+
+func TestTokenomicsTransactions(t *testing.T) {
+	privKey := crypto.GeneratePrivateKey()
+	for _, txInner := range []any{
+		dao.TokenDistributionTx{Fee: 1, Category: dao.DistributionTeam, Recipients: map[string]uint64{"a": 10}},
+		dao.VestingClaimTx{Fee: 1, VestingID: "vesting-1"},
+		dao.StakeTx{Fee: 1, PoolID: "pool", Amount: 100, Duration: 3600},
+		dao.UnstakeTx{Fee: 1, PoolID: "pool", Amount: 100},
+		dao.ClaimRewardsTx{Fee: 1, PoolID: "pool"},
+	} {
+		tx := &Transaction{TxInner: txInner}
+		assert.Nil(t, tx.Sign(privKey))
+		assert.Nil(t, tx.Verify(), "%T", txInner)
+
+		buf := &bytes.Buffer{}
+		assert.Nil(t, tx.Encode(NewBinaryTxEncoder(buf)))
+		txDecoded := new(Transaction)
+		assert.Nil(t, txDecoded.Decode(NewBinaryTxDecoder(buf)))
+		assert.Equal(t, txInner, txDecoded.TxInner)
+		assert.Nil(t, txDecoded.Verify(), "%T", txInner)
+	}
+}
+
+// The binary encoding of a transaction is pinned: it is hashed into blocks
+// and exchanged between nodes, so it must never change silently
+func TestTxBinaryEncodingGolden(t *testing.T) {
+	tx := &Transaction{
+		TxInner: dao.VoteTx{Fee: 5, ProposalID: types.Hash{0xaa}, Choice: dao.VoteChoiceNo, Weight: 300, Reason: "no"},
+		Data:    []byte{0x01},
+		To:      crypto.PublicKey{0x02, 0x03},
+		Value:   7,
+		From:    crypto.PublicKey{0x04},
+		Signature: &crypto.Signature{
+			R: big.NewInt(0x0506),
+			S: big.NewInt(0x07),
+		},
+		Nonce: -1,
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, tx.Encode(NewBinaryTxEncoder(buf)))
+	assert.Equal(t, "010711050aaa0000000000000000000000000000000000000000000000000000000000000002ac02026e6f010102020307010401020102070104050601", hex.EncodeToString(buf.Bytes()))
+}
+
 func randomTxWithSignature(t *testing.T) *Transaction {
 	privKey := crypto.GeneratePrivateKey()
 	tx := Transaction{
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// TxState is a stage in the lifecycle of a transaction
+type TxState string
+
+const (
+	// TxPending transactions were submitted and wait for a block
+	TxPending TxState = "pending"
+	// TxIncluded transactions were applied in a block
+	TxIncluded TxState = "included"
+	// TxFinalized transactions are in a finalized block
+	TxFinalized TxState = "finalized"
+	// TxRejected transactions failed verification or execution
+	TxRejected TxState = "rejected"
+)
+
+// TxStatus is where a transaction is in its lifecycle
+type TxStatus struct {
+	Hash      types.Hash
+	State     TxState
+	Height    uint32 // Block that included the transaction
+	Reason    string // Why the transaction was rejected
+	UpdatedAt int64
+}
+
+const (
+	// txTrackerCapacity bounds how many transactions are tracked, the
+	// oldest are forgotten first
+	txTrackerCapacity = 100000
+	// txTrackerUnfinalizedHeights bounds how many heights wait for finality,
+	// chains without a validator set never finalize
+	txTrackerUnfinalizedHeights = 10000
+)
+
+// TxTracker follows transactions from submission until they are finalized
+// or rejected, and tells its subscribers about every change
+type TxTracker struct {
+	mu       sync.Mutex
+	statuses map[types.Hash]*TxStatus
+	// order is the hashes in the order they were first tracked
+	order []types.Hash
+	// included holds the hashes of included transactions by height until
+	// their block is finalized
+	included    map[uint32][]types.Hash
+	subscribers []func(TxStatus)
+}
+
+// NewTxTracker creates a tracker without transactions
+func NewTxTracker() *TxTracker {
+	return &TxTracker{
+		statuses: make(map[types.Hash]*TxStatus),
+		included: make(map[uint32][]types.Hash),
+	}
+}
+
+// Subscribe calls fn with every status change. fn must not block.
+func (t *TxTracker) Subscribe(fn func(TxStatus)) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subscribers = append(t.subscribers, fn)
+}
+
+// Status returns the status of a transaction
+func (t *TxTracker) Status(hash types.Hash) (TxStatus, bool) {
+	if t == nil {
+		return TxStatus{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, exists := t.statuses[hash]
+	if !exists {
+		return TxStatus{}, false
+	}
+	return *status, true
+}
+
+// Submitted tracks a transaction as pending unless it is already tracked,
+// a transaction can be rejected before its submitter gets to record it
+func (t *TxTracker) Submitted(hash types.Hash) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	if _, exists := t.statuses[hash]; exists {
+		t.mu.Unlock()
+		return
+	}
+	status := t.set(hash, TxPending, 0, "")
+	t.mu.Unlock()
+
+	t.notify(status)
+}
+
+// Included records that a transaction was applied in the block at height
+func (t *TxTracker) Included(hash types.Hash, height uint32) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	status := t.set(hash, TxIncluded, height, "")
+	t.included[height] = append(t.included[height], hash)
+	if height >= txTrackerUnfinalizedHeights {
+		delete(t.included, height-txTrackerUnfinalizedHeights)
+	}
+	t.mu.Unlock()
+
+	t.notify(status)
+}
+
+// Rejected records that a transaction failed verification or execution
+func (t *TxTracker) Rejected(hash types.Hash, reason string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	status := t.set(hash, TxRejected, 0, reason)
+	t.mu.Unlock()
+
+	t.notify(status)
+}
+
+// Finalized records that the blocks up to height are final
+func (t *TxTracker) Finalized(height uint32) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	var finalized []TxStatus
+	for h, hashes := range t.included {
+		if h > height {
+			continue
+		}
+		for _, hash := range hashes {
+			if status, exists := t.statuses[hash]; exists && status.State == TxIncluded && status.Height == h {
+				finalized = append(finalized, t.set(hash, TxFinalized, h, ""))
+			}
+		}
+		delete(t.included, h)
+	}
+	t.mu.Unlock()
+
+	for _, status := range finalized {
+		t.notify(status)
+	}
+}
+
+// set updates the status of a transaction, forgetting the oldest ones when
+// the tracker is full. The caller must hold the lock.
+func (t *TxTracker) set(hash types.Hash, state TxState, height uint32, reason string) TxStatus {
+	status, exists := t.statuses[hash]
+	if !exists {
+		status = &TxStatus{Hash: hash}
+		t.statuses[hash] = status
+		t.order = append(t.order, hash)
+
+		for len(t.statuses) > txTrackerCapacity {
+			delete(t.statuses, t.order[0])
+			t.order = t.order[1:]
+		}
+	}
+
+	status.State = state
+	status.Height = height
+	status.Reason = reason
+	status.UpdatedAt = time.Now().Unix()
+	return *status
+}
+
+func (t *TxTracker) notify(status TxStatus) {
+	t.mu.Lock()
+	subscribers := append(make([]func(TxStatus), 0, len(t.subscribers)), t.subscribers...)
+	t.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(status)
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxTracker_Lifecycle(t *testing.T) {
+	tracker := NewTxTracker()
+	var changes []TxStatus
+	tracker.Subscribe(func(status TxStatus) { changes = append(changes, status) })
+
+	a, b, c := types.Hash{0x01}, types.Hash{0x02}, types.Hash{0x03}
+	tracker.Submitted(a)
+	tracker.Submitted(b)
+	tracker.Submitted(c)
+
+	// A rejection that comes first is not overwritten by the submitter
+	tracker.Rejected(c, "invalid signature")
+	tracker.Submitted(c)
+	status, ok := tracker.Status(c)
+	require.True(t, ok)
+	assert.Equal(t, TxRejected, status.State)
+	assert.Equal(t, "invalid signature", status.Reason)
+
+	tracker.Included(a, 4)
+	tracker.Included(b, 6)
+	tracker.Finalized(5)
+
+	status, _ = tracker.Status(a)
+	assert.Equal(t, TxFinalized, status.State)
+	assert.Equal(t, uint32(4), status.Height)
+	status, _ = tracker.Status(b)
+	assert.Equal(t, TxIncluded, status.State)
+
+	_, ok = tracker.Status(types.Hash{0x04})
+	assert.False(t, ok)
+
+	states := make([]TxState, len(changes))
+	for i, change := range changes {
+		states[i] = change.State
+	}
+	assert.Equal(t, []TxState{TxPending, TxPending, TxPending, TxRejected, TxIncluded, TxIncluded, TxFinalized}, states)
+
+	// A nil tracker ignores updates
+	var none *TxTracker
+	none.Submitted(a)
+	_, ok = none.Status(a)
+	assert.False(t, ok)
+}
+
+func TestBlockchain_TracksTransactions(t *testing.T) {
+	bc := newBlockchainWithGenesis(t)
+
+	block := randomBlock(t, 1, getPrevBlockHash(t, bc, 1))
+	included := block.Transactions[0].Hash(TxHasher{})
+
+	// A transfer without funds fails when the block is applied
+	from, to := crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()
+	bc.accountState.CreateAccount(from.PublicKey().Address())
+	tx := NewTransaction([]byte{})
+	tx.From = from.PublicKey()
+	tx.To = to.PublicKey()
+	tx.Value = 100
+	require.NoError(t, tx.Sign(from))
+	block.AddTransaction(tx)
+	require.NoError(t, block.Sign(crypto.GeneratePrivateKey()))
+	rejected := tx.Hash(TxHasher{})
+
+	bc.TxTracker().Submitted(rejected)
+	require.NoError(t, bc.AddBlock(block))
+
+	status, ok := bc.TxTracker().Status(included)
+	require.True(t, ok)
+	assert.Equal(t, TxIncluded, status.State)
+	assert.Equal(t, uint32(1), status.Height)
+
+	status, ok = bc.TxTracker().Status(rejected)
+	require.True(t, ok)
+	assert.Equal(t, TxRejected, status.State)
+	assert.NotEmpty(t, status.Reason)
+}
@@ -25,6 +25,15 @@ func NewBlockValidator(bc *Blockchain) *BlockValidator {
 
 func (v *BlockValidator) ValidateBlock(b *Block) error {
 	if v.bc.HasBlock(b.Height) {
+		// A different block at a finalized height belongs to a fork that
+		// must never replace the finalized chain
+		if v.bc.IsFinalized(b.Height) {
+			header, err := v.bc.GetHeader(b.Height)
+			if err == nil && (BlockHasher{}).Hash(header) != b.Hash(BlockHasher{}) {
+				return ErrFinalityConflict
+			}
+		}
+
 		// return fmt.Errorf("chain already contains block (%d) with hash (%s)", b.Height, b.Hash(BlockHasher{}))
 		return ErrBlockKnown
 	}
@@ -47,6 +56,18 @@ func (v *BlockValidator) ValidateBlock(b *Block) error {
 		return err
 	}
 
+	// Headers that commit to a DAO state root must agree with ours
+	if !b.StateRoot.IsZero() {
+		stateRoot, err := v.bc.GetDAOStateRoot(b.Height - 1)
+		if err != nil {
+			return err
+		}
+
+		if stateRoot != b.StateRoot {
+			return fmt.Errorf("block (%s) has DAO state root (%s), expected (%s)", b.Hash(BlockHasher{}), b.StateRoot, stateRoot)
+		}
+	}
+
 	// Validate DAO transactions in the block
 	for _, tx := range b.Transactions {
 		if err := v.validateDAOTransaction(tx); err != nil {
@@ -63,36 +84,46 @@ func (v *BlockValidator) validateDAOTransaction(tx *Transaction) error {
 		return nil // Not a DAO transaction
 	}
 
+	// A registered DAO state machine validates transactions when applying them
+	if v.bc.HasDAOStateMachine() {
+		return nil
+	}
+
+	txInner, ok := daoTxPointer(tx.TxInner)
+	if !ok {
+		return nil // Not a DAO transaction
+	}
+
 	// Get DAO validator from blockchain
 	daoValidator := dao.NewDAOValidator(v.bc.GetDAOState(), v.bc.GetDAOTokenState())
 
-	switch t := tx.TxInner.(type) {
-	case dao.ProposalTx:
-		return daoValidator.ValidateProposalTx(&t, tx.From)
+	switch t := txInner.(type) {
+	case *dao.ProposalTx:
+		return daoValidator.ValidateProposalTx(t, tx.From)
 
-	case dao.VoteTx:
-		return daoValidator.ValidateVoteTx(&t, tx.From)
+	case *dao.VoteTx:
+		return daoValidator.ValidateVoteTx(t, tx.From)
 
-	case dao.DelegationTx:
-		return daoValidator.ValidateDelegationTx(&t, tx.From)
+	case *dao.DelegationTx:
+		return daoValidator.ValidateDelegationTx(t, tx.From)
 
-	case dao.TreasuryTx:
-		return daoValidator.ValidateTreasuryTx(&t)
+	case *dao.TreasuryTx:
+		return daoValidator.ValidateTreasuryTx(t)
 
-	case dao.TokenMintTx:
-		return daoValidator.ValidateTokenMintTx(&t, tx.From)
+	case *dao.TokenMintTx:
+		return daoValidator.ValidateTokenMintTx(t, tx.From)
 
-	case dao.TokenBurnTx:
-		return daoValidator.ValidateTokenBurnTx(&t, tx.From)
+	case *dao.TokenBurnTx:
+		return daoValidator.ValidateTokenBurnTx(t, tx.From)
 
-	case dao.TokenTransferTx:
-		return daoValidator.ValidateTokenTransferTx(&t, tx.From)
+	case *dao.TokenTransferTx:
+		return daoValidator.ValidateTokenTransferTx(t, tx.From)
 
-	case dao.TokenApproveTx:
-		return daoValidator.ValidateTokenApproveTx(&t, tx.From)
+	case *dao.TokenApproveTx:
+		return daoValidator.ValidateTokenApproveTx(t, tx.From)
 
-	case dao.TokenTransferFromTx:
-		return daoValidator.ValidateTokenTransferFromTx(&t, tx.From)
+	case *dao.TokenTransferFromTx:
+		return daoValidator.ValidateTokenTransferFromTx(t, tx.From)
 
 	default:
 		// Not a DAO transaction, no validation needed
@@ -259,7 +259,7 @@ func (vm *VM) execCreateProposal() error {
 	vm.governanceState.Proposals[proposalID] = proposal
 
 	// Initialize vote map for this proposal
-	vm.governanceState.Votes[proposalID] = make(map[string]*dao.Vote)
+	vm.governanceState.ResetVotes(proposalID)
 
 	// Push proposal ID to stack as result
 	vm.stack.Push(proposalID[:])
@@ -294,7 +294,7 @@ func (vm *VM) execCastVote() error {
 
 	// Check for duplicate vote
 	voterKey := string(vm.caller[:])
-	if _, hasVoted := vm.governanceState.Votes[proposalID][voterKey]; hasVoted {
+	if vm.governanceState.HasVoted(proposalID, voterKey) {
 		return dao.ErrDuplicateVoteError
 	}
 
@@ -314,6 +314,7 @@ func (vm *VM) execCastVote() error {
 
 	// Store vote
 	vm.governanceState.Votes[proposalID][voterKey] = vote
+	vm.governanceState.RecordVoter(proposalID, voterKey)
 
 	// Update proposal results
 	switch choice {
@@ -480,7 +481,7 @@ func (vm *VM) execQuadraticVote() error {
 
 	// Check for duplicate vote
 	voterKey := string(vm.caller[:])
-	if _, hasVoted := vm.governanceState.Votes[proposalID][voterKey]; hasVoted {
+	if vm.governanceState.HasVoted(proposalID, voterKey) {
 		return dao.ErrDuplicateVoteError
 	}
 
@@ -495,6 +496,7 @@ func (vm *VM) execQuadraticVote() error {
 
 	// Store vote
 	vm.governanceState.Votes[proposalID][voterKey] = vote
+	vm.governanceState.RecordVoter(proposalID, voterKey)
 
 	// Update proposal results with effective voting power
 	switch choice {
@@ -647,7 +649,7 @@ func (vm *VM) execGetProposal() error {
 	copy(proposalID[:], proposalIDBytes)
 
 	// Check if proposal exists
-	proposal, exists := vm.governanceState.Proposals[proposalID]
+	proposal, exists := vm.governanceState.Proposal(proposalID)
 	if !exists {
 		vm.stack.Push(nil)
 		return nil
@@ -677,7 +679,7 @@ func (vm *VM) execGetVote() error {
 	voterKey := string(voterBytes)
 
 	// Check if vote exists
-	if votes, exists := vm.governanceState.Votes[proposalID]; exists {
+	if votes, exists := vm.governanceState.ProposalVotes(proposalID); exists {
 		if vote, hasVoted := votes[voterKey]; hasVoted {
 			// Serialize vote data
 			voteData, err := json.Marshal(vote)
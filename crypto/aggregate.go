@@ -0,0 +1,102 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AggregateScheme names a scheme combining the signatures of several keys
+// over one message into one signature
+type AggregateScheme string
+
+// AggregateSignature is one signature standing for every key of Signers
+type AggregateSignature struct {
+	Scheme  AggregateScheme
+	Signers []PublicKey // Sorted by key bytes
+	Data    []byte
+}
+
+// AggregateSigner is an aggregate signature scheme
+type AggregateSigner interface {
+	Scheme() AggregateScheme
+	// NewSession starts the signing of msg by signers
+	NewSession(signers []PublicKey, msg []byte) (AggregateSession, error)
+	// Verify checks that sig is a signature of msg by all of its signers
+	Verify(sig *AggregateSignature, msg []byte) error
+}
+
+// AggregateSession collects the contributions of the signers of a message.
+// Every signer commits first and signs once all of them committed; schemes
+// without a commitment round accept empty commitments. Signers holding
+// their key in process use Commit and Sign, the contributions of remote
+// signers are added as received.
+type AggregateSession interface {
+	// Commit starts the signing by key and returns its public commitment
+	Commit(key PrivateKey) ([]byte, error)
+	// AddCommitment records the commitment of a remote signer
+	AddCommitment(signer PublicKey, commitment []byte) error
+	// Sign returns the partial signature of key, after every commitment
+	Sign(key PrivateKey) ([]byte, error)
+	// AddPartial records and checks the partial signature of a remote signer
+	AddPartial(signer PublicKey, partial []byte) error
+	// Signature combines the partial signatures of every signer
+	Signature() (*AggregateSignature, error)
+}
+
+var (
+	aggregateMu      sync.RWMutex
+	aggregateSigners = make(map[AggregateScheme]AggregateSigner)
+)
+
+// RegisterAggregateSigner makes a scheme available to VerifyAggregate
+func RegisterAggregateSigner(signer AggregateSigner) {
+	aggregateMu.Lock()
+	defer aggregateMu.Unlock()
+	aggregateSigners[signer.Scheme()] = signer
+}
+
+// AggregateSignerFor returns the registered signer of a scheme
+func AggregateSignerFor(scheme AggregateScheme) (AggregateSigner, error) {
+	aggregateMu.RLock()
+	defer aggregateMu.RUnlock()
+	signer, exists := aggregateSigners[scheme]
+	if !exists {
+		return nil, fmt.Errorf("unknown aggregate signature scheme %s", scheme)
+	}
+	return signer, nil
+}
+
+// VerifyAggregate checks an aggregate signature of msg with the signer of
+// its scheme
+func VerifyAggregate(sig *AggregateSignature, msg []byte) error {
+	if sig == nil {
+		return errors.New("missing aggregate signature")
+	}
+	signer, err := AggregateSignerFor(sig.Scheme)
+	if err != nil {
+		return err
+	}
+	return signer.Verify(sig, msg)
+}
+
+// sortedSigners returns a sorted copy of signers, rejecting duplicates and
+// empty sets
+func sortedSigners(signers []PublicKey) ([]PublicKey, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("aggregate signature needs at least one signer")
+	}
+	sorted := make([]PublicKey, len(signers))
+	copy(sorted, signers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	for i := 1; i < len(sorted); i++ {
+		if bytes.Equal(sorted[i-1], sorted[i]) {
+			return nil, fmt.Errorf("duplicate signer %s", sorted[i])
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuSig2(t *testing.T) {
+	for _, curve := range []Curve{CurveP256, CurveSecp256k1} {
+		t.Run(string(curve), func(t *testing.T) {
+			keys := []PrivateKey{GenerateCurvePrivateKey(curve), GenerateCurvePrivateKey(curve), GenerateCurvePrivateKey(curve)}
+			signers := []PublicKey{keys[0].PublicKey(), keys[1].PublicKey(), keys[2].PublicKey()}
+			msg := []byte("pay the auditor")
+
+			signer, err := AggregateSignerFor(AggregateSchemeMuSig2)
+			require.NoError(t, err)
+			session, err := signer.NewSession(signers, msg)
+			require.NoError(t, err)
+
+			// The last key signs remotely with its own session
+			remote, err := signer.NewSession(signers, msg)
+			require.NoError(t, err)
+
+			var commitments [][]byte
+			for _, key := range keys[:2] {
+				commitment, err := session.Commit(key)
+				require.NoError(t, err)
+				commitments = append(commitments, commitment)
+			}
+			remoteCommitment, err := remote.Commit(keys[2])
+			require.NoError(t, err)
+
+			_, err = session.Sign(keys[0])
+			assert.Error(t, err, "signing waits for every commitment")
+
+			require.NoError(t, session.AddCommitment(signers[2], remoteCommitment))
+			for i, commitment := range commitments {
+				require.NoError(t, remote.AddCommitment(signers[i], commitment))
+			}
+
+			for _, key := range keys[:2] {
+				_, err := session.Sign(key)
+				require.NoError(t, err)
+			}
+			_, err = session.Sign(keys[0])
+			assert.Error(t, err, "nonces are used once")
+
+			partial, err := remote.Sign(keys[2])
+			require.NoError(t, err)
+			tampered := append([]byte(nil), partial...)
+			tampered[31] ^= 0x01
+			assert.Error(t, session.AddPartial(signers[2], tampered))
+			require.NoError(t, session.AddPartial(signers[2], partial))
+
+			sig, err := session.Signature()
+			require.NoError(t, err)
+			assert.Len(t, sig.Data, 65)
+			assert.NoError(t, VerifyAggregate(sig, msg))
+			assert.Error(t, VerifyAggregate(sig, []byte("pay someone else")))
+
+			// The signature stands for exactly its signers
+			sig.Signers = sig.Signers[:2]
+			assert.Error(t, VerifyAggregate(sig, msg))
+		})
+	}
+}
+
+func TestMuSig2RejectsInvalidSigners(t *testing.T) {
+	key := GeneratePrivateKey().PublicKey()
+
+	_, err := MuSig2{}.NewSession(nil, []byte("msg"))
+	assert.Error(t, err)
+	_, err = MuSig2{}.NewSession([]PublicKey{key, key}, []byte("msg"))
+	assert.Error(t, err)
+	_, err = MuSig2{}.NewSession([]PublicKey{key, GenerateCurvePrivateKey(CurveSecp256k1).PublicKey()}, []byte("msg"))
+	assert.Error(t, err)
+
+	_, err = AggregateSignerFor("bls")
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package crypto
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// batchWorkers is the number of goroutines batches are verified on
+var batchWorkers = runtime.NumCPU()
+
+// SignedMessage is a message with the signature to check against a key
+type SignedMessage struct {
+	Key       PublicKey
+	Data      []byte
+	Signature *Signature
+}
+
+// verify reports whether the message carries a valid signature of Key.
+// Messages come from decoded blocks, so the signature or its values may be
+// missing.
+func (m SignedMessage) verify() bool {
+	if m.Signature == nil || m.Signature.R == nil || m.Signature.S == nil {
+		return false
+	}
+	return m.Signature.Verify(m.Key, m.Data)
+}
+
+// VerifyBatch checks the signatures of messages and returns whether each is
+// valid. ECDSA has no batch equation, since a signature does not carry its
+// nonce point, so the checks are spread over one goroutine per CPU instead.
+func VerifyBatch(messages []SignedMessage) []bool {
+	valid := make([]bool, len(messages))
+	parallelize(len(messages), func(i int) bool {
+		valid[i] = messages[i].verify()
+		return true
+	})
+	return valid
+}
+
+// VerifyAll checks the signatures of messages like VerifyBatch and returns
+// the index of the first invalid one, or -1 when all are valid. The checks
+// still running stop once one fails.
+func VerifyAll(messages []SignedMessage) int {
+	first := int64(len(messages))
+	parallelize(len(messages), func(i int) bool {
+		if messages[i].verify() {
+			return true
+		}
+		for {
+			current := atomic.LoadInt64(&first)
+			if int64(i) >= current || atomic.CompareAndSwapInt64(&first, current, int64(i)) {
+				return false
+			}
+		}
+	})
+
+	if first == int64(len(messages)) {
+		return -1
+	}
+	return int(first)
+}
+
+// MatchSigners finds which of keys signed data for each signature, checking
+// the signatures side by side. Each signature is tried against the keys in
+// order until one matches, and the index of that key is returned for it. A
+// signature no key signed stops the checks still to start and gets -1, as
+// do the signatures left unchecked; ok then reports false.
+func MatchSigners(data []byte, signatures []Signature, keys []PublicKey) (matches []int, ok bool) {
+	matches = make([]int, len(signatures))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	var failed int32
+	parallelize(len(signatures), func(i int) bool {
+		for k, key := range keys {
+			if (SignedMessage{Key: key, Data: data, Signature: &signatures[i]}).verify() {
+				matches[i] = k
+				return true
+			}
+		}
+		atomic.StoreInt32(&failed, 1)
+		return false
+	})
+	return matches, failed == 0
+}
+
+// parallelize calls fn for 0 to n-1 from batchWorkers goroutines, handing out
+// indexes in order. Once fn returns false no new indexes are handed out.
+func parallelize(n int, fn func(i int) bool) {
+	workers := batchWorkers
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if !fn(i) {
+				return
+			}
+		}
+		return
+	}
+
+	var (
+		next    int64 = -1
+		stopped int32
+		wg      sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&stopped) == 0 {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				if !fn(i) {
+					atomic.StoreInt32(&stopped, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,100 @@
+package crypto
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedMessages(t testing.TB, n int) []SignedMessage {
+	messages := make([]SignedMessage, n)
+	for i := range messages {
+		key := GeneratePrivateKey()
+		if i%2 == 1 {
+			key = GenerateCurvePrivateKey(CurveSecp256k1)
+		}
+		data := []byte(fmt.Sprintf("message %d", i))
+		sig, err := key.Sign(data)
+		require.NoError(t, err)
+		messages[i] = SignedMessage{Key: key.PublicKey(), Data: data, Signature: sig}
+	}
+	return messages
+}
+
+func TestVerifyBatch(t *testing.T) {
+	defer func(workers int) { batchWorkers = workers }(batchWorkers)
+	batchWorkers = 4
+
+	messages := signedMessages(t, 20)
+	assert.Equal(t, -1, VerifyAll(messages))
+	for i, valid := range VerifyBatch(messages) {
+		assert.True(t, valid, "message %d", i)
+	}
+
+	// Swapped data, a missing signature or value and a key of the other
+	// curve fail
+	messages[3].Data = messages[4].Data
+	messages[9].Signature = nil
+	messages[11].Signature = &Signature{R: messages[11].Signature.R}
+	messages[15].Key = messages[16].Key
+	valid := VerifyBatch(messages)
+	for i := range messages {
+		assert.Equal(t, i != 3 && i != 9 && i != 11 && i != 15, valid[i], "message %d", i)
+	}
+	assert.Equal(t, 3, VerifyAll(messages))
+
+	assert.Empty(t, VerifyBatch(nil))
+	assert.Equal(t, -1, VerifyAll(nil))
+
+	// A single worker checks in order
+	batchWorkers = 1
+	assert.Equal(t, 3, VerifyAll(messages))
+}
+
+func TestMatchSigners(t *testing.T) {
+	defer func(workers int) { batchWorkers = workers }(batchWorkers)
+	batchWorkers = 4
+
+	data := []byte("digest")
+	keys := []PrivateKey{GeneratePrivateKey(), GeneratePrivateKey(), GeneratePrivateKey()}
+	publicKeys := []PublicKey{keys[0].PublicKey(), keys[1].PublicKey(), keys[2].PublicKey()}
+	sign := func(key PrivateKey) Signature {
+		sig, err := key.Sign(data)
+		require.NoError(t, err)
+		return *sig
+	}
+
+	matches, ok := MatchSigners(data, []Signature{sign(keys[2]), sign(keys[0]), sign(keys[2])}, publicKeys)
+	assert.True(t, ok)
+	assert.Equal(t, []int{2, 0, 2}, matches)
+
+	matches, ok = MatchSigners(data, []Signature{sign(keys[1]), sign(GeneratePrivateKey()), {}}, publicKeys)
+	assert.False(t, ok)
+	assert.Equal(t, -1, matches[1])
+
+	matches, ok = MatchSigners(data, nil, publicKeys)
+	assert.True(t, ok)
+	assert.Empty(t, matches)
+}
+
+func BenchmarkVerifySerial(b *testing.B) {
+	messages := signedMessages(b, 256)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, m := range messages {
+			m.Signature.Verify(m.Key, m.Data)
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	messages := signedMessages(b, 256)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyBatch(messages)
+	}
+}
@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Curve is the elliptic curve of a key pair
+type Curve string
+
+const (
+	// CurveP256 is NIST P-256, the curve of keys generated by the node
+	CurveP256 Curve = "p256"
+	// CurveSecp256k1 is the curve of Bitcoin and Ethereum wallets
+	CurveSecp256k1 Curve = "secp256k1"
+)
+
+// secp256k1Tag prefixes the compressed point of secp256k1 public keys. A
+// compressed point does not tell its curve, and P-256 keys predate the tag.
+const secp256k1Tag byte = 0x01
+
+// ParseCurve returns the curve of a name, P-256 when name is empty
+func ParseCurve(name string) (Curve, error) {
+	switch Curve(strings.ToLower(strings.TrimSpace(name))) {
+	case "", CurveP256, "p-256", "secp256r1":
+		return CurveP256, nil
+	case CurveSecp256k1:
+		return CurveSecp256k1, nil
+	default:
+		return "", fmt.Errorf("unknown curve %s, expected p256 or secp256k1", name)
+	}
+}
+
+func (c Curve) elliptic() elliptic.Curve {
+	if c == CurveSecp256k1 {
+		return secp256k1.S256()
+	}
+	return elliptic.P256()
+}
+
+// splitKeyHex splits the optional curve prefix, as in secp256k1:<hex>, and
+// 0x prefix off a hex encoded key
+func splitKeyHex(s string) (Curve, string, bool, error) {
+	s = strings.TrimSpace(s)
+	prefixed := false
+	curve := CurveP256
+	if name, rest, found := strings.Cut(s, ":"); found {
+		parsed, err := ParseCurve(name)
+		if err != nil {
+			return "", "", false, err
+		}
+		curve, s, prefixed = parsed, rest, true
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	return curve, s, prefixed, nil
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// eciesInfo binds derived keys to this scheme
+var eciesInfo = []byte("bockchain-ecies-v1")
+
+// ErrDecryption is returned when sealed data cannot be opened with a key
+var ErrDecryption = errors.New("failed to decrypt sealed data")
+
+// SealFor encrypts data so only the holder of the private key of pubKey can
+// read it. The scheme is ECIES over P-256, as available in WebCrypto: an
+// ephemeral ECDH key agreement, HKDF-SHA256 of the shared X coordinate
+// salted with the ephemeral key, and AES-256-GCM. The output is the 65 byte
+// uncompressed ephemeral public key, the 12 byte nonce and the ciphertext.
+func SealFor(pubKey PublicKey, data []byte) ([]byte, error) {
+	if pubKey.Curve() != CurveP256 {
+		return nil, errors.New("data can only be sealed for P-256 keys")
+	}
+	curve := elliptic.P256()
+	x, y := elliptic.UnmarshalCompressed(curve, pubKey)
+	if x == nil {
+		return nil, errors.New("invalid public key")
+	}
+
+	ephemeral := GeneratePrivateKey()
+	ephemeralPub := elliptic.Marshal(curve, ephemeral.key.PublicKey.X, ephemeral.key.PublicKey.Y)
+	sharedX, _ := curve.ScalarMult(x, y, ephemeral.Bytes())
+
+	gcm, err := eciesCipher(sharedX, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(ephemeralPub, nonce...)
+	return gcm.Seal(sealed, nonce, data, nil), nil
+}
+
+// Open decrypts data sealed for the public key of k with SealFor
+func (k PrivateKey) Open(sealed []byte) ([]byte, error) {
+	curve := elliptic.P256()
+	const pubLen, nonceLen = 65, 12
+	if k.Curve() != CurveP256 || len(sealed) < pubLen+nonceLen {
+		return nil, ErrDecryption
+	}
+
+	ephemeralPub := sealed[:pubLen]
+	x, y := elliptic.Unmarshal(curve, ephemeralPub)
+	if x == nil {
+		return nil, ErrDecryption
+	}
+	sharedX, _ := curve.ScalarMult(x, y, k.Bytes())
+
+	gcm, err := eciesCipher(sharedX, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := sealed[pubLen : pubLen+nonceLen]
+	data, err := gcm.Open(nil, nonce, sealed[pubLen+nonceLen:], nil)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return data, nil
+}
+
+// eciesCipher derives the AES-256-GCM cipher of an ECDH shared secret
+func eciesCipher(sharedX *big.Int, ephemeralPub []byte) (cipher.AEAD, error) {
+	secret := make([]byte, 32)
+	sharedX.FillBytes(secret)
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, ephemeralPub, eciesInfo), key); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealForOpen(t *testing.T) {
+	recipient := GeneratePrivateKey()
+	data := []byte("treasury signer briefing")
+
+	sealed, err := SealFor(recipient.PublicKey(), data)
+	require.NoError(t, err)
+	assert.Len(t, sealed, 65+12+len(data)+16)
+
+	opened, err := recipient.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	// Sealing is randomized
+	again, err := SealFor(recipient.PublicKey(), data)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+}
+
+func TestOpenRejectsOtherKeysAndTampering(t *testing.T) {
+	recipient := GeneratePrivateKey()
+	sealed, err := SealFor(recipient.PublicKey(), []byte("secret"))
+	require.NoError(t, err)
+
+	_, err = GeneratePrivateKey().Open(sealed)
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	sealed[len(sealed)-1] ^= 0xFF
+	_, err = recipient.Open(sealed)
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	_, err = recipient.Open(sealed[:40])
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	_, err = SealFor(PublicKey{0x02, 0x01}, []byte("secret"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,485 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Threshold signatures follow FROST: a key generated jointly by n
+// participants, each holding a share, signs with the shares of any
+// threshold of them. The whole key never exists on any machine. Signatures
+// are 65 byte Schnorr signatures of the group key, the compressed nonce
+// point and the scalar.
+
+// DKGDealing is what a participant publishes in a distributed key
+// generation: commitments to the coefficients of its secret polynomial, and
+// the share of every other participant sealed to their key. The proof shows
+// the dealer knows the constant coefficient it committed to, so no dealer
+// can pick its commitment to cancel the keys of the others.
+type DKGDealing struct {
+	From        int            // Index of the dealer, from 1
+	Commitments [][]byte       // Compressed coefficient points, threshold of them
+	Shares      map[int][]byte // Sealed share by participant index
+	Proof       []byte         // Schnorr signature of the index by the constant coefficient
+}
+
+// VerifyProof checks the proof of knowledge of the constant coefficient of
+// a dealing
+func (d *DKGDealing) VerifyProof(curve Curve) error {
+	if len(d.Commitments) == 0 {
+		return fmt.Errorf("participant %d committed to no coefficients", d.From)
+	}
+	g := newGroup(curve)
+	constant, err := g.parsePoint(d.Commitments[0])
+	if err != nil {
+		return fmt.Errorf("invalid commitment of participant %d", d.From)
+	}
+	if err := verifySchnorr(g, constant, indexBytes(d.From), d.Proof, "frost/dkg-proof"); err != nil {
+		return fmt.Errorf("invalid proof of knowledge of participant %d: %w", d.From, err)
+	}
+	return nil
+}
+
+// DKGParticipant is the secret polynomial of one participant of a
+// distributed key generation
+type DKGParticipant struct {
+	group
+	curve     Curve
+	index     int
+	threshold int
+	coefs     []*big.Int
+}
+
+// NewDKGParticipant starts the key generation of participant index, from
+// 1, for a key any threshold of the participants sign with
+func NewDKGParticipant(curve Curve, index, threshold int) (*DKGParticipant, error) {
+	if index < 1 {
+		return nil, errors.New("participant index must be at least 1")
+	}
+	if threshold < 1 {
+		return nil, errors.New("threshold must be at least 1")
+	}
+
+	p := &DKGParticipant{group: newGroup(curve), curve: curve, index: index, threshold: threshold}
+	p.coefs = make([]*big.Int, threshold)
+	for i := range p.coefs {
+		coef, err := p.randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		p.coefs[i] = coef
+	}
+	return p, nil
+}
+
+// Deal returns the dealing of the participant, sealing the share of each
+// other participant to its key. Participants are keyed by index and need
+// P-256 keys, the curve shares are sealed with.
+func (p *DKGParticipant) Deal(participants map[int]PublicKey) (*DKGDealing, error) {
+	if _, exists := participants[p.index]; !exists {
+		return nil, fmt.Errorf("participant %d is not part of the key generation", p.index)
+	}
+	if p.threshold > len(participants) {
+		return nil, errors.New("threshold exceeds the number of participants")
+	}
+
+	dealing := &DKGDealing{
+		From:        p.index,
+		Commitments: make([][]byte, len(p.coefs)),
+		Shares:      make(map[int][]byte, len(participants)-1),
+	}
+	for i, coef := range p.coefs {
+		dealing.Commitments[i] = p.compress(p.baseMul(coef))
+	}
+	proof, err := p.proveConstant()
+	if err != nil {
+		return nil, err
+	}
+	dealing.Proof = proof
+	for index, key := range participants {
+		if index == p.index {
+			continue
+		}
+		sealed, err := SealFor(key, scalarBytes(p.evaluate(index)))
+		if err != nil {
+			return nil, fmt.Errorf("sealing the share of participant %d: %w", index, err)
+		}
+		dealing.Shares[index] = sealed
+	}
+	return dealing, nil
+}
+
+// proveConstant signs the index of the participant with its constant
+// coefficient, proving it knows the secret behind its commitment
+func (p *DKGParticipant) proveConstant() ([]byte, error) {
+	k, err := p.randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	r := p.baseMul(k)
+	c := p.hashScalar("frost/dkg-proof", p.compress(r), p.compress(p.baseMul(p.coefs[0])), indexBytes(p.index))
+
+	// s = k + c a_0
+	s := new(big.Int).Mul(c, p.coefs[0])
+	s.Add(s, k)
+	return append(p.compress(r), scalarBytes(s.Mod(s, p.n()))...), nil
+}
+
+// evaluate returns the share of participant x
+func (p *DKGParticipant) evaluate(x int) *big.Int {
+	result := new(big.Int)
+	for i := len(p.coefs) - 1; i >= 0; i-- {
+		result.Mul(result, big.NewInt(int64(x)))
+		result.Add(result, p.coefs[i])
+		result.Mod(result, p.n())
+	}
+	return result
+}
+
+// Finish opens the shares dealt to the participant with its key, checks
+// them against the commitments of their dealers and returns its share of
+// the group key. dealings holds the dealing of every participant.
+func (p *DKGParticipant) Finish(key PrivateKey, dealings []*DKGDealing) (*ThresholdKey, error) {
+	groupKey, _, err := DKGPublicKeys(p.curve, p.threshold, dealings)
+	if err != nil {
+		return nil, err
+	}
+
+	share := new(big.Int)
+	for _, dealing := range dealings {
+		if dealing.From == p.index {
+			share.Add(share, p.evaluate(p.index))
+			continue
+		}
+
+		sealed, exists := dealing.Shares[p.index]
+		if !exists {
+			return nil, fmt.Errorf("participant %d dealt no share to participant %d", dealing.From, p.index)
+		}
+		opened, err := key.Open(sealed)
+		if err != nil || len(opened) != 32 {
+			return nil, fmt.Errorf("cannot open the share of participant %d", dealing.From)
+		}
+		value := new(big.Int).SetBytes(opened)
+
+		commitments, err := p.parseCommitments(dealing)
+		if err != nil {
+			return nil, err
+		}
+		if !p.baseMul(value).equal(p.commitmentAt(commitments, p.index)) {
+			return nil, fmt.Errorf("share of participant %d does not match its commitments", dealing.From)
+		}
+		share.Add(share, value)
+	}
+	share.Mod(share, p.n())
+
+	return &ThresholdKey{
+		Curve:     p.curve,
+		Index:     p.index,
+		Threshold: p.threshold,
+		Share:     share,
+		GroupKey:  groupKey,
+	}, nil
+}
+
+func (g group) parseCommitments(dealing *DKGDealing) ([]*point, error) {
+	commitments := make([]*point, len(dealing.Commitments))
+	for i, commitment := range dealing.Commitments {
+		var err error
+		if commitments[i], err = g.parsePoint(commitment); err != nil {
+			return nil, fmt.Errorf("invalid commitment of participant %d", dealing.From)
+		}
+	}
+	return commitments, nil
+}
+
+// commitmentAt evaluates committed coefficients at x, the public point of
+// the share of participant x
+func (g group) commitmentAt(commitments []*point, x int) *point {
+	var result *point
+	for i := len(commitments) - 1; i >= 0; i-- {
+		result = g.add(g.mul(result, big.NewInt(int64(x))), commitments[i])
+	}
+	return result
+}
+
+// DKGPublicKeys returns the group key of a key generation and the public
+// key of the share of each participant, from the dealings of every
+// participant. Anyone can compute them, they check partial signatures.
+func DKGPublicKeys(curve Curve, threshold int, dealings []*DKGDealing) (PublicKey, map[int]PublicKey, error) {
+	g := newGroup(curve)
+	if threshold < 1 || threshold > len(dealings) {
+		return nil, nil, errors.New("threshold must be between 1 and the number of participants")
+	}
+
+	seen := make(map[int]bool, len(dealings))
+	all := make([][]*point, len(dealings))
+	var groupPoint *point
+	for i, dealing := range dealings {
+		if dealing.From < 1 || seen[dealing.From] {
+			return nil, nil, fmt.Errorf("invalid or duplicate dealer %d", dealing.From)
+		}
+		seen[dealing.From] = true
+		if len(dealing.Commitments) != threshold {
+			return nil, nil, fmt.Errorf("participant %d committed to %d coefficients, expected %d", dealing.From, len(dealing.Commitments), threshold)
+		}
+		commitments, err := g.parseCommitments(dealing)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := dealing.VerifyProof(curve); err != nil {
+			return nil, nil, err
+		}
+		all[i] = commitments
+		groupPoint = g.add(groupPoint, commitments[0])
+	}
+	if groupPoint == nil {
+		return nil, nil, errors.New("group key is the point at infinity")
+	}
+
+	shares := make(map[int]PublicKey, len(dealings))
+	for _, dealing := range dealings {
+		var share *point
+		for _, commitments := range all {
+			share = g.add(share, g.commitmentAt(commitments, dealing.From))
+		}
+		if share == nil {
+			return nil, nil, fmt.Errorf("share of participant %d is the point at infinity", dealing.From)
+		}
+		shares[dealing.From] = publicKeyOf(curve, share.x, share.y)
+	}
+	return publicKeyOf(curve, groupPoint.x, groupPoint.y), shares, nil
+}
+
+// ThresholdKey is the share of a participant in a threshold key
+type ThresholdKey struct {
+	Curve     Curve
+	Index     int
+	Threshold int
+	Share     *big.Int
+	GroupKey  PublicKey
+}
+
+// ThresholdNonce is the secret nonce pair of one signing by a share. It
+// signs once.
+type ThresholdNonce struct {
+	d, e *big.Int
+	used bool
+}
+
+// Commit draws the nonces of a signing and returns the commitment to
+// publish, two 33 byte points
+func (k *ThresholdKey) Commit() (*ThresholdNonce, []byte, error) {
+	g := newGroup(k.Curve)
+	d, err := g.randomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	e, err := g.randomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ThresholdNonce{d: d, e: e}, append(g.compress(g.baseMul(d)), g.compress(g.baseMul(e))...), nil
+}
+
+// Sign returns the partial signature of msg by the share, with the nonce
+// it committed and the commitments of every signer by index
+func (k *ThresholdKey) Sign(nonce *ThresholdNonce, msg []byte, commitments map[int][]byte) ([]byte, error) {
+	if nonce.used {
+		return nil, errors.New("nonce already signed")
+	}
+	signing, err := newFrostSigning(k.Curve, k.GroupKey, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	own, exists := signing.nonces[k.Index]
+	if !exists {
+		return nil, fmt.Errorf("participant %d did not commit to the signing", k.Index)
+	}
+	if !signing.baseMul(nonce.d).equal(own[0]) || !signing.baseMul(nonce.e).equal(own[1]) {
+		return nil, errors.New("commitment of the participant does not match its nonce")
+	}
+	nonce.used = true
+
+	// z_i = d_i + e_i rho_i + lambda_i s_i c
+	z := new(big.Int).Mul(nonce.e, signing.rho[k.Index])
+	z.Add(z, nonce.d)
+	weight := new(big.Int).Mul(signing.lambda(k.Index), k.Share)
+	z.Add(z, weight.Mul(weight, signing.c))
+	return scalarBytes(z.Mod(z, signing.n())), nil
+}
+
+// ThresholdSession combines the partial signatures of the signers of a
+// message, checking each against the public key of its share
+type ThresholdSession struct {
+	signing  *frostSigning
+	shares   map[int]*point
+	partials map[int]*big.Int
+}
+
+// NewThresholdSession starts combining the signature of msg by the
+// participants that committed, keyed by index
+func NewThresholdSession(groupKey PublicKey, shares map[int]PublicKey, msg []byte, commitments map[int][]byte) (*ThresholdSession, error) {
+	signing, err := newFrostSigning(groupKey.Curve(), groupKey, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &ThresholdSession{
+		signing:  signing,
+		shares:   make(map[int]*point, len(commitments)),
+		partials: make(map[int]*big.Int, len(commitments)),
+	}
+	for index := range commitments {
+		key, exists := shares[index]
+		if !exists {
+			return nil, fmt.Errorf("participant %d has no share", index)
+		}
+		if session.shares[index], err = signing.publicPoint(key); err != nil {
+			return nil, fmt.Errorf("invalid share of participant %d", index)
+		}
+	}
+	return session, nil
+}
+
+// AddPartial checks and records the partial signature of a participant
+func (s *ThresholdSession) AddPartial(index int, partial []byte) error {
+	share, exists := s.shares[index]
+	if !exists {
+		return fmt.Errorf("participant %d is not a signer of the session", index)
+	}
+	if len(partial) != 32 {
+		return errors.New("partial signature must be 32 bytes")
+	}
+	z := new(big.Int).SetBytes(partial)
+	if z.Cmp(s.signing.n()) >= 0 {
+		return errors.New("partial signature is out of range")
+	}
+
+	// z_i G = D_i + rho_i E_i + lambda_i c Y_i
+	nonces := s.signing.nonces[index]
+	expected := s.signing.add(nonces[0], s.signing.mul(nonces[1], s.signing.rho[index]))
+	weight := new(big.Int).Mul(s.signing.lambda(index), s.signing.c)
+	expected = s.signing.add(expected, s.signing.mul(share, weight))
+	if !s.signing.baseMul(z).equal(expected) {
+		return fmt.Errorf("invalid partial signature from participant %d", index)
+	}
+
+	s.partials[index] = z
+	return nil
+}
+
+// Signature combines the partial signatures once every signer signed
+func (s *ThresholdSession) Signature() ([]byte, error) {
+	sum := new(big.Int)
+	for _, index := range s.signing.indices {
+		partial, exists := s.partials[index]
+		if !exists {
+			return nil, fmt.Errorf("waiting for the partial signature of participant %d", index)
+		}
+		sum.Add(sum, partial)
+	}
+	sum.Mod(sum, s.signing.n())
+	return append(s.signing.compress(s.signing.r), scalarBytes(sum)...), nil
+}
+
+// VerifyThreshold checks a threshold signature of msg by a group key
+func VerifyThreshold(groupKey PublicKey, msg, sig []byte) error {
+	g := newGroup(groupKey.Curve())
+	key, err := g.publicPoint(groupKey)
+	if err != nil {
+		return err
+	}
+	if err := verifySchnorr(g, key, msg, sig, "frost/challenge"); err != nil {
+		return fmt.Errorf("invalid threshold signature: %w", err)
+	}
+	return nil
+}
+
+// frostSigning is what every signer of a message derives from the
+// commitments: the binding factor of each signer, the nonce and the
+// challenge
+type frostSigning struct {
+	group
+	indices []int // Ascending
+	nonces  map[int][2]*point
+	rho     map[int]*big.Int
+	r       *point
+	c       *big.Int
+}
+
+func newFrostSigning(curve Curve, groupKey PublicKey, msg []byte, commitments map[int][]byte) (*frostSigning, error) {
+	g := newGroup(curve)
+	if groupKey.Curve() != curve {
+		return nil, errors.New("group key is not on the curve of the share")
+	}
+	groupPoint, err := g.publicPoint(groupKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(commitments) == 0 {
+		return nil, errors.New("signing needs at least one commitment")
+	}
+
+	signing := &frostSigning{
+		group:  g,
+		nonces: make(map[int][2]*point, len(commitments)),
+		rho:    make(map[int]*big.Int, len(commitments)),
+	}
+	for index := range commitments {
+		if index < 1 {
+			return nil, fmt.Errorf("invalid participant index %d", index)
+		}
+		signing.indices = append(signing.indices, index)
+	}
+	sort.Ints(signing.indices)
+
+	// The binding factors commit to the group key and every commitment, so
+	// no signer can adapt its nonce to the others nor replay it under
+	// another key
+	var list []byte
+	for _, index := range signing.indices {
+		nonces, err := parseNonceCommitment(g, commitments[index])
+		if err != nil {
+			return nil, fmt.Errorf("participant %d: %w", index, err)
+		}
+		signing.nonces[index] = nonces
+		list = append(list, indexBytes(index)...)
+		list = append(list, commitments[index]...)
+	}
+	for _, index := range signing.indices {
+		signing.rho[index] = g.hashScalar("frost/rho", indexBytes(index), g.compress(groupPoint), msg, list)
+		nonces := signing.nonces[index]
+		signing.r = g.add(signing.r, g.add(nonces[0], g.mul(nonces[1], signing.rho[index])))
+	}
+	if signing.r == nil {
+		return nil, errors.New("signing nonce is the point at infinity")
+	}
+
+	signing.c = g.hashScalar("frost/challenge", g.compress(signing.r), g.compress(groupPoint), msg)
+	return signing, nil
+}
+
+// lambda returns the Lagrange coefficient at zero of a signer over the
+// signer set
+func (s *frostSigning) lambda(index int) *big.Int {
+	num, den := big.NewInt(1), big.NewInt(1)
+	for _, other := range s.indices {
+		if other == index {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(other)))
+		den.Mul(den, big.NewInt(int64(other-index)))
+	}
+	den.Mod(den, s.n())
+	return num.Mul(num, den.ModInverse(den, s.n())).Mod(num, s.n())
+}
+
+func indexBytes(index int) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(index))
+	return b
+}
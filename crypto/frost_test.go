@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runDKG generates a threshold key among n participants
+func runDKG(t *testing.T, n, threshold int) ([]*ThresholdKey, map[int]PublicKey) {
+	keys := make(map[int]PrivateKey, n)
+	public := make(map[int]PublicKey, n)
+	for i := 1; i <= n; i++ {
+		keys[i] = GeneratePrivateKey()
+		public[i] = keys[i].PublicKey()
+	}
+
+	participants := make([]*DKGParticipant, n)
+	dealings := make([]*DKGDealing, n)
+	for i := range participants {
+		var err error
+		participants[i], err = NewDKGParticipant(CurveP256, i+1, threshold)
+		require.NoError(t, err)
+		dealings[i], err = participants[i].Deal(public)
+		require.NoError(t, err)
+	}
+
+	shares := make([]*ThresholdKey, n)
+	for i, participant := range participants {
+		var err error
+		shares[i], err = participant.Finish(keys[i+1], dealings)
+		require.NoError(t, err)
+	}
+
+	groupKey, shareKeys, err := DKGPublicKeys(CurveP256, threshold, dealings)
+	require.NoError(t, err)
+	g := newGroup(CurveP256)
+	for _, share := range shares {
+		assert.Equal(t, groupKey, share.GroupKey)
+		public := g.baseMul(share.Share)
+		assert.Equal(t, shareKeys[share.Index], publicKeyOf(CurveP256, public.x, public.y))
+	}
+	return shares, shareKeys
+}
+
+func TestThresholdSignature(t *testing.T) {
+	shares, shareKeys := runDKG(t, 4, 3)
+	groupKey := shares[0].GroupKey
+	msg := []byte("pay the auditor")
+
+	// Any 3 of the 4 shares sign
+	for _, signers := range [][]int{{0, 1, 2}, {1, 2, 3}, {0, 2, 3}} {
+		nonces := make(map[int]*ThresholdNonce)
+		commitments := make(map[int][]byte)
+		for _, i := range signers {
+			nonce, commitment, err := shares[i].Commit()
+			require.NoError(t, err)
+			nonces[shares[i].Index] = nonce
+			commitments[shares[i].Index] = commitment
+		}
+
+		session, err := NewThresholdSession(groupKey, shareKeys, msg, commitments)
+		require.NoError(t, err)
+		for _, i := range signers {
+			partial, err := shares[i].Sign(nonces[shares[i].Index], msg, commitments)
+			require.NoError(t, err)
+
+			tampered := append([]byte(nil), partial...)
+			tampered[0] ^= 0x01
+			assert.Error(t, session.AddPartial(shares[i].Index, tampered))
+			require.NoError(t, session.AddPartial(shares[i].Index, partial))
+
+			_, err = shares[i].Sign(nonces[shares[i].Index], msg, commitments)
+			assert.Error(t, err, "nonces sign once")
+		}
+
+		sig, err := session.Signature()
+		require.NoError(t, err)
+		assert.NoError(t, VerifyThreshold(groupKey, msg, sig))
+		assert.Error(t, VerifyThreshold(groupKey, []byte("pay someone else"), sig))
+	}
+
+	// Fewer shares than the threshold do not make a valid signature
+	commitments := make(map[int][]byte)
+	nonces := make(map[int]*ThresholdNonce)
+	for _, share := range shares[:2] {
+		nonce, commitment, err := share.Commit()
+		require.NoError(t, err)
+		nonces[share.Index], commitments[share.Index] = nonce, commitment
+	}
+	session, err := NewThresholdSession(groupKey, shareKeys, msg, commitments)
+	require.NoError(t, err)
+	for _, share := range shares[:2] {
+		partial, err := share.Sign(nonces[share.Index], msg, commitments)
+		require.NoError(t, err)
+		require.NoError(t, session.AddPartial(share.Index, partial))
+	}
+	sig, err := session.Signature()
+	require.NoError(t, err)
+	assert.Error(t, VerifyThreshold(groupKey, msg, sig))
+}
+
+func TestDKGRejectsBadShares(t *testing.T) {
+	keys := map[int]PrivateKey{1: GeneratePrivateKey(), 2: GeneratePrivateKey()}
+	public := map[int]PublicKey{1: keys[1].PublicKey(), 2: keys[2].PublicKey()}
+
+	first, err := NewDKGParticipant(CurveP256, 1, 2)
+	require.NoError(t, err)
+	second, err := NewDKGParticipant(CurveP256, 2, 2)
+	require.NoError(t, err)
+	firstDealing, err := first.Deal(public)
+	require.NoError(t, err)
+	secondDealing, err := second.Deal(public)
+	require.NoError(t, err)
+
+	// A share sealed for another value than committed is caught
+	forged, err := SealFor(public[2], scalarBytes(first.evaluate(3)))
+	require.NoError(t, err)
+	firstDealing.Shares[2] = forged
+	_, err = second.Finish(keys[2], []*DKGDealing{firstDealing, secondDealing})
+	assert.Error(t, err)
+
+	_, _, err = DKGPublicKeys(CurveP256, 2, []*DKGDealing{firstDealing, firstDealing})
+	assert.Error(t, err)
+
+	// A dealer must prove it knows the constant coefficient it committed
+	// to, or it could commit to a key that cancels the others
+	require.NoError(t, secondDealing.VerifyProof(CurveP256))
+	rogue := *secondDealing
+	rogue.Proof = nil
+	_, _, err = DKGPublicKeys(CurveP256, 2, []*DKGDealing{firstDealing, &rogue})
+	assert.Error(t, err)
+
+	// Proofs are bound to the index of their dealer
+	rogue = *firstDealing
+	rogue.From = 2
+	assert.Error(t, rogue.VerifyProof(CurveP256))
+	_, _, err = DKGPublicKeys(CurveP256, 2, []*DKGDealing{firstDealing, &rogue})
+	assert.Error(t, err)
+
+	_, err = NewDKGParticipant(CurveP256, 0, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package crypto
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+)
+
+// point is an affine curve point, nil standing for the point at infinity
+type point struct {
+	x, y *big.Int
+}
+
+func (p *point) equal(q *point) bool {
+	if p == nil || q == nil {
+		return p == q
+	}
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
+}
+
+// group is the curve arithmetic the Schnorr based schemes share
+type group struct {
+	curve elliptic.Curve
+}
+
+func newGroup(curve Curve) group {
+	return group{curve: curve.elliptic()}
+}
+
+func (g group) n() *big.Int {
+	return g.curve.Params().N
+}
+
+func (g group) baseMul(k *big.Int) *point {
+	return g.affine(g.curve.ScalarBaseMult(scalarBytes(new(big.Int).Mod(k, g.n()))))
+}
+
+func (g group) mul(p *point, k *big.Int) *point {
+	if p == nil {
+		return nil
+	}
+	return g.affine(g.curve.ScalarMult(p.x, p.y, scalarBytes(new(big.Int).Mod(k, g.n()))))
+}
+
+func (g group) add(a, b *point) *point {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return g.affine(g.curve.Add(a.x, a.y, b.x, b.y))
+}
+
+// affine wraps coordinates, (0, 0) being the point at infinity
+func (g group) affine(x, y *big.Int) *point {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil
+	}
+	return &point{x: x, y: y}
+}
+
+// hashScalar hashes a domain tag and parts to a scalar
+func (g group) hashScalar(tag string, parts ...[]byte) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(tag))
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(h.Sum(nil)), g.n())
+}
+
+// compress encodes a point as 33 bytes, without the curve tag of public
+// keys
+func (g group) compress(p *point) []byte {
+	if p == nil {
+		return make([]byte, 33)
+	}
+	return elliptic.MarshalCompressed(g.curve, p.x, p.y)
+}
+
+func (g group) parsePoint(b []byte) (*point, error) {
+	key, err := CurvePublicKeyFromBytes(curveOf(g.curve), b)
+	if err != nil {
+		return nil, err
+	}
+	return g.publicPoint(key)
+}
+
+// publicPoint returns the point of a public key
+func (g group) publicPoint(key PublicKey) (*point, error) {
+	ecdsaKey, err := key.ecdsa()
+	if err != nil {
+		return nil, err
+	}
+	return &point{x: ecdsaKey.X, y: ecdsaKey.Y}, nil
+}
+
+// randomScalar returns a uniformly random non zero scalar
+func (g group) randomScalar() (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, g.n())
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+// scalarBytes returns a scalar as 32 big endian bytes
+func scalarBytes(k *big.Int) []byte {
+	return k.FillBytes(make([]byte, 32))
+}
@@ -0,0 +1,187 @@
+package crypto
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HardenedOffset is added to the index of hardened derivation steps
+	HardenedOffset uint32 = 0x80000000
+
+	// BockCoinType is the BIP-44 coin type of BockChain accounts
+	BockCoinType uint32 = 7331
+
+	// slip10Seed is the SLIP-10 HMAC key of P-256 master keys
+	slip10Seed = "Nist256p1 seed"
+)
+
+// ExtendedKey is a node of a hierarchical deterministic key tree. Keys are
+// derived following SLIP-10, the generalisation of BIP-32 to P-256.
+type ExtendedKey struct {
+	key       PrivateKey
+	chainCode []byte
+	depth     uint8
+	index     uint32
+}
+
+// NewMasterKey derives the root of the key tree of a seed
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be 16 to 64 bytes, got %d", len(seed))
+	}
+
+	data := seed
+	for {
+		sum := hmacSHA512([]byte(slip10Seed), data)
+		// Out of range keys are retried with the digest as input
+		if key, err := PrivateKeyFromBytes(sum[:32]); err == nil {
+			return &ExtendedKey{key: key, chainCode: sum[32:]}, nil
+		}
+		data = sum
+	}
+}
+
+// PrivateKey returns the key of the node
+func (k *ExtendedKey) PrivateKey() PrivateKey {
+	return k.key
+}
+
+// ChainCode returns the chain code of the node
+func (k *ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), k.chainCode...)
+}
+
+// Depth returns the number of derivation steps from the master key
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Index returns the index the node was derived with
+func (k *ExtendedKey) Index() uint32 {
+	return k.index
+}
+
+// Derive returns the child at index, which is hardened when index is at
+// least HardenedOffset
+func (k *ExtendedKey) Derive(index uint32) (*ExtendedKey, error) {
+	if k.depth == 255 {
+		return nil, fmt.Errorf("maximum derivation depth reached")
+	}
+
+	n := elliptic.P256().Params().N
+	data := make([]byte, 37)
+	if index >= HardenedOffset {
+		copy(data[1:33], k.key.Bytes())
+	} else {
+		copy(data[:33], k.key.PublicKey())
+	}
+
+	for {
+		binary.BigEndian.PutUint32(data[33:], index)
+		sum := hmacSHA512(k.chainCode, data)
+
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) < 0 {
+			d := il.Add(il, k.key.key.D)
+			d.Mod(d, n)
+			if d.Sign() != 0 {
+				b := make([]byte, 32)
+				key, err := PrivateKeyFromBytes(d.FillBytes(b))
+				if err != nil {
+					return nil, err
+				}
+				return &ExtendedKey{key: key, chainCode: sum[32:], depth: k.depth + 1, index: index}, nil
+			}
+		}
+
+		// Invalid children are retried with the right half of the digest
+		data[0] = 1
+		copy(data[1:33], sum[32:])
+	}
+}
+
+// DerivePath derives the node at a path relative to k, such as
+// "m/44'/7331'/0'/0/0"
+func (k *ExtendedKey) DerivePath(path string) (*ExtendedKey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, index := range indexes {
+		if key, err = key.Derive(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ParseDerivationPath parses a path such as "m/44'/7331'/0'/0/0" into its
+// child indexes. Hardened steps are marked with ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m", path)
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			offset = HardenedOffset
+			part = part[:len(part)-1]
+		}
+
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid index %q in derivation path %q", part, path)
+		}
+		indexes = append(indexes, uint32(index)+offset)
+	}
+
+	return indexes, nil
+}
+
+// AccountPath returns the BIP-44 path m/44'/coinType'/0'/0/index of an
+// account
+func AccountPath(coinType, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", coinType, index)
+}
+
+// DeriveAccount derives the BIP-44 account key at index from a seed
+func DeriveAccount(seed []byte, coinType, index uint32) (PrivateKey, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	account, err := master.DerivePath(AccountPath(coinType, index))
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	return account.PrivateKey(), nil
+}
+
+// DeriveAccountFromMnemonic derives the BIP-44 account key at index from a
+// BIP-39 mnemonic and its optional passphrase
+func DeriveAccountFromMnemonic(mnemonic, passphrase string, coinType, index uint32) (PrivateKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	return DeriveAccount(seed, coinType, index)
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,114 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonic_Vectors(t *testing.T) {
+	vectors := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{"00000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{"ffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+	}
+
+	for _, v := range vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+
+		mnemonic, err := EntropyToMnemonic(entropy)
+		require.NoError(t, err)
+		assert.Equal(t, v.mnemonic, mnemonic)
+
+		decoded, err := MnemonicToEntropy(mnemonic)
+		require.NoError(t, err)
+		assert.Equal(t, entropy, decoded)
+	}
+
+	seed, err := MnemonicToSeed(vectors[0].mnemonic, "TREZOR")
+	require.NoError(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+}
+
+func TestMnemonic_Generate(t *testing.T) {
+	for bits, words := range map[int]int{128: 12, 192: 18, 256: 24} {
+		mnemonic, err := NewMnemonic(bits)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), words)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := NewMnemonic(100)
+	assert.Error(t, err)
+}
+
+func TestMnemonic_Invalid(t *testing.T) {
+	assert.ErrorIs(t, ValidateMnemonic("abandon abandon"), ErrInvalidMnemonic)
+	assert.ErrorIs(t, ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon bockchain"), ErrInvalidMnemonic)
+	assert.ErrorIs(t, ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"), ErrMnemonicChecksum)
+}
+
+func TestHDWallet_MasterKey(t *testing.T) {
+	// SLIP-10 test vector 1 for nist256p1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+	assert.Equal(t, "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", hex.EncodeToString(master.ChainCode()))
+	assert.Equal(t, "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2", hex.EncodeToString(master.PrivateKey().Bytes()))
+}
+
+func TestHDWallet_DerivePath(t *testing.T) {
+	seed, err := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	require.NoError(t, err)
+
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	account, err := master.DerivePath(AccountPath(BockCoinType, 0))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(5), account.Depth())
+	assert.Equal(t, uint32(0), account.Index())
+
+	// Deriving step by step gives the same key
+	key := master
+	for _, index := range []uint32{44 + HardenedOffset, BockCoinType + HardenedOffset, HardenedOffset, 0, 0} {
+		key, err = key.Derive(index)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, account.PrivateKey().Bytes(), key.PrivateKey().Bytes())
+
+	// Accounts are deterministic and distinct
+	again, err := DeriveAccount(seed, BockCoinType, 0)
+	require.NoError(t, err)
+	assert.Equal(t, account.PrivateKey().Bytes(), again.Bytes())
+
+	next, err := DeriveAccount(seed, BockCoinType, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, again.PublicKey().Address(), next.PublicKey().Address())
+
+	withPassphrase, err := DeriveAccountFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR", BockCoinType, 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, again.Bytes(), withPassphrase.Bytes())
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indexes, err := ParseDerivationPath("m/44'/7331h/0'/0/5")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{44 + HardenedOffset, 7331 + HardenedOffset, HardenedOffset, 0, 5}, indexes)
+
+	indexes, err = ParseDerivationPath("m")
+	require.NoError(t, err)
+	assert.Empty(t, indexes)
+
+	for _, path := range []string{"", "44'/0'", "m/x", "m/2147483648", "m//0"} {
+		_, err := ParseDerivationPath(path)
+		assert.Error(t, err, path)
+	}
+}
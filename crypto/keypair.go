@@ -6,10 +6,12 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"io"
 	"math/big"
 
 	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
 type PrivateKey struct {
@@ -43,22 +45,204 @@ func GeneratePrivateKey() PrivateKey {
 	return NewPrivateKeyFromReader(rand.Reader)
 }
 
+// GenerateCurvePrivateKey generates a key pair on curve
+func GenerateCurvePrivateKey(curve Curve) PrivateKey {
+	key, err := ecdsa.GenerateKey(curve.elliptic(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	return PrivateKey{
+		key: key,
+	}
+}
+
+// PrivateKeyFromBytes parses a 32 byte P-256 private key scalar
+func PrivateKeyFromBytes(b []byte) (PrivateKey, error) {
+	return CurvePrivateKeyFromBytes(CurveP256, b)
+}
+
+// CurvePrivateKeyFromBytes parses a 32 byte private key scalar of curve
+func CurvePrivateKeyFromBytes(curve Curve, b []byte) (PrivateKey, error) {
+	params := curve.elliptic()
+	if len(b) != 32 {
+		return PrivateKey{}, errors.New("private key must be 32 bytes")
+	}
+
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() == 0 || d.Cmp(params.Params().N) >= 0 {
+		return PrivateKey{}, errors.New("private key is out of range")
+	}
+
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: params},
+		D:         d,
+	}
+	key.PublicKey.X, key.PublicKey.Y = params.ScalarBaseMult(b)
+
+	return PrivateKey{key: key}, nil
+}
+
+// PrivateKeyFromHex parses a hex encoded private key as written by Hex. Keys
+// without a curve prefix are P-256 keys.
+func PrivateKeyFromHex(s string) (PrivateKey, error) {
+	curve, s, _, err := splitKeyHex(s)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return PrivateKey{}, errors.New("private key is not hex encoded")
+	}
+	return CurvePrivateKeyFromBytes(curve, b)
+}
+
+// Bytes returns the 32 byte scalar of the private key
+func (k PrivateKey) Bytes() []byte {
+	b := make([]byte, 32)
+	k.key.D.FillBytes(b)
+	return b
+}
+
+// Hex returns the hex encoded scalar of the private key, prefixed with
+// secp256k1: for secp256k1 keys
+func (k PrivateKey) Hex() string {
+	if k.Curve() == CurveSecp256k1 {
+		return string(CurveSecp256k1) + ":" + hex.EncodeToString(k.Bytes())
+	}
+	return hex.EncodeToString(k.Bytes())
+}
+
+// Curve returns the curve of the key pair
+func (k PrivateKey) Curve() Curve {
+	return curveOf(k.key.Curve)
+}
+
 func (k PrivateKey) PublicKey() PublicKey {
-	return elliptic.MarshalCompressed(k.key.PublicKey, k.key.PublicKey.X, k.key.PublicKey.Y)
+	return publicKeyOf(k.Curve(), k.key.PublicKey.X, k.key.PublicKey.Y)
 }
 
+// PublicKey is a compressed SEC 1 point. secp256k1 points are prefixed with
+// a tag byte, 34 bytes in all.
 type PublicKey []byte
 
+// PublicKeyFromBytes parses a public key, a compressed or uncompressed
+// P-256 point or a tagged secp256k1 point, and checks it is on its curve
+func PublicKeyFromBytes(b []byte) (PublicKey, error) {
+	if len(b) == 34 && b[0] == secp256k1Tag {
+		key := PublicKey(append([]byte(nil), b...))
+		if err := key.Validate(); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	return CurvePublicKeyFromBytes(CurveP256, b)
+}
+
+// CurvePublicKeyFromBytes parses a compressed or uncompressed SEC 1 point of
+// curve, as exported by wallets, and checks it is on the curve
+func CurvePublicKeyFromBytes(curve Curve, b []byte) (PublicKey, error) {
+	if curve == CurveSecp256k1 {
+		key, err := secp256k1.ParsePubKey(b)
+		if err != nil {
+			return nil, errors.New("invalid secp256k1 public key")
+		}
+		return publicKeyOf(curve, key.X(), key.Y()), nil
+	}
+
+	var x, y *big.Int
+	switch len(b) {
+	case 33:
+		x, y = elliptic.UnmarshalCompressed(elliptic.P256(), b)
+	case 65:
+		x, y = elliptic.Unmarshal(elliptic.P256(), b)
+	default:
+		return nil, errors.New("public key must be a 33 or 65 byte P-256 point")
+	}
+	if x == nil {
+		return nil, errors.New("invalid P-256 public key")
+	}
+	return publicKeyOf(curve, x, y), nil
+}
+
+// PublicKeyFromHex parses a hex encoded public key. A curve prefix, as in
+// secp256k1:<hex>, reads a plain SEC 1 point of the curve.
+func PublicKeyFromHex(s string) (PublicKey, error) {
+	curve, s, prefixed, err := splitKeyHex(s)
+	if err != nil {
+		return nil, err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("public key is not hex encoded")
+	}
+	if prefixed {
+		return CurvePublicKeyFromBytes(curve, b)
+	}
+	return PublicKeyFromBytes(b)
+}
+
 func (k PublicKey) String() string {
 	return hex.EncodeToString(k)
 }
 
+// Curve returns the curve of the key
+func (k PublicKey) Curve() Curve {
+	if len(k) == 34 && k[0] == secp256k1Tag {
+		return CurveSecp256k1
+	}
+	return CurveP256
+}
+
+// Validate checks the key is a point of its curve
+func (k PublicKey) Validate() error {
+	_, err := k.ecdsa()
+	return err
+}
+
+// ecdsa returns the point of the key
+func (k PublicKey) ecdsa() (*ecdsa.PublicKey, error) {
+	if k.Curve() == CurveSecp256k1 {
+		key, err := secp256k1.ParsePubKey(k[1:])
+		if err != nil {
+			return nil, errors.New("invalid secp256k1 public key")
+		}
+		return key.ToECDSA(), nil
+	}
+
+	if len(k) != 33 {
+		return nil, errors.New("public key must be a 33 byte compressed point")
+	}
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), k)
+	if x == nil {
+		return nil, errors.New("invalid P-256 public key")
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
+
 func (k PublicKey) Address() types.Address {
 	h := sha256.Sum256(k)
 
 	return types.AddressFromBytes(h[len(h)-20:])
 }
 
+// publicKeyOf encodes a point of curve
+func publicKeyOf(curve Curve, x, y *big.Int) PublicKey {
+	compressed := elliptic.MarshalCompressed(curve.elliptic(), x, y)
+	if curve == CurveSecp256k1 {
+		return append([]byte{secp256k1Tag}, compressed...)
+	}
+	return compressed
+}
+
+// curveOf returns the Curve of an elliptic curve
+func curveOf(c elliptic.Curve) Curve {
+	if c.Params().Name == secp256k1.S256().Params().Name {
+		return CurveSecp256k1
+	}
+	return CurveP256
+}
+
 type Signature struct {
 	S *big.Int
 	R *big.Int
@@ -69,13 +253,23 @@ func (sig Signature) String() string {
 	return hex.EncodeToString(b)
 }
 
+// Verify reports whether sig is a signature of data by pubKey. Signatures
+// decoded from the network may lack R or S, those never verify.
 func (sig Signature) Verify(pubKey PublicKey, data []byte) bool {
-	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pubKey)
-	key := &ecdsa.PublicKey{
-		Curve: elliptic.P256(),
-		X:     x,
-		Y:     y,
+	key, err := pubKey.ecdsa()
+	if err != nil {
+		return false
+	}
+	if !sig.inRange(key.Curve.Params().N) {
+		return false
 	}
 
 	return ecdsa.Verify(key, data, sig.R, sig.S)
 }
+
+// inRange reports whether R and S are set and within [1, n-1]
+func (sig Signature) inRange(n *big.Int) bool {
+	return sig.R != nil && sig.S != nil &&
+		sig.R.Sign() > 0 && sig.S.Sign() > 0 &&
+		sig.R.Cmp(n) < 0 && sig.S.Cmp(n) < 0
+}
@@ -1,9 +1,13 @@
 package crypto
 
 import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKeypairSignVerifySuccess(t *testing.T) {
@@ -30,4 +34,105 @@ func TestKeypairSignVerifyFail(t *testing.T) {
 
 	assert.False(t, sig.Verify(otherPublicKey, msg))
 	assert.False(t, sig.Verify(publicKey, []byte("xxxxxx")))
+
+	// Missing and out of range values fail rather than panic
+	n := elliptic.P256().Params().N
+	for _, bad := range []Signature{
+		{R: sig.R},
+		{S: sig.S},
+		{},
+		{R: big.NewInt(0), S: sig.S},
+		{R: sig.R, S: new(big.Int).Neg(sig.S)},
+		{R: new(big.Int).Add(sig.R, n), S: sig.S},
+		{R: sig.R, S: n},
+	} {
+		assert.False(t, bad.Verify(publicKey, msg))
+	}
+}
+
+func TestPrivateKeyBytesRoundTrip(t *testing.T) {
+	privKey := GeneratePrivateKey()
+
+	parsed, err := PrivateKeyFromBytes(privKey.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, privKey.PublicKey(), parsed.PublicKey())
+
+	_, err = PrivateKeyFromBytes(make([]byte, 32))
+	assert.NotNil(t, err)
+
+	_, err = PrivateKeyFromBytes([]byte{0x01})
+	assert.NotNil(t, err)
+}
+
+func TestSecp256k1SignVerify(t *testing.T) {
+	privKey := GenerateCurvePrivateKey(CurveSecp256k1)
+	publicKey := privKey.PublicKey()
+	msg := []byte("hello world")
+
+	assert.Equal(t, CurveSecp256k1, privKey.Curve())
+	assert.Equal(t, CurveSecp256k1, publicKey.Curve())
+	assert.Len(t, publicKey, 34)
+
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	assert.True(t, sig.Verify(publicKey, msg))
+	assert.False(t, sig.Verify(publicKey, []byte("xxxxxx")))
+	assert.False(t, sig.Verify(GeneratePrivateKey().PublicKey(), msg))
+}
+
+func TestPrivateKeyFromHex(t *testing.T) {
+	// The secp256k1 key 1 is the generator of the curve
+	key, err := PrivateKeyFromHex("secp256k1:0x" + hex.EncodeToString(append(make([]byte, 31), 1)))
+	require.NoError(t, err)
+	assert.Equal(t, "010279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", key.PublicKey().String())
+
+	parsed, err := PrivateKeyFromHex(key.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey(), parsed.PublicKey())
+
+	p256 := GeneratePrivateKey()
+	parsed, err = PrivateKeyFromHex(p256.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, p256.PublicKey(), parsed.PublicKey())
+
+	_, err = PrivateKeyFromHex("ed25519:" + p256.Hex())
+	assert.Error(t, err)
+	_, err = PrivateKeyFromHex("not hex")
+	assert.Error(t, err)
+}
+
+func TestPublicKeyFromBytes(t *testing.T) {
+	privKey := GeneratePrivateKey()
+	pubKey := privKey.PublicKey()
+
+	parsed, err := PublicKeyFromBytes(pubKey)
+	require.NoError(t, err)
+	assert.Equal(t, pubKey, parsed)
+
+	// Uncompressed points are compressed
+	uncompressed := elliptic.Marshal(elliptic.P256(), privKey.key.X, privKey.key.Y)
+	parsed, err = PublicKeyFromBytes(uncompressed)
+	require.NoError(t, err)
+	assert.Equal(t, pubKey, parsed)
+
+	// Points off the curve are rejected
+	invalid := append([]byte(nil), uncompressed...)
+	invalid[64] ^= 0x01
+	_, err = PublicKeyFromBytes(invalid)
+	assert.Error(t, err)
+	_, err = PublicKeyFromBytes(pubKey[:32])
+	assert.Error(t, err)
+
+	// Wallets export secp256k1 keys without the tag
+	secpKey := GenerateCurvePrivateKey(CurveSecp256k1)
+	wallet := secpKey.PublicKey()[1:]
+	parsed, err = PublicKeyFromHex("secp256k1:" + hex.EncodeToString(wallet))
+	require.NoError(t, err)
+	assert.Equal(t, secpKey.PublicKey(), parsed)
+
+	parsed, err = PublicKeyFromBytes(secpKey.PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, secpKey.PublicKey(), parsed)
+	assert.NoError(t, parsed.Validate())
+	assert.Error(t, PublicKey(pubKey[:32]).Validate())
 }
@@ -0,0 +1,369 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StandardScryptN is the scrypt cost used for keys at rest
+	StandardScryptN = 1 << 18
+	// LightScryptN is a cheaper scrypt cost for tests and development
+	LightScryptN = 1 << 12
+
+	keystoreVersion = 1
+	scryptR         = 8
+	scryptP         = 1
+	scryptKeyLen    = 32
+)
+
+var (
+	ErrKeyNotFound     = errors.New("key not found in keystore")
+	ErrKeyExists       = errors.New("key already exists in keystore")
+	ErrWrongPassphrase = errors.New("could not decrypt key with the given passphrase")
+
+	keyNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+// EncryptedKey is a private key encrypted with a passphrase, as stored in a
+// JSON keystore file
+type EncryptedKey struct {
+	Version   int          `json:"version"`
+	Curve     Curve        `json:"curve,omitempty"` // P-256 when empty
+	PublicKey string       `json:"public_key"`
+	Address   string       `json:"address"`
+	Crypto    CryptoParams `json:"crypto"`
+}
+
+// CryptoParams describes how an EncryptedKey was encrypted. The AES-256-GCM
+// key is derived from the passphrase with scrypt.
+type CryptoParams struct {
+	Cipher     string       `json:"cipher"`
+	CipherText string       `json:"ciphertext"`
+	Nonce      string       `json:"nonce"`
+	KDF        string       `json:"kdf"`
+	KDFParams  ScryptParams `json:"kdfparams"`
+}
+
+type ScryptParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// EncryptKey encrypts a private key with a passphrase using scrypt cost n
+func EncryptKey(key PrivateKey, passphrase string, n int) (*EncryptedKey, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	params := ScryptParams{N: n, R: scryptR, P: scryptP, DKLen: scryptKeyLen, Salt: hex.EncodeToString(salt)}
+	gcm, err := keystoreCipher(passphrase, params)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	pubKey := key.PublicKey()
+	// The public key is authenticated so a file cannot be relabelled
+	cipherText := gcm.Seal(nil, nonce, key.Bytes(), pubKey)
+
+	encrypted := &EncryptedKey{
+		Version:   keystoreVersion,
+		PublicKey: pubKey.String(),
+		Address:   pubKey.Address().String(),
+		Crypto: CryptoParams{
+			Cipher:     "aes-256-gcm",
+			CipherText: hex.EncodeToString(cipherText),
+			Nonce:      hex.EncodeToString(nonce),
+			KDF:        "scrypt",
+			KDFParams:  params,
+		},
+	}
+	if key.Curve() != CurveP256 {
+		encrypted.Curve = key.Curve()
+	}
+	return encrypted, nil
+}
+
+// DecryptKey decrypts an encrypted private key with its passphrase
+func DecryptKey(encrypted *EncryptedKey, passphrase string) (PrivateKey, error) {
+	if encrypted.Version != keystoreVersion || encrypted.Crypto.Cipher != "aes-256-gcm" || encrypted.Crypto.KDF != "scrypt" {
+		return PrivateKey{}, fmt.Errorf("unsupported keystore format")
+	}
+
+	pubKey, err := hex.DecodeString(encrypted.PublicKey)
+	if err != nil {
+		return PrivateKey{}, fmt.Errorf("invalid keystore public key")
+	}
+	cipherText, err := hex.DecodeString(encrypted.Crypto.CipherText)
+	if err != nil {
+		return PrivateKey{}, fmt.Errorf("invalid keystore ciphertext")
+	}
+	nonce, err := hex.DecodeString(encrypted.Crypto.Nonce)
+	if err != nil {
+		return PrivateKey{}, fmt.Errorf("invalid keystore nonce")
+	}
+
+	gcm, err := keystoreCipher(passphrase, encrypted.Crypto.KDFParams)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return PrivateKey{}, fmt.Errorf("invalid keystore nonce")
+	}
+
+	plain, err := gcm.Open(nil, nonce, cipherText, pubKey)
+	if err != nil {
+		return PrivateKey{}, ErrWrongPassphrase
+	}
+
+	curve, err := ParseCurve(string(encrypted.Curve))
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	return CurvePrivateKeyFromBytes(curve, plain)
+}
+
+// keystoreCipher derives the AES-GCM cipher of a passphrase
+func keystoreCipher(passphrase string, params ScryptParams) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt")
+	}
+
+	derived, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// KeystoreEntry describes a key held by a keystore
+type KeystoreEntry struct {
+	Name      string
+	PublicKey PublicKey
+}
+
+// Keystore keeps named private keys encrypted at rest, one JSON file per key
+type Keystore struct {
+	mu      sync.Mutex
+	dir     string
+	scryptN int
+}
+
+// NewKeystore opens the keystore in dir, creating the directory if needed.
+// New keys are encrypted with scrypt cost scryptN.
+func NewKeystore(dir string, scryptN int) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &Keystore{dir: dir, scryptN: scryptN}, nil
+}
+
+// Generate creates a new key and stores it under name
+func (ks *Keystore) Generate(name, passphrase string) (PrivateKey, error) {
+	key := GeneratePrivateKey()
+	if err := ks.Import(name, key, passphrase); err != nil {
+		return PrivateKey{}, err
+	}
+	return key, nil
+}
+
+// Import stores an existing key under name
+func (ks *Keystore) Import(name string, key PrivateKey, passphrase string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	path, err := ks.path(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return ErrKeyExists
+	}
+
+	return ks.write(path, key, passphrase)
+}
+
+// Load decrypts the key stored under name
+func (ks *Keystore) Load(name, passphrase string) (PrivateKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	encrypted, err := ks.read(name)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	return DecryptKey(encrypted, passphrase)
+}
+
+// Export returns the raw private key stored under name
+func (ks *Keystore) Export(name, passphrase string) ([]byte, error) {
+	key, err := ks.Load(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return key.Bytes(), nil
+}
+
+// ChangePassphrase re-encrypts the key stored under name with a new
+// passphrase
+func (ks *Keystore) ChangePassphrase(name, oldPassphrase, newPassphrase string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	encrypted, err := ks.read(name)
+	if err != nil {
+		return err
+	}
+
+	key, err := DecryptKey(encrypted, oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	path, err := ks.path(name)
+	if err != nil {
+		return err
+	}
+
+	return ks.write(path, key, newPassphrase)
+}
+
+// Delete removes the key stored under name
+func (ks *Keystore) Delete(name string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	path, err := ks.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		return ErrKeyNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List returns the keys of the keystore ordered by name, without
+// decrypting them
+func (ks *Keystore) List() ([]KeystoreEntry, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	files, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []KeystoreEntry{}
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".json")
+		if file.IsDir() || name == file.Name() || !keyNamePattern.MatchString(name) {
+			continue
+		}
+
+		encrypted, err := ks.read(name)
+		if err != nil {
+			return nil, err
+		}
+
+		pubKey, err := hex.DecodeString(encrypted.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key in keystore file %s", file.Name())
+		}
+		entries = append(entries, KeystoreEntry{Name: name, PublicKey: pubKey})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// path returns the file of a key, rejecting names that could escape the
+// keystore directory
+func (ks *Keystore) path(name string) (string, error) {
+	if !keyNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid key name %q, use letters, digits, '-' and '_'", name)
+	}
+	return filepath.Join(ks.dir, name+".json"), nil
+}
+
+// read loads an encrypted key. The caller must hold the lock.
+func (ks *Keystore) read(name string) (*EncryptedKey, error) {
+	path, err := ks.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := &EncryptedKey{}
+	if err := json.Unmarshal(data, encrypted); err != nil {
+		return nil, fmt.Errorf("invalid keystore file %s: %w", path, err)
+	}
+
+	return encrypted, nil
+}
+
+// write encrypts a key into its file, replacing it atomically. The caller
+// must hold the lock.
+func (ks *Keystore) write(path string, key PrivateKey, passphrase string) error {
+	encrypted, err := EncryptKey(key, passphrase, ks.scryptN)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(encrypted, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,115 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore_GenerateLoadAndExport(t *testing.T) {
+	ks, err := NewKeystore(t.TempDir(), LightScryptN)
+	require.NoError(t, err)
+
+	key, err := ks.Generate("validator", "correct horse")
+	require.NoError(t, err)
+
+	loaded, err := ks.Load("validator", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey(), loaded.PublicKey())
+
+	exported, err := ks.Export("validator", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, key.Bytes(), exported)
+
+	_, err = ks.Load("validator", "wrong")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	_, err = ks.Load("missing", "correct horse")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = ks.Generate("validator", "other")
+	assert.ErrorIs(t, err, ErrKeyExists)
+}
+
+func TestKeystore_Secp256k1Key(t *testing.T) {
+	ks, err := NewKeystore(t.TempDir(), LightScryptN)
+	require.NoError(t, err)
+
+	key := GenerateCurvePrivateKey(CurveSecp256k1)
+	require.NoError(t, ks.Import("wallet", key, "correct horse"))
+
+	loaded, err := ks.Load("wallet", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, CurveSecp256k1, loaded.Curve())
+	assert.Equal(t, key.PublicKey(), loaded.PublicKey())
+}
+
+func TestKeystore_EncryptedAtRest(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := NewKeystore(dir, LightScryptN)
+	require.NoError(t, err)
+
+	key := GeneratePrivateKey()
+	require.NoError(t, ks.Import("treasury", key, "secret"))
+
+	path := filepath.Join(dir, "treasury.json")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), hex.EncodeToString(key.Bytes()))
+	assert.Contains(t, string(data), key.PublicKey().String())
+
+	entries, err := ks.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "treasury", entries[0].Name)
+	assert.Equal(t, key.PublicKey(), entries[0].PublicKey)
+}
+
+func TestKeystore_ChangePassphrase(t *testing.T) {
+	ks, err := NewKeystore(t.TempDir(), LightScryptN)
+	require.NoError(t, err)
+
+	key, err := ks.Generate("validator", "old")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, ks.ChangePassphrase("validator", "wrong", "new"), ErrWrongPassphrase)
+	require.NoError(t, ks.ChangePassphrase("validator", "old", "new"))
+
+	_, err = ks.Load("validator", "old")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	loaded, err := ks.Load("validator", "new")
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey(), loaded.PublicKey())
+
+	require.NoError(t, ks.Delete("validator"))
+	assert.ErrorIs(t, ks.Delete("validator"), ErrKeyNotFound)
+}
+
+func TestKeystore_RejectsInvalidNames(t *testing.T) {
+	ks, err := NewKeystore(t.TempDir(), LightScryptN)
+	require.NoError(t, err)
+
+	_, err = ks.Generate("../escape", "secret")
+	assert.Error(t, err)
+	_, err = ks.Generate("", "secret")
+	assert.Error(t, err)
+}
+
+func TestDecryptKey_DetectsTampering(t *testing.T) {
+	encrypted, err := EncryptKey(GeneratePrivateKey(), "secret", LightScryptN)
+	require.NoError(t, err)
+
+	// Relabelling the key with another public key fails authentication
+	encrypted.PublicKey = GeneratePrivateKey().PublicKey().String()
+	_, err = DecryptKey(encrypted, "secret")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+}
@@ -0,0 +1,346 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// AggregateSchemeMuSig2 is MuSig2, two round Schnorr multi-signatures over
+// the curve the signers share. A signature is the 33 byte compressed nonce
+// point and the 32 byte scalar, whatever the number of signers.
+const AggregateSchemeMuSig2 AggregateScheme = "musig2"
+
+func init() {
+	RegisterAggregateSigner(MuSig2{})
+}
+
+// MuSig2 is the AggregateSigner of AggregateSchemeMuSig2
+type MuSig2 struct{}
+
+// Scheme implements AggregateSigner
+func (MuSig2) Scheme() AggregateScheme {
+	return AggregateSchemeMuSig2
+}
+
+// NewSession implements AggregateSigner
+func (MuSig2) NewSession(signers []PublicKey, msg []byte) (AggregateSession, error) {
+	keys, err := newMusigKeys(signers)
+	if err != nil {
+		return nil, err
+	}
+	return &musigSession{
+		keys:     keys,
+		msg:      append([]byte(nil), msg...),
+		nonces:   make([][2]*point, len(keys.signers)),
+		secrets:  make(map[int][2]*big.Int),
+		partials: make([]*big.Int, len(keys.signers)),
+	}, nil
+}
+
+// Verify implements AggregateSigner
+func (MuSig2) Verify(sig *AggregateSignature, msg []byte) error {
+	if sig.Scheme != AggregateSchemeMuSig2 {
+		return fmt.Errorf("not a %s signature", AggregateSchemeMuSig2)
+	}
+	keys, err := newMusigKeys(sig.Signers)
+	if err != nil {
+		return err
+	}
+	for i := range sig.Signers {
+		if !bytes.Equal(sig.Signers[i], keys.signers[i]) {
+			return errors.New("aggregate signers must be sorted")
+		}
+	}
+
+	if err := verifySchnorr(keys.group, keys.agg, msg, sig.Data, "musig2/challenge"); err != nil {
+		return fmt.Errorf("invalid musig2 signature: %w", err)
+	}
+	return nil
+}
+
+// verifySchnorr checks a 65 byte Schnorr signature R || s of msg by key:
+// s G = R + H(R, key, msg) key
+func verifySchnorr(g group, key *point, msg, sig []byte, tag string) error {
+	if len(sig) != 65 {
+		return errors.New("signature must be 65 bytes")
+	}
+	r, err := g.parsePoint(sig[:33])
+	if err != nil {
+		return errors.New("invalid nonce point")
+	}
+	s := new(big.Int).SetBytes(sig[33:])
+	if s.Cmp(g.n()) >= 0 {
+		return errors.New("signature scalar is out of range")
+	}
+
+	c := g.hashScalar(tag, g.compress(r), g.compress(key), msg)
+	if !g.baseMul(s).equal(g.add(r, g.mul(key, c))) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// musigKeys is the aggregate key of a signer set. Each key is weighted by
+// a coefficient bound to the whole set, so no signer can pick its key to
+// cancel the others.
+type musigKeys struct {
+	group
+	signers []PublicKey
+	points  []*point
+	coefs   []*big.Int
+	agg     *point
+}
+
+func newMusigKeys(signers []PublicKey) (*musigKeys, error) {
+	sorted, err := sortedSigners(signers)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := sorted[0].Curve()
+	keys := &musigKeys{
+		group:   newGroup(curve),
+		signers: sorted,
+		points:  make([]*point, len(sorted)),
+		coefs:   make([]*big.Int, len(sorted)),
+	}
+
+	list := sha256.New()
+	for i, signer := range sorted {
+		if signer.Curve() != curve {
+			return nil, errors.New("aggregate signers must share a curve")
+		}
+		if keys.points[i], err = keys.publicPoint(signer); err != nil {
+			return nil, fmt.Errorf("invalid signer %s: %w", signer, err)
+		}
+		list.Write(signer)
+	}
+	listHash := list.Sum(nil)
+
+	for i, signer := range sorted {
+		keys.coefs[i] = keys.hashScalar("musig2/keyagg", listHash, signer)
+		keys.agg = keys.add(keys.agg, keys.mul(keys.points[i], keys.coefs[i]))
+	}
+	if keys.agg == nil {
+		return nil, errors.New("aggregate key is the point at infinity")
+	}
+	return keys, nil
+}
+
+// index returns the position of a signer in the set, -1 when it is not part
+// of it
+func (k *musigKeys) index(signer PublicKey) int {
+	for i, key := range k.signers {
+		if bytes.Equal(key, signer) {
+			return i
+		}
+	}
+	return -1
+}
+
+// musigSession signs a message by a signer set. Each signer commits to two
+// nonce points; the nonce of the signature is the first sum plus the
+// second weighted by a hash of both, which keeps concurrent sessions safe.
+type musigSession struct {
+	mu       sync.Mutex
+	keys     *musigKeys
+	msg      []byte
+	nonces   [][2]*point
+	secrets  map[int][2]*big.Int // Nonces of the signers signing in process
+	partials []*big.Int
+
+	// Set once every signer committed
+	b *big.Int
+	r *point
+	c *big.Int
+}
+
+func (s *musigSession) signer(key PublicKey) (int, error) {
+	i := s.keys.index(key)
+	if i < 0 {
+		return -1, fmt.Errorf("%s is not a signer of the session", key)
+	}
+	return i, nil
+}
+
+// Commit implements AggregateSession
+func (s *musigSession) Commit(key PrivateKey) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.signer(key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	if s.nonces[i][0] != nil {
+		return nil, errors.New("signer already committed")
+	}
+
+	var secret [2]*big.Int
+	for j := range secret {
+		if secret[j], err = s.keys.randomScalar(); err != nil {
+			return nil, err
+		}
+		s.nonces[i][j] = s.keys.baseMul(secret[j])
+	}
+	s.secrets[i] = secret
+
+	return append(s.keys.compress(s.nonces[i][0]), s.keys.compress(s.nonces[i][1])...), nil
+}
+
+// AddCommitment implements AggregateSession
+func (s *musigSession) AddCommitment(signer PublicKey, commitment []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.signer(signer)
+	if err != nil {
+		return err
+	}
+	if s.nonces[i][0] != nil {
+		return errors.New("signer already committed")
+	}
+	nonces, err := parseNonceCommitment(s.keys.group, commitment)
+	if err != nil {
+		return err
+	}
+	s.nonces[i] = nonces
+	return nil
+}
+
+// parseNonceCommitment reads the two 33 byte nonce points a signer commits
+// to
+func parseNonceCommitment(g group, commitment []byte) ([2]*point, error) {
+	var nonces [2]*point
+	if len(commitment) != 66 {
+		return nonces, errors.New("nonce commitment must be two 33 byte points")
+	}
+	for j := range nonces {
+		var err error
+		if nonces[j], err = g.parsePoint(commitment[j*33 : (j+1)*33]); err != nil {
+			return nonces, errors.New("invalid nonce commitment")
+		}
+	}
+	return nonces, nil
+}
+
+// challenge derives the nonce and challenge of the signature once every
+// signer committed
+func (s *musigSession) challenge() error {
+	if s.c != nil {
+		return nil
+	}
+
+	var r1, r2 *point
+	for i, nonces := range s.nonces {
+		if nonces[0] == nil {
+			return fmt.Errorf("waiting for the commitment of %s", s.keys.signers[i])
+		}
+		r1 = s.keys.add(r1, nonces[0])
+		r2 = s.keys.add(r2, nonces[1])
+	}
+
+	agg := s.keys.compress(s.keys.agg)
+	b := s.keys.hashScalar("musig2/noncecoef", agg, s.keys.compress(r1), s.keys.compress(r2), s.msg)
+	r := s.keys.add(r1, s.keys.mul(r2, b))
+	if r == nil {
+		return errors.New("session nonce is the point at infinity")
+	}
+
+	s.b, s.r = b, r
+	s.c = s.keys.hashScalar("musig2/challenge", s.keys.compress(r), agg, s.msg)
+	return nil
+}
+
+// Sign implements AggregateSession. The nonces of key are forgotten once
+// used so they can never sign twice.
+func (s *musigSession) Sign(key PrivateKey) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.signer(key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	secret, exists := s.secrets[i]
+	if !exists {
+		return nil, errors.New("signer has no nonces in this session, commit first")
+	}
+	if err := s.challenge(); err != nil {
+		return nil, err
+	}
+
+	partial := new(big.Int).Mul(s.b, secret[1])
+	partial.Add(partial, secret[0])
+	weight := new(big.Int).Mul(s.c, s.keys.coefs[i])
+	partial.Add(partial, weight.Mul(weight, key.key.D))
+	partial.Mod(partial, s.keys.n())
+
+	delete(s.secrets, i)
+	s.partials[i] = partial
+	return scalarBytes(partial), nil
+}
+
+// AddPartial implements AggregateSession
+func (s *musigSession) AddPartial(signer PublicKey, partial []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.signer(signer)
+	if err != nil {
+		return err
+	}
+	if s.partials[i] != nil {
+		return errors.New("signer already signed")
+	}
+	if err := s.challenge(); err != nil {
+		return err
+	}
+	if len(partial) != 32 {
+		return errors.New("musig2 partial signature must be 32 bytes")
+	}
+	value := new(big.Int).SetBytes(partial)
+	if value.Cmp(s.keys.n()) >= 0 {
+		return errors.New("musig2 partial signature is out of range")
+	}
+
+	// s_i G = R_i1 + b R_i2 + c a_i P_i
+	expected := s.keys.add(s.nonces[i][0], s.keys.mul(s.nonces[i][1], s.b))
+	expected = s.keys.add(expected, s.keys.mul(s.keys.points[i], new(big.Int).Mul(s.c, s.keys.coefs[i])))
+	if !s.keys.baseMul(value).equal(expected) {
+		return fmt.Errorf("invalid partial signature from %s", signer)
+	}
+
+	s.partials[i] = value
+	return nil
+}
+
+// Signature implements AggregateSession
+func (s *musigSession) Signature() (*AggregateSignature, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.challenge(); err != nil {
+		return nil, err
+	}
+	sum := new(big.Int)
+	for i, partial := range s.partials {
+		if partial == nil {
+			return nil, fmt.Errorf("waiting for the partial signature of %s", s.keys.signers[i])
+		}
+		sum.Add(sum, partial)
+	}
+	sum.Mod(sum, s.keys.n())
+
+	signers := make([]PublicKey, len(s.keys.signers))
+	copy(signers, s.keys.signers)
+	return &AggregateSignature{
+		Scheme:  AggregateSchemeMuSig2,
+		Signers: signers,
+		Data:    append(s.keys.compress(s.r), scalarBytes(sum)...),
+	}, nil
+}
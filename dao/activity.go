@@ -0,0 +1,591 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Activity types used to label entries in the transaction history index
+const (
+	ActivityTypeProposal            = "proposal"
+	ActivityTypeVote                = "vote"
+	ActivityTypeDelegation          = "delegation"
+	ActivityTypeTreasury            = "treasury"
+	ActivityTypeTokenMint           = "token_mint"
+	ActivityTypeTokenBurn           = "token_burn"
+	ActivityTypeTokenTransfer       = "token_transfer"
+	ActivityTypeTokenApprove        = "token_approve"
+	ActivityTypeTokenTransferFrom   = "token_transfer_from"
+	ActivityTypeParameter           = "parameter"
+	ActivityTypeDistribution        = "token_distribution"
+	ActivityTypeVestingClaim        = "vesting_claim"
+	ActivityTypeStake               = "stake"
+	ActivityTypeUnstake             = "unstake"
+	ActivityTypeClaimRewards        = "claim_rewards"
+	ActivityTypePositionTransfer    = "position_transfer"
+	ActivityTypeDispute             = "dispute"
+	ActivityTypeDisputeEvidence     = "dispute_evidence"
+	ActivityTypeJurorCommit         = "juror_commit"
+	ActivityTypeJurorReveal         = "juror_reveal"
+	ActivityTypeFundingKPI          = "funding_kpi"
+	ActivityTypeImpactReview        = "impact_review"
+	ActivityTypeValidatorConfig     = "validator_config"
+	ActivityTypeClaimCommission     = "claim_commission"
+	ActivityTypeValidatorSet        = "validator_set"
+	ActivityTypeValidatorSetExecute = "validator_set_execute"
+	ActivityTypeMultisigCreate      = "multisig_create"
+	ActivityTypeMultisigSubmit      = "multisig_submit"
+	ActivityTypeMultisigSign        = "multisig_sign"
+	ActivityTypeSubDAOCreate        = "subdao_create"
+	ActivityTypeSubDAOCreateExecute = "subdao_create_execute"
+	ActivityTypeSubDAOProposal      = "subdao_proposal"
+	ActivityTypeSubDAOVote          = "subdao_vote"
+	ActivityTypeGrantProposal       = "grant_proposal"
+	ActivityTypeGrantExecute        = "grant_execute"
+	ActivityTypeGrantSubmit         = "grant_milestone_submit"
+	ActivityTypeGrantReview         = "grant_milestone_review"
+	ActivityTypeGrantCancel         = "grant_cancel"
+	ActivityTypeBountyProposal      = "bounty_proposal"
+	ActivityTypeBountyPost          = "bounty_post"
+	ActivityTypeBountyClaim         = "bounty_claim"
+	ActivityTypeBountySubmit        = "bounty_submit"
+	ActivityTypeBountyReview        = "bounty_review"
+	ActivityTypeBountyCancel        = "bounty_cancel"
+	ActivityTypeQFRoundProposal     = "qf_round_proposal"
+	ActivityTypeQFRoundOpen         = "qf_round_open"
+	ActivityTypeQFContribute        = "qf_contribute"
+	ActivityTypeQFPayout            = "qf_payout"
+	ActivityTypeOptimisticProposal  = "optimistic_proposal"
+	ActivityTypeOptimisticChallenge = "optimistic_challenge"
+	ActivityTypeRPGFRoundProposal   = "rpgf_round_proposal"
+	ActivityTypeRPGFRoundOpen       = "rpgf_round_open"
+	ActivityTypeRPGFNominate        = "rpgf_nominate"
+	ActivityTypeRPGFBallot          = "rpgf_ballot"
+	ActivityTypeRPGFFinalize        = "rpgf_finalize"
+	ActivityTypeRPGFClaim           = "rpgf_claim"
+	ActivityTypeOracleFeedProposal  = "oracle_feed_proposal"
+	ActivityTypeOracleFeedActivate  = "oracle_feed_activate"
+	ActivityTypeOracleReport        = "oracle_report"
+	ActivityTypeTrackProposal       = "track_proposal"
+	ActivityTypeTrackActivate       = "track_activate"
+	ActivityTypeParameterSchedule   = "parameter_schedule"
+	ActivityTypeConstitutionAmend   = "constitution_amend"
+	ActivityTypeConstitutionEnact   = "constitution_enact"
+	ActivityTypeEmergencySpend      = "emergency_spend"
+	ActivityTypeEmergencyExecute    = "emergency_execute"
+	ActivityTypeParticipationOptIn  = "participation_opt_in"
+	ActivityTypeEndorseProposal     = "endorse_proposal"
+	ActivityTypePrivacySettings     = "privacy_settings"
+	ActivityTypeNameRegister        = "name_register"
+	ActivityTypeNameRenew           = "name_renew"
+	ActivityTypeNameTransfer        = "name_transfer"
+	ActivityTypeProfileUpdate       = "profile_update"
+	ActivityTypeDelegateStatement   = "delegate_statement"
+	ActivityTypeComment             = "comment"
+	ActivityTypeJoinRequest         = "join_request"
+	ActivityTypeJoinApproval        = "join_approval"
+	ActivityTypeMembershipStatus    = "membership_status"
+	ActivityTypeRageQuit            = "rage_quit"
+	ActivityTypeDAOCreate           = "dao_create"
+	ActivityTypeUnknown             = "unknown"
+)
+
+// Activity roles describe how an address took part in a transaction
+const (
+	ActivityRoleSender    = "sender"
+	ActivityRoleRecipient = "recipient"
+	ActivityRoleDelegate  = "delegate"
+	ActivityRoleSpender   = "spender"
+	ActivityRoleOwner     = "owner"
+)
+
+// ActivityRecord represents a single DAO transaction seen from one address
+type ActivityRecord struct {
+	TxHash       types.Hash
+	TxType       string
+	Role         string
+	Counterparty string
+	Amount       uint64
+	Fee          uint64 // Paid by the sender
+	BlockHeight  uint32
+	Timestamp    int64
+}
+
+// ActivityIndex keeps a per-address history of processed DAO transactions
+type ActivityIndex struct {
+	mu      sync.RWMutex
+	records map[string][]*ActivityRecord
+}
+
+// NewActivityIndex creates a new, empty activity index
+func NewActivityIndex() *ActivityIndex {
+	return &ActivityIndex{
+		records: make(map[string][]*ActivityRecord),
+	}
+}
+
+// ActivityTypeOf returns the activity type label for a DAO transaction
+func ActivityTypeOf(txInner interface{}) string {
+	switch tx := txInner.(type) {
+	case *ProposalTx:
+		return ActivityTypeProposal
+	case *VoteTx:
+		return ActivityTypeVote
+	case *DelegationTx:
+		return ActivityTypeDelegation
+	case *TreasuryTx:
+		return ActivityTypeTreasury
+	case *TokenMintTx:
+		return ActivityTypeTokenMint
+	case *TokenBurnTx:
+		return ActivityTypeTokenBurn
+	case *TokenTransferTx:
+		return ActivityTypeTokenTransfer
+	case *TokenApproveTx:
+		return ActivityTypeTokenApprove
+	case *TokenTransferFromTx:
+		return ActivityTypeTokenTransferFrom
+	case *ParameterProposalTx:
+		return ActivityTypeParameter
+	case *TokenDistributionTx:
+		return ActivityTypeDistribution
+	case *VestingClaimTx:
+		return ActivityTypeVestingClaim
+	case *StakeTx:
+		return ActivityTypeStake
+	case *UnstakeTx:
+		return ActivityTypeUnstake
+	case *ClaimRewardsTx:
+		return ActivityTypeClaimRewards
+	case *PositionTransferTx:
+		return ActivityTypePositionTransfer
+	case *DisputeTx:
+		return ActivityTypeDispute
+	case *DisputeEvidenceTx:
+		return ActivityTypeDisputeEvidence
+	case *JurorCommitTx:
+		return ActivityTypeJurorCommit
+	case *JurorRevealTx:
+		return ActivityTypeJurorReveal
+	case *FundingKPITx:
+		return ActivityTypeFundingKPI
+	case *ImpactReviewTx:
+		return ActivityTypeImpactReview
+	case *ValidatorConfigTx:
+		return ActivityTypeValidatorConfig
+	case *ClaimCommissionTx:
+		return ActivityTypeClaimCommission
+	case *ValidatorSetProposalTx:
+		return ActivityTypeValidatorSet
+	case *ValidatorSetExecuteTx:
+		return ActivityTypeValidatorSetExecute
+	case *MultisigCreateTx:
+		return ActivityTypeMultisigCreate
+	case *MultisigSubmitTx:
+		return ActivityTypeMultisigSubmit
+	case *MultisigSignTx:
+		return ActivityTypeMultisigSign
+	case *SubDAOCreateProposalTx:
+		return ActivityTypeSubDAOCreate
+	case *SubDAOCreateExecuteTx:
+		return ActivityTypeSubDAOCreateExecute
+	case *SubDAOProposalTx:
+		return ActivityTypeSubDAOProposal
+	case *SubDAOVoteTx:
+		return ActivityTypeSubDAOVote
+	case *GrantProposalTx:
+		return ActivityTypeGrantProposal
+	case *GrantExecuteTx:
+		return ActivityTypeGrantExecute
+	case *GrantMilestoneSubmitTx:
+		return ActivityTypeGrantSubmit
+	case *GrantMilestoneReviewTx:
+		return ActivityTypeGrantReview
+	case *GrantCancelTx:
+		return ActivityTypeGrantCancel
+	case *BountyProposalTx:
+		return ActivityTypeBountyProposal
+	case *BountyPostTx:
+		return ActivityTypeBountyPost
+	case *BountyClaimTx:
+		return ActivityTypeBountyClaim
+	case *BountySubmitTx:
+		return ActivityTypeBountySubmit
+	case *BountyReviewTx:
+		return ActivityTypeBountyReview
+	case *BountyCancelTx:
+		return ActivityTypeBountyCancel
+	case *QFRoundProposalTx:
+		return ActivityTypeQFRoundProposal
+	case *QFRoundOpenTx:
+		return ActivityTypeQFRoundOpen
+	case *QFContributeTx:
+		return ActivityTypeQFContribute
+	case *QFPayoutTx:
+		return ActivityTypeQFPayout
+	case *OptimisticProposalTx:
+		return ActivityTypeOptimisticProposal
+	case *OptimisticChallengeTx:
+		return ActivityTypeOptimisticChallenge
+	case *RPGFRoundProposalTx:
+		return ActivityTypeRPGFRoundProposal
+	case *RPGFRoundOpenTx:
+		return ActivityTypeRPGFRoundOpen
+	case *RPGFNominateTx:
+		return ActivityTypeRPGFNominate
+	case *RPGFBallotTx:
+		return ActivityTypeRPGFBallot
+	case *RPGFFinalizeTx:
+		return ActivityTypeRPGFFinalize
+	case *RPGFClaimTx:
+		return ActivityTypeRPGFClaim
+	case *OracleFeedProposalTx:
+		return ActivityTypeOracleFeedProposal
+	case *OracleFeedActivateTx:
+		return ActivityTypeOracleFeedActivate
+	case *TrackProposalTx:
+		return ActivityTypeTrackProposal
+	case *TrackActivateTx:
+		return ActivityTypeTrackActivate
+	case *ParameterScheduleTx:
+		return ActivityTypeParameterSchedule
+	case *OracleReportTx:
+		return ActivityTypeOracleReport
+	case *ConstitutionAmendmentTx:
+		return ActivityTypeConstitutionAmend
+	case *ConstitutionEnactTx:
+		return ActivityTypeConstitutionEnact
+	case *EmergencySpendTx:
+		return ActivityTypeEmergencySpend
+	case *EmergencyExecuteTx:
+		return ActivityTypeEmergencyExecute
+	case *ParticipationOptInTx:
+		return ActivityTypeParticipationOptIn
+	case *EndorseProposalTx:
+		return ActivityTypeEndorseProposal
+	case *PrivacySettingsTx:
+		return ActivityTypePrivacySettings
+	case *NameRegisterTx:
+		return ActivityTypeNameRegister
+	case *NameRenewTx:
+		return ActivityTypeNameRenew
+	case *NameTransferTx:
+		return ActivityTypeNameTransfer
+	case *ProfileUpdateTx:
+		return ActivityTypeProfileUpdate
+	case *DelegateStatementTx:
+		return ActivityTypeDelegateStatement
+	case *CommentTx:
+		return ActivityTypeComment
+	case *JoinRequestTx:
+		return ActivityTypeJoinRequest
+	case *JoinApprovalTx:
+		return ActivityTypeJoinApproval
+	case *MembershipStatusTx:
+		return ActivityTypeMembershipStatus
+	case *RageQuitTx:
+		return ActivityTypeRageQuit
+	case *DAOCreateTx:
+		return ActivityTypeDAOCreate
+	case *HostedDAOTx:
+		return ActivityTypeOf(tx.Tx)
+	default:
+		return ActivityTypeUnknown
+	}
+}
+
+// RecordTransaction indexes a successfully processed DAO transaction under
+// the sender and every counterparty involved in it
+func (ai *ActivityIndex) RecordTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash, blockHeight uint32) {
+	txType := ActivityTypeOf(txInner)
+	now := time.Now().Unix()
+	fromStr := from.String()
+	fee := TxFee(txInner)
+
+	newRecord := func(role, counterparty string, amount uint64) *ActivityRecord {
+		record := &ActivityRecord{
+			TxHash:       txHash,
+			TxType:       txType,
+			Role:         role,
+			Counterparty: counterparty,
+			Amount:       amount,
+			BlockHeight:  blockHeight,
+			Timestamp:    now,
+		}
+		if role == ActivityRoleSender {
+			record.Fee = fee
+		}
+		return record
+	}
+
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	switch tx := txInner.(type) {
+	case *DelegationTx:
+		delegateStr := tx.Delegate.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, delegateStr, 0))
+		if len(tx.Delegate) > 0 && delegateStr != fromStr {
+			ai.append(delegateStr, newRecord(ActivityRoleDelegate, fromStr, 0))
+		}
+	case *TreasuryTx:
+		recipientStr := tx.Recipient.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, recipientStr, tx.Amount))
+		ai.append(recipientStr, newRecord(ActivityRoleRecipient, fromStr, tx.Amount))
+	case *TokenMintTx:
+		recipientStr := tx.Recipient.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, recipientStr, tx.Amount))
+		if recipientStr != fromStr {
+			ai.append(recipientStr, newRecord(ActivityRoleRecipient, fromStr, tx.Amount))
+		}
+	case *TokenBurnTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, "", tx.Amount))
+	case *RageQuitTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, "", tx.Amount))
+	case *TokenTransferTx:
+		recipientStr := tx.Recipient.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, recipientStr, tx.Amount))
+		if recipientStr != fromStr {
+			ai.append(recipientStr, newRecord(ActivityRoleRecipient, fromStr, tx.Amount))
+		}
+	case *TokenApproveTx:
+		spenderStr := tx.Spender.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, spenderStr, tx.Amount))
+		ai.append(spenderStr, newRecord(ActivityRoleSpender, fromStr, tx.Amount))
+	case *TokenTransferFromTx:
+		ownerStr := tx.From.String()
+		recipientStr := tx.Recipient.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, recipientStr, tx.Amount))
+		ai.append(ownerStr, newRecord(ActivityRoleOwner, recipientStr, tx.Amount))
+		if recipientStr != ownerStr {
+			ai.append(recipientStr, newRecord(ActivityRoleRecipient, ownerStr, tx.Amount))
+		}
+	case *TokenDistributionTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, "", 0))
+		for recipientStr, amount := range tx.Recipients {
+			ai.append(recipientStr, newRecord(ActivityRoleRecipient, fromStr, amount))
+		}
+	case *StakeTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.PoolID, tx.Amount))
+	case *UnstakeTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.PoolID, tx.Amount))
+	case *ClaimRewardsTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.PoolID, 0))
+	case *PositionTransferTx:
+		recipientStr := tx.Recipient.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, recipientStr, 0))
+		ai.append(recipientStr, newRecord(ActivityRoleRecipient, fromStr, 0))
+	case *VoteTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), tx.Weight))
+	case *DisputeTx:
+		respondentStr := tx.Respondent.String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, respondentStr, tx.Amount))
+		if len(tx.Respondent) > 0 {
+			ai.append(respondentStr, newRecord(ActivityRoleRecipient, fromStr, tx.Amount))
+		}
+	case *DisputeEvidenceTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.DisputeID.String(), 0))
+	case *JurorCommitTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.DisputeID.String(), 0))
+	case *JurorRevealTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.DisputeID.String(), 0))
+	case *FundingKPITx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), tx.Amount))
+	case *ImpactReviewTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *ValidatorConfigTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.PoolID, 0))
+	case *ValidatorSetProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Validator.String(), 0))
+	case *ValidatorSetExecuteTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *MultisigCreateTx:
+		accountStr := MultisigAccountKey(txHash).String()
+		ai.append(fromStr, newRecord(ActivityRoleSender, accountStr, 0))
+		for _, owner := range tx.Owners {
+			if ownerStr := owner.String(); ownerStr != fromStr {
+				ai.append(ownerStr, newRecord(ActivityRoleOwner, accountStr, 0))
+			}
+		}
+	case *MultisigSubmitTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.MultisigID.String(), 0))
+	case *MultisigSignTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.MultisigID.String(), 0))
+	case *SubDAOCreateProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Name, tx.Budget))
+	case *SubDAOCreateExecuteTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *SubDAOProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.SubDAOID.String(), tx.Amount))
+	case *SubDAOVoteTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *GrantProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Committee.String(), tx.Total()))
+	case *GrantExecuteTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *GrantMilestoneSubmitTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.GrantID.String(), 0))
+	case *GrantMilestoneReviewTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.GrantID.String(), 0))
+	case *GrantCancelTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.GrantID.String(), 0))
+	case *BountyProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Reviewer.String(), tx.Reward))
+	case *BountyPostTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *BountyClaimTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.BountyID.String(), 0))
+	case *BountySubmitTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.BountyID.String(), 0))
+	case *BountyReviewTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.BountyID.String(), 0))
+	case *BountyCancelTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.BountyID.String(), 0))
+	case *QFRoundProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Title, tx.MatchingPool))
+	case *QFRoundOpenTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *QFContributeTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.RoundID.String(), tx.Amount))
+	case *QFPayoutTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.RoundID.String(), 0))
+	case *OptimisticProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Title, 0))
+	case *OptimisticChallengeTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *RPGFRoundProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Title, tx.Budget))
+	case *RPGFRoundOpenTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *RPGFNominateTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.RoundID.String(), 0))
+		ai.append(tx.Recipient.String(), newRecord(ActivityRoleRecipient, tx.RoundID.String(), 0))
+	case *RPGFBallotTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.RoundID.String(), 0))
+	case *RPGFFinalizeTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.RoundID.String(), 0))
+	case *RPGFClaimTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.RoundID.String(), 0))
+	case *OracleFeedProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.FeedID, 0))
+	case *OracleFeedActivateTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *OracleReportTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.FeedID, 0))
+	case *TrackProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Track.ID, 0))
+	case *TrackActivateTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *ParameterScheduleTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, txHash.String(), 0))
+	case *ConstitutionAmendmentTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.DocumentID, 0))
+	case *ConstitutionEnactTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *EmergencySpendTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Recipient.String(), tx.Amount))
+	case *EmergencyExecuteTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *EndorseProposalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *NameTransferTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Recipient.String(), 0))
+		ai.append(tx.Recipient.String(), newRecord(ActivityRoleRecipient, fromStr, 0))
+	case *CommentTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.ProposalID.String(), 0))
+	case *JoinApprovalTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Applicant.String(), 0))
+		ai.append(tx.Applicant.String(), newRecord(ActivityRoleRecipient, fromStr, 0))
+	case *MembershipStatusTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.Member.String(), 0))
+		if tx.Member.String() != fromStr {
+			ai.append(tx.Member.String(), newRecord(ActivityRoleRecipient, fromStr, 0))
+		}
+	case *DAOCreateTx:
+		ai.append(fromStr, newRecord(ActivityRoleSender, tx.DAOID, 0))
+	default:
+		ai.append(fromStr, newRecord(ActivityRoleSender, "", 0))
+	}
+}
+
+// SetClaimedAmount sets the amount of a claim the sender made, which the
+// claim transaction itself does not carry
+func (ai *ActivityIndex) SetClaimedAmount(address string, txHash types.Hash, amount uint64) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	history := ai.records[address]
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].TxHash == txHash && history[i].Role == ActivityRoleSender {
+			history[i].Amount = amount
+			return
+		}
+	}
+}
+
+// append adds a record to an address history; callers must hold the lock
+func (ai *ActivityIndex) append(address string, record *ActivityRecord) {
+	ai.records[address] = append(ai.records[address], record)
+}
+
+// GetActivity returns the newest-first activity of an address, optionally
+// filtered by transaction types, together with the total number of matches
+func (ai *ActivityIndex) GetActivity(address string, txTypes []string, offset, limit int) ([]*ActivityRecord, int) {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	filter := make(map[string]bool, len(txTypes))
+	for _, txType := range txTypes {
+		filter[txType] = true
+	}
+
+	history := ai.records[address]
+	matched := make([]*ActivityRecord, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		if len(filter) > 0 && !filter[history[i].TxType] {
+			continue
+		}
+		matched = append(matched, history[i])
+	}
+
+	total := len(matched)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total {
+		return []*ActivityRecord{}, total
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	return matched[offset:end], total
+}
+
+// GetActivityBetween returns copies of the records of an address with a
+// timestamp in [from, to), oldest first
+func (ai *ActivityIndex) GetActivityBetween(address string, from, to int64) []ActivityRecord {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	records := make([]ActivityRecord, 0)
+	for _, record := range ai.records[address] {
+		if record.Timestamp >= from && record.Timestamp < to {
+			records = append(records, *record)
+		}
+	}
+	return records
+}
+
+// GetActivityCount returns the number of indexed records for an address
+func (ai *ActivityIndex) GetActivityCount(address string) int {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	return len(ai.records[address])
+}
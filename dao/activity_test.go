@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityIndex_RecordTransfer(t *testing.T) {
+	index := NewActivityIndex()
+
+	sender := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	tx := &TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 500}
+	txHash := randomTreasuryHash()
+	index.RecordTransaction(tx, sender, txHash, 7)
+
+	senderHistory, total := index.GetActivity(sender.String(), nil, 0, 0)
+	require.Equal(t, 1, total)
+	assert.Equal(t, ActivityTypeTokenTransfer, senderHistory[0].TxType)
+	assert.Equal(t, ActivityRoleSender, senderHistory[0].Role)
+	assert.Equal(t, recipient.String(), senderHistory[0].Counterparty)
+	assert.Equal(t, uint64(500), senderHistory[0].Amount)
+	assert.Equal(t, uint32(7), senderHistory[0].BlockHeight)
+	assert.Equal(t, txHash, senderHistory[0].TxHash)
+
+	recipientHistory, total := index.GetActivity(recipient.String(), nil, 0, 0)
+	require.Equal(t, 1, total)
+	assert.Equal(t, ActivityRoleRecipient, recipientHistory[0].Role)
+	assert.Equal(t, sender.String(), recipientHistory[0].Counterparty)
+}
+
+func TestActivityIndex_FilterAndPagination(t *testing.T) {
+	index := NewActivityIndex()
+
+	member := crypto.GeneratePrivateKey().PublicKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+
+	for i := 0; i < 5; i++ {
+		index.RecordTransaction(&TokenTransferTx{Recipient: other, Amount: uint64(i + 1)}, member, randomTreasuryHash(), uint32(i))
+	}
+	index.RecordTransaction(&VoteTx{ProposalID: randomTreasuryHash(), Choice: VoteChoiceYes, Weight: 10}, member, randomTreasuryHash(), 5)
+
+	assert.Equal(t, 6, index.GetActivityCount(member.String()))
+
+	// Newest first
+	records, total := index.GetActivity(member.String(), nil, 0, 2)
+	assert.Equal(t, 6, total)
+	require.Len(t, records, 2)
+	assert.Equal(t, ActivityTypeVote, records[0].TxType)
+	assert.Equal(t, uint64(5), records[1].Amount)
+
+	// Filter by type
+	records, total = index.GetActivity(member.String(), []string{ActivityTypeTokenTransfer}, 3, 10)
+	assert.Equal(t, 5, total)
+	require.Len(t, records, 2)
+	assert.Equal(t, uint64(2), records[0].Amount)
+	assert.Equal(t, uint64(1), records[1].Amount)
+
+	// Offset past the end
+	records, total = index.GetActivity(member.String(), nil, 10, 10)
+	assert.Equal(t, 6, total)
+	assert.Empty(t, records)
+}
+
+func TestDAO_ProcessTransactionRecordsActivity(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	sender := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	err := dao.InitialTokenDistribution(map[string]uint64{
+		sender.String(): 10000,
+	})
+	require.NoError(t, err)
+
+	tx := &TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 1000}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, sender, randomTreasuryHash()))
+
+	records, total := dao.GetMemberActivity(recipient, nil, 0, 10)
+	require.Equal(t, 1, total)
+	assert.Equal(t, ActivityTypeTokenTransfer, records[0].TxType)
+
+	// Failed transactions are not indexed
+	badTx := &TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 1000000}
+	assert.Error(t, dao.ProcessDAOTransaction(badTx, sender, randomTreasuryHash()))
+
+	_, total = dao.GetMemberActivity(sender, nil, 0, 10)
+	assert.Equal(t, 1, total)
+}
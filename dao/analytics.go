@@ -4,12 +4,16 @@ import (
 	"math"
 	"sort"
 	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
 )
 
 // AnalyticsSystem provides comprehensive analytics and reporting for DAO operations
 type AnalyticsSystem struct {
-	governanceState *GovernanceState
-	tokenState      *GovernanceToken
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	oracles          *OracleManager    // Values the treasury, nil disables valuations
+	parameterManager *ParameterManager // Burn window and runway thresholds, defaults when nil
 }
 
 // NewAnalyticsSystem creates a new analytics system instance
@@ -80,6 +84,9 @@ type TreasuryPerformanceMetrics struct {
 	CurrentBalance         uint64              `json:"current_balance"`
 	TotalInflows           uint64              `json:"total_inflows"`
 	TotalOutflows          uint64              `json:"total_outflows"`
+	YieldDistributed       uint64              `json:"yield_distributed"`
+	FeesSponsored          uint64              `json:"fees_sponsored"`
+	ParticipationRewards   uint64              `json:"participation_rewards"`
 	NetFlow                int64               `json:"net_flow"`
 	TransactionCount       uint64              `json:"transaction_count"`
 	AverageTransactionSize uint64              `json:"average_transaction_size"`
@@ -92,6 +99,14 @@ type TreasuryPerformanceMetrics struct {
 	PendingTransactions    uint64              `json:"pending_transactions"`
 	ExecutedTransactions   uint64              `json:"executed_transactions"`
 	ExpiredTransactions    uint64              `json:"expired_transactions"`
+
+	// Runway at the burn rate of the transactions executed in the burn
+	// window, unset while nothing is spent
+	BurnRate        uint64                   `json:"burn_rate"` // Tokens per month
+	BurnWindow      int64                    `json:"burn_window"`
+	RunwayMonths    *float64                 `json:"runway_months,omitempty"`
+	RunwayAlert     *TreasuryAlert           `json:"runway_alert,omitempty"`
+	AssetValuations []TreasuryAssetValuation `json:"asset_valuations"`
 }
 
 // TreasuryFlowPoint represents treasury flow data at a specific time
@@ -116,6 +131,7 @@ type ProposalAnalytics struct {
 	VotingPatternsByType  map[ProposalType]VotingPattern `json:"voting_patterns_by_type"`
 	TimeToResolution      []ResolutionTimePoint          `json:"time_to_resolution"`
 	PopularityMetrics     ProposalPopularityMetrics      `json:"popularity_metrics"`
+	TurnoutSegments       TurnoutSegmentation            `json:"turnout_segments"` // Over the votes on every proposal
 }
 
 // VotingPattern represents voting behavior for a specific category
@@ -176,6 +192,35 @@ type RiskIndicator struct {
 	Mitigation  string  `json:"mitigation"`
 }
 
+// SubDAOAnalytics rolls the sub-DAOs up into one report
+type SubDAOAnalytics struct {
+	TotalSubDAOs  uint64          `json:"total_subdaos"`
+	TotalMembers  uint64          `json:"total_members"`
+	TotalBudget   uint64          `json:"total_budget"`
+	TotalStreamed uint64          `json:"total_streamed"`
+	TotalSpent    uint64          `json:"total_spent"`
+	TotalBalance  uint64          `json:"total_balance"`
+	SubDAOs       []SubDAOSummary `json:"subdaos"`
+}
+
+// SubDAOSummary reports the budget and proposals of one sub-DAO
+type SubDAOSummary struct {
+	ID                string  `json:"id"`
+	Name              string  `json:"name"`
+	Members           uint64  `json:"members"`
+	Budget            uint64  `json:"budget"`
+	Vested            uint64  `json:"vested"`
+	Streamed          uint64  `json:"streamed"`
+	Spent             uint64  `json:"spent"`
+	Balance           uint64  `json:"balance"`
+	Proposals         uint64  `json:"proposals"`
+	ActiveProposals   uint64  `json:"active_proposals"`
+	ExecutedProposals uint64  `json:"executed_proposals"`
+	RejectedProposals uint64  `json:"rejected_proposals"`
+	ExpiredProposals  uint64  `json:"expired_proposals"`
+	Utilization       float64 `json:"utilization"` // Spent share of the streamed funds
+}
+
 // GetGovernanceParticipationMetrics calculates comprehensive participation metrics
 func (as *AnalyticsSystem) GetGovernanceParticipationMetrics() *GovernanceParticipationMetrics {
 	metrics := &GovernanceParticipationMetrics{
@@ -185,8 +230,11 @@ func (as *AnalyticsSystem) GetGovernanceParticipationMetrics() *GovernancePartic
 		TimeSeriesData:  make([]ParticipationTimePoint, 0),
 	}
 
-	// Count proposals and analyze by type
-	for _, proposal := range as.governanceState.Proposals {
+	// Count proposals and analyze them with their votes
+	uniqueVoters := make(map[string]bool)
+	participantStats := make(map[string]*ParticipantStats)
+
+	as.governanceState.EachProposal(func(_ types.Hash, proposal *Proposal, votes map[string]*Vote) bool {
 		metrics.TotalProposals++
 		metrics.ProposalsByType[proposal.ProposalType]++
 		metrics.VotingByType[proposal.VotingType]++
@@ -194,13 +242,7 @@ func (as *AnalyticsSystem) GetGovernanceParticipationMetrics() *GovernancePartic
 		if proposal.Status == ProposalStatusActive {
 			metrics.ActiveProposals++
 		}
-	}
 
-	// Analyze votes and voting patterns
-	uniqueVoters := make(map[string]bool)
-	participantStats := make(map[string]*ParticipantStats)
-
-	for proposalID, votes := range as.governanceState.Votes {
 		for voterStr, vote := range votes {
 			metrics.TotalVotes++
 			uniqueVoters[voterStr] = true
@@ -216,13 +258,12 @@ func (as *AnalyticsSystem) GetGovernanceParticipationMetrics() *GovernancePartic
 			participantStats[voterStr].LastActivity = vote.Timestamp
 
 			// Check if this voter created the proposal
-			if proposal, exists := as.governanceState.Proposals[proposalID]; exists {
-				if proposal.Creator.String() == voterStr {
-					participantStats[voterStr].ProposalsCreated++
-				}
+			if proposal.Creator.String() == voterStr {
+				participantStats[voterStr].ProposalsCreated++
 			}
 		}
-	}
+		return true
+	})
 
 	metrics.UniqueVoters = uint64(len(uniqueVoters))
 
@@ -328,15 +369,20 @@ func (as *AnalyticsSystem) getDelegationAnalytics() DelegationAnalytics {
 func (as *AnalyticsSystem) GetTreasuryPerformanceMetrics() *TreasuryPerformanceMetrics {
 	metrics := &TreasuryPerformanceMetrics{
 		CurrentBalance:        as.governanceState.Treasury.Balance,
+		TotalInflows:          as.governanceState.Treasury.TotalInflows,
+		YieldDistributed:      as.governanceState.Treasury.YieldDistributed,
+		FeesSponsored:         as.governanceState.Treasury.FeesSponsored,
+		ParticipationRewards:  as.governanceState.Treasury.ParticipationRewards,
 		TransactionsByPurpose: make(map[string]uint64),
 		MonthlyFlows:          make([]TreasuryFlowPoint, 0),
+		AssetValuations:       make([]TreasuryAssetValuation, 0),
 	}
 
 	var totalTransactionSize uint64
 	var signingTimes []float64
 
 	// Analyze treasury transactions
-	for _, tx := range as.governanceState.Treasury.Transactions {
+	as.governanceState.EachTreasuryTransaction(func(_ types.Hash, tx *PendingTx) bool {
 		metrics.TransactionCount++
 
 		if tx.Executed {
@@ -369,7 +415,8 @@ func (as *AnalyticsSystem) GetTreasuryPerformanceMetrics() *TreasuryPerformanceM
 		} else {
 			metrics.PendingTransactions++
 		}
-	}
+		return true
+	})
 
 	// Calculate averages
 	if metrics.ExecutedTransactions > 0 {
@@ -389,9 +436,15 @@ func (as *AnalyticsSystem) GetTreasuryPerformanceMetrics() *TreasuryPerformanceM
 		metrics.SigningEfficiency = float64(metrics.ExecutedTransactions) / float64(metrics.TransactionCount) * 100
 	}
 
+	// Staking yield, sponsored voting fees and participation rewards leave
+	// the treasury as well
+	metrics.TotalOutflows += metrics.YieldDistributed + metrics.FeesSponsored + metrics.ParticipationRewards
+
 	// Calculate net flow
 	metrics.NetFlow = int64(metrics.TotalInflows) - int64(metrics.TotalOutflows)
 
+	as.treasuryRunway(metrics, time.Now().Unix())
+
 	return metrics
 }
 
@@ -414,9 +467,10 @@ func (as *AnalyticsSystem) GetProposalAnalytics() *ProposalAnalytics {
 	var quorumAchieved uint64
 	var totalParticipation float64
 	var participationCount uint64
+	var totalWeight uint64
 
 	// Analyze proposals
-	for _, proposal := range as.governanceState.Proposals {
+	as.governanceState.EachProposal(func(_ types.Hash, proposal *Proposal, votes map[string]*Vote) bool {
 		analytics.TotalProposals++
 		analytics.ProposalsByCreator[proposal.Creator.String()]++
 
@@ -449,11 +503,14 @@ func (as *AnalyticsSystem) GetProposalAnalytics() *ProposalAnalytics {
 		}
 
 		// Analyze voting patterns for this proposal
-		if votes, exists := as.governanceState.Votes[proposal.ID]; exists {
+		if votes != nil {
 			var yesVotes, noVotes, abstainVotes uint64
 			totalVotes := uint64(len(votes))
 
 			for _, vote := range votes {
+				totalWeight += vote.Weight
+				analytics.TurnoutSegments.add(as, proposal, vote)
+
 				switch vote.Choice {
 				case VoteChoiceYes:
 					yesVotes++
@@ -495,7 +552,10 @@ func (as *AnalyticsSystem) GetProposalAnalytics() *ProposalAnalytics {
 		}
 
 		typeStats[proposal.ProposalType] = stats
-	}
+		return true
+	})
+
+	analytics.TurnoutSegments.finish(totalWeight)
 
 	// Calculate success rate
 	if analytics.TotalProposals > 0 {
@@ -642,6 +702,54 @@ func (as *AnalyticsSystem) GetDAOHealthMetrics() *DAOHealthMetrics {
 	return health
 }
 
+// GetSubDAOAnalytics rolls up the budgets and proposals of the sub-DAOs at
+// time now
+func (as *AnalyticsSystem) GetSubDAOAnalytics(subDAOs *SubDAOManager, now int64) *SubDAOAnalytics {
+	analytics := &SubDAOAnalytics{
+		SubDAOs: make([]SubDAOSummary, 0),
+	}
+
+	for _, subDAO := range subDAOs.ListSubDAOs() {
+		summary := SubDAOSummary{
+			ID:       subDAO.ID.String(),
+			Name:     subDAO.Name,
+			Members:  uint64(len(subDAO.Members)),
+			Budget:   subDAO.Budget,
+			Vested:   subDAO.Vested(now),
+			Streamed: subDAO.Streamed,
+			Spent:    subDAO.Spent,
+			Balance:  subDAO.Balance(),
+		}
+		if subDAO.Streamed > 0 {
+			summary.Utilization = float64(subDAO.Spent) / float64(subDAO.Streamed)
+		}
+
+		for _, proposal := range subDAOs.GetProposals(subDAO.ID) {
+			summary.Proposals++
+			switch proposal.StatusAt(now) {
+			case SubDAOProposalActive:
+				summary.ActiveProposals++
+			case SubDAOProposalExecuted:
+				summary.ExecutedProposals++
+			case SubDAOProposalRejected:
+				summary.RejectedProposals++
+			case SubDAOProposalExpired:
+				summary.ExpiredProposals++
+			}
+		}
+
+		analytics.TotalSubDAOs++
+		analytics.TotalMembers += summary.Members
+		analytics.TotalBudget += summary.Budget
+		analytics.TotalStreamed += summary.Streamed
+		analytics.TotalSpent += summary.Spent
+		analytics.TotalBalance += summary.Balance
+		analytics.SubDAOs = append(analytics.SubDAOs, summary)
+	}
+
+	return analytics
+}
+
 // GetAnalyticsSummary provides a comprehensive analytics summary
 func (as *AnalyticsSystem) GetAnalyticsSummary() map[string]interface{} {
 	return map[string]interface{}{
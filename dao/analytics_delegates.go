@@ -0,0 +1,172 @@
+package dao
+
+import (
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxScorecardTrendPoints is how many of a delegate's latest votes the
+// voting-power trend covers
+const MaxScorecardTrendPoints = 20
+
+// DelegateScorecard summarizes how a delegate votes so token holders can pick
+// one informed
+type DelegateScorecard struct {
+	Address        string `json:"address"`
+	Delegators     uint64 `json:"delegators"`
+	DelegatedPower uint64 `json:"delegated_power"` // Balance of the active delegators
+	OwnPower       uint64 `json:"own_power"`
+	// ParticipationRate is the percent of the proposals opened since the
+	// delegate joined that they voted on
+	EligibleProposals uint64  `json:"eligible_proposals"`
+	VotesCast         uint64  `json:"votes_cast"`
+	ParticipationRate float64 `json:"participation_rate"`
+	// AlignmentRate is the percent of decided proposals where the delegate's
+	// yes or no matched the outcome
+	DecidedVotes  uint64  `json:"decided_votes"`
+	AlignedVotes  uint64  `json:"aligned_votes"`
+	AlignmentRate float64 `json:"alignment_rate"`
+	// AverageLatency is the mean number of seconds between a vote opening
+	// and the delegate voting
+	AverageLatency float64               `json:"average_latency"`
+	PowerTrend     []VotingPowerPoint    `json:"power_trend"`  // Oldest first
+	PowerChange    int64                 `json:"power_change"` // Weight of the latest vote less the first in the trend
+	Choices        map[VoteChoice]uint64 `json:"choices"`
+	LastVote       int64                 `json:"last_vote"`
+}
+
+// VotingPowerPoint is the weight a delegate cast on a proposal
+type VotingPowerPoint struct {
+	Timestamp  int64  `json:"timestamp"`
+	ProposalID string `json:"proposal_id"`
+	Weight     uint64 `json:"weight"`
+}
+
+// GetDelegateScorecards returns the scorecards of every address with active
+// delegators, most delegated power first
+func (as *AnalyticsSystem) GetDelegateScorecards(now int64) []*DelegateScorecard {
+	delegates := make(map[string]bool)
+	for _, delegation := range as.governanceState.Delegations {
+		if isActiveDelegation(delegation, now) {
+			delegates[delegation.Delegate.String()] = true
+		}
+	}
+
+	scorecards := make([]*DelegateScorecard, 0, len(delegates))
+	for delegate := range delegates {
+		scorecards = append(scorecards, as.GetDelegateScorecard(delegate, now))
+	}
+
+	sort.Slice(scorecards, func(i, j int) bool {
+		if scorecards[i].DelegatedPower != scorecards[j].DelegatedPower {
+			return scorecards[i].DelegatedPower > scorecards[j].DelegatedPower
+		}
+		return scorecards[i].Address < scorecards[j].Address
+	})
+	return scorecards
+}
+
+// GetDelegateScorecard returns the scorecard of an address, whether or not
+// anyone currently delegates to it
+func (as *AnalyticsSystem) GetDelegateScorecard(delegate string, now int64) *DelegateScorecard {
+	scorecard := &DelegateScorecard{
+		Address:    delegate,
+		OwnPower:   as.tokenState.Balances.Get(delegate),
+		PowerTrend: make([]VotingPowerPoint, 0),
+		Choices:    make(map[VoteChoice]uint64),
+	}
+
+	for delegator, delegation := range as.governanceState.Delegations {
+		if isActiveDelegation(delegation, now) && delegation.Delegate.String() == delegate {
+			scorecard.Delegators++
+			scorecard.DelegatedPower += as.tokenState.Balances.Get(delegator)
+		}
+	}
+
+	var joinedAt int64
+	if holder, exists := as.governanceState.TokenHolders[delegate]; exists {
+		joinedAt = holder.JoinedAt
+	}
+
+	var latency int64
+	as.governanceState.EachProposal(func(id types.Hash, proposal *Proposal, votes map[string]*Vote) bool {
+		vote, voted := votes[delegate]
+		if !voted {
+			if proposal.Status == ProposalStatusCancelled || proposal.StartTime > now || proposal.StartTime < joinedAt {
+				return true
+			}
+			scorecard.EligibleProposals++
+			return true
+		}
+
+		scorecard.EligibleProposals++
+		scorecard.VotesCast++
+		scorecard.Choices[vote.Choice]++
+		if vote.Timestamp > proposal.StartTime {
+			latency += vote.Timestamp - proposal.StartTime
+		}
+		if vote.Timestamp > scorecard.LastVote {
+			scorecard.LastVote = vote.Timestamp
+		}
+		scorecard.PowerTrend = append(scorecard.PowerTrend, VotingPowerPoint{
+			Timestamp:  vote.Timestamp,
+			ProposalID: id.String(),
+			Weight:     vote.Weight,
+		})
+
+		if aligned, decided := voteAlignment(vote.Choice, proposal.Status); decided {
+			scorecard.DecidedVotes++
+			if aligned {
+				scorecard.AlignedVotes++
+			}
+		}
+		return true
+	})
+
+	if scorecard.EligibleProposals > 0 {
+		scorecard.ParticipationRate = float64(scorecard.VotesCast) / float64(scorecard.EligibleProposals) * 100
+	}
+	if scorecard.DecidedVotes > 0 {
+		scorecard.AlignmentRate = float64(scorecard.AlignedVotes) / float64(scorecard.DecidedVotes) * 100
+	}
+	if scorecard.VotesCast > 0 {
+		scorecard.AverageLatency = float64(latency) / float64(scorecard.VotesCast)
+	}
+
+	sort.Slice(scorecard.PowerTrend, func(i, j int) bool {
+		if scorecard.PowerTrend[i].Timestamp != scorecard.PowerTrend[j].Timestamp {
+			return scorecard.PowerTrend[i].Timestamp < scorecard.PowerTrend[j].Timestamp
+		}
+		return scorecard.PowerTrend[i].ProposalID < scorecard.PowerTrend[j].ProposalID
+	})
+	if len(scorecard.PowerTrend) > MaxScorecardTrendPoints {
+		scorecard.PowerTrend = scorecard.PowerTrend[len(scorecard.PowerTrend)-MaxScorecardTrendPoints:]
+	}
+	if trend := scorecard.PowerTrend; len(trend) > 1 {
+		scorecard.PowerChange = int64(trend[len(trend)-1].Weight) - int64(trend[0].Weight)
+	}
+
+	return scorecard
+}
+
+// isActiveDelegation reports whether delegation is in effect at now
+func isActiveDelegation(delegation *Delegation, now int64) bool {
+	return delegation.Active && now >= delegation.StartTime && now <= delegation.EndTime
+}
+
+// voteAlignment reports whether a vote matched the outcome of its proposal,
+// and whether the proposal was decided at all. Abstentions are not counted.
+func voteAlignment(choice VoteChoice, status ProposalStatus) (aligned, decided bool) {
+	if choice != VoteChoiceYes && choice != VoteChoiceNo {
+		return false, false
+	}
+	switch status {
+	case ProposalStatusPassed, ProposalStatusExecuted:
+		return choice == VoteChoiceYes, true
+	case ProposalStatusRejected:
+		return choice == VoteChoiceNo, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegateScorecard(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	delegate := crypto.GeneratePrivateKey().PublicKey()
+	delegators := []crypto.PublicKey{crypto.GeneratePrivateKey().PublicKey(), crypto.GeneratePrivateKey().PublicKey()}
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		delegate.String():      1000,
+		delegators[0].String(): 3000,
+		delegators[1].String(): 2000,
+	}))
+
+	now := int64(1700000000)
+	dao.GovernanceState.TokenHolders[delegate.String()].JoinedAt = now - 10000
+	for i, delegator := range delegators {
+		dao.GovernanceState.Delegations[delegator.String()] = &Delegation{
+			Delegator: delegator, Delegate: delegate, StartTime: now - 100, EndTime: now + 100, Active: i == 0,
+		}
+	}
+
+	proposals := []struct {
+		status ProposalStatus
+		start  int64
+		choice VoteChoice
+		weight uint64
+		voted  bool
+	}{
+		{ProposalStatusPassed, now - 9000, VoteChoiceYes, 1000, true},     // Aligned
+		{ProposalStatusRejected, now - 8000, VoteChoiceYes, 2000, true},   // Not aligned
+		{ProposalStatusActive, now - 7000, VoteChoiceAbstain, 4000, true}, // Undecided
+		{ProposalStatusRejected, now - 6000, 0, 0, false},                 // Missed
+		{ProposalStatusPassed, now - 20000, 0, 0, false},                  // Before joining
+		{ProposalStatusCancelled, now - 5000, 0, 0, false},
+	}
+	for i, p := range proposals {
+		id := types.Hash{byte(i + 1)}
+		dao.GovernanceState.Proposals[id] = &Proposal{ID: id, Status: p.status, StartTime: p.start, EndTime: p.start + 5000}
+		if p.voted {
+			dao.GovernanceState.Votes[id] = map[string]*Vote{
+				delegate.String(): {Voter: delegate, Choice: p.choice, Weight: p.weight, Timestamp: p.start + 600},
+			}
+		}
+	}
+
+	scorecard := dao.AnalyticsSystem.GetDelegateScorecard(delegate.String(), now)
+	assert.Equal(t, uint64(1), scorecard.Delegators)
+	assert.Equal(t, uint64(3000), scorecard.DelegatedPower)
+	assert.Equal(t, uint64(1000), scorecard.OwnPower)
+	assert.Equal(t, uint64(4), scorecard.EligibleProposals)
+	assert.Equal(t, uint64(3), scorecard.VotesCast)
+	assert.Equal(t, 75.0, scorecard.ParticipationRate)
+	assert.Equal(t, uint64(2), scorecard.DecidedVotes)
+	assert.Equal(t, 50.0, scorecard.AlignmentRate)
+	assert.Equal(t, 600.0, scorecard.AverageLatency)
+	require.Len(t, scorecard.PowerTrend, 3)
+	assert.Equal(t, uint64(1000), scorecard.PowerTrend[0].Weight)
+	assert.Equal(t, int64(3000), scorecard.PowerChange)
+	assert.Equal(t, now-7000+600, scorecard.LastVote)
+
+	scorecards := dao.AnalyticsSystem.GetDelegateScorecards(now)
+	require.Len(t, scorecards, 1)
+	assert.Equal(t, delegate.String(), scorecards[0].Address)
+
+	// Expired delegations no longer count
+	assert.Empty(t, dao.AnalyticsSystem.GetDelegateScorecards(now+200))
+}
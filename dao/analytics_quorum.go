@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// AdaptiveQuorum is the quorum derived from the turnout of recently
+// finalized proposals
+type AdaptiveQuorum struct {
+	Window         uint64  `json:"window"`
+	Samples        int     `json:"samples"` // Finalized proposals averaged, at most the window
+	AverageTurnout float64 `json:"average_turnout"`
+	Min            uint64  `json:"min"`
+	Max            uint64  `json:"max"`
+	Quorum         uint64  `json:"quorum"` // Average turnout bounded by min and max, 0 when disabled
+}
+
+// GetAdaptiveQuorum averages the votes cast on the last window finalized
+// proposals and bounds the average by min and max. The quorum follows
+// turnout both ways, so it decays when members vote less. Without finalized
+// proposals it is min. A zero window disables it and the quorum is 0.
+func (as *AnalyticsSystem) GetAdaptiveQuorum(window, min, max uint64) *AdaptiveQuorum {
+	adaptive := &AdaptiveQuorum{
+		Window: window,
+		Min:    min,
+		Max:    max,
+	}
+	if window == 0 {
+		return adaptive
+	}
+
+	// Most recently closed first, keeping no more than the window as
+	// archived proposals are read back
+	var finalized []*Proposal
+	latest := func() {
+		sort.Slice(finalized, func(i, j int) bool {
+			if finalized[i].EndTime != finalized[j].EndTime {
+				return finalized[i].EndTime > finalized[j].EndTime
+			}
+			return finalized[i].ID.String() < finalized[j].ID.String()
+		})
+		if uint64(len(finalized)) > window {
+			finalized = finalized[:window]
+		}
+	}
+	as.governanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
+		switch proposal.Status {
+		case ProposalStatusPassed, ProposalStatusRejected, ProposalStatusExecuted:
+		default:
+			return true
+		}
+		if proposal.Results == nil {
+			return true
+		}
+		finalized = append(finalized, proposal)
+		if uint64(len(finalized)) > 2*window {
+			latest()
+		}
+		return true
+	})
+	latest()
+
+	total := uint64(0)
+	for _, proposal := range finalized {
+		total += proposal.Results.YesVotes + proposal.Results.NoVotes + proposal.Results.AbstainVotes
+	}
+	adaptive.Samples = len(finalized)
+
+	quorum := min
+	if len(finalized) > 0 {
+		adaptive.AverageTurnout = float64(total) / float64(len(finalized))
+		quorum = total / uint64(len(finalized))
+	}
+	if quorum < min {
+		quorum = min
+	}
+	if quorum > max {
+		quorum = max
+	}
+	adaptive.Quorum = quorum
+
+	return adaptive
+}
@@ -0,0 +1,89 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveQuorum(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 10000,
+		bob.String():   10000,
+	}))
+
+	now := time.Now().Unix()
+	propose := func(id types.Hash) *Proposal {
+		require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+			Fee:          100,
+			Title:        "Routine",
+			Description:  "A routine change",
+			ProposalType: ProposalTypeGeneral,
+			VotingType:   VotingTypeSimple,
+			StartTime:    now,
+			EndTime:      now + 86400,
+			Threshold:    5100,
+		}, alice, id))
+		proposal, err := dao.GetProposal(id)
+		require.NoError(t, err)
+		return proposal
+	}
+	finalized := func(id byte, endTime int64, turnout uint64) {
+		dao.GovernanceState.Proposals[types.Hash{id}] = &Proposal{
+			ID:      types.Hash{id},
+			EndTime: endTime,
+			Status:  ProposalStatusRejected,
+			Results: &VoteResults{YesVotes: turnout / 2, NoVotes: turnout - turnout/2},
+		}
+	}
+
+	// Disabled by default, so proposals keep the configured quorum
+	assert.Zero(t, dao.GetAdaptiveQuorum().Quorum)
+	assert.Zero(t, propose(types.Hash{0xA0}).AdaptiveQuorum)
+
+	config := dao.ParameterManager.GetParameterConfig()
+	config.AdaptiveQuorumWindow = 3
+	config.AdaptiveQuorumMin = 500
+	config.AdaptiveQuorumMax = 3000
+
+	// Without finalized proposals the quorum is the minimum
+	assert.Equal(t, uint64(500), dao.GetAdaptiveQuorum().Quorum)
+
+	// Only the latest three finalized proposals count
+	finalized(0x01, now-400, 9000)
+	finalized(0x02, now-300, 1200)
+	finalized(0x03, now-200, 900)
+	finalized(0x04, now-100, 1500)
+	adaptive := dao.GetAdaptiveQuorum()
+	assert.Equal(t, 3, adaptive.Samples)
+	assert.Equal(t, float64(1200), adaptive.AverageTurnout)
+	assert.Equal(t, uint64(1200), adaptive.Quorum)
+
+	// Low turnout decays the quorum below the configured one
+	proposal := propose(types.Hash{0xA1})
+	assert.Equal(t, uint64(1200), proposal.AdaptiveQuorum)
+	require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposal.ID, Choice: VoteChoiceYes, Weight: 1300}, bob, types.Hash{0xB0}))
+	proposal.EndTime = now - 1
+	require.NoError(t, dao.Processor.UpdateProposalStatus(proposal.ID))
+	assert.Equal(t, ProposalStatusPassed, proposal.Status)
+
+	// High turnout raises it up to the maximum
+	finalized(0x05, now+100, 9000)
+	finalized(0x06, now+200, 9000)
+	assert.Equal(t, uint64(3000), dao.GetAdaptiveQuorum().Quorum)
+
+	// A proposal's own quorum still applies on top
+	proposal = propose(types.Hash{0xA2})
+	proposal.Quorum = 4000
+	assert.Equal(t, uint64(4000), proposal.RequiredQuorum(dao.GovernanceState.Config))
+	proposal.Quorum = 0
+	assert.Equal(t, uint64(3000), proposal.RequiredQuorum(dao.GovernanceState.Config))
+}
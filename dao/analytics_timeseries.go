@@ -0,0 +1,231 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Sampled analytics metrics
+const (
+	MetricParticipationRate = "participation_rate" // Percent of token holders who voted
+	MetricTreasuryBalance   = "treasury_balance"
+	MetricActiveMembers     = "active_members"
+	MetricProposalVelocity  = "proposal_velocity" // Proposals created in the trailing velocity window
+)
+
+// TimeSeriesMetrics are the metrics the time-series store samples
+var TimeSeriesMetrics = []string{MetricParticipationRate, MetricTreasuryBalance, MetricActiveMembers, MetricProposalVelocity}
+
+const (
+	// DefaultMetricsSampleInterval is how often metrics are sampled when no
+	// interval is configured
+	DefaultMetricsSampleInterval = 5 * time.Minute
+	// DefaultMetricsRetention is how long samples are kept when no retention
+	// is configured
+	DefaultMetricsRetention = 90 * 24 * time.Hour
+	// ProposalVelocityWindow is the trailing window proposal velocity counts
+	// proposals over
+	ProposalVelocityWindow int64 = 7 * 24 * 3600
+)
+
+// MetricPoint is the value of a metric at a time, or the average of the
+// samples in a bucket when downsampled
+type MetricPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+	Samples   int     `json:"samples"`
+}
+
+// metricSample is every metric sampled at one time
+type metricSample struct {
+	timestamp int64
+	values    map[string]float64
+	proposals int // Total proposals, the base of the velocity
+}
+
+// MetricsTimeSeries samples analytics metrics periodically so their trends
+// can be charted
+type MetricsTimeSeries struct {
+	analytics *AnalyticsSystem
+	retention int64
+	samples   []metricSample // Oldest first
+	stop      chan struct{}
+	mu        sync.RWMutex
+}
+
+// NewMetricsTimeSeries creates a time-series store keeping samples for
+// retention
+func NewMetricsTimeSeries(analytics *AnalyticsSystem, retention time.Duration) *MetricsTimeSeries {
+	if retention <= 0 {
+		retention = DefaultMetricsRetention
+	}
+	return &MetricsTimeSeries{
+		analytics: analytics,
+		retention: int64(retention / time.Second),
+	}
+}
+
+// IsTimeSeriesMetric reports whether metric is sampled by the store
+func IsTimeSeriesMetric(metric string) bool {
+	for _, known := range TimeSeriesMetrics {
+		if known == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Sample records the current value of every metric at now and drops the
+// samples older than the retention
+func (ts *MetricsTimeSeries) Sample(now int64) {
+	values, proposals := ts.analytics.currentMetrics()
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// Velocity is the growth in proposals since the oldest sample in the window
+	velocity := 0
+	for _, sample := range ts.samples {
+		if sample.timestamp >= now-ProposalVelocityWindow && sample.timestamp <= now {
+			velocity = proposals - sample.proposals
+			break
+		}
+	}
+	if velocity < 0 {
+		velocity = 0
+	}
+	values[MetricProposalVelocity] = float64(velocity)
+
+	ts.samples = append(ts.samples, metricSample{timestamp: now, values: values, proposals: proposals})
+
+	cutoff := now - ts.retention
+	expired := 0
+	for expired < len(ts.samples) && ts.samples[expired].timestamp < cutoff {
+		expired++
+	}
+	ts.samples = ts.samples[expired:]
+}
+
+// Query returns the points of metric between from and to, oldest first. A
+// positive resolution in seconds averages the samples into buckets of that
+// size; zero returns every sample.
+func (ts *MetricsTimeSeries) Query(metric string, from, to, resolution int64) ([]MetricPoint, error) {
+	if !IsTimeSeriesMetric(metric) {
+		return nil, NewDAOError(ErrInvalidProposal, "unknown metric", map[string]interface{}{"metric": metric, "metrics": TimeSeriesMetrics})
+	}
+	if from > to {
+		return nil, NewDAOError(ErrInvalidTimeframe, "from must not be after to", nil)
+	}
+	if resolution < 0 {
+		return nil, NewDAOError(ErrInvalidTimeframe, "resolution must not be negative", nil)
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	points := make([]MetricPoint, 0)
+	for _, sample := range ts.samples {
+		if sample.timestamp < from || sample.timestamp > to {
+			continue
+		}
+		value := sample.values[metric]
+		if resolution == 0 {
+			points = append(points, MetricPoint{Timestamp: sample.timestamp, Value: value, Samples: 1})
+			continue
+		}
+
+		bucket := sample.timestamp - sample.timestamp%resolution
+		if last := len(points) - 1; last >= 0 && points[last].Timestamp == bucket {
+			point := &points[last]
+			point.Value = (point.Value*float64(point.Samples) + value) / float64(point.Samples+1)
+			point.Samples++
+			continue
+		}
+		points = append(points, MetricPoint{Timestamp: bucket, Value: value, Samples: 1})
+	}
+
+	return points, nil
+}
+
+// Len returns the number of retained samples
+func (ts *MetricsTimeSeries) Len() int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return len(ts.samples)
+}
+
+// Start samples every interval in the background until Stop is called
+func (ts *MetricsTimeSeries) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultMetricsSampleInterval
+	}
+
+	ts.mu.Lock()
+	if ts.stop != nil {
+		ts.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	ts.stop = stop
+	ts.mu.Unlock()
+
+	ts.Sample(time.Now().Unix())
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ts.Sample(time.Now().Unix())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends background sampling
+func (ts *MetricsTimeSeries) Stop() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.stop != nil {
+		close(ts.stop)
+		ts.stop = nil
+	}
+}
+
+// currentMetrics returns the point-in-time value of the sampled metrics,
+// except the velocity, and the total number of proposals
+func (as *AnalyticsSystem) currentMetrics() (map[string]float64, int) {
+	voters := make(map[string]bool)
+	as.governanceState.EachProposal(func(_ types.Hash, _ *Proposal, votes map[string]*Vote) bool {
+		for voter := range votes {
+			voters[voter] = true
+		}
+		return true
+	})
+
+	activeMembers := 0
+	for _, holder := range as.governanceState.TokenHolders {
+		if holder.Status == MembershipStatusActive {
+			activeMembers++
+		}
+	}
+
+	participation := 0.0
+	if holders := len(as.governanceState.TokenHolders); holders > 0 {
+		participation = float64(len(voters)) / float64(holders) * 100
+	}
+
+	return map[string]float64{
+		MetricParticipationRate: participation,
+		MetricTreasuryBalance:   float64(as.governanceState.Treasury.Balance),
+		MetricActiveMembers:     float64(activeMembers),
+	}, as.governanceState.ProposalCount()
+}
@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsTimeSeries_Sample(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		voter.String(): 1000,
+		other.String(): 1000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 5000
+
+	now := int64(1700000000)
+	dao.Metrics.Sample(now)
+
+	dao.GovernanceState.Proposals[types.Hash{0x01}] = &Proposal{ID: types.Hash{0x01}}
+	dao.GovernanceState.Proposals[types.Hash{0x02}] = &Proposal{ID: types.Hash{0x02}}
+	dao.GovernanceState.Votes[types.Hash{0x01}] = map[string]*Vote{voter.String(): {Voter: voter, Choice: VoteChoiceYes}}
+	dao.GovernanceState.TokenHolders[other.String()].Status = MembershipStatusSuspended
+	dao.GovernanceState.Treasury.Balance = 3000
+	dao.Metrics.Sample(now + 3600)
+
+	points, err := dao.GetMetricTimeSeries(MetricParticipationRate, now, now+3600, 0)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, 0.0, points[0].Value)
+	assert.Equal(t, 50.0, points[1].Value)
+
+	points, _ = dao.GetMetricTimeSeries(MetricActiveMembers, now, now+3600, 0)
+	assert.Equal(t, []float64{2, 1}, []float64{points[0].Value, points[1].Value})
+
+	points, _ = dao.GetMetricTimeSeries(MetricProposalVelocity, now, now+3600, 0)
+	assert.Equal(t, 2.0, points[1].Value)
+
+	// Proposals older than the velocity window no longer count
+	dao.Metrics.Sample(now + ProposalVelocityWindow + 7200)
+	points, _ = dao.GetMetricTimeSeries(MetricProposalVelocity, now+3601, now+ProposalVelocityWindow+7200, 0)
+	require.Len(t, points, 1)
+	assert.Equal(t, 0.0, points[0].Value)
+
+	_, err = dao.GetMetricTimeSeries("tvl", now, now+3600, 0)
+	assert.Error(t, err)
+	_, err = dao.GetMetricTimeSeries(MetricTreasuryBalance, now+1, now, 0)
+	assert.Error(t, err)
+}
+
+func TestMetricsTimeSeries_Downsample(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	series := NewMetricsTimeSeries(dao.AnalyticsSystem, 2*time.Hour)
+
+	start := int64(1699999200) // Aligned to the hour
+	for i, balance := range []uint64{100, 200, 300, 400} {
+		dao.GovernanceState.Treasury.Balance = balance
+		series.Sample(start + int64(i)*1800)
+	}
+
+	points, err := series.Query(MetricTreasuryBalance, start, start+7200, 3600)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, MetricPoint{Timestamp: start, Value: 150, Samples: 2}, points[0])
+	assert.Equal(t, 350.0, points[1].Value)
+
+	// Samples past the retention are dropped
+	series.Sample(start + 3*3600)
+	assert.Equal(t, 3, series.Len())
+}
+
+func TestMetricsTimeSeries_StartStop(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	series := dao.EnableMetricsSampling(time.Hour, 0)
+	defer series.Stop()
+
+	// Starting samples straight away
+	assert.Equal(t, 1, series.Len())
+	series.Start(time.Hour)
+	assert.Equal(t, 1, series.Len())
+}
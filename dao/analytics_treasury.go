@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// SecondsPerMonth is the month burn rates and runways are measured in
+const SecondsPerMonth = 30 * 24 * 3600
+
+// Treasury runway alert levels
+const (
+	TreasuryAlertWarning  = "warning"
+	TreasuryAlertCritical = "critical"
+)
+
+// TreasuryRunwayAlertEvent is the notification type of runway alerts
+const TreasuryRunwayAlertEvent = "treasury_runway_alert"
+
+// TreasuryAssetValuation values the treasury balance and burn rate in the
+// stable unit of an oracle feed pricing the token. Values are nil while the
+// feed is stale.
+type TreasuryAssetValuation struct {
+	FeedID      string  `json:"feed_id"`
+	Description string  `json:"description,omitempty"`
+	Price       int64   `json:"price"`
+	Decimals    uint8   `json:"decimals"`
+	Value       *uint64 `json:"value,omitempty"`
+	BurnRate    *uint64 `json:"burn_rate,omitempty"`
+	Stale       bool    `json:"stale"`
+}
+
+// TreasuryAlert is raised when the runway falls below a threshold
+type TreasuryAlert struct {
+	Level        string  `json:"level"`
+	RunwayMonths float64 `json:"runway_months"`
+	Threshold    uint64  `json:"threshold"` // Months
+	Message      string  `json:"message"`
+}
+
+// treasuryRunway sets the burn rate of the executed transactions in the
+// burn window, the runway at that rate, the valuations and the runway alert
+func (as *AnalyticsSystem) treasuryRunway(metrics *TreasuryPerformanceMetrics, now int64) {
+	config := NewDefaultParameterConfig()
+	if as.parameterManager != nil {
+		config = as.parameterManager.GetParameterConfig()
+	}
+
+	window := config.TreasuryBurnWindow
+	var spent uint64
+	as.governanceState.EachTreasuryTransaction(func(_ types.Hash, tx *PendingTx) bool {
+		if tx.Executed && tx.ExecutedAt > now-window && tx.ExecutedAt <= now {
+			spent += tx.Amount
+		}
+		return true
+	})
+	metrics.BurnWindow = window
+	if window > 0 {
+		metrics.BurnRate = mulDiv(spent, SecondsPerMonth, uint64(window))
+	}
+
+	if metrics.BurnRate > 0 {
+		runway := float64(metrics.CurrentBalance) / float64(metrics.BurnRate)
+		metrics.RunwayMonths = &runway
+
+		switch {
+		case config.TreasuryRunwayCritical > 0 && runway < float64(config.TreasuryRunwayCritical):
+			metrics.RunwayAlert = newTreasuryAlert(TreasuryAlertCritical, runway, config.TreasuryRunwayCritical)
+		case config.TreasuryRunwayWarning > 0 && runway < float64(config.TreasuryRunwayWarning):
+			metrics.RunwayAlert = newTreasuryAlert(TreasuryAlertWarning, runway, config.TreasuryRunwayWarning)
+		}
+	}
+
+	if as.oracles == nil {
+		return
+	}
+	for _, feed := range as.oracles.ListFeeds() {
+		valuation := TreasuryAssetValuation{
+			FeedID:      feed.ID,
+			Description: feed.Description,
+			Decimals:    feed.Decimals,
+		}
+		price, _, err := feed.Value(now)
+		if err != nil || price <= 0 {
+			valuation.Stale = true
+		} else {
+			valuation.Price = price
+			valuation.Value = stableValue(metrics.CurrentBalance, price, feed.Decimals)
+			valuation.BurnRate = stableValue(metrics.BurnRate, price, feed.Decimals)
+		}
+		metrics.AssetValuations = append(metrics.AssetValuations, valuation)
+	}
+}
+
+func newTreasuryAlert(level string, runway float64, threshold uint64) *TreasuryAlert {
+	return &TreasuryAlert{
+		Level:        level,
+		RunwayMonths: runway,
+		Threshold:    threshold,
+		Message:      fmt.Sprintf("treasury runway of %.1f months is below %d months", runway, threshold),
+	}
+}
+
+// stableValue converts native tokens to a feed's stable unit, the inverse of
+// OracleManager.Convert. It is nil when the value overflows.
+func stableValue(native uint64, price int64, decimals uint8) *uint64 {
+	value := new(big.Int).SetUint64(native)
+	value.Mul(value, big.NewInt(price))
+	value.Quo(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	if !value.IsUint64() {
+		return nil
+	}
+	v := value.Uint64()
+	return &v
+}
+
+// mulDiv returns a*b/c without overflowing the product
+func mulDiv(a, b, c uint64) uint64 {
+	result := new(big.Int).SetUint64(a)
+	result.Mul(result, new(big.Int).SetUint64(b))
+	result.Quo(result, new(big.Int).SetUint64(c))
+	if !result.IsUint64() {
+		return ^uint64(0)
+	}
+	return result.Uint64()
+}
+
+// CheckTreasuryRunway notifies members of the runway alert whenever its
+// level changes and returns the alert it sent
+func (d *DAO) CheckTreasuryRunway() *TreasuryAlert {
+	alert := d.AnalyticsSystem.GetTreasuryPerformanceMetrics().RunwayAlert
+
+	level := ""
+	if alert != nil {
+		level = alert.Level
+	}
+	changed := level != d.runwayAlertLevel
+	d.runwayAlertLevel = level
+
+	if alert == nil || !changed {
+		return nil
+	}
+	notification := NewNotification(TreasuryRunwayAlertEvent, map[string]interface{}{
+		"title":         alert.Message,
+		"level":         alert.Level,
+		"runway_months": alert.RunwayMonths,
+		"threshold":     alert.Threshold,
+	}, time.Now().Unix())
+	d.Notifications.Notify(notification)
+	return alert
+}
@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreasuryRunway(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	dao.AddTreasuryFunds(90000)
+
+	now := time.Now().Unix()
+	window := dao.ParameterManager.GetParameterConfig().TreasuryBurnWindow
+	spend := func(id byte, amount uint64, executedAt int64) {
+		dao.GovernanceState.Treasury.Transactions[types.Hash{id}] = &PendingTx{
+			ID:         types.Hash{id},
+			Amount:     amount,
+			Executed:   true,
+			ExecutedAt: executedAt,
+		}
+	}
+
+	// Nothing spent, so no runway or alert
+	metrics := dao.GetTreasuryPerformanceMetrics()
+	assert.Zero(t, metrics.BurnRate)
+	assert.Nil(t, metrics.RunwayMonths)
+	assert.Nil(t, metrics.RunwayAlert)
+	assert.Empty(t, metrics.AssetValuations)
+
+	// Only spending inside the burn window counts, 30000 over three months
+	spend(0x01, 20000, now-100)
+	spend(0x02, 10000, now-window+100)
+	spend(0x03, 50000, now-window-100)
+	metrics = dao.GetTreasuryPerformanceMetrics()
+	assert.Equal(t, window, metrics.BurnWindow)
+	assert.Equal(t, uint64(10000), metrics.BurnRate)
+	require.NotNil(t, metrics.RunwayMonths)
+	assert.InDelta(t, 9, *metrics.RunwayMonths, 0.001)
+	require.NotNil(t, metrics.RunwayAlert)
+	assert.Equal(t, TreasuryAlertWarning, metrics.RunwayAlert.Level)
+	assert.Equal(t, uint64(12), metrics.RunwayAlert.Threshold)
+
+	// Valued through every oracle feed, stale feeds without values
+	oracle := crypto.GeneratePrivateKey().PublicKey()
+	feed := func(id string, value, observedAt int64) {
+		dao.OracleManager.feeds[id] = &OracleFeed{
+			ID:         id,
+			Decimals:   2,
+			Oracles:    []crypto.PublicKey{oracle},
+			MinReports: 1,
+			MaxAge:     600,
+			Reports: map[string]*OracleDataPoint{
+				oracle.String(): {Oracle: oracle, Value: value, ObservedAt: observedAt},
+			},
+		}
+	}
+	feed("GOV-EUR", 180, now-1000)
+	feed("GOV-USD", 250, now)
+	metrics = dao.GetTreasuryPerformanceMetrics()
+	require.Len(t, metrics.AssetValuations, 2)
+	assert.True(t, metrics.AssetValuations[0].Stale)
+	assert.Nil(t, metrics.AssetValuations[0].Value)
+	usd := metrics.AssetValuations[1]
+	assert.Equal(t, int64(250), usd.Price)
+	require.NotNil(t, usd.Value)
+	assert.Equal(t, uint64(225000), *usd.Value)
+	assert.Equal(t, uint64(25000), *usd.BurnRate)
+}
+
+func TestCheckTreasuryRunway(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	dao.AddTreasuryFunds(90000)
+
+	spend := func(id byte, amount uint64) {
+		dao.GovernanceState.Treasury.Transactions[types.Hash{id}] = &PendingTx{
+			ID:         types.Hash{id},
+			Amount:     amount,
+			Executed:   true,
+			ExecutedAt: time.Now().Unix() - 100,
+		}
+	}
+	queued := func() []*Notification {
+		var notifications []*Notification
+		for len(dao.Notifications.queue) > 0 {
+			notifications = append(notifications, <-dao.Notifications.queue)
+		}
+		return notifications
+	}
+
+	// A healthy runway sends nothing
+	assert.Nil(t, dao.CheckTreasuryRunway())
+	spend(0x01, 3000)
+	assert.Nil(t, dao.CheckTreasuryRunway())
+	assert.Empty(t, queued())
+
+	// Warned once when it drops below the warning threshold
+	spend(0x02, 27000)
+	alert := dao.CheckTreasuryRunway()
+	require.NotNil(t, alert)
+	assert.Equal(t, TreasuryAlertWarning, alert.Level)
+	assert.Nil(t, dao.CheckTreasuryRunway())
+
+	// And again when it turns critical
+	spend(0x03, 30000)
+	alert = dao.CheckTreasuryRunway()
+	require.NotNil(t, alert)
+	assert.Equal(t, TreasuryAlertCritical, alert.Level)
+
+	notifications := queued()
+	require.Len(t, notifications, 2)
+	assert.Equal(t, TreasuryRunwayAlertEvent, notifications[0].Type)
+	assert.Equal(t, TreasuryAlertWarning, notifications[0].Data["level"])
+	assert.Equal(t, TreasuryAlertCritical, notifications[1].Data["level"])
+	assert.Equal(t, alert.Message, notifications[1].Body)
+}
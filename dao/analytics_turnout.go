@@ -0,0 +1,173 @@
+package dao
+
+import (
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Turnout segmentation thresholds
+const (
+	// WhaleThresholdBps is the share of the total supply, in basis points, a
+	// vote must weigh to count as a whale's
+	WhaleThresholdBps = 100
+	// NewMemberPeriod is how long before a proposal opened a member must
+	// have joined to count as a veteran
+	NewMemberPeriod = 30 * 24 * 3600
+	// DefaultTurnoutBuckets and MaxTurnoutBuckets bound the votes-over-time
+	// histogram
+	DefaultTurnoutBuckets = 24
+	MaxTurnoutBuckets     = 100
+)
+
+// TurnoutSegment is the share of a turnout cast by one kind of voter
+type TurnoutSegment struct {
+	Voters uint64  `json:"voters"`
+	Weight uint64  `json:"weight"`
+	Share  float64 `json:"share"` // Percent of the weight cast
+}
+
+// TurnoutSegmentation splits a turnout three ways: whales against small
+// holders, delegated against direct power, and new members against veterans.
+// Delegated weight is the part of a vote carried for delegators; a voter
+// counts as delegated when any of their weight was.
+type TurnoutSegmentation struct {
+	Whales       TurnoutSegment `json:"whales"`
+	SmallHolders TurnoutSegment `json:"small_holders"`
+	Delegated    TurnoutSegment `json:"delegated"`
+	Direct       TurnoutSegment `json:"direct"`
+	NewMembers   TurnoutSegment `json:"new_members"`
+	Veterans     TurnoutSegment `json:"veterans"`
+}
+
+// TurnoutBucket is the votes cast in one interval of a proposal's vote
+type TurnoutBucket struct {
+	Start            int64  `json:"start"`
+	End              int64  `json:"end"`
+	Votes            uint64 `json:"votes"`
+	Weight           uint64 `json:"weight"`
+	CumulativeWeight uint64 `json:"cumulative_weight"`
+}
+
+// ProposalTurnout is the segmented turnout of a proposal with the weight
+// cast over its voting period
+type ProposalTurnout struct {
+	ProposalID  string              `json:"proposal_id"`
+	Voters      uint64              `json:"voters"`
+	Weight      uint64              `json:"weight"`
+	Segments    TurnoutSegmentation `json:"segments"`
+	VotesByTime []TurnoutBucket     `json:"votes_by_time"`
+}
+
+// add counts a vote in the segments
+func (ts *TurnoutSegmentation) add(as *AnalyticsSystem, proposal *Proposal, vote *Vote) {
+	whale := as.tokenState.TotalSupply > 0 && vote.Weight*10000 >= as.tokenState.TotalSupply*WhaleThresholdBps
+	addTo(&ts.Whales, &ts.SmallHolders, whale, vote.Weight)
+
+	delegated := as.delegatedPowerAt(vote.Voter.String(), vote.Timestamp)
+	if delegated > vote.Weight {
+		delegated = vote.Weight
+	}
+	if delegated > 0 {
+		ts.Delegated.Voters++
+		ts.Delegated.Weight += delegated
+	} else {
+		ts.Direct.Voters++
+	}
+	ts.Direct.Weight += vote.Weight - delegated
+
+	holder, exists := as.governanceState.TokenHolders[vote.Voter.String()]
+	veteran := exists && holder.JoinedAt <= proposal.StartTime-NewMemberPeriod
+	addTo(&ts.Veterans, &ts.NewMembers, veteran, vote.Weight)
+}
+
+// addTo counts a voter and their weight in one of two segments
+func addTo(yes, no *TurnoutSegment, in bool, weight uint64) {
+	segment := no
+	if in {
+		segment = yes
+	}
+	segment.Voters++
+	segment.Weight += weight
+}
+
+// finish sets the share of each segment in the total weight
+func (ts *TurnoutSegmentation) finish(total uint64) {
+	if total == 0 {
+		return
+	}
+	for _, segment := range []*TurnoutSegment{&ts.Whales, &ts.SmallHolders, &ts.Delegated, &ts.Direct, &ts.NewMembers, &ts.Veterans} {
+		segment.Share = float64(segment.Weight) / float64(total) * 100
+	}
+}
+
+// delegatedPowerAt returns the balance delegated to a delegate by the
+// delegations in effect at a time
+func (as *AnalyticsSystem) delegatedPowerAt(delegate string, at int64) uint64 {
+	var power uint64
+	for delegator, delegation := range as.governanceState.Delegations {
+		if isActiveDelegation(delegation, at) && delegation.Delegate.String() == delegate {
+			power += as.tokenState.Balances.Get(delegator)
+		}
+	}
+	return power
+}
+
+// GetProposalTurnout returns the segmented turnout of a proposal. The
+// histogram splits the voting period, or up to now while it runs, into
+// buckets of equal length.
+func (as *AnalyticsSystem) GetProposalTurnout(proposalID types.Hash, buckets int, now int64) (*ProposalTurnout, error) {
+	proposal, exists := as.governanceState.Proposal(proposalID)
+	if !exists {
+		return nil, ErrProposalNotFoundError
+	}
+	if buckets < 1 {
+		buckets = DefaultTurnoutBuckets
+	}
+	if buckets > MaxTurnoutBuckets {
+		buckets = MaxTurnoutBuckets
+	}
+
+	turnout := &ProposalTurnout{
+		ProposalID:  proposalID.String(),
+		VotesByTime: make([]TurnoutBucket, 0, buckets),
+	}
+
+	start, end := proposal.StartTime, proposal.EndTime
+	if now < end {
+		end = now
+	}
+	if end <= start {
+		end = start + 1
+	}
+	length := (end - start + int64(buckets) - 1) / int64(buckets)
+	for i := 0; i < buckets; i++ {
+		turnout.VotesByTime = append(turnout.VotesByTime, TurnoutBucket{
+			Start: start + int64(i)*length,
+			End:   start + int64(i+1)*length,
+		})
+	}
+
+	votes, _ := as.governanceState.ProposalVotes(proposalID)
+	for _, vote := range votes {
+		turnout.Voters++
+		turnout.Weight += vote.Weight
+		turnout.Segments.add(as, proposal, vote)
+
+		i := 0
+		if vote.Timestamp > start {
+			i = int((vote.Timestamp - start) / length)
+		}
+		if i >= buckets {
+			i = buckets - 1
+		}
+		turnout.VotesByTime[i].Votes++
+		turnout.VotesByTime[i].Weight += vote.Weight
+	}
+	turnout.Segments.finish(turnout.Weight)
+
+	var cumulative uint64
+	for i := range turnout.VotesByTime {
+		cumulative += turnout.VotesByTime[i].Weight
+		turnout.VotesByTime[i].CumulativeWeight = cumulative
+	}
+	return turnout, nil
+}
@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalTurnout(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	whale := crypto.GeneratePrivateKey().PublicKey()
+	small := crypto.GeneratePrivateKey().PublicKey()
+	delegate := crypto.GeneratePrivateKey().PublicKey()
+	delegator := crypto.GeneratePrivateKey().PublicKey()
+	newcomer := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		whale.String():     20000,
+		small.String():     500,
+		delegate.String():  500,
+		delegator.String(): 1000,
+		newcomer.String():  300,
+		crypto.GeneratePrivateKey().PublicKey().String(): 200000,
+	}))
+
+	// Genesis members joined now, so they are veterans of a later proposal
+	start := time.Now().Unix() + 2*NewMemberPeriod
+	end := start + 86400
+	proposalID := types.Hash{0x01}
+	dao.GovernanceState.Proposals[proposalID] = &Proposal{ID: proposalID, StartTime: start, EndTime: end, Status: ProposalStatusPassed}
+	dao.GovernanceState.TokenHolders[newcomer.String()].JoinedAt = start - 86400
+	dao.GovernanceState.Delegations[delegator.String()] = &Delegation{
+		Delegator: delegator,
+		Delegate:  delegate,
+		StartTime: start - 10,
+		EndTime:   end + 1000,
+		Active:    true,
+	}
+	dao.GovernanceState.Votes[proposalID] = map[string]*Vote{
+		whale.String():    {Voter: whale, Choice: VoteChoiceYes, Weight: 20000, Timestamp: start + 100},
+		small.String():    {Voter: small, Choice: VoteChoiceNo, Weight: 500, Timestamp: start + 3700},
+		delegate.String(): {Voter: delegate, Choice: VoteChoiceYes, Weight: 1500, Timestamp: start + 7300},
+		newcomer.String(): {Voter: newcomer, Choice: VoteChoiceAbstain, Weight: 300, Timestamp: start + 86000},
+	}
+
+	turnout, err := dao.AnalyticsSystem.GetProposalTurnout(proposalID, 4, end+10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), turnout.Voters)
+	assert.Equal(t, uint64(22300), turnout.Weight)
+
+	segments := turnout.Segments
+	assert.Equal(t, TurnoutSegment{Voters: 1, Weight: 20000, Share: float64(20000) / 22300 * 100}, segments.Whales)
+	assert.Equal(t, uint64(3), segments.SmallHolders.Voters)
+	assert.Equal(t, uint64(2300), segments.SmallHolders.Weight)
+
+	// Only the delegators' part of the delegate's vote is delegated
+	assert.Equal(t, uint64(1), segments.Delegated.Voters)
+	assert.Equal(t, uint64(1000), segments.Delegated.Weight)
+	assert.Equal(t, uint64(3), segments.Direct.Voters)
+	assert.Equal(t, uint64(21300), segments.Direct.Weight)
+
+	assert.Equal(t, uint64(1), segments.NewMembers.Voters)
+	assert.Equal(t, uint64(300), segments.NewMembers.Weight)
+	assert.Equal(t, uint64(22000), segments.Veterans.Weight)
+
+	require.Len(t, turnout.VotesByTime, 4)
+	assert.Equal(t, TurnoutBucket{Start: start, End: start + 21600, Votes: 3, Weight: 22000, CumulativeWeight: 22000}, turnout.VotesByTime[0])
+	assert.Equal(t, uint64(22000), turnout.VotesByTime[2].CumulativeWeight)
+	assert.Equal(t, uint64(1), turnout.VotesByTime[3].Votes)
+	assert.Equal(t, uint64(22300), turnout.VotesByTime[3].CumulativeWeight)
+
+	// A running vote is only bucketed up to now
+	turnout, err = dao.AnalyticsSystem.GetProposalTurnout(proposalID, 0, start+7200)
+	require.NoError(t, err)
+	require.Len(t, turnout.VotesByTime, DefaultTurnoutBuckets)
+	assert.Equal(t, start+7200, turnout.VotesByTime[DefaultTurnoutBuckets-1].End)
+
+	// The proposal analytics segment the votes on every proposal
+	assert.Equal(t, uint64(20000), dao.GetProposalAnalytics().TurnoutSegments.Whales.Weight)
+
+	_, err = dao.GetProposalTurnout(types.Hash{0x02}, 4)
+	assert.Error(t, err)
+}
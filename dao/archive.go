@@ -0,0 +1,647 @@
+package dao
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/BOCK-CHAIN/BockChain/codec"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// ArchiveConfig configures the memory-bounded mode of the DAO. Undecided
+// proposals and the most recently used finalized data stay in memory, older
+// finalized proposals with their votes and executed treasury transactions
+// are written to Dir and read back when asked for.
+type ArchiveConfig struct {
+	Dir            string // Where archived records are written, required
+	MaxProposals   int    // Finalized proposals kept in memory, 256 when zero
+	MaxTreasuryTxs int    // Executed treasury transactions kept in memory, 256 when zero
+	CacheEntries   int    // Archived records kept in memory once read back, 64 when zero
+}
+
+// ArchiveStats summarises the memory-bounded mode
+type ArchiveStats struct {
+	ResidentProposals   int    `json:"resident_proposals"`
+	ArchivedProposals   int    `json:"archived_proposals"`
+	ResidentTreasuryTxs int    `json:"resident_treasury_txs"`
+	ArchivedTreasuryTxs int    `json:"archived_treasury_txs"`
+	CacheHits           uint64 `json:"cache_hits"`
+	DiskReads           uint64 `json:"disk_reads"`
+	Evictions           uint64 `json:"evictions"`
+}
+
+// Kinds of archived records
+const (
+	archiveProposal   byte = 'p'
+	archiveTreasuryTx byte = 't'
+)
+
+// archiveKey names an archived record
+type archiveKey struct {
+	kind byte
+	id   types.Hash
+}
+
+// archivedProposal is the disk record of a proposal and its votes
+type archivedProposal struct {
+	Proposal *Proposal
+	Votes    map[string]*Vote
+}
+
+// recencyList orders keys from most to least recently used, optionally
+// holding a value for each
+type recencyList struct {
+	elements map[archiveKey]*list.Element
+	order    *list.List
+}
+
+type recencyEntry struct {
+	key   archiveKey
+	value interface{}
+}
+
+func newRecencyList() *recencyList {
+	return &recencyList{elements: make(map[archiveKey]*list.Element), order: list.New()}
+}
+
+// touch marks key as the most recently used, adding it when missing
+func (rl *recencyList) touch(key archiveKey, value interface{}) {
+	if element, exists := rl.elements[key]; exists {
+		element.Value.(*recencyEntry).value = value
+		rl.order.MoveToFront(element)
+		return
+	}
+	rl.elements[key] = rl.order.PushFront(&recencyEntry{key: key, value: value})
+}
+
+// get returns the value of key and marks it as the most recently used
+func (rl *recencyList) get(key archiveKey) (interface{}, bool) {
+	element, exists := rl.elements[key]
+	if !exists {
+		return nil, false
+	}
+	rl.order.MoveToFront(element)
+	return element.Value.(*recencyEntry).value, true
+}
+
+func (rl *recencyList) remove(key archiveKey) {
+	if element, exists := rl.elements[key]; exists {
+		rl.order.Remove(element)
+		delete(rl.elements, key)
+	}
+}
+
+// oldest returns the least recently used key
+func (rl *recencyList) oldest() (archiveKey, bool) {
+	element := rl.order.Back()
+	if element == nil {
+		return archiveKey{}, false
+	}
+	return element.Value.(*recencyEntry).key, true
+}
+
+// Archive holds the finalized data the memory-bounded mode moved out of the
+// governance state. Archived entries stay committed to the state root: the
+// archive keeps their encoded state leaves, which never change, while the
+// records themselves are on disk.
+type Archive struct {
+	config ArchiveConfig
+	// resident tracks the finalized records still in the governance state,
+	// the least recently used are archived first
+	resident *recencyList
+	leaves   map[archiveKey][]StateLeaf // Committed leaves of archived records
+	counts   map[byte]int               // Archived records of each kind
+	cache    *recencyList               // Archived records read back from disk
+
+	cacheHits uint64
+	diskReads uint64
+	evictions uint64
+	mu        sync.Mutex
+}
+
+// NewArchive creates an archive writing to config.Dir. The archive starts
+// empty: records left by an earlier run are never read, as the state they
+// were part of is rebuilt from the chain, and are overwritten as the rebuilt
+// state archives them again.
+func NewArchive(config ArchiveConfig) (*Archive, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("archive directory is required")
+	}
+	if config.MaxProposals <= 0 {
+		config.MaxProposals = 256
+	}
+	if config.MaxTreasuryTxs <= 0 {
+		config.MaxTreasuryTxs = 256
+	}
+	if config.CacheEntries <= 0 {
+		config.CacheEntries = 64
+	}
+
+	for _, kind := range []byte{archiveProposal, archiveTreasuryTx} {
+		if err := os.MkdirAll(filepath.Join(config.Dir, archiveDirs[kind]), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Archive{
+		config:   config,
+		resident: newRecencyList(),
+		leaves:   make(map[archiveKey][]StateLeaf),
+		counts:   make(map[byte]int),
+		cache:    newRecencyList(),
+	}, nil
+}
+
+var archiveDirs = map[byte]string{
+	archiveProposal:   "proposals",
+	archiveTreasuryTx: "treasury",
+}
+
+func (a *Archive) path(key archiveKey) string {
+	return filepath.Join(a.config.Dir, archiveDirs[key.kind], key.id.String())
+}
+
+// Stats returns the archive counters
+func (a *Archive) Stats() ArchiveStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats := ArchiveStats{CacheHits: a.cacheHits, DiskReads: a.diskReads, Evictions: a.evictions}
+	for key := range a.resident.elements {
+		if key.kind == archiveProposal {
+			stats.ResidentProposals++
+		} else {
+			stats.ResidentTreasuryTxs++
+		}
+	}
+	for key := range a.leaves {
+		if key.kind == archiveProposal {
+			stats.ArchivedProposals++
+		} else {
+			stats.ArchivedTreasuryTxs++
+		}
+	}
+	return stats
+}
+
+// touch marks a resident record as used, so it is archived later. Only
+// lookups made for readers touch records, never those made applying
+// transactions.
+func (a *Archive) touch(key archiveKey) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, resident := a.resident.elements[key]; resident {
+		a.resident.touch(key, nil)
+	}
+}
+
+// StateLeaves returns the committed leaves of the archived records
+func (a *Archive) StateLeaves() []StateLeaf {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var leaves []StateLeaf
+	for _, recordLeaves := range a.leaves {
+		leaves = append(leaves, recordLeaves...)
+	}
+	return leaves
+}
+
+// ids returns the IDs of the archived records of a kind
+func (a *Archive) ids(kind byte) []types.Hash {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var ids []types.Hash
+	for key := range a.leaves {
+		if key.kind == kind {
+			ids = append(ids, key.id)
+		}
+	}
+	return ids
+}
+
+// count returns the number of archived records of a kind
+func (a *Archive) count(kind byte) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.counts[kind]
+}
+
+// write stores a record on disk along with its committed leaves
+func (a *Archive) write(key archiveKey, record interface{}, values map[string]interface{}) error {
+	leaves := make([]StateLeaf, 0, len(values))
+	for stateKey, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode state leaf %s: %w", stateKey, err)
+		}
+		leaves = append(leaves, StateLeaf{Key: stateKey, Value: encoded})
+	}
+
+	data, err := codec.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(a.path(key), data, 0o644); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.resident.remove(key)
+	if _, archived := a.leaves[key]; !archived {
+		a.counts[key.kind]++
+	}
+	a.leaves[key] = leaves
+	a.evictions++
+	return nil
+}
+
+// read returns an archived record, from the cache when it was read recently
+func (a *Archive) read(key archiveKey, record interface{}) (interface{}, bool, error) {
+	return a.load(key, record, true)
+}
+
+// peek returns an archived record like read, without adding it to the
+// cache, for readers going through every record
+func (a *Archive) peek(key archiveKey, record interface{}) (interface{}, bool, error) {
+	return a.load(key, record, false)
+}
+
+func (a *Archive) load(key archiveKey, record interface{}, cache bool) (interface{}, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, archived := a.leaves[key]; !archived {
+		return nil, false, nil
+	}
+	if value, cached := a.cache.get(key); cached {
+		a.cacheHits++
+		return value, true, nil
+	}
+
+	data, err := os.ReadFile(a.path(key))
+	if err != nil {
+		return nil, false, err
+	}
+	if err := codec.Unmarshal(data, record); err != nil {
+		return nil, false, err
+	}
+	a.diskReads++
+	if !cache {
+		return record, true, nil
+	}
+
+	a.cache.touch(key, record)
+	for len(a.cache.elements) > a.config.CacheEntries {
+		oldest, _ := a.cache.oldest()
+		a.cache.remove(oldest)
+	}
+	return record, true, nil
+}
+
+// Proposal reads back an archived proposal and its votes
+func (a *Archive) Proposal(id types.Hash) (*Proposal, map[string]*Vote, bool, error) {
+	value, archived, err := a.read(archiveKey{archiveProposal, id}, &archivedProposal{})
+	if !archived {
+		return nil, nil, false, err
+	}
+	record := value.(*archivedProposal)
+	return record.Proposal, record.Votes, true, nil
+}
+
+// restore reads back an archived proposal and its votes and drops them from
+// the archive, as they return to the governance state. The file stays until
+// the proposal is archived again.
+func (a *Archive) restore(id types.Hash) (*Proposal, map[string]*Vote, bool, error) {
+	proposal, votes, archived, err := a.Proposal(id)
+	if !archived {
+		return nil, nil, false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := archiveKey{archiveProposal, id}
+	if _, archived := a.leaves[key]; archived {
+		a.counts[key.kind]--
+	}
+	delete(a.leaves, key)
+	a.cache.remove(key)
+	return proposal, votes, true, nil
+}
+
+// TreasuryTx reads back an archived treasury transaction
+func (a *Archive) TreasuryTx(id types.Hash) (*PendingTx, bool, error) {
+	value, archived, err := a.read(archiveKey{archiveTreasuryTx, id}, &PendingTx{})
+	if !archived {
+		return nil, false, err
+	}
+	return value.(*PendingTx), true, nil
+}
+
+// EnableArchive turns on the memory-bounded mode. ArchiveFinalized then
+// moves finalized data beyond the configured budget to disk, and the
+// GovernanceState accessors read it back.
+func (d *DAO) EnableArchive(config ArchiveConfig) (*Archive, error) {
+	archive, err := NewArchive(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	d.Archive = archive
+	d.GovernanceState.archive = archive
+	return archive, nil
+}
+
+// ArchiveFinalized moves the least recently used finalized proposals, with
+// their votes, and executed treasury transactions beyond the configured
+// budget to disk and returns how many records it archived. Proposals whose
+// result still waits to be anchored stay in memory.
+func (d *DAO) ArchiveFinalized() (int, error) {
+	archive := d.Archive
+	if archive == nil {
+		return 0, nil
+	}
+	gs := d.GovernanceState
+
+	// Newly finalized records join as the most recently used, in the order
+	// they finalized
+	var proposals []types.Hash
+	for id, proposal := range gs.Proposals {
+		if d.archivable(proposal) {
+			proposals = append(proposals, id)
+		}
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		a, b := gs.Proposals[proposals[i]], gs.Proposals[proposals[j]]
+		return a.EndTime < b.EndTime || (a.EndTime == b.EndTime && proposals[i].String() < proposals[j].String())
+	})
+
+	var txs []types.Hash
+	for id, tx := range gs.Treasury.Transactions {
+		if tx.Executed {
+			txs = append(txs, id)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		a, b := gs.Treasury.Transactions[txs[i]], gs.Treasury.Transactions[txs[j]]
+		return a.ExecutedAt < b.ExecutedAt || (a.ExecutedAt == b.ExecutedAt && txs[i].String() < txs[j].String())
+	})
+
+	archive.mu.Lock()
+	for _, id := range proposals {
+		if key := (archiveKey{archiveProposal, id}); archive.resident.elements[key] == nil {
+			archive.resident.touch(key, nil)
+		}
+	}
+	for _, id := range txs {
+		if key := (archiveKey{archiveTreasuryTx, id}); archive.resident.elements[key] == nil {
+			archive.resident.touch(key, nil)
+		}
+	}
+
+	// Records removed from the state some other way are no longer tracked
+	var evict []archiveKey
+	proposalCount, txCount := 0, 0
+	for key := range archive.resident.elements {
+		if key.kind == archiveProposal {
+			if _, exists := gs.Proposals[key.id]; !exists {
+				archive.resident.remove(key)
+				continue
+			}
+			proposalCount++
+		} else {
+			if _, exists := gs.Treasury.Transactions[key.id]; !exists {
+				archive.resident.remove(key)
+				continue
+			}
+			txCount++
+		}
+	}
+	for element := archive.resident.order.Back(); element != nil; element = element.Prev() {
+		key := element.Value.(*recencyEntry).key
+		if key.kind == archiveProposal && proposalCount > archive.config.MaxProposals {
+			evict = append(evict, key)
+			proposalCount--
+		} else if key.kind == archiveTreasuryTx && txCount > archive.config.MaxTreasuryTxs {
+			evict = append(evict, key)
+			txCount--
+		}
+	}
+	archive.mu.Unlock()
+
+	for i, key := range evict {
+		if err := d.archiveRecord(key); err != nil {
+			return i, fmt.Errorf("failed to archive %s: %w", key.id, err)
+		}
+	}
+	return len(evict), nil
+}
+
+// archivable reports whether a proposal is finalized: in a status no
+// transaction changes anymore, and with its result anchored when this node
+// anchors results. Passed proposals stay until they execute, as an archived
+// record is never written again.
+func (d *DAO) archivable(proposal *Proposal) bool {
+	switch proposal.Status {
+	case ProposalStatusRejected, ProposalStatusExecuted, ProposalStatusCancelled:
+	default:
+		return false
+	}
+
+	return d.anchoring == nil || !hasFinalResults(proposal) || proposal.ResultHash != (types.Hash{})
+}
+
+// archiveRecord writes a record to the archive and drops it from the state
+func (d *DAO) archiveRecord(key archiveKey) error {
+	gs := d.GovernanceState
+
+	if key.kind == archiveTreasuryTx {
+		tx := gs.Treasury.Transactions[key.id]
+		values := map[string]interface{}{"treasury_tx/" + key.id.String(): tx}
+		if err := d.Archive.write(key, tx, values); err != nil {
+			return err
+		}
+		delete(gs.Treasury.Transactions, key.id)
+		return nil
+	}
+
+	record := &archivedProposal{Proposal: gs.Proposals[key.id], Votes: gs.Votes[key.id]}
+	values := map[string]interface{}{ProposalStateKey(key.id): record.Proposal}
+	for voter, vote := range record.Votes {
+		values[VoteStateKey(key.id, voter)] = vote
+	}
+	if err := d.Archive.write(key, record, values); err != nil {
+		return err
+	}
+
+	// Who voted stays in the voter set, which is kept for every proposal,
+	// and the proposal stays in the index so pages still list it
+	delete(gs.Proposals, key.id)
+	delete(gs.Votes, key.id)
+	return nil
+}
+
+// The accessors below read archived records back, so lookups give the same
+// answer whatever the memory-bounded mode moved to disk. They leave the
+// order records are archived in alone: what is archived depends on the
+// local clock and reads, and must never change how transactions apply.
+// Undecided proposals, proposals whose result waits to be anchored and
+// pending treasury transactions are never archived, so code that only deals
+// with those may read and change the maps directly.
+
+// Proposal returns a proposal, reading it back from the archive in the
+// memory-bounded mode
+func (gs *GovernanceState) Proposal(id types.Hash) (*Proposal, bool) {
+	if proposal, exists := gs.Proposals[id]; exists {
+		return proposal, true
+	}
+	if gs.archive == nil {
+		return nil, false
+	}
+
+	proposal, _, archived, _ := gs.archive.Proposal(id)
+	return proposal, archived
+}
+
+// residentProposal returns a proposal for a transaction to change, moving
+// it back into memory with its votes when it was archived. Rejected and
+// cancelled proposals are archived, but an appeal may reopen them.
+func (gs *GovernanceState) residentProposal(id types.Hash) (*Proposal, bool) {
+	if proposal, exists := gs.Proposals[id]; exists {
+		return proposal, true
+	}
+	if gs.archive == nil {
+		return nil, false
+	}
+
+	proposal, votes, archived, _ := gs.archive.restore(id)
+	if !archived {
+		return nil, false
+	}
+	gs.Proposals[id] = proposal
+	if votes != nil {
+		gs.Votes[id] = votes
+	}
+	return proposal, true
+}
+
+// ProposalVotes returns the votes of a proposal, reading them back from the
+// archive in the memory-bounded mode
+func (gs *GovernanceState) ProposalVotes(id types.Hash) (map[string]*Vote, bool) {
+	if votes, exists := gs.Votes[id]; exists {
+		return votes, true
+	}
+	if gs.archive == nil {
+		return nil, false
+	}
+
+	_, votes, archived, _ := gs.archive.Proposal(id)
+	return votes, archived && votes != nil
+}
+
+// ProposalCount returns the number of proposals, archived ones included
+func (gs *GovernanceState) ProposalCount() int {
+	if gs.archive == nil {
+		return len(gs.Proposals)
+	}
+	return len(gs.Proposals) + gs.archive.count(archiveProposal)
+}
+
+// EachProposal calls fn with every proposal and its votes, archived ones
+// included, until fn returns false. Archived records are read back one at a
+// time, so going through them keeps the memory bound.
+func (gs *GovernanceState) EachProposal(fn func(id types.Hash, proposal *Proposal, votes map[string]*Vote) bool) {
+	for id, proposal := range gs.Proposals {
+		if !fn(id, proposal, gs.Votes[id]) {
+			return
+		}
+	}
+	if gs.archive == nil {
+		return
+	}
+
+	for _, id := range gs.archive.ids(archiveProposal) {
+		value, archived, _ := gs.archive.peek(archiveKey{archiveProposal, id}, &archivedProposal{})
+		if !archived {
+			continue
+		}
+		record := value.(*archivedProposal)
+		if !fn(id, record.Proposal, record.Votes) {
+			return
+		}
+	}
+}
+
+// ProposalIDs returns the IDs of every proposal, archived ones included, so
+// readers can go through them one at a time
+func (gs *GovernanceState) ProposalIDs() []types.Hash {
+	ids := make([]types.Hash, 0, len(gs.Proposals))
+	for id := range gs.Proposals {
+		ids = append(ids, id)
+	}
+	if gs.archive != nil {
+		ids = append(ids, gs.archive.ids(archiveProposal)...)
+	}
+	return ids
+}
+
+// TreasuryTransactionIDs returns the IDs of every treasury transaction,
+// archived ones included
+func (gs *GovernanceState) TreasuryTransactionIDs() []types.Hash {
+	ids := make([]types.Hash, 0, len(gs.Treasury.Transactions))
+	for id := range gs.Treasury.Transactions {
+		ids = append(ids, id)
+	}
+	if gs.archive != nil {
+		ids = append(ids, gs.archive.ids(archiveTreasuryTx)...)
+	}
+	return ids
+}
+
+// TreasuryTransaction returns a treasury transaction, reading it back from
+// the archive in the memory-bounded mode
+func (gs *GovernanceState) TreasuryTransaction(id types.Hash) (*PendingTx, bool) {
+	if tx, exists := gs.Treasury.Transactions[id]; exists {
+		return tx, true
+	}
+	if gs.archive == nil {
+		return nil, false
+	}
+
+	tx, archived, _ := gs.archive.TreasuryTx(id)
+	return tx, archived
+}
+
+// EachTreasuryTransaction calls fn with every treasury transaction, archived
+// ones included and read back one at a time, until fn returns false
+func (gs *GovernanceState) EachTreasuryTransaction(fn func(id types.Hash, tx *PendingTx) bool) {
+	for id, tx := range gs.Treasury.Transactions {
+		if !fn(id, tx) {
+			return
+		}
+	}
+	if gs.archive == nil {
+		return
+	}
+
+	for _, id := range gs.archive.ids(archiveTreasuryTx) {
+		value, archived, _ := gs.archive.peek(archiveKey{archiveTreasuryTx, id}, &PendingTx{})
+		if archived && !fn(id, value.(*PendingTx)) {
+			return
+		}
+	}
+}
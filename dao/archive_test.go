@@ -0,0 +1,254 @@
+package dao
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addArchiveProposal adds a proposal in status that ended at endTime, with
+// one vote
+func addArchiveProposal(d *DAO, n byte, status ProposalStatus, endTime int64) types.Hash {
+	id := types.Hash{n}
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	d.GovernanceState.Proposals[id] = &Proposal{
+		ID:        id,
+		Creator:   voter,
+		Title:     fmt.Sprintf("Proposal %d", n),
+		Status:    status,
+		StartTime: endTime - 100,
+		EndTime:   endTime,
+		Results:   &VoteResults{YesVotes: 10, TotalVoters: 1, Passed: status != ProposalStatusRejected},
+	}
+	d.GovernanceState.ResetVotes(id)
+	d.GovernanceState.Votes[id][voter.String()] = &Vote{Voter: voter, Choice: VoteChoiceYes, Weight: 10, Timestamp: endTime - 50}
+	d.GovernanceState.RecordVoter(id, voter.String())
+	return id
+}
+
+func TestDAO_ArchiveFinalized(t *testing.T) {
+	d := NewDAO("TEST", "Test Token", 18)
+	now := time.Now().Unix()
+
+	oldest := addArchiveProposal(d, 1, ProposalStatusExecuted, now-3000)
+	rejected := addArchiveProposal(d, 2, ProposalStatusRejected, now-2000)
+	latest := addArchiveProposal(d, 3, ProposalStatusExecuted, now-1000)
+	active := addArchiveProposal(d, 4, ProposalStatusActive, now+1000)
+	recentlyPassed := addArchiveProposal(d, 5, ProposalStatusPassed, now-500)
+
+	for i, executedAt := range []int64{now - 2000, now - 1000} {
+		id := types.Hash{0xf0, byte(i)}
+		d.GovernanceState.Treasury.Transactions[id] = &PendingTx{ID: id, Amount: 100, Purpose: "grant", Executed: true, ExecutedAt: executedAt}
+	}
+	pendingTx := types.Hash{0xf0, 9}
+	d.GovernanceState.Treasury.Transactions[pendingTx] = &PendingTx{ID: pendingTx, Amount: 50, ExpiresAt: now + 3600}
+
+	archive, err := d.EnableArchive(ArchiveConfig{Dir: t.TempDir(), MaxProposals: 1, MaxTreasuryTxs: 1})
+	require.NoError(t, err)
+
+	root, err := d.StateRoot()
+	require.NoError(t, err)
+
+	archived, err := d.ArchiveFinalized()
+	require.NoError(t, err)
+	assert.Equal(t, 3, archived)
+
+	// Archived entries stay committed
+	after, err := d.StateRoot()
+	require.NoError(t, err)
+	assert.Equal(t, root, after)
+
+	for _, id := range []types.Hash{oldest, rejected} {
+		assert.NotContains(t, d.GovernanceState.Proposals, id)
+		assert.NotContains(t, d.GovernanceState.Votes, id)
+	}
+	for _, id := range []types.Hash{latest, active, recentlyPassed} {
+		assert.Contains(t, d.GovernanceState.Proposals, id)
+	}
+	assert.Len(t, d.GovernanceState.Treasury.Transactions, 2)
+	assert.Contains(t, d.GovernanceState.Treasury.Transactions, pendingTx)
+
+	// Archived records read back on demand, then from the cache
+	proposal, err := d.GetProposal(rejected)
+	require.NoError(t, err)
+	assert.Equal(t, "Proposal 2", proposal.Title)
+	assert.Equal(t, ProposalStatusRejected, proposal.Status)
+	votes, err := d.GetVotes(rejected)
+	require.NoError(t, err)
+	assert.Len(t, votes, 1)
+	tx, exists := d.GetTreasuryTransaction(types.Hash{0xf0, 0})
+	require.True(t, exists)
+	assert.Equal(t, "grant", tx.Purpose)
+
+	stats := archive.Stats()
+	assert.Equal(t, 2, stats.ArchivedProposals)
+	assert.Equal(t, 1, stats.ArchivedTreasuryTxs)
+	assert.Equal(t, 1, stats.ResidentProposals)
+	assert.Equal(t, uint64(2), stats.DiskReads)
+	assert.Equal(t, uint64(1), stats.CacheHits)
+
+	_, err = d.GetProposal(types.Hash{0xee})
+	assert.Equal(t, ErrProposalNotFoundError, err)
+
+	// Archived proposals still satisfy dependencies
+	assert.NoError(t, d.Dependencies.Check(types.Hash{0xaa}, []types.Hash{oldest}))
+	d.Dependencies.Add(types.Hash{0xaa}, []types.Hash{oldest})
+	assert.Empty(t, d.Dependencies.Blocking(types.Hash{0xaa}))
+
+	// Voters of archived proposals are still known
+	for voter := range votes {
+		assert.True(t, d.GovernanceState.HasVoted(rejected, voter))
+	}
+}
+
+func TestDAO_ArchiveFinalizedLeastRecentlyUsed(t *testing.T) {
+	d := NewDAO("TEST", "Test Token", 18)
+	now := time.Now().Unix()
+
+	first := addArchiveProposal(d, 1, ProposalStatusExecuted, now-3000)
+	second := addArchiveProposal(d, 2, ProposalStatusExecuted, now-2000)
+
+	_, err := d.EnableArchive(ArchiveConfig{Dir: t.TempDir(), MaxProposals: 2})
+	require.NoError(t, err)
+
+	archived, err := d.ArchiveFinalized()
+	require.NoError(t, err)
+	assert.Equal(t, 0, archived)
+
+	// Reading the first proposal makes the second the least recently used
+	_, err = d.GetProposal(first)
+	require.NoError(t, err)
+	third := addArchiveProposal(d, 3, ProposalStatusRejected, now-1000)
+
+	archived, err = d.ArchiveFinalized()
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+	assert.NotContains(t, d.GovernanceState.Proposals, second)
+	assert.Contains(t, d.GovernanceState.Proposals, first)
+	assert.Contains(t, d.GovernanceState.Proposals, third)
+}
+
+func TestDAO_ArchiveFinalizedKeepsPassed(t *testing.T) {
+	d := NewDAO("TEST", "Test Token", 18)
+	now := time.Now().Unix()
+
+	// Passed proposals still execute, however long ago they ended
+	passed := addArchiveProposal(d, 1, ProposalStatusPassed, now-7*24*3600)
+	addArchiveProposal(d, 2, ProposalStatusExecuted, now-60)
+	addArchiveProposal(d, 3, ProposalStatusExecuted, now-30)
+
+	_, err := d.EnableArchive(ArchiveConfig{Dir: t.TempDir(), MaxProposals: 1})
+	require.NoError(t, err)
+
+	archived, err := d.ArchiveFinalized()
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+	assert.Contains(t, d.GovernanceState.Proposals, passed)
+}
+
+func TestDAO_ArchivedRecordsReadEverywhere(t *testing.T) {
+	d := NewDAO("TEST", "Test Token", 18)
+	now := time.Now().Unix()
+
+	first := addArchiveProposal(d, 1, ProposalStatusExecuted, now-3000)
+	second := addArchiveProposal(d, 2, ProposalStatusRejected, now-2000)
+	for i, executedAt := range []int64{now - 2000, now - 1000} {
+		id := types.Hash{0xf0, byte(i)}
+		d.GovernanceState.Treasury.Transactions[id] = &PendingTx{ID: id, Amount: 100, Purpose: "grant", Executed: true, ExecutedAt: executedAt}
+	}
+
+	archive, err := d.EnableArchive(ArchiveConfig{Dir: t.TempDir(), MaxProposals: 2, MaxTreasuryTxs: 1})
+	require.NoError(t, err)
+	archived, err := d.ArchiveFinalized()
+	require.NoError(t, err)
+	require.Equal(t, 1, archived)
+
+	// Reading through the state leaves the order of archiving alone, so the
+	// first proposal is still the least recently used
+	_, exists := d.GovernanceState.Proposal(first)
+	require.True(t, exists)
+	addArchiveProposal(d, 3, ProposalStatusExecuted, now-500)
+
+	archived, err = d.ArchiveFinalized()
+	require.NoError(t, err)
+	require.Equal(t, 1, archived)
+	require.NotContains(t, d.GovernanceState.Proposals, first)
+	require.Contains(t, d.GovernanceState.Proposals, second)
+
+	assert.Len(t, d.ListAllProposals(), 3)
+	assert.Len(t, d.GetTreasuryHistory(), 2)
+	assert.Equal(t, 3, d.GovernanceState.ProposalCount())
+
+	// Going through every record reads archived ones back without keeping
+	// them
+	votes := 0
+	d.GovernanceState.EachProposal(func(_ types.Hash, _ *Proposal, proposalVotes map[string]*Vote) bool {
+		votes += len(proposalVotes)
+		return true
+	})
+	assert.Equal(t, 3, votes)
+	assert.Empty(t, archive.cache.elements)
+
+	rows := 0
+	require.NoError(t, d.Export(ExportVotes, func(row []interface{}) error {
+		rows++
+		return nil
+	}))
+	assert.Equal(t, 3, rows)
+}
+
+func TestGovernanceState_ResidentProposal(t *testing.T) {
+	d := NewDAO("TEST", "Test Token", 18)
+	now := time.Now().Unix()
+
+	rejected := addArchiveProposal(d, 1, ProposalStatusRejected, now-2000)
+	addArchiveProposal(d, 2, ProposalStatusExecuted, now-1000)
+
+	_, err := d.EnableArchive(ArchiveConfig{Dir: t.TempDir(), MaxProposals: 1})
+	require.NoError(t, err)
+
+	root, err := d.StateRoot()
+	require.NoError(t, err)
+	_, err = d.ArchiveFinalized()
+	require.NoError(t, err)
+	require.NotContains(t, d.GovernanceState.Proposals, rejected)
+
+	// An appeal moves the proposal back into memory to change it
+	proposal, exists := d.GovernanceState.residentProposal(rejected)
+	require.True(t, exists)
+	assert.Equal(t, "Proposal 1", proposal.Title)
+	assert.Contains(t, d.GovernanceState.Proposals, rejected)
+	assert.Len(t, d.GovernanceState.Votes[rejected], 1)
+	assert.Equal(t, 0, d.Archive.Stats().ArchivedProposals)
+
+	after, err := d.StateRoot()
+	require.NoError(t, err)
+	assert.Equal(t, root, after)
+}
+
+func TestNewArchive(t *testing.T) {
+	_, err := NewArchive(ArchiveConfig{})
+	assert.Error(t, err)
+
+	// Records left in the directory are kept
+	dir := t.TempDir()
+	_, err = NewArchive(ArchiveConfig{Dir: dir})
+	require.NoError(t, err)
+	leftover := filepath.Join(dir, "proposals", "leftover")
+	require.NoError(t, os.WriteFile(leftover, []byte("record"), 0o644))
+	_, err = NewArchive(ArchiveConfig{Dir: dir})
+	require.NoError(t, err)
+	assert.FileExists(t, leftover)
+
+	d := NewDAO("TEST", "Test Token", 18)
+	archived, err := d.ArchiveFinalized()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, archived)
+}
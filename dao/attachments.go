@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// DocumentEncryptionAlgorithm is the cipher of encrypted proposal documents
+const DocumentEncryptionAlgorithm = "AES-256-GCM"
+
+// DocumentEncryption describes an encrypted document. The content is
+// encrypted with a random key that is wrapped for every member of the
+// allowed roles with crypto.SealFor, so clients decrypt it with their own
+// key without the server.
+type DocumentEncryption struct {
+	Algorithm string `json:"algorithm"`
+	Nonce     string `json:"nonce"` // Hex AES-GCM nonce of the content
+	Roles     []Role `json:"roles"`
+	// WrappedKeys maps recipient public keys to their hex sealed content key
+	WrappedKeys map[string]string `json:"wrapped_keys"`
+}
+
+// CanDecrypt reports whether the content key is wrapped for a user
+func (e *DocumentEncryption) CanDecrypt(user crypto.PublicKey) bool {
+	_, exists := e.WrappedKeys[user.String()]
+	return exists
+}
+
+// Recipients returns the users the content key is wrapped for
+func (e *DocumentEncryption) Recipients() []string {
+	recipients := make([]string, 0, len(e.WrappedKeys))
+	for recipient := range e.WrappedKeys {
+		recipients = append(recipients, recipient)
+	}
+	sort.Strings(recipients)
+	return recipients
+}
+
+// EncryptDocument encrypts data with a fresh content key wrapped for each
+// recipient
+func EncryptDocument(data []byte, roles []Role, recipients []crypto.PublicKey) ([]byte, *DocumentEncryption, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, nil, err
+	}
+
+	gcm, err := documentCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+
+	encryption := &DocumentEncryption{
+		Algorithm:   DocumentEncryptionAlgorithm,
+		Nonce:       hex.EncodeToString(nonce),
+		Roles:       roles,
+		WrappedKeys: make(map[string]string),
+	}
+	for _, recipient := range recipients {
+		if err := encryption.wrapKey(key, recipient); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return gcm.Seal(nil, nonce, data, nil), encryption, nil
+}
+
+// DecryptDocument decrypts an encrypted document with the key of one of
+// its recipients
+func DecryptDocument(ciphertext []byte, encryption *DocumentEncryption, key crypto.PrivateKey) ([]byte, error) {
+	contentKey, err := encryption.unwrapKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := documentCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(encryption.Nonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, NewDAOError(ErrInvalidProposal, "invalid document nonce", nil)
+	}
+
+	data, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, NewDAOError(ErrInvalidProposal, "document content does not match its encryption", nil)
+	}
+	return data, nil
+}
+
+// ShareKey wraps the content key for recipient using the key of a current
+// recipient
+func (e *DocumentEncryption) ShareKey(holder crypto.PrivateKey, recipient crypto.PublicKey) error {
+	contentKey, err := e.unwrapKey(holder)
+	if err != nil {
+		return err
+	}
+	return e.wrapKey(contentKey, recipient)
+}
+
+func (e *DocumentEncryption) wrapKey(contentKey []byte, recipient crypto.PublicKey) error {
+	sealed, err := crypto.SealFor(recipient, contentKey)
+	if err != nil {
+		return err
+	}
+	e.WrappedKeys[recipient.String()] = hex.EncodeToString(sealed)
+	return nil
+}
+
+func (e *DocumentEncryption) unwrapKey(key crypto.PrivateKey) ([]byte, error) {
+	wrapped, exists := e.WrappedKeys[key.PublicKey().String()]
+	if !exists {
+		return nil, NewDAOError(ErrUnauthorized, "document key is not shared with this user", nil)
+	}
+
+	sealed, err := hex.DecodeString(wrapped)
+	if err != nil {
+		return nil, NewDAOError(ErrInvalidProposal, "invalid wrapped document key", nil)
+	}
+	return key.Open(sealed)
+}
+
+func documentCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,169 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIPFSNode serves the version, add and cat calls of the IPFS node API from memory
+func newTestIPFSNode(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	content := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/version"):
+			json.NewEncoder(w).Encode(map[string]string{"Version": "0.20.0"})
+		case strings.HasSuffix(r.URL.Path, "/add"):
+			reader, err := r.MultipartReader()
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			part, err := reader.NextPart()
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(part)
+			digest := sha256.Sum256(data)
+			cid := "Qm" + hex.EncodeToString(digest[:])
+			content[cid] = data
+			json.NewEncoder(w).Encode(map[string]string{"Hash": cid})
+		case strings.HasSuffix(r.URL.Path, "/cat"):
+			data, exists := content[r.URL.Query().Get("arg")]
+			if !exists {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"Message": "not found"})
+				return
+			}
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func setupEncryptedDocuments(t *testing.T) (*DAO, crypto.PrivateKey, crypto.PrivateKey) {
+	node := newTestIPFSNode(t)
+	dao := NewDAO("GOV", "Governance Token", 18)
+	dao.IPFSClient = NewIPFSClient(strings.TrimPrefix(node.URL, "http://"))
+
+	admin := crypto.GeneratePrivateKey()
+	dao.SecurityManager.accessControl[admin.PublicKey().String()] = &AccessControlEntry{
+		User:        admin.PublicKey(),
+		Role:        RoleAdmin,
+		Permissions: dao.SecurityManager.rolePermissions[RoleAdmin],
+		GrantedBy:   admin.PublicKey(),
+		GrantedAt:   time.Now().Unix(),
+		Active:      true,
+	}
+
+	signer := crypto.GeneratePrivateKey()
+	require.NoError(t, dao.GrantRole(signer.PublicKey(), RoleModerator, admin.PublicKey(), 0))
+
+	return dao, admin, signer
+}
+
+func TestEncryptDocument(t *testing.T) {
+	alice, bob := crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()
+	data := []byte("signer rotation plan")
+
+	ciphertext, encryption, err := EncryptDocument(data, []Role{RoleAdmin}, []crypto.PublicKey{alice.PublicKey()})
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "rotation")
+	assert.Equal(t, DocumentEncryptionAlgorithm, encryption.Algorithm)
+	assert.True(t, encryption.CanDecrypt(alice.PublicKey()))
+
+	decrypted, err := DecryptDocument(ciphertext, encryption, alice)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, err = DecryptDocument(ciphertext, encryption, bob)
+	assert.Error(t, err)
+
+	require.NoError(t, encryption.ShareKey(alice, bob.PublicKey()))
+	decrypted, err = DecryptDocument(ciphertext, encryption, bob)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+	assert.Len(t, encryption.Recipients(), 2)
+}
+
+func TestDAO_EncryptedProposalDocument(t *testing.T) {
+	dao, admin, signer := setupEncryptedDocuments(t)
+	data := []byte("multisig signer addresses")
+
+	_, err := dao.UploadEncryptedProposalDocument("signers.txt", data, "text/plain", admin.PublicKey(), RoleSuperAdmin)
+	assert.Error(t, err)
+
+	docRef, err := dao.UploadEncryptedProposalDocument("signers.txt", data, "text/plain", admin.PublicKey(), RoleAdmin, RoleModerator)
+	require.NoError(t, err)
+	require.NotNil(t, docRef.Encryption)
+	assert.ElementsMatch(t, []string{admin.PublicKey().String(), signer.PublicKey().String()}, docRef.Encryption.Recipients())
+
+	// IPFS only holds the ciphertext
+	stored, err := dao.RetrieveProposalDocument(docRef)
+	require.NoError(t, err)
+	assert.NotEqual(t, data, stored)
+
+	decrypted, err := dao.RetrieveEncryptedProposalDocument(docRef, signer)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	outsider := crypto.GeneratePrivateKey()
+	_, err = dao.RetrieveEncryptedProposalDocument(docRef, outsider)
+	assert.Error(t, err)
+
+	// Revoked members lose access even though they hold a wrapped key
+	require.NoError(t, dao.RevokeRole(signer.PublicKey(), admin.PublicKey()))
+	_, err = dao.RetrieveEncryptedProposalDocument(docRef, signer)
+	assert.Error(t, err)
+}
+
+func TestDAO_DistributeDocumentKeys(t *testing.T) {
+	dao, admin, signer := setupEncryptedDocuments(t)
+
+	docRef, err := dao.UploadEncryptedProposalDocument("budget.csv", []byte("q3,1200"), "text/csv", admin.PublicKey(), RoleModerator)
+	require.NoError(t, err)
+
+	// Admins are not moderators so cannot distribute keys
+	_, err = dao.DistributeDocumentKeys(docRef, admin)
+	assert.Error(t, err)
+
+	newSigner := crypto.GeneratePrivateKey()
+	require.NoError(t, dao.GrantRole(newSigner.PublicKey(), RoleModerator, admin.PublicKey(), 0))
+	_, err = dao.RetrieveEncryptedProposalDocument(docRef, newSigner)
+	assert.Error(t, err)
+
+	added, err := dao.DistributeDocumentKeys(docRef, signer)
+	require.NoError(t, err)
+	assert.Equal(t, []crypto.PublicKey{newSigner.PublicKey()}, added)
+
+	decrypted, err := dao.RetrieveEncryptedProposalDocument(docRef, newSigner)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("q3,1200"), decrypted)
+
+	// Keys of members who lost the role are dropped
+	require.NoError(t, dao.RevokeRole(signer.PublicKey(), admin.PublicKey()))
+	_, err = dao.DistributeDocumentKeys(docRef, newSigner)
+	require.NoError(t, err)
+	assert.False(t, docRef.Encryption.CanDecrypt(signer.PublicKey()))
+}
@@ -0,0 +1,147 @@
+package dao
+
+import "sync"
+
+// balanceShardCount is the number of lock stripes of a BalanceStore, a power
+// of two so an address hash picks its shard with a mask
+const balanceShardCount = 64
+
+// BalanceStore holds token balances by address. Addresses are spread over
+// lock-striped shards so that operations on different holders rarely
+// contend, and every operation on one address is atomic. Like the map it
+// replaces, it keeps addresses whose balance dropped to zero until they are
+// deleted.
+type BalanceStore struct {
+	shards [balanceShardCount]balanceShard
+}
+
+type balanceShard struct {
+	mu       sync.RWMutex
+	balances map[string]uint64
+	_        [32]byte // Keeps shards on separate cache lines
+}
+
+// NewBalanceStore creates an empty balance store
+func NewBalanceStore() *BalanceStore {
+	bs := &BalanceStore{}
+	for i := range bs.shards {
+		bs.shards[i].balances = make(map[string]uint64)
+	}
+	return bs
+}
+
+// shard returns the shard of address, picked by its FNV-1a hash
+func (bs *BalanceStore) shard(address string) *balanceShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(address); i++ {
+		hash ^= uint32(address[i])
+		hash *= 16777619
+	}
+	return &bs.shards[hash&(balanceShardCount-1)]
+}
+
+// Get returns the balance of address, zero when it has none
+func (bs *BalanceStore) Get(address string) uint64 {
+	balance, _ := bs.Lookup(address)
+	return balance
+}
+
+// Lookup returns the balance of address and whether it is held at all
+func (bs *BalanceStore) Lookup(address string) (uint64, bool) {
+	shard := bs.shard(address)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	balance, exists := shard.balances[address]
+	return balance, exists
+}
+
+// Set replaces the balance of address
+func (bs *BalanceStore) Set(address string, balance uint64) {
+	shard := bs.shard(address)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.balances[address] = balance
+}
+
+// Add credits amount to address and returns the new balance
+func (bs *BalanceStore) Add(address string, amount uint64) uint64 {
+	shard := bs.shard(address)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.balances[address] += amount
+	return shard.balances[address]
+}
+
+// Sub debits amount from address and returns the new balance. Callers check
+// the balance first; Debit does both at once.
+func (bs *BalanceStore) Sub(address string, amount uint64) uint64 {
+	shard := bs.shard(address)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.balances[address] -= amount
+	return shard.balances[address]
+}
+
+// Debit takes amount from address if it holds enough and reports whether it
+// did
+func (bs *BalanceStore) Debit(address string, amount uint64) bool {
+	shard := bs.shard(address)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if shard.balances[address] < amount {
+		return false
+	}
+	shard.balances[address] -= amount
+	return true
+}
+
+// Delete drops address from the store
+func (bs *BalanceStore) Delete(address string) {
+	shard := bs.shard(address)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	delete(shard.balances, address)
+}
+
+// Len returns the number of addresses in the store
+func (bs *BalanceStore) Len() int {
+	total := 0
+	for i := range bs.shards {
+		shard := &bs.shards[i]
+		shard.mu.RLock()
+		total += len(shard.balances)
+		shard.mu.RUnlock()
+	}
+	return total
+}
+
+// Snapshot copies every balance for iteration. All shards are read locked
+// together, so the copy is of a single point in time.
+func (bs *BalanceStore) Snapshot() map[string]uint64 {
+	for i := range bs.shards {
+		bs.shards[i].mu.RLock()
+	}
+	defer func() {
+		for i := range bs.shards {
+			bs.shards[i].mu.RUnlock()
+		}
+	}()
+
+	size := 0
+	for i := range bs.shards {
+		size += len(bs.shards[i].balances)
+	}
+	snapshot := make(map[string]uint64, size)
+	for i := range bs.shards {
+		for address, balance := range bs.shards[i].balances {
+			snapshot[address] = balance
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,141 @@
+package dao
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeHolderCount is the holder count the DAO is sized for
+const largeHolderCount = 1000000
+
+func TestBalanceStore(t *testing.T) {
+	store := NewBalanceStore()
+
+	_, exists := store.Lookup("alice")
+	assert.False(t, exists)
+	assert.Equal(t, uint64(0), store.Get("alice"))
+
+	store.Set("alice", 100)
+	assert.Equal(t, uint64(150), store.Add("alice", 50))
+	assert.Equal(t, uint64(120), store.Sub("alice", 30))
+	assert.False(t, store.Debit("alice", 121))
+	assert.True(t, store.Debit("alice", 120))
+
+	// Emptied addresses stay until deleted, like map entries
+	balance, exists := store.Lookup("alice")
+	assert.True(t, exists)
+	assert.Equal(t, uint64(0), balance)
+	store.Delete("alice")
+	_, exists = store.Lookup("alice")
+	assert.False(t, exists)
+
+	for i := 0; i < 1000; i++ {
+		store.Set(fmt.Sprintf("holder-%d", i), uint64(i))
+	}
+	assert.Equal(t, 1000, store.Len())
+
+	// Snapshots are copies, later changes do not reach them
+	snapshot := store.Snapshot()
+	require.Len(t, snapshot, 1000)
+	store.Add("holder-7", 1)
+	assert.Equal(t, uint64(7), snapshot["holder-7"])
+	assert.Equal(t, uint64(8), store.Get("holder-7"))
+}
+
+func TestBalanceStoreConcurrentTransfers(t *testing.T) {
+	token := NewGovernanceToken("GOV", "Governance Token", 18)
+	const holders = 64
+	for i := 0; i < holders; i++ {
+		require.NoError(t, token.Mint(fmt.Sprintf("holder-%d", i), 1000))
+	}
+
+	// Concurrent transfers never overdraw and keep the supply
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				from := fmt.Sprintf("holder-%d", (worker*7+i)%holders)
+				to := fmt.Sprintf("holder-%d", (worker*13+i*3+1)%holders)
+				token.Transfer(from, to, uint64(i%300))
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	total := uint64(0)
+	for _, balance := range token.Balances.Snapshot() {
+		total += balance
+	}
+	assert.Equal(t, token.TotalSupply, total)
+}
+
+func largeBalanceStore() (*BalanceStore, []string) {
+	addresses := make([]string, largeHolderCount)
+	store := NewBalanceStore()
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("%064x", i)
+		store.Set(addresses[i], uint64(i))
+	}
+	return store, addresses
+}
+
+func BenchmarkBalanceStoreParallel(b *testing.B) {
+	store, addresses := largeBalanceStore()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			address := addresses[(i*7919)%len(addresses)]
+			if i%4 == 0 {
+				store.Add(address, 1)
+			} else {
+				store.Get(address)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkLockedMapParallel is the single locked map the store replaces
+func BenchmarkLockedMapParallel(b *testing.B) {
+	_, addresses := largeBalanceStore()
+	var mu sync.RWMutex
+	balances := make(map[string]uint64, len(addresses))
+	for i, address := range addresses {
+		balances[address] = uint64(i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			address := addresses[(i*7919)%len(addresses)]
+			if i%4 == 0 {
+				mu.Lock()
+				balances[address]++
+				mu.Unlock()
+			} else {
+				mu.RLock()
+				_ = balances[address]
+				mu.RUnlock()
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkBalanceStoreSnapshot(b *testing.B) {
+	store, _ := largeBalanceStore()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store.Snapshot()
+	}
+}
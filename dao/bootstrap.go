@@ -0,0 +1,470 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// FounderPower represents a temporary power held by founders during bootstrap
+type FounderPower string
+
+const (
+	FounderPowerVeto      FounderPower = "veto"       // Reject any proposal that has not been executed
+	FounderPowerFastTrack FounderPower = "fast_track" // Shorten the voting period of a proposal
+)
+
+// MilestoneType represents the condition that triggers a decentralization milestone
+type MilestoneType byte
+
+const (
+	MilestoneTypeTime         MilestoneType = 0x01 // Target is a unix timestamp
+	MilestoneTypeMemberCount  MilestoneType = 0x02 // Target is a number of token holders
+	MilestoneTypeDistribution MilestoneType = 0x03 // Target is the max founder supply share in basis points
+)
+
+// DecentralizationMilestone sunsets a set of founder powers once its target is reached
+type DecentralizationMilestone struct {
+	ID        string         `json:"id"`
+	Type      MilestoneType  `json:"type"`
+	Target    int64          `json:"target"`
+	Powers    []FounderPower `json:"powers"`
+	Reached   bool           `json:"reached"`
+	ReachedAt int64          `json:"reached_at"`
+}
+
+// BootstrapPlan is the progressive decentralization schedule of a young DAO
+type BootstrapPlan struct {
+	Founders   []crypto.PublicKey           `json:"founders"`
+	Milestones []*DecentralizationMilestone `json:"milestones"`
+	StartedBy  crypto.PublicKey             `json:"started_by"`
+	StartedAt  int64                        `json:"started_at"`
+	EndedAt    int64                        `json:"ended_at"` // Set once every founder power has sunset
+}
+
+// BootstrapProgress is a snapshot of the values milestones are measured against
+type BootstrapProgress struct {
+	Now                int64  `json:"now"`
+	MemberCount        int64  `json:"member_count"`
+	FounderSupplyShare uint64 `json:"founder_supply_share"` // Basis points of total supply held by founders
+}
+
+// MilestoneCountdown reports how far a milestone is from being reached
+type MilestoneCountdown struct {
+	*DecentralizationMilestone
+	Current   int64 `json:"current"`
+	Remaining int64 `json:"remaining"` // Seconds, members or basis points left, depending on type
+}
+
+// BootstrapStatus is the public view of the decentralization schedule
+type BootstrapStatus struct {
+	Active       bool                  `json:"active"`
+	Founders     []string              `json:"founders"`
+	ActivePowers []FounderPower        `json:"active_powers"`
+	Milestones   []*MilestoneCountdown `json:"milestones"`
+	Progress     BootstrapProgress     `json:"progress"`
+	StartedAt    int64                 `json:"started_at"`
+	EndedAt      int64                 `json:"ended_at"`
+}
+
+// StartBootstrap installs a progressive decentralization plan. Only one plan
+// may ever be installed, so founder powers cannot be renewed once sunset.
+func (sm *SecurityManager) StartBootstrap(plan *BootstrapPlan, startedBy crypto.PublicKey) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if !sm.hasPermissionInternal(startedBy, PermissionSystemUpgrade) {
+		sm.logAuditEvent(startedBy, "BOOTSTRAP_START_DENIED", "bootstrap", "FAILURE",
+			map[string]interface{}{"reason": "insufficient_permissions"}, SecurityLevelCritical)
+		return NewDAOError(ErrUnauthorized, "insufficient permissions to start bootstrap", nil)
+	}
+
+	if sm.bootstrap != nil {
+		return NewDAOError(ErrBootstrapConfig, "bootstrap plan already installed", nil)
+	}
+
+	if err := validateBootstrapPlan(plan); err != nil {
+		return err
+	}
+
+	plan.StartedBy = startedBy
+	plan.StartedAt = time.Now().Unix()
+	sm.bootstrap = plan
+
+	sm.logAuditEvent(startedBy, "BOOTSTRAP_STARTED", "bootstrap", "SUCCESS",
+		map[string]interface{}{"founders": len(plan.Founders), "milestones": len(plan.Milestones)}, SecurityLevelCritical)
+
+	return nil
+}
+
+// validateBootstrapPlan checks that every milestone is well formed and every
+// founder power eventually sunsets
+func validateBootstrapPlan(plan *BootstrapPlan) error {
+	if plan == nil || len(plan.Founders) == 0 {
+		return NewDAOError(ErrBootstrapConfig, "bootstrap plan must have at least one founder", nil)
+	}
+
+	if len(plan.Milestones) == 0 {
+		return NewDAOError(ErrBootstrapConfig, "bootstrap plan must have at least one milestone", nil)
+	}
+
+	sunset := make(map[FounderPower]bool)
+	hasTimeMilestone := false
+	for _, milestone := range plan.Milestones {
+		if milestone.ID == "" || len(milestone.Powers) == 0 {
+			return NewDAOError(ErrBootstrapConfig, "milestone must have an ID and at least one power", nil)
+		}
+
+		switch milestone.Type {
+		case MilestoneTypeTime:
+			hasTimeMilestone = true
+		case MilestoneTypeMemberCount:
+			if milestone.Target <= 0 {
+				return NewDAOError(ErrBootstrapConfig, "member count milestone target must be positive", nil)
+			}
+		case MilestoneTypeDistribution:
+			if milestone.Target < 0 || milestone.Target >= 10000 {
+				return NewDAOError(ErrBootstrapConfig, "distribution milestone target must be below 10000 basis points", nil)
+			}
+		default:
+			return NewDAOError(ErrBootstrapConfig, "unknown milestone type", nil)
+		}
+
+		for _, power := range milestone.Powers {
+			if power != FounderPowerVeto && power != FounderPowerFastTrack {
+				return NewDAOError(ErrBootstrapConfig, "unknown founder power: "+string(power), nil)
+			}
+			if milestone.Type == MilestoneTypeTime {
+				sunset[power] = true
+			}
+		}
+	}
+
+	// A time milestone guarantees the powers end even if adoption stalls
+	if !hasTimeMilestone || !sunset[FounderPowerVeto] || !sunset[FounderPowerFastTrack] {
+		return NewDAOError(ErrBootstrapConfig, "every founder power must have a time-based sunset", nil)
+	}
+
+	return nil
+}
+
+// UpdateBootstrapMilestones marks milestones reached by the given progress and
+// returns the milestones reached by this call
+func (sm *SecurityManager) UpdateBootstrapMilestones(progress BootstrapProgress) []*DecentralizationMilestone {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.bootstrap == nil || sm.bootstrap.EndedAt != 0 {
+		return nil
+	}
+
+	reached := make([]*DecentralizationMilestone, 0)
+	for _, milestone := range sm.bootstrap.Milestones {
+		if milestone.Reached || !milestoneReached(milestone, progress) {
+			continue
+		}
+
+		milestone.Reached = true
+		milestone.ReachedAt = progress.Now
+		reached = append(reached, milestone)
+
+		sm.logAuditEvent(sm.bootstrap.StartedBy, "BOOTSTRAP_MILESTONE_REACHED", milestone.ID, "SUCCESS",
+			map[string]interface{}{"type": milestone.Type, "target": milestone.Target, "powers": milestone.Powers}, SecurityLevelCritical)
+	}
+
+	if len(sm.activeFounderPowersInternal()) == 0 {
+		sm.bootstrap.EndedAt = progress.Now
+		sm.logAuditEvent(sm.bootstrap.StartedBy, "BOOTSTRAP_ENDED", "bootstrap", "SUCCESS", nil, SecurityLevelCritical)
+	}
+
+	return reached
+}
+
+// milestoneReached reports whether progress satisfies a milestone target
+func milestoneReached(milestone *DecentralizationMilestone, progress BootstrapProgress) bool {
+	switch milestone.Type {
+	case MilestoneTypeTime:
+		return progress.Now >= milestone.Target
+	case MilestoneTypeMemberCount:
+		return progress.MemberCount >= milestone.Target
+	case MilestoneTypeDistribution:
+		return int64(progress.FounderSupplyShare) <= milestone.Target
+	default:
+		return false
+	}
+}
+
+// IsFounderPowerActive reports whether a founder power has not yet sunset
+func (sm *SecurityManager) IsFounderPowerActive(power FounderPower) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	for _, active := range sm.activeFounderPowersInternal() {
+		if active == power {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activeFounderPowersInternal returns founder powers not yet sunset (assumes lock is held)
+func (sm *SecurityManager) activeFounderPowersInternal() []FounderPower {
+	if sm.bootstrap == nil || sm.bootstrap.EndedAt != 0 {
+		return []FounderPower{}
+	}
+
+	sunset := make(map[FounderPower]bool)
+	for _, milestone := range sm.bootstrap.Milestones {
+		if milestone.Reached {
+			for _, power := range milestone.Powers {
+				sunset[power] = true
+			}
+		}
+	}
+
+	active := make([]FounderPower, 0)
+	for _, power := range []FounderPower{FounderPowerVeto, FounderPowerFastTrack} {
+		if !sunset[power] {
+			active = append(active, power)
+		}
+	}
+
+	return active
+}
+
+// CheckFounderPower verifies that a user is a founder and the power has not sunset
+func (sm *SecurityManager) CheckFounderPower(user crypto.PublicKey, power FounderPower, resource string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.bootstrap == nil || !sm.isFounderInternal(user) {
+		sm.logAuditEvent(user, "FOUNDER_POWER_DENIED", resource, "FAILURE",
+			map[string]interface{}{"power": power, "reason": "not_a_founder"}, SecurityLevelCritical)
+		return NewDAOError(ErrUnauthorized, "only founders may use founder powers", nil)
+	}
+
+	active := false
+	for _, p := range sm.activeFounderPowersInternal() {
+		if p == power {
+			active = true
+			break
+		}
+	}
+
+	if !active {
+		sm.logAuditEvent(user, "FOUNDER_POWER_DENIED", resource, "FAILURE",
+			map[string]interface{}{"power": power, "reason": "sunset"}, SecurityLevelCritical)
+		return ErrFounderPowerSunsetError
+	}
+
+	sm.logAuditEvent(user, "FOUNDER_POWER_USED", resource, "SUCCESS",
+		map[string]interface{}{"power": power}, SecurityLevelCritical)
+
+	return nil
+}
+
+// isFounderInternal reports whether a user is listed in the bootstrap plan (assumes lock is held)
+func (sm *SecurityManager) isFounderInternal(user crypto.PublicKey) bool {
+	userStr := user.String()
+	for _, founder := range sm.bootstrap.Founders {
+		if founder.String() == userStr {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBootstrapStatus returns the countdown towards each decentralization milestone
+func (sm *SecurityManager) GetBootstrapStatus(progress BootstrapProgress) *BootstrapStatus {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	status := &BootstrapStatus{
+		Founders:     make([]string, 0),
+		ActivePowers: sm.activeFounderPowersInternal(),
+		Milestones:   make([]*MilestoneCountdown, 0),
+		Progress:     progress,
+	}
+
+	if sm.bootstrap == nil {
+		return status
+	}
+
+	status.Active = sm.bootstrap.EndedAt == 0
+	status.StartedAt = sm.bootstrap.StartedAt
+	status.EndedAt = sm.bootstrap.EndedAt
+
+	for _, founder := range sm.bootstrap.Founders {
+		status.Founders = append(status.Founders, founder.String())
+	}
+
+	for _, milestone := range sm.bootstrap.Milestones {
+		countdown := &MilestoneCountdown{DecentralizationMilestone: milestone}
+
+		switch milestone.Type {
+		case MilestoneTypeTime:
+			countdown.Current = progress.Now
+			countdown.Remaining = milestone.Target - progress.Now
+		case MilestoneTypeMemberCount:
+			countdown.Current = progress.MemberCount
+			countdown.Remaining = milestone.Target - progress.MemberCount
+		case MilestoneTypeDistribution:
+			countdown.Current = int64(progress.FounderSupplyShare)
+			countdown.Remaining = int64(progress.FounderSupplyShare) - milestone.Target
+		}
+
+		if milestone.Reached || countdown.Remaining < 0 {
+			countdown.Remaining = 0
+		}
+
+		status.Milestones = append(status.Milestones, countdown)
+	}
+
+	// Soonest time milestones first, then the rest in plan order
+	sort.SliceStable(status.Milestones, func(i, j int) bool {
+		a, b := status.Milestones[i], status.Milestones[j]
+		if a.Type == MilestoneTypeTime && b.Type == MilestoneTypeTime {
+			return a.Target < b.Target
+		}
+		return a.Type == MilestoneTypeTime && b.Type != MilestoneTypeTime
+	})
+
+	return status
+}
+
+// getBootstrapFounders returns the founders of the installed plan
+func (sm *SecurityManager) getBootstrapFounders() []crypto.PublicKey {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if sm.bootstrap == nil {
+		return nil
+	}
+	return sm.bootstrap.Founders
+}
+
+// StartBootstrap installs a progressive decentralization plan for the DAO
+func (d *DAO) StartBootstrap(plan *BootstrapPlan, startedBy crypto.PublicKey) error {
+	if err := d.SecurityManager.StartBootstrap(plan, startedBy); err != nil {
+		return err
+	}
+
+	// Milestones already satisfied at start sunset immediately
+	d.RefreshBootstrap()
+	return nil
+}
+
+// RefreshBootstrap evaluates milestones against the current DAO state
+func (d *DAO) RefreshBootstrap() []*DecentralizationMilestone {
+	return d.SecurityManager.UpdateBootstrapMilestones(d.bootstrapProgress(time.Now().Unix()))
+}
+
+// GetBootstrapStatus returns the public decentralization countdown
+func (d *DAO) GetBootstrapStatus() *BootstrapStatus {
+	d.RefreshBootstrap()
+	return d.SecurityManager.GetBootstrapStatus(d.bootstrapProgress(time.Now().Unix()))
+}
+
+// bootstrapProgress measures the DAO state milestones are evaluated against
+func (d *DAO) bootstrapProgress(now int64) BootstrapProgress {
+	// Members are addresses holding or staking governance tokens
+	members := make(map[string]bool)
+	for address, balance := range d.TokenState.Balances.Snapshot() {
+		if balance > 0 {
+			members[address] = true
+		}
+	}
+	for address, holder := range d.GovernanceState.TokenHolders {
+		if holder.Staked > 0 {
+			members[address] = true
+		}
+	}
+
+	progress := BootstrapProgress{
+		Now:         now,
+		MemberCount: int64(len(members)),
+	}
+
+	if d.TokenState.TotalSupply == 0 {
+		return progress
+	}
+
+	founderHoldings := uint64(0)
+	for _, founder := range d.SecurityManager.getBootstrapFounders() {
+		founderHoldings += d.TokenState.Balances.Get(founder.String())
+		if holder, exists := d.GovernanceState.TokenHolders[founder.String()]; exists {
+			founderHoldings += holder.Staked
+		}
+	}
+
+	progress.FounderSupplyShare = founderHoldings * 10000 / d.TokenState.TotalSupply
+	if progress.FounderSupplyShare > 10000 {
+		progress.FounderSupplyShare = 10000
+	}
+
+	return progress
+}
+
+// FounderVeto rejects a proposal that has not yet been executed
+func (d *DAO) FounderVeto(proposalID types.Hash, founder crypto.PublicKey, reason string) error {
+	d.RefreshBootstrap()
+
+	proposal, exists := d.GovernanceState.Proposal(proposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+
+	if proposal.Status == ProposalStatusExecuted || proposal.Status == ProposalStatusRejected || proposal.Status == ProposalStatusCancelled {
+		return NewDAOError(ErrInvalidProposal, "proposal can no longer be vetoed", nil)
+	}
+
+	if err := d.SecurityManager.CheckFounderPower(founder, FounderPowerVeto, proposalID.String()); err != nil {
+		return err
+	}
+
+	proposal.Status = ProposalStatusRejected
+	d.Indexes.UpdateProposal(proposalID, proposal)
+	d.SecurityManager.LogAuditEvent(founder, "FOUNDER_VETO", proposalID.String(), "SUCCESS",
+		map[string]interface{}{"reason": reason}, SecurityLevelCritical)
+
+	return nil
+}
+
+// FastTrackProposal shortens the voting period of a pending or active proposal
+// to the given number of seconds, bounded below by the minimum voting period
+func (d *DAO) FastTrackProposal(proposalID types.Hash, founder crypto.PublicKey, votingPeriod int64) error {
+	d.RefreshBootstrap()
+
+	proposal, exists := d.GovernanceState.Proposal(proposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+
+	if proposal.Status != ProposalStatusPending && proposal.Status != ProposalStatusActive {
+		return NewDAOError(ErrVotingClosed, "only pending or active proposals can be fast-tracked", nil)
+	}
+
+	minPeriod := d.ParameterManager.GetParameterConfig().MinVotingPeriod
+	if votingPeriod < minPeriod {
+		return NewDAOError(ErrInvalidTimeframe, "voting period is shorter than the minimum voting period", nil)
+	}
+
+	newEndTime := proposal.StartTime + votingPeriod
+	if newEndTime >= proposal.EndTime {
+		return NewDAOError(ErrInvalidTimeframe, "fast-track must shorten the voting period", nil)
+	}
+
+	if err := d.SecurityManager.CheckFounderPower(founder, FounderPowerFastTrack, proposalID.String()); err != nil {
+		return err
+	}
+
+	proposal.EndTime = newEndTime
+	d.Indexes.UpdateProposal(proposalID, proposal)
+	d.SecurityManager.LogAuditEvent(founder, "FOUNDER_FAST_TRACK", proposalID.String(), "SUCCESS",
+		map[string]interface{}{"end_time": newEndTime}, SecurityLevelCritical)
+
+	return nil
+}
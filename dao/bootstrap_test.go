@@ -0,0 +1,174 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBootstrapDAO(t *testing.T, milestones []*DecentralizationMilestone) (*DAO, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	member := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		founder.String(): 8000,
+		member.String():  2000,
+	}))
+	require.NoError(t, dao.InitializeFounderRoles([]crypto.PublicKey{founder}))
+
+	plan := &BootstrapPlan{
+		Founders:   []crypto.PublicKey{founder},
+		Milestones: milestones,
+	}
+	require.NoError(t, dao.StartBootstrap(plan, founder))
+
+	return dao, founder, member
+}
+
+func defaultMilestones() []*DecentralizationMilestone {
+	return []*DecentralizationMilestone{
+		{ID: "members", Type: MilestoneTypeMemberCount, Target: 3, Powers: []FounderPower{FounderPowerFastTrack}},
+		{ID: "distribution", Type: MilestoneTypeDistribution, Target: 5000, Powers: []FounderPower{FounderPowerVeto}},
+		{ID: "deadline", Type: MilestoneTypeTime, Target: time.Now().Unix() + 86400, Powers: []FounderPower{FounderPowerVeto, FounderPowerFastTrack}},
+	}
+}
+
+func addActiveProposal(dao *DAO, creator crypto.PublicKey) types.Hash {
+	now := time.Now().Unix()
+	id := types.Hash{0xB0}
+	dao.GovernanceState.Proposals[id] = &Proposal{
+		ID:        id,
+		Creator:   creator,
+		Title:     "Bootstrap Proposal",
+		StartTime: now,
+		EndTime:   now + 7*86400,
+		Status:    ProposalStatusActive,
+	}
+	return id
+}
+
+func TestBootstrap_PlanValidation(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitializeFounderRoles([]crypto.PublicKey{founder}))
+
+	// Powers without a time-based sunset are rejected
+	plan := &BootstrapPlan{
+		Founders: []crypto.PublicKey{founder},
+		Milestones: []*DecentralizationMilestone{
+			{ID: "members", Type: MilestoneTypeMemberCount, Target: 10, Powers: []FounderPower{FounderPowerVeto, FounderPowerFastTrack}},
+		},
+	}
+	err := dao.StartBootstrap(plan, founder)
+	require.Error(t, err)
+	assert.Equal(t, ErrBootstrapConfig, err.(*DAOError).Code)
+
+	// Non-admins cannot install a plan
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	plan.Milestones = defaultMilestones()
+	require.Error(t, dao.StartBootstrap(plan, outsider))
+
+	require.NoError(t, dao.StartBootstrap(plan, founder))
+
+	// Only one plan may ever be installed
+	require.Error(t, dao.StartBootstrap(&BootstrapPlan{Founders: plan.Founders, Milestones: defaultMilestones()}, founder))
+}
+
+func TestBootstrap_FounderVeto(t *testing.T) {
+	dao, founder, member := setupBootstrapDAO(t, defaultMilestones())
+	proposalID := addActiveProposal(dao, member)
+
+	err := dao.FounderVeto(proposalID, member, "not a founder")
+	require.Error(t, err)
+	assert.Equal(t, ErrUnauthorized, err.(*DAOError).Code)
+
+	require.NoError(t, dao.FounderVeto(proposalID, founder, "premature"))
+	assert.Equal(t, ProposalStatusRejected, dao.GovernanceState.Proposals[proposalID].Status)
+}
+
+func TestBootstrap_FastTrack(t *testing.T) {
+	dao, founder, member := setupBootstrapDAO(t, defaultMilestones())
+	proposalID := addActiveProposal(dao, member)
+	proposal := dao.GovernanceState.Proposals[proposalID]
+	minPeriod := dao.GetParameterConfig().MinVotingPeriod
+
+	require.Error(t, dao.FastTrackProposal(proposalID, founder, minPeriod-1))
+
+	require.NoError(t, dao.FastTrackProposal(proposalID, founder, minPeriod))
+	assert.Equal(t, proposal.StartTime+minPeriod, proposal.EndTime)
+}
+
+func TestBootstrap_MilestonesSunsetPowers(t *testing.T) {
+	dao, founder, member := setupBootstrapDAO(t, defaultMilestones())
+	assert.True(t, dao.SecurityManager.IsFounderPowerActive(FounderPowerVeto))
+	assert.True(t, dao.SecurityManager.IsFounderPowerActive(FounderPowerFastTrack))
+
+	// A third member sunsets fast-track only
+	newcomer := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.TransferTokens(founder, newcomer, 500))
+	reached := dao.RefreshBootstrap()
+	require.Len(t, reached, 1)
+	assert.Equal(t, "members", reached[0].ID)
+
+	proposalID := addActiveProposal(dao, member)
+	err := dao.FastTrackProposal(proposalID, founder, dao.GetParameterConfig().MinVotingPeriod)
+	require.Error(t, err)
+	assert.Equal(t, ErrFounderPowerSunset, err.(*DAOError).Code)
+	assert.True(t, dao.SecurityManager.IsFounderPowerActive(FounderPowerVeto))
+
+	// Dropping the founder share to 50% sunsets veto and ends bootstrap
+	require.NoError(t, dao.TransferTokens(founder, member, 2500))
+	status := dao.GetBootstrapStatus()
+	assert.False(t, status.Active)
+	assert.Empty(t, status.ActivePowers)
+
+	err = dao.FounderVeto(proposalID, founder, "too late")
+	require.Error(t, err)
+	assert.Equal(t, ProposalStatusActive, dao.GovernanceState.Proposals[proposalID].Status)
+}
+
+func TestBootstrap_TimeMilestone(t *testing.T) {
+	milestones := defaultMilestones()
+	milestones[2].Target = time.Now().Unix() - 1
+
+	// An already expired deadline sunsets every power at start
+	dao, _, _ := setupBootstrapDAO(t, milestones)
+
+	status := dao.GetBootstrapStatus()
+	assert.False(t, status.Active)
+	assert.NotZero(t, status.EndedAt)
+	assert.False(t, dao.SecurityManager.IsFounderPowerActive(FounderPowerVeto))
+}
+
+func TestBootstrap_StatusCountdown(t *testing.T) {
+	dao, _, _ := setupBootstrapDAO(t, defaultMilestones())
+
+	status := dao.GetBootstrapStatus()
+	require.True(t, status.Active)
+	require.Len(t, status.Milestones, 3)
+	assert.Len(t, status.ActivePowers, 2)
+
+	assert.Equal(t, "deadline", status.Milestones[0].ID)
+	assert.InDelta(t, 86400, status.Milestones[0].Remaining, 5)
+
+	for _, countdown := range status.Milestones {
+		switch countdown.ID {
+		case "members":
+			assert.Equal(t, int64(2), countdown.Current)
+			assert.Equal(t, int64(1), countdown.Remaining)
+		case "distribution":
+			assert.Equal(t, int64(8000), countdown.Current)
+			assert.Equal(t, int64(3000), countdown.Remaining)
+		}
+	}
+
+	// Unconfigured DAOs report an inactive plan
+	status = NewDAO("GOV", "Governance Token", 18).GetBootstrapStatus()
+	assert.False(t, status.Active)
+	assert.Empty(t, status.Milestones)
+}
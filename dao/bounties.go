@@ -0,0 +1,280 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// BountyStatus is the stage of a bounty on the board
+type BountyStatus string
+
+const (
+	BountyStatusProposed  BountyStatus = "proposed" // Awaiting the main DAO vote
+	BountyStatusOpen      BountyStatus = "open"
+	BountyStatusClaimed   BountyStatus = "claimed"
+	BountyStatusSubmitted BountyStatus = "submitted"
+	BountyStatusDisputed  BountyStatus = "disputed" // The reviewer asked for changes
+	BountyStatusPaid      BountyStatus = "paid"
+	BountyStatusCancelled BountyStatus = "cancelled"
+)
+
+// Bounty is a task posted by governance with its reward escrowed from the
+// treasury. One contributor claims it at a time and has the claim period to
+// get the work approved, after which the claim lapses and the bounty
+// reopens. Its ID is the ID of the proposal that posted it.
+type Bounty struct {
+	ID            types.Hash
+	Title         string
+	Description   string
+	Reward        uint64
+	Reviewer      crypto.PublicKey
+	ClaimPeriod   int64 // Seconds a claimant has to get the work approved
+	Proposer      crypto.PublicKey
+	Status        BountyStatus
+	Claimant      crypto.PublicKey
+	ClaimedAt     int64
+	ClaimExpires  int64
+	WorkHash      types.Hash // IPFS hash of the submitted work
+	SubmittedAt   int64
+	ReviewComment string
+	ReviewedAt    int64
+	CreatedAt     int64
+	PostedAt      int64
+	ClosedAt      int64
+}
+
+// StatusAt returns the status of the bounty at time now. Claims lapse once
+// their period ends unless the work awaits review.
+func (b *Bounty) StatusAt(now int64) BountyStatus {
+	if (b.Status == BountyStatusClaimed || b.Status == BountyStatusDisputed) && now > b.ClaimExpires {
+		return BountyStatusOpen
+	}
+	return b.Status
+}
+
+// Proposal returns the main DAO proposal voting on the bounty
+func (tx *BountyProposalTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        "Bounty: " + tx.Title,
+		Description:  description,
+		ProposalType: ProposalTypeTreasury,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// BountyManager runs the bounty board from posting to payment
+type BountyManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	bounties        map[types.Hash]*Bounty
+}
+
+// NewBountyManager creates a new bounty manager
+func NewBountyManager(governanceState *GovernanceState, tokenState *GovernanceToken) *BountyManager {
+	return &BountyManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		bounties:        make(map[types.Hash]*Bounty),
+	}
+}
+
+// RecordProposal adds the bounty of a main DAO proposal to the board
+func (bm *BountyManager) RecordProposal(proposalID types.Hash, tx *BountyProposalTx, proposer crypto.PublicKey) {
+	bm.bounties[proposalID] = &Bounty{
+		ID:          proposalID,
+		Title:       tx.Title,
+		Description: tx.Description,
+		Reward:      tx.Reward,
+		Reviewer:    tx.Reviewer,
+		ClaimPeriod: tx.ClaimPeriod,
+		Proposer:    proposer,
+		Status:      BountyStatusProposed,
+		CreatedAt:   time.Now().Unix(),
+	}
+}
+
+// ProcessBountyPostTx opens the bounty of a passed proposal, escrowing its
+// reward from the treasury
+func (bm *BountyManager) ProcessBountyPostTx(tx *BountyPostTx, poster crypto.PublicKey) error {
+	bounty, exists := bm.bounties[tx.ProposalID]
+	if !exists {
+		return ErrBountyNotFoundError
+	}
+	if bounty.Status != BountyStatusProposed {
+		return NewDAOError(ErrInvalidProposal, "bounty is already posted", nil)
+	}
+
+	proposal, exists := bm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	if bm.governanceState.Treasury.Balance < bounty.Reward {
+		return NewDAOError(ErrTreasuryInsufficient, "insufficient treasury funds for the bounty", map[string]interface{}{
+			"balance": bm.governanceState.Treasury.Balance,
+			"reward":  bounty.Reward,
+		})
+	}
+
+	bm.tokenState.Balances.Sub(poster.String(), uint64(tx.Fee))
+	bm.governanceState.Treasury.Balance -= bounty.Reward
+	bounty.Status = BountyStatusOpen
+	bounty.PostedAt = time.Now().Unix()
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// ProcessBountyClaimTx assigns an open bounty to a contributor
+func (bm *BountyManager) ProcessBountyClaimTx(tx *BountyClaimTx, claimant crypto.PublicKey) error {
+	bounty, exists := bm.bounties[tx.BountyID]
+	if !exists {
+		return ErrBountyNotFoundError
+	}
+
+	now := time.Now().Unix()
+	if bounty.StatusAt(now) != BountyStatusOpen {
+		return NewDAOError(ErrInvalidProposal, "bounty is not open for claims", nil)
+	}
+	if bounty.Reviewer.String() == claimant.String() {
+		return NewDAOError(ErrUnauthorized, "reviewers cannot claim their own bounty", nil)
+	}
+
+	bm.tokenState.Balances.Sub(claimant.String(), uint64(tx.Fee))
+	bounty.Status = BountyStatusClaimed
+	bounty.Claimant = claimant
+	bounty.ClaimedAt = now
+	bounty.ClaimExpires = now + bounty.ClaimPeriod
+	bounty.WorkHash = types.Hash{}
+	bounty.ReviewComment = ""
+
+	return nil
+}
+
+// ProcessBountySubmitTx submits the claimant's work for review
+func (bm *BountyManager) ProcessBountySubmitTx(tx *BountySubmitTx, claimant crypto.PublicKey) error {
+	bounty, exists := bm.bounties[tx.BountyID]
+	if !exists {
+		return ErrBountyNotFoundError
+	}
+
+	now := time.Now().Unix()
+	status := bounty.StatusAt(now)
+	if status != BountyStatusClaimed && status != BountyStatusDisputed {
+		return NewDAOError(ErrInvalidProposal, "bounty is not awaiting work", nil)
+	}
+	if bounty.Claimant.String() != claimant.String() {
+		return NewDAOError(ErrUnauthorized, "only the claimant can submit work", nil)
+	}
+
+	bm.tokenState.Balances.Sub(claimant.String(), uint64(tx.Fee))
+	bounty.Status = BountyStatusSubmitted
+	bounty.WorkHash = tx.WorkHash
+	bounty.SubmittedAt = now
+
+	return nil
+}
+
+// ProcessBountyReviewTx approves the submitted work, releasing the reward
+// to the claimant, or disputes it so the claimant can resubmit within the
+// claim period
+func (bm *BountyManager) ProcessBountyReviewTx(tx *BountyReviewTx, reviewer crypto.PublicKey) error {
+	bounty, exists := bm.bounties[tx.BountyID]
+	if !exists {
+		return ErrBountyNotFoundError
+	}
+	if bounty.Status != BountyStatusSubmitted {
+		return NewDAOError(ErrInvalidProposal, "bounty has no work awaiting review", nil)
+	}
+	if bounty.Reviewer.String() != reviewer.String() {
+		return NewDAOError(ErrUnauthorized, "only the reviewer can review bounty work", nil)
+	}
+
+	now := time.Now().Unix()
+	bm.tokenState.Balances.Sub(reviewer.String(), uint64(tx.Fee))
+	bounty.ReviewComment = tx.Comment
+	bounty.ReviewedAt = now
+
+	if !tx.Approve {
+		bounty.Status = BountyStatusDisputed
+		return nil
+	}
+
+	bm.tokenState.Balances.Add(bounty.Claimant.String(), bounty.Reward)
+	bounty.Status = BountyStatusPaid
+	bounty.ClosedAt = now
+
+	return nil
+}
+
+// ProcessBountyCancelTx withdraws a bounty nobody is working on, returning
+// its reward to the treasury
+func (bm *BountyManager) ProcessBountyCancelTx(tx *BountyCancelTx, canceller crypto.PublicKey) error {
+	bounty, exists := bm.bounties[tx.BountyID]
+	if !exists {
+		return ErrBountyNotFoundError
+	}
+	if bounty.Reviewer.String() != canceller.String() {
+		return NewDAOError(ErrUnauthorized, "only the reviewer can cancel a bounty", nil)
+	}
+
+	now := time.Now().Unix()
+	switch bounty.StatusAt(now) {
+	case BountyStatusOpen, BountyStatusDisputed:
+	default:
+		return NewDAOError(ErrInvalidProposal, "only open or disputed bounties can be cancelled", nil)
+	}
+
+	bm.tokenState.Balances.Sub(canceller.String(), uint64(tx.Fee))
+	bm.governanceState.Treasury.Balance += bounty.Reward
+	bounty.Status = BountyStatusCancelled
+	bounty.ClosedAt = now
+
+	return nil
+}
+
+// GetBounty returns a bounty
+func (bm *BountyManager) GetBounty(id types.Hash) (*Bounty, bool) {
+	bounty, exists := bm.bounties[id]
+	return bounty, exists
+}
+
+// ListBounties returns the bounties, optionally only those in a status or
+// claimed by a contributor, newest first
+func (bm *BountyManager) ListBounties(status BountyStatus, claimant crypto.PublicKey) []*Bounty {
+	now := time.Now().Unix()
+
+	bounties := make([]*Bounty, 0, len(bm.bounties))
+	for _, bounty := range bm.bounties {
+		if status != "" && bounty.StatusAt(now) != status {
+			continue
+		}
+		if len(claimant) > 0 && (len(bounty.Claimant) == 0 || bounty.Claimant.String() != claimant.String()) {
+			continue
+		}
+		bounties = append(bounties, bounty)
+	}
+
+	sort.Slice(bounties, func(i, j int) bool {
+		if bounties[i].CreatedAt != bounties[j].CreatedAt {
+			return bounties[i].CreatedAt > bounties[j].CreatedAt
+		}
+		return bounties[i].ID.String() < bounties[j].ID.String()
+	})
+	return bounties
+}
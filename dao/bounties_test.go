@@ -0,0 +1,159 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBounty(t *testing.T, post bool) (*DAO, *Bounty, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	reviewer := crypto.GeneratePrivateKey().PublicKey()
+	contributor := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		reviewer.String():    10000,
+		contributor.String(): 1000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 20000
+
+	now := time.Now().Unix()
+	bountyID := types.Hash{0xB0}
+	propose := &BountyProposalTx{
+		Fee:         200,
+		Title:       "Fix the sync bug",
+		Reward:      1500,
+		Reviewer:    reviewer,
+		ClaimPeriod: 7 * 86400,
+		VotingType:  VotingTypeSimple,
+		StartTime:   now + 60,
+		EndTime:     now + 7*86400,
+		Threshold:   5100,
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(propose, reviewer, bountyID))
+
+	if post {
+		proposal, err := dao.GetProposal(bountyID)
+		require.NoError(t, err)
+		proposal.Status = ProposalStatusPassed
+		require.NoError(t, dao.ProcessDAOTransaction(&BountyPostTx{Fee: 100, ProposalID: bountyID}, reviewer, types.Hash{0xB1}))
+	}
+
+	bounty, exists := dao.GetBounty(bountyID)
+	require.True(t, exists)
+	return dao, bounty, reviewer, contributor
+}
+
+func TestBounty_Post(t *testing.T) {
+	dao, bounty, reviewer, contributor := setupBounty(t, false)
+	assert.Equal(t, BountyStatusProposed, bounty.Status)
+
+	// Bounties cannot be claimed or posted before their proposal passes
+	assert.Error(t, dao.ProcessDAOTransaction(&BountyClaimTx{Fee: 10, BountyID: bounty.ID}, contributor, types.Hash{0x01}))
+	post := &BountyPostTx{Fee: 100, ProposalID: bounty.ID}
+	assert.Error(t, dao.ProcessDAOTransaction(post, reviewer, types.Hash{0x02}))
+
+	proposal, err := dao.GetProposal(bounty.ID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(post, reviewer, types.Hash{0x03}))
+
+	assert.Equal(t, BountyStatusOpen, bounty.Status)
+	assert.Equal(t, uint64(18500), dao.GovernanceState.Treasury.Balance)
+	assert.Error(t, dao.ProcessDAOTransaction(post, reviewer, types.Hash{0x04}))
+
+	now := time.Now().Unix()
+	invalid := []*BountyProposalTx{
+		{Fee: 200, Title: "", Reward: 1, Reviewer: reviewer, ClaimPeriod: 1},
+		{Fee: 200, Title: "free", Reviewer: reviewer, ClaimPeriod: 1},
+		{Fee: 200, Title: "unreviewed", Reward: 1, ClaimPeriod: 1},
+		{Fee: 200, Title: "endless", Reward: 1, Reviewer: reviewer},
+	}
+	for i, tx := range invalid {
+		tx.VotingType, tx.StartTime, tx.EndTime, tx.Threshold = VotingTypeSimple, now+60, now+7*86400, 5100
+		assert.Error(t, dao.ProcessDAOTransaction(tx, reviewer, types.Hash{0x10, byte(i)}), tx.Title)
+	}
+}
+
+func TestBounty_ClaimSubmitApprove(t *testing.T) {
+	dao, bounty, reviewer, contributor := setupBounty(t, true)
+
+	claim := &BountyClaimTx{Fee: 10, BountyID: bounty.ID}
+	assert.Error(t, dao.ProcessDAOTransaction(claim, reviewer, types.Hash{0x01}))
+	require.NoError(t, dao.ProcessDAOTransaction(claim, contributor, types.Hash{0x02}))
+	assert.Equal(t, BountyStatusClaimed, bounty.Status)
+
+	// Claimed bounties are exclusive
+	other := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(other.String(), 100)
+	assert.Error(t, dao.ProcessDAOTransaction(claim, other, types.Hash{0x03}))
+
+	submit := &BountySubmitTx{Fee: 10, BountyID: bounty.ID, WorkHash: types.Hash{0x1F}}
+	assert.Error(t, dao.ProcessDAOTransaction(submit, other, types.Hash{0x04}))
+	assert.Error(t, dao.ProcessDAOTransaction(&BountySubmitTx{Fee: 10, BountyID: bounty.ID}, contributor, types.Hash{0x05}))
+	require.NoError(t, dao.ProcessDAOTransaction(submit, contributor, types.Hash{0x06}))
+	assert.Equal(t, BountyStatusSubmitted, bounty.Status)
+
+	review := &BountyReviewTx{Fee: 10, BountyID: bounty.ID, Approve: true}
+	assert.Error(t, dao.ProcessDAOTransaction(review, contributor, types.Hash{0x07}))
+	require.NoError(t, dao.ProcessDAOTransaction(review, reviewer, types.Hash{0x08}))
+
+	assert.Equal(t, BountyStatusPaid, bounty.Status)
+	assert.Equal(t, uint64(1000-20+1500), dao.GetTokenBalance(contributor))
+	assert.Error(t, dao.ProcessDAOTransaction(review, reviewer, types.Hash{0x09}))
+	assert.Len(t, dao.ListBounties(BountyStatusPaid, contributor), 1)
+}
+
+func TestBounty_DisputeAndResubmit(t *testing.T) {
+	dao, bounty, reviewer, contributor := setupBounty(t, true)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&BountyClaimTx{Fee: 10, BountyID: bounty.ID}, contributor, types.Hash{0x01}))
+	submit := &BountySubmitTx{Fee: 10, BountyID: bounty.ID, WorkHash: types.Hash{0x1F}}
+	require.NoError(t, dao.ProcessDAOTransaction(submit, contributor, types.Hash{0x02}))
+
+	// Disputes need a reason
+	assert.Error(t, dao.ProcessDAOTransaction(&BountyReviewTx{Fee: 10, BountyID: bounty.ID}, reviewer, types.Hash{0x03}))
+	dispute := &BountyReviewTx{Fee: 10, BountyID: bounty.ID, Comment: "Tests are missing"}
+	require.NoError(t, dao.ProcessDAOTransaction(dispute, reviewer, types.Hash{0x04}))
+	assert.Equal(t, BountyStatusDisputed, bounty.Status)
+	assert.Equal(t, "Tests are missing", bounty.ReviewComment)
+
+	submit.WorkHash = types.Hash{0x2F}
+	require.NoError(t, dao.ProcessDAOTransaction(submit, contributor, types.Hash{0x05}))
+	assert.Equal(t, BountyStatusSubmitted, bounty.Status)
+	assert.Equal(t, types.Hash{0x2F}, bounty.WorkHash)
+}
+
+func TestBounty_ClaimLapses(t *testing.T) {
+	dao, bounty, reviewer, contributor := setupBounty(t, true)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&BountyClaimTx{Fee: 10, BountyID: bounty.ID}, contributor, types.Hash{0x01}))
+	assert.Error(t, dao.ProcessDAOTransaction(&BountyCancelTx{Fee: 10, BountyID: bounty.ID}, reviewer, types.Hash{0x02}))
+
+	// Once the claim period ends others can claim
+	bounty.ClaimExpires = time.Now().Unix() - 1
+	assert.Equal(t, BountyStatusOpen, bounty.StatusAt(time.Now().Unix()))
+	assert.Error(t, dao.ProcessDAOTransaction(&BountySubmitTx{Fee: 10, BountyID: bounty.ID, WorkHash: types.Hash{0x1F}}, contributor, types.Hash{0x03}))
+
+	other := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(other.String(), 100)
+	require.NoError(t, dao.ProcessDAOTransaction(&BountyClaimTx{Fee: 10, BountyID: bounty.ID}, other, types.Hash{0x04}))
+	assert.Equal(t, other, bounty.Claimant)
+}
+
+func TestBounty_CancelRefundsTreasury(t *testing.T) {
+	dao, bounty, reviewer, contributor := setupBounty(t, true)
+
+	cancel := &BountyCancelTx{Fee: 10, BountyID: bounty.ID}
+	assert.Error(t, dao.ProcessDAOTransaction(cancel, contributor, types.Hash{0x01}))
+	require.NoError(t, dao.ProcessDAOTransaction(cancel, reviewer, types.Hash{0x02}))
+
+	assert.Equal(t, BountyStatusCancelled, bounty.Status)
+	assert.Equal(t, uint64(20000), dao.GovernanceState.Treasury.Balance)
+	assert.Error(t, dao.ProcessDAOTransaction(&BountyClaimTx{Fee: 10, BountyID: bounty.ID}, contributor, types.Hash{0x03}))
+	assert.Empty(t, dao.ListBounties(BountyStatusOpen, nil))
+}
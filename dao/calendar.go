@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// MaxProposalScheduleAhead is how far ahead a proposal can schedule the start
+// of its vote
+const MaxProposalScheduleAhead int64 = 30 * 24 * 3600
+
+// Governance calendar event types
+const (
+	CalendarVoteStart      = "vote_start"
+	CalendarVoteEnd        = "vote_end"
+	CalendarTreasuryExpiry = "treasury_expiry" // Unsigned treasury transaction lapses
+	CalendarVestingCliff   = "vesting_cliff"
+	CalendarVestingEnd     = "vesting_end"
+	CalendarStakeUnlock    = "stake_unlock"
+)
+
+// CalendarEvent is an upcoming governance deadline
+type CalendarEvent struct {
+	Type        string `json:"type"`
+	Time        int64  `json:"time"`
+	ReferenceID string `json:"reference_id"` // Proposal, treasury transaction, vesting schedule or staking pool
+	Title       string `json:"title,omitempty"`
+	Amount      uint64 `json:"amount,omitempty"`
+}
+
+// ValidateProposalSchedule checks the start time a creator picked for the
+// vote of a proposal
+func ValidateProposalSchedule(startTime, now int64) error {
+	if startTime < now {
+		return NewDAOError(ErrInvalidTimeframe, "proposal start time is in the past", nil)
+	}
+	if startTime-now > MaxProposalScheduleAhead {
+		return NewDAOError(ErrInvalidTimeframe, "proposal start time is too far ahead", map[string]interface{}{"max_seconds": MaxProposalScheduleAhead})
+	}
+	return nil
+}
+
+// GovernanceCalendar returns the governance events between from and to,
+// soonest first. Vesting and staking events are only included for member,
+// pass nil for the public calendar.
+func (d *DAO) GovernanceCalendar(from, to int64, member crypto.PublicKey) []CalendarEvent {
+	var events []CalendarEvent
+	add := func(event CalendarEvent) {
+		if event.Time >= from && event.Time <= to {
+			events = append(events, event)
+		}
+	}
+
+	for id, proposal := range d.GovernanceState.Proposals {
+		if proposal.Status != ProposalStatusPending && proposal.Status != ProposalStatusActive {
+			continue
+		}
+		add(CalendarEvent{Type: CalendarVoteStart, Time: proposal.StartTime, ReferenceID: id.String(), Title: proposal.Title})
+		add(CalendarEvent{Type: CalendarVoteEnd, Time: proposal.EndTime, ReferenceID: id.String(), Title: proposal.Title})
+	}
+
+	for id, tx := range d.GovernanceState.Treasury.Transactions {
+		if !tx.Executed {
+			add(CalendarEvent{Type: CalendarTreasuryExpiry, Time: tx.ExpiresAt, ReferenceID: id.String(), Title: tx.Purpose, Amount: tx.Amount})
+		}
+	}
+
+	if member != nil {
+		for _, schedule := range d.TokenomicsManager.GetVestingSchedulesByBeneficiary(member) {
+			if schedule.Revoked || schedule.Released >= schedule.TotalAmount {
+				continue
+			}
+			if schedule.CliffTime > schedule.StartTime {
+				add(CalendarEvent{Type: CalendarVestingCliff, Time: schedule.CliffTime, ReferenceID: schedule.ID, Amount: schedule.TotalAmount})
+			}
+			add(CalendarEvent{Type: CalendarVestingEnd, Time: schedule.StartTime + schedule.Duration, ReferenceID: schedule.ID, Amount: schedule.TotalAmount - schedule.Released})
+		}
+
+		for poolID, pool := range d.TokenomicsManager.ListAllStakingPools() {
+			if staker, exists := pool.Stakers[member.String()]; exists && staker.StakedAmount > 0 {
+				add(CalendarEvent{Type: CalendarStakeUnlock, Time: staker.UnlockTime, ReferenceID: poolID, Title: pool.Name, Amount: staker.StakedAmount})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Time != events[j].Time {
+			return events[i].Time < events[j].Time
+		}
+		if events[i].Type != events[j].Type {
+			return events[i].Type < events[j].Type
+		}
+		return events[i].ReferenceID < events[j].ReferenceID
+	})
+	return events
+}
@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_ScheduledProposal(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		creator.String(): 10000,
+		voter.String():   1000,
+	}))
+
+	now := time.Now().Unix()
+	assert.NoError(t, ValidateProposalSchedule(now+3600, now))
+	assert.Error(t, ValidateProposalSchedule(now-1, now))
+	assert.Error(t, ValidateProposalSchedule(now+MaxProposalScheduleAhead+1, now))
+
+	proposalID := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+		Fee: 100, Title: "Later", Description: "Opens tomorrow", ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple,
+		StartTime: now + 86400, EndTime: now + 2*86400, Threshold: 5100,
+	}, creator, proposalID))
+
+	// Votes wait for the scheduled start
+	vote := &VoteTx{Fee: 10, ProposalID: proposalID, Choice: VoteChoiceYes, Weight: 100}
+	assert.Equal(t, ErrVotingNotStarted, dao.ProcessDAOTransaction(vote, voter, types.Hash{0x02}))
+
+	// Once started the first vote opens the proposal
+	proposal := dao.GovernanceState.Proposals[proposalID]
+	proposal.StartTime = now - 10
+	require.Equal(t, ProposalStatusPending, proposal.Status)
+	require.NoError(t, dao.ProcessDAOTransaction(vote, voter, types.Hash{0x02}))
+	assert.Equal(t, ProposalStatusActive, proposal.Status)
+}
+
+func TestCalendar_Events(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	member := crypto.GeneratePrivateKey().PublicKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+	now := int64(1700000000)
+
+	dao.GovernanceState.Proposals[types.Hash{0x01}] = &Proposal{ID: types.Hash{0x01}, Title: "Scheduled", Status: ProposalStatusPending, StartTime: now + 100, EndTime: now + 500}
+	dao.GovernanceState.Proposals[types.Hash{0x02}] = &Proposal{ID: types.Hash{0x02}, Title: "Open", Status: ProposalStatusActive, StartTime: now - 100, EndTime: now + 200}
+	dao.GovernanceState.Proposals[types.Hash{0x03}] = &Proposal{ID: types.Hash{0x03}, Title: "Done", Status: ProposalStatusPassed, StartTime: now - 500, EndTime: now + 50}
+	dao.GovernanceState.Treasury.Transactions[types.Hash{0x04}] = &PendingTx{ID: types.Hash{0x04}, Purpose: "Audit", Amount: 500, ExpiresAt: now + 300}
+
+	dao.TokenomicsManager.createVestingSchedule(member, 1000, VestingTypeLinear, now, 150, 1000)
+	dao.TokenomicsManager.createVestingSchedule(other, 1000, VestingTypeLinear, now, 150, 1000)
+	require.NoError(t, dao.TokenomicsManager.CreateStakingPool("pool", "Main pool", 1, 1, 400))
+	pool, _ := dao.TokenomicsManager.GetStakingPool("pool")
+	pool.Stakers[member.String()] = &StakerInfo{Address: member, StakedAmount: 250, UnlockTime: now + 400}
+
+	// The public calendar only has governance deadlines
+	events := dao.GovernanceCalendar(now, now+2000, nil)
+	var kinds []string
+	for _, event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	assert.Equal(t, []string{CalendarVoteStart, CalendarVoteEnd, CalendarTreasuryExpiry, CalendarVoteEnd}, kinds)
+	assert.Equal(t, "Scheduled", events[0].Title)
+	assert.Equal(t, uint64(500), events[2].Amount)
+
+	events = dao.GovernanceCalendar(now, now+2000, member)
+	kinds = nil
+	for _, event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	assert.Equal(t, []string{CalendarVoteStart, CalendarVestingCliff, CalendarVoteEnd, CalendarTreasuryExpiry, CalendarStakeUnlock, CalendarVoteEnd, CalendarVestingEnd}, kinds)
+
+	// Events outside the window are left out
+	assert.Len(t, dao.GovernanceCalendar(now+150, now+250, member), 2)
+}
@@ -0,0 +1,90 @@
+package dao
+
+import "github.com/BOCK-CHAIN/BockChain/codec"
+
+// Codec tags of the DAO transaction types. A tag is part of every encoding
+// of a transaction carrying the type, so tags are never reused: new types
+// take the next free tag. The first ten match their core TxType.
+func init() {
+	codec.Register(0x10, ProposalTx{})
+	codec.Register(0x11, VoteTx{})
+	codec.Register(0x12, DelegationTx{})
+	codec.Register(0x13, TreasuryTx{})
+	codec.Register(0x14, TokenMintTx{})
+	codec.Register(0x15, TokenBurnTx{})
+	codec.Register(0x16, TokenTransferTx{})
+	codec.Register(0x17, TokenApproveTx{})
+	codec.Register(0x18, TokenTransferFromTx{})
+	codec.Register(0x19, ParameterProposalTx{})
+	codec.Register(0x1a, PositionTransferTx{})
+	codec.Register(0x1b, DisputeTx{})
+	codec.Register(0x1c, DisputeEvidenceTx{})
+	codec.Register(0x1d, JurorCommitTx{})
+	codec.Register(0x1e, JurorRevealTx{})
+	codec.Register(0x1f, FundingKPITx{})
+	codec.Register(0x20, ImpactReviewTx{})
+	codec.Register(0x21, ValidatorConfigTx{})
+	codec.Register(0x22, ClaimCommissionTx{})
+	codec.Register(0x23, ValidatorSetProposalTx{})
+	codec.Register(0x24, ValidatorSetExecuteTx{})
+	codec.Register(0x25, MultisigCreateTx{})
+	codec.Register(0x26, MultisigSubmitTx{})
+	codec.Register(0x27, MultisigSignTx{})
+	codec.Register(0x28, SubDAOCreateProposalTx{})
+	codec.Register(0x29, SubDAOCreateExecuteTx{})
+	codec.Register(0x2a, SubDAOProposalTx{})
+	codec.Register(0x2b, SubDAOVoteTx{})
+	codec.Register(0x2c, GrantProposalTx{})
+	codec.Register(0x2d, GrantExecuteTx{})
+	codec.Register(0x2e, GrantMilestoneSubmitTx{})
+	codec.Register(0x2f, GrantMilestoneReviewTx{})
+	codec.Register(0x30, GrantCancelTx{})
+	codec.Register(0x31, BountyProposalTx{})
+	codec.Register(0x32, BountyPostTx{})
+	codec.Register(0x33, BountyClaimTx{})
+	codec.Register(0x34, BountySubmitTx{})
+	codec.Register(0x35, BountyReviewTx{})
+	codec.Register(0x36, BountyCancelTx{})
+	codec.Register(0x37, QFRoundProposalTx{})
+	codec.Register(0x38, QFRoundOpenTx{})
+	codec.Register(0x39, QFContributeTx{})
+	codec.Register(0x3a, QFPayoutTx{})
+	codec.Register(0x3b, OptimisticProposalTx{})
+	codec.Register(0x3c, OptimisticChallengeTx{})
+	codec.Register(0x3d, RPGFRoundProposalTx{})
+	codec.Register(0x3e, RPGFRoundOpenTx{})
+	codec.Register(0x3f, RPGFNominateTx{})
+	codec.Register(0x40, RPGFBallotTx{})
+	codec.Register(0x41, RPGFFinalizeTx{})
+	codec.Register(0x42, RPGFClaimTx{})
+	codec.Register(0x43, OracleFeedProposalTx{})
+	codec.Register(0x44, OracleFeedActivateTx{})
+	codec.Register(0x45, OracleReportTx{})
+	codec.Register(0x46, TrackProposalTx{})
+	codec.Register(0x47, TrackActivateTx{})
+	codec.Register(0x48, ParameterScheduleTx{})
+	codec.Register(0x49, ConstitutionAmendmentTx{})
+	codec.Register(0x4a, ConstitutionEnactTx{})
+	codec.Register(0x4b, EmergencySpendTx{})
+	codec.Register(0x4c, EmergencyExecuteTx{})
+	codec.Register(0x4d, ParticipationOptInTx{})
+	codec.Register(0x4e, EndorseProposalTx{})
+	codec.Register(0x4f, PrivacySettingsTx{})
+	codec.Register(0x50, NameRegisterTx{})
+	codec.Register(0x51, NameRenewTx{})
+	codec.Register(0x52, NameTransferTx{})
+	codec.Register(0x53, ProfileUpdateTx{})
+	codec.Register(0x54, DelegateStatementTx{})
+	codec.Register(0x55, CommentTx{})
+	codec.Register(0x56, JoinRequestTx{})
+	codec.Register(0x57, JoinApprovalTx{})
+	codec.Register(0x58, MembershipStatusTx{})
+	codec.Register(0x59, RageQuitTx{})
+	codec.Register(0x5a, DAOCreateTx{})
+	codec.Register(0x5b, HostedDAOTx{})
+	codec.Register(0x5c, TokenDistributionTx{})
+	codec.Register(0x5d, VestingClaimTx{})
+	codec.Register(0x5e, StakeTx{})
+	codec.Register(0x5f, UnstakeTx{})
+	codec.Register(0x60, ClaimRewardsTx{})
+}
@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/codec"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the codec golden file")
+
+const codecGoldenFile = "testdata/codec.golden"
+
+// codecSample fills a value of type t deterministically, every field
+// different from its zero value
+func codecSample(t reflect.Type, seed *int, depth int) reflect.Value {
+	*seed++
+	n := *seed
+	v := reflect.New(t).Elem()
+	if t == reflect.TypeOf(big.Int{}) {
+		v.Set(reflect.ValueOf(*big.NewInt(int64(n) * 1000003)))
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(-n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(n) + 0.25)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("s%d", n))
+	case reflect.Slice:
+		if depth > 2 {
+			return v
+		}
+		slice := reflect.MakeSlice(t, 2, 2)
+		for i := 0; i < 2; i++ {
+			slice.Index(i).Set(codecSample(t.Elem(), seed, depth+1))
+		}
+		v.Set(slice)
+	case reflect.Array:
+		for i := 0; i < t.Len(); i++ {
+			v.Index(i).Set(codecSample(t.Elem(), seed, depth+1))
+		}
+	case reflect.Map:
+		if depth > 2 {
+			return v
+		}
+		m := reflect.MakeMap(t)
+		for i := 0; i < 2; i++ {
+			m.SetMapIndex(codecSample(t.Key(), seed, depth+1), codecSample(t.Elem(), seed, depth+1))
+		}
+		v.Set(m)
+	case reflect.Ptr:
+		if depth > 2 {
+			return v
+		}
+		elem := reflect.New(t.Elem())
+		elem.Elem().Set(codecSample(t.Elem(), seed, depth+1))
+		v.Set(elem)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(uint64(n)))
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				v.Field(i).Set(codecSample(t.Field(i).Type, seed, depth+1))
+			}
+		}
+	}
+	return v
+}
+
+// codecGoldenTypes are the types whose encoding is pinned: the state types
+// and every registered transaction type
+func codecGoldenTypes() []reflect.Type {
+	types := []reflect.Type{
+		reflect.TypeOf(Proposal{}),
+		reflect.TypeOf(Vote{}),
+		reflect.TypeOf(Delegation{}),
+	}
+	pkg := reflect.TypeOf(Proposal{}).PkgPath()
+	for tag := int(codec.FirstTag); tag <= 0xffff; tag++ {
+		if t, registered := codec.TypeOf(uint16(tag)); registered && t.PkgPath() == pkg {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+func TestCodecGolden(t *testing.T) {
+	encodings := make(map[string]string)
+	var names []string
+	for _, typ := range codecGoldenTypes() {
+		seed := 0
+		data, err := codec.Marshal(codecSample(typ, &seed, 0).Interface())
+		if err != nil {
+			t.Fatalf("Failed to encode %s: %v", typ.Name(), err)
+		}
+		encodings[typ.Name()] = hex.EncodeToString(data)
+		names = append(names, typ.Name())
+	}
+
+	if *updateGolden {
+		var b strings.Builder
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s %s\n", name, encodings[name])
+		}
+		if err := os.WriteFile(codecGoldenFile, []byte(b.String()), 0o644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+	}
+
+	file, err := os.Open(codecGoldenFile)
+	if err != nil {
+		t.Fatalf("Failed to open golden file: %v", err)
+	}
+	defer file.Close()
+
+	golden := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			golden[fields[0]] = fields[1]
+		}
+	}
+
+	for _, typ := range codecGoldenTypes() {
+		name := typ.Name()
+		expected, exists := golden[name]
+		if !exists {
+			t.Errorf("%s has no golden encoding, run go test -run TestCodecGolden -update", name)
+			continue
+		}
+
+		// Pinned encodings keep decoding whatever fields were appended since
+		data, _ := hex.DecodeString(expected)
+		decoded := reflect.New(typ)
+		if err := codec.Unmarshal(data, decoded.Interface()); err != nil {
+			t.Errorf("Golden encoding of %s no longer decodes: %v", name, err)
+			continue
+		}
+		if encodings[name] != expected {
+			t.Errorf("Encoding of %s changed. Appending fields is compatible: check the golden encoding still decodes, then run go test -run TestCodecGolden -update", name)
+		}
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	for _, typ := range codecGoldenTypes() {
+		seed := 0
+		value := codecSample(typ, &seed, 0)
+		data, err := codec.Marshal(value.Interface())
+		if err != nil {
+			t.Fatalf("Failed to encode %s: %v", typ.Name(), err)
+		}
+
+		decoded := reflect.New(typ)
+		if err := codec.Unmarshal(data, decoded.Interface()); err != nil {
+			t.Fatalf("Failed to decode %s: %v", typ.Name(), err)
+		}
+		if !reflect.DeepEqual(value.Interface(), decoded.Elem().Interface()) {
+			t.Errorf("%s changed through the codec", typ.Name())
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package dao
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxCommentLength is the longest comment body in bytes
+const MaxCommentLength = 10000
+
+// Reactions members can leave on comments
+const (
+	ReactionLike      = "like"
+	ReactionDislike   = "dislike"
+	ReactionHeart     = "heart"
+	ReactionCelebrate = "celebrate"
+	ReactionConfused  = "confused"
+	ReactionEyes      = "eyes"
+)
+
+var validReactions = map[string]bool{
+	ReactionLike:      true,
+	ReactionDislike:   true,
+	ReactionHeart:     true,
+	ReactionCelebrate: true,
+	ReactionConfused:  true,
+	ReactionEyes:      true,
+}
+
+// Comment is a member's comment on a proposal. Its body is stored on IPFS
+// and its ID is the hash of the transaction that posted it.
+type Comment struct {
+	ID           types.Hash
+	ProposalID   types.Hash
+	ParentID     types.Hash // Zero for top level comments
+	Author       crypto.PublicKey
+	BodyHash     types.Hash
+	CreatedAt    int64
+	Hidden       bool // Hidden by a moderator
+	HiddenBy     crypto.PublicKey
+	HiddenReason string
+	Reactions    map[string]int // Members per reaction
+
+	reactions map[string]map[string]bool
+}
+
+// CommentThread is a comment with its replies, oldest first
+type CommentThread struct {
+	*Comment
+	Replies []*CommentThread
+}
+
+// copy returns a snapshot of the comment with its reaction counts
+func (c *Comment) copy() *Comment {
+	snapshot := *c
+	snapshot.reactions = nil
+	snapshot.Reactions = make(map[string]int, len(c.reactions))
+	for reaction, members := range c.reactions {
+		if len(members) > 0 {
+			snapshot.Reactions[reaction] = len(members)
+		}
+	}
+	return &snapshot
+}
+
+// CommentManager keeps the discussion threads of proposals. Comments are
+// posted on chain; reactions and moderation are kept by this node.
+type CommentManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+
+	mu         sync.RWMutex
+	comments   map[types.Hash]*Comment
+	byProposal map[types.Hash][]types.Hash // Posting order
+}
+
+// NewCommentManager creates a new comment manager
+func NewCommentManager(governanceState *GovernanceState, tokenState *GovernanceToken) *CommentManager {
+	return &CommentManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		comments:        make(map[types.Hash]*Comment),
+		byProposal:      make(map[types.Hash][]types.Hash),
+	}
+}
+
+// ProcessCommentTx adds a comment to the thread of its proposal
+func (cm *CommentManager) ProcessCommentTx(tx *CommentTx, author crypto.PublicKey, commentID types.Hash) error {
+	if _, exists := cm.governanceState.Proposal(tx.ProposalID); !exists {
+		return ErrProposalNotFoundError
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !tx.ParentID.IsZero() {
+		parent, exists := cm.comments[tx.ParentID]
+		if !exists {
+			return ErrCommentNotFoundError
+		}
+		if parent.ProposalID != tx.ProposalID {
+			return NewDAOError(ErrInvalidProposal, "replies must be on the proposal of their parent comment", nil)
+		}
+	}
+
+	cm.tokenState.Balances.Sub(author.String(), uint64(tx.Fee))
+	cm.comments[commentID] = &Comment{
+		ID:         commentID,
+		ProposalID: tx.ProposalID,
+		ParentID:   tx.ParentID,
+		Author:     author,
+		BodyHash:   tx.BodyHash,
+		CreatedAt:  time.Now().Unix(),
+		reactions:  make(map[string]map[string]bool),
+	}
+	cm.byProposal[tx.ProposalID] = append(cm.byProposal[tx.ProposalID], commentID)
+
+	return nil
+}
+
+// React adds or removes a member's reaction to a comment
+func (cm *CommentManager) React(commentID types.Hash, member crypto.PublicKey, reaction string, add bool) (*Comment, error) {
+	if !validReactions[reaction] {
+		return nil, NewDAOError(ErrInvalidProposal, "unknown reaction", map[string]interface{}{"reaction": reaction})
+	}
+	if cm.tokenState.Balances.Get(member.String()) == 0 {
+		return nil, NewDAOError(ErrUnauthorized, "only token holders can react to comments", nil)
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	comment, exists := cm.comments[commentID]
+	if !exists {
+		return nil, ErrCommentNotFoundError
+	}
+
+	members := comment.reactions[reaction]
+	if add {
+		if members == nil {
+			members = make(map[string]bool)
+			comment.reactions[reaction] = members
+		}
+		members[member.String()] = true
+	} else {
+		delete(members, member.String())
+	}
+
+	return comment.copy(), nil
+}
+
+// SetHidden hides a comment from its thread or shows it again. Callers
+// check that the moderator may moderate.
+func (cm *CommentManager) SetHidden(commentID types.Hash, moderator crypto.PublicKey, hidden bool, reason string) (*Comment, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	comment, exists := cm.comments[commentID]
+	if !exists {
+		return nil, ErrCommentNotFoundError
+	}
+
+	comment.Hidden = hidden
+	if hidden {
+		comment.HiddenBy = moderator
+		comment.HiddenReason = reason
+	} else {
+		comment.HiddenBy = nil
+		comment.HiddenReason = ""
+	}
+
+	return comment.copy(), nil
+}
+
+// GetComment returns a comment
+func (cm *CommentManager) GetComment(commentID types.Hash) (*Comment, bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	comment, exists := cm.comments[commentID]
+	if !exists {
+		return nil, false
+	}
+	return comment.copy(), true
+}
+
+// GetThreads returns a page of the top level comments of a proposal, oldest
+// first with their replies, and the number of top level comments
+func (cm *CommentManager) GetThreads(proposalID types.Hash, offset, limit int) ([]*CommentThread, int) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	threads := make(map[types.Hash]*CommentThread)
+	var roots []*CommentThread
+	for _, id := range cm.byProposal[proposalID] {
+		comment := cm.comments[id]
+		thread := &CommentThread{Comment: comment.copy()}
+		threads[id] = thread
+
+		// Parents are always posted before their replies
+		if parent, exists := threads[comment.ParentID]; exists {
+			parent.Replies = append(parent.Replies, thread)
+		} else {
+			roots = append(roots, thread)
+		}
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].CreatedAt < roots[j].CreatedAt
+	})
+
+	total := len(roots)
+	if offset >= total {
+		return []*CommentThread{}, total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return roots[offset:end], total
+}
+
+// CommentCount returns the number of comments on a proposal
+func (cm *CommentManager) CommentCount(proposalID types.Hash) int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return len(cm.byProposal[proposalID])
+}
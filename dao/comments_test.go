@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupComments(t *testing.T) (*DAO, types.Hash, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   1000,
+	}))
+
+	proposalID := types.Hash{0xC0}
+	dao.GovernanceState.Proposals[proposalID] = &Proposal{ID: proposalID, Creator: alice, Status: ProposalStatusActive}
+
+	return dao, proposalID, alice, bob
+}
+
+func TestComments_Threads(t *testing.T) {
+	dao, proposalID, alice, bob := setupComments(t)
+
+	first := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, BodyHash: types.Hash{0xA1}}, alice, first))
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, BodyHash: types.Hash{0xA2}}, bob, types.Hash{0x02}))
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, ParentID: first, BodyHash: types.Hash{0xA3}}, bob, types.Hash{0x03}))
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, ParentID: types.Hash{0x03}, BodyHash: types.Hash{0xA4}}, alice, types.Hash{0x04}))
+	assert.Equal(t, uint64(980), dao.GetTokenBalance(alice))
+
+	threads, total := dao.GetProposalComments(proposalID, 0, 0)
+	assert.Equal(t, 2, total)
+	require.Len(t, threads, 2)
+	assert.Equal(t, first, threads[0].ID)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, types.Hash{0x03}, threads[0].Replies[0].ID)
+	require.Len(t, threads[0].Replies[0].Replies, 1)
+	assert.Empty(t, threads[1].Replies)
+
+	page, total := dao.GetProposalComments(proposalID, 1, 1)
+	assert.Equal(t, 2, total)
+	require.Len(t, page, 1)
+	assert.Equal(t, types.Hash{0x02}, page[0].ID)
+
+	page, _ = dao.GetProposalComments(proposalID, 5, 10)
+	assert.Empty(t, page)
+}
+
+func TestComments_Validation(t *testing.T) {
+	dao, proposalID, alice, _ := setupComments(t)
+
+	// Bodies are required, proposals and parents must exist
+	assert.Error(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID}, alice, types.Hash{0x01}))
+	assert.Error(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: types.Hash{0xFF}, BodyHash: types.Hash{0xA1}}, alice, types.Hash{0x01}))
+	assert.Error(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, ParentID: types.Hash{0xEE}, BodyHash: types.Hash{0xA1}}, alice, types.Hash{0x01}))
+
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	assert.Error(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, BodyHash: types.Hash{0xA1}}, outsider, types.Hash{0x01}))
+
+	// Replies stay on the proposal of their parent
+	other := types.Hash{0xC1}
+	dao.GovernanceState.Proposals[other] = &Proposal{ID: other, Creator: alice, Status: ProposalStatusActive}
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, BodyHash: types.Hash{0xA1}}, alice, types.Hash{0x01}))
+	assert.Error(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: other, ParentID: types.Hash{0x01}, BodyHash: types.Hash{0xA2}}, alice, types.Hash{0x02}))
+}
+
+func TestComments_Reactions(t *testing.T) {
+	dao, proposalID, alice, bob := setupComments(t)
+	id := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, BodyHash: types.Hash{0xA1}}, alice, id))
+
+	_, err := dao.ReactToComment(id, alice, ReactionLike, true)
+	require.NoError(t, err)
+	// Reacting twice counts once
+	comment, err := dao.ReactToComment(id, bob, ReactionLike, true)
+	require.NoError(t, err)
+	comment, err = dao.ReactToComment(id, bob, ReactionLike, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, comment.Reactions[ReactionLike])
+
+	comment, err = dao.ReactToComment(id, bob, ReactionLike, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, comment.Reactions[ReactionLike])
+
+	_, err = dao.ReactToComment(id, bob, "shrug", true)
+	assert.Error(t, err)
+	_, err = dao.ReactToComment(id, crypto.GeneratePrivateKey().PublicKey(), ReactionLike, true)
+	assert.Error(t, err)
+	_, err = dao.ReactToComment(types.Hash{0xEE}, bob, ReactionLike, true)
+	assert.Error(t, err)
+}
+
+func TestComments_Moderation(t *testing.T) {
+	dao, proposalID, alice, bob := setupComments(t)
+	id := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&CommentTx{Fee: 10, ProposalID: proposalID, BodyHash: types.Hash{0xA1}}, bob, id))
+
+	_, err := dao.ModerateComment(id, alice, true, "spam")
+	assert.Error(t, err)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitializeFounderRoles([]crypto.PublicKey{founder}))
+	require.NoError(t, dao.GrantRole(alice, RoleModerator, founder, 0))
+
+	comment, err := dao.ModerateComment(id, alice, true, "spam")
+	require.NoError(t, err)
+	assert.True(t, comment.Hidden)
+	assert.Equal(t, "spam", comment.HiddenReason)
+
+	comment, err = dao.ModerateComment(id, alice, false, "")
+	require.NoError(t, err)
+	assert.False(t, comment.Hidden)
+	assert.Empty(t, comment.HiddenBy)
+}
@@ -0,0 +1,271 @@
+package dao
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxDocumentLength is the largest governance document text in bytes
+const MaxDocumentLength = 256 * 1024
+
+// DocumentVersion is an enacted version of a governance document
+type DocumentVersion struct {
+	Version     uint32
+	ContentHash types.Hash // IPFS hash of the document text
+	DiffHash    types.Hash // IPFS hash of the DocumentDiff from the previous version
+	Summary     string
+	ProposalID  types.Hash // Proposal that enacted the version
+	Proposer    crypto.PublicKey
+	EnactedAt   int64
+}
+
+// GovernanceDocument is a document such as the constitution or a charter
+// that binds the DAO. Its text lives on IPFS and only its hashes are kept,
+// one version per amendment passed by a supermajority.
+type GovernanceDocument struct {
+	ID       string
+	Title    string
+	Versions []*DocumentVersion // Oldest first
+}
+
+// Current returns the latest version of the document
+func (d *GovernanceDocument) Current() *DocumentVersion {
+	if len(d.Versions) == 0 {
+		return nil
+	}
+	return d.Versions[len(d.Versions)-1]
+}
+
+// Version returns a version of the document
+func (d *GovernanceDocument) Version(version uint32) (*DocumentVersion, bool) {
+	if version == 0 || int(version) > len(d.Versions) {
+		return nil, false
+	}
+	return d.Versions[version-1], true
+}
+
+// DocumentDiff is the metadata of an amendment stored on IPFS next to the
+// amended text, describing how it changes the previous version
+type DocumentDiff struct {
+	DocumentID   string   `json:"document_id"`
+	FromVersion  uint32   `json:"from_version"`
+	ToVersion    uint32   `json:"to_version"`
+	PreviousHash string   `json:"previous_hash,omitempty"`
+	ContentHash  string   `json:"content_hash"`
+	Summary      string   `json:"summary"`
+	Sections     []string `json:"sections,omitempty"` // Sections the amendment changes
+	Additions    int      `json:"additions"`          // Lines added
+	Deletions    int      `json:"deletions"`          // Lines removed
+	CreatedAt    int64    `json:"created_at"`
+}
+
+// NewDocumentDiff describes the amendment of a document's previous text to
+// its current text. Lines are compared as a multiset, so moved lines count
+// as unchanged.
+func NewDocumentDiff(documentID string, fromVersion uint32, previousHash, contentHash types.Hash, previous, current, summary string, sections []string) *DocumentDiff {
+	diff := &DocumentDiff{
+		DocumentID:  documentID,
+		FromVersion: fromVersion,
+		ToVersion:   fromVersion + 1,
+		ContentHash: contentHash.String(),
+		Summary:     summary,
+		Sections:    sections,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if !previousHash.IsZero() {
+		diff.PreviousHash = previousHash.String()
+	}
+
+	lines := make(map[string]int)
+	if previous != "" {
+		for _, line := range strings.Split(previous, "\n") {
+			lines[line]++
+		}
+	}
+	for _, line := range strings.Split(current, "\n") {
+		if lines[line] > 0 {
+			lines[line]--
+			continue
+		}
+		diff.Additions++
+	}
+	for _, count := range lines {
+		diff.Deletions += count
+	}
+	return diff
+}
+
+// Proposal returns the proposal of an amendment
+func (tx *ConstitutionAmendmentTx) Proposal() *ProposalTx {
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        tx.Title,
+		Description:  tx.Description,
+		ProposalType: ProposalTypeConstitution,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+		MetadataHash: tx.DiffHash,
+	}
+}
+
+// ConstitutionManager keeps the registry of governance documents and the
+// amendments proposed to them
+type ConstitutionManager struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	documents        map[string]*GovernanceDocument
+	proposed         map[types.Hash]*ConstitutionAmendmentTx
+}
+
+// NewConstitutionManager creates a new constitution manager
+func NewConstitutionManager(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager) *ConstitutionManager {
+	return &ConstitutionManager{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		documents:        make(map[string]*GovernanceDocument),
+		proposed:         make(map[types.Hash]*ConstitutionAmendmentTx),
+	}
+}
+
+// currentVersion returns the latest version number of a document, 0 when
+// it has not been adopted
+func (cm *ConstitutionManager) currentVersion(documentID string) uint32 {
+	if doc, exists := cm.documents[documentID]; exists {
+		return uint32(len(doc.Versions))
+	}
+	return 0
+}
+
+// CheckAmendment checks an amendment asks for a supermajority and amends
+// the current version of its document
+func (cm *ConstitutionManager) CheckAmendment(tx *ConstitutionAmendmentTx) error {
+	threshold := cm.parameterManager.GetParameterConfig().ConstitutionAmendmentThreshold
+	if tx.Threshold < threshold {
+		return NewDAOError(ErrInvalidThreshold, "amendments need a supermajority threshold", map[string]interface{}{
+			"threshold": tx.Threshold,
+			"minimum":   threshold,
+		})
+	}
+
+	return cm.checkBaseVersion(tx)
+}
+
+// checkBaseVersion checks an amendment builds on the document's current
+// version, so that of two amendments passed together only the first enacts
+func (cm *ConstitutionManager) checkBaseVersion(tx *ConstitutionAmendmentTx) error {
+	current := cm.currentVersion(tx.DocumentID)
+	if tx.BaseVersion != current {
+		return NewDAOError(ErrDocumentConflict, "amendment does not build on the current version of the document", map[string]interface{}{
+			"document":     tx.DocumentID,
+			"base_version": tx.BaseVersion,
+			"current":      current,
+		})
+	}
+	return nil
+}
+
+// RecordProposal records the amendment of a proposal
+func (cm *ConstitutionManager) RecordProposal(proposalID types.Hash, tx *ConstitutionAmendmentTx) {
+	cm.proposed[proposalID] = tx
+}
+
+// ProcessConstitutionEnactTx enacts the amendment of a passed proposal. The
+// enactor pays the fee.
+func (cm *ConstitutionManager) ProcessConstitutionEnactTx(tx *ConstitutionEnactTx, enactor crypto.PublicKey) error {
+	if err := cm.Enact(tx.ProposalID); err != nil {
+		return err
+	}
+	cm.tokenState.Balances.Sub(enactor.String(), uint64(tx.Fee))
+	return nil
+}
+
+// Enact adds the amendment of a passed proposal as the new version of its
+// document and marks the proposal executed
+func (cm *ConstitutionManager) Enact(proposalID types.Hash) error {
+	amendment, exists := cm.proposed[proposalID]
+	if !exists {
+		return NewDAOError(ErrDocumentNotFound, "proposal does not amend a governance document", nil)
+	}
+
+	proposal, exists := cm.governanceState.Proposal(proposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+	if err := cm.checkBaseVersion(amendment); err != nil {
+		return err
+	}
+
+	doc, exists := cm.documents[amendment.DocumentID]
+	if !exists {
+		doc = &GovernanceDocument{ID: amendment.DocumentID}
+		cm.documents[amendment.DocumentID] = doc
+	}
+	if amendment.DocumentTitle != "" {
+		doc.Title = amendment.DocumentTitle
+	}
+	doc.Versions = append(doc.Versions, &DocumentVersion{
+		Version:     amendment.BaseVersion + 1,
+		ContentHash: amendment.ContentHash,
+		DiffHash:    amendment.DiffHash,
+		Summary:     amendment.Summary,
+		ProposalID:  proposalID,
+		Proposer:    proposal.Creator,
+		EnactedAt:   time.Now().Unix(),
+	})
+
+	delete(cm.proposed, proposalID)
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// GetDocument returns a governance document
+func (cm *ConstitutionManager) GetDocument(id string) (*GovernanceDocument, bool) {
+	doc, exists := cm.documents[id]
+	return doc, exists
+}
+
+// ListDocuments returns the governance documents ordered by ID
+func (cm *ConstitutionManager) ListDocuments() []*GovernanceDocument {
+	docs := make([]*GovernanceDocument, 0, len(cm.documents))
+	for _, doc := range cm.documents {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs
+}
+
+// PendingAmendments returns the IDs of the proposals amending a document
+// that are being voted on or have passed without being enacted, ordered by ID
+func (cm *ConstitutionManager) PendingAmendments(documentID string) []types.Hash {
+	var ids []types.Hash
+	for id, amendment := range cm.proposed {
+		if amendment.DocumentID != documentID {
+			continue
+		}
+		if proposal, exists := cm.governanceState.Proposal(id); exists &&
+			(proposal.Status == ProposalStatusRejected || proposal.Status == ProposalStatusCancelled) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// GetAmendment returns the amendment a proposal has not yet enacted
+func (cm *ConstitutionManager) GetAmendment(proposalID types.Hash) (*ConstitutionAmendmentTx, bool) {
+	amendment, exists := cm.proposed[proposalID]
+	return amendment, exists
+}
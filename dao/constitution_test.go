@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentDiff(t *testing.T) {
+	diff := NewDocumentDiff("constitution", 1, types.Hash{0x01}, types.Hash{0x02},
+		"Article 1\nArticle 2\nArticle 3", "Article 1\nArticle 3\nArticle 2a\nArticle 4", "Rework article 2", []string{"Article 2"})
+	assert.Equal(t, uint32(2), diff.ToVersion)
+	assert.Equal(t, types.Hash{0x01}.String(), diff.PreviousHash)
+	assert.Equal(t, 2, diff.Additions)
+	assert.Equal(t, 1, diff.Deletions)
+
+	adopted := NewDocumentDiff("charter", 0, types.Hash{}, types.Hash{0x03}, "", "Line 1\nLine 2", "", nil)
+	assert.Empty(t, adopted.PreviousHash)
+	assert.Equal(t, 2, adopted.Additions)
+	assert.Equal(t, 0, adopted.Deletions)
+}
+
+func TestConstitution_Amendments(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	now := time.Now().Unix()
+	amendment := func(base uint32, content byte, threshold uint64) *ConstitutionAmendmentTx {
+		return &ConstitutionAmendmentTx{
+			Fee:           100,
+			Title:         "Constitution",
+			Description:   "Amend the constitution",
+			DocumentID:    "constitution",
+			DocumentTitle: "DAO Constitution",
+			BaseVersion:   base,
+			ContentHash:   types.Hash{content},
+			DiffHash:      types.Hash{content, 0xDD},
+			Summary:       "Clarify quorum rules",
+			VotingType:    VotingTypeSimple,
+			StartTime:     now,
+			EndTime:       now + 86400,
+			Threshold:     threshold,
+		}
+	}
+	pass := func(id types.Hash) {
+		proposal, err := dao.GetProposal(id)
+		require.NoError(t, err)
+		proposal.Status = ProposalStatusPassed
+	}
+
+	// Amendments need a supermajority and plain proposals cannot amend
+	assert.Error(t, dao.ProcessDAOTransaction(amendment(0, 0x01, 5100), founder, types.Hash{0xA0}))
+	plain := amendment(0, 0x01, 9000).Proposal()
+	assert.Error(t, dao.ProcessDAOTransaction(plain, founder, types.Hash{0xA1}))
+
+	adoptID := types.Hash{0xA2}
+	require.NoError(t, dao.ProcessDAOTransaction(amendment(0, 0x01, 6667), founder, adoptID))
+	proposal, err := dao.GetProposal(adoptID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalTypeConstitution, proposal.ProposalType)
+	assert.Equal(t, []types.Hash{adoptID}, dao.Constitution.PendingAmendments("constitution"))
+
+	// Enacting waits for the vote
+	enact := &ConstitutionEnactTx{Fee: 10, ProposalID: adoptID}
+	assert.Error(t, dao.ProcessDAOTransaction(enact, founder, types.Hash{0xA3}))
+	pass(adoptID)
+	require.NoError(t, dao.ProcessDAOTransaction(enact, founder, types.Hash{0xA4}))
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	doc, exists := dao.GetGovernanceDocument("constitution")
+	require.True(t, exists)
+	assert.Equal(t, "DAO Constitution", doc.Title)
+	require.NotNil(t, doc.Current())
+	assert.Equal(t, uint32(1), doc.Current().Version)
+	assert.Equal(t, founder, doc.Current().Proposer)
+	assert.Empty(t, dao.Constitution.PendingAmendments("constitution"))
+
+	// An amendment must build on the current version
+	err = dao.ProcessDAOTransaction(amendment(0, 0x02, 7000), founder, types.Hash{0xA5})
+	assert.Equal(t, ErrDocumentConflict, err.(*DAOError).Code)
+
+	// Of two competing amendments only the first enacted applies
+	first, second := types.Hash{0xA6}, types.Hash{0xA7}
+	require.NoError(t, dao.ProcessDAOTransaction(amendment(1, 0x02, 7000), founder, first))
+	require.NoError(t, dao.ProcessDAOTransaction(amendment(1, 0x03, 7000), founder, second))
+	pass(first)
+	pass(second)
+	require.NoError(t, dao.ProposalManager.ExecuteProposal(first, founder))
+	err = dao.ProcessDAOTransaction(&ConstitutionEnactTx{Fee: 10, ProposalID: second}, founder, types.Hash{0xA8})
+	assert.Equal(t, ErrDocumentConflict, err.(*DAOError).Code)
+
+	require.Len(t, doc.Versions, 2)
+	version, exists := doc.Version(2)
+	require.True(t, exists)
+	assert.Equal(t, types.Hash{0x02}, version.ContentHash)
+	assert.Equal(t, first, version.ProposalID)
+	_, exists = doc.Version(3)
+	assert.False(t, exists)
+	assert.Len(t, dao.ListGovernanceDocuments(), 1)
+}
@@ -0,0 +1,225 @@
+package dao
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	shell "github.com/ipfs/go-ipfs-api"
+)
+
+// Content store providers selectable in configuration
+const (
+	ContentStoreIPFS    = "ipfs"
+	ContentStoreS3      = "s3"
+	ContentStoreArweave = "arweave"
+)
+
+// ContentStore stores proposal metadata and documents off chain. Put returns
+// the content ID Get reads the content back with.
+type ContentStore interface {
+	Name() string
+	Put(data []byte) (string, error)
+	Get(id string) ([]byte, error)
+	// Pin keeps content from being garbage collected, stores that never
+	// drop content treat it as a no-op
+	Pin(id string) error
+	Unpin(id string) error
+}
+
+// IPFSStore stores content on an IPFS node
+type IPFSStore struct {
+	shell *shell.Shell
+}
+
+// NewIPFSStore creates a store for the IPFS node API at nodeURL
+func NewIPFSStore(nodeURL string) *IPFSStore {
+	if nodeURL == "" {
+		nodeURL = "localhost:5001" // Default IPFS API endpoint
+	}
+
+	return &IPFSStore{shell: shell.NewShell(nodeURL)}
+}
+
+// Name returns the provider name
+func (s *IPFSStore) Name() string {
+	return ContentStoreIPFS
+}
+
+// Put adds content to the node and returns its CID
+func (s *IPFSStore) Put(data []byte) (string, error) {
+	return s.shell.Add(bytes.NewReader(data))
+}
+
+// Get reads content from the node
+func (s *IPFSStore) Get(id string) ([]byte, error) {
+	reader, err := s.shell.Cat(id)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// Pin pins content on the node
+func (s *IPFSStore) Pin(id string) error {
+	return s.shell.Pin(id)
+}
+
+// Unpin unpins content on the node
+func (s *IPFSStore) Unpin(id string) error {
+	return s.shell.Unpin(id)
+}
+
+// S3ContentStore stores content in an S3-compatible bucket under the hex
+// SHA-256 of the content, which is also its ID
+type S3ContentStore struct {
+	objects *S3MirrorStore
+}
+
+// NewS3ContentStore creates a new S3-compatible content store
+func NewS3ContentStore(config MirrorConfig) *S3ContentStore {
+	return &S3ContentStore{objects: NewS3MirrorStore(config)}
+}
+
+// Name returns the provider name
+func (s *S3ContentStore) Name() string {
+	return ContentStoreS3
+}
+
+// Put uploads content and returns its digest
+func (s *S3ContentStore) Put(data []byte) (string, error) {
+	digest := sha256.Sum256(data)
+	id := hex.EncodeToString(digest[:])
+
+	if err := s.objects.Put(s.key(id), data); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Get downloads content and verifies it against its ID
+func (s *S3ContentStore) Get(id string) ([]byte, error) {
+	data, err := s.objects.Get(s.key(id))
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(data)
+	if hex.EncodeToString(digest[:]) != id {
+		return nil, fmt.Errorf("content integrity check failed for %s", id)
+	}
+	return data, nil
+}
+
+// Pin is a no-op, bucket objects are kept until deleted
+func (s *S3ContentStore) Pin(id string) error {
+	return nil
+}
+
+// Unpin is a no-op, proposal content is kept for the record
+func (s *S3ContentStore) Unpin(id string) error {
+	return nil
+}
+
+func (s *S3ContentStore) key(id string) string {
+	return "content/" + id
+}
+
+// ArweaveConfig configures the Arweave content store
+type ArweaveConfig struct {
+	Gateway string // Reads go through this gateway, https://arweave.net when empty
+	// UploadURL is a bundling service that signs and pays for the Arweave
+	// transaction of uploaded data and answers with its ID
+	UploadURL string
+	Token     string // Bearer token of the bundling service
+	Timeout   time.Duration
+}
+
+// ArweaveStore stores content permanently on Arweave
+type ArweaveStore struct {
+	config ArweaveConfig
+	client *http.Client
+}
+
+// NewArweaveStore creates a new Arweave content store
+func NewArweaveStore(config ArweaveConfig) *ArweaveStore {
+	if config.Gateway == "" {
+		config.Gateway = "https://arweave.net"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &ArweaveStore{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Name returns the provider name
+func (s *ArweaveStore) Name() string {
+	return ContentStoreArweave
+}
+
+// Put uploads content through the bundling service and returns its
+// transaction ID
+func (s *ArweaveStore) Put(data []byte) (string, error) {
+	req, err := http.NewRequest(http.MethodPost, s.config.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to Arweave: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("arweave upload failed with status %d", resp.StatusCode)
+	}
+
+	var receipt struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&receipt); err != nil || receipt.ID == "" {
+		return "", fmt.Errorf("arweave upload returned no transaction ID")
+	}
+	return receipt.ID, nil
+}
+
+// Get reads content from the gateway
+func (s *ArweaveStore) Get(id string) ([]byte, error) {
+	resp, err := s.client.Get(strings.TrimRight(s.config.Gateway, "/") + "/" + id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from Arweave: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("arweave read failed with status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Pin is a no-op, Arweave storage is permanent
+func (s *ArweaveStore) Pin(id string) error {
+	return nil
+}
+
+// Unpin is a no-op, Arweave content cannot be removed
+func (s *ArweaveStore) Unpin(id string) error {
+	return nil
+}
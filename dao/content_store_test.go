@@ -0,0 +1,133 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestArweave serves a bundling service upload endpoint and a gateway
+// sharing the same transactions
+func newTestArweave(t *testing.T) (*httptest.Server, map[string][]byte) {
+	var mu sync.Mutex
+	transactions := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/tx":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			id := fmt.Sprintf("tx-%d", len(transactions)+1)
+			transactions[id] = data
+			json.NewEncoder(w).Encode(map[string]string{"id": id})
+		case r.Method == http.MethodGet:
+			data, exists := transactions[r.URL.Path[1:]]
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, transactions
+}
+
+func TestS3ContentStore_PutGet(t *testing.T) {
+	objects, server := newTestObjectStore(t)
+	store := NewS3ContentStore(MirrorConfig{Endpoint: server.URL, Bucket: "dao-content"})
+
+	id, err := store.Put([]byte("content"))
+	require.NoError(t, err)
+	assert.Len(t, id, 64)
+	assert.Equal(t, []byte("content"), objects.objects["/dao-content/content/"+id])
+
+	data, err := store.Get(id)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("content"), data)
+
+	// Tampered objects fail the integrity check
+	objects.objects["/dao-content/content/"+id] = []byte("tampered")
+	_, err = store.Get(id)
+	assert.Error(t, err)
+
+	assert.NoError(t, store.Pin(id))
+	assert.NoError(t, store.Unpin(id))
+}
+
+func TestArweaveStore_PutGet(t *testing.T) {
+	server, transactions := newTestArweave(t)
+	store := NewArweaveStore(ArweaveConfig{Gateway: server.URL, UploadURL: server.URL + "/tx", Token: "token"})
+
+	id, err := store.Put([]byte("permanent"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("permanent"), transactions[id])
+
+	data, err := store.Get(id)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("permanent"), data)
+
+	_, err = store.Get("missing")
+	assert.Error(t, err)
+
+	unauthorized := NewArweaveStore(ArweaveConfig{Gateway: server.URL, UploadURL: server.URL + "/tx"})
+	_, err = unauthorized.Put([]byte("permanent"))
+	assert.Error(t, err)
+}
+
+func TestIPFSClient_ContentStore(t *testing.T) {
+	_, objectServer := newTestObjectStore(t)
+	arweave, _ := newTestArweave(t)
+
+	stores := []ContentStore{
+		NewS3ContentStore(MirrorConfig{Endpoint: objectServer.URL, Bucket: "dao-content"}),
+		NewArweaveStore(ArweaveConfig{Gateway: arweave.URL, UploadURL: arweave.URL + "/tx", Token: "token"}),
+	}
+	for _, store := range stores {
+		client := NewIPFSClientWithStore(store)
+
+		hash, err := client.UploadProposalMetadata(&ProposalMetadata{Title: "Budget", Description: "Q3"})
+		require.NoError(t, err, store.Name())
+
+		metadata, err := client.RetrieveProposalMetadata(hash)
+		require.NoError(t, err, store.Name())
+		assert.Equal(t, "Budget", metadata.Title)
+
+		docRef, err := client.UploadDocument("plan.pdf", []byte("plan"), "application/pdf")
+		require.NoError(t, err, store.Name())
+		data, err := client.RetrieveDocument(docRef)
+		require.NoError(t, err, store.Name())
+		assert.Equal(t, []byte("plan"), data)
+
+		require.NoError(t, client.PinContent(hash))
+		exists, err := client.VerifyContentExists(hash)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		size, err := client.GetContentSize(hash)
+		require.NoError(t, err)
+		assert.Positive(t, size)
+
+		pinned, err := client.ListPinnedContent()
+		require.NoError(t, err)
+		assert.Contains(t, pinned, hash)
+
+		info, err := client.GetNodeInfo()
+		require.NoError(t, err)
+		assert.Equal(t, store.Name(), info["store"])
+	}
+}
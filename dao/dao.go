@@ -3,6 +3,7 @@ package dao
 import (
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/BOCK-CHAIN/BockChain/crypto"
@@ -23,6 +24,58 @@ type DAO struct {
 	ReputationSystem  *ReputationSystem
 	SecurityManager   *SecurityManager
 	AnalyticsSystem   *AnalyticsSystem
+	Metrics           *MetricsTimeSeries
+	ActivityIndex     *ActivityIndex
+	Indexes           *StateIndex
+	PositionManager   *PositionManager
+	YieldManager      *YieldManager
+	DisputeManager    *DisputeManager
+	ImpactTracker     *ImpactTracker
+	ValidatorManager  *ValidatorManager
+	MultisigManager   *MultisigManager
+	SubDAOManager     *SubDAOManager
+	GrantManager      *GrantManager
+	BountyManager     *BountyManager
+	QFManager         *QFManager
+	RPGFManager       *RPGFManager
+	OptimisticManager *OptimisticManager
+	OracleManager     *OracleManager
+	Constitution      *ConstitutionManager
+	EmergencySpends   *EmergencyManager
+	Dependencies      *ProposalDependencies
+	Endorsements      *EndorsementManager
+	Privacy           *PrivacyManager
+	Names             *NameRegistry
+	Profiles          *ProfileRegistry
+	Delegates         *DelegateRegistry
+	Soulbound         *SoulboundRegistry
+	CommentManager    *CommentManager
+	ProposalTemplates *ProposalTemplates
+	Drafts            *DraftManager
+	Membership        *MembershipManager
+	FeeSponsor        *FeeSponsorRelayer
+	Notifications     *NotificationService
+	Webhooks          *WebhookManager
+	Archive           *Archive // Set in the memory-bounded mode
+
+	chainSubmitter ChainSubmitter
+	// registry hosts the DAOs created alongside this one, nil unless this
+	// is the default DAO of a registry
+	registry *DAORegistry
+	// appliedListeners are told about every DAO transaction that applies
+	appliedListeners []func(txInner interface{}, from crypto.PublicKey, txHash types.Hash)
+	// expiryListeners are told about delegations the expiry sweep ended or
+	// renewed
+	expiryListeners []func(DelegationExpiry)
+	// optimisticListeners are told about optimistic proposals the proposal
+	// status job executed or settled
+	optimisticListeners []func(OptimisticProposal)
+	listenersMu         sync.RWMutex
+	// runwayAlertLevel is the level of the last runway alert members were
+	// notified of, empty while the runway is healthy
+	runwayAlertLevel string
+	// anchoring publishes the results of finalized proposals, nil disables it
+	anchoring *resultAnchoring
 }
 
 // NewDAO creates a new DAO instance
@@ -39,8 +92,15 @@ func NewDAO(tokenSymbol, tokenName string, decimals uint8) *DAO {
 		Validator:       validator,
 		IPFSClient:      NewIPFSClient(""), // Use default IPFS node
 		SecurityManager: NewSecurityManager(),
+		ActivityIndex:   NewActivityIndex(),
+		Indexes:         NewStateIndex(),
+		Notifications:   NewNotificationService(DefaultNotificationQueueSize),
+		Webhooks:        NewWebhookManager(WebhookConfig{}),
 	}
 
+	// Keep the state indexes current as the processor applies transactions
+	processor.index = dao.Indexes
+
 	// Initialize ProposalManager with the DAO instance
 	dao.ProposalManager = NewProposalManager(dao)
 
@@ -56,9 +116,112 @@ func NewDAO(tokenSymbol, tokenName string, decimals uint8) *DAO {
 	// Initialize AnalyticsSystem
 	dao.AnalyticsSystem = NewAnalyticsSystem(governanceState, tokenState)
 
+	// Initialize MetricsTimeSeries
+	dao.Metrics = NewMetricsTimeSeries(dao.AnalyticsSystem, DefaultMetricsRetention)
+
 	// Initialize TokenomicsManager
 	dao.TokenomicsManager = NewTokenomicsManager(governanceState, tokenState)
 
+	// Initialize PositionManager
+	dao.PositionManager = NewPositionManager(governanceState, tokenState, dao.TokenomicsManager, dao.ParameterManager)
+
+	// Initialize YieldManager
+	dao.YieldManager = NewYieldManager(governanceState, tokenState, dao.TokenomicsManager, dao.ParameterManager)
+
+	// Initialize DisputeManager
+	dao.DisputeManager = NewDisputeManager(governanceState, tokenState, dao.TokenomicsManager, dao.ParameterManager)
+
+	// Initialize ImpactTracker
+	dao.ImpactTracker = NewImpactTracker(governanceState, tokenState, dao.SecurityManager)
+
+	// Initialize ValidatorManager
+	dao.ValidatorManager = NewValidatorManager(governanceState, tokenState, dao.TokenomicsManager, dao.ParameterManager)
+
+	// Initialize MultisigManager
+	dao.MultisigManager = NewMultisigManager(governanceState, tokenState)
+
+	// Initialize SubDAOManager
+	dao.SubDAOManager = NewSubDAOManager(governanceState, tokenState)
+
+	// Initialize GrantManager
+	dao.GrantManager = NewGrantManager(governanceState, tokenState)
+
+	// Initialize BountyManager
+	dao.BountyManager = NewBountyManager(governanceState, tokenState)
+
+	// Initialize QFManager
+	dao.QFManager = NewQFManager(governanceState, tokenState)
+
+	// Initialize RPGFManager
+	dao.RPGFManager = NewRPGFManager(governanceState, tokenState)
+
+	// Initialize OptimisticManager
+	dao.OptimisticManager = NewOptimisticManager(governanceState, tokenState, dao.ParameterManager)
+
+	// Initialize OracleManager
+	dao.OracleManager = NewOracleManager(governanceState, tokenState)
+
+	// Let the treasury convert stable denominated amounts, and analytics
+	// value the treasury, through the oracles
+	dao.TreasuryManager.oracles = dao.OracleManager
+	dao.TreasuryManager.parameterManager = dao.ParameterManager
+	dao.AnalyticsSystem.oracles = dao.OracleManager
+	dao.AnalyticsSystem.parameterManager = dao.ParameterManager
+	processor.treasury = dao.TreasuryManager
+
+	// Let new proposals take the quorum recent turnout sets
+	processor.analytics = dao.AnalyticsSystem
+	processor.parameters = dao.ParameterManager
+
+	// Hold proposals to the rules of their tracks
+	processor.validator.parameters = dao.ParameterManager
+	validator.parameters = dao.ParameterManager
+
+	// Initialize ConstitutionManager
+	dao.Constitution = NewConstitutionManager(governanceState, tokenState, dao.ParameterManager)
+
+	// Initialize EmergencyManager
+	dao.EmergencySpends = NewEmergencyManager(governanceState, tokenState, dao.ParameterManager, dao.SecurityManager)
+
+	// Initialize ProposalDependencies
+	dao.Dependencies = NewProposalDependencies(governanceState)
+
+	// Initialize EndorsementManager and keep unendorsed proposals closed
+	dao.Endorsements = NewEndorsementManager(governanceState, tokenState, dao.ParameterManager, processor)
+	processor.endorsements = dao.Endorsements
+
+	// Initialize PrivacyManager
+	dao.Privacy = NewPrivacyManager(governanceState, tokenState, dao.ParameterManager)
+
+	// Initialize NameRegistry
+	dao.Names = NewNameRegistry(governanceState, tokenState, dao.ParameterManager)
+
+	// Initialize ProfileRegistry
+	dao.Profiles = NewProfileRegistry(tokenState)
+
+	// Initialize DelegateRegistry
+	dao.Delegates = NewDelegateRegistry(tokenState)
+
+	// Initialize CommentManager
+	dao.CommentManager = NewCommentManager(governanceState, tokenState)
+
+	// Initialize ProposalTemplates
+	dao.ProposalTemplates = NewProposalTemplates()
+
+	// Initialize DraftManager
+	dao.Drafts = NewDraftManager(tokenState)
+
+	// Initialize SoulboundRegistry and keep role tokens in step with roles
+	dao.Soulbound = NewSoulboundRegistry()
+	dao.SecurityManager.OnRoleChange(dao.Soulbound.RoleChanged)
+
+	// Initialize MembershipManager, minting members their membership tokens
+	dao.Membership = NewMembershipManager(governanceState, tokenState, dao.ParameterManager)
+	dao.Membership.soulbound = dao.Soulbound
+
+	// Initialize FeeSponsorRelayer
+	dao.FeeSponsor = NewFeeSponsorRelayer(governanceState, tokenState, dao.ParameterManager)
+
 	return dao
 }
 
@@ -92,7 +255,7 @@ func (d *DAO) InitialTokenDistribution(distributions map[string]uint64) error {
 
 	// Distribute tokens
 	for recipientStr, amount := range distributions {
-		d.TokenState.Balances[recipientStr] = amount
+		d.TokenState.Balances.Set(recipientStr, amount)
 
 		// Create token holder record
 		d.GovernanceState.TokenHolders[recipientStr] = &TokenHolder{
@@ -116,6 +279,7 @@ func (d *DAO) InitialTokenDistribution(distributions map[string]uint64) error {
 		}
 		pubKey := crypto.PublicKey(pubKeyBytes)
 		d.ReputationSystem.InitializeReputation(pubKey, amount)
+		d.Soulbound.MintMembership(pubKey, d.GovernanceState.Now())
 	}
 
 	return nil
@@ -123,16 +287,17 @@ func (d *DAO) InitialTokenDistribution(distributions map[string]uint64) error {
 
 // GetProposal retrieves a proposal by ID
 func (d *DAO) GetProposal(proposalID types.Hash) (*Proposal, error) {
-	proposal, exists := d.GovernanceState.Proposals[proposalID]
+	proposal, exists := d.GovernanceState.Proposal(proposalID)
 	if !exists {
 		return nil, ErrProposalNotFoundError
 	}
+	d.Archive.touch(archiveKey{archiveProposal, proposalID})
 	return proposal, nil
 }
 
 // GetVotes retrieves all votes for a proposal
 func (d *DAO) GetVotes(proposalID types.Hash) (map[string]*Vote, error) {
-	votes, exists := d.GovernanceState.Votes[proposalID]
+	votes, exists := d.GovernanceState.ProposalVotes(proposalID)
 	if !exists {
 		return nil, ErrProposalNotFoundError
 	}
@@ -141,7 +306,7 @@ func (d *DAO) GetVotes(proposalID types.Hash) (map[string]*Vote, error) {
 
 // GetTokenBalance retrieves the token balance for an address
 func (d *DAO) GetTokenBalance(address crypto.PublicKey) uint64 {
-	return d.TokenState.Balances[address.String()]
+	return d.TokenState.Balances.Get(address.String())
 }
 
 // GetTotalSupply returns the total token supply
@@ -149,6 +314,12 @@ func (d *DAO) GetTotalSupply() uint64 {
 	return d.TokenState.TotalSupply
 }
 
+// GetTreasuryStatement returns the treasury statement of a month ("2024-06")
+// or a year ("2024")
+func (d *DAO) GetTreasuryStatement(period string) (*TreasuryStatement, error) {
+	return d.TreasuryManager.GenerateStatement(period, d.GovernanceState.Now())
+}
+
 // GetTreasuryBalance returns the current treasury balance
 func (d *DAO) GetTreasuryBalance() uint64 {
 	return d.GovernanceState.Treasury.Balance
@@ -165,6 +336,12 @@ func (d *DAO) GetEffectiveVotingPower(user crypto.PublicKey) uint64 {
 	return d.Processor.GetEffectiveVotingPower(user)
 }
 
+// GetEffectiveVotingPowers returns the effective voting power of many users
+// by address
+func (d *DAO) GetEffectiveVotingPowers(users []crypto.PublicKey) map[string]uint64 {
+	return d.Processor.GetEffectiveVotingPowers(users)
+}
+
 // GetDelegatedPower returns the total voting power delegated to a user
 func (d *DAO) GetDelegatedPower(delegate crypto.PublicKey) uint64 {
 	return d.Processor.GetDelegatedPower(delegate)
@@ -183,7 +360,7 @@ func (d *DAO) RevokeDelegation(delegator crypto.PublicKey) error {
 // ListDelegations returns all active delegations
 func (d *DAO) ListDelegations() map[string]*Delegation {
 	activeDelegations := make(map[string]*Delegation)
-	now := time.Now().Unix()
+	now := d.GovernanceState.Now()
 
 	for delegatorStr, delegation := range d.GovernanceState.Delegations {
 		if delegation.Active && now >= delegation.StartTime && now <= delegation.EndTime {
@@ -198,7 +375,7 @@ func (d *DAO) ListDelegations() map[string]*Delegation {
 func (d *DAO) GetDelegationsByDelegate(delegate crypto.PublicKey) []*Delegation {
 	var delegations []*Delegation
 	delegateStr := delegate.String()
-	now := time.Now().Unix()
+	now := d.GovernanceState.Now()
 
 	for _, delegation := range d.GovernanceState.Delegations {
 		if delegation.Active && delegation.Delegate.String() == delegateStr {
@@ -234,9 +411,10 @@ func (d *DAO) ListActiveProposals() []*Proposal {
 func (d *DAO) ListAllProposals() []*Proposal {
 	var allProposals []*Proposal
 
-	for _, proposal := range d.GovernanceState.Proposals {
+	d.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
 		allProposals = append(allProposals, proposal)
-	}
+		return true
+	})
 
 	return allProposals
 }
@@ -279,6 +457,51 @@ func (d *DAO) SignTreasuryTransaction(txHash types.Hash, signer crypto.PrivateKe
 	return d.TreasuryManager.SignTreasuryTransaction(txHash, signer)
 }
 
+// AttachAggregateSignature signs a pending treasury transaction with one
+// aggregate signature of its signers
+func (d *DAO) AttachAggregateSignature(txHash types.Hash, sig *crypto.AggregateSignature) error {
+	return d.TreasuryManager.AttachAggregateSignature(txHash, sig)
+}
+
+// StartKeyGeneration starts the generation of a treasury threshold key
+// among the treasury signers
+func (d *DAO) StartKeyGeneration(initiator crypto.PublicKey, threshold uint8) (*TreasuryKeyGeneration, error) {
+	return d.TreasuryManager.StartKeyGeneration(initiator, threshold)
+}
+
+// SubmitKeyDealing records the dealing of a key generation participant
+func (d *DAO) SubmitKeyDealing(signer crypto.PublicKey, dealing *crypto.DKGDealing) error {
+	return d.TreasuryManager.SubmitKeyDealing(signer, dealing)
+}
+
+// ConfirmKeyGeneration records whether a participant accepts the shares
+// dealt to it
+func (d *DAO) ConfirmKeyGeneration(signer crypto.PublicKey, accept bool) error {
+	return d.TreasuryManager.ConfirmKeyGeneration(signer, accept)
+}
+
+// CommitThresholdNonce records a nonce commitment to sign a treasury
+// transaction with the threshold key
+func (d *DAO) CommitThresholdNonce(txHash types.Hash, signer crypto.PublicKey, commitment []byte) error {
+	return d.TreasuryManager.CommitThresholdNonce(txHash, signer, commitment)
+}
+
+// SubmitThresholdPartial records a partial threshold signature of a
+// treasury transaction
+func (d *DAO) SubmitThresholdPartial(txHash types.Hash, signer crypto.PublicKey, partial []byte) error {
+	return d.TreasuryManager.SubmitThresholdPartial(txHash, signer, partial)
+}
+
+// GetThresholdKey returns the treasury threshold key
+func (d *DAO) GetThresholdKey() *TreasuryThresholdKey {
+	return d.TreasuryManager.GetThresholdKey()
+}
+
+// GetKeyGeneration returns the running treasury key generation
+func (d *DAO) GetKeyGeneration() *TreasuryKeyGeneration {
+	return d.TreasuryManager.GetKeyGeneration()
+}
+
 // ExecuteTreasuryTransaction executes a treasury transaction if it has sufficient signatures
 func (d *DAO) ExecuteTreasuryTransaction(txHash types.Hash) error {
 	return d.TreasuryManager.ExecuteTreasuryTransaction(txHash)
@@ -291,7 +514,17 @@ func (d *DAO) GetPendingTreasuryTransactions() map[types.Hash]*PendingTx {
 
 // GetTreasuryTransaction returns a specific treasury transaction
 func (d *DAO) GetTreasuryTransaction(txHash types.Hash) (*PendingTx, bool) {
-	return d.TreasuryManager.GetTreasuryTransaction(txHash)
+	tx, exists := d.TreasuryManager.GetTreasuryTransaction(txHash)
+	if exists {
+		d.Archive.touch(archiveKey{archiveTreasuryTx, txHash})
+	}
+	return tx, exists
+}
+
+// GetTreasuryAwaitingSigners returns the signers yet to sign a pending
+// treasury transaction
+func (d *DAO) GetTreasuryAwaitingSigners(txHash types.Hash) []crypto.PublicKey {
+	return d.TreasuryManager.AwaitingSigners(txHash)
 }
 
 // GetTreasurySigners returns the list of authorized treasury signers
@@ -314,6 +547,12 @@ func (d *DAO) CleanupExpiredTransactions() int {
 	return d.TreasuryManager.CleanupExpiredTransactions()
 }
 
+// RetryTreasuryConversions retries the re-queued stable denominated treasury
+// transactions and returns how many executed
+func (d *DAO) RetryTreasuryConversions() int {
+	return d.TreasuryManager.RetryConversions()
+}
+
 // GetTreasuryHistory returns all treasury transactions (executed and pending)
 func (d *DAO) GetTreasuryHistory() map[types.Hash]*PendingTx {
 	return d.TreasuryManager.GetTreasuryHistory()
@@ -324,13 +563,55 @@ func (d *DAO) GetExecutedTreasuryTransactions() map[types.Hash]*PendingTx {
 	return d.TreasuryManager.GetExecutedTreasuryTransactions()
 }
 
-// ProcessDAOTransaction processes any DAO transaction type
+// ProcessDAOTransaction processes any DAO transaction type. When a chain
+// submitter is set the transaction is handed to the chain instead of being
+// applied directly.
 func (d *DAO) ProcessDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash) error {
+	if d.chainSubmitter != nil {
+		return d.chainSubmitter.SubmitDAOTransaction(txInner, from, txHash)
+	}
+
+	return d.ApplyDAOTransaction(txInner, from, txHash, 0)
+}
+
+// dispatchDAOTransaction routes a DAO transaction to the matching processor method
+func (d *DAO) dispatchDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash) error {
 	switch tx := txInner.(type) {
 	case *ProposalTx:
-		return d.Processor.ProcessProposalTx(tx, from, txHash)
+		if tx.ProposalType == ProposalTypeConstitution {
+			return NewDAOError(ErrInvalidProposal, "constitution proposals are submitted as amendments", nil)
+		}
+		if err := d.FeeSponsor.CheckBudget(tx.VoteSponsor); err != nil {
+			return err
+		}
+		if err := d.OracleManager.CheckConditionSpecs(tx.Conditions); err != nil {
+			return err
+		}
+		if err := d.Dependencies.Check(txHash, tx.DependsOn); err != nil {
+			return err
+		}
+		if err := d.Endorsements.CheckDeposit(from, tx.Fee); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx, from, txHash); err != nil {
+			return err
+		}
+		d.OracleManager.Attach(txHash, tx.Conditions)
+		d.Dependencies.Add(txHash, tx.DependsOn)
+		d.Endorsements.Open(txHash, from, d.GovernanceState.Now())
+		return d.FeeSponsor.Reserve(txHash, tx.VoteSponsor)
 	case *VoteTx:
-		return d.Processor.ProcessVoteTx(tx, from)
+		if err := d.Endorsements.CheckVotable(tx.ProposalID); err != nil {
+			return err
+		}
+		if err := d.ParameterManager.CheckTrackQueue(tx.ProposalID); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessVoteTx(tx, from); err != nil {
+			return err
+		}
+		d.FeeSponsor.SponsorVoteFee(tx.ProposalID, from, uint64(tx.Fee))
+		return nil
 	case *DelegationTx:
 		return d.Processor.ProcessDelegationTx(tx, from)
 	case *TreasuryTx:
@@ -339,6 +620,26 @@ func (d *DAO) ProcessDAOTransaction(txInner interface{}, from crypto.PublicKey,
 		return d.Processor.ProcessTokenMintTx(tx, from)
 	case *TokenBurnTx:
 		return d.Processor.ProcessTokenBurnTx(tx, from)
+	case *RageQuitTx:
+		if err := d.Processor.ProcessRageQuitTx(tx, from); err != nil {
+			return err
+		}
+		if holder, exists := d.GovernanceState.TokenHolders[from.String()]; exists && holder.Status == MembershipStatusExited {
+			d.Soulbound.BurnMembership(from, d.GovernanceState.Now())
+		}
+		return nil
+	case *DAOCreateTx:
+		if d.registry == nil {
+			return NewDAOError(ErrInvalidDAO, "this DAO does not host other DAOs", nil)
+		}
+		if err := d.Validator.ValidateDAOCreateTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.registry.create(tx, from); err != nil {
+			return err
+		}
+		d.TokenState.Balances.Sub(from.String(), uint64(tx.Fee))
+		return nil
 	case *TokenTransferTx:
 		return d.Processor.ProcessTokenTransferTx(tx, from)
 	case *TokenApproveTx:
@@ -357,13 +658,424 @@ func (d *DAO) ProcessDAOTransaction(txInner interface{}, from crypto.PublicKey,
 		return d.Processor.ProcessUnstakeTx(tx, from)
 	case *ClaimRewardsTx:
 		return d.Processor.ProcessClaimRewardsTx(tx, from)
+	case *PositionTransferTx:
+		if err := d.Validator.ValidatePositionTransferTx(tx, from); err != nil {
+			return err
+		}
+		return d.PositionManager.ProcessPositionTransferTx(tx, from, txHash)
+	case *DisputeTx:
+		if err := d.Validator.ValidateDisputeTx(tx, from); err != nil {
+			return err
+		}
+		return d.DisputeManager.ProcessDisputeTx(tx, from, txHash)
+	case *DisputeEvidenceTx:
+		if err := d.Validator.ValidateDisputeEvidenceTx(tx, from); err != nil {
+			return err
+		}
+		return d.DisputeManager.ProcessDisputeEvidenceTx(tx, from)
+	case *JurorCommitTx:
+		if err := d.Validator.ValidateJurorCommitTx(tx, from); err != nil {
+			return err
+		}
+		return d.DisputeManager.ProcessJurorCommitTx(tx, from)
+	case *JurorRevealTx:
+		if err := d.Validator.ValidateJurorRevealTx(tx, from); err != nil {
+			return err
+		}
+		return d.DisputeManager.ProcessJurorRevealTx(tx, from)
+	case *FundingKPITx:
+		if err := d.Validator.ValidateFundingKPITx(tx, from); err != nil {
+			return err
+		}
+		return d.ImpactTracker.ProcessFundingKPITx(tx, from)
+	case *ImpactReviewTx:
+		if err := d.Validator.ValidateImpactReviewTx(tx, from); err != nil {
+			return err
+		}
+		return d.ImpactTracker.ProcessImpactReviewTx(tx, from)
+	case *ValidatorConfigTx:
+		if err := d.Validator.ValidateValidatorConfigTx(tx, from); err != nil {
+			return err
+		}
+		return d.ValidatorManager.ProcessValidatorConfigTx(tx, from)
+	case *ClaimCommissionTx:
+		if err := d.Validator.ValidateClaimCommissionTx(tx, from); err != nil {
+			return err
+		}
+		return d.ValidatorManager.ProcessClaimCommissionTx(tx, from)
+	case *ValidatorSetProposalTx:
+		if err := d.ValidatorManager.CheckValidatorSetChange(tx.Validator, tx.Remove); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.ValidatorManager.RecordValidatorSetChange(txHash, tx)
+		return nil
+	case *ValidatorSetExecuteTx:
+		if err := d.Validator.ValidateValidatorSetExecuteTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.ValidatorManager.ProcessValidatorSetExecuteTx(tx, from)
+	case *MultisigCreateTx:
+		if err := d.Validator.ValidateMultisigCreateTx(tx, from); err != nil {
+			return err
+		}
+		return d.MultisigManager.ProcessMultisigCreateTx(tx, from, txHash)
+	case *MultisigSubmitTx:
+		if err := d.Validator.ValidateMultisigSubmitTx(tx, from); err != nil {
+			return err
+		}
+		account, err := d.MultisigManager.CheckSubmit(tx, from)
+		if err != nil {
+			return err
+		}
+		// The transaction runs before any approval is recorded, so a failed
+		// execution leaves the multisig untouched
+		executed := account.Threshold <= 1
+		if executed {
+			if err := d.dispatchDAOTransaction(tx.Tx, account.Account(), txHash); err != nil {
+				return err
+			}
+		}
+		d.MultisigManager.RecordSubmit(tx, from, txHash, executed)
+		return nil
+	case *MultisigSignTx:
+		if err := d.Validator.ValidateMultisigSignTx(tx, from); err != nil {
+			return err
+		}
+		pending, account, err := d.MultisigManager.CheckSign(tx, from)
+		if err != nil {
+			return err
+		}
+		executed := len(pending.Approvals)+1 >= int(account.Threshold)
+		if executed {
+			if err := d.dispatchDAOTransaction(pending.Tx, account.Account(), pending.ID); err != nil {
+				return err
+			}
+		}
+		d.MultisigManager.RecordSign(tx, from, txHash, executed)
+		return nil
+	case *SubDAOCreateProposalTx:
+		if err := d.Validator.ValidateSubDAOCreateProposalTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.SubDAOManager.CheckCharter(tx.Name); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.SubDAOManager.RecordCharter(txHash, tx)
+		return nil
+	case *SubDAOCreateExecuteTx:
+		if err := d.Validator.ValidateSubDAOCreateExecuteTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.SubDAOManager.ProcessSubDAOCreateExecuteTx(tx, from)
+	case *SubDAOProposalTx:
+		if err := d.Validator.ValidateSubDAOProposalTx(tx, from); err != nil {
+			return err
+		}
+		return d.SubDAOManager.ProcessSubDAOProposalTx(tx, from, txHash)
+	case *SubDAOVoteTx:
+		if err := d.Validator.ValidateSubDAOVoteTx(tx, from); err != nil {
+			return err
+		}
+		return d.SubDAOManager.ProcessSubDAOVoteTx(tx, from)
+	case *GrantProposalTx:
+		if err := d.Validator.ValidateGrantProposalTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.GrantManager.RecordProposal(txHash, tx, from)
+		return nil
+	case *GrantExecuteTx:
+		if err := d.Validator.ValidateGrantExecuteTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.GrantManager.ProcessGrantExecuteTx(tx, from)
+	case *GrantMilestoneSubmitTx:
+		if err := d.Validator.ValidateGrantMilestoneSubmitTx(tx, from); err != nil {
+			return err
+		}
+		return d.GrantManager.ProcessGrantMilestoneSubmitTx(tx, from)
+	case *GrantMilestoneReviewTx:
+		if err := d.Validator.ValidateGrantMilestoneReviewTx(tx, from); err != nil {
+			return err
+		}
+		return d.GrantManager.ProcessGrantMilestoneReviewTx(tx, from)
+	case *GrantCancelTx:
+		if err := d.Validator.ValidateGrantCancelTx(tx, from); err != nil {
+			return err
+		}
+		return d.GrantManager.ProcessGrantCancelTx(tx, from)
+	case *BountyProposalTx:
+		if err := d.Validator.ValidateBountyProposalTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.BountyManager.RecordProposal(txHash, tx, from)
+		return nil
+	case *BountyPostTx:
+		if err := d.Validator.ValidateBountyPostTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.BountyManager.ProcessBountyPostTx(tx, from)
+	case *BountyClaimTx:
+		if err := d.Validator.ValidateBountyClaimTx(tx, from); err != nil {
+			return err
+		}
+		return d.BountyManager.ProcessBountyClaimTx(tx, from)
+	case *BountySubmitTx:
+		if err := d.Validator.ValidateBountySubmitTx(tx, from); err != nil {
+			return err
+		}
+		return d.BountyManager.ProcessBountySubmitTx(tx, from)
+	case *BountyReviewTx:
+		if err := d.Validator.ValidateBountyReviewTx(tx, from); err != nil {
+			return err
+		}
+		return d.BountyManager.ProcessBountyReviewTx(tx, from)
+	case *BountyCancelTx:
+		if err := d.Validator.ValidateBountyCancelTx(tx, from); err != nil {
+			return err
+		}
+		return d.BountyManager.ProcessBountyCancelTx(tx, from)
+	case *QFRoundProposalTx:
+		if err := d.Validator.ValidateQFRoundProposalTx(tx, from); err != nil {
+			return err
+		}
+		if _, exists := d.MultisigManager.GetAccount(tx.Multisig); !exists {
+			return ErrMultisigNotFoundError
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.QFManager.RecordProposal(txHash, tx, from)
+		return nil
+	case *QFRoundOpenTx:
+		if err := d.Validator.ValidateQFRoundOpenTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.QFManager.ProcessQFRoundOpenTx(tx, from)
+	case *QFContributeTx:
+		if err := d.Validator.ValidateQFContributeTx(tx, from); err != nil {
+			return err
+		}
+		return d.QFManager.ProcessQFContributeTx(tx, from)
+	case *QFPayoutTx:
+		if err := d.Validator.ValidateQFPayoutTx(tx, from); err != nil {
+			return err
+		}
+		return d.QFManager.ProcessQFPayoutTx(tx, from)
+	case *OptimisticProposalTx:
+		if err := d.Validator.ValidateOptimisticProposalTx(tx, from); err != nil {
+			return err
+		}
+		return d.OptimisticManager.ProcessOptimisticProposalTx(tx, from, txHash)
+	case *OptimisticChallengeTx:
+		if err := d.Validator.ValidateOptimisticChallengeTx(tx, from); err != nil {
+			return err
+		}
+		return d.OptimisticManager.ProcessOptimisticChallengeTx(tx, from)
+	case *RPGFRoundProposalTx:
+		if err := d.Validator.ValidateRPGFRoundProposalTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.RPGFManager.RecordProposal(txHash, tx, from)
+		return nil
+	case *RPGFRoundOpenTx:
+		if err := d.Validator.ValidateRPGFRoundOpenTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.RPGFManager.ProcessRPGFRoundOpenTx(tx, from)
+	case *RPGFNominateTx:
+		if err := d.Validator.ValidateRPGFNominateTx(tx, from); err != nil {
+			return err
+		}
+		return d.RPGFManager.ProcessRPGFNominateTx(tx, from)
+	case *RPGFBallotTx:
+		if err := d.Validator.ValidateRPGFBallotTx(tx, from); err != nil {
+			return err
+		}
+		return d.RPGFManager.ProcessRPGFBallotTx(tx, from)
+	case *RPGFFinalizeTx:
+		if err := d.Validator.ValidateRPGFFinalizeTx(tx, from); err != nil {
+			return err
+		}
+		return d.RPGFManager.ProcessRPGFFinalizeTx(tx, from)
+	case *RPGFClaimTx:
+		if err := d.Validator.ValidateRPGFClaimTx(tx, from); err != nil {
+			return err
+		}
+		return d.RPGFManager.ProcessRPGFClaimTx(tx, from)
+	case *OracleFeedProposalTx:
+		if err := d.Validator.ValidateOracleFeedProposalTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.OracleManager.RecordProposal(txHash, tx)
+		return nil
+	case *OracleFeedActivateTx:
+		if err := d.Validator.ValidateOracleFeedActivateTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.OracleManager.ProcessOracleFeedActivateTx(tx, from)
+	case *TrackProposalTx:
+		if err := d.Validator.ValidateTrackProposalTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.ParameterManager.RecordTrackProposal(txHash, tx)
+		return nil
+	case *TrackActivateTx:
+		if err := d.Validator.ValidateTrackActivateTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.ParameterManager.ProcessTrackActivateTx(tx, from)
+	case *ParameterScheduleTx:
+		if err := d.Validator.ValidateParameterScheduleTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.ParameterManager.RecordParameterSchedule(txHash, tx)
+		return nil
+	case *OracleReportTx:
+		if err := d.Validator.ValidateOracleReportTx(tx, from); err != nil {
+			return err
+		}
+		return d.OracleManager.ProcessOracleReportTx(tx, from)
+	case *ConstitutionAmendmentTx:
+		if err := d.Validator.ValidateConstitutionAmendmentTx(tx, from); err != nil {
+			return err
+		}
+		if err := d.Constitution.CheckAmendment(tx); err != nil {
+			return err
+		}
+		if err := d.Processor.ProcessProposalTx(tx.Proposal(), from, txHash); err != nil {
+			return err
+		}
+		d.Constitution.RecordProposal(txHash, tx)
+		return nil
+	case *ConstitutionEnactTx:
+		if err := d.Validator.ValidateConstitutionEnactTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.Constitution.ProcessConstitutionEnactTx(tx, from)
+	case *EmergencySpendTx:
+		if err := d.Validator.ValidateEmergencySpendTx(tx, from); err != nil {
+			return err
+		}
+		return d.EmergencySpends.ProcessEmergencySpendTx(tx, from, txHash)
+	case *EmergencyExecuteTx:
+		if err := d.Validator.ValidateEmergencyExecuteTx(tx, from); err != nil {
+			return err
+		}
+		d.Processor.UpdateProposalStatus(tx.ProposalID)
+		return d.EmergencySpends.ProcessEmergencyExecuteTx(tx, from)
+	case *ParticipationOptInTx:
+		if err := d.Validator.ValidateParticipationOptInTx(tx, from); err != nil {
+			return err
+		}
+		return d.TokenomicsManager.ProcessParticipationOptInTx(tx, from)
+	case *EndorseProposalTx:
+		if err := d.Validator.ValidateEndorseProposalTx(tx, from); err != nil {
+			return err
+		}
+		return d.Endorsements.ProcessEndorseProposalTx(tx, from)
+	case *PrivacySettingsTx:
+		if err := d.Validator.ValidatePrivacySettingsTx(tx, from); err != nil {
+			return err
+		}
+		return d.Privacy.ProcessPrivacySettingsTx(tx, from)
+	case *NameRegisterTx:
+		if err := d.Validator.ValidateNameTx(tx.Fee, tx.Name, from); err != nil {
+			return err
+		}
+		return d.Names.ProcessNameRegisterTx(tx, from)
+	case *NameRenewTx:
+		if err := d.Validator.ValidateNameTx(tx.Fee, tx.Name, from); err != nil {
+			return err
+		}
+		return d.Names.ProcessNameRenewTx(tx, from)
+	case *NameTransferTx:
+		if err := d.Validator.ValidateNameTx(tx.Fee, tx.Name, from); err != nil {
+			return err
+		}
+		return d.Names.ProcessNameTransferTx(tx, from)
+	case *ProfileUpdateTx:
+		if err := d.Validator.ValidateProfileUpdateTx(tx, from); err != nil {
+			return err
+		}
+		return d.Profiles.ProcessProfileUpdateTx(tx, from)
+	case *DelegateStatementTx:
+		if err := d.Validator.ValidateDelegateStatementTx(tx, from); err != nil {
+			return err
+		}
+		return d.Delegates.ProcessDelegateStatementTx(tx, from)
+	case *CommentTx:
+		if err := d.Validator.ValidateCommentTx(tx, from); err != nil {
+			return err
+		}
+		return d.CommentManager.ProcessCommentTx(tx, from, txHash)
+	case *JoinRequestTx:
+		if err := d.Validator.ValidateJoinRequestTx(tx, from); err != nil {
+			return err
+		}
+		return d.Membership.ProcessJoinRequestTx(tx, from)
+	case *JoinApprovalTx:
+		if err := d.Validator.ValidateJoinApprovalTx(tx, from); err != nil {
+			return err
+		}
+		return d.Membership.ProcessJoinApprovalTx(tx, from)
+	case *MembershipStatusTx:
+		if err := d.Validator.ValidateMembershipStatusTx(tx, from); err != nil {
+			return err
+		}
+		return d.Membership.ProcessMembershipStatusTx(tx, from)
 	default:
 		return NewDAOError(ErrInvalidProposal, "unknown DAO transaction type", nil)
 	}
 }
 
+// GetMemberActivity returns the DAO transaction history of an address, newest first
+func (d *DAO) GetMemberActivity(address crypto.PublicKey, txTypes []string, offset, limit int) ([]*ActivityRecord, int) {
+	return d.ActivityIndex.GetActivity(address.String(), txTypes, offset, limit)
+}
+
+// GetHolderStatement returns the statement of an address for a year ("2024")
+func (d *DAO) GetHolderStatement(address crypto.PublicKey, year string) (*HolderStatement, error) {
+	return d.ActivityIndex.GenerateHolderStatement(address.String(), year, d.GovernanceState.Now())
+}
+
 // UpdateAllProposalStatuses updates the status of all proposals based on current time
 func (d *DAO) UpdateAllProposalStatuses() {
+	// Open queued proposals whose tracks have free slots, such as after a
+	// cancellation or a raised limit
+	d.reindexProposals(d.ParameterManager.AdvanceTrackQueues(d.GovernanceState.Now()))
 	for proposalID := range d.GovernanceState.Proposals {
 		d.Processor.UpdateProposalStatus(proposalID)
 	}
@@ -491,6 +1203,108 @@ func (d *DAO) RetrieveProposalDocument(docRef *DocumentReference) ([]byte, error
 	return d.IPFSClient.RetrieveDocument(docRef)
 }
 
+// UploadEncryptedProposalDocument encrypts a document for the members of
+// roles and uploads the ciphertext. The returned reference carries the
+// content key wrapped for each member.
+func (d *DAO) UploadEncryptedProposalDocument(name string, data []byte, mimeType string, uploader crypto.PublicKey, roles ...Role) (*DocumentReference, error) {
+	recipients := d.SecurityManager.MembersWithRoles(roles...)
+	if len(recipients) == 0 {
+		return nil, NewDAOError(ErrInvalidProposal, "no members hold the roles the document is encrypted for", nil)
+	}
+
+	ciphertext, encryption, err := EncryptDocument(data, roles, recipients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt document: %w", err)
+	}
+
+	docRef, err := d.IPFSClient.UploadDocument(name, ciphertext, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	docRef.Encryption = encryption
+
+	d.SecurityManager.LogAuditEvent(uploader, "UPLOAD_ENCRYPTED_DOCUMENT", docRef.Hash, "SUCCESS",
+		map[string]interface{}{"roles": roles, "recipients": len(recipients)}, SecurityLevelSensitive)
+
+	return docRef, nil
+}
+
+// RetrieveEncryptedProposalDocument decrypts a document for a user who
+// currently holds one of the roles it is encrypted for
+func (d *DAO) RetrieveEncryptedProposalDocument(docRef *DocumentReference, key crypto.PrivateKey) ([]byte, error) {
+	if docRef.Encryption == nil {
+		return nil, NewDAOError(ErrInvalidProposal, "document is not encrypted", nil)
+	}
+
+	user := key.PublicKey()
+	if !d.holdsDocumentRole(user, docRef.Encryption) {
+		d.SecurityManager.LogAuditEvent(user, "READ_ENCRYPTED_DOCUMENT_DENIED", docRef.Hash, "FAILURE",
+			nil, SecurityLevelSensitive)
+		return nil, NewDAOError(ErrUnauthorized, "user does not hold a role the document is shared with", nil)
+	}
+
+	ciphertext, err := d.IPFSClient.RetrieveDocument(docRef)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptDocument(ciphertext, docRef.Encryption, key)
+}
+
+// DistributeDocumentKeys re-wraps the content key of an encrypted document
+// for members granted its roles since the upload, using the key of a
+// current member, and drops the keys of users who lost the roles. Copies of
+// the reference published before keep the dropped keys. It returns the
+// members the key was newly shared with.
+func (d *DAO) DistributeDocumentKeys(docRef *DocumentReference, holder crypto.PrivateKey) ([]crypto.PublicKey, error) {
+	encryption := docRef.Encryption
+	if encryption == nil {
+		return nil, NewDAOError(ErrInvalidProposal, "document is not encrypted", nil)
+	}
+	if !d.holdsDocumentRole(holder.PublicKey(), encryption) {
+		return nil, NewDAOError(ErrUnauthorized, "user does not hold a role the document is shared with", nil)
+	}
+
+	members := d.SecurityManager.MembersWithRoles(encryption.Roles...)
+	current := make(map[string]bool, len(members))
+	var added []crypto.PublicKey
+	for _, member := range members {
+		current[member.String()] = true
+		if encryption.CanDecrypt(member) {
+			continue
+		}
+		if err := encryption.ShareKey(holder, member); err != nil {
+			return nil, err
+		}
+		added = append(added, member)
+	}
+
+	for recipient := range encryption.WrappedKeys {
+		if !current[recipient] {
+			delete(encryption.WrappedKeys, recipient)
+		}
+	}
+
+	d.SecurityManager.LogAuditEvent(holder.PublicKey(), "DISTRIBUTE_DOCUMENT_KEYS", docRef.Hash, "SUCCESS",
+		map[string]interface{}{"added": len(added), "recipients": len(encryption.WrappedKeys)}, SecurityLevelSensitive)
+
+	return added, nil
+}
+
+// holdsDocumentRole reports whether a user holds a role an encrypted
+// document is shared with
+func (d *DAO) holdsDocumentRole(user crypto.PublicKey, encryption *DocumentEncryption) bool {
+	role, active := d.SecurityManager.GetUserRole(user)
+	if !active {
+		return false
+	}
+	for _, allowed := range encryption.Roles {
+		if role == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // VerifyProposalMetadata verifies that proposal metadata exists and is accessible
 func (d *DAO) VerifyProposalMetadata(proposalID types.Hash) (bool, error) {
 	proposal, err := d.GetProposal(proposalID)
@@ -515,6 +1329,105 @@ func (d *DAO) ListPinnedContent() ([]types.Hash, error) {
 	return d.IPFSClient.ListPinnedContent()
 }
 
+// EnableIPFSMirror mirrors proposal metadata and documents to an S3-compatible
+// store and serves them from it when IPFS is slow or unreachable
+func (d *DAO) EnableIPFSMirror(config MirrorConfig, fallbackDelay time.Duration) *IPFSMirror {
+	mirror := NewIPFSMirror(NewS3MirrorStore(config))
+	d.IPFSClient.EnableMirror(mirror, fallbackDelay)
+	return mirror
+}
+
+// EnableIPFSPinning replicates pins to additional IPFS nodes and pinning
+// services, re-pinning lost content every reconcile interval, and reads
+// content from the gateways when the IPFS node cannot serve it
+func (d *DAO) EnableIPFSPinning(config IPFSPinningConfig) *PinReconciler {
+	if len(config.Gateways) > 0 {
+		d.IPFSClient.SetGateways(config.Gateways)
+	}
+
+	pinners := config.Pinners()
+	if len(pinners) == 0 {
+		return nil
+	}
+
+	pins := NewPinReconciler(pinners...)
+	d.IPFSClient.EnableReplication(pins)
+	pins.Start(config.ReconcileInterval)
+	return pins
+}
+
+// EnableMetadataCache caches proposal metadata in memory and on disk and
+// warms it with the metadata of active proposals in the background
+func (d *DAO) EnableMetadataCache(config MetadataCacheConfig) (*MetadataCache, error) {
+	cache, err := NewMetadataCache(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata cache: %w", err)
+	}
+
+	d.IPFSClient.EnableMetadataCache(cache)
+	go d.warmMetadata(d.activeMetadataHashes())
+	return cache, nil
+}
+
+// WarmMetadataCache retrieves the metadata of active proposals so it is
+// cached before clients ask for it, returning how many were retrieved
+func (d *DAO) WarmMetadataCache() int {
+	return d.warmMetadata(d.activeMetadataHashes())
+}
+
+// activeMetadataHashes returns the metadata hashes of active proposals
+func (d *DAO) activeMetadataHashes() []types.Hash {
+	var hashes []types.Hash
+	for _, proposal := range d.ListActiveProposals() {
+		if proposal.MetadataHash != (types.Hash{}) {
+			hashes = append(hashes, proposal.MetadataHash)
+		}
+	}
+	return hashes
+}
+
+func (d *DAO) warmMetadata(hashes []types.Hash) int {
+	warmed := 0
+	for _, hash := range hashes {
+		if _, err := d.IPFSClient.RetrieveProposalMetadata(hash); err == nil {
+			warmed++
+		}
+	}
+	return warmed
+}
+
+// EnableNotifications delivers queued notifications through notifiers
+func (d *DAO) EnableNotifications(notifiers ...Notifier) {
+	for _, notifier := range notifiers {
+		d.Notifications.RegisterNotifier(notifier)
+	}
+	d.Notifications.Start()
+}
+
+// SetNotificationPreferences stores the notification preferences of a
+// token holder
+func (d *DAO) SetNotificationPreferences(member crypto.PublicKey, preferences *NotificationPreferences) error {
+	if _, exists := d.GetTokenHolder(member); !exists {
+		return NewDAOError(ErrUnauthorized, "only token holders can subscribe to notifications", nil)
+	}
+
+	preferences.Member = member.String()
+	if err := d.Notifications.SetPreferences(preferences); err != nil {
+		return NewDAOError(ErrInvalidProposal, err.Error(), nil)
+	}
+	return nil
+}
+
+// GetNotificationPreferences returns the notification preferences of a member
+func (d *DAO) GetNotificationPreferences(member crypto.PublicKey) (*NotificationPreferences, bool) {
+	return d.Notifications.GetPreferences(member.String())
+}
+
+// RemoveNotificationPreferences unsubscribes a member from notifications
+func (d *DAO) RemoveNotificationPreferences(member crypto.PublicKey) {
+	d.Notifications.RemovePreferences(member.String())
+}
+
 // CleanupUnusedMetadata unpins metadata for proposals that are no longer active
 func (d *DAO) CleanupUnusedMetadata() error {
 	// Get all pinned content
@@ -533,6 +1446,14 @@ func (d *DAO) CleanupUnusedMetadata() error {
 		}
 	}
 
+	// Anchored results stay pinned for good
+	d.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
+		if proposal.ResultHash != (types.Hash{}) {
+			activeMetadataHashes[proposal.ResultHash] = true
+		}
+		return true
+	})
+
 	// Unpin metadata that's not associated with active proposals
 	for _, hash := range pinnedHashes {
 		if !activeMetadataHashes[hash] {
@@ -738,6 +1659,10 @@ func (d *DAO) SecureProcessDAOTransaction(txInner interface{}, from crypto.Publi
 		operation = "BurnTokens"
 		permission = PermissionVote // Users can burn their own tokens
 		securityLevel = SecurityLevelMember
+	case *RageQuitTx:
+		operation = "RageQuit"
+		permission = PermissionVote
+		securityLevel = SecurityLevelMember
 	case *ParameterProposalTx:
 		operation = "ParameterProposal"
 		permission = PermissionCreateProposal
@@ -788,7 +1713,7 @@ func (d *DAO) InitializeFounderRoles(founders []crypto.PublicKey) error {
 		Role:        RoleSuperAdmin,
 		Permissions: d.SecurityManager.rolePermissions[RoleSuperAdmin],
 		GrantedBy:   firstFounder,
-		GrantedAt:   time.Now().Unix(),
+		GrantedAt:   d.GovernanceState.Now(),
 		ExpiresAt:   0,
 		Active:      true,
 	}
@@ -886,14 +1811,60 @@ func (d *DAO) GetProposalAnalytics() *ProposalAnalytics {
 	return d.AnalyticsSystem.GetProposalAnalytics()
 }
 
+// GetDelegateScorecards returns the voting record of every current delegate
+func (d *DAO) GetDelegateScorecards() []*DelegateScorecard {
+	return d.AnalyticsSystem.GetDelegateScorecards(d.GovernanceState.Now())
+}
+
+// GetDelegateScorecard returns the voting record of a delegate
+func (d *DAO) GetDelegateScorecard(delegate crypto.PublicKey) *DelegateScorecard {
+	return d.AnalyticsSystem.GetDelegateScorecard(delegate.String(), d.GovernanceState.Now())
+}
+
+// GetProposalTurnout returns the segmented turnout of a proposal with a
+// votes-over-time histogram of the given number of buckets
+func (d *DAO) GetProposalTurnout(proposalID types.Hash, buckets int) (*ProposalTurnout, error) {
+	return d.AnalyticsSystem.GetProposalTurnout(proposalID, buckets, d.GovernanceState.Now())
+}
+
+// GetAdaptiveQuorum returns the quorum recent turnout sets for new proposals
+func (d *DAO) GetAdaptiveQuorum() *AdaptiveQuorum {
+	config := d.ParameterManager.GetParameterConfig()
+	return d.AnalyticsSystem.GetAdaptiveQuorum(config.AdaptiveQuorumWindow, config.AdaptiveQuorumMin, config.AdaptiveQuorumMax)
+}
+
 // GetDAOHealthMetrics returns overall DAO health indicators
 func (d *DAO) GetDAOHealthMetrics() *DAOHealthMetrics {
 	return d.AnalyticsSystem.GetDAOHealthMetrics()
 }
 
+// GetStakingYieldMetrics returns treasury yield analytics for staking pools
+func (d *DAO) GetStakingYieldMetrics() *StakingYieldMetrics {
+	metrics := d.YieldManager.GetStakingYieldMetrics()
+	for _, validator := range d.ValidatorManager.ListValidators() {
+		metrics.Validators = append(metrics.Validators, validator.Summary())
+	}
+	return metrics
+}
+
 // GetAnalyticsSummary returns a comprehensive analytics summary
 func (d *DAO) GetAnalyticsSummary() map[string]interface{} {
-	return d.AnalyticsSystem.GetAnalyticsSummary()
+	summary := d.AnalyticsSystem.GetAnalyticsSummary()
+	summary["staking_yield_metrics"] = d.GetStakingYieldMetrics()
+	return summary
+}
+
+// EnableMetricsSampling samples the analytics metrics every interval into a
+// time-series store keeping samples for retention
+func (d *DAO) EnableMetricsSampling(interval, retention time.Duration) *MetricsTimeSeries {
+	d.EnableMetrics(retention).Start(interval)
+	return d.Metrics
+}
+
+// GetMetricTimeSeries returns the sampled values of metric between from and
+// to, averaged into buckets of resolution seconds when resolution is positive
+func (d *DAO) GetMetricTimeSeries(metric string, from, to, resolution int64) ([]MetricPoint, error) {
+	return d.Metrics.Query(metric, from, to, resolution)
 }
 
 // ExecuteParameterChanges executes approved parameter changes
@@ -940,6 +1911,7 @@ func (d *DAO) IsParameterChangeAllowed(parameter string, newValue interface{}) (
 func (d *DAO) GetParameterConstraints(parameter string) map[string]interface{} {
 	return d.ParameterManager.GetParameterConstraints(parameter)
 }
+
 // Tokenomics-related methods
 
 // InitializeTokenomics sets up the initial token distribution system
@@ -977,6 +1949,415 @@ func (d *DAO) ClaimStakingRewards(poolID string, staker crypto.PublicKey) (uint6
 	return d.TokenomicsManager.ClaimStakingRewards(poolID, staker)
 }
 
+// MintVestingPosition creates a transferable position for a vesting schedule
+func (d *DAO) MintVestingPosition(vestingID string, owner crypto.PublicKey) (*Position, error) {
+	return d.PositionManager.MintVestingPosition(vestingID, owner)
+}
+
+// MintStakePosition creates a transferable position for a stake in a pool
+func (d *DAO) MintStakePosition(poolID string, owner crypto.PublicKey) (*Position, error) {
+	return d.PositionManager.MintStakePosition(poolID, owner)
+}
+
+// TransferPosition transfers a position and its underlying vesting or stake
+func (d *DAO) TransferPosition(positionID string, from, to crypto.PublicKey, txHash types.Hash) error {
+	return d.PositionManager.TransferPosition(positionID, from, to, txHash)
+}
+
+// GetPosition returns a position by ID
+func (d *DAO) GetPosition(positionID string) (*Position, bool) {
+	return d.PositionManager.GetPosition(positionID)
+}
+
+// GetPositionsByOwner returns all positions owned by an address
+func (d *DAO) GetPositionsByOwner(owner crypto.PublicKey) []*Position {
+	return d.PositionManager.GetPositionsByOwner(owner)
+}
+
+// ProcessYieldEpoch closes the treasury yield epoch if it is due and
+// returns it, or returns nil while the epoch is still running
+func (d *DAO) ProcessYieldEpoch() (*YieldEpoch, error) {
+	return d.YieldManager.ProcessEpoch(d.GovernanceState.Now())
+}
+
+// GetCurrentYieldEpoch returns the open treasury yield epoch
+func (d *DAO) GetCurrentYieldEpoch() *YieldEpoch {
+	return d.YieldManager.GetCurrentEpoch()
+}
+
+// GetYieldEpochs returns all closed treasury yield epochs
+func (d *DAO) GetYieldEpochs() []*YieldEpoch {
+	return d.YieldManager.GetEpochs()
+}
+
+// GetDispute returns a dispute by ID
+func (d *DAO) GetDispute(disputeID types.Hash) (*Dispute, bool) {
+	return d.DisputeManager.GetDispute(disputeID)
+}
+
+// ListDisputes returns all disputes, oldest first
+func (d *DAO) ListDisputes() []*Dispute {
+	return d.DisputeManager.ListDisputes()
+}
+
+// GetJurorDisputes returns the disputes an address sits on as juror
+func (d *DAO) GetJurorDisputes(juror crypto.PublicKey) []*Dispute {
+	return d.DisputeManager.GetJurorDisputes(juror)
+}
+
+// ResolveDueDisputes enforces rulings on disputes whose reveal phase has ended
+func (d *DAO) ResolveDueDisputes() []*Dispute {
+	return d.DisputeManager.ResolveDueDisputes(d.GovernanceState.Now())
+}
+
+// GetFundingImpact returns the KPIs and reviews of a funding proposal
+func (d *DAO) GetFundingImpact(proposalID types.Hash) (*FundingImpact, bool) {
+	return d.ImpactTracker.GetFundingImpact(proposalID)
+}
+
+// GetPublicGoodsAnalytics returns cost-effectiveness of funded proposals per category and grantee
+func (d *DAO) GetPublicGoodsAnalytics() *PublicGoodsAnalytics {
+	return d.ImpactTracker.GetPublicGoodsAnalytics()
+}
+
+// GetValidator returns the commission settings and fee revenue of a validator
+func (d *DAO) GetValidator(address crypto.PublicKey) (*ValidatorInfo, bool) {
+	return d.ValidatorManager.GetValidator(address)
+}
+
+// ListValidators returns all registered validators ordered by fee revenue
+func (d *DAO) ListValidators() []*ValidatorInfo {
+	return d.ValidatorManager.ListValidators()
+}
+
+// ValidatorSet returns the validators finalizing blocks and their voting power
+func (d *DAO) ValidatorSet() []ValidatorPower {
+	return d.ValidatorManager.ValidatorSet()
+}
+
+// GetValidatorSetChange returns the validator set change of a proposal
+func (d *DAO) GetValidatorSetChange(proposalID types.Hash) (*ValidatorSetChange, bool) {
+	return d.ValidatorManager.GetValidatorSetChange(proposalID)
+}
+
+// GetMultisigAccount returns a multisig account
+func (d *DAO) GetMultisigAccount(id types.Hash) (*MultisigAccount, bool) {
+	return d.MultisigManager.GetAccount(id)
+}
+
+// ListMultisigAccounts returns the multisig accounts, only those owned by
+// owner when it is not empty
+func (d *DAO) ListMultisigAccounts(owner crypto.PublicKey) []*MultisigAccount {
+	return d.MultisigManager.ListAccounts(owner)
+}
+
+// GetMultisigTransactions returns the submitted transactions of a multisig
+// account
+func (d *DAO) GetMultisigTransactions(multisigID types.Hash) []*MultisigTransaction {
+	return d.MultisigManager.GetTransactions(multisigID)
+}
+
+// GetSubDAO returns a sub-DAO
+func (d *DAO) GetSubDAO(id types.Hash) (*SubDAO, bool) {
+	return d.SubDAOManager.GetSubDAO(id)
+}
+
+// ListSubDAOs returns the sub-DAOs, oldest first
+func (d *DAO) ListSubDAOs() []*SubDAO {
+	return d.SubDAOManager.ListSubDAOs()
+}
+
+// GetSubDAOProposals returns the proposals of a sub-DAO
+func (d *DAO) GetSubDAOProposals(subDAOID types.Hash) []*SubDAOProposal {
+	return d.SubDAOManager.GetProposals(subDAOID)
+}
+
+// GetGrant returns a grant
+func (d *DAO) GetGrant(id types.Hash) (*Grant, bool) {
+	return d.GrantManager.GetGrant(id)
+}
+
+// ListGrants returns the grants, optionally filtered by stage and applicant
+func (d *DAO) ListGrants(status GrantStatus, applicant crypto.PublicKey) []*Grant {
+	return d.GrantManager.ListGrants(status, applicant)
+}
+
+// GetGrantPipeline summarises the grants by stage
+func (d *DAO) GetGrantPipeline() *GrantPipeline {
+	return d.GrantManager.Pipeline()
+}
+
+// GetBounty returns a bounty
+func (d *DAO) GetBounty(id types.Hash) (*Bounty, bool) {
+	return d.BountyManager.GetBounty(id)
+}
+
+// GetQFRound returns a quadratic funding round
+func (d *DAO) GetQFRound(id types.Hash) (*QFRound, bool) {
+	return d.QFManager.GetRound(id)
+}
+
+// ListQFRounds returns the quadratic funding rounds, optionally filtered by
+// stage
+func (d *DAO) ListQFRounds(status QFRoundStatus) []*QFRound {
+	return d.QFManager.ListRounds(status)
+}
+
+// GetOptimisticProposal returns an optimistic proposal
+func (d *DAO) GetOptimisticProposal(id types.Hash) (*OptimisticProposal, bool) {
+	return d.OptimisticManager.GetProposal(id)
+}
+
+// ListOptimisticProposals returns the optimistic proposals, optionally
+// filtered by stage
+func (d *DAO) ListOptimisticProposals(status OptimisticStatus) []*OptimisticProposal {
+	return d.OptimisticManager.ListProposals(status)
+}
+
+// ResolveOptimisticProposals executes the optimistic proposals whose
+// challenge window ended unchallenged and settles the bonds of decided
+// challenge votes
+func (d *DAO) ResolveOptimisticProposals() []*OptimisticProposal {
+	for _, id := range d.OptimisticManager.Challenged() {
+		d.Processor.UpdateProposalStatus(id)
+	}
+	return d.OptimisticManager.ResolveDue(d.GovernanceState.Now())
+}
+
+// GetRPGFRound returns a retroactive public goods funding round
+func (d *DAO) GetRPGFRound(id types.Hash) (*RPGFRound, bool) {
+	return d.RPGFManager.GetRound(id)
+}
+
+// ListRPGFRounds returns the retroactive public goods funding rounds,
+// optionally filtered by stage
+func (d *DAO) ListRPGFRounds(status RPGFRoundStatus) []*RPGFRound {
+	return d.RPGFManager.ListRounds(status)
+}
+
+// GetOracleFeed returns an oracle feed
+func (d *DAO) GetOracleFeed(id string) (*OracleFeed, bool) {
+	return d.OracleManager.GetFeed(id)
+}
+
+// ListOracleFeeds returns the oracle feeds ordered by ID
+func (d *DAO) ListOracleFeeds() []*OracleFeed {
+	return d.OracleManager.ListFeeds()
+}
+
+// ApplyParameterRollouts moves the scheduled parameter changes of passed
+// proposals on to where they should be at now
+func (d *DAO) ApplyParameterRollouts(now int64) []*ParameterChange {
+	for _, proposalID := range d.ParameterManager.ScheduledProposals() {
+		d.Processor.UpdateProposalStatus(proposalID)
+	}
+	return d.ParameterManager.ApplyParameterRollouts(now)
+}
+
+// GetPendingParameterChanges previews the scheduled parameter changes that
+// have not reached their target
+func (d *DAO) GetPendingParameterChanges() []PendingParameterChange {
+	return d.ParameterManager.PendingParameterChanges()
+}
+
+// SimulateParameterChanges replays the latest closed proposals under
+// proposed quorum and passing thresholds
+func (d *DAO) SimulateParameterChanges(changes map[string]string, limit int) (*ParameterSimulation, error) {
+	return d.ParameterManager.SimulateParameterChanges(changes, limit)
+}
+
+// GetProposalTrack returns a proposal track
+func (d *DAO) GetProposalTrack(id string) (*ProposalTrack, bool) {
+	return d.ParameterManager.GetTrack(id)
+}
+
+// ListProposalTracks returns the proposal tracks ordered by ID
+func (d *DAO) ListProposalTracks() []*ProposalTrack {
+	return d.ParameterManager.ListTracks()
+}
+
+// GetTrackQueuePosition returns the 1-based position of a proposal in its
+// track's queue
+func (d *DAO) GetTrackQueuePosition(proposalID types.Hash) (int, bool) {
+	return d.ParameterManager.QueuePosition(proposalID)
+}
+
+// GetTrackQueue returns the proposals queued on a track, first in line first
+func (d *DAO) GetTrackQueue(id string) []types.Hash {
+	return d.ParameterManager.TrackQueue(id)
+}
+
+// GetSoulboundToken returns a soulbound token
+func (d *DAO) GetSoulboundToken(id types.Hash) (*SoulboundToken, bool) {
+	return d.Soulbound.Get(id)
+}
+
+// ListSoulboundTokens returns the soulbound tokens of a member in mint
+// order, with the burned ones when includeBurned is set
+func (d *DAO) ListSoulboundTokens(holder crypto.PublicKey, includeBurned bool) []*SoulboundToken {
+	return d.Soulbound.TokensOf(holder, includeBurned)
+}
+
+// EvaluateProposalConditions evaluates the oracle conditions of a proposal
+// against the current feed values
+func (d *DAO) EvaluateProposalConditions(proposalID types.Hash) []OracleConditionResult {
+	return d.OracleManager.Evaluate(proposalID, d.GovernanceState.Now())
+}
+
+// GetGovernanceDocument returns a governance document with its version history
+func (d *DAO) GetGovernanceDocument(id string) (*GovernanceDocument, bool) {
+	return d.Constitution.GetDocument(id)
+}
+
+// ListGovernanceDocuments returns the governance documents ordered by ID
+func (d *DAO) ListGovernanceDocuments() []*GovernanceDocument {
+	return d.Constitution.ListDocuments()
+}
+
+// GetEmergencySpend returns the spend of an emergency proposal
+func (d *DAO) GetEmergencySpend(proposalID types.Hash) (*EmergencySpend, bool) {
+	return d.EmergencySpends.GetSpend(proposalID)
+}
+
+// ListEmergencySpends returns the emergency spends, newest first
+func (d *DAO) ListEmergencySpends() []*EmergencySpend {
+	return d.EmergencySpends.ListSpends()
+}
+
+// ProcessParticipationEpoch closes the participation reward epoch if it has
+// run its full length and returns it, or returns nil while it is running
+func (d *DAO) ProcessParticipationEpoch() *ParticipationEpoch {
+	now := d.GovernanceState.Now()
+	config := d.ParameterManager.GetParameterConfig()
+	if now < d.TokenomicsManager.GetCurrentParticipationEpoch().StartTime+config.ParticipationRewardEpoch {
+		return nil
+	}
+	return d.TokenomicsManager.CloseParticipationEpoch(now, config.ParticipationRewardBudget, config.ParticipationRewardCap)
+}
+
+// PublishMemberProfile validates and signs a member profile and uploads it
+// to IPFS. The returned hash is published with a ProfileUpdateTx.
+func (d *DAO) PublishMemberProfile(profile *MemberProfile, key crypto.PrivateKey) (types.Hash, error) {
+	if err := profile.Validate(); err != nil {
+		return types.Hash{}, err
+	}
+	if err := profile.Sign(key); err != nil {
+		return types.Hash{}, fmt.Errorf("failed to sign member profile: %w", err)
+	}
+	return d.IPFSClient.UploadMemberProfile(profile)
+}
+
+// GetMemberProfile retrieves the published profile of a member from IPFS.
+// A document that is not signed by the member is rejected.
+func (d *DAO) GetMemberProfile(member string) (*MemberProfile, *ProfileRecord, error) {
+	record, exists := d.Profiles.Get(member)
+	if !exists {
+		return nil, nil, NewDAOError(ErrInvalidProfile, "member has no profile", nil)
+	}
+
+	profile, err := d.IPFSClient.RetrieveMemberProfile(record.Hash)
+	if err != nil {
+		return nil, record, err
+	}
+	if profile.Address != member || !profile.Verify() {
+		return nil, record, NewDAOError(ErrInvalidProfile, "profile is not signed by the member", map[string]interface{}{
+			"hash": record.Hash.String(),
+		})
+	}
+	return profile, record, nil
+}
+
+// GetProposalEndorsements returns the endorsements of a proposal that
+// needed them
+func (d *DAO) GetProposalEndorsements(proposalID types.Hash) (*ProposalEndorsements, bool) {
+	return d.Endorsements.Get(proposalID)
+}
+
+// ExpireUnendorsedProposals cancels the proposals whose endorsement period
+// ended without enough endorsements
+func (d *DAO) ExpireUnendorsedProposals() []*ProposalEndorsements {
+	return d.Endorsements.ExpireProposals(d.GovernanceState.Now())
+}
+
+// ListBounties returns the bounties, optionally filtered by status and
+// claimant
+func (d *DAO) ListBounties(status BountyStatus, claimant crypto.PublicKey) []*Bounty {
+	return d.BountyManager.ListBounties(status, claimant)
+}
+
+// GetComment returns a proposal comment
+func (d *DAO) GetComment(id types.Hash) (*Comment, bool) {
+	return d.CommentManager.GetComment(id)
+}
+
+// GetProposalComments returns a page of the discussion threads of a
+// proposal and the number of threads
+func (d *DAO) GetProposalComments(proposalID types.Hash, offset, limit int) ([]*CommentThread, int) {
+	return d.CommentManager.GetThreads(proposalID, offset, limit)
+}
+
+// ReactToComment adds or removes a member's reaction to a comment
+func (d *DAO) ReactToComment(id types.Hash, member crypto.PublicKey, reaction string, add bool) (*Comment, error) {
+	return d.CommentManager.React(id, member, reaction, add)
+}
+
+// ModerateComment hides a comment or shows it again, which requires the
+// permission to moderate proposals
+func (d *DAO) ModerateComment(id types.Hash, moderator crypto.PublicKey, hidden bool, reason string) (*Comment, error) {
+	if !d.SecurityManager.HasPermission(moderator, PermissionModerateProposals) {
+		d.SecurityManager.LogAuditEvent(moderator, "MODERATE_COMMENT_DENIED", id.String(), "FAILURE",
+			nil, SecurityLevelMember)
+		return nil, NewDAOError(ErrUnauthorized, "insufficient permissions to moderate comments", nil)
+	}
+
+	comment, err := d.CommentManager.SetHidden(id, moderator, hidden, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SecurityManager.LogAuditEvent(moderator, "MODERATE_COMMENT", id.String(), "SUCCESS",
+		map[string]interface{}{"hidden": hidden, "reason": reason}, SecurityLevelMember)
+	return comment, nil
+}
+
+// GetMembershipApplication returns the latest membership application of an
+// applicant
+func (d *DAO) GetMembershipApplication(applicant crypto.PublicKey) (*MembershipApplication, bool) {
+	return d.Membership.GetApplication(applicant)
+}
+
+// ListMembershipApplications returns the membership applications with the
+// given status, newest first
+func (d *DAO) ListMembershipApplications(status ApplicationStatus) []*MembershipApplication {
+	return d.Membership.ListApplications(status)
+}
+
+// GetMembershipStatus returns the membership status of an address. Addresses
+// that never joined are reported as not members.
+func (d *DAO) GetMembershipStatus(address crypto.PublicKey) (MembershipStatus, bool) {
+	holder, exists := d.GovernanceState.TokenHolders[address.String()]
+	if !exists {
+		return 0, false
+	}
+	return holder.Status, true
+}
+
+// GetSubDAOAnalytics rolls the sub-DAOs up into one report
+func (d *DAO) GetSubDAOAnalytics() *SubDAOAnalytics {
+	return d.AnalyticsSystem.GetSubDAOAnalytics(d.SubDAOManager, d.GovernanceState.Now())
+}
+
+// GetVoteSponsorship returns the sponsored voting budget of a proposal
+func (d *DAO) GetVoteSponsorship(proposalID types.Hash) (*VoteSponsorship, bool) {
+	return d.FeeSponsor.GetSponsorship(proposalID)
+}
+
+// ReleaseExpiredSponsorships returns unspent sponsored voting budgets of
+// closed voting windows to the treasury
+func (d *DAO) ReleaseExpiredSponsorships() uint64 {
+	return d.FeeSponsor.ReleaseExpired(d.GovernanceState.Now())
+}
+
 // GetDistribution returns a distribution by category
 func (d *DAO) GetDistribution(category DistributionCategory) (*TokenDistribution, bool) {
 	return d.TokenomicsManager.GetDistribution(category)
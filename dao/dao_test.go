@@ -57,12 +57,12 @@ func TestInitialTokenDistribution(t *testing.T) {
 		t.Errorf("Expected total supply 3000, got %d", dao.TokenState.TotalSupply)
 	}
 
-	if dao.TokenState.Balances[addr1.String()] != 1000 {
-		t.Errorf("Expected addr1 balance 1000, got %d", dao.TokenState.Balances[addr1.String()])
+	if dao.TokenState.Balances.Get(addr1.String()) != 1000 {
+		t.Errorf("Expected addr1 balance 1000, got %d", dao.TokenState.Balances.Get(addr1.String()))
 	}
 
-	if dao.TokenState.Balances[addr2.String()] != 2000 {
-		t.Errorf("Expected addr2 balance 2000, got %d", dao.TokenState.Balances[addr2.String()])
+	if dao.TokenState.Balances.Get(addr2.String()) != 2000 {
+		t.Errorf("Expected addr2 balance 2000, got %d", dao.TokenState.Balances.Get(addr2.String()))
 	}
 
 	// Check token holder records
@@ -161,8 +161,8 @@ func TestProposalCreation(t *testing.T) {
 	}
 
 	// Verify fee was deducted
-	if dao.TokenState.Balances[creator.String()] != 1900 {
-		t.Errorf("Expected creator balance 1900, got %d", dao.TokenState.Balances[creator.String()])
+	if dao.TokenState.Balances.Get(creator.String()) != 1900 {
+		t.Errorf("Expected creator balance 1900, got %d", dao.TokenState.Balances.Get(creator.String()))
 	}
 }
 
@@ -230,8 +230,8 @@ func TestTokenMinting(t *testing.T) {
 	}
 
 	// Verify recipient balance
-	if dao.TokenState.Balances[recipient.String()] != 1000 {
-		t.Errorf("Expected recipient balance 1000, got %d", dao.TokenState.Balances[recipient.String()])
+	if dao.TokenState.Balances.Get(recipient.String()) != 1000 {
+		t.Errorf("Expected recipient balance 1000, got %d", dao.TokenState.Balances.Get(recipient.String()))
 	}
 
 	// Verify total supply increased
@@ -240,8 +240,8 @@ func TestTokenMinting(t *testing.T) {
 	}
 
 	// Verify minter fee was deducted
-	if dao.TokenState.Balances[minter.String()] != 1900 { // 2000 - 100 fee
-		t.Errorf("Expected minter balance 1900, got %d", dao.TokenState.Balances[minter.String()])
+	if dao.TokenState.Balances.Get(minter.String()) != 1900 { // 2000 - 100 fee
+		t.Errorf("Expected minter balance 1900, got %d", dao.TokenState.Balances.Get(minter.String()))
 	}
 
 	// Verify token holder record was created
@@ -277,8 +277,8 @@ func TestTokenBurning(t *testing.T) {
 	}
 
 	// Verify burner balance decreased
-	if dao.TokenState.Balances[burner.String()] != 1400 { // 2000 - 500 - 100 fee
-		t.Errorf("Expected burner balance 1400, got %d", dao.TokenState.Balances[burner.String()])
+	if dao.TokenState.Balances.Get(burner.String()) != 1400 { // 2000 - 500 - 100 fee
+		t.Errorf("Expected burner balance 1400, got %d", dao.TokenState.Balances.Get(burner.String()))
 	}
 
 	// Verify total supply decreased
@@ -312,13 +312,13 @@ func TestTokenTransfer(t *testing.T) {
 	}
 
 	// Verify sender balance decreased
-	if dao.TokenState.Balances[sender.String()] != 1400 { // 2000 - 500 - 100 fee
-		t.Errorf("Expected sender balance 1400, got %d", dao.TokenState.Balances[sender.String()])
+	if dao.TokenState.Balances.Get(sender.String()) != 1400 { // 2000 - 500 - 100 fee
+		t.Errorf("Expected sender balance 1400, got %d", dao.TokenState.Balances.Get(sender.String()))
 	}
 
 	// Verify recipient balance increased
-	if dao.TokenState.Balances[recipient.String()] != 500 {
-		t.Errorf("Expected recipient balance 500, got %d", dao.TokenState.Balances[recipient.String()])
+	if dao.TokenState.Balances.Get(recipient.String()) != 500 {
+		t.Errorf("Expected recipient balance 500, got %d", dao.TokenState.Balances.Get(recipient.String()))
 	}
 
 	// Verify total supply unchanged
@@ -367,8 +367,8 @@ func TestTokenApproval(t *testing.T) {
 	}
 
 	// Verify owner fee was deducted
-	if dao.TokenState.Balances[owner.String()] != 1900 { // 2000 - 100 fee
-		t.Errorf("Expected owner balance 1900, got %d", dao.TokenState.Balances[owner.String()])
+	if dao.TokenState.Balances.Get(owner.String()) != 1900 { // 2000 - 100 fee
+		t.Errorf("Expected owner balance 1900, got %d", dao.TokenState.Balances.Get(owner.String()))
 	}
 }
 
@@ -403,18 +403,18 @@ func TestTokenTransferFrom(t *testing.T) {
 	}
 
 	// Verify owner balance decreased
-	if dao.TokenState.Balances[owner.String()] != 1700 { // 2000 - 300
-		t.Errorf("Expected owner balance 1700, got %d", dao.TokenState.Balances[owner.String()])
+	if dao.TokenState.Balances.Get(owner.String()) != 1700 { // 2000 - 300
+		t.Errorf("Expected owner balance 1700, got %d", dao.TokenState.Balances.Get(owner.String()))
 	}
 
 	// Verify recipient balance increased
-	if dao.TokenState.Balances[recipient.String()] != 300 {
-		t.Errorf("Expected recipient balance 300, got %d", dao.TokenState.Balances[recipient.String()])
+	if dao.TokenState.Balances.Get(recipient.String()) != 300 {
+		t.Errorf("Expected recipient balance 300, got %d", dao.TokenState.Balances.Get(recipient.String()))
 	}
 
 	// Verify spender fee was deducted
-	if dao.TokenState.Balances[spender.String()] != 900 { // 1000 - 100 fee
-		t.Errorf("Expected spender balance 900, got %d", dao.TokenState.Balances[spender.String()])
+	if dao.TokenState.Balances.Get(spender.String()) != 900 { // 1000 - 100 fee
+		t.Errorf("Expected spender balance 900, got %d", dao.TokenState.Balances.Get(spender.String()))
 	}
 
 	// Verify allowance was reduced
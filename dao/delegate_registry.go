@@ -0,0 +1,238 @@
+package dao
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// Delegate statement limits
+const (
+	MaxDelegatePlatformLength = 2000
+	MaxDelegateFocusAreas     = 5
+	MaxDelegateFocusLength    = 32
+)
+
+// DelegateStatement is what a member publishes to stand as a delegate
+type DelegateStatement struct {
+	Delegate    crypto.PublicKey
+	Platform    string
+	FocusAreas  []string
+	Fee         uint64 // Basis points of delegators' rewards the delegate asks for
+	PublishedAt int64
+	UpdatedAt   int64
+}
+
+// DelegateListing is a delegate's statement with their scorecard
+type DelegateListing struct {
+	Statement *DelegateStatement
+	Scorecard *DelegateScorecard
+}
+
+// Delegate listing sort keys, the scorecard metrics and the fee
+const (
+	DelegateSortDelegatedPower    = "delegated_power"
+	DelegateSortDelegators        = "delegators"
+	DelegateSortParticipationRate = "participation_rate"
+	DelegateSortAlignmentRate     = "alignment_rate"
+	DelegateSortAverageLatency    = "average_latency"
+	DelegateSortVotesCast         = "votes_cast"
+	DelegateSortFee               = "fee"
+)
+
+// DelegateSortKeys lists the keys delegate listings can be sorted by
+var DelegateSortKeys = []string{
+	DelegateSortDelegatedPower, DelegateSortDelegators, DelegateSortParticipationRate,
+	DelegateSortAlignmentRate, DelegateSortAverageLatency, DelegateSortVotesCast, DelegateSortFee,
+}
+
+// DelegateQuery filters and orders delegate listings
+type DelegateQuery struct {
+	Focus     string // Only delegates with this focus area
+	Text      string // Case-insensitive match on the platform, focus areas or name
+	SortBy    string // One of DelegateSortKeys, delegated power when empty
+	Ascending bool
+}
+
+// DelegateRegistry keeps the statements of members standing as delegates
+type DelegateRegistry struct {
+	tokenState *GovernanceToken
+	statements map[string]*DelegateStatement
+}
+
+// NewDelegateRegistry creates an empty delegate registry
+func NewDelegateRegistry(tokenState *GovernanceToken) *DelegateRegistry {
+	return &DelegateRegistry{
+		tokenState: tokenState,
+		statements: make(map[string]*DelegateStatement),
+	}
+}
+
+// NormalizeFocusAreas lowercases and deduplicates focus areas and checks
+// each is 1 to 32 letters, digits, spaces or hyphens
+func NormalizeFocusAreas(areas []string) ([]string, error) {
+	if len(areas) > MaxDelegateFocusAreas {
+		return nil, NewDAOError(ErrInvalidDelegate, "too many focus areas", map[string]interface{}{
+			"max": MaxDelegateFocusAreas,
+		})
+	}
+
+	normalized := make([]string, 0, len(areas))
+	seen := make(map[string]bool)
+	for _, area := range areas {
+		area = strings.ToLower(strings.TrimSpace(area))
+		if area == "" || utf8.RuneCountInString(area) > MaxDelegateFocusLength {
+			return nil, NewDAOError(ErrInvalidDelegate, "focus areas must be 1 to 32 characters", nil)
+		}
+		for _, r := range area {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == ' ' || r == '-') {
+				return nil, NewDAOError(ErrInvalidDelegate, "focus areas may only hold letters, digits, spaces and hyphens", map[string]interface{}{
+					"focus_area": area,
+				})
+			}
+		}
+		if !seen[area] {
+			seen[area] = true
+			normalized = append(normalized, area)
+		}
+	}
+	return normalized, nil
+}
+
+// ProcessDelegateStatementTx publishes, replaces or withdraws the sender's
+// delegate statement. The sender pays the fee.
+func (dr *DelegateRegistry) ProcessDelegateStatementTx(tx *DelegateStatementTx, delegate crypto.PublicKey) error {
+	delegateStr := delegate.String()
+	if tx.Withdraw {
+		if _, exists := dr.statements[delegateStr]; !exists {
+			return NewDAOError(ErrInvalidDelegate, "no delegate statement to withdraw", nil)
+		}
+		delete(dr.statements, delegateStr)
+		dr.tokenState.Balances.Sub(delegateStr, uint64(tx.Fee))
+		return nil
+	}
+
+	focusAreas, err := NormalizeFocusAreas(tx.FocusAreas)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	statement, exists := dr.statements[delegateStr]
+	if !exists {
+		statement = &DelegateStatement{Delegate: delegate, PublishedAt: now}
+		dr.statements[delegateStr] = statement
+	}
+	statement.Platform = strings.TrimSpace(tx.Platform)
+	statement.FocusAreas = focusAreas
+	statement.Fee = tx.DelegateFee
+	statement.UpdatedAt = now
+
+	dr.tokenState.Balances.Sub(delegateStr, uint64(tx.Fee))
+	return nil
+}
+
+// Get returns the statement of a delegate
+func (dr *DelegateRegistry) Get(delegate string) (*DelegateStatement, bool) {
+	statement, exists := dr.statements[delegate]
+	return statement, exists
+}
+
+// Statements returns every published statement
+func (dr *DelegateRegistry) Statements() []*DelegateStatement {
+	statements := make([]*DelegateStatement, 0, len(dr.statements))
+	for _, statement := range dr.statements {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// matches reports whether a listing passes the query's filters
+func (q DelegateQuery) matches(listing *DelegateListing, name string) bool {
+	statement := listing.Statement
+	if focus := strings.ToLower(strings.TrimSpace(q.Focus)); focus != "" {
+		found := false
+		for _, area := range statement.FocusAreas {
+			if area == focus {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	text := strings.ToLower(strings.TrimSpace(q.Text))
+	if text == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(statement.Platform), text) ||
+		strings.Contains(strings.Join(statement.FocusAreas, " "), text) ||
+		strings.Contains(name, text)
+}
+
+// sortValue returns the metric a listing is sorted by
+func (q DelegateQuery) sortValue(listing *DelegateListing) float64 {
+	scorecard := listing.Scorecard
+	switch q.SortBy {
+	case DelegateSortDelegators:
+		return float64(scorecard.Delegators)
+	case DelegateSortParticipationRate:
+		return scorecard.ParticipationRate
+	case DelegateSortAlignmentRate:
+		return scorecard.AlignmentRate
+	case DelegateSortAverageLatency:
+		return scorecard.AverageLatency
+	case DelegateSortVotesCast:
+		return float64(scorecard.VotesCast)
+	case DelegateSortFee:
+		return float64(listing.Statement.Fee)
+	default:
+		return float64(scorecard.DelegatedPower)
+	}
+}
+
+// SearchDelegates returns the listings of the delegates matching the query
+// in its order, ties broken by address
+func (d *DAO) SearchDelegates(query DelegateQuery) []*DelegateListing {
+	now := time.Now().Unix()
+	listings := make([]*DelegateListing, 0)
+	for _, statement := range d.Delegates.Statements() {
+		address := statement.Delegate.String()
+		listing := &DelegateListing{
+			Statement: statement,
+			Scorecard: d.AnalyticsSystem.GetDelegateScorecard(address, now),
+		}
+		if query.matches(listing, d.Names.NameOfAddress(address)) {
+			listings = append(listings, listing)
+		}
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		vi, vj := query.sortValue(listings[i]), query.sortValue(listings[j])
+		if vi != vj {
+			if query.Ascending {
+				return vi < vj
+			}
+			return vi > vj
+		}
+		return listings[i].Scorecard.Address < listings[j].Scorecard.Address
+	})
+	return listings
+}
+
+// GetDelegateListing returns a delegate's statement with their scorecard
+func (d *DAO) GetDelegateListing(delegate string) (*DelegateListing, bool) {
+	statement, exists := d.Delegates.Get(delegate)
+	if !exists {
+		return nil, false
+	}
+	return &DelegateListing{
+		Statement: statement,
+		Scorecard: d.AnalyticsSystem.GetDelegateScorecard(delegate, time.Now().Unix()),
+	}, true
+}
@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeFocusAreas(t *testing.T) {
+	areas, err := NormalizeFocusAreas([]string{" Treasury ", "DeFi", "treasury", "public goods"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"treasury", "defi", "public goods"}, areas)
+
+	for _, invalid := range [][]string{
+		{""},
+		{"treasury/ops"},
+		{"a", "b", "c", "d", "e", "f"},
+		{"abcdefghijklmnopqrstuvwxyz0123456"},
+	} {
+		_, err := NormalizeFocusAreas(invalid)
+		require.Error(t, err)
+		assert.Equal(t, ErrInvalidDelegate, err.(*DAOError).Code)
+	}
+}
+
+func TestDelegateRegistry_Search(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	carol := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   1000,
+		carol.String(): 3000,
+	}))
+
+	publish := func(delegate crypto.PublicKey, platform string, fee uint64, areas ...string) error {
+		return dao.ProcessDAOTransaction(&DelegateStatementTx{
+			Fee:         10,
+			Platform:    platform,
+			FocusAreas:  areas,
+			DelegateFee: fee,
+		}, delegate, types.Hash{byte(len(platform))})
+	}
+
+	// Statements are validated
+	assert.Error(t, publish(alice, "", 0))
+	assert.Error(t, publish(alice, "Fee too high", 10001))
+	assert.Error(t, publish(alice, "Bad area", 0, "defi!"))
+
+	require.NoError(t, publish(alice, "Fund audits before launches", 500, "Security", "treasury"))
+	require.NoError(t, publish(bob, "Grow the grants program", 0, "grants"))
+	assert.Equal(t, uint64(990), dao.GetTokenBalance(alice))
+
+	statement, exists := dao.Delegates.Get(alice.String())
+	require.True(t, exists)
+	assert.Equal(t, []string{"security", "treasury"}, statement.FocusAreas)
+
+	// Carol delegates to bob, who then leads by delegated power
+	require.NoError(t, dao.ProcessDAOTransaction(&DelegationTx{Fee: 10, Delegate: bob, Duration: 86400}, carol, types.Hash{0xD0}))
+	listings := dao.SearchDelegates(DelegateQuery{})
+	require.Len(t, listings, 2)
+	assert.Equal(t, bob.String(), listings[0].Scorecard.Address)
+	assert.Equal(t, uint64(1), listings[0].Scorecard.Delegators)
+
+	listings = dao.SearchDelegates(DelegateQuery{SortBy: DelegateSortFee})
+	assert.Equal(t, alice.String(), listings[0].Scorecard.Address)
+	listings = dao.SearchDelegates(DelegateQuery{SortBy: DelegateSortFee, Ascending: true})
+	assert.Equal(t, bob.String(), listings[0].Scorecard.Address)
+
+	// Filters by focus area and text, including the registered name
+	listings = dao.SearchDelegates(DelegateQuery{Focus: "Treasury"})
+	require.Len(t, listings, 1)
+	assert.Equal(t, alice.String(), listings[0].Scorecard.Address)
+	assert.Len(t, dao.SearchDelegates(DelegateQuery{Text: "GRANTS"}), 1)
+	assert.Empty(t, dao.SearchDelegates(DelegateQuery{Text: "bobby"}))
+	require.NoError(t, dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "bobby"}, bob, types.Hash{0xE0}))
+	assert.Len(t, dao.SearchDelegates(DelegateQuery{Text: "bobby"}), 1)
+
+	// Updating keeps the publication time and withdrawing removes the listing
+	require.NoError(t, publish(alice, "Fund audits before every launch", 250, "security"))
+	listing, exists := dao.GetDelegateListing(alice.String())
+	require.True(t, exists)
+	assert.Equal(t, uint64(250), listing.Statement.Fee)
+	assert.Equal(t, statement.PublishedAt, listing.Statement.PublishedAt)
+	require.NoError(t, dao.ProcessDAOTransaction(&DelegateStatementTx{Fee: 10, Withdraw: true}, alice, types.Hash{0xF0}))
+	_, exists = dao.GetDelegateListing(alice.String())
+	assert.False(t, exists)
+	assert.Error(t, dao.ProcessDAOTransaction(&DelegateStatementTx{Fee: 10, Withdraw: true}, alice, types.Hash{0xF1}))
+}
@@ -48,8 +48,8 @@ func TestDelegationCreation(t *testing.T) {
 	}
 
 	// Verify fee was deducted
-	if dao.TokenState.Balances[delegator.String()] != 1900 {
-		t.Errorf("Expected delegator balance 1900, got %d", dao.TokenState.Balances[delegator.String()])
+	if dao.TokenState.Balances.Get(delegator.String()) != 1900 {
+		t.Errorf("Expected delegator balance 1900, got %d", dao.TokenState.Balances.Get(delegator.String()))
 	}
 }
 
@@ -110,8 +110,8 @@ func TestDelegationRevocation(t *testing.T) {
 
 	// Verify revocation fee was deducted
 	expectedBalance := 2000 - 100 - 50 // initial - creation fee - revocation fee
-	if dao.TokenState.Balances[delegator.String()] != uint64(expectedBalance) {
-		t.Errorf("Expected delegator balance %d, got %d", expectedBalance, dao.TokenState.Balances[delegator.String()])
+	if dao.TokenState.Balances.Get(delegator.String()) != uint64(expectedBalance) {
+		t.Errorf("Expected delegator balance %d, got %d", expectedBalance, dao.TokenState.Balances.Get(delegator.String()))
 	}
 }
 
@@ -378,6 +378,43 @@ func TestMultipleDelegations(t *testing.T) {
 	}
 }
 
+func TestBulkDelegationLookup(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	delegator1 := crypto.GeneratePrivateKey().PublicKey()
+	delegator2 := crypto.GeneratePrivateKey().PublicKey()
+	delegate := crypto.GeneratePrivateKey().PublicKey()
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+
+	distributions := map[string]uint64{
+		delegator1.String(): 2000,
+		delegator2.String(): 1500,
+		delegate.String():   1000,
+	}
+	dao.InitialTokenDistribution(distributions)
+
+	delegationTx := &DelegationTx{
+		Fee:      100,
+		Delegate: delegate,
+		Duration: 86400,
+	}
+	if err := dao.Processor.ProcessDelegationTx(delegationTx, delegator1); err != nil {
+		t.Fatalf("Failed to create delegation: %v", err)
+	}
+
+	// Bulk lookups agree with the single ones
+	users := []crypto.PublicKey{delegator1, delegate, delegator2, outsider}
+	powers := dao.GetEffectiveVotingPowers(users)
+	if len(powers) != len(users) {
+		t.Fatalf("Expected %d bulk voting powers, got %d", len(users), len(powers))
+	}
+	for _, user := range users {
+		if powers[user.String()] != dao.GetEffectiveVotingPower(user) {
+			t.Errorf("Expected bulk voting power %d, got %d", dao.GetEffectiveVotingPower(user), powers[user.String()])
+		}
+	}
+}
+
 func TestDelegationRevocationValidation(t *testing.T) {
 	dao := NewDAO("GOV", "Governance Token", 18)
 
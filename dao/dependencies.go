@@ -0,0 +1,135 @@
+package dao
+
+import (
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxProposalDependencies is the most proposals a proposal may depend on
+const MaxProposalDependencies = 10
+
+// ProposalDependencies is the graph of proposals that execute only after
+// other proposals have executed. Edges point from a proposal to the
+// proposals it depends on.
+type ProposalDependencies struct {
+	governanceState *GovernanceState
+	dependsOn       map[types.Hash][]types.Hash
+	dependents      map[types.Hash][]types.Hash
+}
+
+// NewProposalDependencies creates an empty dependency graph
+func NewProposalDependencies(governanceState *GovernanceState) *ProposalDependencies {
+	return &ProposalDependencies{
+		governanceState: governanceState,
+		dependsOn:       make(map[types.Hash][]types.Hash),
+		dependents:      make(map[types.Hash][]types.Hash),
+	}
+}
+
+// Check checks the dependencies a new proposal declares. Each must be an
+// existing proposal that can still execute, and none may lead back to the
+// new proposal.
+func (pd *ProposalDependencies) Check(proposalID types.Hash, dependsOn []types.Hash) error {
+	if len(dependsOn) > MaxProposalDependencies {
+		return NewDAOError(ErrInvalidProposal, "proposal has too many dependencies", map[string]interface{}{
+			"max": MaxProposalDependencies,
+		})
+	}
+
+	seen := make(map[types.Hash]bool, len(dependsOn))
+	for _, id := range dependsOn {
+		if seen[id] {
+			return NewDAOError(ErrInvalidProposal, "proposal dependencies must be distinct", nil)
+		}
+		seen[id] = true
+
+		dependency, exists := pd.governanceState.Proposal(id)
+		if !exists {
+			return NewDAOError(ErrProposalNotFound, "dependency not found", map[string]interface{}{
+				"dependency": id.String(),
+			})
+		}
+		if dependency.Status == ProposalStatusRejected || dependency.Status == ProposalStatusCancelled {
+			return NewDAOError(ErrDependencyUnmet, "dependency can no longer execute", map[string]interface{}{
+				"dependency": id.String(),
+			})
+		}
+	}
+
+	if pd.reaches(dependsOn, proposalID) {
+		return NewDAOError(ErrDependencyCycle, "proposal dependencies form a cycle", nil)
+	}
+	return nil
+}
+
+// reaches reports whether target is among the proposals or their
+// transitive dependencies
+func (pd *ProposalDependencies) reaches(from []types.Hash, target types.Hash) bool {
+	visited := make(map[types.Hash]bool)
+	stack := append([]types.Hash(nil), from...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == target {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, pd.dependsOn[id]...)
+	}
+	return false
+}
+
+// Add records the dependencies of a proposal
+func (pd *ProposalDependencies) Add(proposalID types.Hash, dependsOn []types.Hash) {
+	if len(dependsOn) == 0 {
+		return
+	}
+	pd.dependsOn[proposalID] = dependsOn
+	for _, id := range dependsOn {
+		pd.dependents[id] = append(pd.dependents[id], proposalID)
+	}
+}
+
+// DependsOn returns the proposals a proposal depends on
+func (pd *ProposalDependencies) DependsOn(proposalID types.Hash) []types.Hash {
+	return pd.dependsOn[proposalID]
+}
+
+// Dependents returns the proposals that depend on a proposal, ordered by ID
+func (pd *ProposalDependencies) Dependents(proposalID types.Hash) []types.Hash {
+	dependents := append([]types.Hash(nil), pd.dependents[proposalID]...)
+	sort.Slice(dependents, func(i, j int) bool { return dependents[i].String() < dependents[j].String() })
+	return dependents
+}
+
+// Blocking returns the dependencies of a proposal that have not executed
+func (pd *ProposalDependencies) Blocking(proposalID types.Hash) []types.Hash {
+	var blocking []types.Hash
+	for _, id := range pd.dependsOn[proposalID] {
+		if dependency, exists := pd.governanceState.Proposal(id); !exists || dependency.Status != ProposalStatusExecuted {
+			blocking = append(blocking, id)
+		}
+	}
+	return blocking
+}
+
+// CheckExecutable returns an error unless every dependency of a proposal
+// has executed
+func (pd *ProposalDependencies) CheckExecutable(proposalID types.Hash) error {
+	blocking := pd.Blocking(proposalID)
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(blocking))
+	for i, id := range blocking {
+		ids[i] = id.String()
+	}
+	return NewDAOError(ErrDependencyUnmet, "proposal dependencies have not executed", map[string]interface{}{
+		"blocking": ids,
+	})
+}
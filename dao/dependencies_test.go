@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalDependencies_Cycles(t *testing.T) {
+	state := NewGovernanceState()
+	a, b, c := types.Hash{0x0A}, types.Hash{0x0B}, types.Hash{0x0C}
+	for _, id := range []types.Hash{a, b, c} {
+		state.Proposals[id] = &Proposal{ID: id, Status: ProposalStatusActive}
+	}
+
+	pd := NewProposalDependencies(state)
+	require.NoError(t, pd.Check(b, []types.Hash{a}))
+	pd.Add(b, []types.Hash{a})
+	require.NoError(t, pd.Check(c, []types.Hash{b}))
+	pd.Add(c, []types.Hash{b})
+
+	// a -> c -> b -> a
+	err := pd.Check(a, []types.Hash{c})
+	assert.Equal(t, ErrDependencyCycle, err.(*DAOError).Code)
+	err = pd.Check(a, []types.Hash{a})
+	assert.Equal(t, ErrDependencyCycle, err.(*DAOError).Code)
+
+	assert.Error(t, pd.Check(types.Hash{0x0D}, []types.Hash{a, a}))
+	assert.Error(t, pd.Check(types.Hash{0x0D}, []types.Hash{{0xFF}}))
+	state.Proposals[a].Status = ProposalStatusRejected
+	err = pd.Check(types.Hash{0x0D}, []types.Hash{a})
+	assert.Equal(t, ErrDependencyUnmet, err.(*DAOError).Code)
+}
+
+func TestProposalDependencies_Sequencing(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	now := time.Now().Unix()
+	propose := func(id types.Hash, dependsOn ...types.Hash) *Proposal {
+		require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+			Fee:          100,
+			Title:        "Step",
+			Description:  "One step of a rollout",
+			ProposalType: ProposalTypeGeneral,
+			VotingType:   VotingTypeSimple,
+			StartTime:    now,
+			EndTime:      now + 86400,
+			Threshold:    5100,
+			DependsOn:    dependsOn,
+		}, founder, id))
+		proposal, err := dao.GetProposal(id)
+		require.NoError(t, err)
+		return proposal
+	}
+
+	first := propose(types.Hash{0xB0})
+	second := propose(types.Hash{0xB1}, first.ID)
+	third := propose(types.Hash{0xB2}, first.ID, second.ID)
+	assert.Equal(t, []types.Hash{second.ID, third.ID}, dao.Dependencies.Dependents(first.ID))
+
+	// Dependents wait for their dependencies to execute, in order
+	second.Status = ProposalStatusPassed
+	third.Status = ProposalStatusPassed
+	err := dao.ProposalManager.ExecuteProposal(third.ID, founder)
+	assert.Equal(t, ErrDependencyUnmet, err.(*DAOError).Code)
+	assert.Equal(t, []types.Hash{first.ID, second.ID}, dao.Dependencies.Blocking(third.ID))
+
+	first.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProposalManager.ExecuteProposal(first.ID, founder))
+	err = dao.ProposalManager.ExecuteProposal(third.ID, founder)
+	assert.Equal(t, ErrDependencyUnmet, err.(*DAOError).Code)
+	require.NoError(t, dao.ProposalManager.ExecuteProposal(second.ID, founder))
+	require.NoError(t, dao.ProposalManager.ExecuteProposal(third.ID, founder))
+	assert.Empty(t, dao.Dependencies.Blocking(third.ID))
+}
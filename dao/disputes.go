@@ -0,0 +1,480 @@
+package dao
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// DisputeType represents the kind of conflict brought before a juror panel
+type DisputeType byte
+
+const (
+	DisputeTypeGrant            DisputeType = 0x01 // Claimant asks the treasury to pay a disputed grant
+	DisputeTypeClawback         DisputeType = 0x02 // Claimant asks to return funds held by the respondent to the treasury
+	DisputeTypeModerationAppeal DisputeType = 0x03 // Claimant appeals the cancellation or rejection of a proposal
+)
+
+// DisputeRuling represents the outcome a juror votes for
+type DisputeRuling byte
+
+const (
+	DisputeRulingNone       DisputeRuling = 0x00
+	DisputeRulingClaimant   DisputeRuling = 0x01
+	DisputeRulingRespondent DisputeRuling = 0x02
+)
+
+// DisputePhase represents the stage a dispute is in
+type DisputePhase string
+
+const (
+	DisputePhaseEvidence DisputePhase = "evidence"
+	DisputePhaseCommit   DisputePhase = "commit"
+	DisputePhaseReveal   DisputePhase = "reveal"
+	DisputePhaseRuling   DisputePhase = "awaiting_ruling"
+	DisputePhaseResolved DisputePhase = "resolved"
+)
+
+// DisputeEvidence is a piece of evidence stored on IPFS
+type DisputeEvidence struct {
+	Submitter   crypto.PublicKey
+	Hash        types.Hash // IPFS hash of the evidence document
+	Description string
+	SubmittedAt int64
+}
+
+// Dispute is a conflict escalated to a panel of staked jurors
+type Dispute struct {
+	ID               types.Hash
+	Type             DisputeType
+	Claimant         crypto.PublicKey
+	Respondent       crypto.PublicKey
+	Subject          types.Hash // Proposal or treasury transaction the dispute is about
+	Amount           uint64
+	Description      string
+	Bond             uint64
+	Evidence         []*DisputeEvidence
+	Jurors           []crypto.PublicKey
+	Commitments      map[string]types.Hash
+	Reveals          map[string]DisputeRuling
+	OpenedAt         int64
+	EvidenceDeadline int64
+	CommitDeadline   int64
+	RevealDeadline   int64
+	Resolved         bool
+	Ruling           DisputeRuling
+	ResolvedAt       int64
+	Executed         uint64 // Amount actually moved when the ruling was enforced
+}
+
+// Phase returns the stage of the dispute at the given time
+func (d *Dispute) Phase(now int64) DisputePhase {
+	switch {
+	case d.Resolved:
+		return DisputePhaseResolved
+	case now < d.EvidenceDeadline:
+		return DisputePhaseEvidence
+	case now < d.CommitDeadline:
+		return DisputePhaseCommit
+	case now < d.RevealDeadline:
+		return DisputePhaseReveal
+	default:
+		return DisputePhaseRuling
+	}
+}
+
+// IsJuror reports whether an address sits on the dispute's panel
+func (d *Dispute) IsJuror(address crypto.PublicKey) bool {
+	addressStr := address.String()
+	for _, juror := range d.Jurors {
+		if juror.String() == addressStr {
+			return true
+		}
+	}
+	return false
+}
+
+// isParty reports whether an address is the claimant or the respondent
+func (d *Dispute) isParty(address crypto.PublicKey) bool {
+	addressStr := address.String()
+	return d.Claimant.String() == addressStr || (len(d.Respondent) > 0 && d.Respondent.String() == addressStr)
+}
+
+// DisputeCommitment returns the hash a juror commits to before revealing a ruling
+func DisputeCommitment(disputeID types.Hash, juror crypto.PublicKey, ruling DisputeRuling, salt types.Hash) types.Hash {
+	h := sha256.New()
+	h.Write(disputeID[:])
+	h.Write(juror)
+	h.Write([]byte{byte(ruling)})
+	h.Write(salt[:])
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// DisputeManager runs disputes from filing to enforced ruling
+type DisputeManager struct {
+	governanceState   *GovernanceState
+	tokenState        *GovernanceToken
+	tokenomicsManager *TokenomicsManager
+	parameterManager  *ParameterManager
+	disputes          map[types.Hash]*Dispute
+}
+
+// NewDisputeManager creates a new dispute manager
+func NewDisputeManager(governanceState *GovernanceState, tokenState *GovernanceToken, tokenomicsManager *TokenomicsManager, parameterManager *ParameterManager) *DisputeManager {
+	return &DisputeManager{
+		governanceState:   governanceState,
+		tokenState:        tokenState,
+		tokenomicsManager: tokenomicsManager,
+		parameterManager:  parameterManager,
+		disputes:          make(map[types.Hash]*Dispute),
+	}
+}
+
+// ProcessDisputeTx opens a dispute, escrows the claimant's bond and draws the juror panel
+func (dm *DisputeManager) ProcessDisputeTx(tx *DisputeTx, claimant crypto.PublicKey, txHash types.Hash) error {
+	if _, exists := dm.disputes[txHash]; exists {
+		return NewDAOError(ErrInvalidProposal, "dispute already exists", nil)
+	}
+
+	config := dm.parameterManager.GetParameterConfig()
+	claimantStr := claimant.String()
+
+	if dm.tokenState.Balances.Get(claimantStr) < uint64(tx.Fee)+config.DisputeBond {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for dispute fee and bond", nil)
+	}
+
+	switch tx.DisputeType {
+	case DisputeTypeClawback:
+		if tx.Amount == 0 {
+			return NewDAOError(ErrInvalidProposal, "clawback amount must be positive", nil)
+		}
+	case DisputeTypeModerationAppeal:
+		proposal, exists := dm.governanceState.Proposal(tx.Subject)
+		if !exists {
+			return ErrProposalNotFoundError
+		}
+		if proposal.Status != ProposalStatusCancelled && proposal.Status != ProposalStatusRejected {
+			return NewDAOError(ErrInvalidProposal, "only cancelled or rejected proposals can be appealed", nil)
+		}
+	}
+
+	jurors, err := dm.drawJurors(txHash, claimant, tx.Respondent, config.DisputeJurorCount, config.DisputeMinJurorStake)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	dispute := &Dispute{
+		ID:               txHash,
+		Type:             tx.DisputeType,
+		Claimant:         claimant,
+		Respondent:       tx.Respondent,
+		Subject:          tx.Subject,
+		Amount:           tx.Amount,
+		Description:      tx.Description,
+		Bond:             config.DisputeBond,
+		Evidence:         make([]*DisputeEvidence, 0),
+		Jurors:           jurors,
+		Commitments:      make(map[string]types.Hash),
+		Reveals:          make(map[string]DisputeRuling),
+		OpenedAt:         now,
+		EvidenceDeadline: now + config.DisputePhasePeriod,
+		CommitDeadline:   now + 2*config.DisputePhasePeriod,
+		RevealDeadline:   now + 3*config.DisputePhasePeriod,
+	}
+
+	if !tx.EvidenceHash.IsZero() {
+		dispute.Evidence = append(dispute.Evidence, &DisputeEvidence{
+			Submitter:   claimant,
+			Hash:        tx.EvidenceHash,
+			Description: "opening statement",
+			SubmittedAt: now,
+		})
+	}
+
+	// Deduct fee and escrow bond
+	dm.tokenState.Balances.Sub(claimantStr, uint64(tx.Fee)+dispute.Bond)
+	dm.disputes[dispute.ID] = dispute
+
+	return nil
+}
+
+// drawJurors deterministically draws a panel from eligible stakers, seeded by
+// the dispute ID so every node draws the same panel
+func (dm *DisputeManager) drawJurors(seed types.Hash, claimant, respondent crypto.PublicKey, count, minStake uint64) ([]crypto.PublicKey, error) {
+	stakes := make(map[string]uint64)
+	addresses := make(map[string]crypto.PublicKey)
+	for _, pool := range dm.tokenomicsManager.ListAllStakingPools() {
+		for addressStr, stakerInfo := range pool.Stakers {
+			stakes[addressStr] += stakerInfo.StakedAmount
+			addresses[addressStr] = stakerInfo.Address
+		}
+	}
+
+	type candidate struct {
+		address crypto.PublicKey
+		score   []byte
+	}
+
+	candidates := make([]candidate, 0)
+	for addressStr, stake := range stakes {
+		if stake == 0 || stake < minStake || addressStr == claimant.String() {
+			continue
+		}
+		if len(respondent) > 0 && addressStr == respondent.String() {
+			continue
+		}
+
+		score := sha256.Sum256(append(seed[:], addresses[addressStr]...))
+		candidates = append(candidates, candidate{address: addresses[addressStr], score: score[:]})
+	}
+
+	if uint64(len(candidates)) < count {
+		return nil, NewDAOError(ErrInsufficientJurors, "not enough eligible jurors", map[string]interface{}{
+			"eligible": len(candidates),
+			"required": count,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return bytes.Compare(candidates[i].score, candidates[j].score) < 0
+	})
+
+	jurors := make([]crypto.PublicKey, count)
+	for i := range jurors {
+		jurors[i] = candidates[i].address
+	}
+
+	return jurors, nil
+}
+
+// ProcessDisputeEvidenceTx adds evidence from one of the parties
+func (dm *DisputeManager) ProcessDisputeEvidenceTx(tx *DisputeEvidenceTx, submitter crypto.PublicKey) error {
+	dispute, exists := dm.disputes[tx.DisputeID]
+	if !exists {
+		return ErrDisputeNotFoundError
+	}
+
+	if !dispute.isParty(submitter) {
+		return NewDAOError(ErrUnauthorized, "only dispute parties may submit evidence", nil)
+	}
+
+	now := time.Now().Unix()
+	if dispute.Phase(now) != DisputePhaseEvidence {
+		return NewDAOError(ErrDisputePhase, "evidence period has ended", nil)
+	}
+
+	dispute.Evidence = append(dispute.Evidence, &DisputeEvidence{
+		Submitter:   submitter,
+		Hash:        tx.EvidenceHash,
+		Description: tx.Description,
+		SubmittedAt: now,
+	})
+
+	// Deduct fee
+	dm.tokenState.Balances.Sub(submitter.String(), uint64(tx.Fee))
+
+	return nil
+}
+
+// ProcessJurorCommitTx records a juror's hidden ruling
+func (dm *DisputeManager) ProcessJurorCommitTx(tx *JurorCommitTx, juror crypto.PublicKey) error {
+	dispute, exists := dm.disputes[tx.DisputeID]
+	if !exists {
+		return ErrDisputeNotFoundError
+	}
+
+	if !dispute.IsJuror(juror) {
+		return NewDAOError(ErrUnauthorized, "not a juror on this dispute", nil)
+	}
+
+	if dispute.Phase(time.Now().Unix()) != DisputePhaseCommit {
+		return NewDAOError(ErrDisputePhase, "dispute is not in the commit phase", nil)
+	}
+
+	// A juror may replace a commitment until the commit phase ends
+	dispute.Commitments[juror.String()] = tx.Commitment
+
+	// Deduct fee
+	dm.tokenState.Balances.Sub(juror.String(), uint64(tx.Fee))
+
+	return nil
+}
+
+// ProcessJurorRevealTx opens a juror's commitment and resolves the dispute
+// once every juror has revealed
+func (dm *DisputeManager) ProcessJurorRevealTx(tx *JurorRevealTx, juror crypto.PublicKey) error {
+	dispute, exists := dm.disputes[tx.DisputeID]
+	if !exists {
+		return ErrDisputeNotFoundError
+	}
+
+	jurorStr := juror.String()
+	commitment, committed := dispute.Commitments[jurorStr]
+	if !dispute.IsJuror(juror) || !committed {
+		return NewDAOError(ErrUnauthorized, "no commitment from this juror", nil)
+	}
+
+	now := time.Now().Unix()
+	if dispute.Phase(now) != DisputePhaseReveal {
+		return NewDAOError(ErrDisputePhase, "dispute is not in the reveal phase", nil)
+	}
+
+	if _, revealed := dispute.Reveals[jurorStr]; revealed {
+		return NewDAOError(ErrDuplicateVote, "ruling already revealed", nil)
+	}
+
+	if DisputeCommitment(dispute.ID, juror, tx.Ruling, tx.Salt) != commitment {
+		return NewDAOError(ErrInvalidSignature, "reveal does not match commitment", nil)
+	}
+
+	dispute.Reveals[jurorStr] = tx.Ruling
+
+	// Deduct fee
+	dm.tokenState.Balances.Sub(jurorStr, uint64(tx.Fee))
+
+	if len(dispute.Reveals) == len(dispute.Jurors) {
+		dm.resolve(dispute, now)
+	}
+
+	return nil
+}
+
+// ResolveDueDisputes rules on every dispute whose reveal phase has ended and
+// returns the disputes resolved by this call
+func (dm *DisputeManager) ResolveDueDisputes(now int64) []*Dispute {
+	resolved := make([]*Dispute, 0)
+	for _, dispute := range dm.ListDisputes() {
+		if dispute.Phase(now) == DisputePhaseRuling {
+			dm.resolve(dispute, now)
+			resolved = append(resolved, dispute)
+		}
+	}
+	return resolved
+}
+
+// resolve tallies revealed rulings and enforces the outcome. Ties and
+// disputes without reveals leave the status quo in place.
+func (dm *DisputeManager) resolve(dispute *Dispute, now int64) {
+	forClaimant, forRespondent := 0, 0
+	for _, ruling := range dispute.Reveals {
+		switch ruling {
+		case DisputeRulingClaimant:
+			forClaimant++
+		case DisputeRulingRespondent:
+			forRespondent++
+		}
+	}
+
+	dispute.Ruling = DisputeRulingRespondent
+	if forClaimant > forRespondent {
+		dispute.Ruling = DisputeRulingClaimant
+	}
+
+	dispute.Resolved = true
+	dispute.ResolvedAt = now
+
+	if dispute.Ruling == DisputeRulingClaimant {
+		dm.tokenState.Balances.Add(dispute.Claimant.String(), dispute.Bond)
+		dispute.Executed = dm.enforce(dispute, now)
+	} else {
+		dm.forfeitBond(dispute)
+	}
+}
+
+// enforce executes a ruling in the claimant's favour and returns the amount moved
+func (dm *DisputeManager) enforce(dispute *Dispute, now int64) uint64 {
+	treasury := dm.governanceState.Treasury
+
+	switch dispute.Type {
+	case DisputeTypeGrant:
+		amount := dispute.Amount
+		if amount > treasury.Balance {
+			amount = treasury.Balance
+		}
+		treasury.Balance -= amount
+		dm.tokenState.Balances.Add(dispute.Claimant.String(), amount)
+		return amount
+
+	case DisputeTypeClawback:
+		respondentStr := dispute.Respondent.String()
+		amount := dispute.Amount
+		if amount > dm.tokenState.Balances.Get(respondentStr) {
+			amount = dm.tokenState.Balances.Get(respondentStr)
+		}
+		dm.tokenState.Balances.Sub(respondentStr, amount)
+		treasury.recordInflow(amount, InflowSourceClawback, now)
+		return amount
+
+	case DisputeTypeModerationAppeal:
+		if proposal, exists := dm.governanceState.residentProposal(dispute.Subject); exists {
+			proposal.Status = ProposalStatusActive
+			if proposal.EndTime < now+dm.parameterManager.GetParameterConfig().VotingPeriod {
+				proposal.EndTime = now + dm.parameterManager.GetParameterConfig().VotingPeriod
+			}
+		}
+	}
+
+	return 0
+}
+
+// forfeitBond splits a losing claimant's bond between jurors who revealed
+// with the majority; the remainder goes to the treasury
+func (dm *DisputeManager) forfeitBond(dispute *Dispute) {
+	majority := make([]crypto.PublicKey, 0)
+	for _, juror := range dispute.Jurors {
+		if dispute.Reveals[juror.String()] == dispute.Ruling {
+			majority = append(majority, juror)
+		}
+	}
+
+	paid := uint64(0)
+	if len(majority) > 0 {
+		share := dispute.Bond / uint64(len(majority))
+		for _, juror := range majority {
+			dm.tokenState.Balances.Add(juror.String(), share)
+			paid += share
+		}
+	}
+
+	remainder := dispute.Bond - paid
+	dm.governanceState.Treasury.recordInflow(remainder, InflowSourceForfeitedBond, dispute.ResolvedAt)
+}
+
+// GetDispute returns a dispute by ID
+func (dm *DisputeManager) GetDispute(disputeID types.Hash) (*Dispute, bool) {
+	dispute, exists := dm.disputes[disputeID]
+	return dispute, exists
+}
+
+// ListDisputes returns all disputes, oldest first
+func (dm *DisputeManager) ListDisputes() []*Dispute {
+	disputes := make([]*Dispute, 0, len(dm.disputes))
+	for _, dispute := range dm.disputes {
+		disputes = append(disputes, dispute)
+	}
+
+	sort.Slice(disputes, func(i, j int) bool {
+		if disputes[i].OpenedAt != disputes[j].OpenedAt {
+			return disputes[i].OpenedAt < disputes[j].OpenedAt
+		}
+		return disputes[i].ID.String() < disputes[j].ID.String()
+	})
+
+	return disputes
+}
+
+// GetJurorDisputes returns the disputes an address sits on as juror
+func (dm *DisputeManager) GetJurorDisputes(juror crypto.PublicKey) []*Dispute {
+	disputes := make([]*Dispute, 0)
+	for _, dispute := range dm.ListDisputes() {
+		if dispute.IsJuror(juror) {
+			disputes = append(disputes, dispute)
+		}
+	}
+	return disputes
+}
@@ -0,0 +1,213 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDisputeDAO(t *testing.T, stakers int) (*DAO, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	claimant := crypto.GeneratePrivateKey().PublicKey()
+	respondent := crypto.GeneratePrivateKey().PublicKey()
+	distribution := map[string]uint64{
+		claimant.String():   10000,
+		respondent.String(): 10000,
+	}
+
+	keys := make([]crypto.PublicKey, stakers)
+	for i := range keys {
+		keys[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[keys[i].String()] = 5000
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	require.NoError(t, dao.CreateStakingPool("jurors", "Juror Pool", 0, 100, 0))
+	for _, key := range keys {
+		require.NoError(t, dao.StakeTokens("jurors", key, 2000, 0))
+	}
+
+	// Parties staking must not end up on their own panel
+	require.NoError(t, dao.StakeTokens("jurors", respondent, 2000, 0))
+
+	dao.AddTreasuryFunds(10000)
+
+	return dao, claimant, respondent
+}
+
+// advanceDispute moves a dispute into the given phase
+func advanceDispute(dispute *Dispute, phase DisputePhase) {
+	now := time.Now().Unix()
+	switch phase {
+	case DisputePhaseCommit:
+		dispute.EvidenceDeadline = now - 1
+	case DisputePhaseReveal:
+		dispute.EvidenceDeadline = now - 2
+		dispute.CommitDeadline = now - 1
+	case DisputePhaseRuling:
+		dispute.EvidenceDeadline = now - 3
+		dispute.CommitDeadline = now - 2
+		dispute.RevealDeadline = now - 1
+	}
+}
+
+func commitAndReveal(t *testing.T, dao *DAO, dispute *Dispute, rulings []DisputeRuling) {
+	advanceDispute(dispute, DisputePhaseCommit)
+	for i, ruling := range rulings {
+		juror := dispute.Jurors[i]
+		salt := types.Hash{byte(i + 1)}
+		tx := &JurorCommitTx{Fee: 10, DisputeID: dispute.ID, Commitment: DisputeCommitment(dispute.ID, juror, ruling, salt)}
+		require.NoError(t, dao.ProcessDAOTransaction(tx, juror, types.Hash{0xC0, byte(i)}))
+	}
+
+	advanceDispute(dispute, DisputePhaseReveal)
+	for i, ruling := range rulings {
+		juror := dispute.Jurors[i]
+		tx := &JurorRevealTx{Fee: 10, DisputeID: dispute.ID, Ruling: ruling, Salt: types.Hash{byte(i + 1)}}
+		require.NoError(t, dao.ProcessDAOTransaction(tx, juror, types.Hash{0xD0, byte(i)}))
+	}
+}
+
+func TestDispute_OpenDrawsPanelAndEscrowsBond(t *testing.T) {
+	dao, claimant, respondent := setupDisputeDAO(t, 7)
+	config := dao.GetParameterConfig()
+
+	disputeID := types.Hash{0xAA}
+	tx := &DisputeTx{Fee: 100, DisputeType: DisputeTypeGrant, Respondent: respondent, Amount: 3000, Description: "grant milestone was delivered"}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, claimant, disputeID))
+
+	dispute, exists := dao.GetDispute(disputeID)
+	require.True(t, exists)
+	require.Len(t, dispute.Jurors, int(config.DisputeJurorCount))
+	assert.False(t, dispute.IsJuror(claimant))
+	assert.False(t, dispute.IsJuror(respondent))
+	assert.Equal(t, DisputePhaseEvidence, dispute.Phase(time.Now().Unix()))
+	assert.Equal(t, uint64(10000-100)-config.DisputeBond, dao.GetTokenBalance(claimant))
+
+	// The draw is deterministic for a given dispute ID
+	again, err := dao.DisputeManager.drawJurors(disputeID, claimant, respondent, config.DisputeJurorCount, config.DisputeMinJurorStake)
+	require.NoError(t, err)
+	assert.Equal(t, dispute.Jurors, again)
+}
+
+func TestDispute_NotEnoughJurors(t *testing.T) {
+	dao, claimant, respondent := setupDisputeDAO(t, 3)
+
+	tx := &DisputeTx{Fee: 100, DisputeType: DisputeTypeGrant, Respondent: respondent, Amount: 3000, Description: "grant"}
+	err := dao.ProcessDAOTransaction(tx, claimant, types.Hash{0xAB})
+	require.Error(t, err)
+	assert.Equal(t, ErrInsufficientJurors, err.(*DAOError).Code)
+}
+
+func TestDispute_EvidenceAndPhases(t *testing.T) {
+	dao, claimant, respondent := setupDisputeDAO(t, 5)
+	disputeID := types.Hash{0xAC}
+	require.NoError(t, dao.ProcessDAOTransaction(&DisputeTx{
+		Fee: 100, DisputeType: DisputeTypeGrant, Respondent: respondent, Amount: 100, Description: "grant",
+	}, claimant, disputeID))
+	dispute, _ := dao.GetDispute(disputeID)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&DisputeEvidenceTx{Fee: 10, DisputeID: disputeID, EvidenceHash: types.Hash{1}, Description: "invoice"}, respondent, types.Hash{0xE1}))
+	assert.Len(t, dispute.Evidence, 1)
+
+	outsider := dispute.Jurors[0]
+	err := dao.ProcessDAOTransaction(&DisputeEvidenceTx{Fee: 10, DisputeID: disputeID, EvidenceHash: types.Hash{2}}, outsider, types.Hash{0xE2})
+	require.Error(t, err)
+
+	// Commitments are only accepted during the commit phase
+	err = dao.ProcessDAOTransaction(&JurorCommitTx{Fee: 10, DisputeID: disputeID, Commitment: types.Hash{3}}, outsider, types.Hash{0xE3})
+	require.Error(t, err)
+	assert.Equal(t, ErrDisputePhase, err.(*DAOError).Code)
+
+	advanceDispute(dispute, DisputePhaseCommit)
+	err = dao.ProcessDAOTransaction(&DisputeEvidenceTx{Fee: 10, DisputeID: disputeID, EvidenceHash: types.Hash{4}}, claimant, types.Hash{0xE4})
+	require.Error(t, err)
+
+	// Reveals must match the commitment
+	juror := dispute.Jurors[0]
+	commitment := DisputeCommitment(disputeID, juror, DisputeRulingClaimant, types.Hash{9})
+	require.NoError(t, dao.ProcessDAOTransaction(&JurorCommitTx{Fee: 10, DisputeID: disputeID, Commitment: commitment}, juror, types.Hash{0xE5}))
+
+	advanceDispute(dispute, DisputePhaseReveal)
+	err = dao.ProcessDAOTransaction(&JurorRevealTx{Fee: 10, DisputeID: disputeID, Ruling: DisputeRulingRespondent, Salt: types.Hash{9}}, juror, types.Hash{0xE6})
+	require.Error(t, err)
+	require.NoError(t, dao.ProcessDAOTransaction(&JurorRevealTx{Fee: 10, DisputeID: disputeID, Ruling: DisputeRulingClaimant, Salt: types.Hash{9}}, juror, types.Hash{0xE7}))
+}
+
+func TestDispute_GrantRulingPaysClaimant(t *testing.T) {
+	dao, claimant, respondent := setupDisputeDAO(t, 5)
+	disputeID := types.Hash{0xAD}
+	require.NoError(t, dao.ProcessDAOTransaction(&DisputeTx{
+		Fee: 100, DisputeType: DisputeTypeGrant, Respondent: respondent, Amount: 3000, Description: "grant",
+	}, claimant, disputeID))
+	dispute, _ := dao.GetDispute(disputeID)
+	balanceBefore := dao.GetTokenBalance(claimant)
+
+	commitAndReveal(t, dao, dispute, []DisputeRuling{
+		DisputeRulingClaimant, DisputeRulingClaimant, DisputeRulingRespondent, DisputeRulingClaimant, DisputeRulingRespondent,
+	})
+
+	// Resolved as soon as every juror revealed
+	require.True(t, dispute.Resolved)
+	assert.Equal(t, DisputeRulingClaimant, dispute.Ruling)
+	assert.Equal(t, uint64(3000), dispute.Executed)
+	assert.Equal(t, balanceBefore+dispute.Bond+3000, dao.GetTokenBalance(claimant))
+	assert.Equal(t, uint64(7000), dao.GetTreasuryBalance())
+}
+
+func TestDispute_LostClawbackForfeitsBond(t *testing.T) {
+	dao, claimant, respondent := setupDisputeDAO(t, 5)
+	disputeID := types.Hash{0xAE}
+	require.NoError(t, dao.ProcessDAOTransaction(&DisputeTx{
+		Fee: 100, DisputeType: DisputeTypeClawback, Respondent: respondent, Amount: 1000, Description: "misused funds",
+	}, claimant, disputeID))
+	dispute, _ := dao.GetDispute(disputeID)
+	respondentBalance := dao.GetTokenBalance(respondent)
+	treasuryBefore := dao.GetTreasuryBalance()
+
+	// Only one juror reveals before the deadline
+	commitAndReveal(t, dao, dispute, []DisputeRuling{DisputeRulingRespondent})
+	jurorBalance := dao.GetTokenBalance(dispute.Jurors[0])
+	require.False(t, dispute.Resolved)
+
+	advanceDispute(dispute, DisputePhaseRuling)
+	resolved := dao.ResolveDueDisputes()
+	require.Len(t, resolved, 1)
+
+	assert.Equal(t, DisputeRulingRespondent, dispute.Ruling)
+	assert.Equal(t, respondentBalance, dao.GetTokenBalance(respondent))
+	assert.Equal(t, jurorBalance+dispute.Bond, dao.GetTokenBalance(dispute.Jurors[0]))
+	assert.Equal(t, treasuryBefore, dao.GetTreasuryBalance())
+
+	// Resolved disputes are not resolved again
+	assert.Empty(t, dao.ResolveDueDisputes())
+}
+
+func TestDispute_ModerationAppealReinstatesProposal(t *testing.T) {
+	dao, claimant, _ := setupDisputeDAO(t, 5)
+
+	proposalID := types.Hash{0xBB}
+	now := time.Now().Unix()
+	dao.GovernanceState.Proposals[proposalID] = &Proposal{
+		ID: proposalID, Creator: claimant, Title: "Moderated", StartTime: now - 100, EndTime: now - 10, Status: ProposalStatusCancelled,
+	}
+
+	disputeID := types.Hash{0xAF}
+	require.NoError(t, dao.ProcessDAOTransaction(&DisputeTx{
+		Fee: 100, DisputeType: DisputeTypeModerationAppeal, Subject: proposalID, Description: "wrongly cancelled",
+	}, claimant, disputeID))
+	dispute, _ := dao.GetDispute(disputeID)
+
+	commitAndReveal(t, dao, dispute, []DisputeRuling{
+		DisputeRulingClaimant, DisputeRulingClaimant, DisputeRulingClaimant, DisputeRulingRespondent, DisputeRulingRespondent,
+	})
+
+	proposal := dao.GovernanceState.Proposals[proposalID]
+	assert.Equal(t, ProposalStatusActive, proposal.Status)
+	assert.Greater(t, proposal.EndTime, now)
+}
@@ -0,0 +1,202 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// EmergencySpend is the treasury payment of an emergency proposal. Its ID is
+// the ID of the proposal voting on it.
+type EmergencySpend struct {
+	ID         types.Hash
+	Proposer   crypto.PublicKey
+	Guardian   crypto.PublicKey
+	Recipient  crypto.PublicKey
+	Amount     uint64
+	Incident   string
+	CreatedAt  int64
+	ExecutedAt int64
+}
+
+// Digest returns the hash of the spend the guardian co-signs
+func (tx *EmergencySpendTx) Digest() types.Hash {
+	h := sha256.New()
+	h.Write([]byte("bockchain-emergency-spend"))
+	binary.Write(h, binary.BigEndian, uint32(len(tx.Title)))
+	h.Write([]byte(tx.Title))
+	binary.Write(h, binary.BigEndian, uint32(len(tx.Incident)))
+	h.Write([]byte(tx.Incident))
+	h.Write(tx.Recipient)
+	binary.Write(h, binary.BigEndian, tx.Amount)
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// Sign co-signs the spend with a guardian key
+func (tx *EmergencySpendTx) Sign(key crypto.PrivateKey) error {
+	digest := tx.Digest()
+	sig, err := key.Sign(digest.ToSlice())
+	if err != nil {
+		return err
+	}
+	tx.Guardian = key.PublicKey()
+	tx.GuardianSignature = *sig
+	return nil
+}
+
+// VerifySignature reports whether the spend is signed by its guardian
+func (tx *EmergencySpendTx) VerifySignature() bool {
+	if len(tx.Guardian) == 0 || tx.GuardianSignature.R == nil || tx.GuardianSignature.S == nil {
+		return false
+	}
+	digest := tx.Digest()
+	return tx.GuardianSignature.Verify(tx.Guardian, digest.ToSlice())
+}
+
+// EmergencyManager runs emergency spends. They skip the normal voting period
+// and are voted on from submission over the emergency voting period, but
+// need the emergency quorum and threshold and the co-signature of a
+// guardian, a member allowed to pause the DAO in an emergency.
+type EmergencyManager struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	securityManager  *SecurityManager
+	spends           map[types.Hash]*EmergencySpend
+}
+
+// NewEmergencyManager creates a new emergency spend manager
+func NewEmergencyManager(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager, securityManager *SecurityManager) *EmergencyManager {
+	return &EmergencyManager{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		securityManager:  securityManager,
+		spends:           make(map[types.Hash]*EmergencySpend),
+	}
+}
+
+// ProcessEmergencySpendTx checks the guardian's co-signature and opens the
+// vote on an emergency spend
+func (em *EmergencyManager) ProcessEmergencySpendTx(tx *EmergencySpendTx, proposer crypto.PublicKey, txHash types.Hash) error {
+	if _, exists := em.governanceState.Proposal(txHash); exists {
+		return NewDAOError(ErrInvalidProposal, "proposal already exists", nil)
+	}
+
+	if tx.Guardian.String() == proposer.String() {
+		return NewDAOError(ErrUnauthorized, "guardians cannot co-sign their own emergency spend", nil)
+	}
+	if !em.securityManager.HasPermission(tx.Guardian, PermissionEmergencyPause) {
+		return NewDAOError(ErrUnauthorized, "co-signer is not a guardian", map[string]interface{}{
+			"guardian": tx.Guardian.String(),
+		})
+	}
+	if !tx.VerifySignature() {
+		return NewDAOError(ErrGuardianSignature, "invalid guardian signature", nil)
+	}
+
+	if em.governanceState.Treasury.Balance < tx.Amount {
+		return NewDAOError(ErrTreasuryInsufficient, "insufficient treasury funds for the spend", map[string]interface{}{
+			"balance": em.governanceState.Treasury.Balance,
+			"amount":  tx.Amount,
+		})
+	}
+
+	config := em.parameterManager.GetParameterConfig()
+	now := time.Now().Unix()
+	em.governanceState.Proposals[txHash] = &Proposal{
+		ID:           txHash,
+		Creator:      proposer,
+		Title:        tx.Title,
+		Description:  tx.Description,
+		ProposalType: ProposalTypeEmergency,
+		VotingType:   tx.VotingType,
+		StartTime:    now,
+		EndTime:      now + config.EmergencyVotingPeriod,
+		Status:       ProposalStatusActive,
+		Threshold:    config.EmergencyThreshold,
+		Quorum:       config.EmergencyQuorum,
+		Results:      &VoteResults{},
+	}
+	em.governanceState.ResetVotes(txHash)
+
+	em.tokenState.Balances.Sub(proposer.String(), uint64(tx.Fee))
+	em.spends[txHash] = &EmergencySpend{
+		ID:        txHash,
+		Proposer:  proposer,
+		Guardian:  tx.Guardian,
+		Recipient: tx.Recipient,
+		Amount:    tx.Amount,
+		Incident:  tx.Incident,
+		CreatedAt: now,
+	}
+
+	return nil
+}
+
+// ProcessEmergencyExecuteTx pays the spend of a passed emergency proposal.
+// The executor pays the fee.
+func (em *EmergencyManager) ProcessEmergencyExecuteTx(tx *EmergencyExecuteTx, executor crypto.PublicKey) error {
+	if err := em.Execute(tx.ProposalID); err != nil {
+		return err
+	}
+	em.tokenState.Balances.Sub(executor.String(), uint64(tx.Fee))
+	return nil
+}
+
+// Execute pays the spend of a passed emergency proposal from the treasury
+// and marks the proposal executed
+func (em *EmergencyManager) Execute(proposalID types.Hash) error {
+	spend, exists := em.spends[proposalID]
+	if !exists {
+		return NewDAOError(ErrInvalidProposal, "proposal is not an emergency spend", nil)
+	}
+
+	proposal, exists := em.governanceState.Proposal(proposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	if em.governanceState.Treasury.Balance < spend.Amount {
+		return NewDAOError(ErrTreasuryInsufficient, "insufficient treasury funds for the spend", map[string]interface{}{
+			"balance": em.governanceState.Treasury.Balance,
+			"amount":  spend.Amount,
+		})
+	}
+
+	em.governanceState.Treasury.Balance -= spend.Amount
+	em.tokenState.Balances.Add(spend.Recipient.String(), spend.Amount)
+	spend.ExecutedAt = time.Now().Unix()
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// GetSpend returns the spend of an emergency proposal
+func (em *EmergencyManager) GetSpend(proposalID types.Hash) (*EmergencySpend, bool) {
+	spend, exists := em.spends[proposalID]
+	return spend, exists
+}
+
+// ListSpends returns the emergency spends, newest first
+func (em *EmergencyManager) ListSpends() []*EmergencySpend {
+	spends := make([]*EmergencySpend, 0, len(em.spends))
+	for _, spend := range em.spends {
+		spends = append(spends, spend)
+	}
+
+	sort.Slice(spends, func(i, j int) bool {
+		if spends[i].CreatedAt != spends[j].CreatedAt {
+			return spends[i].CreatedAt > spends[j].CreatedAt
+		}
+		return spends[i].ID.String() < spends[j].ID.String()
+	})
+	return spends
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencySpend_Lifecycle(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	guardian := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		founder.String(): 20000,
+		voter.String():   10000,
+	}))
+	require.NoError(t, dao.InitializeFounderRoles([]crypto.PublicKey{founder}))
+	require.NoError(t, dao.GrantRole(guardian.PublicKey(), RoleEmergency, founder, 0))
+	dao.GovernanceState.Treasury.Balance = 50000
+
+	spend := func(amount uint64) *EmergencySpendTx {
+		return &EmergencySpendTx{
+			Fee:         100,
+			Title:       "Patch the bridge",
+			Description: "Pay the auditors reviewing the exploit fix",
+			Recipient:   recipient,
+			Amount:      amount,
+			Incident:    "INC-42 bridge exploit",
+			VotingType:  VotingTypeSimple,
+		}
+	}
+	signed := func(amount uint64) *EmergencySpendTx {
+		tx := spend(amount)
+		require.NoError(t, tx.Sign(guardian))
+		return tx
+	}
+	vote := func(proposalID types.Hash, voter crypto.PublicKey, choice VoteChoice, weight uint64, txHash types.Hash) {
+		require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposalID, Choice: choice, Weight: weight}, voter, txHash))
+	}
+	closeVote := func(proposalID types.Hash) *Proposal {
+		proposal, err := dao.GetProposal(proposalID)
+		require.NoError(t, err)
+		proposal.EndTime = time.Now().Unix() - 1
+		require.NoError(t, dao.Processor.UpdateProposalStatus(proposalID))
+		return proposal
+	}
+
+	// Spends need the co-signature of a guardian other than the proposer
+	err := dao.ProcessDAOTransaction(spend(1000), founder, types.Hash{0xE0})
+	assert.Equal(t, ErrGuardianSignature, err.(*DAOError).Code)
+	stranger := spend(1000)
+	require.NoError(t, stranger.Sign(crypto.GeneratePrivateKey()))
+	err = dao.ProcessDAOTransaction(stranger, founder, types.Hash{0xE1})
+	assert.Equal(t, ErrUnauthorized, err.(*DAOError).Code)
+	tampered := signed(1000)
+	tampered.Amount = 40000
+	err = dao.ProcessDAOTransaction(tampered, founder, types.Hash{0xE2})
+	assert.Equal(t, ErrGuardianSignature, err.(*DAOError).Code)
+	err = dao.ProcessDAOTransaction(signed(1000), guardian.PublicKey(), types.Hash{0xE3})
+	assert.Error(t, err)
+	assert.Error(t, dao.ProcessDAOTransaction(signed(60000), founder, types.Hash{0xE4}))
+
+	// The vote opens at once on the short window with the elevated quorum
+	// and threshold
+	rejectedID := types.Hash{0xE5}
+	require.NoError(t, dao.ProcessDAOTransaction(signed(1000), founder, rejectedID))
+	proposal, err := dao.GetProposal(rejectedID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalTypeEmergency, proposal.ProposalType)
+	assert.Equal(t, ProposalStatusActive, proposal.Status)
+	assert.Equal(t, int64(21600), proposal.EndTime-proposal.StartTime)
+	assert.Equal(t, uint64(6667), proposal.Threshold)
+	assert.Equal(t, uint64(4000), proposal.Quorum)
+
+	// A simple majority is not enough
+	vote(rejectedID, founder, VoteChoiceYes, 6000, types.Hash{0xE6})
+	vote(rejectedID, voter, VoteChoiceNo, 3100, types.Hash{0xE7})
+	assert.Equal(t, ProposalStatusRejected, closeVote(rejectedID).Status)
+
+	// Neither is the DAO's usual quorum
+	thinID := types.Hash{0xE8}
+	require.NoError(t, dao.ProcessDAOTransaction(signed(1000), founder, thinID))
+	vote(thinID, founder, VoteChoiceYes, 3000, types.Hash{0xE9})
+	assert.Equal(t, ProposalStatusRejected, closeVote(thinID).Status)
+
+	passedID := types.Hash{0xEA}
+	require.NoError(t, dao.ProcessDAOTransaction(signed(1000), founder, passedID))
+	execute := &EmergencyExecuteTx{Fee: 10, ProposalID: passedID}
+	assert.Error(t, dao.ProcessDAOTransaction(execute, voter, types.Hash{0xEB}))
+	vote(passedID, founder, VoteChoiceYes, 5000, types.Hash{0xEC})
+	closeVote(passedID)
+	require.NoError(t, dao.ProcessDAOTransaction(execute, voter, types.Hash{0xED}))
+
+	proposal, err = dao.GetProposal(passedID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+	assert.Equal(t, uint64(49000), dao.GetTreasuryBalance())
+	assert.Equal(t, uint64(1000), dao.GetTokenBalance(recipient))
+	assert.Error(t, dao.ProcessDAOTransaction(execute, voter, types.Hash{0xEE}))
+
+	executed, exists := dao.GetEmergencySpend(passedID)
+	require.True(t, exists)
+	assert.Equal(t, guardian.PublicKey(), executed.Guardian)
+	assert.NotZero(t, executed.ExecutedAt)
+	assert.Len(t, dao.ListEmergencySpends(), 3)
+}
@@ -0,0 +1,193 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// ProposalEndorsements tracks the seconding of a proposal. While the DAO
+// requires endorsements, a new proposal stays closed to votes until members
+// with enough voting power endorse it, and expires if they do not in time.
+type ProposalEndorsements struct {
+	ProposalID types.Hash
+	Proposer   crypto.PublicKey
+	Required   uint64 // Voting power the endorsements must total
+	Deadline   int64
+	Deposit    uint64 // Held from the proposer until the proposal is endorsed or expires
+	ExpiryBurn uint64 // Basis points of the deposit burned on expiry
+	Endorsers  map[string]uint64
+	Total      uint64
+	EndorsedAt int64
+	ExpiredAt  int64
+	Burned     uint64
+}
+
+// Awaiting reports whether the proposal still needs endorsements
+func (pe *ProposalEndorsements) Awaiting() bool {
+	return pe.EndorsedAt == 0 && pe.ExpiredAt == 0
+}
+
+// EndorsementManager gates new proposals behind stake-weighted endorsements
+// to keep low-effort proposals from reaching a vote
+type EndorsementManager struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	processor        *DAOProcessor
+	endorsements     map[types.Hash]*ProposalEndorsements
+}
+
+// NewEndorsementManager creates a new endorsement manager
+func NewEndorsementManager(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager, processor *DAOProcessor) *EndorsementManager {
+	return &EndorsementManager{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		processor:        processor,
+		endorsements:     make(map[types.Hash]*ProposalEndorsements),
+	}
+}
+
+// CheckDeposit returns an error unless the proposer can pay the fee of a new
+// proposal and the endorsement deposit
+func (em *EndorsementManager) CheckDeposit(proposer crypto.PublicKey, fee int64) error {
+	config := em.parameterManager.GetParameterConfig()
+	if config.EndorsementThreshold == 0 {
+		return nil
+	}
+	if em.tokenState.Balances.Get(proposer.String()) < uint64(fee)+config.EndorsementDeposit {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for the endorsement deposit", map[string]interface{}{
+			"deposit": config.EndorsementDeposit,
+		})
+	}
+	return nil
+}
+
+// Open starts collecting endorsements for a new proposal and holds the
+// proposer's deposit. It does nothing while endorsements are not required.
+func (em *EndorsementManager) Open(proposalID types.Hash, proposer crypto.PublicKey, now int64) {
+	config := em.parameterManager.GetParameterConfig()
+	if config.EndorsementThreshold == 0 {
+		return
+	}
+
+	em.tokenState.Balances.Sub(proposer.String(), config.EndorsementDeposit)
+	em.endorsements[proposalID] = &ProposalEndorsements{
+		ProposalID: proposalID,
+		Proposer:   proposer,
+		Required:   config.EndorsementThreshold,
+		Deadline:   now + config.EndorsementPeriod,
+		Deposit:    config.EndorsementDeposit,
+		ExpiryBurn: config.EndorsementExpiryBurn,
+		Endorsers:  make(map[string]uint64),
+	}
+}
+
+// Awaiting reports whether a proposal is closed to votes until it is
+// endorsed
+func (em *EndorsementManager) Awaiting(proposalID types.Hash) bool {
+	record, exists := em.endorsements[proposalID]
+	return exists && record.Awaiting()
+}
+
+// CheckVotable returns an error while a proposal awaits endorsements
+func (em *EndorsementManager) CheckVotable(proposalID types.Hash) error {
+	if !em.Awaiting(proposalID) {
+		return nil
+	}
+	record := em.endorsements[proposalID]
+	return NewDAOError(ErrNotEndorsed, "proposal has not gathered its endorsements", map[string]interface{}{
+		"required": record.Required,
+		"total":    record.Total,
+		"deadline": record.Deadline,
+	})
+}
+
+// ProcessEndorseProposalTx adds the endorser's voting power to a proposal.
+// The endorser pays the fee.
+func (em *EndorsementManager) ProcessEndorseProposalTx(tx *EndorseProposalTx, endorser crypto.PublicKey) error {
+	if err := em.Endorse(tx.ProposalID, endorser, time.Now().Unix()); err != nil {
+		return err
+	}
+	em.tokenState.Balances.Sub(endorser.String(), uint64(tx.Fee))
+	return nil
+}
+
+// Endorse adds the endorser's current voting power to a proposal. Reaching
+// the required power returns the deposit and opens the vote; a vote whose
+// start has passed is moved to start now and keeps its full length.
+func (em *EndorsementManager) Endorse(proposalID types.Hash, endorser crypto.PublicKey, now int64) error {
+	record, exists := em.endorsements[proposalID]
+	if !exists {
+		return NewDAOError(ErrInvalidProposal, "proposal does not need endorsements", nil)
+	}
+	if !record.Awaiting() {
+		return NewDAOError(ErrInvalidProposal, "proposal is no longer awaiting endorsements", nil)
+	}
+	if now > record.Deadline {
+		return NewDAOError(ErrProposalExpired, "endorsement period has ended", map[string]interface{}{
+			"deadline": record.Deadline,
+		})
+	}
+
+	endorserStr := endorser.String()
+	if endorserStr == record.Proposer.String() {
+		return NewDAOError(ErrUnauthorized, "proposers cannot endorse their own proposal", nil)
+	}
+	if _, endorsed := record.Endorsers[endorserStr]; endorsed {
+		return NewDAOError(ErrDuplicateVote, "proposal already endorsed by this member", nil)
+	}
+	power := em.processor.GetEffectiveVotingPower(endorser)
+	if power == 0 {
+		return NewDAOError(ErrInsufficientTokens, "endorser has no voting power", nil)
+	}
+
+	record.Endorsers[endorserStr] = power
+	record.Total += power
+	if record.Total < record.Required {
+		return nil
+	}
+
+	record.EndorsedAt = now
+	em.tokenState.Balances.Add(record.Proposer.String(), record.Deposit)
+	if proposal, exists := em.governanceState.Proposals[proposalID]; exists && now > proposal.StartTime {
+		proposal.EndTime = now + proposal.EndTime - proposal.StartTime
+		proposal.StartTime = now
+	}
+	return nil
+}
+
+// ExpireProposals cancels the proposals whose endorsement period ended
+// without enough endorsements. Part of each deposit is burned and the rest
+// returned to the proposer.
+func (em *EndorsementManager) ExpireProposals(now int64) []*ProposalEndorsements {
+	var expired []*ProposalEndorsements
+	for _, record := range em.endorsements {
+		if !record.Awaiting() || now <= record.Deadline {
+			continue
+		}
+
+		record.ExpiredAt = now
+		record.Burned = record.Deposit * record.ExpiryBurn / 10000
+		em.tokenState.Balances.Add(record.Proposer.String(), record.Deposit-record.Burned)
+		em.tokenState.TotalSupply -= record.Burned
+		if proposal, exists := em.governanceState.Proposals[record.ProposalID]; exists {
+			proposal.Status = ProposalStatusCancelled
+		}
+		expired = append(expired, record)
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].ProposalID.String() < expired[j].ProposalID.String()
+	})
+	return expired
+}
+
+// Get returns the endorsements of a proposal
+func (em *EndorsementManager) Get(proposalID types.Hash) (*ProposalEndorsements, bool) {
+	record, exists := em.endorsements[proposalID]
+	return record, exists
+}
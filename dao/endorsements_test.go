@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndorsements_Lifecycle(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	proposer := crypto.GeneratePrivateKey().PublicKey()
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		proposer.String(): 5000,
+		alice.String():    3000,
+		bob.String():      4000,
+	}))
+
+	config := dao.ParameterManager.GetParameterConfig()
+	config.EndorsementThreshold = 6000
+	config.EndorsementDeposit = 1000
+	config.EndorsementExpiryBurn = 2500
+
+	now := time.Now().Unix()
+	propose := func(id types.Hash) *Proposal {
+		require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+			Fee:          100,
+			Title:        "Routine",
+			Description:  "A routine change",
+			ProposalType: ProposalTypeGeneral,
+			VotingType:   VotingTypeSimple,
+			StartTime:    now,
+			EndTime:      now + 86400,
+			Threshold:    5100,
+		}, proposer, id))
+		proposal, err := dao.GetProposal(id)
+		require.NoError(t, err)
+		return proposal
+	}
+	endorse := func(proposalID types.Hash, endorser crypto.PublicKey, txHash types.Hash) error {
+		return dao.ProcessDAOTransaction(&EndorseProposalTx{Fee: 10, ProposalID: proposalID}, endorser, txHash)
+	}
+
+	// The proposer's deposit is held and the proposal is closed to votes
+	proposal := propose(types.Hash{0xA0})
+	assert.Equal(t, uint64(5000-100-1000), dao.GetTokenBalance(proposer))
+	err := dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposal.ID, Choice: VoteChoiceYes, Weight: 100}, alice, types.Hash{0xB0})
+	assert.Equal(t, ErrNotEndorsed, err.(*DAOError).Code)
+	require.NoError(t, dao.Processor.UpdateProposalStatus(proposal.ID))
+	assert.Equal(t, ProposalStatusPending, proposal.Status)
+
+	// Proposers cannot second themselves and endorsers count once
+	assert.Error(t, endorse(proposal.ID, proposer, types.Hash{0xC0}))
+	require.NoError(t, endorse(proposal.ID, alice, types.Hash{0xC1}))
+	assert.Error(t, endorse(proposal.ID, alice, types.Hash{0xC2}))
+	assert.True(t, dao.Endorsements.Awaiting(proposal.ID))
+
+	// Reaching the threshold returns the deposit and opens the vote
+	require.NoError(t, endorse(proposal.ID, bob, types.Hash{0xC3}))
+	record, exists := dao.GetProposalEndorsements(proposal.ID)
+	require.True(t, exists)
+	assert.Equal(t, uint64(7000), record.Total)
+	assert.NotZero(t, record.EndorsedAt)
+	assert.Equal(t, uint64(5000-100), dao.GetTokenBalance(proposer))
+	assert.Equal(t, int64(86400), proposal.EndTime-proposal.StartTime)
+	require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposal.ID, Choice: VoteChoiceYes, Weight: 100}, alice, types.Hash{0xB1}))
+
+	// An unendorsed proposal expires and a quarter of its deposit is burned
+	supply := dao.TokenState.TotalSupply
+	stale := propose(types.Hash{0xA1})
+	require.NoError(t, endorse(stale.ID, alice, types.Hash{0xC4}))
+	assert.Empty(t, dao.Endorsements.ExpireProposals(now+10))
+	expired := dao.Endorsements.ExpireProposals(now + config.EndorsementPeriod + 10)
+	require.Len(t, expired, 1)
+	assert.Equal(t, uint64(250), expired[0].Burned)
+	assert.Equal(t, ProposalStatusCancelled, stale.Status)
+	assert.Equal(t, uint64(5000-200-250), dao.GetTokenBalance(proposer))
+	assert.Equal(t, supply-250, dao.TokenState.TotalSupply)
+	assert.Error(t, endorse(stale.ID, bob, types.Hash{0xC5}))
+
+	// Proposals made while endorsements are off open at once
+	config.EndorsementThreshold = 0
+	open := propose(types.Hash{0xA2})
+	_, exists = dao.GetProposalEndorsements(open.ID)
+	assert.False(t, exists)
+	assert.Error(t, endorse(open.ID, alice, types.Hash{0xC6}))
+}
@@ -26,8 +26,123 @@ const (
 	ErrFunctionPaused       ErrorCode = 4018
 	ErrRoleExpired          ErrorCode = 4019
 	ErrAuditAccessDenied    ErrorCode = 4020
+	ErrPositionNotFound     ErrorCode = 4021
+	ErrPositionLocked       ErrorCode = 4022
+	ErrBootstrapConfig      ErrorCode = 4023
+	ErrFounderPowerSunset   ErrorCode = 4024
+	ErrDisputeNotFound      ErrorCode = 4025
+	ErrDisputePhase         ErrorCode = 4026
+	ErrInsufficientJurors   ErrorCode = 4027
+	ErrMultisigNotFound     ErrorCode = 4028
+	ErrSubDAONotFound       ErrorCode = 4029
+	ErrGrantNotFound        ErrorCode = 4030
+	ErrBountyNotFound       ErrorCode = 4031
+	ErrCommentNotFound      ErrorCode = 4032
+	ErrDraftNotFound        ErrorCode = 4033
+	ErrDraftConflict        ErrorCode = 4034
+	ErrNotActiveMember      ErrorCode = 4035
+	ErrJobNotFound          ErrorCode = 4036
+	ErrQFRoundNotFound      ErrorCode = 4037
+	ErrRPGFRoundNotFound    ErrorCode = 4038
+	ErrOptimisticNotFound   ErrorCode = 4039
+	ErrOracleFeedNotFound   ErrorCode = 4040
+	ErrOracleStale          ErrorCode = 4041
+	ErrConditionUnmet       ErrorCode = 4042
+	ErrConversionSlippage   ErrorCode = 4043
+	ErrDocumentNotFound     ErrorCode = 4044
+	ErrDocumentConflict     ErrorCode = 4045
+	ErrDependencyUnmet      ErrorCode = 4046
+	ErrDependencyCycle      ErrorCode = 4047
+	ErrGuardianSignature    ErrorCode = 4048
+	ErrNotEndorsed          ErrorCode = 4049
+	ErrInvalidName          ErrorCode = 4050
+	ErrNameTaken            ErrorCode = 4051
+	ErrNameNotFound         ErrorCode = 4052
+	ErrInvalidProfile       ErrorCode = 4053
+	ErrInvalidDelegate      ErrorCode = 4054
+	ErrInvalidTrack         ErrorCode = 4055
+	ErrProposalQueued       ErrorCode = 4056
+	ErrInvalidDAO           ErrorCode = 4057
+	ErrDAONotFound          ErrorCode = 4058
 )
 
+// errorCodeNames are the stable names of the error codes that API clients
+// branch on
+var errorCodeNames = map[ErrorCode]string{
+	ErrInsufficientTokens:   "insufficient_tokens",
+	ErrProposalNotFound:     "proposal_not_found",
+	ErrVotingClosed:         "voting_closed",
+	ErrUnauthorized:         "unauthorized",
+	ErrInvalidSignature:     "invalid_signature",
+	ErrQuorumNotMet:         "quorum_not_met",
+	ErrTreasuryInsufficient: "treasury_insufficient",
+	ErrInvalidProposal:      "invalid_proposal",
+	ErrDuplicateVote:        "duplicate_vote",
+	ErrInvalidDelegation:    "invalid_delegation",
+	ErrInvalidTimeframe:     "invalid_timeframe",
+	ErrInvalidThreshold:     "invalid_threshold",
+	ErrTokenTransferFailed:  "token_transfer_failed",
+	ErrInvalidVoteChoice:    "invalid_vote_choice",
+	ErrProposalExpired:      "proposal_expired",
+	ErrSecurityViolation:    "security_violation",
+	ErrEmergencyActive:      "emergency_active",
+	ErrFunctionPaused:       "function_paused",
+	ErrRoleExpired:          "role_expired",
+	ErrAuditAccessDenied:    "audit_access_denied",
+	ErrPositionNotFound:     "position_not_found",
+	ErrPositionLocked:       "position_locked",
+	ErrBootstrapConfig:      "bootstrap_config",
+	ErrFounderPowerSunset:   "founder_power_sunset",
+	ErrDisputeNotFound:      "dispute_not_found",
+	ErrDisputePhase:         "dispute_phase",
+	ErrInsufficientJurors:   "insufficient_jurors",
+	ErrMultisigNotFound:     "multisig_not_found",
+	ErrSubDAONotFound:       "subdao_not_found",
+	ErrGrantNotFound:        "grant_not_found",
+	ErrBountyNotFound:       "bounty_not_found",
+	ErrCommentNotFound:      "comment_not_found",
+	ErrDraftNotFound:        "draft_not_found",
+	ErrDraftConflict:        "draft_conflict",
+	ErrNotActiveMember:      "not_active_member",
+	ErrJobNotFound:          "job_not_found",
+	ErrQFRoundNotFound:      "qf_round_not_found",
+	ErrRPGFRoundNotFound:    "rpgf_round_not_found",
+	ErrOptimisticNotFound:   "optimistic_proposal_not_found",
+	ErrOracleFeedNotFound:   "oracle_feed_not_found",
+	ErrOracleStale:          "oracle_data_stale",
+	ErrConditionUnmet:       "oracle_condition_unmet",
+	ErrConversionSlippage:   "conversion_slippage",
+	ErrDocumentNotFound:     "document_not_found",
+	ErrDocumentConflict:     "document_version_conflict",
+	ErrDependencyUnmet:      "dependency_unmet",
+	ErrDependencyCycle:      "dependency_cycle",
+	ErrGuardianSignature:    "guardian_signature_invalid",
+	ErrNotEndorsed:          "proposal_not_endorsed",
+	ErrInvalidName:          "invalid_name",
+	ErrNameTaken:            "name_taken",
+	ErrNameNotFound:         "name_not_found",
+	ErrInvalidProfile:       "invalid_profile",
+	ErrInvalidDelegate:      "invalid_delegate_statement",
+	ErrInvalidTrack:         "invalid_track",
+	ErrProposalQueued:       "proposal_queued",
+	ErrInvalidDAO:           "invalid_dao",
+	ErrDAONotFound:          "dao_not_found",
+}
+
+// String returns the stable name of the code, such as "voting_closed"
+func (c ErrorCode) String() string {
+	if name, exists := errorCodeNames[c]; exists {
+		return name
+	}
+	return fmt.Sprintf("dao_error_%d", int(c))
+}
+
+// Retryable reports whether the same request can succeed later without
+// changes, as it does once an emergency ends or a function is unpaused
+func (c ErrorCode) Retryable() bool {
+	return c == ErrEmergencyActive || c == ErrFunctionPaused
+}
+
 // DAOError represents a DAO-specific error
 type DAOError struct {
 	Code    ErrorCode
@@ -182,4 +297,112 @@ var (
 		"access to audit log denied",
 		nil,
 	)
+
+	ErrPositionNotFoundError = NewDAOError(
+		ErrPositionNotFound,
+		"position not found",
+		nil,
+	)
+
+	ErrPositionNotTransferableError = NewDAOError(
+		ErrPositionLocked,
+		"position is not transferable",
+		nil,
+	)
+
+	ErrFounderPowerSunsetError = NewDAOError(
+		ErrFounderPowerSunset,
+		"founder power has sunset",
+		nil,
+	)
+
+	ErrDisputeNotFoundError = NewDAOError(
+		ErrDisputeNotFound,
+		"dispute not found",
+		nil,
+	)
+
+	ErrMultisigNotFoundError = NewDAOError(
+		ErrMultisigNotFound,
+		"multisig account not found",
+		nil,
+	)
+
+	ErrSubDAONotFoundError = NewDAOError(
+		ErrSubDAONotFound,
+		"sub-DAO not found",
+		nil,
+	)
+
+	ErrGrantNotFoundError = NewDAOError(
+		ErrGrantNotFound,
+		"grant not found",
+		nil,
+	)
+
+	ErrBountyNotFoundError = NewDAOError(
+		ErrBountyNotFound,
+		"bounty not found",
+		nil,
+	)
+
+	ErrQFRoundNotFoundError = NewDAOError(
+		ErrQFRoundNotFound,
+		"quadratic funding round not found",
+		nil,
+	)
+
+	ErrRPGFRoundNotFoundError = NewDAOError(
+		ErrRPGFRoundNotFound,
+		"retroactive funding round not found",
+		nil,
+	)
+
+	ErrOptimisticNotFoundError = NewDAOError(
+		ErrOptimisticNotFound,
+		"optimistic proposal not found",
+		nil,
+	)
+
+	ErrTrackNotFoundError = NewDAOError(
+		ErrInvalidTrack,
+		"proposal track not found",
+		nil,
+	)
+
+	ErrDAONotFoundError = NewDAOError(
+		ErrDAONotFound,
+		"DAO not found",
+		nil,
+	)
+
+	ErrOracleFeedNotFoundError = NewDAOError(
+		ErrOracleFeedNotFound,
+		"oracle feed not found",
+		nil,
+	)
+
+	ErrDocumentNotFoundError = NewDAOError(
+		ErrDocumentNotFound,
+		"governance document not found",
+		nil,
+	)
+
+	ErrCommentNotFoundError = NewDAOError(
+		ErrCommentNotFound,
+		"comment not found",
+		nil,
+	)
+
+	ErrDraftNotFoundError = NewDAOError(
+		ErrDraftNotFound,
+		"draft not found",
+		nil,
+	)
+
+	ErrNotActiveMemberError = NewDAOError(
+		ErrNotActiveMember,
+		"membership is suspended or exited",
+		nil,
+	)
 )
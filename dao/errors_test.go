@@ -0,0 +1,23 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodeNames(t *testing.T) {
+	// Every code has a distinct name for API clients to branch on
+	seen := make(map[string]bool)
+	for code := ErrInsufficientTokens; code <= ErrDAONotFound; code++ {
+		name := code.String()
+		assert.NotContains(t, name, "dao_error_", "code %d has no name", int(code))
+		assert.False(t, seen[name], "duplicate name %s", name)
+		seen[name] = true
+	}
+
+	assert.Equal(t, "voting_closed", ErrVotingClosed.String())
+	assert.Equal(t, "dao_error_4999", ErrorCode(4999).String())
+	assert.True(t, ErrFunctionPaused.Retryable())
+	assert.False(t, ErrDuplicateVote.Retryable())
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Exportable entities of the DAO state
+const (
+	ExportProposals = "proposals"
+	ExportVotes     = "votes"
+	ExportMembers   = "members"
+	ExportTreasury  = "treasury"
+)
+
+// ExportEntities are the exportable entities in export order
+var ExportEntities = []string{ExportProposals, ExportVotes, ExportMembers, ExportTreasury}
+
+// exportColumns are the columns of each exportable entity
+var exportColumns = map[string][]string{
+	ExportProposals: {"id", "creator", "title", "proposal_type", "voting_type", "status", "start_time", "end_time", "threshold", "yes_votes", "no_votes", "abstain_votes", "metadata_hash"},
+	ExportVotes:     {"proposal_id", "voter", "choice", "weight", "timestamp", "reason"},
+	ExportMembers:   {"address", "balance", "staked", "reputation", "status", "joined_at", "last_active"},
+	ExportTreasury:  {"id", "recipient", "amount", "purpose", "signatures", "created_at", "expires_at", "executed", "executed_at"},
+}
+
+// ExportColumns returns the columns of an exportable entity
+func ExportColumns(entity string) ([]string, bool) {
+	columns, exists := exportColumns[entity]
+	return columns, exists
+}
+
+// Export calls emit with every row of entity, in a stable order, so large
+// datasets can be streamed without building them in memory. Each row has a
+// value per column of the entity.
+func (d *DAO) Export(entity string, emit func(row []interface{}) error) error {
+	switch entity {
+	case ExportProposals:
+		for _, id := range sortHashes(d.GovernanceState.ProposalIDs()) {
+			proposal, _ := d.GovernanceState.Proposal(id)
+			results := proposal.Results
+			if results == nil {
+				results = &VoteResults{}
+			}
+			if err := emit([]interface{}{
+				id.String(), proposal.Creator.String(), proposal.Title, proposal.ProposalType, proposal.VotingType, proposal.Status,
+				proposal.StartTime, proposal.EndTime, proposal.Threshold,
+				results.YesVotes, results.NoVotes, results.AbstainVotes, proposal.MetadataHash.String(),
+			}); err != nil {
+				return err
+			}
+		}
+
+	case ExportVotes:
+		for _, id := range sortHashes(d.GovernanceState.ProposalIDs()) {
+			votes, _ := d.GovernanceState.ProposalVotes(id)
+			voters := make([]string, 0, len(votes))
+			for voter := range votes {
+				voters = append(voters, voter)
+			}
+			sort.Strings(voters)
+
+			for _, voter := range voters {
+				vote := votes[voter]
+				if err := emit([]interface{}{id.String(), voter, vote.Choice, vote.Weight, vote.Timestamp, vote.Reason}); err != nil {
+					return err
+				}
+			}
+		}
+
+	case ExportMembers:
+		addresses := make([]string, 0, len(d.GovernanceState.TokenHolders))
+		for address := range d.GovernanceState.TokenHolders {
+			addresses = append(addresses, address)
+		}
+		sort.Strings(addresses)
+
+		for _, address := range addresses {
+			holder := d.GovernanceState.TokenHolders[address]
+			if err := emit([]interface{}{
+				address, d.TokenState.Balances.Get(address), holder.Staked, holder.Reputation, holder.Status.String(),
+				holder.JoinedAt, holder.LastActive,
+			}); err != nil {
+				return err
+			}
+		}
+
+	case ExportTreasury:
+		for _, id := range sortHashes(d.GovernanceState.TreasuryTransactionIDs()) {
+			tx, _ := d.GovernanceState.TreasuryTransaction(id)
+			if err := emit([]interface{}{
+				id.String(), tx.Recipient.String(), tx.Amount, tx.Purpose, tx.SignatureCount(),
+				tx.CreatedAt, tx.ExpiresAt, tx.Executed, tx.ExecutedAt,
+			}); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return NewDAOError(ErrInvalidProposal, "unknown export entity", map[string]interface{}{"entity": entity, "entities": ExportEntities})
+	}
+
+	return nil
+}
+
+// sortHashes sorts hashes in ascending order and returns them
+func sortHashes(hashes []types.Hash) []types.Hash {
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].String() < hashes[j].String()
+	})
+	return hashes
+}
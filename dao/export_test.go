@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	members := []crypto.PublicKey{crypto.GeneratePrivateKey().PublicKey(), crypto.GeneratePrivateKey().PublicKey()}
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		members[0].String(): 4000,
+		members[1].String(): 1000,
+	}))
+
+	for _, id := range []types.Hash{{0x02}, {0x01}} {
+		dao.GovernanceState.Proposals[id] = &Proposal{ID: id, Creator: members[0], Title: "Signal", Status: ProposalStatusActive}
+	}
+	dao.GovernanceState.Votes[types.Hash{0x01}] = map[string]*Vote{
+		members[1].String(): {Voter: members[1], Choice: VoteChoiceNo, Weight: 1000, Timestamp: 10},
+		members[0].String(): {Voter: members[0], Choice: VoteChoiceYes, Weight: 4000, Timestamp: 20},
+	}
+	dao.GovernanceState.Treasury.Transactions[types.Hash{0x03}] = &PendingTx{ID: types.Hash{0x03}, Recipient: members[1], Amount: 250, Purpose: "Audit"}
+
+	export := func(entity string) [][]interface{} {
+		var rows [][]interface{}
+		require.NoError(t, dao.Export(entity, func(row []interface{}) error {
+			columns, _ := ExportColumns(entity)
+			require.Len(t, row, len(columns))
+			rows = append(rows, row)
+			return nil
+		}))
+		return rows
+	}
+
+	// Rows come in a stable order
+	proposals := export(ExportProposals)
+	require.Len(t, proposals, 2)
+	assert.Equal(t, types.Hash{0x01}.String(), proposals[0][0])
+	assert.Equal(t, members[0].String(), proposals[0][1])
+
+	votes := export(ExportVotes)
+	require.Len(t, votes, 2)
+	assert.Equal(t, uint64(5000), votes[0][3].(uint64)+votes[1][3].(uint64))
+	assert.Less(t, votes[0][1].(string), votes[1][1].(string))
+
+	membersRows := export(ExportMembers)
+	require.Len(t, membersRows, 2)
+	assert.Equal(t, "active", membersRows[0][4])
+
+	treasury := export(ExportTreasury)
+	require.Len(t, treasury, 1)
+	assert.Equal(t, "Audit", treasury[0][3])
+
+	assert.Error(t, dao.Export("wallets", func(row []interface{}) error { return nil }))
+}
@@ -0,0 +1,261 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// Genesis declares the initial state of a DAO in one document: its token
+// and who holds it, founders, treasury, governance parameters and staking
+// pools
+type Genesis struct {
+	Token        GenesisToken         `json:"token"`
+	Allocations  []GenesisAllocation  `json:"allocations,omitempty"`
+	Founders     []string             `json:"founders,omitempty"` // The first is super admin, the others admins
+	Treasury     GenesisTreasury      `json:"treasury"`
+	Parameters   GenesisParameters    `json:"parameters,omitempty"`
+	StakingPools []GenesisStakingPool `json:"staking_pools,omitempty"`
+}
+
+// GenesisToken is the governance token of a DAO
+type GenesisToken struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// GenesisAllocation is a token balance a DAO starts with
+type GenesisAllocation struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+// GenesisTreasury is the treasury a DAO starts with
+type GenesisTreasury struct {
+	Signers      []string `json:"signers"`
+	RequiredSigs uint8    `json:"required_sigs"`
+	Balance      uint64   `json:"balance,omitempty"`
+}
+
+// GenesisStakingPool is a staking pool a DAO starts with
+type GenesisStakingPool struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	RewardRate   uint64 `json:"reward_rate"`
+	MinStake     uint64 `json:"min_stake"`
+	LockupPeriod uint64 `json:"lockup_period"` // Seconds
+}
+
+// GenesisParameters are governance parameter values by name, such as
+// "voting_period"
+type GenesisParameters map[string]string
+
+// UnmarshalJSON accepts parameter values written as strings, numbers or
+// booleans
+func (p *GenesisParameters) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	params := make(GenesisParameters, len(raw))
+	for name, value := range raw {
+		s, err := ParameterValueString(value)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", name, err)
+		}
+		params[name] = s
+	}
+
+	*p = params
+	return nil
+}
+
+// Check reports everything that would keep a DAO from starting from the
+// genesis document
+func (g *Genesis) Check() []DraftIssue {
+	issues := make([]DraftIssue, 0)
+	issue := func(field, format string, args ...interface{}) {
+		issues = append(issues, DraftIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(g.Token.Symbol) == 0 || len(g.Token.Symbol) > 10 {
+		issue("token.symbol", "Token symbol must be between 1 and 10 characters")
+	}
+	if len(g.Token.Name) == 0 || len(g.Token.Name) > 100 {
+		issue("token.name", "Token name must be between 1 and 100 characters")
+	}
+	if g.Token.Decimals > 18 {
+		issue("token.decimals", "Token decimals must be at most 18")
+	}
+
+	supply := uint64(0)
+	holders := make(map[string]bool)
+	for i, allocation := range g.Allocations {
+		field := fmt.Sprintf("allocations[%d]", i)
+		if _, err := parseAddress(allocation.Address); err != nil {
+			issue(field+".address", "Address must be a hex encoded public key")
+		} else if holders[allocation.Address] {
+			issue(field+".address", "Address is allocated more than once")
+		}
+		holders[allocation.Address] = true
+
+		if allocation.Amount == 0 {
+			issue(field+".amount", "Amount must be greater than zero")
+		} else if supply+allocation.Amount < supply {
+			issue(field+".amount", "Allocations exceed the maximum token supply")
+		}
+		supply += allocation.Amount
+	}
+
+	founders := make(map[string]bool)
+	for i, founder := range g.Founders {
+		if _, err := parseAddress(founder); err != nil {
+			issue(fmt.Sprintf("founders[%d]", i), "Founder must be a hex encoded public key")
+		} else if founders[founder] {
+			issue(fmt.Sprintf("founders[%d]", i), "Founder is listed more than once")
+		}
+		founders[founder] = true
+	}
+
+	signers := make(map[string]bool)
+	if len(g.Treasury.Signers) == 0 {
+		issue("treasury.signers", "The treasury needs at least one signer")
+	}
+	for i, signer := range g.Treasury.Signers {
+		if _, err := parseAddress(signer); err != nil {
+			issue(fmt.Sprintf("treasury.signers[%d]", i), "Signer must be a hex encoded public key")
+		} else if signers[signer] {
+			issue(fmt.Sprintf("treasury.signers[%d]", i), "Signer is listed more than once")
+		}
+		signers[signer] = true
+	}
+	if g.Treasury.RequiredSigs == 0 || int(g.Treasury.RequiredSigs) > len(g.Treasury.Signers) {
+		issue("treasury.required_sigs", "Required signatures must be between 1 and the number of signers")
+	}
+
+	// Parameters are checked against the supply the allocations create, in
+	// name order so that limits set earlier apply to later values
+	token := NewGovernanceToken(g.Token.Symbol, g.Token.Name, g.Token.Decimals)
+	token.TotalSupply = supply
+	pm := NewParameterManager(NewGovernanceState(), token)
+	for _, name := range g.parameterNames() {
+		field := "parameters." + name
+		current := pm.getCurrentParameterValue(name)
+		if current == nil {
+			issue(field, "Unknown parameter")
+			continue
+		}
+		value, err := parseParameterValue(current, g.Parameters[name])
+		if err != nil {
+			issue(field, "Value must be a %T", current)
+			continue
+		}
+		if err := pm.validateSingleParameter(name, value); err != nil {
+			issue(field, "%s", err.Error())
+			continue
+		}
+		if err := pm.applyParameterChange(name, value); err != nil {
+			issue(field, "%s", err.Error())
+		}
+	}
+
+	pools := make(map[string]bool)
+	for i, pool := range g.StakingPools {
+		field := fmt.Sprintf("staking_pools[%d]", i)
+		if len(pool.ID) == 0 || len(pool.ID) > 64 {
+			issue(field+".id", "Pool ID must be between 1 and 64 characters")
+		} else if pools[pool.ID] {
+			issue(field+".id", "Pool ID is used more than once")
+		}
+		pools[pool.ID] = true
+		if len(pool.Name) == 0 || len(pool.Name) > 100 {
+			issue(field+".name", "Pool name must be between 1 and 100 characters")
+		}
+	}
+
+	return issues
+}
+
+// Validate returns the first problem with the genesis document, with the
+// others in its details
+func (g *Genesis) Validate() error {
+	issues := g.Check()
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return NewDAOError(ErrInvalidDAO, issues[0].Message, map[string]interface{}{
+		"field":  issues[0].Field,
+		"issues": issues,
+	})
+}
+
+// NewDAOFromGenesis creates a DAO in the state a genesis document declares
+func NewDAOFromGenesis(g *Genesis) (*DAO, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	d := NewDAO(g.Token.Symbol, g.Token.Name, g.Token.Decimals)
+
+	if len(g.Allocations) > 0 {
+		distribution := make(map[string]uint64, len(g.Allocations))
+		for _, allocation := range g.Allocations {
+			distribution[allocation.Address] = allocation.Amount
+		}
+		if err := d.InitialTokenDistribution(distribution); err != nil {
+			return nil, err
+		}
+	}
+
+	signers := make([]crypto.PublicKey, len(g.Treasury.Signers))
+	for i, signer := range g.Treasury.Signers {
+		signers[i], _ = parseAddress(signer)
+	}
+	if err := d.InitializeTreasury(signers, g.Treasury.RequiredSigs); err != nil {
+		return nil, err
+	}
+	if g.Treasury.Balance > 0 {
+		d.AddTreasuryFunds(g.Treasury.Balance)
+	}
+
+	if len(g.Founders) > 0 {
+		founders := make([]crypto.PublicKey, len(g.Founders))
+		for i, founder := range g.Founders {
+			founders[i], _ = parseAddress(founder)
+		}
+		if err := d.InitializeFounderRoles(founders); err != nil {
+			return nil, err
+		}
+	}
+
+	pm := d.ParameterManager
+	for _, name := range g.parameterNames() {
+		value, _ := parseParameterValue(pm.getCurrentParameterValue(name), g.Parameters[name])
+		if err := pm.applyParameterChange(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pool := range g.StakingPools {
+		if err := d.CreateStakingPool(pool.ID, pool.Name, pool.RewardRate, pool.MinStake, pool.LockupPeriod); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// parameterNames returns the names of the genesis parameters in order
+func (g *Genesis) parameterNames() []string {
+	names := make([]string, 0, len(g.Parameters))
+	for name := range g.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
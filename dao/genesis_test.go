@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDAOFromGenesis(t *testing.T) {
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	admin := crypto.GeneratePrivateKey().PublicKey()
+	holder := crypto.GeneratePrivateKey().PublicKey()
+
+	document := `{
+		"token": {"symbol": "ACME", "name": "Acme Token", "decimals": 6},
+		"allocations": [
+			{"address": "` + founder.String() + `", "amount": 6000},
+			{"address": "` + holder.String() + `", "amount": 4000}
+		],
+		"founders": ["` + founder.String() + `", "` + admin.String() + `"],
+		"treasury": {"signers": ["` + founder.String() + `", "` + admin.String() + `"], "required_sigs": 2, "balance": 2500},
+		"parameters": {"voting_period": 172800, "quorum_threshold": "3000", "delegation_enabled": false},
+		"staking_pools": [{"id": "core", "name": "Core Stakers", "reward_rate": 5, "min_stake": 100, "lockup_period": 86400}]
+	}`
+
+	var genesis Genesis
+	require.NoError(t, json.Unmarshal([]byte(document), &genesis))
+	assert.Equal(t, "172800", genesis.Parameters["voting_period"])
+	assert.Equal(t, "false", genesis.Parameters["delegation_enabled"])
+	assert.Empty(t, genesis.Check())
+
+	d, err := NewDAOFromGenesis(&genesis)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ACME", d.TokenState.Symbol)
+	assert.Equal(t, uint8(6), d.TokenState.Decimals)
+	assert.Equal(t, uint64(10000), d.TokenState.TotalSupply)
+	assert.Equal(t, uint64(4000), d.GetTokenBalance(holder))
+
+	assert.Equal(t, uint64(2500), d.GetTreasuryBalance())
+	assert.Equal(t, uint8(2), d.GovernanceState.Treasury.RequiredSigs)
+	assert.Len(t, d.GovernanceState.Treasury.Signers, 2)
+
+	role, ok := d.GetUserRole(founder)
+	require.True(t, ok)
+	assert.Equal(t, RoleSuperAdmin, role)
+	role, ok = d.GetUserRole(admin)
+	require.True(t, ok)
+	assert.Equal(t, RoleAdmin, role)
+
+	config := d.GetParameterConfig()
+	assert.Equal(t, int64(172800), config.VotingPeriod)
+	assert.Equal(t, uint64(3000), config.QuorumThreshold)
+	assert.False(t, config.DelegationEnabled)
+
+	pool, exists := d.TokenomicsManager.stakingPools["core"]
+	require.True(t, exists)
+	assert.Equal(t, "Core Stakers", pool.Name)
+	assert.Equal(t, int64(86400), pool.LockupPeriod)
+}
+
+func TestGenesisCheck(t *testing.T) {
+	signer := crypto.GeneratePrivateKey().PublicKey()
+
+	genesis := Genesis{
+		Token: GenesisToken{Symbol: "", Name: "Acme Token", Decimals: 30},
+		Allocations: []GenesisAllocation{
+			{Address: signer.String(), Amount: 1000},
+			{Address: signer.String(), Amount: 0},
+			{Address: "not-an-address", Amount: 1},
+		},
+		Treasury: GenesisTreasury{Signers: []string{signer.String()}, RequiredSigs: 2},
+		Parameters: GenesisParameters{
+			"unknown_parameter":      "1",
+			"voting_period":          "soon",
+			"min_proposal_threshold": "900",
+		},
+		StakingPools: []GenesisStakingPool{
+			{ID: "core", Name: "Core"},
+			{ID: "core", Name: "Core Again"},
+		},
+	}
+
+	fields := make([]string, 0)
+	for _, issue := range genesis.Check() {
+		fields = append(fields, issue.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"token.symbol",
+		"token.decimals",
+		"allocations[1].address",
+		"allocations[1].amount",
+		"allocations[2].address",
+		"treasury.required_sigs",
+		"parameters.unknown_parameter",
+		"parameters.voting_period",
+		"parameters.min_proposal_threshold", // More than half of the allocated supply
+		"staking_pools[1].id",
+	}, fields)
+
+	err := genesis.Validate()
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidDAO, err.(*DAOError).Code)
+
+	_, err = NewDAOFromGenesis(&genesis)
+	assert.Error(t, err)
+
+	var params GenesisParameters
+	assert.Error(t, json.Unmarshal([]byte(`{"voting_period": [1]}`), &params))
+}
@@ -0,0 +1,370 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxGrantMilestones bounds the milestones of a grant
+const MaxGrantMilestones = 20
+
+// GrantStatus is the stage of a grant in the pipeline
+type GrantStatus string
+
+const (
+	GrantStatusProposed  GrantStatus = "proposed" // Awaiting the main DAO vote
+	GrantStatusRejected  GrantStatus = "rejected" // The main DAO did not approve it
+	GrantStatusActive    GrantStatus = "active"   // Funded and delivering milestones
+	GrantStatusCompleted GrantStatus = "completed"
+	GrantStatusCancelled GrantStatus = "cancelled"
+)
+
+// MilestoneStatus is the stage of a grant milestone
+type MilestoneStatus string
+
+const (
+	MilestoneStatusPending          MilestoneStatus = "pending"
+	MilestoneStatusSubmitted        MilestoneStatus = "submitted"
+	MilestoneStatusChangesRequested MilestoneStatus = "changes_requested"
+	MilestoneStatusPaid             MilestoneStatus = "paid"
+	MilestoneStatusCancelled        MilestoneStatus = "cancelled"
+)
+
+// GrantMilestoneSpec is a milestone promised by a grant proposal
+type GrantMilestoneSpec struct {
+	Title       string
+	Description string
+	Amount      uint64
+}
+
+// GrantMilestone tracks the delivery and payout of a milestone
+type GrantMilestone struct {
+	Title       string
+	Description string
+	Amount      uint64
+	Status      MilestoneStatus
+	Deliverable string // Link or IPFS hash of the delivered work
+	SubmittedAt int64
+	Comment     string // Review comment of the committee
+	ReviewedAt  int64
+	PaidAt      int64
+}
+
+// Grant is a treasury grant paid out milestone by milestone. Its ID is the
+// ID of the main DAO proposal that approved it. The committee is a single
+// key, usually a multisig account, whose approval pays each milestone.
+type Grant struct {
+	ID          types.Hash
+	Title       string
+	Description string
+	Applicant   crypto.PublicKey
+	Committee   crypto.PublicKey
+	Milestones  []*GrantMilestone
+	Total       uint64
+	Escrowed    uint64 // Reserved from the treasury and not paid yet
+	Paid        uint64
+	Status      GrantStatus
+	CreatedAt   int64
+	FundedAt    int64
+	ClosedAt    int64
+}
+
+// Proposal returns the main DAO proposal voting on the grant
+func (tx *GrantProposalTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        tx.Title,
+		Description:  description,
+		ProposalType: ProposalTypeTreasury,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// Total returns the sum of the milestone amounts
+func (tx *GrantProposalTx) Total() uint64 {
+	total := uint64(0)
+	for _, milestone := range tx.Milestones {
+		total += milestone.Amount
+	}
+	return total
+}
+
+// GrantPipeline counts the grants in each stage and the funds they move
+type GrantPipeline struct {
+	Proposed  uint64 `json:"proposed"`
+	Rejected  uint64 `json:"rejected"`
+	Active    uint64 `json:"active"`
+	Completed uint64 `json:"completed"`
+	Cancelled uint64 `json:"cancelled"`
+	Requested uint64 `json:"requested"` // Total of the grants still under vote
+	Escrowed  uint64 `json:"escrowed"`
+	Paid      uint64 `json:"paid"`
+	Refunded  uint64 `json:"refunded"`
+}
+
+// GrantManager keeps the grants and their milestones
+type GrantManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	grants          map[types.Hash]*Grant
+	refunded        uint64
+}
+
+// NewGrantManager creates a new grant manager
+func NewGrantManager(governanceState *GovernanceState, tokenState *GovernanceToken) *GrantManager {
+	return &GrantManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		grants:          make(map[types.Hash]*Grant),
+	}
+}
+
+// RecordProposal adds the grant of a main DAO proposal to the pipeline
+func (gm *GrantManager) RecordProposal(proposalID types.Hash, tx *GrantProposalTx, applicant crypto.PublicKey) {
+	milestones := make([]*GrantMilestone, len(tx.Milestones))
+	for i, spec := range tx.Milestones {
+		milestones[i] = &GrantMilestone{
+			Title:       spec.Title,
+			Description: spec.Description,
+			Amount:      spec.Amount,
+			Status:      MilestoneStatusPending,
+		}
+	}
+
+	gm.grants[proposalID] = &Grant{
+		ID:          proposalID,
+		Title:       tx.Title,
+		Description: tx.Description,
+		Applicant:   applicant,
+		Committee:   tx.Committee,
+		Milestones:  milestones,
+		Total:       tx.Total(),
+		Status:      GrantStatusProposed,
+		CreatedAt:   time.Now().Unix(),
+	}
+}
+
+// ProcessGrantExecuteTx funds the grant of a passed proposal, reserving its
+// total from the treasury
+func (gm *GrantManager) ProcessGrantExecuteTx(tx *GrantExecuteTx, executor crypto.PublicKey) error {
+	grant, exists := gm.grants[tx.ProposalID]
+	if !exists {
+		return ErrGrantNotFoundError
+	}
+	if grant.Status != GrantStatusProposed {
+		return NewDAOError(ErrInvalidProposal, "grant is already funded", nil)
+	}
+
+	proposal, exists := gm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	if gm.governanceState.Treasury.Balance < grant.Total {
+		return NewDAOError(ErrTreasuryInsufficient, "insufficient treasury funds for the grant", map[string]interface{}{
+			"balance": gm.governanceState.Treasury.Balance,
+			"total":   grant.Total,
+		})
+	}
+
+	now := time.Now().Unix()
+	gm.tokenState.Balances.Sub(executor.String(), uint64(tx.Fee))
+	gm.governanceState.Treasury.Balance -= grant.Total
+	grant.Escrowed = grant.Total
+	grant.Status = GrantStatusActive
+	grant.FundedAt = now
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// ProcessGrantMilestoneSubmitTx submits the deliverable of a milestone for
+// review
+func (gm *GrantManager) ProcessGrantMilestoneSubmitTx(tx *GrantMilestoneSubmitTx, applicant crypto.PublicKey) error {
+	grant, milestone, err := gm.activeMilestone(tx.GrantID, tx.Milestone)
+	if err != nil {
+		return err
+	}
+	if grant.Applicant.String() != applicant.String() {
+		return NewDAOError(ErrUnauthorized, "only the applicant can submit milestones", nil)
+	}
+	if milestone.Status != MilestoneStatusPending && milestone.Status != MilestoneStatusChangesRequested {
+		return NewDAOError(ErrInvalidProposal, "milestone is not awaiting delivery", nil)
+	}
+
+	gm.tokenState.Balances.Sub(applicant.String(), uint64(tx.Fee))
+	milestone.Status = MilestoneStatusSubmitted
+	milestone.Deliverable = tx.Deliverable
+	milestone.SubmittedAt = time.Now().Unix()
+
+	return nil
+}
+
+// ProcessGrantMilestoneReviewTx records the committee's review of a
+// submitted milestone, paying it out when approved
+func (gm *GrantManager) ProcessGrantMilestoneReviewTx(tx *GrantMilestoneReviewTx, reviewer crypto.PublicKey) error {
+	grant, milestone, err := gm.activeMilestone(tx.GrantID, tx.Milestone)
+	if err != nil {
+		return err
+	}
+	if grant.Committee.String() != reviewer.String() {
+		return NewDAOError(ErrUnauthorized, "only the review committee can review milestones", nil)
+	}
+	if milestone.Status != MilestoneStatusSubmitted {
+		return NewDAOError(ErrInvalidProposal, "milestone has not been submitted", nil)
+	}
+
+	now := time.Now().Unix()
+	gm.tokenState.Balances.Sub(reviewer.String(), uint64(tx.Fee))
+	milestone.Comment = tx.Comment
+	milestone.ReviewedAt = now
+
+	if !tx.Approve {
+		milestone.Status = MilestoneStatusChangesRequested
+		return nil
+	}
+
+	milestone.Status = MilestoneStatusPaid
+	milestone.PaidAt = now
+	grant.Escrowed -= milestone.Amount
+	grant.Paid += milestone.Amount
+	gm.tokenState.Balances.Add(grant.Applicant.String(), milestone.Amount)
+
+	if grant.Paid == grant.Total {
+		grant.Status = GrantStatusCompleted
+		grant.ClosedAt = now
+	}
+
+	return nil
+}
+
+// ProcessGrantCancelTx cancels an active grant, returning the unpaid funds
+// to the treasury. Either the applicant or the committee can cancel.
+func (gm *GrantManager) ProcessGrantCancelTx(tx *GrantCancelTx, canceller crypto.PublicKey) error {
+	grant, exists := gm.grants[tx.GrantID]
+	if !exists {
+		return ErrGrantNotFoundError
+	}
+	if grant.Status != GrantStatusActive {
+		return NewDAOError(ErrInvalidProposal, "only active grants can be cancelled", nil)
+	}
+
+	cancellerStr := canceller.String()
+	if grant.Applicant.String() != cancellerStr && grant.Committee.String() != cancellerStr {
+		return NewDAOError(ErrUnauthorized, "only the applicant or the review committee can cancel a grant", nil)
+	}
+
+	gm.tokenState.Balances.Sub(cancellerStr, uint64(tx.Fee))
+	gm.governanceState.Treasury.Balance += grant.Escrowed
+	gm.refunded += grant.Escrowed
+	grant.Escrowed = 0
+	grant.Status = GrantStatusCancelled
+	grant.ClosedAt = time.Now().Unix()
+
+	for _, milestone := range grant.Milestones {
+		if milestone.Status != MilestoneStatusPaid {
+			milestone.Status = MilestoneStatusCancelled
+		}
+	}
+
+	return nil
+}
+
+func (gm *GrantManager) activeMilestone(grantID types.Hash, index uint8) (*Grant, *GrantMilestone, error) {
+	grant, exists := gm.grants[grantID]
+	if !exists {
+		return nil, nil, ErrGrantNotFoundError
+	}
+	if grant.Status != GrantStatusActive {
+		return nil, nil, NewDAOError(ErrInvalidProposal, "grant is not active", nil)
+	}
+	if int(index) >= len(grant.Milestones) {
+		return nil, nil, NewDAOError(ErrInvalidProposal, "milestone does not exist", nil)
+	}
+
+	return grant, grant.Milestones[index], nil
+}
+
+// StatusOf returns the pipeline stage of a grant. Grants under vote are
+// rejected once their proposal fails.
+func (gm *GrantManager) StatusOf(grant *Grant) GrantStatus {
+	if grant.Status != GrantStatusProposed {
+		return grant.Status
+	}
+
+	if proposal, exists := gm.governanceState.Proposal(grant.ID); exists {
+		switch proposal.Status {
+		case ProposalStatusRejected, ProposalStatusCancelled:
+			return GrantStatusRejected
+		}
+	}
+	return grant.Status
+}
+
+// GetGrant returns a grant
+func (gm *GrantManager) GetGrant(id types.Hash) (*Grant, bool) {
+	grant, exists := gm.grants[id]
+	return grant, exists
+}
+
+// ListGrants returns the grants, optionally only those of a stage or an
+// applicant, newest first
+func (gm *GrantManager) ListGrants(status GrantStatus, applicant crypto.PublicKey) []*Grant {
+	grants := make([]*Grant, 0, len(gm.grants))
+	for _, grant := range gm.grants {
+		if status != "" && gm.StatusOf(grant) != status {
+			continue
+		}
+		if len(applicant) > 0 && grant.Applicant.String() != applicant.String() {
+			continue
+		}
+		grants = append(grants, grant)
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].CreatedAt != grants[j].CreatedAt {
+			return grants[i].CreatedAt > grants[j].CreatedAt
+		}
+		return grants[i].ID.String() < grants[j].ID.String()
+	})
+	return grants
+}
+
+// Pipeline summarises the grants by stage
+func (gm *GrantManager) Pipeline() *GrantPipeline {
+	pipeline := &GrantPipeline{Refunded: gm.refunded}
+
+	for _, grant := range gm.grants {
+		switch gm.StatusOf(grant) {
+		case GrantStatusProposed:
+			pipeline.Proposed++
+			pipeline.Requested += grant.Total
+		case GrantStatusRejected:
+			pipeline.Rejected++
+		case GrantStatusActive:
+			pipeline.Active++
+		case GrantStatusCompleted:
+			pipeline.Completed++
+		case GrantStatusCancelled:
+			pipeline.Cancelled++
+		}
+		pipeline.Escrowed += grant.Escrowed
+		pipeline.Paid += grant.Paid
+	}
+
+	return pipeline
+}
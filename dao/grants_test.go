@@ -0,0 +1,230 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGrantDAO(t *testing.T) (*DAO, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	applicant := crypto.GeneratePrivateKey().PublicKey()
+	committee := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		applicant.String(): 10000,
+		committee.String(): 10000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 50000
+
+	return dao, applicant, committee
+}
+
+func proposeGrant(t *testing.T, dao *DAO, applicant, committee crypto.PublicKey, grantID types.Hash) {
+	now := time.Now().Unix()
+	tx := &GrantProposalTx{
+		Fee:   200,
+		Title: "Mobile wallet",
+		Milestones: []GrantMilestoneSpec{
+			{Title: "Design", Amount: 2000},
+			{Title: "Beta release", Amount: 3000},
+		},
+		Committee:  committee,
+		VotingType: VotingTypeSimple,
+		StartTime:  now + 60,
+		EndTime:    now + 7*86400,
+		Threshold:  5100,
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, applicant, grantID))
+}
+
+func fundGrant(t *testing.T, dao *DAO, applicant, committee crypto.PublicKey, grantID types.Hash) *Grant {
+	proposeGrant(t, dao, applicant, committee, grantID)
+
+	proposal, err := dao.GetProposal(grantID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+
+	execute := &GrantExecuteTx{Fee: 100, ProposalID: grantID}
+	require.NoError(t, dao.ProcessDAOTransaction(execute, applicant, types.Hash{0xEE}))
+
+	grant, exists := dao.GetGrant(grantID)
+	require.True(t, exists)
+	return grant
+}
+
+func TestGrant_Funding(t *testing.T) {
+	dao, applicant, committee := setupGrantDAO(t)
+	grantID := types.Hash{0x01}
+	proposeGrant(t, dao, applicant, committee, grantID)
+
+	grant, exists := dao.GetGrant(grantID)
+	require.True(t, exists)
+	assert.Equal(t, GrantStatusProposed, grant.Status)
+	assert.Equal(t, uint64(5000), grant.Total)
+	assert.Equal(t, uint64(5000), dao.GetGrantPipeline().Requested)
+
+	// Grants are funded only once their proposal passes
+	execute := &GrantExecuteTx{Fee: 100, ProposalID: grantID}
+	assert.Error(t, dao.ProcessDAOTransaction(execute, applicant, types.Hash{0x02}))
+
+	proposal, err := dao.GetProposal(grantID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(execute, applicant, types.Hash{0x03}))
+
+	assert.Equal(t, GrantStatusActive, grant.Status)
+	assert.Equal(t, uint64(5000), grant.Escrowed)
+	assert.Equal(t, uint64(45000), dao.GovernanceState.Treasury.Balance)
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+	assert.Error(t, dao.ProcessDAOTransaction(execute, applicant, types.Hash{0x04}))
+
+	now := time.Now().Unix()
+	invalid := []*GrantProposalTx{
+		{Fee: 200, Title: "none", Committee: committee},
+		{Fee: 200, Title: "zero", Committee: committee, Milestones: []GrantMilestoneSpec{{Title: "Free"}}},
+		{Fee: 200, Title: "self", Committee: applicant, Milestones: []GrantMilestoneSpec{{Title: "Paid", Amount: 1}}},
+		{Fee: 200, Title: "nobody", Milestones: []GrantMilestoneSpec{{Title: "Paid", Amount: 1}}},
+	}
+	for i, tx := range invalid {
+		tx.VotingType, tx.StartTime, tx.EndTime, tx.Threshold = VotingTypeSimple, now+60, now+7*86400, 5100
+		assert.Error(t, dao.ProcessDAOTransaction(tx, applicant, types.Hash{0x10, byte(i)}), tx.Title)
+	}
+}
+
+func TestGrant_InsufficientTreasury(t *testing.T) {
+	dao, applicant, committee := setupGrantDAO(t)
+	grantID := types.Hash{0x01}
+	proposeGrant(t, dao, applicant, committee, grantID)
+
+	proposal, err := dao.GetProposal(grantID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	dao.GovernanceState.Treasury.Balance = 4000
+
+	execute := &GrantExecuteTx{Fee: 100, ProposalID: grantID}
+	assert.Error(t, dao.ProcessDAOTransaction(execute, applicant, types.Hash{0x02}))
+
+	grant, _ := dao.GetGrant(grantID)
+	assert.Equal(t, GrantStatusProposed, grant.Status)
+	assert.Equal(t, uint64(4000), dao.GovernanceState.Treasury.Balance)
+}
+
+func TestGrant_MilestonePayouts(t *testing.T) {
+	dao, applicant, committee := setupGrantDAO(t)
+	grant := fundGrant(t, dao, applicant, committee, types.Hash{0x01})
+	balance := dao.GetTokenBalance(applicant)
+
+	submit := &GrantMilestoneSubmitTx{Fee: 10, GrantID: grant.ID, Milestone: 0, Deliverable: "ipfs://designs"}
+	review := &GrantMilestoneReviewTx{Fee: 10, GrantID: grant.ID, Milestone: 0, Approve: true}
+
+	// Milestones are reviewed after submission and only by the committee
+	assert.Error(t, dao.ProcessDAOTransaction(review, committee, types.Hash{0x02}))
+	assert.Error(t, dao.ProcessDAOTransaction(submit, committee, types.Hash{0x03}))
+	require.NoError(t, dao.ProcessDAOTransaction(submit, applicant, types.Hash{0x04}))
+	assert.Error(t, dao.ProcessDAOTransaction(review, applicant, types.Hash{0x05}))
+
+	// Requested changes send the milestone back to the applicant
+	changes := &GrantMilestoneReviewTx{Fee: 10, GrantID: grant.ID, Milestone: 0, Comment: "Add the dark theme"}
+	require.NoError(t, dao.ProcessDAOTransaction(changes, committee, types.Hash{0x06}))
+	assert.Equal(t, MilestoneStatusChangesRequested, grant.Milestones[0].Status)
+	require.NoError(t, dao.ProcessDAOTransaction(submit, applicant, types.Hash{0x07}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(review, committee, types.Hash{0x08}))
+	assert.Equal(t, MilestoneStatusPaid, grant.Milestones[0].Status)
+	assert.Equal(t, balance-20+2000, dao.GetTokenBalance(applicant))
+	assert.Equal(t, uint64(3000), grant.Escrowed)
+
+	// A paid milestone cannot be paid again
+	assert.Error(t, dao.ProcessDAOTransaction(review, committee, types.Hash{0x09}))
+
+	submit.Milestone, review.Milestone = 1, 1
+	require.NoError(t, dao.ProcessDAOTransaction(submit, applicant, types.Hash{0x0A}))
+	require.NoError(t, dao.ProcessDAOTransaction(review, committee, types.Hash{0x0B}))
+	assert.Equal(t, GrantStatusCompleted, grant.Status)
+	assert.Zero(t, grant.Escrowed)
+	assert.Equal(t, uint64(5000), grant.Paid)
+
+	submit.Milestone = 2
+	assert.Error(t, dao.ProcessDAOTransaction(submit, applicant, types.Hash{0x0C}))
+}
+
+func TestGrant_CancelRefundsTreasury(t *testing.T) {
+	dao, applicant, committee := setupGrantDAO(t)
+	grant := fundGrant(t, dao, applicant, committee, types.Hash{0x01})
+
+	require.NoError(t, dao.ProcessDAOTransaction(&GrantMilestoneSubmitTx{Fee: 10, GrantID: grant.ID, Deliverable: "ipfs://designs"}, applicant, types.Hash{0x02}))
+	require.NoError(t, dao.ProcessDAOTransaction(&GrantMilestoneReviewTx{Fee: 10, GrantID: grant.ID, Approve: true}, committee, types.Hash{0x03}))
+
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(outsider.String(), 100)
+	cancel := &GrantCancelTx{Fee: 10, GrantID: grant.ID, Reason: "Team disbanded"}
+	assert.Error(t, dao.ProcessDAOTransaction(cancel, outsider, types.Hash{0x04}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(cancel, committee, types.Hash{0x05}))
+	assert.Equal(t, GrantStatusCancelled, grant.Status)
+	assert.Equal(t, MilestoneStatusPaid, grant.Milestones[0].Status)
+	assert.Equal(t, MilestoneStatusCancelled, grant.Milestones[1].Status)
+	assert.Equal(t, uint64(48000), dao.GovernanceState.Treasury.Balance)
+	assert.Error(t, dao.ProcessDAOTransaction(cancel, applicant, types.Hash{0x06}))
+
+	pipeline := dao.GetGrantPipeline()
+	assert.Equal(t, uint64(1), pipeline.Cancelled)
+	assert.Equal(t, uint64(2000), pipeline.Paid)
+	assert.Equal(t, uint64(3000), pipeline.Refunded)
+}
+
+func TestGrant_MultisigCommittee(t *testing.T) {
+	dao, applicant, _ := setupGrantDAO(t)
+
+	reviewers := []crypto.PublicKey{crypto.GeneratePrivateKey().PublicKey(), crypto.GeneratePrivateKey().PublicKey()}
+	for _, reviewer := range reviewers {
+		dao.TokenState.Balances.Set(reviewer.String(), 1000)
+	}
+	create := &MultisigCreateTx{Fee: 10, Name: "Grant reviewers", Owners: reviewers, Threshold: 2}
+	require.NoError(t, dao.ProcessDAOTransaction(create, reviewers[0], types.Hash{0xA0}))
+	committee, _ := dao.GetMultisigAccount(types.Hash{0xA0})
+	dao.TokenState.Balances.Set(committee.Account().String(), 100)
+
+	grant := fundGrant(t, dao, applicant, committee.Account(), types.Hash{0x01})
+	require.NoError(t, dao.ProcessDAOTransaction(&GrantMilestoneSubmitTx{Fee: 10, GrantID: grant.ID, Deliverable: "ipfs://designs"}, applicant, types.Hash{0x02}))
+
+	// One reviewer alone cannot pay the milestone
+	submit := &MultisigSubmitTx{
+		Fee:        10,
+		MultisigID: committee.ID,
+		Tx:         &GrantMilestoneReviewTx{Fee: 10, GrantID: grant.ID, Approve: true},
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(submit, reviewers[0], types.Hash{0x03}))
+	assert.Equal(t, MilestoneStatusSubmitted, grant.Milestones[0].Status)
+
+	sign := &MultisigSignTx{Fee: 10, MultisigID: committee.ID, TxID: types.Hash{0x03}}
+	require.NoError(t, dao.ProcessDAOTransaction(sign, reviewers[1], types.Hash{0x04}))
+	assert.Equal(t, MilestoneStatusPaid, grant.Milestones[0].Status)
+}
+
+func TestGrant_Pipeline(t *testing.T) {
+	dao, applicant, committee := setupGrantDAO(t)
+	fundGrant(t, dao, applicant, committee, types.Hash{0x01})
+	proposeGrant(t, dao, applicant, committee, types.Hash{0x02})
+	proposeGrant(t, dao, applicant, committee, types.Hash{0x03})
+
+	rejected, err := dao.GetProposal(types.Hash{0x03})
+	require.NoError(t, err)
+	rejected.Status = ProposalStatusRejected
+
+	pipeline := dao.GetGrantPipeline()
+	assert.Equal(t, uint64(1), pipeline.Active)
+	assert.Equal(t, uint64(1), pipeline.Proposed)
+	assert.Equal(t, uint64(1), pipeline.Rejected)
+	assert.Equal(t, uint64(5000), pipeline.Escrowed)
+
+	assert.Len(t, dao.ListGrants("", nil), 3)
+	assert.Len(t, dao.ListGrants(GrantStatusRejected, nil), 1)
+	assert.Len(t, dao.ListGrants(GrantStatusActive, applicant), 1)
+	assert.Empty(t, dao.ListGrants("", committee))
+}
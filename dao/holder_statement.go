@@ -0,0 +1,198 @@
+package dao
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Holder statement entry types besides inflows and outflows
+const (
+	StatementStakingReward = "staking_reward"
+	StatementVestingClaim  = "vesting_claim"
+	StatementFee           = "fee"
+)
+
+// HolderStatement summarizes what moved through an address's token balance
+// in a calendar year, for tax and accounting. Transfers in and payouts are
+// inflows, transfers out, burns and rage quits are outflows, and claimed
+// staking rewards and vesting are reported apart from both. Tokens moved in
+// and out of stake stay the holder's and are left out.
+type HolderStatement struct {
+	Address        string                 `json:"address"`
+	Year           int                    `json:"year"`
+	From           int64                  `json:"from"`
+	To             int64                  `json:"to"` // Exclusive
+	TotalInflows   uint64                 `json:"total_inflows"`
+	TotalOutflows  uint64                 `json:"total_outflows"`
+	StakingRewards uint64                 `json:"staking_rewards"`
+	VestingClaims  uint64                 `json:"vesting_claims"`
+	FeesPaid       uint64                 `json:"fees_paid"`
+	NetFlow        int64                  `json:"net_flow"`
+	Entries        []HolderStatementEntry `json:"entries"` // Oldest first
+}
+
+// HolderStatementEntry is a line of a holder statement
+type HolderStatementEntry struct {
+	Timestamp    int64  `json:"timestamp"`
+	Type         string `json:"type"`
+	Category     string `json:"category"` // Activity type of the transaction
+	Counterparty string `json:"counterparty,omitempty"`
+	Reference    string `json:"reference"` // Transaction hash
+	Amount       uint64 `json:"amount"`
+}
+
+// IsClaimActivity reports whether transactions of an activity type pay the
+// sender out of DAO state
+func IsClaimActivity(txType string) bool {
+	switch txType {
+	case ActivityTypeClaimRewards, ActivityTypeClaimCommission, ActivityTypeVestingClaim, ActivityTypeRPGFClaim:
+		return true
+	}
+	return false
+}
+
+// holderEntryType returns how a record moved the balance of the address it
+// is indexed under, or "" when it did not
+func holderEntryType(record ActivityRecord) string {
+	if record.Amount == 0 {
+		return ""
+	}
+
+	switch record.Role {
+	case ActivityRoleRecipient:
+		switch record.TxType {
+		case ActivityTypeTokenTransfer, ActivityTypeTokenTransferFrom, ActivityTypeTreasury,
+			ActivityTypeTokenMint, ActivityTypeDistribution:
+			return StatementInflow
+		}
+	case ActivityRoleOwner:
+		if record.TxType == ActivityTypeTokenTransferFrom {
+			return StatementOutflow
+		}
+	case ActivityRoleSender:
+		switch record.TxType {
+		case ActivityTypeTokenTransfer, ActivityTypeTokenBurn, ActivityTypeRageQuit:
+			return StatementOutflow
+		case ActivityTypeClaimRewards, ActivityTypeClaimCommission:
+			return StatementStakingReward
+		case ActivityTypeVestingClaim:
+			return StatementVestingClaim
+		case ActivityTypeRPGFClaim:
+			return StatementInflow
+		}
+	}
+	return ""
+}
+
+// ParseStatementYear returns the UTC bounds of a year such as "2024", the end
+// exclusive
+func ParseStatementYear(year string) (int, int64, int64, error) {
+	start, err := time.Parse("2006", year)
+	if err != nil {
+		return 0, 0, 0, NewDAOError(ErrInvalidTimeframe, "year must be a year such as 2024", map[string]interface{}{"year": year})
+	}
+	return start.Year(), start.Unix(), start.AddDate(1, 0, 0).Unix(), nil
+}
+
+// GenerateHolderStatement builds the statement of an address for a year from
+// its transaction history
+func (ai *ActivityIndex) GenerateHolderStatement(address, year string, now int64) (*HolderStatement, error) {
+	y, from, to, err := ParseStatementYear(year)
+	if err != nil {
+		return nil, err
+	}
+	if from > now {
+		return nil, NewDAOError(ErrInvalidTimeframe, "year has not started", map[string]interface{}{"year": year})
+	}
+
+	statement := &HolderStatement{
+		Address: address,
+		Year:    y,
+		From:    from,
+		To:      to,
+		Entries: make([]HolderStatementEntry, 0),
+	}
+
+	for _, record := range ai.GetActivityBetween(address, from, to) {
+		entry := HolderStatementEntry{
+			Timestamp:    record.Timestamp,
+			Category:     record.TxType,
+			Counterparty: record.Counterparty,
+			Reference:    record.TxHash.String(),
+		}
+
+		if entryType := holderEntryType(record); entryType != "" {
+			entry.Type = entryType
+			entry.Amount = record.Amount
+			statement.Entries = append(statement.Entries, entry)
+
+			switch entryType {
+			case StatementInflow:
+				statement.TotalInflows += record.Amount
+			case StatementOutflow:
+				statement.TotalOutflows += record.Amount
+			case StatementStakingReward:
+				statement.StakingRewards += record.Amount
+			case StatementVestingClaim:
+				statement.VestingClaims += record.Amount
+			}
+		}
+
+		if record.Fee > 0 {
+			entry.Type = StatementFee
+			entry.Amount = record.Fee
+			statement.Entries = append(statement.Entries, entry)
+			statement.FeesPaid += record.Fee
+		}
+	}
+
+	statement.NetFlow = int64(statement.TotalInflows+statement.StakingRewards+statement.VestingClaims) -
+		int64(statement.TotalOutflows+statement.FeesPaid)
+
+	return statement, nil
+}
+
+// WriteCSV writes the entries of the statement as CSV, followed by a row per
+// total
+func (s *HolderStatement) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	format := func(timestamp int64) string {
+		return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	}
+
+	rows := [][]string{{"date", "type", "category", "counterparty", "reference", "amount"}}
+	for _, entry := range s.Entries {
+		rows = append(rows, []string{
+			format(entry.Timestamp),
+			entry.Type,
+			entry.Category,
+			entry.Counterparty,
+			entry.Reference,
+			strconv.FormatUint(entry.Amount, 10),
+		})
+	}
+
+	end := format(s.To)
+	totals := []struct {
+		name   string
+		amount string
+	}{
+		{"total_inflows", strconv.FormatUint(s.TotalInflows, 10)},
+		{"total_outflows", strconv.FormatUint(s.TotalOutflows, 10)},
+		{"staking_rewards", strconv.FormatUint(s.StakingRewards, 10)},
+		{"vesting_claims", strconv.FormatUint(s.VestingClaims, 10)},
+		{"fees_paid", strconv.FormatUint(s.FeesPaid, 10)},
+		{"net_flow", strconv.FormatInt(s.NetFlow, 10)},
+	}
+	for _, total := range totals {
+		rows = append(rows, []string{end, total.name, "", "", "", total.amount})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	return nil
+}
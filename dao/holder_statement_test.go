@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolderStatement(t *testing.T) {
+	dao, validator, alice, _ := setupValidatorDAO(t)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000}, validator, types.Hash{0x01}))
+	dao.CollectBlockFees(validator, 300, 1)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&ClaimCommissionTx{Fee: 10}, validator, types.Hash{0x02}))
+	require.NoError(t, dao.ProcessDAOTransaction(&TokenTransferTx{Fee: 5, Recipient: alice, Amount: 1000}, validator, types.Hash{0x03}))
+	require.NoError(t, dao.ProcessDAOTransaction(&TokenTransferTx{Fee: 5, Recipient: validator, Amount: 400}, alice, types.Hash{0x04}))
+	require.NoError(t, dao.ProcessDAOTransaction(&TokenBurnTx{Fee: 5, Amount: 100, Reason: "Supply reduction"}, validator, types.Hash{0x06}))
+
+	// Claims are indexed with the amount they paid out
+	records, _ := dao.GetMemberActivity(validator, []string{ActivityTypeClaimCommission}, 0, 0)
+	require.Len(t, records, 1)
+	assert.Equal(t, uint64(300), records[0].Amount)
+	assert.Equal(t, uint64(10), records[0].Fee)
+
+	year := strconv.Itoa(time.Now().UTC().Year())
+	statement, err := dao.GetHolderStatement(validator, year)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(400), statement.TotalInflows)
+	assert.Equal(t, uint64(1100), statement.TotalOutflows)
+	assert.Equal(t, uint64(300), statement.StakingRewards)
+	assert.Zero(t, statement.VestingClaims)
+	assert.Equal(t, uint64(30), statement.FeesPaid)
+	assert.Equal(t, int64(400+300-1100-30), statement.NetFlow)
+	assert.Equal(t, int64(statement.TotalInflows+statement.StakingRewards)-int64(statement.TotalOutflows+statement.FeesPaid),
+		int64(dao.GetTokenBalance(validator))-10000)
+
+	// The recipient sees the transfer as an inflow
+	aliceStatement, err := dao.GetHolderStatement(alice, year)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), aliceStatement.TotalInflows)
+	assert.Equal(t, uint64(400), aliceStatement.TotalOutflows)
+
+	// Other years have no entries
+	previous, err := dao.GetHolderStatement(validator, strconv.Itoa(time.Now().UTC().Year()-1))
+	require.NoError(t, err)
+	assert.Empty(t, previous.Entries)
+
+	_, err = dao.GetHolderStatement(validator, strconv.Itoa(time.Now().UTC().Year()+1))
+	assert.Error(t, err)
+	_, err = dao.GetHolderStatement(validator, "last year")
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, "date,type,category,counterparty,reference,amount", lines[0])
+	assert.Len(t, lines, 1+len(statement.Entries)+6)
+	assert.True(t, strings.HasSuffix(lines[len(lines)-1], ",net_flow,,,,"+strconv.FormatInt(statement.NetFlow, 10)))
+}
@@ -0,0 +1,283 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxKPIsPerProposal caps the number of KPIs a funding proposal can carry
+const MaxKPIsPerProposal = 10
+
+// KPITarget is a measurable outcome promised by a funding proposal
+type KPITarget struct {
+	Name   string
+	Unit   string
+	Target uint64
+}
+
+// KPI tracks a promised outcome against what was actually delivered
+type KPI struct {
+	Name     string `json:"name"`
+	Unit     string `json:"unit"`
+	Target   uint64 `json:"target"`
+	Actual   uint64 `json:"actual"`
+	Measured bool   `json:"measured"`
+}
+
+// Achievement returns the delivered share of the target, capped at 1
+func (k *KPI) Achievement() float64 {
+	if !k.Measured || k.Target == 0 {
+		return 0
+	}
+	if k.Actual >= k.Target {
+		return 1
+	}
+	return float64(k.Actual) / float64(k.Target)
+}
+
+// ImpactReview is a post-execution measurement of a funded proposal
+type ImpactReview struct {
+	Reviewer   crypto.PublicKey
+	Actuals    map[string]uint64
+	ReportHash types.Hash // IPFS hash of the review report
+	ReviewedAt int64
+}
+
+// FundingImpact links a treasury proposal to its KPIs and reviews
+type FundingImpact struct {
+	ProposalID types.Hash
+	Category   string
+	Grantee    crypto.PublicKey
+	Amount     uint64
+	KPIs       []*KPI
+	Reviews    []*ImpactReview
+	AttachedAt int64
+}
+
+// Reviewed reports whether at least one review has measured the KPIs
+func (f *FundingImpact) Reviewed() bool {
+	return len(f.Reviews) > 0
+}
+
+// Achievement returns the average achievement across all KPIs
+func (f *FundingImpact) Achievement() float64 {
+	if len(f.KPIs) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, kpi := range f.KPIs {
+		total += kpi.Achievement()
+	}
+	return total / float64(len(f.KPIs))
+}
+
+// ImpactMetrics aggregates cost-effectiveness over a group of funded proposals
+type ImpactMetrics struct {
+	FundedProposals    uint64  `json:"funded_proposals"`
+	ReviewedProposals  uint64  `json:"reviewed_proposals"`
+	TotalFunded        uint64  `json:"total_funded"`
+	ReviewedFunding    uint64  `json:"reviewed_funding"`
+	DeliveredValue     uint64  `json:"delivered_value"`     // Reviewed funding weighted by achievement
+	AverageAchievement float64 `json:"average_achievement"` // Mean achievement of reviewed proposals
+	CostEffectiveness  float64 `json:"cost_effectiveness"`  // Delivered value per token of reviewed funding
+}
+
+// PublicGoodsAnalytics reports cost-effectiveness per category and per grantee
+type PublicGoodsAnalytics struct {
+	Overall    ImpactMetrics             `json:"overall"`
+	ByCategory map[string]*ImpactMetrics `json:"by_category"`
+	ByGrantee  map[string]*ImpactMetrics `json:"by_grantee"`
+}
+
+// add folds a funded proposal into the metrics
+func (m *ImpactMetrics) add(impact *FundingImpact) {
+	m.FundedProposals++
+	m.TotalFunded += impact.Amount
+
+	if !impact.Reviewed() {
+		return
+	}
+
+	achievement := impact.Achievement()
+	m.AverageAchievement = (m.AverageAchievement*float64(m.ReviewedProposals) + achievement) / float64(m.ReviewedProposals+1)
+	m.ReviewedProposals++
+	m.ReviewedFunding += impact.Amount
+	m.DeliveredValue += uint64(float64(impact.Amount) * achievement)
+
+	if m.ReviewedFunding > 0 {
+		m.CostEffectiveness = float64(m.DeliveredValue) / float64(m.ReviewedFunding)
+	}
+}
+
+// ImpactTracker records KPIs and post-execution reviews of funding proposals
+type ImpactTracker struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	securityManager *SecurityManager
+	impacts         map[types.Hash]*FundingImpact
+}
+
+// NewImpactTracker creates a new impact tracker
+func NewImpactTracker(governanceState *GovernanceState, tokenState *GovernanceToken, securityManager *SecurityManager) *ImpactTracker {
+	return &ImpactTracker{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		securityManager: securityManager,
+		impacts:         make(map[types.Hash]*FundingImpact),
+	}
+}
+
+// ProcessFundingKPITx attaches KPIs to a treasury proposal before it is decided
+func (it *ImpactTracker) ProcessFundingKPITx(tx *FundingKPITx, from crypto.PublicKey) error {
+	proposal, exists := it.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+
+	if proposal.ProposalType != ProposalTypeTreasury {
+		return NewDAOError(ErrInvalidProposal, "KPIs can only be attached to treasury proposals", nil)
+	}
+
+	if proposal.Creator.String() != from.String() {
+		return NewDAOError(ErrUnauthorized, "only the proposal creator can attach KPIs", nil)
+	}
+
+	if proposal.Status != ProposalStatusPending && proposal.Status != ProposalStatusActive {
+		return NewDAOError(ErrInvalidProposal, "KPIs must be attached before the proposal is decided", nil)
+	}
+
+	if _, exists := it.impacts[tx.ProposalID]; exists {
+		return NewDAOError(ErrInvalidProposal, "proposal already has KPIs attached", nil)
+	}
+
+	kpis := make([]*KPI, len(tx.KPIs))
+	for i, target := range tx.KPIs {
+		kpis[i] = &KPI{Name: target.Name, Unit: target.Unit, Target: target.Target}
+	}
+
+	it.tokenState.Balances.Sub(from.String(), uint64(tx.Fee))
+	it.impacts[tx.ProposalID] = &FundingImpact{
+		ProposalID: tx.ProposalID,
+		Category:   tx.Category,
+		Grantee:    tx.Grantee,
+		Amount:     tx.Amount,
+		KPIs:       kpis,
+		Reviews:    make([]*ImpactReview, 0),
+		AttachedAt: time.Now().Unix(),
+	}
+
+	return nil
+}
+
+// ProcessImpactReviewTx records measured KPI actuals for an executed proposal.
+// Later reviews supersede the actuals of earlier ones.
+func (it *ImpactTracker) ProcessImpactReviewTx(tx *ImpactReviewTx, reviewer crypto.PublicKey) error {
+	impact, exists := it.impacts[tx.ProposalID]
+	if !exists {
+		return NewDAOError(ErrInvalidProposal, "proposal has no KPIs attached", nil)
+	}
+
+	proposal, exists := it.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+
+	if proposal.Status != ProposalStatusExecuted {
+		return NewDAOError(ErrInvalidProposal, "impact reviews require an executed proposal", nil)
+	}
+
+	if !it.securityManager.HasPermission(reviewer, PermissionAuditAccess) {
+		return NewDAOError(ErrUnauthorized, "impact reviews require audit access", nil)
+	}
+
+	if impact.Grantee.String() == reviewer.String() {
+		return NewDAOError(ErrUnauthorized, "grantees cannot review their own funding", nil)
+	}
+
+	kpis := make(map[string]*KPI, len(impact.KPIs))
+	for _, kpi := range impact.KPIs {
+		kpis[kpi.Name] = kpi
+	}
+	for name := range tx.Actuals {
+		if _, exists := kpis[name]; !exists {
+			return NewDAOError(ErrInvalidProposal, "unknown KPI", map[string]interface{}{"kpi": name})
+		}
+	}
+
+	for name, actual := range tx.Actuals {
+		kpis[name].Actual = actual
+		kpis[name].Measured = true
+	}
+
+	actuals := make(map[string]uint64, len(tx.Actuals))
+	for name, actual := range tx.Actuals {
+		actuals[name] = actual
+	}
+
+	it.tokenState.Balances.Sub(reviewer.String(), uint64(tx.Fee))
+	impact.Reviews = append(impact.Reviews, &ImpactReview{
+		Reviewer:   reviewer,
+		Actuals:    actuals,
+		ReportHash: tx.ReportHash,
+		ReviewedAt: time.Now().Unix(),
+	})
+
+	return nil
+}
+
+// GetFundingImpact returns the KPIs and reviews of a funding proposal
+func (it *ImpactTracker) GetFundingImpact(proposalID types.Hash) (*FundingImpact, bool) {
+	impact, exists := it.impacts[proposalID]
+	return impact, exists
+}
+
+// ListFundingImpacts returns all tracked funding proposals, oldest first
+func (it *ImpactTracker) ListFundingImpacts() []*FundingImpact {
+	impacts := make([]*FundingImpact, 0, len(it.impacts))
+	for _, impact := range it.impacts {
+		impacts = append(impacts, impact)
+	}
+
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].AttachedAt != impacts[j].AttachedAt {
+			return impacts[i].AttachedAt < impacts[j].AttachedAt
+		}
+		return impacts[i].ProposalID.String() < impacts[j].ProposalID.String()
+	})
+
+	return impacts
+}
+
+// GetPublicGoodsAnalytics computes cost-effectiveness per category and per grantee.
+// Only executed proposals count as funded.
+func (it *ImpactTracker) GetPublicGoodsAnalytics() *PublicGoodsAnalytics {
+	analytics := &PublicGoodsAnalytics{
+		ByCategory: make(map[string]*ImpactMetrics),
+		ByGrantee:  make(map[string]*ImpactMetrics),
+	}
+
+	for _, impact := range it.ListFundingImpacts() {
+		proposal, exists := it.governanceState.Proposal(impact.ProposalID)
+		if !exists || proposal.Status != ProposalStatusExecuted {
+			continue
+		}
+
+		grantee := impact.Grantee.String()
+		if analytics.ByCategory[impact.Category] == nil {
+			analytics.ByCategory[impact.Category] = &ImpactMetrics{}
+		}
+		if analytics.ByGrantee[grantee] == nil {
+			analytics.ByGrantee[grantee] = &ImpactMetrics{}
+		}
+
+		analytics.Overall.add(impact)
+		analytics.ByCategory[impact.Category].add(impact)
+		analytics.ByGrantee[grantee].add(impact)
+	}
+
+	return analytics
+}
@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupImpactDAO(t *testing.T) (*DAO, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	auditor := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		creator.String(): 10000,
+		auditor.String(): 10000,
+	}))
+	require.NoError(t, dao.InitializeFounderRoles([]crypto.PublicKey{auditor}))
+
+	return dao, creator, auditor
+}
+
+func addFundingProposal(dao *DAO, id types.Hash, creator crypto.PublicKey) *Proposal {
+	now := time.Now().Unix()
+	proposal := &Proposal{
+		ID: id, Creator: creator, Title: "Grant", ProposalType: ProposalTypeTreasury,
+		StartTime: now, EndTime: now + 3600, Status: ProposalStatusActive,
+	}
+	dao.GovernanceState.Proposals[id] = proposal
+	return proposal
+}
+
+func attachKPIs(t *testing.T, dao *DAO, proposalID types.Hash, creator, grantee crypto.PublicKey, category string, amount uint64) {
+	tx := &FundingKPITx{
+		Fee: 10, ProposalID: proposalID, Category: category, Grantee: grantee, Amount: amount,
+		KPIs: []KPITarget{
+			{Name: "users", Unit: "accounts", Target: 1000},
+			{Name: "audits", Unit: "reports", Target: 2},
+		},
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, creator, types.Hash{0xF0, proposalID[0]}))
+}
+
+func TestImpact_AttachKPIs(t *testing.T) {
+	dao, creator, auditor := setupImpactDAO(t)
+	grantee := crypto.GeneratePrivateKey().PublicKey()
+	proposalID := types.Hash{0x01}
+	addFundingProposal(dao, proposalID, creator)
+
+	// Only the creator can attach KPIs
+	tx := &FundingKPITx{Fee: 10, ProposalID: proposalID, Category: "tooling", Grantee: grantee, Amount: 500,
+		KPIs: []KPITarget{{Name: "users", Target: 100}}}
+	err := dao.ProcessDAOTransaction(tx, auditor, types.Hash{0xA1})
+	require.Error(t, err)
+	assert.Equal(t, ErrUnauthorized, err.(*DAOError).Code)
+
+	attachKPIs(t, dao, proposalID, creator, grantee, "tooling", 500)
+	impact, exists := dao.GetFundingImpact(proposalID)
+	require.True(t, exists)
+	assert.Len(t, impact.KPIs, 2)
+	assert.False(t, impact.Reviewed())
+
+	// KPIs are attached only once
+	require.Error(t, dao.ProcessDAOTransaction(tx, creator, types.Hash{0xA2}))
+
+	// Non-treasury proposals carry no funding KPIs
+	general := addFundingProposal(dao, types.Hash{0x02}, creator)
+	general.ProposalType = ProposalTypeGeneral
+	tx.ProposalID = general.ID
+	require.Error(t, dao.ProcessDAOTransaction(tx, creator, types.Hash{0xA3}))
+
+	// Duplicate KPI names are rejected by validation
+	addFundingProposal(dao, types.Hash{0x03}, creator)
+	tx = &FundingKPITx{Fee: 10, ProposalID: types.Hash{0x03}, Category: "tooling", Grantee: grantee, Amount: 500,
+		KPIs: []KPITarget{{Name: "users", Target: 100}, {Name: "users", Target: 200}}}
+	require.Error(t, dao.ProcessDAOTransaction(tx, creator, types.Hash{0xA4}))
+}
+
+func TestImpact_ReviewRequiresExecutionAndAuditAccess(t *testing.T) {
+	dao, creator, auditor := setupImpactDAO(t)
+	grantee := crypto.GeneratePrivateKey().PublicKey()
+	proposalID := types.Hash{0x01}
+	proposal := addFundingProposal(dao, proposalID, creator)
+	attachKPIs(t, dao, proposalID, creator, grantee, "tooling", 500)
+
+	review := &ImpactReviewTx{Fee: 10, ProposalID: proposalID, Actuals: map[string]uint64{"users": 500}}
+	require.Error(t, dao.ProcessDAOTransaction(review, auditor, types.Hash{0xB1}))
+
+	proposal.Status = ProposalStatusExecuted
+
+	err := dao.ProcessDAOTransaction(review, creator, types.Hash{0xB2})
+	require.Error(t, err)
+	assert.Equal(t, ErrUnauthorized, err.(*DAOError).Code)
+
+	unknown := &ImpactReviewTx{Fee: 10, ProposalID: proposalID, Actuals: map[string]uint64{"downloads": 1}}
+	require.Error(t, dao.ProcessDAOTransaction(unknown, auditor, types.Hash{0xB3}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(review, auditor, types.Hash{0xB4}))
+	impact, _ := dao.GetFundingImpact(proposalID)
+	require.Len(t, impact.Reviews, 1)
+	assert.InDelta(t, 0.25, impact.Achievement(), 1e-9)
+
+	// Later reviews supersede earlier actuals
+	followUp := &ImpactReviewTx{Fee: 10, ProposalID: proposalID, Actuals: map[string]uint64{"users": 1500, "audits": 1}}
+	require.NoError(t, dao.ProcessDAOTransaction(followUp, auditor, types.Hash{0xB5}))
+	assert.InDelta(t, 0.75, impact.Achievement(), 1e-9)
+}
+
+func TestImpact_PublicGoodsAnalytics(t *testing.T) {
+	dao, creator, auditor := setupImpactDAO(t)
+	granteeA := crypto.GeneratePrivateKey().PublicKey()
+	granteeB := crypto.GeneratePrivateKey().PublicKey()
+
+	fundings := []struct {
+		id      types.Hash
+		grantee crypto.PublicKey
+		amount  uint64
+		actuals map[string]uint64
+	}{
+		{types.Hash{0x01}, granteeA, 1000, map[string]uint64{"users": 1000, "audits": 2}},
+		{types.Hash{0x02}, granteeB, 3000, map[string]uint64{"users": 0, "audits": 1}},
+		{types.Hash{0x03}, granteeA, 2000, nil}, // Executed but not yet reviewed
+	}
+
+	for i, funding := range fundings {
+		proposal := addFundingProposal(dao, funding.id, creator)
+		attachKPIs(t, dao, funding.id, creator, funding.grantee, "tooling", funding.amount)
+		proposal.Status = ProposalStatusExecuted
+		if funding.actuals != nil {
+			review := &ImpactReviewTx{Fee: 10, ProposalID: funding.id, Actuals: funding.actuals}
+			require.NoError(t, dao.ProcessDAOTransaction(review, auditor, types.Hash{0xC0, byte(i)}))
+		}
+	}
+
+	// Proposals that never executed are not counted as funded
+	addFundingProposal(dao, types.Hash{0x04}, creator)
+	attachKPIs(t, dao, types.Hash{0x04}, creator, granteeB, "research", 9000)
+
+	analytics := dao.GetPublicGoodsAnalytics()
+	assert.Equal(t, uint64(3), analytics.Overall.FundedProposals)
+	assert.Equal(t, uint64(2), analytics.Overall.ReviewedProposals)
+	assert.Equal(t, uint64(6000), analytics.Overall.TotalFunded)
+	assert.Equal(t, uint64(4000), analytics.Overall.ReviewedFunding)
+	assert.Equal(t, uint64(1000+750), analytics.Overall.DeliveredValue)
+	assert.InDelta(t, 0.625, analytics.Overall.AverageAchievement, 1e-9)
+	assert.InDelta(t, 1750.0/4000.0, analytics.Overall.CostEffectiveness, 1e-9)
+
+	require.Contains(t, analytics.ByCategory, "tooling")
+	assert.NotContains(t, analytics.ByCategory, "research")
+
+	a := analytics.ByGrantee[granteeA.String()]
+	require.NotNil(t, a)
+	assert.Equal(t, uint64(2), a.FundedProposals)
+	assert.InDelta(t, 1.0, a.CostEffectiveness, 1e-9)
+
+	b := analytics.ByGrantee[granteeB.String()]
+	require.NotNil(t, b)
+	assert.InDelta(t, 0.25, b.CostEffectiveness, 1e-9)
+}
@@ -1,49 +1,69 @@
 package dao
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/BOCK-CHAIN/BockChain/types"
 	shell "github.com/ipfs/go-ipfs-api"
 )
 
-// IPFSClient wraps the IPFS shell client with DAO-specific functionality
+// IPFSClient stores proposal metadata and documents in a ContentStore, an
+// IPFS node unless configured otherwise, with DAO-specific functionality
 type IPFSClient struct {
-	shell   *shell.Shell
-	timeout time.Duration
+	store         ContentStore
+	shell         *shell.Shell // Set when the store is an IPFS node
+	ids           map[types.Hash]string
+	idsMu         sync.RWMutex
+	timeout       time.Duration
+	mirror        *IPFSMirror
+	fallbackDelay time.Duration
+	pins          *PinReconciler
+	gateways      []string
+	gatewayClient *http.Client
+	cache         *MetadataCache
 }
 
 // NewIPFSClient creates a new IPFS client instance
 func NewIPFSClient(nodeURL string) *IPFSClient {
-	if nodeURL == "" {
-		nodeURL = "localhost:5001" // Default IPFS API endpoint
-	}
+	return NewIPFSClientWithStore(NewIPFSStore(nodeURL))
+}
 
-	return &IPFSClient{
-		shell:   shell.NewShell(nodeURL),
+// NewIPFSClientWithStore creates a client keeping content in store
+func NewIPFSClientWithStore(store ContentStore) *IPFSClient {
+	client := &IPFSClient{
+		store:   store,
+		ids:     make(map[types.Hash]string),
 		timeout: 30 * time.Second,
 	}
+	if ipfs, ok := store.(*IPFSStore); ok {
+		client.shell = ipfs.shell
+	}
+	return client
 }
 
 // ProposalMetadata represents the metadata structure for proposals
 type ProposalMetadata struct {
-	Title       string              `json:"title"`
-	Description string              `json:"description"`
-	Details     string              `json:"details,omitempty"`
-	Documents   []DocumentReference `json:"documents,omitempty"`
-	Links       []LinkReference     `json:"links,omitempty"`
-	Tags        []string            `json:"tags,omitempty"`
-	Version     string              `json:"version"`
-	CreatedAt   int64               `json:"created_at"`
-	UpdatedAt   int64               `json:"updated_at,omitempty"`
-	Checksum    string              `json:"checksum"`
+	// SchemaVersion is the metadata format, see CurrentMetadataSchemaVersion.
+	// Version is the revision of the content.
+	SchemaVersion int                 `json:"schema_version,omitempty"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Details       string              `json:"details,omitempty"`
+	Documents     []DocumentReference `json:"documents,omitempty"`
+	Links         []LinkReference     `json:"links,omitempty"`
+	Tags          []string            `json:"tags,omitempty"`
+	Version       string              `json:"version"`
+	CreatedAt     int64               `json:"created_at"`
+	UpdatedAt     int64               `json:"updated_at,omitempty"`
+	Checksum      string              `json:"checksum"`
 }
 
 // DocumentReference represents a reference to a document stored on IPFS
@@ -53,6 +73,8 @@ type DocumentReference struct {
 	Hash        string `json:"hash"`
 	Size        int64  `json:"size"`
 	MimeType    string `json:"mime_type,omitempty"`
+	// Encryption is set when the stored content is encrypted
+	Encryption *DocumentEncryption `json:"encryption,omitempty"`
 }
 
 // LinkReference represents an external link reference
@@ -74,6 +96,9 @@ func (c *IPFSClient) UploadProposalMetadata(metadata *ProposalMetadata) (types.H
 	if metadata.Version == "" {
 		metadata.Version = "1.0"
 	}
+	metadata.SchemaVersion = CurrentMetadataSchemaVersion
+	metadata.Tags = normalizeTags(metadata.Tags)
+	metadata.Checksum = ""
 
 	// Serialize metadata to JSON
 	jsonData, err := json.MarshalIndent(metadata, "", "  ")
@@ -91,31 +116,45 @@ func (c *IPFSClient) UploadProposalMetadata(metadata *ProposalMetadata) (types.H
 		return types.Hash{}, fmt.Errorf("failed to marshal metadata with checksum: %w", err)
 	}
 
-	// Upload to IPFS
-	reader := bytes.NewReader(jsonData)
-	ipfsHash, err := c.shell.Add(reader)
+	if _, err := ValidateProposalMetadata(jsonData); err != nil {
+		return types.Hash{}, fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	// Upload to the content store
+	ipfsHash, err := c.put(jsonData)
 	if err != nil {
-		return types.Hash{}, fmt.Errorf("failed to upload to IPFS: %w", err)
+		return types.Hash{}, fmt.Errorf("failed to upload to %s: %w", c.store.Name(), err)
 	}
 
+	c.mirrorContent(ipfsHash, jsonData)
+	c.replicatePin(ipfsHash)
+
 	// Convert IPFS hash to types.Hash
-	return c.ipfsHashToTypesHash(ipfsHash), nil
+	metadataHash := c.ipfsHashToTypesHash(ipfsHash)
+	if c.cache != nil {
+		c.cache.Put(metadataHash, metadata)
+	}
+	return metadataHash, nil
 }
 
-// RetrieveProposalMetadata retrieves proposal metadata from IPFS
+// RetrieveProposalMetadata retrieves proposal metadata from IPFS, validated
+// against the schema it was written with and migrated to the current schema
 func (c *IPFSClient) RetrieveProposalMetadata(hash types.Hash) (*ProposalMetadata, error) {
+	if c.cache != nil {
+		if metadata, ok := c.cache.Get(hash); ok {
+			return metadata, nil
+		}
+	}
 
 	ipfsHash := c.typesHashToIPFSHash(hash)
 
-	reader, err := c.shell.Cat(ipfsHash)
+	data, err := c.cat(ipfsHash, hash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve from IPFS: %w", err)
 	}
-	defer reader.Close()
 
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read IPFS data: %w", err)
+	if _, err := ValidateProposalMetadata(data); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
 	}
 
 	var metadata ProposalMetadata
@@ -128,18 +167,27 @@ func (c *IPFSClient) RetrieveProposalMetadata(hash types.Hash) (*ProposalMetadat
 		return nil, fmt.Errorf("metadata verification failed: %w", err)
 	}
 
+	if err := MigrateProposalMetadata(&metadata); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		c.cache.Put(hash, &metadata)
+	}
 	return &metadata, nil
 }
 
 // UploadDocument uploads a document to IPFS and returns its reference
 func (c *IPFSClient) UploadDocument(name string, data []byte, mimeType string) (*DocumentReference, error) {
 
-	reader := bytes.NewReader(data)
-	ipfsHash, err := c.shell.Add(reader)
+	ipfsHash, err := c.put(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload document to IPFS: %w", err)
+		return nil, fmt.Errorf("failed to upload document to %s: %w", c.store.Name(), err)
 	}
 
+	c.mirrorContent(ipfsHash, data)
+	c.replicatePin(ipfsHash)
+
 	return &DocumentReference{
 		Name:     name,
 		Hash:     ipfsHash,
@@ -151,16 +199,10 @@ func (c *IPFSClient) UploadDocument(name string, data []byte, mimeType string) (
 // RetrieveDocument retrieves a document from IPFS
 func (c *IPFSClient) RetrieveDocument(docRef *DocumentReference) ([]byte, error) {
 
-	reader, err := c.shell.Cat(docRef.Hash)
+	data, err := c.cat(docRef.Hash, c.ipfsHashToTypesHash(docRef.Hash))
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve document from IPFS: %w", err)
 	}
-	defer reader.Close()
-
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read document data: %w", err)
-	}
 
 	// Verify size if specified
 	if docRef.Size > 0 && int64(len(data)) != docRef.Size {
@@ -170,24 +212,196 @@ func (c *IPFSClient) RetrieveDocument(docRef *DocumentReference) ([]byte, error)
 	return data, nil
 }
 
+// UploadComment uploads the body of a proposal comment and returns the hash
+// the comment is posted with
+func (c *IPFSClient) UploadComment(body string) (types.Hash, error) {
+	ipfsHash, err := c.put([]byte(body))
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to upload comment to %s: %w", c.store.Name(), err)
+	}
+
+	c.mirrorContent(ipfsHash, []byte(body))
+	c.replicatePin(ipfsHash)
+
+	return c.ipfsHashToTypesHash(ipfsHash), nil
+}
+
+// RetrieveComment retrieves the body of a proposal comment
+func (c *IPFSClient) RetrieveComment(hash types.Hash) (string, error) {
+	data, err := c.cat(c.typesHashToIPFSHash(hash), hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve comment from IPFS: %w", err)
+	}
+	return string(data), nil
+}
+
+// UploadGovernanceDocument uploads the text of a governance document
+// version and returns its hash
+func (c *IPFSClient) UploadGovernanceDocument(text string) (types.Hash, error) {
+	ipfsHash, err := c.put([]byte(text))
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to upload governance document to %s: %w", c.store.Name(), err)
+	}
+
+	c.mirrorContent(ipfsHash, []byte(text))
+	c.replicatePin(ipfsHash)
+
+	return c.ipfsHashToTypesHash(ipfsHash), nil
+}
+
+// RetrieveGovernanceDocument retrieves the text of a governance document
+// version
+func (c *IPFSClient) RetrieveGovernanceDocument(hash types.Hash) (string, error) {
+	data, err := c.cat(c.typesHashToIPFSHash(hash), hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve governance document from IPFS: %w", err)
+	}
+	return string(data), nil
+}
+
+// UploadDocumentDiff uploads the diff metadata of an amendment
+func (c *IPFSClient) UploadDocumentDiff(diff *DocumentDiff) (types.Hash, error) {
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to marshal document diff: %w", err)
+	}
+
+	ipfsHash, err := c.put(data)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to upload document diff to %s: %w", c.store.Name(), err)
+	}
+
+	c.mirrorContent(ipfsHash, data)
+	c.replicatePin(ipfsHash)
+
+	return c.ipfsHashToTypesHash(ipfsHash), nil
+}
+
+// RetrieveDocumentDiff retrieves the diff metadata of an amendment
+func (c *IPFSClient) RetrieveDocumentDiff(hash types.Hash) (*DocumentDiff, error) {
+	data, err := c.cat(c.typesHashToIPFSHash(hash), hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve document diff from IPFS: %w", err)
+	}
+
+	var diff DocumentDiff
+	if err := json.Unmarshal(data, &diff); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document diff: %w", err)
+	}
+	return &diff, nil
+}
+
+// UploadMemberProfile uploads a signed member profile and returns its hash
+func (c *IPFSClient) UploadMemberProfile(profile *MemberProfile) (types.Hash, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to marshal member profile: %w", err)
+	}
+
+	ipfsHash, err := c.put(data)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to upload member profile to %s: %w", c.store.Name(), err)
+	}
+
+	c.mirrorContent(ipfsHash, data)
+	c.replicatePin(ipfsHash)
+
+	return c.ipfsHashToTypesHash(ipfsHash), nil
+}
+
+// RetrieveMemberProfile retrieves a member profile
+func (c *IPFSClient) RetrieveMemberProfile(hash types.Hash) (*MemberProfile, error) {
+	data, err := c.cat(c.typesHashToIPFSHash(hash), hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve member profile from IPFS: %w", err)
+	}
+
+	var profile MemberProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal member profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// UploadProposalResult uploads a signed proposal result and returns its hash
+func (c *IPFSClient) UploadProposalResult(result *ProposalResult) (types.Hash, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to marshal proposal result: %w", err)
+	}
+
+	ipfsHash, err := c.put(data)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to upload proposal result to %s: %w", c.store.Name(), err)
+	}
+
+	c.mirrorContent(ipfsHash, data)
+	c.replicatePin(ipfsHash)
+
+	return c.ipfsHashToTypesHash(ipfsHash), nil
+}
+
+// RetrieveProposalResult retrieves a proposal result
+func (c *IPFSClient) RetrieveProposalResult(hash types.Hash) (*ProposalResult, error) {
+	data, err := c.cat(c.typesHashToIPFSHash(hash), hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve proposal result from IPFS: %w", err)
+	}
+
+	var result ProposalResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal proposal result: %w", err)
+	}
+	return &result, nil
+}
+
 // PinContent pins content to prevent garbage collection
 func (c *IPFSClient) PinContent(hash types.Hash) error {
 
 	ipfsHash := c.typesHashToIPFSHash(hash)
-	return c.shell.Pin(ipfsHash)
+	if err := c.store.Pin(ipfsHash); err != nil {
+		return err
+	}
+	c.replicatePin(ipfsHash)
+
+	// Content pinned from elsewhere is copied to the mirror as well
+	if c.mirror != nil && !c.mirror.IsMirrored(hash) {
+		data, err := c.catIPFS(ipfsHash)
+		if err != nil {
+			return fmt.Errorf("failed to read pinned content for mirroring: %w", err)
+		}
+		c.mirror.Mirror(hash, ipfsHash, data)
+	}
+
+	return nil
 }
 
 // UnpinContent unpins content to allow garbage collection
 func (c *IPFSClient) UnpinContent(hash types.Hash) error {
 
 	ipfsHash := c.typesHashToIPFSHash(hash)
-	return c.shell.Unpin(ipfsHash)
+	if err := c.store.Unpin(ipfsHash); err != nil {
+		return err
+	}
+
+	if c.pins != nil {
+		return c.pins.Unpin(ipfsHash)
+	}
+	return nil
 }
 
 // GetContentSize returns the size of content stored at the given hash
 func (c *IPFSClient) GetContentSize(hash types.Hash) (int64, error) {
 
 	ipfsHash := c.typesHashToIPFSHash(hash)
+	if c.shell == nil {
+		data, err := c.store.Get(ipfsHash)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get content size: %w", err)
+		}
+		return int64(len(data)), nil
+	}
+
 	stat, err := c.shell.ObjectStat(ipfsHash)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get content size: %w", err)
@@ -200,6 +414,11 @@ func (c *IPFSClient) GetContentSize(hash types.Hash) (int64, error) {
 func (c *IPFSClient) VerifyContentExists(hash types.Hash) (bool, error) {
 
 	ipfsHash := c.typesHashToIPFSHash(hash)
+	if c.shell == nil {
+		_, err := c.store.Get(ipfsHash)
+		return err == nil, nil
+	}
+
 	_, err := c.shell.ObjectStat(ipfsHash)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
@@ -213,6 +432,17 @@ func (c *IPFSClient) VerifyContentExists(hash types.Hash) (bool, error) {
 
 // ListPinnedContent returns a list of all pinned content hashes
 func (c *IPFSClient) ListPinnedContent() ([]types.Hash, error) {
+	// Stores without pins keep everything this client stored
+	if c.shell == nil {
+		c.idsMu.RLock()
+		defer c.idsMu.RUnlock()
+
+		hashes := make([]types.Hash, 0, len(c.ids))
+		for hash := range c.ids {
+			hashes = append(hashes, hash)
+		}
+		return hashes, nil
+	}
 
 	pins, err := c.shell.Pins()
 	if err != nil {
@@ -230,6 +460,9 @@ func (c *IPFSClient) ListPinnedContent() ([]types.Hash, error) {
 
 // GetNodeInfo returns information about the connected IPFS node
 func (c *IPFSClient) GetNodeInfo() (map[string]interface{}, error) {
+	if c.shell == nil {
+		return map[string]interface{}{"store": c.store.Name()}, nil
+	}
 
 	id, err := c.shell.ID()
 	if err != nil {
@@ -245,8 +478,166 @@ func (c *IPFSClient) GetNodeInfo() (map[string]interface{}, error) {
 	}, nil
 }
 
+// EnableMirror copies uploaded and pinned content to mirror and serves reads
+// from it when IPFS fails or takes longer than fallbackDelay
+func (c *IPFSClient) EnableMirror(mirror *IPFSMirror, fallbackDelay time.Duration) {
+	if fallbackDelay <= 0 {
+		fallbackDelay = DefaultMirrorFallbackDelay
+	}
+
+	c.mirror = mirror
+	c.fallbackDelay = fallbackDelay
+}
+
+// GetMirror returns the configured mirror, or nil when mirroring is disabled
+func (c *IPFSClient) GetMirror() *IPFSMirror {
+	return c.mirror
+}
+
+// EnableReplication pins uploaded and pinned content on the pinners of pins
+// as well as the IPFS node
+func (c *IPFSClient) EnableReplication(pins *PinReconciler) {
+	c.pins = pins
+}
+
+// GetReplication returns the pin reconciler, or nil when pins are not replicated
+func (c *IPFSClient) GetReplication() *PinReconciler {
+	return c.pins
+}
+
+// SetGateways sets the HTTP gateways content is read from, in order, when
+// the IPFS node cannot serve it
+func (c *IPFSClient) SetGateways(gateways []string) {
+	c.gateways = gateways
+	if c.gatewayClient == nil {
+		c.gatewayClient = &http.Client{Timeout: c.timeout}
+	}
+}
+
+// EnableMetadataCache serves proposal metadata from cache before IPFS and
+// caches metadata as it is uploaded or retrieved
+func (c *IPFSClient) EnableMetadataCache(cache *MetadataCache) {
+	c.cache = cache
+}
+
+// GetMetadataCache returns the metadata cache, or nil when caching is disabled
+func (c *IPFSClient) GetMetadataCache() *MetadataCache {
+	return c.cache
+}
+
 // Helper functions
 
+// replicatePin pins content on the replication pinners. Failed pins are
+// retried by the reconciler and do not fail the upload.
+func (c *IPFSClient) replicatePin(ipfsHash string) {
+	if c.pins == nil {
+		return
+	}
+	c.pins.Pin(ipfsHash)
+}
+
+// mirrorContent copies freshly uploaded content to the mirror. Failed copies
+// stay pending in the mirror and do not fail the upload.
+func (c *IPFSClient) mirrorContent(ipfsHash string, data []byte) {
+	if c.mirror == nil {
+		return
+	}
+	c.mirror.Mirror(c.ipfsHashToTypesHash(ipfsHash), ipfsHash, data)
+}
+
+// catIPFS reads content from the IPFS node, failing over to the gateways
+func (c *IPFSClient) catIPFS(ipfsHash string) ([]byte, error) {
+	data, err := c.catNode(ipfsHash)
+	if err == nil || len(c.gateways) == 0 {
+		return data, err
+	}
+
+	failures := []string{err.Error()}
+	for _, gateway := range c.gateways {
+		data, gatewayErr := c.catGateway(gateway, ipfsHash)
+		if gatewayErr == nil {
+			return data, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", gateway, gatewayErr))
+	}
+
+	return nil, fmt.Errorf("content unavailable on node and gateways: %s", strings.Join(failures, "; "))
+}
+
+// catNode reads content from the content store
+func (c *IPFSClient) catNode(ipfsHash string) ([]byte, error) {
+	return c.store.Get(ipfsHash)
+}
+
+// put stores content and records its ID under its on-chain hash
+func (c *IPFSClient) put(data []byte) (string, error) {
+	id, err := c.store.Put(data)
+	if err != nil {
+		return "", err
+	}
+
+	c.idsMu.Lock()
+	c.ids[c.ipfsHashToTypesHash(id)] = id
+	c.idsMu.Unlock()
+
+	return id, nil
+}
+
+// catGateway reads content from an HTTP gateway
+func (c *IPFSClient) catGateway(gateway, ipfsHash string) ([]byte, error) {
+	resp, err := c.gatewayClient.Get(strings.TrimRight(gateway, "/") + "/ipfs/" + ipfsHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gateway returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// cat reads content from IPFS, serving the verified mirror copy when IPFS
+// fails or is slower than the fallback delay
+func (c *IPFSClient) cat(ipfsHash string, hash types.Hash) ([]byte, error) {
+	if c.mirror == nil || !c.mirror.IsMirrored(hash) {
+		return c.catIPFS(ipfsHash)
+	}
+
+	type catResult struct {
+		data []byte
+		err  error
+	}
+
+	results := make(chan catResult, 1)
+	go func() {
+		data, err := c.catIPFS(ipfsHash)
+		results <- catResult{data: data, err: err}
+	}()
+
+	timer := time.NewTimer(c.fallbackDelay)
+	defer timer.Stop()
+
+	select {
+	case result := <-results:
+		if result.err == nil {
+			return result.data, nil
+		}
+		data, err := c.mirror.Fetch(hash)
+		if err != nil {
+			return nil, fmt.Errorf("%w (mirror fallback: %v)", result.err, err)
+		}
+		return data, nil
+	case <-timer.C:
+		if data, err := c.mirror.Fetch(hash); err == nil {
+			return data, nil
+		}
+		// The mirror is unusable, keep waiting on IPFS
+		result := <-results
+		return result.data, result.err
+	}
+}
+
 // ipfsHashToTypesHash converts an IPFS hash string to types.Hash
 func (c *IPFSClient) ipfsHashToTypesHash(ipfsHash string) types.Hash {
 	// For now, we'll use the first 32 bytes of the IPFS hash
@@ -259,6 +650,13 @@ func (c *IPFSClient) ipfsHashToTypesHash(ipfsHash string) types.Hash {
 
 // typesHashToIPFSHash converts a types.Hash to an IPFS hash string
 func (c *IPFSClient) typesHashToIPFSHash(hash types.Hash) string {
+	c.idsMu.RLock()
+	id, exists := c.ids[hash]
+	c.idsMu.RUnlock()
+	if exists {
+		return id
+	}
+
 	// This is a simplified conversion - in practice, you'd want to store
 	// the actual IPFS hash and use this as a lookup key
 	return hex.EncodeToString(hash[:])
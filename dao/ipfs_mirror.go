@@ -0,0 +1,311 @@
+package dao
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// DefaultMirrorFallbackDelay is how long a read waits on IPFS before the mirror serves it
+const DefaultMirrorFallbackDelay = 5 * time.Second
+
+// MirrorStore is an HTTP object store holding copies of IPFS content
+type MirrorStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+}
+
+// MirrorConfig configures an S3-compatible mirror store
+type MirrorConfig struct {
+	Endpoint  string // Base URL of the object store, e.g. http://localhost:9000
+	Bucket    string
+	Region    string
+	AccessKey string // Requests are signed with AWS Signature V4 when set
+	SecretKey string
+	Timeout   time.Duration
+}
+
+// S3MirrorStore stores objects in an S3-compatible bucket using path-style URLs
+type S3MirrorStore struct {
+	config MirrorConfig
+	client *http.Client
+}
+
+// NewS3MirrorStore creates a new S3-compatible mirror store
+func NewS3MirrorStore(config MirrorConfig) *S3MirrorStore {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &S3MirrorStore{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Put uploads an object to the bucket
+func (s *S3MirrorStore) Put(key string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create mirror request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	s.sign(req, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to mirror: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("mirror upload failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Get downloads an object from the bucket
+func (s *S3MirrorStore) Get(key string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mirror request: %w", err)
+	}
+	s.sign(req, nil)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from mirror: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mirror read failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror data: %w", err)
+	}
+
+	return data, nil
+}
+
+// objectURL returns the path-style URL of an object
+func (s *S3MirrorStore) objectURL(key string) string {
+	return strings.TrimRight(s.config.Endpoint, "/") + s.objectPath(key)
+}
+
+// objectPath returns the escaped path of an object within the endpoint
+func (s *S3MirrorStore) objectPath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/" + url.PathEscape(s.config.Bucket) + "/" + strings.Join(segments, "/")
+}
+
+// sign adds AWS Signature V4 headers to a request when credentials are configured
+func (s *S3MirrorStore) sign(req *http.Request, payload []byte) {
+	if s.config.AccessKey == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256.Sum256(payload)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHex)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHex,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// MirrorEntry records a copy of IPFS content held by the mirror store
+type MirrorEntry struct {
+	IPFSHash   string
+	ObjectKey  string
+	SHA256     string // Digest of the content at mirroring time
+	Size       int64
+	MirroredAt int64
+}
+
+// MirrorStats summarises mirror activity
+type MirrorStats struct {
+	Mirrored          int    `json:"mirrored"`
+	Pending           int    `json:"pending"`
+	FallbackReads     uint64 `json:"fallback_reads"`
+	IntegrityFailures uint64 `json:"integrity_failures"`
+}
+
+// IPFSMirror copies IPFS content to a mirror store and serves verified copies
+type IPFSMirror struct {
+	store             MirrorStore
+	entries           map[types.Hash]*MirrorEntry
+	pending           map[types.Hash]*pendingMirror
+	fallbackReads     uint64
+	integrityFailures uint64
+	mu                sync.RWMutex
+}
+
+// NewIPFSMirror creates a new mirror backed by store
+func NewIPFSMirror(store MirrorStore) *IPFSMirror {
+	return &IPFSMirror{
+		store:   store,
+		entries: make(map[types.Hash]*MirrorEntry),
+		pending: make(map[types.Hash]*pendingMirror),
+	}
+}
+
+// pendingMirror is content whose upload to the mirror store failed
+type pendingMirror struct {
+	ipfsHash string
+	data     []byte
+}
+
+// Mirror copies content to the mirror store under its on-chain hash. Failed
+// uploads are kept for RetryPending.
+func (m *IPFSMirror) Mirror(hash types.Hash, ipfsHash string, data []byte) error {
+	digest := sha256.Sum256(data)
+	entry := &MirrorEntry{
+		IPFSHash:   ipfsHash,
+		ObjectKey:  "ipfs/" + ipfsHash,
+		SHA256:     hex.EncodeToString(digest[:]),
+		Size:       int64(len(data)),
+		MirroredAt: time.Now().Unix(),
+	}
+
+	if err := m.store.Put(entry.ObjectKey, data); err != nil {
+		m.mu.Lock()
+		m.pending[hash] = &pendingMirror{ipfsHash: ipfsHash, data: data}
+		m.mu.Unlock()
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pending, hash)
+	m.entries[hash] = entry
+
+	return nil
+}
+
+// RetryPending re-attempts failed uploads and returns how many succeeded
+func (m *IPFSMirror) RetryPending() int {
+	m.mu.RLock()
+	pending := make(map[types.Hash]*pendingMirror, len(m.pending))
+	for hash, content := range m.pending {
+		pending[hash] = content
+	}
+	m.mu.RUnlock()
+
+	succeeded := 0
+	for hash, content := range pending {
+		if m.Mirror(hash, content.ipfsHash, content.data) == nil {
+			succeeded++
+		}
+	}
+
+	return succeeded
+}
+
+// IsMirrored reports whether content has been copied to the mirror store
+func (m *IPFSMirror) IsMirrored(hash types.Hash) bool {
+	_, exists := m.GetEntry(hash)
+	return exists
+}
+
+// GetEntry returns the mirror record of IPFS content
+func (m *IPFSMirror) GetEntry(hash types.Hash) (*MirrorEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, exists := m.entries[hash]
+	return entry, exists
+}
+
+// Fetch reads content from the mirror store and verifies it against the
+// digest recorded when it was mirrored
+func (m *IPFSMirror) Fetch(hash types.Hash) ([]byte, error) {
+	entry, exists := m.GetEntry(hash)
+	if !exists {
+		return nil, fmt.Errorf("content %s is not mirrored", hash)
+	}
+
+	data, err := m.store.Get(entry.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	digest := sha256.Sum256(data)
+	if hex.EncodeToString(digest[:]) != entry.SHA256 {
+		m.integrityFailures++
+		return nil, fmt.Errorf("mirror integrity check failed for %s: expected %s, got %x", entry.IPFSHash, entry.SHA256, digest)
+	}
+
+	m.fallbackReads++
+	return data, nil
+}
+
+// GetStats returns mirror activity counters
+func (m *IPFSMirror) GetStats() MirrorStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return MirrorStats{
+		Mirrored:          len(m.entries),
+		Pending:           len(m.pending),
+		FallbackReads:     m.fallbackReads,
+		IntegrityFailures: m.integrityFailures,
+	}
+}
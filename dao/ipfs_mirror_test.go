@@ -0,0 +1,148 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testObjectStore is an in-memory S3-compatible endpoint
+type testObjectStore struct {
+	objects  map[string][]byte
+	auth     []string
+	failPuts int
+	mu       sync.Mutex
+}
+
+func newTestObjectStore(t *testing.T) (*testObjectStore, *httptest.Server) {
+	store := &testObjectStore{objects: make(map[string][]byte)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		store.auth = append(store.auth, r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodPut:
+			if store.failPuts > 0 {
+				store.failPuts--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			store.objects[r.URL.Path] = data
+		case http.MethodGet:
+			data, exists := store.objects[r.URL.Path]
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return store, server
+}
+
+func TestS3MirrorStore_PutGet(t *testing.T) {
+	objects, server := newTestObjectStore(t)
+	store := NewS3MirrorStore(MirrorConfig{
+		Endpoint:  server.URL,
+		Bucket:    "dao-mirror",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+
+	require.NoError(t, store.Put("ipfs/QmTest", []byte("content")))
+	assert.Equal(t, []byte("content"), objects.objects["/dao-mirror/ipfs/QmTest"])
+
+	data, err := store.Get("ipfs/QmTest")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("content"), data)
+
+	_, err = store.Get("ipfs/QmMissing")
+	assert.Error(t, err)
+
+	// Every request carries a V4 signature scoped to the default region
+	require.NotEmpty(t, objects.auth)
+	for _, auth := range objects.auth {
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/"))
+		assert.Contains(t, auth, "/us-east-1/s3/aws4_request")
+	}
+}
+
+func TestIPFSMirror_IntegrityAndRetry(t *testing.T) {
+	objects, server := newTestObjectStore(t)
+	mirror := NewIPFSMirror(NewS3MirrorStore(MirrorConfig{Endpoint: server.URL, Bucket: "mirror"}))
+	client := NewIPFSClient("")
+
+	// Failed uploads stay pending until a retry succeeds
+	objects.failPuts = 1
+	hash := client.ipfsHashToTypesHash("QmDoc")
+	require.Error(t, mirror.Mirror(hash, "QmDoc", []byte("document")))
+	assert.False(t, mirror.IsMirrored(hash))
+	assert.Equal(t, 1, mirror.GetStats().Pending)
+
+	assert.Equal(t, 1, mirror.RetryPending())
+	require.True(t, mirror.IsMirrored(hash))
+
+	data, err := mirror.Fetch(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("document"), data)
+
+	// Content altered in the store is rejected
+	objects.objects["/mirror/ipfs/QmDoc"] = []byte("tampered")
+	_, err = mirror.Fetch(hash)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "integrity")
+
+	stats := mirror.GetStats()
+	assert.Equal(t, 1, stats.Mirrored)
+	assert.Equal(t, 0, stats.Pending)
+	assert.Equal(t, uint64(1), stats.FallbackReads)
+	assert.Equal(t, uint64(1), stats.IntegrityFailures)
+}
+
+func TestIPFSClient_MirrorFallback(t *testing.T) {
+	_, server := newTestObjectStore(t)
+	mirror := NewIPFSMirror(NewS3MirrorStore(MirrorConfig{Endpoint: server.URL, Bucket: "mirror"}))
+
+	// No IPFS node listens on this address
+	client := NewIPFSClient("127.0.0.1:1")
+	client.EnableMirror(mirror, time.Second)
+
+	document := []byte("grant report")
+	client.mirrorContent("QmReport", document)
+
+	data, err := client.RetrieveDocument(&DocumentReference{Name: "report", Hash: "QmReport", Size: int64(len(document))})
+	require.NoError(t, err)
+	assert.Equal(t, document, data)
+
+	// Metadata is served from the mirror and still checksum-verified
+	metadata := &ProposalMetadata{Title: "Mirrored", Description: "Served from the mirror", Version: "1.0", CreatedAt: 1}
+	unsigned, err := json.MarshalIndent(metadata, "", "  ")
+	require.NoError(t, err)
+	checksum := sha256.Sum256(unsigned)
+	metadata.Checksum = hex.EncodeToString(checksum[:])
+	encoded, err := json.MarshalIndent(metadata, "", "  ")
+	require.NoError(t, err)
+
+	client.mirrorContent("QmMetadata", encoded)
+	retrieved, err := client.RetrieveProposalMetadata(client.ipfsHashToTypesHash("QmMetadata"))
+	require.NoError(t, err)
+	assert.Equal(t, "Mirrored", retrieved.Title)
+
+	// Content that was never mirrored still reports the IPFS failure
+	_, err = client.RetrieveDocument(&DocumentReference{Name: "missing", Hash: "QmMissing"})
+	assert.Error(t, err)
+}
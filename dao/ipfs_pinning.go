@@ -0,0 +1,421 @@
+package dao
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	shell "github.com/ipfs/go-ipfs-api"
+)
+
+// DefaultPinReconcileInterval is how often pins are checked on every pinner
+const DefaultPinReconcileInterval = 10 * time.Minute
+
+// Pinner keeps IPFS content pinned somewhere besides the main IPFS node
+type Pinner interface {
+	Name() string
+	Pin(cid string) error
+	Unpin(cid string) error
+	IsPinned(cid string) (bool, error)
+}
+
+// IPFSNodePinner pins content on an additional IPFS node, such as a member
+// of an IPFS cluster
+type IPFSNodePinner struct {
+	url   string
+	shell *shell.Shell
+}
+
+// NewIPFSNodePinner creates a pinner for the IPFS node API at nodeURL
+func NewIPFSNodePinner(nodeURL string) *IPFSNodePinner {
+	return &IPFSNodePinner{
+		url:   nodeURL,
+		shell: shell.NewShell(nodeURL),
+	}
+}
+
+// Name returns the node API URL
+func (p *IPFSNodePinner) Name() string {
+	return p.url
+}
+
+// Pin pins content on the node
+func (p *IPFSNodePinner) Pin(cid string) error {
+	return p.shell.Pin(cid)
+}
+
+// Unpin unpins content on the node
+func (p *IPFSNodePinner) Unpin(cid string) error {
+	return p.shell.Unpin(cid)
+}
+
+// IsPinned reports whether the node pins content
+func (p *IPFSNodePinner) IsPinned(cid string) (bool, error) {
+	pins, err := p.shell.Pins()
+	if err != nil {
+		return false, err
+	}
+	_, pinned := pins[cid]
+	return pinned, nil
+}
+
+// PinningServiceConfig configures a remote pinning service implementing the
+// IPFS Pinning Service API, such as Pinata or web3.storage
+type PinningServiceConfig struct {
+	Name     string
+	Endpoint string // Base URL of the API, e.g. https://api.pinata.cloud/psa
+	Token    string // Bearer access token
+	Timeout  time.Duration
+}
+
+// RemotePinningService pins content through the IPFS Pinning Service API
+type RemotePinningService struct {
+	config PinningServiceConfig
+	client *http.Client
+}
+
+// NewRemotePinningService creates a new pinning service client
+func NewRemotePinningService(config PinningServiceConfig) *RemotePinningService {
+	if config.Name == "" {
+		config.Name = config.Endpoint
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &RemotePinningService{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// pinStatus is a pin request as reported by the pinning service
+type pinStatus struct {
+	RequestID string `json:"requestid"`
+	Status    string `json:"status"` // queued, pinning, pinned or failed
+}
+
+// Name returns the configured service name
+func (s *RemotePinningService) Name() string {
+	return s.config.Name
+}
+
+// Pin asks the service to pin content. The service fetches it from the IPFS
+// network asynchronously.
+func (s *RemotePinningService) Pin(cid string) error {
+	body, err := json.Marshal(map[string]string{"cid": cid, "name": cid})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.do(http.MethodPost, "/pins", body)
+	return err
+}
+
+// Unpin removes every pin request of content
+func (s *RemotePinningService) Unpin(cid string) error {
+	statuses, err := s.list(cid)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if _, err := s.do(http.MethodDelete, "/pins/"+url.PathEscape(status.RequestID), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsPinned reports whether the service pins content or is still fetching it
+func (s *RemotePinningService) IsPinned(cid string) (bool, error) {
+	statuses, err := s.list(cid)
+	if err != nil {
+		return false, err
+	}
+	return len(statuses) > 0, nil
+}
+
+// list returns the live pin requests of content
+func (s *RemotePinningService) list(cid string) ([]pinStatus, error) {
+	query := url.Values{"cid": {cid}, "status": {"queued,pinning,pinned"}}
+	data, err := s.do(http.MethodGet, "/pins?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results struct {
+		Count   int         `json:"count"`
+		Results []pinStatus `json:"results"`
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("invalid pinning service response: %w", err)
+	}
+	return results.Results, nil
+}
+
+func (s *RemotePinningService) do(method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, strings.TrimRight(s.config.Endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("pinning service %s returned %s", s.config.Name, resp.Status)
+	}
+	return data, nil
+}
+
+// PinReconcileReport summarises a reconciliation pass
+type PinReconcileReport struct {
+	Checked  int `json:"checked"`
+	Repinned int `json:"repinned"`
+	Failed   int `json:"failed"`
+}
+
+// PinningStats summarises pin replication
+type PinningStats struct {
+	Pinners         []string `json:"pinners"`
+	Tracked         int      `json:"tracked"`
+	FullyReplicated int      `json:"fully_replicated"`
+	Repins          uint64   `json:"repins"`
+	Failures        uint64   `json:"failures"`
+	LastReconcile   int64    `json:"last_reconcile"`
+}
+
+// PinReconciler replicates pins to every pinner and periodically re-pins
+// content a pinner lost, so proposal metadata survives node churn
+type PinReconciler struct {
+	pinners       []Pinner
+	replicas      map[string]map[string]bool // CID -> pinner name -> pinned
+	repins        uint64
+	failures      uint64
+	lastReconcile int64
+	stop          chan struct{}
+	mu            sync.RWMutex
+}
+
+// NewPinReconciler creates a reconciler replicating pins to pinners
+func NewPinReconciler(pinners ...Pinner) *PinReconciler {
+	return &PinReconciler{
+		pinners:  pinners,
+		replicas: make(map[string]map[string]bool),
+	}
+}
+
+// Pin tracks content and pins it on every pinner. Content stays tracked
+// when some pinners fail so the next reconciliation retries them.
+func (r *PinReconciler) Pin(cid string) error {
+	r.mu.Lock()
+	if _, tracked := r.replicas[cid]; !tracked {
+		r.replicas[cid] = make(map[string]bool)
+	}
+	r.mu.Unlock()
+
+	var failed []string
+	for _, pinner := range r.pinners {
+		err := pinner.Pin(cid)
+		r.record(cid, pinner.Name(), err == nil)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", pinner.Name(), err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to pin %s on %s", cid, strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Unpin stops tracking content and unpins it from every pinner
+func (r *PinReconciler) Unpin(cid string) error {
+	r.mu.Lock()
+	delete(r.replicas, cid)
+	r.mu.Unlock()
+
+	var failed []string
+	for _, pinner := range r.pinners {
+		if err := pinner.Unpin(cid); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", pinner.Name(), err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to unpin %s from %s", cid, strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Reconcile checks every tracked pin on every pinner and re-pins missing ones
+func (r *PinReconciler) Reconcile() PinReconcileReport {
+	r.mu.RLock()
+	cids := make([]string, 0, len(r.replicas))
+	for cid := range r.replicas {
+		cids = append(cids, cid)
+	}
+	r.mu.RUnlock()
+	sort.Strings(cids)
+
+	var report PinReconcileReport
+	for _, cid := range cids {
+		for _, pinner := range r.pinners {
+			report.Checked++
+			if pinned, err := pinner.IsPinned(cid); err == nil && pinned {
+				r.record(cid, pinner.Name(), true)
+				continue
+			}
+
+			if err := pinner.Pin(cid); err != nil {
+				r.record(cid, pinner.Name(), false)
+				report.Failed++
+				continue
+			}
+			r.record(cid, pinner.Name(), true)
+			report.Repinned++
+		}
+	}
+
+	r.mu.Lock()
+	r.repins += uint64(report.Repinned)
+	r.failures += uint64(report.Failed)
+	r.lastReconcile = time.Now().Unix()
+	r.mu.Unlock()
+
+	return report
+}
+
+// Start reconciles every interval in the background until Stop is called
+func (r *PinReconciler) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPinReconcileInterval
+	}
+
+	r.mu.Lock()
+	if r.stop != nil {
+		r.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	r.stop = stop
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.Reconcile()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends background reconciliation
+func (r *PinReconciler) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
+
+// IsReplicated reports whether content is pinned on every pinner
+func (r *PinReconciler) IsReplicated(cid string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	replicas, tracked := r.replicas[cid]
+	return tracked && r.countPinned(replicas) == len(r.pinners)
+}
+
+// GetStats returns replication counters
+func (r *PinReconciler) GetStats() PinningStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := PinningStats{
+		Pinners:       make([]string, 0, len(r.pinners)),
+		Tracked:       len(r.replicas),
+		Repins:        r.repins,
+		Failures:      r.failures,
+		LastReconcile: r.lastReconcile,
+	}
+	for _, pinner := range r.pinners {
+		stats.Pinners = append(stats.Pinners, pinner.Name())
+	}
+	for _, replicas := range r.replicas {
+		if r.countPinned(replicas) == len(r.pinners) {
+			stats.FullyReplicated++
+		}
+	}
+	return stats
+}
+
+// record notes whether a pinner holds content, ignoring untracked content
+func (r *PinReconciler) record(cid, pinner string, pinned bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if replicas, tracked := r.replicas[cid]; tracked {
+		replicas[pinner] = pinned
+	}
+}
+
+func (r *PinReconciler) countPinned(replicas map[string]bool) int {
+	count := 0
+	for _, pinned := range replicas {
+		if pinned {
+			count++
+		}
+	}
+	return count
+}
+
+// IPFSPinningConfig configures pin replication and gateway failover
+type IPFSPinningConfig struct {
+	Nodes             []string // Additional IPFS node APIs, e.g. cluster peers
+	Services          []PinningServiceConfig
+	Gateways          []string // HTTP gateways tried in order when the node cannot serve content
+	ReconcileInterval time.Duration
+}
+
+// Pinners returns the pinners of the configured nodes and services
+func (c IPFSPinningConfig) Pinners() []Pinner {
+	pinners := make([]Pinner, 0, len(c.Nodes)+len(c.Services))
+	for _, node := range c.Nodes {
+		pinners = append(pinners, NewIPFSNodePinner(node))
+	}
+	for _, service := range c.Services {
+		pinners = append(pinners, NewRemotePinningService(service))
+	}
+	return pinners
+}
@@ -0,0 +1,220 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPinner is an in-memory pinner that can lose pins or go down
+type testPinner struct {
+	name string
+	pins map[string]bool
+	down bool
+	mu   sync.Mutex
+}
+
+func newTestPinner(name string) *testPinner {
+	return &testPinner{name: name, pins: make(map[string]bool)}
+}
+
+func (p *testPinner) Name() string { return p.name }
+
+func (p *testPinner) Pin(cid string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.down {
+		return fmt.Errorf("%s is down", p.name)
+	}
+	p.pins[cid] = true
+	return nil
+}
+
+func (p *testPinner) Unpin(cid string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pins, cid)
+	return nil
+}
+
+func (p *testPinner) IsPinned(cid string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.down {
+		return false, fmt.Errorf("%s is down", p.name)
+	}
+	return p.pins[cid], nil
+}
+
+// testPinningService is an in-memory IPFS Pinning Service API endpoint
+type testPinningService struct {
+	pins map[string]string // Request ID -> CID
+	auth []string
+	mu   sync.Mutex
+}
+
+func newTestPinningService(t *testing.T) (*testPinningService, *httptest.Server) {
+	service := &testPinningService{pins: make(map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service.mu.Lock()
+		defer service.mu.Unlock()
+
+		service.auth = append(service.auth, r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/pins":
+			var pin struct {
+				CID string `json:"cid"`
+			}
+			json.NewDecoder(r.Body).Decode(&pin)
+			requestID := fmt.Sprintf("req-%d", len(service.pins)+1)
+			service.pins[requestID] = pin.CID
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]string{"requestid": requestID, "status": "queued"})
+		case r.Method == http.MethodGet && r.URL.Path == "/pins":
+			results := []map[string]string{}
+			for requestID, cid := range service.pins {
+				if cid == r.URL.Query().Get("cid") {
+					results = append(results, map[string]string{"requestid": requestID, "status": "pinned"})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "results": results})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/pins/"):
+			delete(service.pins, strings.TrimPrefix(r.URL.Path, "/pins/"))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return service, server
+}
+
+func TestRemotePinningService(t *testing.T) {
+	service, server := newTestPinningService(t)
+	pinner := NewRemotePinningService(PinningServiceConfig{Name: "pinata", Endpoint: server.URL, Token: "secret"})
+
+	require.NoError(t, pinner.Pin("QmMetadata"))
+	pinned, err := pinner.IsPinned("QmMetadata")
+	require.NoError(t, err)
+	assert.True(t, pinned)
+
+	pinned, err = pinner.IsPinned("QmOther")
+	require.NoError(t, err)
+	assert.False(t, pinned)
+
+	require.NoError(t, pinner.Unpin("QmMetadata"))
+	assert.Empty(t, service.pins)
+	assert.Equal(t, "Bearer secret", service.auth[0])
+
+	down := NewRemotePinningService(PinningServiceConfig{Endpoint: server.URL + "/missing"})
+	assert.Error(t, down.Pin("QmMetadata"))
+}
+
+func TestPinReconciler_ReplicatesPins(t *testing.T) {
+	cluster, service := newTestPinner("cluster"), newTestPinner("service")
+	pins := NewPinReconciler(cluster, service)
+
+	require.NoError(t, pins.Pin("QmMetadata"))
+	assert.True(t, cluster.pins["QmMetadata"])
+	assert.True(t, service.pins["QmMetadata"])
+	assert.True(t, pins.IsReplicated("QmMetadata"))
+
+	require.NoError(t, pins.Unpin("QmMetadata"))
+	assert.Empty(t, cluster.pins)
+	assert.Equal(t, 0, pins.GetStats().Tracked)
+}
+
+func TestPinReconciler_RepinsLostContent(t *testing.T) {
+	cluster, service := newTestPinner("cluster"), newTestPinner("service")
+	pins := NewPinReconciler(cluster, service)
+
+	// Pins failing on a pinner stay tracked
+	service.down = true
+	assert.Error(t, pins.Pin("QmMetadata"))
+	assert.False(t, pins.IsReplicated("QmMetadata"))
+
+	report := pins.Reconcile()
+	assert.Equal(t, PinReconcileReport{Checked: 2, Failed: 1}, report)
+
+	// Once the pinner is back, and after a node lost its pins, the
+	// reconciliation restores every replica
+	service.down = false
+	delete(cluster.pins, "QmMetadata")
+	report = pins.Reconcile()
+	assert.Equal(t, PinReconcileReport{Checked: 2, Repinned: 2}, report)
+	assert.True(t, pins.IsReplicated("QmMetadata"))
+
+	stats := pins.GetStats()
+	assert.Equal(t, []string{"cluster", "service"}, stats.Pinners)
+	assert.Equal(t, 1, stats.FullyReplicated)
+	assert.Equal(t, uint64(2), stats.Repins)
+	assert.Equal(t, uint64(1), stats.Failures)
+	assert.NotZero(t, stats.LastReconcile)
+}
+
+func TestPinReconciler_Background(t *testing.T) {
+	cluster := newTestPinner("cluster")
+	pins := NewPinReconciler(cluster)
+	require.NoError(t, pins.Pin("QmMetadata"))
+
+	cluster.Unpin("QmMetadata")
+	pins.Start(10 * time.Millisecond)
+	defer pins.Stop()
+
+	assert.Eventually(t, func() bool {
+		pinned, _ := cluster.IsPinned("QmMetadata")
+		return pinned
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestIPFSClient_GatewayFailover(t *testing.T) {
+	content := []byte("proposal document")
+	var requests []string
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "down")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Write(content)
+	}))
+	defer gateway.Close()
+
+	// The IPFS node is unreachable
+	client := NewIPFSClient("127.0.0.1:1")
+	_, err := client.RetrieveDocument(&DocumentReference{Hash: "QmDocument"})
+	assert.Error(t, err)
+
+	client.SetGateways([]string{down.URL, gateway.URL + "/"})
+	data, err := client.RetrieveDocument(&DocumentReference{Hash: "QmDocument", Size: int64(len(content))})
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.Equal(t, []string{"down", "/ipfs/QmDocument"}, requests)
+}
+
+func TestDAO_EnableIPFSPinning(t *testing.T) {
+	_, server := newTestPinningService(t)
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	assert.Nil(t, dao.EnableIPFSPinning(IPFSPinningConfig{Gateways: []string{"https://ipfs.io"}}))
+	assert.Nil(t, dao.IPFSClient.GetReplication())
+
+	pins := dao.EnableIPFSPinning(IPFSPinningConfig{
+		Services: []PinningServiceConfig{{Name: "web3.storage", Endpoint: server.URL}},
+	})
+	require.NotNil(t, pins)
+	defer pins.Stop()
+
+	assert.Same(t, pins, dao.IPFSClient.GetReplication())
+	assert.Equal(t, []string{"web3.storage"}, pins.GetStats().Pinners)
+}
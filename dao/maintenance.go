@@ -0,0 +1,182 @@
+package dao
+
+import (
+	"sort"
+	"time"
+)
+
+// Names of the maintenance jobs of the DAO
+const (
+	JobProposalStatus   = "proposal_status"
+	JobReputationDecay  = "reputation_decay"
+	JobTreasuryExpiry   = "treasury_expiry"
+	JobTreasuryRunway   = "treasury_runway"
+	JobDelegationExpiry = "delegation_expiry"
+	JobMetricsSampling  = "metrics_sampling"
+	JobIPFSCleanup      = "ipfs_cleanup"
+	JobParameterRollout = "parameter_rollout"
+	JobArchive          = "archive"
+)
+
+// MaintenanceJobNames lists the maintenance jobs a scheduler can run
+var MaintenanceJobNames = []string{
+	JobProposalStatus,
+	JobReputationDecay,
+	JobTreasuryExpiry,
+	JobTreasuryRunway,
+	JobDelegationExpiry,
+	JobMetricsSampling,
+	JobIPFSCleanup,
+	JobParameterRollout,
+	JobArchive,
+}
+
+// MaintenanceJobs returns the periodic maintenance of the DAO by job name.
+// Jobs that change DAO state run through guard, which must keep them from
+// racing block processing. They run directly when guard is nil.
+func (d *DAO) MaintenanceJobs(guard func(fn func())) map[string]JobFunc {
+	if guard == nil {
+		guard = func(fn func()) { fn() }
+	}
+
+	return map[string]JobFunc{
+		JobProposalStatus: func(time.Time) error {
+			var resolved []OptimisticProposal
+			guard(func() {
+				d.ExpireUnendorsedProposals()
+				d.UpdateDueProposalStatuses()
+				for _, optimistic := range d.ResolveOptimisticProposals() {
+					resolved = append(resolved, *optimistic)
+				}
+				d.ProcessParticipationEpoch()
+			})
+			d.notifyOptimisticResolutions(resolved)
+			_, err := d.AnchorProposalResults(guard)
+			return err
+		},
+		JobReputationDecay: func(time.Time) error {
+			guard(d.ApplyInactivityDecay)
+			return nil
+		},
+		JobTreasuryExpiry: func(time.Time) error {
+			guard(func() {
+				d.RetryTreasuryConversions()
+				d.CleanupExpiredTransactions()
+			})
+			return nil
+		},
+		// Only reads state, the alert goes out through the notifications
+		JobTreasuryRunway: func(time.Time) error {
+			d.CheckTreasuryRunway()
+			return nil
+		},
+		JobDelegationExpiry: func(now time.Time) error {
+			var expiries []DelegationExpiry
+			guard(func() { expiries = d.ExpireDelegations(now.Unix()) })
+			d.notifyDelegationExpiries(expiries)
+			return nil
+		},
+		JobMetricsSampling: func(now time.Time) error {
+			d.Metrics.Sample(now.Unix())
+			return nil
+		},
+		// Unpinning talks to IPFS and only reads the proposals, so it does
+		// not hold up block processing
+		JobIPFSCleanup: func(time.Time) error {
+			return d.CleanupUnusedMetadata()
+		},
+		JobParameterRollout: func(now time.Time) error {
+			guard(func() { d.ApplyParameterRollouts(now.Unix()) })
+			return nil
+		},
+		// Does nothing unless the memory-bounded mode is enabled
+		JobArchive: func(time.Time) error {
+			var err error
+			guard(func() { _, err = d.ArchiveFinalized() })
+			return err
+		},
+	}
+}
+
+// DelegationExpiry is a delegation the expiry sweep ended, or renewed when
+// the delegator opted into auto-renewal
+type DelegationExpiry struct {
+	Delegation Delegation
+	Renewed    bool
+}
+
+// ExpireDelegations deactivates the delegations that ended before now and
+// renews those set to auto-renew for as many periods as it takes to cover
+// now
+func (d *DAO) ExpireDelegations(now int64) []DelegationExpiry {
+	var expiries []DelegationExpiry
+	for _, delegation := range d.GovernanceState.Delegations {
+		if !delegation.Active || delegation.EndTime >= now {
+			continue
+		}
+
+		renewed := delegation.AutoRenew && delegation.Duration > 0
+		if renewed {
+			periods := (now-delegation.EndTime)/delegation.Duration + 1
+			delegation.StartTime = delegation.EndTime + (periods-1)*delegation.Duration
+			delegation.EndTime = delegation.StartTime + delegation.Duration
+		} else {
+			delegation.Active = false
+		}
+		expiries = append(expiries, DelegationExpiry{Delegation: *delegation, Renewed: renewed})
+	}
+
+	// Map order is random, listeners see the sweep in a stable order
+	sort.Slice(expiries, func(i, j int) bool {
+		return expiries[i].Delegation.Delegator.String() < expiries[j].Delegation.Delegator.String()
+	})
+	return expiries
+}
+
+// OnDelegationExpiry calls fn for every delegation the expiry sweep ends or
+// renews
+func (d *DAO) OnDelegationExpiry(fn func(DelegationExpiry)) {
+	d.listenersMu.Lock()
+	defer d.listenersMu.Unlock()
+
+	d.expiryListeners = append(d.expiryListeners, fn)
+}
+
+func (d *DAO) notifyDelegationExpiries(expiries []DelegationExpiry) {
+	d.listenersMu.RLock()
+	defer d.listenersMu.RUnlock()
+
+	for _, expiry := range expiries {
+		for _, fn := range d.expiryListeners {
+			fn(expiry)
+		}
+	}
+}
+
+// OnOptimisticResolution calls fn for every optimistic proposal the
+// proposal status job executes or settles after a challenge vote
+func (d *DAO) OnOptimisticResolution(fn func(OptimisticProposal)) {
+	d.listenersMu.Lock()
+	defer d.listenersMu.Unlock()
+
+	d.optimisticListeners = append(d.optimisticListeners, fn)
+}
+
+func (d *DAO) notifyOptimisticResolutions(resolved []OptimisticProposal) {
+	d.listenersMu.RLock()
+	defer d.listenersMu.RUnlock()
+
+	for _, optimistic := range resolved {
+		for _, fn := range d.optimisticListeners {
+			fn(optimistic)
+		}
+	}
+}
+
+// EnableMetrics keeps metric samples for retention without sampling them in
+// the background, a scheduler samples them with the metrics_sampling job
+func (d *DAO) EnableMetrics(retention time.Duration) *MetricsTimeSeries {
+	d.Metrics.Stop()
+	d.Metrics = NewMetricsTimeSeries(d.AnalyticsSystem, retention)
+	return d.Metrics
+}
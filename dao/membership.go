@@ -0,0 +1,284 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MembershipStatus is the standing of a member in the DAO. The zero value is
+// active so token holders are members unless they exit or are suspended.
+type MembershipStatus byte
+
+const (
+	MembershipStatusActive    MembershipStatus = 0x00
+	MembershipStatusSuspended MembershipStatus = 0x01 // Cannot propose or vote until reinstated
+	MembershipStatusExited    MembershipStatus = 0x02 // Left the DAO, may apply again
+)
+
+// String returns the name of the status
+func (s MembershipStatus) String() string {
+	switch s {
+	case MembershipStatusActive:
+		return "active"
+	case MembershipStatusSuspended:
+		return "suspended"
+	case MembershipStatusExited:
+		return "exited"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseMembershipStatus returns the status with the given name
+func ParseMembershipStatus(name string) (MembershipStatus, bool) {
+	for _, status := range []MembershipStatus{MembershipStatusActive, MembershipStatusSuspended, MembershipStatusExited} {
+		if status.String() == name {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// ApplicationStatus is the stage of a membership application
+type ApplicationStatus string
+
+const (
+	ApplicationStatusPending  ApplicationStatus = "pending"
+	ApplicationStatusApproved ApplicationStatus = "approved"
+)
+
+// MembershipApplication is a request to join the DAO. It is approved once
+// enough active members approve it, or straight away when the applicant
+// holds the membership minimum of tokens.
+type MembershipApplication struct {
+	Applicant      crypto.PublicKey
+	Statement      string
+	KYCAttestation types.Hash
+	Status         ApplicationStatus
+	Approvals      []crypto.PublicKey
+	AutoApproved   bool // Admitted by the minimum token rule
+	CreatedAt      int64
+	DecidedAt      int64
+}
+
+// StatusChange is a pending vote to suspend or reinstate a member
+type StatusChange struct {
+	Member    crypto.PublicKey
+	Status    MembershipStatus
+	Reason    string
+	Votes     []crypto.PublicKey
+	CreatedAt int64
+}
+
+// MembershipManager runs the membership application flow and tracks the
+// status of members on their token holder records
+type MembershipManager struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	applications     map[string]*MembershipApplication
+	statusChanges    map[string]*StatusChange
+	soulbound        *SoulboundRegistry // Membership tokens, nil for none
+}
+
+// NewMembershipManager creates a new membership manager
+func NewMembershipManager(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager) *MembershipManager {
+	return &MembershipManager{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		applications:     make(map[string]*MembershipApplication),
+		statusChanges:    make(map[string]*StatusChange),
+	}
+}
+
+// ProcessJoinRequestTx records a membership application
+func (mm *MembershipManager) ProcessJoinRequestTx(tx *JoinRequestTx, applicant crypto.PublicKey) error {
+	applicantStr := applicant.String()
+	if holder, exists := mm.governanceState.TokenHolders[applicantStr]; exists && holder.Status != MembershipStatusExited {
+		return NewDAOError(ErrInvalidProposal, "applicant is already a member", map[string]interface{}{"status": holder.Status.String()})
+	}
+	if application, exists := mm.applications[applicantStr]; exists && application.Status == ApplicationStatusPending {
+		return NewDAOError(ErrInvalidProposal, "applicant already has a pending application", nil)
+	}
+
+	config := mm.parameterManager.GetParameterConfig()
+	if config.MembershipRequireKYC && tx.KYCAttestation.IsZero() {
+		return NewDAOError(ErrInvalidProposal, "membership requires a KYC attestation", nil)
+	}
+
+	if tx.Fee > 0 {
+		mm.tokenState.Balances.Sub(applicantStr, uint64(tx.Fee))
+	}
+
+	now := time.Now().Unix()
+	application := &MembershipApplication{
+		Applicant:      applicant,
+		Statement:      tx.Statement,
+		KYCAttestation: tx.KYCAttestation,
+		Status:         ApplicationStatusPending,
+		CreatedAt:      now,
+	}
+	mm.applications[applicantStr] = application
+
+	if config.MembershipMinTokens > 0 && mm.tokenState.Balances.Get(applicantStr) >= config.MembershipMinTokens {
+		application.AutoApproved = true
+		mm.admit(application, now)
+	}
+
+	return nil
+}
+
+// ProcessJoinApprovalTx adds a member's approval to a pending application,
+// admitting the applicant once enough members approved
+func (mm *MembershipManager) ProcessJoinApprovalTx(tx *JoinApprovalTx, approver crypto.PublicKey) error {
+	application, exists := mm.applications[tx.Applicant.String()]
+	if !exists || application.Status != ApplicationStatusPending {
+		return NewDAOError(ErrInvalidProposal, "no pending application for applicant", nil)
+	}
+	if approver.String() == tx.Applicant.String() {
+		return NewDAOError(ErrUnauthorized, "applicants cannot approve themselves", nil)
+	}
+	for _, existing := range application.Approvals {
+		if existing.String() == approver.String() {
+			return NewDAOError(ErrInvalidProposal, "application already approved by this member", nil)
+		}
+	}
+
+	mm.tokenState.Balances.Sub(approver.String(), uint64(tx.Fee))
+	application.Approvals = append(application.Approvals, approver)
+
+	if uint64(len(application.Approvals)) >= mm.parameterManager.GetParameterConfig().MembershipApprovals {
+		mm.admit(application, time.Now().Unix())
+	}
+
+	return nil
+}
+
+// ProcessMembershipStatusTx changes the status of a member. A member exits
+// on their own; suspending or reinstating a member is a vote of the other
+// active members that applies once enough of them agree.
+func (mm *MembershipManager) ProcessMembershipStatusTx(tx *MembershipStatusTx, sender crypto.PublicKey) error {
+	memberStr := tx.Member.String()
+	holder, exists := mm.governanceState.TokenHolders[memberStr]
+	if !exists || holder.Status == MembershipStatusExited {
+		return NewDAOError(ErrInvalidProposal, "not a member", nil)
+	}
+
+	if tx.Status == MembershipStatusExited {
+		if sender.String() != memberStr {
+			return NewDAOError(ErrUnauthorized, "members can only exit on their own", nil)
+		}
+		mm.tokenState.Balances.Sub(sender.String(), uint64(tx.Fee))
+		mm.setStatus(memberStr, holder, MembershipStatusExited)
+		if mm.soulbound != nil {
+			mm.soulbound.BurnMembership(sender, time.Now().Unix())
+		}
+		return nil
+	}
+
+	if sender.String() == memberStr {
+		return NewDAOError(ErrUnauthorized, "members cannot change their own status", nil)
+	}
+	if holder.Status == tx.Status {
+		return NewDAOError(ErrInvalidProposal, "member already has this status", map[string]interface{}{"status": holder.Status.String()})
+	}
+
+	key := memberStr + "/" + tx.Status.String()
+	change, exists := mm.statusChanges[key]
+	if !exists {
+		change = &StatusChange{
+			Member:    tx.Member,
+			Status:    tx.Status,
+			Reason:    tx.Reason,
+			CreatedAt: time.Now().Unix(),
+		}
+	}
+	for _, voter := range change.Votes {
+		if voter.String() == sender.String() {
+			return NewDAOError(ErrInvalidProposal, "status change already voted by this member", nil)
+		}
+	}
+
+	mm.tokenState.Balances.Sub(sender.String(), uint64(tx.Fee))
+	change.Votes = append(change.Votes, sender)
+	mm.statusChanges[key] = change
+
+	if uint64(len(change.Votes)) >= mm.parameterManager.GetParameterConfig().MembershipApprovals {
+		mm.setStatus(memberStr, holder, tx.Status)
+	}
+
+	return nil
+}
+
+// admit approves an application and makes the applicant an active member
+func (mm *MembershipManager) admit(application *MembershipApplication, now int64) {
+	application.Status = ApplicationStatusApproved
+	application.DecidedAt = now
+
+	applicantStr := application.Applicant.String()
+	holder, exists := mm.governanceState.TokenHolders[applicantStr]
+	if !exists {
+		holder = &TokenHolder{
+			Address: application.Applicant,
+			Balance: mm.tokenState.Balances.Get(applicantStr),
+		}
+		mm.governanceState.TokenHolders[applicantStr] = holder
+	}
+	holder.JoinedAt = now
+	holder.LastActive = now
+	holder.KYCAttestation = application.KYCAttestation
+	mm.setStatus(applicantStr, holder, MembershipStatusActive)
+	if mm.soulbound != nil {
+		mm.soulbound.MintMembership(application.Applicant, now)
+	}
+}
+
+// setStatus updates the status of a member and drops the votes it settled
+func (mm *MembershipManager) setStatus(memberStr string, holder *TokenHolder, status MembershipStatus) {
+	holder.Status = status
+
+	for _, pending := range []MembershipStatus{MembershipStatusActive, MembershipStatusSuspended} {
+		delete(mm.statusChanges, memberStr+"/"+pending.String())
+	}
+}
+
+// GetApplication returns the latest application of an applicant
+func (mm *MembershipManager) GetApplication(applicant crypto.PublicKey) (*MembershipApplication, bool) {
+	application, exists := mm.applications[applicant.String()]
+	return application, exists
+}
+
+// ListApplications returns the applications with the given status, all of
+// them for an empty status, newest first
+func (mm *MembershipManager) ListApplications(status ApplicationStatus) []*MembershipApplication {
+	var applications []*MembershipApplication
+	for _, application := range mm.applications {
+		if status == "" || application.Status == status {
+			applications = append(applications, application)
+		}
+	}
+
+	sort.Slice(applications, func(i, j int) bool {
+		if applications[i].CreatedAt != applications[j].CreatedAt {
+			return applications[i].CreatedAt > applications[j].CreatedAt
+		}
+		return applications[i].Applicant.String() < applications[j].Applicant.String()
+	})
+	return applications
+}
+
+// GetStatusChanges returns the pending suspension and reinstatement votes
+// for a member
+func (mm *MembershipManager) GetStatusChanges(member crypto.PublicKey) []*StatusChange {
+	var changes []*StatusChange
+	for _, status := range []MembershipStatus{MembershipStatusActive, MembershipStatusSuspended} {
+		if change, exists := mm.statusChanges[member.String()+"/"+status.String()]; exists {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
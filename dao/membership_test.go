@@ -0,0 +1,144 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMembership(t *testing.T) (*DAO, []crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	members := make([]crypto.PublicKey, 3)
+	distribution := make(map[string]uint64)
+	for i := range members {
+		members[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[members[i].String()] = 10000
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	return dao, members
+}
+
+func TestMembership_ApprovalFlow(t *testing.T) {
+	dao, members := setupMembership(t)
+	applicant := crypto.GeneratePrivateKey().PublicKey()
+
+	// Existing members cannot apply
+	assert.Error(t, dao.ProcessDAOTransaction(&JoinRequestTx{Statement: "Again"}, members[0], types.Hash{0x01}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinRequestTx{Statement: "I write docs"}, applicant, types.Hash{0x02}))
+	assert.Error(t, dao.ProcessDAOTransaction(&JoinRequestTx{Statement: "Twice"}, applicant, types.Hash{0x03}))
+	assert.Len(t, dao.ListMembershipApplications(ApplicationStatusPending), 1)
+
+	_, isMember := dao.GetMembershipStatus(applicant)
+	assert.False(t, isMember)
+
+	// Applicants cannot approve themselves and members approve once
+	assert.Error(t, dao.ProcessDAOTransaction(&JoinApprovalTx{Applicant: applicant}, applicant, types.Hash{0x04}))
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinApprovalTx{Fee: 10, Applicant: applicant}, members[0], types.Hash{0x05}))
+	assert.Error(t, dao.ProcessDAOTransaction(&JoinApprovalTx{Fee: 10, Applicant: applicant}, members[0], types.Hash{0x06}))
+
+	application, exists := dao.GetMembershipApplication(applicant)
+	require.True(t, exists)
+	assert.Equal(t, ApplicationStatusPending, application.Status)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinApprovalTx{Fee: 10, Applicant: applicant}, members[1], types.Hash{0x07}))
+	assert.Equal(t, ApplicationStatusApproved, application.Status)
+	assert.Equal(t, uint64(9990), dao.TokenState.Balances.Get(members[1].String()))
+
+	status, isMember := dao.GetMembershipStatus(applicant)
+	assert.True(t, isMember)
+	assert.Equal(t, MembershipStatusActive, status)
+	assert.Empty(t, dao.ListMembershipApplications(ApplicationStatusPending))
+}
+
+func TestMembership_AutoAdmission(t *testing.T) {
+	dao, _ := setupMembership(t)
+	dao.ParameterManager.GetParameterConfig().MembershipMinTokens = 500
+	dao.ParameterManager.GetParameterConfig().MembershipRequireKYC = true
+
+	applicant := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(applicant.String(), 600)
+
+	assert.Error(t, dao.ProcessDAOTransaction(&JoinRequestTx{}, applicant, types.Hash{0x01}))
+
+	attestation := types.Hash{0xaa}
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinRequestTx{Fee: 50, KYCAttestation: attestation}, applicant, types.Hash{0x02}))
+
+	application, _ := dao.GetMembershipApplication(applicant)
+	assert.True(t, application.AutoApproved)
+	holder, exists := dao.GetTokenHolder(applicant)
+	require.True(t, exists)
+	assert.Equal(t, attestation, holder.KYCAttestation)
+	assert.Equal(t, uint64(550), holder.Balance)
+
+	// Below the minimum the application waits for approvals
+	small := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(small.String(), 100)
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinRequestTx{KYCAttestation: attestation}, small, types.Hash{0x03}))
+	application, _ = dao.GetMembershipApplication(small)
+	assert.Equal(t, ApplicationStatusPending, application.Status)
+}
+
+func TestMembership_StatusChanges(t *testing.T) {
+	dao, members := setupMembership(t)
+	target := members[2]
+
+	// Members cannot suspend themselves
+	suspend := &MembershipStatusTx{Member: target, Status: MembershipStatusSuspended, Reason: "Spam"}
+	assert.Error(t, dao.ProcessDAOTransaction(suspend, target, types.Hash{0x01}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(suspend, members[0], types.Hash{0x02}))
+	assert.Len(t, dao.Membership.GetStatusChanges(target), 1)
+	status, _ := dao.GetMembershipStatus(target)
+	assert.Equal(t, MembershipStatusActive, status)
+
+	require.NoError(t, dao.ProcessDAOTransaction(suspend, members[1], types.Hash{0x03}))
+	status, _ = dao.GetMembershipStatus(target)
+	assert.Equal(t, MembershipStatusSuspended, status)
+	assert.Empty(t, dao.Membership.GetStatusChanges(target))
+
+	// Suspended members cannot propose or vote
+	proposalID := types.Hash{0x10}
+	require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+		Fee: 100, Title: "Signal", Description: "Roadmap", ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple,
+		StartTime: 0, EndTime: 1 << 40, Threshold: 5100,
+	}, members[0], proposalID))
+	err := dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposalID, Choice: VoteChoiceYes, Weight: 100}, target, types.Hash{0x11})
+	assert.Equal(t, ErrNotActiveMemberError, err)
+
+	// Reinstatement takes the same number of votes
+	reinstate := &MembershipStatusTx{Member: target, Status: MembershipStatusActive}
+	require.NoError(t, dao.ProcessDAOTransaction(reinstate, members[0], types.Hash{0x04}))
+	require.NoError(t, dao.ProcessDAOTransaction(reinstate, members[1], types.Hash{0x05}))
+	status, _ = dao.GetMembershipStatus(target)
+	assert.Equal(t, MembershipStatusActive, status)
+}
+
+func TestMembership_Exit(t *testing.T) {
+	dao, members := setupMembership(t)
+	member := members[0]
+
+	exit := &MembershipStatusTx{Member: member, Status: MembershipStatusExited}
+	assert.Error(t, dao.ProcessDAOTransaction(exit, members[1], types.Hash{0x01}))
+	require.NoError(t, dao.ProcessDAOTransaction(exit, member, types.Hash{0x02}))
+
+	status, _ := dao.GetMembershipStatus(member)
+	assert.Equal(t, MembershipStatusExited, status)
+	assert.Equal(t, "exited", status.String())
+
+	// Exited members can apply again
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinRequestTx{Statement: "Back"}, member, types.Hash{0x03}))
+	application, _ := dao.GetMembershipApplication(member)
+	assert.Equal(t, ApplicationStatusPending, application.Status)
+
+	parsed, ok := ParseMembershipStatus("suspended")
+	assert.True(t, ok)
+	assert.Equal(t, MembershipStatusSuspended, parsed)
+	_, ok = ParseMembershipStatus("banned")
+	assert.False(t, ok)
+}
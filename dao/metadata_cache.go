@@ -0,0 +1,209 @@
+package dao
+
+import (
+	"container/list"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MetadataCacheConfig configures the proposal metadata cache
+type MetadataCacheConfig struct {
+	MaxEntries int           // Entries kept in memory, 1024 when zero
+	MaxBytes   int64         // Encoded size kept in memory, unlimited when zero
+	TTL        time.Duration // How long entries are served, 1 hour when zero
+	Dir        string        // Entries are also kept on disk here when set
+}
+
+// MetadataCacheStats summarises cache activity
+type MetadataCacheStats struct {
+	Entries   int    `json:"entries"`
+	Bytes     int64  `json:"bytes"`
+	Hits      uint64 `json:"hits"`
+	DiskHits  uint64 `json:"disk_hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// metadataCacheEntry is a cached metadata document
+type metadataCacheEntry struct {
+	hash      types.Hash
+	metadata  *ProposalMetadata
+	size      int64
+	expiresAt time.Time
+}
+
+// MetadataCache keeps retrieved proposal metadata in a size-bounded LRU
+// backed by an optional disk cache, so reads skip IPFS
+type MetadataCache struct {
+	config    MetadataCacheConfig
+	entries   map[types.Hash]*list.Element
+	lru       *list.List // Most recently used first
+	bytes     int64
+	hits      uint64
+	diskHits  uint64
+	misses    uint64
+	evictions uint64
+	mu        sync.Mutex
+}
+
+// NewMetadataCache creates a new metadata cache, creating its disk
+// directory when configured
+func NewMetadataCache(config MetadataCacheConfig) (*MetadataCache, error) {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1024
+	}
+	if config.TTL <= 0 {
+		config.TTL = time.Hour
+	}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MetadataCache{
+		config:  config,
+		entries: make(map[types.Hash]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// Get returns cached metadata, reading through to the disk cache on a
+// memory miss
+func (mc *MetadataCache) Get(hash types.Hash) (*ProposalMetadata, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	now := time.Now()
+	if element, exists := mc.entries[hash]; exists {
+		entry := element.Value.(*metadataCacheEntry)
+		if now.Before(entry.expiresAt) {
+			mc.lru.MoveToFront(element)
+			mc.hits++
+			return copyMetadata(entry.metadata), true
+		}
+		mc.remove(element)
+	}
+
+	if metadata, size, storedAt, ok := mc.readDisk(hash); ok && now.Before(storedAt.Add(mc.config.TTL)) {
+		mc.add(hash, metadata, size, storedAt.Add(mc.config.TTL))
+		mc.diskHits++
+		return copyMetadata(metadata), true
+	}
+
+	mc.misses++
+	return nil, false
+}
+
+// Put caches metadata in memory and on disk
+func (mc *MetadataCache) Put(hash types.Hash, metadata *ProposalMetadata) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if element, exists := mc.entries[hash]; exists {
+		mc.remove(element)
+	}
+	mc.add(hash, copyMetadata(metadata), int64(len(data)), time.Now().Add(mc.config.TTL))
+
+	// The disk cache is best effort, a failed write only costs a later miss
+	if mc.config.Dir != "" {
+		os.WriteFile(mc.diskPath(hash), data, 0o644)
+	}
+}
+
+// Invalidate drops metadata from memory and disk
+func (mc *MetadataCache) Invalidate(hash types.Hash) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if element, exists := mc.entries[hash]; exists {
+		mc.remove(element)
+	}
+	if mc.config.Dir != "" {
+		os.Remove(mc.diskPath(hash))
+	}
+}
+
+// GetStats returns cache counters
+func (mc *MetadataCache) GetStats() MetadataCacheStats {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	return MetadataCacheStats{
+		Entries:   mc.lru.Len(),
+		Bytes:     mc.bytes,
+		Hits:      mc.hits,
+		DiskHits:  mc.diskHits,
+		Misses:    mc.misses,
+		Evictions: mc.evictions,
+	}
+}
+
+// add inserts an entry and evicts the least recently used ones beyond the
+// limits (assumes lock is held)
+func (mc *MetadataCache) add(hash types.Hash, metadata *ProposalMetadata, size int64, expiresAt time.Time) {
+	mc.entries[hash] = mc.lru.PushFront(&metadataCacheEntry{
+		hash:      hash,
+		metadata:  metadata,
+		size:      size,
+		expiresAt: expiresAt,
+	})
+	mc.bytes += size
+
+	for mc.lru.Len() > 1 && (mc.lru.Len() > mc.config.MaxEntries || (mc.config.MaxBytes > 0 && mc.bytes > mc.config.MaxBytes)) {
+		mc.remove(mc.lru.Back())
+		mc.evictions++
+	}
+}
+
+// remove drops an entry from memory (assumes lock is held)
+func (mc *MetadataCache) remove(element *list.Element) {
+	entry := mc.lru.Remove(element).(*metadataCacheEntry)
+	delete(mc.entries, entry.hash)
+	mc.bytes -= entry.size
+}
+
+func (mc *MetadataCache) readDisk(hash types.Hash) (*ProposalMetadata, int64, time.Time, bool) {
+	if mc.config.Dir == "" {
+		return nil, 0, time.Time{}, false
+	}
+
+	path := mc.diskPath(hash)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, time.Time{}, false
+	}
+
+	var metadata ProposalMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, 0, time.Time{}, false
+	}
+	return &metadata, int64(len(data)), info.ModTime(), true
+}
+
+func (mc *MetadataCache) diskPath(hash types.Hash) string {
+	return filepath.Join(mc.config.Dir, hash.String()+".json")
+}
+
+// copyMetadata copies metadata so callers cannot modify cached entries
+func copyMetadata(metadata *ProposalMetadata) *ProposalMetadata {
+	copied := *metadata
+	copied.Documents = append([]DocumentReference(nil), metadata.Documents...)
+	copied.Links = append([]LinkReference(nil), metadata.Links...)
+	copied.Tags = append([]string(nil), metadata.Tags...)
+	return &copied
+}
@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataCache_LRU(t *testing.T) {
+	cache, err := NewMetadataCache(MetadataCacheConfig{MaxEntries: 2})
+	require.NoError(t, err)
+
+	cache.Put(types.Hash{1}, &ProposalMetadata{Title: "one"})
+	cache.Put(types.Hash{2}, &ProposalMetadata{Title: "two"})
+
+	// Reading the first entry makes the second the least recently used
+	metadata, ok := cache.Get(types.Hash{1})
+	require.True(t, ok)
+	assert.Equal(t, "one", metadata.Title)
+
+	cache.Put(types.Hash{3}, &ProposalMetadata{Title: "three"})
+	_, ok = cache.Get(types.Hash{2})
+	assert.False(t, ok)
+	_, ok = cache.Get(types.Hash{1})
+	assert.True(t, ok)
+
+	stats := cache.GetStats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+
+	// Cached entries cannot be modified through returned copies
+	metadata.Tags = append(metadata.Tags, "changed")
+	metadata.Title = "changed"
+	metadata, _ = cache.Get(types.Hash{1})
+	assert.Equal(t, "one", metadata.Title)
+	assert.Empty(t, metadata.Tags)
+}
+
+func TestMetadataCache_SizeLimitAndTTL(t *testing.T) {
+	cache, err := NewMetadataCache(MetadataCacheConfig{MaxBytes: 300, TTL: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	description := strings.Repeat("x", 100)
+	for i := byte(1); i <= 3; i++ {
+		cache.Put(types.Hash{i}, &ProposalMetadata{Title: "large", Description: description})
+	}
+	stats := cache.GetStats()
+	assert.LessOrEqual(t, stats.Bytes, int64(300))
+	assert.Less(t, stats.Entries, 3)
+
+	_, ok := cache.Get(types.Hash{3})
+	require.True(t, ok)
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = cache.Get(types.Hash{3})
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.GetStats().Entries)
+}
+
+func TestMetadataCache_Disk(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewMetadataCache(MetadataCacheConfig{Dir: dir})
+	require.NoError(t, err)
+	cache.Put(types.Hash{1}, &ProposalMetadata{Title: "persisted", SchemaVersion: CurrentMetadataSchemaVersion})
+
+	// A restarted node reads the disk cache
+	restarted, err := NewMetadataCache(MetadataCacheConfig{Dir: dir, TTL: time.Hour})
+	require.NoError(t, err)
+	metadata, ok := restarted.Get(types.Hash{1})
+	require.True(t, ok)
+	assert.Equal(t, "persisted", metadata.Title)
+	assert.Equal(t, uint64(1), restarted.GetStats().DiskHits)
+
+	// Expired disk entries are ignored
+	path := filepath.Join(dir, types.Hash{1}.String()+".json")
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	expired, err := NewMetadataCache(MetadataCacheConfig{Dir: dir, TTL: time.Hour})
+	require.NoError(t, err)
+	_, ok = expired.Get(types.Hash{1})
+	assert.False(t, ok)
+
+	restarted.Invalidate(types.Hash{1})
+	assert.NoFileExists(t, path)
+}
+
+func TestIPFSClient_MetadataCache(t *testing.T) {
+	node := newTestIPFSNode(t)
+	client := NewIPFSClient(strings.TrimPrefix(node.URL, "http://"))
+	cache, err := NewMetadataCache(MetadataCacheConfig{})
+	require.NoError(t, err)
+	client.EnableMetadataCache(cache)
+
+	hash, err := client.UploadProposalMetadata(&ProposalMetadata{Title: "Budget", Description: "Q3"})
+	require.NoError(t, err)
+
+	// Uploaded metadata is served without the node
+	node.Close()
+	metadata, err := client.RetrieveProposalMetadata(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "Budget", metadata.Title)
+	assert.Equal(t, uint64(1), cache.GetStats().Hits)
+}
+
+func TestDAO_WarmMetadataCache(t *testing.T) {
+	node := newTestIPFSNode(t)
+	dao := NewDAO("GOV", "Governance Token", 18)
+	dao.IPFSClient = NewIPFSClient(strings.TrimPrefix(node.URL, "http://"))
+
+	hash, err := dao.IPFSClient.UploadProposalMetadata(&ProposalMetadata{Title: "Budget", Description: "Q3"})
+	require.NoError(t, err)
+	dao.GovernanceState.Proposals[types.Hash{0xAA}] = &Proposal{
+		ID:           types.Hash{0xAA},
+		Creator:      crypto.GeneratePrivateKey().PublicKey(),
+		Status:       ProposalStatusActive,
+		MetadataHash: hash,
+	}
+
+	cache, err := NewMetadataCache(MetadataCacheConfig{})
+	require.NoError(t, err)
+	dao.IPFSClient.EnableMetadataCache(cache)
+
+	assert.Equal(t, 1, dao.WarmMetadataCache())
+	assert.Equal(t, 1, cache.GetStats().Entries)
+
+	metadata, err := dao.GetProposalMetadata(types.Hash{0xAA})
+	require.NoError(t, err)
+	assert.Equal(t, "Budget", metadata.Title)
+	assert.Equal(t, uint64(1), cache.GetStats().Hits)
+}
@@ -0,0 +1,282 @@
+package dao
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CurrentMetadataSchemaVersion is the schema version new proposal metadata
+// is written with. Metadata without a schema_version is version 1.
+const CurrentMetadataSchemaVersion = 2
+
+// JSONSchema is the subset of JSON Schema proposal metadata is described
+// with. It marshals to a standard JSON Schema document clients can use.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Type                 string                 `json:"type"`
+	Required             []string               `json:"required,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	MinLength            int                    `json:"minLength,omitempty"`
+	MaxLength            int                    `json:"maxLength,omitempty"`
+	MaxItems             int                    `json:"maxItems,omitempty"`
+}
+
+// metadataSchemas holds the schema of every metadata version
+var metadataSchemas = map[int]*JSONSchema{
+	1: metadataSchemaV1(),
+	2: metadataSchemaV2(),
+}
+
+// metadataMigrations upgrade metadata from a schema version to the next
+var metadataMigrations = map[int]func(*ProposalMetadata){
+	1: migrateMetadataV1,
+}
+
+// MetadataSchema returns the JSON schema of a metadata schema version
+func MetadataSchema(version int) (*JSONSchema, bool) {
+	schema, exists := metadataSchemas[version]
+	return schema, exists
+}
+
+// MetadataSchemaVersions returns the known metadata schema versions
+func MetadataSchemaVersions() []int {
+	versions := make([]int, 0, len(metadataSchemas))
+	for version := range metadataSchemas {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// ValidateProposalMetadata validates encoded metadata against the schema of
+// its version and returns that version
+func ValidateProposalMetadata(data []byte) (int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return 0, fmt.Errorf("invalid metadata JSON: %w", err)
+	}
+
+	fields, ok := document.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("metadata must be a JSON object")
+	}
+
+	version := 1
+	if raw, exists := fields["schema_version"]; exists {
+		number, ok := raw.(json.Number)
+		if !ok {
+			return 0, fmt.Errorf("schema_version must be an integer")
+		}
+		parsed, err := number.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("schema_version must be an integer")
+		}
+		version = int(parsed)
+	}
+
+	schema, exists := MetadataSchema(version)
+	if !exists {
+		return version, fmt.Errorf("unsupported metadata schema version %d, this node supports up to %d", version, CurrentMetadataSchemaVersion)
+	}
+
+	return version, schema.validate("metadata", document)
+}
+
+// MigrateProposalMetadata upgrades metadata to the current schema version
+func MigrateProposalMetadata(metadata *ProposalMetadata) error {
+	if metadata.SchemaVersion == 0 {
+		metadata.SchemaVersion = 1
+	}
+	if metadata.SchemaVersion > CurrentMetadataSchemaVersion {
+		return fmt.Errorf("unsupported metadata schema version %d", metadata.SchemaVersion)
+	}
+
+	for metadata.SchemaVersion < CurrentMetadataSchemaVersion {
+		migrate, exists := metadataMigrations[metadata.SchemaVersion]
+		if !exists {
+			return fmt.Errorf("no migration from metadata schema version %d", metadata.SchemaVersion)
+		}
+		migrate(metadata)
+		metadata.SchemaVersion++
+	}
+
+	return nil
+}
+
+// migrateMetadataV1 normalizes tags to unique lowercase values and fills in
+// the revision of metadata written before it was always set
+func migrateMetadataV1(metadata *ProposalMetadata) {
+	metadata.Tags = normalizeTags(metadata.Tags)
+	if metadata.Version == "" {
+		metadata.Version = "1.0"
+	}
+}
+
+// normalizeTags lowercases, trims and deduplicates tags, keeping their order
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// validate checks a decoded JSON value against the schema
+func (s *JSONSchema) validate(path string, value interface{}) error {
+	switch s.Type {
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		for _, name := range s.Required {
+			if _, exists := fields[name]; !exists {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, exists := s.Properties[name]
+			if !exists {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s.%s is not allowed", path, name)
+				}
+				continue
+			}
+			if err := property.validate(path+"."+name, fields[name]); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+		if s.MaxItems > 0 && len(items) > s.MaxItems {
+			return fmt.Errorf("%s must have at most %d items", path, s.MaxItems)
+		}
+		for i, item := range items {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+		length := len([]rune(text))
+		if length < s.MinLength {
+			return fmt.Errorf("%s must be at least %d characters", path, s.MinLength)
+		}
+		if s.MaxLength > 0 && length > s.MaxLength {
+			return fmt.Errorf("%s must be at most %d characters", path, s.MaxLength)
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+		if _, err := number.Int64(); err != nil {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+	}
+
+	return nil
+}
+
+func metadataSchemaV1() *JSONSchema {
+	return &JSONSchema{
+		Schema: "https://json-schema.org/draft/2020-12/schema",
+		ID:     "https://bockchain.io/schemas/proposal-metadata/1",
+		Type:   "object",
+		Required: []string{
+			"title", "description", "version", "created_at", "checksum",
+		},
+		Properties: map[string]*JSONSchema{
+			"title":       {Type: "string"},
+			"description": {Type: "string"},
+			"details":     {Type: "string"},
+			"documents": {Type: "array", Items: &JSONSchema{
+				Type:     "object",
+				Required: []string{"name", "hash", "size"},
+				Properties: map[string]*JSONSchema{
+					"name":        {Type: "string"},
+					"description": {Type: "string"},
+					"hash":        {Type: "string"},
+					"size":        {Type: "integer"},
+					"mime_type":   {Type: "string"},
+				},
+			}},
+			"links": {Type: "array", Items: &JSONSchema{
+				Type:     "object",
+				Required: []string{"title", "url"},
+				Properties: map[string]*JSONSchema{
+					"title":       {Type: "string"},
+					"url":         {Type: "string"},
+					"description": {Type: "string"},
+				},
+			}},
+			"tags":       {Type: "array", Items: &JSONSchema{Type: "string"}},
+			"version":    {Type: "string"},
+			"created_at": {Type: "integer"},
+			"updated_at": {Type: "integer"},
+			"checksum":   {Type: "string"},
+		},
+	}
+}
+
+// metadataSchemaV2 adds schema_version and encrypted documents, bounds the
+// text fields and rejects unknown fields
+func metadataSchemaV2() *JSONSchema {
+	closed := false
+	schema := metadataSchemaV1()
+
+	schema.ID = "https://bockchain.io/schemas/proposal-metadata/2"
+	schema.Required = append([]string{"schema_version"}, schema.Required...)
+	schema.AdditionalProperties = &closed
+	schema.Properties["schema_version"] = &JSONSchema{Type: "integer"}
+	schema.Properties["title"] = &JSONSchema{Type: "string", MinLength: 1, MaxLength: 200}
+	schema.Properties["description"] = &JSONSchema{Type: "string", MinLength: 1, MaxLength: 5000}
+	schema.Properties["tags"] = &JSONSchema{Type: "array", MaxItems: 20, Items: &JSONSchema{Type: "string", MinLength: 1, MaxLength: 50}}
+
+	documents := schema.Properties["documents"].Items
+	documents.Properties["encryption"] = &JSONSchema{
+		Type:     "object",
+		Required: []string{"algorithm", "nonce", "roles", "wrapped_keys"},
+		Properties: map[string]*JSONSchema{
+			"algorithm": {Type: "string"},
+			"nonce":     {Type: "string"},
+			"roles":     {Type: "string"}, // Base64 role bytes
+
+			"wrapped_keys": {Type: "object"},
+		},
+	}
+
+	return schema
+}
@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyMetadata encodes checksummed metadata the way nodes wrote it before
+// schema versions existed
+func legacyMetadata(t *testing.T, metadata *ProposalMetadata) []byte {
+	unsigned, err := json.MarshalIndent(metadata, "", "  ")
+	require.NoError(t, err)
+	checksum := sha256.Sum256(unsigned)
+	metadata.Checksum = hex.EncodeToString(checksum[:])
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	require.NoError(t, err)
+	return data
+}
+
+func TestValidateProposalMetadata(t *testing.T) {
+	legacy := legacyMetadata(t, &ProposalMetadata{Title: "Legacy", Description: "Written by v1", Version: "1.0", CreatedAt: 1, Tags: []string{"Gov"}})
+	version, err := ValidateProposalMetadata(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	valid := `{"schema_version": 2, "title": "Budget", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": "",
+		"documents": [{"name": "plan.pdf", "hash": "QmPlan", "size": 10}]}`
+	version, err = ValidateProposalMetadata([]byte(valid))
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	invalid := map[string]string{
+		"not an object":     `[]`,
+		"future schema":     `{"schema_version": 3}`,
+		"missing title":     `{"schema_version": 2, "description": "Q3", "version": "1.0", "created_at": 1, "checksum": ""}`,
+		"empty title":       `{"schema_version": 2, "title": "", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": ""}`,
+		"long title":        `{"schema_version": 2, "title": "` + strings.Repeat("a", 201) + `", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": ""}`,
+		"unknown field":     `{"schema_version": 2, "title": "Budget", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": "", "extra": true}`,
+		"fractional time":   `{"schema_version": 2, "title": "Budget", "description": "Q3", "version": "1.0", "created_at": 1.5, "checksum": ""}`,
+		"document size":     `{"schema_version": 2, "title": "Budget", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": "", "documents": [{"name": "a", "hash": "Qm", "size": "10"}]}`,
+		"document hash":     `{"schema_version": 2, "title": "Budget", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": "", "documents": [{"name": "a", "size": 10}]}`,
+		"tag type":          `{"schema_version": 2, "title": "Budget", "description": "Q3", "version": "1.0", "created_at": 1, "checksum": "", "tags": [1]}`,
+		"v1 created_at str": `{"title": "Budget", "description": "Q3", "version": "1.0", "created_at": "now", "checksum": ""}`,
+	}
+	for name, data := range invalid {
+		_, err := ValidateProposalMetadata([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestMigrateProposalMetadata(t *testing.T) {
+	metadata := &ProposalMetadata{Title: "Legacy", Tags: []string{" Treasury", "gov", "GOV", ""}}
+	require.NoError(t, MigrateProposalMetadata(metadata))
+
+	assert.Equal(t, CurrentMetadataSchemaVersion, metadata.SchemaVersion)
+	assert.Equal(t, []string{"treasury", "gov"}, metadata.Tags)
+	assert.Equal(t, "1.0", metadata.Version)
+
+	assert.Error(t, MigrateProposalMetadata(&ProposalMetadata{SchemaVersion: CurrentMetadataSchemaVersion + 1}))
+}
+
+func TestMetadataSchema_JSON(t *testing.T) {
+	assert.Equal(t, []int{1, 2}, MetadataSchemaVersions())
+
+	schema, exists := MetadataSchema(CurrentMetadataSchemaVersion)
+	require.True(t, exists)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"$schema":"https://json-schema.org/draft/2020-12/schema"`)
+	assert.Contains(t, string(data), `"additionalProperties":false`)
+
+	// Older schemas are left untouched by newer ones
+	v1, _ := MetadataSchema(1)
+	assert.NotContains(t, v1.Properties, "schema_version")
+	assert.NotContains(t, v1.Properties["documents"].Items.Properties, "encryption")
+}
+
+func TestIPFSClient_MetadataSchemaVersions(t *testing.T) {
+	node := newTestIPFSNode(t)
+	client := NewIPFSClient(strings.TrimPrefix(node.URL, "http://"))
+
+	// New metadata is written with the current schema
+	hash, err := client.UploadProposalMetadata(&ProposalMetadata{Title: "Budget", Description: "Q3", Tags: []string{"Treasury", "treasury"}})
+	require.NoError(t, err)
+
+	metadata, err := client.RetrieveProposalMetadata(hash)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentMetadataSchemaVersion, metadata.SchemaVersion)
+	assert.Equal(t, []string{"treasury"}, metadata.Tags)
+
+	// Updates keep verifying after re-upload
+	updated, err := client.UpdateProposalMetadata(hash, &ProposalMetadata{Details: "Revised"})
+	require.NoError(t, err)
+	metadata, err = client.RetrieveProposalMetadata(updated)
+	require.NoError(t, err)
+	assert.Equal(t, "Revised", metadata.Details)
+	assert.Equal(t, "1.1", metadata.Version)
+
+	// Legacy metadata is migrated on retrieval
+	legacy := legacyMetadata(t, &ProposalMetadata{Title: "Legacy", Description: "Written by v1", Version: "1.0", CreatedAt: 1, Tags: []string{"GOV"}})
+	cid, err := client.put(legacy)
+	require.NoError(t, err)
+
+	metadata, err = client.RetrieveProposalMetadata(client.ipfsHashToTypesHash(cid))
+	require.NoError(t, err)
+	assert.Equal(t, CurrentMetadataSchemaVersion, metadata.SchemaVersion)
+	assert.Equal(t, []string{"gov"}, metadata.Tags)
+
+	// Invalid metadata is rejected before upload
+	_, err = client.UploadProposalMetadata(&ProposalMetadata{Description: "Untitled"})
+	assert.Error(t, err)
+}
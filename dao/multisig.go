@@ -0,0 +1,259 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxMultisigOwners bounds the owners of a multisig account
+const MaxMultisigOwners = 32
+
+// MultisigAccount is an account controlled by a threshold of its owners. It
+// holds tokens and takes part in governance like any other member.
+type MultisigAccount struct {
+	ID        types.Hash
+	Name      string
+	Owners    []crypto.PublicKey
+	Threshold uint8
+	Creator   crypto.PublicKey
+	CreatedAt int64
+}
+
+// Account returns the member key the multisig acts as
+func (m *MultisigAccount) Account() crypto.PublicKey {
+	return MultisigAccountKey(m.ID)
+}
+
+// IsOwner reports whether key is one of the owners
+func (m *MultisigAccount) IsOwner(key crypto.PublicKey) bool {
+	keyStr := key.String()
+	for _, owner := range m.Owners {
+		if owner.String() == keyStr {
+			return true
+		}
+	}
+	return false
+}
+
+// MultisigTransaction is a DAO transaction collecting owner approvals
+type MultisigTransaction struct {
+	ID          types.Hash
+	MultisigID  types.Hash
+	Description string
+	Tx          interface{}
+	Submitter   crypto.PublicKey
+	Approvals   []crypto.PublicKey
+	SubmittedAt int64
+	Executed    bool
+	ExecutedAt  int64
+	ExecutedIn  types.Hash // Transaction whose approval met the threshold
+}
+
+// HasApproved reports whether an owner approved the transaction
+func (t *MultisigTransaction) HasApproved(owner crypto.PublicKey) bool {
+	ownerStr := owner.String()
+	for _, approval := range t.Approvals {
+		if approval.String() == ownerStr {
+			return true
+		}
+	}
+	return false
+}
+
+// MultisigAccountKey returns the member key of a multisig account. It is the
+// 32 byte account ID, which can never collide with a 33 byte public key and
+// has no private key, so only the multisig itself can act as it.
+func MultisigAccountKey(id types.Hash) crypto.PublicKey {
+	key := make(crypto.PublicKey, len(id))
+	copy(key, id[:])
+	return key
+}
+
+// IsMultisigAccountKey reports whether a member key belongs to a multisig
+func IsMultisigAccountKey(key crypto.PublicKey) bool {
+	return len(key) == len(types.Hash{})
+}
+
+// MultisigManager keeps the multisig accounts and their pending transactions
+type MultisigManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	accounts        map[types.Hash]*MultisigAccount
+	transactions    map[types.Hash]*MultisigTransaction
+	executions      map[types.Hash]*MultisigTransaction // Keyed by ExecutedIn
+}
+
+// NewMultisigManager creates a new multisig manager
+func NewMultisigManager(governanceState *GovernanceState, tokenState *GovernanceToken) *MultisigManager {
+	return &MultisigManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		accounts:        make(map[types.Hash]*MultisigAccount),
+		transactions:    make(map[types.Hash]*MultisigTransaction),
+		executions:      make(map[types.Hash]*MultisigTransaction),
+	}
+}
+
+// ProcessMultisigCreateTx creates a multisig account identified by txHash
+func (mm *MultisigManager) ProcessMultisigCreateTx(tx *MultisigCreateTx, creator crypto.PublicKey, txHash types.Hash) error {
+	if _, exists := mm.accounts[txHash]; exists {
+		return NewDAOError(ErrInvalidProposal, "multisig account already exists", nil)
+	}
+
+	mm.tokenState.Balances.Sub(creator.String(), uint64(tx.Fee))
+
+	mm.accounts[txHash] = &MultisigAccount{
+		ID:        txHash,
+		Name:      tx.Name,
+		Owners:    append([]crypto.PublicKey(nil), tx.Owners...),
+		Threshold: tx.Threshold,
+		Creator:   creator,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	return nil
+}
+
+// CheckSubmit returns the account a submission acts for, verifying that the
+// submitter owns it
+func (mm *MultisigManager) CheckSubmit(tx *MultisigSubmitTx, submitter crypto.PublicKey) (*MultisigAccount, error) {
+	account, exists := mm.accounts[tx.MultisigID]
+	if !exists {
+		return nil, ErrMultisigNotFoundError
+	}
+	if !account.IsOwner(submitter) {
+		return nil, NewDAOError(ErrUnauthorized, "only owners can submit multisig transactions", nil)
+	}
+
+	return account, nil
+}
+
+// RecordSubmit stores a submitted transaction with the submitter's
+// approval, executed reports whether that approval already executed it
+func (mm *MultisigManager) RecordSubmit(tx *MultisigSubmitTx, submitter crypto.PublicKey, txHash types.Hash, executed bool) *MultisigTransaction {
+	mm.tokenState.Balances.Sub(submitter.String(), uint64(tx.Fee))
+
+	now := time.Now().Unix()
+	pending := &MultisigTransaction{
+		ID:          txHash,
+		MultisigID:  tx.MultisigID,
+		Description: tx.Description,
+		Tx:          tx.Tx,
+		Submitter:   submitter,
+		Approvals:   []crypto.PublicKey{submitter},
+		SubmittedAt: now,
+	}
+	mm.transactions[txHash] = pending
+
+	if executed {
+		mm.markExecuted(pending, txHash, now)
+	}
+
+	return pending
+}
+
+// CheckSign returns the transaction an approval is for and the account it
+// acts for, verifying that the signer may still approve it
+func (mm *MultisigManager) CheckSign(tx *MultisigSignTx, signer crypto.PublicKey) (*MultisigTransaction, *MultisigAccount, error) {
+	account, exists := mm.accounts[tx.MultisigID]
+	if !exists {
+		return nil, nil, ErrMultisigNotFoundError
+	}
+
+	pending, exists := mm.transactions[tx.TxID]
+	if !exists || pending.MultisigID != tx.MultisigID {
+		return nil, nil, NewDAOError(ErrInvalidProposal, "multisig transaction not found", nil)
+	}
+	if pending.Executed {
+		return nil, nil, NewDAOError(ErrInvalidProposal, "multisig transaction already executed", nil)
+	}
+	if !account.IsOwner(signer) {
+		return nil, nil, NewDAOError(ErrUnauthorized, "only owners can sign multisig transactions", nil)
+	}
+	if pending.HasApproved(signer) {
+		return nil, nil, NewDAOError(ErrDuplicateVote, "owner already signed the multisig transaction", nil)
+	}
+
+	return pending, account, nil
+}
+
+// RecordSign adds an owner's approval, executed reports whether the
+// approval executed the transaction
+func (mm *MultisigManager) RecordSign(tx *MultisigSignTx, signer crypto.PublicKey, txHash types.Hash, executed bool) {
+	mm.tokenState.Balances.Sub(signer.String(), uint64(tx.Fee))
+
+	pending := mm.transactions[tx.TxID]
+	pending.Approvals = append(pending.Approvals, signer)
+
+	if executed {
+		mm.markExecuted(pending, txHash, time.Now().Unix())
+	}
+}
+
+func (mm *MultisigManager) markExecuted(pending *MultisigTransaction, txHash types.Hash, now int64) {
+	pending.Executed = true
+	pending.ExecutedAt = now
+	pending.ExecutedIn = txHash
+	mm.executions[txHash] = pending
+}
+
+// GetAccount returns a multisig account
+func (mm *MultisigManager) GetAccount(id types.Hash) (*MultisigAccount, bool) {
+	account, exists := mm.accounts[id]
+	return account, exists
+}
+
+// ListAccounts returns the multisig accounts, optionally only those owned
+// by owner, oldest first
+func (mm *MultisigManager) ListAccounts(owner crypto.PublicKey) []*MultisigAccount {
+	accounts := make([]*MultisigAccount, 0, len(mm.accounts))
+	for _, account := range mm.accounts {
+		if len(owner) > 0 && !account.IsOwner(owner) {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].CreatedAt != accounts[j].CreatedAt {
+			return accounts[i].CreatedAt < accounts[j].CreatedAt
+		}
+		return accounts[i].ID.String() < accounts[j].ID.String()
+	})
+	return accounts
+}
+
+// GetTransaction returns a multisig transaction
+func (mm *MultisigManager) GetTransaction(id types.Hash) (*MultisigTransaction, bool) {
+	pending, exists := mm.transactions[id]
+	return pending, exists
+}
+
+// GetTransactions returns the transactions of a multisig account, oldest
+// first
+func (mm *MultisigManager) GetTransactions(multisigID types.Hash) []*MultisigTransaction {
+	transactions := make([]*MultisigTransaction, 0)
+	for _, pending := range mm.transactions {
+		if pending.MultisigID == multisigID {
+			transactions = append(transactions, pending)
+		}
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		if transactions[i].SubmittedAt != transactions[j].SubmittedAt {
+			return transactions[i].SubmittedAt < transactions[j].SubmittedAt
+		}
+		return transactions[i].ID.String() < transactions[j].ID.String()
+	})
+	return transactions
+}
+
+// ExecutedBy returns the multisig transaction executed by a chain
+// transaction, if any
+func (mm *MultisigManager) ExecutedBy(txHash types.Hash) (*MultisigTransaction, bool) {
+	pending, exists := mm.executions[txHash]
+	return pending, exists
+}
@@ -0,0 +1,180 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMultisigDAO(t *testing.T) (*DAO, []crypto.PublicKey, *MultisigAccount) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	owners := []crypto.PublicKey{
+		crypto.GeneratePrivateKey().PublicKey(),
+		crypto.GeneratePrivateKey().PublicKey(),
+		crypto.GeneratePrivateKey().PublicKey(),
+	}
+	distribution := make(map[string]uint64)
+	for _, owner := range owners {
+		distribution[owner.String()] = 10000
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	createHash := types.Hash{0xAA}
+	create := &MultisigCreateTx{Fee: 10, Name: "Grants committee", Owners: owners, Threshold: 2}
+	require.NoError(t, dao.ApplyDAOTransaction(create, owners[0], createHash, 1))
+
+	account, exists := dao.GetMultisigAccount(createHash)
+	require.True(t, exists)
+
+	// Fund the multisig like any other member
+	transfer := &TokenTransferTx{Fee: 10, Recipient: account.Account(), Amount: 5000}
+	require.NoError(t, dao.ApplyDAOTransaction(transfer, owners[1], types.Hash{0xAB}, 1))
+
+	return dao, owners, account
+}
+
+func TestMultisig_Create(t *testing.T) {
+	dao, owners, account := setupMultisigDAO(t)
+
+	assert.Equal(t, "Grants committee", account.Name)
+	assert.Equal(t, uint8(2), account.Threshold)
+	assert.True(t, IsMultisigAccountKey(account.Account()))
+	assert.Equal(t, uint64(5000), dao.GetTokenBalance(account.Account()))
+	assert.Equal(t, uint64(9990), dao.GetTokenBalance(owners[0]))
+
+	assert.Len(t, dao.ListMultisigAccounts(owners[2]), 1)
+	assert.Empty(t, dao.ListMultisigAccounts(crypto.GeneratePrivateKey().PublicKey()))
+
+	invalid := []*MultisigCreateTx{
+		{Fee: 10, Name: "", Owners: owners, Threshold: 1},
+		{Fee: 10, Name: "none", Threshold: 1},
+		{Fee: 10, Name: "high", Owners: owners, Threshold: 4},
+		{Fee: 10, Name: "zero", Owners: owners, Threshold: 0},
+		{Fee: 10, Name: "dup", Owners: []crypto.PublicKey{owners[0], owners[0]}, Threshold: 1},
+		{Fee: 10, Name: "nested", Owners: []crypto.PublicKey{account.Account()}, Threshold: 1},
+	}
+	for i, tx := range invalid {
+		assert.Error(t, dao.ApplyDAOTransaction(tx, owners[0], types.Hash{0x10, byte(i)}, 1), tx.Name)
+	}
+}
+
+func TestMultisig_ExecutesAtThreshold(t *testing.T) {
+	dao, owners, account := setupMultisigDAO(t)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	submitHash := types.Hash{0x01}
+	submit := &MultisigSubmitTx{
+		Fee:         10,
+		MultisigID:  account.ID,
+		Description: "Pay the grantee",
+		Tx:          &TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 1000},
+	}
+	require.NoError(t, dao.ApplyDAOTransaction(submit, owners[0], submitHash, 2))
+
+	// One approval of two does not execute
+	pending := dao.GetMultisigTransactions(account.ID)
+	require.Len(t, pending, 1)
+	assert.False(t, pending[0].Executed)
+	assert.Equal(t, uint64(0), dao.GetTokenBalance(recipient))
+
+	// Owners cannot approve twice and outsiders cannot approve at all
+	sign := &MultisigSignTx{Fee: 10, MultisigID: account.ID, TxID: submitHash}
+	assert.Error(t, dao.ApplyDAOTransaction(sign, owners[0], types.Hash{0x02}, 2))
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(outsider.String(), 100)
+	assert.Error(t, dao.ApplyDAOTransaction(sign, outsider, types.Hash{0x03}, 2))
+
+	signHash := types.Hash{0x04}
+	require.NoError(t, dao.ApplyDAOTransaction(sign, owners[1], signHash, 3))
+
+	assert.True(t, pending[0].Executed)
+	assert.Equal(t, signHash, pending[0].ExecutedIn)
+	assert.Len(t, pending[0].Approvals, 2)
+	assert.Equal(t, uint64(1000), dao.GetTokenBalance(recipient))
+	assert.Equal(t, uint64(5000-1000-10), dao.GetTokenBalance(account.Account()))
+
+	// Executed transactions cannot be signed again
+	assert.Error(t, dao.ApplyDAOTransaction(sign, owners[2], types.Hash{0x05}, 3))
+
+	// The transfer shows up in the history of the multisig account
+	activity, total := dao.GetMemberActivity(account.Account(), []string{ActivityTypeTokenTransfer}, 0, 10)
+	require.Equal(t, 2, total) // Funding and payout
+	assert.Equal(t, submitHash, activity[0].TxHash)
+	assert.Equal(t, ActivityRoleSender, activity[0].Role)
+}
+
+func TestMultisig_FailedExecutionKeepsApprovals(t *testing.T) {
+	dao, owners, account := setupMultisigDAO(t)
+
+	// More than the multisig holds
+	submitHash := types.Hash{0x01}
+	submit := &MultisigSubmitTx{
+		Fee:        10,
+		MultisigID: account.ID,
+		Tx:         &TokenTransferTx{Fee: 10, Recipient: owners[2], Amount: 50000},
+	}
+	require.NoError(t, dao.ApplyDAOTransaction(submit, owners[0], submitHash, 2))
+
+	sign := &MultisigSignTx{Fee: 10, MultisigID: account.ID, TxID: submitHash}
+	require.Error(t, dao.ApplyDAOTransaction(sign, owners[1], types.Hash{0x02}, 2))
+
+	pending, exists := dao.MultisigManager.GetTransaction(submitHash)
+	require.True(t, exists)
+	assert.False(t, pending.Executed)
+	assert.Len(t, pending.Approvals, 1)
+	assert.Equal(t, uint64(10000-10-5000), dao.GetTokenBalance(owners[1]))
+}
+
+func TestMultisig_VotesAsMember(t *testing.T) {
+	dao, owners, account := setupMultisigDAO(t)
+
+	now := time.Now().Unix()
+	proposalHash := types.Hash{0x01}
+	proposal := &ProposalTx{
+		Fee:          10,
+		Title:        "Fund the docs",
+		Description:  "Fund the docs",
+		ProposalType: ProposalTypeGeneral,
+		VotingType:   VotingTypeSimple,
+		StartTime:    now - 1,
+		EndTime:      now + 7*24*3600,
+		Threshold:    5100,
+	}
+	require.NoError(t, dao.ApplyDAOTransaction(proposal, owners[2], proposalHash, 2))
+	require.NoError(t, dao.Processor.UpdateProposalStatus(proposalHash))
+
+	submitHash := types.Hash{0x02}
+	submit := &MultisigSubmitTx{
+		Fee:        10,
+		MultisigID: account.ID,
+		Tx:         &VoteTx{Fee: 10, ProposalID: proposalHash, Choice: VoteChoiceYes, Weight: 100},
+	}
+	require.NoError(t, dao.ApplyDAOTransaction(submit, owners[0], submitHash, 3))
+	require.NoError(t, dao.ApplyDAOTransaction(&MultisigSignTx{Fee: 10, MultisigID: account.ID, TxID: submitHash}, owners[2], types.Hash{0x03}, 3))
+
+	votes, exists := dao.GovernanceState.Votes[proposalHash]
+	require.True(t, exists)
+	assert.Contains(t, votes, account.Account().String())
+}
+
+func TestMultisig_RejectsNestedSubmissions(t *testing.T) {
+	dao, owners, account := setupMultisigDAO(t)
+
+	nested := &MultisigSubmitTx{
+		Fee:        10,
+		MultisigID: account.ID,
+		Tx:         &MultisigSignTx{Fee: 10, MultisigID: account.ID, TxID: types.Hash{0x01}},
+	}
+	assert.Error(t, dao.ApplyDAOTransaction(nested, owners[0], types.Hash{0x01}, 2))
+
+	empty := &MultisigSubmitTx{Fee: 10, MultisigID: account.ID}
+	assert.Error(t, dao.ApplyDAOTransaction(empty, owners[0], types.Hash{0x02}, 2))
+
+	unknown := &MultisigSubmitTx{Fee: 10, MultisigID: types.Hash{0xFF}, Tx: &TokenBurnTx{Fee: 10, Amount: 1}}
+	assert.Error(t, dao.ApplyDAOTransaction(unknown, owners[0], types.Hash{0x03}, 2))
+}
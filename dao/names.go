@@ -0,0 +1,272 @@
+package dao
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// Name length bounds, in bytes
+const (
+	MinNameLength = 3
+	MaxNameLength = 32
+)
+
+// NameRegistration maps a human-readable handle to a member's key until it
+// expires
+type NameRegistration struct {
+	Name         string
+	Owner        crypto.PublicKey
+	RegisteredAt int64
+	ExpiresAt    int64
+}
+
+// Active reports whether the registration has not expired
+func (nr *NameRegistration) Active(now int64) bool {
+	return now < nr.ExpiresAt
+}
+
+// NameRegistry is the on-chain registry of member handles. Each address
+// holds at most one active name, so a name also identifies its owner in
+// reverse. Expired names can be registered again by anyone.
+type NameRegistry struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	names            map[string]*NameRegistration
+	owners           map[string]string // Name by owner address
+}
+
+// NewNameRegistry creates an empty name registry
+func NewNameRegistry(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager) *NameRegistry {
+	return &NameRegistry{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		names:            make(map[string]*NameRegistration),
+		owners:           make(map[string]string),
+	}
+}
+
+// NormalizeName lowercases a name and checks it is 3 to 32 letters, digits
+// and inner hyphens
+func NormalizeName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < MinNameLength || len(name) > MaxNameLength {
+		return "", NewDAOError(ErrInvalidName, "name must be 3 to 32 characters", map[string]interface{}{
+			"name": name,
+		})
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '-' && i > 0 && i < len(name)-1:
+		default:
+			return "", NewDAOError(ErrInvalidName, "name may only hold letters, digits and inner hyphens", map[string]interface{}{
+				"name": name,
+			})
+		}
+	}
+	return name, nil
+}
+
+// ProcessNameRegisterTx registers a free name to the sender, who pays the
+// registration fee to the treasury and the transaction fee
+func (nr *NameRegistry) ProcessNameRegisterTx(tx *NameRegisterTx, owner crypto.PublicKey) error {
+	if err := nr.checkFunds(owner, uint64(tx.Fee)); err != nil {
+		return err
+	}
+	if _, err := nr.Register(tx.Name, owner, time.Now().Unix()); err != nil {
+		return err
+	}
+	nr.tokenState.Balances.Sub(owner.String(), uint64(tx.Fee))
+	return nil
+}
+
+// ProcessNameRenewTx extends the sender's name by a registration period
+func (nr *NameRegistry) ProcessNameRenewTx(tx *NameRenewTx, owner crypto.PublicKey) error {
+	if err := nr.checkFunds(owner, uint64(tx.Fee)); err != nil {
+		return err
+	}
+	if _, err := nr.Renew(tx.Name, owner, time.Now().Unix()); err != nil {
+		return err
+	}
+	nr.tokenState.Balances.Sub(owner.String(), uint64(tx.Fee))
+	return nil
+}
+
+// ProcessNameTransferTx hands the sender's name to the recipient
+func (nr *NameRegistry) ProcessNameTransferTx(tx *NameTransferTx, owner crypto.PublicKey) error {
+	if err := nr.Transfer(tx.Name, owner, tx.Recipient, time.Now().Unix()); err != nil {
+		return err
+	}
+	nr.tokenState.Balances.Sub(owner.String(), uint64(tx.Fee))
+	return nil
+}
+
+// Register registers a name to owner for a registration period. The name
+// must be free or expired and the owner may not hold another active name.
+func (nr *NameRegistry) Register(name string, owner crypto.PublicKey, now int64) (*NameRegistration, error) {
+	name, err := NormalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing, exists := nr.names[name]; exists && existing.Active(now) {
+		return nil, NewDAOError(ErrNameTaken, "name is already registered", map[string]interface{}{
+			"name":       name,
+			"expires_at": existing.ExpiresAt,
+		})
+	}
+	if held := nr.NameOf(owner); held != "" {
+		return nil, NewDAOError(ErrNameTaken, "address already holds a name", map[string]interface{}{
+			"name": held,
+		})
+	}
+	if err := nr.collectFee(owner, now); err != nil {
+		return nil, err
+	}
+
+	registration := &NameRegistration{
+		Name:         name,
+		Owner:        owner,
+		RegisteredAt: now,
+		ExpiresAt:    now + nr.parameterManager.GetParameterConfig().NameRegistrationPeriod,
+	}
+	if previous, exists := nr.names[name]; exists && nr.owners[previous.Owner.String()] == name {
+		delete(nr.owners, previous.Owner.String())
+	}
+	nr.names[name] = registration
+	nr.owners[owner.String()] = name
+	return registration, nil
+}
+
+// Renew extends an active name by a registration period from its expiry
+func (nr *NameRegistry) Renew(name string, owner crypto.PublicKey, now int64) (*NameRegistration, error) {
+	registration, err := nr.owned(name, owner, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := nr.collectFee(owner, now); err != nil {
+		return nil, err
+	}
+
+	registration.ExpiresAt += nr.parameterManager.GetParameterConfig().NameRegistrationPeriod
+	return registration, nil
+}
+
+// Transfer hands an active name to a recipient holding no name. The
+// expiry is unchanged.
+func (nr *NameRegistry) Transfer(name string, owner, recipient crypto.PublicKey, now int64) error {
+	registration, err := nr.owned(name, owner, now)
+	if err != nil {
+		return err
+	}
+	if len(recipient) == 0 || recipient.String() == owner.String() {
+		return NewDAOError(ErrInvalidName, "invalid name recipient", nil)
+	}
+	if held := nr.NameOf(recipient); held != "" {
+		return NewDAOError(ErrNameTaken, "recipient already holds a name", map[string]interface{}{
+			"name": held,
+		})
+	}
+
+	delete(nr.owners, owner.String())
+	registration.Owner = recipient
+	nr.owners[recipient.String()] = registration.Name
+	return nil
+}
+
+// owned returns the active registration of a name held by owner
+func (nr *NameRegistry) owned(name string, owner crypto.PublicKey, now int64) (*NameRegistration, error) {
+	name, err := NormalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	registration, exists := nr.names[name]
+	if !exists || !registration.Active(now) {
+		return nil, NewDAOError(ErrNameNotFound, "name is not registered", map[string]interface{}{
+			"name": name,
+		})
+	}
+	if registration.Owner.String() != owner.String() {
+		return nil, NewDAOError(ErrUnauthorized, "name is held by another address", nil)
+	}
+	return registration, nil
+}
+
+// checkFunds returns an error unless the payer holds the registration fee
+// on top of extra
+func (nr *NameRegistry) checkFunds(payer crypto.PublicKey, extra uint64) error {
+	fee := nr.parameterManager.GetParameterConfig().NameRegistrationFee
+	if nr.tokenState.Balances.Get(payer.String()) < fee+extra {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for the name registration fee", map[string]interface{}{
+			"fee": fee,
+		})
+	}
+	return nil
+}
+
+// collectFee moves the registration fee from the payer to the treasury
+func (nr *NameRegistry) collectFee(payer crypto.PublicKey, now int64) error {
+	if err := nr.checkFunds(payer, 0); err != nil {
+		return err
+	}
+	fee := nr.parameterManager.GetParameterConfig().NameRegistrationFee
+	nr.tokenState.Balances.Sub(payer.String(), fee)
+	nr.governanceState.Treasury.recordInflow(fee, InflowSourceNameFee, now)
+	return nil
+}
+
+// Resolve returns the active registration of a name
+func (nr *NameRegistry) Resolve(name string) (*NameRegistration, bool) {
+	name, err := NormalizeName(name)
+	if err != nil {
+		return nil, false
+	}
+	registration, exists := nr.names[name]
+	if !exists || !registration.Active(time.Now().Unix()) {
+		return nil, false
+	}
+	return registration, true
+}
+
+// NameOf returns the active name of an address, or "" when it has none. A
+// nil registry has no names.
+func (nr *NameRegistry) NameOf(address crypto.PublicKey) string {
+	if len(address) == 0 {
+		return ""
+	}
+	return nr.NameOfAddress(address.String())
+}
+
+// NameOfAddress is NameOf for a hex encoded address
+func (nr *NameRegistry) NameOfAddress(address string) string {
+	if nr == nil {
+		return ""
+	}
+	name, exists := nr.owners[address]
+	if !exists {
+		return ""
+	}
+	if registration := nr.names[name]; registration == nil || !registration.Active(time.Now().Unix()) {
+		return ""
+	}
+	return name
+}
+
+// List returns the active registrations ordered by name
+func (nr *NameRegistry) List() []*NameRegistration {
+	now := time.Now().Unix()
+	registrations := make([]*NameRegistration, 0, len(nr.names))
+	for _, registration := range nr.names {
+		if registration.Active(now) {
+			registrations = append(registrations, registration)
+		}
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Name < registrations[j].Name
+	})
+	return registrations
+}
@@ -0,0 +1,103 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	name, err := NormalizeName("  Alice-DAO ")
+	require.NoError(t, err)
+	assert.Equal(t, "alice-dao", name)
+
+	for _, invalid := range []string{"al", "-alice", "alice-", "alice_dao", "alice.eth", "ålice", "abcdefghijklmnopqrstuvwxyz0123456"} {
+		_, err := NormalizeName(invalid)
+		require.Error(t, err, invalid)
+		assert.Equal(t, ErrInvalidName, err.(*DAOError).Code, invalid)
+	}
+}
+
+func TestNameRegistry_Lifecycle(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   1000,
+	}))
+	config := dao.ParameterManager.GetParameterConfig()
+	treasury := dao.GetTreasuryBalance()
+
+	// Registering pays the fee to the treasury and maps the name both ways
+	require.NoError(t, dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "Alice"}, alice, types.Hash{0x01}))
+	assert.Equal(t, uint64(1000-10-100), dao.GetTokenBalance(alice))
+	assert.Equal(t, treasury+100, dao.GetTreasuryBalance())
+	registration, exists := dao.Names.Resolve("ALICE")
+	require.True(t, exists)
+	assert.Equal(t, alice.String(), registration.Owner.String())
+	assert.Equal(t, registration.RegisteredAt+config.NameRegistrationPeriod, registration.ExpiresAt)
+	assert.Equal(t, "alice", dao.Names.NameOf(alice))
+
+	// Names are unique and each address holds one
+	err := dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "alice"}, bob, types.Hash{0x02})
+	assert.Equal(t, ErrNameTaken, err.(*DAOError).Code)
+	err = dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "alice2"}, alice, types.Hash{0x03})
+	assert.Equal(t, ErrNameTaken, err.(*DAOError).Code)
+	assert.Error(t, dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "outsider"}, outsider, types.Hash{0x04}))
+
+	// Only the owner renews, extending the expiry by a period
+	expiresAt := registration.ExpiresAt
+	assert.Error(t, dao.ProcessDAOTransaction(&NameRenewTx{Fee: 10, Name: "alice"}, bob, types.Hash{0x05}))
+	require.NoError(t, dao.ProcessDAOTransaction(&NameRenewTx{Fee: 10, Name: "alice"}, alice, types.Hash{0x06}))
+	assert.Equal(t, expiresAt+config.NameRegistrationPeriod, registration.ExpiresAt)
+	assert.Equal(t, treasury+200, dao.GetTreasuryBalance())
+
+	// A transfer moves the name and frees the sender to register another
+	require.NoError(t, dao.ProcessDAOTransaction(&NameTransferTx{Fee: 10, Name: "alice", Recipient: bob}, alice, types.Hash{0x07}))
+	assert.Equal(t, "alice", dao.Names.NameOf(bob))
+	assert.Empty(t, dao.Names.NameOf(alice))
+	require.NoError(t, dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "carol"}, alice, types.Hash{0x08}))
+	err = dao.ProcessDAOTransaction(&NameTransferTx{Fee: 10, Name: "carol", Recipient: bob}, alice, types.Hash{0x09})
+	assert.Equal(t, ErrNameTaken, err.(*DAOError).Code)
+
+	// Expired names resolve to nobody and are free to register again
+	now := time.Now().Unix()
+	registration.ExpiresAt = now - 1
+	_, exists = dao.Names.Resolve("alice")
+	assert.False(t, exists)
+	assert.Empty(t, dao.Names.NameOf(bob))
+	_, err = dao.Names.Register("alice", alice, now)
+	assert.Equal(t, ErrNameTaken, err.(*DAOError).Code) // alice still holds carol
+	_, err = dao.Names.Register("alice", bob, now)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", dao.Names.NameOf(bob))
+
+	names := dao.Names.List()
+	require.Len(t, names, 2)
+	assert.Equal(t, "alice", names[0].Name)
+	assert.Equal(t, "carol", names[1].Name)
+}
+
+func TestNameRegistry_InsufficientFunds(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 105,
+	}))
+
+	err := dao.ProcessDAOTransaction(&NameRegisterTx{Fee: 10, Name: "alice"}, alice, types.Hash{0x01})
+	assert.Equal(t, ErrInsufficientTokens, err.(*DAOError).Code)
+	assert.Equal(t, uint64(105), dao.GetTokenBalance(alice))
+	assert.Empty(t, dao.Names.List())
+
+	var registry *NameRegistry
+	assert.Empty(t, registry.NameOf(alice))
+}
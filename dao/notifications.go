@@ -0,0 +1,490 @@
+package dao
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notification channels members choose from
+const (
+	NotificationChannelPush    = "push"
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+// Push providers a device token is registered with
+const (
+	PushProviderFCM  = "fcm"
+	PushProviderAPNs = "apns"
+)
+
+// DefaultNotificationQueueSize is how many notifications wait for delivery
+// before new ones are dropped
+const DefaultNotificationQueueSize = 256
+
+// maxPushTokens bounds the devices of a member
+const maxPushTokens = 10
+
+// Notification is a DAO event delivered to members
+type Notification struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp int64                  `json:"timestamp"`
+	// Recipients limits delivery to these members, every subscriber of the
+	// event is notified when empty
+	Recipients []string `json:"-"`
+}
+
+// NewNotification creates the notification of an event, titled after the
+// event type and described by the title of its subject when known
+func NewNotification(eventType string, data interface{}, timestamp int64) *Notification {
+	fields, ok := data.(map[string]interface{})
+	if !ok && data != nil {
+		fields = map[string]interface{}{"data": data}
+	}
+
+	title := strings.ReplaceAll(eventType, "_", " ")
+	if title != "" {
+		title = strings.ToUpper(title[:1]) + title[1:]
+	}
+
+	body, _ := fields["title"].(string)
+
+	return &Notification{
+		Type:      eventType,
+		Title:     title,
+		Body:      body,
+		Data:      fields,
+		Timestamp: timestamp,
+	}
+}
+
+// addressedTo reports whether a member is among the recipients
+func (n *Notification) addressedTo(member string) bool {
+	if len(n.Recipients) == 0 {
+		return true
+	}
+	for _, recipient := range n.Recipients {
+		if recipient == member {
+			return true
+		}
+	}
+	return false
+}
+
+// PushToken is a device registered for push notifications
+type PushToken struct {
+	Provider string `json:"provider"` // fcm or apns
+	Token    string `json:"token"`
+}
+
+// NotificationPreferences are the channels and events a member is notified
+// through
+type NotificationPreferences struct {
+	Member     string      `json:"member"`
+	Channels   []string    `json:"channels"`
+	Events     []string    `json:"events,omitempty"` // Every event when empty
+	PushTokens []PushToken `json:"push_tokens,omitempty"`
+	Email      string      `json:"email,omitempty"`
+	WebhookURL string      `json:"webhook_url,omitempty"`
+	UpdatedAt  int64       `json:"updated_at"`
+}
+
+// Validate checks that every chosen channel has somewhere to deliver to
+func (p *NotificationPreferences) Validate() error {
+	for _, channel := range p.Channels {
+		switch channel {
+		case NotificationChannelPush:
+			if len(p.PushTokens) == 0 {
+				return fmt.Errorf("push notifications require a push token")
+			}
+		case NotificationChannelEmail:
+			if p.Email == "" {
+				return fmt.Errorf("email notifications require an email address")
+			}
+		case NotificationChannelWebhook:
+			if p.WebhookURL == "" {
+				return fmt.Errorf("webhook notifications require a webhook URL")
+			}
+		default:
+			return fmt.Errorf("unknown notification channel %q", channel)
+		}
+	}
+
+	if len(p.PushTokens) > maxPushTokens {
+		return fmt.Errorf("at most %d push tokens are allowed", maxPushTokens)
+	}
+	for _, token := range p.PushTokens {
+		if token.Provider != PushProviderFCM && token.Provider != PushProviderAPNs {
+			return fmt.Errorf("unknown push provider %q", token.Provider)
+		}
+		if token.Token == "" {
+			return fmt.Errorf("push token must not be empty")
+		}
+	}
+
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return fmt.Errorf("invalid email address: %w", err)
+		}
+	}
+	if p.WebhookURL != "" {
+		parsed, err := url.Parse(p.WebhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("webhook URL must be an http or https URL")
+		}
+	}
+
+	return nil
+}
+
+// Wants reports whether the member is notified of an event type
+func (p *NotificationPreferences) Wants(eventType string) bool {
+	if len(p.Events) == 0 {
+		return true
+	}
+	for _, event := range p.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *NotificationPreferences) copy() *NotificationPreferences {
+	copied := *p
+	copied.Channels = append([]string(nil), p.Channels...)
+	copied.Events = append([]string(nil), p.Events...)
+	copied.PushTokens = append([]PushToken(nil), p.PushTokens...)
+	return &copied
+}
+
+// Notifier delivers notifications to a recipient, which is a device token,
+// email address or webhook URL depending on the notifier
+type Notifier interface {
+	Name() string
+	Send(recipient string, notification *Notification) error
+}
+
+// NotificationStats summarises notification delivery
+type NotificationStats struct {
+	Subscribers int               `json:"subscribers"`
+	Queued      int               `json:"queued"`
+	Dropped     uint64            `json:"dropped"`
+	Sent        map[string]uint64 `json:"sent"`   // By notifier
+	Failed      map[string]uint64 `json:"failed"` // By notifier
+}
+
+// NotificationService fans DAO events out to members through the notifiers
+// of their chosen channels
+type NotificationService struct {
+	preferences map[string]*NotificationPreferences
+	notifiers   map[string]Notifier
+	queue       chan *Notification
+	stop        chan struct{}
+	dropped     uint64
+	sent        map[string]uint64
+	failed      map[string]uint64
+	mu          sync.RWMutex
+}
+
+// NewNotificationService creates a notification service queueing up to
+// queueSize notifications
+func NewNotificationService(queueSize int) *NotificationService {
+	if queueSize <= 0 {
+		queueSize = DefaultNotificationQueueSize
+	}
+
+	return &NotificationService{
+		preferences: make(map[string]*NotificationPreferences),
+		notifiers:   make(map[string]Notifier),
+		queue:       make(chan *Notification, queueSize),
+		sent:        make(map[string]uint64),
+		failed:      make(map[string]uint64),
+	}
+}
+
+// RegisterNotifier delivers through notifier. Push notifiers are named
+// after their push provider, the others after their channel.
+func (ns *NotificationService) RegisterNotifier(notifier Notifier) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.notifiers[notifier.Name()] = notifier
+}
+
+// SetPreferences stores the preferences of a member
+func (ns *NotificationService) SetPreferences(preferences *NotificationPreferences) error {
+	if preferences.Member == "" {
+		return fmt.Errorf("preferences require a member")
+	}
+	if err := preferences.Validate(); err != nil {
+		return err
+	}
+
+	stored := preferences.copy()
+	stored.UpdatedAt = time.Now().Unix()
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.preferences[stored.Member] = stored
+	return nil
+}
+
+// GetPreferences returns the preferences of a member
+func (ns *NotificationService) GetPreferences(member string) (*NotificationPreferences, bool) {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	preferences, exists := ns.preferences[member]
+	if !exists {
+		return nil, false
+	}
+	return preferences.copy(), true
+}
+
+// RemovePreferences unsubscribes a member from every notification
+func (ns *NotificationService) RemovePreferences(member string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	delete(ns.preferences, member)
+}
+
+// Notify queues a notification for delivery, dropping it when the queue is
+// full so event producers never block
+func (ns *NotificationService) Notify(notification *Notification) bool {
+	select {
+	case ns.queue <- notification:
+		return true
+	default:
+		ns.mu.Lock()
+		ns.dropped++
+		ns.mu.Unlock()
+		return false
+	}
+}
+
+// Start delivers queued notifications in the background
+func (ns *NotificationService) Start() {
+	ns.mu.Lock()
+	if ns.stop != nil {
+		ns.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	ns.stop = stop
+	ns.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case notification := <-ns.queue:
+				ns.Deliver(notification)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends background delivery, queued notifications stay queued
+func (ns *NotificationService) Stop() {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if ns.stop != nil {
+		close(ns.stop)
+		ns.stop = nil
+	}
+}
+
+// Deliver sends a notification to every member subscribed to its event and
+// returns how many deliveries succeeded
+func (ns *NotificationService) Deliver(notification *Notification) int {
+	type delivery struct {
+		notifier  Notifier
+		recipient string
+	}
+
+	ns.mu.RLock()
+	var deliveries []delivery
+	for _, preferences := range ns.preferences {
+		if !preferences.Wants(notification.Type) || !notification.addressedTo(preferences.Member) {
+			continue
+		}
+		for _, channel := range preferences.Channels {
+			switch channel {
+			case NotificationChannelPush:
+				for _, token := range preferences.PushTokens {
+					if notifier, exists := ns.notifiers[token.Provider]; exists {
+						deliveries = append(deliveries, delivery{notifier, token.Token})
+					}
+				}
+			case NotificationChannelEmail:
+				if notifier, exists := ns.notifiers[NotificationChannelEmail]; exists {
+					deliveries = append(deliveries, delivery{notifier, preferences.Email})
+				}
+			case NotificationChannelWebhook:
+				if notifier, exists := ns.notifiers[NotificationChannelWebhook]; exists {
+					deliveries = append(deliveries, delivery{notifier, preferences.WebhookURL})
+				}
+			}
+		}
+	}
+	ns.mu.RUnlock()
+
+	// Deliveries run without the lock, notifiers make network calls
+	sent := 0
+	for _, d := range deliveries {
+		err := d.notifier.Send(d.recipient, notification)
+
+		ns.mu.Lock()
+		if err != nil {
+			ns.failed[d.notifier.Name()]++
+		} else {
+			ns.sent[d.notifier.Name()]++
+			sent++
+		}
+		ns.mu.Unlock()
+	}
+
+	return sent
+}
+
+// GetStats returns delivery counters
+func (ns *NotificationService) GetStats() NotificationStats {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	stats := NotificationStats{
+		Subscribers: len(ns.preferences),
+		Queued:      len(ns.queue),
+		Dropped:     ns.dropped,
+		Sent:        make(map[string]uint64, len(ns.sent)),
+		Failed:      make(map[string]uint64, len(ns.failed)),
+	}
+	for name, count := range ns.sent {
+		stats.Sent[name] = count
+	}
+	for name, count := range ns.failed {
+		stats.Failed[name] = count
+	}
+	return stats
+}
+
+// WebhookNotifier posts notifications as JSON to member webhooks
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WebhookNotifier{client: &http.Client{Timeout: timeout}}
+}
+
+// Name returns the webhook channel
+func (n *WebhookNotifier) Name() string {
+	return NotificationChannelWebhook
+}
+
+// Send posts the notification to the webhook URL
+func (n *WebhookNotifier) Send(recipient string, notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(recipient, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPConfig configures email notifications
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Authenticates with PLAIN auth when set
+	Password string
+	From     string
+}
+
+// EmailNotifier emails notifications through an SMTP server
+type EmailNotifier struct {
+	config SMTPConfig
+	send   func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates an email notifier
+func NewEmailNotifier(config SMTPConfig) *EmailNotifier {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+
+	return &EmailNotifier{config: config, send: smtp.SendMail}
+}
+
+// Name returns the email channel
+func (n *EmailNotifier) Name() string {
+	return NotificationChannelEmail
+}
+
+// Send emails the notification to the recipient address
+func (n *EmailNotifier) Send(recipient string, notification *Notification) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	// Header values come from members and proposals, line breaks would
+	// inject headers
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(notification.Title)
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&message, "To: %s\r\n", recipient)
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString(notification.Body)
+	if notification.Body != "" {
+		message.WriteString("\r\n")
+	}
+	keys := make([]string, 0, len(notification.Data))
+	for key := range notification.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&message, "%s: %v\r\n", key, notification.Data[key])
+	}
+
+	addr := fmt.Sprintf("%s:%d", n.config.Host, n.config.Port)
+	if err := n.send(addr, auth, n.config.From, []string{recipient}, message.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package dao
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FCMConfig configures push notifications through Firebase Cloud Messaging,
+// which also reaches iOS devices of the Flutter app through its APNs relay
+type FCMConfig struct {
+	ProjectID string
+	// Credentials is the JSON key of a service account allowed to send
+	// messages for the project
+	Credentials []byte
+	Endpoint    string // https://fcm.googleapis.com when empty
+	Timeout     time.Duration
+}
+
+// fcmServiceAccount is the part of a service account key FCM needs
+type fcmServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMNotifier sends push notifications with the FCM HTTP v1 API
+type FCMNotifier struct {
+	config  FCMConfig
+	account fcmServiceAccount
+	key     *rsa.PrivateKey
+	client  *http.Client
+
+	accessToken string
+	expiresAt   time.Time
+	mu          sync.Mutex
+}
+
+// NewFCMNotifier creates an FCM notifier from a service account key
+func NewFCMNotifier(config FCMConfig) (*FCMNotifier, error) {
+	if config.ProjectID == "" {
+		return nil, fmt.Errorf("FCM requires a project ID")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://fcm.googleapis.com"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	var account fcmServiceAccount
+	if err := json.Unmarshal(config.Credentials, &account); err != nil {
+		return nil, fmt.Errorf("invalid FCM service account key: %w", err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("FCM service account key lacks client_email or token_uri")
+	}
+
+	parsed, err := parsePKCS8PEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM service account key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("FCM service account key must be an RSA key")
+	}
+
+	return &FCMNotifier{
+		config:  config,
+		account: account,
+		key:     key,
+		client:  &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Name returns the FCM push provider
+func (n *FCMNotifier) Name() string {
+	return PushProviderFCM
+}
+
+// Send pushes the notification to a device registration token
+func (n *FCMNotifier) Send(recipient string, notification *Notification) error {
+	accessToken, err := n.token()
+	if err != nil {
+		return err
+	}
+
+	// FCM data payloads only carry strings
+	data := make(map[string]string, len(notification.Data)+1)
+	for key, value := range notification.Data {
+		data[key] = fmt.Sprint(value)
+	}
+	data["type"] = notification.Type
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": recipient,
+			"notification": map[string]string{
+				"title": notification.Title,
+				"body":  notification.Body,
+			},
+			"data": data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", strings.TrimRight(n.config.Endpoint, "/"), n.config.ProjectID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call FCM: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("FCM send failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// token returns an OAuth access token, exchanging a signed service account
+// assertion for a new one shortly before the current one expires
+func (n *FCMNotifier) token() (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	if n.accessToken != "" && now.Before(n.expiresAt.Add(-time.Minute)) {
+		return n.accessToken, nil
+	}
+
+	assertion, err := signJWT(map[string]interface{}{"alg": "RS256", "typ": "JWT"}, map[string]interface{}{
+		"iss":   n.account.ClientEmail,
+		"scope": "https://www.googleapis.com/auth/firebase.messaging",
+		"aud":   n.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}, func(digest []byte) ([]byte, error) {
+		return rsa.SignPKCS1v15(rand.Reader, n.key, crypto.SHA256, digest)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := n.client.PostForm(n.account.TokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get FCM access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("FCM access token request failed with status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&grant); err != nil || grant.AccessToken == "" {
+		return "", fmt.Errorf("FCM access token response has no token")
+	}
+
+	n.accessToken = grant.AccessToken
+	n.expiresAt = now.Add(time.Duration(grant.ExpiresIn) * time.Second)
+	return n.accessToken, nil
+}
+
+// APNsConfig configures push notifications sent directly to Apple devices
+type APNsConfig struct {
+	Endpoint string // https://api.push.apple.com when empty
+	Key      []byte // PEM encoded .p8 signing key
+	KeyID    string
+	TeamID   string
+	Topic    string // Bundle ID of the app
+	Timeout  time.Duration
+}
+
+// APNsNotifier sends push notifications with token based APNs
+// authentication
+type APNsNotifier struct {
+	config APNsConfig
+	key    *ecdsa.PrivateKey
+	client *http.Client
+
+	providerToken string
+	issuedAt      time.Time
+	mu            sync.Mutex
+}
+
+// NewAPNsNotifier creates an APNs notifier from a signing key
+func NewAPNsNotifier(config APNsConfig) (*APNsNotifier, error) {
+	if config.KeyID == "" || config.TeamID == "" || config.Topic == "" {
+		return nil, fmt.Errorf("APNs requires a key ID, team ID and topic")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://api.push.apple.com"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	parsed, err := parsePKCS8PEM(config.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs signing key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("APNs signing key must be an ECDSA key")
+	}
+
+	return &APNsNotifier{
+		config: config,
+		key:    key,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Name returns the APNs push provider
+func (n *APNsNotifier) Name() string {
+	return PushProviderAPNs
+}
+
+// Send pushes the notification to a device token
+func (n *APNsNotifier) Send(recipient string, notification *Notification) error {
+	providerToken, err := n.token()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": notification.Title,
+				"body":  notification.Body,
+			},
+		},
+		"type": notification.Type,
+		"data": notification.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimRight(n.config.Endpoint, "/") + "/3/device/" + url.PathEscape(recipient)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", n.config.Topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call APNs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("APNs send failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// token returns the provider token, which APNs accepts for up to an hour
+// and rejects when refreshed more often than every 20 minutes
+func (n *APNsNotifier) token() (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	if n.providerToken != "" && now.Sub(n.issuedAt) < 50*time.Minute {
+		return n.providerToken, nil
+	}
+
+	providerToken, err := signJWT(map[string]interface{}{"alg": "ES256", "kid": n.config.KeyID}, map[string]interface{}{
+		"iss": n.config.TeamID,
+		"iat": now.Unix(),
+	}, func(digest []byte) ([]byte, error) {
+		r, s, err := ecdsa.Sign(rand.Reader, n.key, digest)
+		if err != nil {
+			return nil, err
+		}
+		// JWS encodes ES256 signatures as fixed size r and s
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	n.providerToken = providerToken
+	n.issuedAt = now
+	return providerToken, nil
+}
+
+// signJWT encodes and signs a JSON web token, sign receives the SHA-256
+// digest of the signing input
+func signJWT(header, claims map[string]interface{}, sign func(digest []byte) ([]byte, error)) (string, error) {
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := sign(digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePKCS8PEM parses a PEM encoded PKCS #8 private key
+func parsePKCS8PEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key found")
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
@@ -0,0 +1,329 @@
+package dao
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNotifier records deliveries
+type testNotifier struct {
+	name string
+	fail bool
+
+	mu         sync.Mutex
+	recipients []string
+}
+
+func (n *testNotifier) Name() string {
+	return n.name
+}
+
+func (n *testNotifier) Send(recipient string, notification *Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.fail {
+		return fmt.Errorf("unavailable")
+	}
+	n.recipients = append(n.recipients, recipient)
+	return nil
+}
+
+func (n *testNotifier) sent() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]string(nil), n.recipients...)
+}
+
+func pkcs8PEM(t *testing.T, key interface{}) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestNotificationPreferences_Validate(t *testing.T) {
+	valid := &NotificationPreferences{
+		Channels:   []string{NotificationChannelPush, NotificationChannelEmail, NotificationChannelWebhook},
+		PushTokens: []PushToken{{Provider: PushProviderFCM, Token: "device"}},
+		Email:      "member@example.com",
+		WebhookURL: "https://hooks.example.com/dao",
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := map[string]*NotificationPreferences{
+		"unknown channel":  {Channels: []string{"sms"}},
+		"push no token":    {Channels: []string{NotificationChannelPush}},
+		"email no address": {Channels: []string{NotificationChannelEmail}},
+		"bad email":        {Channels: []string{NotificationChannelEmail}, Email: "not an email"},
+		"webhook scheme":   {Channels: []string{NotificationChannelWebhook}, WebhookURL: "ftp://example.com"},
+		"push provider":    {Channels: []string{NotificationChannelPush}, PushTokens: []PushToken{{Provider: "sms", Token: "x"}}},
+		"empty token":      {Channels: []string{NotificationChannelPush}, PushTokens: []PushToken{{Provider: PushProviderAPNs}}},
+	}
+	for name, preferences := range invalid {
+		assert.Error(t, preferences.Validate(), name)
+	}
+}
+
+func TestNewNotification(t *testing.T) {
+	notification := NewNotification("proposal_created", map[string]interface{}{"title": "Budget"}, 10)
+	assert.Equal(t, "Proposal created", notification.Title)
+	assert.Equal(t, "Budget", notification.Body)
+	assert.Equal(t, int64(10), notification.Timestamp)
+
+	notification = NewNotification("vote_cast", "raw", 10)
+	assert.Equal(t, "raw", notification.Data["data"])
+	assert.Empty(t, notification.Body)
+}
+
+func TestNotificationService_Deliver(t *testing.T) {
+	service := NewNotificationService(0)
+	fcm := &testNotifier{name: PushProviderFCM}
+	email := &testNotifier{name: NotificationChannelEmail}
+	webhook := &testNotifier{name: NotificationChannelWebhook, fail: true}
+	service.RegisterNotifier(fcm)
+	service.RegisterNotifier(email)
+	service.RegisterNotifier(webhook)
+
+	require.NoError(t, service.SetPreferences(&NotificationPreferences{
+		Member:     "alice",
+		Channels:   []string{NotificationChannelPush, NotificationChannelEmail},
+		PushTokens: []PushToken{{Provider: PushProviderFCM, Token: "phone"}, {Provider: PushProviderAPNs, Token: "tablet"}},
+		Email:      "alice@example.com",
+	}))
+	require.NoError(t, service.SetPreferences(&NotificationPreferences{
+		Member:     "bob",
+		Channels:   []string{NotificationChannelWebhook},
+		Events:     []string{"proposal_passed"},
+		WebhookURL: "https://bob.example.com/hook",
+	}))
+	assert.Error(t, service.SetPreferences(&NotificationPreferences{Channels: []string{}}))
+
+	// Bob only wants passed proposals, the APNs token has no notifier
+	assert.Equal(t, 2, service.Deliver(NewNotification("proposal_created", nil, 1)))
+	assert.Equal(t, []string{"phone"}, fcm.sent())
+	assert.Equal(t, []string{"alice@example.com"}, email.sent())
+
+	assert.Equal(t, 2, service.Deliver(NewNotification("proposal_passed", nil, 2)))
+	stats := service.GetStats()
+	assert.Equal(t, 2, stats.Subscribers)
+	assert.Equal(t, uint64(2), stats.Sent[PushProviderFCM])
+	assert.Equal(t, uint64(1), stats.Failed[NotificationChannelWebhook])
+
+	// Returned preferences are copies
+	preferences, exists := service.GetPreferences("alice")
+	require.True(t, exists)
+	preferences.Channels[0] = NotificationChannelWebhook
+	preferences, _ = service.GetPreferences("alice")
+	assert.Equal(t, NotificationChannelPush, preferences.Channels[0])
+
+	service.RemovePreferences("alice")
+	_, exists = service.GetPreferences("alice")
+	assert.False(t, exists)
+}
+
+func TestNotificationService_DeliverRecipients(t *testing.T) {
+	service := NewNotificationService(0)
+	email := &testNotifier{name: NotificationChannelEmail}
+	service.RegisterNotifier(email)
+
+	for _, member := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, service.SetPreferences(&NotificationPreferences{
+			Member:   member,
+			Channels: []string{NotificationChannelEmail},
+			Email:    member + "@example.com",
+		}))
+	}
+
+	notification := NewNotification("delegation_expired", nil, 1)
+	notification.Recipients = []string{"alice", "carol"}
+	assert.Equal(t, 2, service.Deliver(notification))
+	assert.ElementsMatch(t, []string{"alice@example.com", "carol@example.com"}, email.sent())
+}
+
+func TestNotificationService_Queue(t *testing.T) {
+	service := NewNotificationService(1)
+	email := &testNotifier{name: NotificationChannelEmail}
+	service.RegisterNotifier(email)
+	require.NoError(t, service.SetPreferences(&NotificationPreferences{
+		Member:   "alice",
+		Channels: []string{NotificationChannelEmail},
+		Email:    "alice@example.com",
+	}))
+
+	// Producers never block on a full queue
+	assert.True(t, service.Notify(NewNotification("vote_cast", nil, 1)))
+	assert.False(t, service.Notify(NewNotification("vote_cast", nil, 2)))
+	assert.Equal(t, uint64(1), service.GetStats().Dropped)
+
+	service.Start()
+	defer service.Stop()
+	assert.Eventually(t, func() bool { return len(email.sent()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(0)
+	require.NoError(t, notifier.Send(server.URL, NewNotification("vote_cast", map[string]interface{}{"voter": "alice"}, 5)))
+	assert.Equal(t, "vote_cast", received.Type)
+	assert.Equal(t, "alice", received.Data["voter"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, notifier.Send(failing.URL, NewNotification("vote_cast", nil, 5)))
+}
+
+func TestEmailNotifier_Send(t *testing.T) {
+	notifier := NewEmailNotifier(SMTPConfig{Host: "smtp.example.com", Username: "dao", Password: "secret", From: "dao@example.com"})
+
+	var addr string
+	var message []byte
+	notifier.send = func(a string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		addr = a
+		message = msg
+		assert.NotNil(t, auth)
+		assert.Equal(t, []string{"alice@example.com"}, to)
+		return nil
+	}
+
+	notification := NewNotification("proposal_created", map[string]interface{}{"title": "Budget"}, 1)
+	notification.Title = "Injected\r\nBcc: everyone@example.com"
+	require.NoError(t, notifier.Send("alice@example.com", notification))
+
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Contains(t, string(message), "Subject: Injected  Bcc: everyone@example.com\r\n")
+	assert.NotContains(t, string(message), "\r\nBcc:")
+	assert.Contains(t, string(message), "title: Budget\r\n")
+}
+
+func TestFCMNotifier_Send(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var tokenRequests int
+	var message map[string]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+			assert.Len(t, strings.Split(r.Form.Get("assertion"), "."), 3)
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "expires_in": 3600})
+		case "/v1/projects/bock-dao/messages:send":
+			assert.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+			json.NewDecoder(r.Body).Decode(&message)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"client_email": "dao@bock-dao.iam.gserviceaccount.com",
+		"private_key":  string(pkcs8PEM(t, key)),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	notifier, err := NewFCMNotifier(FCMConfig{ProjectID: "bock-dao", Credentials: credentials, Endpoint: server.URL})
+	require.NoError(t, err)
+
+	notification := NewNotification("proposal_created", map[string]interface{}{"title": "Budget", "height": 7}, 1)
+	require.NoError(t, notifier.Send("device", notification))
+	require.NoError(t, notifier.Send("device", notification))
+
+	// The access token is reused until it expires
+	assert.Equal(t, 1, tokenRequests)
+	assert.Equal(t, "device", message["message"]["token"])
+	assert.Equal(t, map[string]interface{}{"title": "Budget", "height": "7", "type": "proposal_created"}, message["message"]["data"])
+
+	_, err = NewFCMNotifier(FCMConfig{ProjectID: "bock-dao", Credentials: []byte(`{}`)})
+	assert.Error(t, err)
+}
+
+func TestAPNsNotifier_Send(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/3/device/device-token", r.URL.Path)
+		assert.Equal(t, "app.bock.dao", r.Header.Get("apns-topic"))
+
+		// The provider token is an ES256 JWT signed by the key
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), `"title":"Proposal created"`)
+	}))
+	defer server.Close()
+
+	notifier, err := NewAPNsNotifier(APNsConfig{
+		Endpoint: server.URL,
+		Key:      pkcs8PEM(t, key),
+		KeyID:    "KEY123",
+		TeamID:   "TEAM123",
+		Topic:    "app.bock.dao",
+	})
+	require.NoError(t, err)
+	require.NoError(t, notifier.Send("device-token", NewNotification("proposal_created", nil, 1)))
+
+	_, err = NewAPNsNotifier(APNsConfig{Key: pkcs8PEM(t, key), KeyID: "KEY123", TeamID: "TEAM123"})
+	assert.Error(t, err)
+}
+
+func TestDAO_NotificationPreferences(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	member := crypto.GeneratePrivateKey().PublicKey()
+	preferences := &NotificationPreferences{Channels: []string{NotificationChannelEmail}, Email: "member@example.com"}
+
+	err := dao.SetNotificationPreferences(member, preferences)
+	require.Error(t, err)
+	assert.Equal(t, ErrUnauthorized, err.(*DAOError).Code)
+
+	dao.GovernanceState.TokenHolders[member.String()] = &TokenHolder{Address: member, Balance: 100}
+	require.NoError(t, dao.SetNotificationPreferences(member, preferences))
+
+	stored, exists := dao.GetNotificationPreferences(member)
+	require.True(t, exists)
+	assert.Equal(t, member.String(), stored.Member)
+	assert.NotZero(t, stored.UpdatedAt)
+
+	dao.RemoveNotificationPreferences(member)
+	_, exists = dao.GetNotificationPreferences(member)
+	assert.False(t, exists)
+}
@@ -0,0 +1,254 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// OptimisticStatus is the stage of an optimistic proposal
+type OptimisticStatus string
+
+const (
+	OptimisticStatusPending    OptimisticStatus = "pending"    // In its challenge window
+	OptimisticStatusChallenged OptimisticStatus = "challenged" // Converted into a full vote
+	OptimisticStatusExecuted   OptimisticStatus = "executed"
+	OptimisticStatusRejected   OptimisticStatus = "rejected" // The challenge vote failed it
+)
+
+// OptimisticProposal is a proposal that passes by default. The proposer
+// posts a bond and the proposal executes once its challenge window ends,
+// unless a member matches the bond to challenge it, which puts it to a full
+// vote. The side that loses the vote forfeits its bond to the other. Its ID
+// is the hash of the proposing transaction and becomes the ID of the vote.
+type OptimisticProposal struct {
+	ID              types.Hash
+	Proposer        crypto.PublicKey
+	Title           string
+	Description     string
+	ProposalType    ProposalType
+	VotingType      VotingType
+	Threshold       uint64
+	MetadataHash    types.Hash
+	VotingPeriod    int64  // Length of the vote when challenged
+	Bond            uint64 // Posted by the proposer and matched by a challenger
+	Status          OptimisticStatus
+	CreatedAt       int64
+	ChallengeEnds   int64
+	Challenger      crypto.PublicKey
+	ChallengeReason string
+	ChallengedAt    int64
+	ResolvedAt      int64
+}
+
+// Proposal returns the proposal of an optimistic proposal voted from start
+func (tx *OptimisticProposalTx) Proposal(start int64) *ProposalTx {
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        tx.Title,
+		Description:  tx.Description,
+		ProposalType: tx.ProposalType,
+		VotingType:   tx.VotingType,
+		StartTime:    start,
+		EndTime:      start + tx.VotingPeriod,
+		Threshold:    tx.Threshold,
+		MetadataHash: tx.MetadataHash,
+	}
+}
+
+// OptimisticManager runs optimistic proposals from their challenge window to
+// execution or a challenge vote
+type OptimisticManager struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	proposals        map[types.Hash]*OptimisticProposal
+}
+
+// NewOptimisticManager creates a new optimistic proposal manager
+func NewOptimisticManager(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager) *OptimisticManager {
+	return &OptimisticManager{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		proposals:        make(map[types.Hash]*OptimisticProposal),
+	}
+}
+
+// ProcessOptimisticProposalTx escrows the proposer's bond and opens the
+// challenge window of an optimistic proposal
+func (om *OptimisticManager) ProcessOptimisticProposalTx(tx *OptimisticProposalTx, proposer crypto.PublicKey, txHash types.Hash) error {
+	if _, exists := om.proposals[txHash]; exists {
+		return NewDAOError(ErrInvalidProposal, "optimistic proposal already exists", nil)
+	}
+	if _, exists := om.governanceState.Proposal(txHash); exists {
+		return NewDAOError(ErrInvalidProposal, "proposal already exists", nil)
+	}
+
+	config := om.parameterManager.GetParameterConfig()
+	proposerStr := proposer.String()
+	if om.tokenState.Balances.Get(proposerStr) < uint64(tx.Fee)+config.OptimisticBond {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for proposal fee and bond", map[string]interface{}{
+			"bond": config.OptimisticBond,
+		})
+	}
+
+	now := time.Now().Unix()
+	om.tokenState.Balances.Sub(proposerStr, uint64(tx.Fee)+config.OptimisticBond)
+	om.proposals[txHash] = &OptimisticProposal{
+		ID:            txHash,
+		Proposer:      proposer,
+		Title:         tx.Title,
+		Description:   tx.Description,
+		ProposalType:  tx.ProposalType,
+		VotingType:    tx.VotingType,
+		Threshold:     tx.Threshold,
+		MetadataHash:  tx.MetadataHash,
+		VotingPeriod:  tx.VotingPeriod,
+		Bond:          config.OptimisticBond,
+		Status:        OptimisticStatusPending,
+		CreatedAt:     now,
+		ChallengeEnds: now + config.OptimisticChallengePeriod,
+	}
+
+	return nil
+}
+
+// ProcessOptimisticChallengeTx matches the bond of a proposal in its
+// challenge window and puts it to a full vote starting now
+func (om *OptimisticManager) ProcessOptimisticChallengeTx(tx *OptimisticChallengeTx, challenger crypto.PublicKey) error {
+	optimistic, exists := om.proposals[tx.ProposalID]
+	if !exists {
+		return ErrOptimisticNotFoundError
+	}
+	if optimistic.Status != OptimisticStatusPending {
+		return NewDAOError(ErrInvalidProposal, "proposal is no longer challengeable", nil)
+	}
+
+	now := time.Now().Unix()
+	if now >= optimistic.ChallengeEnds {
+		return NewDAOError(ErrVotingClosed, "challenge window has closed", nil)
+	}
+
+	challengerStr := challenger.String()
+	if optimistic.Proposer.String() == challengerStr {
+		return NewDAOError(ErrUnauthorized, "proposers cannot challenge their own proposal", nil)
+	}
+	if om.tokenState.Balances.Get(challengerStr) < uint64(tx.Fee)+optimistic.Bond {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for challenge fee and bond", map[string]interface{}{
+			"bond": optimistic.Bond,
+		})
+	}
+
+	om.tokenState.Balances.Sub(challengerStr, uint64(tx.Fee)+optimistic.Bond)
+	optimistic.Status = OptimisticStatusChallenged
+	optimistic.Challenger = challenger
+	optimistic.ChallengeReason = tx.Reason
+	optimistic.ChallengedAt = now
+
+	om.record(optimistic, now, now+optimistic.VotingPeriod, ProposalStatusActive)
+
+	return nil
+}
+
+// ResolveDue executes the unchallenged proposals whose challenge window
+// ended before now and settles the challenged ones whose vote has decided.
+// The vote statuses must be up to date.
+func (om *OptimisticManager) ResolveDue(now int64) []*OptimisticProposal {
+	var resolved []*OptimisticProposal
+	for _, optimistic := range om.ListProposals("") {
+		switch optimistic.Status {
+		case OptimisticStatusPending:
+			if now < optimistic.ChallengeEnds {
+				continue
+			}
+			om.tokenState.Balances.Add(optimistic.Proposer.String(), optimistic.Bond)
+			om.record(optimistic, optimistic.CreatedAt, optimistic.ChallengeEnds, ProposalStatusExecuted)
+			optimistic.Status = OptimisticStatusExecuted
+
+		case OptimisticStatusChallenged:
+			proposal, exists := om.governanceState.Proposal(optimistic.ID)
+			if !exists {
+				continue
+			}
+
+			// The loser's bond goes to the winner
+			switch proposal.Status {
+			case ProposalStatusPassed, ProposalStatusExecuted:
+				om.tokenState.Balances.Add(optimistic.Proposer.String(), 2*optimistic.Bond)
+				proposal.Status = ProposalStatusExecuted
+				optimistic.Status = OptimisticStatusExecuted
+			case ProposalStatusRejected, ProposalStatusCancelled:
+				om.tokenState.Balances.Add(optimistic.Challenger.String(), 2*optimistic.Bond)
+				optimistic.Status = OptimisticStatusRejected
+			default:
+				continue
+			}
+
+		default:
+			continue
+		}
+
+		optimistic.ResolvedAt = now
+		resolved = append(resolved, optimistic)
+	}
+	return resolved
+}
+
+// record adds the governance proposal of an optimistic proposal, which is
+// voted on when challenged and kept as executed otherwise
+func (om *OptimisticManager) record(optimistic *OptimisticProposal, start, end int64, status ProposalStatus) {
+	om.governanceState.Proposals[optimistic.ID] = &Proposal{
+		ID:           optimistic.ID,
+		Creator:      optimistic.Proposer,
+		Title:        optimistic.Title,
+		Description:  optimistic.Description,
+		ProposalType: optimistic.ProposalType,
+		VotingType:   optimistic.VotingType,
+		StartTime:    start,
+		EndTime:      end,
+		Status:       status,
+		Threshold:    optimistic.Threshold,
+		Results:      &VoteResults{Passed: status == ProposalStatusExecuted},
+		MetadataHash: optimistic.MetadataHash,
+	}
+	om.governanceState.ResetVotes(optimistic.ID)
+}
+
+// Challenged returns the IDs of the proposals whose challenge vote has not
+// been settled
+func (om *OptimisticManager) Challenged() []types.Hash {
+	var ids []types.Hash
+	for _, optimistic := range om.ListProposals(OptimisticStatusChallenged) {
+		ids = append(ids, optimistic.ID)
+	}
+	return ids
+}
+
+// GetProposal returns an optimistic proposal
+func (om *OptimisticManager) GetProposal(id types.Hash) (*OptimisticProposal, bool) {
+	optimistic, exists := om.proposals[id]
+	return optimistic, exists
+}
+
+// ListProposals returns the optimistic proposals, optionally only those of a
+// stage, oldest first
+func (om *OptimisticManager) ListProposals(status OptimisticStatus) []*OptimisticProposal {
+	proposals := make([]*OptimisticProposal, 0, len(om.proposals))
+	for _, optimistic := range om.proposals {
+		if status != "" && optimistic.Status != status {
+			continue
+		}
+		proposals = append(proposals, optimistic)
+	}
+
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].CreatedAt != proposals[j].CreatedAt {
+			return proposals[i].CreatedAt < proposals[j].CreatedAt
+		}
+		return proposals[i].ID.String() < proposals[j].ID.String()
+	})
+	return proposals
+}
@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimisticProposal_Lifecycle(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	proposer := crypto.GeneratePrivateKey().PublicKey()
+	challenger := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		proposer.String():   10000,
+		challenger.String(): 5000,
+	}))
+
+	propose := func(title string, txHash types.Hash) *OptimisticProposal {
+		tx := &OptimisticProposalTx{
+			Fee:          100,
+			Title:        title,
+			Description:  "Routine change",
+			ProposalType: ProposalTypeGeneral,
+			VotingType:   VotingTypeSimple,
+			Threshold:    5100,
+			VotingPeriod: 86400,
+		}
+		require.NoError(t, dao.ProcessDAOTransaction(tx, proposer, txHash))
+		optimistic, exists := dao.GetOptimisticProposal(txHash)
+		require.True(t, exists)
+		return optimistic
+	}
+
+	// The vote a challenge opens must follow the usual proposal rules
+	short := &OptimisticProposalTx{Fee: 100, Title: "Short", Description: "Too short", ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple, Threshold: 5100, VotingPeriod: 60}
+	assert.Error(t, dao.ProcessDAOTransaction(short, proposer, types.Hash{0x60}))
+
+	// The proposer's bond is escrowed and no vote is opened
+	unchallenged := propose("Rename the forum", types.Hash{0x61})
+	assert.Equal(t, OptimisticStatusPending, unchallenged.Status)
+	assert.Equal(t, uint64(1000), unchallenged.Bond)
+	assert.Equal(t, uint64(10000-1100), dao.GetTokenBalance(proposer))
+	_, err := dao.GetProposal(unchallenged.ID)
+	assert.Error(t, err)
+	assert.Error(t, dao.ProcessDAOTransaction(&OptimisticChallengeTx{Fee: 10, ProposalID: unchallenged.ID}, proposer, types.Hash{0x62}))
+
+	// Nothing executes before the challenge window ends
+	assert.Empty(t, dao.ResolveOptimisticProposals())
+	unchallenged.ChallengeEnds = time.Now().Unix() - 1
+	assert.Error(t, dao.ProcessDAOTransaction(&OptimisticChallengeTx{Fee: 10, ProposalID: unchallenged.ID}, challenger, types.Hash{0x63}))
+
+	resolved := dao.ResolveOptimisticProposals()
+	require.Len(t, resolved, 1)
+	assert.Equal(t, OptimisticStatusExecuted, unchallenged.Status)
+	assert.Equal(t, uint64(10000-100), dao.GetTokenBalance(proposer))
+	proposal, err := dao.GetProposal(unchallenged.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	// A challenge matches the bond and opens a full vote
+	rejected := propose("Raise the fees", types.Hash{0x64})
+	require.NoError(t, dao.ProcessDAOTransaction(&OptimisticChallengeTx{Fee: 10, ProposalID: rejected.ID, Reason: "Hurts small holders"}, challenger, types.Hash{0x65}))
+	assert.Equal(t, OptimisticStatusChallenged, rejected.Status)
+	assert.Equal(t, uint64(5000-1010), dao.GetTokenBalance(challenger))
+	assert.Error(t, dao.ProcessDAOTransaction(&OptimisticChallengeTx{Fee: 10, ProposalID: rejected.ID}, challenger, types.Hash{0x66}))
+
+	proposal, err = dao.GetProposal(rejected.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalStatusActive, proposal.Status)
+	assert.Empty(t, dao.ResolveOptimisticProposals())
+
+	// The side that loses the vote forfeits its bond to the other
+	proposal.Results.NoVotes = 3000
+	proposal.EndTime = time.Now().Unix() - 1
+	require.Len(t, dao.ResolveOptimisticProposals(), 1)
+	assert.Equal(t, OptimisticStatusRejected, rejected.Status)
+	assert.Equal(t, ProposalStatusRejected, proposal.Status)
+	assert.Equal(t, uint64(5000-10+1000), dao.GetTokenBalance(challenger))
+
+	passed := propose("Fund the meetup", types.Hash{0x67})
+	require.NoError(t, dao.ProcessDAOTransaction(&OptimisticChallengeTx{Fee: 10, ProposalID: passed.ID}, challenger, types.Hash{0x68}))
+	proposal, err = dao.GetProposal(passed.ID)
+	require.NoError(t, err)
+	proposal.Results.YesVotes = 3000
+	proposal.EndTime = time.Now().Unix() - 1
+	before := dao.GetTokenBalance(proposer)
+	require.Len(t, dao.ResolveOptimisticProposals(), 1)
+	assert.Equal(t, OptimisticStatusExecuted, passed.Status)
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+	assert.Equal(t, before+2000, dao.GetTokenBalance(proposer))
+
+	assert.Len(t, dao.ListOptimisticProposals(OptimisticStatusExecuted), 2)
+	assert.Len(t, dao.ListOptimisticProposals(""), 3)
+}
+
+func TestOptimisticProposal_ProposalStatusJob(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	proposer := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{proposer.String(): 10000}))
+
+	tx := &OptimisticProposalTx{Fee: 100, Title: "Adopt the logo", Description: "New logo", ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple, Threshold: 5100, VotingPeriod: 86400}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, proposer, types.Hash{0x70}))
+	optimistic, _ := dao.GetOptimisticProposal(types.Hash{0x70})
+	optimistic.ChallengeEnds = time.Now().Unix() - 1
+
+	var resolved []OptimisticProposal
+	dao.OnOptimisticResolution(func(optimistic OptimisticProposal) {
+		resolved = append(resolved, optimistic)
+	})
+
+	// Unchallenged proposals execute with the proposal status job
+	jobs := dao.MaintenanceJobs(nil)
+	require.NoError(t, jobs[JobProposalStatus](time.Now()))
+	require.Len(t, resolved, 1)
+	assert.Equal(t, OptimisticStatusExecuted, resolved[0].Status)
+	assert.Equal(t, types.Hash{0x70}, resolved[0].ID)
+
+	require.NoError(t, jobs[JobProposalStatus](time.Now()))
+	assert.Len(t, resolved, 1)
+}
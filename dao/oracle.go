@@ -0,0 +1,424 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Bounds of oracle feeds and the conditions proposals set on them
+const (
+	MaxFeedOracles      = 50
+	MaxOracleConditions = 10
+	MaxOracleClockSkew  = 60 // Seconds a report may be observed ahead of the node's clock
+)
+
+// OracleOperator compares a feed value with a condition's value
+type OracleOperator string
+
+const (
+	OracleOperatorGT  OracleOperator = "gt"
+	OracleOperatorGTE OracleOperator = "gte"
+	OracleOperatorLT  OracleOperator = "lt"
+	OracleOperatorLTE OracleOperator = "lte"
+)
+
+// Valid reports whether the operator is known
+func (op OracleOperator) Valid() bool {
+	switch op {
+	case OracleOperatorGT, OracleOperatorGTE, OracleOperatorLT, OracleOperatorLTE:
+		return true
+	}
+	return false
+}
+
+// OracleCondition makes the execution of a proposal wait for a feed, e.g.
+// "spend only if the token price is above X"
+type OracleCondition struct {
+	FeedID   string
+	Operator OracleOperator
+	Value    int64
+}
+
+// Holds reports whether a feed value satisfies the condition
+func (c OracleCondition) Holds(value int64) bool {
+	switch c.Operator {
+	case OracleOperatorGT:
+		return value > c.Value
+	case OracleOperatorGTE:
+		return value >= c.Value
+	case OracleOperatorLT:
+		return value < c.Value
+	case OracleOperatorLTE:
+		return value <= c.Value
+	}
+	return false
+}
+
+// validSlug reports whether an ID, such as a feed ID, is short and safe to
+// use in URLs
+func validSlug(id string) bool {
+	if len(id) == 0 || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// OracleDataPoint is the latest value an oracle reported for a feed
+type OracleDataPoint struct {
+	Oracle     crypto.PublicKey
+	Value      int64
+	ObservedAt int64 // When the oracle observed the value
+	ReportedAt int64 // When the report was applied
+}
+
+// OracleFeed is a stream of external data, such as a price, pushed by a
+// whitelist of oracle keys that governance approved. Its value is the median
+// of the oracles' latest reports younger than MaxAge, and it is stale while
+// fewer than MinReports of them are.
+type OracleFeed struct {
+	ID          string
+	Description string
+	Decimals    uint8 // Fixed point decimals of the values
+	Oracles     []crypto.PublicKey
+	MinReports  uint16
+	MaxAge      int64
+	ProposalID  types.Hash // Proposal that approved the current oracles
+	ActivatedAt int64
+	Reports     map[string]*OracleDataPoint // oracle -> latest report
+}
+
+// IsOracle reports whether a key may report for the feed
+func (f *OracleFeed) IsOracle(key crypto.PublicKey) bool {
+	keyStr := key.String()
+	for _, oracle := range f.Oracles {
+		if oracle.String() == keyStr {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the feed value at time now and the number of fresh reports
+// it is the median of
+func (f *OracleFeed) Value(now int64) (int64, int, error) {
+	var values []int64
+	for _, report := range f.Reports {
+		if now-report.ObservedAt <= f.MaxAge {
+			values = append(values, report.Value)
+		}
+	}
+	if len(values) == 0 || len(values) < int(f.MinReports) {
+		return 0, len(values), NewDAOError(ErrOracleStale, "oracle feed has too few fresh reports", map[string]interface{}{
+			"feed":        f.ID,
+			"fresh":       len(values),
+			"min_reports": f.MinReports,
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid], len(values), nil
+	}
+	low, high := values[mid-1], values[mid]
+	return low + (high-low)/2, len(values), nil
+}
+
+// OracleConditionResult is a condition evaluated against its feed
+type OracleConditionResult struct {
+	Condition OracleCondition
+	Value     int64
+	Holds     bool
+	Err       error // Set when the feed is missing or stale
+}
+
+// Proposal returns the proposal of an oracle feed whitelist
+func (tx *OracleFeedProposalTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        "Oracle feed: " + tx.Title,
+		Description:  description,
+		ProposalType: ProposalTypeTechnical,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// Digest returns the hash an oracle signs to report a data point
+func (tx *OracleReportTx) Digest() types.Hash {
+	h := sha256.New()
+	h.Write([]byte("bockchain-oracle-report"))
+	binary.Write(h, binary.BigEndian, uint32(len(tx.FeedID)))
+	h.Write([]byte(tx.FeedID))
+	binary.Write(h, binary.BigEndian, tx.Value)
+	binary.Write(h, binary.BigEndian, tx.ObservedAt)
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// Sign signs the data point of the report with an oracle key
+func (tx *OracleReportTx) Sign(key crypto.PrivateKey) error {
+	digest := tx.Digest()
+	sig, err := key.Sign(digest.ToSlice())
+	if err != nil {
+		return err
+	}
+	tx.Oracle = key.PublicKey()
+	tx.Signature = *sig
+	return nil
+}
+
+// VerifySignature reports whether the report is signed by its oracle
+func (tx *OracleReportTx) VerifySignature() bool {
+	if len(tx.Oracle) == 0 || tx.Signature.R == nil || tx.Signature.S == nil {
+		return false
+	}
+	digest := tx.Digest()
+	return tx.Signature.Verify(tx.Oracle, digest.ToSlice())
+}
+
+// OracleManager keeps the oracle feeds and the oracle conditions of
+// proposals, which the execution engine checks before executing them
+type OracleManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	feeds           map[string]*OracleFeed
+	proposed        map[types.Hash]*OracleFeedProposalTx
+	conditions      map[types.Hash][]OracleCondition
+}
+
+// NewOracleManager creates a new oracle manager
+func NewOracleManager(governanceState *GovernanceState, tokenState *GovernanceToken) *OracleManager {
+	return &OracleManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		feeds:           make(map[string]*OracleFeed),
+		proposed:        make(map[types.Hash]*OracleFeedProposalTx),
+		conditions:      make(map[types.Hash][]OracleCondition),
+	}
+}
+
+// RecordProposal records the feed of a proposal whitelisting its oracles
+func (om *OracleManager) RecordProposal(proposalID types.Hash, tx *OracleFeedProposalTx) {
+	om.proposed[proposalID] = tx
+}
+
+// ProcessOracleFeedActivateTx activates the feed of a passed proposal. A
+// feed that already exists gets the new oracles and drops the reports of
+// the oracles no longer whitelisted.
+func (om *OracleManager) ProcessOracleFeedActivateTx(tx *OracleFeedActivateTx, activator crypto.PublicKey) error {
+	spec, exists := om.proposed[tx.ProposalID]
+	if !exists {
+		return ErrOracleFeedNotFoundError
+	}
+
+	proposal, exists := om.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	feed := &OracleFeed{
+		ID:          spec.FeedID,
+		Description: spec.Description,
+		Decimals:    spec.Decimals,
+		Oracles:     spec.Oracles,
+		MinReports:  spec.MinReports,
+		MaxAge:      spec.MaxAge,
+		ProposalID:  tx.ProposalID,
+		ActivatedAt: time.Now().Unix(),
+		Reports:     make(map[string]*OracleDataPoint),
+	}
+	if previous, exists := om.feeds[spec.FeedID]; exists {
+		for oracle, report := range previous.Reports {
+			if feed.IsOracle(report.Oracle) {
+				feed.Reports[oracle] = report
+			}
+		}
+	}
+
+	om.tokenState.Balances.Sub(activator.String(), uint64(tx.Fee))
+	om.feeds[spec.FeedID] = feed
+	delete(om.proposed, tx.ProposalID)
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// ProcessOracleReportTx applies a data point signed by one of a feed's
+// oracles. Anyone may submit it and pays the fee.
+func (om *OracleManager) ProcessOracleReportTx(tx *OracleReportTx, submitter crypto.PublicKey) error {
+	feed, exists := om.feeds[tx.FeedID]
+	if !exists {
+		return ErrOracleFeedNotFoundError
+	}
+	if !feed.IsOracle(tx.Oracle) {
+		return NewDAOError(ErrUnauthorized, "key is not an oracle of the feed", nil)
+	}
+	if !tx.VerifySignature() {
+		return NewDAOError(ErrInvalidSignature, "invalid oracle signature", nil)
+	}
+
+	now := time.Now().Unix()
+	if tx.ObservedAt > now+MaxOracleClockSkew {
+		return NewDAOError(ErrInvalidTimeframe, "report is observed in the future", nil)
+	}
+
+	oracleStr := tx.Oracle.String()
+	if previous, exists := feed.Reports[oracleStr]; exists && tx.ObservedAt <= previous.ObservedAt {
+		return NewDAOError(ErrInvalidTimeframe, "report is not newer than the oracle's last report", nil)
+	}
+
+	om.tokenState.Balances.Sub(submitter.String(), uint64(tx.Fee))
+	feed.Reports[oracleStr] = &OracleDataPoint{
+		Oracle:     tx.Oracle,
+		Value:      tx.Value,
+		ObservedAt: tx.ObservedAt,
+		ReportedAt: now,
+	}
+
+	return nil
+}
+
+// CheckConditionSpecs checks that conditions name existing feeds and known
+// operators, before a proposal with them is created
+func (om *OracleManager) CheckConditionSpecs(conditions []OracleCondition) error {
+	if len(conditions) > MaxOracleConditions {
+		return NewDAOError(ErrInvalidProposal, "proposal has too many oracle conditions", map[string]interface{}{
+			"max": MaxOracleConditions,
+		})
+	}
+	for _, condition := range conditions {
+		if _, exists := om.feeds[condition.FeedID]; !exists {
+			return NewDAOError(ErrOracleFeedNotFound, "oracle feed not found", map[string]interface{}{
+				"feed": condition.FeedID,
+			})
+		}
+		if !condition.Operator.Valid() {
+			return NewDAOError(ErrInvalidProposal, "unknown oracle condition operator", map[string]interface{}{
+				"operator": condition.Operator,
+			})
+		}
+	}
+	return nil
+}
+
+// Attach sets the oracle conditions of a proposal
+func (om *OracleManager) Attach(proposalID types.Hash, conditions []OracleCondition) {
+	if len(conditions) == 0 {
+		return
+	}
+	om.conditions[proposalID] = conditions
+}
+
+// Conditions returns the oracle conditions of a proposal
+func (om *OracleManager) Conditions(proposalID types.Hash) []OracleCondition {
+	return om.conditions[proposalID]
+}
+
+// Evaluate evaluates the oracle conditions of a proposal at time now
+func (om *OracleManager) Evaluate(proposalID types.Hash, now int64) []OracleConditionResult {
+	conditions := om.conditions[proposalID]
+	results := make([]OracleConditionResult, len(conditions))
+	for i, condition := range conditions {
+		results[i].Condition = condition
+
+		feed, exists := om.feeds[condition.FeedID]
+		if !exists {
+			results[i].Err = ErrOracleFeedNotFoundError
+			continue
+		}
+		value, _, err := feed.Value(now)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Value = value
+		results[i].Holds = condition.Holds(value)
+	}
+	return results
+}
+
+// CheckConditions returns an error unless every oracle condition of a
+// proposal holds on fresh data at time now
+func (om *OracleManager) CheckConditions(proposalID types.Hash, now int64) error {
+	for _, result := range om.Evaluate(proposalID, now) {
+		if result.Err != nil {
+			return result.Err
+		}
+		if !result.Holds {
+			return NewDAOError(ErrConditionUnmet, "oracle condition is not met", map[string]interface{}{
+				"feed":     result.Condition.FeedID,
+				"operator": result.Condition.Operator,
+				"value":    result.Condition.Value,
+				"current":  result.Value,
+			})
+		}
+	}
+	return nil
+}
+
+// Convert converts an amount in a feed's stable unit to native tokens at
+// the feed's value at time now. The feed prices one native token in the
+// stable unit with the feed's decimals.
+func (om *OracleManager) Convert(feedID string, stableAmount uint64, now int64) (uint64, error) {
+	feed, exists := om.feeds[feedID]
+	if !exists {
+		return 0, ErrOracleFeedNotFoundError
+	}
+	price, _, err := feed.Value(now)
+	if err != nil {
+		return 0, err
+	}
+	if price <= 0 {
+		return 0, NewDAOError(ErrOracleStale, "oracle feed has no positive price", map[string]interface{}{
+			"feed":  feedID,
+			"price": price,
+		})
+	}
+
+	native := new(big.Int).SetUint64(stableAmount)
+	native.Mul(native, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(feed.Decimals)), nil))
+	native.Quo(native, big.NewInt(price))
+	if !native.IsUint64() {
+		return 0, NewDAOError(ErrInvalidProposal, "converted amount overflows", nil)
+	}
+	return native.Uint64(), nil
+}
+
+// GetFeed returns an oracle feed
+func (om *OracleManager) GetFeed(id string) (*OracleFeed, bool) {
+	feed, exists := om.feeds[id]
+	return feed, exists
+}
+
+// ListFeeds returns the oracle feeds ordered by ID
+func (om *OracleManager) ListFeeds() []*OracleFeed {
+	feeds := make([]*OracleFeed, 0, len(om.feeds))
+	for _, feed := range om.feeds {
+		feeds = append(feeds, feed)
+	}
+	sort.Slice(feeds, func(i, j int) bool { return feeds[i].ID < feeds[j].ID })
+	return feeds
+}
@@ -0,0 +1,215 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOracleFeed_Value(t *testing.T) {
+	a := crypto.GeneratePrivateKey().PublicKey()
+	b := crypto.GeneratePrivateKey().PublicKey()
+	c := crypto.GeneratePrivateKey().PublicKey()
+	feed := &OracleFeed{
+		ID:         "GOV-USD",
+		MinReports: 2,
+		MaxAge:     100,
+		Reports: map[string]*OracleDataPoint{
+			a.String(): {Oracle: a, Value: 100, ObservedAt: 1000},
+			b.String(): {Oracle: b, Value: 300, ObservedAt: 1000},
+			c.String(): {Oracle: c, Value: 110, ObservedAt: 950},
+		},
+	}
+
+	// The median resists a single outlying oracle
+	value, fresh, err := feed.Value(1040)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fresh)
+	assert.Equal(t, int64(110), value)
+
+	// Stale reports drop out, an even count takes the mean of the middle two
+	value, fresh, err = feed.Value(1090)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fresh)
+	assert.Equal(t, int64(200), value)
+
+	// Too few fresh reports leave the feed without a value
+	_, _, err = feed.Value(1200)
+	assert.Equal(t, ErrOracleStale, err.(*DAOError).Code)
+}
+
+func TestOracle_ConditionalExecution(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey()
+	submitter := crypto.GeneratePrivateKey().PublicKey()
+	oracleA := crypto.GeneratePrivateKey()
+	oracleB := crypto.GeneratePrivateKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		founder.PublicKey().String(): 10000,
+		submitter.String():           100,
+	}))
+
+	now := time.Now().Unix()
+	propose := &OracleFeedProposalTx{
+		Fee:        100,
+		Title:      "GOV price",
+		FeedID:     "GOV-USD",
+		Decimals:   2,
+		Oracles:    []crypto.PublicKey{oracleA.PublicKey(), oracleB.PublicKey()},
+		MinReports: 3,
+		MaxAge:     600,
+		VotingType: VotingTypeSimple,
+		StartTime:  now,
+		EndTime:    now + 86400,
+		Threshold:  5100,
+	}
+	assert.Error(t, dao.ProcessDAOTransaction(propose, founder.PublicKey(), types.Hash{0x80}))
+	propose.MinReports = 1
+
+	feedProposal := types.Hash{0x81}
+	require.NoError(t, dao.ProcessDAOTransaction(propose, founder.PublicKey(), feedProposal))
+
+	// Conditions must name an active feed
+	spend := &ProposalTx{
+		Fee:          100,
+		Title:        "Buy back",
+		Description:  "Spend only above one dollar",
+		ProposalType: ProposalTypeGeneral,
+		VotingType:   VotingTypeSimple,
+		StartTime:    now,
+		EndTime:      now + 86400,
+		Threshold:    5100,
+		Conditions:   []OracleCondition{{FeedID: "GOV-USD", Operator: OracleOperatorGT, Value: 100}},
+	}
+	assert.Error(t, dao.ProcessDAOTransaction(spend, founder.PublicKey(), types.Hash{0x82}))
+
+	// The feed activates once its proposal passes
+	activate := &OracleFeedActivateTx{Fee: 10, ProposalID: feedProposal}
+	assert.Error(t, dao.ProcessDAOTransaction(activate, founder.PublicKey(), types.Hash{0x83}))
+	proposal, err := dao.GetProposal(feedProposal)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(activate, founder.PublicKey(), types.Hash{0x84}))
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	spendID := types.Hash{0x85}
+	require.NoError(t, dao.ProcessDAOTransaction(spend, founder.PublicKey(), spendID))
+	assert.Len(t, dao.OracleManager.Conditions(spendID), 1)
+	spendProposal, err := dao.GetProposal(spendID)
+	require.NoError(t, err)
+	spendProposal.Status = ProposalStatusPassed
+
+	// Without data the proposal cannot execute
+	err = dao.ProposalManager.ExecuteProposal(spendID, founder.PublicKey())
+	assert.Equal(t, ErrOracleStale, err.(*DAOError).Code)
+
+	// Only whitelisted oracles report, with a valid signature and fresh data
+	report := func(key crypto.PrivateKey, value, observedAt int64) *OracleReportTx {
+		tx := &OracleReportTx{Fee: 1, FeedID: "GOV-USD", Value: value, ObservedAt: observedAt}
+		require.NoError(t, tx.Sign(key))
+		return tx
+	}
+	assert.Error(t, dao.ProcessDAOTransaction(report(crypto.GeneratePrivateKey(), 150, now), submitter, types.Hash{0x86}))
+	forged := report(oracleA, 150, now)
+	forged.Value = 50
+	assert.Error(t, dao.ProcessDAOTransaction(forged, submitter, types.Hash{0x87}))
+	assert.Error(t, dao.ProcessDAOTransaction(report(oracleA, 150, now+3600), submitter, types.Hash{0x88}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(report(oracleA, 90, now), submitter, types.Hash{0x89}))
+	assert.Equal(t, uint64(99), dao.GetTokenBalance(submitter))
+	assert.Error(t, dao.ProcessDAOTransaction(report(oracleA, 95, now), submitter, types.Hash{0x8A}))
+
+	// An unmet condition holds the proposal back
+	err = dao.ProposalManager.ExecuteProposal(spendID, founder.PublicKey())
+	assert.Equal(t, ErrConditionUnmet, err.(*DAOError).Code)
+	results := dao.EvaluateProposalConditions(spendID)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(90), results[0].Value)
+	assert.False(t, results[0].Holds)
+
+	require.NoError(t, dao.ProcessDAOTransaction(report(oracleA, 120, now+1), submitter, types.Hash{0x8B}))
+	require.NoError(t, dao.ProcessDAOTransaction(report(oracleB, 130, now), submitter, types.Hash{0x8C}))
+	require.NoError(t, dao.ProposalManager.ExecuteProposal(spendID, founder.PublicKey()))
+	assert.Equal(t, ProposalStatusExecuted, spendProposal.Status)
+
+	feed, exists := dao.GetOracleFeed("GOV-USD")
+	require.True(t, exists)
+	assert.Len(t, feed.Reports, 2)
+	assert.Len(t, dao.ListOracleFeeds(), 1)
+}
+
+func TestOracle_StableTreasuryConversion(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	signer1 := crypto.GeneratePrivateKey()
+	signer2 := crypto.GeneratePrivateKey()
+	require.NoError(t, dao.InitializeTreasury([]crypto.PublicKey{signer1.PublicKey(), signer2.PublicKey()}, 2))
+	dao.AddTreasuryFunds(10000)
+
+	oracle := crypto.GeneratePrivateKey().PublicKey()
+	now := time.Now().Unix()
+	price := func(value, observedAt int64) {
+		dao.OracleManager.feeds["GOV-USD"] = &OracleFeed{
+			ID:         "GOV-USD",
+			Decimals:   2,
+			Oracles:    []crypto.PublicKey{oracle},
+			MinReports: 1,
+			MaxAge:     600,
+			Reports: map[string]*OracleDataPoint{
+				oracle.String(): {Oracle: oracle, Value: value, ObservedAt: observedAt},
+			},
+		}
+	}
+	price(200, now) // 2.00 per token
+
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	tx := &TreasuryTx{
+		Fee:          100,
+		Recipient:    recipient,
+		Amount:       1000, // 1000 stable units
+		Denomination: "USD-GOV",
+		Purpose:      "Audit paid in dollars",
+		RequiredSigs: 2,
+	}
+	assert.Error(t, dao.CreateTreasuryTransaction(tx, types.Hash{0x90}))
+
+	// The amount is quoted in native tokens when created
+	tx.Denomination = "GOV-USD"
+	txHash := types.Hash{0x91}
+	require.NoError(t, dao.CreateTreasuryTransaction(tx, txHash))
+	pending, exists := dao.GetTreasuryTransaction(txHash)
+	require.True(t, exists)
+	assert.Equal(t, uint64(500), pending.QuotedAmount)
+	assert.Equal(t, uint64(500), pending.Amount)
+	assert.Equal(t, uint64(1000), pending.StableAmount)
+
+	// A price move beyond the slippage bound re-queues instead of executing
+	require.NoError(t, dao.SignTreasuryTransaction(txHash, signer1))
+	price(250, now)
+	require.NoError(t, dao.SignTreasuryTransaction(txHash, signer2))
+	assert.False(t, pending.Executed)
+	assert.Equal(t, 1, pending.Requeues)
+	assert.Equal(t, "conversion_slippage", pending.RequeueReason)
+
+	// So does a stale feed
+	price(202, now-3600)
+	err := dao.ExecuteTreasuryTransaction(txHash)
+	assert.Equal(t, ErrOracleStale, err.(*DAOError).Code)
+	assert.Equal(t, 2, pending.Requeues)
+	assert.Equal(t, "oracle_data_stale", pending.RequeueReason)
+	assert.Equal(t, uint64(10000), dao.GetTreasuryBalance())
+
+	// Within the bound a retry pays the amount converted at execution
+	price(202, now)
+	assert.Equal(t, 1, dao.RetryTreasuryConversions())
+	assert.True(t, pending.Executed)
+	assert.Equal(t, uint64(495), pending.Amount)
+	assert.Equal(t, uint64(495), dao.GetTokenBalance(recipient))
+	assert.Equal(t, uint64(9505), dao.GetTreasuryBalance())
+	assert.Equal(t, 0, dao.RetryTreasuryConversions())
+}
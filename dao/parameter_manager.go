@@ -15,6 +15,12 @@ type ParameterManager struct {
 	tokenState       *GovernanceToken
 	parameterConfig  *ParameterConfig
 	parameterHistory map[string][]*ParameterChange
+	tracks           map[string]*ProposalTrack
+	proposedTracks   map[types.Hash]*TrackProposalTx
+	trackQueues      map[string][]types.Hash             // Track -> proposals waiting for an active slot
+	scheduledChanges map[types.Hash]*ParameterScheduleTx // Schedule proposals awaiting their vote
+	rollouts         []*ParameterRollout
+	height           uint32 // Latest block height seen
 }
 
 // ParameterConfig defines configurable DAO parameters
@@ -55,6 +61,82 @@ type ParameterConfig struct {
 	EmergencyPauseEnabled bool  `json:"emergency_pause_enabled"`
 	MultiSigRequired      bool  `json:"multi_sig_required"`
 	AuditLogRetention     int64 `json:"audit_log_retention"`
+
+	// Position parameters
+	VestingPositionsTransferable bool `json:"vesting_positions_transferable"`
+	StakePositionsTransferable   bool `json:"stake_positions_transferable"`
+
+	// Treasury yield parameters
+	TreasuryYieldShare uint64 `json:"treasury_yield_share"` // Basis points of treasury inflows paid to stakers
+	TreasuryYieldEpoch int64  `json:"treasury_yield_epoch"` // Epoch length in seconds
+
+	// Dispute parameters
+	DisputeJurorCount    uint64 `json:"dispute_juror_count"`     // Jurors drawn per dispute
+	DisputeMinJurorStake uint64 `json:"dispute_min_juror_stake"` // Minimum stake to be eligible as juror
+	DisputePhasePeriod   int64  `json:"dispute_phase_period"`    // Length of each evidence, commit and reveal phase
+	DisputeBond          uint64 `json:"dispute_bond"`            // Bond posted by the claimant
+
+	// State pruning parameters
+	VoteRetentionPeriod int64 `json:"vote_retention_period"` // Seconds finalized vote maps are kept after voting ends, 0 keeps them forever
+
+	// Validator commission parameters
+	ValidatorMinCommission       uint64 `json:"validator_min_commission"`        // Lowest commission in basis points
+	ValidatorMaxCommission       uint64 `json:"validator_max_commission"`        // Highest commission in basis points
+	ValidatorCommissionMaxChange uint64 `json:"validator_commission_max_change"` // Largest change per update in basis points
+	ValidatorCommissionCooldown  int64  `json:"validator_commission_cooldown"`   // Seconds between commission changes
+
+	// Sponsored voting parameters
+	VoteSponsorshipMaxBudget uint64 `json:"vote_sponsorship_max_budget"` // Largest treasury budget a proposal may reserve for voting fees
+
+	// Membership parameters
+	MembershipMinTokens  uint64 `json:"membership_min_tokens"`  // Applicants holding this many tokens join without approvals, 0 disables
+	MembershipApprovals  uint64 `json:"membership_approvals"`   // Member approvals to admit, suspend or reinstate a member
+	MembershipRequireKYC bool   `json:"membership_require_kyc"` // Applications must link an identity attestation
+
+	// Optimistic governance parameters
+	OptimisticBond            uint64 `json:"optimistic_bond"`             // Bond posted by the proposer and matched by a challenger
+	OptimisticChallengePeriod int64  `json:"optimistic_challenge_period"` // Seconds an optimistic proposal can be challenged
+
+	// Stable denominated treasury parameters
+	TreasuryConversionSlippage uint64 `json:"treasury_conversion_slippage"` // Largest drift in basis points between the quoted and executed native amount
+
+	// Constitution parameters
+	ConstitutionAmendmentThreshold uint64 `json:"constitution_amendment_threshold"` // Lowest threshold in basis points an amendment proposal may set
+
+	// Emergency spend parameters
+	EmergencyVotingPeriod int64  `json:"emergency_voting_period"` // Seconds an emergency spend is voted on
+	EmergencyQuorum       uint64 `json:"emergency_quorum"`        // Participation an emergency spend needs
+	EmergencyThreshold    uint64 `json:"emergency_threshold"`     // Basis points of yes votes an emergency spend needs
+
+	// Participation reward parameters
+	ParticipationRewardBudget uint64 `json:"participation_reward_budget"` // Treasury funds paid as participation rewards per epoch, 0 disables
+	ParticipationRewardCap    uint64 `json:"participation_reward_cap"`    // Most a voter earns in an epoch
+	ParticipationRewardEpoch  int64  `json:"participation_reward_epoch"`  // Epoch length in seconds
+
+	// Adaptive quorum parameters
+	AdaptiveQuorumWindow uint64 `json:"adaptive_quorum_window"` // Recent finalized proposals averaged for the quorum, 0 disables
+	AdaptiveQuorumMin    uint64 `json:"adaptive_quorum_min"`    // Lowest quorum the turnout average may set
+	AdaptiveQuorumMax    uint64 `json:"adaptive_quorum_max"`    // Highest quorum the turnout average may set
+
+	// Endorsement parameters
+	EndorsementThreshold  uint64 `json:"endorsement_threshold"`   // Voting power that must endorse a proposal before its vote opens, 0 disables
+	EndorsementPeriod     int64  `json:"endorsement_period"`      // Seconds a proposal has to gather its endorsements
+	EndorsementDeposit    uint64 `json:"endorsement_deposit"`     // Held from the proposer until the proposal is endorsed or expires
+	EndorsementExpiryBurn uint64 `json:"endorsement_expiry_burn"` // Basis points of the deposit burned when a proposal expires unendorsed
+
+	// Privacy parameters, visibilities for members who keep the default
+	PrivacyDefaultBalance    uint64 `json:"privacy_default_balance"`    // 1 public, 2 members, 3 private
+	PrivacyDefaultReputation uint64 `json:"privacy_default_reputation"` // 1 public, 2 members, 3 private
+	PrivacyDefaultVotes      uint64 `json:"privacy_default_votes"`      // 1 public, 2 members, 3 private
+
+	// Name registry parameters
+	NameRegistrationPeriod int64  `json:"name_registration_period"` // Seconds a registration or renewal lasts
+	NameRegistrationFee    uint64 `json:"name_registration_fee"`    // Paid to the treasury per registration or renewal
+
+	// Treasury runway parameters
+	TreasuryBurnWindow     int64  `json:"treasury_burn_window"`     // Seconds of executed outflows the burn rate averages
+	TreasuryRunwayWarning  uint64 `json:"treasury_runway_warning"`  // Months of runway below which members are warned
+	TreasuryRunwayCritical uint64 `json:"treasury_runway_critical"` // Months of runway below which the warning is critical
 }
 
 // ParameterChange represents a parameter change event
@@ -88,6 +170,10 @@ func NewParameterManager(governanceState *GovernanceState, tokenState *Governanc
 		tokenState:       tokenState,
 		parameterConfig:  NewDefaultParameterConfig(),
 		parameterHistory: make(map[string][]*ParameterChange),
+		tracks:           make(map[string]*ProposalTrack),
+		proposedTracks:   make(map[types.Hash]*TrackProposalTx),
+		trackQueues:      make(map[string][]types.Hash),
+		scheduledChanges: make(map[types.Hash]*ParameterScheduleTx),
 	}
 }
 
@@ -130,6 +216,82 @@ func NewDefaultParameterConfig() *ParameterConfig {
 		EmergencyPauseEnabled: true,
 		MultiSigRequired:      true,
 		AuditLogRetention:     2592000, // 30 days
+
+		// Position parameters
+		VestingPositionsTransferable: false,
+		StakePositionsTransferable:   true,
+
+		// Treasury yield parameters
+		TreasuryYieldShare: 0,      // Disabled until governance sets a split
+		TreasuryYieldEpoch: 604800, // 7 days
+
+		// Dispute parameters
+		DisputeJurorCount:    5,
+		DisputeMinJurorStake: 1000,
+		DisputePhasePeriod:   172800, // 2 days
+		DisputeBond:          500,
+
+		// State pruning parameters
+		VoteRetentionPeriod: 0,
+
+		// Validator commission parameters
+		ValidatorMinCommission:       0,
+		ValidatorMaxCommission:       2000, // 20%
+		ValidatorCommissionMaxChange: 500,  // 5% per change
+		ValidatorCommissionCooldown:  86400,
+
+		// Sponsored voting parameters
+		VoteSponsorshipMaxBudget: 0, // Disabled until governance sets a limit
+
+		// Membership parameters
+		MembershipMinTokens:  0,
+		MembershipApprovals:  2,
+		MembershipRequireKYC: false,
+
+		// Optimistic governance parameters
+		OptimisticBond:            1000,
+		OptimisticChallengePeriod: 259200, // 3 days
+
+		// Stable denominated treasury parameters
+		TreasuryConversionSlippage: 200, // 2%
+
+		// Constitution parameters
+		ConstitutionAmendmentThreshold: 6667, // Two thirds
+
+		// Emergency spend parameters
+		EmergencyVotingPeriod: 21600, // 6 hours
+		EmergencyQuorum:       4000,
+		EmergencyThreshold:    6667, // Two thirds
+
+		// Participation reward parameters
+		ParticipationRewardBudget: 0, // Disabled until governance funds the pool
+		ParticipationRewardCap:    50,
+		ParticipationRewardEpoch:  604800, // 1 week
+
+		// Adaptive quorum parameters
+		AdaptiveQuorumWindow: 0, // Disabled, the fixed quorum threshold applies
+		AdaptiveQuorumMin:    1000,
+		AdaptiveQuorumMax:    5000,
+
+		// Endorsement parameters
+		EndorsementThreshold:  0,      // Disabled, proposals open for voting at once
+		EndorsementPeriod:     172800, // 48 hours
+		EndorsementDeposit:    1000,
+		EndorsementExpiryBurn: 1000, // 10%
+
+		// Privacy parameters
+		PrivacyDefaultBalance:    uint64(VisibilityPublic),
+		PrivacyDefaultReputation: uint64(VisibilityPublic),
+		PrivacyDefaultVotes:      uint64(VisibilityPublic),
+
+		// Name registry parameters
+		NameRegistrationPeriod: 31536000, // 1 year
+		NameRegistrationFee:    100,
+
+		// Treasury runway parameters
+		TreasuryBurnWindow:     7776000, // 90 days
+		TreasuryRunwayWarning:  12,
+		TreasuryRunwayCritical: 6,
 	}
 }
 
@@ -189,7 +351,7 @@ func (pm *ParameterManager) CreateParameterProposal(creator crypto.PublicKey, pa
 
 	// Store proposal
 	pm.governanceState.Proposals[proposalID] = proposal
-	pm.governanceState.Votes[proposalID] = make(map[string]*Vote)
+	pm.governanceState.ResetVotes(proposalID)
 
 	return proposalID, nil
 }
@@ -291,12 +453,67 @@ func (pm *ParameterManager) validateSingleParameter(param string, value interfac
 			return fmt.Errorf("max_token_supply must be uint64")
 		}
 
-	case "token_burning_enabled", "delegation_enabled", "reputation_enabled", "emergency_pause_enabled", "multi_sig_required":
+	case "token_burning_enabled", "delegation_enabled", "reputation_enabled", "emergency_pause_enabled", "multi_sig_required",
+		"vesting_positions_transferable", "stake_positions_transferable", "membership_require_kyc":
 		if _, ok := value.(bool); !ok {
 			return fmt.Errorf("%s must be bool", param)
 		}
 
-	case "max_delegation_period", "min_delegation_period", "audit_log_retention":
+	case "treasury_yield_share":
+		if v, ok := value.(uint64); ok {
+			if v > 10000 {
+				return fmt.Errorf("treasury yield share cannot exceed 10000 basis points")
+			}
+		} else {
+			return fmt.Errorf("treasury_yield_share must be uint64")
+		}
+
+	case "dispute_juror_count":
+		if v, ok := value.(uint64); ok {
+			if v == 0 || v%2 == 0 {
+				return fmt.Errorf("dispute juror count must be odd and greater than zero")
+			}
+		} else {
+			return fmt.Errorf("dispute_juror_count must be uint64")
+		}
+
+	case "privacy_default_balance", "privacy_default_reputation", "privacy_default_votes":
+		if v, ok := value.(uint64); ok {
+			if v < uint64(VisibilityPublic) || v > uint64(VisibilityPrivate) {
+				return fmt.Errorf("%s must be 1 (public), 2 (members) or 3 (private)", param)
+			}
+		} else {
+			return fmt.Errorf("%s must be uint64", param)
+		}
+
+	case "adaptive_quorum_min", "adaptive_quorum_max":
+		if v, ok := value.(uint64); ok {
+			if v == 0 {
+				return fmt.Errorf("%s must be greater than zero", param)
+			}
+		} else {
+			return fmt.Errorf("%s must be uint64", param)
+		}
+
+	case "membership_approvals":
+		if v, ok := value.(uint64); ok {
+			if v == 0 {
+				return fmt.Errorf("membership approvals must be greater than zero")
+			}
+		} else {
+			return fmt.Errorf("membership_approvals must be uint64")
+		}
+
+	case "dispute_min_juror_stake", "dispute_bond", "vote_sponsorship_max_budget", "membership_min_tokens", "optimistic_bond",
+		"emergency_quorum", "participation_reward_budget", "participation_reward_cap", "adaptive_quorum_window",
+		"endorsement_threshold", "endorsement_deposit", "name_registration_fee", "treasury_runway_warning", "treasury_runway_critical":
+		if _, ok := value.(uint64); !ok {
+			return fmt.Errorf("%s must be uint64", param)
+		}
+
+	case "max_delegation_period", "min_delegation_period", "audit_log_retention", "treasury_yield_epoch", "dispute_phase_period",
+		"optimistic_challenge_period", "emergency_voting_period", "participation_reward_epoch",
+		"endorsement_period", "name_registration_period", "treasury_burn_window":
 		if v, ok := value.(int64); ok {
 			if v <= 0 {
 				return fmt.Errorf("%s must be positive", param)
@@ -305,6 +522,43 @@ func (pm *ParameterManager) validateSingleParameter(param string, value interfac
 			return fmt.Errorf("%s must be int64", param)
 		}
 
+	case "validator_min_commission", "validator_max_commission", "validator_commission_max_change", "treasury_conversion_slippage",
+		"endorsement_expiry_burn":
+		if v, ok := value.(uint64); ok {
+			if v > 10000 {
+				return fmt.Errorf("%s cannot exceed 10000 basis points", param)
+			}
+		} else {
+			return fmt.Errorf("%s must be uint64", param)
+		}
+
+	case "constitution_amendment_threshold", "emergency_threshold":
+		if v, ok := value.(uint64); ok {
+			if v <= 5000 || v > 10000 {
+				return fmt.Errorf("%s must be a supermajority between 5001 and 10000 basis points", param)
+			}
+		} else {
+			return fmt.Errorf("%s must be uint64", param)
+		}
+
+	case "validator_commission_cooldown":
+		if v, ok := value.(int64); ok {
+			if v < 0 {
+				return fmt.Errorf("validator commission cooldown cannot be negative")
+			}
+		} else {
+			return fmt.Errorf("validator_commission_cooldown must be int64")
+		}
+
+	case "vote_retention_period":
+		if v, ok := value.(int64); ok {
+			if v < 0 {
+				return fmt.Errorf("vote retention period cannot be negative")
+			}
+		} else {
+			return fmt.Errorf("vote_retention_period must be int64")
+		}
+
 	case "reputation_decay_rate", "reputation_boost_rate", "quadratic_voting_cost", "token_minting_rate":
 		if v, ok := value.(uint64); ok {
 			if param == "reputation_decay_rate" || param == "reputation_boost_rate" {
@@ -325,7 +579,7 @@ func (pm *ParameterManager) validateSingleParameter(param string, value interfac
 
 // ExecuteParameterChanges executes approved parameter changes
 func (pm *ParameterManager) ExecuteParameterChanges(proposalID types.Hash, executor crypto.PublicKey) error {
-	proposal, exists := pm.governanceState.Proposals[proposalID]
+	proposal, exists := pm.governanceState.Proposal(proposalID)
 	if !exists {
 		return ErrProposalNotFoundError
 	}
@@ -431,6 +685,90 @@ func (pm *ParameterManager) applyParameterChange(param string, value interface{}
 		pm.parameterConfig.MultiSigRequired = value.(bool)
 	case "audit_log_retention":
 		pm.parameterConfig.AuditLogRetention = value.(int64)
+	case "vesting_positions_transferable":
+		pm.parameterConfig.VestingPositionsTransferable = value.(bool)
+	case "stake_positions_transferable":
+		pm.parameterConfig.StakePositionsTransferable = value.(bool)
+	case "treasury_yield_share":
+		pm.parameterConfig.TreasuryYieldShare = value.(uint64)
+	case "treasury_yield_epoch":
+		pm.parameterConfig.TreasuryYieldEpoch = value.(int64)
+	case "dispute_juror_count":
+		pm.parameterConfig.DisputeJurorCount = value.(uint64)
+	case "dispute_min_juror_stake":
+		pm.parameterConfig.DisputeMinJurorStake = value.(uint64)
+	case "dispute_phase_period":
+		pm.parameterConfig.DisputePhasePeriod = value.(int64)
+	case "dispute_bond":
+		pm.parameterConfig.DisputeBond = value.(uint64)
+	case "vote_retention_period":
+		pm.parameterConfig.VoteRetentionPeriod = value.(int64)
+	case "validator_min_commission":
+		pm.parameterConfig.ValidatorMinCommission = value.(uint64)
+	case "validator_max_commission":
+		pm.parameterConfig.ValidatorMaxCommission = value.(uint64)
+	case "validator_commission_max_change":
+		pm.parameterConfig.ValidatorCommissionMaxChange = value.(uint64)
+	case "validator_commission_cooldown":
+		pm.parameterConfig.ValidatorCommissionCooldown = value.(int64)
+	case "vote_sponsorship_max_budget":
+		pm.parameterConfig.VoteSponsorshipMaxBudget = value.(uint64)
+	case "membership_min_tokens":
+		pm.parameterConfig.MembershipMinTokens = value.(uint64)
+	case "membership_approvals":
+		pm.parameterConfig.MembershipApprovals = value.(uint64)
+	case "membership_require_kyc":
+		pm.parameterConfig.MembershipRequireKYC = value.(bool)
+	case "optimistic_bond":
+		pm.parameterConfig.OptimisticBond = value.(uint64)
+	case "optimistic_challenge_period":
+		pm.parameterConfig.OptimisticChallengePeriod = value.(int64)
+	case "treasury_conversion_slippage":
+		pm.parameterConfig.TreasuryConversionSlippage = value.(uint64)
+	case "constitution_amendment_threshold":
+		pm.parameterConfig.ConstitutionAmendmentThreshold = value.(uint64)
+	case "emergency_voting_period":
+		pm.parameterConfig.EmergencyVotingPeriod = value.(int64)
+	case "emergency_quorum":
+		pm.parameterConfig.EmergencyQuorum = value.(uint64)
+	case "emergency_threshold":
+		pm.parameterConfig.EmergencyThreshold = value.(uint64)
+	case "participation_reward_budget":
+		pm.parameterConfig.ParticipationRewardBudget = value.(uint64)
+	case "participation_reward_cap":
+		pm.parameterConfig.ParticipationRewardCap = value.(uint64)
+	case "participation_reward_epoch":
+		pm.parameterConfig.ParticipationRewardEpoch = value.(int64)
+	case "adaptive_quorum_window":
+		pm.parameterConfig.AdaptiveQuorumWindow = value.(uint64)
+	case "adaptive_quorum_min":
+		pm.parameterConfig.AdaptiveQuorumMin = value.(uint64)
+	case "adaptive_quorum_max":
+		pm.parameterConfig.AdaptiveQuorumMax = value.(uint64)
+	case "endorsement_threshold":
+		pm.parameterConfig.EndorsementThreshold = value.(uint64)
+	case "endorsement_period":
+		pm.parameterConfig.EndorsementPeriod = value.(int64)
+	case "endorsement_deposit":
+		pm.parameterConfig.EndorsementDeposit = value.(uint64)
+	case "endorsement_expiry_burn":
+		pm.parameterConfig.EndorsementExpiryBurn = value.(uint64)
+	case "privacy_default_balance":
+		pm.parameterConfig.PrivacyDefaultBalance = value.(uint64)
+	case "privacy_default_reputation":
+		pm.parameterConfig.PrivacyDefaultReputation = value.(uint64)
+	case "privacy_default_votes":
+		pm.parameterConfig.PrivacyDefaultVotes = value.(uint64)
+	case "name_registration_period":
+		pm.parameterConfig.NameRegistrationPeriod = value.(int64)
+	case "name_registration_fee":
+		pm.parameterConfig.NameRegistrationFee = value.(uint64)
+	case "treasury_burn_window":
+		pm.parameterConfig.TreasuryBurnWindow = value.(int64)
+	case "treasury_runway_warning":
+		pm.parameterConfig.TreasuryRunwayWarning = value.(uint64)
+	case "treasury_runway_critical":
+		pm.parameterConfig.TreasuryRunwayCritical = value.(uint64)
 	default:
 		return fmt.Errorf("unknown parameter: %s", param)
 	}
@@ -487,6 +825,90 @@ func (pm *ParameterManager) getCurrentParameterValue(param string) interface{} {
 		return pm.parameterConfig.MultiSigRequired
 	case "audit_log_retention":
 		return pm.parameterConfig.AuditLogRetention
+	case "vesting_positions_transferable":
+		return pm.parameterConfig.VestingPositionsTransferable
+	case "stake_positions_transferable":
+		return pm.parameterConfig.StakePositionsTransferable
+	case "treasury_yield_share":
+		return pm.parameterConfig.TreasuryYieldShare
+	case "treasury_yield_epoch":
+		return pm.parameterConfig.TreasuryYieldEpoch
+	case "dispute_juror_count":
+		return pm.parameterConfig.DisputeJurorCount
+	case "dispute_min_juror_stake":
+		return pm.parameterConfig.DisputeMinJurorStake
+	case "dispute_phase_period":
+		return pm.parameterConfig.DisputePhasePeriod
+	case "dispute_bond":
+		return pm.parameterConfig.DisputeBond
+	case "vote_retention_period":
+		return pm.parameterConfig.VoteRetentionPeriod
+	case "validator_min_commission":
+		return pm.parameterConfig.ValidatorMinCommission
+	case "validator_max_commission":
+		return pm.parameterConfig.ValidatorMaxCommission
+	case "validator_commission_max_change":
+		return pm.parameterConfig.ValidatorCommissionMaxChange
+	case "validator_commission_cooldown":
+		return pm.parameterConfig.ValidatorCommissionCooldown
+	case "vote_sponsorship_max_budget":
+		return pm.parameterConfig.VoteSponsorshipMaxBudget
+	case "membership_min_tokens":
+		return pm.parameterConfig.MembershipMinTokens
+	case "membership_approvals":
+		return pm.parameterConfig.MembershipApprovals
+	case "membership_require_kyc":
+		return pm.parameterConfig.MembershipRequireKYC
+	case "optimistic_bond":
+		return pm.parameterConfig.OptimisticBond
+	case "optimistic_challenge_period":
+		return pm.parameterConfig.OptimisticChallengePeriod
+	case "treasury_conversion_slippage":
+		return pm.parameterConfig.TreasuryConversionSlippage
+	case "constitution_amendment_threshold":
+		return pm.parameterConfig.ConstitutionAmendmentThreshold
+	case "emergency_voting_period":
+		return pm.parameterConfig.EmergencyVotingPeriod
+	case "emergency_quorum":
+		return pm.parameterConfig.EmergencyQuorum
+	case "emergency_threshold":
+		return pm.parameterConfig.EmergencyThreshold
+	case "participation_reward_budget":
+		return pm.parameterConfig.ParticipationRewardBudget
+	case "participation_reward_cap":
+		return pm.parameterConfig.ParticipationRewardCap
+	case "participation_reward_epoch":
+		return pm.parameterConfig.ParticipationRewardEpoch
+	case "adaptive_quorum_window":
+		return pm.parameterConfig.AdaptiveQuorumWindow
+	case "adaptive_quorum_min":
+		return pm.parameterConfig.AdaptiveQuorumMin
+	case "adaptive_quorum_max":
+		return pm.parameterConfig.AdaptiveQuorumMax
+	case "endorsement_threshold":
+		return pm.parameterConfig.EndorsementThreshold
+	case "endorsement_period":
+		return pm.parameterConfig.EndorsementPeriod
+	case "endorsement_deposit":
+		return pm.parameterConfig.EndorsementDeposit
+	case "endorsement_expiry_burn":
+		return pm.parameterConfig.EndorsementExpiryBurn
+	case "privacy_default_balance":
+		return pm.parameterConfig.PrivacyDefaultBalance
+	case "privacy_default_reputation":
+		return pm.parameterConfig.PrivacyDefaultReputation
+	case "privacy_default_votes":
+		return pm.parameterConfig.PrivacyDefaultVotes
+	case "name_registration_period":
+		return pm.parameterConfig.NameRegistrationPeriod
+	case "name_registration_fee":
+		return pm.parameterConfig.NameRegistrationFee
+	case "treasury_burn_window":
+		return pm.parameterConfig.TreasuryBurnWindow
+	case "treasury_runway_warning":
+		return pm.parameterConfig.TreasuryRunwayWarning
+	case "treasury_runway_critical":
+		return pm.parameterConfig.TreasuryRunwayCritical
 	default:
 		return nil
 	}
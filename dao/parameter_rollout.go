@@ -0,0 +1,269 @@
+package dao
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxScheduledChanges bounds the parameter changes of one schedule proposal
+const MaxScheduledChanges = 16
+
+// MaxRampPeriod is the longest a parameter may take to reach its target
+const MaxRampPeriod = 365 * 24 * 3600
+
+// Statuses of pending parameter changes
+const (
+	RolloutStatusVoting    = "voting"    // The proposal has not passed yet
+	RolloutStatusScheduled = "scheduled" // Waiting for the activation height
+	RolloutStatusRamping   = "ramping"   // Moving towards the target value
+)
+
+// ParameterRollout is a scheduled parameter change of a passed proposal
+// that has not reached its target yet
+type ParameterRollout struct {
+	ProposalID       types.Hash  `json:"proposal_id"`
+	Parameter        string      `json:"parameter"`
+	From             interface{} `json:"from,omitempty"` // Value when the rollout started
+	Target           interface{} `json:"target"`
+	ActivationHeight uint32      `json:"activation_height,omitempty"`
+	RampPeriod       int64       `json:"ramp_period,omitempty"`
+	StartedAt        int64       `json:"started_at,omitempty"` // 0 until the activation height is reached
+}
+
+// PendingParameterChange previews a parameter change that has not taken
+// full effect
+type PendingParameterChange struct {
+	ProposalID       types.Hash  `json:"proposal_id"`
+	Parameter        string      `json:"parameter"`
+	Status           string      `json:"status"`
+	CurrentValue     interface{} `json:"current_value"`
+	TargetValue      interface{} `json:"target_value"`
+	ActivationHeight uint32      `json:"activation_height,omitempty"`
+	BlocksRemaining  uint32      `json:"blocks_remaining,omitempty"`
+	RampPeriod       int64       `json:"ramp_period,omitempty"`
+	RampStartedAt    int64       `json:"ramp_started_at,omitempty"`
+	RampEndsAt       int64       `json:"ramp_ends_at,omitempty"`
+}
+
+// Proposal returns the parameter proposal of a schedule
+func (tx *ParameterScheduleTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        "Parameter schedule: " + tx.Title,
+		Description:  description,
+		ProposalType: ProposalTypeParameter,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// RecordParameterSchedule records the scheduled changes of a proposal
+func (pm *ParameterManager) RecordParameterSchedule(proposalID types.Hash, tx *ParameterScheduleTx) {
+	pm.scheduledChanges[proposalID] = tx
+}
+
+// ScheduledProposals returns the schedule proposals awaiting their vote
+func (pm *ParameterManager) ScheduledProposals() []types.Hash {
+	ids := make([]types.Hash, 0, len(pm.scheduledChanges))
+	for id := range pm.scheduledChanges {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// observeHeight keeps the latest block height for activation heights
+func (pm *ParameterManager) observeHeight(height uint32) {
+	if height > pm.height {
+		pm.height = height
+	}
+}
+
+// parseScheduledChange returns the target value of a scheduled change
+func (pm *ParameterManager) parseScheduledChange(change ScheduledParameterChange) (interface{}, error) {
+	current := pm.getCurrentParameterValue(change.Parameter)
+	if current == nil {
+		return nil, fmt.Errorf("unknown parameter: %s", change.Parameter)
+	}
+
+	value, err := parseParameterValue(current, change.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a %T", change.Parameter, current)
+	}
+	if change.RampPeriod > 0 {
+		if _, ok := current.(bool); ok {
+			return nil, fmt.Errorf("%s cannot be ramped", change.Parameter)
+		}
+	}
+
+	return value, nil
+}
+
+// ApplyParameterRollouts starts the schedules of passed proposals and moves
+// every rollout on to where it should be at now, returning the changes that
+// reached their target
+func (pm *ParameterManager) ApplyParameterRollouts(now int64) []*ParameterChange {
+	for _, id := range pm.ScheduledProposals() {
+		proposal, exists := pm.governanceState.Proposal(id)
+		if !exists {
+			delete(pm.scheduledChanges, id)
+			continue
+		}
+		switch proposal.Status {
+		case ProposalStatusPassed:
+			for _, change := range pm.scheduledChanges[id].Changes {
+				target, err := pm.parseScheduledChange(change)
+				if err != nil {
+					continue
+				}
+				pm.rollouts = append(pm.rollouts, &ParameterRollout{
+					ProposalID:       id,
+					Parameter:        change.Parameter,
+					Target:           target,
+					ActivationHeight: change.ActivationHeight,
+					RampPeriod:       change.RampPeriod,
+				})
+			}
+			proposal.Status = ProposalStatusExecuted
+			delete(pm.scheduledChanges, id)
+		case ProposalStatusRejected, ProposalStatusCancelled, ProposalStatusExecuted:
+			delete(pm.scheduledChanges, id)
+		}
+	}
+
+	var completed []*ParameterChange
+	remaining := pm.rollouts[:0]
+	for _, rollout := range pm.rollouts {
+		if rollout.StartedAt == 0 {
+			if rollout.ActivationHeight > pm.height {
+				remaining = append(remaining, rollout)
+				continue
+			}
+			// Limits may have moved since the vote, such as the supply the
+			// thresholds are checked against
+			if err := pm.validateSingleParameter(rollout.Parameter, rollout.Target); err != nil {
+				continue
+			}
+			rollout.From = pm.getCurrentParameterValue(rollout.Parameter)
+			rollout.StartedAt = now
+		}
+
+		elapsed := now - rollout.StartedAt
+		if elapsed < rollout.RampPeriod {
+			pm.applyParameterChange(rollout.Parameter, rampValue(rollout.From, rollout.Target, elapsed, rollout.RampPeriod))
+			remaining = append(remaining, rollout)
+			continue
+		}
+
+		if err := pm.applyParameterChange(rollout.Parameter, rollout.Target); err != nil {
+			continue
+		}
+		change := &ParameterChange{
+			Parameter:  rollout.Parameter,
+			OldValue:   rollout.From,
+			NewValue:   rollout.Target,
+			ChangedAt:  now,
+			ProposalID: rollout.ProposalID,
+			Reason:     "Scheduled parameter change",
+		}
+		if proposal, exists := pm.governanceState.Proposal(rollout.ProposalID); exists {
+			change.ChangedBy = proposal.Creator
+			change.Reason = proposal.Description
+		}
+		pm.parameterHistory[rollout.Parameter] = append(pm.parameterHistory[rollout.Parameter], change)
+		completed = append(completed, change)
+	}
+	pm.rollouts = remaining
+
+	return completed
+}
+
+// PendingParameterChanges previews the scheduled changes that have not
+// reached their target, those awaiting their vote first
+func (pm *ParameterManager) PendingParameterChanges() []PendingParameterChange {
+	pending := make([]PendingParameterChange, 0)
+
+	for _, id := range pm.ScheduledProposals() {
+		for _, change := range pm.scheduledChanges[id].Changes {
+			target, err := pm.parseScheduledChange(change)
+			if err != nil {
+				continue
+			}
+			pending = append(pending, PendingParameterChange{
+				ProposalID:       id,
+				Parameter:        change.Parameter,
+				Status:           RolloutStatusVoting,
+				CurrentValue:     pm.getCurrentParameterValue(change.Parameter),
+				TargetValue:      target,
+				ActivationHeight: change.ActivationHeight,
+				RampPeriod:       change.RampPeriod,
+			})
+		}
+	}
+
+	for _, rollout := range pm.rollouts {
+		p := PendingParameterChange{
+			ProposalID:       rollout.ProposalID,
+			Parameter:        rollout.Parameter,
+			Status:           RolloutStatusScheduled,
+			CurrentValue:     pm.getCurrentParameterValue(rollout.Parameter),
+			TargetValue:      rollout.Target,
+			ActivationHeight: rollout.ActivationHeight,
+			RampPeriod:       rollout.RampPeriod,
+		}
+		if rollout.StartedAt != 0 {
+			p.Status = RolloutStatusRamping
+			p.RampStartedAt = rollout.StartedAt
+			p.RampEndsAt = rollout.StartedAt + rollout.RampPeriod
+		} else if rollout.ActivationHeight > pm.height {
+			p.BlocksRemaining = rollout.ActivationHeight - pm.height
+		}
+		pending = append(pending, p)
+	}
+
+	return pending
+}
+
+// rampValue returns the value elapsed seconds into a linear ramp from from
+// to target over period seconds
+func rampValue(from, target interface{}, elapsed, period int64) interface{} {
+	switch f := from.(type) {
+	case uint64:
+		return rampUint64(f, target.(uint64), elapsed, period)
+	case uint8:
+		return uint8(rampUint64(uint64(f), uint64(target.(uint8)), elapsed, period))
+	case int64:
+		t := target.(int64)
+		if t >= f {
+			return f + int64(scaleUint64(uint64(t-f), elapsed, period))
+		}
+		return f - int64(scaleUint64(uint64(f-t), elapsed, period))
+	default:
+		return target
+	}
+}
+
+func rampUint64(from, target uint64, elapsed, period int64) uint64 {
+	if target >= from {
+		return from + scaleUint64(target-from, elapsed, period)
+	}
+	return from - scaleUint64(from-target, elapsed, period)
+}
+
+// scaleUint64 returns diff*elapsed/period without overflowing. elapsed must
+// be less than period.
+func scaleUint64(diff uint64, elapsed, period int64) uint64 {
+	hi, lo := bits.Mul64(diff, uint64(elapsed))
+	quo, _ := bits.Div64(hi, lo, uint64(period))
+	return quo
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterRollout(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+	dao.CollectBlockFees(founder, 0, 10)
+
+	now := time.Now().Unix()
+	schedule := &ParameterScheduleTx{
+		Fee:   100,
+		Title: "Longer votes",
+		Changes: []ScheduledParameterChange{
+			{Parameter: "voting_period", Value: "172800", RampPeriod: 10 * 86400},
+			{Parameter: "passing_threshold", Value: "6000", ActivationHeight: 5},
+		},
+		VotingType: VotingTypeSimple,
+		StartTime:  now,
+		EndTime:    now + 86400,
+		Threshold:  5100,
+	}
+
+	// Activation heights must be ahead of the chain and values valid
+	assert.Error(t, dao.ProcessDAOTransaction(schedule, founder, types.Hash{0x01}))
+	schedule.Changes[1].ActivationHeight = 20
+	schedule.Changes[0].Value = "60"
+	assert.Error(t, dao.ProcessDAOTransaction(schedule, founder, types.Hash{0x02}))
+	schedule.Changes[0].Value = "172800"
+	schedule.Changes = append(schedule.Changes, ScheduledParameterChange{Parameter: "delegation_enabled", Value: "false", RampPeriod: 3600})
+	assert.Error(t, dao.ProcessDAOTransaction(schedule, founder, types.Hash{0x03}))
+	schedule.Changes = schedule.Changes[:2]
+
+	proposalID := types.Hash{0x04}
+	require.NoError(t, dao.ProcessDAOTransaction(schedule, founder, proposalID))
+
+	pending := dao.GetPendingParameterChanges()
+	require.Len(t, pending, 2)
+	assert.Equal(t, RolloutStatusVoting, pending[0].Status)
+	assert.Equal(t, uint64(6000), pending[1].TargetValue)
+
+	// Nothing changes until the proposal passes
+	assert.Empty(t, dao.ApplyParameterRollouts(now))
+	assert.Equal(t, int64(86400), dao.GetParameterConfig().VotingPeriod)
+
+	proposal, err := dao.GetProposal(proposalID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	assert.Empty(t, dao.ApplyParameterRollouts(now))
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	pending = dao.GetPendingParameterChanges()
+	require.Len(t, pending, 2)
+	assert.Equal(t, RolloutStatusRamping, pending[0].Status)
+	assert.Equal(t, now+10*86400, pending[0].RampEndsAt)
+	assert.Equal(t, RolloutStatusScheduled, pending[1].Status)
+	assert.Equal(t, uint32(10), pending[1].BlocksRemaining)
+
+	// The voting period ramps linearly while the threshold waits for its
+	// block
+	assert.Empty(t, dao.ApplyParameterRollouts(now+5*86400))
+	assert.Equal(t, int64(129600), dao.GetParameterConfig().VotingPeriod)
+	assert.Equal(t, uint64(5100), dao.GetParameterConfig().PassingThreshold)
+
+	dao.CollectBlockFees(founder, 0, 20)
+	changes := dao.ApplyParameterRollouts(now + 6*86400)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "passing_threshold", changes[0].Parameter)
+	assert.Equal(t, uint64(5100), changes[0].OldValue)
+	assert.Equal(t, uint64(6000), dao.GetParameterConfig().PassingThreshold)
+
+	changes = dao.ApplyParameterRollouts(now + 10*86400)
+	require.Len(t, changes, 1)
+	assert.Equal(t, int64(172800), dao.GetParameterConfig().VotingPeriod)
+	assert.Equal(t, int64(86400), changes[0].OldValue)
+	assert.Len(t, dao.GetParameterHistory("voting_period"), 1)
+	assert.Empty(t, dao.GetPendingParameterChanges())
+}
+
+func TestRampValue(t *testing.T) {
+	assert.Equal(t, uint64(150), rampValue(uint64(100), uint64(200), 1, 2))
+	assert.Equal(t, uint64(175), rampValue(uint64(200), uint64(100), 1, 4))
+	assert.Equal(t, int64(-50), rampValue(int64(0), int64(-100), 1, 2))
+	assert.Equal(t, uint8(5), rampValue(uint8(0), uint8(10), 1, 2))
+	// Large values do not overflow
+	assert.Equal(t, uint64(1<<63-1), rampValue(uint64(0), ^uint64(0), 1, 2))
+}
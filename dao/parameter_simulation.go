@@ -0,0 +1,161 @@
+package dao
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// SimulatedParameters are the parameters a simulation can replay proposals
+// under
+var SimulatedParameters = []string{"quorum_threshold", "passing_threshold"}
+
+// IsSimulatedParameter reports whether proposals can be replayed under a
+// proposed value of param
+func IsSimulatedParameter(param string) bool {
+	for _, known := range SimulatedParameters {
+		if known == param {
+			return true
+		}
+	}
+	return false
+}
+
+// ParameterSimulation reports how the latest closed proposals would have
+// ended under proposed quorum and passing thresholds
+type ParameterSimulation struct {
+	QuorumThreshold          uint64              `json:"quorum_threshold"`
+	PassingThreshold         uint64              `json:"passing_threshold"`
+	ProposedQuorumThreshold  uint64              `json:"proposed_quorum_threshold"`
+	ProposedPassingThreshold uint64              `json:"proposed_passing_threshold"`
+	Replayed                 int                 `json:"replayed"`
+	Changed                  int                 `json:"changed"`
+	NewlyPassed              int                 `json:"newly_passed"`
+	NewlyFailed              int                 `json:"newly_failed"` // Passed proposals that would have failed or missed quorum
+	Proposals                []SimulatedProposal `json:"proposals"`
+}
+
+// SimulatedProposal is the replayed outcome of one closed proposal
+type SimulatedProposal struct {
+	ProposalID       types.Hash `json:"proposal_id"`
+	Title            string     `json:"title"`
+	EndTime          int64      `json:"end_time"`
+	YesVotes         uint64     `json:"yes_votes"`
+	NoVotes          uint64     `json:"no_votes"`
+	AbstainVotes     uint64     `json:"abstain_votes"`
+	Outcome          string     `json:"outcome"`           // Outcome recorded when voting ended
+	SimulatedOutcome string     `json:"simulated_outcome"` // Outcome under the proposed values
+	Changed          bool       `json:"changed"`
+}
+
+// SimulateParameterChanges replays the votes of the latest limit closed
+// proposals under proposed values of the simulated parameters, newest
+// first. Values are written as in parameter templates and must be valid
+// parameter changes.
+func (pm *ParameterManager) SimulateParameterChanges(changes map[string]string, limit int) (*ParameterSimulation, error) {
+	config := *pm.governanceState.Config
+	for param, value := range changes {
+		if !IsSimulatedParameter(param) {
+			return nil, NewDAOError(ErrInvalidProposal, fmt.Sprintf("%s cannot be simulated", param), map[string]interface{}{
+				"parameter": param,
+			})
+		}
+
+		parsed, err := parseParameterValue(pm.getCurrentParameterValue(param), value)
+		if err == nil {
+			err = pm.validateSingleParameter(param, parsed)
+		}
+		if err != nil {
+			return nil, NewDAOError(ErrInvalidProposal, err.Error(), map[string]interface{}{
+				"parameter": param,
+			})
+		}
+
+		switch param {
+		case "quorum_threshold":
+			config.QuorumThreshold = parsed.(uint64)
+		case "passing_threshold":
+			config.PassingThreshold = parsed.(uint64)
+		}
+	}
+
+	sim := &ParameterSimulation{
+		QuorumThreshold:          pm.governanceState.Config.QuorumThreshold,
+		PassingThreshold:         pm.governanceState.Config.PassingThreshold,
+		ProposedQuorumThreshold:  config.QuorumThreshold,
+		ProposedPassingThreshold: config.PassingThreshold,
+		Proposals:                make([]SimulatedProposal, 0),
+	}
+
+	for _, proposal := range pm.closedProposals(limit) {
+		replay := ProposalSimulation{
+			QuorumThreshold:  proposal.RequiredQuorum(&config),
+			PassingThreshold: proposal.RequiredThreshold(&config),
+		}
+		result := SimulatedProposal{
+			ProposalID:   proposal.ID,
+			Title:        proposal.Title,
+			EndTime:      proposal.EndTime,
+			YesVotes:     proposal.Results.YesVotes,
+			NoVotes:      proposal.Results.NoVotes,
+			AbstainVotes: proposal.Results.AbstainVotes,
+			Outcome:      recordedOutcome(proposal),
+		}
+		result.SimulatedOutcome = replay.outcome(result.YesVotes, result.NoVotes, result.AbstainVotes)
+		result.Changed = result.Outcome != result.SimulatedOutcome
+
+		if result.Changed {
+			sim.Changed++
+			if result.SimulatedOutcome == OutcomePass {
+				sim.NewlyPassed++
+			} else if result.Outcome == OutcomePass {
+				sim.NewlyFailed++
+			}
+		}
+		sim.Proposals = append(sim.Proposals, result)
+	}
+	sim.Replayed = len(sim.Proposals)
+
+	return sim, nil
+}
+
+// closedProposals returns the latest limit proposals whose vote has been
+// decided, newest first
+func (pm *ParameterManager) closedProposals(limit int) []*Proposal {
+	var closed []*Proposal
+	pm.governanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
+		if proposal.Results == nil {
+			return true
+		}
+		switch proposal.Status {
+		case ProposalStatusPassed, ProposalStatusRejected, ProposalStatusExecuted:
+			closed = append(closed, proposal)
+		}
+		return true
+	})
+
+	sort.Slice(closed, func(i, j int) bool {
+		if closed[i].EndTime != closed[j].EndTime {
+			return closed[i].EndTime > closed[j].EndTime
+		}
+		return closed[i].ID.String() < closed[j].ID.String()
+	})
+	if limit > 0 && len(closed) > limit {
+		closed = closed[:limit]
+	}
+	return closed
+}
+
+// recordedOutcome returns how a closed proposal ended. The quorum of the
+// results is only recorded when it was met.
+func recordedOutcome(proposal *Proposal) string {
+	switch {
+	case proposal.Results.Passed || proposal.Status == ProposalStatusExecuted:
+		return OutcomePass
+	case proposal.Results.Quorum == 0:
+		return OutcomeNoQuorum
+	default:
+		return OutcomeFail
+	}
+}
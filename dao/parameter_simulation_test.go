@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateParameterChanges(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	closed := func(id byte, endTime int64, status ProposalStatus, results VoteResults) {
+		dao.GovernanceState.Proposals[types.Hash{id}] = &Proposal{
+			ID:      types.Hash{id},
+			Title:   "Proposal",
+			EndTime: endTime,
+			Status:  status,
+			Results: &results,
+		}
+	}
+	closed(0x01, 100, ProposalStatusPassed, VoteResults{YesVotes: 2500, NoVotes: 500, Quorum: 3000, Passed: true})
+	closed(0x02, 200, ProposalStatusExecuted, VoteResults{YesVotes: 5000, NoVotes: 4000, Quorum: 9000, Passed: true})
+	closed(0x03, 300, ProposalStatusRejected, VoteResults{YesVotes: 1500})
+	closed(0x04, 400, ProposalStatusRejected, VoteResults{YesVotes: 2540, NoVotes: 2460, Quorum: 5000})
+	dao.GovernanceState.Proposals[types.Hash{0x05}] = &Proposal{ID: types.Hash{0x05}, EndTime: 500, Status: ProposalStatusActive, Results: &VoteResults{}}
+
+	// Under the current values nothing changes
+	sim, err := dao.SimulateParameterChanges(map[string]string{"quorum_threshold": "2000"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 4, sim.Replayed)
+	assert.Zero(t, sim.Changed)
+	assert.Equal(t, types.Hash{0x04}, sim.Proposals[0].ProposalID)
+	assert.Equal(t, OutcomeFail, sim.Proposals[0].Outcome)
+	assert.Equal(t, OutcomeNoQuorum, sim.Proposals[1].Outcome)
+
+	sim, err = dao.SimulateParameterChanges(map[string]string{"quorum_threshold": "1000", "passing_threshold": "5000"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), sim.ProposedQuorumThreshold)
+	assert.Equal(t, 2, sim.Changed)
+	assert.Equal(t, 2, sim.NewlyPassed)
+	assert.Equal(t, OutcomePass, sim.Proposals[0].SimulatedOutcome)
+	assert.Equal(t, OutcomePass, sim.Proposals[1].SimulatedOutcome)
+
+	sim, err = dao.SimulateParameterChanges(map[string]string{"quorum_threshold": "4000", "passing_threshold": "6000"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sim.Replayed)
+	assert.Zero(t, sim.Changed)
+	sim, err = dao.SimulateParameterChanges(map[string]string{"quorum_threshold": "4000", "passing_threshold": "6000"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sim.NewlyFailed)
+
+	// Values must be valid changes of simulated parameters
+	_, err = dao.SimulateParameterChanges(map[string]string{"voting_period": "7200"}, 10)
+	assert.Error(t, err)
+	_, err = dao.SimulateParameterChanges(map[string]string{"passing_threshold": "20000"}, 10)
+	assert.Error(t, err)
+	_, err = dao.SimulateParameterChanges(map[string]string{"quorum_threshold": "many"}, 10)
+	assert.Error(t, err)
+}
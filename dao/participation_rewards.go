@@ -0,0 +1,180 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// ParticipationEpoch holds the accounting for one participation reward
+// period
+type ParticipationEpoch struct {
+	Number      uint64            `json:"number"`
+	StartTime   int64             `json:"start_time"`
+	EndTime     int64             `json:"end_time"`
+	Budget      uint64            `json:"budget"`
+	VoterCap    uint64            `json:"voter_cap"`
+	Proposals   int               `json:"proposals"` // Proposals finalized with quorum in the epoch
+	Rewards     map[string]uint64 `json:"rewards"`   // Paid by voter address
+	Distributed uint64            `json:"distributed"`
+	Closed      bool              `json:"closed"`
+}
+
+// participationRewards is the state of the participation reward pool
+type participationRewards struct {
+	optIns  map[string]int64 // Opt-in time by address
+	paid    map[string]uint64
+	current *ParticipationEpoch
+	epochs  []*ParticipationEpoch
+}
+
+// openParticipationEpoch starts a new participation epoch at the given time
+func (tm *TokenomicsManager) openParticipationEpoch(startTime int64) {
+	tm.participation.current = &ParticipationEpoch{
+		Number:    uint64(len(tm.participation.epochs)) + 1,
+		StartTime: startTime,
+		Rewards:   make(map[string]uint64),
+	}
+}
+
+// ProcessParticipationOptInTx opts a member in or out of participation
+// rewards. The member pays the fee.
+func (tm *TokenomicsManager) ProcessParticipationOptInTx(tx *ParticipationOptInTx, member crypto.PublicKey) error {
+	tm.SetParticipationOptIn(member, tx.OptIn, time.Now().Unix())
+	tm.tokenState.Balances.Sub(member.String(), uint64(tx.Fee))
+	return nil
+}
+
+// SetParticipationOptIn opts an address in or out of participation rewards.
+// Opting in again keeps the original opt-in time.
+func (tm *TokenomicsManager) SetParticipationOptIn(address crypto.PublicKey, optIn bool, now int64) {
+	addressStr := address.String()
+	if !optIn {
+		delete(tm.participation.optIns, addressStr)
+		return
+	}
+	if _, exists := tm.participation.optIns[addressStr]; !exists {
+		tm.participation.optIns[addressStr] = now
+	}
+}
+
+// ParticipationOptIn returns when an address opted into participation
+// rewards
+func (tm *TokenomicsManager) ParticipationOptIn(address crypto.PublicKey) (int64, bool) {
+	optedInAt, exists := tm.participation.optIns[address.String()]
+	return optedInAt, exists
+}
+
+// ParticipationRewardsPaid returns the participation rewards an address has
+// received
+func (tm *TokenomicsManager) ParticipationRewardsPaid(address crypto.PublicKey) uint64 {
+	return tm.participation.paid[address.String()]
+}
+
+// GetCurrentParticipationEpoch returns the open participation epoch
+func (tm *TokenomicsManager) GetCurrentParticipationEpoch() *ParticipationEpoch {
+	return tm.participation.current
+}
+
+// GetParticipationEpochs returns the closed participation epochs, oldest
+// first
+func (tm *TokenomicsManager) GetParticipationEpochs() []*ParticipationEpoch {
+	return tm.participation.epochs
+}
+
+// CloseParticipationEpoch rewards the opted-in voters of the proposals
+// finalized during the epoch and opens the next epoch.
+//
+// The rewards guard against bribery and farming:
+//   - A voter's reward is voterCap scaled by the share of the epoch's
+//     proposals they voted on. It ignores the vote's direction and weight.
+//   - Only proposals that reached quorum count.
+//   - Only votes on proposals that started after the voter opted in count.
+//   - When the rewards exceed the budget or the treasury balance, they are
+//     scaled down to fit.
+func (tm *TokenomicsManager) CloseParticipationEpoch(now int64, budget, voterCap uint64) *ParticipationEpoch {
+	epoch := tm.participation.current
+	epoch.EndTime = now
+	epoch.Budget = budget
+	epoch.VoterCap = voterCap
+
+	var finalized []*Proposal
+	votes := make(map[types.Hash]map[string]*Vote)
+	tm.governanceState.EachProposal(func(_ types.Hash, proposal *Proposal, proposalVotes map[string]*Vote) bool {
+		if proposal.EndTime < epoch.StartTime || proposal.EndTime >= now {
+			return true
+		}
+		switch proposal.Status {
+		case ProposalStatusPassed, ProposalStatusRejected, ProposalStatusExecuted:
+		default:
+			return true
+		}
+		if proposal.Results == nil || proposal.Results.Quorum == 0 {
+			return true
+		}
+		finalized = append(finalized, proposal)
+		votes[proposal.ID] = proposalVotes
+		return true
+	})
+	epoch.Proposals = len(finalized)
+
+	rewards := make(map[string]uint64)
+	total := uint64(0)
+	if len(finalized) > 0 {
+		for address, optedInAt := range tm.participation.optIns {
+			voted := uint64(0)
+			for _, proposal := range finalized {
+				if proposal.StartTime < optedInAt {
+					continue
+				}
+				if _, exists := votes[proposal.ID][address]; exists {
+					voted++
+				}
+			}
+			if reward := voterCap * voted / uint64(len(finalized)); reward > 0 {
+				rewards[address] = reward
+				total += reward
+			}
+		}
+	}
+
+	available := budget
+	if available > tm.governanceState.Treasury.Balance {
+		available = tm.governanceState.Treasury.Balance
+	}
+
+	// Pay in a stable order so that rounding is deterministic
+	addresses := make([]string, 0, len(rewards))
+	for address := range rewards {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	for _, address := range addresses {
+		reward := rewards[address]
+		if total > available {
+			reward = reward * available / total
+		}
+		if reward == 0 {
+			continue
+		}
+
+		tm.tokenState.Balances.Add(address, reward)
+		if holder, exists := tm.governanceState.TokenHolders[address]; exists {
+			holder.Balance += reward
+		}
+		tm.participation.paid[address] += reward
+		epoch.Rewards[address] = reward
+		epoch.Distributed += reward
+	}
+
+	tm.governanceState.Treasury.Balance -= epoch.Distributed
+	tm.governanceState.Treasury.ParticipationRewards += epoch.Distributed
+
+	epoch.Closed = true
+	tm.participation.epochs = append(tm.participation.epochs, epoch)
+	tm.openParticipationEpoch(now)
+
+	return epoch
+}
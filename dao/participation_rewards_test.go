@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParticipationRewards_Epoch(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	carol := crypto.GeneratePrivateKey().PublicKey()
+	dave := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 10000,
+		bob.String():   5000,
+		carol.String(): 5000,
+		dave.String():  5000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 10000
+
+	// Members opt in with a transaction and can opt out again
+	for i, member := range []crypto.PublicKey{alice, bob, carol} {
+		require.NoError(t, dao.ProcessDAOTransaction(&ParticipationOptInTx{Fee: 10, OptIn: true}, member, types.Hash{0x70, byte(i)}))
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(&ParticipationOptInTx{Fee: 10}, carol, types.Hash{0x73}))
+	_, optedIn := dao.TokenomicsManager.ParticipationOptIn(carol)
+	assert.False(t, optedIn)
+
+	epoch := dao.TokenomicsManager.GetCurrentParticipationEpoch()
+	assert.Nil(t, dao.ProcessParticipationEpoch())
+
+	now := time.Now().Unix()
+	propose := func(id types.Hash) *Proposal {
+		require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+			Fee:          100,
+			Title:        "Routine",
+			Description:  "A routine change",
+			ProposalType: ProposalTypeGeneral,
+			VotingType:   VotingTypeSimple,
+			StartTime:    now,
+			EndTime:      now + 86400,
+			Threshold:    5100,
+		}, alice, id))
+		proposal, err := dao.GetProposal(id)
+		require.NoError(t, err)
+		return proposal
+	}
+	vote := func(proposalID types.Hash, voter crypto.PublicKey, choice VoteChoice, txHash types.Hash) {
+		require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposalID, Choice: choice, Weight: 100}, voter, txHash))
+	}
+	finalize := func(proposal *Proposal, status ProposalStatus, quorum uint64) {
+		proposal.Status = status
+		proposal.Results.Quorum = quorum
+		proposal.EndTime = epoch.StartTime
+	}
+
+	first := propose(types.Hash{0x80})
+	second := propose(types.Hash{0x81})
+	unreached := propose(types.Hash{0x82})
+
+	// Dave opts in after the proposals started, so his votes do not count
+	dao.TokenomicsManager.SetParticipationOptIn(dave, true, now+5)
+
+	vote(first.ID, alice, VoteChoiceYes, types.Hash{0x90})
+	vote(first.ID, bob, VoteChoiceNo, types.Hash{0x91})
+	vote(first.ID, carol, VoteChoiceYes, types.Hash{0x92})
+	vote(first.ID, dave, VoteChoiceYes, types.Hash{0x93})
+	vote(second.ID, alice, VoteChoiceNo, types.Hash{0x94})
+	vote(unreached.ID, bob, VoteChoiceYes, types.Hash{0x95})
+	finalize(first, ProposalStatusPassed, 2400)
+	finalize(second, ProposalStatusRejected, 2100)
+	finalize(unreached, ProposalStatusRejected, 0)
+
+	// Alice voted on both proposals with quorum and bob on one, whatever
+	// the direction. The rewards of 100 and 50 are scaled into the budget.
+	closed := dao.TokenomicsManager.CloseParticipationEpoch(epoch.StartTime+10, 90, 100)
+	assert.True(t, closed.Closed)
+	assert.Equal(t, 2, closed.Proposals)
+	assert.Equal(t, map[string]uint64{alice.String(): 60, bob.String(): 30}, closed.Rewards)
+	assert.Equal(t, uint64(90), closed.Distributed)
+	assert.Equal(t, uint64(10000-90), dao.GetTreasuryBalance())
+	assert.Equal(t, uint64(90), dao.GovernanceState.Treasury.ParticipationRewards)
+	assert.Equal(t, uint64(60), dao.TokenomicsManager.ParticipationRewardsPaid(alice))
+
+	// The next epoch does not pay for the same proposals again
+	next := dao.TokenomicsManager.GetCurrentParticipationEpoch()
+	assert.Equal(t, uint64(2), next.Number)
+	closed = dao.TokenomicsManager.CloseParticipationEpoch(next.StartTime+10, 90, 100)
+	assert.Zero(t, closed.Distributed)
+	assert.Len(t, dao.TokenomicsManager.GetParticipationEpochs(), 2)
+}
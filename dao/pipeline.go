@@ -0,0 +1,288 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/BOCK-CHAIN/BockChain/codec"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// ErrPipelineStopped rejects submissions to a stopped TxPipeline
+var ErrPipelineStopped = errors.New("transaction pipeline is stopped")
+
+// PipelineTx is a DAO transaction submitted to a TxPipeline. Encoded holds
+// the EncodeTx encoding of the transaction and is decoded by the pipeline when
+// Tx is nil. A zero Hash is filled in with the SHA-256 of the encoding. When
+// Signature is set, Hash must be that digest and Signature From's signature
+// of it.
+type PipelineTx struct {
+	Tx        interface{}
+	Encoded   []byte
+	From      crypto.PublicKey
+	Hash      types.Hash
+	Signature *crypto.Signature
+	// Envelope carries what custom stages need beyond the transaction,
+	// such as the chain transaction it came in
+	Envelope interface{}
+}
+
+// PipelineStages are the stages of a TxPipeline. Check runs on the
+// workers, in parallel with the checks of other submissions. Apply runs on
+// the applier for the submissions that passed their check, one at a time
+// and in submission order per sender.
+type PipelineStages struct {
+	Check func(tx *PipelineTx) error
+	Apply func(tx PipelineTx) error
+}
+
+// pipelineJob is a submission moving through the pipeline
+type pipelineJob struct {
+	tx     PipelineTx
+	sender string
+	seq    uint64
+	err    error
+	done   chan error
+}
+
+// senderQueue orders the applies of one sender. Checked jobs that overtook
+// an earlier submission of the same sender wait in pending.
+type senderQueue struct {
+	submitted uint64
+	applied   uint64
+	pending   map[uint64]*pipelineJob
+}
+
+// TxPipeline applies DAO transactions in stages. A pool of workers decodes
+// submissions and checks their hashes and signatures in parallel, then a
+// single applier hands them to ProcessDAOTransaction one at a time.
+// Transactions of one sender are applied in the order they were submitted,
+// transactions of different senders as soon as they are checked. Nodes
+// replace the stages to verify chain transactions and queue them in the
+// mempool instead.
+//
+// Checks that depend on DAO state still run when a transaction is applied,
+// as later transactions can depend on earlier ones.
+type TxPipeline struct {
+	stages  PipelineStages
+	checks  chan *pipelineJob
+	checked chan *pipelineJob
+
+	mu      sync.Mutex
+	senders map[string]*senderQueue
+
+	stopMu  sync.RWMutex
+	stopped bool
+
+	workers sync.WaitGroup
+	applier sync.WaitGroup
+}
+
+// NewTxPipeline starts a pipeline applying to d with the given number of
+// check workers, one per CPU when workers is not positive
+func NewTxPipeline(d *DAO, workers int) *TxPipeline {
+	return NewTxPipelineWithStages(workers, PipelineStages{
+		Check: (*PipelineTx).check,
+		Apply: func(tx PipelineTx) error {
+			return d.ProcessDAOTransaction(tx.Tx, tx.From, tx.Hash)
+		},
+	})
+}
+
+// NewTxPipelineWithStages starts a pipeline running stages with the given
+// number of check workers, one per CPU when workers is not positive
+func NewTxPipelineWithStages(workers int, stages PipelineStages) *TxPipeline {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	tp := &TxPipeline{
+		stages:  stages,
+		checks:  make(chan *pipelineJob, workers*4),
+		checked: make(chan *pipelineJob, workers*4),
+		senders: make(map[string]*senderQueue),
+	}
+
+	tp.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go tp.checkLoop()
+	}
+	tp.applier.Add(1)
+	go tp.applyLoop()
+
+	return tp
+}
+
+// Submit queues tx and returns a channel receiving the result of applying
+// it. Submitting to a stopped pipeline fails at once with
+// ErrPipelineStopped.
+func (tp *TxPipeline) Submit(tx PipelineTx) <-chan error {
+	job := &pipelineJob{tx: tx, sender: tx.From.String(), done: make(chan error, 1)}
+
+	// Stop waits for submissions in flight before closing the channel
+	tp.stopMu.RLock()
+	defer tp.stopMu.RUnlock()
+	if tp.stopped {
+		job.done <- ErrPipelineStopped
+		return job.done
+	}
+
+	tp.mu.Lock()
+	queue, exists := tp.senders[job.sender]
+	if !exists {
+		queue = &senderQueue{pending: make(map[uint64]*pipelineJob)}
+		tp.senders[job.sender] = queue
+	}
+	job.seq = queue.submitted
+	queue.submitted++
+	tp.mu.Unlock()
+
+	tp.checks <- job
+	return job.done
+}
+
+// SubmitAll submits txs in order and waits for all of them, returning the
+// result of each
+func (tp *TxPipeline) SubmitAll(txs []PipelineTx) []error {
+	results := make([]<-chan error, len(txs))
+	for i, tx := range txs {
+		results[i] = tp.Submit(tx)
+	}
+
+	errs := make([]error, len(txs))
+	for i, result := range results {
+		errs[i] = <-result
+	}
+	return errs
+}
+
+// Stop waits for the submitted transactions to be applied and stops the
+// workers
+func (tp *TxPipeline) Stop() {
+	tp.stopMu.Lock()
+	if tp.stopped {
+		tp.stopMu.Unlock()
+		return
+	}
+	tp.stopped = true
+	close(tp.checks)
+	tp.stopMu.Unlock()
+
+	tp.workers.Wait()
+	close(tp.checked)
+	tp.applier.Wait()
+}
+
+// checkLoop decodes and checks submissions in parallel with the other
+// workers
+func (tp *TxPipeline) checkLoop() {
+	defer tp.workers.Done()
+
+	for job := range tp.checks {
+		job.err = tp.stages.Check(&job.tx)
+		tp.checked <- job
+	}
+}
+
+// check decodes the transaction and verifies what does not depend on DAO
+// state
+func (tx *PipelineTx) check() error {
+	if tx.Tx == nil {
+		if len(tx.Encoded) == 0 {
+			return NewDAOError(ErrInvalidProposal, "transaction has no content", nil)
+		}
+		var decoded interface{}
+		if err := codec.Unmarshal(tx.Encoded, &decoded); err != nil {
+			return NewDAOError(ErrInvalidProposal, "failed to decode transaction", map[string]interface{}{"error": err.Error()})
+		}
+		tx.Tx = txPointer(decoded)
+	}
+	if ActivityTypeOf(tx.Tx) == ActivityTypeUnknown {
+		return NewDAOError(ErrInvalidProposal, "not a DAO transaction", map[string]interface{}{"type": fmt.Sprintf("%T", tx.Tx)})
+	}
+
+	if tx.Hash.IsZero() || tx.Signature != nil {
+		encoded := tx.Encoded
+		if encoded == nil {
+			var err error
+			if encoded, err = codec.Marshal(tx.Tx); err != nil {
+				return NewDAOError(ErrInvalidProposal, "failed to encode transaction", map[string]interface{}{"error": err.Error()})
+			}
+		}
+		hash := types.Hash(sha256.Sum256(encoded))
+		if tx.Hash.IsZero() {
+			tx.Hash = hash
+		} else if tx.Hash != hash {
+			return NewDAOError(ErrInvalidSignature, "transaction hash does not match its content", nil)
+		}
+	}
+
+	if tx.Signature != nil && !tx.Signature.Verify(tx.From, tx.Hash[:]) {
+		return ErrInvalidSignatureError
+	}
+	return nil
+}
+
+// EncodeTx returns the codec encoding of a DAO transaction, tagged with its
+// type so it decodes without knowing the type up front
+func EncodeTx(tx interface{}) ([]byte, error) {
+	return codec.Marshal(&tx)
+}
+
+// txPointer returns a pointer to a decoded transaction value, the form the
+// processor dispatches on
+func txPointer(tx interface{}) interface{} {
+	value := reflect.ValueOf(tx)
+	if value.Kind() == reflect.Ptr {
+		return tx
+	}
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	return pointer.Interface()
+}
+
+// applyLoop applies checked jobs one at a time, each sender's in order
+func (tp *TxPipeline) applyLoop() {
+	defer tp.applier.Done()
+
+	for job := range tp.checked {
+		tp.mu.Lock()
+		queue := tp.senders[job.sender]
+		queue.pending[job.seq] = job
+		tp.mu.Unlock()
+
+		for {
+			tp.mu.Lock()
+			next, ready := queue.pending[queue.applied]
+			if ready {
+				delete(queue.pending, queue.applied)
+			}
+			tp.mu.Unlock()
+			if !ready {
+				break
+			}
+
+			tp.apply(next)
+
+			tp.mu.Lock()
+			queue.applied++
+			if queue.applied == queue.submitted {
+				delete(tp.senders, job.sender)
+			}
+			tp.mu.Unlock()
+		}
+	}
+}
+
+// apply hands a checked job to the apply stage and reports the result
+func (tp *TxPipeline) apply(job *pipelineJob) {
+	if job.err == nil {
+		job.err = tp.stages.Apply(job.tx)
+	}
+	job.done <- job.err
+}
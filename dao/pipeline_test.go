@@ -0,0 +1,174 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"sync"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/codec"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPipeline_ConcurrentVotes(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	voters := make([]crypto.PublicKey, 40)
+	distribution := map[string]uint64{creator.String(): 10000}
+	for i := range voters {
+		voters[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[voters[i].String()] = 1000
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	proposalID := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+		Fee: 100, Title: "Signal", Description: "Roadmap", ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple,
+		StartTime: 0, EndTime: 1 << 40, Threshold: 5100,
+	}, creator, proposalID))
+
+	pipeline := NewTxPipeline(dao, 4)
+	defer pipeline.Stop()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(voters))
+	for i, voter := range voters {
+		wg.Add(1)
+		go func(i int, voter crypto.PublicKey) {
+			defer wg.Done()
+			vote := &VoteTx{Fee: 10, ProposalID: proposalID, Choice: VoteChoiceYes, Weight: 100}
+			errs[i] = <-pipeline.Submit(PipelineTx{Tx: vote, From: voter, Hash: types.Hash{0x02, byte(i)}})
+		}(i, voter)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		assert.NoError(t, err, "vote %d", i)
+	}
+	proposal, err := dao.GetProposal(proposalID)
+	require.NoError(t, err)
+	assert.Len(t, dao.GovernanceState.Votes[proposalID], len(voters))
+	assert.Equal(t, uint64(100*len(voters)), proposal.Results.YesVotes)
+}
+
+func TestTxPipeline_SenderOrder(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	senders := make([]crypto.PublicKey, 8)
+	distribution := make(map[string]uint64)
+	for i := range senders {
+		senders[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[senders[i].String()] = 1000
+	}
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	pipeline := NewTxPipeline(dao, 4)
+	defer pipeline.Stop()
+
+	// Each sender can afford the first two transfers but not the third, so
+	// any reordering within a sender shows in the results
+	var txs []PipelineTx
+	for _, amount := range []uint64{500, 400, 200} {
+		for i, sender := range senders {
+			txs = append(txs, PipelineTx{
+				Tx:   &TokenTransferTx{Recipient: recipient, Amount: amount},
+				From: sender,
+				Hash: types.Hash{byte(amount >> 2), byte(i)},
+			})
+		}
+	}
+	errs := pipeline.SubmitAll(txs)
+
+	for i, err := range errs {
+		if i < 2*len(senders) {
+			assert.NoError(t, err, "transfer %d", i)
+		} else {
+			assert.Error(t, err, "transfer %d", i)
+		}
+	}
+	for _, sender := range senders {
+		assert.Equal(t, uint64(100), dao.GetTokenBalance(sender))
+	}
+	assert.Equal(t, uint64(900*len(senders)), dao.GetTokenBalance(recipient))
+}
+
+func TestTxPipeline_EncodedTransactions(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	key := crypto.GeneratePrivateKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{key.PublicKey().String(): 1000}))
+
+	pipeline := NewTxPipeline(dao, 2)
+
+	encode := func(amount uint64) ([]byte, types.Hash) {
+		encoded, err := EncodeTx(&TokenTransferTx{Fee: 1, Recipient: recipient, Amount: amount})
+		require.NoError(t, err)
+		return encoded, types.Hash(sha256.Sum256(encoded))
+	}
+
+	// Encoded transactions are decoded, hashed and their signature checked
+	encoded, hash := encode(100)
+	signature, err := key.Sign(hash[:])
+	require.NoError(t, err)
+	require.NoError(t, <-pipeline.Submit(PipelineTx{Encoded: encoded, From: key.PublicKey(), Signature: signature}))
+	assert.Equal(t, uint64(100), dao.GetTokenBalance(recipient))
+	records, _ := dao.ActivityIndex.GetActivity(key.PublicKey().String(), nil, 0, 10)
+	require.Len(t, records, 1)
+	assert.Equal(t, hash, records[0].TxHash, "applied under the hash of its encoding")
+
+	encoded, hash = encode(200)
+	forged, err := crypto.GeneratePrivateKey().Sign(hash[:])
+	require.NoError(t, err)
+	err = <-pipeline.Submit(PipelineTx{Encoded: encoded, From: key.PublicKey(), Signature: forged})
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidSignature, err.(*DAOError).Code)
+	err = <-pipeline.Submit(PipelineTx{Encoded: encoded, From: key.PublicKey(), Hash: types.Hash{0x01}, Signature: signature})
+	assert.Error(t, err, "hash of other content")
+
+	assert.Error(t, <-pipeline.Submit(PipelineTx{Encoded: []byte{codec.Version, 0x70}, From: key.PublicKey()}))
+	assert.Error(t, <-pipeline.Submit(PipelineTx{Tx: "transfer", From: key.PublicKey()}))
+	assert.Equal(t, uint64(100), dao.GetTokenBalance(recipient))
+
+	pipeline.Stop()
+	assert.Error(t, <-pipeline.Submit(PipelineTx{Encoded: encoded, From: key.PublicKey()}))
+}
+
+func TestTxPipeline_Stages(t *testing.T) {
+	senders := []crypto.PublicKey{crypto.GeneratePrivateKey().PublicKey(), crypto.GeneratePrivateKey().PublicKey()}
+
+	var (
+		mu      sync.Mutex
+		applied = make(map[string][]byte)
+	)
+	pipeline := NewTxPipelineWithStages(4, PipelineStages{
+		Check: func(tx *PipelineTx) error {
+			if tx.Envelope.(byte)%5 == 0 {
+				return ErrInvalidSignatureError
+			}
+			return nil
+		},
+		Apply: func(tx PipelineTx) error {
+			mu.Lock()
+			defer mu.Unlock()
+			applied[tx.From.String()] = append(applied[tx.From.String()], tx.Envelope.(byte))
+			return nil
+		},
+	})
+	defer pipeline.Stop()
+
+	var txs []PipelineTx
+	for i := byte(1); i <= 20; i++ {
+		txs = append(txs, PipelineTx{From: senders[i%2], Envelope: i})
+	}
+	errs := pipeline.SubmitAll(txs)
+
+	// Failed checks are reported and never applied, the rest apply in
+	// submission order per sender
+	for i, err := range errs {
+		assert.Equal(t, (i+1)%5 == 0, err != nil, "submission %d", i)
+	}
+	assert.Equal(t, []byte{2, 4, 6, 8, 12, 14, 16, 18}, applied[senders[0].String()])
+	assert.Equal(t, []byte{1, 3, 7, 9, 11, 13, 17, 19}, applied[senders[1].String()])
+}
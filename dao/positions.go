@@ -0,0 +1,327 @@
+package dao
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// PositionType represents the kind of locked token position
+type PositionType byte
+
+const (
+	PositionTypeVesting PositionType = 0x01 // Backed by a vesting schedule
+	PositionTypeStake   PositionType = 0x02 // Backed by a staking pool stake
+)
+
+// Position is a transferable record of a vesting schedule or locked stake
+type Position struct {
+	ID         string
+	Type       PositionType
+	Owner      crypto.PublicKey
+	SourceID   string // Vesting schedule ID or staking pool ID
+	Amount     uint64 // Locked amount still held by the position
+	UnlockTime int64  // Time at which the position is fully unlocked
+	CreatedAt  int64
+	Closed     bool // Set once the underlying vesting or stake is gone
+	History    []*PositionTransfer
+	sequence   uint64
+}
+
+// PositionTransfer records a single change of ownership of a position
+type PositionTransfer struct {
+	From      crypto.PublicKey
+	To        crypto.PublicKey
+	Timestamp int64
+	TxHash    types.Hash
+}
+
+// PositionManager tracks vesting and stake positions and their transfers
+type PositionManager struct {
+	governanceState   *GovernanceState
+	tokenState        *GovernanceToken
+	tokenomicsManager *TokenomicsManager
+	parameterManager  *ParameterManager
+	positions         map[string]*Position
+	sources           map[string]string // source key -> position ID
+	nextID            uint64
+}
+
+// NewPositionManager creates a new position manager
+func NewPositionManager(governanceState *GovernanceState, tokenState *GovernanceToken, tokenomicsManager *TokenomicsManager, parameterManager *ParameterManager) *PositionManager {
+	return &PositionManager{
+		governanceState:   governanceState,
+		tokenState:        tokenState,
+		tokenomicsManager: tokenomicsManager,
+		parameterManager:  parameterManager,
+		positions:         make(map[string]*Position),
+		sources:           make(map[string]string),
+	}
+}
+
+// MintVestingPosition creates a position record for a vesting schedule owned by owner
+func (pm *PositionManager) MintVestingPosition(vestingID string, owner crypto.PublicKey) (*Position, error) {
+	schedule, exists := pm.tokenomicsManager.GetVestingSchedule(vestingID)
+	if !exists {
+		return nil, NewDAOError(ErrPositionNotFound, "vesting schedule not found", nil)
+	}
+
+	if schedule.Beneficiary.String() != owner.String() {
+		return nil, NewDAOError(ErrUnauthorized, "not the beneficiary of this vesting schedule", nil)
+	}
+
+	if schedule.Revoked {
+		return nil, NewDAOError(ErrInvalidProposal, "vesting schedule has been revoked", nil)
+	}
+
+	sourceKey := vestingSourceKey(vestingID)
+	if _, exists := pm.sources[sourceKey]; exists {
+		return nil, NewDAOError(ErrInvalidProposal, "position already exists for this vesting schedule", nil)
+	}
+
+	position := pm.newPosition(PositionTypeVesting, owner, vestingID)
+	pm.sources[sourceKey] = position.ID
+	pm.refreshPosition(position)
+
+	return position, nil
+}
+
+// MintStakePosition creates a position record for an owner's stake in a pool
+func (pm *PositionManager) MintStakePosition(poolID string, owner crypto.PublicKey) (*Position, error) {
+	stakerInfo, exists := pm.tokenomicsManager.GetStakerInfo(poolID, owner)
+	if !exists || stakerInfo.StakedAmount == 0 {
+		return nil, NewDAOError(ErrPositionNotFound, "no stake found in pool", nil)
+	}
+
+	// A fully unstaked position is closed and may be replaced by a new one
+	sourceKey := stakeSourceKey(poolID, owner.String())
+	if existingID, exists := pm.sources[sourceKey]; exists {
+		if existing, ok := pm.GetPosition(existingID); ok && !existing.Closed {
+			return nil, NewDAOError(ErrInvalidProposal, "position already exists for this stake", nil)
+		}
+	}
+
+	position := pm.newPosition(PositionTypeStake, owner, poolID)
+	pm.sources[sourceKey] = position.ID
+	pm.refreshPosition(position)
+
+	return position, nil
+}
+
+// newPosition allocates a new position with a sequential ID
+func (pm *PositionManager) newPosition(positionType PositionType, owner crypto.PublicKey, sourceID string) *Position {
+	pm.nextID++
+	position := &Position{
+		ID:        fmt.Sprintf("position_%d", pm.nextID),
+		Type:      positionType,
+		Owner:     owner,
+		SourceID:  sourceID,
+		CreatedAt: time.Now().Unix(),
+		History:   make([]*PositionTransfer, 0),
+		sequence:  pm.nextID,
+	}
+
+	pm.positions[position.ID] = position
+	return position
+}
+
+// IsTransferable reports whether positions of the given type may currently be transferred
+func (pm *PositionManager) IsTransferable(positionType PositionType) bool {
+	config := pm.parameterManager.GetParameterConfig()
+
+	switch positionType {
+	case PositionTypeVesting:
+		return config.VestingPositionsTransferable
+	case PositionTypeStake:
+		return config.StakePositionsTransferable
+	default:
+		return false
+	}
+}
+
+// ProcessPositionTransferTx validates and applies a position transfer transaction
+func (pm *PositionManager) ProcessPositionTransferTx(tx *PositionTransferTx, sender crypto.PublicKey, txHash types.Hash) error {
+	if err := pm.TransferPosition(tx.PositionID, sender, tx.Recipient, txHash); err != nil {
+		return err
+	}
+
+	// Deduct fee
+	pm.tokenState.Balances.Sub(sender.String(), uint64(tx.Fee))
+
+	return nil
+}
+
+// TransferPosition moves a position, together with its underlying vesting
+// schedule or stake, from one owner to another
+func (pm *PositionManager) TransferPosition(positionID string, from, to crypto.PublicKey, txHash types.Hash) error {
+	position, exists := pm.positions[positionID]
+	if !exists {
+		return ErrPositionNotFoundError
+	}
+
+	pm.refreshPosition(position)
+
+	fromStr := from.String()
+	toStr := to.String()
+
+	if position.Owner.String() != fromStr {
+		return NewDAOError(ErrUnauthorized, "not the owner of this position", nil)
+	}
+
+	if len(to) == 0 || toStr == fromStr {
+		return NewDAOError(ErrInvalidProposal, "invalid position recipient", nil)
+	}
+
+	if position.Closed {
+		return NewDAOError(ErrPositionLocked, "position is closed", nil)
+	}
+
+	if !pm.IsTransferable(position.Type) {
+		return ErrPositionNotTransferableError
+	}
+
+	switch position.Type {
+	case PositionTypeVesting:
+		schedule, exists := pm.tokenomicsManager.GetVestingSchedule(position.SourceID)
+		if !exists {
+			return ErrPositionNotFoundError
+		}
+		schedule.Beneficiary = to
+
+	case PositionTypeStake:
+		if err := pm.moveStake(position.SourceID, from, to); err != nil {
+			return err
+		}
+		delete(pm.sources, stakeSourceKey(position.SourceID, fromStr))
+		pm.sources[stakeSourceKey(position.SourceID, toStr)] = position.ID
+	}
+
+	position.Owner = to
+	position.History = append(position.History, &PositionTransfer{
+		From:      from,
+		To:        to,
+		Timestamp: time.Now().Unix(),
+		TxHash:    txHash,
+	})
+
+	return nil
+}
+
+// moveStake reassigns a staker entry, including unclaimed rewards, to a new address
+func (pm *PositionManager) moveStake(poolID string, from, to crypto.PublicKey) error {
+	pool, exists := pm.tokenomicsManager.GetStakingPool(poolID)
+	if !exists {
+		return NewDAOError(ErrPositionNotFound, "staking pool not found", nil)
+	}
+
+	fromStr := from.String()
+	toStr := to.String()
+
+	stakerInfo, exists := pool.Stakers[fromStr]
+	if !exists {
+		return NewDAOError(ErrPositionNotFound, "staker not found in pool", nil)
+	}
+
+	if _, exists := pool.Stakers[toStr]; exists {
+		return NewDAOError(ErrInvalidProposal, "recipient already holds a stake in this pool", nil)
+	}
+
+	delete(pool.Stakers, fromStr)
+	stakerInfo.Address = to
+	pool.Stakers[toStr] = stakerInfo
+
+	// Move the staked amount between token holder records
+	if holder, exists := pm.governanceState.TokenHolders[fromStr]; exists {
+		if holder.Staked >= stakerInfo.StakedAmount {
+			holder.Staked -= stakerInfo.StakedAmount
+		} else {
+			holder.Staked = 0
+		}
+	}
+
+	now := time.Now().Unix()
+	if holder, exists := pm.governanceState.TokenHolders[toStr]; exists {
+		holder.Staked += stakerInfo.StakedAmount
+		holder.LastActive = now
+	} else {
+		pm.governanceState.TokenHolders[toStr] = &TokenHolder{
+			Address:    to,
+			Balance:    0,
+			Staked:     stakerInfo.StakedAmount,
+			Reputation: 0,
+			JoinedAt:   now,
+			LastActive: now,
+		}
+	}
+
+	return nil
+}
+
+// refreshPosition syncs the amount and unlock time with the underlying source
+func (pm *PositionManager) refreshPosition(position *Position) {
+	switch position.Type {
+	case PositionTypeVesting:
+		schedule, exists := pm.tokenomicsManager.GetVestingSchedule(position.SourceID)
+		if !exists || schedule.Revoked || schedule.Released >= schedule.TotalAmount {
+			position.Amount = 0
+			position.Closed = true
+			return
+		}
+		position.Amount = schedule.TotalAmount - schedule.Released
+		position.UnlockTime = schedule.StartTime + schedule.Duration
+
+	case PositionTypeStake:
+		stakerInfo, exists := pm.tokenomicsManager.GetStakerInfo(position.SourceID, position.Owner)
+		if !exists || stakerInfo.StakedAmount == 0 {
+			position.Amount = 0
+			position.Closed = true
+			return
+		}
+		position.Amount = stakerInfo.StakedAmount
+		position.UnlockTime = stakerInfo.UnlockTime
+	}
+}
+
+// GetPosition returns a position by ID
+func (pm *PositionManager) GetPosition(positionID string) (*Position, bool) {
+	position, exists := pm.positions[positionID]
+	if exists {
+		pm.refreshPosition(position)
+	}
+	return position, exists
+}
+
+// GetPositionsByOwner returns all positions currently owned by an address
+func (pm *PositionManager) GetPositionsByOwner(owner crypto.PublicKey) []*Position {
+	ownerStr := owner.String()
+	positions := make([]*Position, 0)
+
+	for _, position := range pm.positions {
+		if position.Owner.String() == ownerStr {
+			pm.refreshPosition(position)
+			positions = append(positions, position)
+		}
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].sequence < positions[j].sequence
+	})
+
+	return positions
+}
+
+// ListAllPositions returns all positions
+func (pm *PositionManager) ListAllPositions() map[string]*Position {
+	return pm.positions
+}
+
+func vestingSourceKey(vestingID string) string {
+	return "vesting:" + vestingID
+}
+
+func stakeSourceKey(poolID, staker string) string {
+	return "stake:" + poolID + ":" + staker
+}
@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStakePosition(t *testing.T) (*DAO, crypto.PublicKey, *Position) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	staker := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		staker.String(): 10000,
+	}))
+
+	require.NoError(t, dao.CreateStakingPool("pool1", "Main Pool", 1, 100, 0))
+	require.NoError(t, dao.StakeTokens("pool1", staker, 5000, 3600))
+
+	position, err := dao.MintStakePosition("pool1", staker)
+	require.NoError(t, err)
+
+	return dao, staker, position
+}
+
+func TestPositionManager_MintStakePosition(t *testing.T) {
+	dao, staker, position := setupStakePosition(t)
+
+	assert.Equal(t, PositionTypeStake, position.Type)
+	assert.Equal(t, uint64(5000), position.Amount)
+	assert.Equal(t, "pool1", position.SourceID)
+	assert.False(t, position.Closed)
+	assert.Greater(t, position.UnlockTime, time.Now().Unix())
+
+	// A stake can only back one open position
+	_, err := dao.MintStakePosition("pool1", staker)
+	assert.Error(t, err)
+
+	// No stake, no position
+	_, err = dao.MintStakePosition("pool1", crypto.GeneratePrivateKey().PublicKey())
+	assert.Error(t, err)
+}
+
+func TestPositionManager_TransferStakePosition(t *testing.T) {
+	dao, staker, position := setupStakePosition(t)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	txHash := randomTreasuryHash()
+	require.NoError(t, dao.TransferPosition(position.ID, staker, recipient, txHash))
+
+	// Stake moved with the position
+	_, exists := dao.TokenomicsManager.GetStakerInfo("pool1", staker)
+	assert.False(t, exists)
+	stakerInfo, exists := dao.TokenomicsManager.GetStakerInfo("pool1", recipient)
+	require.True(t, exists)
+	assert.Equal(t, uint64(5000), stakerInfo.StakedAmount)
+
+	holder, exists := dao.GetTokenHolder(recipient)
+	require.True(t, exists)
+	assert.Equal(t, uint64(5000), holder.Staked)
+
+	// Ownership and history updated
+	updated, exists := dao.GetPosition(position.ID)
+	require.True(t, exists)
+	assert.Equal(t, recipient.String(), updated.Owner.String())
+	require.Len(t, updated.History, 1)
+	assert.Equal(t, staker.String(), updated.History[0].From.String())
+	assert.Equal(t, txHash, updated.History[0].TxHash)
+
+	assert.Empty(t, dao.GetPositionsByOwner(staker))
+	assert.Len(t, dao.GetPositionsByOwner(recipient), 1)
+
+	// Previous owner can no longer transfer it
+	err := dao.TransferPosition(position.ID, staker, recipient, randomTreasuryHash())
+	assert.Error(t, err)
+}
+
+func TestPositionManager_VestingTransferability(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	beneficiary := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	now := time.Now().Unix()
+	vestingID := dao.TokenomicsManager.createVestingSchedule(beneficiary, 100000, VestingTypeLinear, now, 3600, 7200)
+
+	position, err := dao.MintVestingPosition(vestingID, beneficiary)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100000), position.Amount)
+
+	// Vesting positions are locked by default
+	err = dao.TransferPosition(position.ID, beneficiary, recipient, randomTreasuryHash())
+	assert.Equal(t, ErrPositionNotTransferableError, err)
+
+	// Governance can enable transfers
+	require.NoError(t, dao.ParameterManager.applyParameterChange("vesting_positions_transferable", true))
+	require.NoError(t, dao.TransferPosition(position.ID, beneficiary, recipient, randomTreasuryHash()))
+
+	schedule, _ := dao.TokenomicsManager.GetVestingSchedule(vestingID)
+	assert.Equal(t, recipient.String(), schedule.Beneficiary.String())
+}
+
+func TestPositionManager_ProcessPositionTransferTx(t *testing.T) {
+	dao, staker, position := setupStakePosition(t)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	// Transfer to self is rejected by the validator
+	selfTx := &PositionTransferTx{Fee: 10, PositionID: position.ID, Recipient: staker}
+	assert.Error(t, dao.ProcessDAOTransaction(selfTx, staker, randomTreasuryHash()))
+
+	balanceBefore := dao.GetTokenBalance(staker)
+	tx := &PositionTransferTx{Fee: 10, PositionID: position.ID, Recipient: recipient}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, staker, randomTreasuryHash()))
+
+	assert.Equal(t, balanceBefore-10, dao.GetTokenBalance(staker))
+
+	records, _ := dao.GetMemberActivity(recipient, []string{ActivityTypePositionTransfer}, 0, 10)
+	assert.Len(t, records, 1)
+}
@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// Visibility is who may see a part of a member's profile through the API.
+// The zero value follows the default governance sets.
+type Visibility byte
+
+const (
+	VisibilityDefault Visibility = 0x00
+	VisibilityPublic  Visibility = 0x01
+	VisibilityMembers Visibility = 0x02 // Active members only
+	VisibilityPrivate Visibility = 0x03 // The member only
+)
+
+// String returns the name of the visibility
+func (v Visibility) String() string {
+	switch v {
+	case VisibilityDefault:
+		return "default"
+	case VisibilityPublic:
+		return "public"
+	case VisibilityMembers:
+		return "members"
+	case VisibilityPrivate:
+		return "private"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseVisibility returns the visibility with the given name
+func ParseVisibility(name string) (Visibility, bool) {
+	for _, visibility := range []Visibility{VisibilityDefault, VisibilityPublic, VisibilityMembers, VisibilityPrivate} {
+		if visibility.String() == name {
+			return visibility, true
+		}
+	}
+	return 0, false
+}
+
+// PrivacyField is a part of a member's profile with its own visibility
+type PrivacyField string
+
+const (
+	PrivacyFieldBalance    PrivacyField = "balance" // Balance and stake
+	PrivacyFieldReputation PrivacyField = "reputation"
+	PrivacyFieldVotes      PrivacyField = "votes"
+)
+
+// PrivacySettings is the visibility a member chose for each field
+type PrivacySettings struct {
+	Balance    Visibility
+	Reputation Visibility
+	Votes      Visibility
+	UpdatedAt  int64
+}
+
+// PrivacyManager keeps the members' privacy settings. They only shape what
+// the API reveals; the chain state itself stays public.
+type PrivacyManager struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	settings         map[string]*PrivacySettings
+}
+
+// NewPrivacyManager creates a new privacy manager
+func NewPrivacyManager(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager) *PrivacyManager {
+	return &PrivacyManager{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		settings:         make(map[string]*PrivacySettings),
+	}
+}
+
+// ProcessPrivacySettingsTx replaces the privacy settings of a member. The
+// member pays the fee.
+func (pm *PrivacyManager) ProcessPrivacySettingsTx(tx *PrivacySettingsTx, member crypto.PublicKey) error {
+	pm.settings[member.String()] = &PrivacySettings{
+		Balance:    tx.Balance,
+		Reputation: tx.Reputation,
+		Votes:      tx.Votes,
+		UpdatedAt:  time.Now().Unix(),
+	}
+	pm.tokenState.Balances.Sub(member.String(), uint64(tx.Fee))
+	return nil
+}
+
+// Settings returns the privacy settings a member chose, all default when
+// they have not chosen any
+func (pm *PrivacyManager) Settings(member string) PrivacySettings {
+	if settings, exists := pm.settings[member]; exists {
+		return *settings
+	}
+	return PrivacySettings{}
+}
+
+// Visibility returns who may see a field of a member, resolving the default
+// to the one governance sets
+func (pm *PrivacyManager) Visibility(member string, field PrivacyField) Visibility {
+	settings := pm.Settings(member)
+	config := pm.parameterManager.GetParameterConfig()
+
+	var chosen, fallback Visibility
+	switch field {
+	case PrivacyFieldBalance:
+		chosen, fallback = settings.Balance, Visibility(config.PrivacyDefaultBalance)
+	case PrivacyFieldReputation:
+		chosen, fallback = settings.Reputation, Visibility(config.PrivacyDefaultReputation)
+	case PrivacyFieldVotes:
+		chosen, fallback = settings.Votes, Visibility(config.PrivacyDefaultVotes)
+	default:
+		return VisibilityPrivate
+	}
+
+	if chosen != VisibilityDefault {
+		return chosen
+	}
+	if fallback == VisibilityDefault {
+		return VisibilityPublic
+	}
+	return fallback
+}
+
+// CanView reports whether viewer may see a field of a member. A nil viewer
+// is an anonymous request. Members always see their own fields.
+func (pm *PrivacyManager) CanView(member string, field PrivacyField, viewer crypto.PublicKey) bool {
+	if viewer != nil && viewer.String() == member {
+		return true
+	}
+
+	switch pm.Visibility(member, field) {
+	case VisibilityPublic:
+		return true
+	case VisibilityMembers:
+		if viewer == nil {
+			return false
+		}
+		holder, exists := pm.governanceState.TokenHolders[viewer.String()]
+		return exists && holder.Status == MembershipStatusActive
+	default:
+		return false
+	}
+}
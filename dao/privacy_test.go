@@ -0,0 +1,55 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacySettings(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String(): 1000,
+		bob.String():   1000,
+	}))
+
+	// Everything is public until someone says otherwise
+	assert.Equal(t, VisibilityPublic, dao.Privacy.Visibility(alice.String(), PrivacyFieldBalance))
+	assert.True(t, dao.Privacy.CanView(alice.String(), PrivacyFieldVotes, nil))
+
+	require.NoError(t, dao.ProcessDAOTransaction(&PrivacySettingsTx{
+		Fee:        10,
+		Balance:    VisibilityPrivate,
+		Reputation: VisibilityMembers,
+	}, alice, types.Hash{0x01}))
+	assert.Equal(t, uint64(990), dao.GetTokenBalance(alice))
+	assert.Error(t, dao.ProcessDAOTransaction(&PrivacySettingsTx{Fee: 10, Votes: Visibility(9)}, alice, types.Hash{0x02}))
+
+	// Private fields are only visible to the member
+	assert.False(t, dao.Privacy.CanView(alice.String(), PrivacyFieldBalance, bob))
+	assert.True(t, dao.Privacy.CanView(alice.String(), PrivacyFieldBalance, alice))
+
+	// Members-only fields need an active member
+	assert.True(t, dao.Privacy.CanView(alice.String(), PrivacyFieldReputation, bob))
+	assert.False(t, dao.Privacy.CanView(alice.String(), PrivacyFieldReputation, outsider))
+	assert.False(t, dao.Privacy.CanView(alice.String(), PrivacyFieldReputation, nil))
+	dao.GovernanceState.TokenHolders[bob.String()].Status = MembershipStatusSuspended
+	assert.False(t, dao.Privacy.CanView(alice.String(), PrivacyFieldReputation, bob))
+
+	// Fields left at the default follow governance
+	assert.True(t, dao.Privacy.CanView(alice.String(), PrivacyFieldVotes, nil))
+	dao.ParameterManager.GetParameterConfig().PrivacyDefaultVotes = uint64(VisibilityPrivate)
+	assert.Equal(t, VisibilityPrivate, dao.Privacy.Visibility(alice.String(), PrivacyFieldVotes))
+	assert.False(t, dao.Privacy.CanView(alice.String(), PrivacyFieldVotes, nil))
+	assert.Equal(t, VisibilityDefault, dao.Privacy.Settings(alice.String()).Votes)
+
+	assert.Error(t, dao.ParameterManager.ValidateParameterChanges(map[string]interface{}{"privacy_default_votes": uint64(0)}))
+	assert.NoError(t, dao.ParameterManager.ValidateParameterChanges(map[string]interface{}{"privacy_default_votes": uint64(2)}))
+}
@@ -1,8 +1,6 @@
 package dao
 
 import (
-	"time"
-
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/types"
 )
@@ -12,6 +10,11 @@ type DAOProcessor struct {
 	governanceState *GovernanceState
 	tokenState      *GovernanceToken
 	validator       *DAOValidator
+	index           *StateIndex
+	treasury        *TreasuryManager
+	analytics       *AnalyticsSystem
+	parameters      *ParameterManager
+	endorsements    *EndorsementManager
 }
 
 // NewDAOProcessor creates a new DAO transaction processor
@@ -46,16 +49,24 @@ func (p *DAOProcessor) ProcessProposalTx(tx *ProposalTx, creator crypto.PublicKe
 		Results:      &VoteResults{},
 		MetadataHash: tx.MetadataHash,
 	}
+	proposal.AdaptiveQuorum = p.adaptiveQuorum()
+	p.applyTrack(proposal, tx.Track)
+
+	// Queue the proposal behind the open ones of its track if it is full
+	if proposal.Track != "" {
+		p.parameters.Enqueue(proposal)
+	}
 
 	// Store the proposal
 	p.governanceState.Proposals[txHash] = proposal
+	p.index.UpdateProposal(txHash, proposal)
 
 	// Initialize vote tracking for this proposal
-	p.governanceState.Votes[txHash] = make(map[string]*Vote)
+	p.governanceState.ResetVotes(txHash)
 
 	// Deduct fee from creator's balance
 	creatorStr := creator.String()
-	p.tokenState.Balances[creatorStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(creatorStr, uint64(tx.Fee)+proposal.Deposit)
 
 	// Update reputation for proposal creation
 	p.updateReputationForProposalCreation(creator)
@@ -75,6 +86,9 @@ func (p *DAOProcessor) ProcessVoteTx(tx *VoteTx, voter crypto.PublicKey) error {
 	if proposal == nil {
 		return ErrProposalNotFoundError
 	}
+	if proposal.Status == ProposalStatusPending {
+		proposal.Status = ProposalStatusActive
+	}
 
 	// Calculate effective voting power and cost based on voting type
 	effectiveWeight, cost, err := p.calculateVotingWeightAndCost(tx, voter, proposal)
@@ -87,7 +101,7 @@ func (p *DAOProcessor) ProcessVoteTx(tx *VoteTx, voter crypto.PublicKey) error {
 		Voter:     voter,
 		Choice:    tx.Choice,
 		Weight:    effectiveWeight,
-		Timestamp: time.Now().Unix(),
+		Timestamp: p.governanceState.Now(),
 		Reason:    tx.Reason,
 	}
 
@@ -97,6 +111,7 @@ func (p *DAOProcessor) ProcessVoteTx(tx *VoteTx, voter crypto.PublicKey) error {
 		p.governanceState.Votes[tx.ProposalID] = make(map[string]*Vote)
 	}
 	p.governanceState.Votes[tx.ProposalID][voterStr] = vote
+	p.governanceState.RecordVoter(tx.ProposalID, voterStr)
 
 	// Update vote results with effective weight
 	if proposal.Results == nil {
@@ -114,10 +129,10 @@ func (p *DAOProcessor) ProcessVoteTx(tx *VoteTx, voter crypto.PublicKey) error {
 	proposal.Results.TotalVoters++
 
 	// Deduct voting cost from voter's balance
-	p.tokenState.Balances[voterStr] -= cost
+	p.tokenState.Balances.Sub(voterStr, cost)
 
 	// Deduct transaction fee
-	p.tokenState.Balances[voterStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(voterStr, uint64(tx.Fee))
 
 	// Update reputation for voting participation
 	p.updateReputationForVoting(voter, tx.ProposalID)
@@ -128,7 +143,7 @@ func (p *DAOProcessor) ProcessVoteTx(tx *VoteTx, voter crypto.PublicKey) error {
 // calculateVotingWeightAndCost calculates the effective voting weight and token cost based on voting type
 func (p *DAOProcessor) calculateVotingWeightAndCost(tx *VoteTx, voter crypto.PublicKey, proposal *Proposal) (uint64, uint64, error) {
 	voterStr := voter.String()
-	voterBalance := p.tokenState.Balances[voterStr]
+	voterBalance := p.tokenState.Balances.Get(voterStr)
 
 	switch proposal.VotingType {
 	case VotingTypeSimple:
@@ -187,7 +202,7 @@ func (p *DAOProcessor) ProcessDelegationTx(tx *DelegationTx, delegator crypto.Pu
 		// Revoke existing delegation
 		if existingDelegation, exists := p.governanceState.Delegations[delegatorStr]; exists {
 			existingDelegation.Active = false
-			existingDelegation.EndTime = time.Now().Unix()
+			existingDelegation.EndTime = p.governanceState.Now()
 		}
 		// Note: We still store the revoked delegation for historical purposes
 	} else {
@@ -195,9 +210,11 @@ func (p *DAOProcessor) ProcessDelegationTx(tx *DelegationTx, delegator crypto.Pu
 		delegation := &Delegation{
 			Delegator: delegator,
 			Delegate:  tx.Delegate,
-			StartTime: time.Now().Unix(),
-			EndTime:   time.Now().Unix() + tx.Duration,
+			StartTime: p.governanceState.Now(),
+			EndTime:   p.governanceState.Now() + tx.Duration,
 			Active:    true,
+			Duration:  tx.Duration,
+			AutoRenew: tx.AutoRenew,
 		}
 
 		// Store the delegation
@@ -205,15 +222,19 @@ func (p *DAOProcessor) ProcessDelegationTx(tx *DelegationTx, delegator crypto.Pu
 	}
 
 	// Deduct fee
-	p.tokenState.Balances[delegatorStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(delegatorStr, uint64(tx.Fee))
 
 	return nil
 }
 
 // ProcessTreasuryTx processes a treasury transaction
 func (p *DAOProcessor) ProcessTreasuryTx(tx *TreasuryTx, txHash types.Hash) error {
-	// Create treasury manager
-	treasuryManager := NewTreasuryManager(p.governanceState, p.tokenState)
+	// Use the DAO's treasury manager when wired, which can convert stable
+	// denominated amounts
+	treasuryManager := p.treasury
+	if treasuryManager == nil {
+		treasuryManager = NewTreasuryManager(p.governanceState, p.tokenState)
+	}
 
 	// Create the treasury transaction
 	if err := treasuryManager.CreateTreasuryTransaction(tx, txHash); err != nil {
@@ -228,7 +249,13 @@ func (p *DAOProcessor) ProcessTreasuryTx(tx *TreasuryTx, txHash types.Hash) erro
 
 		// Try to execute if we have enough signatures
 		if len(tx.Signatures) >= int(tx.RequiredSigs) {
-			return treasuryManager.ExecuteTreasuryTransaction(txHash)
+			if err := treasuryManager.ExecuteTreasuryTransaction(txHash); err != nil && !IsConversionRequeue(err) {
+				return err
+			}
+		}
+	} else if tx.Aggregate != nil {
+		if err := treasuryManager.AttachAggregateSignature(txHash, tx.Aggregate); err != nil {
+			return err
 		}
 	}
 
@@ -250,7 +277,7 @@ func (p *DAOProcessor) ProcessTokenMintTx(tx *TokenMintTx, minter crypto.PublicK
 
 	// Deduct fee from minter
 	minterStr := minter.String()
-	p.tokenState.Balances[minterStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(minterStr, uint64(tx.Fee))
 
 	// Update token holder record
 	p.updateTokenHolderRecord(recipientStr)
@@ -272,7 +299,7 @@ func (p *DAOProcessor) ProcessTokenBurnTx(tx *TokenBurnTx, burner crypto.PublicK
 	}
 
 	// Deduct fee
-	p.tokenState.Balances[burnerStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(burnerStr, uint64(tx.Fee))
 
 	return nil
 }
@@ -293,7 +320,7 @@ func (p *DAOProcessor) ProcessTokenTransferTx(tx *TokenTransferTx, sender crypto
 	}
 
 	// Deduct fee
-	p.tokenState.Balances[senderStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(senderStr, uint64(tx.Fee))
 
 	// Update token holder records
 	p.updateTokenHolderRecord(senderStr)
@@ -318,7 +345,7 @@ func (p *DAOProcessor) ProcessTokenApproveTx(tx *TokenApproveTx, owner crypto.Pu
 	}
 
 	// Deduct fee
-	p.tokenState.Balances[ownerStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(ownerStr, uint64(tx.Fee))
 
 	return nil
 }
@@ -340,7 +367,7 @@ func (p *DAOProcessor) ProcessTokenTransferFromTx(tx *TokenTransferFromTx, spend
 	}
 
 	// Deduct fee from spender
-	p.tokenState.Balances[spenderStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(spenderStr, uint64(tx.Fee))
 
 	// Update token holder records
 	p.updateTokenHolderRecord(fromStr)
@@ -383,16 +410,18 @@ func (p *DAOProcessor) ProcessParameterProposalTx(tx *ParameterProposalTx, creat
 		Results:      &VoteResults{},
 		MetadataHash: types.Hash{}, // Could store parameter changes in IPFS
 	}
+	proposal.AdaptiveQuorum = p.adaptiveQuorum()
 
 	// Store the proposal
 	p.governanceState.Proposals[txHash] = proposal
+	p.index.UpdateProposal(txHash, proposal)
 
 	// Initialize vote tracking for this proposal
-	p.governanceState.Votes[txHash] = make(map[string]*Vote)
+	p.governanceState.ResetVotes(txHash)
 
 	// Deduct fee from creator's balance
 	creatorStr := creator.String()
-	p.tokenState.Balances[creatorStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(creatorStr, uint64(tx.Fee))
 
 	// Update reputation for proposal creation
 	p.updateReputationForProposalCreation(creator)
@@ -406,7 +435,7 @@ func (p *DAOProcessor) updateTokenHolderRecord(address string) {
 
 	if holder, exists := p.governanceState.TokenHolders[address]; exists {
 		holder.Balance = balance
-		holder.LastActive = time.Now().Unix()
+		holder.LastActive = p.governanceState.Now()
 	} else if balance > 0 {
 		// Create new token holder record
 		pubKey := crypto.PublicKey(address) // Convert string back to PublicKey
@@ -415,10 +444,58 @@ func (p *DAOProcessor) updateTokenHolderRecord(address string) {
 			Balance:    balance,
 			Staked:     0,
 			Reputation: balance / 10, // Initial reputation based on balance
-			JoinedAt:   time.Now().Unix(),
-			LastActive: time.Now().Unix(),
+			JoinedAt:   p.governanceState.Now(),
+			LastActive: p.governanceState.Now(),
 		}
 	}
+
+	if holder, exists := p.governanceState.TokenHolders[address]; exists {
+		p.index.UpdateHolder(address, holder)
+	}
+}
+
+// adaptiveQuorum returns the quorum recent turnout sets for a new proposal,
+// or 0 when adaptive quorum is off
+func (p *DAOProcessor) adaptiveQuorum() uint64 {
+	if p.analytics == nil || p.parameters == nil {
+		return 0
+	}
+	config := p.parameters.GetParameterConfig()
+	return p.analytics.GetAdaptiveQuorum(config.AdaptiveQuorumWindow, config.AdaptiveQuorumMin, config.AdaptiveQuorumMax).Quorum
+}
+
+// applyTrack sets the voting rules of a proposal's track on it, the track's
+// threshold only raising the proposal's own
+func (p *DAOProcessor) applyTrack(proposal *Proposal, id string) {
+	if id == "" || p.parameters == nil {
+		return
+	}
+	track, exists := p.parameters.GetTrack(id)
+	if !exists {
+		return
+	}
+
+	proposal.Track = track.ID
+	proposal.Quorum = track.Quorum
+	proposal.Timelock = track.Timelock
+	proposal.Deposit = track.Deposit
+	if track.Threshold > proposal.Threshold {
+		proposal.Threshold = track.Threshold
+	}
+}
+
+// settleDeposit refunds the track deposit of a proposal whose vote met
+// quorum and moves it to the treasury otherwise
+func (p *DAOProcessor) settleDeposit(proposal *Proposal, quorumMet bool, now int64) {
+	if proposal.Deposit == 0 {
+		return
+	}
+	if quorumMet {
+		p.tokenState.Balances.Add(proposal.Creator.String(), proposal.Deposit)
+	} else {
+		p.governanceState.Treasury.recordInflow(proposal.Deposit, InflowSourceTrackDeposit, now)
+	}
+	proposal.Deposit = 0
 }
 
 // UpdateProposalStatus updates proposal status based on current time and voting results
@@ -428,7 +505,18 @@ func (p *DAOProcessor) UpdateProposalStatus(proposalID types.Hash) error {
 		return ErrProposalNotFoundError
 	}
 
-	now := time.Now().Unix()
+	// Proposals awaiting endorsements or a slot on their track neither
+	// open nor close
+	if p.endorsements != nil && p.endorsements.Awaiting(proposalID) {
+		return nil
+	}
+	if p.parameters != nil {
+		if _, queued := p.parameters.QueuePosition(proposalID); queued {
+			return nil
+		}
+	}
+
+	now := p.governanceState.Now()
 
 	// Check if voting period has started
 	if now >= proposal.StartTime && proposal.Status == ProposalStatusPending {
@@ -441,14 +529,16 @@ func (p *DAOProcessor) UpdateProposalStatus(proposalID types.Hash) error {
 		totalVotes := proposal.Results.YesVotes + proposal.Results.NoVotes + proposal.Results.AbstainVotes
 
 		// Check quorum
-		if totalVotes >= p.governanceState.Config.QuorumThreshold {
+		quorumMet := totalVotes >= proposal.RequiredQuorum(p.governanceState.Config)
+		p.settleDeposit(proposal, quorumMet, now)
+		if quorumMet {
 			proposal.Results.Quorum = totalVotes
 
 			// Check if passed (excluding abstain votes from calculation)
 			activeVotes := proposal.Results.YesVotes + proposal.Results.NoVotes
 			if activeVotes > 0 {
 				passPercentage := (proposal.Results.YesVotes * 10000) / activeVotes
-				if passPercentage >= p.governanceState.Config.PassingThreshold {
+				if passPercentage >= proposal.RequiredThreshold(p.governanceState.Config) {
 					proposal.Status = ProposalStatusPassed
 					proposal.Results.Passed = true
 				} else {
@@ -468,15 +558,22 @@ func (p *DAOProcessor) UpdateProposalStatus(proposalID types.Hash) error {
 
 		// Update reputation based on proposal outcome
 		p.updateReputationForProposalOutcome(proposalID)
+
+		// The closed vote frees a slot for the next queued proposal
+		if proposal.Track != "" && p.parameters != nil {
+			p.parameters.AdvanceTrackQueue(proposal.Track, now)
+		}
 	}
 
+	p.index.UpdateProposal(proposalID, proposal)
+
 	return nil
 }
 
 // GetEffectiveVotingPower calculates the effective voting power for a user, including delegations
 func (p *DAOProcessor) GetEffectiveVotingPower(user crypto.PublicKey) uint64 {
 	userStr := user.String()
-	now := time.Now().Unix()
+	now := p.governanceState.Now()
 
 	// Check if user has delegated their voting power
 	if delegation, exists := p.governanceState.Delegations[userStr]; exists && delegation.Active {
@@ -487,13 +584,13 @@ func (p *DAOProcessor) GetEffectiveVotingPower(user crypto.PublicKey) uint64 {
 	}
 
 	// Start with user's own balance
-	power := p.tokenState.Balances[userStr]
+	power := p.tokenState.Balances.Get(userStr)
 
 	// Add delegated power from others
 	for delegatorStr, delegation := range p.governanceState.Delegations {
 		if delegation.Active && delegation.Delegate.String() == userStr {
 			if now >= delegation.StartTime && now <= delegation.EndTime {
-				power += p.tokenState.Balances[delegatorStr]
+				power += p.tokenState.Balances.Get(delegatorStr)
 			}
 		}
 	}
@@ -501,16 +598,46 @@ func (p *DAOProcessor) GetEffectiveVotingPower(user crypto.PublicKey) uint64 {
 	return power
 }
 
+// GetEffectiveVotingPowers calculates the effective voting power of many
+// users, going through the delegations once for all of them
+func (p *DAOProcessor) GetEffectiveVotingPowers(users []crypto.PublicKey) map[string]uint64 {
+	now := p.governanceState.Now()
+	powers := make(map[string]uint64, len(users))
+	for _, user := range users {
+		powers[user.String()] = p.tokenState.Balances.Get(user.String())
+	}
+
+	var delegators []string
+	for delegatorStr, delegation := range p.governanceState.Delegations {
+		if !delegation.Active || now < delegation.StartTime || now > delegation.EndTime {
+			continue
+		}
+		if _, requested := powers[delegatorStr]; requested {
+			delegators = append(delegators, delegatorStr)
+		}
+		if _, requested := powers[delegation.Delegate.String()]; requested {
+			powers[delegation.Delegate.String()] += p.tokenState.Balances.Get(delegatorStr)
+		}
+	}
+
+	// Users who delegated have no direct voting power
+	for _, delegatorStr := range delegators {
+		powers[delegatorStr] = 0
+	}
+
+	return powers
+}
+
 // GetDelegatedPower returns the total voting power delegated to a user
 func (p *DAOProcessor) GetDelegatedPower(delegate crypto.PublicKey) uint64 {
 	delegateStr := delegate.String()
-	now := time.Now().Unix()
+	now := p.governanceState.Now()
 	delegatedPower := uint64(0)
 
 	for delegatorStr, delegation := range p.governanceState.Delegations {
 		if delegation.Active && delegation.Delegate.String() == delegateStr {
 			if now >= delegation.StartTime && now <= delegation.EndTime {
-				delegatedPower += p.tokenState.Balances[delegatorStr]
+				delegatedPower += p.tokenState.Balances.Get(delegatorStr)
 			}
 		}
 	}
@@ -521,7 +648,7 @@ func (p *DAOProcessor) GetDelegatedPower(delegate crypto.PublicKey) uint64 {
 // GetOwnVotingPower returns the user's own voting power (excluding delegations)
 func (p *DAOProcessor) GetOwnVotingPower(user crypto.PublicKey) uint64 {
 	userStr := user.String()
-	now := time.Now().Unix()
+	now := p.governanceState.Now()
 
 	// Check if user has delegated their voting power
 	if delegation, exists := p.governanceState.Delegations[userStr]; exists && delegation.Active {
@@ -531,7 +658,7 @@ func (p *DAOProcessor) GetOwnVotingPower(user crypto.PublicKey) uint64 {
 		}
 	}
 
-	return p.tokenState.Balances[userStr]
+	return p.tokenState.Balances.Get(userStr)
 }
 
 // RevokeDelegation revokes an active delegation
@@ -544,7 +671,7 @@ func (p *DAOProcessor) RevokeDelegation(delegator crypto.PublicKey) error {
 	}
 
 	delegation.Active = false
-	delegation.EndTime = time.Now().Unix()
+	delegation.EndTime = p.governanceState.Now()
 
 	return nil
 }
@@ -567,7 +694,7 @@ func (p *DAOProcessor) updateReputationForProposalCreation(creator crypto.Public
 		}
 
 		holder.Reputation = newReputation
-		holder.LastActive = time.Now().Unix()
+		holder.LastActive = p.governanceState.Now()
 	}
 }
 
@@ -587,7 +714,7 @@ func (p *DAOProcessor) updateReputationForVoting(voter crypto.PublicKey, proposa
 		}
 
 		holder.Reputation = newReputation
-		holder.LastActive = time.Now().Unix()
+		holder.LastActive = p.governanceState.Now()
 	}
 }
 
@@ -639,7 +766,7 @@ func (p *DAOProcessor) calculateReputationBasedVotingCost(voter crypto.PublicKey
 
 // updateReputationForProposalOutcome updates reputation based on proposal outcomes
 func (p *DAOProcessor) updateReputationForProposalOutcome(proposalID types.Hash) {
-	proposal, exists := p.governanceState.Proposals[proposalID]
+	proposal, exists := p.governanceState.Proposal(proposalID)
 	if !exists {
 		return
 	}
@@ -673,6 +800,8 @@ func (p *DAOProcessor) updateReputationForProposalOutcome(proposalID types.Hash)
 			holder.Reputation = newReputation
 		}
 	}
+
+	p.index.UpdateHolder(creatorStr, holder)
 }
 
 // ProcessTokenDistributionTx processes a token distribution transaction
@@ -698,7 +827,7 @@ func (p *DAOProcessor) ProcessTokenDistributionTx(tx *TokenDistributionTx, distr
 
 	// Deduct fee from distributor
 	distributorStr := distributor.String()
-	p.tokenState.Balances[distributorStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(distributorStr, uint64(tx.Fee))
 
 	return nil
 }
@@ -721,7 +850,7 @@ func (p *DAOProcessor) ProcessVestingClaimTx(tx *VestingClaimTx, claimer crypto.
 
 	// Deduct fee from claimer
 	claimerStr := claimer.String()
-	p.tokenState.Balances[claimerStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(claimerStr, uint64(tx.Fee))
 
 	// Update token holder record
 	p.updateTokenHolderRecord(claimerStr)
@@ -749,7 +878,7 @@ func (p *DAOProcessor) ProcessStakeTx(tx *StakeTx, staker crypto.PublicKey) erro
 
 	// Deduct fee from staker
 	stakerStr := staker.String()
-	p.tokenState.Balances[stakerStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(stakerStr, uint64(tx.Fee))
 
 	return nil
 }
@@ -771,7 +900,7 @@ func (p *DAOProcessor) ProcessUnstakeTx(tx *UnstakeTx, unstaker crypto.PublicKey
 
 	// Deduct fee from unstaker
 	unstakerStr := unstaker.String()
-	p.tokenState.Balances[unstakerStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(unstakerStr, uint64(tx.Fee))
 
 	return nil
 }
@@ -794,7 +923,7 @@ func (p *DAOProcessor) ProcessClaimRewardsTx(tx *ClaimRewardsTx, claimer crypto.
 
 	// Deduct fee from claimer
 	claimerStr := claimer.String()
-	p.tokenState.Balances[claimerStr] -= uint64(tx.Fee)
+	p.tokenState.Balances.Sub(claimerStr, uint64(tx.Fee))
 
 	// Update token holder record
 	p.updateTokenHolderRecord(claimerStr)
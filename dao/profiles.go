@@ -0,0 +1,164 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Member profile limits
+const (
+	MaxProfileDisplayNameLength = 64
+	MaxProfileBioLength         = 1000
+	MaxProfileLinks             = 5
+	MaxProfileLinkLabelLength   = 32
+	MaxProfileAvatarCIDLength   = 128
+)
+
+// ProfileLink is a labelled link on a member profile
+type ProfileLink struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// MemberProfile is the profile document a member stores on IPFS. It is
+// signed by the member, so a document served from any node or gateway can
+// be checked against the address it claims.
+type MemberProfile struct {
+	Address     string        `json:"address"`
+	DisplayName string        `json:"display_name"`
+	AvatarCID   string        `json:"avatar_cid,omitempty"`
+	Bio         string        `json:"bio,omitempty"`
+	Links       []ProfileLink `json:"links,omitempty"`
+	UpdatedAt   int64         `json:"updated_at"`
+	Signature   string        `json:"signature"` // Hex encoded 32 byte r and s over Digest
+}
+
+// Validate checks the profile fields against the profile limits
+func (p *MemberProfile) Validate() error {
+	invalid := func(field, message string) error {
+		return NewDAOError(ErrInvalidProfile, message, map[string]interface{}{
+			"field": field,
+		})
+	}
+
+	if utf8.RuneCountInString(p.DisplayName) > MaxProfileDisplayNameLength {
+		return invalid("display_name", "display name is too long")
+	}
+	if utf8.RuneCountInString(p.Bio) > MaxProfileBioLength {
+		return invalid("bio", "bio is too long")
+	}
+	if len(p.AvatarCID) > MaxProfileAvatarCIDLength {
+		return invalid("avatar_cid", "avatar CID is too long")
+	}
+	for _, r := range p.AvatarCID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return invalid("avatar_cid", "avatar CID must be alphanumeric")
+		}
+	}
+	if len(p.Links) > MaxProfileLinks {
+		return invalid("links", "too many links")
+	}
+	for _, link := range p.Links {
+		if link.Label == "" || utf8.RuneCountInString(link.Label) > MaxProfileLinkLabelLength {
+			return invalid("links", "link labels must be 1 to 32 characters")
+		}
+		parsed, err := url.Parse(link.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return invalid("links", "links must be http or https URLs")
+		}
+	}
+	return nil
+}
+
+// Digest returns the hash the member signs: sha256 of the JSON document
+// without its signature
+func (p *MemberProfile) Digest() []byte {
+	unsigned := *p
+	unsigned.Signature = ""
+	data, _ := json.Marshal(&unsigned)
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+// Sign sets the profile's address to the key's and signs it
+func (p *MemberProfile) Sign(key crypto.PrivateKey) error {
+	p.Address = key.PublicKey().String()
+	signature, err := key.Sign(p.Digest())
+	if err != nil {
+		return err
+	}
+
+	sigBytes := make([]byte, 64)
+	signature.R.FillBytes(sigBytes[:32])
+	signature.S.FillBytes(sigBytes[32:])
+	p.Signature = hex.EncodeToString(sigBytes)
+	return nil
+}
+
+// Verify reports whether the profile is signed by the key of its address
+func (p *MemberProfile) Verify() bool {
+	address, err := crypto.PublicKeyFromHex(p.Address)
+	if err != nil {
+		return false
+	}
+	sigBytes, err := hex.DecodeString(p.Signature)
+	if err != nil || len(sigBytes) != 64 {
+		return false
+	}
+
+	signature := crypto.Signature{
+		R: new(big.Int).SetBytes(sigBytes[:32]),
+		S: new(big.Int).SetBytes(sigBytes[32:]),
+	}
+	return signature.Verify(address, p.Digest())
+}
+
+// ProfileRecord is the profile document a member last published
+type ProfileRecord struct {
+	Hash      types.Hash // IPFS hash of the signed MemberProfile
+	UpdatedAt int64
+}
+
+// ProfileRegistry records the IPFS hash of each member's profile. The
+// documents themselves live on IPFS.
+type ProfileRegistry struct {
+	tokenState *GovernanceToken
+	records    map[string]*ProfileRecord
+}
+
+// NewProfileRegistry creates an empty profile registry
+func NewProfileRegistry(tokenState *GovernanceToken) *ProfileRegistry {
+	return &ProfileRegistry{
+		tokenState: tokenState,
+		records:    make(map[string]*ProfileRecord),
+	}
+}
+
+// ProcessProfileUpdateTx records the sender's profile hash, or removes the
+// profile when the hash is zero. The sender pays the fee.
+func (pr *ProfileRegistry) ProcessProfileUpdateTx(tx *ProfileUpdateTx, member crypto.PublicKey) error {
+	if tx.ProfileHash.IsZero() {
+		delete(pr.records, member.String())
+	} else {
+		pr.records[member.String()] = &ProfileRecord{
+			Hash:      tx.ProfileHash,
+			UpdatedAt: time.Now().Unix(),
+		}
+	}
+	pr.tokenState.Balances.Sub(member.String(), uint64(tx.Fee))
+	return nil
+}
+
+// Get returns the profile record of a member
+func (pr *ProfileRegistry) Get(member string) (*ProfileRecord, bool) {
+	record, exists := pr.records[member]
+	return record, exists
+}
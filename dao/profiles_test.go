@@ -0,0 +1,103 @@
+package dao
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemberProfile_Validate(t *testing.T) {
+	valid := MemberProfile{
+		DisplayName: "Alice",
+		AvatarCID:   "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+		Bio:         "Core contributor",
+		Links:       []ProfileLink{{Label: "Site", URL: "https://alice.example"}},
+	}
+	require.NoError(t, valid.Validate())
+
+	for name, mutate := range map[string]func(p *MemberProfile){
+		"display name": func(p *MemberProfile) { p.DisplayName = strings.Repeat("a", MaxProfileDisplayNameLength+1) },
+		"bio":          func(p *MemberProfile) { p.Bio = strings.Repeat("a", MaxProfileBioLength+1) },
+		"avatar":       func(p *MemberProfile) { p.AvatarCID = "ipfs://Qm" },
+		"link scheme":  func(p *MemberProfile) { p.Links = []ProfileLink{{Label: "Site", URL: "javascript:alert(1)"}} },
+		"link label":   func(p *MemberProfile) { p.Links = []ProfileLink{{URL: "https://alice.example"}} },
+		"links":        func(p *MemberProfile) { p.Links = make([]ProfileLink, MaxProfileLinks+1) },
+	} {
+		profile := valid
+		mutate(&profile)
+		err := profile.Validate()
+		require.Error(t, err, name)
+		assert.Equal(t, ErrInvalidProfile, err.(*DAOError).Code, name)
+	}
+}
+
+func TestMemberProfile_Signature(t *testing.T) {
+	key := crypto.GeneratePrivateKey()
+	profile := &MemberProfile{DisplayName: "Alice", UpdatedAt: 1641081600}
+	require.NoError(t, profile.Sign(key))
+	assert.Equal(t, key.PublicKey().String(), profile.Address)
+	assert.True(t, profile.Verify())
+
+	tampered := *profile
+	tampered.DisplayName = "Mallory"
+	assert.False(t, tampered.Verify())
+
+	claimed := *profile
+	claimed.Address = crypto.GeneratePrivateKey().PublicKey().String()
+	assert.False(t, claimed.Verify())
+
+	claimed.Address = "zz"
+	assert.False(t, claimed.Verify())
+}
+
+func TestDAO_MemberProfiles(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	_, objectServer := newTestObjectStore(t)
+	dao.IPFSClient = NewIPFSClientWithStore(NewS3ContentStore(MirrorConfig{Endpoint: objectServer.URL, Bucket: "dao-content"}))
+
+	aliceKey := crypto.GeneratePrivateKey()
+	alice := aliceKey.PublicKey()
+	bobKey := crypto.GeneratePrivateKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		alice.String():              1000,
+		bobKey.PublicKey().String(): 1000,
+	}))
+
+	_, _, err := dao.GetMemberProfile(alice.String())
+	assert.Error(t, err)
+
+	// Invalid profiles are not uploaded
+	_, err = dao.PublishMemberProfile(&MemberProfile{AvatarCID: "not a cid"}, aliceKey)
+	assert.Error(t, err)
+
+	hash, err := dao.PublishMemberProfile(&MemberProfile{DisplayName: "Alice", Bio: "Core contributor"}, aliceKey)
+	require.NoError(t, err)
+	require.NoError(t, dao.ProcessDAOTransaction(&ProfileUpdateTx{Fee: 10, ProfileHash: hash}, alice, types.Hash{0x01}))
+	assert.Equal(t, uint64(990), dao.GetTokenBalance(alice))
+
+	profile, record, err := dao.GetMemberProfile(alice.String())
+	require.NoError(t, err)
+	assert.Equal(t, hash, record.Hash)
+	assert.Equal(t, "Alice", profile.DisplayName)
+	assert.Equal(t, "Core contributor", profile.Bio)
+
+	// A document signed by someone else is rejected
+	forged, err := dao.PublishMemberProfile(&MemberProfile{DisplayName: "Alice"}, bobKey)
+	require.NoError(t, err)
+	require.NoError(t, dao.ProcessDAOTransaction(&ProfileUpdateTx{Fee: 10, ProfileHash: forged}, alice, types.Hash{0x02}))
+	_, _, err = dao.GetMemberProfile(alice.String())
+	assert.Equal(t, ErrInvalidProfile, err.(*DAOError).Code)
+
+	// A zero hash removes the profile
+	require.NoError(t, dao.ProcessDAOTransaction(&ProfileUpdateTx{Fee: 10}, alice, types.Hash{0x03}))
+	_, exists := dao.Profiles.Get(alice.String())
+	assert.False(t, exists)
+
+	// Only members publish profiles
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	assert.Error(t, dao.ProcessDAOTransaction(&ProfileUpdateTx{Fee: 0, ProfileHash: hash}, outsider, types.Hash{0x04}))
+}
@@ -0,0 +1,334 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Draft limits
+const (
+	MaxDraftCoAuthors     = 10
+	MaxDraftsPerMember    = 50
+	MaxDraftContentLength = 10000
+)
+
+// SavedDraft is a proposal being written before it goes on chain. The owner
+// and co-authors can edit it; only the owner can submit it.
+type SavedDraft struct {
+	ID        types.Hash
+	Owner     crypto.PublicKey
+	CoAuthors []crypto.PublicKey
+	Content   ProposalDraft
+	Version   uint64 // Incremented by every edit
+	CreatedAt int64
+	UpdatedAt int64
+	UpdatedBy crypto.PublicKey
+
+	// Set once the draft is submitted, submitted drafts are read only
+	SubmittedAt int64
+	SubmittedTx types.Hash
+	ContentHash types.Hash // Metadata hash committed by the proposal
+}
+
+// IsAuthor reports whether member owns or co-authors the draft
+func (sd *SavedDraft) IsAuthor(member crypto.PublicKey) bool {
+	if sd.Owner.String() == member.String() {
+		return true
+	}
+	for _, coAuthor := range sd.CoAuthors {
+		if coAuthor.String() == member.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// copy returns a snapshot of the draft
+func (sd *SavedDraft) copy() *SavedDraft {
+	snapshot := *sd
+	snapshot.CoAuthors = append([]crypto.PublicKey(nil), sd.CoAuthors...)
+	if sd.Content.Fields != nil {
+		snapshot.Content.Fields = make(map[string]string, len(sd.Content.Fields))
+		for name, value := range sd.Content.Fields {
+			snapshot.Content.Fields[name] = value
+		}
+	}
+	return &snapshot
+}
+
+// DraftManager keeps proposal drafts. Drafts live on this node outside of
+// consensus until they are submitted.
+type DraftManager struct {
+	tokenState *GovernanceToken
+
+	mu     sync.RWMutex
+	drafts map[types.Hash]*SavedDraft
+	nonce  uint64
+}
+
+// NewDraftManager creates a new draft manager
+func NewDraftManager(tokenState *GovernanceToken) *DraftManager {
+	return &DraftManager{
+		tokenState: tokenState,
+		drafts:     make(map[types.Hash]*SavedDraft),
+	}
+}
+
+// Create saves a new draft owned by owner
+func (dm *DraftManager) Create(owner crypto.PublicKey, content ProposalDraft) (*SavedDraft, error) {
+	if dm.tokenState.Balances.Get(owner.String()) == 0 {
+		return nil, NewDAOError(ErrUnauthorized, "only token holders can write drafts", nil)
+	}
+	if err := validateDraftContent(&content); err != nil {
+		return nil, err
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	owned := 0
+	for _, draft := range dm.drafts {
+		if draft.Owner.String() == owner.String() && draft.SubmittedAt == 0 {
+			owned++
+		}
+	}
+	if owned >= MaxDraftsPerMember {
+		return nil, NewDAOError(ErrInvalidProposal, "too many open drafts", map[string]interface{}{"max": MaxDraftsPerMember})
+	}
+
+	dm.nonce++
+	now := time.Now().Unix()
+	draft := &SavedDraft{
+		ID:        dm.draftID(owner),
+		Owner:     owner,
+		Content:   content,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		UpdatedBy: owner,
+	}
+	dm.drafts[draft.ID] = draft
+
+	return draft.copy(), nil
+}
+
+// Update replaces the content of a draft. version is the version the editor
+// started from, edits based on an older version are rejected.
+func (dm *DraftManager) Update(id types.Hash, editor crypto.PublicKey, content ProposalDraft, version uint64) (*SavedDraft, error) {
+	if err := validateDraftContent(&content); err != nil {
+		return nil, err
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	draft, err := dm.editable(id, editor)
+	if err != nil {
+		return nil, err
+	}
+	if version != draft.Version {
+		return nil, NewDAOError(ErrDraftConflict, "draft was changed by another author", map[string]interface{}{"version": draft.Version})
+	}
+
+	draft.Content = content
+	draft.Version++
+	draft.UpdatedAt = time.Now().Unix()
+	draft.UpdatedBy = editor
+
+	return draft.copy(), nil
+}
+
+// AddCoAuthor invites a co-author to a draft
+func (dm *DraftManager) AddCoAuthor(id types.Hash, owner, coAuthor crypto.PublicKey) (*SavedDraft, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	draft, err := dm.owned(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if draft.IsAuthor(coAuthor) {
+		return draft.copy(), nil
+	}
+	if len(draft.CoAuthors) >= MaxDraftCoAuthors {
+		return nil, NewDAOError(ErrInvalidProposal, "too many co-authors", map[string]interface{}{"max": MaxDraftCoAuthors})
+	}
+
+	draft.CoAuthors = append(draft.CoAuthors, coAuthor)
+	draft.UpdatedAt = time.Now().Unix()
+
+	return draft.copy(), nil
+}
+
+// RemoveCoAuthor removes a co-author from a draft
+func (dm *DraftManager) RemoveCoAuthor(id types.Hash, owner, coAuthor crypto.PublicKey) (*SavedDraft, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	draft, err := dm.owned(id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, existing := range draft.CoAuthors {
+		if existing.String() == coAuthor.String() {
+			draft.CoAuthors = append(draft.CoAuthors[:i], draft.CoAuthors[i+1:]...)
+			draft.UpdatedAt = time.Now().Unix()
+			break
+		}
+	}
+
+	return draft.copy(), nil
+}
+
+// Delete removes an open draft
+func (dm *DraftManager) Delete(id types.Hash, owner crypto.PublicKey) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if _, err := dm.owned(id, owner); err != nil {
+		return err
+	}
+	delete(dm.drafts, id)
+	return nil
+}
+
+// MarkSubmitted records the proposal transaction a draft was submitted with.
+// version must still be the current version so no edit is lost.
+func (dm *DraftManager) MarkSubmitted(id types.Hash, owner crypto.PublicKey, version uint64, txHash, contentHash types.Hash) (*SavedDraft, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	draft, err := dm.owned(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if version != draft.Version {
+		return nil, NewDAOError(ErrDraftConflict, "draft was changed by another author", map[string]interface{}{"version": draft.Version})
+	}
+
+	draft.SubmittedAt = time.Now().Unix()
+	draft.SubmittedTx = txHash
+	draft.ContentHash = contentHash
+
+	return draft.copy(), nil
+}
+
+// Get returns a draft to one of its authors
+func (dm *DraftManager) Get(id types.Hash, member crypto.PublicKey) (*SavedDraft, error) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	draft, exists := dm.drafts[id]
+	if !exists || !draft.IsAuthor(member) {
+		return nil, ErrDraftNotFoundError
+	}
+	return draft.copy(), nil
+}
+
+// List returns the drafts a member owns or co-authors, most recently
+// updated first
+func (dm *DraftManager) List(member crypto.PublicKey) []*SavedDraft {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	var drafts []*SavedDraft
+	for _, draft := range dm.drafts {
+		if draft.IsAuthor(member) {
+			drafts = append(drafts, draft.copy())
+		}
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		if drafts[i].UpdatedAt != drafts[j].UpdatedAt {
+			return drafts[i].UpdatedAt > drafts[j].UpdatedAt
+		}
+		return drafts[i].ID.String() < drafts[j].ID.String()
+	})
+	return drafts
+}
+
+// editable returns an open draft member may edit. Drafts are reported
+// missing to non authors.
+func (dm *DraftManager) editable(id types.Hash, member crypto.PublicKey) (*SavedDraft, error) {
+	draft, exists := dm.drafts[id]
+	if !exists || !draft.IsAuthor(member) {
+		return nil, ErrDraftNotFoundError
+	}
+	if draft.SubmittedAt != 0 {
+		return nil, NewDAOError(ErrInvalidProposal, "draft was already submitted", nil)
+	}
+	return draft, nil
+}
+
+// owned returns an open draft owned by owner
+func (dm *DraftManager) owned(id types.Hash, owner crypto.PublicKey) (*SavedDraft, error) {
+	draft, err := dm.editable(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Owner.String() != owner.String() {
+		return nil, NewDAOError(ErrUnauthorized, "only the draft owner can do this", nil)
+	}
+	return draft, nil
+}
+
+// draftID derives a unique draft ID from its owner
+func (dm *DraftManager) draftID(owner crypto.PublicKey) types.Hash {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], dm.nonce)
+	binary.BigEndian.PutUint64(buf[8:], uint64(time.Now().UnixNano()))
+	return types.Hash(sha256.Sum256(append(owner, buf[:]...)))
+}
+
+// validateDraftContent bounds what a draft may store, the proposal rules
+// are only checked on submission
+func validateDraftContent(content *ProposalDraft) error {
+	size := len(content.Title) + len(content.Description)
+	for name, value := range content.Fields {
+		size += len(name) + len(value)
+	}
+	if size > MaxDraftContentLength {
+		return NewDAOError(ErrInvalidProposal, "draft is too large", map[string]interface{}{"max": MaxDraftContentLength})
+	}
+	return nil
+}
+
+// SubmitDraft prepares the proposal transaction for a draft. The rendered
+// proposal is uploaded to IPFS and its hash committed as the proposal
+// metadata. Callers record the submission with MarkSubmitted once the
+// transaction is sent.
+func (d *DAO) SubmitDraft(id types.Hash, owner crypto.PublicKey, fee int64, now int64) (*SavedDraft, *ProposalTx, []DraftIssue, error) {
+	draft, err := d.Drafts.Get(id, owner)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if draft.Owner.String() != owner.String() {
+		return nil, nil, nil, NewDAOError(ErrUnauthorized, "only the draft owner can submit it", nil)
+	}
+	if draft.SubmittedAt != 0 {
+		return nil, nil, nil, NewDAOError(ErrInvalidProposal, "draft was already submitted", nil)
+	}
+
+	tx, issues := d.ValidateProposalDraft(&draft.Content, owner, fee, now)
+	if len(issues) > 0 {
+		return draft, nil, issues, nil
+	}
+
+	metadataHash, err := d.IPFSClient.UploadProposalMetadata(&ProposalMetadata{
+		Title:       tx.Title,
+		Description: tx.Description,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tx.MetadataHash = metadataHash
+
+	return draft, tx, nil, nil
+}
@@ -0,0 +1,134 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDrafts(t *testing.T) (*DAO, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	owner := crypto.GeneratePrivateKey().PublicKey()
+	coAuthor := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		owner.String():    10000,
+		coAuthor.String(): 1000,
+	}))
+
+	return dao, owner, coAuthor
+}
+
+func TestDrafts_CoAuthors(t *testing.T) {
+	dao, owner, coAuthor := setupDrafts(t)
+
+	draft, err := dao.Drafts.Create(owner, ProposalDraft{Title: "Budget", Description: "First pass"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), draft.Version)
+
+	// Co-authors see nothing until they are invited
+	_, err = dao.Drafts.Get(draft.ID, coAuthor)
+	assert.Equal(t, ErrDraftNotFoundError, err)
+	_, err = dao.Drafts.Update(draft.ID, coAuthor, ProposalDraft{Title: "Budget v2"}, 1)
+	assert.Error(t, err)
+
+	_, err = dao.Drafts.AddCoAuthor(draft.ID, coAuthor, coAuthor)
+	assert.Error(t, err)
+	draft, err = dao.Drafts.AddCoAuthor(draft.ID, owner, coAuthor)
+	require.NoError(t, err)
+	require.Len(t, draft.CoAuthors, 1)
+	assert.Len(t, dao.Drafts.List(coAuthor), 1)
+
+	draft, err = dao.Drafts.Update(draft.ID, coAuthor, ProposalDraft{Title: "Budget v2", Description: "Second pass"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), draft.Version)
+	assert.Equal(t, coAuthor.String(), draft.UpdatedBy.String())
+
+	// Edits from a stale version are rejected
+	_, err = dao.Drafts.Update(draft.ID, owner, ProposalDraft{Title: "Budget v1b"}, 1)
+	require.Error(t, err)
+	assert.Equal(t, ErrDraftConflict, err.(*DAOError).Code)
+
+	// Only the owner manages co-authors and deletes drafts
+	assert.Error(t, dao.Drafts.Delete(draft.ID, coAuthor))
+	draft, err = dao.Drafts.RemoveCoAuthor(draft.ID, owner, coAuthor)
+	require.NoError(t, err)
+	assert.Empty(t, draft.CoAuthors)
+	assert.Empty(t, dao.Drafts.List(coAuthor))
+
+	require.NoError(t, dao.Drafts.Delete(draft.ID, owner))
+	assert.Empty(t, dao.Drafts.List(owner))
+}
+
+func TestDrafts_Limits(t *testing.T) {
+	dao, owner, _ := setupDrafts(t)
+
+	_, err := dao.Drafts.Create(crypto.GeneratePrivateKey().PublicKey(), ProposalDraft{Title: "Outsider"})
+	assert.Error(t, err)
+
+	large := make([]byte, MaxDraftContentLength+1)
+	_, err = dao.Drafts.Create(owner, ProposalDraft{Description: string(large)})
+	assert.Error(t, err)
+
+	for i := 0; i < MaxDraftsPerMember; i++ {
+		_, err := dao.Drafts.Create(owner, ProposalDraft{Title: "Draft"})
+		require.NoError(t, err)
+	}
+	_, err = dao.Drafts.Create(owner, ProposalDraft{Title: "One too many"})
+	assert.Error(t, err)
+}
+
+func TestDrafts_Submit(t *testing.T) {
+	dao, owner, coAuthor := setupDrafts(t)
+	_, objectServer := newTestObjectStore(t)
+	dao.IPFSClient = NewIPFSClientWithStore(NewS3ContentStore(MirrorConfig{Endpoint: objectServer.URL, Bucket: "dao-content"}))
+
+	draft, err := dao.Drafts.Create(owner, ProposalDraft{
+		Title:        "Signal",
+		ProposalType: ProposalTypeGeneral,
+		VotingType:   VotingTypeSimple,
+		Duration:     86400,
+		Threshold:    5100,
+	})
+	require.NoError(t, err)
+	_, err = dao.Drafts.AddCoAuthor(draft.ID, owner, coAuthor)
+	require.NoError(t, err)
+
+	// Drafts are only checked against the proposal rules on submission
+	_, tx, issues, err := dao.SubmitDraft(draft.ID, owner, 100, 1000)
+	require.NoError(t, err)
+	assert.Nil(t, tx)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Message, "description")
+
+	content := draft.Content
+	content.Description = "Signal support for the roadmap"
+	_, err = dao.Drafts.Update(draft.ID, coAuthor, content, 1)
+	require.NoError(t, err)
+
+	_, _, _, err = dao.SubmitDraft(draft.ID, coAuthor, 100, 1000)
+	assert.Error(t, err)
+
+	draft, tx, issues, err = dao.SubmitDraft(draft.ID, owner, 100, 1000)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	require.NotNil(t, tx)
+	assert.False(t, tx.MetadataHash.IsZero())
+
+	// The committed hash resolves to the final content
+	metadata, err := dao.IPFSClient.RetrieveProposalMetadata(tx.MetadataHash)
+	require.NoError(t, err)
+	assert.Equal(t, "Signal support for the roadmap", metadata.Description)
+
+	submitted, err := dao.Drafts.MarkSubmitted(draft.ID, owner, draft.Version, types.Hash{0x01}, tx.MetadataHash)
+	require.NoError(t, err)
+	assert.Equal(t, tx.MetadataHash, submitted.ContentHash)
+
+	_, err = dao.Drafts.Update(draft.ID, coAuthor, content, submitted.Version)
+	assert.Error(t, err)
+	_, _, _, err = dao.SubmitDraft(draft.ID, owner, 100, 1000)
+	assert.Error(t, err)
+}
@@ -307,7 +307,7 @@ func ProposalManagementExample() {
 	fmt.Println("\n--- Final Summary ---")
 	finalStats := dao.ProposalManager.GetProposalStatistics()
 	fmt.Printf("✓ DAO now has %d total proposals across all types\n", finalStats.Total)
-	fmt.Printf("✓ Token holders: %d addresses with tokens\n", len(dao.TokenState.Balances))
+	fmt.Printf("✓ Token holders: %d addresses with tokens\n", dao.TokenState.Balances.Len())
 	fmt.Printf("✓ Treasury balance: %d units\n", dao.GetTreasuryBalance())
 
 	fmt.Println("\n✓ Enhanced Proposal Management Example completed successfully!")
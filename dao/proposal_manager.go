@@ -53,19 +53,46 @@ func (pm *ProposalManager) ExecuteProposal(proposalID types.Hash, executor crypt
 		return NewDAOError(ErrUnauthorized, "executor not authorized for this proposal type", nil)
 	}
 
+	// Proposals on a timelocked track wait for it to run out
+	if unlock := proposal.EndTime + proposal.Timelock; proposal.Timelock > 0 && time.Now().Unix() < unlock {
+		return NewDAOError(ErrInvalidTimeframe, "proposal is timelocked", map[string]interface{}{
+			"unlocks_at": unlock,
+		})
+	}
+
+	// Dependent proposals wait for the proposals they depend on
+	if err := pm.dao.Dependencies.CheckExecutable(proposalID); err != nil {
+		return err
+	}
+
+	// Conditional proposals wait for their oracle data
+	if err := pm.dao.OracleManager.CheckConditions(proposalID, time.Now().Unix()); err != nil {
+		return err
+	}
+
 	// Execute based on proposal type
 	switch proposal.ProposalType {
 	case ProposalTypeGeneral:
-		return pm.executeGeneralProposal(proposal)
+		err = pm.executeGeneralProposal(proposal)
 	case ProposalTypeTreasury:
-		return pm.executeTreasuryProposal(proposal)
+		err = pm.executeTreasuryProposal(proposal)
 	case ProposalTypeTechnical:
-		return pm.executeTechnicalProposal(proposal)
+		err = pm.executeTechnicalProposal(proposal)
 	case ProposalTypeParameter:
-		return pm.executeParameterProposal(proposal)
+		err = pm.executeParameterProposal(proposal)
+	case ProposalTypeConstitution:
+		err = pm.dao.Constitution.Enact(proposal.ID)
+	case ProposalTypeEmergency:
+		err = pm.dao.EmergencySpends.Execute(proposal.ID)
 	default:
 		return NewDAOError(ErrInvalidProposal, "unknown proposal type", nil)
 	}
+	if err != nil {
+		return err
+	}
+
+	pm.dao.Indexes.UpdateProposal(proposalID, proposal)
+	return nil
 }
 
 // CancelProposal allows proposal creator to cancel their proposal before voting starts
@@ -87,28 +114,31 @@ func (pm *ProposalManager) CancelProposal(proposalID types.Hash, canceller crypt
 
 	// Update status
 	proposal.Status = ProposalStatusCancelled
+	pm.dao.Indexes.UpdateProposal(proposalID, proposal)
 	return nil
 }
 
 // GetProposalsByStatus returns all proposals with a specific status
 func (pm *ProposalManager) GetProposalsByStatus(status ProposalStatus) []*Proposal {
 	var proposals []*Proposal
-	for _, proposal := range pm.dao.GovernanceState.Proposals {
+	pm.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
 		if proposal.Status == status {
 			proposals = append(proposals, proposal)
 		}
-	}
+		return true
+	})
 	return proposals
 }
 
 // GetProposalsByType returns all proposals of a specific type
 func (pm *ProposalManager) GetProposalsByType(proposalType ProposalType) []*Proposal {
 	var proposals []*Proposal
-	for _, proposal := range pm.dao.GovernanceState.Proposals {
+	pm.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
 		if proposal.ProposalType == proposalType {
 			proposals = append(proposals, proposal)
 		}
-	}
+		return true
+	})
 	return proposals
 }
 
@@ -116,11 +146,12 @@ func (pm *ProposalManager) GetProposalsByType(proposalType ProposalType) []*Prop
 func (pm *ProposalManager) GetProposalsByCreator(creator crypto.PublicKey) []*Proposal {
 	var proposals []*Proposal
 	creatorStr := creator.String()
-	for _, proposal := range pm.dao.GovernanceState.Proposals {
+	pm.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
 		if proposal.Creator.String() == creatorStr {
 			proposals = append(proposals, proposal)
 		}
-	}
+		return true
+	})
 	return proposals
 }
 
@@ -142,7 +173,7 @@ func (pm *ProposalManager) GetProposalVotingProgress(proposalID types.Hash) (*Vo
 		YesVotes:      proposal.Results.YesVotes,
 		NoVotes:       proposal.Results.NoVotes,
 		AbstainVotes:  proposal.Results.AbstainVotes,
-		QuorumReached: proposal.Results.YesVotes+proposal.Results.NoVotes+proposal.Results.AbstainVotes >= pm.dao.GovernanceState.Config.QuorumThreshold,
+		QuorumReached: proposal.Results.YesVotes+proposal.Results.NoVotes+proposal.Results.AbstainVotes >= proposal.RequiredQuorum(pm.dao.GovernanceState.Config),
 		TimeRemaining: proposal.EndTime - time.Now().Unix(),
 		Voters:        make([]VoterInfo, 0, len(votes)),
 	}
@@ -179,7 +210,7 @@ func (pm *ProposalManager) GetProposalStatistics() *ProposalStatistics {
 		TypeCounts:   make(map[ProposalType]uint64),
 	}
 
-	for _, proposal := range pm.dao.GovernanceState.Proposals {
+	pm.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
 		stats.Total++
 		stats.StatusCounts[proposal.Status]++
 		stats.TypeCounts[proposal.ProposalType]++
@@ -187,7 +218,8 @@ func (pm *ProposalManager) GetProposalStatistics() *ProposalStatistics {
 		if proposal.Results.Passed {
 			stats.Passed++
 		}
-	}
+		return true
+	})
 
 	return stats
 }
@@ -229,12 +261,12 @@ func (pm *ProposalManager) validateProposalCreation(tx *ProposalTx, creator cryp
 // hasRecentProposal checks if creator has submitted a proposal recently
 func (pm *ProposalManager) hasRecentProposal(creator crypto.PublicKey, since int64) bool {
 	creatorStr := creator.String()
-	for _, proposal := range pm.dao.GovernanceState.Proposals {
-		if proposal.Creator.String() == creatorStr && proposal.StartTime > since {
-			return true
-		}
-	}
-	return false
+	recent := false
+	pm.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
+		recent = proposal.Creator.String() == creatorStr && proposal.StartTime > since
+		return !recent
+	})
+	return recent
 }
 
 // isValidMetadataHash validates the metadata hash format
@@ -252,7 +284,7 @@ func (pm *ProposalManager) isAuthorizedExecutor(proposal *Proposal, executor cry
 	case ProposalTypeTreasury:
 		// Only treasury signers can execute treasury proposals
 		return pm.isTreasurySigner(executor)
-	case ProposalTypeTechnical, ProposalTypeParameter:
+	case ProposalTypeTechnical, ProposalTypeParameter, ProposalTypeConstitution, ProposalTypeEmergency:
 		// Only token holders with sufficient balance can execute technical/parameter proposals
 		return pm.dao.GetTokenBalance(executor) >= pm.dao.GovernanceState.Config.MinProposalThreshold
 	default:
@@ -0,0 +1,153 @@
+package dao
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// proposalSchedule is a min-heap of proposal keys by the time their status
+// next changes: the start of pending proposals and the second after the end
+// of active ones. Entries remember their heap position so a proposal can be
+// moved or dropped in O(log n).
+type proposalSchedule struct {
+	entries []*scheduledProposal
+	byKey   map[string]*scheduledProposal
+}
+
+type scheduledProposal struct {
+	key string
+	due int64
+	pos int
+}
+
+func newProposalSchedule() *proposalSchedule {
+	return &proposalSchedule{byKey: make(map[string]*scheduledProposal)}
+}
+
+func (ps *proposalSchedule) Len() int { return len(ps.entries) }
+
+func (ps *proposalSchedule) Less(i, j int) bool {
+	a, b := ps.entries[i], ps.entries[j]
+	return a.due < b.due || (a.due == b.due && a.key < b.key)
+}
+
+func (ps *proposalSchedule) Swap(i, j int) {
+	ps.entries[i], ps.entries[j] = ps.entries[j], ps.entries[i]
+	ps.entries[i].pos = i
+	ps.entries[j].pos = j
+}
+
+func (ps *proposalSchedule) Push(x interface{}) {
+	entry := x.(*scheduledProposal)
+	entry.pos = len(ps.entries)
+	ps.entries = append(ps.entries, entry)
+}
+
+func (ps *proposalSchedule) Pop() interface{} {
+	last := len(ps.entries) - 1
+	entry := ps.entries[last]
+	ps.entries[last] = nil
+	ps.entries = ps.entries[:last]
+	return entry
+}
+
+// Set schedules key at due, moving it if it is already scheduled
+func (ps *proposalSchedule) Set(key string, due int64) {
+	if entry, exists := ps.byKey[key]; exists {
+		if entry.due != due {
+			entry.due = due
+			heap.Fix(ps, entry.pos)
+		}
+		return
+	}
+
+	entry := &scheduledProposal{key: key, due: due}
+	ps.byKey[key] = entry
+	heap.Push(ps, entry)
+}
+
+// Remove unschedules key
+func (ps *proposalSchedule) Remove(key string) {
+	entry, exists := ps.byKey[key]
+	if !exists {
+		return
+	}
+	heap.Remove(ps, entry.pos)
+	delete(ps.byKey, key)
+}
+
+// PopDue unschedules and returns the keys due at or before now, earliest
+// first
+func (ps *proposalSchedule) PopDue(now int64) []string {
+	var keys []string
+	for len(ps.entries) > 0 && ps.entries[0].due <= now {
+		entry := heap.Pop(ps).(*scheduledProposal)
+		delete(ps.byKey, entry.key)
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// proposalDue returns when the status of proposal next changes, or false
+// when it is decided
+func proposalDue(proposal *Proposal) (int64, bool) {
+	switch proposal.Status {
+	case ProposalStatusPending:
+		return proposal.StartTime, true
+	case ProposalStatusActive:
+		return proposal.EndTime + 1, true
+	}
+	return 0, false
+}
+
+// DueProposals unschedules and returns the proposals whose start or end
+// passed by now. Re-indexing them schedules those still undecided again.
+func (si *StateIndex) DueProposals(now int64) []types.Hash {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	keys := si.schedule.PopDue(now)
+	ids := make([]types.Hash, len(keys))
+	for i, key := range keys {
+		ids[i] = types.HashFromBytes([]byte(key))
+	}
+	return ids
+}
+
+// UpdateDueProposalStatuses opens and closes the proposals whose start or
+// end time passed and returns how many it visited. Unlike
+// UpdateAllProposalStatuses it only looks at proposals that are due, so the
+// cost of a run grows with the proposals changing status rather than with
+// every proposal ever made. Proposals still waiting for endorsements or a
+// track slot are visited again on the next run.
+func (d *DAO) UpdateDueProposalStatuses() int {
+	now := time.Now().Unix()
+	d.reindexProposals(d.ParameterManager.AdvanceTrackQueues(now))
+	d.Indexes.sync(d.GovernanceState)
+
+	due := d.Indexes.DueProposals(now)
+	for _, proposalID := range due {
+		d.Processor.UpdateProposalStatus(proposalID)
+	}
+	d.reindexProposals(due)
+	return len(due)
+}
+
+// reindexProposals re-indexes proposals whose times or status changed
+// outside the processor
+func (d *DAO) reindexProposals(ids []types.Hash) {
+	if len(ids) == 0 {
+		return
+	}
+
+	d.Indexes.mu.Lock()
+	defer d.Indexes.mu.Unlock()
+
+	for _, id := range ids {
+		if proposal, exists := d.GovernanceState.Proposal(id); exists {
+			d.Indexes.updateProposal(id, proposal)
+		}
+	}
+}
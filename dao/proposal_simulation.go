@@ -0,0 +1,236 @@
+package dao
+
+import (
+	"math"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Projected proposal outcomes
+const (
+	OutcomePass     = "pass"
+	OutcomeFail     = "fail"
+	OutcomeNoQuorum = "no_quorum"
+)
+
+// SimulationTurnouts are the shares of the remaining voting power, in basis
+// points, projected by a simulation
+var SimulationTurnouts = []uint64{0, 2500, 5000, 7500, 10000}
+
+// ProposalSimulation projects how an open proposal can still end
+type ProposalSimulation struct {
+	ProposalID       string             `json:"proposal_id"`
+	YesVotes         uint64             `json:"yes_votes"`
+	NoVotes          uint64             `json:"no_votes"`
+	AbstainVotes     uint64             `json:"abstain_votes"`
+	RemainingPower   uint64             `json:"remaining_power"` // Largest weight members who have not voted can still cast
+	RemainingVoters  int                `json:"remaining_voters"`
+	QuorumThreshold  uint64             `json:"quorum_threshold"`
+	PassingThreshold uint64             `json:"passing_threshold"` // Basis points of yes and no votes
+	TimeRemaining    int64              `json:"time_remaining"`
+	CurrentOutcome   string             `json:"current_outcome"` // Outcome if voting ended now
+	QuorumReachable  bool               `json:"quorum_reachable"`
+	CanPass          bool               `json:"can_pass"`
+	CanFail          bool               `json:"can_fail"`
+	Scenarios        []TurnoutScenario  `json:"scenarios"`
+	Voter            *VoterContribution `json:"voter,omitempty"`
+}
+
+// TurnoutScenario projects the outcome when part of the remaining voting
+// power votes
+type TurnoutScenario struct {
+	Turnout    uint64 `json:"turnout"` // Basis points of the remaining power
+	AddedPower uint64 `json:"added_power"`
+	// Outcome when the added votes split like the votes cast so far
+	Outcome string `json:"outcome"`
+	// YesNeeded is the least of the added power that must vote yes for the
+	// proposal to pass, when Passable
+	YesNeeded uint64 `json:"yes_needed"`
+	Passable  bool   `json:"passable"`
+}
+
+// VoterContribution is what one address can still add to a proposal
+type VoterContribution struct {
+	Address       string `json:"address"`
+	Eligible      bool   `json:"eligible"`
+	HasVoted      bool   `json:"has_voted"`
+	Weight        uint64 `json:"weight"` // Largest weight the address can cast
+	OutcomeIfYes  string `json:"outcome_if_yes"`
+	OutcomeIfNo   string `json:"outcome_if_no"`
+	OutcomeIfNone string `json:"outcome_if_none"`
+	Decisive      bool   `json:"decisive"` // The vote alone changes the current outcome
+}
+
+// SimulateProposal projects the outcome of an open proposal from its votes
+// and the voting power of the members who have not voted yet. When voter is
+// set the simulation includes the weight the address would contribute.
+func (d *DAO) SimulateProposal(proposalID types.Hash, voter crypto.PublicKey) (*ProposalSimulation, error) {
+	proposal, exists := d.GovernanceState.Proposal(proposalID)
+	if !exists {
+		return nil, ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPending && proposal.Status != ProposalStatusActive {
+		return nil, NewDAOError(ErrVotingClosed, "proposal is no longer open for voting", nil)
+	}
+
+	sim := &ProposalSimulation{
+		ProposalID:       proposalID.String(),
+		QuorumThreshold:  proposal.RequiredQuorum(d.GovernanceState.Config),
+		PassingThreshold: proposal.RequiredThreshold(d.GovernanceState.Config),
+	}
+	if proposal.Results != nil {
+		sim.YesVotes = proposal.Results.YesVotes
+		sim.NoVotes = proposal.Results.NoVotes
+		sim.AbstainVotes = proposal.Results.AbstainVotes
+	}
+	if remaining := proposal.EndTime - time.Now().Unix(); remaining > 0 {
+		sim.TimeRemaining = remaining
+	}
+
+	votes, _ := d.GovernanceState.ProposalVotes(proposalID)
+	for address := range d.TokenState.Balances.Snapshot() {
+		if _, voted := votes[address]; voted {
+			continue
+		}
+		if weight := d.maxVoteWeight(proposal, address); weight > 0 {
+			sim.RemainingPower += weight
+			sim.RemainingVoters++
+		}
+	}
+
+	sim.CurrentOutcome = sim.outcome(sim.YesVotes, sim.NoVotes, sim.AbstainVotes)
+	sim.QuorumReachable = sim.YesVotes+sim.NoVotes+sim.AbstainVotes+sim.RemainingPower >= sim.QuorumThreshold
+	_, sim.CanPass = sim.yesNeeded(sim.RemainingPower)
+	sim.CanFail = sim.outcome(sim.YesVotes, sim.NoVotes+sim.RemainingPower, sim.AbstainVotes) != OutcomePass
+
+	for _, turnout := range SimulationTurnouts {
+		sim.Scenarios = append(sim.Scenarios, sim.scenario(turnout))
+	}
+
+	if voter != nil {
+		sim.Voter = d.voterContribution(sim, proposal, voter)
+	}
+
+	return sim, nil
+}
+
+// maxVoteWeight returns the largest weight address can still cast on
+// proposal under its voting type
+func (d *DAO) maxVoteWeight(proposal *Proposal, address string) uint64 {
+	holder, isHolder := d.GovernanceState.TokenHolders[address]
+	if isHolder && holder.Status != MembershipStatusActive {
+		return 0
+	}
+
+	balance := d.TokenState.Balances.Get(address)
+	switch proposal.VotingType {
+	case VotingTypeSimple, VotingTypeWeighted:
+		return balance
+	case VotingTypeQuadratic:
+		weight := uint64(math.Sqrt(float64(balance)))
+		for weight*weight > balance {
+			weight--
+		}
+		for (weight+1)*(weight+1) <= balance {
+			weight++
+		}
+		return weight
+	case VotingTypeReputation:
+		if !isHolder || balance == 0 {
+			return 0
+		}
+		return holder.Reputation
+	default:
+		return 0
+	}
+}
+
+// voterContribution reports what voter would add to the simulated proposal
+func (d *DAO) voterContribution(sim *ProposalSimulation, proposal *Proposal, voter crypto.PublicKey) *VoterContribution {
+	address := voter.String()
+	contribution := &VoterContribution{
+		Address:       address,
+		OutcomeIfNone: sim.CurrentOutcome,
+	}
+
+	votes, _ := d.GovernanceState.ProposalVotes(proposal.ID)
+	if _, voted := votes[address]; voted {
+		contribution.HasVoted = true
+		contribution.OutcomeIfYes = sim.CurrentOutcome
+		contribution.OutcomeIfNo = sim.CurrentOutcome
+		return contribution
+	}
+
+	contribution.Weight = d.maxVoteWeight(proposal, address)
+	contribution.Eligible = contribution.Weight > 0
+	contribution.OutcomeIfYes = sim.outcome(sim.YesVotes+contribution.Weight, sim.NoVotes, sim.AbstainVotes)
+	contribution.OutcomeIfNo = sim.outcome(sim.YesVotes, sim.NoVotes+contribution.Weight, sim.AbstainVotes)
+	contribution.Decisive = contribution.OutcomeIfYes != sim.CurrentOutcome || contribution.OutcomeIfNo != sim.CurrentOutcome
+
+	return contribution
+}
+
+// scenario projects the outcome when turnout basis points of the remaining
+// power votes
+func (sim *ProposalSimulation) scenario(turnout uint64) TurnoutScenario {
+	added := sim.RemainingPower / 10000 * turnout
+	added += sim.RemainingPower % 10000 * turnout / 10000
+
+	// Added votes follow the split so far, an even yes/no split without votes
+	yes, no, abstain := added/2, added-added/2, uint64(0)
+	if cast := sim.YesVotes + sim.NoVotes + sim.AbstainVotes; cast > 0 {
+		yes = scaleVotes(added, sim.YesVotes, cast)
+		abstain = scaleVotes(added, sim.AbstainVotes, cast)
+		no = added - yes - abstain
+	}
+
+	scenario := TurnoutScenario{
+		Turnout:    turnout,
+		AddedPower: added,
+		Outcome:    sim.outcome(sim.YesVotes+yes, sim.NoVotes+no, sim.AbstainVotes+abstain),
+	}
+	scenario.YesNeeded, scenario.Passable = sim.yesNeeded(added)
+	return scenario
+}
+
+// yesNeeded returns the least of added votes that must be yes for the
+// proposal to pass when the rest vote no, and whether that is possible
+func (sim *ProposalSimulation) yesNeeded(added uint64) (uint64, bool) {
+	if sim.YesVotes+sim.NoVotes+sim.AbstainVotes+added < sim.QuorumThreshold {
+		return 0, false
+	}
+	active := sim.YesVotes + sim.NoVotes + added
+	if active == 0 {
+		return 0, false
+	}
+
+	// (yes + y) * 10000 >= threshold * active
+	required := sim.PassingThreshold * active
+	if sim.YesVotes*10000 >= required {
+		return 0, true
+	}
+	needed := (required - sim.YesVotes*10000 + 9999) / 10000
+	return needed, needed <= added
+}
+
+// outcome mirrors how a proposal is decided when its voting period ends
+func (sim *ProposalSimulation) outcome(yes, no, abstain uint64) string {
+	if yes+no+abstain < sim.QuorumThreshold {
+		return OutcomeNoQuorum
+	}
+	active := yes + no
+	if active == 0 || yes*10000/active < sim.PassingThreshold {
+		return OutcomeFail
+	}
+	return OutcomePass
+}
+
+// scaleVotes returns votes/cast of added, rounded down
+func scaleVotes(added, votes, cast uint64) uint64 {
+	if cast == 0 {
+		return 0
+	}
+	return added/cast*votes + added%cast*votes/cast
+}
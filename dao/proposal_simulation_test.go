@@ -0,0 +1,103 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSimulation(t *testing.T, votingType VotingType) (*DAO, []crypto.PublicKey, types.Hash) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	members := make([]crypto.PublicKey, 4)
+	balances := []uint64{4000, 3000, 2000, 1000}
+	distribution := make(map[string]uint64)
+	for i := range members {
+		members[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[members[i].String()] = balances[i]
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+	dao.GovernanceState.Config.QuorumThreshold = 2000
+	dao.GovernanceState.Config.PassingThreshold = 5000
+
+	proposalID := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+		Title: "Signal", Description: "Roadmap", ProposalType: ProposalTypeGeneral, VotingType: votingType,
+		StartTime: 0, EndTime: 1 << 40, Threshold: 5000,
+	}, members[0], proposalID))
+
+	return dao, members, proposalID
+}
+
+func TestSimulateProposal_Outcomes(t *testing.T) {
+	dao, members, proposalID := setupSimulation(t, VotingTypeSimple)
+
+	sim, err := dao.SimulateProposal(proposalID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeNoQuorum, sim.CurrentOutcome)
+	assert.Equal(t, uint64(10000), sim.RemainingPower)
+	assert.Equal(t, 4, sim.RemainingVoters)
+	assert.True(t, sim.QuorumReachable)
+	assert.True(t, sim.CanPass)
+	assert.True(t, sim.CanFail)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{ProposalID: proposalID, Choice: VoteChoiceNo, Weight: 1500}, members[1], types.Hash{0x02}))
+	require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{ProposalID: proposalID, Choice: VoteChoiceYes, Weight: 500}, members[3], types.Hash{0x03}))
+
+	sim, err = dao.SimulateProposal(proposalID, members[2])
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFail, sim.CurrentOutcome)
+	assert.Equal(t, uint64(6000), sim.RemainingPower)
+	assert.Equal(t, 2, sim.RemainingVoters)
+
+	// Scenarios follow the current 1:3 split
+	require.Len(t, sim.Scenarios, len(SimulationTurnouts))
+	full := sim.Scenarios[len(sim.Scenarios)-1]
+	assert.Equal(t, uint64(6000), full.AddedPower)
+	assert.Equal(t, OutcomeFail, full.Outcome)
+	assert.True(t, full.Passable)
+	assert.Equal(t, uint64(3500), full.YesNeeded)
+	assert.False(t, sim.Scenarios[0].Passable)
+	assert.Equal(t, uint64(1250), sim.Scenarios[1].YesNeeded)
+
+	// The undecided member can turn the vote
+	require.NotNil(t, sim.Voter)
+	assert.True(t, sim.Voter.Eligible)
+	assert.Equal(t, uint64(2000), sim.Voter.Weight)
+	assert.Equal(t, OutcomePass, sim.Voter.OutcomeIfYes)
+	assert.Equal(t, OutcomeFail, sim.Voter.OutcomeIfNo)
+	assert.True(t, sim.Voter.Decisive)
+
+	sim, err = dao.SimulateProposal(proposalID, members[1])
+	require.NoError(t, err)
+	assert.True(t, sim.Voter.HasVoted)
+	assert.Zero(t, sim.Voter.Weight)
+
+	// Decided proposals cannot be simulated
+	dao.GovernanceState.Proposals[proposalID].Status = ProposalStatusRejected
+	_, err = dao.SimulateProposal(proposalID, nil)
+	assert.Error(t, err)
+	_, err = dao.SimulateProposal(types.Hash{0xff}, nil)
+	assert.Equal(t, ErrProposalNotFoundError, err)
+}
+
+func TestSimulateProposal_VotingPower(t *testing.T) {
+	dao, members, proposalID := setupSimulation(t, VotingTypeQuadratic)
+
+	// Quadratic votes cost the square of their weight
+	sim, err := dao.SimulateProposal(proposalID, members[3])
+	require.NoError(t, err)
+	assert.Equal(t, uint64(31), sim.Voter.Weight)
+	assert.Equal(t, uint64(63+54+44+31), sim.RemainingPower)
+
+	// Suspended members cannot vote
+	dao.GovernanceState.TokenHolders[members[3].String()].Status = MembershipStatusSuspended
+	sim, err = dao.SimulateProposal(proposalID, members[3])
+	require.NoError(t, err)
+	assert.False(t, sim.Voter.Eligible)
+	assert.Equal(t, 3, sim.RemainingVoters)
+	assert.False(t, sim.QuorumReachable)
+	assert.False(t, sim.CanPass)
+}
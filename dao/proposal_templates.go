@@ -0,0 +1,419 @@
+package dao
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+// Built-in proposal templates
+const (
+	TemplateTreasuryRequest = "treasury_request"
+	TemplateParameterChange = "parameter_change"
+	TemplateMembership      = "membership"
+)
+
+// Template field types
+const (
+	TemplateFieldText      = "text"
+	TemplateFieldAddress   = "address"   // Hex encoded member public key
+	TemplateFieldAmount    = "amount"    // Token amount
+	TemplateFieldEnum      = "enum"      // One of the field options
+	TemplateFieldParameter = "parameter" // Name of a governance parameter
+)
+
+// TemplateField is a value a proposal template asks for. Values are sent
+// as strings, the way forms collect them.
+type TemplateField struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description,omitempty"`
+	MaxLength   int      `json:"max_length,omitempty"`
+	Min         uint64   `json:"min,omitempty"` // Smallest amount
+	Options     []string `json:"options,omitempty"`
+}
+
+// ProposalTemplate guides members through a kind of proposal. The filled in
+// fields are appended to the proposal description.
+type ProposalTemplate struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	ProposalType ProposalType    `json:"proposal_type"`
+	TitlePrefix  string          `json:"title_prefix,omitempty"`
+	Fields       []TemplateField `json:"fields"`
+
+	// check applies the rules spanning fields or depending on DAO state,
+	// it only runs once every field is valid on its own
+	check func(d *DAO, fields map[string]string) []DraftIssue
+}
+
+// ProposalDraft is a proposal a member is about to sign
+type ProposalDraft struct {
+	Template     string            `json:"template,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	ProposalType ProposalType      `json:"proposal_type"` // Set by the template when there is one
+	VotingType   VotingType        `json:"voting_type"`
+	StartTime    int64             `json:"start_time,omitempty"` // Scheduled vote start, 0 opens on submission
+	Duration     int64             `json:"duration"`             // Seconds
+	Threshold    uint64            `json:"threshold"`
+	VoteSponsor  uint64            `json:"vote_sponsor,omitempty"`
+}
+
+// DraftIssue is a reason a draft would fail, Field is empty for issues with
+// the proposal as a whole
+type DraftIssue struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ProposalTemplates keeps the templates the server offers
+type ProposalTemplates struct {
+	mu        sync.RWMutex
+	templates map[string]*ProposalTemplate
+}
+
+// NewProposalTemplates creates a registry with the built-in templates
+func NewProposalTemplates() *ProposalTemplates {
+	pt := &ProposalTemplates{templates: make(map[string]*ProposalTemplate)}
+	for _, template := range builtinProposalTemplates() {
+		pt.templates[template.ID] = template
+	}
+	return pt
+}
+
+// Register adds or replaces a template
+func (pt *ProposalTemplates) Register(template *ProposalTemplate) error {
+	if template.ID == "" || template.Name == "" {
+		return NewDAOError(ErrInvalidProposal, "templates need an ID and a name", nil)
+	}
+	if template.ProposalType < ProposalTypeGeneral || template.ProposalType > ProposalTypeParameter {
+		return NewDAOError(ErrInvalidProposal, "invalid proposal type", nil)
+	}
+	for _, field := range template.Fields {
+		switch field.Type {
+		case TemplateFieldText, TemplateFieldAddress, TemplateFieldAmount, TemplateFieldParameter:
+		case TemplateFieldEnum:
+			if len(field.Options) == 0 {
+				return NewDAOError(ErrInvalidProposal, "enum fields need options", map[string]interface{}{"field": field.Name})
+			}
+		default:
+			return NewDAOError(ErrInvalidProposal, "unknown field type", map[string]interface{}{"field": field.Name, "type": field.Type})
+		}
+	}
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.templates[template.ID] = template
+	return nil
+}
+
+// Get returns a template
+func (pt *ProposalTemplates) Get(id string) (*ProposalTemplate, bool) {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	template, exists := pt.templates[id]
+	return template, exists
+}
+
+// List returns the templates sorted by ID
+func (pt *ProposalTemplates) List() []*ProposalTemplate {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	templates := make([]*ProposalTemplate, 0, len(pt.templates))
+	for _, template := range pt.templates {
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+// validateFields checks the draft values against the template fields
+func (t *ProposalTemplate) validateFields(d *DAO, fields map[string]string) []DraftIssue {
+	var issues []DraftIssue
+	known := make(map[string]bool, len(t.Fields))
+
+	for _, field := range t.Fields {
+		known[field.Name] = true
+
+		value := strings.TrimSpace(fields[field.Name])
+		if value == "" {
+			if field.Required {
+				issues = append(issues, DraftIssue{Field: field.Name, Message: field.Label + " is required"})
+			}
+			continue
+		}
+
+		if message := validateTemplateValue(d, field, value); message != "" {
+			issues = append(issues, DraftIssue{Field: field.Name, Message: message})
+		}
+	}
+
+	for name := range fields {
+		if !known[name] {
+			issues = append(issues, DraftIssue{Field: name, Message: "unknown field"})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Field < issues[j].Field
+	})
+	return issues
+}
+
+// validateTemplateValue returns why a value does not fit its field
+func validateTemplateValue(d *DAO, field TemplateField, value string) string {
+	if field.MaxLength > 0 && len(value) > field.MaxLength {
+		return fmt.Sprintf("%s must be at most %d characters", field.Label, field.MaxLength)
+	}
+
+	switch field.Type {
+	case TemplateFieldAddress:
+		if _, err := parseAddress(value); err != nil {
+			return field.Label + " must be a hex encoded public key"
+		}
+	case TemplateFieldAmount:
+		amount, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return field.Label + " must be a whole token amount"
+		}
+		if amount < field.Min {
+			return fmt.Sprintf("%s must be at least %d", field.Label, field.Min)
+		}
+	case TemplateFieldEnum:
+		for _, option := range field.Options {
+			if value == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s must be one of %s", field.Label, strings.Join(field.Options, ", "))
+	case TemplateFieldParameter:
+		if _, err := d.GetParameterValue(value); err != nil {
+			return field.Label + " is not a governance parameter"
+		}
+	}
+	return ""
+}
+
+// render appends the filled in fields to the description
+func (t *ProposalTemplate) render(description string, fields map[string]string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(description))
+	b.WriteString("\n\n---\n")
+	for _, field := range t.Fields {
+		if value := strings.TrimSpace(fields[field.Name]); value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field.Label, value)
+		}
+	}
+	return b.String()
+}
+
+// parseAddress parses a hex encoded public key
+func parseAddress(value string) (crypto.PublicKey, error) {
+	key, err := hex.DecodeString(value)
+	if err != nil || len(key) != 33 {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	return crypto.PublicKey(key), nil
+}
+
+// ParameterValueString returns a parameter value written in JSON as a
+// string, number or boolean in the form parameter values are parsed from
+func ParameterValueString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+
+	var scalar interface{}
+	if err := json.Unmarshal(value, &scalar); err != nil {
+		return "", err
+	}
+	switch scalar.(type) {
+	case float64, bool:
+		return string(value), nil
+	default:
+		return "", fmt.Errorf("value must be a string, number or boolean")
+	}
+}
+
+// parseParameterValue parses a parameter value into the type of its
+// current value
+func parseParameterValue(current interface{}, value string) (interface{}, error) {
+	switch current.(type) {
+	case uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case int64:
+		return strconv.ParseInt(value, 10, 64)
+	case uint8:
+		parsed, err := strconv.ParseUint(value, 10, 8)
+		return uint8(parsed), err
+	case bool:
+		return strconv.ParseBool(value)
+	default:
+		return nil, fmt.Errorf("parameter cannot be set from a template")
+	}
+}
+
+// ValidateProposalDraft builds the proposal transaction a draft would submit
+// and reports everything that would make it fail. The transaction is nil
+// when the template or its fields are invalid.
+func (d *DAO) ValidateProposalDraft(draft *ProposalDraft, creator crypto.PublicKey, fee int64, now int64) (*ProposalTx, []DraftIssue) {
+	title := strings.TrimSpace(draft.Title)
+	description := draft.Description
+	proposalType := draft.ProposalType
+
+	if draft.Template != "" {
+		template, exists := d.ProposalTemplates.Get(draft.Template)
+		if !exists {
+			return nil, []DraftIssue{{Field: "template", Message: "unknown template"}}
+		}
+		if issues := template.validateFields(d, draft.Fields); len(issues) > 0 {
+			return nil, issues
+		}
+		if template.check != nil {
+			if issues := template.check(d, draft.Fields); len(issues) > 0 {
+				return nil, issues
+			}
+		}
+
+		if !strings.HasPrefix(title, template.TitlePrefix) {
+			title = template.TitlePrefix + title
+		}
+		description = template.render(description, draft.Fields)
+		proposalType = template.ProposalType
+	}
+
+	startTime := now
+	var issues []DraftIssue
+	if draft.StartTime != 0 {
+		startTime = draft.StartTime
+		if err := ValidateProposalSchedule(startTime, now); err != nil {
+			issues = append(issues, DraftIssue{Field: "start_time", Message: draftIssueMessage(err)})
+		}
+	}
+
+	tx := &ProposalTx{
+		Fee:          fee,
+		Title:        title,
+		Description:  description,
+		ProposalType: proposalType,
+		VotingType:   draft.VotingType,
+		StartTime:    startTime,
+		EndTime:      startTime + draft.Duration,
+		Threshold:    draft.Threshold,
+		VoteSponsor:  draft.VoteSponsor,
+	}
+
+	if err := d.Validator.ValidateProposalTx(tx, creator); err != nil {
+		issues = append(issues, DraftIssue{Message: draftIssueMessage(err)})
+	}
+	if d.GetTokenBalance(creator) < uint64(fee) {
+		issues = append(issues, DraftIssue{Message: "insufficient balance for the proposal fee"})
+	}
+	if err := d.FeeSponsor.CheckBudget(draft.VoteSponsor); err != nil {
+		issues = append(issues, DraftIssue{Field: "vote_sponsor", Message: draftIssueMessage(err)})
+	}
+
+	return tx, issues
+}
+
+// draftIssueMessage returns the message of a DAO error without its code
+func draftIssueMessage(err error) string {
+	if daoErr, ok := err.(*DAOError); ok {
+		return daoErr.Message
+	}
+	return err.Error()
+}
+
+func builtinProposalTemplates() []*ProposalTemplate {
+	return []*ProposalTemplate{
+		{
+			ID:           TemplateTreasuryRequest,
+			Name:         "Treasury request",
+			Description:  "Request funds from the treasury for work that benefits the DAO",
+			ProposalType: ProposalTypeTreasury,
+			TitlePrefix:  "Treasury request: ",
+			Fields: []TemplateField{
+				{Name: "recipient", Label: "Recipient", Type: TemplateFieldAddress, Required: true},
+				{Name: "amount", Label: "Amount", Type: TemplateFieldAmount, Required: true, Min: 1},
+				{Name: "purpose", Label: "Purpose", Type: TemplateFieldText, Required: true, MaxLength: 500},
+				{Name: "deliverables", Label: "Deliverables", Type: TemplateFieldText, MaxLength: 2000},
+			},
+			check: func(d *DAO, fields map[string]string) []DraftIssue {
+				amount, _ := strconv.ParseUint(fields["amount"], 10, 64)
+				if balance := d.GetTreasuryBalance(); amount > balance {
+					return []DraftIssue{{Field: "amount", Message: fmt.Sprintf("Amount exceeds the treasury balance of %d", balance)}}
+				}
+				if max := d.GetParameterConfig().MaxTreasuryWithdraw; max > 0 && amount > max {
+					return []DraftIssue{{Field: "amount", Message: fmt.Sprintf("Amount exceeds the maximum treasury withdrawal of %d", max)}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:           TemplateParameterChange,
+			Name:         "Parameter change",
+			Description:  "Change a governance parameter",
+			ProposalType: ProposalTypeParameter,
+			TitlePrefix:  "Parameter change: ",
+			Fields: []TemplateField{
+				{Name: "parameter", Label: "Parameter", Type: TemplateFieldParameter, Required: true},
+				{Name: "value", Label: "New value", Type: TemplateFieldText, Required: true, MaxLength: 100},
+				{Name: "justification", Label: "Justification", Type: TemplateFieldText, Required: true, MaxLength: 2000},
+			},
+			check: func(d *DAO, fields map[string]string) []DraftIssue {
+				parameter := fields["parameter"]
+				current, _ := d.GetParameterValue(parameter)
+				value, err := parseParameterValue(current, strings.TrimSpace(fields["value"]))
+				if err != nil {
+					return []DraftIssue{{Field: "value", Message: fmt.Sprintf("New value must be a %T", current)}}
+				}
+				if err := d.ParameterManager.ValidateParameterChanges(map[string]interface{}{parameter: value}); err != nil {
+					return []DraftIssue{{Field: "value", Message: draftIssueMessage(err)}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:           TemplateMembership,
+			Name:         "Membership",
+			Description:  "Admit a member to the DAO or remove one",
+			ProposalType: ProposalTypeGeneral,
+			TitlePrefix:  "Membership: ",
+			Fields: []TemplateField{
+				{Name: "member", Label: "Member", Type: TemplateFieldAddress, Required: true},
+				{Name: "action", Label: "Action", Type: TemplateFieldEnum, Required: true, Options: []string{"add", "remove"}},
+				{Name: "role", Label: "Role", Type: TemplateFieldEnum, Options: []string{"member", "moderator"}},
+				{Name: "rationale", Label: "Rationale", Type: TemplateFieldText, Required: true, MaxLength: 2000},
+			},
+			check: func(d *DAO, fields map[string]string) []DraftIssue {
+				member, _ := parseAddress(fields["member"])
+				_, isMember := d.GovernanceState.TokenHolders[member.String()]
+				if fields["action"] == "add" && isMember {
+					return []DraftIssue{{Field: "member", Message: "Member already belongs to the DAO"}}
+				}
+				if fields["action"] == "remove" && !isMember {
+					return []DraftIssue{{Field: "member", Message: "Member does not belong to the DAO"}}
+				}
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProposalTemplates(t *testing.T) (*DAO, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		creator.String(): 10000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 5000
+
+	return dao, creator
+}
+
+func TestProposalTemplates_List(t *testing.T) {
+	templates := NewProposalTemplates()
+
+	list := templates.List()
+	require.Len(t, list, 3)
+	assert.Equal(t, TemplateMembership, list[0].ID)
+	assert.Equal(t, TemplateParameterChange, list[1].ID)
+	assert.Equal(t, TemplateTreasuryRequest, list[2].ID)
+
+	assert.Error(t, templates.Register(&ProposalTemplate{ID: "bad", Name: "Bad", ProposalType: ProposalTypeGeneral,
+		Fields: []TemplateField{{Name: "choice", Type: TemplateFieldEnum}}}))
+	require.NoError(t, templates.Register(&ProposalTemplate{ID: "signal", Name: "Signal", ProposalType: ProposalTypeGeneral}))
+
+	template, exists := templates.Get("signal")
+	require.True(t, exists)
+	assert.Equal(t, "Signal", template.Name)
+}
+
+func TestProposalTemplates_TreasuryRequest(t *testing.T) {
+	dao, creator := setupProposalTemplates(t)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	draft := &ProposalDraft{
+		Template:    TemplateTreasuryRequest,
+		Fields:      map[string]string{"recipient": recipient.String(), "amount": "1000", "purpose": "Audit"},
+		Title:       "Security audit",
+		Description: "Fund the audit of the bridge contracts",
+		VotingType:  VotingTypeSimple,
+		Duration:    86400,
+		Threshold:   5100,
+	}
+
+	tx, issues := dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	assert.Empty(t, issues)
+	require.NotNil(t, tx)
+	assert.Equal(t, "Treasury request: Security audit", tx.Title)
+	assert.Equal(t, ProposalTypeTreasury, tx.ProposalType)
+	assert.True(t, strings.Contains(tx.Description, "Amount: 1000"))
+	assert.Equal(t, int64(1000+86400), tx.EndTime)
+
+	// The treasury cannot pay more than it holds
+	draft.Fields["amount"] = "6000"
+	tx, issues = dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	assert.Nil(t, tx)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "amount", issues[0].Field)
+
+	// Field issues are reported together
+	draft.Fields = map[string]string{"recipient": "nothex", "amount": "-1", "color": "blue"}
+	_, issues = dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	fields := make([]string, len(issues))
+	for i, issue := range issues {
+		fields[i] = issue.Field
+	}
+	assert.Equal(t, []string{"amount", "color", "purpose", "recipient"}, fields)
+}
+
+func TestProposalTemplates_ParameterChange(t *testing.T) {
+	dao, creator := setupProposalTemplates(t)
+
+	draft := &ProposalDraft{
+		Template:    TemplateParameterChange,
+		Fields:      map[string]string{"parameter": "voting_period", "value": "172800", "justification": "More time to vote"},
+		Title:       "Longer voting",
+		Description: "Double the voting period",
+		VotingType:  VotingTypeSimple,
+		Duration:    86400,
+		Threshold:   5100,
+	}
+	_, issues := dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	assert.Empty(t, issues)
+
+	draft.Fields["value"] = "soon"
+	_, issues = dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "value", issues[0].Field)
+
+	draft.Fields["parameter"] = "block_size"
+	_, issues = dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "parameter", issues[0].Field)
+}
+
+func TestProposalTemplates_Membership(t *testing.T) {
+	dao, creator := setupProposalTemplates(t)
+
+	draft := &ProposalDraft{
+		Template:    TemplateMembership,
+		Fields:      map[string]string{"member": creator.String(), "action": "add", "rationale": "Core contributor"},
+		Title:       "Add a member",
+		Description: "Admit a contributor",
+		VotingType:  VotingTypeSimple,
+		Duration:    86400,
+		Threshold:   5100,
+	}
+	_, issues := dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "member", issues[0].Field)
+
+	draft.Fields["action"] = "remove"
+	_, issues = dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	assert.Empty(t, issues)
+}
+
+func TestProposalTemplates_ProposalChecks(t *testing.T) {
+	dao, creator := setupProposalTemplates(t)
+
+	// Drafts without a template still get the transaction checks
+	draft := &ProposalDraft{
+		Title:        "",
+		Description:  "No title",
+		ProposalType: ProposalTypeGeneral,
+		VotingType:   VotingTypeSimple,
+		Duration:     10,
+		Threshold:    5100,
+	}
+	tx, issues := dao.ValidateProposalDraft(draft, creator, 100, 1000)
+	require.NotNil(t, tx)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Message, "title")
+
+	draft.Title = "Signal"
+	draft.Duration = 86400
+	_, issues = dao.ValidateProposalDraft(draft, creator, 20000, 1000)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Message, "fee")
+
+	_, issues = dao.ValidateProposalDraft(&ProposalDraft{Template: "unknown"}, creator, 100, 1000)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "template", issues[0].Field)
+}
@@ -0,0 +1,348 @@
+package dao
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxQFProjects bounds the projects of a quadratic funding round
+const MaxQFProjects = 50
+
+// QFRoundStatus is the stage of a quadratic funding round
+type QFRoundStatus string
+
+const (
+	QFRoundStatusProposed QFRoundStatus = "proposed" // Awaiting the main DAO vote
+	QFRoundStatusRejected QFRoundStatus = "rejected" // The main DAO did not approve it
+	QFRoundStatusOpen     QFRoundStatus = "open"     // Taking contributions
+	QFRoundStatusTallying QFRoundStatus = "tallying" // Closed and awaiting the payout
+	QFRoundStatusPaid     QFRoundStatus = "paid"
+)
+
+// QFProjectSpec is a project a round proposal lists for funding
+type QFProjectSpec struct {
+	Name        string
+	Description string
+	Recipient   crypto.PublicKey
+}
+
+// QFProject is a project of a round with the contributions it received
+type QFProject struct {
+	Name          string
+	Description   string
+	Recipient     crypto.PublicKey
+	Contributions map[string]uint64 // contributor -> amount
+	Raised        uint64
+	Matching      uint64 // Paid from the matching pool with the payout
+}
+
+// QFRound is a quadratic funding round. Members contribute to its projects,
+// contributions go to the project straight away, and the matching pool
+// escrowed from the treasury is shared out by the quadratic formula once
+// the round closes. Its ID is the ID of the proposal that approved it.
+type QFRound struct {
+	ID                 types.Hash
+	Title              string
+	Description        string
+	MatchingPool       uint64
+	Dampening          uint64 // Pairwise coordination allowed before matching is dampened
+	ContributionPeriod int64
+	Multisig           types.Hash // Multisig account that executes the payout
+	Proposer           crypto.PublicKey
+	Projects           []*QFProject
+	Status             QFRoundStatus
+	CreatedAt          int64
+	OpenedAt           int64
+	ClosesAt           int64
+	PaidAt             int64
+	Matched            uint64 // Matching paid to the projects
+	Returned           uint64 // Matching pool left over and returned to the treasury
+}
+
+// StatusAt returns the stage of the round at time now. Open rounds stop
+// taking contributions once their period ends.
+func (r *QFRound) StatusAt(now int64) QFRoundStatus {
+	if r.Status == QFRoundStatusOpen && now >= r.ClosesAt {
+		return QFRoundStatusTallying
+	}
+	return r.Status
+}
+
+// Matching computes the share of the matching pool of every project with
+// pairwise-bounded quadratic funding. Every pair of contributors to a
+// project adds 2*sqrt(a*b) as in plain quadratic funding, dampened by
+// k/(k+c) where c is how much the pair contributed together across all
+// projects of the round, so colluding contributors cannot farm the pool.
+// The shares are scaled down when they exceed the pool.
+func (r *QFRound) Matching() []uint64 {
+	type pair struct{ a, b string }
+
+	contributors := make([][]string, len(r.Projects))
+	coordination := make(map[pair]float64)
+	for p, project := range r.Projects {
+		keys := make([]string, 0, len(project.Contributions))
+		for contributor := range project.Contributions {
+			keys = append(keys, contributor)
+		}
+		sort.Strings(keys)
+		contributors[p] = keys
+
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				coordination[pair{keys[i], keys[j]}] += math.Sqrt(float64(project.Contributions[keys[i]]) * float64(project.Contributions[keys[j]]))
+			}
+		}
+	}
+
+	k := float64(r.Dampening)
+	if k == 0 {
+		k = float64(r.MatchingPool)
+	}
+
+	raw := make([]float64, len(r.Projects))
+	total := 0.0
+	for p, project := range r.Projects {
+		keys := contributors[p]
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				joint := math.Sqrt(float64(project.Contributions[keys[i]]) * float64(project.Contributions[keys[j]]))
+				raw[p] += 2 * joint * k / (k + coordination[pair{keys[i], keys[j]}])
+			}
+		}
+		total += raw[p]
+	}
+
+	scale := 1.0
+	if total > float64(r.MatchingPool) {
+		scale = float64(r.MatchingPool) / total
+	}
+
+	matching := make([]uint64, len(r.Projects))
+	for p := range raw {
+		matching[p] = uint64(raw[p] * scale)
+	}
+	return matching
+}
+
+// Proposal returns the main DAO proposal voting on the round
+func (tx *QFRoundProposalTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        "Quadratic funding: " + tx.Title,
+		Description:  description,
+		ProposalType: ProposalTypeTreasury,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// QFManager runs the quadratic funding rounds from proposal to payout
+type QFManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	rounds          map[types.Hash]*QFRound
+}
+
+// NewQFManager creates a new quadratic funding manager
+func NewQFManager(governanceState *GovernanceState, tokenState *GovernanceToken) *QFManager {
+	return &QFManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		rounds:          make(map[types.Hash]*QFRound),
+	}
+}
+
+// RecordProposal adds the round of a main DAO proposal
+func (qm *QFManager) RecordProposal(proposalID types.Hash, tx *QFRoundProposalTx, proposer crypto.PublicKey) {
+	projects := make([]*QFProject, len(tx.Projects))
+	for i, spec := range tx.Projects {
+		projects[i] = &QFProject{
+			Name:          spec.Name,
+			Description:   spec.Description,
+			Recipient:     spec.Recipient,
+			Contributions: make(map[string]uint64),
+		}
+	}
+
+	qm.rounds[proposalID] = &QFRound{
+		ID:                 proposalID,
+		Title:              tx.Title,
+		Description:        tx.Description,
+		MatchingPool:       tx.MatchingPool,
+		Dampening:          tx.Dampening,
+		ContributionPeriod: tx.ContributionPeriod,
+		Multisig:           tx.Multisig,
+		Proposer:           proposer,
+		Projects:           projects,
+		Status:             QFRoundStatusProposed,
+		CreatedAt:          time.Now().Unix(),
+	}
+}
+
+// ProcessQFRoundOpenTx opens the round of a passed proposal for
+// contributions, escrowing its matching pool from the treasury
+func (qm *QFManager) ProcessQFRoundOpenTx(tx *QFRoundOpenTx, opener crypto.PublicKey) error {
+	round, exists := qm.rounds[tx.ProposalID]
+	if !exists {
+		return ErrQFRoundNotFoundError
+	}
+	if round.Status != QFRoundStatusProposed {
+		return NewDAOError(ErrInvalidProposal, "round is already open", nil)
+	}
+
+	proposal, exists := qm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	if qm.governanceState.Treasury.Balance < round.MatchingPool {
+		return NewDAOError(ErrTreasuryInsufficient, "insufficient treasury funds for the matching pool", map[string]interface{}{
+			"balance":       qm.governanceState.Treasury.Balance,
+			"matching_pool": round.MatchingPool,
+		})
+	}
+
+	now := time.Now().Unix()
+	qm.tokenState.Balances.Sub(opener.String(), uint64(tx.Fee))
+	qm.governanceState.Treasury.Balance -= round.MatchingPool
+	round.Status = QFRoundStatusOpen
+	round.OpenedAt = now
+	round.ClosesAt = now + round.ContributionPeriod
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// ProcessQFContributeTx sends a contribution to a project of an open round
+func (qm *QFManager) ProcessQFContributeTx(tx *QFContributeTx, contributor crypto.PublicKey) error {
+	round, exists := qm.rounds[tx.RoundID]
+	if !exists {
+		return ErrQFRoundNotFoundError
+	}
+	if round.StatusAt(time.Now().Unix()) != QFRoundStatusOpen {
+		return NewDAOError(ErrVotingClosed, "round is not taking contributions", nil)
+	}
+	if int(tx.Project) >= len(round.Projects) {
+		return NewDAOError(ErrInvalidProposal, "project does not exist", nil)
+	}
+
+	project := round.Projects[tx.Project]
+	contributorStr := contributor.String()
+	if project.Recipient.String() == contributorStr {
+		return NewDAOError(ErrUnauthorized, "projects cannot contribute to themselves", nil)
+	}
+
+	qm.tokenState.Balances.Sub(contributorStr, uint64(tx.Fee)+tx.Amount)
+	qm.tokenState.Balances.Add(project.Recipient.String(), tx.Amount)
+	project.Contributions[contributorStr] += tx.Amount
+	project.Raised += tx.Amount
+
+	return nil
+}
+
+// ProcessQFPayoutTx pays the matching of a closed round to its projects and
+// returns what is left of the pool to the treasury. Only the round's
+// multisig account can execute the payout.
+func (qm *QFManager) ProcessQFPayoutTx(tx *QFPayoutTx, payer crypto.PublicKey) error {
+	round, exists := qm.rounds[tx.RoundID]
+	if !exists {
+		return ErrQFRoundNotFoundError
+	}
+	if MultisigAccountKey(round.Multisig).String() != payer.String() {
+		return NewDAOError(ErrUnauthorized, "only the round's multisig can execute the payout", nil)
+	}
+
+	now := time.Now().Unix()
+	if round.StatusAt(now) != QFRoundStatusTallying {
+		return NewDAOError(ErrInvalidProposal, "round is not awaiting its payout", nil)
+	}
+
+	qm.tokenState.Balances.Sub(payer.String(), uint64(tx.Fee))
+
+	matched := uint64(0)
+	for i, matching := range round.Matching() {
+		project := round.Projects[i]
+		project.Matching = matching
+		qm.tokenState.Balances.Add(project.Recipient.String(), matching)
+		matched += matching
+	}
+
+	round.Matched = matched
+	round.Returned = round.MatchingPool - matched
+	qm.governanceState.Treasury.Balance += round.Returned
+	round.Status = QFRoundStatusPaid
+	round.PaidAt = now
+
+	return nil
+}
+
+// StatusOf returns the stage of a round at time now. Rounds under vote are
+// rejected once their proposal fails.
+func (qm *QFManager) StatusOf(round *QFRound, now int64) QFRoundStatus {
+	if round.Status != QFRoundStatusProposed {
+		return round.StatusAt(now)
+	}
+
+	if proposal, exists := qm.governanceState.Proposal(round.ID); exists {
+		switch proposal.Status {
+		case ProposalStatusRejected, ProposalStatusCancelled:
+			return QFRoundStatusRejected
+		}
+	}
+	return round.Status
+}
+
+// Recipients returns the addresses of the projects of a round
+func (qm *QFManager) Recipients(id types.Hash) []string {
+	round, exists := qm.rounds[id]
+	if !exists {
+		return nil
+	}
+
+	recipients := make([]string, len(round.Projects))
+	for i, project := range round.Projects {
+		recipients[i] = project.Recipient.String()
+	}
+	return recipients
+}
+
+// GetRound returns a round
+func (qm *QFManager) GetRound(id types.Hash) (*QFRound, bool) {
+	round, exists := qm.rounds[id]
+	return round, exists
+}
+
+// ListRounds returns the rounds, optionally only those of a stage, newest
+// first
+func (qm *QFManager) ListRounds(status QFRoundStatus) []*QFRound {
+	now := time.Now().Unix()
+	rounds := make([]*QFRound, 0, len(qm.rounds))
+	for _, round := range qm.rounds {
+		if status != "" && qm.StatusOf(round, now) != status {
+			continue
+		}
+		rounds = append(rounds, round)
+	}
+
+	sort.Slice(rounds, func(i, j int) bool {
+		if rounds[i].CreatedAt != rounds[j].CreatedAt {
+			return rounds[i].CreatedAt > rounds[j].CreatedAt
+		}
+		return rounds[i].ID.String() < rounds[j].ID.String()
+	})
+	return rounds
+}
@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQFRound_Matching(t *testing.T) {
+	contributions := func(amounts ...uint64) map[string]uint64 {
+		result := make(map[string]uint64)
+		for i, amount := range amounts {
+			result[string(rune('a'+i))] = amount
+		}
+		return result
+	}
+
+	// Many small contributors beat one large one, a lone contributor gets
+	// no matching
+	round := &QFRound{
+		MatchingPool: 1000000,
+		Dampening:    1 << 40,
+		Projects: []*QFProject{
+			{Contributions: contributions(1, 1, 1, 1)},
+			{Contributions: map[string]uint64{"whale": 100}},
+		},
+	}
+	matching := round.Matching()
+	assert.InDelta(t, 12, float64(matching[0]), 1)
+	assert.Equal(t, uint64(0), matching[1])
+
+	// Matching above the pool is scaled down to it
+	round.MatchingPool = 6
+	matching = round.Matching()
+	assert.Equal(t, uint64(6), matching[0]+matching[1])
+
+	// A pair backing many projects together is dampened on each of them
+	independent := &QFRound{
+		MatchingPool: 1000,
+		Projects: []*QFProject{
+			{Contributions: map[string]uint64{"a": 100, "b": 100}},
+			{Contributions: map[string]uint64{"c": 100, "d": 100}},
+		},
+	}
+	colluding := &QFRound{
+		MatchingPool: 1000,
+		Projects: []*QFProject{
+			{Contributions: map[string]uint64{"a": 100, "b": 100}},
+			{Contributions: map[string]uint64{"a": 100, "b": 100}},
+		},
+	}
+	assert.Less(t, colluding.Matching()[0], independent.Matching()[0])
+
+	// Nothing to match
+	assert.Equal(t, []uint64{0}, (&QFRound{MatchingPool: 10, Projects: []*QFProject{{}}}).Matching())
+}
+
+func TestQFRound_Lifecycle(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	owner := crypto.GeneratePrivateKey().PublicKey()
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	projectA := crypto.GeneratePrivateKey().PublicKey()
+	projectB := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		owner.String(): 10000,
+		alice.String(): 1000,
+		bob.String():   1000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 20000
+
+	multisigID := types.Hash{0x3F}
+	require.NoError(t, dao.ProcessDAOTransaction(&MultisigCreateTx{Fee: 10, Name: "treasury", Owners: []crypto.PublicKey{owner}, Threshold: 1}, owner, multisigID))
+	multisig := MultisigAccountKey(multisigID)
+	require.NoError(t, dao.ProcessDAOTransaction(&TokenTransferTx{Fee: 1, Recipient: multisig, Amount: 100}, owner, types.Hash{0x40}))
+
+	now := time.Now().Unix()
+	propose := &QFRoundProposalTx{
+		Fee:   200,
+		Title: "Public goods Q1",
+		Projects: []QFProjectSpec{
+			{Name: "Explorer", Recipient: projectA},
+			{Name: "Docs", Recipient: projectB},
+		},
+		MatchingPool:       5000,
+		ContributionPeriod: 3600,
+		Multisig:           multisigID,
+		VotingType:         VotingTypeSimple,
+		StartTime:          now + 60,
+		EndTime:            now + 7*86400,
+		Threshold:          5100,
+	}
+	roundID := types.Hash{0x41}
+
+	// The payout multisig must exist
+	unknown := *propose
+	unknown.Multisig = types.Hash{0xEE}
+	assert.Error(t, dao.ProcessDAOTransaction(&unknown, owner, types.Hash{0x42}))
+	duplicate := *propose
+	duplicate.Projects = []QFProjectSpec{{Name: "A", Recipient: projectA}, {Name: "B", Recipient: projectA}}
+	assert.Error(t, dao.ProcessDAOTransaction(&duplicate, owner, types.Hash{0x43}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(propose, owner, roundID))
+	round, exists := dao.GetQFRound(roundID)
+	require.True(t, exists)
+	assert.Equal(t, QFRoundStatusProposed, round.Status)
+
+	// Contributions wait for the round to open, which waits for the vote
+	contribute := &QFContributeTx{Fee: 5, RoundID: roundID, Project: 0, Amount: 100}
+	assert.Error(t, dao.ProcessDAOTransaction(contribute, alice, types.Hash{0x44}))
+	open := &QFRoundOpenTx{Fee: 100, ProposalID: roundID}
+	assert.Error(t, dao.ProcessDAOTransaction(open, owner, types.Hash{0x45}))
+
+	proposal, err := dao.GetProposal(roundID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(open, owner, types.Hash{0x46}))
+	assert.Equal(t, QFRoundStatusOpen, round.Status)
+	assert.Equal(t, uint64(15000), dao.GovernanceState.Treasury.Balance)
+
+	require.NoError(t, dao.ProcessDAOTransaction(contribute, alice, types.Hash{0x47}))
+	require.NoError(t, dao.ProcessDAOTransaction(&QFContributeTx{Fee: 5, RoundID: roundID, Project: 0, Amount: 400}, bob, types.Hash{0x48}))
+	require.NoError(t, dao.ProcessDAOTransaction(&QFContributeTx{Fee: 5, RoundID: roundID, Project: 1, Amount: 300}, bob, types.Hash{0x49}))
+	assert.Equal(t, uint64(895), dao.TokenState.Balances.Get(alice.String()))
+	assert.Equal(t, uint64(500), dao.TokenState.Balances.Get(projectA.String()))
+	assert.Equal(t, uint64(500), round.Projects[0].Raised)
+
+	// Contributions are bounded by the balance and go to existing projects
+	assert.Error(t, dao.ProcessDAOTransaction(&QFContributeTx{Fee: 5, RoundID: roundID, Amount: 5000}, alice, types.Hash{0x4A}))
+	assert.Error(t, dao.ProcessDAOTransaction(&QFContributeTx{Fee: 5, RoundID: roundID, Project: 7, Amount: 1}, alice, types.Hash{0x4B}))
+
+	// The payout waits for the round to close and comes from its multisig
+	payout := &MultisigSubmitTx{Fee: 10, MultisigID: multisigID, Tx: &QFPayoutTx{Fee: 10, RoundID: roundID}}
+	assert.Error(t, dao.ProcessDAOTransaction(payout, owner, types.Hash{0x4C}))
+	round.ClosesAt = time.Now().Unix() - 1
+	assert.Equal(t, QFRoundStatusTallying, dao.QFManager.StatusOf(round, time.Now().Unix()))
+	assert.Error(t, dao.ProcessDAOTransaction(&QFPayoutTx{Fee: 10, RoundID: roundID}, owner, types.Hash{0x4D}))
+	assert.Error(t, dao.ProcessDAOTransaction(&QFContributeTx{Fee: 5, RoundID: roundID, Amount: 1}, alice, types.Hash{0x4E}))
+
+	require.NoError(t, dao.ProcessDAOTransaction(payout, owner, types.Hash{0x4F}))
+	assert.Equal(t, QFRoundStatusPaid, round.Status)
+	// Only the project with two contributors is matched: 2*sqrt(100*400)
+	// dampened by 5000/(5000+200)
+	assert.Equal(t, uint64(384), round.Projects[0].Matching)
+	assert.Equal(t, uint64(0), round.Projects[1].Matching)
+	assert.Equal(t, uint64(884), dao.TokenState.Balances.Get(projectA.String()))
+	assert.Equal(t, uint64(5000-384), round.Returned)
+	assert.Equal(t, uint64(15000+5000-384), dao.GovernanceState.Treasury.Balance)
+
+	assert.Len(t, dao.ListQFRounds(QFRoundStatusPaid), 1)
+	assert.Empty(t, dao.ListQFRounds(QFRoundStatusOpen))
+}
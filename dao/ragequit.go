@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"math/bits"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// RageQuitPreview is what a member would receive for burning tokens
+type RageQuitPreview struct {
+	Amount       uint64   `json:"amount"`
+	Share        uint64   `json:"share"`
+	Unrestricted uint64   `json:"unrestricted"` // Treasury balance not committed to pending transactions
+	TotalSupply  uint64   `json:"total_supply"`
+	OpenVotes    []string `json:"open_votes"` // Undecided proposals blocking the exit
+}
+
+// UnrestrictedBalance returns the treasury balance not committed to pending
+// treasury transactions that can still execute
+func (ts *TreasuryState) UnrestrictedBalance(now int64) uint64 {
+	var committed uint64
+	for _, tx := range ts.Transactions {
+		if !tx.Executed && tx.ExpiresAt > now {
+			committed += tx.Amount
+		}
+	}
+	if committed >= ts.Balance {
+		return 0
+	}
+	return ts.Balance - committed
+}
+
+// OpenVotes returns the pending and active proposals member voted on
+func (gs *GovernanceState) OpenVotes(member string) []types.Hash {
+	var open []types.Hash
+	index, indexed := gs.Members.Lookup(member)
+	if !indexed {
+		return open
+	}
+	for proposalID, voters := range gs.Voters {
+		if !voters.Contains(index) {
+			continue
+		}
+		if proposal, exists := gs.Proposal(proposalID); exists &&
+			(proposal.Status == ProposalStatusPending || proposal.Status == ProposalStatusActive) {
+			open = append(open, proposalID)
+		}
+	}
+
+	sort.Slice(open, func(i, j int) bool { return open[i].String() < open[j].String() })
+	return open
+}
+
+// rageQuitShare returns the part of unrestricted owed for amount of supply
+// tokens, rounded down
+func rageQuitShare(unrestricted, amount, supply uint64) uint64 {
+	if supply == 0 || amount == 0 {
+		return 0
+	}
+	if amount >= supply {
+		return unrestricted
+	}
+	hi, lo := bits.Mul64(unrestricted, amount)
+	share, _ := bits.Div64(hi, lo, supply)
+	return share
+}
+
+// ProcessRageQuitTx burns the member's tokens and pays out their share of
+// the unrestricted treasury. Members burning all their tokens exit the DAO.
+func (p *DAOProcessor) ProcessRageQuitTx(tx *RageQuitTx, member crypto.PublicKey) error {
+	if err := p.validator.ValidateRageQuitTx(tx, member); err != nil {
+		return err
+	}
+
+	memberStr := member.String()
+	treasury := p.governanceState.Treasury
+	share := rageQuitShare(treasury.UnrestrictedBalance(time.Now().Unix()), tx.Amount, p.tokenState.TotalSupply)
+
+	if err := p.tokenState.Burn(memberStr, tx.Amount); err != nil {
+		return err
+	}
+	p.tokenState.Balances.Sub(memberStr, uint64(tx.Fee))
+	exiting := p.tokenState.Balances.Get(memberStr) == 0
+
+	treasury.Balance -= share
+	p.tokenState.Balances.Add(memberStr, share)
+
+	p.updateTokenHolderRecord(memberStr)
+	if holder, exists := p.governanceState.TokenHolders[memberStr]; exists && exiting {
+		holder.Status = MembershipStatusExited
+	}
+
+	return nil
+}
+
+// PreviewRageQuit returns what member would receive for burning amount
+// tokens now
+func (d *DAO) PreviewRageQuit(member crypto.PublicKey, amount uint64) *RageQuitPreview {
+	unrestricted := d.GovernanceState.Treasury.UnrestrictedBalance(time.Now().Unix())
+	openVotes := make([]string, 0)
+	for _, proposalID := range d.GovernanceState.OpenVotes(member.String()) {
+		openVotes = append(openVotes, proposalID.String())
+	}
+
+	return &RageQuitPreview{
+		Amount:       amount,
+		Share:        rageQuitShare(unrestricted, amount, d.TokenState.TotalSupply),
+		Unrestricted: unrestricted,
+		TotalSupply:  d.TokenState.TotalSupply,
+		OpenVotes:    openVotes,
+	}
+}
@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRageQuit_ProRataShare(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	member := crypto.GeneratePrivateKey().PublicKey()
+	other := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		member.String(): 2500,
+		other.String():  7500,
+	}))
+	dao.GovernanceState.Treasury.Balance = 12000
+
+	// Funds committed to pending treasury transactions are not shared out
+	now := time.Now().Unix()
+	dao.GovernanceState.Treasury.Transactions[types.Hash{0x01}] = &PendingTx{Amount: 2000, ExpiresAt: now + 3600}
+	dao.GovernanceState.Treasury.Transactions[types.Hash{0x02}] = &PendingTx{Amount: 500, ExpiresAt: now - 1}
+	dao.GovernanceState.Treasury.Transactions[types.Hash{0x03}] = &PendingTx{Amount: 700, ExpiresAt: now + 3600, Executed: true}
+	assert.Equal(t, uint64(10000), dao.GovernanceState.Treasury.UnrestrictedBalance(now))
+
+	preview := dao.PreviewRageQuit(member, 1000)
+	assert.Equal(t, uint64(1000), preview.Share)
+	assert.Empty(t, preview.OpenVotes)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&RageQuitTx{Fee: 10, Amount: 1000}, member, types.Hash{0x10}))
+	assert.Equal(t, uint64(9000), dao.TokenState.TotalSupply)
+	assert.Equal(t, uint64(11000), dao.GovernanceState.Treasury.Balance)
+	assert.Equal(t, uint64(2500-1000-10+1000), dao.TokenState.Balances.Get(member.String()))
+
+	status, _ := dao.GetMembershipStatus(member)
+	assert.Equal(t, MembershipStatusActive, status)
+
+	assert.Error(t, dao.ProcessDAOTransaction(&RageQuitTx{Amount: 0}, member, types.Hash{0x11}))
+	assert.Error(t, dao.ProcessDAOTransaction(&RageQuitTx{Amount: 5000}, member, types.Hash{0x12}))
+}
+
+func TestRageQuit_BlockedByOpenVotes(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	member := crypto.GeneratePrivateKey().PublicKey()
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		member.String():  1000,
+		creator.String(): 9000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 5000
+
+	proposalID := types.Hash{0x01}
+	require.NoError(t, dao.ProcessDAOTransaction(&ProposalTx{
+		Fee: 100, Title: "Signal", Description: "Roadmap", ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple,
+		StartTime: 0, EndTime: 1 << 40, Threshold: 5100,
+	}, creator, proposalID))
+	require.NoError(t, dao.ProcessDAOTransaction(&VoteTx{Fee: 10, ProposalID: proposalID, Choice: VoteChoiceYes, Weight: 100}, member, types.Hash{0x02}))
+
+	err := dao.ProcessDAOTransaction(&RageQuitTx{Amount: 500}, member, types.Hash{0x03})
+	require.Error(t, err)
+	assert.Equal(t, []string{proposalID.String()}, dao.PreviewRageQuit(member, 500).OpenVotes)
+
+	// Once the proposal is decided the member can leave with everything
+	dao.GovernanceState.Proposals[proposalID].Status = ProposalStatusRejected
+	balance := dao.TokenState.Balances.Get(member.String())
+	share := dao.PreviewRageQuit(member, balance).Share
+	require.NoError(t, dao.ProcessDAOTransaction(&RageQuitTx{Amount: balance}, member, types.Hash{0x04}))
+	assert.Equal(t, share, dao.TokenState.Balances.Get(member.String()))
+
+	status, _ := dao.GetMembershipStatus(member)
+	assert.Equal(t, MembershipStatusExited, status)
+}
+
+func TestRageQuit_Share(t *testing.T) {
+	assert.Equal(t, uint64(0), rageQuitShare(1000, 10, 0))
+	assert.Equal(t, uint64(333), rageQuitShare(1000, 1, 3))
+	assert.Equal(t, uint64(1000), rageQuitShare(1000, 5, 5))
+
+	// Large balances do not overflow
+	assert.Equal(t, uint64(1<<62), rageQuitShare(1<<63, 1<<62, 1<<63))
+}
@@ -0,0 +1,309 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// DefaultDAOID is the ID of the DAO a node starts with
+const DefaultDAOID = "default"
+
+// daoIDPattern is the form of hosted DAO IDs, which appear in API paths
+var daoIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,30}[a-z0-9]$`)
+
+// ValidDAOID reports whether id can identify a hosted DAO
+func ValidDAOID(id string) bool {
+	return daoIDPattern.MatchString(id)
+}
+
+// HostedDAOConfig is the configuration a hosted DAO is created with
+type HostedDAOConfig struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	TokenSymbol string `json:"token_symbol"`
+	TokenName   string `json:"token_name"`
+	Decimals    uint8  `json:"decimals"`
+}
+
+// HostedDAO is a DAO hosted by a registry
+type HostedDAO struct {
+	ID        string          `json:"id"`
+	Config    HostedDAOConfig `json:"config"`
+	Creator   string          `json:"creator,omitempty"`
+	CreatedAt int64           `json:"created_at,omitempty"`
+	DAO       *DAO            `json:"-"`
+}
+
+// DAORegistry hosts independent DAOs on one node. Every hosted DAO has its
+// own token, treasury and governance state. The registry is the chain's
+// state machine: transactions for hosted DAOs arrive wrapped in a
+// HostedDAOTx, everything else applies to the default DAO, and the state of
+// hosted DAOs is committed under the "daos/<id>/" keys of the state root.
+type DAORegistry struct {
+	root      *DAO
+	hosted    map[string]*HostedDAO
+	submitter ChainSubmitter
+	// createdListeners are told about every DAO created in the registry
+	createdListeners []func(*HostedDAO)
+	mu               sync.RWMutex
+}
+
+var (
+	_ StateMachine       = (*DAORegistry)(nil)
+	_ StatePruner        = (*DAORegistry)(nil)
+	_ ParameterSource    = (*DAORegistry)(nil)
+	_ FeeCollector       = (*DAORegistry)(nil)
+	_ ValidatorSetSource = (*DAORegistry)(nil)
+)
+
+// NewDAORegistry creates a registry with root as its default DAO. The
+// default DAO collects DAO creation fees, and keeps the chain's parameters,
+// fee sharing and validator set.
+func NewDAORegistry(root *DAO) *DAORegistry {
+	r := &DAORegistry{
+		root:   root,
+		hosted: make(map[string]*HostedDAO),
+	}
+	root.registry = r
+	return r
+}
+
+// Default returns the default DAO
+func (r *DAORegistry) Default() *DAO {
+	return r.root
+}
+
+// Get returns the DAO with the given ID, including the default one
+func (r *DAORegistry) Get(id string) (*HostedDAO, bool) {
+	if id == DefaultDAOID {
+		return r.defaultDAO(), true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hosted, exists := r.hosted[id]
+	return hosted, exists
+}
+
+// List returns the default DAO followed by the hosted DAOs by ID
+func (r *DAORegistry) List() []*HostedDAO {
+	return append([]*HostedDAO{r.defaultDAO()}, r.hostedDAOs()...)
+}
+
+// OnDAOCreated calls fn after every DAO created in the registry. fn runs on
+// the block processing path and must not block.
+func (r *DAORegistry) OnDAOCreated(fn func(*HostedDAO)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.createdListeners = append(r.createdListeners, fn)
+}
+
+// SetChainSubmitter routes the direct submissions of every DAO in the
+// registry through the chain
+func (r *DAORegistry) SetChainSubmitter(submitter ChainSubmitter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.submitter = submitter
+	r.root.SetChainSubmitter(submitter)
+	for id, hosted := range r.hosted {
+		hosted.DAO.SetChainSubmitter(hostedSubmitter{daoID: id, submitter: submitter})
+	}
+}
+
+// ApplyDAOTransaction applies a transaction to the DAO it is for
+func (r *DAORegistry) ApplyDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash, height uint32) error {
+	tx, ok := txInner.(*HostedDAOTx)
+	if !ok {
+		return r.root.ApplyDAOTransaction(txInner, from, txHash, height)
+	}
+
+	hosted, exists := r.Get(tx.DAOID)
+	if !exists {
+		return ErrDAONotFoundError
+	}
+	return hosted.DAO.ApplyDAOTransaction(tx.Tx, from, txHash, height)
+}
+
+// StateLeaves returns the leaves of the default DAO, and for every hosted
+// DAO a "daos/<id>" leaf with its configuration followed by its own leaves
+// under "daos/<id>/"
+func (r *DAORegistry) StateLeaves() ([]StateLeaf, error) {
+	leaves, err := r.root.StateLeaves()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hosted := range r.hostedDAOs() {
+		key := HostedDAOStateKey(hosted.ID)
+		encoded, err := json.Marshal(hosted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode state leaf %s: %w", key, err)
+		}
+		leaves = append(leaves, StateLeaf{Key: key, Value: encoded})
+
+		hostedLeaves, err := hosted.DAO.StateLeaves()
+		if err != nil {
+			return nil, err
+		}
+		for _, leaf := range hostedLeaves {
+			leaves = append(leaves, StateLeaf{Key: key + "/" + leaf.Key, Value: leaf.Value})
+		}
+	}
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].Key < leaves[j].Key
+	})
+
+	return leaves, nil
+}
+
+// HostedDAOStateKey returns the state key of a hosted DAO, which prefixes
+// the keys of its state
+func HostedDAOStateKey(id string) string {
+	return "daos/" + id
+}
+
+// PruneState prunes the state of every DAO in the registry
+func (r *DAORegistry) PruneState(blockTime int64) int {
+	pruned := r.root.PruneState(blockTime)
+	for _, hosted := range r.hostedDAOs() {
+		pruned += hosted.DAO.PruneState(blockTime)
+	}
+	return pruned
+}
+
+// GetParameterConfig returns the parameters of the default DAO
+func (r *DAORegistry) GetParameterConfig() *ParameterConfig {
+	return r.root.GetParameterConfig()
+}
+
+// CollectBlockFees shares the fees of a block through the default DAO.
+// Fees of hosted DAO transactions are paid in their own tokens and are not
+// part of them, hosted DAOs only see the block height.
+func (r *DAORegistry) CollectBlockFees(producer crypto.PublicKey, fees uint64, height uint32) {
+	r.root.CollectBlockFees(producer, fees, height)
+	for _, hosted := range r.hostedDAOs() {
+		hosted.DAO.ParameterManager.observeHeight(height)
+	}
+}
+
+// ValidatorSet returns the validator set the default DAO governs
+func (r *DAORegistry) ValidatorSet() []ValidatorPower {
+	return r.root.ValidatorSet()
+}
+
+// MaintenanceJobs returns the maintenance jobs of the default DAO, each of
+// which also runs for the hosted DAOs
+func (r *DAORegistry) MaintenanceJobs(guard func(fn func())) map[string]JobFunc {
+	jobs := r.root.MaintenanceJobs(guard)
+
+	for name, job := range jobs {
+		name, job := name, job
+		jobs[name] = func(now time.Time) error {
+			err := job(now)
+			for _, hosted := range r.hostedDAOs() {
+				if hostedErr := hosted.DAO.MaintenanceJobs(guard)[name](now); hostedErr != nil && err == nil {
+					err = fmt.Errorf("DAO %s: %w", hosted.ID, hostedErr)
+				}
+			}
+			return err
+		}
+	}
+
+	return jobs
+}
+
+// create adds the DAO a creation transaction describes
+func (r *DAORegistry) create(tx *DAOCreateTx, creator crypto.PublicKey) error {
+	r.mu.Lock()
+
+	if _, exists := r.hosted[tx.DAOID]; exists || tx.DAOID == DefaultDAOID {
+		r.mu.Unlock()
+		return NewDAOError(ErrInvalidDAO, "DAO ID is already taken", map[string]interface{}{
+			"dao_id": tx.DAOID,
+		})
+	}
+
+	d, err := NewDAOFromGenesis(&tx.Genesis)
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	d.IPFSClient = r.root.IPFSClient
+	if r.submitter != nil {
+		d.SetChainSubmitter(hostedSubmitter{daoID: tx.DAOID, submitter: r.submitter})
+	}
+
+	hosted := &HostedDAO{
+		ID: tx.DAOID,
+		Config: HostedDAOConfig{
+			Name:        tx.Name,
+			Description: tx.Description,
+			TokenSymbol: tx.Genesis.Token.Symbol,
+			TokenName:   tx.Genesis.Token.Name,
+			Decimals:    tx.Genesis.Token.Decimals,
+		},
+		Creator:   creator.String(),
+		CreatedAt: time.Now().Unix(),
+		DAO:       d,
+	}
+	r.hosted[tx.DAOID] = hosted
+	listeners := append([]func(*HostedDAO){}, r.createdListeners...)
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(hosted)
+	}
+
+	return nil
+}
+
+// defaultDAO describes the default DAO
+func (r *DAORegistry) defaultDAO() *HostedDAO {
+	return &HostedDAO{
+		ID: DefaultDAOID,
+		Config: HostedDAOConfig{
+			Name:        r.root.TokenState.Name,
+			TokenSymbol: r.root.TokenState.Symbol,
+			TokenName:   r.root.TokenState.Name,
+			Decimals:    r.root.TokenState.Decimals,
+		},
+		DAO: r.root,
+	}
+}
+
+// hostedDAOs returns the hosted DAOs by ID, without the default one
+func (r *DAORegistry) hostedDAOs() []*HostedDAO {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hosted := make([]*HostedDAO, 0, len(r.hosted))
+	for _, h := range r.hosted {
+		hosted = append(hosted, h)
+	}
+	sort.Slice(hosted, func(i, j int) bool {
+		return hosted[i].ID < hosted[j].ID
+	})
+	return hosted
+}
+
+// hostedSubmitter submits the transactions of a hosted DAO to the chain
+// wrapped for the registry
+type hostedSubmitter struct {
+	daoID     string
+	submitter ChainSubmitter
+}
+
+func (s hostedSubmitter) SubmitDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash) error {
+	return s.submitter.SubmitDAOTransaction(&HostedDAOTx{DAOID: s.daoID, Tx: txInner}, from, txHash)
+}
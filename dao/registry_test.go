@@ -0,0 +1,135 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSubmitter keeps the transactions submitted to it
+type recordingSubmitter struct {
+	submitted []interface{}
+}
+
+func (s *recordingSubmitter) SubmitDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash) error {
+	s.submitted = append(s.submitted, txInner)
+	return nil
+}
+
+func TestDAORegistry(t *testing.T) {
+	root := NewDAO("GOV", "Governance Token", 18)
+	registry := NewDAORegistry(root)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	root.TokenState.Balances.Set(creator.String(), 1000)
+
+	var created []string
+	registry.OnDAOCreated(func(hosted *HostedDAO) {
+		created = append(created, hosted.ID)
+	})
+
+	createTx := &DAOCreateTx{
+		Fee:   100,
+		DAOID: "acme",
+		Name:  "Acme Collective",
+		Genesis: Genesis{
+			Token:       GenesisToken{Symbol: "ACME", Name: "Acme Token", Decimals: 6},
+			Allocations: []GenesisAllocation{{Address: creator.String(), Amount: 5000}},
+			Treasury:    GenesisTreasury{Signers: []string{creator.String()}, RequiredSigs: 1},
+		},
+	}
+	require.NoError(t, registry.ApplyDAOTransaction(createTx, creator, types.Hash{0x01}, 1))
+	assert.Equal(t, []string{"acme"}, created)
+	assert.Equal(t, uint64(900), root.GetTokenBalance(creator))
+
+	// The hosted DAO starts in the state of its genesis document
+	hosted, exists := registry.Get("acme")
+	require.True(t, exists)
+	assert.Equal(t, "Acme Collective", hosted.Config.Name)
+	assert.Equal(t, creator.String(), hosted.Creator)
+	assert.Equal(t, "ACME", hosted.DAO.TokenState.Symbol)
+	assert.Equal(t, uint64(5000), hosted.DAO.GetTokenBalance(creator))
+	assert.Equal(t, []crypto.PublicKey{creator}, hosted.DAO.GovernanceState.Treasury.Signers)
+
+	ids := make([]string, 0)
+	for _, h := range registry.List() {
+		ids = append(ids, h.ID)
+	}
+	assert.Equal(t, []string{DefaultDAOID, "acme"}, ids)
+
+	// IDs are unique and well formed
+	createTx.DAOID = "acme"
+	assert.Error(t, registry.ApplyDAOTransaction(createTx, creator, types.Hash{0x02}, 1))
+	createTx.DAOID = DefaultDAOID
+	assert.Error(t, registry.ApplyDAOTransaction(createTx, creator, types.Hash{0x03}, 1))
+	createTx.DAOID = "Not An ID"
+	assert.Error(t, registry.ApplyDAOTransaction(createTx, creator, types.Hash{0x04}, 1))
+	assert.Equal(t, uint64(900), root.GetTokenBalance(creator))
+
+	// Wrapped transactions apply to the hosted DAO only
+	rootLeaves, err := root.StateLeaves()
+	require.NoError(t, err)
+	transfer := &HostedDAOTx{DAOID: "acme", Tx: &TokenTransferTx{Fee: 5, Recipient: alice, Amount: 300}}
+	require.NoError(t, registry.ApplyDAOTransaction(transfer, creator, types.Hash{0x05}, 2))
+	assert.Equal(t, uint64(300), hosted.DAO.GetTokenBalance(alice))
+	assert.Zero(t, root.GetTokenBalance(alice))
+	unchanged, err := root.StateLeaves()
+	require.NoError(t, err)
+	assert.Equal(t, StateRootFromLeaves(rootLeaves), StateRootFromLeaves(unchanged))
+
+	err = registry.ApplyDAOTransaction(&HostedDAOTx{DAOID: "unknown", Tx: &TokenTransferTx{Recipient: alice, Amount: 1}}, creator, types.Hash{0x06}, 2)
+	require.Error(t, err)
+	assert.Equal(t, ErrDAONotFound, err.(*DAOError).Code)
+
+	// Hosted DAOs cannot create DAOs of their own
+	nested := &HostedDAOTx{DAOID: "acme", Tx: &DAOCreateTx{DAOID: "nested", Name: "Nested", Genesis: createTx.Genesis}}
+	assert.Error(t, registry.ApplyDAOTransaction(nested, creator, types.Hash{0x07}, 2))
+
+	// Hosted state is committed under the DAO's prefix
+	leaves, err := registry.StateLeaves()
+	require.NoError(t, err)
+	keys := make(map[string]bool)
+	for i, leaf := range leaves {
+		keys[leaf.Key] = true
+		if i > 0 {
+			assert.Less(t, leaves[i-1].Key, leaf.Key)
+		}
+	}
+	assert.True(t, keys[HostedDAOStateKey("acme")])
+	assert.True(t, keys[HostedDAOStateKey("acme")+"/"+BalanceStateKey(alice.String())])
+	assert.True(t, keys[BalanceStateKey(creator.String())])
+	assert.False(t, keys[BalanceStateKey(alice.String())])
+
+	// Direct submissions of hosted DAOs reach the chain wrapped
+	submitter := &recordingSubmitter{}
+	registry.SetChainSubmitter(submitter)
+	require.NoError(t, hosted.DAO.ProcessDAOTransaction(&TokenTransferTx{Recipient: alice, Amount: 1}, creator, types.Hash{0x08}))
+	require.NoError(t, root.ProcessDAOTransaction(&TokenTransferTx{Recipient: alice, Amount: 1}, creator, types.Hash{0x09}))
+	require.Len(t, submitter.submitted, 2)
+	wrapped, ok := submitter.submitted[0].(*HostedDAOTx)
+	require.True(t, ok)
+	assert.Equal(t, "acme", wrapped.DAOID)
+	assert.IsType(t, &TokenTransferTx{}, submitter.submitted[1])
+
+	jobs := registry.MaintenanceJobs(nil)
+	for _, name := range MaintenanceJobNames {
+		assert.Contains(t, jobs, name)
+	}
+}
+
+func TestDAOCreateTxWithoutRegistry(t *testing.T) {
+	d := NewDAO("GOV", "Governance Token", 18)
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	d.TokenState.Balances.Set(creator.String(), 1000)
+
+	err := d.ApplyDAOTransaction(&DAOCreateTx{DAOID: "acme", Name: "Acme", Genesis: Genesis{
+		Token:    GenesisToken{Symbol: "ACME", Name: "Acme"},
+		Treasury: GenesisTreasury{Signers: []string{creator.String()}, RequiredSigs: 1},
+	}}, creator, types.Hash{0x01}, 1)
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidDAO, err.(*DAOError).Code)
+}
@@ -103,7 +103,7 @@ func (rs *ReputationSystem) UpdateReputationForVoting(voter crypto.PublicKey, pr
 
 // UpdateReputationForProposalOutcome updates reputation based on proposal outcomes
 func (rs *ReputationSystem) UpdateReputationForProposalOutcome(proposalID types.Hash) {
-	proposal, exists := rs.governanceState.Proposals[proposalID]
+	proposal, exists := rs.governanceState.Proposal(proposalID)
 	if !exists {
 		return
 	}
@@ -311,6 +311,29 @@ func (rs *ReputationSystem) GetReputationConfig() *ReputationConfig {
 func (rs *ReputationSystem) RecalculateAllReputation() {
 	// This is a comprehensive recalculation that can be run periodically
 	// to ensure reputation scores are accurate
+	type activity struct {
+		proposalsCreated, proposalsPassed, proposalsRejected, votesCast int
+	}
+	activities := make(map[string]*activity)
+	activityOf := func(address string) *activity {
+		if activities[address] == nil {
+			activities[address] = &activity{}
+		}
+		return activities[address]
+	}
+	rs.governanceState.EachProposal(func(_ types.Hash, proposal *Proposal, votes map[string]*Vote) bool {
+		creator := activityOf(proposal.Creator.String())
+		creator.proposalsCreated++
+		if proposal.Status == ProposalStatusPassed {
+			creator.proposalsPassed++
+		} else if proposal.Status == ProposalStatusRejected {
+			creator.proposalsRejected++
+		}
+		for voter := range votes {
+			activityOf(voter).votesCast++
+		}
+		return true
+	})
 
 	for addressStr, holder := range rs.governanceState.TokenHolders {
 		// Reset to base reputation
@@ -321,20 +344,10 @@ func (rs *ReputationSystem) RecalculateAllReputation() {
 		holder.Reputation += tokenBonus
 
 		// Count proposals created
-		proposalsCreated := 0
-		proposalsPassed := 0
-		proposalsRejected := 0
-
-		for _, proposal := range rs.governanceState.Proposals {
-			if proposal.Creator.String() == addressStr {
-				proposalsCreated++
-				if proposal.Status == ProposalStatusPassed {
-					proposalsPassed++
-				} else if proposal.Status == ProposalStatusRejected {
-					proposalsRejected++
-				}
-			}
-		}
+		counts := activityOf(addressStr)
+		proposalsCreated := counts.proposalsCreated
+		proposalsPassed := counts.proposalsPassed
+		proposalsRejected := counts.proposalsRejected
 
 		// Add proposal bonuses/penalties
 		holder.Reputation += uint64(proposalsCreated) * rs.config.ProposalCreationBonus
@@ -344,12 +357,7 @@ func (rs *ReputationSystem) RecalculateAllReputation() {
 		}
 
 		// Count votes cast
-		votesCast := 0
-		for _, votes := range rs.governanceState.Votes {
-			if _, voted := votes[addressStr]; voted {
-				votesCast++
-			}
-		}
+		votesCast := counts.votesCast
 
 		// Add voting participation bonus
 		holder.Reputation += uint64(votesCast) * rs.config.VotingParticipation
@@ -383,8 +391,9 @@ func (rs *ReputationSystem) GetUserReputationHistory(user crypto.PublicKey) *Use
 		Events:            make([]*ReputationEvent, 0),
 	}
 
-	// Count proposals
-	for _, proposal := range rs.governanceState.Proposals {
+	// Count proposals, then votes
+	var voteEvents []*ReputationEvent
+	rs.governanceState.EachProposal(func(proposalID types.Hash, proposal *Proposal, votes map[string]*Vote) bool {
 		if proposal.Creator.String() == userStr {
 			eventType := ReputationEventProposalCreated
 			impact := int64(rs.config.ProposalCreationBonus)
@@ -402,19 +411,18 @@ func (rs *ReputationSystem) GetUserReputationHistory(user crypto.PublicKey) *Use
 				ProposalID: &proposal.ID,
 			})
 		}
-	}
 
-	// Count votes
-	for proposalID, votes := range rs.governanceState.Votes {
 		if vote, voted := votes[userStr]; voted {
-			history.Events = append(history.Events, &ReputationEvent{
+			voteEvents = append(voteEvents, &ReputationEvent{
 				Type:       ReputationEventVoteCast,
 				Timestamp:  vote.Timestamp,
 				Impact:     int64(rs.config.VotingParticipation),
 				ProposalID: &proposalID,
 			})
 		}
-	}
+		return true
+	})
+	history.Events = append(history.Events, voteEvents...)
 
 	return history
 }
@@ -0,0 +1,247 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// ProposalResultVersion is the version of the result documents this node
+// publishes
+const ProposalResultVersion = 1
+
+// ProposalResult is the signed record of how a proposal's vote ended,
+// published to IPFS so the result outlives the node that counted it. The
+// voter root is the Merkle root of the proposal's vote state leaves, in the
+// same form the state tree commits them.
+type ProposalResult struct {
+	Version      int            `json:"version"`
+	ProposalID   string         `json:"proposal_id"`
+	Title        string         `json:"title"`
+	Status       ProposalStatus `json:"status"`
+	YesVotes     uint64         `json:"yes_votes"`
+	NoVotes      uint64         `json:"no_votes"`
+	AbstainVotes uint64         `json:"abstain_votes"`
+	TotalVoters  uint64         `json:"total_voters"`
+	Quorum       uint64         `json:"quorum"`
+	Passed       bool           `json:"passed"`
+	VoterRoot    string         `json:"voter_root"`
+	BlockHeight  uint32         `json:"block_height"`
+	FinalizedAt  int64          `json:"finalized_at"`
+	Signer       string         `json:"signer"`
+	Signature    string         `json:"signature"`
+}
+
+// Digest returns the hash the node signs, the document without its signature
+func (r *ProposalResult) Digest() []byte {
+	unsigned := *r
+	unsigned.Signature = ""
+	data, _ := json.Marshal(&unsigned)
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+// Sign sets the result's signer to the key's and signs it
+func (r *ProposalResult) Sign(key crypto.PrivateKey) error {
+	r.Signer = key.PublicKey().String()
+	signature, err := key.Sign(r.Digest())
+	if err != nil {
+		return err
+	}
+
+	sigBytes := make([]byte, 64)
+	signature.R.FillBytes(sigBytes[:32])
+	signature.S.FillBytes(sigBytes[32:])
+	r.Signature = hex.EncodeToString(sigBytes)
+	return nil
+}
+
+// Verify reports whether the result is signed by its signer
+func (r *ProposalResult) Verify() bool {
+	signer, err := crypto.PublicKeyFromHex(r.Signer)
+	if err != nil {
+		return false
+	}
+	sigBytes, err := hex.DecodeString(r.Signature)
+	if err != nil || len(sigBytes) != 64 {
+		return false
+	}
+
+	signature := crypto.Signature{
+		R: new(big.Int).SetBytes(sigBytes[:32]),
+		S: new(big.Int).SetBytes(sigBytes[32:]),
+	}
+	return signature.Verify(signer, r.Digest())
+}
+
+// VoterRoot returns the Merkle root of the vote state leaves of a proposal,
+// ordered by key
+func VoterRoot(proposalID types.Hash, votes map[string]*Vote) (types.Hash, error) {
+	leaves := make([]StateLeaf, 0, len(votes))
+	for voter, vote := range votes {
+		encoded, err := json.Marshal(vote)
+		if err != nil {
+			return types.Hash{}, fmt.Errorf("failed to encode vote of %s: %w", voter, err)
+		}
+		leaves = append(leaves, StateLeaf{Key: VoteStateKey(proposalID, voter), Value: encoded})
+	}
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].Key < leaves[j].Key
+	})
+	return MerkleRoot(leafHashes(leaves)), nil
+}
+
+// resultAnchoring signs and publishes the results of finalized proposals
+type resultAnchoring struct {
+	key    crypto.PrivateKey
+	height func() uint32
+}
+
+// EnableResultAnchoring makes the proposal status job publish a result
+// document signed with key for every proposal whose vote has ended, pin it
+// and record its hash on the proposal. height reports the chain height the
+// results are counted at.
+func (d *DAO) EnableResultAnchoring(key crypto.PrivateKey, height func() uint32) {
+	d.anchoring = &resultAnchoring{key: key, height: height}
+}
+
+// hasFinalResults reports whether a proposal's vote has ended with results
+// to anchor
+func hasFinalResults(proposal *Proposal) bool {
+	switch proposal.Status {
+	case ProposalStatusPassed, ProposalStatusRejected, ProposalStatusExecuted:
+		return proposal.Results != nil
+	}
+	return false
+}
+
+// pendingResult is the unsigned result document of a proposal
+type pendingResult struct {
+	proposalID types.Hash
+	result     *ProposalResult
+}
+
+// pendingResults returns the unsigned result documents of the finalized
+// proposals that have none yet, ordered by proposal ID
+func (d *DAO) pendingResults() []pendingResult {
+	now := time.Now().Unix()
+	var height uint32
+	if d.anchoring.height != nil {
+		height = d.anchoring.height()
+	}
+
+	var results []pendingResult
+	for id, proposal := range d.GovernanceState.Proposals {
+		if !hasFinalResults(proposal) || proposal.ResultHash != (types.Hash{}) {
+			continue
+		}
+
+		root, err := VoterRoot(id, d.GovernanceState.Votes[id])
+		if err != nil {
+			continue
+		}
+		results = append(results, pendingResult{proposalID: id, result: &ProposalResult{
+			Version:      ProposalResultVersion,
+			ProposalID:   id.String(),
+			Title:        proposal.Title,
+			Status:       proposal.Status,
+			YesVotes:     proposal.Results.YesVotes,
+			NoVotes:      proposal.Results.NoVotes,
+			AbstainVotes: proposal.Results.AbstainVotes,
+			TotalVoters:  proposal.Results.TotalVoters,
+			Quorum:       proposal.Results.Quorum,
+			Passed:       proposal.Results.Passed,
+			VoterRoot:    root.String(),
+			BlockHeight:  height,
+			FinalizedAt:  now,
+		}})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].result.ProposalID < results[j].result.ProposalID
+	})
+	return results
+}
+
+// AnchorProposalResults publishes and pins the result documents of the
+// finalized proposals without one and records their hashes, returning the
+// proposals anchored. The documents are built and recorded through guard,
+// like the maintenance jobs, while IPFS is called outside it. Proposals that
+// fail to publish are retried on the next call.
+func (d *DAO) AnchorProposalResults(guard func(fn func())) ([]types.Hash, error) {
+	if d.anchoring == nil {
+		return nil, nil
+	}
+	if guard == nil {
+		guard = func(fn func()) { fn() }
+	}
+
+	var pending []pendingResult
+	guard(func() { pending = d.pendingResults() })
+
+	var anchored []types.Hash
+	var firstErr error
+	for _, p := range pending {
+		hash, err := d.publishResult(p.result)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to anchor result of proposal %s: %w", p.result.ProposalID, err)
+			}
+			continue
+		}
+
+		guard(func() {
+			if proposal, exists := d.GovernanceState.Proposals[p.proposalID]; exists && proposal.ResultHash == (types.Hash{}) {
+				proposal.ResultHash = hash
+				anchored = append(anchored, p.proposalID)
+			}
+		})
+	}
+	return anchored, firstErr
+}
+
+// publishResult signs, uploads and pins a result document
+func (d *DAO) publishResult(result *ProposalResult) (types.Hash, error) {
+	if err := result.Sign(d.anchoring.key); err != nil {
+		return types.Hash{}, fmt.Errorf("failed to sign result: %w", err)
+	}
+	hash, err := d.IPFSClient.UploadProposalResult(result)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	if err := d.IPFSClient.PinContent(hash); err != nil {
+		return types.Hash{}, fmt.Errorf("failed to pin result: %w", err)
+	}
+	return hash, nil
+}
+
+// GetProposalResult retrieves the anchored result document of a proposal
+// and checks its signature
+func (d *DAO) GetProposalResult(proposalID types.Hash) (*ProposalResult, error) {
+	proposal, exists := d.GovernanceState.Proposal(proposalID)
+	if !exists {
+		return nil, ErrProposalNotFoundError
+	}
+	if proposal.ResultHash == (types.Hash{}) {
+		return nil, NewDAOError(ErrInvalidProposal, "proposal result is not anchored", nil)
+	}
+
+	result, err := d.IPFSClient.RetrieveProposalResult(proposal.ResultHash)
+	if err != nil {
+		return nil, err
+	}
+	if result.ProposalID != proposalID.String() || !result.Verify() {
+		return nil, NewDAOError(ErrInvalidProposal, "proposal result is not signed for the proposal", map[string]interface{}{
+			"hash": proposal.ResultHash.String(),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoterRoot(t *testing.T) {
+	proposalID := types.Hash{0x01}
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	votes := map[string]*Vote{
+		alice.String(): {Voter: alice, Choice: VoteChoiceYes, Weight: 100},
+		bob.String():   {Voter: bob, Choice: VoteChoiceNo, Weight: 50},
+	}
+
+	root, err := VoterRoot(proposalID, votes)
+	require.NoError(t, err)
+	again, err := VoterRoot(proposalID, votes)
+	require.NoError(t, err)
+	assert.Equal(t, root, again)
+
+	// The root commits to every vote
+	votes[bob.String()].Weight = 51
+	changed, err := VoterRoot(proposalID, votes)
+	require.NoError(t, err)
+	assert.NotEqual(t, root, changed)
+}
+
+func TestDAO_AnchorProposalResults(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	objects, objectServer := newTestObjectStore(t)
+	dao.IPFSClient = NewIPFSClientWithStore(NewS3ContentStore(MirrorConfig{Endpoint: objectServer.URL, Bucket: "dao-content"}))
+
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	passed, running, failed := types.Hash{0x01}, types.Hash{0x02}, types.Hash{0x03}
+	dao.GovernanceState.Proposals[passed] = &Proposal{
+		ID:      passed,
+		Title:   "Fund audits",
+		Status:  ProposalStatusPassed,
+		Results: &VoteResults{YesVotes: 100, TotalVoters: 1, Quorum: 50, Passed: true},
+	}
+	dao.GovernanceState.Proposals[running] = &Proposal{ID: running, Status: ProposalStatusActive}
+	dao.GovernanceState.Votes[passed] = map[string]*Vote{
+		voter.String(): {Voter: voter, Choice: VoteChoiceYes, Weight: 100},
+	}
+
+	// Nothing happens until a key is set
+	anchored, err := dao.AnchorProposalResults(nil)
+	require.NoError(t, err)
+	assert.Empty(t, anchored)
+
+	key := crypto.GeneratePrivateKey()
+	dao.EnableResultAnchoring(key, func() uint32 { return 42 })
+	anchored, err = dao.AnchorProposalResults(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Hash{passed}, anchored)
+
+	proposal := dao.GovernanceState.Proposals[passed]
+	require.NotEqual(t, types.Hash{}, proposal.ResultHash)
+	assert.Equal(t, types.Hash{}, dao.GovernanceState.Proposals[running].ResultHash)
+
+	result, err := dao.GetProposalResult(passed)
+	require.NoError(t, err)
+	assert.Equal(t, passed.String(), result.ProposalID)
+	assert.Equal(t, uint64(100), result.YesVotes)
+	assert.True(t, result.Passed)
+	assert.Equal(t, uint32(42), result.BlockHeight)
+	assert.Equal(t, key.PublicKey().String(), result.Signer)
+	root, err := VoterRoot(passed, dao.GovernanceState.Votes[passed])
+	require.NoError(t, err)
+	assert.Equal(t, root.String(), result.VoterRoot)
+
+	// Anchored results are not published again, failed uploads are retried
+	dao.GovernanceState.Proposals[failed] = &Proposal{
+		ID:      failed,
+		Status:  ProposalStatusRejected,
+		Results: &VoteResults{NoVotes: 10, TotalVoters: 1},
+	}
+	objects.mu.Lock()
+	objects.failPuts = 1
+	objects.mu.Unlock()
+	anchored, err = dao.AnchorProposalResults(nil)
+	assert.Error(t, err)
+	assert.Empty(t, anchored)
+	anchored, err = dao.AnchorProposalResults(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Hash{failed}, anchored)
+
+	// A document that is not signed for the proposal is rejected
+	dao.GovernanceState.Proposals[running].ResultHash = proposal.ResultHash
+	_, err = dao.GetProposalResult(running)
+	assert.Error(t, err)
+	_, err = dao.GetProposalResult(types.Hash{0x04})
+	assert.Error(t, err)
+}
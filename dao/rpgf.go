@@ -0,0 +1,440 @@
+package dao
+
+import (
+	"math/bits"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Bounds of a retroactive public goods funding round
+const (
+	MaxRPGFBadgeHolders = 100
+	MaxRPGFNominations  = 200
+)
+
+// RPGFRoundStatus is the stage of a retroactive public goods funding round
+type RPGFRoundStatus string
+
+const (
+	RPGFRoundStatusProposed   RPGFRoundStatus = "proposed"   // Awaiting the main DAO vote
+	RPGFRoundStatusRejected   RPGFRoundStatus = "rejected"   // The main DAO did not approve it
+	RPGFRoundStatusNominating RPGFRoundStatus = "nominating" // Taking nominations
+	RPGFRoundStatusVoting     RPGFRoundStatus = "voting"     // Badge holders cast their ballots
+	RPGFRoundStatusTallying   RPGFRoundStatus = "tallying"   // Voting ended, awaiting finalization
+	RPGFRoundStatusStreaming  RPGFRoundStatus = "streaming"  // Allocations stream to the recipients
+)
+
+// RPGFAllocation is the part of the budget a ballot gives a nomination
+type RPGFAllocation struct {
+	Nomination uint16
+	Amount     uint64
+}
+
+// RPGFNomination is a past contribution nominated for retroactive funding
+type RPGFNomination struct {
+	Name        string
+	Description string
+	Recipient   crypto.PublicKey
+	Nominator   crypto.PublicKey
+	NominatedAt int64
+	Allocation  uint64 // Set when the round is finalized
+	Claimed     uint64 // Streamed to the recipient so far
+}
+
+// RPGFRound is a retroactive public goods funding round. Members nominate
+// past contributions, a committee of badge holders splits the budget
+// escrowed from the treasury between them by ballot, and each nomination
+// receives the median of the amounts the ballots gave it, streamed over
+// the stream period. Its ID is the ID of the proposal that approved it.
+type RPGFRound struct {
+	ID               types.Hash
+	Title            string
+	Description      string
+	Budget           uint64
+	BadgeHolders     []crypto.PublicKey
+	NominationPeriod int64
+	VotingPeriod     int64
+	StreamDuration   int64
+	Proposer         crypto.PublicKey
+	Nominations      []*RPGFNomination
+	Ballots          map[string][]RPGFAllocation // badge holder -> allocations
+	Status           RPGFRoundStatus
+	CreatedAt        int64
+	OpenedAt         int64
+	NominationsClose int64
+	VotingCloses     int64
+	StreamStart      int64
+	StreamEnd        int64
+	Allocated        uint64 // Streamed to the nominations
+	Returned         uint64 // Budget left unallocated and returned to the treasury
+}
+
+// StatusAt returns the stage of the round at time now. Open rounds move
+// from nominations to voting to tallying as their periods end.
+func (r *RPGFRound) StatusAt(now int64) RPGFRoundStatus {
+	if r.Status != RPGFRoundStatusNominating {
+		return r.Status
+	}
+
+	switch {
+	case now >= r.VotingCloses:
+		return RPGFRoundStatusTallying
+	case now >= r.NominationsClose:
+		return RPGFRoundStatusVoting
+	default:
+		return RPGFRoundStatusNominating
+	}
+}
+
+// IsBadgeHolder reports whether key sits on the round's committee
+func (r *RPGFRound) IsBadgeHolder(key crypto.PublicKey) bool {
+	keyStr := key.String()
+	for _, holder := range r.BadgeHolders {
+		if holder.String() == keyStr {
+			return true
+		}
+	}
+	return false
+}
+
+// Tally computes the allocation of every nomination. A nomination gets the
+// median of the amounts the cast ballots gave it, counting ballots that
+// left it out as zero, so no single badge holder can move its share far.
+// The medians are scaled down when together they exceed the budget.
+func (r *RPGFRound) Tally() []uint64 {
+	allocations := make([]uint64, len(r.Nominations))
+	if len(r.Ballots) == 0 {
+		return allocations
+	}
+
+	amounts := make([][]uint64, len(r.Nominations))
+	for i := range amounts {
+		amounts[i] = make([]uint64, 0, len(r.Ballots))
+	}
+	for _, ballot := range r.Ballots {
+		for _, allocation := range ballot {
+			amounts[allocation.Nomination] = append(amounts[allocation.Nomination], allocation.Amount)
+		}
+	}
+
+	total := uint64(0)
+	for i, given := range amounts {
+		// Ballots that left the nomination out count as zero
+		for len(given) < len(r.Ballots) {
+			given = append(given, 0)
+		}
+		sort.Slice(given, func(a, b int) bool { return given[a] < given[b] })
+
+		mid := len(given) / 2
+		if len(given)%2 == 1 {
+			allocations[i] = given[mid]
+		} else {
+			allocations[i] = given[mid-1]/2 + given[mid]/2 + (given[mid-1]%2+given[mid]%2)/2
+		}
+		total += allocations[i]
+	}
+
+	if total > r.Budget {
+		for i, allocation := range allocations {
+			hi, lo := bits.Mul64(allocation, r.Budget)
+			allocations[i], _ = bits.Div64(hi, lo, total)
+		}
+	}
+	return allocations
+}
+
+// Vested returns the part of a nomination's allocation streamed by time now
+func (r *RPGFRound) Vested(nomination *RPGFNomination, now int64) uint64 {
+	if r.Status != RPGFRoundStatusStreaming {
+		return 0
+	}
+	return linearlyVested(nomination.Allocation, r.StreamStart, r.StreamEnd, now)
+}
+
+// Proposal returns the main DAO proposal voting on the round
+func (tx *RPGFRoundProposalTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        "Retroactive funding: " + tx.Title,
+		Description:  description,
+		ProposalType: ProposalTypeTreasury,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// RPGFManager runs the retroactive public goods funding rounds from
+// proposal to streamed allocations
+type RPGFManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	rounds          map[types.Hash]*RPGFRound
+}
+
+// NewRPGFManager creates a new retroactive public goods funding manager
+func NewRPGFManager(governanceState *GovernanceState, tokenState *GovernanceToken) *RPGFManager {
+	return &RPGFManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		rounds:          make(map[types.Hash]*RPGFRound),
+	}
+}
+
+// RecordProposal adds the round of a main DAO proposal
+func (rm *RPGFManager) RecordProposal(proposalID types.Hash, tx *RPGFRoundProposalTx, proposer crypto.PublicKey) {
+	rm.rounds[proposalID] = &RPGFRound{
+		ID:               proposalID,
+		Title:            tx.Title,
+		Description:      tx.Description,
+		Budget:           tx.Budget,
+		BadgeHolders:     tx.BadgeHolders,
+		NominationPeriod: tx.NominationPeriod,
+		VotingPeriod:     tx.VotingPeriod,
+		StreamDuration:   tx.StreamDuration,
+		Proposer:         proposer,
+		Ballots:          make(map[string][]RPGFAllocation),
+		Status:           RPGFRoundStatusProposed,
+		CreatedAt:        time.Now().Unix(),
+	}
+}
+
+// ProcessRPGFRoundOpenTx opens the round of a passed proposal for
+// nominations, escrowing its budget from the treasury
+func (rm *RPGFManager) ProcessRPGFRoundOpenTx(tx *RPGFRoundOpenTx, opener crypto.PublicKey) error {
+	round, exists := rm.rounds[tx.ProposalID]
+	if !exists {
+		return ErrRPGFRoundNotFoundError
+	}
+	if round.Status != RPGFRoundStatusProposed {
+		return NewDAOError(ErrInvalidProposal, "round is already open", nil)
+	}
+
+	proposal, exists := rm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	if rm.governanceState.Treasury.Balance < round.Budget {
+		return NewDAOError(ErrTreasuryInsufficient, "insufficient treasury funds for the round budget", map[string]interface{}{
+			"balance": rm.governanceState.Treasury.Balance,
+			"budget":  round.Budget,
+		})
+	}
+
+	now := time.Now().Unix()
+	rm.tokenState.Balances.Sub(opener.String(), uint64(tx.Fee))
+	rm.governanceState.Treasury.Balance -= round.Budget
+	round.Status = RPGFRoundStatusNominating
+	round.OpenedAt = now
+	round.NominationsClose = now + round.NominationPeriod
+	round.VotingCloses = round.NominationsClose + round.VotingPeriod
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// ProcessRPGFNominateTx nominates a contribution for funding by a round
+func (rm *RPGFManager) ProcessRPGFNominateTx(tx *RPGFNominateTx, nominator crypto.PublicKey) error {
+	round, exists := rm.rounds[tx.RoundID]
+	if !exists {
+		return ErrRPGFRoundNotFoundError
+	}
+
+	now := time.Now().Unix()
+	if round.StatusAt(now) != RPGFRoundStatusNominating {
+		return NewDAOError(ErrVotingClosed, "round is not taking nominations", nil)
+	}
+	if len(round.Nominations) >= MaxRPGFNominations {
+		return NewDAOError(ErrInvalidProposal, "round has reached its nomination limit", nil)
+	}
+
+	recipientStr := tx.Recipient.String()
+	for _, nomination := range round.Nominations {
+		if nomination.Recipient.String() == recipientStr {
+			return NewDAOError(ErrInvalidProposal, "recipient is already nominated", nil)
+		}
+	}
+
+	rm.tokenState.Balances.Sub(nominator.String(), uint64(tx.Fee))
+	round.Nominations = append(round.Nominations, &RPGFNomination{
+		Name:        tx.Name,
+		Description: tx.Description,
+		Recipient:   tx.Recipient,
+		Nominator:   nominator,
+		NominatedAt: now,
+	})
+
+	return nil
+}
+
+// ProcessRPGFBallotTx records the ballot of a badge holder, replacing any
+// ballot they cast before
+func (rm *RPGFManager) ProcessRPGFBallotTx(tx *RPGFBallotTx, voter crypto.PublicKey) error {
+	round, exists := rm.rounds[tx.RoundID]
+	if !exists {
+		return ErrRPGFRoundNotFoundError
+	}
+	if !round.IsBadgeHolder(voter) {
+		return NewDAOError(ErrUnauthorized, "only badge holders can vote in the round", nil)
+	}
+	if round.StatusAt(time.Now().Unix()) != RPGFRoundStatusVoting {
+		return NewDAOError(ErrVotingClosed, "round is not voting", nil)
+	}
+
+	total := uint64(0)
+	for _, allocation := range tx.Allocations {
+		if int(allocation.Nomination) >= len(round.Nominations) {
+			return NewDAOError(ErrInvalidProposal, "nomination does not exist", nil)
+		}
+		total += allocation.Amount
+		if total > round.Budget || total < allocation.Amount {
+			return NewDAOError(ErrInvalidProposal, "ballot allocates more than the round budget", map[string]interface{}{
+				"budget": round.Budget,
+			})
+		}
+	}
+
+	voterStr := voter.String()
+	rm.tokenState.Balances.Sub(voterStr, uint64(tx.Fee))
+	round.Ballots[voterStr] = append([]RPGFAllocation(nil), tx.Allocations...)
+
+	return nil
+}
+
+// ProcessRPGFFinalizeTx tallies the ballots of a round whose voting ended,
+// starts streaming the allocations and returns the unallocated budget to
+// the treasury
+func (rm *RPGFManager) ProcessRPGFFinalizeTx(tx *RPGFFinalizeTx, finalizer crypto.PublicKey) error {
+	round, exists := rm.rounds[tx.RoundID]
+	if !exists {
+		return ErrRPGFRoundNotFoundError
+	}
+
+	now := time.Now().Unix()
+	if round.StatusAt(now) != RPGFRoundStatusTallying {
+		return NewDAOError(ErrInvalidProposal, "round is not awaiting finalization", nil)
+	}
+
+	rm.tokenState.Balances.Sub(finalizer.String(), uint64(tx.Fee))
+
+	allocated := uint64(0)
+	for i, allocation := range round.Tally() {
+		round.Nominations[i].Allocation = allocation
+		allocated += allocation
+	}
+
+	round.Allocated = allocated
+	round.Returned = round.Budget - allocated
+	rm.governanceState.Treasury.Balance += round.Returned
+	round.Status = RPGFRoundStatusStreaming
+	round.StreamStart = now
+	round.StreamEnd = now + round.StreamDuration
+
+	return nil
+}
+
+// ProcessRPGFClaimTx pays a nominated recipient the part of its allocation
+// streamed since its last claim
+func (rm *RPGFManager) ProcessRPGFClaimTx(tx *RPGFClaimTx, claimant crypto.PublicKey) error {
+	round, exists := rm.rounds[tx.RoundID]
+	if !exists {
+		return ErrRPGFRoundNotFoundError
+	}
+	if round.Status != RPGFRoundStatusStreaming {
+		return NewDAOError(ErrInvalidProposal, "round is not streaming", nil)
+	}
+	if int(tx.Nomination) >= len(round.Nominations) {
+		return NewDAOError(ErrInvalidProposal, "nomination does not exist", nil)
+	}
+
+	nomination := round.Nominations[tx.Nomination]
+	claimantStr := claimant.String()
+	if nomination.Recipient.String() != claimantStr {
+		return NewDAOError(ErrUnauthorized, "only the nominated recipient can claim", nil)
+	}
+
+	vested := round.Vested(nomination, time.Now().Unix())
+	if vested <= nomination.Claimed {
+		return NewDAOError(ErrInvalidProposal, "nothing to claim yet", nil)
+	}
+
+	owed := vested - nomination.Claimed
+	if rm.tokenState.Balances.Get(claimantStr)+owed < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for claim fee", nil)
+	}
+	rm.tokenState.Balances.Add(claimantStr, owed)
+	rm.tokenState.Balances.Sub(claimantStr, uint64(tx.Fee))
+	nomination.Claimed = vested
+
+	return nil
+}
+
+// StatusOf returns the stage of a round at time now. Rounds under vote are
+// rejected once their proposal fails.
+func (rm *RPGFManager) StatusOf(round *RPGFRound, now int64) RPGFRoundStatus {
+	if round.Status != RPGFRoundStatusProposed {
+		return round.StatusAt(now)
+	}
+
+	if proposal, exists := rm.governanceState.Proposal(round.ID); exists {
+		switch proposal.Status {
+		case ProposalStatusRejected, ProposalStatusCancelled:
+			return RPGFRoundStatusRejected
+		}
+	}
+	return round.Status
+}
+
+// Recipients returns the addresses of the nominations of a round
+func (rm *RPGFManager) Recipients(id types.Hash) []string {
+	round, exists := rm.rounds[id]
+	if !exists {
+		return nil
+	}
+
+	recipients := make([]string, len(round.Nominations))
+	for i, nomination := range round.Nominations {
+		recipients[i] = nomination.Recipient.String()
+	}
+	return recipients
+}
+
+// GetRound returns a round
+func (rm *RPGFManager) GetRound(id types.Hash) (*RPGFRound, bool) {
+	round, exists := rm.rounds[id]
+	return round, exists
+}
+
+// ListRounds returns the rounds, optionally only those of a stage, newest
+// first
+func (rm *RPGFManager) ListRounds(status RPGFRoundStatus) []*RPGFRound {
+	now := time.Now().Unix()
+	rounds := make([]*RPGFRound, 0, len(rm.rounds))
+	for _, round := range rm.rounds {
+		if status != "" && rm.StatusOf(round, now) != status {
+			continue
+		}
+		rounds = append(rounds, round)
+	}
+
+	sort.Slice(rounds, func(i, j int) bool {
+		if rounds[i].CreatedAt != rounds[j].CreatedAt {
+			return rounds[i].CreatedAt > rounds[j].CreatedAt
+		}
+		return rounds[i].ID.String() < rounds[j].ID.String()
+	})
+	return rounds
+}
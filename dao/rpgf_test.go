@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPGFRound_Tally(t *testing.T) {
+	round := &RPGFRound{
+		Budget:      1000,
+		Nominations: []*RPGFNomination{{}, {}, {}},
+		Ballots: map[string][]RPGFAllocation{
+			"a": {{Nomination: 0, Amount: 600}, {Nomination: 1, Amount: 400}},
+			"b": {{Nomination: 0, Amount: 500}, {Nomination: 1, Amount: 100}},
+			"c": {{Nomination: 0, Amount: 100}, {Nomination: 2, Amount: 900}},
+		},
+	}
+
+	// Each nomination gets its median, a single generous ballot does not
+	// carry it
+	assert.Equal(t, []uint64{500, 100, 0}, round.Tally())
+
+	// With an even number of ballots the median is the mean of the middle two
+	round.Ballots["d"] = []RPGFAllocation{{Nomination: 1, Amount: 300}}
+	assert.Equal(t, []uint64{300, 200, 0}, round.Tally())
+
+	// Medians above the budget are scaled down to it
+	spread := &RPGFRound{
+		Budget:      10,
+		Nominations: []*RPGFNomination{{}, {}, {}},
+		Ballots: map[string][]RPGFAllocation{
+			"a": {{Nomination: 0, Amount: 5}, {Nomination: 1, Amount: 5}},
+			"b": {{Nomination: 0, Amount: 5}, {Nomination: 2, Amount: 5}},
+			"c": {{Nomination: 1, Amount: 5}, {Nomination: 2, Amount: 5}},
+		},
+	}
+	tally := spread.Tally()
+	assert.Equal(t, []uint64{3, 3, 3}, tally)
+
+	// Nothing allocated without ballots
+	assert.Equal(t, []uint64{0}, (&RPGFRound{Budget: 10, Nominations: []*RPGFNomination{{}}}).Tally())
+}
+
+func TestRPGFRound_Lifecycle(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	owner := crypto.GeneratePrivateKey().PublicKey()
+	holderA := crypto.GeneratePrivateKey().PublicKey()
+	holderB := crypto.GeneratePrivateKey().PublicKey()
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	builderA := crypto.GeneratePrivateKey().PublicKey()
+	builderB := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		owner.String():    10000,
+		holderA.String():  1000,
+		holderB.String():  1000,
+		outsider.String(): 1000,
+	}))
+	dao.GovernanceState.Treasury.Balance = 20000
+
+	now := time.Now().Unix()
+	propose := &RPGFRoundProposalTx{
+		Fee:              200,
+		Title:            "Retro round 1",
+		Budget:           6000,
+		BadgeHolders:     []crypto.PublicKey{holderA, holderB},
+		NominationPeriod: 3600,
+		VotingPeriod:     3600,
+		StreamDuration:   1000,
+		VotingType:       VotingTypeSimple,
+		StartTime:        now + 60,
+		EndTime:          now + 7*86400,
+		Threshold:        5100,
+	}
+	duplicate := *propose
+	duplicate.BadgeHolders = []crypto.PublicKey{holderA, holderA}
+	assert.Error(t, dao.ProcessDAOTransaction(&duplicate, owner, types.Hash{0x50}))
+
+	roundID := types.Hash{0x51}
+	require.NoError(t, dao.ProcessDAOTransaction(propose, owner, roundID))
+	round, exists := dao.GetRPGFRound(roundID)
+	require.True(t, exists)
+	assert.Equal(t, RPGFRoundStatusProposed, round.Status)
+
+	// Opening waits for the vote and escrows the budget
+	open := &RPGFRoundOpenTx{Fee: 100, ProposalID: roundID}
+	assert.Error(t, dao.ProcessDAOTransaction(open, owner, types.Hash{0x52}))
+	proposal, err := dao.GetProposal(roundID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(open, owner, types.Hash{0x53}))
+	assert.Equal(t, RPGFRoundStatusNominating, round.Status)
+	assert.Equal(t, uint64(14000), dao.GovernanceState.Treasury.Balance)
+
+	require.NoError(t, dao.ProcessDAOTransaction(&RPGFNominateTx{Fee: 5, RoundID: roundID, Name: "Explorer", Recipient: builderA}, outsider, types.Hash{0x54}))
+	require.NoError(t, dao.ProcessDAOTransaction(&RPGFNominateTx{Fee: 5, RoundID: roundID, Name: "Docs", Recipient: builderB}, holderA, types.Hash{0x55}))
+	assert.Error(t, dao.ProcessDAOTransaction(&RPGFNominateTx{Fee: 5, RoundID: roundID, Name: "Again", Recipient: builderA}, owner, types.Hash{0x56}))
+
+	// Ballots wait for the nominations to close
+	ballot := &RPGFBallotTx{Fee: 5, RoundID: roundID, Allocations: []RPGFAllocation{{Nomination: 0, Amount: 3000}, {Nomination: 1, Amount: 1000}}}
+	assert.Error(t, dao.ProcessDAOTransaction(ballot, holderA, types.Hash{0x57}))
+	round.NominationsClose = time.Now().Unix() - 1
+	assert.Equal(t, RPGFRoundStatusVoting, dao.RPGFManager.StatusOf(round, time.Now().Unix()))
+	assert.Error(t, dao.ProcessDAOTransaction(&RPGFNominateTx{Fee: 5, RoundID: roundID, Name: "Late", Recipient: outsider}, owner, types.Hash{0x58}))
+
+	// Only badge holders vote, within the budget, and may revise their ballot
+	assert.Error(t, dao.ProcessDAOTransaction(ballot, outsider, types.Hash{0x59}))
+	assert.Error(t, dao.ProcessDAOTransaction(&RPGFBallotTx{Fee: 5, RoundID: roundID, Allocations: []RPGFAllocation{{Nomination: 0, Amount: 7000}}}, holderA, types.Hash{0x5A}))
+	assert.Error(t, dao.ProcessDAOTransaction(&RPGFBallotTx{Fee: 5, RoundID: roundID, Allocations: []RPGFAllocation{{Nomination: 9, Amount: 1}}}, holderA, types.Hash{0x5B}))
+	require.NoError(t, dao.ProcessDAOTransaction(&RPGFBallotTx{Fee: 5, RoundID: roundID, Allocations: []RPGFAllocation{{Nomination: 1, Amount: 6000}}}, holderA, types.Hash{0x5C}))
+	require.NoError(t, dao.ProcessDAOTransaction(ballot, holderA, types.Hash{0x5D}))
+	require.NoError(t, dao.ProcessDAOTransaction(&RPGFBallotTx{Fee: 5, RoundID: roundID, Allocations: []RPGFAllocation{{Nomination: 0, Amount: 1000}}}, holderB, types.Hash{0x5E}))
+	assert.Len(t, round.Ballots, 2)
+
+	// Finalization waits for voting to end
+	finalize := &RPGFFinalizeTx{Fee: 100, RoundID: roundID}
+	assert.Error(t, dao.ProcessDAOTransaction(finalize, owner, types.Hash{0x5F}))
+	round.VotingCloses = time.Now().Unix() - 1
+	require.NoError(t, dao.ProcessDAOTransaction(finalize, owner, types.Hash{0x60}))
+	assert.Equal(t, RPGFRoundStatusStreaming, round.Status)
+	assert.Equal(t, uint64(2000), round.Nominations[0].Allocation)
+	assert.Equal(t, uint64(500), round.Nominations[1].Allocation)
+	assert.Equal(t, uint64(3500), round.Returned)
+	assert.Equal(t, uint64(14000+3500), dao.GovernanceState.Treasury.Balance)
+
+	// Allocations stream to their recipients
+	claim := &RPGFClaimTx{Fee: 1, RoundID: roundID, Nomination: 0}
+	assert.Error(t, dao.ProcessDAOTransaction(claim, outsider, types.Hash{0x61}))
+	round.StreamStart -= 500
+	round.StreamEnd -= 500
+	require.NoError(t, dao.ProcessDAOTransaction(claim, builderA, types.Hash{0x62}))
+	claimed := round.Nominations[0].Claimed
+	assert.InDelta(t, 1000, float64(claimed), 10)
+	assert.Equal(t, claimed-1, dao.TokenState.Balances.Get(builderA.String()))
+
+	round.StreamEnd = time.Now().Unix()
+	require.NoError(t, dao.ProcessDAOTransaction(claim, builderA, types.Hash{0x63}))
+	assert.Equal(t, uint64(2000), round.Nominations[0].Claimed)
+	assert.Equal(t, uint64(1998), dao.TokenState.Balances.Get(builderA.String()))
+	assert.Error(t, dao.ProcessDAOTransaction(claim, builderA, types.Hash{0x64}))
+
+	assert.Len(t, dao.ListRPGFRounds(RPGFRoundStatusStreaming), 1)
+	assert.Empty(t, dao.ListRPGFRounds(RPGFRoundStatusVoting))
+}
@@ -0,0 +1,387 @@
+package dao
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule decides when a periodic job runs next
+type Schedule interface {
+	// Next returns the first run after t, or the zero time if there is none
+	Next(t time.Time) time.Time
+}
+
+// everySchedule runs at a fixed interval
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSchedule runs at the minutes matching a five field cron expression,
+// every field is a bit set of the values it matches
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// A restricted day of month or day of week matches either, as in cron
+	anyDOM, anyDOW bool
+}
+
+// cronYears bounds the search for the next run of expressions that rarely
+// or never match, such as February 30
+const cronYears = 5
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDOM || s.anyDOW {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// cronShortcuts are the named schedules of cron
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a cron expression of minute, hour, day of month,
+// month and day of week, such as "*/10 * * * *", a shortcut such as
+// "@daily", or a fixed interval such as "@every 5m"
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid interval in schedule %q", spec)
+		}
+		return everySchedule{interval: d}, nil
+	}
+	if expanded, ok := cronShortcuts[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields: minute hour day-of-month month day-of-week", spec)
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minute of schedule %q: %w", spec, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hour of schedule %q: %w", spec, err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("day of month of schedule %q: %w", spec, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("month of schedule %q: %w", spec, err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("day of week of schedule %q: %w", spec, err)
+	}
+	// Sunday is both 0 and 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.anyDOM = fields[2] == "*"
+	s.anyDOW = fields[4] == "*"
+
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("schedule %q never runs", spec)
+	}
+	return s, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps
+// such as "1-5", "*/15" or "0,30" into a bit set
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		low, high := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// JobFunc is the work of a periodic job, given the time it was due
+type JobFunc func(now time.Time) error
+
+// JobMetrics are the run statistics of a scheduled job
+type JobMetrics struct {
+	Name         string `json:"name"`
+	Schedule     string `json:"schedule"`
+	Running      bool   `json:"running"`
+	Runs         uint64 `json:"runs"`
+	Failures     uint64 `json:"failures"`
+	LastRun      int64  `json:"last_run,omitempty"`
+	LastDuration int64  `json:"last_duration_ms"`
+	LastError    string `json:"last_error,omitempty"`
+	NextRun      int64  `json:"next_run,omitempty"`
+}
+
+// scheduledJob is a job with its schedule and statistics
+type scheduledJob struct {
+	run      JobFunc
+	schedule Schedule
+	jitter   time.Duration
+	// running serializes runs, a job never overlaps itself
+	running sync.Mutex
+
+	mu      sync.Mutex
+	metrics JobMetrics
+}
+
+// Scheduler runs the periodic maintenance jobs of the DAO. Every job runs
+// in its own goroutine at the times of its schedule, delayed by a random
+// jitter so that nodes sharing a schedule do not run in lockstep.
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs map[string]*scheduledJob
+	rand *rand.Rand
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler without jobs
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		jobs: make(map[string]*scheduledJob),
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Add schedules run under name with a cron expression or "@every"
+// interval, see ParseSchedule. Jobs must be added before Start.
+func (s *Scheduler) Add(name, spec string, jitter time.Duration, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	if jitter < 0 {
+		return fmt.Errorf("jitter of job %s must not be negative", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already scheduled", name)
+	}
+	s.jobs[name] = &scheduledJob{
+		run:      run,
+		schedule: schedule,
+		jitter:   jitter,
+		metrics:  JobMetrics{Name: name, Schedule: spec},
+	}
+	return nil
+}
+
+// Start runs the jobs in the background until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job, s.stop)
+	}
+}
+
+// Stop ends the background runs and waits for running jobs to finish
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// RunJob runs a job now, outside of its schedule
+func (s *Scheduler) RunJob(name string) error {
+	s.mu.Lock()
+	job, exists := s.jobs[name]
+	s.mu.Unlock()
+
+	if !exists {
+		return NewDAOError(ErrJobNotFound, fmt.Sprintf("job %s is not scheduled", name), nil)
+	}
+	return s.execute(job, time.Now())
+}
+
+// Metrics returns the statistics of every job, sorted by name
+func (s *Scheduler) Metrics() []JobMetrics {
+	s.mu.Lock()
+	jobs := make([]*scheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	metrics := make([]JobMetrics, 0, len(jobs))
+	for _, job := range jobs {
+		metrics = append(metrics, job.snapshot())
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+func (s *Scheduler) loop(job *scheduledJob, stop <-chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		next = next.Add(s.jitter(job.jitter))
+		job.setNextRun(next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.execute(job, time.Now())
+	}
+}
+
+// jitter returns a random delay below max
+func (s *Scheduler) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(s.rand.Int63n(int64(max)))
+}
+
+// execute runs a job and records its statistics. A panicking job fails the
+// run instead of the node.
+func (s *Scheduler) execute(job *scheduledJob, now time.Time) (err error) {
+	job.running.Lock()
+	defer job.running.Unlock()
+
+	job.setRunning()
+	started := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+		job.finish(started, time.Since(started), err)
+	}()
+
+	return job.run(now)
+}
+
+func (j *scheduledJob) snapshot() JobMetrics {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.metrics
+}
+
+func (j *scheduledJob) setNextRun(next time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.metrics.NextRun = next.Unix()
+}
+
+func (j *scheduledJob) setRunning() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.metrics.Running = true
+}
+
+func (j *scheduledJob) finish(started time.Time, took time.Duration, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.metrics.Running = false
+	j.metrics.Runs++
+	j.metrics.LastRun = started.Unix()
+	j.metrics.LastDuration = took.Milliseconds()
+	j.metrics.LastError = ""
+	if err != nil {
+		j.metrics.Failures++
+		j.metrics.LastError = err.Error()
+	}
+}
@@ -0,0 +1,153 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Saturday 15 June 2024, 10:07
+	from := time.Date(2024, 6, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"*/10 * * * *", time.Date(2024, 6, 15, 10, 10, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 6, 16, 3, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 6, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2024, 6, 17, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1,7 *", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		// A restricted day of month or day of week matches either
+		{"0 12 20 * 1", time.Date(2024, 6, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.next, schedule.Next(from), tt.spec)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "0 0 30 2 *", "@every -1m", "@every soon", "@sometimes"} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScheduler_RunsJobs(t *testing.T) {
+	scheduler := NewScheduler()
+	runs := make(chan time.Time, 10)
+	require.NoError(t, scheduler.Add("tick", "@every 10ms", time.Millisecond, func(now time.Time) error {
+		runs <- now
+		return nil
+	}))
+	assert.Error(t, scheduler.Add("tick", "@every 1m", 0, nil))
+	assert.Error(t, scheduler.Add("broken", "every minute", 0, nil))
+
+	scheduler.Start()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+	scheduler.Stop()
+
+	metrics := scheduler.Metrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "tick", metrics[0].Name)
+	assert.Equal(t, "@every 10ms", metrics[0].Schedule)
+	assert.GreaterOrEqual(t, metrics[0].Runs, uint64(3))
+	assert.Zero(t, metrics[0].Failures)
+	assert.False(t, metrics[0].Running)
+}
+
+func TestScheduler_RunJob(t *testing.T) {
+	scheduler := NewScheduler()
+	require.NoError(t, scheduler.Add("failing", "@daily", 0, func(time.Time) error {
+		return errors.New("ipfs unreachable")
+	}))
+	require.NoError(t, scheduler.Add("panicking", "@daily", 0, func(time.Time) error {
+		panic("nil map")
+	}))
+
+	assert.EqualError(t, scheduler.RunJob("failing"), "ipfs unreachable")
+	assert.EqualError(t, scheduler.RunJob("panicking"), "job panicked: nil map")
+
+	err := scheduler.RunJob("missing")
+	var daoErr *DAOError
+	require.ErrorAs(t, err, &daoErr)
+	assert.Equal(t, ErrJobNotFound, daoErr.Code)
+
+	metrics := scheduler.Metrics()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, uint64(1), metrics[0].Failures)
+	assert.Equal(t, "ipfs unreachable", metrics[0].LastError)
+	assert.Equal(t, "job panicked: nil map", metrics[1].LastError)
+}
+
+func TestDAO_MaintenanceJobs(t *testing.T) {
+	dao := NewDAO("TEST", "Test Token", 18)
+	now := time.Now().Unix()
+
+	expired := crypto.GeneratePrivateKey().PublicKey()
+	current := crypto.GeneratePrivateKey().PublicKey()
+	renewing := crypto.GeneratePrivateKey().PublicKey()
+	dao.GovernanceState.Delegations[expired.String()] = &Delegation{Delegator: expired, StartTime: now - 100, EndTime: now - 10, Active: true}
+	dao.GovernanceState.Delegations[current.String()] = &Delegation{Delegator: current, StartTime: now - 100, EndTime: now + 100, Active: true}
+	// Lapsed two and a half periods ago, renews into the current period
+	dao.GovernanceState.Delegations[renewing.String()] = &Delegation{Delegator: renewing, StartTime: now - 140, EndTime: now - 100, Active: true, Duration: 40, AutoRenew: true}
+
+	var expiries []DelegationExpiry
+	dao.OnDelegationExpiry(func(expiry DelegationExpiry) {
+		expiries = append(expiries, expiry)
+	})
+
+	guarded := 0
+	jobs := dao.MaintenanceJobs(func(fn func()) {
+		guarded++
+		fn()
+	})
+	for _, name := range MaintenanceJobNames {
+		assert.Contains(t, jobs, name)
+	}
+
+	require.NoError(t, jobs[JobDelegationExpiry](time.Unix(now, 0)))
+	assert.Equal(t, 1, guarded)
+	assert.False(t, dao.GovernanceState.Delegations[expired.String()].Active)
+	assert.True(t, dao.GovernanceState.Delegations[current.String()].Active)
+
+	renewed := dao.GovernanceState.Delegations[renewing.String()]
+	assert.True(t, renewed.Active)
+	assert.Equal(t, now-20, renewed.StartTime)
+	assert.Equal(t, now+20, renewed.EndTime)
+
+	require.Len(t, expiries, 2)
+	for _, expiry := range expiries {
+		if expiry.Delegation.Delegator.String() == renewing.String() {
+			assert.True(t, expiry.Renewed)
+			assert.Equal(t, now+20, expiry.Delegation.EndTime)
+		} else {
+			assert.Equal(t, expired.String(), expiry.Delegation.Delegator.String())
+			assert.False(t, expiry.Renewed)
+			assert.False(t, expiry.Delegation.Active)
+		}
+	}
+
+	// Sampling only reads state
+	require.NoError(t, jobs[JobMetricsSampling](time.Unix(now, 0)))
+	assert.Equal(t, 1, guarded)
+	assert.Equal(t, 1, dao.Metrics.Len())
+
+	// Expired delegations are only reported once
+	require.NoError(t, jobs[JobDelegationExpiry](time.Unix(now, 0)))
+	assert.Len(t, expiries, 2)
+}
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -21,6 +22,35 @@ const (
 	RoleEmergency  Role = 0x05 // Emergency response role
 )
 
+// String returns the name of the role
+func (r Role) String() string {
+	switch r {
+	case RoleGuest:
+		return "guest"
+	case RoleMember:
+		return "member"
+	case RoleModerator:
+		return "moderator"
+	case RoleAdmin:
+		return "admin"
+	case RoleSuperAdmin:
+		return "super_admin"
+	case RoleEmergency:
+		return "emergency"
+	default:
+		return "unknown"
+	}
+}
+
+// RoleChange is a role granted to or taken from a user. Granting a user a
+// new role takes their previous one.
+type RoleChange struct {
+	User    crypto.PublicKey
+	Role    Role
+	Granted bool
+	At      int64
+}
+
 // Permission represents specific actions that can be performed
 type Permission byte
 
@@ -92,6 +122,8 @@ type SecurityManager struct {
 	securityConfig    *SecurityConfig
 	emergencyContacts []crypto.PublicKey
 	pausedFunctions   map[string]bool
+	bootstrap         *BootstrapPlan
+	roleListeners     []func(RoleChange)
 }
 
 // SecurityConfig holds security-related configuration
@@ -186,6 +218,22 @@ func (sm *SecurityManager) initializeDefaultRolePermissions() {
 	}
 }
 
+// OnRoleChange registers fn to be told about every role granted or taken.
+// It is called with the manager locked and must not call back into it.
+func (sm *SecurityManager) OnRoleChange(fn func(RoleChange)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.roleListeners = append(sm.roleListeners, fn)
+}
+
+// notifyRoleChange tells the listeners about a role change (assumes lock is held)
+func (sm *SecurityManager) notifyRoleChange(user crypto.PublicKey, role Role, granted bool, at int64) {
+	change := RoleChange{User: user, Role: role, Granted: granted, At: at}
+	for _, listener := range sm.roleListeners {
+		listener(change)
+	}
+}
+
 // GrantRole grants a role to a user
 func (sm *SecurityManager) GrantRole(user crypto.PublicKey, role Role, grantedBy crypto.PublicKey, duration int64) error {
 	sm.mu.Lock()
@@ -198,9 +246,13 @@ func (sm *SecurityManager) GrantRole(user crypto.PublicKey, role Role, grantedBy
 		return NewDAOError(ErrUnauthorized, "insufficient permissions to grant roles", nil)
 	}
 
+	now := time.Now().Unix()
 	expiresAt := int64(0)
 	if duration > 0 {
-		expiresAt = time.Now().Unix() + duration
+		expiresAt = now + duration
+	}
+	if previous, exists := sm.accessControl[user.String()]; exists && previous.Active {
+		sm.notifyRoleChange(user, previous.Role, false, now)
 	}
 
 	entry := &AccessControlEntry{
@@ -208,12 +260,13 @@ func (sm *SecurityManager) GrantRole(user crypto.PublicKey, role Role, grantedBy
 		Role:        role,
 		Permissions: sm.rolePermissions[role],
 		GrantedBy:   grantedBy,
-		GrantedAt:   time.Now().Unix(),
+		GrantedAt:   now,
 		ExpiresAt:   expiresAt,
 		Active:      true,
 	}
 
 	sm.accessControl[user.String()] = entry
+	sm.notifyRoleChange(user, role, true, now)
 
 	sm.logAuditEvent(grantedBy, "GRANT_ROLE", user.String(), "SUCCESS",
 		map[string]interface{}{"role": role, "expires_at": expiresAt}, SecurityLevelSensitive)
@@ -235,9 +288,13 @@ func (sm *SecurityManager) RevokeRole(user crypto.PublicKey, revokedBy crypto.Pu
 
 	userStr := user.String()
 	if entry, exists := sm.accessControl[userStr]; exists {
+		wasActive := entry.Active
 		entry.Active = false
 		sm.logAuditEvent(revokedBy, "REVOKE_ROLE", user.String(), "SUCCESS",
 			map[string]interface{}{"role": entry.Role}, SecurityLevelSensitive)
+		if wasActive {
+			sm.notifyRoleChange(user, entry.Role, false, time.Now().Unix())
+		}
 	}
 
 	return nil
@@ -305,6 +362,31 @@ func (sm *SecurityManager) GetUserRole(user crypto.PublicKey) (Role, bool) {
 	return entry.Role, true
 }
 
+// MembersWithRoles returns the users actively holding any of roles
+func (sm *SecurityManager) MembersWithRoles(roles ...Role) []crypto.PublicKey {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	now := time.Now().Unix()
+	var members []crypto.PublicKey
+	for _, entry := range sm.accessControl {
+		if !entry.Active || (entry.ExpiresAt > 0 && now > entry.ExpiresAt) {
+			continue
+		}
+		for _, role := range roles {
+			if entry.Role == role {
+				members = append(members, entry.User)
+				break
+			}
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].String() < members[j].String()
+	})
+	return members
+}
+
 // ActivateEmergency activates emergency mode
 func (sm *SecurityManager) ActivateEmergency(activatedBy crypto.PublicKey, reason string, level SecurityLevel, affectedFunctions []string) error {
 	sm.mu.Lock()
@@ -0,0 +1,168 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Kinds of soulbound tokens
+const (
+	SoulboundKindMembership = "membership"
+	SoulboundKindRole       = "role"
+)
+
+// SoulboundToken is a non-transferable token bound to a member, minted when
+// they join the DAO or are granted a role and burned when they exit or lose
+// the role. Burned tokens stay on record.
+type SoulboundToken struct {
+	ID       types.Hash
+	Kind     string
+	Holder   crypto.PublicKey
+	Role     Role   // Role of role tokens
+	Name     string // Such as "Member" or "Admin 2024"
+	IssuedAt int64
+	BurnedAt int64 // 0 while held
+}
+
+// Active reports whether the token has not been burned
+func (t *SoulboundToken) Active() bool {
+	return t.BurnedAt == 0
+}
+
+// SoulboundRegistry keeps the soulbound tokens of the DAO's members. It has
+// no way to move a token to another holder.
+type SoulboundRegistry struct {
+	mu      sync.RWMutex
+	tokens  map[types.Hash]*SoulboundToken
+	holders map[string][]types.Hash // holder -> tokens in mint order
+	minted  uint64
+}
+
+// NewSoulboundRegistry creates a new soulbound token registry
+func NewSoulboundRegistry() *SoulboundRegistry {
+	return &SoulboundRegistry{
+		tokens:  make(map[types.Hash]*SoulboundToken),
+		holders: make(map[string][]types.Hash),
+	}
+}
+
+// roleTokenName returns the name of a role token issued at a time, the role
+// and the year it was granted in
+func roleTokenName(role Role, issuedAt int64) string {
+	words := strings.Split(role.String(), "_")
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return fmt.Sprintf("%s %d", strings.Join(words, " "), time.Unix(issuedAt, 0).UTC().Year())
+}
+
+// active returns the held token of a kind and role (assumes lock is held)
+func (sr *SoulboundRegistry) active(holder crypto.PublicKey, kind string, role Role) *SoulboundToken {
+	for _, id := range sr.holders[holder.String()] {
+		token := sr.tokens[id]
+		if token.Active() && token.Kind == kind && token.Role == role {
+			return token
+		}
+	}
+	return nil
+}
+
+// mint issues a token unless the holder already holds one of the same kind
+// and role (assumes lock is held)
+func (sr *SoulboundRegistry) mint(holder crypto.PublicKey, kind string, role Role, name string, now int64) *SoulboundToken {
+	if token := sr.active(holder, kind, role); token != nil {
+		return token
+	}
+
+	sr.minted++
+	h := sha256.New()
+	h.Write([]byte("bockchain-soulbound"))
+	binary.Write(h, binary.BigEndian, sr.minted)
+	h.Write([]byte(kind))
+	h.Write(holder)
+	h.Write([]byte{byte(role)})
+
+	token := &SoulboundToken{
+		ID:       types.HashFromBytes(h.Sum(nil)),
+		Kind:     kind,
+		Holder:   holder,
+		Role:     role,
+		Name:     name,
+		IssuedAt: now,
+	}
+	sr.tokens[token.ID] = token
+	sr.holders[holder.String()] = append(sr.holders[holder.String()], token.ID)
+	return token
+}
+
+// burn burns the held token of a kind and role (assumes lock is held)
+func (sr *SoulboundRegistry) burn(holder crypto.PublicKey, kind string, role Role, now int64) *SoulboundToken {
+	token := sr.active(holder, kind, role)
+	if token != nil {
+		token.BurnedAt = now
+	}
+	return token
+}
+
+// MintMembership issues the membership token of a member who joined
+func (sr *SoulboundRegistry) MintMembership(holder crypto.PublicKey, now int64) *SoulboundToken {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return sr.mint(holder, SoulboundKindMembership, RoleMember, "Member", now)
+}
+
+// BurnMembership burns the membership token of a member who left
+func (sr *SoulboundRegistry) BurnMembership(holder crypto.PublicKey, now int64) *SoulboundToken {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return sr.burn(holder, SoulboundKindMembership, RoleMember, now)
+}
+
+// RoleChanged mints the token of a granted role and burns the token of a
+// role taken
+func (sr *SoulboundRegistry) RoleChanged(change RoleChange) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if change.Granted {
+		sr.mint(change.User, SoulboundKindRole, change.Role, roleTokenName(change.Role, change.At), change.At)
+	} else {
+		sr.burn(change.User, SoulboundKindRole, change.Role, change.At)
+	}
+}
+
+// Get returns a soulbound token
+func (sr *SoulboundRegistry) Get(id types.Hash) (*SoulboundToken, bool) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	token, exists := sr.tokens[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *token
+	return &copied, true
+}
+
+// TokensOf returns the tokens of a holder in mint order, with the burned
+// ones when includeBurned is set
+func (sr *SoulboundRegistry) TokensOf(holder crypto.PublicKey, includeBurned bool) []*SoulboundToken {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	tokens := make([]*SoulboundToken, 0)
+	for _, id := range sr.holders[holder.String()] {
+		token := *sr.tokens[id]
+		if token.Active() || includeBurned {
+			tokens = append(tokens, &token)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoulbound_MembershipTokens(t *testing.T) {
+	dao, members := setupMembership(t)
+
+	// Genesis members hold a membership token
+	tokens := dao.ListSoulboundTokens(members[0], false)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, SoulboundKindMembership, tokens[0].Kind)
+	assert.Equal(t, "Member", tokens[0].Name)
+	assert.True(t, tokens[0].Active())
+
+	// Applicants get one when they are admitted
+	applicant := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.ProcessDAOTransaction(&JoinRequestTx{Statement: "I write docs"}, applicant, types.Hash{0x01}))
+	assert.Empty(t, dao.ListSoulboundTokens(applicant, true))
+	approvals := dao.ParameterManager.GetParameterConfig().MembershipApprovals
+	for i := uint64(0); i < approvals; i++ {
+		require.NoError(t, dao.ProcessDAOTransaction(&JoinApprovalTx{Applicant: applicant}, members[i], types.Hash{0x02, byte(i)}))
+	}
+	tokens = dao.ListSoulboundTokens(applicant, false)
+	require.Len(t, tokens, 1)
+	token, exists := dao.GetSoulboundToken(tokens[0].ID)
+	require.True(t, exists)
+	assert.Equal(t, applicant.String(), token.Holder.String())
+
+	// Exiting burns it, the burned token stays on record
+	require.NoError(t, dao.ProcessDAOTransaction(&MembershipStatusTx{Member: members[1], Status: MembershipStatusExited}, members[1], types.Hash{0x03}))
+	assert.Empty(t, dao.ListSoulboundTokens(members[1], false))
+	burned := dao.ListSoulboundTokens(members[1], true)
+	require.Len(t, burned, 1)
+	assert.False(t, burned[0].Active())
+}
+
+func TestSoulbound_RoleTokens(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	admin := crypto.GeneratePrivateKey().PublicKey()
+	dao.SecurityManager.accessControl[admin.String()] = &AccessControlEntry{
+		User:        admin,
+		Role:        RoleAdmin,
+		Permissions: dao.SecurityManager.rolePermissions[RoleAdmin],
+		GrantedBy:   admin,
+		GrantedAt:   time.Now().Unix(),
+		Active:      true,
+	}
+
+	user := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.GrantRole(user, RoleModerator, admin, 0))
+	tokens := dao.ListSoulboundTokens(user, false)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, SoulboundKindRole, tokens[0].Kind)
+	assert.Equal(t, RoleModerator, tokens[0].Role)
+	assert.Equal(t, fmt.Sprintf("Moderator %d", time.Now().UTC().Year()), tokens[0].Name)
+
+	// A new role takes the previous one's token
+	require.NoError(t, dao.GrantRole(user, RoleSuperAdmin, admin, 0))
+	tokens = dao.ListSoulboundTokens(user, false)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, fmt.Sprintf("Super Admin %d", time.Now().UTC().Year()), tokens[0].Name)
+
+	// Revoking burns it
+	require.NoError(t, dao.RevokeRole(user, admin))
+	assert.Empty(t, dao.ListSoulboundTokens(user, false))
+	assert.Len(t, dao.ListSoulboundTokens(user, true), 2)
+
+	// Unauthorized grants mint nothing
+	other := crypto.GeneratePrivateKey().PublicKey()
+	assert.Error(t, dao.GrantRole(other, RoleAdmin, user, 0))
+	assert.Empty(t, dao.ListSoulboundTokens(other, true))
+}
@@ -0,0 +1,143 @@
+package dao
+
+import (
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// VoteSponsorship is a treasury budget that pays the voting fees of a
+// proposal during its voting window
+type VoteSponsorship struct {
+	ProposalID types.Hash
+	Budget     uint64 // Reserved from the treasury when the proposal is created
+	Spent      uint64
+	Voters     map[string]bool // Voters whose fee has been sponsored
+	StartTime  int64
+	EndTime    int64
+	Released   bool // Unspent budget has been returned to the treasury
+}
+
+// Remaining returns the unspent part of the budget
+func (s *VoteSponsorship) Remaining() uint64 {
+	return s.Budget - s.Spent
+}
+
+// FeeSponsorRelayer pays transaction fees on behalf of senders out of
+// treasury budgets. It currently sponsors votes on proposals created with a
+// sponsored voting window.
+type FeeSponsorRelayer struct {
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	parameterManager *ParameterManager
+	sponsorships     map[types.Hash]*VoteSponsorship
+}
+
+// NewFeeSponsorRelayer creates a new fee sponsorship relayer
+func NewFeeSponsorRelayer(governanceState *GovernanceState, tokenState *GovernanceToken, parameterManager *ParameterManager) *FeeSponsorRelayer {
+	return &FeeSponsorRelayer{
+		governanceState:  governanceState,
+		tokenState:       tokenState,
+		parameterManager: parameterManager,
+		sponsorships:     make(map[types.Hash]*VoteSponsorship),
+	}
+}
+
+// CheckBudget reports whether the treasury can sponsor voting with budget
+func (r *FeeSponsorRelayer) CheckBudget(budget uint64) error {
+	if budget == 0 {
+		return nil
+	}
+
+	maxBudget := r.parameterManager.GetParameterConfig().VoteSponsorshipMaxBudget
+	if budget > maxBudget {
+		return NewDAOError(ErrInvalidProposal, "sponsored voting budget exceeds governance limit", map[string]interface{}{
+			"budget": budget,
+			"max":    maxBudget,
+		})
+	}
+
+	if budget > r.governanceState.Treasury.Balance {
+		return ErrTreasuryInsufficientFunds
+	}
+
+	return nil
+}
+
+// Reserve moves a sponsored voting budget for a proposal out of the treasury
+func (r *FeeSponsorRelayer) Reserve(proposalID types.Hash, budget uint64) error {
+	if budget == 0 {
+		return nil
+	}
+
+	if err := r.CheckBudget(budget); err != nil {
+		return err
+	}
+
+	proposal, exists := r.governanceState.Proposal(proposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+
+	if _, exists := r.sponsorships[proposalID]; exists {
+		return NewDAOError(ErrInvalidProposal, "proposal voting is already sponsored", nil)
+	}
+
+	r.governanceState.Treasury.Balance -= budget
+	r.sponsorships[proposalID] = &VoteSponsorship{
+		ProposalID: proposalID,
+		Budget:     budget,
+		Voters:     make(map[string]bool),
+		StartTime:  proposal.StartTime,
+		EndTime:    proposal.EndTime,
+	}
+
+	return nil
+}
+
+// SponsorVoteFee reimburses the fee of a voter's first vote on a sponsored
+// proposal while budget remains, and reports whether it did
+func (r *FeeSponsorRelayer) SponsorVoteFee(proposalID types.Hash, voter crypto.PublicKey, fee uint64) bool {
+	sponsorship, exists := r.sponsorships[proposalID]
+	if !exists || sponsorship.Released || fee == 0 {
+		return false
+	}
+
+	voterStr := voter.String()
+	if sponsorship.Voters[voterStr] || sponsorship.Remaining() < fee {
+		return false
+	}
+
+	sponsorship.Spent += fee
+	sponsorship.Voters[voterStr] = true
+	r.governanceState.Treasury.FeesSponsored += fee
+	r.tokenState.Balances.Add(voterStr, fee)
+
+	if holder, exists := r.governanceState.TokenHolders[voterStr]; exists {
+		holder.Balance += fee
+	}
+
+	return true
+}
+
+// ReleaseExpired returns the unspent budgets of sponsorships whose voting
+// window ended before now to the treasury, and returns the amount released
+func (r *FeeSponsorRelayer) ReleaseExpired(now int64) uint64 {
+	released := uint64(0)
+	for _, sponsorship := range r.sponsorships {
+		if sponsorship.Released || sponsorship.EndTime >= now {
+			continue
+		}
+
+		released += sponsorship.Remaining()
+		sponsorship.Released = true
+	}
+
+	r.governanceState.Treasury.Balance += released
+	return released
+}
+
+// GetSponsorship returns the sponsored voting budget of a proposal
+func (r *FeeSponsorRelayer) GetSponsorship(proposalID types.Hash) (*VoteSponsorship, bool) {
+	sponsorship, exists := r.sponsorships[proposalID]
+	return sponsorship, exists
+}
@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSponsoredProposal(t *testing.T, budget uint64) (*DAO, types.Hash, []crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	voters := []crypto.PublicKey{
+		crypto.GeneratePrivateKey().PublicKey(),
+		crypto.GeneratePrivateKey().PublicKey(),
+		crypto.GeneratePrivateKey().PublicKey(),
+	}
+	distribution := map[string]uint64{creator.String(): 10000}
+	for _, voter := range voters {
+		distribution[voter.String()] = 1000
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	dao.GovernanceState.Treasury.Balance = 5000
+	dao.GetParameterConfig().VoteSponsorshipMaxBudget = 1000
+
+	now := time.Now().Unix()
+	proposalID := types.Hash{0x5A}
+	tx := &ProposalTx{
+		Fee: 100, Title: "Critical upgrade", Description: "Sponsored vote",
+		ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple,
+		StartTime: now - 1, EndTime: now + 7*24*3600, Threshold: 5000, VoteSponsor: budget,
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, creator, proposalID))
+	dao.GovernanceState.Proposals[proposalID].Status = ProposalStatusActive
+
+	return dao, proposalID, voters
+}
+
+func TestSponsoredVoting_ReservesBudget(t *testing.T) {
+	dao, proposalID, _ := setupSponsoredProposal(t, 800)
+
+	sponsorship, exists := dao.GetVoteSponsorship(proposalID)
+	require.True(t, exists)
+	assert.Equal(t, uint64(800), sponsorship.Budget)
+	assert.Equal(t, uint64(4200), dao.GovernanceState.Treasury.Balance)
+
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{creator.String(): 10000}))
+	now := time.Now().Unix()
+	tx := &ProposalTx{
+		Fee: 100, Title: "Too generous", Description: "Over the limit",
+		ProposalType: ProposalTypeGeneral, VotingType: VotingTypeSimple,
+		StartTime: now, EndTime: now + 7*24*3600, Threshold: 5000, VoteSponsor: 1001,
+	}
+
+	// Budgets above the governance limit are rejected before the proposal is created
+	require.Error(t, dao.ProcessDAOTransaction(tx, creator, types.Hash{0x5B}))
+	assert.NotContains(t, dao.GovernanceState.Proposals, types.Hash{0x5B})
+
+	// As are budgets the treasury cannot cover
+	dao.GovernanceState.Treasury.Balance = 500
+	tx.VoteSponsor = 600
+	require.Error(t, dao.ProcessDAOTransaction(tx, creator, types.Hash{0x5C}))
+}
+
+func TestSponsoredVoting_CoversFeesWithinBudget(t *testing.T) {
+	dao, proposalID, voters := setupSponsoredProposal(t, 150)
+
+	vote := func(voter crypto.PublicKey, hash byte) {
+		tx := &VoteTx{Fee: 100, ProposalID: proposalID, Choice: VoteChoiceYes, Weight: 10}
+		require.NoError(t, dao.ProcessDAOTransaction(tx, voter, types.Hash{hash}))
+	}
+
+	// The fee of the first vote is covered, only the voting weight is spent
+	vote(voters[0], 0x01)
+	assert.Equal(t, uint64(1000-10), dao.GetTokenBalance(voters[0]))
+
+	// Once the budget cannot cover a fee the voter pays it
+	vote(voters[1], 0x02)
+	assert.Equal(t, uint64(1000-10-100), dao.GetTokenBalance(voters[1]))
+
+	sponsorship, _ := dao.GetVoteSponsorship(proposalID)
+	assert.Equal(t, uint64(100), sponsorship.Spent)
+	assert.Len(t, sponsorship.Voters, 1)
+	assert.Equal(t, uint64(100), dao.GovernanceState.Treasury.FeesSponsored)
+
+	// Unspent budget returns to the treasury after the window closes
+	treasury := dao.GovernanceState.Treasury.Balance
+	assert.Equal(t, uint64(0), dao.FeeSponsor.ReleaseExpired(sponsorship.EndTime))
+	assert.Equal(t, uint64(50), dao.FeeSponsor.ReleaseExpired(sponsorship.EndTime+1))
+	assert.Equal(t, treasury+50, dao.GovernanceState.Treasury.Balance)
+	assert.Equal(t, uint64(0), dao.FeeSponsor.ReleaseExpired(sponsorship.EndTime+2))
+
+	// Released sponsorships no longer cover fees
+	assert.False(t, dao.FeeSponsor.SponsorVoteFee(proposalID, voters[2], 10))
+}
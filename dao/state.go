@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"time"
+
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/types"
 )
@@ -9,10 +11,15 @@ import (
 type GovernanceState struct {
 	Proposals    map[types.Hash]*Proposal
 	Votes        map[types.Hash]map[string]*Vote
+	Voters       map[types.Hash]*VoterSet // Who voted on each proposal, by member index
+	Members      *MemberIndex
 	Delegations  map[string]*Delegation
 	TokenHolders map[string]*TokenHolder
 	Treasury     *TreasuryState
 	Config       *DAOConfig
+
+	archive *Archive     // Finalized data moved out of memory, nil unless memory-bounded
+	clock   func() int64 // Unix time the state machine runs at, the wall clock when nil
 }
 
 // NewGovernanceState creates a new governance state instance
@@ -20,6 +27,8 @@ func NewGovernanceState() *GovernanceState {
 	return &GovernanceState{
 		Proposals:    make(map[types.Hash]*Proposal),
 		Votes:        make(map[types.Hash]map[string]*Vote),
+		Voters:       make(map[types.Hash]*VoterSet),
+		Members:      NewMemberIndex(),
 		Delegations:  make(map[string]*Delegation),
 		TokenHolders: make(map[string]*TokenHolder),
 		Treasury:     NewTreasuryState(),
@@ -27,20 +36,78 @@ func NewGovernanceState() *GovernanceState {
 	}
 }
 
+// Now returns the Unix time the governance state machine runs at
+func (gs *GovernanceState) Now() int64 {
+	if gs.clock != nil {
+		return gs.clock()
+	}
+	return time.Now().Unix()
+}
+
+// PruneFinalizedVotes drops the vote maps of decided proposals whose voting
+// ended before cutoff and returns how many were dropped. Tallies remain
+// available in the proposal results and who voted in the voter sets.
+func (gs *GovernanceState) PruneFinalizedVotes(cutoff int64) int {
+	pruned := 0
+	for proposalID := range gs.Votes {
+		proposal, exists := gs.Proposals[proposalID]
+		if !exists || proposal.EndTime >= cutoff {
+			continue
+		}
+
+		switch proposal.Status {
+		case ProposalStatusPassed, ProposalStatusRejected, ProposalStatusExecuted, ProposalStatusCancelled:
+			delete(gs.Votes, proposalID)
+			pruned++
+		}
+	}
+
+	return pruned
+}
+
 // Proposal represents a governance proposal
 type Proposal struct {
-	ID           types.Hash
-	Creator      crypto.PublicKey
-	Title        string
-	Description  string
-	ProposalType ProposalType
-	VotingType   VotingType
-	StartTime    int64
-	EndTime      int64
-	Status       ProposalStatus
-	Threshold    uint64
-	Results      *VoteResults
-	MetadataHash types.Hash
+	ID             types.Hash
+	Creator        crypto.PublicKey
+	Title          string
+	Description    string
+	ProposalType   ProposalType
+	VotingType     VotingType
+	StartTime      int64
+	EndTime        int64
+	Status         ProposalStatus
+	Threshold      uint64
+	Quorum         uint64 // Participation required above the DAO quorum, 0 for none
+	AdaptiveQuorum uint64 // DAO quorum set by recent turnout at creation, 0 for the configured one
+	Results        *VoteResults
+	MetadataHash   types.Hash
+	ResultHash     types.Hash // Signed result document on IPFS, zero until anchored
+	Track          string     // Track whose rules the proposal follows, empty for none
+	Timelock       int64      // Seconds after the vote ends before the proposal may execute
+	Deposit        uint64     // Track deposit still held, settled when the vote ends
+}
+
+// RequiredQuorum returns the participation the proposal needs to be valid,
+// the larger of the DAO quorum and its own. The adaptive quorum of the
+// proposal stands in for the configured DAO quorum when set.
+func (p *Proposal) RequiredQuorum(config *DAOConfig) uint64 {
+	quorum := config.QuorumThreshold
+	if p.AdaptiveQuorum > 0 {
+		quorum = p.AdaptiveQuorum
+	}
+	if p.Quorum > quorum {
+		return p.Quorum
+	}
+	return quorum
+}
+
+// RequiredThreshold returns the share of yes votes in basis points the
+// proposal needs to pass, the larger of the DAO threshold and its own
+func (p *Proposal) RequiredThreshold(config *DAOConfig) uint64 {
+	if p.Threshold > config.PassingThreshold {
+		return p.Threshold
+	}
+	return config.PassingThreshold
 }
 
 // Vote represents a cast vote
@@ -59,6 +126,9 @@ type Delegation struct {
 	StartTime int64
 	EndTime   int64
 	Active    bool
+	// Duration and AutoRenew let the expiry sweep renew the delegation
+	Duration  int64
+	AutoRenew bool
 }
 
 // TokenHolder represents a governance token holder
@@ -69,6 +139,9 @@ type TokenHolder struct {
 	Reputation uint64
 	JoinedAt   int64
 	LastActive int64
+	Status     MembershipStatus
+	// KYCAttestation links the identity attestation given when joining
+	KYCAttestation types.Hash
 }
 
 // VoteResults contains the results of a proposal vote
@@ -83,10 +156,17 @@ type VoteResults struct {
 
 // TreasuryState manages the DAO treasury
 type TreasuryState struct {
-	Balance      uint64
-	Signers      []crypto.PublicKey
-	RequiredSigs uint8
-	Transactions map[types.Hash]*PendingTx
+	Balance              uint64
+	Signers              []crypto.PublicKey
+	RequiredSigs         uint8
+	Transactions         map[types.Hash]*PendingTx
+	TotalInflows         uint64                 // Cumulative funds added to the treasury
+	YieldDistributed     uint64                 // Cumulative funds paid out as staking yield
+	FeesSponsored        uint64                 // Cumulative funds spent on sponsored voting fees
+	ParticipationRewards uint64                 // Cumulative funds paid out as participation rewards
+	Inflows              []TreasuryInflow       // Funds added to the treasury, oldest first
+	ThresholdKey         *TreasuryThresholdKey  // Key the signers hold in shares, nil until generated
+	KeyGeneration        *TreasuryKeyGeneration // Running key generation ceremony
 }
 
 // NewTreasuryState creates a new treasury state
@@ -103,12 +183,38 @@ func NewTreasuryState() *TreasuryState {
 type PendingTx struct {
 	ID         types.Hash
 	Recipient  crypto.PublicKey
-	Amount     uint64
+	Amount     uint64 // Native amount, converted again at execution for stable transactions
 	Purpose    string
 	Signatures []crypto.Signature
+	Aggregate  *crypto.AggregateSignature // Replaces Signatures once the signers aggregate them
+	Threshold  *ThresholdSigning          // Signing with the treasury threshold key
 	CreatedAt  int64
 	ExpiresAt  int64
 	Executed   bool
+	ExecutedAt int64
+
+	// Stable denominated transactions
+	Denomination  string // Oracle feed the stable amount converts through
+	StableAmount  uint64
+	QuotedAmount  uint64 // Native amount quoted at creation
+	Requeues      int    // Executions deferred by a stale feed or excess slippage
+	RequeueReason string
+}
+
+// Treasury inflow sources
+const (
+	InflowSourceDeposit       = "deposit"
+	InflowSourceClawback      = "clawback"       // Recovered by a dispute ruling
+	InflowSourceForfeitedBond = "forfeited_bond" // Share of a lost dispute bond
+	InflowSourceNameFee       = "name_fee"       // Name registrations and renewals
+	InflowSourceTrackDeposit  = "track_deposit"  // Deposit of a track proposal that missed quorum
+)
+
+// TreasuryInflow is funds added to the treasury
+type TreasuryInflow struct {
+	Amount    uint64
+	Source    string
+	Timestamp int64
 }
 
 // DAOConfig contains DAO configuration parameters
@@ -137,7 +243,7 @@ type GovernanceToken struct {
 	Name        string
 	TotalSupply uint64
 	Decimals    uint8
-	Balances    map[string]uint64
+	Balances    *BalanceStore
 	Allowances  map[string]map[string]uint64
 }
 
@@ -148,23 +254,17 @@ func NewGovernanceToken(symbol, name string, decimals uint8) *GovernanceToken {
 		Name:        name,
 		TotalSupply: 0,
 		Decimals:    decimals,
-		Balances:    make(map[string]uint64),
+		Balances:    NewBalanceStore(),
 		Allowances:  make(map[string]map[string]uint64),
 	}
 }
 
 // Transfer transfers tokens from one address to another
 func (gt *GovernanceToken) Transfer(from, to string, amount uint64) error {
-	if gt.Balances[from] < amount {
+	if !gt.Balances.Debit(from, amount) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient balance for transfer", nil)
 	}
-
-	gt.Balances[from] -= amount
-	if gt.Balances[to] == 0 {
-		gt.Balances[to] = amount
-	} else {
-		gt.Balances[to] += amount
-	}
+	gt.Balances.Add(to, amount)
 
 	return nil
 }
@@ -185,18 +285,11 @@ func (gt *GovernanceToken) TransferFrom(spender, from, to string, amount uint64)
 		return NewDAOError(ErrInsufficientTokens, "insufficient allowance for transfer", nil)
 	}
 
-	// Check balance
-	if gt.Balances[from] < amount {
-		return NewDAOError(ErrInsufficientTokens, "insufficient balance for transfer", nil)
-	}
-
 	// Perform transfer
-	gt.Balances[from] -= amount
-	if gt.Balances[to] == 0 {
-		gt.Balances[to] = amount
-	} else {
-		gt.Balances[to] += amount
+	if !gt.Balances.Debit(from, amount) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient balance for transfer", nil)
 	}
+	gt.Balances.Add(to, amount)
 
 	// Reduce allowance
 	gt.Allowances[from][spender] -= amount
@@ -206,7 +299,7 @@ func (gt *GovernanceToken) TransferFrom(spender, from, to string, amount uint64)
 
 // GetBalance returns the balance of an address
 func (gt *GovernanceToken) GetBalance(address string) uint64 {
-	return gt.Balances[address]
+	return gt.Balances.Get(address)
 }
 
 // GetAllowance returns the allowance between owner and spender
@@ -225,22 +318,16 @@ func (gt *GovernanceToken) Mint(to string, amount uint64) error {
 	}
 
 	gt.TotalSupply += amount
-	if gt.Balances[to] == 0 {
-		gt.Balances[to] = amount
-	} else {
-		gt.Balances[to] += amount
-	}
+	gt.Balances.Add(to, amount)
 
 	return nil
 }
 
 // Burn destroys tokens from an address
 func (gt *GovernanceToken) Burn(from string, amount uint64) error {
-	if gt.Balances[from] < amount {
+	if !gt.Balances.Debit(from, amount) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient balance to burn", nil)
 	}
-
-	gt.Balances[from] -= amount
 	gt.TotalSupply -= amount
 
 	return nil
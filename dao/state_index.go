@@ -0,0 +1,557 @@
+package dao
+
+import (
+	"sync"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Orders token holders can be listed in
+const (
+	HolderOrderBalance    = "balance"
+	HolderOrderReputation = "reputation"
+	HolderOrderJoinedAt   = "joined_at"
+)
+
+// HolderOrders are the indexed token holder orders
+var HolderOrders = []string{HolderOrderBalance, HolderOrderReputation, HolderOrderJoinedAt}
+
+// rankedIndexMaxLevel bounds the skip list height, enough for millions of
+// entries
+const rankedIndexMaxLevel = 24
+
+// rankedIndex is a skip list of ids ordered by score, then id. Every link
+// records how many entries it spans so entries can be found by rank, which
+// makes pages and score ranges O(log n) to locate.
+type rankedIndex struct {
+	head   *rankedNode
+	level  int
+	length int
+	scores map[string]uint64
+	seed   uint64
+}
+
+type rankedNode struct {
+	score uint64
+	id    string
+	next  []rankedLink
+}
+
+type rankedLink struct {
+	node *rankedNode
+	span int
+}
+
+func newRankedIndex() *rankedIndex {
+	return &rankedIndex{
+		head:   &rankedNode{next: make([]rankedLink, rankedIndexMaxLevel)},
+		level:  1,
+		scores: make(map[string]uint64),
+		seed:   0x9e3779b97f4a7c15,
+	}
+}
+
+// before reports whether node sorts before (score, id)
+func (n *rankedNode) before(score uint64, id string) bool {
+	return n.score < score || (n.score == score && n.id < id)
+}
+
+// randomLevel picks the height of a new node, each level half as likely as
+// the one below. A fixed xorshift keeps the structure deterministic.
+func (ri *rankedIndex) randomLevel() int {
+	ri.seed ^= ri.seed << 13
+	ri.seed ^= ri.seed >> 7
+	ri.seed ^= ri.seed << 17
+
+	level := 1
+	for bits := ri.seed; bits&1 == 1 && level < rankedIndexMaxLevel; bits >>= 1 {
+		level++
+	}
+	return level
+}
+
+// Set inserts id with score, moving it if it is already indexed
+func (ri *rankedIndex) Set(id string, score uint64) {
+	if current, exists := ri.scores[id]; exists {
+		if current == score {
+			return
+		}
+		ri.Remove(id)
+	}
+
+	var update [rankedIndexMaxLevel]*rankedNode
+	var rank [rankedIndexMaxLevel]int
+
+	x := ri.head
+	for i := ri.level - 1; i >= 0; i-- {
+		if i < ri.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i].node != nil && x.next[i].node.before(score, id) {
+			rank[i] += x.next[i].span
+			x = x.next[i].node
+		}
+		update[i] = x
+	}
+
+	level := ri.randomLevel()
+	if level > ri.level {
+		for i := ri.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = ri.head
+			ri.head.next[i].span = ri.length
+		}
+		ri.level = level
+	}
+
+	node := &rankedNode{score: score, id: id, next: make([]rankedLink, level)}
+	for i := 0; i < level; i++ {
+		node.next[i].node = update[i].next[i].node
+		update[i].next[i].node = node
+		node.next[i].span = update[i].next[i].span - (rank[0] - rank[i])
+		update[i].next[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < ri.level; i++ {
+		update[i].next[i].span++
+	}
+
+	ri.scores[id] = score
+	ri.length++
+}
+
+// Remove drops id from the index
+func (ri *rankedIndex) Remove(id string) {
+	score, exists := ri.scores[id]
+	if !exists {
+		return
+	}
+
+	var update [rankedIndexMaxLevel]*rankedNode
+	x := ri.head
+	for i := ri.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && x.next[i].node.before(score, id) {
+			x = x.next[i].node
+		}
+		update[i] = x
+	}
+
+	target := x.next[0].node
+	for i := 0; i < ri.level; i++ {
+		if update[i].next[i].node == target {
+			update[i].next[i].span += target.next[i].span - 1
+			update[i].next[i].node = target.next[i].node
+		} else {
+			update[i].next[i].span--
+		}
+	}
+	for ri.level > 1 && ri.head.next[ri.level-1].node == nil {
+		ri.level--
+	}
+
+	delete(ri.scores, id)
+	ri.length--
+}
+
+// Len returns the number of indexed ids
+func (ri *rankedIndex) Len() int {
+	return ri.length
+}
+
+// CountBelow returns how many ids score below score
+func (ri *rankedIndex) CountBelow(score uint64) int {
+	rank := 0
+	x := ri.head
+	for i := ri.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && x.next[i].node.score < score {
+			rank += x.next[i].span
+			x = x.next[i].node
+		}
+	}
+	return rank
+}
+
+// Slice returns up to limit ids starting at the zero-based rank offset, in
+// ascending order
+func (ri *rankedIndex) Slice(offset, limit int) []string {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= ri.length || limit <= 0 {
+		return []string{}
+	}
+	if offset+limit > ri.length {
+		limit = ri.length - offset
+	}
+
+	// Walk down to the node at rank offset+1
+	traversed := 0
+	x := ri.head
+	for i := ri.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && traversed+x.next[i].span <= offset+1 {
+			traversed += x.next[i].span
+			x = x.next[i].node
+		}
+	}
+
+	ids := make([]string, 0, limit)
+	for ; x != nil && len(ids) < limit; x = x.next[0].node {
+		ids = append(ids, x.id)
+	}
+	return ids
+}
+
+// Page returns the ids at offset of the ascending or descending order
+func (ri *rankedIndex) Page(descending bool, offset, limit int) []string {
+	if !descending {
+		return ri.Slice(offset, limit)
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	end := ri.length - offset
+	start := end - limit
+	if start < 0 {
+		start = 0
+	}
+	if end <= 0 || limit <= 0 {
+		return []string{}
+	}
+
+	ids := ri.Slice(start, end-start)
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	return ids
+}
+
+// timeScore maps a timestamp onto an unsigned score that keeps its order
+func timeScore(timestamp int64) uint64 {
+	return uint64(timestamp) ^ (1 << 63)
+}
+
+// StateIndex keeps sorted indexes of token holders and proposals so that
+// lists can be paged in order without scanning the state maps. The processor
+// keeps it current as transactions apply.
+type StateIndex struct {
+	mu              sync.RWMutex
+	holders         map[string]*rankedIndex
+	proposals       *rankedIndex // By end time
+	proposalsStatus map[ProposalStatus]*rankedIndex
+	statuses        map[string]ProposalStatus
+	schedule        *proposalSchedule // Undecided proposals by next status change
+}
+
+// NewStateIndex creates a new, empty state index
+func NewStateIndex() *StateIndex {
+	si := &StateIndex{}
+	si.reset()
+	return si
+}
+
+// reset empties the index; callers must hold the lock
+func (si *StateIndex) reset() {
+	si.holders = make(map[string]*rankedIndex, len(HolderOrders))
+	for _, order := range HolderOrders {
+		si.holders[order] = newRankedIndex()
+	}
+	si.proposals = newRankedIndex()
+	si.proposalsStatus = make(map[ProposalStatus]*rankedIndex)
+	si.statuses = make(map[string]ProposalStatus)
+	si.schedule = newProposalSchedule()
+}
+
+// IsHolderOrder reports whether order is an indexed token holder order
+func IsHolderOrder(order string) bool {
+	for _, known := range HolderOrders {
+		if known == order {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateHolder re-indexes the token holder at address, or drops it when
+// holder is nil
+func (si *StateIndex) UpdateHolder(address string, holder *TokenHolder) {
+	if si == nil {
+		return
+	}
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	si.updateHolder(address, holder)
+}
+
+// updateHolder re-indexes a token holder; callers must hold the lock
+func (si *StateIndex) updateHolder(address string, holder *TokenHolder) {
+	if holder == nil {
+		for _, index := range si.holders {
+			index.Remove(address)
+		}
+		return
+	}
+
+	si.holders[HolderOrderBalance].Set(address, holder.Balance)
+	si.holders[HolderOrderReputation].Set(address, holder.Reputation)
+	si.holders[HolderOrderJoinedAt].Set(address, timeScore(holder.JoinedAt))
+}
+
+// UpdateProposal re-indexes a proposal, or drops it when proposal is nil
+func (si *StateIndex) UpdateProposal(id types.Hash, proposal *Proposal) {
+	if si == nil {
+		return
+	}
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	si.updateProposal(id, proposal)
+}
+
+// updateProposal re-indexes a proposal; callers must hold the lock
+func (si *StateIndex) updateProposal(id types.Hash, proposal *Proposal) {
+	key := string(id[:])
+	if status, exists := si.statuses[key]; exists && (proposal == nil || status != proposal.Status) {
+		si.proposalsStatus[status].Remove(key)
+		delete(si.statuses, key)
+	}
+	if proposal == nil {
+		si.proposals.Remove(key)
+		si.schedule.Remove(key)
+		return
+	}
+	if due, undecided := proposalDue(proposal); undecided {
+		si.schedule.Set(key, due)
+	} else {
+		si.schedule.Remove(key)
+	}
+
+	score := timeScore(proposal.EndTime)
+	si.proposals.Set(key, score)
+
+	byStatus, exists := si.proposalsStatus[proposal.Status]
+	if !exists {
+		byStatus = newRankedIndex()
+		si.proposalsStatus[proposal.Status] = byStatus
+	}
+	byStatus.Set(key, score)
+	si.statuses[key] = proposal.Status
+}
+
+// Rebuild re-indexes the whole governance state, for changes made outside
+// the processor
+func (si *StateIndex) Rebuild(gs *GovernanceState) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	si.rebuild(gs)
+}
+
+// rebuild re-indexes the governance state; callers must hold the lock
+func (si *StateIndex) rebuild(gs *GovernanceState) {
+	si.reset()
+	for address, holder := range gs.TokenHolders {
+		si.updateHolder(address, holder)
+	}
+	gs.EachProposal(func(id types.Hash, proposal *Proposal, _ map[string]*Vote) bool {
+		si.updateProposal(id, proposal)
+		return true
+	})
+}
+
+// sync rebuilds the index when holders or proposals were added or removed
+// without it, as tests and tools that write the state maps directly do
+func (si *StateIndex) sync(gs *GovernanceState) {
+	si.mu.RLock()
+	stale := si.holders[HolderOrderBalance].Len() != len(gs.TokenHolders) || si.proposals.Len() != gs.ProposalCount()
+	si.mu.RUnlock()
+
+	if stale {
+		si.mu.Lock()
+		si.rebuild(gs)
+		si.mu.Unlock()
+	}
+}
+
+// HolderPage returns the addresses of a page of token holders in order,
+// together with the number of indexed holders
+func (si *StateIndex) HolderPage(order string, descending bool, offset, limit int) ([]string, int, error) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	index, exists := si.holders[order]
+	if !exists {
+		return nil, 0, NewDAOError(ErrInvalidProposal, "unknown holder order", map[string]interface{}{"order": order, "orders": HolderOrders})
+	}
+	return index.Page(descending, offset, limit), index.Len(), nil
+}
+
+// ProposalPage returns the IDs of a page of proposals ordered by end time,
+// together with the number of matches. A nil status matches every status;
+// non-zero bounds keep proposals ending at or after endAfter and before
+// endBefore.
+func (si *StateIndex) ProposalPage(status *ProposalStatus, endAfter, endBefore int64, descending bool, offset, limit int) ([]types.Hash, int) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	index := si.proposals
+	if status != nil {
+		index = si.proposalsStatus[*status]
+		if index == nil {
+			return []types.Hash{}, 0
+		}
+	}
+
+	// Bounds narrow the ranks the page is taken from
+	first, last := 0, index.Len()
+	if endAfter != 0 {
+		first = index.CountBelow(timeScore(endAfter))
+	}
+	if endBefore != 0 {
+		last = index.CountBelow(timeScore(endBefore))
+	}
+	total := last - first
+	if total <= 0 {
+		return []types.Hash{}, 0
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total {
+		return []types.Hash{}, total
+	}
+	if limit <= 0 || offset+limit > total {
+		limit = total - offset
+	}
+
+	start := first + offset
+	if descending {
+		start = last - offset - limit
+	}
+	keys := index.Slice(start, limit)
+	if descending {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	ids := make([]types.Hash, len(keys))
+	for i, key := range keys {
+		ids[i] = types.HashFromBytes([]byte(key))
+	}
+	return ids, total
+}
+
+// refreshIndexes re-indexes the holders and proposals a transaction touched
+func (d *DAO) refreshIndexes(txInner interface{}, from crypto.PublicKey, txHash types.Hash) {
+	addresses := []string{from.String()}
+	proposals := []types.Hash{txHash}
+
+	switch tx := txInner.(type) {
+	case *TreasuryTx:
+		addresses = append(addresses, tx.Recipient.String())
+	case *TokenMintTx:
+		addresses = append(addresses, tx.Recipient.String())
+	case *TokenTransferTx:
+		addresses = append(addresses, tx.Recipient.String())
+	case *TokenTransferFromTx:
+		addresses = append(addresses, tx.From.String(), tx.Recipient.String())
+	case *TokenDistributionTx:
+		for recipient := range tx.Recipients {
+			addresses = append(addresses, recipient)
+		}
+	case *PositionTransferTx:
+		addresses = append(addresses, tx.Recipient.String())
+	case *DisputeTx:
+		addresses = append(addresses, tx.Respondent.String())
+	case *JoinApprovalTx:
+		addresses = append(addresses, tx.Applicant.String())
+	case *MembershipStatusTx:
+		addresses = append(addresses, tx.Member.String())
+	case *VoteTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *ValidatorSetExecuteTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *SubDAOCreateExecuteTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *GrantExecuteTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *BountyPostTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *QFRoundOpenTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *QFContributeTx:
+		addresses = append(addresses, d.QFManager.Recipients(tx.RoundID)...)
+	case *QFPayoutTx:
+		addresses = append(addresses, d.QFManager.Recipients(tx.RoundID)...)
+	case *OptimisticChallengeTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *RPGFRoundOpenTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *RPGFNominateTx:
+		addresses = append(addresses, tx.Recipient.String())
+	case *RPGFFinalizeTx:
+		addresses = append(addresses, d.RPGFManager.Recipients(tx.RoundID)...)
+	case *OracleFeedActivateTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *TrackActivateTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *ConstitutionEnactTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *EndorseProposalTx:
+		proposals = append(proposals, tx.ProposalID)
+	case *NameTransferTx:
+		addresses = append(addresses, tx.Recipient.String())
+	case *EmergencyExecuteTx:
+		proposals = append(proposals, tx.ProposalID)
+		if spend, exists := d.EmergencySpends.GetSpend(tx.ProposalID); exists {
+			addresses = append(addresses, spend.Recipient.String())
+		}
+	case *JurorRevealTx:
+		// A resolved moderation appeal reopens the disputed proposal
+		if dispute, exists := d.DisputeManager.GetDispute(tx.DisputeID); exists {
+			proposals = append(proposals, dispute.Subject)
+		}
+	}
+
+	d.Indexes.mu.Lock()
+	defer d.Indexes.mu.Unlock()
+
+	for _, address := range addresses {
+		if holder, exists := d.GovernanceState.TokenHolders[address]; exists {
+			d.Indexes.updateHolder(address, holder)
+		}
+	}
+	for _, id := range proposals {
+		if proposal, exists := d.GovernanceState.Proposal(id); exists {
+			d.Indexes.updateProposal(id, proposal)
+		}
+	}
+}
+
+// ListTokenHolders returns the addresses of a page of token holders sorted by
+// one of the HolderOrders, together with the number of token holders
+func (d *DAO) ListTokenHolders(order string, descending bool, offset, limit int) ([]string, int, error) {
+	d.Indexes.sync(d.GovernanceState)
+	return d.Indexes.HolderPage(order, descending, offset, limit)
+}
+
+// ListProposalPage returns a page of proposals sorted by end time, optionally
+// filtered by status and by an end time window, together with the number of
+// matches
+func (d *DAO) ListProposalPage(status *ProposalStatus, endAfter, endBefore int64, descending bool, offset, limit int) ([]*Proposal, int) {
+	d.Indexes.sync(d.GovernanceState)
+
+	ids, total := d.Indexes.ProposalPage(status, endAfter, endBefore, descending, offset, limit)
+	proposals := make([]*Proposal, 0, len(ids))
+	for _, id := range ids {
+		if proposal, exists := d.GovernanceState.Proposal(id); exists {
+			proposals = append(proposals, proposal)
+		}
+	}
+	return proposals, total
+}
@@ -0,0 +1,218 @@
+package dao
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankedIndex(t *testing.T) {
+	index := newRankedIndex()
+	scores := make(map[string]uint64)
+	rng := rand.New(rand.NewSource(1))
+
+	// Random inserts, moves and removals stay in step with a sorted copy
+	for i := 0; i < 2000; i++ {
+		id := fmt.Sprintf("id-%d", rng.Intn(300))
+		if rng.Intn(4) == 0 {
+			index.Remove(id)
+			delete(scores, id)
+			continue
+		}
+		score := uint64(rng.Intn(50))
+		index.Set(id, score)
+		scores[id] = score
+	}
+
+	expected := make([]string, 0, len(scores))
+	for id := range scores {
+		expected = append(expected, id)
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		if scores[expected[i]] != scores[expected[j]] {
+			return scores[expected[i]] < scores[expected[j]]
+		}
+		return expected[i] < expected[j]
+	})
+
+	require.Equal(t, len(expected), index.Len())
+	assert.Equal(t, expected, index.Slice(0, len(expected)))
+	assert.Equal(t, expected[10:25], index.Slice(10, 15))
+	assert.Equal(t, expected[len(expected)-3:], index.Slice(len(expected)-3, 10))
+	assert.Empty(t, index.Slice(len(expected), 10))
+
+	descending := index.Page(true, 5, 4)
+	assert.Equal(t, []string{expected[len(expected)-6], expected[len(expected)-7], expected[len(expected)-8], expected[len(expected)-9]}, descending)
+
+	below := 0
+	for _, id := range expected {
+		if scores[id] < 20 {
+			below++
+		}
+	}
+	assert.Equal(t, below, index.CountBelow(20))
+}
+
+func TestTimeScore(t *testing.T) {
+	assert.Less(t, timeScore(-5), timeScore(0))
+	assert.Less(t, timeScore(0), timeScore(1700000000))
+}
+
+func TestStateIndexHolders(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	sender := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		sender.String():    10000,
+		recipient.String(): 500,
+	}))
+
+	addresses, total, err := dao.ListTokenHolders(HolderOrderBalance, true, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{sender.String(), recipient.String()}, addresses)
+
+	// Transfers re-rank both parties as they apply
+	tx := &TokenTransferTx{Fee: 10, Recipient: recipient, Amount: 9000}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, sender, randomTreasuryHash()))
+
+	addresses, _, err = dao.ListTokenHolders(HolderOrderBalance, true, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{recipient.String()}, addresses)
+
+	addresses, _, err = dao.ListTokenHolders(HolderOrderBalance, false, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{recipient.String()}, addresses)
+
+	_, _, err = dao.ListTokenHolders("stake", false, 0, 10)
+	assert.Error(t, err)
+}
+
+func TestStateIndexProposals(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	now := time.Now().Unix()
+
+	for i, status := range []ProposalStatus{ProposalStatusActive, ProposalStatusPending, ProposalStatusActive, ProposalStatusPassed} {
+		id := types.Hash{byte(i + 1)}
+		dao.GovernanceState.Proposals[id] = &Proposal{ID: id, Creator: creator, Status: status, StartTime: now + 3600, EndTime: now + int64(i+1)*3600, Results: &VoteResults{}}
+	}
+
+	proposals, total := dao.ListProposalPage(nil, 0, 0, false, 0, 10)
+	require.Equal(t, 4, total)
+	assert.Equal(t, types.Hash{0x01}, proposals[0].ID)
+	assert.Equal(t, types.Hash{0x04}, proposals[3].ID)
+
+	active := ProposalStatusActive
+	proposals, total = dao.ListProposalPage(&active, 0, 0, true, 0, 10)
+	require.Equal(t, 2, total)
+	assert.Equal(t, types.Hash{0x03}, proposals[0].ID)
+	assert.Equal(t, types.Hash{0x01}, proposals[1].ID)
+
+	// End time windows are half open
+	proposals, total = dao.ListProposalPage(nil, now+2*3600, now+4*3600, false, 1, 10)
+	assert.Equal(t, 2, total)
+	require.Len(t, proposals, 1)
+	assert.Equal(t, types.Hash{0x03}, proposals[0].ID)
+
+	// Status changes move proposals between the status indexes
+	require.NoError(t, dao.ProposalManager.CancelProposal(types.Hash{0x02}, creator))
+	pending := ProposalStatusPending
+	_, total = dao.ListProposalPage(&pending, 0, 0, false, 0, 10)
+	assert.Equal(t, 0, total)
+	cancelled := ProposalStatusCancelled
+	proposals, total = dao.ListProposalPage(&cancelled, 0, 0, false, 0, 10)
+	require.Equal(t, 1, total)
+	assert.Equal(t, types.Hash{0x02}, proposals[0].ID)
+}
+
+func TestProposalSchedule(t *testing.T) {
+	schedule := newProposalSchedule()
+	due := make(map[string]int64)
+	rng := rand.New(rand.NewSource(1))
+
+	// Random schedules, moves and removals stay in step with a map
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key-%d", rng.Intn(300))
+		if rng.Intn(4) == 0 {
+			schedule.Remove(key)
+			delete(due, key)
+			continue
+		}
+		at := int64(rng.Intn(100))
+		schedule.Set(key, at)
+		due[key] = at
+	}
+
+	var expected []string
+	for key, at := range due {
+		if at <= 40 {
+			expected = append(expected, key)
+		}
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		if due[expected[i]] != due[expected[j]] {
+			return due[expected[i]] < due[expected[j]]
+		}
+		return expected[i] < expected[j]
+	})
+
+	assert.Equal(t, expected, schedule.PopDue(40))
+	assert.Equal(t, len(due)-len(expected), schedule.Len())
+	assert.Empty(t, schedule.PopDue(40))
+}
+
+func TestDAO_UpdateDueProposalStatuses(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	creator := crypto.GeneratePrivateKey().PublicKey()
+	now := time.Now().Unix()
+
+	proposals := []*Proposal{
+		{Status: ProposalStatusActive, StartTime: now - 7200, EndTime: now - 10},   // Ended
+		{Status: ProposalStatusPending, StartTime: now - 5, EndTime: now + 3600},   // Started
+		{Status: ProposalStatusActive, StartTime: now - 7200, EndTime: now + 3600}, // Running
+		{Status: ProposalStatusPassed, StartTime: now - 7200, EndTime: now - 3600}, // Decided
+		{Status: ProposalStatusPending, StartTime: now + 3600, EndTime: now + 7200},
+	}
+	for i, proposal := range proposals {
+		proposal.ID = types.Hash{byte(i + 1)}
+		proposal.Creator = creator
+		proposal.Results = &VoteResults{}
+		dao.GovernanceState.Proposals[proposal.ID] = proposal
+	}
+
+	// Only the proposals whose start or end passed are visited
+	assert.Equal(t, 2, dao.UpdateDueProposalStatuses())
+	assert.Equal(t, ProposalStatusRejected, proposals[0].Status)
+	assert.Equal(t, ProposalStatusActive, proposals[1].Status)
+	assert.Equal(t, ProposalStatusActive, proposals[2].Status)
+	assert.Equal(t, ProposalStatusPending, proposals[4].Status)
+	assert.Equal(t, 0, dao.UpdateDueProposalStatuses())
+
+	// Re-indexing a moved end time reschedules the proposal
+	proposals[2].EndTime = now - 1
+	dao.Indexes.UpdateProposal(proposals[2].ID, proposals[2])
+	assert.Equal(t, 1, dao.UpdateDueProposalStatuses())
+	assert.Equal(t, ProposalStatusRejected, proposals[2].Status)
+}
+
+func BenchmarkStateIndexHolderPage(b *testing.B) {
+	index := NewStateIndex()
+	for i := 0; i < 100000; i++ {
+		index.UpdateHolder(fmt.Sprintf("holder-%d", i), &TokenHolder{Balance: uint64(i * 7 % 100003), Reputation: uint64(i % 997), JoinedAt: int64(i)})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := index.HolderPage(HolderOrderBalance, true, 50000, 50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package dao
+
+import (
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// StateMachine applies DAO transactions once they are included in the chain
+// and exposes the resulting state as leaves of the committed state tree
+type StateMachine interface {
+	ApplyDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash, height uint32) error
+	StateLeaves() ([]StateLeaf, error)
+}
+
+// StatePruner is implemented by state machines that drop stale state as
+// blocks are added. Pruning may only depend on the block time and on-chain
+// state, so that every node keeps committing the same state root.
+type StatePruner interface {
+	PruneState(blockTime int64) int
+}
+
+// ParameterSource is implemented by state machines with governance
+// parameters. Parameters are not committed to the state root, but are
+// journaled so they can be queried at past heights.
+type ParameterSource interface {
+	GetParameterConfig() *ParameterConfig
+}
+
+// ChainSubmitter receives the DAO transactions processed directly by a DAO
+// whose state the chain owns, so that block processing stays the single
+// source of truth for DAO state. Chains refuse them, as only transactions
+// signed by their sender and included in a block may change the state.
+type ChainSubmitter interface {
+	SubmitDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash) error
+}
+
+var (
+	_ StateMachine    = (*DAO)(nil)
+	_ StatePruner     = (*DAO)(nil)
+	_ ParameterSource = (*DAO)(nil)
+)
+
+// ApplyDAOTransaction applies a DAO transaction at the given block height.
+// It is called by the chain and must not be used to bypass it.
+func (d *DAO) ApplyDAOTransaction(txInner interface{}, from crypto.PublicKey, txHash types.Hash, height uint32) error {
+	fromStr := from.String()
+	balanceBefore := d.TokenState.Balances.Get(fromStr)
+
+	if err := d.dispatchDAOTransaction(txInner, from, txHash); err != nil {
+		return err
+	}
+
+	d.ParameterManager.observeHeight(height)
+	d.refreshIndexes(txInner, from, txHash)
+	d.ActivityIndex.RecordTransaction(txInner, from, txHash, height)
+
+	// Claims pay out of DAO state, so what they paid shows in the balance
+	if IsClaimActivity(ActivityTypeOf(txInner)) {
+		if received := d.TokenState.Balances.Get(fromStr) + TxFee(txInner); received > balanceBefore {
+			d.ActivityIndex.SetClaimedAmount(fromStr, txHash, received-balanceBefore)
+		}
+	}
+
+	// Executed multisig transactions also show up in the account's history
+	if executed, ok := d.MultisigManager.ExecutedBy(txHash); ok {
+		d.ActivityIndex.RecordTransaction(executed.Tx, MultisigAccountKey(executed.MultisigID), executed.ID, height)
+	}
+
+	d.listenersMu.RLock()
+	defer d.listenersMu.RUnlock()
+	for _, fn := range d.appliedListeners {
+		fn(txInner, from, txHash)
+	}
+
+	return nil
+}
+
+// OnTransactionApplied calls fn after every DAO transaction that applies, so
+// state derived off-chain, such as cached responses, can be refreshed. fn
+// runs on the block processing path and must not block.
+func (d *DAO) OnTransactionApplied(fn func(txInner interface{}, from crypto.PublicKey, txHash types.Hash)) {
+	d.listenersMu.Lock()
+	defer d.listenersMu.Unlock()
+
+	d.appliedListeners = append(d.appliedListeners, fn)
+}
+
+// PruneState drops finalized vote maps older than the governance-set vote
+// retention period
+func (d *DAO) PruneState(blockTime int64) int {
+	retention := d.ParameterManager.GetParameterConfig().VoteRetentionPeriod
+	if retention == 0 {
+		return 0
+	}
+
+	return d.GovernanceState.PruneFinalizedVotes(blockTime - retention)
+}
+
+// CollectBlockFees shares the DAO fees paid in a block with its producer
+// and the producer's delegators
+func (d *DAO) CollectBlockFees(producer crypto.PublicKey, fees uint64, height uint32) {
+	d.ParameterManager.observeHeight(height)
+	d.ValidatorManager.CollectBlockFees(producer, fees)
+}
+
+// SetChainSubmitter makes ProcessDAOTransaction hand transactions to the
+// given chain instead of applying them
+func (d *DAO) SetChainSubmitter(submitter ChainSubmitter) {
+	d.chainSubmitter = submitter
+}
+
+// SetClock makes the governance state machine read the time from clock
+// instead of the wall clock, so simulations can run it at an accelerated,
+// reproducible time. Subsystems outside proposals, votes and delegations
+// keep the wall clock.
+func (d *DAO) SetClock(clock func() int64) {
+	d.GovernanceState.clock = clock
+}
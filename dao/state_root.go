@@ -0,0 +1,296 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Domain separation prefixes keep leaf and inner node hashes distinct
+const (
+	stateLeafPrefix byte = 0x00
+	stateNodePrefix byte = 0x01
+)
+
+// StateLeaf is a single keyed entry of the committed DAO state
+type StateLeaf struct {
+	Key   string
+	Value []byte
+}
+
+// Hash returns the Merkle leaf hash of the entry
+func (l StateLeaf) Hash() types.Hash {
+	h := sha256.New()
+	h.Write([]byte{stateLeafPrefix})
+	h.Write([]byte(l.Key))
+	h.Write([]byte{0})
+	h.Write(l.Value)
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// treasuryLeaf is the committed form of the treasury, pending transactions
+// are committed as separate leaves
+type treasuryLeaf struct {
+	Balance              uint64
+	Signers              []crypto.PublicKey
+	RequiredSigs         uint8
+	TotalInflows         uint64
+	YieldDistributed     uint64
+	FeesSponsored        uint64
+	ParticipationRewards uint64
+}
+
+// tokenLeaf is the committed form of the token metadata
+type tokenLeaf struct {
+	Symbol      string
+	Name        string
+	TotalSupply uint64
+	Decimals    uint8
+}
+
+// StateLeaves returns every entry of the governance and token state, sorted by key
+func StateLeaves(gs *GovernanceState, token *GovernanceToken) ([]StateLeaf, error) {
+	values := make(map[string]interface{})
+
+	for id, proposal := range gs.Proposals {
+		values[ProposalStateKey(id)] = proposal
+	}
+
+	for proposalID, votes := range gs.Votes {
+		for voter, vote := range votes {
+			values[VoteStateKey(proposalID, voter)] = vote
+		}
+	}
+
+	for delegator, delegation := range gs.Delegations {
+		values[DelegationStateKey(delegator)] = delegation
+	}
+
+	for address, holder := range gs.TokenHolders {
+		values["holder/"+address] = holder
+	}
+
+	if gs.Treasury != nil {
+		values["treasury"] = treasuryLeaf{
+			Balance:              gs.Treasury.Balance,
+			Signers:              gs.Treasury.Signers,
+			RequiredSigs:         gs.Treasury.RequiredSigs,
+			TotalInflows:         gs.Treasury.TotalInflows,
+			YieldDistributed:     gs.Treasury.YieldDistributed,
+			FeesSponsored:        gs.Treasury.FeesSponsored,
+			ParticipationRewards: gs.Treasury.ParticipationRewards,
+		}
+
+		for id, tx := range gs.Treasury.Transactions {
+			values["treasury_tx/"+id.String()] = tx
+		}
+	}
+
+	if gs.Config != nil {
+		values["config"] = gs.Config
+	}
+
+	if token != nil {
+		values["token"] = tokenLeaf{
+			Symbol:      token.Symbol,
+			Name:        token.Name,
+			TotalSupply: token.TotalSupply,
+			Decimals:    token.Decimals,
+		}
+
+		for address, balance := range token.Balances.Snapshot() {
+			values[BalanceStateKey(address)] = balance
+		}
+
+		for owner, allowances := range token.Allowances {
+			for spender, amount := range allowances {
+				values["allowance/"+owner+"/"+spender] = amount
+			}
+		}
+	}
+
+	leaves := make([]StateLeaf, 0, len(values))
+	for key, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode state leaf %s: %w", key, err)
+		}
+		leaves = append(leaves, StateLeaf{Key: key, Value: encoded})
+	}
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].Key < leaves[j].Key
+	})
+
+	return leaves, nil
+}
+
+// ParametersStateKey is the history key of the governance parameters, which
+// are journaled alongside the state leaves but not committed to the root
+const ParametersStateKey = "parameters"
+
+// ProposalStateKey returns the state key of a proposal
+func ProposalStateKey(proposalID types.Hash) string {
+	return "proposal/" + proposalID.String()
+}
+
+// VoteStateKey returns the state key of a vote
+func VoteStateKey(proposalID types.Hash, voter string) string {
+	return "vote/" + proposalID.String() + "/" + voter
+}
+
+// DelegationStateKey returns the state key of a delegation
+func DelegationStateKey(delegator string) string {
+	return "delegation/" + delegator
+}
+
+// BalanceStateKey returns the state key of a token balance
+func BalanceStateKey(address string) string {
+	return "balance/" + address
+}
+
+// ComputeStateRoot returns the Merkle root committing to the governance and token state
+func ComputeStateRoot(gs *GovernanceState, token *GovernanceToken) (types.Hash, error) {
+	leaves, err := StateLeaves(gs, token)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return StateRootFromLeaves(leaves), nil
+}
+
+// StateRootFromLeaves returns the Merkle root of sorted state leaves
+func StateRootFromLeaves(leaves []StateLeaf) types.Hash {
+	return MerkleRoot(leafHashes(leaves))
+}
+
+// leafHashes returns the Merkle leaf hashes of state leaves
+func leafHashes(leaves []StateLeaf) []types.Hash {
+	hashes := make([]types.Hash, len(leaves))
+	for i, leaf := range leaves {
+		hashes[i] = leaf.Hash()
+	}
+	return hashes
+}
+
+// MerkleRoot folds leaf hashes into a binary Merkle root. An odd node at the
+// end of a level is carried up unchanged.
+func MerkleRoot(hashes []types.Hash) types.Hash {
+	if len(hashes) == 0 {
+		return types.Hash{}
+	}
+
+	level := hashes
+	for len(level) > 1 {
+		level = nextMerkleLevel(level)
+	}
+
+	return level[0]
+}
+
+// nextMerkleLevel hashes pairs of nodes into the level above
+func nextMerkleLevel(level []types.Hash) []types.Hash {
+	next := make([]types.Hash, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			continue
+		}
+		next = append(next, hashStateNode(level[i], level[i+1]))
+	}
+	return next
+}
+
+// hashStateNode hashes two child nodes into their parent
+func hashStateNode(left, right types.Hash) types.Hash {
+	h := sha256.New()
+	h.Write([]byte{stateNodePrefix})
+	h.Write(left[:])
+	h.Write(right[:])
+	return types.HashFromBytes(h.Sum(nil))
+}
+
+// StateRoot returns the current state root of the DAO
+func (d *DAO) StateRoot() (types.Hash, error) {
+	leaves, err := d.StateLeaves()
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return StateRootFromLeaves(leaves), nil
+}
+
+// StateLeaves returns the current committed entries of the DAO state,
+// including those moved to the archive
+func (d *DAO) StateLeaves() ([]StateLeaf, error) {
+	leaves, err := StateLeaves(d.GovernanceState, d.TokenState)
+	if err != nil || d.Archive == nil {
+		return leaves, err
+	}
+
+	leaves = append(leaves, d.Archive.StateLeaves()...)
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].Key < leaves[j].Key
+	})
+	return leaves, nil
+}
+
+// ProofStep is one sibling hash on the path from a leaf to the root
+type ProofStep struct {
+	Hash types.Hash
+	Left bool // Sibling sits to the left of the running hash
+}
+
+// StateProof proves that a key holds a value under a state root
+type StateProof struct {
+	Key   string
+	Value []byte
+	Steps []ProofStep
+}
+
+// BuildStateProof returns a Merkle inclusion proof for key in sorted leaves
+func BuildStateProof(leaves []StateLeaf, key string) (*StateProof, error) {
+	index := sort.Search(len(leaves), func(i int) bool {
+		return leaves[i].Key >= key
+	})
+	if index == len(leaves) || leaves[index].Key != key {
+		return nil, fmt.Errorf("key %s not found in state", key)
+	}
+
+	proof := &StateProof{
+		Key:   key,
+		Value: leaves[index].Value,
+		Steps: make([]ProofStep, 0),
+	}
+
+	level := leafHashes(leaves)
+	for len(level) > 1 {
+		// A carried odd node has no sibling on this level
+		if index%2 == 1 {
+			proof.Steps = append(proof.Steps, ProofStep{Hash: level[index-1], Left: true})
+		} else if index+1 < len(level) {
+			proof.Steps = append(proof.Steps, ProofStep{Hash: level[index+1], Left: false})
+		}
+
+		level = nextMerkleLevel(level)
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// Verify reports whether the proof resolves to the given state root
+func (p *StateProof) Verify(root types.Hash) bool {
+	hash := StateLeaf{Key: p.Key, Value: p.Value}.Hash()
+	for _, step := range p.Steps {
+		if step.Left {
+			hash = hashStateNode(step.Hash, hash)
+		} else {
+			hash = hashStateNode(hash, step.Hash)
+		}
+	}
+	return hash == root
+}
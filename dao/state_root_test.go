@@ -0,0 +1,135 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateRoot_Deterministic(t *testing.T) {
+	holders := make([]crypto.PublicKey, 5)
+	distribution := make(map[string]uint64)
+	for i := range holders {
+		holders[i] = crypto.GeneratePrivateKey().PublicKey()
+		distribution[holders[i].String()] = uint64(1000 * (i + 1))
+	}
+
+	dao1 := NewDAO("GOV", "Governance Token", 18)
+	dao2 := NewDAO("GOV", "Governance Token", 18)
+	require.NoError(t, dao1.InitialTokenDistribution(distribution))
+	require.NoError(t, dao2.InitialTokenDistribution(distribution))
+
+	// TokenHolders carry join timestamps, align them so only content matters
+	for address, holder := range dao1.GovernanceState.TokenHolders {
+		*dao2.GovernanceState.TokenHolders[address] = *holder
+	}
+
+	root1, err := dao1.StateRoot()
+	require.NoError(t, err)
+	root2, err := dao2.StateRoot()
+	require.NoError(t, err)
+
+	assert.False(t, root1.IsZero())
+	assert.Equal(t, root1, root2)
+
+	// Recomputing without changes yields the same root
+	again, err := dao1.StateRoot()
+	require.NoError(t, err)
+	assert.Equal(t, root1, again)
+}
+
+func TestStateRoot_ChangesWithState(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	root, err := dao.StateRoot()
+	require.NoError(t, err)
+
+	require.NoError(t, dao.TransferTokens(holder, recipient, 100))
+	afterTransfer, err := dao.StateRoot()
+	require.NoError(t, err)
+	assert.NotEqual(t, root, afterTransfer)
+
+	dao.GovernanceState.Treasury.Transactions[types.Hash{1}] = &PendingTx{ID: types.Hash{1}, Amount: 10}
+	afterTreasury, err := dao.StateRoot()
+	require.NoError(t, err)
+	assert.NotEqual(t, afterTransfer, afterTreasury)
+}
+
+func TestStateLeaves_SortedAndKeyed(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	leaves, err := StateLeaves(dao.GovernanceState, dao.TokenState)
+	require.NoError(t, err)
+
+	keys := make([]string, len(leaves))
+	for i, leaf := range leaves {
+		keys[i] = leaf.Key
+	}
+
+	assert.IsIncreasing(t, keys)
+	assert.Contains(t, keys, "balance/"+holder.String())
+	assert.Contains(t, keys, "holder/"+holder.String())
+	assert.Contains(t, keys, "treasury")
+	assert.Contains(t, keys, "token")
+}
+
+func TestMerkleRoot(t *testing.T) {
+	assert.True(t, MerkleRoot(nil).IsZero())
+
+	a, b, c := types.Hash{1}, types.Hash{2}, types.Hash{3}
+	assert.Equal(t, a, MerkleRoot([]types.Hash{a}))
+	assert.Equal(t, hashStateNode(a, b), MerkleRoot([]types.Hash{a, b}))
+	assert.Equal(t, hashStateNode(hashStateNode(a, b), c), MerkleRoot([]types.Hash{a, b, c}))
+	assert.NotEqual(t, MerkleRoot([]types.Hash{a, b}), MerkleRoot([]types.Hash{b, a}))
+}
+
+func TestStateProof_VerifiesEveryLeaf(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	distribution := make(map[string]uint64)
+	for i := 0; i < 6; i++ {
+		distribution[crypto.GeneratePrivateKey().PublicKey().String()] = uint64(100 * (i + 1))
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+
+	leaves, err := dao.StateLeaves()
+	require.NoError(t, err)
+	root := StateRootFromLeaves(leaves)
+
+	// Odd leaf counts exercise carried nodes
+	require.Equal(t, 1, len(leaves)%2)
+
+	for _, leaf := range leaves {
+		proof, err := BuildStateProof(leaves, leaf.Key)
+		require.NoError(t, err)
+		assert.True(t, proof.Verify(root), leaf.Key)
+	}
+}
+
+func TestStateProof_RejectsTampering(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	holder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{holder.String(): 5000}))
+
+	leaves, err := dao.StateLeaves()
+	require.NoError(t, err)
+	root := StateRootFromLeaves(leaves)
+
+	proof, err := BuildStateProof(leaves, BalanceStateKey(holder.String()))
+	require.NoError(t, err)
+	assert.Equal(t, "5000", string(proof.Value))
+	require.True(t, proof.Verify(root))
+
+	proof.Value = []byte("9999")
+	assert.False(t, proof.Verify(root))
+
+	_, err = BuildStateProof(leaves, BalanceStateKey("unknown"))
+	assert.Error(t, err)
+}
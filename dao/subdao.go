@@ -0,0 +1,453 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxSubDAOMembers bounds the member list of a sub-DAO
+const MaxSubDAOMembers = 100
+
+// SubDAOAction is what a sub-DAO proposal does once its members approve it
+type SubDAOAction byte
+
+const (
+	SubDAOActionSpend        SubDAOAction = 0x01 // Pay Amount from the sub-DAO treasury to Target
+	SubDAOActionAddMember    SubDAOAction = 0x02
+	SubDAOActionRemoveMember SubDAOAction = 0x03
+	SubDAOActionSignal       SubDAOAction = 0x04 // Records a decision without side effects
+)
+
+// SubDAOProposalStatus is the stage of a sub-DAO proposal
+type SubDAOProposalStatus string
+
+const (
+	SubDAOProposalActive   SubDAOProposalStatus = "active"
+	SubDAOProposalExecuted SubDAOProposalStatus = "executed"
+	SubDAOProposalRejected SubDAOProposalStatus = "rejected"
+	SubDAOProposalExpired  SubDAOProposalStatus = "expired"
+)
+
+// Proposal returns the main DAO proposal voting on the sub-DAO
+func (tx *SubDAOCreateProposalTx) Proposal() *ProposalTx {
+	title := "Create sub-DAO " + tx.Name
+
+	description := tx.Description
+	if description == "" {
+		description = title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        title,
+		Description:  description,
+		ProposalType: ProposalTypeTreasury,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// SubDAOCharter is the sub-DAO a passed main DAO proposal creates
+type SubDAOCharter struct {
+	Name           string
+	Description    string
+	Members        []crypto.PublicKey
+	Budget         uint64
+	StreamDuration int64
+}
+
+// SubDAO is a working group with its own members, a budget streamed from
+// the main treasury and a lightweight proposal namespace. Its ID is the ID
+// of the main DAO proposal that created it.
+type SubDAO struct {
+	ID          types.Hash
+	Name        string
+	Description string
+	Members     []crypto.PublicKey
+	Budget      uint64 // Total allocation streamed over the stream period
+	StreamStart int64
+	StreamEnd   int64
+	Streamed    uint64 // Moved from the main treasury so far
+	Spent       uint64
+	CreatedAt   int64
+}
+
+// Balance returns the streamed funds the sub-DAO has not spent yet
+func (s *SubDAO) Balance() uint64 {
+	return s.Streamed - s.Spent
+}
+
+// Vested returns the part of the budget released by time now
+func (s *SubDAO) Vested(now int64) uint64 {
+	return linearlyVested(s.Budget, s.StreamStart, s.StreamEnd, now)
+}
+
+// linearlyVested returns the part of total released by time now when it
+// streams linearly from start to end
+func linearlyVested(total uint64, start, end, now int64) uint64 {
+	if now >= end {
+		return total
+	}
+	if now <= start {
+		return 0
+	}
+
+	// Split the product to avoid overflowing large budgets
+	duration := uint64(end - start)
+	elapsed := uint64(now - start)
+	return total/duration*elapsed + total%duration*elapsed/duration
+}
+
+// IsMember reports whether key is a member of the sub-DAO
+func (s *SubDAO) IsMember(key crypto.PublicKey) bool {
+	return s.memberIndex(key) >= 0
+}
+
+func (s *SubDAO) memberIndex(key crypto.PublicKey) int {
+	keyStr := key.String()
+	for i, member := range s.Members {
+		if member.String() == keyStr {
+			return i
+		}
+	}
+	return -1
+}
+
+// SubDAOProposal is a proposal decided by the members of a sub-DAO, one
+// member one vote. It executes as soon as a majority of the members
+// approves it and is rejected once a majority can no longer be reached.
+type SubDAOProposal struct {
+	ID          types.Hash
+	SubDAOID    types.Hash
+	Proposer    crypto.PublicKey
+	Title       string
+	Description string
+	Action      SubDAOAction
+	Target      crypto.PublicKey // Recipient or member the action applies to
+	Amount      uint64
+	CreatedAt   int64
+	EndTime     int64
+	Votes       map[string]bool // Member -> approves
+	YesVotes    uint64
+	NoVotes     uint64
+	Status      SubDAOProposalStatus
+	ResolvedAt  int64
+}
+
+// StatusAt returns the status of the proposal at time now, active
+// proposals expire once their voting window has ended
+func (p *SubDAOProposal) StatusAt(now int64) SubDAOProposalStatus {
+	if p.Status == SubDAOProposalActive && now > p.EndTime {
+		return SubDAOProposalExpired
+	}
+	return p.Status
+}
+
+// SubDAOManager keeps the sub-DAOs and their proposals
+type SubDAOManager struct {
+	governanceState *GovernanceState
+	tokenState      *GovernanceToken
+	charters        map[types.Hash]*SubDAOCharter // Keyed by main DAO proposal
+	subDAOs         map[types.Hash]*SubDAO
+	proposals       map[types.Hash]*SubDAOProposal
+}
+
+// NewSubDAOManager creates a new sub-DAO manager
+func NewSubDAOManager(governanceState *GovernanceState, tokenState *GovernanceToken) *SubDAOManager {
+	return &SubDAOManager{
+		governanceState: governanceState,
+		tokenState:      tokenState,
+		charters:        make(map[types.Hash]*SubDAOCharter),
+		subDAOs:         make(map[types.Hash]*SubDAO),
+		proposals:       make(map[types.Hash]*SubDAOProposal),
+	}
+}
+
+// CheckCharter verifies that no sub-DAO already uses the name of a charter
+func (sm *SubDAOManager) CheckCharter(name string) error {
+	for _, subDAO := range sm.subDAOs {
+		if subDAO.Name == name {
+			return NewDAOError(ErrInvalidProposal, "a sub-DAO with this name already exists", nil)
+		}
+	}
+	return nil
+}
+
+// RecordCharter remembers the sub-DAO a main DAO proposal creates once it
+// passes
+func (sm *SubDAOManager) RecordCharter(proposalID types.Hash, tx *SubDAOCreateProposalTx) {
+	sm.charters[proposalID] = &SubDAOCharter{
+		Name:           tx.Name,
+		Description:    tx.Description,
+		Members:        append([]crypto.PublicKey(nil), tx.Members...),
+		Budget:         tx.Budget,
+		StreamDuration: tx.StreamDuration,
+	}
+}
+
+// GetCharter returns the sub-DAO charter of a main DAO proposal
+func (sm *SubDAOManager) GetCharter(proposalID types.Hash) (*SubDAOCharter, bool) {
+	charter, exists := sm.charters[proposalID]
+	return charter, exists
+}
+
+// ProcessSubDAOCreateExecuteTx creates the sub-DAO of a passed proposal and
+// starts streaming its budget
+func (sm *SubDAOManager) ProcessSubDAOCreateExecuteTx(tx *SubDAOCreateExecuteTx, executor crypto.PublicKey) error {
+	charter, exists := sm.charters[tx.ProposalID]
+	if !exists {
+		return NewDAOError(ErrInvalidProposal, "proposal does not create a sub-DAO", nil)
+	}
+
+	proposal, exists := sm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	// Another sub-DAO may have taken the name meanwhile
+	if err := sm.CheckCharter(charter.Name); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	sm.tokenState.Balances.Sub(executor.String(), uint64(tx.Fee))
+	sm.subDAOs[tx.ProposalID] = &SubDAO{
+		ID:          tx.ProposalID,
+		Name:        charter.Name,
+		Description: charter.Description,
+		Members:     charter.Members,
+		Budget:      charter.Budget,
+		StreamStart: now,
+		StreamEnd:   now + charter.StreamDuration,
+		CreatedAt:   now,
+	}
+	proposal.Status = ProposalStatusExecuted
+	delete(sm.charters, tx.ProposalID)
+
+	return nil
+}
+
+// StreamBudget moves the budget released since the last stream from the
+// main treasury to the sub-DAO. When the treasury runs short the remainder
+// keeps accruing and is streamed later.
+func (sm *SubDAOManager) StreamBudget(subDAO *SubDAO, now int64) uint64 {
+	vested := subDAO.Vested(now)
+	if vested <= subDAO.Streamed {
+		return 0
+	}
+
+	owed := vested - subDAO.Streamed
+	if treasury := sm.governanceState.Treasury.Balance; owed > treasury {
+		owed = treasury
+	}
+
+	sm.governanceState.Treasury.Balance -= owed
+	subDAO.Streamed += owed
+	return owed
+}
+
+// StreamAll streams the released budgets of every sub-DAO, oldest first
+func (sm *SubDAOManager) StreamAll(now int64) uint64 {
+	streamed := uint64(0)
+	for _, subDAO := range sm.ListSubDAOs() {
+		streamed += sm.StreamBudget(subDAO, now)
+	}
+	return streamed
+}
+
+// ProcessSubDAOProposalTx opens a proposal in the namespace of a sub-DAO
+func (sm *SubDAOManager) ProcessSubDAOProposalTx(tx *SubDAOProposalTx, proposer crypto.PublicKey, txHash types.Hash) error {
+	subDAO, exists := sm.subDAOs[tx.SubDAOID]
+	if !exists {
+		return ErrSubDAONotFoundError
+	}
+	if !subDAO.IsMember(proposer) {
+		return NewDAOError(ErrUnauthorized, "only sub-DAO members can propose", nil)
+	}
+	if _, exists := sm.proposals[txHash]; exists {
+		return NewDAOError(ErrInvalidProposal, "sub-DAO proposal already exists", nil)
+	}
+
+	switch tx.Action {
+	case SubDAOActionAddMember:
+		if subDAO.IsMember(tx.Target) {
+			return NewDAOError(ErrInvalidProposal, "address is already a sub-DAO member", nil)
+		}
+		if len(subDAO.Members) >= MaxSubDAOMembers {
+			return NewDAOError(ErrInvalidProposal, "sub-DAO has the maximum number of members", nil)
+		}
+	case SubDAOActionRemoveMember:
+		if !subDAO.IsMember(tx.Target) {
+			return NewDAOError(ErrInvalidProposal, "address is not a sub-DAO member", nil)
+		}
+		if len(subDAO.Members) == 1 {
+			return NewDAOError(ErrInvalidProposal, "cannot remove the last sub-DAO member", nil)
+		}
+	}
+
+	now := time.Now().Unix()
+	sm.tokenState.Balances.Sub(proposer.String(), uint64(tx.Fee))
+	sm.proposals[txHash] = &SubDAOProposal{
+		ID:          txHash,
+		SubDAOID:    tx.SubDAOID,
+		Proposer:    proposer,
+		Title:       tx.Title,
+		Description: tx.Description,
+		Action:      tx.Action,
+		Target:      tx.Target,
+		Amount:      tx.Amount,
+		CreatedAt:   now,
+		EndTime:     now + tx.Duration,
+		Votes:       make(map[string]bool),
+		Status:      SubDAOProposalActive,
+	}
+
+	return nil
+}
+
+// ProcessSubDAOVoteTx records a member's vote and resolves the proposal
+// once a majority is reached either way
+func (sm *SubDAOManager) ProcessSubDAOVoteTx(tx *SubDAOVoteTx, voter crypto.PublicKey) error {
+	subDAO, exists := sm.subDAOs[tx.SubDAOID]
+	if !exists {
+		return ErrSubDAONotFoundError
+	}
+
+	proposal, exists := sm.proposals[tx.ProposalID]
+	if !exists || proposal.SubDAOID != tx.SubDAOID {
+		return NewDAOError(ErrProposalNotFound, "sub-DAO proposal not found", nil)
+	}
+
+	now := time.Now().Unix()
+	sm.expire(proposal, now)
+	if proposal.Status != SubDAOProposalActive {
+		return NewDAOError(ErrVotingClosed, "sub-DAO proposal is not active", nil)
+	}
+
+	if !subDAO.IsMember(voter) {
+		return NewDAOError(ErrUnauthorized, "only sub-DAO members can vote", nil)
+	}
+	voterStr := voter.String()
+	if _, voted := proposal.Votes[voterStr]; voted {
+		return ErrDuplicateVoteError
+	}
+
+	// A majority of the current members decides
+	majority := uint64(len(subDAO.Members)/2 + 1)
+	yes, no := proposal.YesVotes, proposal.NoVotes
+	if tx.Support {
+		yes++
+	} else {
+		no++
+	}
+
+	if yes >= majority {
+		if err := sm.execute(subDAO, proposal, now); err != nil {
+			return err
+		}
+		proposal.Status = SubDAOProposalExecuted
+		proposal.ResolvedAt = now
+	} else if no > uint64(len(subDAO.Members))-majority {
+		proposal.Status = SubDAOProposalRejected
+		proposal.ResolvedAt = now
+	}
+
+	sm.tokenState.Balances.Sub(voterStr, uint64(tx.Fee))
+	proposal.Votes[voterStr] = tx.Support
+	proposal.YesVotes, proposal.NoVotes = yes, no
+
+	return nil
+}
+
+// execute applies the action of an approved proposal
+func (sm *SubDAOManager) execute(subDAO *SubDAO, proposal *SubDAOProposal, now int64) error {
+	switch proposal.Action {
+	case SubDAOActionSpend:
+		sm.StreamBudget(subDAO, now)
+		if subDAO.Balance() < proposal.Amount {
+			return NewDAOError(ErrTreasuryInsufficient, "insufficient streamed sub-DAO funds", map[string]interface{}{
+				"balance": subDAO.Balance(),
+				"amount":  proposal.Amount,
+			})
+		}
+		subDAO.Spent += proposal.Amount
+		sm.tokenState.Balances.Add(proposal.Target.String(), proposal.Amount)
+	case SubDAOActionAddMember:
+		if subDAO.IsMember(proposal.Target) {
+			return NewDAOError(ErrInvalidProposal, "address is already a sub-DAO member", nil)
+		}
+		subDAO.Members = append(subDAO.Members, proposal.Target)
+	case SubDAOActionRemoveMember:
+		index := subDAO.memberIndex(proposal.Target)
+		if index < 0 || len(subDAO.Members) == 1 {
+			return NewDAOError(ErrInvalidProposal, "member cannot be removed", nil)
+		}
+		members := make([]crypto.PublicKey, 0, len(subDAO.Members)-1)
+		members = append(members, subDAO.Members[:index]...)
+		subDAO.Members = append(members, subDAO.Members[index+1:]...)
+	}
+
+	return nil
+}
+
+// expire closes an active proposal whose voting window has ended
+func (sm *SubDAOManager) expire(proposal *SubDAOProposal, now int64) {
+	if status := proposal.StatusAt(now); status != proposal.Status {
+		proposal.Status = status
+		proposal.ResolvedAt = proposal.EndTime
+	}
+}
+
+// GetSubDAO returns a sub-DAO
+func (sm *SubDAOManager) GetSubDAO(id types.Hash) (*SubDAO, bool) {
+	subDAO, exists := sm.subDAOs[id]
+	return subDAO, exists
+}
+
+// ListSubDAOs returns the sub-DAOs, oldest first
+func (sm *SubDAOManager) ListSubDAOs() []*SubDAO {
+	subDAOs := make([]*SubDAO, 0, len(sm.subDAOs))
+	for _, subDAO := range sm.subDAOs {
+		subDAOs = append(subDAOs, subDAO)
+	}
+
+	sort.Slice(subDAOs, func(i, j int) bool {
+		if subDAOs[i].CreatedAt != subDAOs[j].CreatedAt {
+			return subDAOs[i].CreatedAt < subDAOs[j].CreatedAt
+		}
+		return subDAOs[i].ID.String() < subDAOs[j].ID.String()
+	})
+	return subDAOs
+}
+
+// GetProposal returns a sub-DAO proposal
+func (sm *SubDAOManager) GetProposal(id types.Hash) (*SubDAOProposal, bool) {
+	proposal, exists := sm.proposals[id]
+	return proposal, exists
+}
+
+// GetProposals returns the proposals of a sub-DAO, oldest first
+func (sm *SubDAOManager) GetProposals(subDAOID types.Hash) []*SubDAOProposal {
+	proposals := make([]*SubDAOProposal, 0)
+	for _, proposal := range sm.proposals {
+		if proposal.SubDAOID == subDAOID {
+			proposals = append(proposals, proposal)
+		}
+	}
+
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].CreatedAt != proposals[j].CreatedAt {
+			return proposals[i].CreatedAt < proposals[j].CreatedAt
+		}
+		return proposals[i].ID.String() < proposals[j].ID.String()
+	})
+	return proposals
+}
@@ -0,0 +1,251 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSubDAO(t *testing.T, budget uint64) (*DAO, []crypto.PublicKey, *SubDAO) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	members := []crypto.PublicKey{
+		crypto.GeneratePrivateKey().PublicKey(),
+		crypto.GeneratePrivateKey().PublicKey(),
+		crypto.GeneratePrivateKey().PublicKey(),
+	}
+	distribution := make(map[string]uint64)
+	for _, member := range members {
+		distribution[member.String()] = 10000
+	}
+	require.NoError(t, dao.InitialTokenDistribution(distribution))
+	dao.GovernanceState.Treasury.Balance = 100000
+
+	now := time.Now().Unix()
+	proposalID := types.Hash{0xC0}
+	create := &SubDAOCreateProposalTx{
+		Fee:            200,
+		Name:           "Grants working group",
+		Members:        members,
+		Budget:         budget,
+		StreamDuration: 30 * 86400,
+		VotingType:     VotingTypeSimple,
+		StartTime:      now + 60,
+		EndTime:        now + 7*86400,
+		Threshold:      5100,
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(create, members[0], proposalID))
+
+	proposal, err := dao.GetProposal(proposalID)
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+
+	execute := &SubDAOCreateExecuteTx{Fee: 100, ProposalID: proposalID}
+	require.NoError(t, dao.ProcessDAOTransaction(execute, members[0], types.Hash{0xC1}))
+
+	subDAO, exists := dao.GetSubDAO(proposalID)
+	require.True(t, exists)
+
+	return dao, members, subDAO
+}
+
+func TestSubDAO_Create(t *testing.T) {
+	dao, members, subDAO := setupSubDAO(t, 30000)
+
+	assert.Equal(t, "Grants working group", subDAO.Name)
+	assert.Len(t, subDAO.Members, 3)
+	assert.Equal(t, uint64(30000), subDAO.Budget)
+	assert.Equal(t, int64(30*86400), subDAO.StreamEnd-subDAO.StreamStart)
+
+	proposal, err := dao.GetProposal(subDAO.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	// A charter executes once and names are unique
+	execute := &SubDAOCreateExecuteTx{Fee: 100, ProposalID: subDAO.ID}
+	assert.Error(t, dao.ProcessDAOTransaction(execute, members[0], types.Hash{0xC2}))
+
+	now := time.Now().Unix()
+	duplicate := &SubDAOCreateProposalTx{
+		Fee:        200,
+		Name:       "Grants working group",
+		Members:    members,
+		VotingType: VotingTypeSimple,
+		StartTime:  now + 60,
+		EndTime:    now + 7*86400,
+		Threshold:  5100,
+	}
+	assert.Error(t, dao.ProcessDAOTransaction(duplicate, members[0], types.Hash{0xC3}))
+
+	invalid := []*SubDAOCreateProposalTx{
+		{Fee: 200, Name: "", Members: members},
+		{Fee: 200, Name: "empty"},
+		{Fee: 200, Name: "dup", Members: []crypto.PublicKey{members[0], members[0]}},
+		{Fee: 200, Name: "unstreamed", Members: members, Budget: 1000},
+	}
+	for i, tx := range invalid {
+		tx.VotingType, tx.StartTime, tx.EndTime, tx.Threshold = VotingTypeSimple, now+60, now+7*86400, 5100
+		assert.Error(t, dao.ProcessDAOTransaction(tx, members[0], types.Hash{0xD0, byte(i)}), tx.Name)
+	}
+}
+
+func TestSubDAO_StreamBudget(t *testing.T) {
+	dao, _, subDAO := setupSubDAO(t, 30000)
+	treasury := dao.GovernanceState.Treasury.Balance
+
+	// A third of the stream period releases a third of the budget
+	streamed := dao.SubDAOManager.StreamBudget(subDAO, subDAO.StreamStart+10*86400)
+	assert.Equal(t, uint64(10000), streamed)
+	assert.Equal(t, uint64(10000), subDAO.Balance())
+	assert.Equal(t, treasury-10000, dao.GovernanceState.Treasury.Balance)
+
+	// Streaming again at the same time moves nothing
+	assert.Zero(t, dao.SubDAOManager.StreamBudget(subDAO, subDAO.StreamStart+10*86400))
+
+	// A short treasury caps the stream and the rest accrues
+	dao.GovernanceState.Treasury.Balance = 5000
+	assert.Equal(t, uint64(5000), dao.SubDAOManager.StreamBudget(subDAO, subDAO.StreamEnd))
+	assert.Zero(t, dao.GovernanceState.Treasury.Balance)
+
+	dao.GovernanceState.Treasury.Balance = 50000
+	assert.Equal(t, uint64(15000), dao.SubDAOManager.StreamAll(subDAO.StreamEnd+86400))
+	assert.Equal(t, uint64(30000), subDAO.Streamed)
+}
+
+func TestSubDAO_SpendProposal(t *testing.T) {
+	dao, members, subDAO := setupSubDAO(t, 30000)
+	dao.SubDAOManager.StreamBudget(subDAO, subDAO.StreamEnd)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+
+	proposalID := types.Hash{0x01}
+	spend := &SubDAOProposalTx{
+		Fee:      10,
+		SubDAOID: subDAO.ID,
+		Title:    "Fund the indexer grant",
+		Action:   SubDAOActionSpend,
+		Target:   recipient,
+		Amount:   4000,
+		Duration: 86400,
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(spend, members[0], proposalID))
+
+	// Outsiders can neither propose nor vote
+	outsider := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(outsider.String(), 100)
+	assert.Error(t, dao.ProcessDAOTransaction(spend, outsider, types.Hash{0x02}))
+
+	vote := func(voter crypto.PublicKey, support bool, txHash types.Hash) error {
+		tx := &SubDAOVoteTx{Fee: 5, SubDAOID: subDAO.ID, ProposalID: proposalID, Support: support}
+		return dao.ProcessDAOTransaction(tx, voter, txHash)
+	}
+	assert.Error(t, vote(outsider, true, types.Hash{0x03}))
+
+	// One of three members is not a majority
+	require.NoError(t, vote(members[0], true, types.Hash{0x04}))
+	assert.Error(t, vote(members[0], true, types.Hash{0x05}))
+	assert.Zero(t, dao.GetTokenBalance(recipient))
+
+	require.NoError(t, vote(members[1], true, types.Hash{0x06}))
+	assert.Equal(t, uint64(4000), dao.GetTokenBalance(recipient))
+	assert.Equal(t, uint64(26000), subDAO.Balance())
+
+	proposals := dao.GetSubDAOProposals(subDAO.ID)
+	require.Len(t, proposals, 1)
+	assert.Equal(t, SubDAOProposalExecuted, proposals[0].Status)
+
+	// Resolved proposals take no more votes
+	assert.Error(t, vote(members[2], false, types.Hash{0x07}))
+
+	// Spending more than was streamed fails and leaves the proposal open
+	overspend := *spend
+	overspend.Amount = 50000
+	require.NoError(t, dao.ProcessDAOTransaction(&overspend, members[0], types.Hash{0x08}))
+	tx := &SubDAOVoteTx{Fee: 5, SubDAOID: subDAO.ID, ProposalID: types.Hash{0x08}, Support: true}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, members[0], types.Hash{0x09}))
+	assert.Error(t, dao.ProcessDAOTransaction(tx, members[1], types.Hash{0x0A}))
+
+	overspent, _ := dao.SubDAOManager.GetProposal(types.Hash{0x08})
+	assert.Equal(t, SubDAOProposalActive, overspent.Status)
+	assert.Equal(t, uint64(1), overspent.YesVotes)
+}
+
+func TestSubDAO_RejectAndExpire(t *testing.T) {
+	dao, members, subDAO := setupSubDAO(t, 0)
+
+	signal := &SubDAOProposalTx{Fee: 10, SubDAOID: subDAO.ID, Title: "Adopt a charter", Action: SubDAOActionSignal, Duration: 86400}
+	require.NoError(t, dao.ProcessDAOTransaction(signal, members[0], types.Hash{0x01}))
+
+	against := &SubDAOVoteTx{Fee: 5, SubDAOID: subDAO.ID, ProposalID: types.Hash{0x01}}
+	require.NoError(t, dao.ProcessDAOTransaction(against, members[0], types.Hash{0x02}))
+	require.NoError(t, dao.ProcessDAOTransaction(against, members[1], types.Hash{0x03}))
+
+	proposal, _ := dao.SubDAOManager.GetProposal(types.Hash{0x01})
+	assert.Equal(t, SubDAOProposalRejected, proposal.Status)
+
+	require.NoError(t, dao.ProcessDAOTransaction(signal, members[0], types.Hash{0x04}))
+	expired, _ := dao.SubDAOManager.GetProposal(types.Hash{0x04})
+	expired.EndTime = time.Now().Unix() - 1
+	assert.Equal(t, SubDAOProposalExpired, expired.StatusAt(time.Now().Unix()))
+
+	vote := &SubDAOVoteTx{Fee: 5, SubDAOID: subDAO.ID, ProposalID: types.Hash{0x04}, Support: true}
+	assert.Error(t, dao.ProcessDAOTransaction(vote, members[0], types.Hash{0x05}))
+	assert.Equal(t, SubDAOProposalExpired, expired.Status)
+}
+
+func TestSubDAO_Membership(t *testing.T) {
+	dao, members, subDAO := setupSubDAO(t, 0)
+	newcomer := crypto.GeneratePrivateKey().PublicKey()
+
+	approve := func(action SubDAOAction, target crypto.PublicKey, id byte) {
+		tx := &SubDAOProposalTx{Fee: 10, SubDAOID: subDAO.ID, Title: "Membership", Action: action, Target: target, Duration: 86400}
+		require.NoError(t, dao.ProcessDAOTransaction(tx, members[0], types.Hash{id}))
+
+		for i, voter := range subDAO.Members[:len(subDAO.Members)/2+1] {
+			vote := &SubDAOVoteTx{Fee: 5, SubDAOID: subDAO.ID, ProposalID: types.Hash{id}, Support: true}
+			require.NoError(t, dao.ProcessDAOTransaction(vote, voter, types.Hash{id, byte(i + 1)}))
+		}
+	}
+
+	approve(SubDAOActionAddMember, newcomer, 0x01)
+	assert.True(t, subDAO.IsMember(newcomer))
+	assert.Len(t, subDAO.Members, 4)
+
+	// Existing members cannot be added again
+	add := &SubDAOProposalTx{Fee: 10, SubDAOID: subDAO.ID, Title: "Again", Action: SubDAOActionAddMember, Target: newcomer, Duration: 86400}
+	assert.Error(t, dao.ProcessDAOTransaction(add, members[0], types.Hash{0x02}))
+
+	approve(SubDAOActionRemoveMember, members[2], 0x03)
+	assert.False(t, subDAO.IsMember(members[2]))
+	assert.Len(t, subDAO.Members, 3)
+}
+
+func TestSubDAO_Analytics(t *testing.T) {
+	dao, members, subDAO := setupSubDAO(t, 30000)
+	dao.SubDAOManager.StreamBudget(subDAO, subDAO.StreamEnd)
+
+	spend := &SubDAOProposalTx{Fee: 10, SubDAOID: subDAO.ID, Title: "Grant", Action: SubDAOActionSpend, Target: members[2], Amount: 6000, Duration: 86400}
+	require.NoError(t, dao.ProcessDAOTransaction(spend, members[0], types.Hash{0x01}))
+	for i, voter := range members[:2] {
+		vote := &SubDAOVoteTx{Fee: 5, SubDAOID: subDAO.ID, ProposalID: types.Hash{0x01}, Support: true}
+		require.NoError(t, dao.ProcessDAOTransaction(vote, voter, types.Hash{0x02, byte(i)}))
+	}
+	require.NoError(t, dao.ProcessDAOTransaction(spend, members[0], types.Hash{0x03}))
+
+	analytics := dao.GetSubDAOAnalytics()
+	assert.Equal(t, uint64(1), analytics.TotalSubDAOs)
+	assert.Equal(t, uint64(3), analytics.TotalMembers)
+	assert.Equal(t, uint64(30000), analytics.TotalStreamed)
+	assert.Equal(t, uint64(6000), analytics.TotalSpent)
+	assert.Equal(t, uint64(24000), analytics.TotalBalance)
+
+	require.Len(t, analytics.SubDAOs, 1)
+	summary := analytics.SubDAOs[0]
+	assert.Equal(t, uint64(2), summary.Proposals)
+	assert.Equal(t, uint64(1), summary.ExecutedProposals)
+	assert.Equal(t, uint64(1), summary.ActiveProposals)
+	assert.InDelta(t, 0.2, summary.Utilization, 0.0001)
+}
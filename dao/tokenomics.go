@@ -15,6 +15,7 @@ type TokenomicsManager struct {
 	vestingSchedules map[string]*VestingSchedule
 	stakingPools     map[string]*StakingPool
 	config           *TokenomicsConfig
+	participation    *participationRewards
 }
 
 // TokenDistribution represents a token allocation category
@@ -63,6 +64,8 @@ type StakingPool struct {
 	LockupPeriod   int64 // Minimum staking duration
 	Stakers        map[string]*StakerInfo
 	Active         bool
+	TreasuryYield  uint64 // Cumulative treasury yield allocated to this pool
+	FeeRewards     uint64 // Cumulative validator fee revenue shared with this pool
 }
 
 // StakerInfo represents an individual staker's information
@@ -71,6 +74,8 @@ type StakerInfo struct {
 	StakedAmount       uint64
 	RewardPerTokenPaid uint64
 	Rewards            uint64
+	TreasuryRewards    uint64 // Treasury funded yield, already backed by existing tokens
+	FeeRewards         uint64 // Share of validator fee revenue, already backed by paid fees
 	StakeTime          int64
 	UnlockTime         int64
 }
@@ -91,14 +96,21 @@ type TokenomicsConfig struct {
 
 // NewTokenomicsManager creates a new tokenomics manager
 func NewTokenomicsManager(governanceState *GovernanceState, tokenState *GovernanceToken) *TokenomicsManager {
-	return &TokenomicsManager{
+	tm := &TokenomicsManager{
 		governanceState:  governanceState,
 		tokenState:       tokenState,
 		distributions:    make(map[string]*TokenDistribution),
 		vestingSchedules: make(map[string]*VestingSchedule),
 		stakingPools:     make(map[string]*StakingPool),
 		config:           NewDefaultTokenomicsConfig(),
+		participation: &participationRewards{
+			optIns: make(map[string]int64),
+			paid:   make(map[string]uint64),
+		},
 	}
+	tm.openParticipationEpoch(time.Now().Unix())
+
+	return tm
 }
 
 // NewDefaultTokenomicsConfig creates default tokenomics configuration
@@ -384,7 +396,7 @@ func (tm *TokenomicsManager) StakeTokens(poolID string, staker crypto.PublicKey,
 	tm.updatePoolRewards(pool)
 
 	// Transfer tokens from user balance to staked
-	tm.tokenState.Balances[stakerStr] -= amount
+	tm.tokenState.Balances.Sub(stakerStr, amount)
 
 	// Update or create staker info
 	if stakerInfo, exists := pool.Stakers[stakerStr]; exists {
@@ -463,7 +475,7 @@ func (tm *TokenomicsManager) UnstakeTokens(poolID string, staker crypto.PublicKe
 	pool.TotalStaked -= amount
 
 	// Return tokens to user balance
-	tm.tokenState.Balances[stakerStr] += amount
+	tm.tokenState.Balances.Add(stakerStr, amount)
 
 	// Update token holder record
 	if holder, exists := tm.governanceState.TokenHolders[stakerStr]; exists {
@@ -472,8 +484,9 @@ func (tm *TokenomicsManager) UnstakeTokens(poolID string, staker crypto.PublicKe
 		holder.LastActive = now
 	}
 
-	// Remove staker if no tokens left
+	// Remove staker if no tokens left, paying out any backed rewards first
 	if stakerInfo.StakedAmount == 0 {
+		tm.payBackedRewards(stakerInfo)
 		delete(pool.Stakers, stakerStr)
 	}
 
@@ -512,7 +525,29 @@ func (tm *TokenomicsManager) ClaimStakingRewards(poolID string, staker crypto.Pu
 		}
 	}
 
-	return rewards, nil
+	return rewards + tm.payBackedRewards(stakerInfo), nil
+}
+
+// payBackedRewards credits a staker's treasury yield and fee share to their
+// balance. These tokens were taken from the treasury or paid as fees, so
+// nothing is minted.
+func (tm *TokenomicsManager) payBackedRewards(stakerInfo *StakerInfo) uint64 {
+	amount := stakerInfo.TreasuryRewards + stakerInfo.FeeRewards
+	if amount == 0 {
+		return 0
+	}
+
+	stakerStr := stakerInfo.Address.String()
+	stakerInfo.TreasuryRewards = 0
+	stakerInfo.FeeRewards = 0
+	tm.tokenState.Balances.Add(stakerStr, amount)
+
+	if holder, exists := tm.governanceState.TokenHolders[stakerStr]; exists {
+		holder.Balance += amount
+		holder.LastActive = time.Now().Unix()
+	}
+
+	return amount
 }
 
 // updatePoolRewards updates the reward calculations for a staking pool
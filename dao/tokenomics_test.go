@@ -218,7 +218,7 @@ func TestTokenomicsManager_StakeTokens(t *testing.T) {
 	staker := crypto.GeneratePrivateKey().PublicKey()
 	stakerStr := staker.String()
 	initialBalance := uint64(10000)
-	dao.TokenState.Balances[stakerStr] = initialBalance
+	dao.TokenState.Balances.Set(stakerStr, initialBalance)
 
 	// Stake tokens
 	stakeAmount := uint64(5000)
@@ -251,7 +251,7 @@ func TestTokenomicsManager_StakeTokens_InsufficientBalance(t *testing.T) {
 	// Create staker with insufficient tokens
 	staker := crypto.GeneratePrivateKey().PublicKey()
 	stakerStr := staker.String()
-	dao.TokenState.Balances[stakerStr] = 500 // Less than minimum stake
+	dao.TokenState.Balances.Set(stakerStr, 500) // Less than minimum stake
 
 	// Try to stake more than balance
 	err = tm.StakeTokens(poolID, staker, 1000, 0)
@@ -273,7 +273,7 @@ func TestTokenomicsManager_UnstakeTokens(t *testing.T) {
 	staker := crypto.GeneratePrivateKey().PublicKey()
 	stakerStr := staker.String()
 	initialBalance := uint64(10000)
-	dao.TokenState.Balances[stakerStr] = initialBalance
+	dao.TokenState.Balances.Set(stakerStr, initialBalance)
 
 	stakeAmount := uint64(5000)
 	err = tm.StakeTokens(poolID, staker, stakeAmount, 0)
@@ -312,7 +312,7 @@ func TestTokenomicsManager_UnstakeTokens_LockedTokens(t *testing.T) {
 	// Create and stake tokens
 	staker := crypto.GeneratePrivateKey().PublicKey()
 	stakerStr := staker.String()
-	dao.TokenState.Balances[stakerStr] = 10000
+	dao.TokenState.Balances.Set(stakerStr, 10000)
 
 	err = tm.StakeTokens(poolID, staker, 5000, 0)
 	require.NoError(t, err)
@@ -337,7 +337,7 @@ func TestTokenomicsManager_ClaimStakingRewards(t *testing.T) {
 	// Create and stake tokens
 	staker := crypto.GeneratePrivateKey().PublicKey()
 	stakerStr := staker.String()
-	dao.TokenState.Balances[stakerStr] = 10000
+	dao.TokenState.Balances.Set(stakerStr, 10000)
 
 	stakeAmount := uint64(5000)
 	err = tm.StakeTokens(poolID, staker, stakeAmount, 0)
@@ -375,7 +375,7 @@ func TestTokenomicsManager_GetTotalStakedByUser(t *testing.T) {
 	// Create staker
 	staker := crypto.GeneratePrivateKey().PublicKey()
 	stakerStr := staker.String()
-	dao.TokenState.Balances[stakerStr] = 20000
+	dao.TokenState.Balances.Set(stakerStr, 20000)
 
 	// Stake in both pools
 	stake1 := uint64(5000)
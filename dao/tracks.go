@@ -0,0 +1,246 @@
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// MaxProposalTracks bounds the tracks governance may define
+const MaxProposalTracks = 32
+
+// ProposalTrack is a governance-defined kind of proposal with its own voting
+// rules. Proposals on a track must use its voting type and one of its
+// actions, need its quorum and threshold, lock its deposit until the vote
+// ends and wait out its timelock before executing. Proposals beyond the
+// track's active limit wait in a queue, first in first out.
+type ProposalTrack struct {
+	ID          string
+	Name        string
+	VotingType  VotingType
+	Quorum      uint64 // Participation required above the DAO quorum
+	Threshold   uint64 // Basis points of yes votes required
+	Timelock    int64  // Seconds between the vote ending and execution
+	Deposit     uint64 // Refunded when the vote meets quorum
+	Actions     []ProposalType
+	MaxActive   uint16     // Proposals that may be open at once, 0 for no limit
+	ProposalID  types.Hash // Proposal that set the current rules
+	ActivatedAt int64
+}
+
+// Allows reports whether proposals of a type may use the track
+func (t *ProposalTrack) Allows(proposalType ProposalType) bool {
+	for _, action := range t.Actions {
+		if action == proposalType {
+			return true
+		}
+	}
+	return false
+}
+
+// Proposal returns the parameter proposal of a track change
+func (tx *TrackProposalTx) Proposal() *ProposalTx {
+	description := tx.Description
+	if description == "" {
+		description = tx.Title
+	}
+
+	title := "Proposal track: " + tx.Title
+	if tx.Remove {
+		title = "Remove proposal track: " + tx.Title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        title,
+		Description:  description,
+		ProposalType: ProposalTypeParameter,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// RecordTrackProposal records the track change of a proposal
+func (pm *ParameterManager) RecordTrackProposal(proposalID types.Hash, tx *TrackProposalTx) {
+	pm.proposedTracks[proposalID] = tx
+}
+
+// ProcessTrackActivateTx applies the track change of a passed proposal
+func (pm *ParameterManager) ProcessTrackActivateTx(tx *TrackActivateTx, activator crypto.PublicKey) error {
+	spec, exists := pm.proposedTracks[tx.ProposalID]
+	if !exists {
+		return ErrTrackNotFoundError
+	}
+
+	proposal, exists := pm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	if spec.Remove {
+		if _, exists := pm.tracks[spec.Track.ID]; !exists {
+			return ErrTrackNotFoundError
+		}
+		delete(pm.tracks, spec.Track.ID)
+	} else {
+		if _, exists := pm.tracks[spec.Track.ID]; !exists && len(pm.tracks) >= MaxProposalTracks {
+			return NewDAOError(ErrInvalidTrack, "too many proposal tracks", map[string]interface{}{
+				"max": MaxProposalTracks,
+			})
+		}
+		track := spec.Track
+		track.Actions = append([]ProposalType(nil), spec.Track.Actions...)
+		track.ProposalID = tx.ProposalID
+		track.ActivatedAt = time.Now().Unix()
+		pm.tracks[track.ID] = &track
+	}
+
+	pm.tokenState.Balances.Sub(activator.String(), uint64(tx.Fee))
+	delete(pm.proposedTracks, tx.ProposalID)
+	proposal.Status = ProposalStatusExecuted
+
+	return nil
+}
+
+// GetTrack returns a proposal track by ID
+func (pm *ParameterManager) GetTrack(id string) (*ProposalTrack, bool) {
+	track, exists := pm.tracks[id]
+	return track, exists
+}
+
+// ListTracks returns the proposal tracks ordered by ID
+func (pm *ParameterManager) ListTracks() []*ProposalTrack {
+	tracks := make([]*ProposalTrack, 0, len(pm.tracks))
+	for _, track := range pm.tracks {
+		tracks = append(tracks, track)
+	}
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].ID < tracks[j].ID })
+	return tracks
+}
+
+// isOpen reports whether a proposal takes one of its track's active slots
+func (pm *ParameterManager) isOpen(proposal *Proposal) bool {
+	if proposal.Status != ProposalStatusPending && proposal.Status != ProposalStatusActive {
+		return false
+	}
+	_, queued := pm.QueuePosition(proposal.ID)
+	return !queued
+}
+
+// openOnTrack returns the proposals on a track that are open to votes or
+// about to open
+func (pm *ParameterManager) openOnTrack(id string) int {
+	open := 0
+	for _, proposal := range pm.governanceState.Proposals {
+		if proposal.Track == id && pm.isOpen(proposal) {
+			open++
+		}
+	}
+	return open
+}
+
+// Enqueue puts a new proposal at the back of its track's queue when the
+// track is at its active limit and reports whether it did
+func (pm *ParameterManager) Enqueue(proposal *Proposal) bool {
+	track, exists := pm.tracks[proposal.Track]
+	if !exists || track.MaxActive == 0 {
+		return false
+	}
+	if pm.openOnTrack(track.ID) < int(track.MaxActive) {
+		return false
+	}
+	pm.trackQueues[track.ID] = append(pm.trackQueues[track.ID], proposal.ID)
+	return true
+}
+
+// QueuePosition returns the 1-based position of a proposal in its track's
+// queue
+func (pm *ParameterManager) QueuePosition(proposalID types.Hash) (int, bool) {
+	proposal, exists := pm.governanceState.Proposal(proposalID)
+	if !exists {
+		return 0, false
+	}
+	for i, queued := range pm.trackQueues[proposal.Track] {
+		if queued == proposalID {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// TrackQueue returns the proposals queued on a track, first in line first
+func (pm *ParameterManager) TrackQueue(id string) []types.Hash {
+	return append([]types.Hash(nil), pm.trackQueues[id]...)
+}
+
+// CheckTrackQueue returns an error while a proposal waits in its track's
+// queue
+func (pm *ParameterManager) CheckTrackQueue(proposalID types.Hash) error {
+	position, queued := pm.QueuePosition(proposalID)
+	if !queued {
+		return nil
+	}
+	return NewDAOError(ErrProposalQueued, "proposal is queued behind the track's active proposals", map[string]interface{}{
+		"position": position,
+	})
+}
+
+// AdvanceTrackQueue opens queued proposals of a track, oldest first, while
+// it has free active slots. A track that was removed or lost its limit
+// releases its whole queue. Proposals whose start has passed are moved to
+// start now and keep their full voting period.
+func (pm *ParameterManager) AdvanceTrackQueue(id string, now int64) []types.Hash {
+	queue := pm.trackQueues[id]
+	if len(queue) == 0 {
+		return nil
+	}
+
+	free := len(queue)
+	if track, exists := pm.tracks[id]; exists && track.MaxActive > 0 {
+		free = int(track.MaxActive) - pm.openOnTrack(id)
+	}
+
+	var opened []types.Hash
+	for len(queue) > 0 && len(opened) < free {
+		proposalID := queue[0]
+		queue = queue[1:]
+		proposal, exists := pm.governanceState.Proposals[proposalID]
+		if !exists || proposal.Status != ProposalStatusPending {
+			continue
+		}
+		if now > proposal.StartTime {
+			proposal.EndTime = now + proposal.EndTime - proposal.StartTime
+			proposal.StartTime = now
+		}
+		opened = append(opened, proposalID)
+	}
+
+	if len(queue) == 0 {
+		delete(pm.trackQueues, id)
+	} else {
+		pm.trackQueues[id] = queue
+	}
+	return opened
+}
+
+// AdvanceTrackQueues advances the queue of every track
+func (pm *ParameterManager) AdvanceTrackQueues(now int64) []types.Hash {
+	ids := make([]string, 0, len(pm.trackQueues))
+	for id := range pm.trackQueues {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var opened []types.Hash
+	for _, id := range ids {
+		opened = append(opened, pm.AdvanceTrackQueue(id, now)...)
+	}
+	return opened
+}
@@ -0,0 +1,216 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalTracks(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{founder.String(): 10000}))
+
+	now := time.Now().Unix()
+	propose := &TrackProposalTx{
+		Fee:   100,
+		Title: "Small grants",
+		Track: ProposalTrack{
+			ID:         "small-grants",
+			Name:       "Small grants",
+			VotingType: VotingTypeSimple,
+			Quorum:     3000,
+			Threshold:  6000,
+			Timelock:   3600,
+			Deposit:    500,
+			Actions:    []ProposalType{ProposalTypeTreasury, ProposalTypeConstitution},
+		},
+		VotingType: VotingTypeSimple,
+		StartTime:  now,
+		EndTime:    now + 86400,
+		Threshold:  5100,
+	}
+
+	// Constitution and emergency proposals cannot be put on a track
+	assert.Error(t, dao.ProcessDAOTransaction(propose, founder, types.Hash{0x01}))
+	propose.Track.Actions = []ProposalType{ProposalTypeGeneral, ProposalTypeTreasury}
+	trackProposal := types.Hash{0x02}
+	require.NoError(t, dao.ProcessDAOTransaction(propose, founder, trackProposal))
+
+	// The track applies once its proposal passes
+	activate := &TrackActivateTx{Fee: 10, ProposalID: trackProposal}
+	assert.Error(t, dao.ProcessDAOTransaction(activate, founder, types.Hash{0x03}))
+	proposal, err := dao.GetProposal(trackProposal)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalTypeParameter, proposal.ProposalType)
+	proposal.Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(activate, founder, types.Hash{0x04}))
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	track, exists := dao.GetProposalTrack("small-grants")
+	require.True(t, exists)
+	assert.Equal(t, trackProposal, track.ProposalID)
+	assert.Len(t, dao.ListProposalTracks(), 1)
+
+	// Proposals on the track follow its voting type and actions
+	grant := &ProposalTx{
+		Fee:          100,
+		Title:        "Fund the docs",
+		Description:  "A small grant for documentation",
+		ProposalType: ProposalTypeTechnical,
+		VotingType:   VotingTypeSimple,
+		StartTime:    now,
+		EndTime:      now + 86400,
+		Threshold:    5100,
+		Track:        "small-grants",
+	}
+	assert.Error(t, dao.ProcessDAOTransaction(grant, founder, types.Hash{0x05}))
+	grant.ProposalType = ProposalTypeGeneral
+	grant.VotingType = VotingTypeQuadratic
+	assert.Error(t, dao.ProcessDAOTransaction(grant, founder, types.Hash{0x06}))
+	grant.VotingType = VotingTypeSimple
+	grant.Track = "missing"
+	assert.Error(t, dao.ProcessDAOTransaction(grant, founder, types.Hash{0x07}))
+	grant.Track = "small-grants"
+
+	balance := dao.GetTokenBalance(founder)
+	grantID := types.Hash{0x08}
+	require.NoError(t, dao.ProcessDAOTransaction(grant, founder, grantID))
+	created, err := dao.GetProposal(grantID)
+	require.NoError(t, err)
+	assert.Equal(t, "small-grants", created.Track)
+	assert.Equal(t, uint64(6000), created.Threshold)
+	assert.Equal(t, uint64(3000), created.Quorum)
+	assert.Equal(t, int64(3600), created.Timelock)
+	assert.Equal(t, uint64(500), created.Deposit)
+	assert.Equal(t, balance-600, dao.GetTokenBalance(founder))
+
+	// A vote that misses quorum forfeits the deposit to the treasury
+	created.EndTime = now - 1
+	created.Status = ProposalStatusActive
+	treasury := dao.GetTreasuryBalance()
+	require.NoError(t, dao.Processor.UpdateProposalStatus(grantID))
+	assert.Equal(t, ProposalStatusRejected, created.Status)
+	assert.Equal(t, treasury+500, dao.GetTreasuryBalance())
+	assert.Zero(t, created.Deposit)
+
+	// A vote that meets quorum refunds it, and execution waits for the timelock
+	passingID := types.Hash{0x09}
+	require.NoError(t, dao.ProcessDAOTransaction(grant, founder, passingID))
+	passing, err := dao.GetProposal(passingID)
+	require.NoError(t, err)
+	passing.Results.YesVotes = 5000
+	passing.EndTime = now - 1
+	passing.Status = ProposalStatusActive
+	balance = dao.GetTokenBalance(founder)
+	require.NoError(t, dao.Processor.UpdateProposalStatus(passingID))
+	assert.Equal(t, ProposalStatusPassed, passing.Status)
+	assert.Equal(t, balance+500, dao.GetTokenBalance(founder))
+
+	err = dao.ProposalManager.ExecuteProposal(passingID, founder)
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidTimeframe, err.(*DAOError).Code)
+
+	// Removing the track needs a passed proposal too
+	remove := &TrackProposalTx{
+		Fee:        100,
+		Title:      "Small grants",
+		Track:      ProposalTrack{ID: "small-grants"},
+		Remove:     true,
+		VotingType: VotingTypeSimple,
+		StartTime:  now,
+		EndTime:    now + 86400,
+		Threshold:  5100,
+	}
+	removeID := types.Hash{0x0A}
+	require.NoError(t, dao.ProcessDAOTransaction(remove, founder, removeID))
+	dao.GovernanceState.Proposals[removeID].Status = ProposalStatusPassed
+	require.NoError(t, dao.ProcessDAOTransaction(&TrackActivateTx{Fee: 10, ProposalID: removeID}, founder, types.Hash{0x0B}))
+	_, exists = dao.GetProposalTrack("small-grants")
+	assert.False(t, exists)
+	assert.Error(t, dao.ProcessDAOTransaction(grant, founder, types.Hash{0x0C}))
+}
+
+func TestTrackQueue(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	founder := crypto.GeneratePrivateKey().PublicKey()
+	voter := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		founder.String(): 10000,
+		voter.String():   5000,
+	}))
+	dao.ParameterManager.tracks["treasury"] = &ProposalTrack{
+		ID:         "treasury",
+		Name:       "Treasury",
+		VotingType: VotingTypeSimple,
+		Threshold:  5100,
+		Actions:    []ProposalType{ProposalTypeTreasury},
+		MaxActive:  2,
+	}
+
+	now := time.Now().Unix()
+	submit := func(id byte) *Proposal {
+		tx := &ProposalTx{
+			Fee:          10,
+			Title:        "Spend",
+			Description:  "A treasury spend",
+			ProposalType: ProposalTypeTreasury,
+			VotingType:   VotingTypeSimple,
+			StartTime:    now,
+			EndTime:      now + 86400,
+			Threshold:    5100,
+			Track:        "treasury",
+		}
+		require.NoError(t, dao.ProcessDAOTransaction(tx, founder, types.Hash{id}))
+		proposal, err := dao.GetProposal(types.Hash{id})
+		require.NoError(t, err)
+		return proposal
+	}
+
+	// Two proposals open, the rest queue in order
+	first := submit(0x01)
+	submit(0x02)
+	third := submit(0x03)
+	fourth := submit(0x04)
+	_, queued := dao.GetTrackQueuePosition(first.ID)
+	assert.False(t, queued)
+	position, queued := dao.GetTrackQueuePosition(third.ID)
+	require.True(t, queued)
+	assert.Equal(t, 1, position)
+	position, _ = dao.GetTrackQueuePosition(fourth.ID)
+	assert.Equal(t, 2, position)
+	assert.Equal(t, []types.Hash{third.ID, fourth.ID}, dao.GetTrackQueue("treasury"))
+
+	// Queued proposals take no votes and do not close
+	vote := &VoteTx{Fee: 1, ProposalID: third.ID, Choice: VoteChoiceYes, Weight: 100}
+	err := dao.ProcessDAOTransaction(vote, voter, types.Hash{0x10})
+	require.Error(t, err)
+	assert.Equal(t, ErrProposalQueued, err.(*DAOError).Code)
+	third.StartTime, third.EndTime = now-86400, now-1
+	dao.UpdateAllProposalStatuses()
+	assert.Equal(t, ProposalStatusPending, third.Status)
+
+	// Closing an open proposal opens the next in line with a full period
+	first.Status = ProposalStatusActive
+	first.EndTime = now - 1
+	require.NoError(t, dao.Processor.UpdateProposalStatus(first.ID))
+	assert.Equal(t, ProposalStatusRejected, first.Status)
+	_, queued = dao.GetTrackQueuePosition(third.ID)
+	assert.False(t, queued)
+	assert.GreaterOrEqual(t, third.StartTime, now)
+	assert.Equal(t, int64(86399), third.EndTime-third.StartTime)
+	position, _ = dao.GetTrackQueuePosition(fourth.ID)
+	assert.Equal(t, 1, position)
+	require.NoError(t, dao.ProcessDAOTransaction(vote, voter, types.Hash{0x11}))
+
+	// Raising the limit releases the queue on the next status update
+	dao.ParameterManager.tracks["treasury"].MaxActive = 3
+	dao.UpdateAllProposalStatuses()
+	assert.Empty(t, dao.GetTrackQueue("treasury"))
+}
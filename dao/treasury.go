@@ -2,6 +2,9 @@ package dao
 
 import (
 	"crypto/sha256"
+	"errors"
+	"math/big"
+	"sort"
 	"time"
 
 	"github.com/BOCK-CHAIN/BockChain/crypto"
@@ -10,9 +13,11 @@ import (
 
 // TreasuryManager handles multi-signature treasury operations
 type TreasuryManager struct {
-	governanceState *GovernanceState
-	tokenState      *GovernanceToken
-	validator       *DAOValidator
+	governanceState  *GovernanceState
+	tokenState       *GovernanceToken
+	validator        *DAOValidator
+	oracles          *OracleManager    // Converts stable denominated amounts, nil disables them
+	parameterManager *ParameterManager // Supplies the conversion slippage bound
 }
 
 // NewTreasuryManager creates a new treasury manager
@@ -44,6 +49,25 @@ func (tm *TreasuryManager) CreateTreasuryTransaction(tx *TreasuryTx, txHash type
 		Executed:   false,
 	}
 
+	// Quote stable denominated amounts in native tokens now so signers see
+	// what the transaction is expected to pay
+	if tx.Denomination != "" {
+		quoted, err := tm.convert(tx.Denomination, tx.Amount, pendingTx.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if quoted == 0 {
+			return NewDAOError(ErrInvalidProposal, "treasury amount converts to zero tokens", nil)
+		}
+		if quoted > tm.governanceState.Treasury.Balance {
+			return ErrTreasuryInsufficientFunds
+		}
+		pendingTx.Amount = quoted
+		pendingTx.Denomination = tx.Denomination
+		pendingTx.StableAmount = tx.Amount
+		pendingTx.QuotedAmount = quoted
+	}
+
 	// Store the pending transaction
 	tm.governanceState.Treasury.Transactions[txHash] = pendingTx
 
@@ -53,7 +77,7 @@ func (tm *TreasuryManager) CreateTreasuryTransaction(tx *TreasuryTx, txHash type
 // SignTreasuryTransaction adds a signature to a pending treasury transaction
 func (tm *TreasuryManager) SignTreasuryTransaction(txHash types.Hash, signer crypto.PrivateKey) error {
 	// Get pending transaction
-	pendingTx, exists := tm.governanceState.Treasury.Transactions[txHash]
+	pendingTx, exists := tm.governanceState.TreasuryTransaction(txHash)
 	if !exists {
 		return NewDAOError(ErrProposalNotFound, "treasury transaction not found", nil)
 	}
@@ -68,6 +92,10 @@ func (tm *TreasuryManager) SignTreasuryTransaction(txHash types.Hash, signer cry
 		return NewDAOError(ErrInvalidProposal, "treasury transaction already executed", nil)
 	}
 
+	if pendingTx.Aggregate != nil {
+		return NewDAOError(ErrInvalidSignature, "treasury transaction is signed with an aggregate signature", nil)
+	}
+
 	// Check if signer is authorized
 	signerPubKey := signer.PublicKey()
 	if !tm.isAuthorizedSigner(signerPubKey) {
@@ -91,17 +119,62 @@ func (tm *TreasuryManager) SignTreasuryTransaction(txHash types.Hash, signer cry
 	// Add signature
 	pendingTx.Signatures = append(pendingTx.Signatures, *signature)
 
-	// Check if we have enough signatures to execute
-	if len(pendingTx.Signatures) >= int(tm.governanceState.Treasury.RequiredSigs) {
-		return tm.executeTreasuryTransaction(txHash)
+	// Check if we have enough signatures to execute. A re-queued conversion
+	// keeps the signature and executes on a later retry.
+	if pendingTx.SignatureCount() >= int(tm.governanceState.Treasury.RequiredSigs) {
+		if err := tm.executeTreasuryTransaction(txHash); err != nil && !IsConversionRequeue(err) {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// AttachAggregateSignature signs a pending treasury transaction with one
+// aggregate signature of its signers, replacing the signatures collected so
+// far, and executes it when the signers meet the threshold
+func (tm *TreasuryManager) AttachAggregateSignature(txHash types.Hash, sig *crypto.AggregateSignature) error {
+	pendingTx, exists := tm.governanceState.TreasuryTransaction(txHash)
+	if !exists {
+		return NewDAOError(ErrProposalNotFound, "treasury transaction not found", nil)
+	}
+	if time.Now().Unix() > pendingTx.ExpiresAt {
+		return NewDAOError(ErrProposalExpired, "treasury transaction has expired", nil)
+	}
+	if pendingTx.Executed {
+		return NewDAOError(ErrInvalidProposal, "treasury transaction already executed", nil)
+	}
+
+	if err := tm.verifyAggregateSignature(pendingTx, sig); err != nil {
+		return err
+	}
+	if len(sig.Signers) < int(tm.governanceState.Treasury.RequiredSigs) {
+		return NewDAOError(ErrInvalidSignature, "insufficient signers in aggregate signature", nil)
+	}
+
+	pendingTx.Aggregate = sig
+	pendingTx.Signatures = nil
+
+	if err := tm.executeTreasuryTransaction(txHash); err != nil && !IsConversionRequeue(err) {
+		return err
+	}
+	return nil
+}
+
+// SignatureCount returns the number of signers who signed the transaction
+func (tx *PendingTx) SignatureCount() int {
+	if tx.Threshold != nil && tx.Threshold.Signature != nil {
+		return len(tx.Threshold.Partials)
+	}
+	if tx.Aggregate != nil {
+		return len(tx.Aggregate.Signers)
+	}
+	return len(tx.Signatures)
+}
+
 // ExecuteTreasuryTransaction executes a treasury transaction if it has sufficient signatures
 func (tm *TreasuryManager) ExecuteTreasuryTransaction(txHash types.Hash) error {
-	pendingTx, exists := tm.governanceState.Treasury.Transactions[txHash]
+	pendingTx, exists := tm.governanceState.TreasuryTransaction(txHash)
 	if !exists {
 		return NewDAOError(ErrProposalNotFound, "treasury transaction not found", nil)
 	}
@@ -117,7 +190,7 @@ func (tm *TreasuryManager) ExecuteTreasuryTransaction(txHash types.Hash) error {
 	}
 
 	// Verify we have enough signatures
-	if len(pendingTx.Signatures) < int(tm.governanceState.Treasury.RequiredSigs) {
+	if pendingTx.SignatureCount() < int(tm.governanceState.Treasury.RequiredSigs) {
 		return NewDAOError(ErrInvalidSignature, "insufficient signatures for execution", nil)
 	}
 
@@ -133,28 +206,119 @@ func (tm *TreasuryManager) ExecuteTreasuryTransaction(txHash types.Hash) error {
 func (tm *TreasuryManager) executeTreasuryTransaction(txHash types.Hash) error {
 	pendingTx := tm.governanceState.Treasury.Transactions[txHash]
 
+	// Convert stable denominated amounts at the current price
+	amount := pendingTx.Amount
+	if pendingTx.Denomination != "" {
+		converted, err := tm.requote(pendingTx, time.Now().Unix())
+		if err != nil {
+			return err
+		}
+		amount = converted
+	}
+
 	// Check treasury balance
-	if tm.governanceState.Treasury.Balance < pendingTx.Amount {
+	if tm.governanceState.Treasury.Balance < amount {
 		return ErrTreasuryInsufficientFunds
 	}
+	pendingTx.Amount = amount
 
 	// Transfer funds from treasury
 	tm.governanceState.Treasury.Balance -= pendingTx.Amount
 
 	// Add to recipient's token balance
 	recipientStr := pendingTx.Recipient.String()
-	if tm.tokenState.Balances[recipientStr] == 0 {
-		tm.tokenState.Balances[recipientStr] = pendingTx.Amount
-	} else {
-		tm.tokenState.Balances[recipientStr] += pendingTx.Amount
-	}
+	tm.tokenState.Balances.Add(recipientStr, pendingTx.Amount)
 
 	// Mark as executed
 	pendingTx.Executed = true
+	pendingTx.ExecutedAt = time.Now().Unix()
 
 	return nil
 }
 
+// convert converts a stable amount to native tokens through an oracle feed
+func (tm *TreasuryManager) convert(feedID string, stableAmount uint64, now int64) (uint64, error) {
+	if tm.oracles == nil {
+		return 0, ErrOracleFeedNotFoundError
+	}
+	return tm.oracles.Convert(feedID, stableAmount, now)
+}
+
+// requote converts a stable denominated transaction at the current price.
+// The transaction is re-queued rather than executed while its feed is stale
+// or the price has moved beyond the slippage bound since it was quoted.
+func (tm *TreasuryManager) requote(pendingTx *PendingTx, now int64) (uint64, error) {
+	converted, err := tm.convert(pendingTx.Denomination, pendingTx.StableAmount, now)
+	if err != nil {
+		return 0, tm.requeue(pendingTx, err)
+	}
+
+	var slippage uint64
+	if tm.parameterManager != nil {
+		slippage = tm.parameterManager.GetParameterConfig().TreasuryConversionSlippage
+	}
+	drift := new(big.Int).Sub(new(big.Int).SetUint64(converted), new(big.Int).SetUint64(pendingTx.QuotedAmount))
+	drift.Abs(drift).Mul(drift, big.NewInt(10000))
+	bound := new(big.Int).Mul(new(big.Int).SetUint64(pendingTx.QuotedAmount), new(big.Int).SetUint64(slippage))
+	if converted == 0 || drift.Cmp(bound) > 0 {
+		return 0, tm.requeue(pendingTx, NewDAOError(ErrConversionSlippage, "conversion drifted beyond the slippage bound", map[string]interface{}{
+			"quoted":       pendingTx.QuotedAmount,
+			"converted":    converted,
+			"slippage_bps": slippage,
+		}))
+	}
+	return converted, nil
+}
+
+// requeue records why a stable denominated transaction was not executed
+func (tm *TreasuryManager) requeue(pendingTx *PendingTx, err error) error {
+	var daoErr *DAOError
+	if !errors.As(err, &daoErr) || (daoErr.Code != ErrOracleStale && daoErr.Code != ErrConversionSlippage) {
+		return err
+	}
+	pendingTx.Requeues++
+	pendingTx.RequeueReason = daoErr.Code.String()
+	return err
+}
+
+// IsConversionRequeue reports whether an execution error re-queued a stable
+// denominated transaction for a later retry
+func IsConversionRequeue(err error) bool {
+	var daoErr *DAOError
+	return errors.As(err, &daoErr) && (daoErr.Code == ErrOracleStale || daoErr.Code == ErrConversionSlippage)
+}
+
+// RetryConversions retries the execution of fully signed stable denominated
+// transactions that were re-queued, oldest first, and returns how many
+// executed
+func (tm *TreasuryManager) RetryConversions() int {
+	now := time.Now().Unix()
+	var queued []*PendingTx
+	for _, tx := range tm.governanceState.Treasury.Transactions {
+		if tx.Denomination != "" && tx.Requeues > 0 && !tx.Executed && now <= tx.ExpiresAt &&
+			tx.SignatureCount() >= int(tm.governanceState.Treasury.RequiredSigs) {
+			queued = append(queued, tx)
+		}
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		if queued[i].CreatedAt != queued[j].CreatedAt {
+			return queued[i].CreatedAt < queued[j].CreatedAt
+		}
+		return queued[i].ID.String() < queued[j].ID.String()
+	})
+
+	executed := 0
+	for _, tx := range queued {
+		if tm.verifyTreasurySignatures(tx) != nil {
+			continue
+		}
+		if tm.executeTreasuryTransaction(tx.ID) == nil {
+			executed++
+		}
+	}
+	return executed
+}
+
 // GetPendingTreasuryTransactions returns all pending treasury transactions
 func (tm *TreasuryManager) GetPendingTreasuryTransactions() map[types.Hash]*PendingTx {
 	pending := make(map[types.Hash]*PendingTx)
@@ -171,13 +335,40 @@ func (tm *TreasuryManager) GetPendingTreasuryTransactions() map[types.Hash]*Pend
 
 // GetTreasuryTransaction returns a specific treasury transaction
 func (tm *TreasuryManager) GetTreasuryTransaction(txHash types.Hash) (*PendingTx, bool) {
-	tx, exists := tm.governanceState.Treasury.Transactions[txHash]
-	return tx, exists
+	return tm.governanceState.TreasuryTransaction(txHash)
+}
+
+// AwaitingSigners returns the authorized signers who have not signed a
+// pending treasury transaction, none once it executed or expired
+func (tm *TreasuryManager) AwaitingSigners(txHash types.Hash) []crypto.PublicKey {
+	pendingTx, exists := tm.governanceState.TreasuryTransaction(txHash)
+	if !exists || pendingTx.Executed || time.Now().Unix() > pendingTx.ExpiresAt {
+		return nil
+	}
+
+	awaiting := make([]crypto.PublicKey, 0)
+	for _, signer := range tm.governanceState.Treasury.Signers {
+		if !tm.hasSignerSigned(pendingTx, signer) {
+			awaiting = append(awaiting, signer)
+		}
+	}
+	return awaiting
 }
 
 // AddTreasuryFunds adds funds to the treasury
 func (tm *TreasuryManager) AddTreasuryFunds(amount uint64) {
-	tm.governanceState.Treasury.Balance += amount
+	tm.governanceState.Treasury.recordInflow(amount, InflowSourceDeposit, time.Now().Unix())
+}
+
+// recordInflow adds funds to the treasury and keeps them in the inflow
+// history statements are built from
+func (ts *TreasuryState) recordInflow(amount uint64, source string, timestamp int64) {
+	if amount == 0 {
+		return
+	}
+	ts.Balance += amount
+	ts.TotalInflows += amount
+	ts.Inflows = append(ts.Inflows, TreasuryInflow{Amount: amount, Source: source, Timestamp: timestamp})
 }
 
 // GetTreasuryBalance returns the current treasury balance
@@ -228,18 +419,25 @@ func (tm *TreasuryManager) CleanupExpiredTransactions() int {
 
 // GetTreasuryHistory returns all treasury transactions (executed and pending)
 func (tm *TreasuryManager) GetTreasuryHistory() map[types.Hash]*PendingTx {
-	return tm.governanceState.Treasury.Transactions
+	history := make(map[types.Hash]*PendingTx)
+	tm.governanceState.EachTreasuryTransaction(func(txHash types.Hash, tx *PendingTx) bool {
+		history[txHash] = tx
+		return true
+	})
+
+	return history
 }
 
 // GetExecutedTreasuryTransactions returns only executed treasury transactions
 func (tm *TreasuryManager) GetExecutedTreasuryTransactions() map[types.Hash]*PendingTx {
 	executed := make(map[types.Hash]*PendingTx)
 
-	for txHash, tx := range tm.governanceState.Treasury.Transactions {
+	tm.governanceState.EachTreasuryTransaction(func(txHash types.Hash, tx *PendingTx) bool {
 		if tx.Executed {
 			executed[txHash] = tx
 		}
-	}
+		return true
+	})
 
 	return executed
 }
@@ -257,6 +455,21 @@ func (tm *TreasuryManager) isAuthorizedSigner(pubKey crypto.PublicKey) bool {
 
 // hasSignerSigned checks if a signer has already signed a transaction
 func (tm *TreasuryManager) hasSignerSigned(pendingTx *PendingTx, signer crypto.PublicKey) bool {
+	if pendingTx.Threshold != nil && tm.governanceState.Treasury.ThresholdKey != nil {
+		index := tm.governanceState.Treasury.ThresholdKey.participant(signer)
+		if _, signed := pendingTx.Threshold.Partials[index]; signed {
+			return true
+		}
+	}
+	if pendingTx.Aggregate != nil {
+		for _, key := range pendingTx.Aggregate.Signers {
+			if key.String() == signer.String() {
+				return true
+			}
+		}
+		return false
+	}
+
 	txData := tm.createTreasuryTxData(pendingTx)
 
 	for _, sig := range pendingTx.Signatures {
@@ -269,21 +482,29 @@ func (tm *TreasuryManager) hasSignerSigned(pendingTx *PendingTx, signer crypto.P
 
 // createTreasuryTxData creates the data to be signed for a treasury transaction
 func (tm *TreasuryManager) createTreasuryTxData(pendingTx *PendingTx) []byte {
+	// Stable denominated transactions are signed over their stable amount so
+	// converting them again at execution keeps the signatures valid
+	amount := pendingTx.Amount
+	if pendingTx.Denomination != "" {
+		amount = pendingTx.StableAmount
+	}
+
 	// Create a deterministic hash of the transaction data
 	hasher := sha256.New()
 	hasher.Write(pendingTx.ID.ToSlice())
 	hasher.Write([]byte(pendingTx.Recipient))
 	hasher.Write([]byte{
-		byte(pendingTx.Amount >> 56),
-		byte(pendingTx.Amount >> 48),
-		byte(pendingTx.Amount >> 40),
-		byte(pendingTx.Amount >> 32),
-		byte(pendingTx.Amount >> 24),
-		byte(pendingTx.Amount >> 16),
-		byte(pendingTx.Amount >> 8),
-		byte(pendingTx.Amount),
+		byte(amount >> 56),
+		byte(amount >> 48),
+		byte(amount >> 40),
+		byte(amount >> 32),
+		byte(amount >> 24),
+		byte(amount >> 16),
+		byte(amount >> 8),
+		byte(amount),
 	})
 	hasher.Write([]byte(pendingTx.Purpose))
+	hasher.Write([]byte(pendingTx.Denomination))
 	hasher.Write([]byte{
 		byte(pendingTx.CreatedAt >> 56),
 		byte(pendingTx.CreatedAt >> 48),
@@ -300,24 +521,23 @@ func (tm *TreasuryManager) createTreasuryTxData(pendingTx *PendingTx) []byte {
 
 // verifyTreasurySignatures verifies all signatures on a treasury transaction
 func (tm *TreasuryManager) verifyTreasurySignatures(pendingTx *PendingTx) error {
-	txData := tm.createTreasuryTxData(pendingTx)
-	validSignatures := 0
-
-	// Check each signature against authorized signers
-	for _, sig := range pendingTx.Signatures {
-		signatureValid := false
-
-		for _, signer := range tm.governanceState.Treasury.Signers {
-			if sig.Verify(signer, txData) {
-				signatureValid = true
-				validSignatures++
-				break
-			}
+	if pendingTx.Threshold != nil && pendingTx.Threshold.Signature != nil {
+		return tm.verifyThresholdSignature(pendingTx)
+	}
+	if pendingTx.Aggregate != nil {
+		if err := tm.verifyAggregateSignature(pendingTx, pendingTx.Aggregate); err != nil {
+			return err
 		}
-
-		if !signatureValid {
-			return NewDAOError(ErrInvalidSignature, "invalid signature found in treasury transaction", nil)
+		if len(pendingTx.Aggregate.Signers) < int(tm.governanceState.Treasury.RequiredSigs) {
+			return NewDAOError(ErrInvalidSignature, "insufficient valid signatures", nil)
 		}
+		return nil
+	}
+
+	// Check each signature against authorized signers
+	validSignatures, err := tm.validator.ValidateTreasurySignatures(tm.createTreasuryTxData(pendingTx), pendingTx.Signatures)
+	if err != nil {
+		return err
 	}
 
 	if validSignatures < int(tm.governanceState.Treasury.RequiredSigs) {
@@ -326,3 +546,22 @@ func (tm *TreasuryManager) verifyTreasurySignatures(pendingTx *PendingTx) error
 
 	return nil
 }
+
+// verifyAggregateSignature checks that an aggregate signature of a
+// treasury transaction is signed by authorized signers only
+func (tm *TreasuryManager) verifyAggregateSignature(pendingTx *PendingTx, sig *crypto.AggregateSignature) error {
+	if sig == nil {
+		return NewDAOError(ErrInvalidSignature, "missing aggregate signature", nil)
+	}
+	for _, signer := range sig.Signers {
+		if !tm.isAuthorizedSigner(signer) {
+			return NewDAOError(ErrUnauthorized, "aggregate signer not authorized for treasury operations", map[string]interface{}{
+				"signer": signer.String(),
+			})
+		}
+	}
+	if err := crypto.VerifyAggregate(sig, tm.createTreasuryTxData(pendingTx)); err != nil {
+		return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+	}
+	return nil
+}
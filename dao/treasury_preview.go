@@ -0,0 +1,201 @@
+package dao
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// TreasuryPreviewRequest is a treasury disbursement to preview, either a new
+// one or a pending transaction by ID
+type TreasuryPreviewRequest struct {
+	TransactionID types.Hash // Pending transaction, zero for a new one
+	Recipient     crypto.PublicKey
+	Amount        uint64 // In the stable unit when Denomination is set
+	Denomination  string
+	Purpose       string
+	Signer        crypto.PublicKey // Signer to check, optional
+}
+
+// TreasuryPreview is what a treasury transaction would do if it executed
+// now, with the issues that would keep it from executing
+type TreasuryPreview struct {
+	Valid           bool                   `json:"valid"`
+	Issues          []DraftIssue           `json:"issues"`
+	Amount          uint64                 `json:"amount"` // Native amount paid
+	StableAmount    uint64                 `json:"stable_amount,omitempty"`
+	Denomination    string                 `json:"denomination,omitempty"`
+	BudgetLine      string                 `json:"budget_line"`
+	BudgetLineSpent uint64                 `json:"budget_line_spent"` // Paid out of the budget line this month
+	MaxWithdrawal   uint64                 `json:"max_withdrawal"`    // Governance limit, 0 for none
+	TreasuryBalance uint64                 `json:"treasury_balance"`
+	Committed       uint64                 `json:"committed"` // Held by other pending transactions
+	TreasuryAfter   uint64                 `json:"treasury_after"`
+	RecipientBefore uint64                 `json:"recipient_balance"`
+	RecipientAfter  uint64                 `json:"recipient_after"`
+	Signers         []string               `json:"signers"`
+	RequiredSigs    uint8                  `json:"required_sigs"`
+	Signatures      int                    `json:"signatures"`
+	Awaiting        []string               `json:"awaiting"` // Signers yet to sign
+	SigningPayload  TreasurySigningPayload `json:"signing_payload"`
+}
+
+// TreasurySigningPayload is what treasury signers sign: the sha256 of the
+// transaction ID, recipient, big-endian amount, purpose, denomination and
+// big-endian creation time. The ID and creation time are set when the
+// transaction is created, so only pending transactions have a digest.
+type TreasurySigningPayload struct {
+	TransactionID string `json:"transaction_id,omitempty"`
+	Recipient     string `json:"recipient"`
+	Amount        uint64 `json:"amount"` // Stable amount of stable transactions
+	Purpose       string `json:"purpose"`
+	Denomination  string `json:"denomination,omitempty"`
+	CreatedAt     int64  `json:"created_at,omitempty"`
+	Digest        string `json:"digest,omitempty"`
+}
+
+// PreviewTreasuryTransaction checks a treasury disbursement against the
+// treasury balance and the funds pending transactions hold, its budget
+// line, the signer set and the governance withdrawal limit, and predicts
+// the balances it leaves
+func (d *DAO) PreviewTreasuryTransaction(req TreasuryPreviewRequest) (*TreasuryPreview, error) {
+	now := time.Now().Unix()
+	treasury := d.GovernanceState.Treasury
+	tm := d.TreasuryManager
+
+	preview := &TreasuryPreview{
+		Issues:          make([]DraftIssue, 0),
+		MaxWithdrawal:   d.GetParameterConfig().MaxTreasuryWithdraw,
+		TreasuryBalance: treasury.Balance,
+		Committed:       treasury.Balance - treasury.UnrestrictedBalance(now),
+		RequiredSigs:    treasury.RequiredSigs,
+		Signers:         make([]string, len(treasury.Signers)),
+		Awaiting:        make([]string, 0),
+	}
+	issue := func(field, format string, args ...interface{}) {
+		preview.Issues = append(preview.Issues, DraftIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	for i, signer := range treasury.Signers {
+		preview.Signers[i] = signer.String()
+	}
+
+	var pendingTx *PendingTx
+	if req.TransactionID != (types.Hash{}) {
+		existing, exists := tm.GetTreasuryTransaction(req.TransactionID)
+		if !exists {
+			return nil, NewDAOError(ErrProposalNotFound, "treasury transaction not found", nil)
+		}
+		pendingTx = existing
+		req.Recipient = existing.Recipient
+		req.Purpose = existing.Purpose
+		req.Denomination = existing.Denomination
+		req.Amount = existing.Amount
+		if existing.Denomination != "" {
+			req.Amount = existing.StableAmount
+		}
+
+		if existing.Executed {
+			issue("transaction_id", "Treasury transaction has already executed")
+		} else if now > existing.ExpiresAt {
+			issue("transaction_id", "Treasury transaction expired at %d", existing.ExpiresAt)
+		} else if preview.Committed >= existing.Amount {
+			preview.Committed -= existing.Amount
+		}
+		preview.Signatures = existing.SignatureCount()
+	}
+
+	if len(req.Recipient) == 0 {
+		issue("recipient", "Recipient is required")
+	}
+	if len(req.Purpose) == 0 || len(req.Purpose) > 500 {
+		issue("purpose", "Purpose must be between 1 and 500 characters")
+	}
+
+	// Stable amounts convert at the current price, as they will at execution
+	preview.Amount = req.Amount
+	if req.Denomination != "" {
+		preview.Denomination = req.Denomination
+		preview.StableAmount = req.Amount
+		quoted, err := tm.convert(req.Denomination, req.Amount, now)
+		if err != nil {
+			issue("denomination", "%s", err.Error())
+		} else if quoted == 0 && req.Amount > 0 {
+			issue("amount", "Amount converts to zero tokens")
+		}
+		preview.Amount = quoted
+	}
+
+	switch {
+	case req.Amount == 0:
+		issue("amount", "Amount must be greater than zero")
+	case preview.Amount > treasury.Balance:
+		issue("amount", "Amount exceeds the treasury balance of %d", treasury.Balance)
+	case preview.Amount > treasury.Balance-preview.Committed:
+		issue("amount", "Amount exceeds the %d not held by pending treasury transactions", treasury.Balance-preview.Committed)
+	}
+	if preview.MaxWithdrawal > 0 && preview.Amount > preview.MaxWithdrawal {
+		issue("amount", "Amount exceeds the maximum treasury withdrawal of %d", preview.MaxWithdrawal)
+	}
+
+	// Signer set
+	if len(treasury.Signers) == 0 {
+		issue("signers", "The treasury has no signers")
+	} else if int(treasury.RequiredSigs) > len(treasury.Signers) {
+		issue("signers", "%d signatures are required but the treasury has %d signers", treasury.RequiredSigs, len(treasury.Signers))
+	}
+	for _, signer := range treasury.Signers {
+		if pendingTx == nil || !tm.hasSignerSigned(pendingTx, signer) {
+			preview.Awaiting = append(preview.Awaiting, signer.String())
+		}
+	}
+	if len(req.Signer) > 0 {
+		if !tm.isAuthorizedSigner(req.Signer) {
+			issue("signer", "Signer is not a treasury signer")
+		} else if pendingTx != nil && tm.hasSignerSigned(pendingTx, req.Signer) {
+			issue("signer", "Signer has already signed this transaction")
+		}
+	}
+
+	// Budget line spending this month
+	preview.BudgetLine = req.Purpose
+	if preview.BudgetLine == "" {
+		preview.BudgetLine = UnspecifiedBudgetLine
+	}
+	monthStart, _, _ := ParseStatementPeriod(time.Unix(now, 0).UTC().Format("2006-01"))
+	for _, tx := range treasury.Transactions {
+		line := tx.Purpose
+		if line == "" {
+			line = UnspecifiedBudgetLine
+		}
+		if tx.Executed && tx.ExecutedAt >= monthStart && line == preview.BudgetLine {
+			preview.BudgetLineSpent += tx.Amount
+		}
+	}
+
+	// Balances after execution
+	preview.RecipientBefore = d.TokenState.Balances.Get(req.Recipient.String())
+	preview.TreasuryAfter = treasury.Balance
+	preview.RecipientAfter = preview.RecipientBefore
+	preview.Valid = len(preview.Issues) == 0
+	if preview.Valid {
+		preview.TreasuryAfter -= preview.Amount
+		preview.RecipientAfter += preview.Amount
+	}
+
+	preview.SigningPayload = TreasurySigningPayload{
+		Recipient:    req.Recipient.String(),
+		Amount:       req.Amount,
+		Purpose:      req.Purpose,
+		Denomination: req.Denomination,
+	}
+	if pendingTx != nil {
+		preview.SigningPayload.TransactionID = pendingTx.ID.String()
+		preview.SigningPayload.CreatedAt = pendingTx.CreatedAt
+		preview.SigningPayload.Digest = hex.EncodeToString(tm.createTreasuryTxData(pendingTx))
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewTreasuryTransaction(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	signer1 := crypto.GeneratePrivateKey()
+	signer2 := crypto.GeneratePrivateKey()
+	require.NoError(t, dao.InitializeTreasury([]crypto.PublicKey{signer1.PublicKey(), signer2.PublicKey()}, 2))
+	dao.AddTreasuryFunds(10000)
+	recipient := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(recipient.String(), 50)
+
+	// A valid disbursement predicts the balances it leaves
+	preview, err := dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{
+		Recipient: recipient,
+		Amount:    3000,
+		Purpose:   "Audit",
+		Signer:    signer1.PublicKey(),
+	})
+	require.NoError(t, err)
+	assert.True(t, preview.Valid)
+	assert.Empty(t, preview.Issues)
+	assert.Equal(t, uint64(7000), preview.TreasuryAfter)
+	assert.Equal(t, uint64(50), preview.RecipientBefore)
+	assert.Equal(t, uint64(3050), preview.RecipientAfter)
+	assert.Len(t, preview.Awaiting, 2)
+	assert.Empty(t, preview.SigningPayload.Digest)
+
+	// Pending transactions hold funds, and the governance limit applies
+	pendingID := types.Hash{0x01}
+	require.NoError(t, dao.CreateTreasuryTransaction(&TreasuryTx{Recipient: recipient, Amount: 6000, Purpose: "Audit", RequiredSigs: 2}, pendingID))
+	preview, err = dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{
+		Recipient: recipient,
+		Amount:    5000,
+		Purpose:   "Audit",
+		Signer:    crypto.GeneratePrivateKey().PublicKey(),
+	})
+	require.NoError(t, err)
+	assert.False(t, preview.Valid)
+	assert.Equal(t, uint64(6000), preview.Committed)
+	assert.Equal(t, uint64(10000), preview.TreasuryAfter)
+	fields := make([]string, 0)
+	for _, issue := range preview.Issues {
+		fields = append(fields, issue.Field)
+	}
+	assert.ElementsMatch(t, []string{"amount", "signer"}, fields)
+
+	dao.ParameterManager.parameterConfig.MaxTreasuryWithdraw = 1000
+	preview, err = dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{Recipient: recipient, Amount: 2000, Purpose: "Audit"})
+	require.NoError(t, err)
+	require.Len(t, preview.Issues, 1)
+	assert.Contains(t, preview.Issues[0].Message, "maximum treasury withdrawal")
+	dao.ParameterManager.parameterConfig.MaxTreasuryWithdraw = 0
+
+	// Pending transactions come with the digest signers sign
+	require.NoError(t, dao.SignTreasuryTransaction(pendingID, signer1))
+	preview, err = dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{TransactionID: pendingID, Signer: signer1.PublicKey()})
+	require.NoError(t, err)
+	assert.False(t, preview.Valid)
+	assert.Equal(t, "signer", preview.Issues[0].Field)
+	assert.Zero(t, preview.Committed)
+	assert.Equal(t, 1, preview.Signatures)
+	assert.Equal(t, []string{signer2.PublicKey().String()}, preview.Awaiting)
+
+	preview, err = dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{TransactionID: pendingID, Signer: signer2.PublicKey()})
+	require.NoError(t, err)
+	assert.True(t, preview.Valid)
+	assert.Equal(t, uint64(4000), preview.TreasuryAfter)
+	pendingTx, _ := dao.GetTreasuryTransaction(pendingID)
+	digest, err := hex.DecodeString(preview.SigningPayload.Digest)
+	require.NoError(t, err)
+	signature, err := signer2.Sign(digest)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(signer2.PublicKey(), dao.TreasuryManager.createTreasuryTxData(pendingTx)))
+
+	// Executed spending counts against the budget line
+	require.NoError(t, dao.SignTreasuryTransaction(pendingID, signer2))
+	preview, err = dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{Recipient: recipient, Amount: 100, Purpose: "Audit"})
+	require.NoError(t, err)
+	assert.Equal(t, "Audit", preview.BudgetLine)
+	assert.Equal(t, uint64(6000), preview.BudgetLineSpent)
+
+	_, err = dao.PreviewTreasuryTransaction(TreasuryPreviewRequest{TransactionID: types.Hash{0x02}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,246 @@
+package dao
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MaxStatementRecipients is how many of the largest recipients a treasury
+// statement lists
+const MaxStatementRecipients = 10
+
+// Statement entry types
+const (
+	StatementInflow  = "inflow"
+	StatementOutflow = "outflow"
+)
+
+// UnspecifiedBudgetLine is the budget line of treasury transactions without
+// a purpose
+const UnspecifiedBudgetLine = "Unspecified"
+
+// TreasuryStatement accounts for the treasury over a calendar period. Outflows
+// are the executed treasury transactions, grouped into budget lines by their
+// purpose, and inflows are the funds recorded as added to the treasury.
+type TreasuryStatement struct {
+	Period            string            `json:"period"`
+	From              int64             `json:"from"`
+	To                int64             `json:"to"` // Exclusive
+	OpeningBalance    uint64            `json:"opening_balance"`
+	ClosingBalance    uint64            `json:"closing_balance"`
+	TotalInflows      uint64            `json:"total_inflows"`
+	TotalOutflows     uint64            `json:"total_outflows"`
+	NetFlow           int64             `json:"net_flow"`
+	InflowsBySource   map[string]uint64 `json:"inflows_by_source"`
+	BudgetLines       []BudgetLineTotal `json:"budget_lines"` // Largest first
+	LargestRecipients []RecipientTotal  `json:"largest_recipients"`
+	Entries           []StatementEntry  `json:"entries"` // Oldest first
+}
+
+// BudgetLineTotal is what the treasury paid out for one purpose
+type BudgetLineTotal struct {
+	BudgetLine   string `json:"budget_line"`
+	Amount       uint64 `json:"amount"`
+	Transactions int    `json:"transactions"`
+}
+
+// RecipientTotal is what the treasury paid one recipient
+type RecipientTotal struct {
+	Address      string `json:"address"`
+	Amount       uint64 `json:"amount"`
+	Transactions int    `json:"transactions"`
+}
+
+// StatementEntry is a line of a statement with the balance it left
+type StatementEntry struct {
+	Timestamp    int64  `json:"timestamp"`
+	Type         string `json:"type"`
+	Category     string `json:"category"` // Source of an inflow, budget line of an outflow
+	Counterparty string `json:"counterparty,omitempty"`
+	Reference    string `json:"reference,omitempty"` // Treasury transaction ID
+	Amount       uint64 `json:"amount"`
+	Balance      uint64 `json:"balance"`
+}
+
+// ParseStatementPeriod returns the UTC bounds of a month ("2024-06") or a
+// year ("2024"), the end exclusive
+func ParseStatementPeriod(period string) (int64, int64, error) {
+	if month, err := time.Parse("2006-01", period); err == nil {
+		return month.Unix(), month.AddDate(0, 1, 0).Unix(), nil
+	}
+	if year, err := time.Parse("2006", period); err == nil {
+		return year.Unix(), year.AddDate(1, 0, 0).Unix(), nil
+	}
+	return 0, 0, NewDAOError(ErrInvalidTimeframe, "period must be a month such as 2024-06 or a year such as 2024", map[string]interface{}{"period": period})
+}
+
+// GenerateStatement builds the treasury statement of a period. The opening
+// balance is worked back from the current balance through the flows the
+// statements itemize, so it leaves out movements such as grant escrow.
+func (tm *TreasuryManager) GenerateStatement(period string, now int64) (*TreasuryStatement, error) {
+	from, to, err := ParseStatementPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	if from > now {
+		return nil, NewDAOError(ErrInvalidTimeframe, "period has not started", map[string]interface{}{"period": period})
+	}
+
+	treasury := tm.governanceState.Treasury
+	statement := &TreasuryStatement{
+		Period:            period,
+		From:              from,
+		To:                to,
+		InflowsBySource:   make(map[string]uint64),
+		BudgetLines:       make([]BudgetLineTotal, 0),
+		LargestRecipients: make([]RecipientTotal, 0),
+		Entries:           make([]StatementEntry, 0),
+	}
+
+	// Flows since the period opened separate its opening from today's balance
+	opening := int64(treasury.Balance)
+	for _, inflow := range treasury.Inflows {
+		if inflow.Timestamp >= from {
+			opening -= int64(inflow.Amount)
+		}
+		if inflow.Timestamp >= from && inflow.Timestamp < to {
+			statement.Entries = append(statement.Entries, StatementEntry{
+				Timestamp: inflow.Timestamp,
+				Type:      StatementInflow,
+				Category:  inflow.Source,
+				Amount:    inflow.Amount,
+			})
+		}
+	}
+	for id, tx := range treasury.Transactions {
+		if !tx.Executed || tx.ExecutedAt < from {
+			continue
+		}
+		opening += int64(tx.Amount)
+		if tx.ExecutedAt >= to {
+			continue
+		}
+
+		budgetLine := tx.Purpose
+		if budgetLine == "" {
+			budgetLine = UnspecifiedBudgetLine
+		}
+		statement.Entries = append(statement.Entries, StatementEntry{
+			Timestamp:    tx.ExecutedAt,
+			Type:         StatementOutflow,
+			Category:     budgetLine,
+			Counterparty: tx.Recipient.String(),
+			Reference:    id.String(),
+			Amount:       tx.Amount,
+		})
+	}
+	if opening < 0 {
+		opening = 0
+	}
+
+	sort.Slice(statement.Entries, func(i, j int) bool {
+		a, b := statement.Entries[i], statement.Entries[j]
+		if a.Timestamp != b.Timestamp {
+			return a.Timestamp < b.Timestamp
+		}
+		if a.Type != b.Type {
+			return a.Type == StatementInflow
+		}
+		return a.Reference < b.Reference
+	})
+
+	budgetLines := make(map[string]*BudgetLineTotal)
+	recipients := make(map[string]*RecipientTotal)
+	balance := opening
+	for i := range statement.Entries {
+		entry := &statement.Entries[i]
+		if entry.Type == StatementInflow {
+			balance += int64(entry.Amount)
+			statement.TotalInflows += entry.Amount
+			statement.InflowsBySource[entry.Category] += entry.Amount
+		} else {
+			balance -= int64(entry.Amount)
+			statement.TotalOutflows += entry.Amount
+
+			if _, exists := budgetLines[entry.Category]; !exists {
+				budgetLines[entry.Category] = &BudgetLineTotal{BudgetLine: entry.Category}
+			}
+			budgetLines[entry.Category].Amount += entry.Amount
+			budgetLines[entry.Category].Transactions++
+
+			if _, exists := recipients[entry.Counterparty]; !exists {
+				recipients[entry.Counterparty] = &RecipientTotal{Address: entry.Counterparty}
+			}
+			recipients[entry.Counterparty].Amount += entry.Amount
+			recipients[entry.Counterparty].Transactions++
+		}
+		if balance < 0 {
+			balance = 0
+		}
+		entry.Balance = uint64(balance)
+	}
+
+	statement.OpeningBalance = uint64(opening)
+	statement.ClosingBalance = uint64(balance)
+	statement.NetFlow = int64(statement.TotalInflows) - int64(statement.TotalOutflows)
+
+	for _, line := range budgetLines {
+		statement.BudgetLines = append(statement.BudgetLines, *line)
+	}
+	sort.Slice(statement.BudgetLines, func(i, j int) bool {
+		if statement.BudgetLines[i].Amount != statement.BudgetLines[j].Amount {
+			return statement.BudgetLines[i].Amount > statement.BudgetLines[j].Amount
+		}
+		return statement.BudgetLines[i].BudgetLine < statement.BudgetLines[j].BudgetLine
+	})
+
+	for _, recipient := range recipients {
+		statement.LargestRecipients = append(statement.LargestRecipients, *recipient)
+	}
+	sort.Slice(statement.LargestRecipients, func(i, j int) bool {
+		if statement.LargestRecipients[i].Amount != statement.LargestRecipients[j].Amount {
+			return statement.LargestRecipients[i].Amount > statement.LargestRecipients[j].Amount
+		}
+		return statement.LargestRecipients[i].Address < statement.LargestRecipients[j].Address
+	})
+	if len(statement.LargestRecipients) > MaxStatementRecipients {
+		statement.LargestRecipients = statement.LargestRecipients[:MaxStatementRecipients]
+	}
+
+	return statement, nil
+}
+
+// WriteCSV writes the entries of the statement as CSV, framed by opening and
+// closing balance rows
+func (s *TreasuryStatement) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	format := func(timestamp int64) string {
+		return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	}
+
+	rows := [][]string{
+		{"date", "type", "category", "counterparty", "reference", "amount", "balance"},
+		{format(s.From), "opening_balance", "", "", "", "", strconv.FormatUint(s.OpeningBalance, 10)},
+	}
+	for _, entry := range s.Entries {
+		rows = append(rows, []string{
+			format(entry.Timestamp),
+			entry.Type,
+			entry.Category,
+			entry.Counterparty,
+			entry.Reference,
+			strconv.FormatUint(entry.Amount, 10),
+			strconv.FormatUint(entry.Balance, 10),
+		})
+	}
+	rows = append(rows, []string{format(s.To), "closing_balance", "", "", "", "", strconv.FormatUint(s.ClosingBalance, 10)})
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	return nil
+}
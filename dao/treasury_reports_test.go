@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreasuryStatement(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	treasury := dao.GovernanceState.Treasury
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+
+	may := time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC).Unix()
+	june := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC).Unix()
+	july := time.Date(2024, time.July, 3, 0, 0, 0, 0, time.UTC).Unix()
+
+	treasury.recordInflow(10000, InflowSourceDeposit, may)
+	treasury.recordInflow(2000, InflowSourceDeposit, june+3600)
+	treasury.recordInflow(500, InflowSourceClawback, june+7200)
+	treasury.recordInflow(1000, InflowSourceDeposit, july)
+
+	payouts := []struct {
+		recipient  crypto.PublicKey
+		amount     uint64
+		purpose    string
+		executedAt int64
+		executed   bool
+	}{
+		{alice, 1500, "Audits", may + 60, true},
+		{alice, 3000, "Development", june + 86400, true},
+		{bob, 1000, "Development", june + 2*86400, true},
+		{bob, 400, "", june + 3*86400, true},
+		{bob, 9999, "Marketing", 0, false},
+		{alice, 700, "Audits", july + 60, true},
+	}
+	for i, payout := range payouts {
+		id := types.Hash{byte(i + 1)}
+		treasury.Transactions[id] = &PendingTx{
+			ID: id, Recipient: payout.recipient, Amount: payout.amount, Purpose: payout.purpose,
+			Executed: payout.executed, ExecutedAt: payout.executedAt,
+		}
+		if payout.executed {
+			treasury.Balance -= payout.amount
+		}
+	}
+	require.Equal(t, uint64(6900), treasury.Balance)
+
+	statement, err := dao.TreasuryManager.GenerateStatement("2024-06", july+86400)
+	require.NoError(t, err)
+	assert.Equal(t, june, statement.From)
+	assert.Equal(t, uint64(8500), statement.OpeningBalance)
+	assert.Equal(t, uint64(2500), statement.TotalInflows)
+	assert.Equal(t, uint64(4400), statement.TotalOutflows)
+	assert.Equal(t, int64(-1900), statement.NetFlow)
+	assert.Equal(t, uint64(6600), statement.ClosingBalance)
+	assert.Equal(t, uint64(500), statement.InflowsBySource[InflowSourceClawback])
+
+	require.Len(t, statement.BudgetLines, 2)
+	assert.Equal(t, BudgetLineTotal{BudgetLine: "Development", Amount: 4000, Transactions: 2}, statement.BudgetLines[0])
+	assert.Equal(t, UnspecifiedBudgetLine, statement.BudgetLines[1].BudgetLine)
+
+	require.Len(t, statement.LargestRecipients, 2)
+	assert.Equal(t, alice.String(), statement.LargestRecipients[0].Address)
+	assert.Equal(t, uint64(1400), statement.LargestRecipients[1].Amount)
+
+	require.Len(t, statement.Entries, 5)
+	assert.Equal(t, StatementInflow, statement.Entries[0].Type)
+	assert.Equal(t, uint64(10500), statement.Entries[0].Balance)
+	assert.Equal(t, statement.ClosingBalance, statement.Entries[4].Balance)
+
+	// The opening of a month is the closing of the one before
+	previous, err := dao.TreasuryManager.GenerateStatement("2024-05", july+86400)
+	require.NoError(t, err)
+	assert.Equal(t, statement.OpeningBalance, previous.ClosingBalance)
+
+	year, err := dao.TreasuryManager.GenerateStatement("2024", july+86400)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), year.OpeningBalance)
+	assert.Equal(t, treasury.Balance, year.ClosingBalance)
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteCSV(&buf))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 8)
+	assert.Equal(t, []string{"2024-06-01T00:00:00Z", "opening_balance", "", "", "", "", "8500"}, rows[1])
+	assert.Equal(t, "6600", rows[7][6])
+
+	_, err = dao.TreasuryManager.GenerateStatement("June", july)
+	assert.Error(t, err)
+	_, err = dao.TreasuryManager.GenerateStatement("2024-08", july)
+	assert.Error(t, err)
+}
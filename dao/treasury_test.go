@@ -153,12 +153,22 @@ func TestTreasuryManager_SignTreasuryTransaction(t *testing.T) {
 		t.Fatalf("Failed to create treasury transaction: %v", err)
 	}
 
+	// Both signers are awaited until they sign
+	if awaiting := dao.GetTreasuryAwaitingSigners(txHash); len(awaiting) != 2 {
+		t.Errorf("Expected 2 awaited signers, got %d", len(awaiting))
+	}
+
 	// Sign with first signer
 	err = dao.SignTreasuryTransaction(txHash, signer1)
 	if err != nil {
 		t.Fatalf("Failed to sign treasury transaction: %v", err)
 	}
 
+	awaiting := dao.GetTreasuryAwaitingSigners(txHash)
+	if len(awaiting) != 1 || awaiting[0].String() != signer2.PublicKey().String() {
+		t.Errorf("Expected only the second signer to be awaited, got %v", awaiting)
+	}
+
 	// Verify signature was added
 	pendingTx, _ := dao.GetTreasuryTransaction(txHash)
 	if len(pendingTx.Signatures) != 1 {
@@ -181,6 +191,9 @@ func TestTreasuryManager_SignTreasuryTransaction(t *testing.T) {
 	if !pendingTx.Executed {
 		t.Error("Transaction should be executed after sufficient signatures")
 	}
+	if awaiting := dao.GetTreasuryAwaitingSigners(txHash); len(awaiting) != 0 {
+		t.Errorf("Expected no awaited signers once executed, got %d", len(awaiting))
+	}
 
 	// Verify treasury balance was reduced
 	if dao.GetTreasuryBalance() != 5000 { // 10000 - 5000
@@ -193,3 +206,117 @@ func TestTreasuryManager_SignTreasuryTransaction(t *testing.T) {
 		t.Errorf("Expected recipient balance 5000, got %d", recipientBalance)
 	}
 }
+
+func TestTreasuryManager_AttachAggregateSignature(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	signers := []crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey(), keys[2].PublicKey()}
+	if err := dao.InitializeTreasury(signers, 2); err != nil {
+		t.Fatalf("Failed to initialize treasury: %v", err)
+	}
+	dao.AddTreasuryFunds(10000)
+
+	txHash := randomTreasuryHash()
+	err := dao.CreateTreasuryTransaction(&TreasuryTx{
+		Fee:          100,
+		Recipient:    crypto.GeneratePrivateKey().PublicKey(),
+		Amount:       4000,
+		Purpose:      "Audit",
+		RequiredSigs: 2,
+	}, txHash)
+	if err != nil {
+		t.Fatalf("Failed to create treasury transaction: %v", err)
+	}
+	pendingTx, _ := dao.GetTreasuryTransaction(txHash)
+	digest := dao.TreasuryManager.createTreasuryTxData(pendingTx)
+
+	aggregate := func(msg []byte, keys ...crypto.PrivateKey) *crypto.AggregateSignature {
+		var signers []crypto.PublicKey
+		for _, key := range keys {
+			signers = append(signers, key.PublicKey())
+		}
+		session, err := crypto.MuSig2{}.NewSession(signers, msg)
+		if err != nil {
+			t.Fatalf("Failed to start signing session: %v", err)
+		}
+		for _, key := range keys {
+			if _, err := session.Commit(key); err != nil {
+				t.Fatalf("Failed to commit: %v", err)
+			}
+		}
+		for _, key := range keys {
+			if _, err := session.Sign(key); err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+		}
+		sig, err := session.Signature()
+		if err != nil {
+			t.Fatalf("Failed to aggregate: %v", err)
+		}
+		return sig
+	}
+
+	// Too few signers, outsiders and other messages are rejected
+	if err := dao.AttachAggregateSignature(txHash, aggregate(digest, keys[0])); err == nil {
+		t.Error("Expected a single signer to be below the threshold")
+	}
+	if err := dao.AttachAggregateSignature(txHash, aggregate(digest, keys[0], crypto.GeneratePrivateKey())); err == nil {
+		t.Error("Expected an unauthorized signer to be rejected")
+	}
+	if err := dao.AttachAggregateSignature(txHash, aggregate([]byte("other"), keys[0], keys[1])); err == nil {
+		t.Error("Expected a signature of another message to be rejected")
+	}
+
+	if err := dao.SignTreasuryTransaction(txHash, keys[2]); err != nil {
+		t.Fatalf("Failed to sign treasury transaction: %v", err)
+	}
+
+	// The aggregate replaces the signature collected so far and executes
+	if err := dao.AttachAggregateSignature(txHash, aggregate(digest, keys[0], keys[1])); err != nil {
+		t.Fatalf("Failed to attach aggregate signature: %v", err)
+	}
+	pendingTx, _ = dao.GetTreasuryTransaction(txHash)
+	if !pendingTx.Executed {
+		t.Error("Transaction should be executed by the aggregate signature")
+	}
+	if len(pendingTx.Signatures) != 0 || pendingTx.SignatureCount() != 2 {
+		t.Errorf("Expected the aggregate of 2 signers alone, got %d signatures", len(pendingTx.Signatures))
+	}
+	if dao.GetTreasuryBalance() != 6000 {
+		t.Errorf("Expected treasury balance 6000, got %d", dao.GetTreasuryBalance())
+	}
+}
+
+func TestDAOValidator_ValidateTreasurySignatures(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	if err := dao.InitializeTreasury([]crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey(), keys[2].PublicKey()}, 2); err != nil {
+		t.Fatalf("Failed to initialize treasury: %v", err)
+	}
+
+	data := []byte("treasury transaction digest")
+	sign := func(key crypto.PrivateKey) crypto.Signature {
+		sig, err := key.Sign(data)
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		return *sig
+	}
+
+	valid, err := dao.Validator.ValidateTreasurySignatures(data, []crypto.Signature{sign(keys[2]), sign(keys[0])})
+	if err != nil || valid != 2 {
+		t.Errorf("Expected 2 valid signatures, got %d (%v)", valid, err)
+	}
+
+	// Copies of one signature approve once
+	valid, err = dao.Validator.ValidateTreasurySignatures(data, []crypto.Signature{sign(keys[1]), sign(keys[1])})
+	if err != nil || valid != 1 {
+		t.Errorf("Expected 1 distinct signer, got %d (%v)", valid, err)
+	}
+
+	outsider := sign(crypto.GeneratePrivateKey())
+	if _, err := dao.Validator.ValidateTreasurySignatures(data, []crypto.Signature{sign(keys[1]), outsider}); err == nil {
+		t.Error("Expected a signature of an outsider to be rejected")
+	}
+}
@@ -0,0 +1,382 @@
+package dao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// KeyGenerationPeriod is how long the treasury signers have to generate a
+// threshold key once a ceremony starts
+const KeyGenerationPeriod int64 = 86400
+
+// ThresholdSigningPeriod is how long a complete signing set has to sign a
+// treasury transaction before share holders may start the signing over
+const ThresholdSigningPeriod int64 = 3600
+
+// TreasuryThresholdKey is a key the treasury signers hold in shares, any
+// Threshold of them signing for the treasury. The signers generate it among
+// themselves; the node only relays their public messages, so no machine
+// ever holds the key.
+type TreasuryThresholdKey struct {
+	GroupKey     crypto.PublicKey
+	Threshold    uint8
+	Participants []crypto.PublicKey // Signer holding share i+1
+	Shares       []crypto.PublicKey // Public key of share i+1
+	CreatedAt    int64
+}
+
+// participant returns the share index of a signer, 0 when it holds no share
+func (k *TreasuryThresholdKey) participant(signer crypto.PublicKey) int {
+	return participantIndex(k.Participants, signer)
+}
+
+// shareKeys returns the public keys of the shares by index
+func (k *TreasuryThresholdKey) shareKeys() map[int]crypto.PublicKey {
+	shares := make(map[int]crypto.PublicKey, len(k.Shares))
+	for i, share := range k.Shares {
+		shares[i+1] = share
+	}
+	return shares
+}
+
+// TreasuryKeyGeneration is a key generation ceremony among the treasury
+// signers. Each signer deals, then every signer checks the shares dealt to
+// it and confirms; a single rejection aborts the ceremony.
+type TreasuryKeyGeneration struct {
+	Threshold     uint8
+	Participants  []crypto.PublicKey // Signer of participant index i+1
+	Dealings      map[int]*crypto.DKGDealing
+	Key           *TreasuryThresholdKey // Derived once every participant dealt
+	Confirmations map[int]bool
+	StartedAt     int64
+	ExpiresAt     int64
+}
+
+// ThresholdSigning collects the signing of a treasury transaction with the
+// threshold key: the first Threshold participants to commit sign it
+type ThresholdSigning struct {
+	Commitments map[int][]byte // Nonce commitment by share index
+	Partials    map[int][]byte // Partial signature by share index
+	Signature   []byte         // Set once every committed participant signed
+	CompletedAt int64          // When the last of the signing set committed
+}
+
+// stale reports whether the signing set completed more than
+// ThresholdSigningPeriod ago without signing
+func (s *ThresholdSigning) stale(now int64) bool {
+	return s.Signature == nil && s.CompletedAt > 0 && now > s.CompletedAt+ThresholdSigningPeriod
+}
+
+func participantIndex(participants []crypto.PublicKey, signer crypto.PublicKey) int {
+	signerStr := signer.String()
+	for i, participant := range participants {
+		if participant.String() == signerStr {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// StartKeyGeneration starts the generation of a threshold key among the
+// current treasury signers, any threshold of whom will sign. A zero
+// threshold uses the required signatures.
+func (tm *TreasuryManager) StartKeyGeneration(initiator crypto.PublicKey, threshold uint8) (*TreasuryKeyGeneration, error) {
+	treasury := tm.governanceState.Treasury
+	if !tm.isAuthorizedSigner(initiator) {
+		return nil, NewDAOError(ErrUnauthorized, "only treasury signers can start a key generation", nil)
+	}
+
+	now := time.Now().Unix()
+	if treasury.KeyGeneration != nil && now <= treasury.KeyGeneration.ExpiresAt {
+		return nil, NewDAOError(ErrInvalidProposal, "a key generation is already running", map[string]interface{}{
+			"expires_at": treasury.KeyGeneration.ExpiresAt,
+		})
+	}
+
+	if threshold == 0 {
+		threshold = treasury.RequiredSigs
+	}
+	if threshold < treasury.RequiredSigs || int(threshold) > len(treasury.Signers) {
+		return nil, NewDAOError(ErrInvalidProposal, fmt.Sprintf("threshold must be between the %d required signatures and the %d signers", treasury.RequiredSigs, len(treasury.Signers)), nil)
+	}
+	// Shares are sealed to the signers with P-256 ECIES
+	for _, signer := range treasury.Signers {
+		if signer.Curve() != crypto.CurveP256 {
+			return nil, NewDAOError(ErrInvalidSignature, "every treasury signer needs a P-256 key to receive shares", map[string]interface{}{
+				"signer": signer.String(),
+			})
+		}
+	}
+
+	participants := make([]crypto.PublicKey, len(treasury.Signers))
+	copy(participants, treasury.Signers)
+	treasury.KeyGeneration = &TreasuryKeyGeneration{
+		Threshold:     threshold,
+		Participants:  participants,
+		Dealings:      make(map[int]*crypto.DKGDealing),
+		Confirmations: make(map[int]bool),
+		StartedAt:     now,
+		ExpiresAt:     now + KeyGenerationPeriod,
+	}
+	return treasury.KeyGeneration, nil
+}
+
+// runningKeyGeneration returns the key generation a signer takes part in
+// and its participant index
+func (tm *TreasuryManager) runningKeyGeneration(signer crypto.PublicKey) (*TreasuryKeyGeneration, int, error) {
+	keygen := tm.governanceState.Treasury.KeyGeneration
+	if keygen == nil || time.Now().Unix() > keygen.ExpiresAt {
+		return nil, 0, NewDAOError(ErrInvalidProposal, "no key generation is running", nil)
+	}
+	index := participantIndex(keygen.Participants, signer)
+	if index == 0 {
+		return nil, 0, NewDAOError(ErrUnauthorized, "signer does not take part in the key generation", nil)
+	}
+	return keygen, index, nil
+}
+
+// SubmitKeyDealing records the dealing of a participant. Once every
+// participant dealt, the group key and the public keys of the shares are
+// derived and await confirmation.
+func (tm *TreasuryManager) SubmitKeyDealing(signer crypto.PublicKey, dealing *crypto.DKGDealing) error {
+	keygen, index, err := tm.runningKeyGeneration(signer)
+	if err != nil {
+		return err
+	}
+	if _, dealt := keygen.Dealings[index]; dealt {
+		return NewDAOError(ErrDuplicateVote, "participant already dealt", nil)
+	}
+	if dealing.From != index {
+		return NewDAOError(ErrInvalidProposal, fmt.Sprintf("dealing must be from participant %d", index), nil)
+	}
+	if len(dealing.Commitments) != int(keygen.Threshold) {
+		return NewDAOError(ErrInvalidProposal, fmt.Sprintf("dealing must commit to %d coefficients", keygen.Threshold), nil)
+	}
+	for i := range keygen.Participants {
+		if i+1 == index {
+			continue
+		}
+		if len(dealing.Shares[i+1]) == 0 {
+			return NewDAOError(ErrInvalidProposal, fmt.Sprintf("dealing has no share for participant %d", i+1), nil)
+		}
+	}
+	if len(dealing.Shares) != len(keygen.Participants)-1 {
+		return NewDAOError(ErrInvalidProposal, "dealing has shares for unknown participants", nil)
+	}
+	if err := dealing.VerifyProof(crypto.CurveP256); err != nil {
+		return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+	}
+
+	keygen.Dealings[index] = dealing
+	if len(keygen.Dealings) < len(keygen.Participants) {
+		return nil
+	}
+
+	dealings := make([]*crypto.DKGDealing, 0, len(keygen.Dealings))
+	for i := range keygen.Participants {
+		dealings = append(dealings, keygen.Dealings[i+1])
+	}
+	groupKey, shares, err := crypto.DKGPublicKeys(crypto.CurveP256, int(keygen.Threshold), dealings)
+	if err != nil {
+		// A malformed dealing spoils the ceremony for everyone
+		tm.governanceState.Treasury.KeyGeneration = nil
+		return NewDAOError(ErrInvalidProposal, fmt.Sprintf("key generation aborted: %v", err), nil)
+	}
+
+	key := &TreasuryThresholdKey{
+		GroupKey:     groupKey,
+		Threshold:    keygen.Threshold,
+		Participants: keygen.Participants,
+		Shares:       make([]crypto.PublicKey, len(keygen.Participants)),
+		CreatedAt:    time.Now().Unix(),
+	}
+	for i := range key.Shares {
+		key.Shares[i] = shares[i+1]
+	}
+	keygen.Key = key
+	return nil
+}
+
+// ConfirmKeyGeneration records whether a participant could open and check
+// the shares dealt to it. The key replaces the treasury threshold key once
+// every participant accepts; a rejection aborts the ceremony.
+func (tm *TreasuryManager) ConfirmKeyGeneration(signer crypto.PublicKey, accept bool) error {
+	keygen, index, err := tm.runningKeyGeneration(signer)
+	if err != nil {
+		return err
+	}
+	if keygen.Key == nil {
+		return NewDAOError(ErrInvalidProposal, "waiting for every participant to deal", nil)
+	}
+
+	if !accept {
+		tm.governanceState.Treasury.KeyGeneration = nil
+		return nil
+	}
+	keygen.Confirmations[index] = true
+	if len(keygen.Confirmations) == len(keygen.Participants) {
+		tm.governanceState.Treasury.ThresholdKey = keygen.Key
+		tm.governanceState.Treasury.KeyGeneration = nil
+	}
+	return nil
+}
+
+// signableTransaction returns a pending transaction that can still be
+// signed
+func (tm *TreasuryManager) signableTransaction(txHash types.Hash) (*PendingTx, error) {
+	pendingTx, exists := tm.governanceState.TreasuryTransaction(txHash)
+	if !exists {
+		return nil, NewDAOError(ErrProposalNotFound, "treasury transaction not found", nil)
+	}
+	if time.Now().Unix() > pendingTx.ExpiresAt {
+		return nil, NewDAOError(ErrProposalExpired, "treasury transaction has expired", nil)
+	}
+	if pendingTx.Executed {
+		return nil, NewDAOError(ErrInvalidProposal, "treasury transaction already executed", nil)
+	}
+	return pendingTx, nil
+}
+
+// thresholdParticipant returns the threshold key and the share index of a
+// signer
+func (tm *TreasuryManager) thresholdParticipant(signer crypto.PublicKey) (*TreasuryThresholdKey, int, error) {
+	key := tm.governanceState.Treasury.ThresholdKey
+	if key == nil {
+		return nil, 0, NewDAOError(ErrInvalidProposal, "the treasury has no threshold key", nil)
+	}
+	index := key.participant(signer)
+	if index == 0 {
+		return nil, 0, NewDAOError(ErrUnauthorized, "signer holds no share of the threshold key", nil)
+	}
+	return key, index, nil
+}
+
+// CommitThresholdNonce records the nonce commitment of a share holder to
+// sign a pending transaction. The first threshold holders to commit form
+// the signing set; if it has not signed ThresholdSigningPeriod after it
+// completed, the next commitment starts the signing over.
+func (tm *TreasuryManager) CommitThresholdNonce(txHash types.Hash, signer crypto.PublicKey, commitment []byte) error {
+	key, index, err := tm.thresholdParticipant(signer)
+	if err != nil {
+		return err
+	}
+	pendingTx, err := tm.signableTransaction(txHash)
+	if err != nil {
+		return err
+	}
+
+	// A stale set is dropped whole rather than patched: the partials already
+	// made are bound to its commitments, and a nonce must never sign under
+	// another set
+	now := time.Now().Unix()
+	if pendingTx.Threshold == nil || pendingTx.Threshold.stale(now) {
+		pendingTx.Threshold = &ThresholdSigning{
+			Commitments: make(map[int][]byte),
+			Partials:    make(map[int][]byte),
+		}
+	}
+	signing := pendingTx.Threshold
+	if _, committed := signing.Commitments[index]; committed {
+		return NewDAOError(ErrDuplicateVote, "share holder already committed", nil)
+	}
+	if len(signing.Commitments) >= int(key.Threshold) {
+		return NewDAOError(ErrInvalidProposal, "the signing set is complete", nil)
+	}
+
+	// Parsing the commitment alone checks its points
+	if _, err := crypto.NewThresholdSession(key.GroupKey, key.shareKeys(), nil, map[int][]byte{index: commitment}); err != nil {
+		return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+	}
+	signing.Commitments[index] = commitment
+	if len(signing.Commitments) == int(key.Threshold) {
+		signing.CompletedAt = now
+	}
+	return nil
+}
+
+// SubmitThresholdPartial records the partial signature of a share holder
+// of the signing set. The last one assembles the threshold signature and
+// executes the transaction.
+func (tm *TreasuryManager) SubmitThresholdPartial(txHash types.Hash, signer crypto.PublicKey, partial []byte) error {
+	key, index, err := tm.thresholdParticipant(signer)
+	if err != nil {
+		return err
+	}
+	pendingTx, err := tm.signableTransaction(txHash)
+	if err != nil {
+		return err
+	}
+
+	signing := pendingTx.Threshold
+	if signing == nil || len(signing.Commitments) < int(key.Threshold) {
+		return NewDAOError(ErrInvalidProposal, "waiting for the signing set to commit", nil)
+	}
+	if _, committed := signing.Commitments[index]; !committed {
+		return NewDAOError(ErrUnauthorized, "share holder is not part of the signing set", nil)
+	}
+	if _, signed := signing.Partials[index]; signed {
+		return NewDAOError(ErrDuplicateVote, "share holder already signed", nil)
+	}
+
+	session, err := crypto.NewThresholdSession(key.GroupKey, key.shareKeys(), tm.createTreasuryTxData(pendingTx), signing.Commitments)
+	if err == nil {
+		err = session.AddPartial(index, partial)
+	}
+	if err != nil {
+		return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+	}
+	signing.Partials[index] = partial
+	if len(signing.Partials) < len(signing.Commitments) {
+		return nil
+	}
+
+	for i, other := range signing.Partials {
+		if err := session.AddPartial(i, other); err != nil {
+			return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+		}
+	}
+	signature, err := session.Signature()
+	if err != nil {
+		return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+	}
+	signing.Signature = signature
+
+	if err := tm.ExecuteTreasuryTransaction(txHash); err != nil && !IsConversionRequeue(err) {
+		return err
+	}
+	return nil
+}
+
+// verifyThresholdSignature checks the threshold signature of a pending
+// transaction against the treasury threshold key
+func (tm *TreasuryManager) verifyThresholdSignature(pendingTx *PendingTx) error {
+	key := tm.governanceState.Treasury.ThresholdKey
+	if key == nil {
+		return NewDAOError(ErrInvalidSignature, "the treasury has no threshold key", nil)
+	}
+	if key.Threshold < tm.governanceState.Treasury.RequiredSigs {
+		return NewDAOError(ErrInvalidSignature, "threshold key is below the required signatures", nil)
+	}
+	if err := crypto.VerifyThreshold(key.GroupKey, tm.createTreasuryTxData(pendingTx), pendingTx.Threshold.Signature); err != nil {
+		return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+	}
+	return nil
+}
+
+// GetThresholdKey returns the treasury threshold key, nil when the signers
+// have not generated one
+func (tm *TreasuryManager) GetThresholdKey() *TreasuryThresholdKey {
+	return tm.governanceState.Treasury.ThresholdKey
+}
+
+// GetKeyGeneration returns the running key generation, nil when none runs
+func (tm *TreasuryManager) GetKeyGeneration() *TreasuryKeyGeneration {
+	keygen := tm.governanceState.Treasury.KeyGeneration
+	if keygen == nil || time.Now().Unix() > keygen.ExpiresAt {
+		return nil
+	}
+	return keygen
+}
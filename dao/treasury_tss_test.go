@@ -0,0 +1,304 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+)
+
+func TestTreasuryManager_ThresholdKey(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	signers := []crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey(), keys[2].PublicKey()}
+	if err := dao.InitializeTreasury(signers, 2); err != nil {
+		t.Fatalf("Failed to initialize treasury: %v", err)
+	}
+	dao.AddTreasuryFunds(10000)
+
+	if _, err := dao.StartKeyGeneration(crypto.GeneratePrivateKey().PublicKey(), 0); err == nil {
+		t.Error("Expected an outsider to be unable to start a key generation")
+	}
+	if _, err := dao.StartKeyGeneration(signers[0], 1); err == nil {
+		t.Error("Expected a threshold below the required signatures to be rejected")
+	}
+	keygen, err := dao.StartKeyGeneration(signers[0], 0)
+	if err != nil {
+		t.Fatalf("Failed to start key generation: %v", err)
+	}
+	if keygen.Threshold != 2 {
+		t.Errorf("Expected the threshold to default to 2, got %d", keygen.Threshold)
+	}
+
+	// Every signer deals off chain, the node only relays the dealings
+	participants := make(map[int]crypto.PublicKey)
+	for i, participant := range keygen.Participants {
+		participants[i+1] = participant
+	}
+	dealers := make([]*crypto.DKGParticipant, len(keys))
+	dealings := make([]*crypto.DKGDealing, len(keys))
+	for i := range keys {
+		if dealers[i], err = crypto.NewDKGParticipant(crypto.CurveP256, i+1, 2); err != nil {
+			t.Fatalf("Failed to start dealer: %v", err)
+		}
+		if dealings[i], err = dealers[i].Deal(participants); err != nil {
+			t.Fatalf("Failed to deal: %v", err)
+		}
+	}
+	if err := dao.SubmitKeyDealing(signers[1], dealings[0]); err == nil {
+		t.Error("Expected a dealing from another participant to be rejected")
+	}
+	unproven := *dealings[0]
+	unproven.Proof = nil
+	if err := dao.SubmitKeyDealing(signers[0], &unproven); err == nil {
+		t.Error("Expected a dealing without a proof of knowledge to be rejected")
+	}
+	for i := range keys {
+		if err := dao.SubmitKeyDealing(signers[i], dealings[i]); err != nil {
+			t.Fatalf("Failed to submit dealing: %v", err)
+		}
+	}
+
+	shares := make([]*crypto.ThresholdKey, len(keys))
+	for i, key := range keys {
+		if shares[i], err = dealers[i].Finish(key, dealings); err != nil {
+			t.Fatalf("Failed to open shares: %v", err)
+		}
+		if err := dao.ConfirmKeyGeneration(signers[i], true); err != nil {
+			t.Fatalf("Failed to confirm key generation: %v", err)
+		}
+	}
+	thresholdKey := dao.GetThresholdKey()
+	if thresholdKey == nil || dao.GetKeyGeneration() != nil {
+		t.Fatal("Expected the confirmed key to replace the ceremony")
+	}
+	if thresholdKey.GroupKey.String() != shares[0].GroupKey.String() {
+		t.Error("Expected the treasury key to match the key of the shares")
+	}
+
+	txHash := randomTreasuryHash()
+	err = dao.CreateTreasuryTransaction(&TreasuryTx{
+		Fee:          100,
+		Recipient:    crypto.GeneratePrivateKey().PublicKey(),
+		Amount:       4000,
+		Purpose:      "Audit",
+		RequiredSigs: 2,
+	}, txHash)
+	if err != nil {
+		t.Fatalf("Failed to create treasury transaction: %v", err)
+	}
+	pendingTx, _ := dao.GetTreasuryTransaction(txHash)
+	digest := dao.TreasuryManager.createTreasuryTxData(pendingTx)
+
+	// The first and last share holders sign
+	nonces := make(map[int]*crypto.ThresholdNonce)
+	commitments := make(map[int][]byte)
+	for _, share := range []*crypto.ThresholdKey{shares[0], shares[2]} {
+		nonce, commitment, err := share.Commit()
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		nonces[share.Index], commitments[share.Index] = nonce, commitment
+		if err := dao.CommitThresholdNonce(txHash, signers[share.Index-1], commitment); err != nil {
+			t.Fatalf("Failed to record commitment: %v", err)
+		}
+	}
+	if _, commitment, _ := shares[1].Commit(); dao.CommitThresholdNonce(txHash, signers[1], commitment) == nil {
+		t.Error("Expected commitments past the threshold to be rejected")
+	}
+
+	for _, share := range []*crypto.ThresholdKey{shares[0], shares[2]} {
+		partial, err := share.Sign(nonces[share.Index], digest, commitments)
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		tampered := append([]byte(nil), partial...)
+		tampered[0] ^= 0x01
+		if err := dao.SubmitThresholdPartial(txHash, signers[share.Index-1], tampered); err == nil {
+			t.Error("Expected a tampered partial signature to be rejected")
+		}
+		if pendingTx.Executed {
+			t.Error("Transaction should wait for every committed share holder")
+		}
+		if err := dao.SubmitThresholdPartial(txHash, signers[share.Index-1], partial); err != nil {
+			t.Fatalf("Failed to submit partial signature: %v", err)
+		}
+	}
+
+	if !pendingTx.Executed || pendingTx.SignatureCount() != 2 {
+		t.Error("Transaction should be executed by the threshold signature")
+	}
+	if dao.GetTreasuryBalance() != 6000 {
+		t.Errorf("Expected treasury balance 6000, got %d", dao.GetTreasuryBalance())
+	}
+	if !dao.TreasuryManager.hasSignerSigned(pendingTx, signers[2]) || dao.TreasuryManager.hasSignerSigned(pendingTx, signers[1]) {
+		t.Error("Expected the share holders of the signing set alone to have signed")
+	}
+}
+
+// newThresholdTreasury returns a DAO whose treasury holds a threshold key
+// shared among n signers, and the shares of the signers
+func newThresholdTreasury(t *testing.T, n int, threshold uint8) (*DAO, []crypto.PublicKey, []*crypto.ThresholdKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	keys := make([]crypto.PrivateKey, n)
+	signers := make([]crypto.PublicKey, n)
+	participants := make(map[int]crypto.PublicKey, n)
+	for i := range keys {
+		keys[i] = crypto.GeneratePrivateKey()
+		signers[i] = keys[i].PublicKey()
+		participants[i+1] = signers[i]
+	}
+	if err := dao.InitializeTreasury(signers, threshold); err != nil {
+		t.Fatalf("Failed to initialize treasury: %v", err)
+	}
+	dao.AddTreasuryFunds(10000)
+	if _, err := dao.StartKeyGeneration(signers[0], threshold); err != nil {
+		t.Fatalf("Failed to start key generation: %v", err)
+	}
+
+	dealers := make([]*crypto.DKGParticipant, n)
+	dealings := make([]*crypto.DKGDealing, n)
+	for i := range keys {
+		var err error
+		if dealers[i], err = crypto.NewDKGParticipant(crypto.CurveP256, i+1, int(threshold)); err != nil {
+			t.Fatalf("Failed to start dealer: %v", err)
+		}
+		if dealings[i], err = dealers[i].Deal(participants); err != nil {
+			t.Fatalf("Failed to deal: %v", err)
+		}
+		if err := dao.SubmitKeyDealing(signers[i], dealings[i]); err != nil {
+			t.Fatalf("Failed to submit dealing: %v", err)
+		}
+	}
+
+	shares := make([]*crypto.ThresholdKey, n)
+	for i, key := range keys {
+		var err error
+		if shares[i], err = dealers[i].Finish(key, dealings); err != nil {
+			t.Fatalf("Failed to open shares: %v", err)
+		}
+		if err := dao.ConfirmKeyGeneration(signers[i], true); err != nil {
+			t.Fatalf("Failed to confirm key generation: %v", err)
+		}
+	}
+	return dao, signers, shares
+}
+
+func TestTreasuryManager_StaleThresholdSigning(t *testing.T) {
+	dao, signers, shares := newThresholdTreasury(t, 3, 2)
+
+	txHash := randomTreasuryHash()
+	err := dao.CreateTreasuryTransaction(&TreasuryTx{
+		Fee:          100,
+		Recipient:    crypto.GeneratePrivateKey().PublicKey(),
+		Amount:       4000,
+		Purpose:      "Audit",
+		RequiredSigs: 2,
+	}, txHash)
+	if err != nil {
+		t.Fatalf("Failed to create treasury transaction: %v", err)
+	}
+	pendingTx, _ := dao.GetTreasuryTransaction(txHash)
+	digest := dao.TreasuryManager.createTreasuryTxData(pendingTx)
+
+	// The first two holders commit, but only the first signs
+	nonce, commitment, _ := shares[0].Commit()
+	if err := dao.CommitThresholdNonce(txHash, signers[0], commitment); err != nil {
+		t.Fatalf("Failed to record commitment: %v", err)
+	}
+	stale := map[int][]byte{1: commitment}
+	_, commitment, _ = shares[1].Commit()
+	if err := dao.CommitThresholdNonce(txHash, signers[1], commitment); err != nil {
+		t.Fatalf("Failed to record commitment: %v", err)
+	}
+	stale[2] = commitment
+	partial, err := shares[0].Sign(nonce, digest, stale)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if err := dao.SubmitThresholdPartial(txHash, signers[0], partial); err != nil {
+		t.Fatalf("Failed to submit partial signature: %v", err)
+	}
+
+	_, commitment, _ = shares[2].Commit()
+	if err := dao.CommitThresholdNonce(txHash, signers[2], commitment); err == nil {
+		t.Error("Expected the signing set to hold until it goes stale")
+	}
+
+	// Once stale, the next commitment starts the signing over
+	pendingTx.Threshold.CompletedAt -= ThresholdSigningPeriod + 1
+	nonces := make(map[int]*crypto.ThresholdNonce)
+	commitments := make(map[int][]byte)
+	for _, share := range []*crypto.ThresholdKey{shares[2], shares[0]} {
+		nonce, commitment, err := share.Commit()
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		nonces[share.Index], commitments[share.Index] = nonce, commitment
+		if err := dao.CommitThresholdNonce(txHash, signers[share.Index-1], commitment); err != nil {
+			t.Fatalf("Failed to record commitment: %v", err)
+		}
+	}
+	if len(pendingTx.Threshold.Partials) != 0 {
+		t.Error("Expected the partials of the stale set to be dropped")
+	}
+	if err := dao.SubmitThresholdPartial(txHash, signers[1], partial); err == nil {
+		t.Error("Expected a holder outside the new signing set to be rejected")
+	}
+
+	for _, share := range []*crypto.ThresholdKey{shares[0], shares[2]} {
+		partial, err := share.Sign(nonces[share.Index], digest, commitments)
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if err := dao.SubmitThresholdPartial(txHash, signers[share.Index-1], partial); err != nil {
+			t.Fatalf("Failed to submit partial signature: %v", err)
+		}
+	}
+	if !pendingTx.Executed {
+		t.Error("Transaction should be executed by the new signing set")
+	}
+}
+
+func TestTreasuryManager_KeyGenerationRejected(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	keys := []crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+	signers := []crypto.PublicKey{keys[0].PublicKey(), keys[1].PublicKey()}
+	if err := dao.InitializeTreasury(signers, 2); err != nil {
+		t.Fatalf("Failed to initialize treasury: %v", err)
+	}
+	if _, err := dao.StartKeyGeneration(signers[0], 0); err != nil {
+		t.Fatalf("Failed to start key generation: %v", err)
+	}
+	if _, err := dao.StartKeyGeneration(signers[1], 0); err == nil {
+		t.Error("Expected a single key generation at a time")
+	}
+	if err := dao.ConfirmKeyGeneration(signers[0], true); err == nil {
+		t.Error("Expected confirmations to wait for the dealings")
+	}
+
+	participants := map[int]crypto.PublicKey{1: signers[0], 2: signers[1]}
+	for i, signer := range signers {
+		dealer, err := crypto.NewDKGParticipant(crypto.CurveP256, i+1, 2)
+		if err != nil {
+			t.Fatalf("Failed to start dealer: %v", err)
+		}
+		dealing, err := dealer.Deal(participants)
+		if err != nil {
+			t.Fatalf("Failed to deal: %v", err)
+		}
+		if err := dao.SubmitKeyDealing(signer, dealing); err != nil {
+			t.Fatalf("Failed to submit dealing: %v", err)
+		}
+	}
+
+	// A participant whose shares do not check out aborts the ceremony
+	if err := dao.ConfirmKeyGeneration(signers[1], false); err != nil {
+		t.Fatalf("Failed to reject key generation: %v", err)
+	}
+	if dao.GetKeyGeneration() != nil || dao.GetThresholdKey() != nil {
+		t.Error("Expected the rejected ceremony to leave no key")
+	}
+}
@@ -9,28 +9,91 @@ import (
 type DAOTxType byte
 
 const (
-	TxTypeProposal          DAOTxType = 0x10
-	TxTypeVote              DAOTxType = 0x11
-	TxTypeDelegation        DAOTxType = 0x12
-	TxTypeTreasury          DAOTxType = 0x13
-	TxTypeTokenMint         DAOTxType = 0x14
-	TxTypeTokenBurn         DAOTxType = 0x15
-	TxTypeTokenDistribution DAOTxType = 0x16
-	TxTypeVestingClaim      DAOTxType = 0x17
-	TxTypeStake             DAOTxType = 0x18
-	TxTypeParameter         DAOTxType = 0x19
-	TxTypeUnstake           DAOTxType = 0x1A
-	TxTypeClaimRewards      DAOTxType = 0x1B
+	TxTypeProposal             DAOTxType = 0x10
+	TxTypeVote                 DAOTxType = 0x11
+	TxTypeDelegation           DAOTxType = 0x12
+	TxTypeTreasury             DAOTxType = 0x13
+	TxTypeTokenMint            DAOTxType = 0x14
+	TxTypeTokenBurn            DAOTxType = 0x15
+	TxTypeTokenDistribution    DAOTxType = 0x16
+	TxTypeVestingClaim         DAOTxType = 0x17
+	TxTypeStake                DAOTxType = 0x18
+	TxTypeParameter            DAOTxType = 0x19
+	TxTypeUnstake              DAOTxType = 0x1A
+	TxTypeClaimRewards         DAOTxType = 0x1B
+	TxTypePositionTransfer     DAOTxType = 0x1C
+	TxTypeDispute              DAOTxType = 0x1D
+	TxTypeDisputeEvidence      DAOTxType = 0x1E
+	TxTypeJurorCommit          DAOTxType = 0x1F
+	TxTypeJurorReveal          DAOTxType = 0x20
+	TxTypeFundingKPI           DAOTxType = 0x21
+	TxTypeImpactReview         DAOTxType = 0x22
+	TxTypeValidatorConfig      DAOTxType = 0x23
+	TxTypeClaimCommission      DAOTxType = 0x24
+	TxTypeValidatorSetProposal DAOTxType = 0x25
+	TxTypeValidatorSetExecute  DAOTxType = 0x26
+	TxTypeMultisigCreate       DAOTxType = 0x27
+	TxTypeMultisigSubmit       DAOTxType = 0x28
+	TxTypeMultisigSign         DAOTxType = 0x29
+	TxTypeSubDAOCreateProposal DAOTxType = 0x2A
+	TxTypeSubDAOCreateExecute  DAOTxType = 0x2B
+	TxTypeSubDAOProposal       DAOTxType = 0x2C
+	TxTypeSubDAOVote           DAOTxType = 0x2D
+	TxTypeGrantProposal        DAOTxType = 0x2E
+	TxTypeGrantExecute         DAOTxType = 0x2F
+	TxTypeGrantMilestoneSubmit DAOTxType = 0x30
+	TxTypeGrantMilestoneReview DAOTxType = 0x31
+	TxTypeGrantCancel          DAOTxType = 0x32
+	TxTypeBountyProposal       DAOTxType = 0x33
+	TxTypeBountyPost           DAOTxType = 0x34
+	TxTypeBountyClaim          DAOTxType = 0x35
+	TxTypeBountySubmit         DAOTxType = 0x36
+	TxTypeBountyReview         DAOTxType = 0x37
+	TxTypeBountyCancel         DAOTxType = 0x38
+	TxTypeQFRoundProposal      DAOTxType = 0x39
+	TxTypeQFRoundOpen          DAOTxType = 0x3A
+	TxTypeQFContribute         DAOTxType = 0x3B
+	TxTypeQFPayout             DAOTxType = 0x3C
+	TxTypeRPGFRoundProposal    DAOTxType = 0x3D
+	TxTypeRPGFRoundOpen        DAOTxType = 0x3E
+	TxTypeRPGFNominate         DAOTxType = 0x3F
+	TxTypeRPGFBallot           DAOTxType = 0x40
+	TxTypeRPGFFinalize         DAOTxType = 0x41
+	TxTypeRPGFClaim            DAOTxType = 0x42
+	TxTypeOptimisticProposal   DAOTxType = 0x43
+	TxTypeOptimisticChallenge  DAOTxType = 0x44
+	TxTypeOracleFeedProposal   DAOTxType = 0x45
+	TxTypeOracleFeedActivate   DAOTxType = 0x46
+	TxTypeOracleReport         DAOTxType = 0x47
+	TxTypeConstitutionAmend    DAOTxType = 0x48
+	TxTypeConstitutionEnact    DAOTxType = 0x49
+	TxTypeEmergencySpend       DAOTxType = 0x4A
+	TxTypeEmergencyExecute     DAOTxType = 0x4B
+	TxTypeParticipationOptIn   DAOTxType = 0x4C
+	TxTypeEndorseProposal      DAOTxType = 0x4D
+	TxTypePrivacySettings      DAOTxType = 0x4E
+	TxTypeNameRegister         DAOTxType = 0x4F
+	TxTypeNameRenew            DAOTxType = 0x50
+	TxTypeNameTransfer         DAOTxType = 0x51
+	TxTypeProfileUpdate        DAOTxType = 0x52
+	TxTypeDelegateStatement    DAOTxType = 0x53
+	TxTypeTrackProposal        DAOTxType = 0x54
+	TxTypeTrackActivate        DAOTxType = 0x55
+	TxTypeDAOCreate            DAOTxType = 0x56
+	TxTypeHostedDAO            DAOTxType = 0x57
+	TxTypeParameterSchedule    DAOTxType = 0x58
 )
 
 // ProposalType represents different categories of proposals
 type ProposalType byte
 
 const (
-	ProposalTypeGeneral   ProposalType = 0x01 // General governance
-	ProposalTypeTreasury  ProposalType = 0x02 // Treasury spending
-	ProposalTypeTechnical ProposalType = 0x03 // Protocol changes
-	ProposalTypeParameter ProposalType = 0x04 // Parameter updates
+	ProposalTypeGeneral      ProposalType = 0x01 // General governance
+	ProposalTypeTreasury     ProposalType = 0x02 // Treasury spending
+	ProposalTypeTechnical    ProposalType = 0x03 // Protocol changes
+	ProposalTypeParameter    ProposalType = 0x04 // Parameter updates
+	ProposalTypeConstitution ProposalType = 0x05 // Governance document amendments, passed by supermajority
+	ProposalTypeEmergency    ProposalType = 0x06 // Guardian co-signed treasury spends on a short vote
 )
 
 // ProposalStatus represents the current state of a proposal
@@ -74,7 +137,11 @@ type ProposalTx struct {
 	StartTime    int64
 	EndTime      int64
 	Threshold    uint64
-	MetadataHash types.Hash // IPFS hash for large content
+	MetadataHash types.Hash        // IPFS hash for large content
+	VoteSponsor  uint64            // Treasury budget covering voters' fees, 0 for none
+	Conditions   []OracleCondition // Oracle data required to execute, if any
+	DependsOn    []types.Hash      // Proposals that must execute first
+	Track        string            // Governance-defined track setting the voting rules, empty for none
 }
 
 // VoteTx represents a voting transaction
@@ -92,15 +159,20 @@ type DelegationTx struct {
 	Delegate crypto.PublicKey
 	Duration int64
 	Revoke   bool // If true, revokes existing delegation
+	// AutoRenew renews the delegation for another Duration whenever it
+	// expires, until it is revoked
+	AutoRenew bool
 }
 
 // TreasuryTx represents a treasury operation transaction
 type TreasuryTx struct {
 	Fee          int64
 	Recipient    crypto.PublicKey
-	Amount       uint64
+	Amount       uint64 // In the stable unit when Denomination is set
+	Denomination string // Oracle feed pricing the token in a stable unit, empty for native amounts
 	Purpose      string
 	Signatures   []crypto.Signature
+	Aggregate    *crypto.AggregateSignature // One signature of several signers, instead of Signatures
 	RequiredSigs uint8
 }
 
@@ -178,6 +250,597 @@ type ClaimRewardsTx struct {
 	PoolID string
 }
 
+// PositionTransferTx transfers ownership of a vesting or stake position
+type PositionTransferTx struct {
+	Fee        int64
+	PositionID string
+	Recipient  crypto.PublicKey
+}
+
+// DisputeTx escalates a conflict to a juror panel
+type DisputeTx struct {
+	Fee          int64
+	DisputeType  DisputeType
+	Respondent   crypto.PublicKey // Optional for grant disputes and appeals
+	Subject      types.Hash       // Proposal or treasury transaction in dispute
+	Amount       uint64           // Tokens at stake for grant and clawback disputes
+	Description  string
+	EvidenceHash types.Hash // IPFS hash of the opening statement
+}
+
+// DisputeEvidenceTx submits evidence for an open dispute
+type DisputeEvidenceTx struct {
+	Fee          int64
+	DisputeID    types.Hash
+	EvidenceHash types.Hash // IPFS hash of the evidence document
+	Description  string
+}
+
+// JurorCommitTx commits to a hidden ruling on a dispute
+type JurorCommitTx struct {
+	Fee        int64
+	DisputeID  types.Hash
+	Commitment types.Hash // DisputeCommitment of the ruling and salt
+}
+
+// JurorRevealTx reveals a previously committed ruling
+type JurorRevealTx struct {
+	Fee       int64
+	DisputeID types.Hash
+	Ruling    DisputeRuling
+	Salt      types.Hash
+}
+
+// FundingKPITx attaches measurable KPIs to a treasury proposal
+type FundingKPITx struct {
+	Fee        int64
+	ProposalID types.Hash
+	Category   string
+	Grantee    crypto.PublicKey
+	Amount     uint64
+	KPIs       []KPITarget
+}
+
+// ImpactReviewTx records the measured KPI actuals of an executed funding proposal
+type ImpactReviewTx struct {
+	Fee        int64
+	ProposalID types.Hash
+	Actuals    map[string]uint64 // KPI name -> measured value
+	ReportHash types.Hash        // IPFS hash of the review report
+}
+
+// ValidatorConfigTx registers a validator or updates its commission
+type ValidatorConfigTx struct {
+	Fee           int64
+	PoolID        string // Staking pool delegators stake into
+	CommissionBps uint64
+}
+
+// ClaimCommissionTx pays out a validator's accrued commission
+type ClaimCommissionTx struct {
+	Fee int64
+}
+
+// ValidatorSetProposalTx proposes adding a registered validator to the set
+// that finalizes blocks, or removing one from it
+type ValidatorSetProposalTx struct {
+	Fee         int64
+	Validator   crypto.PublicKey
+	Remove      bool
+	Description string
+	VotingType  VotingType
+	StartTime   int64
+	EndTime     int64
+	Threshold   uint64
+}
+
+// ValidatorSetExecuteTx applies the validator set change of a passed proposal
+type ValidatorSetExecuteTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// MultisigCreateTx creates a multisig account acting for its owners
+type MultisigCreateTx struct {
+	Fee       int64
+	Name      string
+	Owners    []crypto.PublicKey
+	Threshold uint8 // Owner approvals needed to execute a transaction
+}
+
+// MultisigSubmitTx proposes a DAO transaction on behalf of a multisig
+// account, the submitter's approval is counted
+type MultisigSubmitTx struct {
+	Fee         int64
+	MultisigID  types.Hash
+	Description string
+	Tx          interface{} // DAO transaction sent from the multisig account
+}
+
+// MultisigSignTx approves a pending multisig transaction, which executes
+// once the threshold is met
+type MultisigSignTx struct {
+	Fee        int64
+	MultisigID types.Hash
+	TxID       types.Hash
+}
+
+// SubDAOCreateProposalTx proposes a sub-DAO to the main DAO, which creates
+// it once the proposal passes
+type SubDAOCreateProposalTx struct {
+	Fee            int64
+	Name           string
+	Description    string
+	Members        []crypto.PublicKey
+	Budget         uint64 // Streamed from the main treasury
+	StreamDuration int64  // Seconds over which the budget is streamed
+	VotingType     VotingType
+	StartTime      int64
+	EndTime        int64
+	Threshold      uint64
+}
+
+// SubDAOCreateExecuteTx creates the sub-DAO of a passed proposal
+type SubDAOCreateExecuteTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// SubDAOProposalTx opens a proposal decided by the members of a sub-DAO
+type SubDAOProposalTx struct {
+	Fee         int64
+	SubDAOID    types.Hash
+	Title       string
+	Description string
+	Action      SubDAOAction
+	Target      crypto.PublicKey // Recipient or member the action applies to
+	Amount      uint64
+	Duration    int64 // Voting window in seconds
+}
+
+// SubDAOVoteTx votes on a sub-DAO proposal
+type SubDAOVoteTx struct {
+	Fee        int64
+	SubDAOID   types.Hash
+	ProposalID types.Hash
+	Support    bool
+}
+
+// GrantProposalTx applies for a grant paid out milestone by milestone once
+// the main DAO approves it
+type GrantProposalTx struct {
+	Fee         int64
+	Title       string
+	Description string
+	Milestones  []GrantMilestoneSpec
+	Committee   crypto.PublicKey // Reviews and approves the milestone payouts
+	VotingType  VotingType
+	StartTime   int64
+	EndTime     int64
+	Threshold   uint64
+}
+
+// GrantExecuteTx funds the grant of a passed proposal from the treasury
+type GrantExecuteTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// GrantMilestoneSubmitTx submits a delivered milestone for review
+type GrantMilestoneSubmitTx struct {
+	Fee         int64
+	GrantID     types.Hash
+	Milestone   uint8
+	Deliverable string
+}
+
+// GrantMilestoneReviewTx approves and pays a milestone, or requests changes
+type GrantMilestoneReviewTx struct {
+	Fee       int64
+	GrantID   types.Hash
+	Milestone uint8
+	Approve   bool
+	Comment   string
+}
+
+// GrantCancelTx cancels a grant and returns its unpaid funds to the treasury
+type GrantCancelTx struct {
+	Fee     int64
+	GrantID types.Hash
+	Reason  string
+}
+
+// BountyProposalTx proposes a bounty, posted on the board once the main
+// DAO approves it
+type BountyProposalTx struct {
+	Fee         int64
+	Title       string
+	Description string
+	Reward      uint64
+	Reviewer    crypto.PublicKey
+	ClaimPeriod int64 // Seconds a claimant has to get the work approved
+	VotingType  VotingType
+	StartTime   int64
+	EndTime     int64
+	Threshold   uint64
+}
+
+// BountyPostTx posts the bounty of a passed proposal, escrowing its reward
+type BountyPostTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// BountyClaimTx claims an open bounty
+type BountyClaimTx struct {
+	Fee      int64
+	BountyID types.Hash
+}
+
+// BountySubmitTx submits the work done for a claimed bounty
+type BountySubmitTx struct {
+	Fee      int64
+	BountyID types.Hash
+	WorkHash types.Hash // IPFS hash of the work
+}
+
+// BountyReviewTx approves submitted work, paying the reward, or disputes it
+type BountyReviewTx struct {
+	Fee      int64
+	BountyID types.Hash
+	Approve  bool
+	Comment  string
+}
+
+// BountyCancelTx withdraws a bounty and returns its reward to the treasury
+type BountyCancelTx struct {
+	Fee      int64
+	BountyID types.Hash
+}
+
+// QFRoundProposalTx proposes a quadratic funding round, opened for
+// contributions once the main DAO approves it
+type QFRoundProposalTx struct {
+	Fee                int64
+	Title              string
+	Description        string
+	Projects           []QFProjectSpec
+	MatchingPool       uint64     // Escrowed from the treasury when the round opens
+	Dampening          uint64     // Pairwise coordination bound, 0 for the matching pool
+	ContributionPeriod int64      // Seconds the round takes contributions
+	Multisig           types.Hash // Multisig account that executes the payout
+	VotingType         VotingType
+	StartTime          int64
+	EndTime            int64
+	Threshold          uint64
+}
+
+// QFRoundOpenTx opens the round of a passed proposal, escrowing its
+// matching pool
+type QFRoundOpenTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// QFContributeTx contributes to a project of an open round
+type QFContributeTx struct {
+	Fee     int64
+	RoundID types.Hash
+	Project uint8
+	Amount  uint64
+}
+
+// QFPayoutTx pays the matching of a closed round, sent by its multisig
+type QFPayoutTx struct {
+	Fee     int64
+	RoundID types.Hash
+}
+
+// OptimisticProposalTx proposes a measure that executes once its challenge
+// window ends unless it is challenged
+type OptimisticProposalTx struct {
+	Fee          int64
+	Title        string
+	Description  string
+	ProposalType ProposalType
+	VotingType   VotingType
+	Threshold    uint64
+	MetadataHash types.Hash
+	VotingPeriod int64 // Seconds the vote lasts if the proposal is challenged
+}
+
+// OptimisticChallengeTx challenges an optimistic proposal, matching its bond
+// and putting it to a full vote
+type OptimisticChallengeTx struct {
+	Fee        int64
+	ProposalID types.Hash
+	Reason     string
+}
+
+// RPGFRoundProposalTx proposes a retroactive public goods funding round,
+// opened for nominations once the main DAO approves it
+type RPGFRoundProposalTx struct {
+	Fee              int64
+	Title            string
+	Description      string
+	Budget           uint64 // Escrowed from the treasury when the round opens
+	BadgeHolders     []crypto.PublicKey
+	NominationPeriod int64 // Seconds the round takes nominations
+	VotingPeriod     int64 // Seconds badge holders then have to vote
+	StreamDuration   int64 // Seconds the allocations stream over
+	VotingType       VotingType
+	StartTime        int64
+	EndTime          int64
+	Threshold        uint64
+}
+
+// RPGFRoundOpenTx opens the round of a passed proposal, escrowing its budget
+type RPGFRoundOpenTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// RPGFNominateTx nominates a past contribution for retroactive funding
+type RPGFNominateTx struct {
+	Fee         int64
+	RoundID     types.Hash
+	Name        string
+	Description string
+	Recipient   crypto.PublicKey
+}
+
+// RPGFBallotTx is a badge holder's split of the round budget
+type RPGFBallotTx struct {
+	Fee         int64
+	RoundID     types.Hash
+	Allocations []RPGFAllocation
+}
+
+// RPGFFinalizeTx tallies a round whose voting ended and starts streaming
+type RPGFFinalizeTx struct {
+	Fee     int64
+	RoundID types.Hash
+}
+
+// RPGFClaimTx claims the streamed part of a nomination's allocation
+type RPGFClaimTx struct {
+	Fee        int64
+	RoundID    types.Hash
+	Nomination uint16
+}
+
+// OracleFeedProposalTx proposes an oracle feed and the oracle keys allowed
+// to report it, or new oracles for an existing feed
+type OracleFeedProposalTx struct {
+	Fee         int64
+	Title       string
+	Description string
+	FeedID      string
+	Decimals    uint8
+	Oracles     []crypto.PublicKey
+	MinReports  uint16 // Fresh reports the feed needs to have a value
+	MaxAge      int64  // Seconds after which a report is stale
+	VotingType  VotingType
+	StartTime   int64
+	EndTime     int64
+	Threshold   uint64
+}
+
+// OracleFeedActivateTx activates the feed of a passed proposal
+type OracleFeedActivateTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// OracleReportTx pushes a data point signed by one of a feed's oracles
+type OracleReportTx struct {
+	Fee        int64
+	FeedID     string
+	Value      int64
+	ObservedAt int64
+	Oracle     crypto.PublicKey
+	Signature  crypto.Signature // Oracle's signature over the report digest
+}
+
+// TrackProposalTx proposes a proposal track, replacing the track with the
+// same ID, or its removal
+type TrackProposalTx struct {
+	Fee         int64
+	Title       string
+	Description string
+	Track       ProposalTrack
+	Remove      bool
+	VotingType  VotingType
+	StartTime   int64
+	EndTime     int64
+	Threshold   uint64
+}
+
+// TrackActivateTx applies the track of a passed proposal
+type TrackActivateTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// ConstitutionAmendmentTx proposes a new version of a governance document,
+// adopting the document when it has no versions yet. The document text and
+// the diff metadata are stored on IPFS.
+type ConstitutionAmendmentTx struct {
+	Fee           int64
+	Title         string
+	Description   string
+	DocumentID    string
+	DocumentTitle string
+	BaseVersion   uint32     // Version being amended, 0 to adopt a new document
+	ContentHash   types.Hash // IPFS hash of the amended text
+	DiffHash      types.Hash // IPFS hash of the DocumentDiff
+	Summary       string
+	VotingType    VotingType
+	StartTime     int64
+	EndTime       int64
+	Threshold     uint64 // At least the constitution amendment threshold
+}
+
+// ConstitutionEnactTx enacts the amendment of a passed proposal
+type ConstitutionEnactTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// EmergencySpendTx proposes a treasury payment in response to an incident.
+// It is voted on from submission over the emergency voting period and must
+// be co-signed by a guardian.
+type EmergencySpendTx struct {
+	Fee               int64
+	Title             string
+	Description       string
+	Recipient         crypto.PublicKey
+	Amount            uint64
+	Incident          string // Short reference of the incident the spend responds to
+	VotingType        VotingType
+	Guardian          crypto.PublicKey
+	GuardianSignature crypto.Signature // Guardian's signature over the spend digest
+}
+
+// EmergencyExecuteTx pays the spend of a passed emergency proposal
+type EmergencyExecuteTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// ParticipationOptInTx opts the sender in or out of participation rewards
+type ParticipationOptInTx struct {
+	Fee   int64
+	OptIn bool
+}
+
+// EndorseProposalTx seconds a proposal awaiting endorsements with the
+// sender's voting power
+type EndorseProposalTx struct {
+	Fee        int64
+	ProposalID types.Hash
+}
+
+// PrivacySettingsTx sets who may see the sender's balance, reputation and
+// votes through the API
+type PrivacySettingsTx struct {
+	Fee        int64
+	Balance    Visibility
+	Reputation Visibility
+	Votes      Visibility
+}
+
+// NameRegisterTx registers a handle for the sender's address
+type NameRegisterTx struct {
+	Fee  int64
+	Name string
+}
+
+// NameRenewTx extends the sender's handle by a registration period
+type NameRenewTx struct {
+	Fee  int64
+	Name string
+}
+
+// NameTransferTx hands the sender's handle to another address
+type NameTransferTx struct {
+	Fee       int64
+	Name      string
+	Recipient crypto.PublicKey
+}
+
+// ProfileUpdateTx publishes the sender's profile, stored on IPFS. A zero
+// hash removes it.
+type ProfileUpdateTx struct {
+	Fee         int64
+	ProfileHash types.Hash // IPFS hash of the signed MemberProfile
+}
+
+// DelegateStatementTx publishes the sender's delegate statement, replacing
+// any earlier one, or withdraws it
+type DelegateStatementTx struct {
+	Fee         int64
+	Platform    string
+	FocusAreas  []string
+	DelegateFee uint64 // Basis points of delegators' rewards the delegate asks for
+	Withdraw    bool
+}
+
+// CommentTx posts a comment on a proposal, its body is stored on IPFS
+type CommentTx struct {
+	Fee        int64
+	ProposalID types.Hash
+	ParentID   types.Hash // Comment replied to, zero for top level comments
+	BodyHash   types.Hash // IPFS hash of the body
+}
+
+// JoinRequestTx applies for membership of the DAO
+type JoinRequestTx struct {
+	Fee            int64
+	Statement      string     // Why the applicant wants to join
+	KYCAttestation types.Hash // Hash of an identity attestation, zero for none
+}
+
+// JoinApprovalTx is an active member's approval of a pending application
+type JoinApprovalTx struct {
+	Fee       int64
+	Applicant crypto.PublicKey
+}
+
+// MembershipStatusTx changes the membership status of a member. Members
+// exit themselves; suspension and reinstatement are voted by active members.
+type MembershipStatusTx struct {
+	Fee    int64
+	Member crypto.PublicKey
+	Status MembershipStatus
+	Reason string
+}
+
+// RageQuitTx burns governance tokens for their pro-rata share of the
+// unrestricted treasury
+type RageQuitTx struct {
+	Fee    int64
+	Amount uint64 // Tokens to burn
+}
+
+// DAOCreateTx creates a DAO hosted alongside the default one, with its own
+// token, treasury and governance state set up from a genesis document
+type DAOCreateTx struct {
+	Fee         int64 // Paid in the default DAO's token
+	DAOID       string
+	Name        string
+	Description string
+	Genesis     Genesis
+}
+
+// HostedDAOTx carries a DAO transaction for a DAO hosted by the registry
+type HostedDAOTx struct {
+	DAOID string
+	Tx    interface{} // DAO transaction applied to the hosted DAO
+}
+
+// ScheduledParameterChange is a parameter change that takes effect after
+// its proposal passes, at a block height, gradually, or both
+type ScheduledParameterChange struct {
+	Parameter        string
+	Value            string // Target value, parsed as the parameter's type
+	ActivationHeight uint32 // Block height the change starts at, 0 for as soon as the proposal passes
+	RampPeriod       int64  // Seconds the value moves linearly to the target over, 0 to apply at once
+}
+
+// ParameterScheduleTx proposes staged parameter changes. The maintenance
+// scheduler rolls them out once the proposal passes.
+type ParameterScheduleTx struct {
+	Fee         int64
+	Title       string
+	Description string
+	Changes     []ScheduledParameterChange
+	VotingType  VotingType
+	StartTime   int64
+	EndTime     int64
+	Threshold   uint64
+}
+
 // DistributionCategory represents different token allocation categories
 type DistributionCategory byte
 
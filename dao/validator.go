@@ -2,7 +2,8 @@ package dao
 
 import (
 	"fmt"
-	"time"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/types"
@@ -12,6 +13,7 @@ import (
 type DAOValidator struct {
 	governanceState *GovernanceState
 	tokenState      *GovernanceToken
+	parameters      *ParameterManager // Proposal tracks, nil for none
 }
 
 // NewDAOValidator creates a new DAO validator
@@ -26,10 +28,13 @@ func NewDAOValidator(governanceState *GovernanceState, tokenState *GovernanceTok
 func (v *DAOValidator) ValidateProposalTx(tx *ProposalTx, creator crypto.PublicKey) error {
 	// Check if creator has sufficient tokens
 	creatorStr := creator.String()
-	balance, exists := v.tokenState.Balances[creatorStr]
+	balance, exists := v.tokenState.Balances.Lookup(creatorStr)
 	if !exists || balance < v.governanceState.Config.MinProposalThreshold {
 		return ErrInsufficientTokensForProposal
 	}
+	if err := v.validateMembership(creator); err != nil {
+		return err
+	}
 
 	// Validate proposal format
 	if len(tx.Title) == 0 || len(tx.Title) > 200 {
@@ -56,7 +61,7 @@ func (v *DAOValidator) ValidateProposalTx(tx *ProposalTx, creator crypto.PublicK
 	}
 
 	// Validate proposal type
-	if tx.ProposalType < ProposalTypeGeneral || tx.ProposalType > ProposalTypeParameter {
+	if tx.ProposalType < ProposalTypeGeneral || tx.ProposalType > ProposalTypeConstitution {
 		return NewDAOError(ErrInvalidProposal, "invalid proposal type", nil)
 	}
 
@@ -77,19 +82,56 @@ func (v *DAOValidator) ValidateProposalTx(tx *ProposalTx, creator crypto.PublicK
 		}
 	}
 
+	if tx.Track != "" {
+		return v.validateProposalTrack(tx, balance)
+	}
+
+	return nil
+}
+
+// validateProposalTrack checks a proposal against the rules of its track
+func (v *DAOValidator) validateProposalTrack(tx *ProposalTx, balance uint64) error {
+	if v.parameters == nil {
+		return ErrTrackNotFoundError
+	}
+	track, exists := v.parameters.GetTrack(tx.Track)
+	if !exists {
+		return ErrTrackNotFoundError
+	}
+
+	if tx.VotingType != track.VotingType {
+		return NewDAOError(ErrInvalidTrack, "voting type is not the track's", map[string]interface{}{
+			"track":       track.ID,
+			"voting_type": track.VotingType,
+		})
+	}
+
+	if !track.Allows(tx.ProposalType) {
+		return NewDAOError(ErrInvalidTrack, "proposal type is not allowed on the track", map[string]interface{}{
+			"track":   track.ID,
+			"actions": track.Actions,
+		})
+	}
+
+	if tx.Fee < 0 || balance < uint64(tx.Fee)+track.Deposit {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for the track deposit", map[string]interface{}{
+			"deposit": track.Deposit,
+		})
+	}
+
 	return nil
 }
 
 // ValidateVoteTx validates a vote transaction with comprehensive checks
 func (v *DAOValidator) ValidateVoteTx(tx *VoteTx, voter crypto.PublicKey) error {
 	// Check if proposal exists
-	proposal, exists := v.governanceState.Proposals[tx.ProposalID]
+	proposal, exists := v.governanceState.Proposal(tx.ProposalID)
 	if !exists {
 		return ErrProposalNotFoundError
 	}
 
 	// Check if proposal is active
-	now := time.Now().Unix()
+	now := v.governanceState.Now()
 	if now < proposal.StartTime {
 		return ErrVotingNotStarted
 	}
@@ -98,10 +140,16 @@ func (v *DAOValidator) ValidateVoteTx(tx *VoteTx, voter crypto.PublicKey) error
 		return ErrVotingPeriodClosed
 	}
 
-	if proposal.Status != ProposalStatusActive {
+	// Scheduled proposals stay pending until their start time, after that
+	// they are open even before their status is refreshed
+	if proposal.Status != ProposalStatusActive && proposal.Status != ProposalStatusPending {
 		return NewDAOError(ErrVotingClosed, "proposal is not in active status", nil)
 	}
 
+	if err := v.validateMembership(voter); err != nil {
+		return err
+	}
+
 	// Enhanced double-voting prevention
 	voterStr := voter.String()
 	if err := v.validateNoDuplicateVote(tx.ProposalID, voterStr); err != nil {
@@ -114,7 +162,7 @@ func (v *DAOValidator) ValidateVoteTx(tx *VoteTx, voter crypto.PublicKey) error
 	}
 
 	// Check voter eligibility (must have tokens)
-	balance, exists := v.tokenState.Balances[voterStr]
+	balance, exists := v.tokenState.Balances.Lookup(voterStr)
 	if !exists || balance == 0 {
 		return ErrInsufficientTokensForVote
 	}
@@ -137,20 +185,25 @@ func (v *DAOValidator) ValidateVoteTx(tx *VoteTx, voter crypto.PublicKey) error
 	return nil
 }
 
-// validateNoDuplicateVote ensures the voter hasn't already voted on this proposal
+// validateNoDuplicateVote ensures the voter hasn't already voted on this proposal.
+// The voter set answers the check, the vote record only adds details while it
+// has not been pruned.
 func (v *DAOValidator) validateNoDuplicateVote(proposalID types.Hash, voterStr string) error {
-	if votes, exists := v.governanceState.Votes[proposalID]; exists {
-		if existingVote, hasVoted := votes[voterStr]; hasVoted {
-			return NewDAOError(ErrDuplicateVote,
-				fmt.Sprintf("voter has already cast %s vote on this proposal",
-					v.voteChoiceToString(existingVote.Choice)),
-				map[string]interface{}{
-					"existing_vote_timestamp": existingVote.Timestamp,
-					"existing_vote_weight":    existingVote.Weight,
-				})
-		}
+	if !v.governanceState.HasVoted(proposalID, voterStr) {
+		return nil
 	}
-	return nil
+
+	existingVote, exists := v.governanceState.Votes[proposalID][voterStr]
+	if !exists {
+		return NewDAOError(ErrDuplicateVote, "voter has already voted on this proposal", nil)
+	}
+	return NewDAOError(ErrDuplicateVote,
+		fmt.Sprintf("voter has already cast %s vote on this proposal",
+			v.voteChoiceToString(existingVote.Choice)),
+		map[string]interface{}{
+			"existing_vote_timestamp": existingVote.Timestamp,
+			"existing_vote_weight":    existingVote.Weight,
+		})
 }
 
 // validateVotingWeightAndCost validates vote weight and ensures voter has sufficient tokens
@@ -237,7 +290,7 @@ func (v *DAOValidator) voteChoiceToString(choice VoteChoice) string {
 func (v *DAOValidator) ValidateDelegationTx(tx *DelegationTx, delegator crypto.PublicKey) error {
 	// Check if delegator has tokens
 	delegatorStr := delegator.String()
-	balance, exists := v.tokenState.Balances[delegatorStr]
+	balance, exists := v.tokenState.Balances.Lookup(delegatorStr)
 	if !exists || balance == 0 {
 		return NewDAOError(ErrInsufficientTokens, "delegator has no tokens", nil)
 	}
@@ -273,13 +326,13 @@ func (v *DAOValidator) ValidateDelegationTx(tx *DelegationTx, delegator crypto.P
 
 		// Check if delegate exists (has tokens or is registered)
 		delegateStr := tx.Delegate.String()
-		if _, exists := v.tokenState.Balances[delegateStr]; !exists {
+		if _, exists := v.tokenState.Balances.Lookup(delegateStr); !exists {
 			return NewDAOError(ErrInvalidDelegation, "delegate address not found", nil)
 		}
 
 		// Check if delegator already has an active delegation
 		if existingDelegation, exists := v.governanceState.Delegations[delegatorStr]; exists && existingDelegation.Active {
-			now := time.Now().Unix()
+			now := v.governanceState.Now()
 			if now >= existingDelegation.StartTime && now <= existingDelegation.EndTime {
 				return NewDAOError(ErrInvalidDelegation, "delegator already has an active delegation", nil)
 			}
@@ -291,8 +344,9 @@ func (v *DAOValidator) ValidateDelegationTx(tx *DelegationTx, delegator crypto.P
 
 // ValidateTreasuryTx validates a treasury transaction
 func (v *DAOValidator) ValidateTreasuryTx(tx *TreasuryTx) error {
-	// Check treasury balance
-	if tx.Amount > v.governanceState.Treasury.Balance {
+	// Check treasury balance. Stable denominated amounts are checked once
+	// converted.
+	if tx.Denomination == "" && tx.Amount > v.governanceState.Treasury.Balance {
 		return ErrTreasuryInsufficientFunds
 	}
 
@@ -311,6 +365,20 @@ func (v *DAOValidator) ValidateTreasuryTx(tx *TreasuryTx) error {
 		return NewDAOError(ErrInvalidSignature, "required signatures exceeds available signers", nil)
 	}
 
+	// Aggregate signatures replace the signature list and are checked
+	// against the pending transaction they sign
+	if tx.Aggregate != nil {
+		if len(tx.Signatures) > 0 {
+			return NewDAOError(ErrInvalidSignature, "treasury transaction carries both signatures and an aggregate signature", nil)
+		}
+		if _, err := crypto.AggregateSignerFor(tx.Aggregate.Scheme); err != nil {
+			return NewDAOError(ErrInvalidSignature, err.Error(), nil)
+		}
+		if len(tx.Aggregate.Signers) > len(v.governanceState.Treasury.Signers) {
+			return NewDAOError(ErrInvalidSignature, "too many aggregate signers", nil)
+		}
+	}
+
 	// Validate each signature if any are provided
 	for i, sig := range tx.Signatures {
 		if i >= len(v.governanceState.Treasury.Signers) {
@@ -327,11 +395,28 @@ func (v *DAOValidator) ValidateTreasuryTx(tx *TreasuryTx) error {
 	return nil
 }
 
+// ValidateTreasurySignatures checks signatures of data against the treasury
+// signers and returns how many distinct signers signed. The signatures are
+// checked side by side, each against the signers until one matches, so a
+// signer whose signature is repeated is counted once.
+func (v *DAOValidator) ValidateTreasurySignatures(data []byte, signatures []crypto.Signature) (int, error) {
+	matches, ok := crypto.MatchSigners(data, signatures, v.governanceState.Treasury.Signers)
+	if !ok {
+		return 0, NewDAOError(ErrInvalidSignature, "invalid signature found in treasury transaction", nil)
+	}
+
+	signers := make(map[int]bool, len(matches))
+	for _, signer := range matches {
+		signers[signer] = true
+	}
+	return len(signers), nil
+}
+
 // ValidateTokenMintTx validates a token minting transaction
 func (v *DAOValidator) ValidateTokenMintTx(tx *TokenMintTx, minter crypto.PublicKey) error {
 	// Check if minter is authorized (for now, any token holder can mint - this would be restricted in production)
 	minterStr := minter.String()
-	balance, exists := v.tokenState.Balances[minterStr]
+	balance, exists := v.tokenState.Balances.Lookup(minterStr)
 	if !exists || balance == 0 {
 		return NewDAOError(ErrUnauthorized, "minter has no tokens", nil)
 	}
@@ -358,7 +443,7 @@ func (v *DAOValidator) ValidateTokenMintTx(tx *TokenMintTx, minter crypto.Public
 func (v *DAOValidator) ValidateTokenBurnTx(tx *TokenBurnTx, burner crypto.PublicKey) error {
 	// Check if burner has sufficient tokens
 	burnerStr := burner.String()
-	balance, exists := v.tokenState.Balances[burnerStr]
+	balance, exists := v.tokenState.Balances.Lookup(burnerStr)
 	if !exists || balance < tx.Amount+uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens to burn and pay fee", nil)
 	}
@@ -376,11 +461,55 @@ func (v *DAOValidator) ValidateTokenBurnTx(tx *TokenBurnTx, burner crypto.Public
 	return nil
 }
 
+// ValidateRageQuitTx validates a rage quit. Members cannot leave while their
+// votes on undecided proposals still count.
+func (v *DAOValidator) ValidateRageQuitTx(tx *RageQuitTx, member crypto.PublicKey) error {
+	memberStr := member.String()
+	balance, exists := v.tokenState.Balances.Lookup(memberStr)
+	if !exists || balance < tx.Amount+uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens to burn and pay fee", nil)
+	}
+
+	if tx.Amount == 0 {
+		return NewDAOError(ErrInvalidProposal, "rage quit amount must be greater than zero", nil)
+	}
+
+	if open := v.governanceState.OpenVotes(memberStr); len(open) > 0 {
+		return NewDAOError(ErrInvalidProposal, "cannot rage quit with votes on pending proposals", map[string]interface{}{
+			"proposals": len(open),
+		})
+	}
+
+	return nil
+}
+
+// ValidateDAOCreateTx validates the creation of a hosted DAO
+func (v *DAOValidator) ValidateDAOCreateTx(tx *DAOCreateTx, creator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(creator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for DAO creation fee", nil)
+	}
+
+	if !ValidDAOID(tx.DAOID) {
+		return NewDAOError(ErrInvalidDAO, "DAO ID must be 3 to 32 lowercase letters, digits and dashes", map[string]interface{}{
+			"dao_id": tx.DAOID,
+		})
+	}
+	if len(tx.Name) == 0 || len(tx.Name) > 100 {
+		return NewDAOError(ErrInvalidDAO, "DAO name must be between 1 and 100 characters", nil)
+	}
+	if len(tx.Description) > 1000 {
+		return NewDAOError(ErrInvalidDAO, "DAO description must be at most 1000 characters", nil)
+	}
+
+	return tx.Genesis.Validate()
+}
+
 // ValidateTokenTransferTx validates a token transfer transaction
 func (v *DAOValidator) ValidateTokenTransferTx(tx *TokenTransferTx, sender crypto.PublicKey) error {
 	// Check if sender has sufficient tokens
 	senderStr := sender.String()
-	balance, exists := v.tokenState.Balances[senderStr]
+	balance, exists := v.tokenState.Balances.Lookup(senderStr)
 	if !exists || balance < tx.Amount+uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for transfer and fee", nil)
 	}
@@ -402,7 +531,7 @@ func (v *DAOValidator) ValidateTokenTransferTx(tx *TokenTransferTx, sender crypt
 func (v *DAOValidator) ValidateTokenApproveTx(tx *TokenApproveTx, owner crypto.PublicKey) error {
 	// Check if owner has sufficient tokens for fee
 	ownerStr := owner.String()
-	balance, exists := v.tokenState.Balances[ownerStr]
+	balance, exists := v.tokenState.Balances.Lookup(ownerStr)
 	if !exists || balance < uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for approval fee", nil)
 	}
@@ -420,14 +549,14 @@ func (v *DAOValidator) ValidateTokenApproveTx(tx *TokenApproveTx, owner crypto.P
 func (v *DAOValidator) ValidateTokenTransferFromTx(tx *TokenTransferFromTx, spender crypto.PublicKey) error {
 	// Check if spender has sufficient tokens for fee
 	spenderStr := spender.String()
-	spenderBalance, exists := v.tokenState.Balances[spenderStr]
+	spenderBalance, exists := v.tokenState.Balances.Lookup(spenderStr)
 	if !exists || spenderBalance < uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for transfer fee", nil)
 	}
 
 	// Check if from address has sufficient balance
 	fromStr := tx.From.String()
-	fromBalance, exists := v.tokenState.Balances[fromStr]
+	fromBalance, exists := v.tokenState.Balances.Lookup(fromStr)
 	if !exists || fromBalance < tx.Amount {
 		return NewDAOError(ErrInsufficientTokens, "insufficient balance in from address", nil)
 	}
@@ -455,7 +584,7 @@ func (v *DAOValidator) ValidateTokenTransferFromTx(tx *TokenTransferFromTx, spen
 func (v *DAOValidator) ValidateTokenDistributionTx(tx *TokenDistributionTx, distributor crypto.PublicKey) error {
 	// Check if distributor is authorized (should be DAO admin or governance)
 	distributorStr := distributor.String()
-	balance, exists := v.tokenState.Balances[distributorStr]
+	balance, exists := v.tokenState.Balances.Lookup(distributorStr)
 	if !exists || balance < uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for distribution fee", nil)
 	}
@@ -510,7 +639,7 @@ func (v *DAOValidator) ValidateTokenDistributionTx(tx *TokenDistributionTx, dist
 func (v *DAOValidator) ValidateVestingClaimTx(tx *VestingClaimTx, claimer crypto.PublicKey) error {
 	// Check if claimer has sufficient tokens for fee
 	claimerStr := claimer.String()
-	balance, exists := v.tokenState.Balances[claimerStr]
+	balance, exists := v.tokenState.Balances.Lookup(claimerStr)
 	if !exists || balance < uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for claim fee", nil)
 	}
@@ -527,7 +656,7 @@ func (v *DAOValidator) ValidateVestingClaimTx(tx *VestingClaimTx, claimer crypto
 func (v *DAOValidator) ValidateStakeTx(tx *StakeTx, staker crypto.PublicKey) error {
 	// Check if staker has sufficient tokens
 	stakerStr := staker.String()
-	balance, exists := v.tokenState.Balances[stakerStr]
+	balance, exists := v.tokenState.Balances.Lookup(stakerStr)
 	if !exists || balance < tx.Amount+uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for staking and fee", nil)
 	}
@@ -554,7 +683,7 @@ func (v *DAOValidator) ValidateStakeTx(tx *StakeTx, staker crypto.PublicKey) err
 func (v *DAOValidator) ValidateUnstakeTx(tx *UnstakeTx, unstaker crypto.PublicKey) error {
 	// Check if unstaker has sufficient tokens for fee
 	unstakerStr := unstaker.String()
-	balance, exists := v.tokenState.Balances[unstakerStr]
+	balance, exists := v.tokenState.Balances.Lookup(unstakerStr)
 	if !exists || balance < uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for unstaking fee", nil)
 	}
@@ -576,7 +705,7 @@ func (v *DAOValidator) ValidateUnstakeTx(tx *UnstakeTx, unstaker crypto.PublicKe
 func (v *DAOValidator) ValidateClaimRewardsTx(tx *ClaimRewardsTx, claimer crypto.PublicKey) error {
 	// Check if claimer has sufficient tokens for fee
 	claimerStr := claimer.String()
-	balance, exists := v.tokenState.Balances[claimerStr]
+	balance, exists := v.tokenState.Balances.Lookup(claimerStr)
 	if !exists || balance < uint64(tx.Fee) {
 		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for claim fee", nil)
 	}
@@ -588,3 +717,1115 @@ func (v *DAOValidator) ValidateClaimRewardsTx(tx *ClaimRewardsTx, claimer crypto
 
 	return nil
 }
+
+// ValidatePositionTransferTx validates a position transfer transaction
+func (v *DAOValidator) ValidatePositionTransferTx(tx *PositionTransferTx, sender crypto.PublicKey) error {
+	// Check if sender has sufficient tokens for fee
+	senderStr := sender.String()
+	balance, exists := v.tokenState.Balances.Lookup(senderStr)
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for transfer fee", nil)
+	}
+
+	// Validate position ID
+	if len(tx.PositionID) == 0 {
+		return NewDAOError(ErrInvalidProposal, "position ID cannot be empty", nil)
+	}
+
+	// Validate recipient
+	if len(tx.Recipient) == 0 {
+		return NewDAOError(ErrInvalidProposal, "recipient cannot be empty", nil)
+	}
+
+	if tx.Recipient.String() == senderStr {
+		return NewDAOError(ErrInvalidProposal, "cannot transfer position to self", nil)
+	}
+
+	return nil
+}
+
+// ValidateDisputeTx validates a dispute filing transaction
+func (v *DAOValidator) ValidateDisputeTx(tx *DisputeTx, claimant crypto.PublicKey) error {
+	// Check if claimant has sufficient tokens for fee
+	claimantStr := claimant.String()
+	balance, exists := v.tokenState.Balances.Lookup(claimantStr)
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for dispute fee", nil)
+	}
+
+	switch tx.DisputeType {
+	case DisputeTypeGrant, DisputeTypeModerationAppeal:
+	case DisputeTypeClawback:
+		if len(tx.Respondent) == 0 {
+			return NewDAOError(ErrInvalidProposal, "clawback dispute requires a respondent", nil)
+		}
+	default:
+		return NewDAOError(ErrInvalidProposal, "invalid dispute type", nil)
+	}
+
+	if len(tx.Respondent) > 0 && tx.Respondent.String() == claimantStr {
+		return NewDAOError(ErrInvalidProposal, "cannot open a dispute against yourself", nil)
+	}
+
+	if len(tx.Description) == 0 {
+		return NewDAOError(ErrInvalidProposal, "dispute description cannot be empty", nil)
+	}
+
+	return nil
+}
+
+// ValidateDisputeEvidenceTx validates an evidence submission transaction
+func (v *DAOValidator) ValidateDisputeEvidenceTx(tx *DisputeEvidenceTx, submitter crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(submitter.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for evidence fee", nil)
+	}
+
+	if tx.EvidenceHash.IsZero() {
+		return NewDAOError(ErrInvalidProposal, "evidence hash cannot be empty", nil)
+	}
+
+	return nil
+}
+
+// ValidateJurorCommitTx validates a juror commitment transaction
+func (v *DAOValidator) ValidateJurorCommitTx(tx *JurorCommitTx, juror crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(juror.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for commit fee", nil)
+	}
+
+	if tx.Commitment.IsZero() {
+		return NewDAOError(ErrInvalidProposal, "commitment cannot be empty", nil)
+	}
+
+	return nil
+}
+
+// ValidateJurorRevealTx validates a juror reveal transaction
+func (v *DAOValidator) ValidateJurorRevealTx(tx *JurorRevealTx, juror crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(juror.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for reveal fee", nil)
+	}
+
+	if tx.Ruling != DisputeRulingClaimant && tx.Ruling != DisputeRulingRespondent {
+		return NewDAOError(ErrInvalidVoteChoice, "invalid ruling", nil)
+	}
+
+	return nil
+}
+
+// ValidateFundingKPITx validates a KPI attachment transaction
+func (v *DAOValidator) ValidateFundingKPITx(tx *FundingKPITx, from crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(from.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for KPI fee", nil)
+	}
+
+	if len(tx.Category) == 0 {
+		return NewDAOError(ErrInvalidProposal, "funding category cannot be empty", nil)
+	}
+
+	if len(tx.Grantee) == 0 {
+		return NewDAOError(ErrInvalidProposal, "grantee cannot be empty", nil)
+	}
+
+	if tx.Amount == 0 {
+		return NewDAOError(ErrInvalidProposal, "funding amount must be positive", nil)
+	}
+
+	if len(tx.KPIs) == 0 || len(tx.KPIs) > MaxKPIsPerProposal {
+		return NewDAOError(ErrInvalidProposal, "invalid number of KPIs", map[string]interface{}{
+			"count": len(tx.KPIs),
+			"max":   MaxKPIsPerProposal,
+		})
+	}
+
+	names := make(map[string]bool, len(tx.KPIs))
+	for _, kpi := range tx.KPIs {
+		if len(kpi.Name) == 0 {
+			return NewDAOError(ErrInvalidProposal, "KPI name cannot be empty", nil)
+		}
+		if names[kpi.Name] {
+			return NewDAOError(ErrInvalidProposal, "duplicate KPI name", map[string]interface{}{"kpi": kpi.Name})
+		}
+		if kpi.Target == 0 {
+			return NewDAOError(ErrInvalidProposal, "KPI target must be positive", map[string]interface{}{"kpi": kpi.Name})
+		}
+		names[kpi.Name] = true
+	}
+
+	return nil
+}
+
+// ValidateImpactReviewTx validates an impact review transaction
+func (v *DAOValidator) ValidateImpactReviewTx(tx *ImpactReviewTx, reviewer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(reviewer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for review fee", nil)
+	}
+
+	if len(tx.Actuals) == 0 {
+		return NewDAOError(ErrInvalidProposal, "review must measure at least one KPI", nil)
+	}
+
+	return nil
+}
+
+// ValidateValidatorConfigTx validates a validator configuration transaction
+func (v *DAOValidator) ValidateValidatorConfigTx(tx *ValidatorConfigTx, validator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(validator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for validator config fee", nil)
+	}
+
+	if len(tx.PoolID) == 0 {
+		return NewDAOError(ErrInvalidProposal, "staking pool cannot be empty", nil)
+	}
+
+	if tx.CommissionBps > 10000 {
+		return NewDAOError(ErrInvalidProposal, "commission cannot exceed 10000 basis points", nil)
+	}
+
+	return nil
+}
+
+// ValidateValidatorSetExecuteTx validates a validator set change execution
+func (v *DAOValidator) ValidateValidatorSetExecuteTx(tx *ValidatorSetExecuteTx, executor crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(executor.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateClaimCommissionTx validates a commission claim transaction
+func (v *DAOValidator) ValidateClaimCommissionTx(tx *ClaimCommissionTx, validator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(validator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for claim fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateMultisigCreateTx validates a multisig account creation
+func (v *DAOValidator) ValidateMultisigCreateTx(tx *MultisigCreateTx, creator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(creator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for multisig creation fee", nil)
+	}
+
+	if len(tx.Name) == 0 || len(tx.Name) > 100 {
+		return NewDAOError(ErrInvalidProposal, "multisig name must be 1 to 100 characters", nil)
+	}
+
+	if len(tx.Owners) == 0 || len(tx.Owners) > MaxMultisigOwners {
+		return NewDAOError(ErrInvalidProposal, "multisig must have between 1 and 32 owners", nil)
+	}
+
+	seen := make(map[string]bool, len(tx.Owners))
+	for _, owner := range tx.Owners {
+		if IsMultisigAccountKey(owner) {
+			return NewDAOError(ErrInvalidProposal, "multisig accounts cannot own other multisig accounts", nil)
+		}
+		if len(owner) == 0 || seen[owner.String()] {
+			return NewDAOError(ErrInvalidProposal, "multisig owners must be distinct public keys", nil)
+		}
+		seen[owner.String()] = true
+	}
+
+	if tx.Threshold == 0 || int(tx.Threshold) > len(tx.Owners) {
+		return NewDAOError(ErrInvalidThreshold, "multisig threshold must be between 1 and the number of owners", nil)
+	}
+
+	return nil
+}
+
+// ValidateMultisigSubmitTx validates a multisig transaction submission
+func (v *DAOValidator) ValidateMultisigSubmitTx(tx *MultisigSubmitTx, submitter crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(submitter.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for multisig submission fee", nil)
+	}
+
+	switch tx.Tx.(type) {
+	case nil:
+		return NewDAOError(ErrInvalidProposal, "multisig submission has no transaction", nil)
+	case *MultisigSubmitTx, *MultisigSignTx:
+		return NewDAOError(ErrInvalidProposal, "multisig transactions cannot be nested", nil)
+	}
+
+	if ActivityTypeOf(tx.Tx) == ActivityTypeUnknown {
+		return NewDAOError(ErrInvalidProposal, "unknown DAO transaction type", nil)
+	}
+
+	return nil
+}
+
+// ValidateMultisigSignTx validates a multisig approval
+func (v *DAOValidator) ValidateMultisigSignTx(tx *MultisigSignTx, signer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(signer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for multisig signature fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateSubDAOCreateProposalTx validates the charter of a proposed sub-DAO
+func (v *DAOValidator) ValidateSubDAOCreateProposalTx(tx *SubDAOCreateProposalTx, proposer crypto.PublicKey) error {
+	if len(tx.Name) == 0 || len(tx.Name) > 100 {
+		return NewDAOError(ErrInvalidProposal, "sub-DAO name must be 1 to 100 characters", nil)
+	}
+
+	if len(tx.Members) == 0 || len(tx.Members) > MaxSubDAOMembers {
+		return NewDAOError(ErrInvalidProposal, "sub-DAO must have between 1 and 100 members", nil)
+	}
+
+	seen := make(map[string]bool, len(tx.Members))
+	for _, member := range tx.Members {
+		if len(member) == 0 || seen[member.String()] {
+			return NewDAOError(ErrInvalidProposal, "sub-DAO members must be distinct public keys", nil)
+		}
+		seen[member.String()] = true
+	}
+
+	if tx.Budget > 0 && tx.StreamDuration <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "sub-DAO budget needs a positive stream duration", nil)
+	}
+
+	return nil
+}
+
+// ValidateSubDAOCreateExecuteTx validates a sub-DAO creation
+func (v *DAOValidator) ValidateSubDAOCreateExecuteTx(tx *SubDAOCreateExecuteTx, executor crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(executor.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateSubDAOProposalTx validates a sub-DAO proposal
+func (v *DAOValidator) ValidateSubDAOProposalTx(tx *SubDAOProposalTx, proposer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(proposer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for sub-DAO proposal fee", nil)
+	}
+
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "title must be 1 to 200 characters", nil)
+	}
+
+	if tx.Duration <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "voting window must be positive", nil)
+	}
+
+	switch tx.Action {
+	case SubDAOActionSpend:
+		if tx.Amount == 0 || len(tx.Target) == 0 {
+			return NewDAOError(ErrInvalidProposal, "spending needs a recipient and a positive amount", nil)
+		}
+	case SubDAOActionAddMember, SubDAOActionRemoveMember:
+		if len(tx.Target) == 0 {
+			return NewDAOError(ErrInvalidProposal, "membership changes need a member", nil)
+		}
+	case SubDAOActionSignal:
+	default:
+		return NewDAOError(ErrInvalidProposal, "unknown sub-DAO action", nil)
+	}
+
+	return nil
+}
+
+// ValidateSubDAOVoteTx validates a sub-DAO vote
+func (v *DAOValidator) ValidateSubDAOVoteTx(tx *SubDAOVoteTx, voter crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(voter.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for sub-DAO vote fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateGrantProposalTx validates a grant application
+func (v *DAOValidator) ValidateGrantProposalTx(tx *GrantProposalTx, applicant crypto.PublicKey) error {
+	if len(tx.Milestones) == 0 || len(tx.Milestones) > MaxGrantMilestones {
+		return NewDAOError(ErrInvalidProposal, "grant must have between 1 and 20 milestones", nil)
+	}
+
+	for _, milestone := range tx.Milestones {
+		if len(milestone.Title) == 0 || len(milestone.Title) > 200 {
+			return NewDAOError(ErrInvalidProposal, "milestone title must be 1 to 200 characters", nil)
+		}
+		if milestone.Amount == 0 {
+			return NewDAOError(ErrInvalidProposal, "milestone amount must be positive", nil)
+		}
+	}
+
+	if len(tx.Committee) == 0 {
+		return NewDAOError(ErrInvalidProposal, "grant needs a review committee", nil)
+	}
+	if tx.Committee.String() == applicant.String() {
+		return NewDAOError(ErrInvalidProposal, "applicants cannot review their own grant", nil)
+	}
+
+	return nil
+}
+
+// ValidateGrantExecuteTx validates the funding of a grant
+func (v *DAOValidator) ValidateGrantExecuteTx(tx *GrantExecuteTx, executor crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(executor.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateGrantMilestoneSubmitTx validates a milestone submission
+func (v *DAOValidator) ValidateGrantMilestoneSubmitTx(tx *GrantMilestoneSubmitTx, applicant crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(applicant.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for milestone submission fee", nil)
+	}
+
+	if len(tx.Deliverable) == 0 || len(tx.Deliverable) > 1000 {
+		return NewDAOError(ErrInvalidProposal, "deliverable must be 1 to 1000 characters", nil)
+	}
+
+	return nil
+}
+
+// ValidateGrantMilestoneReviewTx validates a milestone review
+func (v *DAOValidator) ValidateGrantMilestoneReviewTx(tx *GrantMilestoneReviewTx, reviewer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(reviewer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for milestone review fee", nil)
+	}
+
+	if !tx.Approve && len(tx.Comment) == 0 {
+		return NewDAOError(ErrInvalidProposal, "requesting changes needs a comment", nil)
+	}
+
+	return nil
+}
+
+// ValidateGrantCancelTx validates a grant cancellation
+func (v *DAOValidator) ValidateGrantCancelTx(tx *GrantCancelTx, canceller crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(canceller.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for cancellation fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateBountyProposalTx validates a proposed bounty
+func (v *DAOValidator) ValidateBountyProposalTx(tx *BountyProposalTx, proposer crypto.PublicKey) error {
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "bounty title must be 1 to 200 characters", nil)
+	}
+
+	if tx.Reward == 0 {
+		return NewDAOError(ErrInvalidProposal, "bounty reward must be positive", nil)
+	}
+
+	if len(tx.Reviewer) == 0 {
+		return NewDAOError(ErrInvalidProposal, "bounty needs a reviewer", nil)
+	}
+
+	if tx.ClaimPeriod <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "claim period must be positive", nil)
+	}
+
+	return nil
+}
+
+// ValidateBountyPostTx validates the posting of a bounty
+func (v *DAOValidator) ValidateBountyPostTx(tx *BountyPostTx, poster crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(poster.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateQFRoundProposalTx validates a proposed quadratic funding round
+func (v *DAOValidator) ValidateQFRoundProposalTx(tx *QFRoundProposalTx, proposer crypto.PublicKey) error {
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "round title must be 1 to 200 characters", nil)
+	}
+
+	if tx.MatchingPool == 0 {
+		return NewDAOError(ErrInvalidProposal, "matching pool must be positive", nil)
+	}
+
+	if tx.ContributionPeriod <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "contribution period must be positive", nil)
+	}
+
+	if len(tx.Projects) == 0 || len(tx.Projects) > MaxQFProjects {
+		return NewDAOError(ErrInvalidProposal, "round must have between 1 and 50 projects", nil)
+	}
+
+	recipients := make(map[string]bool, len(tx.Projects))
+	for _, project := range tx.Projects {
+		if len(project.Name) == 0 || len(project.Name) > 200 {
+			return NewDAOError(ErrInvalidProposal, "project name must be 1 to 200 characters", nil)
+		}
+		if len(project.Recipient) == 0 {
+			return NewDAOError(ErrInvalidProposal, "project needs a recipient", nil)
+		}
+		if recipients[project.Recipient.String()] {
+			return NewDAOError(ErrInvalidProposal, "projects must have different recipients", nil)
+		}
+		recipients[project.Recipient.String()] = true
+	}
+
+	return nil
+}
+
+// ValidateQFRoundOpenTx validates the opening of a quadratic funding round
+func (v *DAOValidator) ValidateQFRoundOpenTx(tx *QFRoundOpenTx, opener crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(opener.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateQFContributeTx validates a contribution to a quadratic funding
+// project
+func (v *DAOValidator) ValidateQFContributeTx(tx *QFContributeTx, contributor crypto.PublicKey) error {
+	if tx.Amount == 0 {
+		return NewDAOError(ErrInvalidProposal, "contribution must be positive", nil)
+	}
+
+	balance := v.tokenState.Balances.Get(contributor.String())
+	if tx.Fee < 0 || balance < uint64(tx.Fee) || balance-uint64(tx.Fee) < tx.Amount {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for contribution and fee", map[string]interface{}{
+			"balance": balance,
+			"amount":  tx.Amount,
+		})
+	}
+
+	return v.validateMembership(contributor)
+}
+
+// ValidateQFPayoutTx validates the payout of a quadratic funding round
+func (v *DAOValidator) ValidateQFPayoutTx(tx *QFPayoutTx, payer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(payer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for payout fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateOptimisticProposalTx validates an optimistic proposal by the
+// rules of the vote it becomes when challenged
+func (v *DAOValidator) ValidateOptimisticProposalTx(tx *OptimisticProposalTx, proposer crypto.PublicKey) error {
+	if tx.Fee < 0 {
+		return NewDAOError(ErrInvalidProposal, "fee cannot be negative", nil)
+	}
+
+	return v.ValidateProposalTx(tx.Proposal(v.governanceState.Now()), proposer)
+}
+
+// ValidateOptimisticChallengeTx validates a challenge to an optimistic
+// proposal
+func (v *DAOValidator) ValidateOptimisticChallengeTx(tx *OptimisticChallengeTx, challenger crypto.PublicKey) error {
+	if tx.Fee < 0 {
+		return NewDAOError(ErrInvalidProposal, "fee cannot be negative", nil)
+	}
+
+	if len(tx.Reason) > 1000 {
+		return NewDAOError(ErrInvalidProposal, "challenge reason must be at most 1000 characters", nil)
+	}
+
+	return v.validateMembership(challenger)
+}
+
+// ValidateRPGFRoundProposalTx validates a proposed retroactive funding round
+func (v *DAOValidator) ValidateRPGFRoundProposalTx(tx *RPGFRoundProposalTx, proposer crypto.PublicKey) error {
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "round title must be 1 to 200 characters", nil)
+	}
+
+	if tx.Budget == 0 {
+		return NewDAOError(ErrInvalidProposal, "round budget must be positive", nil)
+	}
+
+	if tx.NominationPeriod <= 0 || tx.VotingPeriod <= 0 || tx.StreamDuration <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "nomination, voting and stream periods must be positive", nil)
+	}
+
+	if len(tx.BadgeHolders) == 0 || len(tx.BadgeHolders) > MaxRPGFBadgeHolders {
+		return NewDAOError(ErrInvalidProposal, "round must have between 1 and 100 badge holders", nil)
+	}
+
+	holders := make(map[string]bool, len(tx.BadgeHolders))
+	for _, holder := range tx.BadgeHolders {
+		if len(holder) == 0 || holders[holder.String()] {
+			return NewDAOError(ErrInvalidProposal, "badge holders must be distinct addresses", nil)
+		}
+		holders[holder.String()] = true
+	}
+
+	return nil
+}
+
+// ValidateRPGFRoundOpenTx validates the opening of a retroactive funding
+// round
+func (v *DAOValidator) ValidateRPGFRoundOpenTx(tx *RPGFRoundOpenTx, opener crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(opener.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateRPGFNominateTx validates a nomination for retroactive funding
+func (v *DAOValidator) ValidateRPGFNominateTx(tx *RPGFNominateTx, nominator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(nominator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for nomination fee", nil)
+	}
+
+	if len(tx.Name) == 0 || len(tx.Name) > 200 {
+		return NewDAOError(ErrInvalidProposal, "nomination name must be 1 to 200 characters", nil)
+	}
+
+	if len(tx.Recipient) == 0 {
+		return NewDAOError(ErrInvalidProposal, "nomination needs a recipient", nil)
+	}
+
+	return v.validateMembership(nominator)
+}
+
+// ValidateRPGFBallotTx validates a badge holder's ballot
+func (v *DAOValidator) ValidateRPGFBallotTx(tx *RPGFBallotTx, voter crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(voter.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for ballot fee", nil)
+	}
+
+	if len(tx.Allocations) == 0 {
+		return NewDAOError(ErrInvalidProposal, "ballot must allocate to at least one nomination", nil)
+	}
+
+	seen := make(map[uint16]bool, len(tx.Allocations))
+	for _, allocation := range tx.Allocations {
+		if allocation.Amount == 0 {
+			return NewDAOError(ErrInvalidProposal, "allocations must be positive", nil)
+		}
+		if seen[allocation.Nomination] {
+			return NewDAOError(ErrInvalidProposal, "ballot allocates to a nomination twice", nil)
+		}
+		seen[allocation.Nomination] = true
+	}
+
+	return nil
+}
+
+// ValidateRPGFFinalizeTx validates the finalization of a retroactive funding
+// round
+func (v *DAOValidator) ValidateRPGFFinalizeTx(tx *RPGFFinalizeTx, finalizer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(finalizer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateRPGFClaimTx validates a claim of streamed retroactive funding.
+// The fee may be paid from the claimed amount.
+func (v *DAOValidator) ValidateRPGFClaimTx(tx *RPGFClaimTx, claimant crypto.PublicKey) error {
+	if tx.Fee < 0 {
+		return NewDAOError(ErrInvalidProposal, "fee cannot be negative", nil)
+	}
+
+	return nil
+}
+
+// ValidateOracleFeedProposalTx validates a proposed oracle feed
+func (v *DAOValidator) ValidateOracleFeedProposalTx(tx *OracleFeedProposalTx, proposer crypto.PublicKey) error {
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "feed title must be 1 to 200 characters", nil)
+	}
+
+	if !validSlug(tx.FeedID) {
+		return NewDAOError(ErrInvalidProposal, "feed ID must be 1 to 64 letters, digits, '-', '_' or '.'", nil)
+	}
+
+	if tx.MaxAge <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "feed max age must be positive", nil)
+	}
+
+	if len(tx.Oracles) == 0 || len(tx.Oracles) > MaxFeedOracles {
+		return NewDAOError(ErrInvalidProposal, "feed must have between 1 and 50 oracles", nil)
+	}
+
+	if tx.MinReports == 0 || int(tx.MinReports) > len(tx.Oracles) {
+		return NewDAOError(ErrInvalidProposal, "feed minimum reports must be between 1 and the number of oracles", nil)
+	}
+
+	oracles := make(map[string]bool, len(tx.Oracles))
+	for _, oracle := range tx.Oracles {
+		if len(oracle) == 0 || oracles[oracle.String()] {
+			return NewDAOError(ErrInvalidProposal, "oracles must be distinct keys", nil)
+		}
+		oracles[oracle.String()] = true
+	}
+
+	return nil
+}
+
+// ValidateOracleFeedActivateTx validates the activation of an oracle feed
+func (v *DAOValidator) ValidateOracleFeedActivateTx(tx *OracleFeedActivateTx, activator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(activator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateTrackProposalTx validates a proposed proposal track
+func (v *DAOValidator) ValidateTrackProposalTx(tx *TrackProposalTx, proposer crypto.PublicKey) error {
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "track title must be 1 to 200 characters", nil)
+	}
+
+	track := &tx.Track
+	if !validSlug(track.ID) {
+		return NewDAOError(ErrInvalidTrack, "track ID must be 1 to 64 letters, digits, '-', '_' or '.'", nil)
+	}
+	if tx.Remove {
+		if v.parameters == nil {
+			return ErrTrackNotFoundError
+		}
+		if _, exists := v.parameters.GetTrack(track.ID); !exists {
+			return ErrTrackNotFoundError
+		}
+		return nil
+	}
+
+	if len(track.Name) == 0 || len(track.Name) > 100 {
+		return NewDAOError(ErrInvalidTrack, "track name must be 1 to 100 characters", nil)
+	}
+
+	if track.VotingType < VotingTypeSimple || track.VotingType > VotingTypeReputation {
+		return NewDAOError(ErrInvalidTrack, "invalid track voting type", nil)
+	}
+
+	if track.Threshold == 0 || track.Threshold > 10000 {
+		return NewDAOError(ErrInvalidTrack, "track threshold must be 1 to 10000 basis points", nil)
+	}
+
+	if track.Timelock < 0 {
+		return NewDAOError(ErrInvalidTrack, "track timelock cannot be negative", nil)
+	}
+
+	// Constitution and emergency proposals keep their own flows
+	if len(track.Actions) == 0 {
+		return NewDAOError(ErrInvalidTrack, "track must allow at least one proposal type", nil)
+	}
+	actions := make(map[ProposalType]bool, len(track.Actions))
+	for _, action := range track.Actions {
+		if action < ProposalTypeGeneral || action > ProposalTypeParameter || actions[action] {
+			return NewDAOError(ErrInvalidTrack, "track actions must be distinct general, treasury, technical or parameter types", nil)
+		}
+		actions[action] = true
+	}
+
+	return nil
+}
+
+// ValidateTrackActivateTx validates the activation of a proposal track
+func (v *DAOValidator) ValidateTrackActivateTx(tx *TrackActivateTx, activator crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(activator.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateParameterScheduleTx validates proposed staged parameter changes.
+// Target values must be valid now, and are checked again when their
+// rollout starts.
+func (v *DAOValidator) ValidateParameterScheduleTx(tx *ParameterScheduleTx, proposer crypto.PublicKey) error {
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "schedule title must be 1 to 200 characters", nil)
+	}
+
+	if len(tx.Changes) == 0 || len(tx.Changes) > MaxScheduledChanges {
+		return NewDAOError(ErrInvalidProposal, fmt.Sprintf("schedule must change 1 to %d parameters", MaxScheduledChanges), nil)
+	}
+	if v.parameters == nil {
+		return NewDAOError(ErrInvalidProposal, "parameters cannot be scheduled", nil)
+	}
+
+	parameters := make(map[string]bool, len(tx.Changes))
+	for _, change := range tx.Changes {
+		if parameters[change.Parameter] {
+			return NewDAOError(ErrInvalidProposal, "parameter is scheduled more than once", map[string]interface{}{
+				"parameter": change.Parameter,
+			})
+		}
+		parameters[change.Parameter] = true
+
+		value, err := v.parameters.parseScheduledChange(change)
+		if err == nil {
+			err = v.parameters.validateSingleParameter(change.Parameter, value)
+		}
+		if err != nil {
+			return NewDAOError(ErrInvalidProposal, err.Error(), map[string]interface{}{
+				"parameter": change.Parameter,
+			})
+		}
+
+		if change.ActivationHeight != 0 && change.ActivationHeight <= v.parameters.height {
+			return NewDAOError(ErrInvalidTimeframe, "activation height must be in the future", map[string]interface{}{
+				"parameter": change.Parameter,
+				"height":    v.parameters.height,
+			})
+		}
+		if change.RampPeriod < 0 || change.RampPeriod > MaxRampPeriod {
+			return NewDAOError(ErrInvalidTimeframe, "ramp period must be between 0 and 365 days", map[string]interface{}{
+				"parameter": change.Parameter,
+			})
+		}
+	}
+
+	return nil
+}
+
+// ValidateOracleReportTx validates the submission of an oracle report. The
+// oracle's signature is checked against the feed when it is applied.
+func (v *DAOValidator) ValidateOracleReportTx(tx *OracleReportTx, submitter crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(submitter.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for report fee", nil)
+	}
+
+	if tx.ObservedAt <= 0 {
+		return NewDAOError(ErrInvalidTimeframe, "report observation time is required", nil)
+	}
+
+	return nil
+}
+
+// ValidateConstitutionAmendmentTx validates a proposed amendment of a
+// governance document. The threshold and base version are checked against
+// the registry when it is applied.
+func (v *DAOValidator) ValidateConstitutionAmendmentTx(tx *ConstitutionAmendmentTx, proposer crypto.PublicKey) error {
+	if !validSlug(tx.DocumentID) {
+		return NewDAOError(ErrInvalidProposal, "document ID must be 1 to 64 letters, digits, '-', '_' or '.'", nil)
+	}
+
+	if len(tx.DocumentTitle) > 200 {
+		return NewDAOError(ErrInvalidProposal, "document title cannot exceed 200 characters", nil)
+	}
+
+	if tx.BaseVersion == 0 && len(tx.DocumentTitle) == 0 {
+		return NewDAOError(ErrInvalidProposal, "adopting a document requires a document title", nil)
+	}
+
+	if tx.ContentHash.IsZero() || tx.DiffHash.IsZero() {
+		return NewDAOError(ErrInvalidProposal, "amendment content and diff hashes are required", nil)
+	}
+
+	if len(tx.Summary) > 1000 {
+		return NewDAOError(ErrInvalidProposal, "amendment summary cannot exceed 1000 characters", nil)
+	}
+
+	return nil
+}
+
+// ValidateConstitutionEnactTx validates the enactment of an amendment
+func (v *DAOValidator) ValidateConstitutionEnactTx(tx *ConstitutionEnactTx, enactor crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(enactor.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateEmergencySpendTx validates an emergency spend. The guardian's
+// co-signature and the treasury balance are checked when it is applied.
+func (v *DAOValidator) ValidateEmergencySpendTx(tx *EmergencySpendTx, proposer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(proposer.String())
+	if !exists || balance < v.governanceState.Config.MinProposalThreshold || balance < uint64(tx.Fee) {
+		return ErrInsufficientTokensForProposal
+	}
+	if err := v.validateMembership(proposer); err != nil {
+		return err
+	}
+
+	if len(tx.Title) == 0 || len(tx.Title) > 200 {
+		return NewDAOError(ErrInvalidProposal, "proposal title must be between 1 and 200 characters", nil)
+	}
+
+	if len(tx.Description) == 0 || len(tx.Description) > 10000 {
+		return NewDAOError(ErrInvalidProposal, "proposal description must be between 1 and 10000 characters", nil)
+	}
+
+	if len(tx.Incident) == 0 || len(tx.Incident) > 200 {
+		return NewDAOError(ErrInvalidProposal, "incident must be between 1 and 200 characters", nil)
+	}
+
+	if len(tx.Recipient) == 0 {
+		return NewDAOError(ErrInvalidProposal, "recipient is required", nil)
+	}
+
+	if tx.Amount == 0 {
+		return NewDAOError(ErrInvalidProposal, "amount must be greater than zero", nil)
+	}
+
+	if tx.VotingType < VotingTypeSimple || tx.VotingType > VotingTypeReputation {
+		return NewDAOError(ErrInvalidProposal, "invalid voting type", nil)
+	}
+
+	if len(tx.Guardian) == 0 {
+		return NewDAOError(ErrGuardianSignature, "emergency spends must be co-signed by a guardian", nil)
+	}
+
+	return nil
+}
+
+// ValidateEmergencyExecuteTx validates the payment of an emergency spend
+func (v *DAOValidator) ValidateEmergencyExecuteTx(tx *EmergencyExecuteTx, executor crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(executor.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for execution fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateParticipationOptInTx validates opting in or out of participation
+// rewards
+func (v *DAOValidator) ValidateParticipationOptInTx(tx *ParticipationOptInTx, member crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(member.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for opt-in fee", nil)
+	}
+
+	if tx.OptIn {
+		return v.validateMembership(member)
+	}
+	return nil
+}
+
+// ValidateEndorseProposalTx validates endorsing a proposal
+func (v *DAOValidator) ValidateEndorseProposalTx(tx *EndorseProposalTx, endorser crypto.PublicKey) error {
+	if _, exists := v.governanceState.Proposal(tx.ProposalID); !exists {
+		return ErrProposalNotFoundError
+	}
+
+	balance, exists := v.tokenState.Balances.Lookup(endorser.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for endorsement fee", nil)
+	}
+
+	return v.validateMembership(endorser)
+}
+
+// ValidatePrivacySettingsTx validates a member's privacy settings
+func (v *DAOValidator) ValidatePrivacySettingsTx(tx *PrivacySettingsTx, member crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(member.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for privacy settings fee", nil)
+	}
+
+	for _, visibility := range []Visibility{tx.Balance, tx.Reputation, tx.Votes} {
+		if visibility > VisibilityPrivate {
+			return NewDAOError(ErrInvalidProposal, "invalid visibility", map[string]interface{}{
+				"visibility": visibility,
+			})
+		}
+	}
+	return nil
+}
+
+// ValidateNameTx validates registering, renewing or transferring a name.
+// The registry checks ownership and availability.
+func (v *DAOValidator) ValidateNameTx(fee int64, name string, sender crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(sender.String())
+	if !exists || balance < uint64(fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for name fee", nil)
+	}
+
+	if _, err := NormalizeName(name); err != nil {
+		return err
+	}
+	return v.validateMembership(sender)
+}
+
+// ValidateProfileUpdateTx validates publishing a member profile
+func (v *DAOValidator) ValidateProfileUpdateTx(tx *ProfileUpdateTx, member crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(member.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for profile fee", nil)
+	}
+
+	return v.validateMembership(member)
+}
+
+// ValidateDelegateStatementTx validates a delegate statement
+func (v *DAOValidator) ValidateDelegateStatementTx(tx *DelegateStatementTx, delegate crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(delegate.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for delegate statement fee", nil)
+	}
+	if tx.Withdraw {
+		return nil
+	}
+
+	platform := strings.TrimSpace(tx.Platform)
+	if platform == "" || utf8.RuneCountInString(platform) > MaxDelegatePlatformLength {
+		return NewDAOError(ErrInvalidDelegate, "platform must be 1 to 2000 characters", nil)
+	}
+	if tx.DelegateFee > 10000 {
+		return NewDAOError(ErrInvalidDelegate, "delegate fee must be at most 10000 basis points", map[string]interface{}{
+			"fee": tx.DelegateFee,
+		})
+	}
+	if _, err := NormalizeFocusAreas(tx.FocusAreas); err != nil {
+		return err
+	}
+	return v.validateMembership(delegate)
+}
+
+// ValidateCommentTx validates a comment on a proposal
+func (v *DAOValidator) ValidateCommentTx(tx *CommentTx, author crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(author.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for comment fee", nil)
+	}
+
+	if tx.BodyHash.IsZero() {
+		return NewDAOError(ErrInvalidProposal, "comment body hash is required", nil)
+	}
+
+	return nil
+}
+
+// ValidateJoinRequestTx validates a membership application. Applicants may
+// not hold tokens yet so the fee can be zero.
+func (v *DAOValidator) ValidateJoinRequestTx(tx *JoinRequestTx, applicant crypto.PublicKey) error {
+	if tx.Fee < 0 || v.tokenState.Balances.Get(applicant.String()) < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for join request fee", nil)
+	}
+
+	if len(tx.Statement) > 1000 {
+		return NewDAOError(ErrInvalidProposal, "membership statement cannot exceed 1000 characters", nil)
+	}
+
+	return nil
+}
+
+// ValidateJoinApprovalTx validates a member's approval of an application
+func (v *DAOValidator) ValidateJoinApprovalTx(tx *JoinApprovalTx, approver crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(approver.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for join approval fee", nil)
+	}
+
+	if holder, exists := v.governanceState.TokenHolders[approver.String()]; !exists || holder.Status != MembershipStatusActive {
+		return NewDAOError(ErrUnauthorized, "only active members can approve applications", nil)
+	}
+
+	return nil
+}
+
+// ValidateMembershipStatusTx validates a membership status change
+func (v *DAOValidator) ValidateMembershipStatusTx(tx *MembershipStatusTx, sender crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(sender.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for membership status fee", nil)
+	}
+
+	if tx.Status > MembershipStatusExited {
+		return NewDAOError(ErrInvalidProposal, "invalid membership status", nil)
+	}
+
+	if len(tx.Reason) > 500 {
+		return NewDAOError(ErrInvalidProposal, "reason cannot exceed 500 characters", nil)
+	}
+
+	// Suspended members may still leave
+	if tx.Status == MembershipStatusExited && tx.Member.String() == sender.String() {
+		return nil
+	}
+
+	return v.validateMembership(sender)
+}
+
+// validateMembership rejects suspended and exited members
+func (v *DAOValidator) validateMembership(member crypto.PublicKey) error {
+	if holder, exists := v.governanceState.TokenHolders[member.String()]; exists && holder.Status != MembershipStatusActive {
+		return ErrNotActiveMemberError
+	}
+	return nil
+}
+
+// ValidateBountyClaimTx validates a bounty claim
+func (v *DAOValidator) ValidateBountyClaimTx(tx *BountyClaimTx, claimant crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(claimant.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for bounty claim fee", nil)
+	}
+
+	return nil
+}
+
+// ValidateBountySubmitTx validates a bounty work submission
+func (v *DAOValidator) ValidateBountySubmitTx(tx *BountySubmitTx, claimant crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(claimant.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for bounty submission fee", nil)
+	}
+
+	if tx.WorkHash.IsZero() {
+		return NewDAOError(ErrInvalidProposal, "work hash is required", nil)
+	}
+
+	return nil
+}
+
+// ValidateBountyReviewTx validates a bounty review
+func (v *DAOValidator) ValidateBountyReviewTx(tx *BountyReviewTx, reviewer crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(reviewer.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for bounty review fee", nil)
+	}
+
+	if !tx.Approve && len(tx.Comment) == 0 {
+		return NewDAOError(ErrInvalidProposal, "disputing work needs a comment", nil)
+	}
+
+	return nil
+}
+
+// ValidateBountyCancelTx validates a bounty cancellation
+func (v *DAOValidator) ValidateBountyCancelTx(tx *BountyCancelTx, canceller crypto.PublicKey) error {
+	balance, exists := v.tokenState.Balances.Lookup(canceller.String())
+	if !exists || balance < uint64(tx.Fee) {
+		return NewDAOError(ErrInsufficientTokens, "insufficient tokens for cancellation fee", nil)
+	}
+
+	return nil
+}
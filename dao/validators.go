@@ -0,0 +1,378 @@
+package dao
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// FeeCollector is implemented by state machines that share the fees of a
+// block with its producer. Fee sharing may only depend on the block and
+// on-chain state, so that every node keeps committing the same state root.
+type FeeCollector interface {
+	CollectBlockFees(producer crypto.PublicKey, fees uint64, height uint32)
+}
+
+// ValidatorSetSource is implemented by state machines that govern which
+// validators finalize blocks and with how much voting power
+type ValidatorSetSource interface {
+	ValidatorSet() []ValidatorPower
+}
+
+// ValidatorPower is the finality voting power of an active validator
+type ValidatorPower struct {
+	Address crypto.PublicKey
+	Power   uint64 // Stake delegated to the validator's pool
+}
+
+var (
+	_ FeeCollector       = (*DAO)(nil)
+	_ ValidatorSetSource = (*DAO)(nil)
+)
+
+// TxFee returns the fee a DAO transaction charges its sender. Treasury
+// transactions are authorized by signers rather than a sender and pay no fee.
+func TxFee(txInner interface{}) uint64 {
+	switch txInner.(type) {
+	case *TreasuryTx, TreasuryTx:
+		return 0
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(txInner))
+	if value.Kind() != reflect.Struct {
+		return 0
+	}
+
+	fee := value.FieldByName("Fee")
+	if !fee.IsValid() || fee.Kind() != reflect.Int64 || fee.Int() < 0 {
+		return 0
+	}
+
+	return uint64(fee.Int())
+}
+
+// ValidatorInfo holds the commission settings and fee revenue of a block producer
+type ValidatorInfo struct {
+	Address              crypto.PublicKey
+	PoolID               string // Staking pool delegators stake into
+	CommissionBps        uint64 // Share of fee revenue kept by the validator
+	LastCommissionChange int64
+	BlocksProduced       uint64
+	FeesEarned           uint64 // Total fee revenue of produced blocks
+	CommissionEarned     uint64 // Total revenue kept as commission
+	DelegatorRewards     uint64 // Total revenue shared with delegators
+	ClaimableCommission  uint64
+	RegisteredAt         int64
+	Active               bool // Admitted to the finality validator set by governance
+}
+
+// ValidatorSetChange is the change a validator set proposal applies once passed
+type ValidatorSetChange struct {
+	Validator crypto.PublicKey
+	Remove    bool
+}
+
+// Proposal returns the governance proposal voting on the validator set change
+func (tx *ValidatorSetProposalTx) Proposal() *ProposalTx {
+	title := "Add validator " + tx.Validator.String()
+	if tx.Remove {
+		title = "Remove validator " + tx.Validator.String()
+	}
+
+	description := tx.Description
+	if description == "" {
+		description = title
+	}
+
+	return &ProposalTx{
+		Fee:          tx.Fee,
+		Title:        title,
+		Description:  description,
+		ProposalType: ProposalTypeTechnical,
+		VotingType:   tx.VotingType,
+		StartTime:    tx.StartTime,
+		EndTime:      tx.EndTime,
+		Threshold:    tx.Threshold,
+	}
+}
+
+// ValidatorSummary is the staking analytics view of a validator
+type ValidatorSummary struct {
+	Address          string `json:"address"`
+	PoolID           string `json:"pool_id"`
+	CommissionBps    uint64 `json:"commission_bps"`
+	FeesEarned       uint64 `json:"fees_earned"`
+	DelegatorRewards uint64 `json:"delegator_rewards"`
+	Active           bool   `json:"active"`
+}
+
+// Summary returns the staking analytics view of the validator
+func (v *ValidatorInfo) Summary() ValidatorSummary {
+	return ValidatorSummary{
+		Address:          v.Address.String(),
+		PoolID:           v.PoolID,
+		CommissionBps:    v.CommissionBps,
+		FeesEarned:       v.FeesEarned,
+		DelegatorRewards: v.DelegatorRewards,
+		Active:           v.Active,
+	}
+}
+
+// ValidatorManager registers validators and splits block fee revenue between
+// validators and the delegators staking in their pools
+type ValidatorManager struct {
+	governanceState   *GovernanceState
+	tokenState        *GovernanceToken
+	tokenomicsManager *TokenomicsManager
+	parameterManager  *ParameterManager
+	validators        map[string]*ValidatorInfo
+	setChanges        map[types.Hash]*ValidatorSetChange
+}
+
+// NewValidatorManager creates a new validator manager
+func NewValidatorManager(governanceState *GovernanceState, tokenState *GovernanceToken, tokenomicsManager *TokenomicsManager, parameterManager *ParameterManager) *ValidatorManager {
+	return &ValidatorManager{
+		governanceState:   governanceState,
+		tokenState:        tokenState,
+		tokenomicsManager: tokenomicsManager,
+		parameterManager:  parameterManager,
+		validators:        make(map[string]*ValidatorInfo),
+		setChanges:        make(map[types.Hash]*ValidatorSetChange),
+	}
+}
+
+// ProcessValidatorConfigTx registers a validator or updates its commission
+// within the governance-set bounds
+func (vm *ValidatorManager) ProcessValidatorConfigTx(tx *ValidatorConfigTx, from crypto.PublicKey) error {
+	config := vm.parameterManager.GetParameterConfig()
+	if tx.CommissionBps < config.ValidatorMinCommission || tx.CommissionBps > config.ValidatorMaxCommission {
+		return NewDAOError(ErrInvalidProposal, "commission outside governance bounds", map[string]interface{}{
+			"commission": tx.CommissionBps,
+			"min":        config.ValidatorMinCommission,
+			"max":        config.ValidatorMaxCommission,
+		})
+	}
+
+	if _, exists := vm.tokenomicsManager.GetStakingPool(tx.PoolID); !exists {
+		return NewDAOError(ErrInvalidProposal, "staking pool not found", map[string]interface{}{"pool_id": tx.PoolID})
+	}
+
+	fromStr := from.String()
+	for address, other := range vm.validators {
+		if address != fromStr && other.PoolID == tx.PoolID {
+			return NewDAOError(ErrInvalidProposal, "staking pool already belongs to another validator", map[string]interface{}{"pool_id": tx.PoolID})
+		}
+	}
+
+	now := time.Now().Unix()
+	validator, exists := vm.validators[fromStr]
+	if exists && tx.CommissionBps != validator.CommissionBps {
+		if now < validator.LastCommissionChange+config.ValidatorCommissionCooldown {
+			return NewDAOError(ErrInvalidProposal, "commission changed too recently", map[string]interface{}{
+				"next_change": validator.LastCommissionChange + config.ValidatorCommissionCooldown,
+			})
+		}
+
+		change := tx.CommissionBps - validator.CommissionBps
+		if tx.CommissionBps < validator.CommissionBps {
+			change = validator.CommissionBps - tx.CommissionBps
+		}
+		if change > config.ValidatorCommissionMaxChange {
+			return NewDAOError(ErrInvalidProposal, "commission change exceeds governance limit", map[string]interface{}{
+				"change": change,
+				"max":    config.ValidatorCommissionMaxChange,
+			})
+		}
+	}
+
+	vm.tokenState.Balances.Sub(fromStr, uint64(tx.Fee))
+
+	if !exists {
+		vm.validators[fromStr] = &ValidatorInfo{
+			Address:              from,
+			PoolID:               tx.PoolID,
+			CommissionBps:        tx.CommissionBps,
+			LastCommissionChange: now,
+			RegisteredAt:         now,
+		}
+		return nil
+	}
+
+	if tx.CommissionBps != validator.CommissionBps {
+		validator.CommissionBps = tx.CommissionBps
+		validator.LastCommissionChange = now
+	}
+	validator.PoolID = tx.PoolID
+
+	return nil
+}
+
+// ProcessClaimCommissionTx pays out a validator's accrued commission
+func (vm *ValidatorManager) ProcessClaimCommissionTx(tx *ClaimCommissionTx, from crypto.PublicKey) error {
+	validator, exists := vm.validators[from.String()]
+	if !exists {
+		return NewDAOError(ErrUnauthorized, "address is not a registered validator", nil)
+	}
+
+	if validator.ClaimableCommission == 0 {
+		return NewDAOError(ErrInvalidProposal, "no commission to claim", nil)
+	}
+
+	// Commission is backed by fees already paid, so nothing is minted
+	fromStr := from.String()
+	vm.tokenState.Balances.Sub(fromStr, uint64(tx.Fee))
+	vm.tokenState.Balances.Add(fromStr, validator.ClaimableCommission)
+
+	if holder, exists := vm.governanceState.TokenHolders[fromStr]; exists {
+		holder.Balance += validator.ClaimableCommission
+		holder.LastActive = time.Now().Unix()
+	}
+	validator.ClaimableCommission = 0
+
+	return nil
+}
+
+// CollectBlockFees splits the fees of a block between its producer and the
+// delegators of the producer's pool. Fees of unregistered producers stay burned.
+func (vm *ValidatorManager) CollectBlockFees(producer crypto.PublicKey, fees uint64) {
+	validator, exists := vm.validators[producer.String()]
+	if !exists {
+		return
+	}
+
+	validator.BlocksProduced++
+	if fees == 0 {
+		return
+	}
+
+	validator.FeesEarned += fees
+	commission := fees * validator.CommissionBps / 10000
+
+	shared := uint64(0)
+	if pool, exists := vm.tokenomicsManager.GetStakingPool(validator.PoolID); exists && pool.TotalStaked > 0 {
+		remainder := fees - commission
+		for _, stakerInfo := range pool.Stakers {
+			share := remainder * stakerInfo.StakedAmount / pool.TotalStaked
+			stakerInfo.FeeRewards += share
+			shared += share
+		}
+		pool.FeeRewards += shared
+	}
+
+	// Rounding dust and revenue of empty pools go to the validator
+	validator.DelegatorRewards += shared
+	validator.CommissionEarned += fees - shared
+	validator.ClaimableCommission += fees - shared
+}
+
+// CheckValidatorSetChange reports whether a validator can be added to or
+// removed from the validator set
+func (vm *ValidatorManager) CheckValidatorSetChange(address crypto.PublicKey, remove bool) error {
+	validator, exists := vm.validators[address.String()]
+	if !exists {
+		return NewDAOError(ErrInvalidProposal, "address is not a registered validator", nil)
+	}
+
+	if remove && !validator.Active {
+		return NewDAOError(ErrInvalidProposal, "validator is not in the validator set", nil)
+	}
+	if !remove && validator.Active {
+		return NewDAOError(ErrInvalidProposal, "validator is already in the validator set", nil)
+	}
+
+	return nil
+}
+
+// RecordValidatorSetChange remembers the change a validator set proposal
+// applies once it passes
+func (vm *ValidatorManager) RecordValidatorSetChange(proposalID types.Hash, tx *ValidatorSetProposalTx) {
+	vm.setChanges[proposalID] = &ValidatorSetChange{
+		Validator: tx.Validator,
+		Remove:    tx.Remove,
+	}
+}
+
+// ProcessValidatorSetExecuteTx applies the validator set change of a passed proposal
+func (vm *ValidatorManager) ProcessValidatorSetExecuteTx(tx *ValidatorSetExecuteTx, executor crypto.PublicKey) error {
+	change, exists := vm.setChanges[tx.ProposalID]
+	if !exists {
+		return NewDAOError(ErrInvalidProposal, "proposal does not change the validator set", nil)
+	}
+
+	proposal, exists := vm.governanceState.Proposal(tx.ProposalID)
+	if !exists {
+		return ErrProposalNotFoundError
+	}
+	if proposal.Status != ProposalStatusPassed {
+		return NewDAOError(ErrInvalidProposal, "proposal has not passed", nil)
+	}
+
+	// The validator may have been changed by another proposal meanwhile
+	if err := vm.CheckValidatorSetChange(change.Validator, change.Remove); err != nil {
+		return err
+	}
+
+	vm.tokenState.Balances.Sub(executor.String(), uint64(tx.Fee))
+	vm.validators[change.Validator.String()].Active = !change.Remove
+	proposal.Status = ProposalStatusExecuted
+	delete(vm.setChanges, tx.ProposalID)
+
+	return nil
+}
+
+// GetValidatorSetChange returns the validator set change of a proposal
+func (vm *ValidatorManager) GetValidatorSetChange(proposalID types.Hash) (*ValidatorSetChange, bool) {
+	change, exists := vm.setChanges[proposalID]
+	return change, exists
+}
+
+// ValidatorSet returns the active validators weighted by the stake delegated
+// to their pools, ordered by address. Validators without stake have no
+// voting power and are left out.
+func (vm *ValidatorManager) ValidatorSet() []ValidatorPower {
+	set := []ValidatorPower{}
+	for _, validator := range vm.validators {
+		if !validator.Active {
+			continue
+		}
+
+		pool, exists := vm.tokenomicsManager.GetStakingPool(validator.PoolID)
+		if !exists || pool.TotalStaked == 0 {
+			continue
+		}
+
+		set = append(set, ValidatorPower{Address: validator.Address, Power: pool.TotalStaked})
+	}
+
+	sort.Slice(set, func(i, j int) bool {
+		return set[i].Address.String() < set[j].Address.String()
+	})
+
+	return set
+}
+
+// GetValidator returns a registered validator
+func (vm *ValidatorManager) GetValidator(address crypto.PublicKey) (*ValidatorInfo, bool) {
+	validator, exists := vm.validators[address.String()]
+	return validator, exists
+}
+
+// ListValidators returns all registered validators ordered by fee revenue
+func (vm *ValidatorManager) ListValidators() []*ValidatorInfo {
+	validators := make([]*ValidatorInfo, 0, len(vm.validators))
+	for _, validator := range vm.validators {
+		validators = append(validators, validator)
+	}
+
+	sort.Slice(validators, func(i, j int) bool {
+		if validators[i].FeesEarned != validators[j].FeesEarned {
+			return validators[i].FeesEarned > validators[j].FeesEarned
+		}
+		return validators[i].Address.String() < validators[j].Address.String()
+	})
+
+	return validators
+}
@@ -0,0 +1,185 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupValidatorDAO(t *testing.T) (*DAO, crypto.PublicKey, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	validator := crypto.GeneratePrivateKey().PublicKey()
+	alice := crypto.GeneratePrivateKey().PublicKey()
+	bob := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		validator.String(): 10000,
+		alice.String():     10000,
+		bob.String():       10000,
+	}))
+	require.NoError(t, dao.TokenomicsManager.CreateStakingPool("validator-pool", "Validator Pool", 0, 1, 0))
+
+	return dao, validator, alice, bob
+}
+
+func TestTxFee(t *testing.T) {
+	assert.Equal(t, uint64(25), TxFee(&VoteTx{Fee: 25}))
+	assert.Equal(t, uint64(25), TxFee(VoteTx{Fee: 25}))
+	assert.Equal(t, uint64(0), TxFee(&TreasuryTx{Fee: 25}))
+	assert.Equal(t, uint64(0), TxFee(&TokenTransferTx{Fee: -1}))
+	assert.Equal(t, uint64(0), TxFee("not a transaction"))
+}
+
+func TestValidator_CommissionBounds(t *testing.T) {
+	dao, validator, _, _ := setupValidatorDAO(t)
+	config := dao.GetParameterConfig()
+
+	// Above the governance maximum
+	tx := &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: config.ValidatorMaxCommission + 1}
+	require.Error(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x01}))
+
+	// Unknown pools are rejected
+	tx = &ValidatorConfigTx{Fee: 10, PoolID: "missing", CommissionBps: 1000}
+	require.Error(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x02}))
+
+	tx = &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x03}))
+	info, exists := dao.GetValidator(validator)
+	require.True(t, exists)
+	assert.Equal(t, uint64(1000), info.CommissionBps)
+	assert.Equal(t, uint64(9990), dao.GetTokenBalance(validator))
+
+	// Changes are rate limited by the cooldown
+	tx = &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1200}
+	require.Error(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x04}))
+
+	// And by the maximum step once the cooldown has passed
+	info.LastCommissionChange -= config.ValidatorCommissionCooldown
+	tx = &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000 + config.ValidatorCommissionMaxChange + 1}
+	require.Error(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x05}))
+
+	tx = &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1200}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x06}))
+	assert.Equal(t, uint64(1200), info.CommissionBps)
+
+	// A pool belongs to a single validator
+	other := crypto.GeneratePrivateKey().PublicKey()
+	dao.TokenState.Balances.Set(other.String(), 100)
+	tx = &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000}
+	require.Error(t, dao.ProcessDAOTransaction(tx, other, types.Hash{0x07}))
+}
+
+func TestValidator_FeeSharingAndClaims(t *testing.T) {
+	dao, validator, alice, bob := setupValidatorDAO(t)
+
+	tx := &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x01}))
+
+	// Without delegators the validator keeps all fees
+	dao.CollectBlockFees(validator, 100, 1)
+	info, _ := dao.GetValidator(validator)
+	assert.Equal(t, uint64(100), info.ClaimableCommission)
+
+	require.NoError(t, dao.TokenomicsManager.StakeTokens("validator-pool", alice, 3000, 0))
+	require.NoError(t, dao.TokenomicsManager.StakeTokens("validator-pool", bob, 1000, 0))
+
+	dao.CollectBlockFees(validator, 1000, 2)
+	assert.Equal(t, uint64(2), info.BlocksProduced)
+	assert.Equal(t, uint64(1100), info.FeesEarned)
+	assert.Equal(t, uint64(900), info.DelegatorRewards)
+	assert.Equal(t, uint64(200), info.ClaimableCommission)
+
+	aliceInfo, _ := dao.TokenomicsManager.GetStakerInfo("validator-pool", alice)
+	bobInfo, _ := dao.TokenomicsManager.GetStakerInfo("validator-pool", bob)
+	assert.Equal(t, uint64(675), aliceInfo.FeeRewards)
+	assert.Equal(t, uint64(225), bobInfo.FeeRewards)
+
+	// Fees of unregistered producers are not credited to anyone
+	dao.CollectBlockFees(alice, 1000, 3)
+	assert.Equal(t, uint64(675), aliceInfo.FeeRewards)
+
+	// Delegators receive their share when claiming staking rewards
+	claimed, err := dao.TokenomicsManager.ClaimStakingRewards("validator-pool", bob)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(225), claimed)
+	assert.Equal(t, uint64(9000+225), dao.GetTokenBalance(bob))
+
+	// Validators claim their commission
+	require.NoError(t, dao.ProcessDAOTransaction(&ClaimCommissionTx{Fee: 10}, validator, types.Hash{0x02}))
+	assert.Equal(t, uint64(9990-10+200), dao.GetTokenBalance(validator))
+	assert.Equal(t, uint64(0), info.ClaimableCommission)
+	require.Error(t, dao.ProcessDAOTransaction(&ClaimCommissionTx{Fee: 10}, validator, types.Hash{0x03}))
+
+	err = dao.ProcessDAOTransaction(&ClaimCommissionTx{Fee: 10}, alice, types.Hash{0x04})
+	require.Error(t, err)
+	assert.Equal(t, ErrUnauthorized, err.(*DAOError).Code)
+
+	metrics := dao.GetStakingYieldMetrics()
+	require.Len(t, metrics.Validators, 1)
+	assert.Equal(t, uint64(900), metrics.Validators[0].DelegatorRewards)
+	require.Len(t, metrics.Pools, 1)
+	assert.Equal(t, uint64(900), metrics.Pools[0].FeeRewards)
+}
+
+func TestValidator_SetGovernance(t *testing.T) {
+	dao, validator, alice, _ := setupValidatorDAO(t)
+	now := time.Now().Unix()
+
+	propose := func(remove bool, txHash types.Hash) error {
+		tx := &ValidatorSetProposalTx{
+			Fee:        200,
+			Validator:  validator,
+			Remove:     remove,
+			VotingType: VotingTypeSimple,
+			StartTime:  now + 60,
+			EndTime:    now + 7*86400,
+			Threshold:  5100,
+		}
+		return dao.ProcessDAOTransaction(tx, alice, txHash)
+	}
+
+	// Only registered validators can join the set
+	require.Error(t, propose(false, types.Hash{0x01}))
+
+	tx := &ValidatorConfigTx{Fee: 10, PoolID: "validator-pool", CommissionBps: 1000}
+	require.NoError(t, dao.ProcessDAOTransaction(tx, validator, types.Hash{0x02}))
+
+	// Inactive validators cannot be removed
+	require.Error(t, propose(true, types.Hash{0x03}))
+	require.NoError(t, propose(false, types.Hash{0x04}))
+
+	change, exists := dao.GetValidatorSetChange(types.Hash{0x04})
+	require.True(t, exists)
+	assert.False(t, change.Remove)
+
+	// Proposals must pass before they are executed
+	execute := &ValidatorSetExecuteTx{Fee: 100, ProposalID: types.Hash{0x04}}
+	require.Error(t, dao.ProcessDAOTransaction(execute, alice, types.Hash{0x05}))
+
+	proposal, err := dao.GetProposal(types.Hash{0x04})
+	require.NoError(t, err)
+	proposal.Status = ProposalStatusPassed
+
+	require.NoError(t, dao.ProcessDAOTransaction(execute, alice, types.Hash{0x06}))
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+
+	info, _ := dao.GetValidator(validator)
+	assert.True(t, info.Active)
+
+	// Validators without stake have no power
+	assert.Empty(t, dao.ValidatorSet())
+
+	require.NoError(t, dao.TokenomicsManager.StakeTokens("validator-pool", alice, 3000, 0))
+	set := dao.ValidatorSet()
+	require.Len(t, set, 1)
+	assert.Equal(t, validator, set[0].Address)
+	assert.Equal(t, uint64(3000), set[0].Power)
+
+	// A change is applied once
+	require.Error(t, dao.ProcessDAOTransaction(execute, alice, types.Hash{0x07}))
+	require.Error(t, propose(false, types.Hash{0x08}))
+}
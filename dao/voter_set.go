@@ -0,0 +1,224 @@
+package dao
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// voterArrayMax is the most member indexes a voter set container keeps as a
+// sorted array, past it a bitmap of the 65536 possible indexes is smaller
+const voterArrayMax = 4096
+
+// MemberIndex numbers addresses densely in the order they are first seen,
+// so sets of members can be kept as bitmaps of their indexes
+type MemberIndex struct {
+	indexes   map[string]uint32
+	addresses []string
+}
+
+// NewMemberIndex creates an empty member index
+func NewMemberIndex() *MemberIndex {
+	return &MemberIndex{indexes: make(map[string]uint32)}
+}
+
+// Index returns the index of address, assigning the next one if it has none
+func (mi *MemberIndex) Index(address string) uint32 {
+	if index, exists := mi.indexes[address]; exists {
+		return index
+	}
+	index := uint32(len(mi.addresses))
+	mi.indexes[address] = index
+	mi.addresses = append(mi.addresses, address)
+	return index
+}
+
+// Lookup returns the index of address, if it has one
+func (mi *MemberIndex) Lookup(address string) (uint32, bool) {
+	if mi == nil {
+		return 0, false
+	}
+	index, exists := mi.indexes[address]
+	return index, exists
+}
+
+// Address returns the address at index
+func (mi *MemberIndex) Address(index uint32) (string, bool) {
+	if int(index) >= len(mi.addresses) {
+		return "", false
+	}
+	return mi.addresses[index], true
+}
+
+// Len returns the number of indexed addresses
+func (mi *MemberIndex) Len() int {
+	return len(mi.addresses)
+}
+
+// VoterSet is a compressed set of member indexes in the layout of a roaring
+// bitmap. Indexes are split by their high 16 bits into containers holding
+// the low bits, as a sorted array while sparse and as a bitmap once dense,
+// so a set costs about two bytes per voter at most.
+type VoterSet struct {
+	keys       []uint16 // High bits of each container, sorted
+	containers []*voterContainer
+	count      int
+}
+
+type voterContainer struct {
+	array  []uint16 // Sorted low bits while sparse
+	bitmap []uint64 // 1024 words once dense, array is nil then
+}
+
+// NewVoterSet creates an empty voter set
+func NewVoterSet() *VoterSet {
+	return &VoterSet{}
+}
+
+// container returns the position of the container for high and whether it
+// exists
+func (vs *VoterSet) container(high uint16) (int, bool) {
+	i := sort.Search(len(vs.keys), func(i int) bool { return vs.keys[i] >= high })
+	return i, i < len(vs.keys) && vs.keys[i] == high
+}
+
+// Add puts index in the set and reports whether it was new
+func (vs *VoterSet) Add(index uint32) bool {
+	high, low := uint16(index>>16), uint16(index)
+	i, exists := vs.container(high)
+	if !exists {
+		vs.keys = append(vs.keys, 0)
+		copy(vs.keys[i+1:], vs.keys[i:])
+		vs.keys[i] = high
+		vs.containers = append(vs.containers, nil)
+		copy(vs.containers[i+1:], vs.containers[i:])
+		vs.containers[i] = &voterContainer{}
+	}
+
+	if !vs.containers[i].add(low) {
+		return false
+	}
+	vs.count++
+	return true
+}
+
+// Contains reports whether index is in the set
+func (vs *VoterSet) Contains(index uint32) bool {
+	if vs == nil {
+		return false
+	}
+	i, exists := vs.container(uint16(index >> 16))
+	return exists && vs.containers[i].contains(uint16(index))
+}
+
+// Len returns the number of indexes in the set
+func (vs *VoterSet) Len() int {
+	if vs == nil {
+		return 0
+	}
+	return vs.count
+}
+
+// Each calls fn with every index of the set in ascending order
+func (vs *VoterSet) Each(fn func(index uint32)) {
+	if vs == nil {
+		return
+	}
+	for i, c := range vs.containers {
+		high := uint32(vs.keys[i]) << 16
+		if c.bitmap == nil {
+			for _, low := range c.array {
+				fn(high | uint32(low))
+			}
+			continue
+		}
+		for w, word := range c.bitmap {
+			for word != 0 {
+				fn(high | uint32(w*64+bits.TrailingZeros64(word)))
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// SizeBytes returns the approximate memory the set's contents take
+func (vs *VoterSet) SizeBytes() int {
+	if vs == nil {
+		return 0
+	}
+	size := len(vs.keys) * 2
+	for _, c := range vs.containers {
+		size += len(c.array)*2 + len(c.bitmap)*8
+	}
+	return size
+}
+
+func (c *voterContainer) contains(low uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[low/64]&(1<<(low%64)) != 0
+	}
+	i := sort.Search(len(c.array), func(i int) bool { return c.array[i] >= low })
+	return i < len(c.array) && c.array[i] == low
+}
+
+func (c *voterContainer) add(low uint16) bool {
+	if c.bitmap != nil {
+		word, bit := low/64, uint64(1)<<(low%64)
+		if c.bitmap[word]&bit != 0 {
+			return false
+		}
+		c.bitmap[word] |= bit
+		return true
+	}
+
+	i := sort.Search(len(c.array), func(i int) bool { return c.array[i] >= low })
+	if i < len(c.array) && c.array[i] == low {
+		return false
+	}
+	if len(c.array) < voterArrayMax {
+		c.array = append(c.array, 0)
+		copy(c.array[i+1:], c.array[i:])
+		c.array[i] = low
+		return true
+	}
+
+	// Dense containers switch to a bitmap
+	c.bitmap = make([]uint64, 1<<16/64)
+	for _, existing := range c.array {
+		c.bitmap[existing/64] |= 1 << (existing % 64)
+	}
+	c.array = nil
+	c.bitmap[low/64] |= 1 << (low % 64)
+	return true
+}
+
+// RecordVoter marks voter as having voted on a proposal
+func (gs *GovernanceState) RecordVoter(proposalID types.Hash, voter string) {
+	if gs.Members == nil {
+		gs.Members = NewMemberIndex()
+	}
+	if gs.Voters == nil {
+		gs.Voters = make(map[types.Hash]*VoterSet)
+	}
+
+	voters, exists := gs.Voters[proposalID]
+	if !exists {
+		voters = NewVoterSet()
+		gs.Voters[proposalID] = voters
+	}
+	voters.Add(gs.Members.Index(voter))
+}
+
+// HasVoted reports whether voter voted on a proposal, without touching the
+// proposal's vote records
+func (gs *GovernanceState) HasVoted(proposalID types.Hash, voter string) bool {
+	index, exists := gs.Members.Lookup(voter)
+	return exists && gs.Voters[proposalID].Contains(index)
+}
+
+// ResetVotes starts an empty vote record and voter set for a proposal
+func (gs *GovernanceState) ResetVotes(proposalID types.Hash) {
+	gs.Votes[proposalID] = make(map[string]*Vote)
+	delete(gs.Voters, proposalID)
+}
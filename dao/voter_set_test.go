@@ -0,0 +1,134 @@
+package dao
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemberIndex(t *testing.T) {
+	members := NewMemberIndex()
+
+	assert.Equal(t, uint32(0), members.Index("alice"))
+	assert.Equal(t, uint32(1), members.Index("bob"))
+	assert.Equal(t, uint32(0), members.Index("alice"))
+	assert.Equal(t, 2, members.Len())
+
+	index, exists := members.Lookup("bob")
+	assert.True(t, exists)
+	assert.Equal(t, uint32(1), index)
+	_, exists = members.Lookup("carol")
+	assert.False(t, exists)
+
+	address, exists := members.Address(1)
+	assert.True(t, exists)
+	assert.Equal(t, "bob", address)
+	_, exists = members.Address(2)
+	assert.False(t, exists)
+}
+
+func TestVoterSet(t *testing.T) {
+	voters := NewVoterSet()
+
+	assert.True(t, voters.Add(7))
+	assert.False(t, voters.Add(7))
+	assert.True(t, voters.Add(1<<20|3))
+	assert.True(t, voters.Add(2))
+	assert.True(t, voters.Contains(7))
+	assert.True(t, voters.Contains(1<<20|3))
+	assert.False(t, voters.Contains(3))
+	assert.False(t, voters.Contains(1<<20|7))
+	assert.Equal(t, 3, voters.Len())
+
+	var seen []uint32
+	voters.Each(func(index uint32) { seen = append(seen, index) })
+	assert.Equal(t, []uint32{2, 7, 1<<20 | 3}, seen)
+
+	var empty *VoterSet
+	assert.False(t, empty.Contains(7))
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestVoterSetDense(t *testing.T) {
+	voters := NewVoterSet()
+
+	// Every other index, past the point the container becomes a bitmap
+	for i := uint32(0); i < 2*voterArrayMax+2; i += 2 {
+		require.True(t, voters.Add(i))
+	}
+	assert.Equal(t, voterArrayMax+1, voters.Len())
+	assert.Equal(t, 2+8192, voters.SizeBytes())
+
+	for i := uint32(0); i < 2*voterArrayMax+2; i++ {
+		require.Equal(t, i%2 == 0, voters.Contains(i), "index %d", i)
+	}
+	assert.False(t, voters.Add(2*voterArrayMax))
+
+	count := 0
+	last := int64(-1)
+	voters.Each(func(index uint32) {
+		assert.Greater(t, int64(index), last)
+		last = int64(index)
+		count++
+	})
+	assert.Equal(t, voters.Len(), count)
+}
+
+func TestGovernanceState_VoterSets(t *testing.T) {
+	gs := NewGovernanceState()
+	proposalID := types.Hash{1}
+	gs.Proposals[proposalID] = &Proposal{ID: proposalID, Status: ProposalStatusPassed, EndTime: 10}
+
+	gs.ResetVotes(proposalID)
+	gs.Votes[proposalID]["alice"] = &Vote{Choice: VoteChoiceYes, Weight: 5}
+	gs.RecordVoter(proposalID, "alice")
+
+	assert.True(t, gs.HasVoted(proposalID, "alice"))
+	assert.False(t, gs.HasVoted(proposalID, "bob"))
+	assert.False(t, gs.HasVoted(types.Hash{2}, "alice"))
+
+	// Pruned vote records still count as votes
+	assert.Equal(t, 1, gs.PruneFinalizedVotes(20))
+	assert.True(t, gs.HasVoted(proposalID, "alice"))
+
+	validator := NewDAOValidator(gs, NewGovernanceToken("TEST", "Test", 18))
+	err := validator.validateNoDuplicateVote(proposalID, "alice")
+	require.Error(t, err)
+	assert.Equal(t, ErrDuplicateVote, err.(*DAOError).Code)
+	assert.NoError(t, validator.validateNoDuplicateVote(proposalID, "bob"))
+
+	gs.ResetVotes(proposalID)
+	assert.False(t, gs.HasVoted(proposalID, "alice"))
+}
+
+func BenchmarkVoterSetContains(b *testing.B) {
+	voters := NewVoterSet()
+	for i := uint32(0); i < largeHolderCount; i++ {
+		voters.Add(i)
+	}
+	b.ReportMetric(float64(voters.SizeBytes()), "set-bytes")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		voters.Contains(uint32(i % largeHolderCount))
+	}
+}
+
+func BenchmarkVoteMapContains(b *testing.B) {
+	votes := make(map[string]*Vote, largeHolderCount)
+	for i := 0; i < largeHolderCount; i++ {
+		votes[fmt.Sprintf("voter-%d", i)] = &Vote{}
+	}
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("voter-%d", i*977)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = votes[keys[i%len(keys)]]
+	}
+}
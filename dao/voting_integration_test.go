@@ -177,7 +177,7 @@ func testQuadraticVotingScenario(t *testing.T, dao *DAO, founder, developer1, co
 	}
 
 	for _, vote := range votes {
-		initialBalance := dao.TokenState.Balances[vote.voter.String()]
+		initialBalance := dao.TokenState.Balances.Get(vote.voter.String())
 
 		voteTx := &VoteTx{
 			Fee:        100,
@@ -194,7 +194,7 @@ func testQuadraticVotingScenario(t *testing.T, dao *DAO, founder, developer1, co
 
 		// Verify cost was deducted correctly
 		expectedBalance := initialBalance - vote.expectedCost - 100 // cost + fee
-		actualBalance := dao.TokenState.Balances[vote.voter.String()]
+		actualBalance := dao.TokenState.Balances.Get(vote.voter.String())
 		if actualBalance != expectedBalance {
 			t.Errorf("Expected balance %d after quadratic vote, got %d", expectedBalance, actualBalance)
 		}
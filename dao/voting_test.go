@@ -126,7 +126,7 @@ func TestQuadraticVoting(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		initialBalance := dao.TokenState.Balances[tc.voter.String()]
+		initialBalance := dao.TokenState.Balances.Get(tc.voter.String())
 
 		voteTx := &VoteTx{
 			Fee:        100,
@@ -144,7 +144,7 @@ func TestQuadraticVoting(t *testing.T) {
 		} else if !tc.shouldFail {
 			// Verify cost was deducted correctly
 			expectedBalance := initialBalance - tc.expectedCost - 100 // cost + fee
-			actualBalance := dao.TokenState.Balances[tc.voter.String()]
+			actualBalance := dao.TokenState.Balances.Get(tc.voter.String())
 			if actualBalance != expectedBalance {
 				t.Errorf("Expected balance %d after %s, got %d", expectedBalance, tc.reason, actualBalance)
 			}
@@ -349,6 +349,60 @@ func GenerateTestWallet() (crypto.PrivateKey, crypto.PublicKey, types.Address, e
 	return privateKey, publicKey, address, nil
 }
 
+// GenerateTestWalletFromMnemonic derives the test wallet at account index
+// m/44'/7331'/0'/0/index of a mnemonic, so the same mnemonic always gives
+// the same wallets
+func GenerateTestWalletFromMnemonic(mnemonic, passphrase string, index uint32) (crypto.PrivateKey, crypto.PublicKey, types.Address, error) {
+	privateKey, err := crypto.DeriveAccountFromMnemonic(mnemonic, passphrase, crypto.BockCoinType, index)
+	if err != nil {
+		return crypto.PrivateKey{}, nil, types.Address{}, err
+	}
+
+	publicKey := privateKey.PublicKey()
+	return privateKey, publicKey, publicKey.Address(), nil
+}
+
+// DerivedAccount is an account derived from a mnemonic
+type DerivedAccount struct {
+	Index     uint32 `json:"index"`
+	Path      string `json:"path"`
+	PublicKey string `json:"public_key"`
+	Address   string `json:"address"`
+}
+
+// DeriveAccounts derives count consecutive accounts of a mnemonic starting
+// at index start, without exposing their private keys
+func DeriveAccounts(mnemonic, passphrase string, start, count uint32) ([]DerivedAccount, error) {
+	seed, err := crypto.MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := crypto.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]DerivedAccount, 0, count)
+	for index := start; index < start+count; index++ {
+		path := crypto.AccountPath(crypto.BockCoinType, index)
+		key, err := master.DerivePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey := key.PrivateKey().PublicKey()
+		accounts = append(accounts, DerivedAccount{
+			Index:     index,
+			Path:      path,
+			PublicKey: publicKey.String(),
+			Address:   publicKey.Address().String(),
+		})
+	}
+
+	return accounts, nil
+}
+
 // WalletConnectionManager manages multiple wallet connections
 type WalletConnectionManager struct {
 	service *WalletIntegrationService
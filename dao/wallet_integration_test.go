@@ -468,3 +468,35 @@ func BenchmarkTransactionSigning(b *testing.B) {
 
 	_ = publicKey
 }
+
+func TestGenerateTestWalletFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	privateKey, publicKey, address, err := GenerateTestWalletFromMnemonic(mnemonic, "", 0)
+	if err != nil {
+		t.Fatalf("Failed to derive test wallet: %v", err)
+	}
+	if privateKey.PublicKey().String() != publicKey.String() || publicKey.Address() != address {
+		t.Error("Derived keys and address don't match")
+	}
+
+	_, _, again, err := GenerateTestWalletFromMnemonic(mnemonic, "", 0)
+	if err != nil || again != address {
+		t.Error("Derivation is not deterministic")
+	}
+
+	accounts, err := DeriveAccounts(mnemonic, "", 0, 3)
+	if err != nil {
+		t.Fatalf("Failed to derive accounts: %v", err)
+	}
+	if len(accounts) != 3 || accounts[0].Address != address.String() || accounts[1].Address == accounts[0].Address {
+		t.Errorf("Unexpected derived accounts: %+v", accounts)
+	}
+	if accounts[2].Path != "m/44'/7331'/0'/0/2" {
+		t.Errorf("Unexpected derivation path %s", accounts[2].Path)
+	}
+
+	if _, _, _, err := GenerateTestWalletFromMnemonic("not a mnemonic", "", 0); err == nil {
+		t.Error("Expected invalid mnemonic to fail")
+	}
+}
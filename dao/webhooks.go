@@ -0,0 +1,421 @@
+package dao
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook request headers
+const (
+	WebhookSignatureHeader = "X-Bock-Signature"
+	WebhookEventHeader     = "X-Bock-Event"
+	WebhookDeliveryHeader  = "X-Bock-Delivery"
+)
+
+// WebhookPingEvent is the event type of test deliveries
+const WebhookPingEvent = "ping"
+
+// maxWebhookDeliveryLog bounds the delivery log kept per subscription
+const maxWebhookDeliveryLog = 100
+
+// WebhookConfig configures webhook delivery
+type WebhookConfig struct {
+	MaxAttempts    int           // Attempts per event, 6 when zero
+	InitialBackoff time.Duration // Delay before the first retry, doubled after each, 1s when zero
+	MaxBackoff     time.Duration // Longest delay between retries, 5m when zero
+	Timeout        time.Duration
+}
+
+// WebhookSubscription is an integrator endpoint receiving DAO events
+type WebhookSubscription struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"` // Every event when empty
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+// wants reports whether the subscription receives an event type
+func (s *WebhookSubscription) wants(eventType string) bool {
+	if len(s.Events) == 0 || eventType == WebhookPingEvent {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the body of a webhook request
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// WebhookDelivery records one attempt to deliver an event
+type WebhookDelivery struct {
+	EventID        string `json:"event_id"`
+	SubscriptionID string `json:"subscription_id"`
+	EventType      string `json:"event_type"`
+	Attempt        int    `json:"attempt"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	Error          string `json:"error,omitempty"`
+	Succeeded      bool   `json:"succeeded"`
+	DurationMs     int64  `json:"duration_ms"`
+	Timestamp      int64  `json:"timestamp"`
+	NextRetryAt    int64  `json:"next_retry_at,omitempty"`
+}
+
+// WebhookManager delivers DAO events to subscribed integrators, signing
+// every request and retrying failures with exponential backoff
+type WebhookManager struct {
+	config        WebhookConfig
+	client        *http.Client
+	subscriptions map[string]*WebhookSubscription
+	deliveries    map[string][]*WebhookDelivery
+	stop          chan struct{}
+	wg            sync.WaitGroup
+	mu            sync.RWMutex
+}
+
+// NewWebhookManager creates a new webhook manager
+func NewWebhookManager(config WebhookConfig) *WebhookManager {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 6
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &WebhookManager{
+		config:        config,
+		client:        &http.Client{Timeout: config.Timeout},
+		subscriptions: make(map[string]*WebhookSubscription),
+		deliveries:    make(map[string][]*WebhookDelivery),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Subscribe registers an endpoint for event types and returns the
+// subscription with the secret its requests are signed with. The secret is
+// not returned again.
+func (m *WebhookManager) Subscribe(endpoint string, events []string) (*WebhookSubscription, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, NewDAOError(ErrInvalidProposal, "webhook URL must be an http or https URL", nil)
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &WebhookSubscription{
+		ID:        id,
+		URL:       endpoint,
+		Events:    append([]string(nil), events...),
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscriptions[id] = subscription
+	copied := *subscription
+	return &copied, nil
+}
+
+// Unsubscribe removes a subscription and its delivery log
+func (m *WebhookManager) Unsubscribe(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.subscriptions[id]; !exists {
+		return NewDAOError(ErrProposalNotFound, "webhook subscription not found", nil)
+	}
+	delete(m.subscriptions, id)
+	delete(m.deliveries, id)
+	return nil
+}
+
+// GetSubscription returns a subscription without its secret
+func (m *WebhookManager) GetSubscription(id string) (*WebhookSubscription, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subscription, exists := m.subscriptions[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *subscription
+	copied.Secret = ""
+	return &copied, true
+}
+
+// ListSubscriptions returns every subscription without secrets, oldest first
+func (m *WebhookManager) ListSubscriptions() []*WebhookSubscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subscriptions := make([]*WebhookSubscription, 0, len(m.subscriptions))
+	for _, subscription := range m.subscriptions {
+		copied := *subscription
+		copied.Secret = ""
+		subscriptions = append(subscriptions, &copied)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].CreatedAt != subscriptions[j].CreatedAt {
+			return subscriptions[i].CreatedAt < subscriptions[j].CreatedAt
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions
+}
+
+// GetDeliveries returns the delivery log of a subscription, newest first
+func (m *WebhookManager) GetDeliveries(id string) ([]*WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.subscriptions[id]; !exists {
+		return nil, NewDAOError(ErrProposalNotFound, "webhook subscription not found", nil)
+	}
+
+	log := m.deliveries[id]
+	deliveries := make([]*WebhookDelivery, len(log))
+	for i, delivery := range log {
+		copied := *delivery
+		deliveries[len(log)-1-i] = &copied
+	}
+	return deliveries, nil
+}
+
+// Dispatch delivers an event to every subscription wanting it in the
+// background, retrying failed deliveries
+func (m *WebhookManager) Dispatch(eventType string, data interface{}, timestamp int64) {
+	m.mu.RLock()
+	var subscriptions []*WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.wants(eventType) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, subscription := range subscriptions {
+		event, err := newWebhookEvent(eventType, data, timestamp)
+		if err != nil {
+			continue
+		}
+
+		m.wg.Add(1)
+		go func(subscription *WebhookSubscription) {
+			defer m.wg.Done()
+			m.deliverWithRetry(subscription, event)
+		}(subscription)
+	}
+}
+
+// TestFire sends a ping event to a subscription once and returns the
+// delivery
+func (m *WebhookManager) TestFire(id string) (*WebhookDelivery, error) {
+	m.mu.RLock()
+	subscription, exists := m.subscriptions[id]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, NewDAOError(ErrProposalNotFound, "webhook subscription not found", nil)
+	}
+
+	event, err := newWebhookEvent(WebhookPingEvent, map[string]string{"subscription_id": id}, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := m.deliver(subscription, event, 1)
+	m.record(delivery)
+	return delivery, nil
+}
+
+// Stop abandons pending retries and waits for deliveries in flight
+func (m *WebhookManager) Stop() {
+	m.mu.Lock()
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+func (m *WebhookManager) deliverWithRetry(subscription *WebhookSubscription, event *WebhookEvent) {
+	backoff := m.config.InitialBackoff
+	for attempt := 1; attempt <= m.config.MaxAttempts; attempt++ {
+		delivery := m.deliver(subscription, event, attempt)
+		if !delivery.Succeeded && attempt < m.config.MaxAttempts {
+			delivery.NextRetryAt = time.Now().Add(backoff).Unix()
+		}
+		m.record(delivery)
+
+		if delivery.Succeeded || attempt == m.config.MaxAttempts {
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-m.stop:
+			return
+		}
+
+		backoff *= 2
+		if backoff > m.config.MaxBackoff {
+			backoff = m.config.MaxBackoff
+		}
+	}
+}
+
+// deliver makes one signed delivery attempt
+func (m *WebhookManager) deliver(subscription *WebhookSubscription, event *WebhookEvent, attempt int) *WebhookDelivery {
+	start := time.Now()
+	delivery := &WebhookDelivery{
+		EventID:        event.ID,
+		SubscriptionID: subscription.ID,
+		EventType:      event.Type,
+		Attempt:        attempt,
+		Timestamp:      start.Unix(),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event.Type)
+	req.Header.Set(WebhookDeliveryHeader, event.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, start.Unix(), body))
+
+	resp, err := m.client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Succeeded = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Succeeded {
+		delivery.Error = fmt.Sprintf("endpoint answered with status %d", resp.StatusCode)
+	}
+	return delivery
+}
+
+// record appends a delivery to the log of its subscription
+func (m *WebhookManager) record(delivery *WebhookDelivery) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.subscriptions[delivery.SubscriptionID]; !exists {
+		return
+	}
+
+	log := append(m.deliveries[delivery.SubscriptionID], delivery)
+	if len(log) > maxWebhookDeliveryLog {
+		log = log[len(log)-maxWebhookDeliveryLog:]
+	}
+	m.deliveries[delivery.SubscriptionID] = log
+}
+
+func newWebhookEvent(eventType string, data interface{}, timestamp int64) (*WebhookEvent, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookEvent{ID: id, Type: eventType, Data: data, Timestamp: timestamp}, nil
+}
+
+// SignWebhookPayload returns the signature header of a webhook body sent at
+// timestamp: "t=<timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">"
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifyWebhookSignature checks the signature header of a received webhook
+// body, rejecting signatures older than tolerance to stop replays
+func VerifyWebhookSignature(secret, header string, body []byte, tolerance time.Duration) error {
+	var timestamp int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid signature timestamp")
+			}
+			timestamp = parsed
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == 0 || signature == "" {
+		return fmt.Errorf("malformed signature header")
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return fmt.Errorf("signature timestamp outside tolerance")
+	}
+
+	expected := SignWebhookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(fmt.Sprintf("t=%d,v1=%s", timestamp, signature))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,162 @@
+package dao
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWebhookEndpoint fails the first failures requests and records the
+// verified events it accepts
+type testWebhookEndpoint struct {
+	secret   string
+	failures int
+
+	mu       sync.Mutex
+	requests int
+	events   []WebhookEvent
+}
+
+func (e *testWebhookEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	if e.requests <= e.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	if err := VerifyWebhookSignature(e.secret, r.Header.Get(WebhookSignatureHeader), body, time.Minute); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	json.Unmarshal(body, &event)
+	e.events = append(e.events, event)
+}
+
+func (e *testWebhookEndpoint) received() []WebhookEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]WebhookEvent(nil), e.events...)
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"type":"vote_cast"}`)
+	now := time.Now().Unix()
+	header := SignWebhookPayload("secret", now, body)
+
+	assert.NoError(t, VerifyWebhookSignature("secret", header, body, time.Minute))
+	assert.Error(t, VerifyWebhookSignature("other", header, body, time.Minute))
+	assert.Error(t, VerifyWebhookSignature("secret", header, []byte(`{"type":"proposal_passed"}`), time.Minute))
+	assert.Error(t, VerifyWebhookSignature("secret", "v1=abc", body, time.Minute))
+
+	// Old signatures are replays
+	stale := SignWebhookPayload("secret", now-3600, body)
+	assert.Error(t, VerifyWebhookSignature("secret", stale, body, time.Minute))
+}
+
+func TestWebhookManager_Subscriptions(t *testing.T) {
+	manager := NewWebhookManager(WebhookConfig{})
+
+	_, err := manager.Subscribe("ftp://example.com", nil)
+	assert.Error(t, err)
+
+	subscription, err := manager.Subscribe("https://integrator.example.com/hook", []string{"vote_cast"})
+	require.NoError(t, err)
+	assert.Len(t, subscription.Secret, 64)
+
+	// Secrets are only returned on creation
+	stored, exists := manager.GetSubscription(subscription.ID)
+	require.True(t, exists)
+	assert.Empty(t, stored.Secret)
+	assert.Empty(t, manager.ListSubscriptions()[0].Secret)
+
+	require.NoError(t, manager.Unsubscribe(subscription.ID))
+	assert.Error(t, manager.Unsubscribe(subscription.ID))
+	assert.Empty(t, manager.ListSubscriptions())
+}
+
+func TestWebhookManager_DispatchRetries(t *testing.T) {
+	manager := NewWebhookManager(WebhookConfig{InitialBackoff: 10 * time.Millisecond, MaxAttempts: 4})
+	defer manager.Stop()
+
+	endpoint := &testWebhookEndpoint{failures: 2}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	subscription, err := manager.Subscribe(server.URL, []string{"proposal_created", "treasury_executed"})
+	require.NoError(t, err)
+	endpoint.secret = subscription.Secret
+
+	manager.Dispatch("vote_cast", nil, 1)
+	manager.Dispatch("proposal_created", map[string]interface{}{"title": "Budget"}, 2)
+
+	require.Eventually(t, func() bool { return len(endpoint.received()) == 1 }, 2*time.Second, 5*time.Millisecond)
+	event := endpoint.received()[0]
+	assert.Equal(t, "proposal_created", event.Type)
+	assert.Equal(t, map[string]interface{}{"title": "Budget"}, event.Data)
+
+	// Every attempt is logged, newest first
+	deliveries, err := manager.GetDeliveries(subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	assert.True(t, deliveries[0].Succeeded)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[2].StatusCode)
+	assert.NotZero(t, deliveries[2].NextRetryAt)
+	assert.Equal(t, deliveries[0].EventID, deliveries[2].EventID)
+}
+
+func TestWebhookManager_GivesUp(t *testing.T) {
+	manager := NewWebhookManager(WebhookConfig{InitialBackoff: time.Millisecond, MaxAttempts: 3})
+	defer manager.Stop()
+
+	endpoint := &testWebhookEndpoint{failures: 10}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	subscription, err := manager.Subscribe(server.URL, nil)
+	require.NoError(t, err)
+
+	manager.Dispatch("vote_cast", nil, 1)
+	require.Eventually(t, func() bool {
+		deliveries, _ := manager.GetDeliveries(subscription.ID)
+		return len(deliveries) == 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	deliveries, _ := manager.GetDeliveries(subscription.ID)
+	assert.False(t, deliveries[0].Succeeded)
+	assert.Zero(t, deliveries[0].NextRetryAt)
+}
+
+func TestWebhookManager_TestFire(t *testing.T) {
+	manager := NewWebhookManager(WebhookConfig{})
+	endpoint := &testWebhookEndpoint{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	// Pings reach subscriptions regardless of their events
+	subscription, err := manager.Subscribe(server.URL, []string{"proposal_passed"})
+	require.NoError(t, err)
+	endpoint.secret = subscription.Secret
+
+	delivery, err := manager.TestFire(subscription.ID)
+	require.NoError(t, err)
+	assert.True(t, delivery.Succeeded)
+	assert.Equal(t, WebhookPingEvent, endpoint.received()[0].Type)
+
+	_, err = manager.TestFire("missing")
+	assert.Error(t, err)
+}
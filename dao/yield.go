@@ -0,0 +1,227 @@
+package dao
+
+import (
+	"sort"
+	"time"
+)
+
+// YieldEpoch holds the accounting for one treasury yield distribution period
+type YieldEpoch struct {
+	Number          uint64            `json:"number"`
+	StartTime       int64             `json:"start_time"`
+	EndTime         int64             `json:"end_time"`
+	Inflows         uint64            `json:"inflows"`
+	ShareBps        uint64            `json:"share_bps"`
+	Distributed     uint64            `json:"distributed"`
+	PoolAllocations map[string]uint64 `json:"pool_allocations"`
+	Closed          bool              `json:"closed"`
+
+	startInflows uint64 // Treasury.TotalInflows when the epoch opened
+}
+
+// StakingPoolYield summarizes the treasury yield received by a staking pool
+type StakingPoolYield struct {
+	PoolID        string  `json:"pool_id"`
+	Name          string  `json:"name"`
+	TotalStaked   uint64  `json:"total_staked"`
+	StakerCount   int     `json:"staker_count"`
+	TreasuryYield uint64  `json:"treasury_yield"`
+	LastEpochAPR  float64 `json:"last_epoch_apr"` // Annualized yield of the last closed epoch, in percent
+	FeeRewards    uint64  `json:"fee_rewards"`    // Validator fee revenue shared with the pool
+}
+
+// StakingYieldMetrics summarizes treasury yield distribution across staking pools
+type StakingYieldMetrics struct {
+	ShareBps         uint64             `json:"share_bps"`
+	EpochLength      int64              `json:"epoch_length"`
+	EpochsClosed     uint64             `json:"epochs_closed"`
+	TotalDistributed uint64             `json:"total_distributed"`
+	PendingInflows   uint64             `json:"pending_inflows"`
+	Pools            []StakingPoolYield `json:"pools"`
+	Validators       []ValidatorSummary `json:"validators"`
+	GeneratedAt      int64              `json:"generated_at"`
+}
+
+// YieldManager converts a governance-set share of treasury inflows into
+// staking pool rewards once per epoch
+type YieldManager struct {
+	governanceState   *GovernanceState
+	tokenState        *GovernanceToken
+	tokenomicsManager *TokenomicsManager
+	parameterManager  *ParameterManager
+	currentEpoch      *YieldEpoch
+	epochs            []*YieldEpoch
+}
+
+// NewYieldManager creates a new yield manager with its first epoch open
+func NewYieldManager(governanceState *GovernanceState, tokenState *GovernanceToken, tokenomicsManager *TokenomicsManager, parameterManager *ParameterManager) *YieldManager {
+	ym := &YieldManager{
+		governanceState:   governanceState,
+		tokenState:        tokenState,
+		tokenomicsManager: tokenomicsManager,
+		parameterManager:  parameterManager,
+		epochs:            make([]*YieldEpoch, 0),
+	}
+	ym.openEpoch(time.Now().Unix())
+
+	return ym
+}
+
+// openEpoch starts a new epoch at the given time
+func (ym *YieldManager) openEpoch(startTime int64) {
+	ym.currentEpoch = &YieldEpoch{
+		Number:          uint64(len(ym.epochs)) + 1,
+		StartTime:       startTime,
+		PoolAllocations: make(map[string]uint64),
+		startInflows:    ym.governanceState.Treasury.TotalInflows,
+	}
+}
+
+// GetCurrentEpoch returns the open epoch with its inflows so far
+func (ym *YieldManager) GetCurrentEpoch() *YieldEpoch {
+	ym.currentEpoch.Inflows = ym.governanceState.Treasury.TotalInflows - ym.currentEpoch.startInflows
+	ym.currentEpoch.ShareBps = ym.parameterManager.GetParameterConfig().TreasuryYieldShare
+	return ym.currentEpoch
+}
+
+// GetEpochs returns all closed epochs, oldest first
+func (ym *YieldManager) GetEpochs() []*YieldEpoch {
+	return ym.epochs
+}
+
+// IsEpochDue reports whether the current epoch has run its full length
+func (ym *YieldManager) IsEpochDue(now int64) bool {
+	return now >= ym.currentEpoch.StartTime+ym.parameterManager.GetParameterConfig().TreasuryYieldEpoch
+}
+
+// ProcessEpoch closes the current epoch if it is due and returns it, or
+// returns nil when the epoch is still running
+func (ym *YieldManager) ProcessEpoch(now int64) (*YieldEpoch, error) {
+	if !ym.IsEpochDue(now) {
+		return nil, nil
+	}
+
+	return ym.CloseEpoch(now)
+}
+
+// CloseEpoch distributes the configured share of the epoch's treasury inflows
+// to active staking pools, proportionally to their stake, and opens the next epoch
+func (ym *YieldManager) CloseEpoch(now int64) (*YieldEpoch, error) {
+	epoch := ym.GetCurrentEpoch()
+	if now < epoch.StartTime {
+		return nil, NewDAOError(ErrInvalidTimeframe, "epoch cannot end before it starts", nil)
+	}
+
+	epoch.EndTime = now
+
+	yield := epoch.Inflows * epoch.ShareBps / 10000
+	if yield > ym.governanceState.Treasury.Balance {
+		yield = ym.governanceState.Treasury.Balance
+	}
+
+	pools, totalStaked := ym.eligiblePools()
+	if yield > 0 && totalStaked > 0 {
+		for _, pool := range pools {
+			poolYield := yield * pool.TotalStaked / totalStaked
+			distributed := ym.distributeToPool(pool, poolYield)
+			if distributed > 0 {
+				epoch.PoolAllocations[pool.ID] = distributed
+				epoch.Distributed += distributed
+			}
+		}
+	}
+
+	ym.governanceState.Treasury.Balance -= epoch.Distributed
+	ym.governanceState.Treasury.YieldDistributed += epoch.Distributed
+
+	epoch.Closed = true
+	ym.epochs = append(ym.epochs, epoch)
+	ym.openEpoch(now)
+
+	return epoch, nil
+}
+
+// eligiblePools returns active pools with stake, in a stable order
+func (ym *YieldManager) eligiblePools() ([]*StakingPool, uint64) {
+	pools := make([]*StakingPool, 0)
+	totalStaked := uint64(0)
+
+	for _, pool := range ym.tokenomicsManager.ListAllStakingPools() {
+		if pool.Active && pool.TotalStaked > 0 {
+			pools = append(pools, pool)
+			totalStaked += pool.TotalStaked
+		}
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].ID < pools[j].ID
+	})
+
+	return pools, totalStaked
+}
+
+// distributeToPool credits a pool's yield to its stakers by stake weight and
+// returns the amount actually credited after rounding
+func (ym *YieldManager) distributeToPool(pool *StakingPool, amount uint64) uint64 {
+	if amount == 0 || pool.TotalStaked == 0 {
+		return 0
+	}
+
+	distributed := uint64(0)
+	for _, stakerInfo := range pool.Stakers {
+		share := amount * stakerInfo.StakedAmount / pool.TotalStaked
+		stakerInfo.TreasuryRewards += share
+		distributed += share
+	}
+
+	pool.TreasuryYield += distributed
+	return distributed
+}
+
+// GetStakingYieldMetrics returns treasury yield analytics for staking pools
+func (ym *YieldManager) GetStakingYieldMetrics() *StakingYieldMetrics {
+	config := ym.parameterManager.GetParameterConfig()
+	current := ym.GetCurrentEpoch()
+
+	metrics := &StakingYieldMetrics{
+		ShareBps:         config.TreasuryYieldShare,
+		EpochLength:      config.TreasuryYieldEpoch,
+		EpochsClosed:     uint64(len(ym.epochs)),
+		TotalDistributed: ym.governanceState.Treasury.YieldDistributed,
+		PendingInflows:   current.Inflows,
+		Pools:            make([]StakingPoolYield, 0),
+		GeneratedAt:      time.Now().Unix(),
+	}
+
+	var lastEpoch *YieldEpoch
+	if len(ym.epochs) > 0 {
+		lastEpoch = ym.epochs[len(ym.epochs)-1]
+	}
+
+	for _, pool := range ym.tokenomicsManager.ListAllStakingPools() {
+		poolYield := StakingPoolYield{
+			PoolID:        pool.ID,
+			Name:          pool.Name,
+			TotalStaked:   pool.TotalStaked,
+			StakerCount:   len(pool.Stakers),
+			TreasuryYield: pool.TreasuryYield,
+			FeeRewards:    pool.FeeRewards,
+		}
+
+		if lastEpoch != nil && pool.TotalStaked > 0 {
+			duration := lastEpoch.EndTime - lastEpoch.StartTime
+			if duration > 0 {
+				epochsPerYear := float64(365*24*3600) / float64(duration)
+				poolYield.LastEpochAPR = float64(lastEpoch.PoolAllocations[pool.ID]) / float64(pool.TotalStaked) * epochsPerYear * 100
+			}
+		}
+
+		metrics.Pools = append(metrics.Pools, poolYield)
+	}
+
+	sort.Slice(metrics.Pools, func(i, j int) bool {
+		return metrics.Pools[i].PoolID < metrics.Pools[j].PoolID
+	})
+
+	return metrics
+}
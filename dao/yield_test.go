@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupYieldDAO(t *testing.T) (*DAO, crypto.PublicKey, crypto.PublicKey) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	staker1 := crypto.GeneratePrivateKey().PublicKey()
+	staker2 := crypto.GeneratePrivateKey().PublicKey()
+	require.NoError(t, dao.InitialTokenDistribution(map[string]uint64{
+		staker1.String(): 10000,
+		staker2.String(): 10000,
+	}))
+
+	require.NoError(t, dao.CreateStakingPool("pool1", "Pool One", 0, 100, 0))
+	require.NoError(t, dao.CreateStakingPool("pool2", "Pool Two", 0, 100, 0))
+	require.NoError(t, dao.StakeTokens("pool1", staker1, 3000, 0))
+	require.NoError(t, dao.StakeTokens("pool2", staker2, 1000, 0))
+
+	return dao, staker1, staker2
+}
+
+func TestYieldManager_DisabledByDefault(t *testing.T) {
+	dao, _, _ := setupYieldDAO(t)
+	dao.AddTreasuryFunds(100000)
+
+	epoch, err := dao.YieldManager.CloseEpoch(time.Now().Unix())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(100000), epoch.Inflows)
+	assert.Equal(t, uint64(0), epoch.Distributed)
+	assert.Equal(t, uint64(100000), dao.GetTreasuryBalance())
+}
+
+func TestYieldManager_CloseEpochDistributesShare(t *testing.T) {
+	dao, staker1, staker2 := setupYieldDAO(t)
+	require.NoError(t, dao.ParameterManager.applyParameterChange("treasury_yield_share", uint64(2000)))
+
+	dao.AddTreasuryFunds(40000)
+
+	epoch, err := dao.YieldManager.CloseEpoch(time.Now().Unix())
+	require.NoError(t, err)
+
+	// 20% of 40000 split 3:1 between the pools
+	assert.Equal(t, uint64(1), epoch.Number)
+	assert.Equal(t, uint64(8000), epoch.Distributed)
+	assert.Equal(t, uint64(6000), epoch.PoolAllocations["pool1"])
+	assert.Equal(t, uint64(2000), epoch.PoolAllocations["pool2"])
+	assert.Equal(t, uint64(32000), dao.GetTreasuryBalance())
+
+	// Inflows of the next epoch start from zero
+	assert.Equal(t, uint64(2), dao.GetCurrentYieldEpoch().Number)
+	assert.Equal(t, uint64(0), dao.GetCurrentYieldEpoch().Inflows)
+
+	// Stakers receive the yield on claim without new tokens being minted
+	supplyBefore := dao.TokenState.TotalSupply
+	balanceBefore := dao.GetTokenBalance(staker1)
+	claimed, err := dao.ClaimStakingRewards("pool1", staker1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6000), claimed)
+	assert.Equal(t, balanceBefore+6000, dao.GetTokenBalance(staker1))
+	assert.Equal(t, supplyBefore, dao.TokenState.TotalSupply)
+
+	// Full unstake pays out remaining yield
+	balanceBefore = dao.GetTokenBalance(staker2)
+	require.NoError(t, dao.UnstakeTokens("pool2", staker2, 1000))
+	assert.Equal(t, balanceBefore+1000+2000, dao.GetTokenBalance(staker2))
+
+	// Visible in analytics
+	treasuryMetrics := dao.GetTreasuryPerformanceMetrics()
+	assert.Equal(t, uint64(40000), treasuryMetrics.TotalInflows)
+	assert.Equal(t, uint64(8000), treasuryMetrics.YieldDistributed)
+
+	stakingMetrics := dao.GetStakingYieldMetrics()
+	assert.Equal(t, uint64(1), stakingMetrics.EpochsClosed)
+	assert.Equal(t, uint64(8000), stakingMetrics.TotalDistributed)
+	require.Len(t, stakingMetrics.Pools, 2)
+	assert.Equal(t, uint64(6000), stakingMetrics.Pools[0].TreasuryYield)
+}
+
+func TestYieldManager_ProcessEpochWaitsForEpochEnd(t *testing.T) {
+	dao, _, _ := setupYieldDAO(t)
+	require.NoError(t, dao.ParameterManager.applyParameterChange("treasury_yield_share", uint64(1000)))
+	dao.AddTreasuryFunds(10000)
+
+	start := dao.GetCurrentYieldEpoch().StartTime
+
+	epoch, err := dao.YieldManager.ProcessEpoch(start + 10)
+	require.NoError(t, err)
+	assert.Nil(t, epoch)
+
+	epoch, err = dao.YieldManager.ProcessEpoch(start + dao.ParameterManager.GetParameterConfig().TreasuryYieldEpoch)
+	require.NoError(t, err)
+	require.NotNil(t, epoch)
+	assert.Equal(t, uint64(1000), epoch.Distributed)
+	assert.Len(t, dao.GetYieldEpochs(), 1)
+}
+
+func TestYieldManager_ShareValidation(t *testing.T) {
+	dao := NewDAO("GOV", "Governance Token", 18)
+
+	assert.Error(t, dao.ParameterManager.validateSingleParameter("treasury_yield_share", uint64(10001)))
+	assert.NoError(t, dao.ParameterManager.validateSingleParameter("treasury_yield_share", uint64(2500)))
+	assert.Error(t, dao.ParameterManager.validateSingleParameter("treasury_yield_epoch", int64(0)))
+}
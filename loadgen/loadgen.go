@@ -0,0 +1,315 @@
+// Package loadgen generates governance workloads against a node: bursts of
+// proposals, storms of votes on a few proposals and churn of delegations.
+// Workloads run in process against a DAO or over the REST API of a running
+// node, and report the latency percentiles of their operations. Targets
+// that can be profiled capture CPU and heap profiles of the run.
+package loadgen
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Workload scenarios
+const (
+	ScenarioProposalBurst   = "proposal-burst"   // Accounts create proposals as fast as they can
+	ScenarioVoteStorm       = "vote-storm"       // Accounts vote on a few open proposals
+	ScenarioDelegationChurn = "delegation-churn" // Accounts delegate and revoke over and over
+)
+
+// Scenarios lists the workload scenarios
+var Scenarios = []string{ScenarioProposalBurst, ScenarioVoteStorm, ScenarioDelegationChurn}
+
+// Fees paid by generated transactions. Nodes reached through the API charge
+// their configured fees instead.
+const (
+	ProposalFee   = 200
+	VoteFee       = 10
+	DelegationFee = 10
+)
+
+// votingPeriod is the voting period of generated proposals, long enough for
+// any run
+const votingPeriod = 7 * 24 * 3600
+
+// Config configures a workload
+type Config struct {
+	Scenario string
+	// Accounts sign the operations and must hold tokens on the target,
+	// unless it funds them
+	Accounts    []crypto.PrivateKey
+	Operations  int     // Operations in the run, 1000 when zero
+	Concurrency int     // Operations in flight at once, 16 when zero
+	Rate        float64 // Operations started per second, unlimited when zero
+	// Proposals is the number of proposals a vote storm spreads its votes
+	// over, 1 when zero
+	Proposals int
+	// ProfileDir receives CPU and heap profiles of the run when set and the
+	// target can be profiled
+	ProfileDir string
+}
+
+// Target is what a workload runs against
+type Target interface {
+	// Submit applies tx signed by key and returns its hash, which is the ID
+	// of the proposal a proposal transaction creates
+	Submit(ctx context.Context, key crypto.PrivateKey, tx interface{}) (types.Hash, error)
+}
+
+// Funder is a Target that can give the accounts of a workload tokens
+type Funder interface {
+	Fund(accounts []crypto.PublicKey) error
+}
+
+// Waiter is a Target that applies transactions asynchronously and can wait
+// for a proposal to be created
+type Waiter interface {
+	WaitProposal(ctx context.Context, id types.Hash) error
+}
+
+// Profiler is a Target whose CPU and heap can be profiled
+type Profiler interface {
+	// StartProfiling begins a CPU profile. The returned stop function ends
+	// it and writes the CPU and heap profiles to dir.
+	StartProfiling(ctx context.Context, dir string) (stop func() error, err error)
+}
+
+// operation is one transaction of a workload
+type operation struct {
+	account int
+	tx      interface{}
+}
+
+// GenerateAccounts returns n new account keys
+func GenerateAccounts(n int) []crypto.PrivateKey {
+	accounts := make([]crypto.PrivateKey, n)
+	for i := range accounts {
+		accounts[i] = crypto.GeneratePrivateKey()
+	}
+	return accounts
+}
+
+// Run runs the workload config describes against target and reports on it.
+// Operations of one account run in order, so an account's transactions
+// never race each other. The run stops early when ctx is done.
+func Run(ctx context.Context, target Target, config Config) (*Report, error) {
+	if config.Operations <= 0 {
+		config.Operations = 1000
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 16
+	}
+	if config.Proposals <= 0 {
+		config.Proposals = 1
+	}
+	if len(config.Accounts) == 0 {
+		return nil, fmt.Errorf("workload needs accounts")
+	}
+
+	if funder, ok := target.(Funder); ok {
+		keys := make([]crypto.PublicKey, len(config.Accounts))
+		for i, account := range config.Accounts {
+			keys[i] = account.PublicKey()
+		}
+		if err := funder.Fund(keys); err != nil {
+			return nil, fmt.Errorf("failed to fund accounts: %w", err)
+		}
+	}
+
+	operations, err := plan(ctx, target, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ProfileDir != "" {
+		if profiler, ok := target.(Profiler); ok {
+			stop, err := profiler.StartProfiling(ctx, config.ProfileDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to start profiling: %w", err)
+			}
+			defer stop()
+
+			report := execute(ctx, target, config, operations)
+			if err := stop(); err != nil {
+				return report, fmt.Errorf("failed to write profiles: %w", err)
+			}
+			return report, nil
+		}
+	}
+
+	return execute(ctx, target, config, operations), nil
+}
+
+// plan prepares the scenario on the target and returns its operations
+func plan(ctx context.Context, target Target, config Config) ([]operation, error) {
+	accounts := len(config.Accounts)
+	operations := make([]operation, config.Operations)
+
+	switch config.Scenario {
+	case ScenarioProposalBurst:
+		for k := range operations {
+			operations[k] = operation{account: k % accounts, tx: newProposalTx(fmt.Sprintf("Load proposal %d", k))}
+		}
+
+	case ScenarioVoteStorm:
+		if config.Operations > accounts*config.Proposals {
+			return nil, fmt.Errorf("a vote storm of %d votes needs %d proposals or %d accounts",
+				config.Operations, (config.Operations+accounts-1)/accounts, (config.Operations+config.Proposals-1)/config.Proposals)
+		}
+
+		// Every account votes once on each proposal
+		proposals := make([]types.Hash, config.Proposals)
+		for i := range proposals {
+			id, err := target.Submit(ctx, config.Accounts[i%accounts], newProposalTx(fmt.Sprintf("Vote storm %d", i)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create proposal: %w", err)
+			}
+			proposals[i] = id
+		}
+		if waiter, ok := target.(Waiter); ok {
+			for _, id := range proposals {
+				if err := waiter.WaitProposal(ctx, id); err != nil {
+					return nil, fmt.Errorf("proposal %s was not created: %w", id, err)
+				}
+			}
+		}
+
+		choices := []dao.VoteChoice{dao.VoteChoiceYes, dao.VoteChoiceNo, dao.VoteChoiceYes, dao.VoteChoiceAbstain}
+		for k := range operations {
+			operations[k] = operation{account: k / config.Proposals, tx: &dao.VoteTx{
+				Fee:        VoteFee,
+				ProposalID: proposals[k%config.Proposals],
+				Choice:     choices[k%len(choices)],
+				Weight:     10,
+			}}
+		}
+
+	case ScenarioDelegationChurn:
+		// A tenth of the accounts are delegates, which never delegate, so
+		// delegations cannot form cycles
+		delegates := accounts / 10
+		if delegates == 0 {
+			delegates = 1
+		}
+		delegators := accounts - delegates
+		if delegators == 0 {
+			return nil, fmt.Errorf("delegation churn needs at least two accounts")
+		}
+
+		for k := range operations {
+			delegator := delegates + k%delegators
+			if (k/delegators)%2 == 1 {
+				operations[k] = operation{account: delegator, tx: &dao.DelegationTx{Fee: DelegationFee, Revoke: true}}
+				continue
+			}
+			operations[k] = operation{account: delegator, tx: &dao.DelegationTx{
+				Fee:      DelegationFee,
+				Delegate: config.Accounts[k%delegates].PublicKey(),
+				Duration: votingPeriod,
+			}}
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown scenario %q", config.Scenario)
+	}
+
+	return operations, nil
+}
+
+// newProposalTx returns a general proposal open for voting now
+func newProposalTx(title string) *dao.ProposalTx {
+	var metadata types.Hash
+	rand.Read(metadata[:])
+
+	now := time.Now().Unix()
+	return &dao.ProposalTx{
+		Fee:          ProposalFee,
+		Title:        title,
+		Description:  "Generated by loadgen",
+		ProposalType: dao.ProposalTypeGeneral,
+		VotingType:   dao.VotingTypeSimple,
+		StartTime:    now,
+		EndTime:      now + votingPeriod,
+		Threshold:    5100,
+		MetadataHash: metadata,
+	}
+}
+
+// execute runs the operations on Concurrency workers. All operations of an
+// account go to the same worker, in order.
+func execute(ctx context.Context, target Target, config Config, operations []operation) *Report {
+	type result struct {
+		latency time.Duration
+		err     error
+	}
+
+	queues := make([]chan operation, config.Concurrency)
+	results := make([][]result, config.Concurrency)
+	var wg sync.WaitGroup
+	for w := range queues {
+		queues[w] = make(chan operation, 64)
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for op := range queues[w] {
+				started := time.Now()
+				_, err := target.Submit(ctx, config.Accounts[op.account], op.tx)
+				results[w] = append(results[w], result{latency: time.Since(started), err: err})
+			}
+		}(w)
+	}
+
+	var tick <-chan time.Time
+	if config.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / config.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	started := time.Now()
+dispatch:
+	for _, op := range operations {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		select {
+		case queues[op.account%config.Concurrency] <- op:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	report := &Report{Scenario: config.Scenario, Duration: time.Since(started), Errors: make(map[string]int)}
+	var latencies []time.Duration
+	for _, workerResults := range results {
+		for _, r := range workerResults {
+			report.Operations++
+			if r.err != nil {
+				report.Failed++
+				report.Errors[r.err.Error()]++
+				continue
+			}
+			latencies = append(latencies, r.latency)
+		}
+	}
+	report.Latency = Summarize(latencies)
+	if seconds := report.Duration.Seconds(); seconds > 0 {
+		report.Throughput = float64(len(latencies)) / seconds
+	}
+	return report
+}
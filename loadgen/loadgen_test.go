@@ -0,0 +1,208 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runInProcess(t *testing.T, config Config) (*Report, *dao.DAO) {
+	d := dao.NewDAO("TEST", "Test Token", 18)
+	target := NewInProcessTarget(d, 2)
+	defer target.Close()
+
+	report, err := Run(context.Background(), target, config)
+	require.NoError(t, err)
+	return report, d
+}
+
+func TestRun_ProposalBurst(t *testing.T) {
+	report, d := runInProcess(t, Config{
+		Scenario:    ScenarioProposalBurst,
+		Accounts:    GenerateAccounts(4),
+		Operations:  20,
+		Concurrency: 3,
+		ProfileDir:  t.TempDir(),
+	})
+
+	assert.Equal(t, 20, report.Operations)
+	assert.Equal(t, 0, report.Failed, report.Errors)
+	assert.Len(t, d.GovernanceState.Proposals, 20)
+	assert.Greater(t, report.Latency.Max, time.Duration(0))
+	assert.Greater(t, report.Throughput, 0.0)
+}
+
+func TestRun_VoteStorm(t *testing.T) {
+	report, d := runInProcess(t, Config{
+		Scenario:    ScenarioVoteStorm,
+		Accounts:    GenerateAccounts(10),
+		Operations:  30,
+		Concurrency: 4,
+		Proposals:   3,
+	})
+
+	assert.Equal(t, 30, report.Operations)
+	assert.Equal(t, 0, report.Failed, report.Errors)
+	require.Len(t, d.GovernanceState.Proposals, 3)
+	for id := range d.GovernanceState.Proposals {
+		assert.Len(t, d.GovernanceState.Votes[id], 10)
+	}
+}
+
+func TestRun_VoteStormTooManyVotes(t *testing.T) {
+	target := NewInProcessTarget(dao.NewDAO("TEST", "Test Token", 18), 1)
+	defer target.Close()
+
+	_, err := Run(context.Background(), target, Config{
+		Scenario:   ScenarioVoteStorm,
+		Accounts:   GenerateAccounts(5),
+		Operations: 11,
+		Proposals:  2,
+	})
+	assert.Error(t, err)
+}
+
+func TestRun_DelegationChurn(t *testing.T) {
+	report, d := runInProcess(t, Config{
+		Scenario:    ScenarioDelegationChurn,
+		Accounts:    GenerateAccounts(11),
+		Operations:  25,
+		Concurrency: 4,
+	})
+
+	// Ten delegators delegate, revoke, then delegate again for half of them
+	assert.Equal(t, 25, report.Operations)
+	assert.Equal(t, 0, report.Failed, report.Errors)
+	active := 0
+	for _, delegation := range d.GovernanceState.Delegations {
+		if delegation.Active {
+			active++
+		}
+	}
+	assert.Equal(t, 5, active)
+}
+
+func TestRun_UnknownScenario(t *testing.T) {
+	target := NewInProcessTarget(dao.NewDAO("TEST", "Test Token", 18), 1)
+	defer target.Close()
+
+	_, err := Run(context.Background(), target, Config{Scenario: "stampede", Accounts: GenerateAccounts(1)})
+	assert.Error(t, err)
+}
+
+func TestSummarize(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+
+	summary := Summarize(latencies)
+	assert.Equal(t, time.Millisecond, summary.Min)
+	assert.Equal(t, 50*time.Millisecond+500*time.Microsecond, summary.Mean)
+	assert.Equal(t, 50*time.Millisecond, summary.P50)
+	assert.Equal(t, 90*time.Millisecond, summary.P90)
+	assert.Equal(t, 99*time.Millisecond, summary.P99)
+	assert.Equal(t, 100*time.Millisecond, summary.Max)
+
+	assert.Equal(t, LatencySummary{}, Summarize(nil))
+	assert.Equal(t, time.Second, Summarize([]time.Duration{time.Second}).P99)
+}
+
+func TestReport_Write(t *testing.T) {
+	report := &Report{
+		Scenario:   ScenarioVoteStorm,
+		Operations: 10,
+		Failed:     3,
+		Errors:     map[string]int{"insufficient balance": 1, "proposal not found": 2},
+		Duration:   time.Second,
+		Throughput: 7,
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "10 (3 failed)")
+	assert.Contains(t, out.String(), "7.0 ops/s")
+	assert.Less(t, bytes.Index(out.Bytes(), []byte("proposal not found")), bytes.Index(out.Bytes(), []byte("insufficient balance")))
+}
+
+func TestAPITarget(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	proposalID := types.Hash{7}
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+
+		switch {
+		case r.URL.Path == "/dao/proposal/"+proposalID.String():
+			// The proposal shows up once its block is made
+			if polls++; polls < 2 {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"message": "proposal not found"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{})
+		case r.URL.Path == "/admin/debug/pprof/profile" || r.URL.Path == "/admin/debug/pprof/heap":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("profile"))
+		default:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.NotEmpty(t, body["private_key"])
+			json.NewEncoder(w).Encode(map[string]string{"tx_hash": proposalID.String()})
+		}
+	}))
+	defer server.Close()
+
+	target := NewAPITarget(server.URL, "secret")
+	target.PollInterval = time.Millisecond
+	target.ProfileSeconds = 1
+	dir := t.TempDir()
+
+	report, err := Run(context.Background(), target, Config{
+		Scenario:    ScenarioVoteStorm,
+		Accounts:    GenerateAccounts(4),
+		Operations:  4,
+		Concurrency: 2,
+		ProfileDir:  dir,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Failed, report.Errors)
+
+	assert.Equal(t, 1, requests["/dao/proposal"])
+	assert.Equal(t, 4, requests["/dao/vote"])
+	assert.Equal(t, 2, requests["/dao/proposal/"+proposalID.String()])
+	for _, file := range []string{CPUProfileFile, HeapProfileFile} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		assert.Equal(t, "profile", string(data))
+	}
+
+	report, err = Run(context.Background(), target, Config{
+		Scenario:   ScenarioDelegationChurn,
+		Accounts:   GenerateAccounts(3),
+		Operations: 4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Failed, report.Errors)
+	assert.Equal(t, 2, requests["/dao/delegate"])
+	assert.Equal(t, 2, requests["/dao/revoke-delegation"])
+}
@@ -0,0 +1,90 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// LatencySummary summarises the latencies of successful operations
+type LatencySummary struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// Summarize returns the summary of latencies, in any order
+func Summarize(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	return LatencySummary{
+		Min:  sorted[0],
+		Mean: total / time.Duration(len(sorted)),
+		P50:  percentile(sorted, 50),
+		P90:  percentile(sorted, 90),
+		P99:  percentile(sorted, 99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Report is the outcome of a workload run
+type Report struct {
+	Scenario   string         `json:"scenario"`
+	Operations int            `json:"operations"` // Operations run, fewer than planned when the run was cancelled
+	Failed     int            `json:"failed"`
+	Errors     map[string]int `json:"errors"` // Failed operations by error
+	Duration   time.Duration  `json:"duration"`
+	Throughput float64        `json:"throughput"` // Successful operations per second
+	Latency    LatencySummary `json:"latency"`
+}
+
+// Write prints the report as a table, followed by the errors seen, most
+// frequent first
+func (r *Report) Write(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "scenario\t%s\n", r.Scenario)
+	fmt.Fprintf(w, "operations\t%d (%d failed)\n", r.Operations, r.Failed)
+	fmt.Fprintf(w, "duration\t%v\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(w, "throughput\t%.1f ops/s\n", r.Throughput)
+	fmt.Fprintf(w, "latency\tmin %v  mean %v  p50 %v  p90 %v  p99 %v  max %v\n",
+		r.Latency.Min, r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+
+	errors := make([]string, 0, len(r.Errors))
+	for message := range r.Errors {
+		errors = append(errors, message)
+	}
+	sort.Slice(errors, func(i, j int) bool {
+		if r.Errors[errors[i]] != r.Errors[errors[j]] {
+			return r.Errors[errors[i]] > r.Errors[errors[j]]
+		}
+		return errors[i] < errors[j]
+	})
+	for _, message := range errors {
+		fmt.Fprintf(w, "error\t%dx %s\n", r.Errors[message], message)
+	}
+
+	return w.Flush()
+}
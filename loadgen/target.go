@@ -0,0 +1,350 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Profile files written to the profile directory
+const (
+	CPUProfileFile  = "cpu.pprof"
+	HeapProfileFile = "heap.pprof"
+)
+
+// InProcessTarget applies transactions to a DAO in this process through a
+// transaction pipeline, the way a node applies the transactions of a block
+type InProcessTarget struct {
+	DAO *dao.DAO
+	// Balance is the token balance Fund gives each account
+	Balance  uint64
+	pipeline *dao.TxPipeline
+}
+
+// NewInProcessTarget returns a target applying to d with the given number of
+// pipeline workers, one per CPU when workers is not positive. Close stops
+// the pipeline.
+func NewInProcessTarget(d *dao.DAO, workers int) *InProcessTarget {
+	return &InProcessTarget{
+		DAO:      d,
+		Balance:  1_000_000,
+		pipeline: dao.NewTxPipeline(d, workers),
+	}
+}
+
+// Close waits for submitted transactions and stops the pipeline
+func (t *InProcessTarget) Close() {
+	t.pipeline.Stop()
+}
+
+// Fund distributes Balance tokens to each account as the initial token
+// distribution of the DAO, so it is meant for a fresh DAO
+func (t *InProcessTarget) Fund(accounts []crypto.PublicKey) error {
+	distribution := make(map[string]uint64, len(accounts))
+	for _, account := range accounts {
+		distribution[account.String()] = t.Balance
+	}
+	return t.DAO.InitialTokenDistribution(distribution)
+}
+
+// Submit signs tx with key and waits for the pipeline to apply it
+func (t *InProcessTarget) Submit(ctx context.Context, key crypto.PrivateKey, tx interface{}) (types.Hash, error) {
+	encoded, err := dao.EncodeTx(tx)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	hash := types.Hash(sha256.Sum256(encoded))
+	signature, err := key.Sign(hash[:])
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	result := t.pipeline.Submit(dao.PipelineTx{
+		Tx:        tx,
+		Encoded:   encoded,
+		From:      key.PublicKey(),
+		Hash:      hash,
+		Signature: signature,
+	})
+	select {
+	case err := <-result:
+		return hash, err
+	case <-ctx.Done():
+		return hash, ctx.Err()
+	}
+}
+
+// StartProfiling profiles the CPU of this process until stop is called,
+// then writes the CPU and heap profiles to dir
+func (t *InProcessTarget) StartProfiling(ctx context.Context, dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	cpu, err := os.Create(filepath.Join(dir, CPUProfileFile))
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, err
+	}
+
+	stopped := false
+	return func() error {
+		if stopped {
+			return nil
+		}
+		stopped = true
+
+		pprof.StopCPUProfile()
+		if err := cpu.Close(); err != nil {
+			return err
+		}
+
+		heap, err := os.Create(filepath.Join(dir, HeapProfileFile))
+		if err != nil {
+			return err
+		}
+		defer heap.Close()
+		runtime.GC()
+		return pprof.WriteHeapProfile(heap)
+	}, nil
+}
+
+// APITarget submits transactions to a running node through its REST API.
+// The node signs with the keys it is sent, charges its configured fees and
+// applies the transactions when it includes them in a block.
+type APITarget struct {
+	BaseURL string
+	// AdminToken authorizes the admin profiling endpoints, profiling is
+	// skipped without it
+	AdminToken string
+	// ProfileSeconds is the length of the CPU profile, 30 when zero
+	ProfileSeconds int
+	// PollInterval is how often WaitProposal checks for a proposal, 500ms
+	// when zero
+	PollInterval time.Duration
+	HTTP         *http.Client
+}
+
+// NewAPITarget returns a target for the node serving its API at baseURL
+func NewAPITarget(baseURL, adminToken string) *APITarget {
+	return &APITarget{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		AdminToken: adminToken,
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Submit posts tx to the endpoint creating its kind of transaction
+func (t *APITarget) Submit(ctx context.Context, key crypto.PrivateKey, tx interface{}) (types.Hash, error) {
+	var path string
+	var body map[string]interface{}
+
+	switch tx := tx.(type) {
+	case *dao.ProposalTx:
+		path = "/dao/proposal"
+		body = map[string]interface{}{
+			"title":         tx.Title,
+			"description":   tx.Description,
+			"proposal_type": tx.ProposalType,
+			"voting_type":   tx.VotingType,
+			"start_time":    tx.StartTime,
+			"duration":      tx.EndTime - tx.StartTime,
+			"threshold":     tx.Threshold,
+			"metadata_hash": tx.MetadataHash.String(),
+		}
+	case *dao.VoteTx:
+		path = "/dao/vote"
+		body = map[string]interface{}{
+			"proposal_id": tx.ProposalID.String(),
+			"choice":      tx.Choice,
+			"weight":      tx.Weight,
+			"reason":      tx.Reason,
+		}
+	case *dao.DelegationTx:
+		if tx.Revoke {
+			path = "/dao/revoke-delegation"
+			body = map[string]interface{}{}
+			break
+		}
+		path = "/dao/delegate"
+		body = map[string]interface{}{
+			"delegate":   tx.Delegate.String(),
+			"duration":   tx.Duration,
+			"auto_renew": tx.AutoRenew,
+		}
+	default:
+		return types.Hash{}, fmt.Errorf("unsupported transaction type %T", tx)
+	}
+	body["private_key"] = key.Hex()
+
+	var response struct {
+		TxHash string `json:"tx_hash"`
+	}
+	if err := t.do(ctx, http.MethodPost, path, body, &response); err != nil {
+		return types.Hash{}, err
+	}
+
+	hash, err := hex.DecodeString(response.TxHash)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("invalid transaction hash %q", response.TxHash)
+	}
+	return types.HashFromBytes(hash), nil
+}
+
+// WaitProposal polls the node until the proposal with id is created
+func (t *APITarget) WaitProposal(ctx context.Context, id types.Hash) error {
+	interval := t.PollInterval
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+
+	for {
+		err := t.do(ctx, http.MethodGet, "/dao/proposal/"+id.String(), nil, nil)
+		if err == nil {
+			return nil
+		}
+		if statusErr, ok := err.(*statusError); !ok || statusErr.status != http.StatusNotFound {
+			return err
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// StartProfiling starts a CPU profile of the node through its admin API.
+// The node profiles for ProfileSeconds rather than until the run ends, so
+// stop waits for the CPU profile and fetches the heap profile.
+func (t *APITarget) StartProfiling(ctx context.Context, dir string) (func() error, error) {
+	if t.AdminToken == "" {
+		return func() error { return nil }, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	seconds := t.ProfileSeconds
+	if seconds <= 0 {
+		seconds = 30
+	}
+	cpu := make(chan error, 1)
+	go func() {
+		path := fmt.Sprintf("/admin/debug/pprof/profile?seconds=%d", seconds)
+		cpu <- t.fetchProfile(ctx, path, filepath.Join(dir, CPUProfileFile))
+	}()
+
+	stopped := false
+	return func() error {
+		if stopped {
+			return nil
+		}
+		stopped = true
+
+		if err := t.fetchProfile(ctx, "/admin/debug/pprof/heap", filepath.Join(dir, HeapProfileFile)); err != nil {
+			return err
+		}
+		return <-cpu
+	}, nil
+}
+
+// fetchProfile saves the profile served at path to file
+func (t *APITarget) fetchProfile(ctx context.Context, path, file string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.AdminToken)
+
+	// Profiles take longer than the client timeout allows
+	client := *t.HTTP
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{status: resp.StatusCode, message: fmt.Sprintf("GET %s: %s", path, resp.Status)}
+	}
+
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// statusError is an error status returned by the node
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+func (t *APITarget) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string
+		}
+		message := fmt.Sprintf("%s %s: %s", method, path, resp.Status)
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Message != "" {
+			message = fmt.Sprintf("%s %s: %s", method, path, apiErr.Message)
+		}
+		return &statusError{status: resp.StatusCode, message: message}
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
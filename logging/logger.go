@@ -0,0 +1,173 @@
+// Package logging provides the logger of a node, whose level and log file
+// can be changed while the node runs.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// Level is the least severe level of the entries a Logger writes
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	if name, exists := levelNames[l]; exists {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int32(l))
+}
+
+// ParseLevel parses a level name such as "info"
+func ParseLevel(name string) (Level, error) {
+	for l, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+}
+
+// Logger writes logfmt entries to stderr or to a log file. Entries logged
+// without a go-kit level count as info.
+type Logger struct {
+	level  int32
+	path   string
+	logger log.Logger
+
+	mu   sync.Mutex
+	out  io.Writer
+	file *os.File
+}
+
+// New creates a logger writing to the file at path, or to stderr when path
+// is empty
+func New(path string, lvl Level) (*Logger, error) {
+	l := &Logger{
+		level: int32(lvl),
+		path:  path,
+		out:   os.Stderr,
+	}
+
+	if path != "" {
+		file, err := openLogFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.file, l.out = file, file
+	}
+
+	l.logger = log.NewLogfmtLogger(writerFunc(l.write))
+	return l, nil
+}
+
+// Log writes an entry unless it is less severe than the level of the logger
+func (l *Logger) Log(keyvals ...interface{}) error {
+	entryLevel := LevelInfo
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] != level.Key() {
+			continue
+		}
+		if value, ok := keyvals[i+1].(level.Value); ok {
+			if parsed, err := ParseLevel(value.String()); err == nil {
+				entryLevel = parsed
+			}
+		}
+		break
+	}
+
+	if entryLevel < l.Level() {
+		return nil
+	}
+	return l.logger.Log(keyvals...)
+}
+
+// Level returns the least severe level logged
+func (l *Logger) Level() Level {
+	return Level(atomic.LoadInt32(&l.level))
+}
+
+// SetLevel changes the least severe level logged
+func (l *Logger) SetLevel(lvl Level) {
+	atomic.StoreInt32(&l.level, int32(lvl))
+}
+
+// Path returns the log file, which is empty when logging to stderr
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Rotate moves the log file aside under a timestamped name and continues in
+// a new file at the original path. It returns where the old entries went.
+func (l *Logger) Rotate() (string, error) {
+	if l.path == "" {
+		return "", fmt.Errorf("logging to stderr, there is no log file to rotate")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rotated := l.path + "." + time.Now().UTC().Format("20060102T150405.000")
+	if err := os.Rename(l.path, rotated); err != nil {
+		return "", err
+	}
+
+	// Entries keep going to the renamed file if the new one cannot be opened
+	file, err := openLogFile(l.path)
+	if err != nil {
+		return "", err
+	}
+	l.file.Close()
+	l.file, l.out = file, file
+
+	return rotated, nil
+}
+
+// Close closes the log file
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func (l *Logger) write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.out.Write(p)
+}
+
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
+
+// writerFunc adapts a function to io.Writer
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
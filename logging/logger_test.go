@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	lvl, err := ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, LevelWarn, lvl)
+	assert.Equal(t, "warn", lvl.String())
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLogger_Level(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.log")
+	logger, err := New(path, LevelInfo)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	nodeLogger := log.With(logger, "addr", "node-1")
+	level.Debug(nodeLogger).Log("msg", "hidden")
+	nodeLogger.Log("msg", "unleveled")
+	level.Error(nodeLogger).Log("msg", "failed")
+
+	logger.SetLevel(LevelDebug)
+	level.Debug(nodeLogger).Log("msg", "shown")
+
+	logger.SetLevel(LevelError)
+	nodeLogger.Log("msg", "quiet")
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "addr=node-1 msg=unleveled\nlevel=error addr=node-1 msg=failed\nlevel=debug addr=node-1 msg=shown\n", string(contents))
+}
+
+func TestLogger_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.log")
+	logger, err := New(path, LevelInfo)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	logger.Log("msg", "before")
+	rotated, err := logger.Rotate()
+	require.NoError(t, err)
+	logger.Log("msg", "after")
+
+	old, err := os.ReadFile(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, "msg=before\n", string(old))
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "msg=after\n", string(current))
+
+	stderrLogger, err := New("", LevelInfo)
+	require.NoError(t, err)
+	_, err = stderrLogger.Rotate()
+	assert.Error(t, err)
+}
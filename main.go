@@ -3,10 +3,12 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/BOCK-CHAIN/BockChain/config"
 	"github.com/BOCK-CHAIN/BockChain/core"
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/network"
@@ -15,6 +17,14 @@ import (
 )
 
 func main() {
+	configPath := flag.String("config", "", "path to a YAML node configuration, runs the local demo network when empty")
+	flag.Parse()
+
+	if *configPath != "" {
+		runNode(*configPath)
+		return
+	}
+
 	validatorPrivKey := crypto.GeneratePrivateKey()
 	localNode := makeServer("LOCAL_NODE", &validatorPrivKey, ":3000", []string{":4000"}, ":9000")
 	go localNode.Start()
@@ -53,6 +63,27 @@ func main() {
 	select {}
 }
 
+// runNode starts a single node from a configuration file and the BOCK_
+// environment variables
+func runNode(path string) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts, err := network.ServerOptsFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := network.NewServer(opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s.Start()
+}
+
 func sendTransaction(privKey crypto.PrivateKey) error {
 	toPrivKey := crypto.GeneratePrivateKey()
 
@@ -0,0 +1,327 @@
+package network
+
+import (
+	"bytes"
+	"encoding/gob"
+	"net"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/api"
+)
+
+const (
+	DefaultMaxPeers          = 32
+	DefaultDiscoveryInterval = 30 * time.Second
+
+	// maxPeerExchange is the number of addresses shared or accepted in a
+	// single peer exchange
+	maxPeerExchange = 32
+	dialTimeout     = 5 * time.Second
+)
+
+// addPeer registers a new connection, refusing it when the node is full or
+// the peer is banned
+func (s *Server) addPeer(peer *TCPPeer) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if peer.listenAddr != "" {
+		delete(s.dialing, peer.listenAddr)
+	}
+
+	if len(s.peerMap) >= s.MaxPeers || (peer.listenAddr != "" && s.peerStore.IsBanned(peer.listenAddr)) {
+		peer.conn.Close()
+		return false
+	}
+
+	s.peerMap[peer.conn.RemoteAddr()] = peer
+	if peer.listenAddr != "" {
+		s.peerStore.MarkConnected(peer.listenAddr)
+	}
+
+	return true
+}
+
+// removePeer closes and forgets the connection to a peer
+func (s *Server) removePeer(from net.Addr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if peer, ok := s.peerMap[from]; ok {
+		peer.conn.Close()
+		delete(s.peerMap, from)
+	}
+}
+
+// peerListenAddr returns the address the peer at from accepts connections on,
+// or an empty string if it has not told us yet
+func (s *Server) peerListenAddr(from net.Addr) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if peer, ok := s.peerMap[from]; ok {
+		return peer.listenAddr
+	}
+	return ""
+}
+
+// dialPeer connects to a peer in the background
+func (s *Server) dialPeer(addr string) {
+	s.mu.Lock()
+	if s.dialing[addr] {
+		s.mu.Unlock()
+		return
+	}
+	s.dialing[addr] = true
+	s.mu.Unlock()
+
+	go func() {
+		conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+		if err != nil {
+			s.Logger.Log("msg", "could not connect to peer", "addr", addr, "err", err)
+			s.peerStore.MarkDialFailed(addr)
+
+			s.mu.Lock()
+			delete(s.dialing, addr)
+			s.mu.Unlock()
+			return
+		}
+
+		s.peerCh <- &TCPPeer{
+			conn:       conn,
+			Outgoing:   true,
+			listenAddr: addr,
+		}
+	}()
+}
+
+// discoveryLoop periodically asks peers for more peers, dials the best known
+// addresses while the node has room, and persists the peer store
+func (s *Server) discoveryLoop() {
+	ticker := time.NewTicker(s.DiscoveryInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.discoverPeers()
+	}
+}
+
+func (s *Server) discoverPeers() {
+	s.dialCandidates()
+
+	if err := s.gossipMessage(nil, MessageTypeGetPeers, &GetPeersMessage{}); err != nil {
+		s.Logger.Log("error", "failed to request peers", "err", err)
+	}
+
+	if err := s.peerStore.Save(); err != nil {
+		s.Logger.Log("error", "failed to save peer store", "err", err)
+	}
+}
+
+// dialCandidates dials known peers until the node would reach MaxPeers
+func (s *Server) dialCandidates() {
+	s.mu.RLock()
+	exclude := map[string]bool{s.ListenAddr: true}
+	for addr := range s.selfAddrs {
+		exclude[addr] = true
+	}
+	for addr := range s.dialing {
+		exclude[addr] = true
+	}
+	for _, peer := range s.peerMap {
+		if peer.listenAddr != "" {
+			exclude[peer.listenAddr] = true
+		}
+	}
+	room := s.MaxPeers - len(s.peerMap) - len(s.dialing)
+	s.mu.RUnlock()
+
+	if room <= 0 {
+		return
+	}
+
+	for _, addr := range s.peerStore.Candidates(room, exclude) {
+		s.dialPeer(addr)
+	}
+}
+
+// registerPeerAddr learns the listen address of a peer from its status and
+// reports whether the connection is kept
+func (s *Server) registerPeerAddr(from net.Addr, data *StatusMessage) bool {
+	s.mu.Lock()
+	peer, ok := s.peerMap[from]
+	if !ok {
+		s.mu.Unlock()
+		return false
+	}
+
+	if peer.listenAddr == "" {
+		peer.listenAddr = advertisedAddr(from, data.ListenAddr)
+	}
+	addr := peer.listenAddr
+
+	// We dialed ourselves through an address learned from peer exchange
+	self := s.ID != "" && data.ID == s.ID
+	if self && addr != "" {
+		s.selfAddrs[addr] = true
+	}
+	s.mu.Unlock()
+
+	if self || addr == "" {
+		s.removePeer(from)
+		return false
+	}
+
+	if s.peerStore.IsBanned(addr) {
+		s.Logger.Log("msg", "disconnecting banned peer", "addr", addr)
+		s.removePeer(from)
+		return false
+	}
+
+	s.peerStore.MarkConnected(addr)
+	return true
+}
+
+// penalizePeer lowers the score of a misbehaving peer and disconnects it once
+// it is banned. Peers that have not told us their address are dropped.
+func (s *Server) penalizePeer(from net.Addr, penalty int) {
+	if from == nil {
+		return
+	}
+
+	addr := s.peerListenAddr(from)
+	if addr == "" || s.peerStore.Penalize(addr, penalty) {
+		s.Logger.Log("msg", "disconnecting misbehaving peer", "from", from, "addr", addr)
+		s.removePeer(from)
+	}
+}
+
+// rewardPeer raises the score of a peer that sent useful data
+func (s *Server) rewardPeer(from net.Addr) {
+	if from == nil {
+		return
+	}
+
+	if addr := s.peerListenAddr(from); addr != "" {
+		s.peerStore.Reward(addr, 1)
+	}
+}
+
+func (s *Server) processGetPeersMessage(from net.Addr, data *GetPeersMessage) error {
+	requester := s.peerListenAddr(from)
+	now := time.Now().Unix()
+
+	// Only addresses we have connected to ourselves are shared
+	peers := &PeersMessage{}
+	for _, record := range s.peerStore.List() {
+		if len(peers.Addrs) == maxPeerExchange {
+			break
+		}
+		if record.Addr == requester || record.LastSeen == 0 || record.BannedUntil > now {
+			continue
+		}
+		peers.Addrs = append(peers.Addrs, record.Addr)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(peers); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	peer, ok := s.peerMap[from]
+	if !ok {
+		return nil
+	}
+
+	msg := NewMessage(MessageTypePeers, buf.Bytes())
+	return peer.Send(msg.Bytes())
+}
+
+func (s *Server) processPeersMessage(from net.Addr, data *PeersMessage) error {
+	addrs := data.Addrs
+	if len(addrs) > maxPeerExchange {
+		s.penalizePeer(from, penaltyInvalidMessage)
+		addrs = addrs[:maxPeerExchange]
+	}
+
+	learned := 0
+	for _, addr := range addrs {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || host == "" || port == "" || addr == s.ListenAddr {
+			continue
+		}
+		if s.peerStore.Add(addr) {
+			learned++
+		}
+	}
+
+	if learned > 0 {
+		s.Logger.Log("msg", "learned peers through peer exchange", "from", from, "count", learned)
+		s.dialCandidates()
+	}
+
+	return nil
+}
+
+// Peers returns the known and connected peers of the node
+func (s *Server) Peers() []api.PeerInfo {
+	s.mu.RLock()
+	connected := make(map[string]*TCPPeer, len(s.peerMap))
+	for addr, peer := range s.peerMap {
+		key := peer.listenAddr
+		if key == "" {
+			key = addr.String()
+		}
+		connected[key] = peer
+	}
+	s.mu.RUnlock()
+
+	now := time.Now().Unix()
+	peers := []api.PeerInfo{}
+	for _, record := range s.peerStore.List() {
+		info := api.PeerInfo{
+			Addr:     record.Addr,
+			Score:    record.Score,
+			Banned:   record.BannedUntil > now,
+			LastSeen: record.LastSeen,
+		}
+		if info.Banned {
+			info.BannedUntil = record.BannedUntil
+		}
+		if peer, ok := connected[record.Addr]; ok {
+			info.Connected = true
+			info.Outgoing = peer.Outgoing
+			delete(connected, record.Addr)
+		}
+		peers = append(peers, info)
+	}
+
+	// Connections that have not told us their listen address yet
+	for addr, peer := range connected {
+		peers = append(peers, api.PeerInfo{Addr: addr, Connected: true, Outgoing: peer.Outgoing})
+	}
+
+	return peers
+}
+
+// advertisedAddr returns the dialable address of a peer, using the host it
+// connected from when it listens on all interfaces
+func advertisedAddr(from net.Addr, listenAddr string) string {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil || port == "" {
+		return ""
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		fromHost, _, err := net.SplitHostPort(from.String())
+		if err != nil {
+			return ""
+		}
+		host = fromHost
+	}
+
+	return net.JoinHostPort(host, port)
+}
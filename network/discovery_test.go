@@ -0,0 +1,76 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvertisedAddr(t *testing.T) {
+	from := &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 51234}
+
+	assert.Equal(t, "10.1.2.3:3000", advertisedAddr(from, ":3000"))
+	assert.Equal(t, "10.1.2.3:3000", advertisedAddr(from, "0.0.0.0:3000"))
+	assert.Equal(t, "node.example:3000", advertisedAddr(from, "node.example:3000"))
+	assert.Equal(t, "", advertisedAddr(from, "not-an-address"))
+}
+
+func TestServer_PeerExchange(t *testing.T) {
+	s, peers := newGossipTestServer(t, GossipConfig{}, 1)
+	// Keep the node full so learned peers are not dialed during the test
+	s.MaxPeers = 1
+
+	s.peerStore.MarkConnected("10.0.0.5:3000")
+
+	// The peer's status tells us where it listens and triggers a peer request
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: &StatusMessage{ID: "remote", ListenAddr: ":4000"}}))
+	assert.Equal(t, "127.0.0.1:4000", s.peerListenAddr(peers[0].addr))
+
+	msg := peers[0].receive(t)
+	require.NotNil(t, msg)
+	assert.IsType(t, &GetPeersMessage{}, msg.Data)
+
+	// Known peers are shared, except the requester itself
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: &GetPeersMessage{}}))
+	msg = peers[0].receive(t)
+	require.NotNil(t, msg)
+	assert.Equal(t, []string{"10.0.0.5:3000"}, msg.Data.(*PeersMessage).Addrs)
+
+	// Learned addresses are stored, malformed ones are ignored
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: &PeersMessage{Addrs: []string{"10.0.0.6:3000", "bogus"}}}))
+	addrs := []string{}
+	for _, record := range s.peerStore.List() {
+		addrs = append(addrs, record.Addr)
+	}
+	assert.Equal(t, []string{"10.0.0.5:3000", "10.0.0.6:3000", "127.0.0.1:4000"}, addrs)
+
+	info := s.Peers()
+	require.Len(t, info, 3)
+	assert.True(t, info[2].Connected)
+	assert.False(t, info[0].Connected)
+}
+
+func TestServer_BansMisbehavingPeer(t *testing.T) {
+	s, peers := newGossipTestServer(t, GossipConfig{}, 1)
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: &StatusMessage{ID: "remote", ListenAddr: ":4000"}}))
+
+	for i := 0; i < 5; i++ {
+		tx := &core.Transaction{TxInner: dao.VoteTx{Fee: int64(i + 1), Weight: 5}}
+		require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+		// Claiming another sender invalidates the signature
+		tx.From = crypto.GeneratePrivateKey().PublicKey()
+
+		assert.Error(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: tx}))
+	}
+
+	assert.True(t, s.peerStore.IsBanned("127.0.0.1:4000"))
+	assert.Empty(t, s.peerMap)
+
+	// A banned peer is refused when it connects again
+	assert.False(t, s.addPeer(&TCPPeer{conn: peers[0].remote, listenAddr: "127.0.0.1:4000"}))
+}
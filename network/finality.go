@@ -0,0 +1,72 @@
+package network
+
+import (
+	"errors"
+	"net"
+
+	"github.com/BOCK-CHAIN/BockChain/core"
+)
+
+// voteFinality signs and gossips this node's vote for a block when the node
+// is an active validator
+func (s *Server) voteFinality(b *core.Block, phase core.VotePhase) {
+	if s.PrivateKey == nil || !s.isActiveValidator() {
+		return
+	}
+
+	vote, err := core.NewFinalityVote(*s.PrivateKey, b.Height, b.Hash(core.BlockHasher{}), phase)
+	if err != nil {
+		s.Logger.Log("error", "failed to sign finality vote", "err", err)
+		return
+	}
+
+	if err := s.processFinalityVote(nil, vote); err != nil {
+		s.Logger.Log("error", "failed to cast finality vote", "err", err)
+	}
+}
+
+// isActiveValidator reports whether this node's key is in the validator set
+func (s *Server) isActiveValidator() bool {
+	address := s.PrivateKey.PublicKey().String()
+	for _, validator := range s.chain.ValidatorSet() {
+		if validator.Address.String() == address {
+			return true
+		}
+	}
+	return false
+}
+
+// processFinalityVote counts a finality vote and gossips it on. A prevote
+// quorum makes this node precommit, a precommit quorum finalizes the block.
+// from is nil for votes cast by this node.
+func (s *Server) processFinalityVote(from net.Addr, vote *core.FinalityVote) error {
+	if !s.gossip.MarkSeen(vote.ID()) {
+		return nil
+	}
+
+	quorum, err := s.chain.AddFinalityVote(vote)
+	if err != nil {
+		if errors.Is(err, core.ErrEquivocation) || vote.Verify() != nil {
+			s.penalizePeer(from, penaltyInvalidMessage)
+		}
+		return err
+	}
+
+	if err := s.gossipMessage(from, MessageTypeFinalityVote, vote); err != nil {
+		return err
+	}
+
+	if !quorum {
+		return nil
+	}
+
+	if vote.Phase == core.VotePhasePrevote {
+		block, err := s.chain.GetBlock(vote.Height)
+		if err != nil {
+			return err
+		}
+		s.voteFinality(block, core.VotePhasePrecommit)
+	}
+
+	return nil
+}
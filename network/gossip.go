@@ -0,0 +1,236 @@
+package network
+
+import (
+	"crypto/sha256"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+const (
+	DefaultSeenCacheSize       = 10000
+	DefaultRelayBytesPerSecond = 1 << 20
+)
+
+// GossipConfig configures how DAO transactions and announcements are relayed
+type GossipConfig struct {
+	SeenCacheSize       int // Number of message hashes remembered for deduplication
+	RelayBytesPerSecond int // Bandwidth budget for relaying messages received from peers
+}
+
+// GossipStats counts gossip traffic
+type GossipStats struct {
+	Received   uint64
+	Duplicates uint64
+	Relayed    uint64
+	Dropped    uint64 // Relays skipped because the bandwidth budget was spent
+}
+
+// Gossip deduplicates gossiped messages and limits the bandwidth spent
+// relaying messages that originated at other peers
+type Gossip struct {
+	mu sync.Mutex
+
+	seen      map[types.Hash]struct{}
+	seenOrder []types.Hash
+	seenSize  int
+
+	announced map[types.Hash]dao.ProposalStatus
+
+	rate       float64
+	allowance  float64
+	lastRefill time.Time
+
+	stats GossipStats
+}
+
+// NewGossip creates a new gossip state
+func NewGossip(config GossipConfig) *Gossip {
+	if config.SeenCacheSize <= 0 {
+		config.SeenCacheSize = DefaultSeenCacheSize
+	}
+	if config.RelayBytesPerSecond <= 0 {
+		config.RelayBytesPerSecond = DefaultRelayBytesPerSecond
+	}
+
+	return &Gossip{
+		seen:       make(map[types.Hash]struct{}),
+		seenSize:   config.SeenCacheSize,
+		announced:  make(map[types.Hash]dao.ProposalStatus),
+		rate:       float64(config.RelayBytesPerSecond),
+		allowance:  float64(config.RelayBytesPerSecond),
+		lastRefill: time.Now(),
+	}
+}
+
+// MarkSeen records a message hash and reports whether it was new. The
+// oldest hashes are forgotten once the cache is full.
+func (g *Gossip) MarkSeen(hash types.Hash) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.stats.Received++
+	if _, ok := g.seen[hash]; ok {
+		g.stats.Duplicates++
+		return false
+	}
+
+	if len(g.seenOrder) >= g.seenSize {
+		delete(g.seen, g.seenOrder[0])
+		g.seenOrder = g.seenOrder[1:]
+	}
+
+	g.seen[hash] = struct{}{}
+	g.seenOrder = append(g.seenOrder, hash)
+
+	return true
+}
+
+// AllowRelay spends size bytes of the relay budget, reporting false when
+// the budget is exhausted
+func (g *Gossip) AllowRelay(size int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	g.allowance += now.Sub(g.lastRefill).Seconds() * g.rate
+	if g.allowance > g.rate {
+		g.allowance = g.rate
+	}
+	g.lastRefill = now
+
+	if float64(size) > g.allowance {
+		g.stats.Dropped++
+		return false
+	}
+
+	g.allowance -= float64(size)
+	g.stats.Relayed++
+
+	return true
+}
+
+// markAnnounced records that a proposal status was announced, reporting
+// false if it already was
+func (g *Gossip) markAnnounced(proposalID types.Hash, status dao.ProposalStatus) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if previous, ok := g.announced[proposalID]; ok && previous == status {
+		return false
+	}
+
+	g.announced[proposalID] = status
+	return true
+}
+
+// Stats returns the gossip traffic counters
+func (g *Gossip) Stats() GossipStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.stats
+}
+
+// announcementKey identifies a proposal announcement for deduplication
+func announcementKey(proposalID types.Hash) types.Hash {
+	return sha256.Sum256(append([]byte("proposal"), proposalID.ToSlice()...))
+}
+
+// resultKey identifies a proposal result for deduplication
+func resultKey(proposalID types.Hash, status dao.ProposalStatus) types.Hash {
+	data := append([]byte("result"), proposalID.ToSlice()...)
+	return sha256.Sum256(append(data, byte(status)))
+}
+
+// announceDAOEvents gossips the proposals included in a block and the
+// proposals that have reached a final status since the last announcement
+func (s *Server) announceDAOEvents(b *core.Block) {
+	for _, tx := range b.Transactions {
+		var proposal *dao.ProposalTx
+		switch t := tx.TxInner.(type) {
+		case dao.ProposalTx:
+			proposal = &t
+		case *dao.ProposalTx:
+			proposal = t
+		default:
+			continue
+		}
+
+		announcement := &ProposalAnnouncementMessage{
+			ProposalID: tx.Hash(core.TxHasher{}),
+			Creator:    tx.From,
+			Title:      proposal.Title,
+			StartTime:  proposal.StartTime,
+			EndTime:    proposal.EndTime,
+			Height:     b.Height,
+		}
+		if !s.gossip.MarkSeen(announcementKey(announcement.ProposalID)) {
+			continue
+		}
+		if err := s.gossipMessage(nil, MessageTypeProposalAnnouncement, announcement); err != nil {
+			s.Logger.Log("error", "failed to announce proposal", "err", err)
+		}
+	}
+
+	if s.dao == nil {
+		return
+	}
+
+	for _, proposal := range s.dao.GovernanceState.Proposals {
+		if !isFinalProposalStatus(proposal.Status) || !s.gossip.markAnnounced(proposal.ID, proposal.Status) {
+			continue
+		}
+
+		result := &ProposalResultMessage{
+			ProposalID: proposal.ID,
+			Status:     proposal.Status,
+			Height:     b.Height,
+		}
+		if proposal.Results != nil {
+			result.YesVotes = proposal.Results.YesVotes
+			result.NoVotes = proposal.Results.NoVotes
+			result.AbstainVotes = proposal.Results.AbstainVotes
+		}
+
+		if !s.gossip.MarkSeen(resultKey(result.ProposalID, result.Status)) {
+			continue
+		}
+		if err := s.gossipMessage(nil, MessageTypeProposalResult, result); err != nil {
+			s.Logger.Log("error", "failed to announce proposal result", "err", err)
+		}
+	}
+}
+
+func (s *Server) processProposalAnnouncement(from net.Addr, data *ProposalAnnouncementMessage) error {
+	if !s.gossip.MarkSeen(announcementKey(data.ProposalID)) {
+		return nil
+	}
+
+	s.Logger.Log("msg", "received proposal announcement", "from", from, "proposal", data.ProposalID, "height", data.Height)
+
+	return s.gossipMessage(from, MessageTypeProposalAnnouncement, data)
+}
+
+func (s *Server) processProposalResult(from net.Addr, data *ProposalResultMessage) error {
+	if !s.gossip.MarkSeen(resultKey(data.ProposalID, data.Status)) {
+		return nil
+	}
+
+	s.Logger.Log("msg", "received proposal result", "from", from, "proposal", data.ProposalID, "status", data.Status, "height", data.Height)
+
+	return s.gossipMessage(from, MessageTypeProposalResult, data)
+}
+
+// isFinalProposalStatus reports whether a proposal can no longer change outcome
+func isFinalProposalStatus(status dao.ProposalStatus) bool {
+	switch status {
+	case dao.ProposalStatusPassed, dao.ProposalStatusRejected, dao.ProposalStatusExecuted, dao.ProposalStatusCancelled:
+		return true
+	}
+	return false
+}
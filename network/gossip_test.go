@@ -0,0 +1,214 @@
+package network
+
+import (
+	"bytes"
+	"encoding/gob"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/api"
+	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPeer is a loopback connection registered as a peer of a server
+type testPeer struct {
+	addr    net.Addr
+	remote  net.Conn
+	pending bytes.Buffer
+}
+
+func newGossipTestServer(t *testing.T, config GossipConfig, peers int) (*Server, []*testPeer) {
+	s, err := NewServer(ServerOpts{ID: "gossip-test", Logger: log.NewNopLogger(), Gossip: config})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	testPeers := make([]*testPeer, peers)
+	for i := range testPeers {
+		remote, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		local, err := ln.Accept()
+		require.NoError(t, err)
+		t.Cleanup(func() { remote.Close(); local.Close() })
+
+		s.peerMap[local.RemoteAddr()] = &TCPPeer{conn: local}
+		testPeers[i] = &testPeer{addr: local.RemoteAddr(), remote: remote}
+	}
+
+	return s, testPeers
+}
+
+// receive decodes the next message sent to the peer, or returns nil.
+// Messages written back to back may arrive in a single read.
+func (p *testPeer) receive(t *testing.T) *DecodedMessage {
+	if p.pending.Len() == 0 {
+		p.remote.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		buf := make([]byte, 4096)
+		n, err := p.remote.Read(buf)
+		if err != nil {
+			return nil
+		}
+		p.pending.Write(buf[:n])
+	}
+
+	msg := Message{}
+	require.NoError(t, gob.NewDecoder(&p.pending).Decode(&msg))
+
+	decoded, err := DefaultRPCDecodeFunc(RPC{From: p.addr, Payload: bytes.NewReader(msg.Bytes())})
+	require.NoError(t, err)
+	return decoded
+}
+
+func newSignedDAOTx(t *testing.T) *core.Transaction {
+	tx := &core.Transaction{
+		TxInner: dao.VoteTx{Fee: 10, ProposalID: types.Hash{0x01}, Choice: dao.VoteChoiceYes, Weight: 5},
+	}
+	require.NoError(t, tx.Sign(crypto.GeneratePrivateKey()))
+	return tx
+}
+
+func TestGossip_SeenCacheEviction(t *testing.T) {
+	g := NewGossip(GossipConfig{SeenCacheSize: 2})
+
+	assert.True(t, g.MarkSeen(types.Hash{0x01}))
+	assert.False(t, g.MarkSeen(types.Hash{0x01}))
+	assert.True(t, g.MarkSeen(types.Hash{0x02}))
+	assert.True(t, g.MarkSeen(types.Hash{0x03}))
+
+	// The oldest hash was forgotten
+	assert.True(t, g.MarkSeen(types.Hash{0x01}))
+
+	stats := g.Stats()
+	assert.Equal(t, uint64(5), stats.Received)
+	assert.Equal(t, uint64(1), stats.Duplicates)
+}
+
+func TestGossip_RelayBudget(t *testing.T) {
+	g := NewGossip(GossipConfig{RelayBytesPerSecond: 100})
+
+	assert.True(t, g.AllowRelay(60))
+	assert.False(t, g.AllowRelay(60))
+	assert.True(t, g.AllowRelay(30))
+
+	stats := g.Stats()
+	assert.Equal(t, uint64(2), stats.Relayed)
+	assert.Equal(t, uint64(1), stats.Dropped)
+}
+
+func TestServer_RelaysDAOTxOnce(t *testing.T) {
+	s, peers := newGossipTestServer(t, GossipConfig{}, 3)
+	tx := newSignedDAOTx(t)
+
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: tx}))
+	assert.True(t, s.mempool.Contains(tx.Hash(core.TxHasher{})))
+
+	// Every peer but the sender receives the transaction
+	assert.Nil(t, peers[0].receive(t))
+	for _, peer := range peers[1:] {
+		msg := peer.receive(t)
+		require.NotNil(t, msg)
+		relayed, ok := msg.Data.(*core.Transaction)
+		require.True(t, ok)
+		assert.Equal(t, tx.Hash(core.TxHasher{}), relayed.Hash(core.TxHasher{}))
+	}
+
+	// The same transaction from another peer is not relayed again
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[1].addr, Data: tx}))
+	for _, peer := range peers {
+		assert.Nil(t, peer.receive(t))
+	}
+	assert.Equal(t, uint64(1), s.gossip.Stats().Duplicates)
+}
+
+func TestServer_SubmitTransaction(t *testing.T) {
+	s, peers := newGossipTestServer(t, GossipConfig{}, 1)
+
+	// Local submissions are checked before they are queued and relayed
+	tx := newSignedDAOTx(t)
+	require.NoError(t, s.SubmitTransaction(tx))
+	assert.True(t, s.mempool.Contains(tx.Hash(core.TxHasher{})))
+	assert.NotNil(t, peers[0].receive(t))
+
+	// A payload rewritten after signing fails
+	signed := newSignedDAOTx(t)
+	forged := &core.Transaction{TxInner: dao.VoteTx{Fee: 99, Weight: 5}, From: signed.From, Signature: signed.Signature, Nonce: signed.Nonce}
+	assert.ErrorIs(t, s.SubmitTransaction(forged), api.ErrTxInvalid)
+	assert.False(t, s.mempool.Contains(forged.Hash(core.TxHasher{})))
+	assert.Nil(t, peers[0].receive(t))
+
+	status, _ := s.chain.TxTracker().Status(forged.Hash(core.TxHasher{}))
+	assert.Equal(t, core.TxRejected, status.State)
+
+	// Resubmissions are known, and a stopped pipeline can be retried later
+	assert.ErrorIs(t, s.SubmitTransaction(tx), api.ErrTxKnown)
+	s.pipeline.Stop()
+	assert.ErrorIs(t, s.SubmitTransaction(newSignedDAOTx(t)), api.ErrTxUnavailable)
+}
+
+func TestServer_RelayBandwidthLimit(t *testing.T) {
+	s, peers := newGossipTestServer(t, GossipConfig{RelayBytesPerSecond: 1}, 2)
+	tx := newSignedDAOTx(t)
+
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: tx}))
+	assert.Nil(t, peers[1].receive(t))
+	assert.Equal(t, uint64(1), s.gossip.Stats().Dropped)
+
+	// Transactions submitted to this node are not limited
+	local := newSignedDAOTx(t)
+	require.NoError(t, s.processTransaction(nil, local))
+	assert.NotNil(t, peers[0].receive(t))
+	assert.NotNil(t, peers[1].receive(t))
+}
+
+func TestServer_AnnouncesProposalsAndResults(t *testing.T) {
+	s, peers := newGossipTestServer(t, GossipConfig{}, 1)
+	s.dao = dao.NewDAO("GOV", "Governance Token", 18)
+
+	creator := crypto.GeneratePrivateKey()
+	tx := &core.Transaction{TxInner: dao.ProposalTx{Fee: 10, Title: "Upgrade", StartTime: 1, EndTime: 2}}
+	require.NoError(t, tx.Sign(creator))
+
+	block, err := core.NewBlock(&core.Header{Height: 1}, []*core.Transaction{tx})
+	require.NoError(t, err)
+
+	proposalID := types.Hash{0x0A}
+	s.dao.GovernanceState.Proposals[proposalID] = &dao.Proposal{
+		ID: proposalID, Status: dao.ProposalStatusPassed, Results: &dao.VoteResults{YesVotes: 7, NoVotes: 2},
+	}
+
+	s.announceDAOEvents(block)
+
+	msg := peers[0].receive(t)
+	require.NotNil(t, msg)
+	announcement, ok := msg.Data.(*ProposalAnnouncementMessage)
+	require.True(t, ok)
+	assert.Equal(t, tx.Hash(core.TxHasher{}), announcement.ProposalID)
+	assert.Equal(t, "Upgrade", announcement.Title)
+
+	msg = peers[0].receive(t)
+	require.NotNil(t, msg)
+	result, ok := msg.Data.(*ProposalResultMessage)
+	require.True(t, ok)
+	assert.Equal(t, proposalID, result.ProposalID)
+	assert.Equal(t, uint64(7), result.YesVotes)
+
+	// Results are announced once per status, and echoes from peers are dropped
+	s.announceDAOEvents(block)
+	assert.Nil(t, peers[0].receive(t))
+	require.NoError(t, s.ProcessMessage(&DecodedMessage{From: peers[0].addr, Data: result}))
+
+	s.dao.GovernanceState.Proposals[proposalID].Status = dao.ProposalStatusExecuted
+	s.announceDAOEvents(block)
+	msg = peers[0].receive(t)
+	require.NotNil(t, msg)
+	assert.Equal(t, dao.ProposalStatusExecuted, msg.Data.(*ProposalResultMessage).Status)
+}
@@ -1,6 +1,11 @@
 package network
 
-import "github.com/BOCK-CHAIN/BockChain/core"
+import (
+	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
 
 type GetBlocksMessage struct {
 	From uint32
@@ -19,4 +24,34 @@ type StatusMessage struct {
 	ID            string
 	Version       uint32
 	CurrentHeight uint32
+	// the address the server accepts connections on
+	ListenAddr string
+}
+
+// GetPeersMessage asks a peer for the addresses of the peers it knows
+type GetPeersMessage struct{}
+
+// PeersMessage shares known peer addresses for peer exchange
+type PeersMessage struct {
+	Addrs []string
+}
+
+// ProposalAnnouncementMessage tells peers about a proposal included in a block
+type ProposalAnnouncementMessage struct {
+	ProposalID types.Hash
+	Creator    crypto.PublicKey
+	Title      string
+	StartTime  int64
+	EndTime    int64
+	Height     uint32
+}
+
+// ProposalResultMessage tells peers about a proposal that reached a final status
+type ProposalResultMessage struct {
+	ProposalID   types.Hash
+	Status       dao.ProposalStatus
+	YesVotes     uint64
+	NoVotes      uint64
+	AbstainVotes uint64
+	Height       uint32
 }
@@ -0,0 +1,264 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPeerBanDuration = time.Hour
+
+	// Peers are banned once their score drops to PeerBanThreshold
+	PeerBanThreshold = -100
+	peerScoreMax     = 100
+
+	penaltyInvalidMessage = 20
+	penaltyDialFailure    = 5
+)
+
+// PeerRecord is what the peer store remembers about a peer, keyed by the
+// address it accepts connections on
+type PeerRecord struct {
+	Addr        string `json:"addr"`
+	Score       int    `json:"score"`
+	BannedUntil int64  `json:"banned_until,omitempty"`
+	LastSeen    int64  `json:"last_seen,omitempty"`
+	Failures    int    `json:"failures,omitempty"` // Consecutive failed dials
+}
+
+// PeerStore keeps the addresses of known peers and their scores, and
+// persists them so a restarted node can reconnect without a bootstrap list
+type PeerStore struct {
+	mu          sync.Mutex
+	path        string
+	banDuration time.Duration
+	peers       map[string]*PeerRecord
+	dirty       bool
+}
+
+// NewPeerStore creates a peer store persisted at path, loading it if the
+// file exists. An empty path keeps the store in memory.
+func NewPeerStore(path string, banDuration time.Duration) (*PeerStore, error) {
+	if banDuration <= 0 {
+		banDuration = DefaultPeerBanDuration
+	}
+
+	ps := &PeerStore{
+		path:        path,
+		banDuration: banDuration,
+		peers:       make(map[string]*PeerRecord),
+	}
+
+	if path == "" {
+		return ps, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ps, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := []*PeerRecord{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		ps.peers[record.Addr] = record
+	}
+
+	return ps, nil
+}
+
+// Add records a peer address, reporting whether it was new
+func (ps *PeerStore) Add(addr string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.peers[addr]; ok {
+		return false
+	}
+
+	ps.peers[addr] = &PeerRecord{Addr: addr}
+	ps.dirty = true
+
+	return true
+}
+
+// MarkConnected records a successful connection to a peer
+func (ps *PeerStore) MarkConnected(addr string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	record := ps.record(addr)
+	record.LastSeen = time.Now().Unix()
+	record.Failures = 0
+	ps.dirty = true
+}
+
+// MarkDialFailed records a failed connection attempt to a peer
+func (ps *PeerStore) MarkDialFailed(addr string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	record := ps.record(addr)
+	record.Failures++
+	ps.adjust(record, -penaltyDialFailure)
+}
+
+// Reward raises the score of a peer that behaved well
+func (ps *PeerStore) Reward(addr string, delta int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.adjust(ps.record(addr), delta)
+}
+
+// Penalize lowers the score of a misbehaving peer and reports whether the
+// peer is now banned
+func (ps *PeerStore) Penalize(addr string, penalty int) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	record := ps.record(addr)
+	ps.adjust(record, -penalty)
+
+	return record.BannedUntil > time.Now().Unix()
+}
+
+// IsBanned reports whether a peer is currently banned
+func (ps *PeerStore) IsBanned(addr string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	record, ok := ps.peers[addr]
+	return ok && ps.banned(record, time.Now().Unix())
+}
+
+// Candidates returns up to n addresses to dial, skipping banned and
+// excluded peers, best scored first
+func (ps *PeerStore) Candidates(n int, exclude map[string]bool) []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	now := time.Now().Unix()
+	records := make([]*PeerRecord, 0, len(ps.peers))
+	for addr, record := range ps.peers {
+		if exclude[addr] || ps.banned(record, now) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Score != records[j].Score {
+			return records[i].Score > records[j].Score
+		}
+		if records[i].Failures != records[j].Failures {
+			return records[i].Failures < records[j].Failures
+		}
+		return records[i].Addr < records[j].Addr
+	})
+
+	if len(records) > n {
+		records = records[:n]
+	}
+
+	addrs := make([]string, len(records))
+	for i, record := range records {
+		addrs[i] = record.Addr
+	}
+
+	return addrs
+}
+
+// List returns a copy of every peer record, ordered by address
+func (ps *PeerStore) List() []PeerRecord {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	records := make([]PeerRecord, 0, len(ps.peers))
+	for _, record := range ps.peers {
+		records = append(records, *record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Addr < records[j].Addr
+	})
+
+	return records
+}
+
+// Save writes the store to disk if it changed since the last save
+func (ps *PeerStore) Save() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.path == "" || !ps.dirty {
+		return nil
+	}
+
+	records := make([]*PeerRecord, 0, len(ps.peers))
+	for _, record := range ps.peers {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Addr < records[j].Addr
+	})
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a torn store
+	tmp := ps.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, ps.path); err != nil {
+		return err
+	}
+
+	ps.dirty = false
+	return nil
+}
+
+// record returns the record of addr, creating it if needed. The caller
+// must hold the lock.
+func (ps *PeerStore) record(addr string) *PeerRecord {
+	record, ok := ps.peers[addr]
+	if !ok {
+		record = &PeerRecord{Addr: addr}
+		ps.peers[addr] = record
+	}
+	return record
+}
+
+// adjust changes the score of a peer within bounds and bans it when the
+// score reaches the ban threshold. The caller must hold the lock.
+func (ps *PeerStore) adjust(record *PeerRecord, delta int) {
+	record.Score += delta
+	if record.Score > peerScoreMax {
+		record.Score = peerScoreMax
+	}
+
+	if record.Score <= PeerBanThreshold {
+		record.BannedUntil = time.Now().Add(ps.banDuration).Unix()
+		record.Score = 0
+	}
+
+	ps.dirty = true
+}
+
+// banned reports whether a record is banned at now. The caller must hold
+// the lock.
+func (ps *PeerStore) banned(record *PeerRecord, now int64) bool {
+	return record.BannedUntil > now
+}
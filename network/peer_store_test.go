@@ -0,0 +1,50 @@
+package network
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerStore_ScoringAndBans(t *testing.T) {
+	ps, err := NewPeerStore("", 0)
+	require.NoError(t, err)
+
+	assert.True(t, ps.Add("10.0.0.1:3000"))
+	assert.False(t, ps.Add("10.0.0.1:3000"))
+	ps.Add("10.0.0.2:3000")
+	ps.Reward("10.0.0.2:3000", 5)
+
+	assert.Equal(t, []string{"10.0.0.2:3000", "10.0.0.1:3000"}, ps.Candidates(10, nil))
+	assert.Equal(t, []string{"10.0.0.1:3000"}, ps.Candidates(10, map[string]bool{"10.0.0.2:3000": true}))
+
+	for i := 0; i < 4; i++ {
+		assert.False(t, ps.Penalize("10.0.0.1:3000", penaltyInvalidMessage))
+	}
+	assert.True(t, ps.Penalize("10.0.0.1:3000", penaltyInvalidMessage))
+	assert.True(t, ps.IsBanned("10.0.0.1:3000"))
+	assert.Equal(t, []string{"10.0.0.2:3000"}, ps.Candidates(10, nil))
+}
+
+func TestPeerStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peers.json")
+
+	ps, err := NewPeerStore(path, 0)
+	require.NoError(t, err)
+	ps.Add("10.0.0.1:3000")
+	ps.MarkConnected("10.0.0.1:3000")
+	ps.MarkDialFailed("10.0.0.2:3000")
+	require.NoError(t, ps.Save())
+
+	reloaded, err := NewPeerStore(path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, ps.List(), reloaded.List())
+
+	records := reloaded.List()
+	require.Len(t, records, 2)
+	assert.NotZero(t, records[0].LastSeen)
+	assert.Equal(t, 1, records[1].Failures)
+	assert.Equal(t, -penaltyDialFailure, records[1].Score)
+}
@@ -0,0 +1,70 @@
+package network
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/core"
+)
+
+// replicaRetryInterval is how long a replica waits before reconnecting to
+// its primary after the block feed fails
+const replicaRetryInterval = 5 * time.Second
+
+// followPrimary keeps the chain of a read replica in step with the primary
+// named by ReplicaOf until ctx is done, reconnecting after failures
+func (s *Server) followPrimary(ctx context.Context) {
+	for {
+		if err := s.syncFromPrimary(ctx); err != nil && ctx.Err() == nil {
+			s.Logger.Log("msg", "replica feed interrupted", "primary", s.ReplicaOf, "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(replicaRetryInterval):
+		}
+	}
+}
+
+// syncFromPrimary streams blocks from the feed of the primary into the chain
+// until the feed ends. Every block is validated as if a peer had sent it.
+func (s *Server) syncFromPrimary(ctx context.Context) error {
+	url := fmt.Sprintf("%s/chain/feed?from=%d", strings.TrimRight(s.ReplicaOf, "/"), s.chain.Height()+1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("block feed returned %s", res.Status)
+	}
+
+	s.Logger.Log("msg", "following primary", "primary", s.ReplicaOf, "height", s.chain.Height())
+
+	decoder := gob.NewDecoder(res.Body)
+	for {
+		block := new(core.Block)
+		if err := decoder.Decode(block); err != nil {
+			return err
+		}
+
+		if err := s.chain.AddBlock(block); err != nil {
+			if errors.Is(err, core.ErrBlockKnown) {
+				continue
+			}
+			return fmt.Errorf("rejected block (%d) from primary: %w", block.Height, err)
+		}
+	}
+}
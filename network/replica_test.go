@@ -0,0 +1,69 @@
+package network
+
+import (
+	"context"
+	"encoding/gob"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_FollowPrimary(t *testing.T) {
+	primary, err := NewServer(ServerOpts{ID: "primary", Logger: log.NewNopLogger()})
+	require.NoError(t, err)
+	privKey := crypto.GeneratePrivateKey()
+	primary.PrivateKey = &privKey
+	for i := 0; i < 3; i++ {
+		require.NoError(t, primary.createNewBlock())
+	}
+
+	// The feed serves the primary's blocks from the requested height on
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("from"))
+		encoder := gob.NewEncoder(w)
+		for height := uint32(1); height <= primary.chain.Height(); height++ {
+			block, err := primary.chain.GetBlock(height)
+			require.NoError(t, err)
+			require.NoError(t, encoder.Encode(block))
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer feed.Close()
+
+	replica, err := NewServer(ServerOpts{ID: "replica", Logger: log.NewNopLogger(), ReplicaOf: feed.URL + "/"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		replica.followPrimary(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return replica.chain.Height() == 3 }, 2*time.Second, 10*time.Millisecond)
+	primaryHead, err := primary.chain.GetHeader(3)
+	require.NoError(t, err)
+	replicaHead, err := replica.chain.GetHeader(3)
+	require.NoError(t, err)
+	assert.Equal(t, primaryHead, replicaHead)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("replica did not stop following")
+	}
+}
+
+func TestNewServer_ReplicaCannotValidate(t *testing.T) {
+	privKey := crypto.GeneratePrivateKey()
+	_, err := NewServer(ServerOpts{Logger: log.NewNopLogger(), ReplicaOf: "http://primary:9000", PrivateKey: &privKey})
+	assert.Error(t, err)
+}
@@ -21,6 +21,14 @@ const (
 	MessageTypeStatus    MessageType = 0x4
 	MessageTypeGetStatus MessageType = 0x5
 	MessageTypeBlocks    MessageType = 0x6
+
+	MessageTypeProposalAnnouncement MessageType = 0x7
+	MessageTypeProposalResult       MessageType = 0x8
+
+	MessageTypeGetPeers MessageType = 0x9
+	MessageTypePeers    MessageType = 0xa
+
+	MessageTypeFinalityVote MessageType = 0xb
 )
 
 type RPC struct {
@@ -69,7 +77,7 @@ func DefaultRPCDecodeFunc(rpc RPC) (*DecodedMessage, error) {
 	switch msg.Header {
 	case MessageTypeTx:
 		tx := new(core.Transaction)
-		if err := tx.Decode(core.NewGobTxDecoder(bytes.NewReader(msg.Data))); err != nil {
+		if err := tx.Decode(core.NewBinaryTxDecoder(bytes.NewReader(msg.Data))); err != nil {
 			return nil, err
 		}
 
@@ -80,7 +88,7 @@ func DefaultRPCDecodeFunc(rpc RPC) (*DecodedMessage, error) {
 
 	case MessageTypeBlock:
 		block := new(core.Block)
-		if err := block.Decode(core.NewGobBlockDecoder(bytes.NewReader(msg.Data))); err != nil {
+		if err := block.Decode(core.NewBinaryBlockDecoder(bytes.NewReader(msg.Data))); err != nil {
 			return nil, err
 		}
 
@@ -128,6 +136,56 @@ func DefaultRPCDecodeFunc(rpc RPC) (*DecodedMessage, error) {
 			Data: blocks,
 		}, nil
 
+	case MessageTypeProposalAnnouncement:
+		announcement := new(ProposalAnnouncementMessage)
+		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(announcement); err != nil {
+			return nil, err
+		}
+
+		return &DecodedMessage{
+			From: rpc.From,
+			Data: announcement,
+		}, nil
+
+	case MessageTypeProposalResult:
+		result := new(ProposalResultMessage)
+		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(result); err != nil {
+			return nil, err
+		}
+
+		return &DecodedMessage{
+			From: rpc.From,
+			Data: result,
+		}, nil
+
+	case MessageTypeGetPeers:
+		return &DecodedMessage{
+			From: rpc.From,
+			Data: &GetPeersMessage{},
+		}, nil
+
+	case MessageTypePeers:
+		peers := new(PeersMessage)
+		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(peers); err != nil {
+			return nil, err
+		}
+
+		return &DecodedMessage{
+			From: rpc.From,
+			Data: peers,
+		}, nil
+
+	case MessageTypeFinalityVote:
+		vote := new(core.FinalityVote)
+		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(vote); err != nil {
+			return nil, err
+		}
+
+		return &DecodedMessage{
+			From: rpc.From,
+			Data: vote,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("invalid message header %x", msg.Header)
 	}
@@ -2,7 +2,9 @@ package network
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -10,9 +12,11 @@ import (
 	"time"
 
 	"github.com/BOCK-CHAIN/BockChain/api"
+	"github.com/BOCK-CHAIN/BockChain/config"
 	"github.com/BOCK-CHAIN/BockChain/core"
 	"github.com/BOCK-CHAIN/BockChain/crypto"
 	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/logging"
 	"github.com/BOCK-CHAIN/BockChain/types"
 	"github.com/go-kit/log"
 )
@@ -30,18 +34,84 @@ type ServerOpts struct {
 	RPCProcessor  RPCProcessor
 	BlockTime     time.Duration
 	PrivateKey    *crypto.PrivateKey
+	Pruning       core.PruningConfig
+	Gossip        GossipConfig
+	// PeerStorePath persists discovered peers across restarts, peers are
+	// kept in memory when it is empty
+	PeerStorePath     string
+	MaxPeers          int
+	DiscoveryInterval time.Duration
+	// Config holds the DAO and API settings of the node, the defaults are
+	// used when nil
+	Config *config.Config
+	// ReplicaOf makes the node a read replica following the full node with
+	// this API URL instead of joining the network
+	ReplicaOf string
+	// LogControl changes the level and rotates the file of Logger at
+	// runtime through the admin API
+	LogControl *logging.Logger
+}
+
+// ServerOptsFromConfig returns the options of a node described by a
+// configuration, decrypting its validator key from the keystore
+func ServerOptsFromConfig(cfg *config.Config) (ServerOpts, error) {
+	opts := ServerOpts{
+		ID:                cfg.Node.ID,
+		ListenAddr:        cfg.Node.ListenAddr,
+		SeedNodes:         cfg.Node.SeedNodes,
+		BlockTime:         cfg.Node.BlockTime,
+		PeerStorePath:     cfg.Node.PeerStorePath,
+		MaxPeers:          cfg.Node.MaxPeers,
+		DiscoveryInterval: cfg.Node.DiscoveryInterval,
+		APIListenAddr:     cfg.Server.ListenAddr,
+		Config:            cfg,
+		ReplicaOf:         cfg.Node.ReplicaOf,
+	}
+
+	logLevel, err := logging.ParseLevel(cfg.Node.LogLevel)
+	if err != nil {
+		return opts, err
+	}
+	logger, err := logging.New(cfg.Node.LogFile, logLevel)
+	if err != nil {
+		return opts, fmt.Errorf("failed to open log file: %w", err)
+	}
+	opts.LogControl = logger
+	opts.Logger = log.With(logger, "addr", cfg.Node.ID)
+
+	if cfg.Node.ValidatorKey != "" {
+		keystore, err := crypto.NewKeystore(cfg.Node.KeystoreDir, crypto.StandardScryptN)
+		if err != nil {
+			return opts, err
+		}
+
+		privKey, err := keystore.Load(cfg.Node.ValidatorKey, cfg.Node.KeystorePassphrase)
+		if err != nil {
+			return opts, fmt.Errorf("failed to load validator key %s: %w", cfg.Node.ValidatorKey, err)
+		}
+		opts.PrivateKey = &privKey
+	}
+
+	return opts, nil
 }
 
 type Server struct {
 	TCPTransport *TCPTransport
 	peerCh       chan *TCPPeer
 
-	mu      sync.RWMutex
-	peerMap map[net.Addr]*TCPPeer
+	mu        sync.RWMutex
+	peerMap   map[net.Addr]*TCPPeer
+	dialing   map[string]bool
+	selfAddrs map[string]bool
+	peerStore *PeerStore
 
 	ServerOpts
 	mempool     *TxPool
 	chain       *core.Blockchain
+	dao         *dao.DAO
+	scheduler   *dao.Scheduler
+	gossip      *Gossip
+	pipeline    *dao.TxPipeline
 	isValidator bool
 	rpcCh       chan RPC
 	quitCh      chan struct{}
@@ -59,31 +129,109 @@ func NewServer(opts ServerOpts) (*Server, error) {
 		opts.Logger = log.NewLogfmtLogger(os.Stderr)
 		opts.Logger = log.With(opts.Logger, "addr", opts.ID)
 	}
+	if opts.MaxPeers <= 0 {
+		opts.MaxPeers = DefaultMaxPeers
+	}
+	if opts.DiscoveryInterval == time.Duration(0) {
+		opts.DiscoveryInterval = DefaultDiscoveryInterval
+	}
+	if opts.Config == nil {
+		opts.Config = config.Default()
+	}
+	if opts.ReplicaOf != "" && opts.PrivateKey != nil {
+		return nil, fmt.Errorf("a read replica cannot be a validator")
+	}
+
+	peerStore, err := NewPeerStore(opts.PeerStorePath, DefaultPeerBanDuration)
+	if err != nil {
+		return nil, err
+	}
 
 	chain, err := core.NewBlockchain(opts.Logger, genesisBlock())
 	if err != nil {
 		return nil, err
 	}
 
+	if err := chain.SetPruning(opts.Pruning); err != nil {
+		return nil, err
+	}
+
 	// Channel being used to communicate between the JSON RPC server
 	// and the node that will process this message.
 	txChan := make(chan *core.Transaction)
 
+	var (
+		daoInstance *dao.DAO
+		daoServer   *api.DAOServer
+		scheduler   *dao.Scheduler
+	)
+
 	// Only boot up the API server if the config has a valid port number.
 	if len(opts.APIListenAddr) > 0 {
 		apiServerCfg := api.ServerConfig{
 			Logger:     opts.Logger,
+			LogControl: opts.LogControl,
 			ListenAddr: opts.APIListenAddr,
+			Config:     opts.Config,
 		}
 
 		// Initialize DAO instance
-		daoInstance := dao.NewDAO("PX", "ProjectX Token", 18)
+		daoConfig := opts.Config.DAO
+		daoInstance = dao.NewDAO(daoConfig.TokenSymbol, daoConfig.TokenName, daoConfig.TokenDecimals)
+		daoInstance.IPFSClient = dao.NewIPFSClientWithStore(contentStore(daoConfig))
+		daoInstance.EnableIPFSPinning(ipfsPinningConfig(daoConfig.IPFSPinning))
+		if _, err := daoInstance.EnableMetadataCache(dao.MetadataCacheConfig{
+			MaxEntries: daoConfig.MetadataCache.MaxEntries,
+			MaxBytes:   daoConfig.MetadataCache.MaxBytes,
+			TTL:        daoConfig.MetadataCache.TTL,
+			Dir:        daoConfig.MetadataCache.Dir,
+		}); err != nil {
+			return nil, err
+		}
+		if archive := daoConfig.Archive; archive.Dir != "" {
+			if _, err := daoInstance.EnableArchive(dao.ArchiveConfig{
+				Dir:            archive.Dir,
+				MaxProposals:   archive.MaxProposals,
+				MaxTreasuryTxs: archive.MaxTreasuryTxs,
+				CacheEntries:   archive.CacheEntries,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		notifiers, err := notifiers(daoConfig.Notifications)
+		if err != nil {
+			return nil, err
+		}
+		daoInstance.Notifications = dao.NewNotificationService(daoConfig.Notifications.QueueSize)
+		daoInstance.EnableNotifications(notifiers...)
+		daoInstance.Webhooks = dao.NewWebhookManager(dao.WebhookConfig{
+			MaxAttempts:    daoConfig.Webhooks.MaxAttempts,
+			InitialBackoff: daoConfig.Webhooks.InitialBackoff,
+			MaxBackoff:     daoConfig.Webhooks.MaxBackoff,
+			Timeout:        daoConfig.Webhooks.Timeout,
+		})
+		daoInstance.EnableMetrics(daoConfig.Analytics.Retention)
+
+		// Route all DAO state transitions through block processing, the
+		// registry hosting the DAOs created alongside the default one
+		registry := dao.NewDAORegistry(daoInstance)
+		chain.RegisterDAOStateMachine(registry)
+		registry.SetChainSubmitter(chain)
+
+		// Validators sign the results of finalized proposals onto IPFS
+		if opts.PrivateKey != nil {
+			daoInstance.EnableResultAnchoring(*opts.PrivateKey, chain.Height)
+		}
 
-		// Create DAO-enhanced API server
-		daoServer := api.NewDAOServer(apiServerCfg, chain, txChan, daoInstance)
-		go daoServer.Start()
+		// Maintenance jobs change DAO state in step with block processing
+		if scheduler, err = maintenanceScheduler(registry, chain, daoConfig); err != nil {
+			return nil, err
+		}
 
-		opts.Logger.Log("msg", "DAO API server running", "port", opts.APIListenAddr)
+		// Create DAO-enhanced API server
+		daoServer = api.NewDAOServer(apiServerCfg, chain, txChan, daoInstance)
+		daoServer.SetScheduler(scheduler)
+		daoServer.SetRegistry(registry)
 	}
 
 	peerCh := make(chan *TCPPeer)
@@ -93,8 +241,14 @@ func NewServer(opts ServerOpts) (*Server, error) {
 		TCPTransport: tr,
 		peerCh:       peerCh,
 		peerMap:      make(map[net.Addr]*TCPPeer),
+		dialing:      make(map[string]bool),
+		selfAddrs:    make(map[string]bool),
+		peerStore:    peerStore,
 		ServerOpts:   opts,
 		chain:        chain,
+		dao:          daoInstance,
+		scheduler:    scheduler,
+		gossip:       NewGossip(opts.Gossip),
 		mempool:      NewTxPool(1000),
 		isValidator:  opts.PrivateKey != nil,
 		rpcCh:        make(chan RPC),
@@ -104,12 +258,28 @@ func NewServer(opts ServerOpts) (*Server, error) {
 
 	s.TCPTransport.peerCh = peerCh
 
+	// Transactions are verified side by side and queued in the mempool in
+	// the order each sender submitted them
+	s.pipeline = dao.NewTxPipelineWithStages(0, dao.PipelineStages{
+		Check: checkTransaction,
+		Apply: s.queueTransaction,
+	})
+
 	// If we dont got any processor from the server options, we going to use
 	// the server as default.
 	if s.RPCProcessor == nil {
 		s.RPCProcessor = s
 	}
 
+	if daoServer != nil {
+		daoServer.SetPeerSource(s)
+		daoServer.SetTxSubmitter(s)
+		go daoServer.Start()
+		scheduler.Start()
+
+		opts.Logger.Log("msg", "DAO API server running", "port", opts.APIListenAddr)
+	}
+
 	if s.isValidator {
 		go s.validatorLoop()
 	}
@@ -117,30 +287,38 @@ func NewServer(opts ServerOpts) (*Server, error) {
 	return s, nil
 }
 
+// bootstrapNetwork connects to the seed nodes and to the best peers
+// remembered from earlier runs
 func (s *Server) bootstrapNetwork() {
 	for _, addr := range s.SeedNodes {
-		fmt.Println("trying to connect to ", addr)
-
-		go func(addr string) {
-			conn, err := net.Dial("tcp", addr)
-			if err != nil {
-				fmt.Printf("could not connect to %+v\n", conn)
-				return
-			}
-
-			s.peerCh <- &TCPPeer{
-				conn: conn,
-			}
-		}(addr)
+		s.peerStore.Add(addr)
+		s.dialPeer(addr)
 	}
+
+	s.dialCandidates()
 }
 
 func (s *Server) Start() {
+	// Read replicas only follow their primary and serve the API
+	if s.ReplicaOf != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		go s.followPrimary(ctx)
+
+		<-s.quitCh
+		cancel()
+		s.stopScheduler()
+		s.pipeline.Stop()
+
+		s.Logger.Log("msg", "Server is shutting down")
+		return
+	}
+
 	s.TCPTransport.Start()
 
 	time.Sleep(time.Second * 1)
 
 	s.bootstrapNetwork()
+	go s.discoveryLoop()
 
 	s.Logger.Log("msg", "accepting TCP connection on", "addr", s.ListenAddr, "id", s.ID)
 
@@ -148,7 +326,9 @@ free:
 	for {
 		select {
 		case peer := <-s.peerCh:
-			s.peerMap[peer.conn.RemoteAddr()] = peer
+			if !s.addPeer(peer) {
+				continue
+			}
 
 			go peer.readLoop(s.rpcCh)
 
@@ -160,7 +340,7 @@ free:
 			s.Logger.Log("msg", "peer added to the server", "outgoing", peer.Outgoing, "addr", peer.conn.RemoteAddr())
 
 		case tx := <-s.txChan:
-			if err := s.processTransaction(tx); err != nil {
+			if err := s.processTransaction(nil, tx); err != nil {
 				s.Logger.Log("process TX error", err)
 			}
 
@@ -182,9 +362,18 @@ free:
 		}
 	}
 
+	s.stopScheduler()
+	s.pipeline.Stop()
 	s.Logger.Log("msg", "Server is shutting down")
 }
 
+// stopScheduler ends the maintenance jobs of nodes serving the API
+func (s *Server) stopScheduler() {
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+	}
+}
+
 func (s *Server) validatorLoop() {
 	ticker := time.NewTicker(s.BlockTime)
 
@@ -204,9 +393,9 @@ func (s *Server) validatorLoop() {
 func (s *Server) ProcessMessage(msg *DecodedMessage) error {
 	switch t := msg.Data.(type) {
 	case *core.Transaction:
-		return s.processTransaction(t)
+		return s.processTransaction(msg.From, t)
 	case *core.Block:
-		return s.processBlock(t)
+		return s.processBlock(msg.From, t)
 	case *GetStatusMessage:
 		return s.processGetStatusMessage(msg.From, t)
 	case *StatusMessage:
@@ -215,6 +404,16 @@ func (s *Server) ProcessMessage(msg *DecodedMessage) error {
 		return s.processGetBlocksMessage(msg.From, t)
 	case *BlocksMessage:
 		return s.processBlocksMessage(msg.From, t)
+	case *ProposalAnnouncementMessage:
+		return s.processProposalAnnouncement(msg.From, t)
+	case *ProposalResultMessage:
+		return s.processProposalResult(msg.From, t)
+	case *GetPeersMessage:
+		return s.processGetPeersMessage(msg.From, t)
+	case *PeersMessage:
+		return s.processPeersMessage(msg.From, t)
+	case *core.FinalityVote:
+		return s.processFinalityVote(msg.From, t)
 	}
 
 	return nil
@@ -260,6 +459,24 @@ func (s *Server) processGetBlocksMessage(from net.Addr, data *GetBlocksMessage)
 	return peer.Send(msg.Bytes())
 }
 
+func (s *Server) sendGetPeersMessage(from net.Addr) error {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(&GetPeersMessage{}); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	peer, ok := s.peerMap[from]
+	if !ok {
+		return fmt.Errorf("peer %s not known", from)
+	}
+
+	msg := NewMessage(MessageTypeGetPeers, buf.Bytes())
+	return peer.Send(msg.Bytes())
+}
+
 func (s *Server) sendGetStatusMessage(peer *TCPPeer) error {
 	var (
 		getStatusMsg = new(GetStatusMessage)
@@ -285,6 +502,43 @@ func (s *Server) broadcast(payload []byte) error {
 	return nil
 }
 
+// relay forwards a message received from a peer to every other peer,
+// within the gossip bandwidth budget
+func (s *Server) relay(from net.Addr, payload []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for netAddr, peer := range s.peerMap {
+		if netAddr.String() == from.String() {
+			continue
+		}
+		if !s.gossip.AllowRelay(len(payload)) {
+			s.Logger.Log("msg", "relay budget exhausted, dropping gossip", "to", netAddr)
+			continue
+		}
+		if err := peer.Send(payload); err != nil {
+			fmt.Printf("peer send error => addr %s [err: %s]\n", netAddr, err)
+		}
+	}
+
+	return nil
+}
+
+// gossipMessage broadcasts a message that originated at this node, or relays one
+// received from the peer at from
+func (s *Server) gossipMessage(from net.Addr, msgType MessageType, data any) error {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(data); err != nil {
+		return err
+	}
+
+	msg := NewMessage(msgType, buf.Bytes())
+	if from == nil {
+		return s.broadcast(msg.Bytes())
+	}
+
+	return s.relay(from, msg.Bytes())
+}
+
 func (s *Server) processBlocksMessage(from net.Addr, data *BlocksMessage) error {
 	s.Logger.Log("msg", "received BLOCKS!!!!!!!!", "from", from)
 
@@ -293,6 +547,8 @@ func (s *Server) processBlocksMessage(from net.Addr, data *BlocksMessage) error
 			s.Logger.Log("error", err.Error())
 			return err
 		}
+		s.announceDAOEvents(block)
+		s.voteFinality(block, core.VotePhasePrevote)
 	}
 
 	return nil
@@ -301,6 +557,15 @@ func (s *Server) processBlocksMessage(from net.Addr, data *BlocksMessage) error
 func (s *Server) processStatusMessage(from net.Addr, data *StatusMessage) error {
 	s.Logger.Log("msg", "received STATUS message", "from", from)
 
+	if !s.registerPeerAddr(from, data) {
+		return nil
+	}
+
+	// Ask every new peer for the peers it knows
+	if err := s.sendGetPeersMessage(from); err != nil {
+		s.Logger.Log("error", "failed to request peers", "err", err)
+	}
+
 	if data.CurrentHeight <= s.chain.Height() {
 		s.Logger.Log("msg", "cannot sync blockHeight to low", "ourHeight", s.chain.Height(), "theirHeight", data.CurrentHeight, "addr", from)
 		return nil
@@ -317,6 +582,7 @@ func (s *Server) processGetStatusMessage(from net.Addr, data *GetStatusMessage)
 	statusMessage := &StatusMessage{
 		CurrentHeight: s.chain.Height(),
 		ID:            s.ID,
+		ListenAddr:    s.ListenAddr,
 	}
 
 	buf := new(bytes.Buffer)
@@ -337,37 +603,85 @@ func (s *Server) processGetStatusMessage(from net.Addr, data *GetStatusMessage)
 	return peer.Send(msg.Bytes())
 }
 
-func (s *Server) processBlock(b *core.Block) error {
+func (s *Server) processBlock(from net.Addr, b *core.Block) error {
 	if err := s.chain.AddBlock(b); err != nil {
+		// Blocks that fail verification are misbehavior, blocks from a
+		// different height are not
+		if err != core.ErrBlockKnown && b.Verify() != nil {
+			s.penalizePeer(from, penaltyInvalidMessage)
+		}
 		s.Logger.Log("error", err.Error())
 		return err
 	}
 
+	s.rewardPeer(from)
+
 	go s.broadcastBlock(b)
 
+	s.announceDAOEvents(b)
+	s.voteFinality(b, core.VotePhasePrevote)
+
 	return nil
 }
 
-func (s *Server) processTransaction(tx *core.Transaction) error {
+// processTransaction adds a transaction to the mempool and gossips it on.
+// from is nil for transactions submitted to this node.
+func (s *Server) processTransaction(from net.Addr, tx *core.Transaction) error {
 	hash := tx.Hash(core.TxHasher{})
 
-	if s.mempool.Contains(hash) {
+	if !s.gossip.MarkSeen(hash) || s.mempool.Contains(hash) {
+		// Peers relay what others already sent, only local submissions
+		// are told
+		if from == nil {
+			return api.ErrTxKnown
+		}
 		return nil
 	}
 
-	if err := tx.Verify(); err != nil {
+	pipelined := dao.PipelineTx{Tx: tx.TxInner, From: tx.From, Hash: hash, Envelope: relayedTx{from: from, tx: tx}}
+	if err := <-s.pipeline.Submit(pipelined); err != nil {
+		if errors.Is(err, dao.ErrPipelineStopped) {
+			return fmt.Errorf("%w: %v", api.ErrTxUnavailable, err)
+		}
+		s.penalizePeer(from, penaltyInvalidMessage)
+		s.chain.TxTracker().Rejected(hash, err.Error())
 		return err
 	}
 
-	// s.Logger.Log(
-	// 	"msg", "adding new tx to mempool",
-	// 	"hash", hash,
-	// 	"mempoolPending", s.mempool.PendingCount(),
-	// )
+	return nil
+}
 
-	go s.broadcastTx(tx)
+// SubmitTransaction adds a transaction submitted to this node to the
+// mempool, for the API
+func (s *Server) SubmitTransaction(tx *core.Transaction) error {
+	return s.processTransaction(nil, tx)
+}
 
-	s.mempool.Add(tx)
+// relayedTx is a transaction moving through the pipeline with the peer it
+// came from, nil for transactions submitted to this node
+type relayedTx struct {
+	from net.Addr
+	tx   *core.Transaction
+}
+
+// checkTransaction verifies the signature of a pipelined transaction
+func checkTransaction(tx *dao.PipelineTx) error {
+	if err := tx.Envelope.(relayedTx).tx.Verify(); err != nil {
+		return fmt.Errorf("%w: %v", api.ErrTxInvalid, err)
+	}
+	return nil
+}
+
+// queueTransaction adds a checked transaction to the mempool and gossips it
+// on
+func (s *Server) queueTransaction(tx dao.PipelineTx) error {
+	relayed := tx.Envelope.(relayedTx)
+	s.rewardPeer(relayed.from)
+
+	go s.broadcastTx(relayed.from, relayed.tx)
+
+	s.mempool.Add(relayed.tx)
+	s.chain.TxTracker().Submitted(tx.Hash)
 
 	return nil
 }
@@ -412,7 +726,7 @@ func (s *Server) requestBlocksLoop(peer net.Addr) error {
 
 func (s *Server) broadcastBlock(b *core.Block) error {
 	buf := &bytes.Buffer{}
-	if err := b.Encode(core.NewGobBlockEncoder(buf)); err != nil {
+	if err := b.Encode(core.NewBinaryBlockEncoder(buf)); err != nil {
 		return err
 	}
 
@@ -421,15 +735,18 @@ func (s *Server) broadcastBlock(b *core.Block) error {
 	return s.broadcast(msg.Bytes())
 }
 
-func (s *Server) broadcastTx(tx *core.Transaction) error {
+func (s *Server) broadcastTx(from net.Addr, tx *core.Transaction) error {
 	buf := &bytes.Buffer{}
-	if err := tx.Encode(core.NewGobTxEncoder(buf)); err != nil {
+	if err := tx.Encode(core.NewBinaryTxEncoder(buf)); err != nil {
 		return err
 	}
 
 	msg := NewMessage(MessageTypeTx, buf.Bytes())
+	if from == nil {
+		return s.broadcast(msg.Bytes())
+	}
 
-	return s.broadcast(msg.Bytes())
+	return s.relay(from, msg.Bytes())
 }
 
 func (s *Server) createNewBlock() error {
@@ -449,6 +766,12 @@ func (s *Server) createNewBlock() error {
 		return err
 	}
 
+	stateRoot, err := s.chain.GetDAOStateRoot(currentHeader.Height)
+	if err != nil {
+		return err
+	}
+	block.StateRoot = stateRoot
+
 	if err := block.Sign(*s.PrivateKey); err != nil {
 		return err
 	}
@@ -463,9 +786,128 @@ func (s *Server) createNewBlock() error {
 
 	go s.broadcastBlock(block)
 
+	s.announceDAOEvents(block)
+	s.voteFinality(block, core.VotePhasePrevote)
+
 	return nil
 }
 
+// contentStore creates the configured storage provider of proposal content
+func contentStore(cfg config.DAOConfig) dao.ContentStore {
+	switch store := cfg.ContentStore; store.Provider {
+	case dao.ContentStoreS3:
+		return dao.NewS3ContentStore(dao.MirrorConfig{
+			Endpoint:  store.S3.Endpoint,
+			Bucket:    store.S3.Bucket,
+			Region:    store.S3.Region,
+			AccessKey: store.S3.AccessKey,
+			SecretKey: store.S3.SecretKey,
+		})
+	case dao.ContentStoreArweave:
+		return dao.NewArweaveStore(dao.ArweaveConfig{
+			Gateway:   store.Arweave.Gateway,
+			UploadURL: store.Arweave.UploadURL,
+			Token:     store.Arweave.Token,
+		})
+	default:
+		return dao.NewIPFSStore(cfg.IPFSNodeURL)
+	}
+}
+
+// notifiers creates the configured notification channels
+func notifiers(cfg config.NotificationsConfig) ([]dao.Notifier, error) {
+	var notifiers []dao.Notifier
+
+	if cfg.FCM.CredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.FCM.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+		}
+		fcm, err := dao.NewFCMNotifier(dao.FCMConfig{ProjectID: cfg.FCM.ProjectID, Credentials: credentials})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, fcm)
+	}
+
+	if cfg.APNs.KeyFile != "" {
+		key, err := os.ReadFile(cfg.APNs.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read APNs key: %w", err)
+		}
+		apns, err := dao.NewAPNsNotifier(dao.APNsConfig{
+			Endpoint: cfg.APNs.Endpoint,
+			Key:      key,
+			KeyID:    cfg.APNs.KeyID,
+			TeamID:   cfg.APNs.TeamID,
+			Topic:    cfg.APNs.Topic,
+		})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, apns)
+	}
+
+	if cfg.SMTP.Host != "" {
+		notifiers = append(notifiers, dao.NewEmailNotifier(dao.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		}))
+	}
+
+	if cfg.Webhooks {
+		notifiers = append(notifiers, dao.NewWebhookNotifier(0))
+	}
+
+	return notifiers, nil
+}
+
+// ipfsPinningConfig converts the configured pin replication to the DAO's
+func ipfsPinningConfig(cfg config.IPFSPinningConfig) dao.IPFSPinningConfig {
+	services := make([]dao.PinningServiceConfig, 0, len(cfg.Services))
+	for _, service := range cfg.Services {
+		services = append(services, dao.PinningServiceConfig{
+			Name:     service.Name,
+			Endpoint: service.Endpoint,
+			Token:    service.Token,
+		})
+	}
+
+	return dao.IPFSPinningConfig{
+		Nodes:             cfg.Nodes,
+		Services:          services,
+		Gateways:          cfg.Gateways,
+		ReconcileInterval: cfg.ReconcileInterval,
+	}
+}
+
+// maintenanceScheduler schedules the configured maintenance jobs of the hosted DAOs.
+// Metric sampling follows the analytics sample interval unless it has a
+// schedule of its own.
+func maintenanceScheduler(registry *dao.DAORegistry, chain *core.Blockchain, cfg config.DAOConfig) (*dao.Scheduler, error) {
+	scheduler := dao.NewScheduler()
+
+	jobs := registry.MaintenanceJobs(chain.WithDAOState)
+	for _, name := range dao.MaintenanceJobNames {
+		job, configured := cfg.Jobs[name]
+		if name == dao.JobMetricsSampling && !configured {
+			job.Schedule = "@every " + cfg.Analytics.SampleInterval.String()
+		}
+		if job.Schedule == "" || (name == dao.JobArchive && cfg.Archive.Dir == "") {
+			continue
+		}
+
+		if err := scheduler.Add(name, job.Schedule, job.Jitter, jobs[name]); err != nil {
+			return nil, fmt.Errorf("failed to schedule %s: %w", name, err)
+		}
+	}
+
+	return scheduler, nil
+}
+
 func genesisBlock() *core.Block {
 	header := &core.Header{
 		Version:   1,
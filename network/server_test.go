@@ -0,0 +1,56 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/BOCK-CHAIN/BockChain/config"
+	"github.com/BOCK-CHAIN/BockChain/core"
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerOptsFromConfig_ValidatorKey(t *testing.T) {
+	cfg := config.Default()
+	cfg.Node.KeystoreDir = t.TempDir()
+
+	opts, err := ServerOptsFromConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, opts.PrivateKey)
+
+	keystore, err := crypto.NewKeystore(cfg.Node.KeystoreDir, crypto.LightScryptN)
+	require.NoError(t, err)
+	key, err := keystore.Generate("validator", "secret")
+	require.NoError(t, err)
+
+	cfg.Node.ValidatorKey = "validator"
+	cfg.Node.KeystorePassphrase = "wrong"
+	_, err = ServerOptsFromConfig(cfg)
+	assert.ErrorIs(t, err, crypto.ErrWrongPassphrase)
+
+	cfg.Node.KeystorePassphrase = "secret"
+	opts, err = ServerOptsFromConfig(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, opts.PrivateKey)
+	assert.Equal(t, key.PublicKey(), opts.PrivateKey.PublicKey())
+}
+
+func TestMaintenanceScheduler(t *testing.T) {
+	cfg := config.Default()
+	chain, err := core.NewBlockchain(log.NewNopLogger(), genesisBlock())
+	require.NoError(t, err)
+
+	scheduler, err := maintenanceScheduler(dao.NewDAORegistry(dao.NewDAO("TEST", "Test Token", 18)), chain, cfg.DAO)
+	require.NoError(t, err)
+
+	var scheduled []string
+	for _, job := range scheduler.Metrics() {
+		scheduled = append(scheduled, job.Name)
+	}
+	// IPFS cleanup is off by default and sampling follows the analytics
+	// interval
+	assert.Equal(t, []string{"delegation_expiry", "metrics_sampling", "parameter_rollout", "proposal_status", "reputation_decay", "treasury_expiry", "treasury_runway"}, scheduled)
+	assert.Equal(t, "@every 5m0s", scheduler.Metrics()[1].Schedule)
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -10,6 +11,9 @@ import (
 type TCPPeer struct {
 	conn     net.Conn
 	Outgoing bool
+	// listenAddr is the address the peer accepts connections on, learned
+	// when dialing it or from its status message
+	listenAddr string
 }
 
 func (p *TCPPeer) Send(b []byte) error {
@@ -21,8 +25,8 @@ func (p *TCPPeer) readLoop(rpcCh chan RPC) {
 	buf := make([]byte, 4096)
 	for {
 		n, err := p.conn.Read(buf)
-		if err == io.EOF {
-			continue
+		if err == io.EOF || errors.Is(err, net.ErrClosed) {
+			return
 		}
 		if err != nil {
 			fmt.Printf("read error: %s", err)
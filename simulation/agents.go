@@ -0,0 +1,247 @@
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Fees paid by the transactions of agents
+const (
+	ProposalFee   = 200
+	VoteFee       = 10
+	DelegationFee = 10
+)
+
+// Voters are ordinary members who vote on some of the open proposals with
+// a small share of their tokens
+type Voters struct {
+	ID      string
+	Count   int
+	Tokens  uint64
+	Turnout float64 // Chance of voting on an open proposal each step
+	Support float64 // Chance a vote is yes rather than no
+	Abstain float64 // Chance a vote abstains, before choosing yes or no
+}
+
+func (v *Voters) Name() string    { return v.ID }
+func (v *Voters) Accounts() int   { return v.Count }
+func (v *Voters) Balance() uint64 { return v.Tokens }
+
+// Act votes on open proposals the voters have not voted on
+func (v *Voters) Act(w *World, accounts []crypto.PrivateKey) {
+	for _, proposal := range w.OpenProposals() {
+		for _, key := range accounts {
+			if w.HasVoted(key, proposal.ID) || w.Rand().Float64() >= v.Turnout {
+				continue
+			}
+
+			choice := dao.VoteChoiceNo
+			switch {
+			case w.Rand().Float64() < v.Abstain:
+				choice = dao.VoteChoiceAbstain
+			case w.Rand().Float64() < v.Support:
+				choice = dao.VoteChoiceYes
+			}
+			w.Submit(key, &dao.VoteTx{
+				Fee:        VoteFee,
+				ProposalID: proposal.ID,
+				Choice:     choice,
+				Weight:     voteWeight(w, key, proposal, 20),
+			})
+		}
+	}
+}
+
+// Whales hold large balances, create proposals and back their own
+// proposals with a large share of their tokens
+type Whales struct {
+	ID           string
+	Count        int
+	Tokens       uint64
+	ProposalRate float64 // Chance of creating a proposal each step
+	VotingType   dao.VotingType
+}
+
+func (h *Whales) Name() string    { return h.ID }
+func (h *Whales) Accounts() int   { return h.Count }
+func (h *Whales) Balance() uint64 { return h.Tokens }
+
+// Act creates proposals and votes yes on the open proposals of any whale
+func (h *Whales) Act(w *World, accounts []crypto.PrivateKey) {
+	for _, key := range accounts {
+		if w.Rand().Float64() < h.ProposalRate {
+			w.Submit(key, newProposalTx(w, h.VotingType))
+		}
+	}
+
+	whales := make(map[string]bool, len(accounts))
+	for _, key := range accounts {
+		whales[key.PublicKey().String()] = true
+	}
+	for _, proposal := range w.OpenProposals() {
+		if !whales[proposal.Creator.String()] {
+			continue
+		}
+		for _, key := range accounts {
+			if w.HasVoted(key, proposal.ID) {
+				continue
+			}
+			w.Submit(key, &dao.VoteTx{
+				Fee:        VoteFee,
+				ProposalID: proposal.ID,
+				Choice:     dao.VoteChoiceYes,
+				Weight:     voteWeight(w, key, proposal, 4),
+			})
+		}
+	}
+}
+
+// Attack kinds tried by Attackers
+const (
+	AttackDoubleVote     = "double vote"
+	AttackOverweightVote = "overweight vote"
+	AttackLateVote       = "late vote"
+	AttackSelfDelegation = "self delegation"
+	AttackOutsiderVote   = "outsider vote"
+)
+
+// Attacks lists the attack kinds
+var Attacks = []string{AttackDoubleVote, AttackOverweightVote, AttackLateVote, AttackSelfDelegation, AttackOutsiderVote}
+
+// Attackers try transactions the governance rules forbid, each of which
+// the DAO must reject
+type Attackers struct {
+	ID     string
+	Count  int
+	Tokens uint64
+	Rate   float64 // Chance of an attack by each account each step
+}
+
+func (a *Attackers) Name() string    { return a.ID }
+func (a *Attackers) Accounts() int   { return a.Count }
+func (a *Attackers) Balance() uint64 { return a.Tokens }
+
+// Act tries a random attack for some of the attackers' accounts
+func (a *Attackers) Act(w *World, accounts []crypto.PrivateKey) {
+	for _, key := range accounts {
+		if w.Rand().Float64() >= a.Rate {
+			continue
+		}
+
+		attack := Attacks[w.Rand().Intn(len(Attacks))]
+		switch attack {
+		case AttackDoubleVote:
+			open := w.OpenProposals()
+			if len(open) == 0 {
+				continue
+			}
+			proposal := open[w.Rand().Intn(len(open))]
+			vote := &dao.VoteTx{Fee: VoteFee, ProposalID: proposal.ID, Choice: dao.VoteChoiceNo, Weight: 1}
+			if !w.HasVoted(key, proposal.ID) {
+				// The first vote is legitimate
+				if _, err := w.Submit(key, vote); err != nil {
+					continue
+				}
+			}
+			w.Attack(attack, key, vote)
+
+		case AttackOverweightVote:
+			open := w.OpenProposals()
+			if len(open) == 0 {
+				continue
+			}
+			proposal := open[w.Rand().Intn(len(open))]
+			w.Attack(attack, key, &dao.VoteTx{
+				Fee:        VoteFee,
+				ProposalID: proposal.ID,
+				Choice:     dao.VoteChoiceNo,
+				Weight:     w.Balance(key) + 1,
+			})
+
+		case AttackLateVote:
+			var closed []*dao.Proposal
+			for _, proposal := range w.Proposals() {
+				if w.Now() > proposal.EndTime {
+					closed = append(closed, proposal)
+				}
+			}
+			if len(closed) == 0 {
+				continue
+			}
+			proposal := closed[w.Rand().Intn(len(closed))]
+			w.Attack(attack, key, &dao.VoteTx{Fee: VoteFee, ProposalID: proposal.ID, Choice: dao.VoteChoiceNo, Weight: 1})
+
+		case AttackSelfDelegation:
+			w.Attack(attack, key, &dao.DelegationTx{
+				Fee:      DelegationFee,
+				Delegate: key.PublicKey(),
+				Duration: 86400,
+			})
+
+		case AttackOutsiderVote:
+			// A key that never received tokens or membership
+			outsider, err := w.newKey()
+			if err != nil {
+				continue
+			}
+			open := w.OpenProposals()
+			if len(open) == 0 {
+				continue
+			}
+			w.Attack(attack, outsider, &dao.VoteTx{Fee: VoteFee, ProposalID: open[0].ID, Choice: dao.VoteChoiceYes, Weight: 1})
+		}
+	}
+}
+
+// voteWeight draws a vote weight of up to 1/share of what the account can
+// spend on a vote on proposal
+func voteWeight(w *World, key crypto.PrivateKey, proposal *dao.Proposal, share uint64) uint64 {
+	spendable := w.Balance(key) / share
+	if spendable <= VoteFee {
+		return 1
+	}
+	limit := spendable - VoteFee
+	if proposal.VotingType == dao.VotingTypeQuadratic {
+		limit = isqrt(limit)
+	}
+	if limit <= 1 {
+		return 1
+	}
+	return 1 + uint64(w.Rand().Int63n(int64(limit)))
+}
+
+// isqrt returns the integer square root of n
+func isqrt(n uint64) uint64 {
+	root := uint64(0)
+	for bit := uint64(1) << 31; bit > 0; bit >>= 1 {
+		if candidate := root | bit; candidate*candidate <= n {
+			root = candidate
+		}
+	}
+	return root
+}
+
+// newProposalTx returns a proposal open for voting now for the governed
+// voting period
+func newProposalTx(w *World, votingType dao.VotingType) *dao.ProposalTx {
+	if votingType == 0 {
+		votingType = dao.VotingTypeSimple
+	}
+
+	var metadata types.Hash
+	w.Rand().Read(metadata[:])
+	return &dao.ProposalTx{
+		Fee:          ProposalFee,
+		Title:        fmt.Sprintf("Simulated proposal %x", metadata[:4]),
+		Description:  "Created by a simulated agent",
+		ProposalType: dao.ProposalTypeGeneral,
+		VotingType:   votingType,
+		StartTime:    w.Now(),
+		EndTime:      w.Now() + w.DAO().GovernanceState.Config.VotingPeriod,
+		Threshold:    5100,
+		MetadataHash: metadata,
+	}
+}
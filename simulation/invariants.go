@@ -0,0 +1,123 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BOCK-CHAIN/BockChain/dao"
+)
+
+// DefaultInvariants are checked when a simulation configures none
+var DefaultInvariants = []Invariant{
+	SupplyConservation,
+	TallyConsistency,
+	StatusConsistency,
+	AttacksRejected,
+}
+
+// SupplyConservation holds when every token of the supply is either held
+// by an account or was spent by a transaction on fees and voting costs,
+// as the governance rules price them
+var SupplyConservation = Invariant{
+	Name: "supply conservation",
+	Check: func(w *World) error {
+		var held uint64
+		for _, balance := range w.dao.TokenState.Balances.Snapshot() {
+			held += balance
+		}
+
+		supply := w.dao.TokenState.TotalSupply
+		if held+w.spent != supply {
+			return fmt.Errorf("accounts hold %d and transactions spent %d of a supply of %d", held, w.spent, supply)
+		}
+		return nil
+	},
+}
+
+// TallyConsistency holds when the results of every proposal add up the
+// weights of its recorded votes and its voter set names exactly its voters
+var TallyConsistency = Invariant{
+	Name: "tally consistency",
+	Check: func(w *World) error {
+		gs := w.dao.GovernanceState
+		for _, proposal := range w.Proposals() {
+			votes, recorded := gs.ProposalVotes(proposal.ID)
+			if !recorded {
+				// Pruned, the results are all that is left
+				continue
+			}
+
+			var tally dao.VoteResults
+			for voter, vote := range votes {
+				switch vote.Choice {
+				case dao.VoteChoiceYes:
+					tally.YesVotes += vote.Weight
+				case dao.VoteChoiceNo:
+					tally.NoVotes += vote.Weight
+				case dao.VoteChoiceAbstain:
+					tally.AbstainVotes += vote.Weight
+				}
+				if !gs.HasVoted(proposal.ID, voter) {
+					return fmt.Errorf("proposal %s: voter %s is missing from the voter set", proposal.ID, voter)
+				}
+			}
+
+			results := proposal.Results
+			if results.YesVotes != tally.YesVotes || results.NoVotes != tally.NoVotes || results.AbstainVotes != tally.AbstainVotes {
+				return fmt.Errorf("proposal %s: results %d/%d/%d do not match its votes %d/%d/%d", proposal.ID,
+					results.YesVotes, results.NoVotes, results.AbstainVotes, tally.YesVotes, tally.NoVotes, tally.AbstainVotes)
+			}
+			if int(results.TotalVoters) != len(votes) || gs.Voters[proposal.ID].Len() != len(votes) {
+				return fmt.Errorf("proposal %s: %d voters counted and %d in the voter set for %d votes", proposal.ID,
+					results.TotalVoters, gs.Voters[proposal.ID].Len(), len(votes))
+			}
+		}
+		return nil
+	},
+}
+
+// StatusConsistency holds when every proposal whose voting ended is
+// decided, and decided the way its results and the governance thresholds
+// say
+var StatusConsistency = Invariant{
+	Name: "status consistency",
+	Check: func(w *World) error {
+		config := w.dao.GovernanceState.Config
+		for _, proposal := range w.Proposals() {
+			switch proposal.Status {
+			case dao.ProposalStatusPending, dao.ProposalStatusActive:
+				if w.now > proposal.EndTime {
+					return fmt.Errorf("proposal %s is still open after its voting ended", proposal.ID)
+				}
+
+			case dao.ProposalStatusPassed, dao.ProposalStatusRejected:
+				results := proposal.Results
+				passed := proposal.Status == dao.ProposalStatusPassed
+				if results.Passed != passed {
+					return fmt.Errorf("proposal %s has status %d but results passed %t", proposal.ID, proposal.Status, results.Passed)
+				}
+
+				total := results.YesVotes + results.NoVotes + results.AbstainVotes
+				active := results.YesVotes + results.NoVotes
+				shouldPass := total >= proposal.RequiredQuorum(config) && active > 0 &&
+					results.YesVotes*10000/active >= proposal.RequiredThreshold(config)
+				if passed != shouldPass {
+					return fmt.Errorf("proposal %s with %d yes, %d no and %d abstaining should have passed: %t, passed: %t",
+						proposal.ID, results.YesVotes, results.NoVotes, results.AbstainVotes, shouldPass, passed)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// AttacksRejected holds when the DAO rejected every attack
+var AttacksRejected = Invariant{
+	Name: "attacks rejected",
+	Check: func(w *World) error {
+		if len(w.breaches) > 0 {
+			return fmt.Errorf("attacks applied: %s", strings.Join(w.breaches, ", "))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,371 @@
+// Package simulation runs the DAO governance state machine deterministically.
+// A seeded random source drives programmable agents — voters, whales and
+// attackers — that act once per step, while a simulated clock advances by a
+// fixed step, so days of governance pass in milliseconds. Invariants such as
+// supply conservation and tally consistency are checked after every step,
+// and the same seed always reproduces the same run, so a violation found by
+// exploring seeds can be replayed and debugged.
+package simulation
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/BOCK-CHAIN/BockChain/types"
+)
+
+// Config configures a simulation
+type Config struct {
+	Seed int64
+	// Start is the Unix time the simulation starts at, 1700000000 when zero
+	Start int64
+	Step  time.Duration // Simulated time between steps, an hour when zero
+	Steps int           // Steps to run, 168 when zero
+	// Agents act in order on every step
+	Agents []Agent
+	// Invariants are checked after every step, DefaultInvariants when nil
+	Invariants []Invariant
+}
+
+// Agent is a participant of a simulation. Agents keep no state of their
+// own between steps, so one agent can take part in many runs.
+type Agent interface {
+	// Name identifies the agent in reports
+	Name() string
+	// Accounts returns the number of accounts the agent controls
+	Accounts() int
+	// Balance returns the tokens each account of the agent starts with
+	Balance() uint64
+	// Act submits the transactions of the agent's accounts for the current
+	// step
+	Act(w *World, accounts []crypto.PrivateKey)
+}
+
+// Invariant is a property of the DAO state that holds after every step
+type Invariant struct {
+	Name  string
+	Check func(w *World) error
+}
+
+// Violation is an invariant found not to hold
+type Violation struct {
+	Invariant string `json:"invariant"`
+	Step      int    `json:"step"`
+	Time      int64  `json:"time"`
+	Error     string `json:"error"`
+}
+
+func (v *Violation) String() string {
+	return fmt.Sprintf("step %d: %s: %s", v.Step, v.Invariant, v.Error)
+}
+
+// AgentStats counts the transactions of an agent
+type AgentStats struct {
+	Applied  int `json:"applied"`
+	Rejected int `json:"rejected"`
+	Attacks  int `json:"attacks"` // Transactions expected to be rejected, included in Rejected when they were
+}
+
+// Report is the outcome of a simulation
+type Report struct {
+	Seed      int64                      `json:"seed"`
+	Steps     int                        `json:"steps"` // Steps run, fewer than configured after a violation
+	Start     int64                      `json:"start"`
+	End       int64                      `json:"end"`
+	Agents    map[string]*AgentStats     `json:"agents"`
+	Proposals map[dao.ProposalStatus]int `json:"proposals"`
+	StateRoot types.Hash                 `json:"state_root"`
+	Violation *Violation                 `json:"violation,omitempty"` // First invariant violated, nil when all held
+}
+
+// World is the state of a running simulation that agents act on
+type World struct {
+	dao    *dao.DAO
+	rand   *rand.Rand
+	now    int64
+	step   int
+	nonce  uint64
+	report *Report
+	agent  string // Agent acting, whose stats submissions count towards
+
+	// spent is the tokens applied transactions took out of circulation,
+	// worked out from their content rather than from balances
+	spent uint64
+	// breaches are the attacks that applied
+	breaches []string
+}
+
+// Run runs the simulation config describes. A violated invariant stops the
+// run and is reported rather than returned as an error.
+func Run(config Config) (*Report, error) {
+	if config.Start == 0 {
+		config.Start = 1700000000
+	}
+	if config.Step <= 0 {
+		config.Step = time.Hour
+	}
+	if config.Steps <= 0 {
+		config.Steps = 168
+	}
+	if config.Invariants == nil {
+		config.Invariants = DefaultInvariants
+	}
+	if len(config.Agents) == 0 {
+		return nil, fmt.Errorf("simulation needs agents")
+	}
+
+	w := &World{
+		dao:  dao.NewDAO("SIM", "Simulation Token", 18),
+		rand: rand.New(rand.NewSource(config.Seed)),
+		now:  config.Start,
+		report: &Report{
+			Seed:      config.Seed,
+			Start:     config.Start,
+			Agents:    make(map[string]*AgentStats),
+			Proposals: make(map[dao.ProposalStatus]int),
+		},
+	}
+	w.dao.SetClock(w.Now)
+
+	// Accounts are derived from the seed so runs are reproducible
+	accounts := make([][]crypto.PrivateKey, len(config.Agents))
+	distribution := make(map[string]uint64)
+	for i, agent := range config.Agents {
+		if _, exists := w.report.Agents[agent.Name()]; exists {
+			return nil, fmt.Errorf("agent %q is configured twice", agent.Name())
+		}
+		w.report.Agents[agent.Name()] = &AgentStats{}
+
+		accounts[i] = make([]crypto.PrivateKey, agent.Accounts())
+		for j := range accounts[i] {
+			key, err := w.newKey()
+			if err != nil {
+				return nil, err
+			}
+			accounts[i][j] = key
+			distribution[key.PublicKey().String()] = agent.Balance()
+		}
+	}
+	if err := w.dao.InitialTokenDistribution(distribution); err != nil {
+		return nil, fmt.Errorf("failed to distribute tokens: %w", err)
+	}
+
+	step := int64(config.Step / time.Second)
+	for w.step = 1; w.step <= config.Steps; w.step++ {
+		w.now += step
+
+		for i, agent := range config.Agents {
+			w.agent = agent.Name()
+			agent.Act(w, accounts[i])
+		}
+		w.dao.UpdateAllProposalStatuses()
+
+		w.report.Steps = w.step
+		if violation := w.check(config.Invariants); violation != nil {
+			w.report.Violation = violation
+			break
+		}
+	}
+
+	w.report.End = w.now
+	w.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *dao.Proposal, _ map[string]*dao.Vote) bool {
+		w.report.Proposals[proposal.Status]++
+		return true
+	})
+	root, err := w.dao.StateRoot()
+	if err != nil {
+		return w.report, err
+	}
+	w.report.StateRoot = root
+	return w.report, nil
+}
+
+// Explore runs the simulation with runs consecutive seeds starting at
+// config.Seed and returns the report of the first run violating an
+// invariant, or nil when every run held
+func Explore(config Config, runs int) (*Report, error) {
+	for i := 0; i < runs; i++ {
+		seeded := config
+		seeded.Seed = config.Seed + int64(i)
+
+		report, err := Run(seeded)
+		if err != nil {
+			return nil, fmt.Errorf("seed %d: %w", seeded.Seed, err)
+		}
+		if report.Violation != nil {
+			return report, nil
+		}
+	}
+	return nil, nil
+}
+
+// newKey derives an account key from the random source
+func (w *World) newKey() (crypto.PrivateKey, error) {
+	scalar := make([]byte, 32)
+	for attempt := 0; attempt < 16; attempt++ {
+		w.rand.Read(scalar)
+		if key, err := crypto.PrivateKeyFromBytes(scalar); err == nil {
+			return key, nil
+		}
+	}
+	return crypto.PrivateKey{}, fmt.Errorf("failed to derive an account key")
+}
+
+// check runs the invariants and returns the first violation
+func (w *World) check(invariants []Invariant) *Violation {
+	for _, invariant := range invariants {
+		if err := invariant.Check(w); err != nil {
+			return &Violation{Invariant: invariant.Name, Step: w.step, Time: w.now, Error: err.Error()}
+		}
+	}
+	return nil
+}
+
+// DAO returns the simulated DAO. Agents read it; they change it through
+// Submit and Attack only.
+func (w *World) DAO() *dao.DAO {
+	return w.dao
+}
+
+// Rand returns the random source of the simulation. Agents draw all their
+// randomness from it, in a fixed order, to keep runs reproducible.
+func (w *World) Rand() *rand.Rand {
+	return w.rand
+}
+
+// Now returns the simulated Unix time
+func (w *World) Now() int64 {
+	return w.now
+}
+
+// Step returns the current step, from 1
+func (w *World) Step() int {
+	return w.step
+}
+
+// Balance returns the token balance of an account
+func (w *World) Balance(key crypto.PrivateKey) uint64 {
+	return w.dao.TokenState.Balances.Get(key.PublicKey().String())
+}
+
+// Proposals returns the proposals of the DAO ordered by ID
+func (w *World) Proposals() []*dao.Proposal {
+	proposals := make([]*dao.Proposal, 0, w.dao.GovernanceState.ProposalCount())
+	w.dao.GovernanceState.EachProposal(func(_ types.Hash, proposal *dao.Proposal, _ map[string]*dao.Vote) bool {
+		proposals = append(proposals, proposal)
+		return true
+	})
+	sort.Slice(proposals, func(i, j int) bool {
+		return string(proposals[i].ID[:]) < string(proposals[j].ID[:])
+	})
+	return proposals
+}
+
+// OpenProposals returns the proposals open for voting, ordered by ID
+func (w *World) OpenProposals() []*dao.Proposal {
+	var open []*dao.Proposal
+	for _, proposal := range w.Proposals() {
+		if w.IsOpen(proposal) {
+			open = append(open, proposal)
+		}
+	}
+	return open
+}
+
+// IsOpen reports whether votes on proposal are accepted now
+func (w *World) IsOpen(proposal *dao.Proposal) bool {
+	return (proposal.Status == dao.ProposalStatusPending || proposal.Status == dao.ProposalStatusActive) &&
+		w.now >= proposal.StartTime && w.now <= proposal.EndTime
+}
+
+// HasVoted reports whether the account voted on a proposal
+func (w *World) HasVoted(key crypto.PrivateKey, proposalID types.Hash) bool {
+	return w.dao.GovernanceState.HasVoted(proposalID, key.PublicKey().String())
+}
+
+// Submit applies tx from the account of key, as a block at the current
+// step would, and returns its hash, which is the ID of the proposal a
+// proposal transaction creates
+func (w *World) Submit(key crypto.PrivateKey, tx interface{}) (types.Hash, error) {
+	hash, err := w.apply(key, tx)
+	stats := w.report.Agents[w.agent]
+	if err != nil {
+		stats.Rejected++
+		return hash, err
+	}
+	stats.Applied++
+	return hash, nil
+}
+
+// Attack submits tx from the account of key, expecting the DAO to reject
+// it. An attack that applies breaks the AttacksRejected invariant.
+func (w *World) Attack(name string, key crypto.PrivateKey, tx interface{}) {
+	stats := w.report.Agents[w.agent]
+	stats.Attacks++
+
+	if _, err := w.apply(key, tx); err != nil {
+		stats.Rejected++
+		return
+	}
+	stats.Applied++
+	w.breaches = append(w.breaches, fmt.Sprintf("%s by %s", name, w.agent))
+}
+
+// apply applies tx and accounts for the tokens it spends
+func (w *World) apply(key crypto.PrivateKey, tx interface{}) (types.Hash, error) {
+	encoded, err := dao.EncodeTx(tx)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	// Identical transactions of different steps still get their own hash
+	w.nonce++
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], w.nonce)
+	hash := types.Hash(sha256.Sum256(append(encoded, nonce[:]...)))
+
+	from := key.PublicKey()
+	expected, known := w.expectedSpend(tx)
+	before := w.dao.TokenState.Balances.Get(from.String())
+
+	if err := w.dao.ApplyDAOTransaction(tx, from, hash, uint32(w.step)); err != nil {
+		return hash, err
+	}
+
+	if !known {
+		expected = before - w.dao.TokenState.Balances.Get(from.String())
+	}
+	w.spent += expected
+	return hash, nil
+}
+
+// expectedSpend returns the tokens tx takes out of circulation when it
+// applies, as the governance rules define them. Reputation weighted votes
+// are not worked out.
+func (w *World) expectedSpend(tx interface{}) (uint64, bool) {
+	switch tx := tx.(type) {
+	case *dao.ProposalTx:
+		return uint64(tx.Fee), tx.Track == ""
+	case *dao.VoteTx:
+		proposal, exists := w.dao.GovernanceState.Proposal(tx.ProposalID)
+		if !exists {
+			return 0, true
+		}
+		switch proposal.VotingType {
+		case dao.VotingTypeSimple, dao.VotingTypeWeighted:
+			return uint64(tx.Fee) + tx.Weight, true
+		case dao.VotingTypeQuadratic:
+			return uint64(tx.Fee) + tx.Weight*tx.Weight, true
+		}
+		return 0, false
+	case *dao.DelegationTx:
+		return uint64(tx.Fee), true
+	}
+	return 0, false
+}
@@ -0,0 +1,151 @@
+package simulation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BOCK-CHAIN/BockChain/crypto"
+	"github.com/BOCK-CHAIN/BockChain/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAgents() []Agent {
+	return []Agent{
+		&Whales{ID: "whales", Count: 2, Tokens: 1_000_000, ProposalRate: 0.1},
+		&Voters{ID: "voters", Count: 20, Tokens: 10_000, Turnout: 0.2, Support: 0.6, Abstain: 0.1},
+		&Attackers{ID: "attackers", Count: 3, Tokens: 5_000, Rate: 0.5},
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(Config{Seed: 1, Steps: 96, Agents: testAgents()})
+	require.NoError(t, err)
+	require.Nil(t, report.Violation, "%v", report.Violation)
+
+	// Four simulated days pass, long enough for proposals to be decided
+	assert.Equal(t, 96, report.Steps)
+	assert.Equal(t, int64(96*3600), report.End-report.Start)
+	assert.Greater(t, report.Proposals[dao.ProposalStatusPassed]+report.Proposals[dao.ProposalStatusRejected], 0)
+	assert.Greater(t, report.Agents["voters"].Applied, 0)
+	assert.Greater(t, report.Agents["attackers"].Attacks, 0)
+}
+
+func TestRun_Deterministic(t *testing.T) {
+	first, err := Run(Config{Seed: 7, Steps: 48, Agents: testAgents()})
+	require.NoError(t, err)
+	second, err := Run(Config{Seed: 7, Steps: 48, Agents: testAgents()})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other, err := Run(Config{Seed: 8, Steps: 48, Agents: testAgents()})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.StateRoot, other.StateRoot)
+}
+
+func TestRun_QuadraticVoting(t *testing.T) {
+	agents := testAgents()
+	agents[0] = &Whales{ID: "whales", Count: 2, Tokens: 1_000_000, ProposalRate: 0.2, VotingType: dao.VotingTypeQuadratic}
+
+	report, err := Run(Config{Seed: 3, Steps: 72, Step: 2 * time.Hour, Agents: agents})
+	require.NoError(t, err)
+	assert.Nil(t, report.Violation, "%v", report.Violation)
+}
+
+// breachAgent applies a legitimate vote as an attack
+type breachAgent struct{}
+
+func (breachAgent) Name() string    { return "breach" }
+func (breachAgent) Accounts() int   { return 1 }
+func (breachAgent) Balance() uint64 { return 1000 }
+
+func (breachAgent) Act(w *World, accounts []crypto.PrivateKey) {
+	for _, proposal := range w.OpenProposals() {
+		if !w.HasVoted(accounts[0], proposal.ID) {
+			w.Attack("legitimate vote", accounts[0], &dao.VoteTx{Fee: VoteFee, ProposalID: proposal.ID, Choice: dao.VoteChoiceYes, Weight: 1})
+		}
+	}
+}
+
+func TestRun_Violation(t *testing.T) {
+	agents := append(testAgents(), breachAgent{})
+
+	report, err := Explore(Config{Seed: 1, Steps: 48, Agents: agents}, 3)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.NotNil(t, report.Violation)
+	assert.Equal(t, AttacksRejected.Name, report.Violation.Invariant)
+	assert.Contains(t, report.Violation.Error, "legitimate vote by breach")
+	assert.Equal(t, report.Violation.Step, report.Steps)
+
+	// Replaying the seed reproduces the violation
+	replay, err := Run(Config{Seed: report.Seed, Steps: 48, Agents: agents})
+	require.NoError(t, err)
+	assert.Equal(t, report.Violation, replay.Violation)
+}
+
+func TestInvariants_DetectCorruption(t *testing.T) {
+	corruptions := map[string]func(w *World){
+		SupplyConservation.Name: func(w *World) {
+			for address := range w.dao.TokenState.Balances.Snapshot() {
+				w.dao.TokenState.Balances.Add(address, 1)
+				return
+			}
+		},
+		TallyConsistency.Name: func(w *World) {
+			for _, proposal := range w.dao.GovernanceState.Proposals {
+				proposal.Results.YesVotes++
+			}
+		},
+		StatusConsistency.Name: func(w *World) {
+			for _, proposal := range w.dao.GovernanceState.Proposals {
+				proposal.Status = dao.ProposalStatusPassed
+			}
+		},
+	}
+
+	for invariant, corrupt := range corruptions {
+		t.Run(invariant, func(t *testing.T) {
+			agents := append(testAgents(), corruptor{corrupt})
+			report, err := Run(Config{Seed: 1, Steps: 48, Agents: agents})
+			require.NoError(t, err)
+			require.NotNil(t, report.Violation)
+			assert.Equal(t, invariant, report.Violation.Invariant, report.Violation.Error)
+		})
+	}
+}
+
+// corruptor changes the DAO state behind the governance rules once there
+// are proposals
+type corruptor struct {
+	corrupt func(w *World)
+}
+
+func (corruptor) Name() string    { return "corruptor" }
+func (corruptor) Accounts() int   { return 0 }
+func (corruptor) Balance() uint64 { return 0 }
+
+func (c corruptor) Act(w *World, accounts []crypto.PrivateKey) {
+	if len(w.Proposals()) > 0 {
+		c.corrupt(w)
+	}
+}
+
+func TestRun_Config(t *testing.T) {
+	_, err := Run(Config{})
+	assert.Error(t, err)
+
+	voters := &Voters{ID: "voters", Count: 1, Tokens: 100}
+	_, err = Run(Config{Agents: []Agent{voters, voters}})
+	assert.Error(t, err)
+}
+
+func BenchmarkRun(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		report, err := Run(Config{Seed: int64(i), Steps: 168, Agents: testAgents()})
+		if err != nil || report.Violation != nil {
+			b.Fatal(fmt.Sprint(err, report.Violation))
+		}
+	}
+}
@@ -489,7 +489,10 @@ func (suite *ComprehensiveTestSuite) testMultiProposalConcurrentVoting(t *testin
 		proposalHashes[i] = proposalHash
 	}
 
-	// Vote on all proposals concurrently
+	// Vote on all proposals concurrently, through the transaction pipeline
+	pipeline := dao.NewTxPipeline(suite.daoInstance, 0)
+	defer pipeline.Stop()
+
 	var wg sync.WaitGroup
 	for _, voter := range voters {
 		wg.Add(1)
@@ -511,7 +514,7 @@ func (suite *ComprehensiveTestSuite) testMultiProposalConcurrentVoting(t *testin
 				}
 
 				voteHash := suite.generateTxHash(voteTx, v)
-				err := suite.daoInstance.ProcessDAOTransaction(voteTx, v.PublicKey(), voteHash)
+				err := <-pipeline.Submit(dao.PipelineTx{Tx: voteTx, From: v.PublicKey(), Hash: voteHash})
 				require.NoError(t, err)
 			}
 		}(voter)
@@ -599,7 +602,10 @@ func (suite *ComprehensiveTestSuite) testConcurrentVotingLoad(t *testing.T) {
 	err := suite.daoInstance.ProcessDAOTransaction(proposalTx, creator.PublicKey(), proposalHash)
 	require.NoError(t, err)
 
-	// Concurrent voting
+	// Concurrent voting, through the transaction pipeline
+	pipeline := dao.NewTxPipeline(suite.daoInstance, 0)
+	defer pipeline.Stop()
+
 	start := time.Now()
 	var wg sync.WaitGroup
 
@@ -622,7 +628,7 @@ func (suite *ComprehensiveTestSuite) testConcurrentVotingLoad(t *testing.T) {
 			}
 
 			voteHash := suite.generateTxHash(voteTx, v)
-			err := suite.daoInstance.ProcessDAOTransaction(voteTx, v.PublicKey(), voteHash)
+			err := <-pipeline.Submit(dao.PipelineTx{Tx: voteTx, From: v.PublicKey(), Hash: voteHash})
 			require.NoError(t, err)
 		}(voter, i)
 	}